@@ -2,12 +2,13 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
-	"strconv"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
@@ -17,7 +18,12 @@ import (
 	"github.com/Kisanlink/aaa-service/v2/internal/grpc_server"
 	actionHandlers "github.com/Kisanlink/aaa-service/v2/internal/handlers/actions"
 	"github.com/Kisanlink/aaa-service/v2/internal/handlers/admin"
+	authHandlers "github.com/Kisanlink/aaa-service/v2/internal/handlers/auth"
+	authzHandlers "github.com/Kisanlink/aaa-service/v2/internal/handlers/authz"
+	existenceHandlers "github.com/Kisanlink/aaa-service/v2/internal/handlers/existence"
+	healthHandlers "github.com/Kisanlink/aaa-service/v2/internal/handlers/health"
 	kycHandlers "github.com/Kisanlink/aaa-service/v2/internal/handlers/kyc"
+	organizationHandlers "github.com/Kisanlink/aaa-service/v2/internal/handlers/organizations"
 	"github.com/Kisanlink/aaa-service/v2/internal/handlers/permissions"
 	principalHandlers "github.com/Kisanlink/aaa-service/v2/internal/handlers/principals"
 	resourceHandlers "github.com/Kisanlink/aaa-service/v2/internal/handlers/resources"
@@ -29,6 +35,7 @@ import (
 	addressRepo "github.com/Kisanlink/aaa-service/v2/internal/repositories/addresses"
 	auditRepo "github.com/Kisanlink/aaa-service/v2/internal/repositories/audit"
 	contactRepo "github.com/Kisanlink/aaa-service/v2/internal/repositories/contacts"
+	eventRepo "github.com/Kisanlink/aaa-service/v2/internal/repositories/events"
 	groupRepo "github.com/Kisanlink/aaa-service/v2/internal/repositories/groups"
 	kycRepositories "github.com/Kisanlink/aaa-service/v2/internal/repositories/kyc"
 	organizationRepo "github.com/Kisanlink/aaa-service/v2/internal/repositories/organizations"
@@ -39,22 +46,28 @@ import (
 	rolePermRepo "github.com/Kisanlink/aaa-service/v2/internal/repositories/role_permissions"
 	roleRepo "github.com/Kisanlink/aaa-service/v2/internal/repositories/roles"
 	smsRepo "github.com/Kisanlink/aaa-service/v2/internal/repositories/sms"
+	"github.com/Kisanlink/aaa-service/v2/internal/repositories/tenancy"
 	userRepo "github.com/Kisanlink/aaa-service/v2/internal/repositories/users"
 	"github.com/Kisanlink/aaa-service/v2/internal/routes"
+	"github.com/Kisanlink/aaa-service/v2/internal/security"
 	"github.com/Kisanlink/aaa-service/v2/internal/services"
+	"github.com/Kisanlink/aaa-service/v2/internal/services/geocoding"
 	actionService "github.com/Kisanlink/aaa-service/v2/internal/services/actions"
 	"github.com/Kisanlink/aaa-service/v2/internal/services/catalog"
 	serviceAdapters "github.com/Kisanlink/aaa-service/v2/internal/services/adapters"
 	contactService "github.com/Kisanlink/aaa-service/v2/internal/services/contacts"
 	groupService "github.com/Kisanlink/aaa-service/v2/internal/services/groups"
 	kycServices "github.com/Kisanlink/aaa-service/v2/internal/services/kyc"
+	"github.com/Kisanlink/aaa-service/v2/internal/services/notifications"
 	organizationService "github.com/Kisanlink/aaa-service/v2/internal/services/organizations"
+	otpService "github.com/Kisanlink/aaa-service/v2/internal/services/otp"
 	permissionService "github.com/Kisanlink/aaa-service/v2/internal/services/permissions"
 	principalService "github.com/Kisanlink/aaa-service/v2/internal/services/principals"
 	resourceService "github.com/Kisanlink/aaa-service/v2/internal/services/resources"
 	roleAssignmentService "github.com/Kisanlink/aaa-service/v2/internal/services/role_assignments"
 	smsService "github.com/Kisanlink/aaa-service/v2/internal/services/sms"
 	"github.com/Kisanlink/aaa-service/v2/internal/services/user"
+	"github.com/Kisanlink/aaa-service/v2/internal/utils/pagination"
 	"github.com/Kisanlink/aaa-service/v2/migrations"
 	"github.com/Kisanlink/aaa-service/v2/utils"
 	"github.com/Kisanlink/kisanlink-db/pkg/base"
@@ -62,6 +75,9 @@ import (
 	scalar "github.com/MarceloPetrucio/go-scalar-api-reference"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
@@ -197,9 +213,15 @@ func runSeedScripts(ctx context.Context, dbManager *db.DatabaseManager, logger *
 
 // Server manages both HTTP and gRPC servers
 type Server struct {
-	httpServer *HTTPServer
-	grpcServer *grpc_server.GRPCServer
-	logger     *zap.Logger
+	httpServer              *HTTPServer
+	grpcServer              *grpc_server.GRPCServer
+	tenantRouter            *tenancy.Router
+	invalidationBroadcaster interfaces.InvalidationBroadcaster
+	outboxRelay             *services.OutboxRelay
+	auditRetryWorker        *services.AuditRetryWorker
+	accountDeletionWorker   *services.AccountDeletionWorker
+	signingKeyRotator       *services.SigningKeyRotator
+	logger                  *zap.Logger
 }
 
 // HTTPServer wraps the gin router with middleware
@@ -228,13 +250,19 @@ func main() {
 		}
 	}()
 
-	// Load configuration from environment
-	httpPort := getEnv("HTTP_PORT", "8080")
-	grpcPort := getEnv("GRPC_PORT", "50051")
-	jwtSecret := getEnv("JWT_SECRET", "default-secret-key-change-in-production")
+	// Load and validate configuration from environment; misconfiguration stops the startup
+	cfg, err := config.Load()
+	if err != nil {
+		logger.Fatal("Invalid configuration", zap.Error(err))
+	}
+
+	// Keys redacted from request logs and audit details, on top of the built-in list
+	if len(cfg.LogRedactKeys) > 0 {
+		security.SetAdditionalSensitiveKeys(cfg.LogRedactKeys)
+	}
 
 	// Initialize database manager
-	dbManager, err := config.NewDatabaseManager(logger)
+	dbManager, err := config.NewDatabaseManager(cfg.Usernames, logger)
 	if err != nil {
 		logger.Fatal("Failed to initialize database manager", zap.Error(err))
 	}
@@ -245,7 +273,7 @@ func main() {
 	}()
 
 	// Optionally run database seeding scripts
-	if getEnv("AAA_RUN_SEED", "true") == "true" {
+	if cfg.RunSeed {
 		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
 		if err := runSeedScripts(ctx, dbManager, logger); err != nil {
@@ -284,7 +312,7 @@ func main() {
 
 	// Initialize services and repositories
 	server, err := initializeServer(
-		httpPort, grpcPort, jwtSecret,
+		cfg,
 		dbManager, logger,
 	)
 	if err != nil {
@@ -293,8 +321,8 @@ func main() {
 
 	// Start servers
 	logger.Info("Starting AAA service",
-		zap.String("http_port", httpPort),
-		zap.String("grpc_port", grpcPort))
+		zap.String("http_port", cfg.HTTPPort),
+		zap.String("grpc_port", cfg.GRPCPort))
 
 	if err := server.Start(); err != nil {
 		logger.Fatal("Failed to start servers", zap.Error(err))
@@ -311,7 +339,7 @@ func main() {
 
 // initializeServer initializes all services and creates the server
 func initializeServer(
-	httpPort, grpcPort, jwtSecret string,
+	cfg *config.Config,
 	dbManager *db.DatabaseManager,
 	logger *zap.Logger,
 ) (*Server, error) {
@@ -326,6 +354,17 @@ func initializeServer(
 		logger.Fatal("No database manager available, exiting")
 	}
 
+	// Audit logs of isolated organizations live in their own schema; single schema by default. Users
+	// stay in the shared schema, where logins look them up.
+	tenantRouter, err := config.NewTenantRouter(context.Background(), primaryDBManager, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize tenant schemas: %w", err)
+	}
+	tenantDBManager := primaryDBManager
+	if tenantRouter != nil {
+		tenantDBManager = tenantRouter
+	}
+
 	// Initialize repositories with the DatabaseManager for advanced operations
 	userRepository := userRepo.NewUserRepository(primaryDBManager)
 	userProfileRepository := userRepo.NewUserProfileRepository(primaryDBManager)
@@ -353,11 +392,11 @@ func initializeServer(
 	// The S3Manager from kisanlink-db handles AWS S3 operations
 	// AWS credentials are picked up from environment variables (AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY)
 	var s3Manager *db.S3Manager
-	awsBucket := getEnv("AWS_S3_BUCKET", "")
+	awsBucket := cfg.S3.Bucket
 	if awsBucket != "" {
 		s3Config := &db.Config{
 			S3Bucket: awsBucket,
-			S3Region: getEnv("AWS_REGION", "us-east-1"),
+			S3Region: cfg.S3.Region,
 		}
 		s3Manager = db.NewS3Manager(s3Config, logger)
 		if err := s3Manager.Connect(context.Background()); err != nil {
@@ -368,53 +407,79 @@ func initializeServer(
 		} else {
 			logger.Info("S3Manager initialized successfully",
 				zap.String("bucket", awsBucket),
-				zap.String("region", getEnv("AWS_REGION", "us-east-1")))
+				zap.String("region", cfg.S3.Region))
 		}
 	} else {
 		logger.Info("AWS_S3_BUCKET not configured, photo upload features will be disabled")
 	}
 
+	// Full details of truncated audit logs are kept in S3 only when asked to
+	var auditDetailsStore services.AuditDetailsStore
+	if s3Manager != nil && cfg.S3.AuditDetailsOffload {
+		auditDetailsStore = services.NewS3AuditDetailsStore(s3Manager)
+	}
+
 	// Initialize cache service
 	// FIX: Check CACHE_DISABLED environment variable to optionally disable Redis
 	var cacheService interfaces.CacheService
-	if getEnv("CACHE_DISABLED", "false") == "true" {
+	if cfg.Cache.Disabled {
 		logger.Info("Cache disabled by CACHE_DISABLED=true, using no-op cache service")
 		cacheService = services.NewNoOpCacheService(loggerAdapter)
 	} else {
-		// Initialize cache service from environment variables (fallback to defaults)
-		redisHost := getEnv("REDIS_HOST", "localhost")
-		redisPort := getEnv("REDIS_PORT", "6379")
-		redisPassword := getEnv("REDIS_PASSWORD", "")
-		redisTLSEnabled := getEnv("REDIS_TLS_ENABLED", "false") == "true"
+		redis := cfg.Cache.Redis
 		redisDB := 0 // Could be made configurable via REDIS_DB env var
 
-		// Parse timeout values from environment (with sensible defaults)
-		dialTimeout := parseDurationEnv("REDIS_DIAL_TIMEOUT", 5*time.Second)
-		readTimeout := parseDurationEnv("REDIS_READ_TIMEOUT", 3*time.Second)
-		writeTimeout := parseDurationEnv("REDIS_WRITE_TIMEOUT", 3*time.Second)
-		poolSize := parseIntEnv("REDIS_POOL_SIZE", 10)
-		minIdleConns := parseIntEnv("REDIS_MIN_IDLE_CONNS", 2)
-
-		config := services.RedisConfig{
-			Addr:         redisHost + ":" + redisPort,
-			Password:     redisPassword,
+		redisConfig := services.RedisConfig{
+			Addr:         redis.Addr(),
+			Password:     redis.Password,
 			DB:           redisDB,
-			TLSEnabled:   redisTLSEnabled,
-			DialTimeout:  dialTimeout,
-			ReadTimeout:  readTimeout,
-			WriteTimeout: writeTimeout,
-			PoolSize:     poolSize,
-			MinIdleConns: minIdleConns,
+			TLSEnabled:   redis.TLSEnabled,
+			DialTimeout:  redis.DialTimeout,
+			ReadTimeout:  redis.ReadTimeout,
+			WriteTimeout: redis.WriteTimeout,
+			PoolSize:     redis.PoolSize,
+			MinIdleConns: redis.MinIdleConns,
 		}
 
 		logger.Info("Initializing Redis cache service",
-			zap.String("host", redisHost),
-			zap.String("port", redisPort),
-			zap.Bool("tls_enabled", redisTLSEnabled),
-			zap.Duration("dial_timeout", dialTimeout),
-			zap.Duration("read_timeout", readTimeout),
-			zap.Duration("write_timeout", writeTimeout))
-		cacheService = services.NewCacheService(config, loggerAdapter)
+			zap.String("host", redis.Host),
+			zap.String("port", redis.Port),
+			zap.Bool("tls_enabled", redis.TLSEnabled),
+			zap.Duration("dial_timeout", redis.DialTimeout),
+			zap.Duration("read_timeout", redis.ReadTimeout),
+			zap.Duration("write_timeout", redis.WriteTimeout))
+		cacheService = services.NewCacheService(redisConfig, loggerAdapter)
+
+		// Circuit breaker that bypasses Redis while it is unreachable, failing open or closed
+		cacheService = services.NewDegradingCacheService(cacheService, cfg.Cache.Degradation, loggerAdapter)
+
+		// Optional in-process L1 cache in front of Redis for very hot keys
+		cacheService = services.NewTieredCacheService(cacheService, services.L1CacheConfig{
+			Enabled:    cfg.Cache.L1Enabled,
+			MaxEntries: cfg.Cache.L1MaxEntries,
+			TTL:        cfg.Cache.L1TTL,
+		}, loggerAdapter)
+	}
+
+	// Permission and role cache invalidations are repeated on the other instances over Redis pub/sub
+	invalidationBroadcaster := services.NewInvalidationBroadcaster(cacheService, loggerAdapter)
+	if broadcaster, ok := invalidationBroadcaster.(*services.RedisInvalidationBroadcaster); ok {
+		broadcaster.Start(context.Background())
+	}
+
+	// Events written to the transactional outbox are relayed to the configured webhook
+	var outboxRelay *services.OutboxRelay
+	if cfg.Outbox.WebhookURL != "" {
+		outboxRelay = services.NewOutboxRelay(
+			eventRepo.NewOutboxRepository(primaryDBManager),
+			services.NewWebhookEventPublisher(cfg.Outbox.WebhookURL, cfg.Outbox.WebhookTimeout),
+			services.OutboxRelayConfig{
+				PollInterval: cfg.Outbox.PollInterval,
+				MaxAttempts:  cfg.Outbox.MaxAttempts,
+			},
+			logger,
+		)
+		outboxRelay.Start(context.Background())
 	}
 
 	// Initialize maintenance service
@@ -422,9 +487,33 @@ func initializeServer(
 
 	// Initialize business services
 	addressService := services.NewAddressService(addressRepository, cacheService, loggerAdapter, validator)
+	geocoder, err := geocoding.NewGeocoder(geocoding.Config{
+		Provider: cfg.Geocoder.Provider,
+		APIKey:   cfg.Geocoder.APIKey,
+		BaseURL:  cfg.Geocoder.BaseURL,
+		Timeout:  cfg.Geocoder.Timeout,
+	})
+	if err != nil {
+		logger.Warn("Geocoder not configured, addresses will be stored without coordinates", zap.Error(err))
+	} else if svc, ok := addressService.(*services.AddressService); ok {
+		svc.SetGeocoder(geocoder)
+	}
 	roleService := services.NewRoleService(roleRepository, userRoleRepository, cacheService, loggerAdapter, validator)
+	if svc, ok := roleService.(*services.RoleService); ok {
+		svc.SetUserRepository(userRepository)
+		svc.SetInvalidationBroadcaster(invalidationBroadcaster)
+		svc.SetConstraintRepository(roleRepo.NewRoleConstraintRepository(primaryDBManager))
+		svc.SetKYCGate(userProfileRepository, roleRepo.NewPendingRoleAssignmentRepository(primaryDBManager))
+	}
 	userServiceInstance := user.NewService(userRepository, roleRepository, userRoleRepository, cacheService, logger, validator)
 
+	// Hash passwords and MPINs at the configured bcrypt costs; weaker hashes are upgraded on sign-in
+	encryptionConfig := config.LoadSecurityConfig().Encryption
+	credentialHasher := security.NewCredentialHasher(encryptionConfig.PasswordHashCost, encryptionConfig.MPinHashCost)
+	if svc, ok := userServiceInstance.(*user.Service); ok {
+		svc.SetCredentialHasher(credentialHasher)
+	}
+
 	// Inject organizational repositories for JWT context enhancement
 	if svc, ok := userServiceInstance.(*user.Service); ok {
 		svc.SetOrganizationalRepositories(groupMembershipRepository, groupRepository, organizationRepository)
@@ -462,21 +551,62 @@ func initializeServer(
 	)
 
 	// Initialize audit service early for RBAC services to use
-	auditRepository := auditRepo.NewAuditRepository(primaryDBManager)
+	auditRepository := auditRepo.NewAuditRepository(tenantDBManager)
 	auditServiceConcrete := services.NewAuditService(primaryDBManager, auditRepository, cacheService, logger)
+	configureAuditDetails(cfg, auditServiceConcrete, auditDetailsStore)
+	principalService.SetActivityRepository(auditRepository)
+	principalService.SetGrantRepository(resourcePermissionRepository)
 	auditServiceAdapter := serviceAdapters.NewAuditServiceAdapter(auditServiceConcrete)
+	principalService.SetAuditService(auditServiceAdapter)
+	if degradingCache, ok := services.DegradingCacheOf(cacheService); ok {
+		degradingCache.SetAuditService(auditServiceAdapter)
+	}
+
+	// Audit logs whose database write failed are cached and written again until they persist or
+	// are dead-lettered
+	auditRetryWorker := services.NewAuditRetryWorker(cacheService, auditRepository, services.AuditRetryConfig{
+		Interval:    cfg.Audit.RetryInterval,
+		MaxAttempts: cfg.Audit.RetryMaxAttempts,
+	}, logger)
+	auditRetryWorker.Start(context.Background())
+	principalService.SetRotationGracePeriod(cfg.ServiceKeyRotationGrace)
+	if svc, ok := roleService.(*services.RoleService); ok {
+		svc.SetAuditService(auditServiceAdapter)
+	}
+	var accountDeletionWorker *services.AccountDeletionWorker
+	if svc, ok := userServiceInstance.(*user.Service); ok {
+		svc.SetDeviceMPinSupport(userRepo.NewUserDeviceRepository(primaryDBManager), auditServiceAdapter)
+		svc.SetDataExportAuditRepository(auditRepository)
+
+		// New users get the default roles; organizations add their own with the default_user_roles setting
+		svc.SetDefaultRoles(cfg.DefaultUserRoles, roleRepository, organizationRepo.NewOrganizationSettingRepository(primaryDBManager))
+		svc.ValidateDefaultRoles(context.Background())
+		// Organizations can lower the concurrent session limit with the max_concurrent_sessions setting
+		svc.SetSessionLimitSettings(organizationRepo.NewOrganizationSettingRepository(primaryDBManager))
+		// User details requested in an organization include the custom attributes it keeps on the user
+		svc.SetUserAttributeRepository(organizationRepo.NewOrganizationUserAttributeRepository(primaryDBManager))
+		// Profile completeness scores weigh the items as configured
+		svc.SetProfileCompletenessWeights(cfg.ProfileCompletenessWeights)
+		// New users' usernames are required, unique and generated as configured
+		svc.SetUsernamePolicy(cfg.Usernames)
+
+		// Accounts their users delete are purged once the grace period for changing their mind is over
+		svc.SetAccountDeletion(userRepo.NewAccountDeletionRepository(primaryDBManager), cfg.Deletion.GracePeriod)
+		accountDeletionWorker = services.NewAccountDeletionWorker(svc, cfg.Deletion.PurgeInterval, logger)
+		accountDeletionWorker.Start(context.Background())
+	}
 
 	// Initialize SMS service (AWS SNS) for OTP delivery
-	smsEnabled := getEnv("SMS_ENABLED", "false") == "true"
-	if smsEnabled {
+	var securityAlertSMS interfaces.SMSService
+	if cfg.SMS.Enabled {
 		smsConfig := &smsService.SNSConfig{
-			Region:        getEnv("AWS_REGION", "ap-south-1"),
+			Region:        cfg.SMS.Region,
 			Enabled:       true,
-			SenderID:      getEnv("SMS_SENDER_ID", "KISANLINK"),
-			MessageType:   getEnv("SMS_MESSAGE_TYPE", "Transactional"),
-			OTPExpiry:     time.Duration(parseIntEnv("SMS_OTP_EXPIRY_MINUTES", 10)) * time.Minute,
-			MaxSMSPerHour: parseIntEnv("SMS_MAX_PER_HOUR", 5),
-			MaxSMSPerDay:  parseIntEnv("SMS_MAX_PER_DAY", 20),
+			SenderID:      cfg.SMS.SenderID,
+			MessageType:   cfg.SMS.MessageType,
+			OTPExpiry:     cfg.SMS.OTPExpiry,
+			MaxSMSPerHour: cfg.SMS.MaxPerHour,
+			MaxSMSPerDay:  cfg.SMS.MaxPerDay,
 		}
 		smsDeliveryRepository := smsRepo.NewSMSDeliveryRepository(primaryDBManager, logger)
 		snsServiceInstance, err := smsService.NewSNSService(
@@ -494,6 +624,7 @@ func initializeServer(
 			if svc, ok := userServiceInstance.(*user.Service); ok {
 				svc.SetSMSService(snsServiceInstance)
 			}
+			securityAlertSMS = snsServiceInstance
 			logger.Info("SMS service (AWS SNS) initialized successfully",
 				zap.String("region", smsConfig.Region),
 				zap.String("sender_id", smsConfig.SenderID))
@@ -502,9 +633,43 @@ func initializeServer(
 		logger.Info("SMS service disabled (SMS_ENABLED=false)")
 	}
 
+	// Initialize security event notifications (password/MPIN changes, new device sign-ins)
+	securityNotifier := notifications.NewNotifier(
+		notifications.LoadConfigFromEnv(),
+		notifications.NewContactRecipientResolver(contactRepository, userRepository),
+		map[string]notifications.Channel{
+			notifications.ChannelSMS:   notifications.NewSMSChannel(securityAlertSMS, logger),
+			notifications.ChannelEmail: notifications.NewEmailChannel(logger),
+		},
+		logger,
+	)
+	if svc, ok := userServiceInstance.(*user.Service); ok {
+		svc.SetSecurityNotifier(securityNotifier)
+	}
+
+	// Initialize OTP delivery for contact verification and password and MPIN resets; the sender is
+	// chosen per contact type
+	otpConfig := otpService.LoadConfigFromEnv()
+	otps := otpService.NewService(
+		cacheService,
+		otpService.NewSenders(otpConfig, securityAlertSMS, logger),
+		otpConfig,
+		logger,
+	)
+	contactServiceInstance.SetOTPService(otps)
+	if svc, ok := userServiceInstance.(*user.Service); ok {
+		svc.SetCredentialReset(otps)
+	}
+
+	// Check that permissions reference existing resources; strict mode rejects dangling references
+	resourceReferenceValidator := resourceService.NewReferenceValidator(resourceRepository)
+	strictResourcePermissions := cfg.StrictResourcePermissions
+
 	// Initialize RBAC services with proper dependencies
 	resourceService := resourceService.NewService(
 		resourceRepository,
+		actionRepository,
+		permissionRepository,
 		cacheService,
 		logger,
 	)
@@ -525,6 +690,8 @@ func initializeServer(
 		auditServiceAdapter,
 		loggerAdapter,
 	)
+	permissionService.SetInvalidationBroadcaster(invalidationBroadcaster)
+	permissionService.SetResourceValidator(resourceReferenceValidator, strictResourcePermissions)
 
 	roleAssignmentService := roleAssignmentService.NewService(
 		roleRepository,
@@ -536,18 +703,16 @@ func initializeServer(
 		auditServiceAdapter,
 		loggerAdapter,
 	)
+	roleAssignmentService.SetInvalidationBroadcaster(invalidationBroadcaster)
+	roleAssignmentService.SetResourceValidator(resourceReferenceValidator, strictResourcePermissions)
 
 	// Initialize KYC service and dependencies
 	// Sandbox API client for Aadhaar verification
-	sandboxBaseURL := getEnv("AADHAAR_SANDBOX_URL", "")
-	sandboxAPIKey := getEnv("AADHAAR_SANDBOX_API_KEY", "")
-	sandboxAPISecret := getEnv("AADHAAR_SANDBOX_API_SECRET", "")
-
-	if sandboxBaseURL == "" {
+	if cfg.KYC.SandboxURL == "" {
 		logger.Warn("AADHAAR_SANDBOX_URL not configured, Aadhaar verification will not work")
 	}
 
-	sandboxClient := kycServices.NewSandboxClient(sandboxBaseURL, sandboxAPIKey, sandboxAPISecret, logger)
+	sandboxClient := kycServices.NewSandboxClient(cfg.KYC.SandboxURL, cfg.KYC.SandboxAPIKey, cfg.KYC.SandboxAPISecret, logger)
 
 	// Create Aadhaar verification repository
 	aadhaarRepo := kycRepositories.NewAadhaarVerificationRepository(primaryDBManager, s3Manager, logger)
@@ -560,10 +725,15 @@ func initializeServer(
 
 	// KYC service configuration
 	kycConfig := &kycServices.Config{
-		OTPExpirationSeconds: parseIntEnv("OTP_EXPIRATION_SECONDS", 300),
-		OTPMaxAttempts:       parseIntEnv("OTP_MAX_ATTEMPTS", 3),
-		OTPCooldownSeconds:   parseIntEnv("OTP_COOLDOWN_SECONDS", 60),
-		PhotoMaxSizeMB:       parseIntEnv("PHOTO_MAX_SIZE_MB", 5),
+		OTPExpirationSeconds: cfg.KYC.OTPExpirationSeconds,
+		OTPMaxAttempts:       cfg.KYC.OTPMaxAttempts,
+		OTPCooldownSeconds:   cfg.KYC.OTPCooldownSeconds,
+		PhotoMaxSizeMB:       cfg.KYC.PhotoMaxSizeMB,
+
+		CallbackSecret:         cfg.KYC.CallbackSecret,
+		CallbackMaxAttempts:    cfg.KYC.CallbackMaxAttempts,
+		CallbackTimeoutSeconds: cfg.KYC.CallbackTimeoutSeconds,
+		CallbackAllowedHosts:   cfg.KYC.CallbackAllowedHosts,
 	}
 
 	// Create KYC service with all dependencies
@@ -576,6 +746,9 @@ func initializeServer(
 		logger,
 		kycConfig,
 	)
+	if svc, ok := roleService.(*services.RoleService); ok {
+		kycService.SetRoleActivator(svc)
+	}
 
 	// Initialize handlers
 	permissionHandler := permissions.NewPermissionHandler(permissionService, roleAssignmentService, validator, responder, logger)
@@ -583,18 +756,39 @@ func initializeServer(
 	actionHandler := actionHandlers.NewActionHandler(actionService, validator, responder, logger)
 	principalHandler := principalHandlers.NewPrincipalHandler(principalService, responder, logger)
 	kycHandler := kycHandlers.NewHandler(kycService, validator, responder, logger)
+	healthHandler := healthHandlers.NewHealthHandler(dbManager, cacheService, s3Manager, responder, logger)
 
 	// Initialize CatalogService for seeding roles/permissions via HTTP
 	catalogService := catalog.NewCatalogService(primaryDBManager, logger)
 
+	// Tokens are signed with rotated RS256 keys when AAA_JWT_SIGNING_KEYS_DIR is set, and with the
+	// HS256 secret otherwise
+	signingKeyConfig := config.LoadSigningKeyConfig()
+	var signingKeys *security.SigningKeyRing
+	var signingKeyRotator *services.SigningKeyRotator
+	if signingKeyConfig.Enabled() {
+		signingKeys, err = security.LoadSigningKeyRing(signingKeyConfig.Dir, signingKeyConfig.Overlap)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load signing keys: %w", err)
+		}
+		signingKeys.AcceptHS256Until(signingKeyConfig.AcceptHS256Until)
+		signingKeyRotator = services.NewSigningKeyRotator(signingKeys, signingKeyConfig.RotationInterval, logger)
+		signingKeyRotator.Start(context.Background())
+	}
+
 	// Initialize HTTP server
 	httpServer, err := initializeHTTPServer(
-		httpPort, jwtSecret,
+		cfg,
 		primaryDBManager, userService, roleService, userRepository, userRoleRepository,
 		cacheService, validator, responder, maintenanceService, logger, permissionHandler, resourceHandler, actionHandler, principalHandler, kycHandler, contactServiceInstance, addressService,
 		organizationRepository, groupRepository, groupRoleRepository, groupMembershipRepository, roleRepository,
 		serviceRepository,
 		catalogService,
+		healthHandler,
+		securityNotifier,
+		tenantRouter,
+		auditDetailsStore,
+		signingKeys,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize HTTP server: %w", err)
@@ -602,8 +796,8 @@ func initializeServer(
 
 	// Initialize gRPC server using organizationServiceInstance from httpServer
 	grpcConfig := &grpc_server.GRPCServerConfig{
-		Port:             grpcPort,
-		JWTSecret:        jwtSecret,
+		Port:             cfg.GRPCPort,
+		JWTSecret:        cfg.JWTSecret,
 		TokenExpiry:      24 * time.Hour,
 		RefreshExpiry:    7 * 24 * time.Hour,
 		EnableReflection: true,
@@ -617,17 +811,26 @@ func initializeServer(
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize gRPC server: %w", err)
 	}
+	if signingKeys != nil {
+		grpcServer.SetSigningKeys(signingKeys)
+	}
 
 	return &Server{
-		httpServer: httpServer,
-		grpcServer: grpcServer,
-		logger:     logger,
+		httpServer:              httpServer,
+		grpcServer:              grpcServer,
+		tenantRouter:            tenantRouter,
+		invalidationBroadcaster: invalidationBroadcaster,
+		outboxRelay:             outboxRelay,
+		auditRetryWorker:        auditRetryWorker,
+		accountDeletionWorker:   accountDeletionWorker,
+		signingKeyRotator:       signingKeyRotator,
+		logger:                  logger,
 	}, nil
 }
 
 // initializeHTTPServer creates and configures the HTTP server with middleware
 func initializeHTTPServer(
-	port, jwtSecret string,
+	cfg *config.Config,
 	dbManager db.DBManager,
 	userService interfaces.UserService,
 	roleService interfaces.RoleService,
@@ -652,23 +855,55 @@ func initializeHTTPServer(
 	roleRepository *roleRepo.RoleRepository,
 	serviceRepository interfaces.ServiceRepository,
 	catalogService *catalog.CatalogService,
+	healthHandler *healthHandlers.HealthHandler,
+	securityNotifier interfaces.SecurityNotifier,
+	tenantRouter *tenancy.Router,
+	auditDetailsStore services.AuditDetailsStore,
+	signingKeys *security.SigningKeyRing,
 ) (*HTTPServer, error) {
+	auditDBManager := dbManager
+	if tenantRouter != nil {
+		auditDBManager = tenantRouter
+	}
+
 	// Build auth, authorization, and audit stack
 	auditService, authzService, authService, authMiddleware, auditMiddleware, err := setupAuthStack(
 		context.Background(),
 		dbManager,
+		auditDBManager,
 		cacheService,
 		userRepository,
 		roleService,
 		userRoleRepository,
 		serviceRepository,
-		jwtSecret,
+		cfg.JWTSecret,
+		signingKeys,
 		logger,
 		validator,
 	)
 	if err != nil {
 		return nil, err
 	}
+	configureAuditDetails(cfg, auditService, auditDetailsStore)
+	authzService.SetPermissionCheckAudit(services.PermissionCheckAuditConfig{
+		Enabled:       cfg.Audit.PermissionChecks,
+		ResourceTypes: cfg.Audit.PermissionCheckResources,
+		SamplePercent: cfg.Audit.PermissionCheckSamplePercent,
+	})
+	authService.SetSecurityNotifier(securityNotifier)
+	authService.SetLoginStepUpConfig(config.LoadSecurityConfig().LoginStepUp)
+	authService.SetDeviceSessionConfig(config.LoadSecurityConfig().DeviceSessions)
+	sessionLimits, _ := userService.(interfaces.SessionLimitResolver)
+	authService.SetSessionLimit(cfg.Sessions, sessionLimits)
+	// A completed password or MPIN reset, or an account deletion request, signs the user out everywhere
+	if svc, ok := userService.(*user.Service); ok {
+		svc.SetSessionRevoker(authService)
+	}
+	encryptionConfig := config.LoadSecurityConfig().Encryption
+	authService.SetCredentialHasher(security.NewCredentialHasher(encryptionConfig.PasswordHashCost, encryptionConfig.MPinHashCost))
+	if tenantRouter != nil {
+		authMiddleware.SetTenantSchemaSelector(tenantRouter)
+	}
 
 	// Initialize roleHandler now that auditService is available
 	roleHandler := roles.NewRoleHandler(roleService, validator, responder, auditService, logger)
@@ -678,8 +913,9 @@ func initializeHTTPServer(
 	groupRepositoryAdapter := repositoryAdapters.NewGroupRepositoryAdapter(groupRepository)
 
 	// Create audit service adapter
-	auditRepository := auditRepo.NewAuditRepository(dbManager)
+	auditRepository := auditRepo.NewAuditRepository(auditDBManager)
 	auditServiceConcrete := services.NewAuditService(dbManager, auditRepository, cacheService, logger)
+	configureAuditDetails(cfg, auditServiceConcrete, auditDetailsStore)
 	auditServiceAdapter := serviceAdapters.NewAuditServiceAdapter(auditServiceConcrete)
 
 	// Initialize organization service with adapters
@@ -693,6 +929,16 @@ func initializeHTTPServer(
 		auditServiceAdapter,
 		logger,
 	)
+	organizationServiceConcrete.SetMemberRepository(organizationRepo.NewOrganizationMemberRepository(dbManager))
+	organizationServiceConcrete.SetSettingRepository(organizationRepo.NewOrganizationSettingRepository(dbManager))
+	organizationServiceConcrete.SetUserAttributeRepository(organizationRepo.NewOrganizationUserAttributeRepository(dbManager))
+	organizationServiceConcrete.SetRoleTemplateRepository(roleRepo.NewRoleTemplateRepository(dbManager))
+	organizationServiceConcrete.SetRoleRepository(roleRepository)
+	organizationServiceConcrete.SetGroupTreeRepository(groupRepository)
+	organizationServiceConcrete.SetMemberRemovalPolicy(organizationService.ParseMemberRemovalPolicy(cfg.OrgMemberRemovalPolicy))
+	organizationServiceConcrete.SetAuditRetention(auditRepository, config.LoadSecurityConfig().Audit.RetentionDays)
+	// Bound organization and group nesting; 0 leaves hierarchies unbounded
+	organizationServiceConcrete.SetMaxHierarchyDepth(cfg.MaxHierarchyDepth)
 	organizationServiceInstance := organizationService.NewServiceAdapter(organizationServiceConcrete, logger)
 
 	// Initialize group service with adapters
@@ -710,23 +956,106 @@ func initializeHTTPServer(
 	)
 	// Inject user service for cache invalidation
 	groupServiceConcrete.SetUserService(userService)
+	// Inject resource permissions for resource-scoped effective roles
+	groupServiceConcrete.SetResourcePermissionRepository(resourcePermRepo.NewResourcePermissionRepository(dbManager))
+	groupServiceConcrete.SetMaxHierarchyDepth(cfg.MaxHierarchyDepth)
 	groupServiceInstance := groupServiceConcrete
 
 	// Inject group service into organization service to resolve circular dependency
 	organizationServiceConcrete.SetGroupService(groupServiceInstance)
 
+	// Default page size and page size cap of list endpoints
+	pagination.SetDefaults(pagination.Defaults{
+		Limit:    cfg.Pagination.DefaultLimit,
+		MaxLimit: cfg.Pagination.MaxLimit,
+	})
+
+	// Expose the primary connection pool to Prometheus and the admin endpoints
+	var dbPool interfaces.DBPoolStatsProvider
+	if sqlDB, err := config.SQLDB(dbManager); err != nil {
+		logger.Warn("Database pool statistics are unavailable", zap.Error(err))
+	} else {
+		dbPool = sqlDB
+		if err := prometheus.Register(collectors.NewDBStatsCollector(sqlDB, config.LoadDatabaseConfig().Postgres.DBName)); err != nil {
+			logger.Warn("Failed to register database pool metrics", zap.Error(err))
+		}
+	}
+
+	// Per-organization rate limits and monthly API quotas, counted in Redis (AAA_ORG_QUOTAS_ENABLED)
+	quotaService := services.NewOrganizationQuotaService(config.LoadOrganizationQuotaConfig(), cacheService, organizationRepository, logger)
+
 	// Create gin router
 	router := gin.New()
 
 	// Setup middleware stack
-	setupHTTPMiddleware(router, authMiddleware, auditMiddleware, maintenanceService, responder, logger)
+	setupHTTPMiddleware(cfg, router, authMiddleware, auditMiddleware, auditService, quotaService, maintenanceService, responder, logger)
 
 	// Setup routes and docs
-	setupRoutesAndDocs(router, authService, authzService, auditService, authMiddleware, maintenanceService, validator, responder, logger, roleHandler, permissionHandler, resourceHandler, actionHandler, principalHandler, kycHandler, userService, roleService, contactServiceInstance, addressService, organizationServiceInstance, groupServiceInstance, catalogService)
+	setupRoutesAndDocs(cfg, router, authService, authzService, auditService, authMiddleware, maintenanceService, cacheService, validator, responder, logger, roleHandler, permissionHandler, resourceHandler, actionHandler, principalHandler, kycHandler, userService, roleService, contactServiceInstance, addressService, organizationServiceInstance, groupServiceInstance, catalogService, dbPool, signingKeys, migrations.NewRBACSeedPreviewer(dbManager, logger))
+
+	// Register OIDC login routes (only when external identity providers are configured)
+	oidcService := services.NewOIDCService(
+		config.LoadOIDCProvidersFromEnv(),
+		authService,
+		userRepository,
+		userRepo.NewUserIdentityRepository(dbManager),
+		contactRepo.NewContactRepository(dbManager),
+		roleService,
+		cacheService,
+		auditService,
+		logger,
+	)
+	routes.RegisterOIDCRoutes(router, authHandlers.NewOIDCHandler(oidcService, responder, logger), oidcService.Enabled())
+
+	// Publish the token verification keys (only when tokens are signed with RS256 keys)
+	routes.RegisterJWKSRoutes(router, authHandlers.NewJWKSHandler(signingKeys), signingKeys != nil)
+
+	// Register the quota status of organizations
+	routes.RegisterOrganizationQuotaRoutes(router, organizationHandlers.NewQuotaHandler(quotaService, logger, responder), authMiddleware)
+
+	// Register batch audit ingestion, through which other services record their audit events here
+	if users, ok := userRepository.(*userRepo.UserRepository); ok {
+		auditService.SetUserLookup(users)
+	}
+	routes.RegisterAuditIngestRoutes(router, auditService, authMiddleware, cfg.Audit.IngestRatePerMinute, logger)
+
+	// Register batch existence checks used by other services to validate references
+	existenceHandler := existenceHandlers.NewHandler(responder, logger)
+	existenceHandler.SetChecker(existenceHandlers.ResourceUser, userRepository)
+	existenceHandler.SetChecker(existenceHandlers.ResourceOrganization, organizationRepository)
+	existenceHandler.SetChecker(existenceHandlers.ResourceRole, roleRepository)
+	existenceHandler.SetChecker(existenceHandlers.ResourceGroup, groupRepository)
+	routes.RegisterExistenceRoutes(router, existenceHandler, authMiddleware)
+
+	// Register liveness and readiness probes
+	routes.RegisterProbeRoutes(router, healthHandler)
+
+	// Serve Prometheus metrics, including the database pool gauges (gated by env)
+	if cfg.HTTP.EnableMetrics {
+		router.GET("/metrics", gin.WrapH(promhttp.Handler()))
+	}
+
+	// Authorize every route by its declared policy; undeclared routes fall back to the path convention
+	// unless AAA_ROUTE_POLICY_STRICT is set, in which case the server refuses to start
+	routePolicies := middleware.NewRoutePolicies()
+	routes.DeclareRoutePolicies(routePolicies)
+	strictRoutePolicies := cfg.HTTP.RoutePolicyStrict
+	if undeclared := routePolicies.Undeclared(router.Routes()); len(undeclared) > 0 {
+		if strictRoutePolicies {
+			return nil, fmt.Errorf("routes without an authorization policy: %s", strings.Join(undeclared, ", "))
+		}
+		logger.Warn("Routes without an authorization policy use the path convention",
+			zap.Strings("routes", undeclared))
+	}
+	authMiddleware.SetRoutePolicies(routePolicies, strictRoutePolicies)
+	// Resource types whose denied lookups answer 404, hiding which IDs exist
+	if len(cfg.HTTP.ConcealedResources) > 0 {
+		authMiddleware.SetConcealedResources(cfg.HTTP.ConcealedResources)
+	}
 
 	return &HTTPServer{
 		router:                      router,
-		port:                        port,
+		port:                        cfg.HTTPPort,
 		logger:                      logger,
 		organizationServiceInstance: organizationServiceInstance,
 		groupServiceInstance:        groupServiceInstance,
@@ -737,17 +1066,19 @@ func initializeHTTPServer(
 func setupAuthStack(
 	ctx context.Context,
 	dbManager db.DBManager,
+	auditDBManager db.DBManager,
 	cacheService interfaces.CacheService,
 	userRepository interfaces.UserRepository,
 	roleService interfaces.RoleService,
 	userRoleRepository interfaces.UserRoleRepository,
 	serviceRepository interfaces.ServiceRepository,
 	jwtSecret string,
+	signingKeys *security.SigningKeyRing,
 	logger *zap.Logger,
 	validator interfaces.Validator,
 ) (*services.AuditService, *services.AuthorizationService, *services.AuthService, *middleware.AuthMiddleware, *middleware.AuditMiddleware, error) {
 	// Initialize audit repository
-	auditRepository := auditRepo.NewAuditRepository(dbManager)
+	auditRepository := auditRepo.NewAuditRepository(auditDBManager)
 	auditService := services.NewAuditService(dbManager, auditRepository, cacheService, logger)
 
 	// Get database connection for authorization service
@@ -800,43 +1131,73 @@ func setupAuthStack(
 		return nil, nil, nil, nil, nil, fmt.Errorf("failed to create authentication service: %w", err)
 	}
 
-	authMiddleware := middleware.NewAuthMiddleware(authService, authzService, auditService, serviceRepository, logger, middleware.NewHS256Verifier(), jwtCfg)
+	var verifier middleware.JWTVerifier = middleware.NewHS256Verifier()
+	if signingKeys != nil {
+		authService.SetSigningKeys(signingKeys)
+		verifier = middleware.NewSigningKeyVerifier(signingKeys)
+	}
+	authMiddleware := middleware.NewAuthMiddleware(authService, authzService, auditService, serviceRepository, logger, verifier, jwtCfg)
 	auditMiddleware := middleware.NewAuditMiddleware(auditService, logger)
 	return auditService, authzService, authService, authMiddleware, auditMiddleware, nil
 }
 
 // setupHTTPMiddleware configures the middleware stack on the provided router
 func setupHTTPMiddleware(
+	cfg *config.Config,
 	router *gin.Engine,
 	authMiddleware *middleware.AuthMiddleware,
 	auditMiddleware *middleware.AuditMiddleware,
+	auditService *services.AuditService,
+	quotaService interfaces.OrganizationQuotaService,
 	maintenanceService interfaces.MaintenanceService,
 	responder interfaces.Responder,
 	logger *zap.Logger,
 ) {
 	loggerAdapter := utils.NewLoggerAdapter(logger)
-	routes.SetupMiddleware(router,
+	stack := []gin.HandlerFunc{
 		middleware.CORS(), // Use our custom CORS middleware instead of cors.Default()
 		middleware.RequestID(),
+	}
+
+	// Compress before the audit middleware wraps the writer, so audit captures the plain body
+	if cfg.HTTP.CompressionEnabled {
+		compressionConfig := middleware.DefaultCompressionConfig()
+		compressionConfig.MinSize = cfg.HTTP.CompressionMinSize
+		stack = append(stack, middleware.ResponseCompressionWithConfig(compressionConfig))
+	}
+
+	// Requests are cut off with 504 after AAA_REQUEST_TIMEOUT; slower ones than the threshold are
+	// logged and recorded in the audit performance metrics
+	timeoutConfig := middleware.DefaultRequestTimeoutConfig(logger)
+	timeoutConfig.Timeout = cfg.HTTP.RequestTimeout
+	timeoutConfig.SlowRequestThreshold = cfg.HTTP.SlowRequestThreshold
+	timeoutConfig.Recorder = auditService
+	stack = append(stack, middleware.RequestTimeout(timeoutConfig))
+
+	stack = append(stack,
 		middleware.Logger(loggerAdapter),
 		middleware.ResponseContextHeaders(),
 		auditMiddleware.HTTPAuditMiddleware(),
 		authMiddleware.HTTPAuthMiddleware(),
 		authMiddleware.HTTPAuthzMiddleware(),
+		middleware.OrganizationQuota(quotaService, auditService, logger),
 		middleware.ErrorHandler,
 		middleware.PanicRecoveryHandler(loggerAdapter),
 		middleware.MaintenanceMode(maintenanceService, responder, loggerAdapter),
 	)
+	routes.SetupMiddleware(router, stack...)
 }
 
 // setupRoutesAndDocs registers API routes and documentation endpoints
 func setupRoutesAndDocs(
+	cfg *config.Config,
 	router *gin.Engine,
 	authService *services.AuthService,
 	authzService *services.AuthorizationService,
 	auditService *services.AuditService,
 	authMiddleware *middleware.AuthMiddleware,
 	maintenanceService interfaces.MaintenanceService,
+	cacheService interfaces.CacheService,
 	validator interfaces.Validator,
 	responder interfaces.Responder,
 	logger *zap.Logger,
@@ -853,9 +1214,29 @@ func setupRoutesAndDocs(
 	organizationServiceInstance interfaces.OrganizationService,
 	groupServiceInstance interfaces.GroupService,
 	catalogService *catalog.CatalogService,
+	dbPool interfaces.DBPoolStatsProvider,
+	signingKeys *security.SigningKeyRing,
+	rbacSeedPreview interfaces.RBACSeedPreviewer,
 ) {
 	// Create AdminHandler for v2 admin routes
 	adminHandler := admin.NewAdminHandler(maintenanceService, validator, responder, logger)
+	adminHandler.SetImpersonationService(authService)
+	if tieredCache, ok := cacheService.(interfaces.CacheTierStatsProvider); ok {
+		adminHandler.SetCacheTierStats(tieredCache)
+	}
+	if degradingCache, ok := services.DegradingCacheOf(cacheService); ok {
+		adminHandler.SetCacheDegradation(degradingCache)
+	}
+	if dbPool != nil {
+		adminHandler.SetDBPoolStats(dbPool)
+	}
+	if signingKeys != nil {
+		adminHandler.SetSigningKeys(signingKeys)
+	}
+	adminHandler.SetRBACSeedPreview(rbacSeedPreview)
+	if depthChecker, ok := organizationServiceInstance.(interfaces.HierarchyDepthChecker); ok {
+		adminHandler.SetHierarchyDepthChecker(depthChecker)
+	}
 
 	// Setup routes using the enhanced wrapper that supports organization services
 	routes.SetupAAAWithOrganizations(
@@ -885,6 +1266,12 @@ func setupRoutesAndDocs(
 	// Register RBAC resource routes
 	routes.RegisterResourceRoutes(router, resourceHandler, authMiddleware)
 
+	// Register the permission check used by gateways and other services
+	authzHandler := authzHandlers.NewHandler(authzService, responder, logger)
+	authzHandler.SetAccessReporter(authzService)
+	routes.RegisterPermissionCheckRoutes(router, authzHandler, authMiddleware)
+	routes.RegisterAccessReportRoutes(router, authzHandler, authMiddleware)
+
 	// Register RBAC action routes
 	routes.RegisterActionRoutes(router.Group("/api/v1"), actionHandler)
 
@@ -892,7 +1279,7 @@ func setupRoutesAndDocs(
 	kycHandlers.RegisterRoutes(router, kycHandler, authMiddleware.HTTPAuthMiddleware())
 
 	// Serve OpenAPI and Scalar-powered docs UI (gated by env)
-	if getEnv("AAA_ENABLE_DOCS", "true") == "true" {
+	if cfg.HTTP.EnableDocs {
 		router.StaticFile("/docs/swagger.json", "docs/swagger.json")
 		router.StaticFile("/docs/swagger.yaml", "docs/swagger.yaml")
 		router.GET("/docs", func(c *gin.Context) {
@@ -983,20 +1370,57 @@ func (s *Server) Stop() {
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		s.grpcServer.Stop()
+		s.grpcServer.Stop(ctx)
 	}()
 
 	wg.Wait()
+
+	if s.tenantRouter != nil {
+		if err := s.tenantRouter.Close(); err != nil {
+			s.logger.Warn("Failed to close tenant databases", zap.Error(err))
+		}
+	}
+	if s.outboxRelay != nil {
+		s.outboxRelay.Stop()
+	}
+	if s.auditRetryWorker != nil {
+		s.auditRetryWorker.Stop()
+	}
+	if s.accountDeletionWorker != nil {
+		s.accountDeletionWorker.Stop()
+	}
+	if s.signingKeyRotator != nil {
+		s.signingKeyRotator.Stop()
+	}
+	if s.invalidationBroadcaster != nil {
+		if err := s.invalidationBroadcaster.Close(); err != nil {
+			s.logger.Warn("Failed to close cache invalidation subscription", zap.Error(err))
+		}
+	}
 	s.logger.Info("All servers stopped gracefully")
 }
 
 // Start starts the HTTP server
 func (s *HTTPServer) Start() error {
+	tlsConfig, err := config.LoadServerTLSConfig()
+	if err != nil {
+		return fmt.Errorf("invalid TLS configuration: %w", err)
+	}
+
 	s.server = &http.Server{
 		Addr:    ":" + s.port,
 		Handler: s.router,
 	}
 
+	// Serve HTTPS directly when a certificate is configured
+	if tlsConfig.Enabled() {
+		s.server.TLSConfig = tlsConfig.TLSConfig()
+		s.logger.Info("Starting HTTPS server",
+			zap.String("port", s.port),
+			zap.String("min_tls_version", tls.VersionName(tlsConfig.MinVersion)))
+		return s.server.ListenAndServeTLS(tlsConfig.CertFile, tlsConfig.KeyFile)
+	}
+
 	s.logger.Info("Starting HTTP server", zap.String("port", s.port))
 	return s.server.ListenAndServe()
 }
@@ -1012,32 +1436,9 @@ func (s *HTTPServer) Stop(ctx context.Context) {
 	}
 }
 
-// getEnv gets environment variable with default value
-func getEnv(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
-	}
-	return defaultValue
-}
-
-// parseDurationEnv parses a duration from environment variable (in seconds) with fallback
-func parseDurationEnv(key string, defaultValue time.Duration) time.Duration {
-	if value := os.Getenv(key); value != "" {
-		if seconds, err := strconv.Atoi(value); err == nil {
-			return time.Duration(seconds) * time.Second
-		}
-	}
-	return defaultValue
-}
-
-// parseIntEnv parses an integer from environment variable with fallback
-func parseIntEnv(key string, defaultValue int) int {
-	if value := os.Getenv(key); value != "" {
-		if intVal, err := strconv.Atoi(value); err == nil {
-			return intVal
-		}
-	}
-	return defaultValue
+// configureAuditDetails applies the audit details size limit, AAA_AUDIT_MAX_DETAILS_BYTES (0 disables it)
+func configureAuditDetails(cfg *config.Config, auditService *services.AuditService, store services.AuditDetailsStore) {
+	auditService.SetDetailsLimit(cfg.Audit.MaxDetailsBytes, store)
 }
 
 // InMemoryDBManager is a fallback implementation for testing
@@ -4,6 +4,7 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/Kisanlink/aaa-service/v2/internal/config"
@@ -11,6 +12,21 @@ import (
 	jwt "github.com/golang-jwt/jwt/v4"
 )
 
+// Organization claims
+//
+// Access tokens carry the IDs of the user's organizations in orgs, the organization downstream
+// services should act in by default in primary_org, and the IDs of the user's groups in group_ids,
+// so org-scoped decisions need no call back to this service. To keep tokens small the lists are
+// capped; a capped list is flagged with orgs_truncated or groups_truncated, and the full lists are
+// served by GET /api/v1/me/organizations and GET /api/v1/me/groups. The organizations and groups
+// in user_context are capped the same way.
+const (
+	// MaxOrgClaims is the most organizations an access token carries
+	MaxOrgClaims = 20
+	// MaxGroupClaims is the most groups an access token carries
+	MaxGroupClaims = 50
+)
+
 // OrganizationContext represents organization information in JWT
 type OrganizationContext struct {
 	ID   string `json:"id"`
@@ -49,8 +65,21 @@ type UserContext struct {
 	Groups        []GroupContext        `json:"groups"`
 }
 
+// TokenSigner signs the tokens this service issues and returns the key a token is verified with,
+// for example the current RS256 key of a rotated key ring. The functions taking a TokenSigner use
+// the HS256 secret when it is nil.
+type TokenSigner interface {
+	SignToken(claims jwt.Claims) (string, error)
+	TokenKey(token *jwt.Token) (interface{}, error)
+}
+
 // GenerateAccessTokenWithContext generates a JWT access token with comprehensive organizational context
 func GenerateAccessTokenWithContext(userID string, userRoles []models.UserRole, username, phoneNumber, countryCode string, isValidated bool, organizations []OrganizationContext, groups []GroupContext) (string, error) {
+	return GenerateAccessTokenWithSigner(nil, userID, userRoles, username, phoneNumber, countryCode, isValidated, organizations, groups)
+}
+
+// GenerateAccessTokenWithSigner is GenerateAccessTokenWithContext signing the token with signer
+func GenerateAccessTokenWithSigner(signer TokenSigner, userID string, userRoles []models.UserRole, username, phoneNumber, countryCode string, isValidated bool, organizations []OrganizationContext, groups []GroupContext) (string, error) {
 	cfg := config.LoadJWTConfigFromEnv()
 	now := time.Now()
 	iat := now.Add(-cfg.Leeway / 2)
@@ -84,6 +113,9 @@ func GenerateAccessTokenWithContext(userID string, userRoles []models.UserRole,
 		roleContexts[i] = roleContext
 	}
 
+	orgClaims := buildOrganizationClaims(userRoles, organizations, groups)
+	organizations, groups = orgClaims.organizations, orgClaims.groups
+
 	// Build comprehensive user context with provided organizations and groups
 	userContext := UserContext{
 		ID:            userID,
@@ -130,10 +162,22 @@ func GenerateAccessTokenWithContext(userID string, userRoles []models.UserRole,
 		"permissions":    extractPermissions(userRoles),
 		"scopes":         extractScopes(userRoles),
 		"tenant_context": extractTenantContext(userRoles),
+
+		// Organization context for org-scoped decisions
+		"orgs":      orgClaims.orgIDs,
+		"group_ids": orgClaims.groupIDs,
+	}
+	if orgClaims.primaryOrg != "" {
+		claims["primary_org"] = orgClaims.primaryOrg
+	}
+	if orgClaims.orgsTruncated {
+		claims["orgs_truncated"] = true
+	}
+	if orgClaims.groupsTruncated {
+		claims["groups_truncated"] = true
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(cfg.Secret))
+	return signClaims(signer, claims, cfg.Secret)
 }
 
 // GenerateAccessToken generates a JWT access token (backward compatibility wrapper)
@@ -145,6 +189,11 @@ func GenerateAccessToken(userID string, userRoles []models.UserRole, username st
 
 // GenerateRefreshToken generates a long-lived JWT refresh token with minimal context
 func GenerateRefreshToken(userID string, userRoles []models.UserRole, username string, isValidated bool) (string, error) {
+	return GenerateRefreshTokenWithSigner(nil, userID, userRoles, username, isValidated)
+}
+
+// GenerateRefreshTokenWithSigner is GenerateRefreshToken signing the token with signer
+func GenerateRefreshTokenWithSigner(signer TokenSigner, userID string, userRoles []models.UserRole, username string, isValidated bool) (string, error) {
 	cfg := config.LoadJWTConfigFromEnv()
 	now := time.Now()
 	iat := now.Add(-cfg.Leeway / 2)
@@ -180,16 +229,37 @@ func GenerateRefreshToken(userID string, userRoles []models.UserRole, username s
 		"roleIds":    roleIDs, // Only role IDs, not full objects
 	}
 
+	return signClaims(signer, claims, cfg.Secret)
+}
+
+// signClaims signs claims with signer, or with the HS256 secret when signer is nil
+func signClaims(signer TokenSigner, claims jwt.Claims, secret string) (string, error) {
+	if signer != nil {
+		return signer.SignToken(claims)
+	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(cfg.Secret))
+	return token.SignedString([]byte(secret))
 }
 
-// ValidateToken validates the JWT token and returns the user ID (sub preferred)
-func ValidateToken(tokenString string) (string, error) {
+// tokenKeyFunc returns the key lookup of signer, or one returning the HS256 secret when signer is nil
+func tokenKeyFunc(signer TokenSigner) jwt.Keyfunc {
+	if signer != nil {
+		return signer.TokenKey
+	}
 	cfg := config.LoadJWTConfigFromEnv()
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (any, error) {
+	return func(token *jwt.Token) (any, error) {
 		return []byte(cfg.Secret), nil
-	})
+	}
+}
+
+// ValidateToken validates the JWT token and returns the user ID (sub preferred)
+func ValidateToken(tokenString string) (string, error) {
+	return ValidateTokenWithSigner(nil, tokenString)
+}
+
+// ValidateTokenWithSigner is ValidateToken for tokens signed by signer
+func ValidateTokenWithSigner(signer TokenSigner, tokenString string) (string, error) {
+	token, err := jwt.Parse(tokenString, tokenKeyFunc(signer))
 	if err != nil || !token.Valid {
 		return "", err
 	}
@@ -208,10 +278,12 @@ func ValidateToken(tokenString string) (string, error) {
 
 // ValidateTokenWithContext validates the JWT token and returns comprehensive token information
 func ValidateTokenWithContext(tokenString string) (*TokenContext, error) {
-	cfg := config.LoadJWTConfigFromEnv()
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (any, error) {
-		return []byte(cfg.Secret), nil
-	})
+	return ValidateTokenWithContextAndSigner(nil, tokenString)
+}
+
+// ValidateTokenWithContextAndSigner is ValidateTokenWithContext for tokens signed by signer
+func ValidateTokenWithContextAndSigner(signer TokenSigner, tokenString string) (*TokenContext, error) {
+	token, err := jwt.Parse(tokenString, tokenKeyFunc(signer))
 	if err != nil || !token.Valid {
 		return nil, err
 	}
@@ -258,6 +330,17 @@ func ValidateTokenWithContext(tokenString string) (*TokenContext, error) {
 		tokenContext.Scopes = convertToStringSlice(scopes)
 	}
 
+	// Extract organization claims
+	if orgs, ok := claims["orgs"].([]any); ok {
+		tokenContext.OrgIDs = convertToStringSlice(orgs)
+	}
+	tokenContext.PrimaryOrg = getStringClaim(claims, "primary_org", "")
+	if groupIDs, ok := claims["group_ids"].([]any); ok {
+		tokenContext.GroupIDs = convertToStringSlice(groupIDs)
+	}
+	tokenContext.OrgsTruncated, _ = claims["orgs_truncated"].(bool)
+	tokenContext.GroupsTruncated, _ = claims["groups_truncated"].(bool)
+
 	return tokenContext, nil
 }
 
@@ -273,6 +356,12 @@ type TokenContext struct {
 	UserContext  *UserContext `json:"user_context,omitempty"`
 	Permissions  []string     `json:"permissions"`
 	Scopes       []string     `json:"scopes"`
+	// Organization claims; the lists are capped when OrgsTruncated or GroupsTruncated is set
+	OrgIDs          []string `json:"orgs,omitempty"`
+	PrimaryOrg      string   `json:"primary_org,omitempty"`
+	GroupIDs        []string `json:"group_ids,omitempty"`
+	OrgsTruncated   bool     `json:"orgs_truncated,omitempty"`
+	GroupsTruncated bool     `json:"groups_truncated,omitempty"`
 }
 
 // Helper functions for JWT generation and parsing
@@ -406,6 +495,80 @@ func extractTenantContext(userRoles []models.UserRole) map[string]any {
 	return tenantContext
 }
 
+// organizationClaims are the capped organization and group claims of an access token
+type organizationClaims struct {
+	orgIDs          []string
+	primaryOrg      string
+	groupIDs        []string
+	orgsTruncated   bool
+	groupsTruncated bool
+	// organizations and groups are the user_context entries of the organizations and groups kept
+	organizations []OrganizationContext
+	groups        []GroupContext
+}
+
+// buildOrganizationClaims orders the user's organizations by ID with the primary organization
+// first and caps them at MaxOrgClaims, and caps the groups, ordered by ID, at MaxGroupClaims. The
+// primary organization is the one most of the user's active roles are scoped to, or the first by
+// ID when no role is scoped to any of them.
+func buildOrganizationClaims(userRoles []models.UserRole, organizations []OrganizationContext, groups []GroupContext) organizationClaims {
+	roleCounts := make(map[string]int)
+	for _, userRole := range userRoles {
+		if userRole.IsActive && userRole.Role.IsActive && userRole.Role.OrganizationID != nil {
+			roleCounts[*userRole.Role.OrganizationID]++
+		}
+	}
+
+	orgs := make([]OrganizationContext, 0, len(organizations))
+	seenOrgs := make(map[string]bool)
+	for _, org := range organizations {
+		if org.ID != "" && !seenOrgs[org.ID] {
+			seenOrgs[org.ID] = true
+			orgs = append(orgs, org)
+		}
+	}
+	sort.Slice(orgs, func(i, j int) bool { return orgs[i].ID < orgs[j].ID })
+
+	result := organizationClaims{orgIDs: []string{}, groupIDs: []string{}}
+	if len(orgs) > 0 {
+		primary := 0
+		for i := range orgs {
+			if roleCounts[orgs[i].ID] > roleCounts[orgs[primary].ID] {
+				primary = i
+			}
+		}
+		result.primaryOrg = orgs[primary].ID
+		orgs = append(append([]OrganizationContext{orgs[primary]}, orgs[:primary]...), orgs[primary+1:]...)
+	}
+	if len(orgs) > MaxOrgClaims {
+		orgs = orgs[:MaxOrgClaims]
+		result.orgsTruncated = true
+	}
+	for _, org := range orgs {
+		result.orgIDs = append(result.orgIDs, org.ID)
+	}
+	result.organizations = orgs
+
+	kept := make([]GroupContext, 0, len(groups))
+	seenGroups := make(map[string]bool)
+	for _, group := range groups {
+		if group.ID != "" && !seenGroups[group.ID] {
+			seenGroups[group.ID] = true
+			kept = append(kept, group)
+		}
+	}
+	sort.Slice(kept, func(i, j int) bool { return kept[i].ID < kept[j].ID })
+	if len(kept) > MaxGroupClaims {
+		kept = kept[:MaxGroupClaims]
+		result.groupsTruncated = true
+	}
+	for _, group := range kept {
+		result.groupIDs = append(result.groupIDs, group.ID)
+	}
+	result.groups = kept
+	return result
+}
+
 // getStringClaim safely extracts a string claim with a default value
 func getStringClaim(claims jwt.MapClaims, key, defaultValue string) string {
 	if value, ok := claims[key].(string); ok {
@@ -0,0 +1,109 @@
+package helper
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/kisanlink-db/pkg/base"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// orgScopedRole returns an active user role scoped to the organization
+func orgScopedRole(roleID, orgID string) models.UserRole {
+	userRole := models.UserRole{
+		BaseModel: &base.BaseModel{},
+		UserID:    "user_123",
+		RoleID:    roleID,
+		IsActive:  true,
+		Role: models.Role{
+			BaseModel:      &base.BaseModel{},
+			Name:           roleID,
+			Scope:          models.RoleScopeOrg,
+			OrganizationID: &orgID,
+			IsActive:       true,
+		},
+	}
+	userRole.SetID("user_" + roleID)
+	userRole.Role.SetID(roleID)
+	return userRole
+}
+
+func TestOrganizationClaims_MultiOrgUser(t *testing.T) {
+	userRoles := []models.UserRole{
+		orgScopedRole("role_viewer", "org_a"),
+		orgScopedRole("role_admin", "org_c"),
+		orgScopedRole("role_editor", "org_c"),
+	}
+	organizations := []OrganizationContext{
+		{ID: "org_b", Name: "Beta Farms"},
+		{ID: "org_c", Name: "Gamma Co-op"},
+		{ID: "org_a", Name: "Alpha FPO"},
+		{ID: "org_a", Name: "Alpha FPO"},
+	}
+	groups := []GroupContext{
+		{ID: "grp_2", Name: "Field Agents", OrganizationID: "org_c"},
+		{ID: "grp_1", Name: "Admins", OrganizationID: "org_a"},
+	}
+
+	token, err := GenerateAccessTokenWithContext("user_123", userRoles, "john_doe", "9876543210", "+91", true, organizations, groups)
+	require.NoError(t, err)
+
+	tokenContext, err := ValidateTokenWithContext(token)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"org_c", "org_a", "org_b"}, tokenContext.OrgIDs,
+		"the primary organization comes first, then the rest by ID without duplicates")
+	assert.Equal(t, "org_c", tokenContext.PrimaryOrg, "most of the user's roles are scoped to org_c")
+	assert.Equal(t, []string{"grp_1", "grp_2"}, tokenContext.GroupIDs)
+	assert.False(t, tokenContext.OrgsTruncated)
+	assert.False(t, tokenContext.GroupsTruncated)
+
+	require.NotNil(t, tokenContext.UserContext)
+	assert.Len(t, tokenContext.UserContext.Organizations, 3)
+	assert.Len(t, tokenContext.UserContext.Groups, 2)
+}
+
+func TestOrganizationClaims_PrimaryWithoutScopedRoles(t *testing.T) {
+	organizations := []OrganizationContext{{ID: "org_z"}, {ID: "org_m"}}
+
+	token, err := GenerateAccessTokenWithContext("user_123", nil, "john_doe", "9876543210", "+91", true, organizations, nil)
+	require.NoError(t, err)
+
+	tokenContext, err := ValidateTokenWithContext(token)
+	require.NoError(t, err)
+
+	assert.Equal(t, "org_m", tokenContext.PrimaryOrg, "the first organization by ID")
+	assert.Equal(t, []string{"org_m", "org_z"}, tokenContext.OrgIDs)
+	assert.Empty(t, tokenContext.GroupIDs)
+}
+
+func TestOrganizationClaims_Truncated(t *testing.T) {
+	var organizations []OrganizationContext
+	for i := 0; i < MaxOrgClaims+5; i++ {
+		organizations = append(organizations, OrganizationContext{ID: fmt.Sprintf("org_%02d", i)})
+	}
+	var groups []GroupContext
+	for i := 0; i < MaxGroupClaims+1; i++ {
+		groups = append(groups, GroupContext{ID: fmt.Sprintf("grp_%02d", i)})
+	}
+	userRoles := []models.UserRole{orgScopedRole("role_admin", "org_24")}
+
+	token, err := GenerateAccessTokenWithContext("user_123", userRoles, "john_doe", "9876543210", "+91", true, organizations, groups)
+	require.NoError(t, err)
+
+	tokenContext, err := ValidateTokenWithContext(token)
+	require.NoError(t, err)
+
+	require.Len(t, tokenContext.OrgIDs, MaxOrgClaims)
+	assert.Equal(t, "org_24", tokenContext.OrgIDs[0], "the primary organization is kept ahead of the cap")
+	assert.Equal(t, "org_24", tokenContext.PrimaryOrg)
+	assert.True(t, tokenContext.OrgsTruncated)
+	assert.Len(t, tokenContext.GroupIDs, MaxGroupClaims)
+	assert.True(t, tokenContext.GroupsTruncated)
+
+	require.NotNil(t, tokenContext.UserContext)
+	assert.Len(t, tokenContext.UserContext.Organizations, MaxOrgClaims)
+	assert.Len(t, tokenContext.UserContext.Groups, MaxGroupClaims)
+}
@@ -47,9 +47,13 @@ type JWTConfig struct {
 	Issuer   string        `mapstructure:"issuer"`
 	Audience string        `mapstructure:"audience"`
 	TTL      time.Duration `mapstructure:"ttl"`
-	Leeway   time.Duration `mapstructure:"leeway"`
+	// Leeway is the clock skew tolerated when validating exp, nbf and iat
+	Leeway time.Duration `mapstructure:"leeway"`
 }
 
+// DefaultJWTLeeway is the clock skew tolerated when AAA_JWT_LEEWAY is not set
+const DefaultJWTLeeway = 60 * time.Second
+
 // LoadJWTConfigFromEnv loads JWT configuration from environment variables.
 // Variables:
 //
@@ -57,10 +61,13 @@ type JWTConfig struct {
 //	AAA_JWT_ISSUER
 //	AAA_JWT_AUDIENCE
 //	AAA_JWT_TTL (e.g., "24h")
-//	AAA_JWT_LEEWAY (optional; default 2m)
+//	AAA_JWT_LEEWAY (optional; clock skew tolerance, default 60s; "0s" disables)
 func LoadJWTConfigFromEnv() *JWTConfig {
 	ttl := parseDurationWithDefault(getenv("AAA_JWT_TTL", "24h"), 24*time.Hour)
-	leeway := parseDurationWithDefault(getenv("AAA_JWT_LEEWAY", ""), 0)
+	leeway := parseDurationWithDefault(getenv("AAA_JWT_LEEWAY", ""), DefaultJWTLeeway)
+	if leeway < 0 {
+		leeway = DefaultJWTLeeway
+	}
 	cfg := &JWTConfig{
 		Secret:   getenv("AAA_JWT_SECRET", ""),
 		Issuer:   getenv("AAA_JWT_ISSUER", "aaa-service"),
@@ -68,9 +75,6 @@ func LoadJWTConfigFromEnv() *JWTConfig {
 		TTL:      ttl,
 		Leeway:   leeway,
 	}
-	if cfg.Leeway == 0 {
-		cfg.Leeway = 2 * time.Minute
-	}
 	return cfg
 }
 
@@ -0,0 +1,72 @@
+package config
+
+import (
+	"crypto/tls"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadJWTConfigFromEnv_Leeway(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{value: "", want: DefaultJWTLeeway},
+		{value: "30s", want: 30 * time.Second},
+		{value: "0s", want: 0},
+		{value: "-5s", want: DefaultJWTLeeway},
+		{value: "soon", want: DefaultJWTLeeway},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Setenv("AAA_JWT_LEEWAY", tt.value)
+			assert.Equal(t, tt.want, LoadJWTConfigFromEnv().Leeway)
+		})
+	}
+}
+
+func TestLoadDatabaseConfig_ConnMaxLifetime(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{value: "", want: 5 * time.Minute},
+		{value: "30m", want: 30 * time.Minute},
+		{value: "forever", want: 5 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Setenv("DB_POSTGRES_CONN_MAX_LIFETIME", tt.value)
+			assert.Equal(t, tt.want, LoadDatabaseConfig().Postgres.ConnMaxLifetime)
+		})
+	}
+}
+
+func TestLoadServerTLSConfig(t *testing.T) {
+	t.Setenv("AAA_TLS_CERT_FILE", "")
+	t.Setenv("AAA_TLS_KEY_FILE", "")
+	t.Setenv("AAA_TLS_MIN_VERSION", "")
+	cfg, err := LoadServerTLSConfig()
+	assert.NoError(t, err)
+	assert.False(t, cfg.Enabled())
+	assert.Equal(t, uint16(tls.VersionTLS12), cfg.MinVersion)
+
+	t.Setenv("AAA_TLS_CERT_FILE", "/etc/aaa/tls.crt")
+	_, err = LoadServerTLSConfig()
+	assert.Error(t, err, "a certificate without its key is rejected")
+
+	t.Setenv("AAA_TLS_KEY_FILE", "/etc/aaa/tls.key")
+	t.Setenv("AAA_TLS_MIN_VERSION", "TLS1.3")
+	cfg, err = LoadServerTLSConfig()
+	assert.NoError(t, err)
+	assert.True(t, cfg.Enabled())
+	assert.Equal(t, uint16(tls.VersionTLS13), cfg.TLSConfig().MinVersion)
+
+	t.Setenv("AAA_TLS_MIN_VERSION", "1.0")
+	_, err = LoadServerTLSConfig()
+	assert.Error(t, err, "versions older than TLS 1.2 are rejected")
+}
@@ -2,11 +2,17 @@ package config
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/repositories/querytimeout"
+	"github.com/Kisanlink/aaa-service/v2/internal/repositories/tenancy"
+	"github.com/Kisanlink/aaa-service/v2/internal/repositories/utctime"
+	"github.com/Kisanlink/aaa-service/v2/migrations"
 	"github.com/Kisanlink/kisanlink-db/pkg/db"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
@@ -17,6 +23,7 @@ type DatabaseConfig struct {
 	PrimaryBackend db.BackendType
 	Postgres       PostgresConfig
 	DynamoDB       DynamoDBConfig
+	Tenancy        TenancyConfig
 }
 
 // PostgresConfig holds PostgreSQL configuration
@@ -30,6 +37,20 @@ type PostgresConfig struct {
 	MaxConns     int
 	IdleConns    int
 	ReadReplicas []string
+	// ConnMaxLifetime closes connections older than this so they are recycled across failovers
+	ConnMaxLifetime time.Duration
+	// QueryTimeout bounds the run time of a single statement; zero leaves statements unbounded
+	QueryTimeout time.Duration
+}
+
+// TenancyConfig holds the per-organization schema isolation configuration
+type TenancyConfig struct {
+	// SchemaIsolation routes the isolated organizations' data to their own schema; off by default
+	SchemaIsolation bool
+	// IsolatedOrgs lists the organizations whose schema has been provisioned
+	IsolatedOrgs []string
+	// MaxConns caps the connection pool of each tenant schema
+	MaxConns int
 }
 
 // DynamoDBConfig holds DynamoDB configuration
@@ -52,20 +73,145 @@ func LoadDatabaseConfig() *DatabaseConfig {
 			MaxConns:     getEnvAsInt("DB_POSTGRES_MAX_CONNS", 10),
 			IdleConns:    getEnvAsInt("DB_POSTGRES_IDLE_CONNS", 5),
 			ReadReplicas: getEnvAsSlice("DB_POSTGRES_READ_REPLICAS", ","),
+			// kisanlink-db hard-codes 5 minutes, so the same default keeps existing deployments unchanged
+			ConnMaxLifetime: getEnvAsDuration("DB_POSTGRES_CONN_MAX_LIFETIME", 5*time.Minute),
+			QueryTimeout:    getEnvAsDuration("DB_POSTGRES_QUERY_TIMEOUT", 30*time.Second),
 		},
 		DynamoDB: DynamoDBConfig{
 			Region: getEnv("DB_DYNAMO_REGION", "us-east-1"),
 			Table:  getEnv("DB_DYNAMO_TABLE", ""),
 		},
+		Tenancy: TenancyConfig{
+			SchemaIsolation: getEnv("AAA_TENANT_SCHEMA_ISOLATION", "false") == "true",
+			IsolatedOrgs:    getEnvAsSlice("AAA_TENANT_SCHEMA_ORGS", ","),
+			MaxConns:        getEnvAsInt("AAA_TENANT_DB_MAX_CONNS", 5),
+		},
 	}
 }
 
-// NewDatabaseManager creates a new database manager with the loaded configuration
-func NewDatabaseManager(logger *zap.Logger) (*db.DatabaseManager, error) {
+// NewDatabaseManager creates a new database manager with the loaded configuration. The username
+// policy decides which username indexes automigration creates.
+func NewDatabaseManager(usernames UsernamePolicyConfig, logger *zap.Logger) (*db.DatabaseManager, error) {
 	logger.Info("Loading database configuration")
 	config := LoadDatabaseConfig()
 
-	dbConfig := &db.Config{
+	dm := db.NewDatabaseManagerWithConfig(config.dbConfig())
+
+	// Connect to the database
+	if err := dm.Connect(context.Background()); err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %s", sanitizeError(err.Error()))
+	}
+
+	if err := applyPoolSettings(dm, config.Postgres, logger); err != nil {
+		logger.Warn("Failed to apply database pool settings", zap.Error(err))
+	}
+	if postgresManager := dm.GetPostgresManager(); postgresManager != nil {
+		if err := applyQueryTimeout(postgresManager, len(config.Postgres.ReadReplicas), config.Postgres.QueryTimeout); err != nil {
+			return nil, fmt.Errorf("failed to apply database query timeout: %w", err)
+		}
+		logger.Info("Database query timeout configured", zap.Duration("query_timeout", config.Postgres.QueryTimeout))
+		if err := applyUTCTimestamps(postgresManager, len(config.Postgres.ReadReplicas)); err != nil {
+			return nil, fmt.Errorf("failed to apply UTC timestamps: %w", err)
+		}
+	}
+
+	// Run automigration for all models if enabled
+	if getEnv("AAA_AUTO_MIGRATE", "false") == "true" {
+		if err := runAutomigration(dm, usernames, logger); err != nil {
+			return nil, fmt.Errorf("failed to run automigration: %s", sanitizeError(err.Error()))
+		}
+	} else {
+		logger.Info("Skipping automigration; AAA_AUTO_MIGRATE is not true")
+	}
+
+	logger.Info("Database manager initialized successfully")
+	return dm, nil
+}
+
+// SQLDB returns the connection pool behind a PostgreSQL database manager, whose Stats report
+// pool saturation
+func SQLDB(manager db.DBManager) (*sql.DB, error) {
+	gormManager, ok := manager.(interface {
+		GetDB(context.Context, bool) (*gorm.DB, error)
+	})
+	if !ok {
+		return nil, fmt.Errorf("database manager does not expose a connection pool")
+	}
+
+	gormDB, err := gormManager.GetDB(context.Background(), false)
+	if err != nil {
+		return nil, err
+	}
+	return gormDB.DB()
+}
+
+// applyPoolSettings sizes the primary connection pool. kisanlink-db applies the connection
+// limits when it connects but not a configurable connection lifetime, so all three are set here.
+func applyPoolSettings(dm *db.DatabaseManager, postgres PostgresConfig, logger *zap.Logger) error {
+	postgresManager := dm.GetPostgresManager()
+	if postgresManager == nil {
+		return nil
+	}
+
+	sqlDB, err := SQLDB(postgresManager)
+	if err != nil {
+		return err
+	}
+
+	sqlDB.SetMaxOpenConns(postgres.MaxConns)
+	sqlDB.SetMaxIdleConns(postgres.IdleConns)
+	sqlDB.SetConnMaxLifetime(postgres.ConnMaxLifetime)
+
+	logger.Info("Database pool configured",
+		zap.Int("max_open_conns", postgres.MaxConns),
+		zap.Int("max_idle_conns", postgres.IdleConns),
+		zap.Duration("conn_max_lifetime", postgres.ConnMaxLifetime))
+	return nil
+}
+
+// applyQueryTimeout bounds the statements of the primary connection and of each read replica
+func applyQueryTimeout(manager db.DBManager, replicas int, timeout time.Duration) error {
+	return forEachConnection(manager, replicas, func(gormDB *gorm.DB) error {
+		return querytimeout.Register(gormDB, timeout)
+	})
+}
+
+// applyUTCTimestamps stores the timestamps written through the primary connection and each read
+// replica in UTC
+func applyUTCTimestamps(manager db.DBManager, replicas int) error {
+	return forEachConnection(manager, replicas, utctime.Register)
+}
+
+// forEachConnection calls fn once for the primary connection and once for each read replica,
+// which the manager hands out in turn
+func forEachConnection(manager db.DBManager, replicas int, fn func(*gorm.DB) error) error {
+	gormManager, ok := manager.(interface {
+		GetDB(context.Context, bool) (*gorm.DB, error)
+	})
+	if !ok {
+		return nil
+	}
+
+	applied := make(map[*gorm.DB]bool, replicas+1)
+	for i := 0; i <= replicas; i++ {
+		gormDB, err := gormManager.GetDB(context.Background(), i > 0)
+		if err != nil {
+			return err
+		}
+		if applied[gormDB] {
+			continue
+		}
+		if err := fn(gormDB); err != nil {
+			return err
+		}
+		applied[gormDB] = true
+	}
+	return nil
+}
+
+// dbConfig converts the configuration to the kisanlink-db connection configuration
+func (config *DatabaseConfig) dbConfig() *db.Config {
+	return &db.Config{
 		PrimaryBackend:       config.PrimaryBackend,
 		PostgresHost:         config.Postgres.Host,
 		PostgresPort:         config.Postgres.Port,
@@ -80,29 +226,46 @@ func NewDatabaseManager(logger *zap.Logger) (*db.DatabaseManager, error) {
 		DynamoDBTable:        config.DynamoDB.Table,
 		LogLevel:             getEnv("DB_LOG_LEVEL", "info"),
 	}
+}
 
-	dm := db.NewDatabaseManagerWithConfig(dbConfig)
-
-	// Connect to the database
-	if err := dm.Connect(context.Background()); err != nil {
-		return nil, fmt.Errorf("failed to connect to database: %s", sanitizeError(err.Error()))
+// NewTenantRouter connects the schema of every isolated organization and returns a router that
+// sends repository queries to the schema selected in the request context. It returns nil when
+// schema isolation is disabled, which keeps the single-schema mode.
+func NewTenantRouter(ctx context.Context, shared db.DBManager, logger *zap.Logger) (*tenancy.Router, error) {
+	config := LoadDatabaseConfig()
+	if !config.Tenancy.SchemaIsolation {
+		return nil, nil
 	}
 
-	// Run automigration for all models if enabled
-	if getEnv("AAA_AUTO_MIGRATE", "false") == "true" {
-		if err := runAutomigration(dm, logger); err != nil {
-			return nil, fmt.Errorf("failed to run automigration: %s", sanitizeError(err.Error()))
+	tenants := make(map[string]db.DBManager, len(config.Tenancy.IsolatedOrgs))
+	for _, orgID := range config.Tenancy.IsolatedOrgs {
+		orgID = strings.TrimSpace(orgID)
+		if orgID == "" {
+			continue
 		}
-	} else {
-		logger.Info("Skipping automigration; AAA_AUTO_MIGRATE is not true")
+		schema := tenancy.SchemaName(orgID)
+		if _, exists := tenants[schema]; exists {
+			continue
+		}
+		manager, err := tenancy.ConnectSchema(ctx, *config.dbConfig(), schema, config.Tenancy.MaxConns, logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect tenant schema for organization %s: %s", orgID, sanitizeError(err.Error()))
+		}
+		if err := applyQueryTimeout(manager, len(config.Postgres.ReadReplicas), config.Postgres.QueryTimeout); err != nil {
+			return nil, fmt.Errorf("failed to apply query timeout to tenant schema for organization %s: %w", orgID, err)
+		}
+		if err := applyUTCTimestamps(manager, len(config.Postgres.ReadReplicas)); err != nil {
+			return nil, fmt.Errorf("failed to apply UTC timestamps to tenant schema for organization %s: %w", orgID, err)
+		}
+		tenants[schema] = manager
 	}
 
-	logger.Info("Database manager initialized successfully")
-	return dm, nil
+	logger.Info("Tenant schema isolation enabled", zap.Int("isolated_organizations", len(tenants)))
+	return tenancy.NewRouter(shared, tenants, logger), nil
 }
 
 // runAutomigration runs automigration for all models
-func runAutomigration(dm *db.DatabaseManager, logger *zap.Logger) error {
+func runAutomigration(dm *db.DatabaseManager, usernames UsernamePolicyConfig, logger *zap.Logger) error {
 	logger.Info("Starting automigration")
 
 	// Import models from aaa-service
@@ -112,11 +275,17 @@ func runAutomigration(dm *db.DatabaseManager, logger *zap.Logger) error {
 		&models.UserProfile{},
 		&models.Contact{},
 		&models.Address{},
+		&models.UserIdentity{},
+		&models.UserDevice{},
 		&models.Service{},
 		&models.Principal{},
 
 		// Organization and groups
 		&models.Organization{},
+		&models.OrganizationMember{},
+		&models.OrganizationSetting{},
+		&models.OrganizationUserAttributeSchema{},
+		&models.OrganizationUserAttributes{},
 		&models.Group{},
 		&models.GroupMembership{},
 		&models.GroupInheritance{},
@@ -127,9 +296,12 @@ func runAutomigration(dm *db.DatabaseManager, logger *zap.Logger) error {
 		&models.Permission{},
 		&models.UserRole{},
 		&models.Action{},
-		&models.RolePermission{},     // Role-Permission mapping
-		&models.ResourcePermission{}, // Resource-Role-Action mapping
-		&models.ServiceRoleMapping{}, // Service-Role mapping for audit trail
+		&models.RolePermission{},        // Role-Permission mapping
+		&models.ResourcePermission{},    // Resource-Role-Action mapping
+		&models.ServiceRoleMapping{},    // Service-Role mapping for audit trail
+		&models.RoleTemplate{},          // Admin-defined role templates
+		&models.RoleConstraint{},        // Organization role assignment constraints
+		&models.PendingRoleAssignment{}, // KYC-gated role assignments awaiting Aadhaar verification
 
 		// Resources
 		&models.Resource{},
@@ -151,10 +323,14 @@ func runAutomigration(dm *db.DatabaseManager, logger *zap.Logger) error {
 		&models.AuditLog{},
 		&models.Event{},
 		&models.EventCheckpoint{},
+		&models.OutboxEvent{},
 
 		// Password reset and SMS
 		&models.PasswordResetToken{},
 		&models.SMSDeliveryLog{},
+
+		// User-initiated account deletion
+		&models.AccountDeletionRequest{},
 	}
 
 	logger.Info("Models to migrate", zap.Int("count", len(allModels)))
@@ -195,6 +371,25 @@ func runAutomigration(dm *db.DatabaseManager, logger *zap.Logger) error {
 					logger.Info("Successfully modified username column size to match model definition")
 				}
 			}
+
+			// Widen phone_number from VARCHAR(10) so normalized national numbers of other regions fit
+			if migrator.HasColumn(&models.User{}, "phone_number") {
+				if err := migrator.AlterColumn(&models.User{}, "phone_number"); err != nil {
+					logger.Warn("Failed to alter phone_number column", zap.Error(err))
+				}
+			}
+
+			// Phone numbers are unique among live users only, so soft-deleted accounts do not block re-registration
+			if err := migrations.AddUsersPhonePartialUniqueIndex(ctx, gormDB, logger); err != nil {
+				logger.Warn("Failed to apply users phone partial unique index", zap.Error(err))
+			}
+
+			// Usernames differing only in case are taken unless the policy turns it off
+			if usernames.Unique {
+				if err := migrations.AddUsersUsernameCaseInsensitiveIndex(ctx, gormDB, logger); err != nil {
+					logger.Warn("Failed to apply users username case-insensitive index", zap.Error(err))
+				}
+			}
 		}
 	} else {
 		logger.Warn("Database manager does not support GetDB method, skipping column modifications")
@@ -223,6 +418,16 @@ func getEnvAsInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if duration, err := time.ParseDuration(value); err == nil {
+			return duration
+		}
+		fmt.Printf("Warning: Invalid duration value for %s: %s, using default %s\n", key, value, defaultValue)
+	}
+	return defaultValue
+}
+
 func getEnvAsSlice(key, separator string) []string {
 	if value := os.Getenv(key); value != "" {
 		return strings.Split(value, separator)
@@ -0,0 +1,54 @@
+package config
+
+import (
+	"strings"
+)
+
+// OIDCProviderConfig holds the client configuration for a single external OIDC identity provider
+type OIDCProviderConfig struct {
+	Name         string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+}
+
+// LoadOIDCProvidersFromEnv loads external identity provider configuration from environment variables.
+// Variables:
+//
+//	AAA_OIDC_PROVIDERS (comma-separated provider names, e.g. "google,azure")
+//	AAA_OIDC_<NAME>_ISSUER (required)
+//	AAA_OIDC_<NAME>_CLIENT_ID (required)
+//	AAA_OIDC_<NAME>_CLIENT_SECRET (required)
+//	AAA_OIDC_<NAME>_REDIRECT_URL (required; must point at the callback route)
+//	AAA_OIDC_<NAME>_SCOPES (optional; default "openid,email,profile,phone")
+//
+// Providers with missing required values are skipped. An empty map means OIDC login is disabled.
+func LoadOIDCProvidersFromEnv() map[string]*OIDCProviderConfig {
+	providers := make(map[string]*OIDCProviderConfig)
+
+	for _, name := range getEnvStringSlice("AAA_OIDC_PROVIDERS", nil) {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+
+		prefix := "AAA_OIDC_" + strings.ToUpper(name) + "_"
+		cfg := &OIDCProviderConfig{
+			Name:         name,
+			Issuer:       strings.TrimSuffix(getEnvString(prefix+"ISSUER", ""), "/"),
+			ClientID:     getEnvString(prefix+"CLIENT_ID", ""),
+			ClientSecret: getEnvString(prefix+"CLIENT_SECRET", ""),
+			RedirectURL:  getEnvString(prefix+"REDIRECT_URL", ""),
+			Scopes:       getEnvStringSlice(prefix+"SCOPES", []string{"openid", "email", "profile", "phone"}),
+		}
+		if cfg.Issuer == "" || cfg.ClientID == "" || cfg.ClientSecret == "" || cfg.RedirectURL == "" {
+			continue
+		}
+
+		providers[name] = cfg
+	}
+
+	return providers
+}
@@ -0,0 +1,71 @@
+package config
+
+import (
+	"strings"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+)
+
+// OrganizationQuota limits the API requests made in one organization. A zero limit is unlimited.
+type OrganizationQuota struct {
+	RequestsPerMinute int64
+	MonthlyRequests   int64
+}
+
+// Unlimited reports whether the quota limits nothing
+func (q OrganizationQuota) Unlimited() bool {
+	return q.RequestsPerMinute <= 0 && q.MonthlyRequests <= 0
+}
+
+// OrganizationQuotaConfig holds the per-organization rate limits and monthly quotas
+type OrganizationQuotaConfig struct {
+	Enabled bool
+	// Default applies to organization types without their own quota
+	Default OrganizationQuota
+	// TypeQuotas holds the quota of each organization type, keyed by models.OrgType*
+	TypeQuotas map[string]OrganizationQuota
+}
+
+// defaultOrganizationTypeQuotas are the built-in quotas of the organization types that differ from
+// the default
+var defaultOrganizationTypeQuotas = map[string]OrganizationQuota{
+	models.OrgTypeIndividual:    {RequestsPerMinute: 120, MonthlyRequests: 100000},
+	models.OrgTypeSmallBusiness: {RequestsPerMinute: 300, MonthlyRequests: 500000},
+	models.OrgTypeEnterprise:    {RequestsPerMinute: 3000, MonthlyRequests: 0},
+	models.OrgTypeGovernment:    {RequestsPerMinute: 3000, MonthlyRequests: 0},
+}
+
+// QuotaFor returns the quota of organizations of orgType
+func (c OrganizationQuotaConfig) QuotaFor(orgType string) OrganizationQuota {
+	if quota, ok := c.TypeQuotas[orgType]; ok {
+		return quota
+	}
+	return c.Default
+}
+
+// LoadOrganizationQuotaConfig loads the organization quotas from environment variables.
+// AAA_ORG_RATE_LIMIT_PER_MINUTE and AAA_ORG_MONTHLY_QUOTA set the default quota, and the same
+// variables suffixed with an upper-cased organization type (e.g. AAA_ORG_MONTHLY_QUOTA_FPO)
+// override it for that type.
+func LoadOrganizationQuotaConfig() OrganizationQuotaConfig {
+	cfg := OrganizationQuotaConfig{
+		Enabled: getEnvBool("AAA_ORG_QUOTAS_ENABLED", false),
+		Default: OrganizationQuota{
+			RequestsPerMinute: getEnvInt64("AAA_ORG_RATE_LIMIT_PER_MINUTE", 600),
+			MonthlyRequests:   getEnvInt64("AAA_ORG_MONTHLY_QUOTA", 1000000),
+		},
+		TypeQuotas: make(map[string]OrganizationQuota),
+	}
+
+	for _, orgType := range models.OrganizationTypes() {
+		quota, ok := defaultOrganizationTypeQuotas[orgType]
+		if !ok {
+			quota = cfg.Default
+		}
+		suffix := "_" + strings.ToUpper(orgType)
+		quota.RequestsPerMinute = getEnvInt64("AAA_ORG_RATE_LIMIT_PER_MINUTE"+suffix, quota.RequestsPerMinute)
+		quota.MonthlyRequests = getEnvInt64("AAA_ORG_MONTHLY_QUOTA"+suffix, quota.MonthlyRequests)
+		cfg.TypeQuotas[orgType] = quota
+	}
+	return cfg
+}
@@ -0,0 +1,23 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadOrganizationQuotaConfig_TypeDefaultsAndOverrides(t *testing.T) {
+	t.Setenv("AAA_ORG_QUOTAS_ENABLED", "true")
+	t.Setenv("AAA_ORG_RATE_LIMIT_PER_MINUTE", "500")
+	t.Setenv("AAA_ORG_MONTHLY_QUOTA_FPO", "20000")
+
+	cfg := LoadOrganizationQuotaConfig()
+
+	assert.True(t, cfg.Enabled)
+	assert.Equal(t, OrganizationQuota{RequestsPerMinute: 500, MonthlyRequests: 1000000}, cfg.Default)
+	assert.Equal(t, OrganizationQuota{RequestsPerMinute: 500, MonthlyRequests: 20000}, cfg.QuotaFor(models.OrgTypeFPO))
+	assert.Equal(t, OrganizationQuota{RequestsPerMinute: 120, MonthlyRequests: 100000}, cfg.QuotaFor(models.OrgTypeIndividual))
+	assert.Zero(t, cfg.QuotaFor(models.OrgTypeEnterprise).MonthlyRequests, "enterprises have no monthly quota")
+	assert.Equal(t, cfg.Default, cfg.QuotaFor("unknown"))
+}
@@ -31,6 +31,12 @@ type SecurityConfig struct {
 
 	// Cookie configuration
 	Cookie CookieConfig
+
+	// Stepped login configuration
+	LoginStepUp LoginStepUpConfig
+
+	// Long-lived "remember me" session configuration
+	DeviceSessions DeviceSessionConfig
 }
 
 // RateLimitConfig holds general rate limiting configuration
@@ -68,16 +74,24 @@ type InputValidationConfig struct {
 
 // SecurityHeadersConfig holds security headers configuration
 type SecurityHeadersConfig struct {
-	EnableHSTS               bool
-	HSTSMaxAge               int
-	HSTSIncludeSubdomains    bool
-	HSTSPreload              bool
-	ContentSecurityPolicy    string
-	ReferrerPolicy           string
-	PermissionsPolicy        string
-	EnableXSSProtection      bool
-	EnableContentTypeNoSniff bool
-	FrameOptions             string
+	// Enabled turns all security headers on or off
+	Enabled               bool
+	EnableHSTS            bool
+	HSTSMaxAge            int
+	HSTSIncludeSubdomains bool
+	HSTSPreload           bool
+	EnableCSP             bool
+	ContentSecurityPolicy string
+	// DocsContentSecurityPolicy applies to the API reference UI under /docs, which loads its
+	// scripts, styles and fonts from a CDN
+	DocsContentSecurityPolicy string
+	EnableReferrerPolicy      bool
+	ReferrerPolicy            string
+	PermissionsPolicy         string
+	EnableXSSProtection       bool
+	EnableContentTypeNoSniff  bool
+	EnableFrameOptions        bool
+	FrameOptions              string
 }
 
 // CORSConfig holds CORS configuration
@@ -117,6 +131,26 @@ type CookieConfig struct {
 	Domain string
 }
 
+// LoginStepUpConfig holds configuration for logins that need a second step
+type LoginStepUpConfig struct {
+	// RequireMPin makes a password login of a user who has an MPIN return an MPIN challenge
+	// instead of tokens; the tokens are issued once the MPIN is verified
+	RequireMPin bool
+	// ChallengeTTL is how long a login challenge can be redeemed
+	ChallengeTTL time.Duration
+	// MaxAttempts is the number of wrong MPINs after which the challenge is discarded
+	MaxAttempts int
+}
+
+// DeviceSessionConfig holds configuration for the long-lived sessions of remembered devices
+type DeviceSessionConfig struct {
+	// TTL is how long a session token can be exchanged for access tokens
+	TTL time.Duration
+	// MaxPerUser is the number of sessions a user can hold; the least recently used is revoked
+	// when a new one would exceed it
+	MaxPerUser int
+}
+
 // LoadSecurityConfig loads security configuration from environment variables
 func LoadSecurityConfig() *SecurityConfig {
 	return &SecurityConfig{
@@ -150,16 +184,21 @@ func LoadSecurityConfig() *SecurityConfig {
 			}),
 		},
 		SecurityHeaders: SecurityHeadersConfig{
-			EnableHSTS:               getEnvBool("AAA_ENABLE_HSTS", true),
-			HSTSMaxAge:               getEnvInt("AAA_HSTS_MAX_AGE", 31536000), // 1 year
-			HSTSIncludeSubdomains:    getEnvBool("AAA_HSTS_INCLUDE_SUBDOMAINS", true),
-			HSTSPreload:              getEnvBool("AAA_HSTS_PRELOAD", false),
-			ContentSecurityPolicy:    getEnvString("AAA_CSP", "default-src 'self'; script-src 'self' 'unsafe-inline' 'unsafe-eval' https://cdn.jsdelivr.net https://unpkg.com; style-src 'self' 'unsafe-inline' https://cdn.jsdelivr.net https://unpkg.com; img-src 'self' data: https:; font-src 'self' https://cdn.jsdelivr.net https://unpkg.com; connect-src 'self'; frame-ancestors 'none'; base-uri 'self'; form-action 'self'"),
-			ReferrerPolicy:           getEnvString("AAA_REFERRER_POLICY", "strict-origin-when-cross-origin"),
-			PermissionsPolicy:        getEnvString("AAA_PERMISSIONS_POLICY", "camera=(), microphone=(), geolocation=()"),
-			EnableXSSProtection:      getEnvBool("AAA_ENABLE_XSS_PROTECTION", true),
-			EnableContentTypeNoSniff: getEnvBool("AAA_ENABLE_CONTENT_TYPE_NOSNIFF", true),
-			FrameOptions:             getEnvString("AAA_FRAME_OPTIONS", "DENY"),
+			Enabled:                   getEnvBool("AAA_SECURITY_HEADERS_ENABLED", true),
+			EnableHSTS:                getEnvBool("AAA_ENABLE_HSTS", true),
+			HSTSMaxAge:                getEnvInt("AAA_HSTS_MAX_AGE", 31536000), // 1 year
+			HSTSIncludeSubdomains:     getEnvBool("AAA_HSTS_INCLUDE_SUBDOMAINS", true),
+			HSTSPreload:               getEnvBool("AAA_HSTS_PRELOAD", false),
+			EnableCSP:                 getEnvBool("AAA_ENABLE_CSP", true),
+			ContentSecurityPolicy:     getEnvString("AAA_CSP", "default-src 'self'; script-src 'self' 'unsafe-inline' 'unsafe-eval' https://cdn.jsdelivr.net https://unpkg.com; style-src 'self' 'unsafe-inline' https://cdn.jsdelivr.net https://unpkg.com; img-src 'self' data: https:; font-src 'self' https://cdn.jsdelivr.net https://unpkg.com; connect-src 'self'; frame-ancestors 'none'; base-uri 'self'; form-action 'self'"),
+			DocsContentSecurityPolicy: getEnvString("AAA_DOCS_CSP", "default-src 'self'; script-src 'self' 'unsafe-inline' 'unsafe-eval' https://cdn.jsdelivr.net; style-src 'self' 'unsafe-inline' https://cdn.jsdelivr.net https://fonts.scalar.com; img-src 'self' data: https:; font-src 'self' data: https://cdn.jsdelivr.net https://fonts.scalar.com; connect-src 'self'; worker-src 'self' blob:; frame-ancestors 'none'; base-uri 'self'; form-action 'self'"),
+			EnableReferrerPolicy:      getEnvBool("AAA_ENABLE_REFERRER_POLICY", true),
+			ReferrerPolicy:            getEnvString("AAA_REFERRER_POLICY", "strict-origin-when-cross-origin"),
+			PermissionsPolicy:         getEnvString("AAA_PERMISSIONS_POLICY", "camera=(), microphone=(), geolocation=()"),
+			EnableXSSProtection:       getEnvBool("AAA_ENABLE_XSS_PROTECTION", true),
+			EnableContentTypeNoSniff:  getEnvBool("AAA_ENABLE_CONTENT_TYPE_NOSNIFF", true),
+			EnableFrameOptions:        getEnvBool("AAA_ENABLE_FRAME_OPTIONS", true),
+			FrameOptions:              getEnvString("AAA_FRAME_OPTIONS", "DENY"),
 		},
 		CORS: CORSConfig{
 			AllowedOrigins: getEnvStringSlice("AAA_CORS_ALLOWED_ORIGINS", []string{"*"}),
@@ -204,6 +243,15 @@ func LoadSecurityConfig() *SecurityConfig {
 			// Empty string means cookies are only valid for the exact host
 			Domain: getEnvString("AAA_COOKIE_DOMAIN", ""),
 		},
+		LoginStepUp: LoginStepUpConfig{
+			RequireMPin:  getEnvBool("AAA_LOGIN_REQUIRE_MPIN", false),
+			ChallengeTTL: getEnvDuration("AAA_LOGIN_CHALLENGE_TTL", 5*time.Minute),
+			MaxAttempts:  getEnvInt("AAA_LOGIN_CHALLENGE_MAX_ATTEMPTS", 5),
+		},
+		DeviceSessions: DeviceSessionConfig{
+			TTL:        getEnvDuration("AAA_DEVICE_SESSION_TTL", 30*24*time.Hour),
+			MaxPerUser: getEnvInt("AAA_DEVICE_SESSION_MAX", 10),
+		},
 	}
 }
 
@@ -0,0 +1,662 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultJWTSecret is the JWT secret used when JWT_SECRET is not set; it is rejected in production
+const DefaultJWTSecret = "default-secret-key-change-in-production"
+
+// Config is the server configuration, loaded and validated once at startup by Load. Settings
+// that are not set take their defaults; settings that are set but invalid fail the startup.
+type Config struct {
+	// Environment is APP_ENV, used to reject development defaults in production
+	Environment string
+
+	HTTPPort  string
+	GRPCPort  string
+	JWTSecret string
+	// RunSeed runs the database seeding scripts at startup (AAA_RUN_SEED)
+	RunSeed bool
+	// LogRedactKeys are redacted from request logs and audit details on top of the built-in list
+	LogRedactKeys []string
+
+	Cache      CacheConfig
+	S3         S3Config
+	Outbox     OutboxConfig
+	Geocoder   GeocoderConfig
+	Audit      AuditLogConfig
+	SMS        SMSConfig
+	KYC        KYCConfig
+	HTTP       HTTPConfig
+	Pagination PaginationConfig
+	Sessions   SessionLimitConfig
+	Deletion   AccountDeletionConfig
+	Usernames  UsernamePolicyConfig
+
+	// ProfileCompletenessWeights weighs the profile completeness items, keyed by ProfileItem* name
+	ProfileCompletenessWeights map[string]int
+
+	// ServiceKeyRotationGrace is how long a rotated service key keeps working
+	ServiceKeyRotationGrace time.Duration
+	// DefaultUserRoles are granted to every new user
+	DefaultUserRoles []string
+	// StrictResourcePermissions rejects permissions referencing unknown resources
+	StrictResourcePermissions bool
+	// OrgMemberRemovalPolicy is "warn" or "block" (ORG_MEMBER_REMOVAL_POLICY)
+	OrgMemberRemovalPolicy string
+	// MaxHierarchyDepth bounds organization and group nesting; 0 leaves hierarchies unbounded
+	MaxHierarchyDepth int
+}
+
+// CacheConfig selects the cache backend. With Disabled set a no-op cache is used and none of the
+// Redis or L1 settings may be set.
+type CacheConfig struct {
+	Disabled     bool
+	Redis        RedisConfig
+	L1Enabled    bool
+	L1MaxEntries int
+	L1TTL        time.Duration
+	Degradation  CacheDegradationConfig
+}
+
+// Cache degradation policies: what happens to permission checks while Redis is unreachable
+const (
+	// CacheDegradationFailOpen decides permission checks from the database
+	CacheDegradationFailOpen = "fail_open"
+	// CacheDegradationFailClosed rejects permission checks until Redis is reachable again
+	CacheDegradationFailClosed = "fail_closed"
+)
+
+// CacheDegradationConfig configures the circuit breaker that stops the service from waiting on an
+// unreachable Redis. Reads bypass the cache to the database under either policy.
+type CacheDegradationConfig struct {
+	// Policy is CacheDegradationFailOpen or CacheDegradationFailClosed
+	Policy string
+	// FailureThreshold is the number of consecutive cache errors that opens the breaker
+	FailureThreshold int
+	// ProbeInterval is how long the breaker stays open before Redis is probed again
+	ProbeInterval time.Duration
+}
+
+// RedisConfig holds the Redis connection settings
+type RedisConfig struct {
+	Host         string
+	Port         string
+	Password     string
+	TLSEnabled   bool
+	DialTimeout  time.Duration
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+	PoolSize     int
+	MinIdleConns int
+}
+
+// Addr returns the host:port Redis is dialed at
+func (c RedisConfig) Addr() string {
+	return c.Host + ":" + c.Port
+}
+
+// S3Config holds the bucket photos, documents and offloaded audit details are stored in
+type S3Config struct {
+	// Bucket is empty when S3 storage is not configured
+	Bucket string
+	Region string
+	// AuditDetailsOffload keeps the full details of truncated audit logs in the bucket
+	AuditDetailsOffload bool
+}
+
+// OutboxConfig configures relaying outbox events to a webhook; the relay runs only when
+// WebhookURL is set
+type OutboxConfig struct {
+	WebhookURL     string
+	WebhookTimeout time.Duration
+	PollInterval   time.Duration
+	MaxAttempts    int
+}
+
+// GeocoderConfig selects the provider resolving address coordinates
+type GeocoderConfig struct {
+	Provider string
+	APIKey   string
+	BaseURL  string
+	Timeout  time.Duration
+}
+
+// AuditLogConfig holds the audit log limits and the retry of failed writes
+type AuditLogConfig struct {
+	// MaxDetailsBytes truncates larger audit details; 0 disables the limit
+	MaxDetailsBytes     int
+	RetryInterval       time.Duration
+	RetryMaxAttempts    int
+	IngestRatePerMinute int
+	// PermissionChecks audits every permission check, not only denials, for the resource types in
+	// PermissionCheckResources (all when empty), sampling PermissionCheckSamplePercent of them
+	PermissionChecks             bool
+	PermissionCheckResources     []string
+	PermissionCheckSamplePercent int
+}
+
+// SMSConfig configures OTP and alert delivery over AWS SNS
+type SMSConfig struct {
+	Enabled     bool
+	Region      string
+	SenderID    string
+	MessageType string
+	OTPExpiry   time.Duration
+	MaxPerHour  int
+	MaxPerDay   int
+}
+
+// KYCConfig configures Aadhaar verification through the sandbox API
+type KYCConfig struct {
+	SandboxURL             string
+	SandboxAPIKey          string
+	SandboxAPISecret       string
+	OTPExpirationSeconds   int
+	OTPMaxAttempts         int
+	OTPCooldownSeconds     int
+	PhotoMaxSizeMB         int
+	CallbackSecret         string
+	CallbackMaxAttempts    int
+	CallbackTimeoutSeconds int
+	CallbackAllowedHosts   []string
+}
+
+// HTTPConfig holds the HTTP middleware and endpoint toggles
+type HTTPConfig struct {
+	CompressionEnabled bool
+	CompressionMinSize int
+	// RequestTimeout answers slower requests with 504; 0 disables timeouts
+	RequestTimeout       time.Duration
+	SlowRequestThreshold time.Duration
+	EnableMetrics        bool
+	EnableDocs           bool
+	// RoutePolicyStrict refuses to start when a route has no declared authorization policy
+	RoutePolicyStrict bool
+	// ConcealedResources are resource types whose denied lookups answer 404
+	ConcealedResources []string
+}
+
+// PaginationConfig holds the default page size and page size cap of list endpoints
+type PaginationConfig struct {
+	DefaultLimit int
+	MaxLimit     int
+}
+
+// Session limit modes: what happens to a login that would exceed the concurrent session limit
+const (
+	// SessionLimitModeEvictOldest signs out the oldest sessions to make room for the new login
+	SessionLimitModeEvictOldest = "evict_oldest"
+	// SessionLimitModeReject refuses the new login
+	SessionLimitModeReject = "reject"
+)
+
+// SessionLimitConfig bounds the logins a user can hold at once. Organizations can lower the
+// limit for their members with the max_concurrent_sessions setting.
+type SessionLimitConfig struct {
+	// MaxConcurrent is the number of logins a user can hold; 0 means unlimited
+	MaxConcurrent int
+	// Mode is SessionLimitModeEvictOldest or SessionLimitModeReject
+	Mode string
+}
+
+// AccountDeletionConfig holds the grace period of user-initiated account deletions
+type AccountDeletionConfig struct {
+	// GracePeriod is how long a deleted account can still be restored by its user
+	GracePeriod time.Duration
+	// PurgeInterval is the wait between passes hard deleting the accounts whose grace period ended
+	PurgeInterval time.Duration
+}
+
+// Username generation strategies: how a user created without a username gets one
+const (
+	// UsernameGenerateNone leaves the user without a username
+	UsernameGenerateNone = "none"
+	// UsernameGenerateName derives the username from the user's name, or the phone number without one
+	UsernameGenerateName = "name"
+	// UsernameGeneratePhone derives the username from the last digits of the phone number
+	UsernameGeneratePhone = "phone"
+)
+
+// UsernamePolicyConfig governs the usernames of new users. Exact duplicates are always rejected by
+// the unique constraint on the username column.
+type UsernamePolicyConfig struct {
+	// Required rejects users created without a username, unless one is generated
+	Required bool
+	// Unique also rejects usernames differing only in case from a live user's; the
+	// idx_users_username_lower_active index enforces it
+	Unique bool
+	// Generate is UsernameGenerateNone, UsernameGenerateName or UsernameGeneratePhone
+	Generate string
+}
+
+// Profile completeness items, weighed by AAA_PROFILE_COMPLETENESS_WEIGHTS
+const (
+	ProfileItemName            = "name"
+	ProfileItemDateOfBirth     = "date_of_birth"
+	ProfileItemPhoto           = "photo"
+	ProfileItemVerifiedContact = "verified_contact"
+	ProfileItemAddress         = "address"
+	ProfileItemKYCVerified     = "kyc_verified"
+)
+
+// DefaultProfileCompletenessWeights returns the weights of the profile completeness items, which
+// add up to 100. A verified Aadhaar weighs the most: it is what unlocks KYC-gated features.
+func DefaultProfileCompletenessWeights() map[string]int {
+	return map[string]int{
+		ProfileItemName:            15,
+		ProfileItemDateOfBirth:     10,
+		ProfileItemPhoto:           10,
+		ProfileItemVerifiedContact: 15,
+		ProfileItemAddress:         20,
+		ProfileItemKYCVerified:     30,
+	}
+}
+
+// redisVariables are the settings that configure the Redis cache
+var redisVariables = []string{
+	"REDIS_HOST", "REDIS_PORT", "REDIS_PASSWORD", "REDIS_TLS_ENABLED", "REDIS_DIAL_TIMEOUT",
+	"REDIS_READ_TIMEOUT", "REDIS_WRITE_TIMEOUT", "REDIS_POOL_SIZE", "REDIS_MIN_IDLE_CONNS",
+}
+
+// Load reads the server configuration from environment variables and validates it. All problems
+// are reported together, so that one restart is enough to fix them.
+func Load() (*Config, error) {
+	env := &envReader{}
+
+	cfg := &Config{
+		Environment:   strings.ToLower(env.String("APP_ENV", "")),
+		HTTPPort:      env.String("HTTP_PORT", "8080"),
+		GRPCPort:      env.String("GRPC_PORT", "50051"),
+		JWTSecret:     env.String("JWT_SECRET", DefaultJWTSecret),
+		RunSeed:       env.Bool("AAA_RUN_SEED", true),
+		LogRedactKeys: env.List("LOG_REDACT_KEYS"),
+		Cache: CacheConfig{
+			Disabled: env.Bool("CACHE_DISABLED", false),
+			Redis: RedisConfig{
+				Host:         env.String("REDIS_HOST", "localhost"),
+				Port:         env.String("REDIS_PORT", "6379"),
+				Password:     env.String("REDIS_PASSWORD", ""),
+				TLSEnabled:   env.Bool("REDIS_TLS_ENABLED", false),
+				DialTimeout:  env.Duration("REDIS_DIAL_TIMEOUT", 5*time.Second),
+				ReadTimeout:  env.Duration("REDIS_READ_TIMEOUT", 3*time.Second),
+				WriteTimeout: env.Duration("REDIS_WRITE_TIMEOUT", 3*time.Second),
+				PoolSize:     env.Int("REDIS_POOL_SIZE", 10),
+				MinIdleConns: env.Int("REDIS_MIN_IDLE_CONNS", 2),
+			},
+			L1Enabled:    env.Bool("CACHE_L1_ENABLED", false),
+			L1MaxEntries: env.Int("CACHE_L1_MAX_ENTRIES", 10000),
+			L1TTL:        env.Duration("CACHE_L1_TTL", 5*time.Second),
+			Degradation: CacheDegradationConfig{
+				Policy:           strings.ToLower(env.String("CACHE_DEGRADATION_POLICY", CacheDegradationFailOpen)),
+				FailureThreshold: env.Int("CACHE_BREAKER_FAILURE_THRESHOLD", 5),
+				ProbeInterval:    env.Duration("CACHE_BREAKER_PROBE_INTERVAL", 10*time.Second),
+			},
+		},
+		S3: S3Config{
+			Bucket:              env.String("AWS_S3_BUCKET", ""),
+			Region:              env.String("AWS_REGION", "us-east-1"),
+			AuditDetailsOffload: env.Bool("AAA_AUDIT_DETAILS_OFFLOAD", false),
+		},
+		Outbox: OutboxConfig{
+			WebhookURL:     env.String("AAA_OUTBOX_WEBHOOK_URL", ""),
+			WebhookTimeout: env.Duration("AAA_OUTBOX_WEBHOOK_TIMEOUT", 10*time.Second),
+			PollInterval:   env.Duration("AAA_OUTBOX_POLL_INTERVAL", time.Second),
+			MaxAttempts:    env.Int("AAA_OUTBOX_MAX_ATTEMPTS", 20),
+		},
+		Geocoder: GeocoderConfig{
+			Provider: strings.ToLower(env.String("GEOCODER_PROVIDER", "none")),
+			APIKey:   env.String("GEOCODER_API_KEY", ""),
+			BaseURL:  env.String("GEOCODER_BASE_URL", ""),
+			Timeout:  env.Duration("GEOCODER_TIMEOUT", 5*time.Second),
+		},
+		Audit: AuditLogConfig{
+			MaxDetailsBytes:     env.Int("AAA_AUDIT_MAX_DETAILS_BYTES", 64*1024),
+			RetryInterval:       env.Duration("AAA_AUDIT_RETRY_INTERVAL", 30*time.Second),
+			RetryMaxAttempts:    env.Int("AAA_AUDIT_RETRY_MAX_ATTEMPTS", 10),
+			IngestRatePerMinute: env.Int("AAA_AUDIT_INGEST_RATE_PER_MINUTE", 60),
+
+			PermissionChecks:             env.Bool("AAA_AUDIT_PERMISSION_CHECKS", false),
+			PermissionCheckResources:     env.List("AAA_AUDIT_PERMISSION_CHECK_RESOURCES"),
+			PermissionCheckSamplePercent: env.Int("AAA_AUDIT_PERMISSION_CHECK_SAMPLE_PERCENT", 100),
+		},
+		SMS: SMSConfig{
+			Enabled:     env.Bool("SMS_ENABLED", false),
+			Region:      env.String("AWS_REGION", "ap-south-1"),
+			SenderID:    env.String("SMS_SENDER_ID", "KISANLINK"),
+			MessageType: env.String("SMS_MESSAGE_TYPE", "Transactional"),
+			OTPExpiry:   time.Duration(env.Int("SMS_OTP_EXPIRY_MINUTES", 10)) * time.Minute,
+			MaxPerHour:  env.Int("SMS_MAX_PER_HOUR", 5),
+			MaxPerDay:   env.Int("SMS_MAX_PER_DAY", 20),
+		},
+		KYC: KYCConfig{
+			SandboxURL:             env.String("AADHAAR_SANDBOX_URL", ""),
+			SandboxAPIKey:          env.String("AADHAAR_SANDBOX_API_KEY", ""),
+			SandboxAPISecret:       env.String("AADHAAR_SANDBOX_API_SECRET", ""),
+			OTPExpirationSeconds:   env.Int("OTP_EXPIRATION_SECONDS", 300),
+			OTPMaxAttempts:         env.Int("OTP_MAX_ATTEMPTS", 3),
+			OTPCooldownSeconds:     env.Int("OTP_COOLDOWN_SECONDS", 60),
+			PhotoMaxSizeMB:         env.Int("PHOTO_MAX_SIZE_MB", 5),
+			CallbackSecret:         env.String("KYC_CALLBACK_SECRET", ""),
+			CallbackMaxAttempts:    env.Int("KYC_CALLBACK_MAX_ATTEMPTS", 5),
+			CallbackTimeoutSeconds: env.Int("KYC_CALLBACK_TIMEOUT_SECONDS", 10),
+			CallbackAllowedHosts:   env.List("KYC_CALLBACK_ALLOWED_HOSTS"),
+		},
+		HTTP: HTTPConfig{
+			CompressionEnabled:   env.Bool("RESPONSE_COMPRESSION_ENABLED", true),
+			CompressionMinSize:   env.Int("RESPONSE_COMPRESSION_MIN_SIZE", 1024),
+			RequestTimeout:       env.Duration("AAA_REQUEST_TIMEOUT", 30*time.Second),
+			SlowRequestThreshold: env.Duration("AAA_SLOW_REQUEST_THRESHOLD", 2*time.Second),
+			EnableMetrics:        env.Bool("AAA_ENABLE_METRICS", true),
+			EnableDocs:           env.Bool("AAA_ENABLE_DOCS", true),
+			RoutePolicyStrict:    env.Bool("AAA_ROUTE_POLICY_STRICT", false),
+			ConcealedResources:   env.List("AAA_AUTHZ_CONCEAL_RESOURCES"),
+		},
+		Pagination: PaginationConfig{
+			DefaultLimit: env.Int("PAGINATION_DEFAULT_LIMIT", 10),
+			MaxLimit:     env.Int("PAGINATION_MAX_LIMIT", 100),
+		},
+		Sessions: SessionLimitConfig{
+			MaxConcurrent: env.Int("AAA_MAX_CONCURRENT_SESSIONS", 0),
+			Mode:          strings.ToLower(env.String("AAA_SESSION_LIMIT_MODE", SessionLimitModeEvictOldest)),
+		},
+		Deletion: AccountDeletionConfig{
+			GracePeriod:   env.Duration("AAA_ACCOUNT_DELETION_GRACE_PERIOD", 30*24*time.Hour),
+			PurgeInterval: env.Duration("AAA_ACCOUNT_DELETION_PURGE_INTERVAL", time.Hour),
+		},
+		Usernames: UsernamePolicyConfig{
+			Required: env.Bool("AAA_USERNAME_REQUIRED", false),
+			Unique:   env.Bool("AAA_USERNAME_UNIQUE", true),
+			Generate: strings.ToLower(env.String("AAA_USERNAME_GENERATE", UsernameGenerateNone)),
+		},
+		ServiceKeyRotationGrace:   env.Duration("AAA_SERVICE_KEY_ROTATION_GRACE", 24*time.Hour),
+		DefaultUserRoles:          env.List("AAA_DEFAULT_USER_ROLES"),
+		StrictResourcePermissions: env.Bool("STRICT_RESOURCE_PERMISSIONS", false),
+		OrgMemberRemovalPolicy:    strings.ToLower(env.String("ORG_MEMBER_REMOVAL_POLICY", "warn")),
+		MaxHierarchyDepth:         env.Int("AAA_MAX_HIERARCHY_DEPTH", 0),
+
+		ProfileCompletenessWeights: env.Weights("AAA_PROFILE_COMPLETENESS_WEIGHTS", DefaultProfileCompletenessWeights()),
+	}
+
+	if cfg.Cache.Disabled {
+		for _, key := range append(redisVariables, "CACHE_L1_ENABLED", "CACHE_L1_MAX_ENTRIES", "CACHE_L1_TTL",
+			"CACHE_DEGRADATION_POLICY", "CACHE_BREAKER_FAILURE_THRESHOLD", "CACHE_BREAKER_PROBE_INTERVAL") {
+			if env.IsSet(key) {
+				env.Errorf("CACHE_DISABLED=true conflicts with %s; unset it or enable the cache", key)
+			}
+		}
+	}
+
+	cfg.validate(env)
+	if len(env.errs) > 0 {
+		return nil, fmt.Errorf("invalid configuration:\n%w", errors.Join(env.errs...))
+	}
+	return cfg, nil
+}
+
+// IsProduction returns true if running in production or staging
+func (c *Config) IsProduction() bool {
+	return (&AppConfig{Environment: c.Environment}).IsProduction()
+}
+
+// validate checks value ranges and the settings that depend on each other
+func (c *Config) validate(env *envReader) {
+	env.Port("HTTP_PORT", c.HTTPPort)
+	env.Port("GRPC_PORT", c.GRPCPort)
+	if c.HTTPPort == c.GRPCPort {
+		env.Errorf("HTTP_PORT and GRPC_PORT must differ, both are %s", c.HTTPPort)
+	}
+	if c.IsProduction() && c.JWTSecret == DefaultJWTSecret {
+		env.Errorf("JWT_SECRET must be set in %s", c.Environment)
+	}
+
+	if !c.Cache.Disabled {
+		env.Port("REDIS_PORT", c.Cache.Redis.Port)
+		env.Positive("REDIS_DIAL_TIMEOUT", c.Cache.Redis.DialTimeout)
+		env.Positive("REDIS_READ_TIMEOUT", c.Cache.Redis.ReadTimeout)
+		env.Positive("REDIS_WRITE_TIMEOUT", c.Cache.Redis.WriteTimeout)
+		env.AtLeast("REDIS_POOL_SIZE", c.Cache.Redis.PoolSize, 1)
+		env.AtLeast("REDIS_MIN_IDLE_CONNS", c.Cache.Redis.MinIdleConns, 0)
+		if c.Cache.Redis.MinIdleConns > c.Cache.Redis.PoolSize {
+			env.Errorf("REDIS_MIN_IDLE_CONNS (%d) must not exceed REDIS_POOL_SIZE (%d)", c.Cache.Redis.MinIdleConns, c.Cache.Redis.PoolSize)
+		}
+		if c.Cache.L1Enabled {
+			env.AtLeast("CACHE_L1_MAX_ENTRIES", c.Cache.L1MaxEntries, 1)
+			env.Positive("CACHE_L1_TTL", c.Cache.L1TTL)
+		}
+		if policy := c.Cache.Degradation.Policy; policy != CacheDegradationFailOpen && policy != CacheDegradationFailClosed {
+			env.Errorf("CACHE_DEGRADATION_POLICY must be %s or %s, got %q", CacheDegradationFailOpen, CacheDegradationFailClosed, policy)
+		}
+		env.AtLeast("CACHE_BREAKER_FAILURE_THRESHOLD", c.Cache.Degradation.FailureThreshold, 1)
+		env.Positive("CACHE_BREAKER_PROBE_INTERVAL", c.Cache.Degradation.ProbeInterval)
+	}
+
+	if c.S3.AuditDetailsOffload && c.S3.Bucket == "" {
+		env.Errorf("AAA_AUDIT_DETAILS_OFFLOAD=true requires AWS_S3_BUCKET")
+	}
+
+	if c.Outbox.WebhookURL != "" {
+		env.URL("AAA_OUTBOX_WEBHOOK_URL", c.Outbox.WebhookURL)
+		env.Positive("AAA_OUTBOX_WEBHOOK_TIMEOUT", c.Outbox.WebhookTimeout)
+		env.Positive("AAA_OUTBOX_POLL_INTERVAL", c.Outbox.PollInterval)
+		env.AtLeast("AAA_OUTBOX_MAX_ATTEMPTS", c.Outbox.MaxAttempts, 1)
+	}
+
+	switch c.Geocoder.Provider {
+	case "none":
+	case "google":
+		if c.Geocoder.APIKey == "" {
+			env.Errorf("GEOCODER_PROVIDER=google requires GEOCODER_API_KEY")
+		}
+		if c.Geocoder.BaseURL != "" {
+			env.URL("GEOCODER_BASE_URL", c.Geocoder.BaseURL)
+		}
+		env.Positive("GEOCODER_TIMEOUT", c.Geocoder.Timeout)
+	default:
+		env.Errorf("GEOCODER_PROVIDER must be none or google, got %q", c.Geocoder.Provider)
+	}
+
+	env.AtLeast("AAA_AUDIT_MAX_DETAILS_BYTES", c.Audit.MaxDetailsBytes, 0)
+	env.Positive("AAA_AUDIT_RETRY_INTERVAL", c.Audit.RetryInterval)
+	env.AtLeast("AAA_AUDIT_RETRY_MAX_ATTEMPTS", c.Audit.RetryMaxAttempts, 1)
+	env.AtLeast("AAA_AUDIT_INGEST_RATE_PER_MINUTE", c.Audit.IngestRatePerMinute, 0)
+	if c.Audit.PermissionChecks {
+		if percent := c.Audit.PermissionCheckSamplePercent; percent < 1 || percent > 100 {
+			env.Errorf("AAA_AUDIT_PERMISSION_CHECK_SAMPLE_PERCENT must be between 1 and 100, got %d", percent)
+		}
+	} else if len(c.Audit.PermissionCheckResources) > 0 {
+		env.Errorf("AAA_AUDIT_PERMISSION_CHECK_RESOURCES requires AAA_AUDIT_PERMISSION_CHECKS=true")
+	}
+
+	if c.SMS.Enabled {
+		env.Positive("SMS_OTP_EXPIRY_MINUTES", c.SMS.OTPExpiry)
+		env.AtLeast("SMS_MAX_PER_HOUR", c.SMS.MaxPerHour, 1)
+		if c.SMS.MaxPerDay < c.SMS.MaxPerHour {
+			env.Errorf("SMS_MAX_PER_DAY (%d) must be at least SMS_MAX_PER_HOUR (%d)", c.SMS.MaxPerDay, c.SMS.MaxPerHour)
+		}
+	}
+
+	if c.KYC.SandboxURL != "" {
+		env.URL("AADHAAR_SANDBOX_URL", c.KYC.SandboxURL)
+		if c.KYC.SandboxAPIKey == "" || c.KYC.SandboxAPISecret == "" {
+			env.Errorf("AADHAAR_SANDBOX_URL requires AADHAAR_SANDBOX_API_KEY and AADHAAR_SANDBOX_API_SECRET")
+		}
+	}
+	env.AtLeast("OTP_EXPIRATION_SECONDS", c.KYC.OTPExpirationSeconds, 1)
+	env.AtLeast("OTP_MAX_ATTEMPTS", c.KYC.OTPMaxAttempts, 1)
+	env.AtLeast("OTP_COOLDOWN_SECONDS", c.KYC.OTPCooldownSeconds, 0)
+	env.AtLeast("PHOTO_MAX_SIZE_MB", c.KYC.PhotoMaxSizeMB, 1)
+	env.AtLeast("KYC_CALLBACK_MAX_ATTEMPTS", c.KYC.CallbackMaxAttempts, 1)
+	env.AtLeast("KYC_CALLBACK_TIMEOUT_SECONDS", c.KYC.CallbackTimeoutSeconds, 1)
+
+	env.AtLeast("RESPONSE_COMPRESSION_MIN_SIZE", c.HTTP.CompressionMinSize, 0)
+	env.NotNegative("AAA_REQUEST_TIMEOUT", c.HTTP.RequestTimeout)
+	env.NotNegative("AAA_SLOW_REQUEST_THRESHOLD", c.HTTP.SlowRequestThreshold)
+
+	env.AtLeast("PAGINATION_DEFAULT_LIMIT", c.Pagination.DefaultLimit, 1)
+	if c.Pagination.MaxLimit < c.Pagination.DefaultLimit {
+		env.Errorf("PAGINATION_MAX_LIMIT (%d) must be at least PAGINATION_DEFAULT_LIMIT (%d)", c.Pagination.MaxLimit, c.Pagination.DefaultLimit)
+	}
+
+	env.AtLeast("AAA_MAX_CONCURRENT_SESSIONS", c.Sessions.MaxConcurrent, 0)
+	if c.Sessions.Mode != SessionLimitModeEvictOldest && c.Sessions.Mode != SessionLimitModeReject {
+		env.Errorf("AAA_SESSION_LIMIT_MODE must be %s or %s, got %q", SessionLimitModeEvictOldest, SessionLimitModeReject, c.Sessions.Mode)
+	}
+
+	env.NotNegative("AAA_ACCOUNT_DELETION_GRACE_PERIOD", c.Deletion.GracePeriod)
+
+	switch c.Usernames.Generate {
+	case UsernameGenerateNone, UsernameGenerateName, UsernameGeneratePhone:
+	default:
+		env.Errorf("AAA_USERNAME_GENERATE must be %s, %s or %s, got %q",
+			UsernameGenerateNone, UsernameGenerateName, UsernameGeneratePhone, c.Usernames.Generate)
+	}
+
+	totalWeight := 0
+	for _, weight := range c.ProfileCompletenessWeights {
+		totalWeight += weight
+	}
+	if totalWeight == 0 {
+		env.Errorf("AAA_PROFILE_COMPLETENESS_WEIGHTS must give at least one item a weight")
+	}
+	env.Positive("AAA_ACCOUNT_DELETION_PURGE_INTERVAL", c.Deletion.PurgeInterval)
+
+	env.NotNegative("AAA_SERVICE_KEY_ROTATION_GRACE", c.ServiceKeyRotationGrace)
+	if c.OrgMemberRemovalPolicy != "warn" && c.OrgMemberRemovalPolicy != "block" {
+		env.Errorf("ORG_MEMBER_REMOVAL_POLICY must be warn or block, got %q", c.OrgMemberRemovalPolicy)
+	}
+	env.AtLeast("AAA_MAX_HIERARCHY_DEPTH", c.MaxHierarchyDepth, 0)
+}
+
+// envReader reads typed environment variables, collecting an error for every value that does not
+// parse or validate instead of falling back to the default
+type envReader struct {
+	errs []error
+}
+
+func (r *envReader) Errorf(format string, args ...interface{}) {
+	r.errs = append(r.errs, fmt.Errorf(format, args...))
+}
+
+// IsSet reports whether key has a non-empty value
+func (r *envReader) IsSet(key string) bool {
+	return strings.TrimSpace(os.Getenv(key)) != ""
+}
+
+func (r *envReader) String(key, defaultValue string) string {
+	if value := strings.TrimSpace(os.Getenv(key)); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// List splits a comma-separated value, dropping empty entries
+func (r *envReader) List(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// Weights reads comma-separated name=weight pairs overriding the weights in defaults. Names must be
+// keys of defaults and weights must not be negative; a weight of 0 leaves the item out.
+func (r *envReader) Weights(key string, defaults map[string]int) map[string]int {
+	for _, pair := range r.List(key) {
+		name, value, _ := strings.Cut(pair, "=")
+		name = strings.TrimSpace(name)
+		if _, ok := defaults[name]; !ok {
+			r.Errorf("%s has unknown item %q", key, name)
+			continue
+		}
+		weight, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || weight < 0 {
+			r.Errorf("%s weight of %s must be a non-negative integer, got %q", key, name, value)
+			continue
+		}
+		defaults[name] = weight
+	}
+	return defaults
+}
+
+func (r *envReader) Bool(key string, defaultValue bool) bool {
+	value := r.String(key, "")
+	if value == "" {
+		return defaultValue
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		r.Errorf("%s must be true or false, got %q", key, value)
+		return defaultValue
+	}
+	return parsed
+}
+
+func (r *envReader) Int(key string, defaultValue int) int {
+	value := r.String(key, "")
+	if value == "" {
+		return defaultValue
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		r.Errorf("%s must be an integer, got %q", key, value)
+		return defaultValue
+	}
+	return parsed
+}
+
+// Duration parses a number of seconds or a Go duration such as "500ms" or "24h"
+func (r *envReader) Duration(key string, defaultValue time.Duration) time.Duration {
+	value := r.String(key, "")
+	if value == "" {
+		return defaultValue
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		r.Errorf("%s must be a number of seconds or a duration such as \"30s\", got %q", key, value)
+		return defaultValue
+	}
+	return parsed
+}
+
+func (r *envReader) Port(key, value string) {
+	if port, err := strconv.Atoi(value); err != nil || port < 1 || port > 65535 {
+		r.Errorf("%s must be a port between 1 and 65535, got %q", key, value)
+	}
+}
+
+func (r *envReader) URL(key, value string) {
+	if parsed, err := url.Parse(value); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		r.Errorf("%s must be an http or https URL, got %q", key, value)
+	}
+}
+
+func (r *envReader) AtLeast(key string, value, minimum int) {
+	if value < minimum {
+		r.Errorf("%s must be at least %d, got %d", key, minimum, value)
+	}
+}
+
+func (r *envReader) Positive(key string, value time.Duration) {
+	if value <= 0 {
+		r.Errorf("%s must be positive, got %s", key, value)
+	}
+}
+
+func (r *envReader) NotNegative(key string, value time.Duration) {
+	if value < 0 {
+		r.Errorf("%s must not be negative, got %s", key, value)
+	}
+}
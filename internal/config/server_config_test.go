@@ -0,0 +1,155 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad_Defaults(t *testing.T) {
+	cfg, err := Load()
+	require.NoError(t, err)
+
+	assert.Equal(t, "8080", cfg.HTTPPort)
+	assert.Equal(t, "50051", cfg.GRPCPort)
+	assert.Equal(t, "localhost:6379", cfg.Cache.Redis.Addr())
+	assert.Equal(t, 5*time.Second, cfg.Cache.Redis.DialTimeout)
+	assert.Equal(t, 10, cfg.Pagination.DefaultLimit)
+	assert.Equal(t, "warn", cfg.OrgMemberRemovalPolicy)
+	assert.True(t, cfg.RunSeed)
+	assert.Equal(t, SessionLimitConfig{MaxConcurrent: 0, Mode: SessionLimitModeEvictOldest}, cfg.Sessions)
+	assert.Equal(t, AccountDeletionConfig{GracePeriod: 30 * 24 * time.Hour, PurgeInterval: time.Hour}, cfg.Deletion)
+	assert.False(t, cfg.Audit.PermissionChecks)
+	assert.Equal(t, UsernamePolicyConfig{Unique: true, Generate: UsernameGenerateNone}, cfg.Usernames)
+}
+
+func TestLoad_ParsesValues(t *testing.T) {
+	t.Setenv("REDIS_READ_TIMEOUT", "2")
+	t.Setenv("AAA_REQUEST_TIMEOUT", "500ms")
+	t.Setenv("AAA_AUTHZ_CONCEAL_RESOURCES", " aaa/user, ,aaa/role")
+	t.Setenv("SMS_OTP_EXPIRY_MINUTES", "15")
+	t.Setenv("AAA_PROFILE_COMPLETENESS_WEIGHTS", "kyc_verified=50, photo=0")
+	t.Setenv("AAA_USERNAME_GENERATE", "Name")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, 2*time.Second, cfg.Cache.Redis.ReadTimeout)
+	assert.Equal(t, 500*time.Millisecond, cfg.HTTP.RequestTimeout)
+	assert.Equal(t, []string{"aaa/user", "aaa/role"}, cfg.HTTP.ConcealedResources)
+	assert.Equal(t, 15*time.Minute, cfg.SMS.OTPExpiry)
+	assert.Equal(t, 50, cfg.ProfileCompletenessWeights[ProfileItemKYCVerified])
+	assert.Zero(t, cfg.ProfileCompletenessWeights[ProfileItemPhoto])
+	assert.Equal(t, 15, cfg.ProfileCompletenessWeights[ProfileItemName], "items not listed keep their default weight")
+	assert.Equal(t, UsernameGenerateName, cfg.Usernames.Generate)
+}
+
+func TestLoad_InvalidConfigFailsStartup(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		message string
+	}{
+		{
+			name:    "cache disabled with Redis settings",
+			env:     map[string]string{"CACHE_DISABLED": "true", "REDIS_HOST": "redis.internal"},
+			message: "CACHE_DISABLED=true conflicts with REDIS_HOST",
+		},
+		{
+			name:    "port out of range",
+			env:     map[string]string{"HTTP_PORT": "70000"},
+			message: "HTTP_PORT must be a port between 1 and 65535",
+		},
+		{
+			name:    "unparsable integer",
+			env:     map[string]string{"REDIS_POOL_SIZE": "ten"},
+			message: `REDIS_POOL_SIZE must be an integer, got "ten"`,
+		},
+		{
+			name:    "unparsable boolean",
+			env:     map[string]string{"SMS_ENABLED": "yes please"},
+			message: "SMS_ENABLED must be true or false",
+		},
+		{
+			name:    "default page size above the cap",
+			env:     map[string]string{"PAGINATION_DEFAULT_LIMIT": "200"},
+			message: "PAGINATION_MAX_LIMIT (100) must be at least PAGINATION_DEFAULT_LIMIT (200)",
+		},
+		{
+			name:    "default JWT secret in production",
+			env:     map[string]string{"APP_ENV": "production"},
+			message: "JWT_SECRET must be set in production",
+		},
+		{
+			name:    "geocoder without API key",
+			env:     map[string]string{"GEOCODER_PROVIDER": "google"},
+			message: "GEOCODER_PROVIDER=google requires GEOCODER_API_KEY",
+		},
+		{
+			name:    "unknown session limit mode",
+			env:     map[string]string{"AAA_SESSION_LIMIT_MODE": "block"},
+			message: `AAA_SESSION_LIMIT_MODE must be evict_oldest or reject, got "block"`,
+		},
+		{
+			name:    "negative account deletion grace period",
+			env:     map[string]string{"AAA_ACCOUNT_DELETION_GRACE_PERIOD": "-1h"},
+			message: "AAA_ACCOUNT_DELETION_GRACE_PERIOD must not be negative",
+		},
+		{
+			name:    "unknown member removal policy",
+			env:     map[string]string{"ORG_MEMBER_REMOVAL_POLICY": "ignore"},
+			message: `ORG_MEMBER_REMOVAL_POLICY must be warn or block, got "ignore"`,
+		},
+		{
+			name:    "unknown cache degradation policy",
+			env:     map[string]string{"CACHE_DEGRADATION_POLICY": "fail_slow"},
+			message: `CACHE_DEGRADATION_POLICY must be fail_open or fail_closed, got "fail_slow"`,
+		},
+		{
+			name:    "unknown profile completeness item",
+			env:     map[string]string{"AAA_PROFILE_COMPLETENESS_WEIGHTS": "nickname=10"},
+			message: `AAA_PROFILE_COMPLETENESS_WEIGHTS has unknown item "nickname"`,
+		},
+		{
+			name:    "negative profile completeness weight",
+			env:     map[string]string{"AAA_PROFILE_COMPLETENESS_WEIGHTS": "photo=-5"},
+			message: `AAA_PROFILE_COMPLETENESS_WEIGHTS weight of photo must be a non-negative integer, got "-5"`,
+		},
+		{
+			name:    "permission check sample above 100 percent",
+			env:     map[string]string{"AAA_AUDIT_PERMISSION_CHECKS": "true", "AAA_AUDIT_PERMISSION_CHECK_SAMPLE_PERCENT": "150"},
+			message: "AAA_AUDIT_PERMISSION_CHECK_SAMPLE_PERCENT must be between 1 and 100, got 150",
+		},
+		{
+			name:    "unknown username generation strategy",
+			env:     map[string]string{"AAA_USERNAME_GENERATE": "email"},
+			message: `AAA_USERNAME_GENERATE must be none, name or phone, got "email"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			cfg, err := Load()
+			require.Error(t, err)
+			assert.Nil(t, cfg)
+			assert.Contains(t, err.Error(), tt.message)
+		})
+	}
+}
+
+func TestLoad_ReportsEveryProblem(t *testing.T) {
+	t.Setenv("GRPC_PORT", "grpc")
+	t.Setenv("AAA_OUTBOX_WEBHOOK_URL", "events.internal/hook")
+	t.Setenv("REDIS_MIN_IDLE_CONNS", "20")
+
+	_, err := Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "GRPC_PORT must be a port")
+	assert.Contains(t, err.Error(), "AAA_OUTBOX_WEBHOOK_URL must be an http or https URL")
+	assert.Contains(t, err.Error(), "REDIS_MIN_IDLE_CONNS (20) must not exceed REDIS_POOL_SIZE (10)")
+}
@@ -0,0 +1,50 @@
+package config
+
+import "time"
+
+// DefaultSigningKeyOverlap is how long a retired signing key keeps verifying tokens when
+// AAA_JWT_KEY_OVERLAP is not set. It covers the 7 day refresh token lifetime.
+const DefaultSigningKeyOverlap = 8 * 24 * time.Hour
+
+// SigningKeyConfig configures RS256 token signing with rotated keys
+type SigningKeyConfig struct {
+	// Dir holds the signing keys; tokens are signed with the HS256 secret when it is empty
+	Dir string
+	// RotationInterval is the age at which the current key is replaced; zero rotates only on demand
+	RotationInterval time.Duration
+	// Overlap is how long a replaced key keeps verifying the tokens it signed
+	Overlap time.Duration
+	// AcceptHS256Until is the end of the migration window in which tokens signed with the HS256
+	// secret still verify; zero refuses them as soon as RS256 signing is enabled
+	AcceptHS256Until time.Time
+}
+
+// Enabled reports whether tokens are signed with rotated RS256 keys
+func (c SigningKeyConfig) Enabled() bool {
+	return c.Dir != ""
+}
+
+// LoadSigningKeyConfig loads the signing key configuration from environment variables.
+// Variables:
+//
+//	AAA_JWT_SIGNING_KEYS_DIR (directory or mounted secret holding the keys; enables RS256)
+//	AAA_JWT_KEY_ROTATION_INTERVAL (e.g. "720h"; default "0s", rotate only on demand)
+//	AAA_JWT_KEY_OVERLAP (default "192h"; must cover the longest token lifetime)
+//	AAA_JWT_HS256_ACCEPT_UNTIL (RFC 3339 time; HS256 tokens verify until then, default never)
+func LoadSigningKeyConfig() SigningKeyConfig {
+	cfg := SigningKeyConfig{
+		Dir:              getEnvString("AAA_JWT_SIGNING_KEYS_DIR", ""),
+		RotationInterval: getEnvDuration("AAA_JWT_KEY_ROTATION_INTERVAL", 0),
+		Overlap:          getEnvDuration("AAA_JWT_KEY_OVERLAP", DefaultSigningKeyOverlap),
+	}
+	if cfg.RotationInterval < 0 {
+		cfg.RotationInterval = 0
+	}
+	if cfg.Overlap <= 0 {
+		cfg.Overlap = DefaultSigningKeyOverlap
+	}
+	if until, err := time.Parse(time.RFC3339, getEnvString("AAA_JWT_HS256_ACCEPT_UNTIL", "")); err == nil {
+		cfg.AcceptHS256Until = until
+	}
+	return cfg
+}
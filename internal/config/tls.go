@@ -0,0 +1,61 @@
+package config
+
+import (
+	"crypto/tls"
+	"fmt"
+	"strings"
+)
+
+// ServerTLSConfig holds the certificate the HTTP server serves HTTPS with
+type ServerTLSConfig struct {
+	CertFile   string
+	KeyFile    string
+	MinVersion uint16
+}
+
+// LoadServerTLSConfig loads the HTTP server's TLS configuration from environment variables.
+// Variables:
+//
+//	AAA_TLS_CERT_FILE   (PEM certificate chain; HTTPS is served only when it and the key are set)
+//	AAA_TLS_KEY_FILE    (PEM private key)
+//	AAA_TLS_MIN_VERSION (optional; "1.2" or "1.3", default "1.2")
+func LoadServerTLSConfig() (*ServerTLSConfig, error) {
+	minVersion, err := ParseTLSVersion(getEnvString("AAA_TLS_MIN_VERSION", "1.2"))
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &ServerTLSConfig{
+		CertFile:   getEnvString("AAA_TLS_CERT_FILE", ""),
+		KeyFile:    getEnvString("AAA_TLS_KEY_FILE", ""),
+		MinVersion: minVersion,
+	}
+	if (cfg.CertFile == "") != (cfg.KeyFile == "") {
+		return nil, fmt.Errorf("AAA_TLS_CERT_FILE and AAA_TLS_KEY_FILE must be set together")
+	}
+	return cfg, nil
+}
+
+// Enabled reports whether a certificate is configured, so the server should serve HTTPS
+func (c *ServerTLSConfig) Enabled() bool {
+	return c != nil && c.CertFile != "" && c.KeyFile != ""
+}
+
+// TLSConfig returns the tls.Config the HTTP server is started with
+func (c *ServerTLSConfig) TLSConfig() *tls.Config {
+	return &tls.Config{MinVersion: c.MinVersion}
+}
+
+// ParseTLSVersion converts a TLS version such as "1.2" or "TLS1.3" to its crypto/tls constant.
+// Versions older than TLS 1.2 are rejected.
+func ParseTLSVersion(version string) (uint16, error) {
+	normalized := strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(version)), "TLS")
+	switch strings.TrimSpace(normalized) {
+	case "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	default:
+		return 0, fmt.Errorf("unsupported minimum TLS version %q: use 1.2 or 1.3", version)
+	}
+}
@@ -27,6 +27,9 @@ type AadhaarVerification struct {
 	AddressJSON        AadhaarAddress `gorm:"type:jsonb" json:"address,omitempty"`
 	Attempts           int            `gorm:"default:0" json:"attempts"`
 	LastAttemptAt      *time.Time     `json:"last_attempt_at,omitempty"`
+	CallbackURL        string         `gorm:"type:text" json:"callback_url,omitempty"`
+	CallbackStatus     string         `gorm:"type:varchar(50)" json:"callback_status,omitempty"`
+	CallbackSentAt     *time.Time     `json:"callback_sent_at,omitempty"`
 	CreatedAt          time.Time      `json:"created_at"`
 	UpdatedAt          time.Time      `json:"updated_at"`
 	DeletedAt          *time.Time     `gorm:"index" json:"deleted_at,omitempty"`
@@ -0,0 +1,95 @@
+package models
+
+import (
+	"time"
+
+	"github.com/Kisanlink/kisanlink-db/pkg/base"
+	"github.com/Kisanlink/kisanlink-db/pkg/core/hash"
+	"gorm.io/gorm"
+)
+
+// Account deletion request statuses
+const (
+	// AccountDeletionStatusPending is a request whose account is soft-deleted and awaits hard deletion
+	AccountDeletionStatusPending = "pending"
+	// AccountDeletionStatusCancelled is a request the user cancelled within the grace period, or
+	// whose account was restored by other means
+	AccountDeletionStatusCancelled = "cancelled"
+	// AccountDeletionStatusCompleted is a request whose account was hard deleted
+	AccountDeletionStatusCompleted = "completed"
+)
+
+// AccountDeletionRequest records a user's request to delete their own account. The account is
+// soft-deleted when the request is made and hard deleted once ScheduledFor passes, unless the user
+// cancels first. The request outlives the account, as evidence the deletion was asked for.
+type AccountDeletionRequest struct {
+	*base.BaseModel
+	UserID       string     `json:"user_id" gorm:"type:varchar(255);not null;index"`
+	Status       string     `json:"status" gorm:"type:varchar(20);not null;default:'pending';index"`
+	Reason       string     `json:"reason,omitempty" gorm:"type:text"`
+	ScheduledFor time.Time  `json:"scheduled_for" gorm:"not null;index"`
+	CancelledAt  *time.Time `json:"cancelled_at,omitempty"`
+	CompletedAt  *time.Time `json:"completed_at,omitempty"`
+}
+
+// NewAccountDeletionRequest creates a pending deletion request of a user's account
+func NewAccountDeletionRequest(userID, reason string, scheduledFor time.Time) *AccountDeletionRequest {
+	return &AccountDeletionRequest{
+		BaseModel:    base.NewBaseModel("ACC_DEL", hash.Medium),
+		UserID:       userID,
+		Status:       AccountDeletionStatusPending,
+		Reason:       reason,
+		ScheduledFor: scheduledFor,
+	}
+}
+
+// IsDue reports whether the grace period of a pending request is over at the given time
+func (r *AccountDeletionRequest) IsDue(now time.Time) bool {
+	return r.Status == AccountDeletionStatusPending && !now.Before(r.ScheduledFor)
+}
+
+// MarkCancelled marks the request cancelled at the given time
+func (r *AccountDeletionRequest) MarkCancelled(now time.Time) {
+	r.Status = AccountDeletionStatusCancelled
+	r.CancelledAt = &now
+}
+
+// MarkCompleted marks the request completed at the given time
+func (r *AccountDeletionRequest) MarkCompleted(now time.Time) {
+	r.Status = AccountDeletionStatusCompleted
+	r.CompletedAt = &now
+}
+
+func (r *AccountDeletionRequest) BeforeCreate() error     { return r.BaseModel.BeforeCreate() }
+func (r *AccountDeletionRequest) BeforeUpdate() error     { return r.BaseModel.BeforeUpdate() }
+func (r *AccountDeletionRequest) BeforeDelete() error     { return r.BaseModel.BeforeDelete() }
+func (r *AccountDeletionRequest) BeforeSoftDelete() error { return r.BaseModel.BeforeSoftDelete() }
+
+// GORM Hooks - These are for GORM compatibility
+// BeforeCreateGORM is called by GORM before creating a new record
+func (r *AccountDeletionRequest) BeforeCreateGORM(tx *gorm.DB) error {
+	return r.BeforeCreate()
+}
+
+// BeforeUpdateGORM is called by GORM before updating an existing record
+func (r *AccountDeletionRequest) BeforeUpdateGORM(tx *gorm.DB) error {
+	return r.BeforeUpdate()
+}
+
+// AfterFind initializes the embedded BaseModel pointer when GORM loads a record
+func (r *AccountDeletionRequest) AfterFind(tx *gorm.DB) error {
+	if r.BaseModel == nil {
+		r.BaseModel = &base.BaseModel{}
+	}
+	return nil
+}
+
+func (r *AccountDeletionRequest) GetTableIdentifier() string   { return "ACC_DEL" }
+func (r *AccountDeletionRequest) GetTableSize() hash.TableSize { return hash.Medium }
+
+// TableName returns the GORM table name for this model
+func (r *AccountDeletionRequest) TableName() string { return "account_deletion_requests" }
+
+// Explicit method implementations to satisfy linter
+func (r *AccountDeletionRequest) GetID() string   { return r.BaseModel.GetID() }
+func (r *AccountDeletionRequest) SetID(id string) { r.BaseModel.SetID(id) }
@@ -9,18 +9,20 @@ import (
 // Address represents a user's address
 type Address struct {
 	*base.BaseModel
-	UserID      string  `json:"user_id" gorm:"type:varchar(255);not null;index"`
-	House       *string `json:"house" gorm:"type:varchar(255)"`
-	Street      *string `json:"street" gorm:"type:varchar(255)"`
-	Landmark    *string `json:"landmark" gorm:"type:varchar(255)"`
-	PostOffice  *string `json:"post_office" gorm:"type:varchar(255)"`
-	Subdistrict *string `json:"subdistrict" gorm:"type:varchar(255)"`
-	District    *string `json:"district" gorm:"type:varchar(255)"`
-	VTC         *string `json:"vtc" gorm:"type:varchar(255)"` // Village/Town/City
-	State       *string `json:"state" gorm:"type:varchar(255)"`
-	Country     *string `json:"country" gorm:"type:varchar(255)"`
-	Pincode     *string `json:"pincode" gorm:"type:varchar(10)"`
-	FullAddress *string `json:"full_address" gorm:"type:text"`
+	UserID      string   `json:"user_id" gorm:"type:varchar(255);not null;index"`
+	House       *string  `json:"house" gorm:"type:varchar(255)"`
+	Street      *string  `json:"street" gorm:"type:varchar(255)"`
+	Landmark    *string  `json:"landmark" gorm:"type:varchar(255)"`
+	PostOffice  *string  `json:"post_office" gorm:"type:varchar(255)"`
+	Subdistrict *string  `json:"subdistrict" gorm:"type:varchar(255)"`
+	District    *string  `json:"district" gorm:"type:varchar(255)"`
+	VTC         *string  `json:"vtc" gorm:"type:varchar(255)"` // Village/Town/City
+	State       *string  `json:"state" gorm:"type:varchar(255)"`
+	Country     *string  `json:"country" gorm:"type:varchar(255)"`
+	Pincode     *string  `json:"pincode" gorm:"type:varchar(10)"`
+	FullAddress *string  `json:"full_address" gorm:"type:text"`
+	Latitude    *float64 `json:"latitude,omitempty" gorm:"type:double precision"`
+	Longitude   *float64 `json:"longitude,omitempty" gorm:"type:double precision"`
 }
 
 const (
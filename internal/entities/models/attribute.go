@@ -6,6 +6,7 @@ import (
 	"errors"
 	"time"
 
+	"github.com/Kisanlink/aaa-service/v2/internal/utils/utc"
 	"github.com/Kisanlink/kisanlink-db/pkg/base"
 	"github.com/Kisanlink/kisanlink-db/pkg/core/hash"
 	"gorm.io/gorm"
@@ -122,7 +123,7 @@ func NewAttributeHistory(attributeID, subjectID string, subjectType AttributeSub
 		Key:         key,
 		Action:      action,
 		ChangedByID: changedByID,
-		ChangedAt:   time.Now(),
+		ChangedAt:   utc.Now(),
 	}
 }
 
@@ -145,7 +146,7 @@ func (ah *AttributeHistory) BeforeCreate() error {
 	}
 
 	if ah.ChangedAt.IsZero() {
-		ah.ChangedAt = time.Now()
+		ah.ChangedAt = utc.Now()
 	}
 
 	return nil
@@ -3,6 +3,7 @@ package models
 import (
 	"time"
 
+	"github.com/Kisanlink/aaa-service/v2/internal/utils/utc"
 	"github.com/Kisanlink/kisanlink-db/pkg/base"
 	"github.com/Kisanlink/kisanlink-db/pkg/core/hash"
 	"gorm.io/gorm"
@@ -13,10 +14,12 @@ import (
 type AuditLog struct {
 	*base.BaseModel
 	UserID       *string                `json:"user_id" gorm:"type:varchar(255);default:null"`
+	ActorID      *string                `json:"actor_id,omitempty" gorm:"type:varchar(255);default:null;index"`        // who performed the action, the real user when UserID was impersonated
+	OnBehalfOfID *string                `json:"on_behalf_of_id,omitempty" gorm:"type:varchar(255);default:null;index"` // user the action was performed on or for, when not the actor
 	Action       string                 `json:"action" gorm:"size:100;not null"`
 	ResourceType string                 `json:"resource_type" gorm:"size:100;not null"` // e.g., "aaa/user", "aaa/role"
 	ResourceID   *string                `json:"resource_id" gorm:"type:varchar(255);default:null"`
-	IPAddress    string                 `json:"ip_address" gorm:"size:45"`
+	IPAddress    string                 `json:"ip_address" gorm:"size:45;index"`
 	UserAgent    string                 `json:"user_agent" gorm:"type:text"`
 	Status       string                 `json:"status" gorm:"size:20;not null"` // success, failure, warning
 	Message      string                 `json:"message" gorm:"type:text"`
@@ -65,21 +68,41 @@ const (
 	AuditActionBackup            = "backup"
 	AuditActionRestore           = "restore"
 	AuditActionAPICall           = "api_call"
+	AuditActionHTTPRequest       = "http_request"
+	AuditActionGRPCCall          = "grpc_call"
 	AuditActionDatabaseOperation = "database_operation"
+	// Permission changes audited by LogPermissionChange; those on a role form its permission history
+	AuditActionPermissionGrant  = "permission_grant"
+	AuditActionPermissionRevoke = "permission_revoke"
+	// Impersonation operations
+	AuditActionStartImpersonation = "start_impersonation"
+	AuditActionStopImpersonation  = "stop_impersonation"
 	// Organization operations
-	AuditActionCreateOrganization     = "create_organization"
-	AuditActionUpdateOrganization     = "update_organization"
-	AuditActionDeleteOrganization     = "delete_organization"
-	AuditActionActivateOrganization   = "activate_organization"
-	AuditActionDeactivateOrganization = "deactivate_organization"
+	AuditActionCreateOrganization         = "create_organization"
+	AuditActionUpdateOrganization         = "update_organization"
+	AuditActionDeleteOrganization         = "delete_organization"
+	AuditActionHardDeleteOrganization     = "hard_delete_organization"
+	AuditActionRestoreOrganization        = "restore_organization"
+	AuditActionMergeOrganization          = "merge_organization"
+	AuditActionActivateOrganization       = "activate_organization"
+	AuditActionDeactivateOrganization     = "deactivate_organization"
+	AuditActionAddOrganizationMember      = "add_organization_member"
+	AuditActionRemoveOrganizationMember   = "remove_organization_member"
+	AuditActionUpdateOrganizationSettings = "update_organization_settings"
+	AuditActionApplyRoleTemplate          = "apply_role_template"
+	AuditActionUpdateUserAttributeSchema  = "update_user_attribute_schema"
+	AuditActionUpdateUserAttributes       = "update_user_attributes"
 	// Group operations
-	AuditActionCreateGroup       = "create_group"
-	AuditActionUpdateGroup       = "update_group"
-	AuditActionDeleteGroup       = "delete_group"
-	AuditActionAddGroupMember    = "add_group_member"
-	AuditActionRemoveGroupMember = "remove_group_member"
-	AuditActionAssignGroupRole   = "assign_group_role"
-	AuditActionRemoveGroupRole   = "remove_group_role"
+	AuditActionCreateGroup        = "create_group"
+	AuditActionUpdateGroup        = "update_group"
+	AuditActionDeleteGroup        = "delete_group"
+	AuditActionAddGroupMember     = "add_group_member"
+	AuditActionRemoveGroupMember  = "remove_group_member"
+	AuditActionAssignGroupRole    = "assign_group_role"
+	AuditActionRemoveGroupRole    = "remove_group_role"
+	AuditActionRestoreGroup       = "restore_group"
+	AuditActionRestoreGroupMember = "restore_group_member"
+	AuditActionRestoreGroupRole   = "restore_group_role"
 	// Organization hierarchy operations
 	AuditActionChangeOrganizationHierarchy = "change_organization_hierarchy"
 	AuditActionChangeGroupHierarchy        = "change_group_hierarchy"
@@ -94,7 +117,7 @@ func NewAuditLog(action, resourceType, status, message string) *AuditLog {
 		Status:       status,
 		Message:      message,
 		Details:      make(map[string]interface{}),
-		Timestamp:    time.Now(),
+		Timestamp:    utc.Now(),
 	}
 }
 
@@ -120,10 +143,21 @@ func NewAuditLogWithUserAndResource(userID, action, resourceType, resourceID, st
 	return auditLog
 }
 
+// SetDelegation records who performed the action and the user it was performed on behalf of.
+// Empty IDs are ignored, and so is an on-behalf-of user that is the actor.
+func (al *AuditLog) SetDelegation(actorID, onBehalfOfID string) {
+	if actorID != "" {
+		al.ActorID = &actorID
+	}
+	if onBehalfOfID != "" && onBehalfOfID != actorID {
+		al.OnBehalfOfID = &onBehalfOfID
+	}
+}
+
 // BeforeCreate is called before creating a new audit log
 func (al *AuditLog) BeforeCreate() error {
 	if al.Timestamp.IsZero() {
-		al.Timestamp = time.Now()
+		al.Timestamp = utc.Now()
 	}
 	return al.BaseModel.BeforeCreate()
 }
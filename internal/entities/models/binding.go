@@ -6,6 +6,7 @@ import (
 	"errors"
 	"time"
 
+	"github.com/Kisanlink/aaa-service/v2/internal/utils/utc"
 	"github.com/Kisanlink/kisanlink-db/pkg/base"
 	"github.com/Kisanlink/kisanlink-db/pkg/core/hash"
 	"gorm.io/gorm"
@@ -164,7 +165,7 @@ func (bh *BindingHistory) BeforeCreate() error {
 		return err
 	}
 	if bh.ChangedAt.IsZero() {
-		bh.ChangedAt = time.Now()
+		bh.ChangedAt = utc.Now()
 	}
 	return nil
 }
@@ -252,7 +253,7 @@ func (b *Binding) CreateHistoryRecord(action string, changedByID string) *Bindin
 		Version:        b.Version,
 		Action:         action,
 		ChangedByID:    changedByID,
-		ChangedAt:      time.Now(),
+		ChangedAt:      utc.Now(),
 	}
 }
 
@@ -263,6 +264,6 @@ func NewBindingHistory(bindingID string, action string, changedByID string) *Bin
 		BindingID:   bindingID,
 		Action:      action,
 		ChangedByID: changedByID,
-		ChangedAt:   time.Now(),
+		ChangedAt:   utc.Now(),
 	}
 }
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/Kisanlink/aaa-service/v2/internal/utils/utc"
 	"github.com/Kisanlink/kisanlink-db/pkg/base"
 	"github.com/Kisanlink/kisanlink-db/pkg/core/hash"
 	"gorm.io/gorm"
@@ -145,7 +146,7 @@ func (c *Contact) SetAsSecondary() {
 func (c *Contact) Verify(verifiedBy string) {
 	c.IsVerified = true
 	c.VerifiedBy = &verifiedBy
-	now := time.Now().Format(time.RFC3339)
+	now := utc.Now().Format(time.RFC3339)
 	c.VerifiedAt = &now
 }
 
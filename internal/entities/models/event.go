@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/Kisanlink/aaa-service/v2/internal/utils/utc"
 	"github.com/Kisanlink/kisanlink-db/pkg/base"
 	"github.com/Kisanlink/kisanlink-db/pkg/core/hash"
 	"gorm.io/gorm"
@@ -124,7 +125,7 @@ func (e *Event) TableName() string {
 func NewEvent(actorID, actorType string, kind EventKind, resourceType, resourceID string, payload EventPayload) *Event {
 	return &Event{
 		BaseModel:    base.NewBaseModel("evt", hash.Large),
-		OccurredAt:   time.Now(),
+		OccurredAt:   utc.Now(),
 		ActorID:      actorID,
 		ActorType:    actorType,
 		Kind:         kind,
@@ -142,7 +143,7 @@ func (e *Event) BeforeCreate() error {
 
 	// OccurredAt must be set
 	if e.OccurredAt.IsZero() {
-		e.OccurredAt = time.Now()
+		e.OccurredAt = utc.Now()
 	}
 
 	// Payload cannot be nil
@@ -265,7 +266,7 @@ func (ec *EventCheckpoint) TableName() string {
 func NewEventCheckpoint(lastEventID string, lastSequenceNum int64, lastEventHash string, merkleRoot string, eventCount int64, createdByID string) *EventCheckpoint {
 	return &EventCheckpoint{
 		BaseModel:       base.NewBaseModel("EVENT", hash.Small),
-		CheckpointTime:  time.Now(),
+		CheckpointTime:  utc.Now(),
 		LastEventID:     lastEventID,
 		LastSequenceNum: lastSequenceNum,
 		LastEventHash:   lastEventHash,
@@ -282,7 +283,7 @@ func (ec *EventCheckpoint) BeforeCreate() error {
 	}
 
 	if ec.CheckpointTime.IsZero() {
-		ec.CheckpointTime = time.Now()
+		ec.CheckpointTime = utc.Now()
 	}
 
 	return nil
@@ -2,6 +2,7 @@ package models
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/Kisanlink/kisanlink-db/pkg/base"
 	"github.com/Kisanlink/kisanlink-db/pkg/core/hash"
@@ -62,25 +63,75 @@ func NewOrganization(name, description, orgType string) *Organization {
 	}
 }
 
+// organizationTypes lists the known organization types in the order they are documented. The
+// request validation tags and the Swagger type lists repeat it and are checked against it in tests.
+var organizationTypes = []string{
+	OrgTypeEnterprise,
+	OrgTypeSmallBusiness,
+	OrgTypeIndividual,
+	OrgTypeFPO,
+	OrgTypeCooperative,
+	OrgTypeAgribusiness,
+	OrgTypeFarmersGroup,
+	OrgTypeSHG,
+	OrgTypeNGO,
+	OrgTypeGovernment,
+	OrgTypeInputSupplier,
+	OrgTypeTrader,
+	OrgTypeProcessingUnit,
+	OrgTypeResearchInstitute,
+}
+
+// organizationTypeTransitions lists the types an organization may change to. Organizations can
+// formalize (an individual becoming a business or a farmers group registering as an FPO) and
+// businesses can change line of business, but not go back to a less formal type. NGOs, government
+// agencies and research institutes keep their type.
+var organizationTypeTransitions = map[string][]string{
+	OrgTypeIndividual: {
+		OrgTypeSmallBusiness, OrgTypeEnterprise, OrgTypeAgribusiness, OrgTypeInputSupplier, OrgTypeTrader,
+		OrgTypeProcessingUnit, OrgTypeFarmersGroup, OrgTypeSHG, OrgTypeFPO, OrgTypeCooperative,
+	},
+	OrgTypeFarmersGroup: {OrgTypeSHG, OrgTypeFPO, OrgTypeCooperative, OrgTypeNGO},
+	OrgTypeSHG:          {OrgTypeFarmersGroup, OrgTypeFPO, OrgTypeCooperative, OrgTypeNGO},
+	OrgTypeFPO:          {OrgTypeCooperative},
+	OrgTypeCooperative:  {OrgTypeFPO},
+	OrgTypeSmallBusiness: {
+		OrgTypeEnterprise, OrgTypeAgribusiness, OrgTypeInputSupplier, OrgTypeTrader, OrgTypeProcessingUnit,
+	},
+	OrgTypeEnterprise:     {OrgTypeAgribusiness, OrgTypeInputSupplier, OrgTypeTrader, OrgTypeProcessingUnit},
+	OrgTypeAgribusiness:   {OrgTypeEnterprise, OrgTypeInputSupplier, OrgTypeTrader, OrgTypeProcessingUnit},
+	OrgTypeInputSupplier:  {OrgTypeEnterprise, OrgTypeAgribusiness, OrgTypeTrader, OrgTypeProcessingUnit},
+	OrgTypeTrader:         {OrgTypeEnterprise, OrgTypeAgribusiness, OrgTypeInputSupplier, OrgTypeProcessingUnit},
+	OrgTypeProcessingUnit: {OrgTypeEnterprise, OrgTypeAgribusiness, OrgTypeInputSupplier, OrgTypeTrader},
+}
+
+// OrganizationTypes returns the known organization types
+func OrganizationTypes() []string {
+	return append([]string(nil), organizationTypes...)
+}
+
 // ValidOrganizationType checks if the provided organization type is valid
 func ValidOrganizationType(orgType string) bool {
-	validTypes := map[string]bool{
-		OrgTypeEnterprise:        true,
-		OrgTypeSmallBusiness:     true,
-		OrgTypeIndividual:        true,
-		OrgTypeFPO:               true,
-		OrgTypeCooperative:       true,
-		OrgTypeAgribusiness:      true,
-		OrgTypeFarmersGroup:      true,
-		OrgTypeSHG:               true,
-		OrgTypeNGO:               true,
-		OrgTypeGovernment:        true,
-		OrgTypeInputSupplier:     true,
-		OrgTypeTrader:            true,
-		OrgTypeProcessingUnit:    true,
-		OrgTypeResearchInstitute: true,
+	for _, known := range organizationTypes {
+		if orgType == known {
+			return true
+		}
 	}
-	return validTypes[orgType]
+	return false
+}
+
+// CanChangeOrganizationType reports whether an organization of type from may change to type to.
+// Keeping the same type is always allowed.
+func CanChangeOrganizationType(from, to string) bool {
+	if from == to {
+		return ValidOrganizationType(to)
+	}
+	for _, allowed := range organizationTypeTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
 }
 
 // BeforeCreate is called before creating a new organization
@@ -165,3 +216,54 @@ func (o *Organization) GetResourceType() string {
 func (o *Organization) GetObjectID() string {
 	return o.GetID()
 }
+
+// OrganizationDeletionSnapshot holds the rows removed by the hard delete of an organization
+type OrganizationDeletionSnapshot struct {
+	Organization     *Organization          `json:"organization"`
+	Groups           []*Group               `json:"groups"`
+	GroupMemberships []*GroupMembership     `json:"group_memberships"`
+	GroupInheritance []*GroupInheritance    `json:"group_inheritance"`
+	GroupRoles       []*GroupRole           `json:"group_roles"`
+	Members          []*OrganizationMember  `json:"members"`
+	Settings         []*OrganizationSetting `json:"settings"`
+}
+
+// OrganizationCascadeSummary holds the rows soft-deleted, or restored, together with an
+// organization. They all carry the organization's deletion time.
+type OrganizationCascadeSummary struct {
+	Organization     *Organization      `json:"organization"`
+	Groups           []*Group           `json:"groups"`
+	GroupMemberships []*GroupMembership `json:"group_memberships"`
+	GroupRoles       []*GroupRole       `json:"group_roles"`
+	DeletedAt        time.Time          `json:"deleted_at"`
+}
+
+// OrganizationMergeSummary describes what the merge of one organization into another changed
+type OrganizationMergeSummary struct {
+	SourceID string `json:"source_id"`
+	TargetID string `json:"target_id"`
+
+	// ReparentedChildren are the child organizations of the source now under the target
+	ReparentedChildren []string `json:"reparented_children"`
+	// MovedGroups are the groups of the source now owned by the target; RenamedGroups maps the
+	// IDs of those renamed because the target already had a group of the same name to their new name
+	MovedGroups   []string          `json:"moved_groups"`
+	RenamedGroups map[string]string `json:"renamed_groups,omitempty"`
+	// MovedMembers are the users whose membership moved to the target; MergedMembers were already
+	// members of the target and kept that membership
+	MovedMembers  []string `json:"moved_members"`
+	MergedMembers []string `json:"merged_members"`
+	// MovedRoles are the roles of the source now owned by the target; MergedRoles maps the IDs of
+	// source roles folded into the target role of the same name to that role's ID
+	MovedRoles  []string          `json:"moved_roles"`
+	MergedRoles map[string]string `json:"merged_roles,omitempty"`
+	// UnmergedPermissions lists, by merged source role ID, the permission IDs the source role had
+	// and the target role lacks. They are not granted by the merge.
+	UnmergedPermissions map[string][]string `json:"unmerged_permissions,omitempty"`
+	// MovedSettings are the setting keys moved to the target; SkippedSettings were already set on
+	// the target and stay with the source
+	MovedSettings   []string `json:"moved_settings"`
+	SkippedSettings []string `json:"skipped_settings"`
+	// MovedRecords counts, by table, the other organization-scoped rows moved to the target
+	MovedRecords map[string]int64 `json:"moved_records,omitempty"`
+}
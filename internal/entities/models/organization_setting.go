@@ -0,0 +1,227 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/Kisanlink/kisanlink-db/pkg/base"
+	"github.com/Kisanlink/kisanlink-db/pkg/core/hash"
+	"gorm.io/gorm"
+)
+
+// OrganizationSettingType is the JSON type a setting value must have
+type OrganizationSettingType string
+
+const (
+	OrganizationSettingTypeBool   OrganizationSettingType = "bool"
+	OrganizationSettingTypeInt    OrganizationSettingType = "int"
+	OrganizationSettingTypeString OrganizationSettingType = "string"
+)
+
+// Known organization setting keys
+const (
+	// OrgSettingKYCRequiredBeforeRoleAssignment requires users to complete KYC before they are given roles in the organization
+	OrgSettingKYCRequiredBeforeRoleAssignment = "kyc_required_before_role_assignment"
+	// OrgSettingMaxMembers caps the number of direct members of the organization; 0 means unlimited
+	OrgSettingMaxMembers = "max_members"
+	// OrgSettingDefaultMemberRole is the member role given to users added without an explicit role
+	OrgSettingDefaultMemberRole = "default_member_role"
+	// OrgSettingDefaultUserRoles lists, comma-separated, the roles given to users registered in the organization
+	OrgSettingDefaultUserRoles = "default_user_roles"
+	// OrgSettingMaxConcurrentSessions caps the logins each member can hold at once; 0 leaves the service limit
+	OrgSettingMaxConcurrentSessions = "max_concurrent_sessions"
+)
+
+// OrganizationSettingDefinition describes a known setting: its type, its default and, for
+// some organization types, a different default
+type OrganizationSettingDefinition struct {
+	Key          string
+	Type         OrganizationSettingType
+	Description  string
+	Default      interface{}
+	TypeDefaults map[string]interface{}
+	// Min and Max bound int settings
+	Min, Max int64
+	// Allowed restricts string settings to a fixed set of values
+	Allowed []string
+}
+
+// OrganizationSettingDefinitions lists every setting that can be stored for an organization
+var OrganizationSettingDefinitions = map[string]OrganizationSettingDefinition{
+	OrgSettingKYCRequiredBeforeRoleAssignment: {
+		Key:         OrgSettingKYCRequiredBeforeRoleAssignment,
+		Type:        OrganizationSettingTypeBool,
+		Description: "Users must complete KYC before roles are assigned to them in this organization",
+		Default:     false,
+		TypeDefaults: map[string]interface{}{
+			OrgTypeFPO:         true,
+			OrgTypeCooperative: true,
+		},
+	},
+	OrgSettingMaxMembers: {
+		Key:         OrgSettingMaxMembers,
+		Type:        OrganizationSettingTypeInt,
+		Description: "Maximum number of direct members; 0 means unlimited",
+		Default:     int64(0),
+		Min:         0,
+		Max:         1000000,
+	},
+	OrgSettingDefaultMemberRole: {
+		Key:         OrgSettingDefaultMemberRole,
+		Type:        OrganizationSettingTypeString,
+		Description: "Member role given to users added to the organization without an explicit role",
+		Default:     OrganizationMemberRoleMember,
+		Allowed:     []string{OrganizationMemberRoleAdmin, OrganizationMemberRoleMember},
+	},
+	OrgSettingDefaultUserRoles: {
+		Key:         OrgSettingDefaultUserRoles,
+		Type:        OrganizationSettingTypeString,
+		Description: "Comma-separated names of roles given to users registered in this organization, in addition to the global default roles",
+		Default:     "",
+	},
+	OrgSettingMaxConcurrentSessions: {
+		Key:         OrgSettingMaxConcurrentSessions,
+		Type:        OrganizationSettingTypeInt,
+		Description: "Maximum number of logins each member can hold at once; 0 leaves the service limit. The lowest limit of a user's organizations applies",
+		Default:     int64(0),
+		Min:         0,
+		Max:         1000,
+	},
+}
+
+// DefaultFor returns the value of the setting for an organization of orgType that has not set it
+func (d OrganizationSettingDefinition) DefaultFor(orgType string) interface{} {
+	if value, ok := d.TypeDefaults[orgType]; ok {
+		return value
+	}
+	return d.Default
+}
+
+// Normalize checks that value, as decoded from JSON, has the setting's type and range,
+// and returns it in canonical form (bool, int64 or string)
+func (d OrganizationSettingDefinition) Normalize(value interface{}) (interface{}, error) {
+	switch d.Type {
+	case OrganizationSettingTypeBool:
+		b, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("%s must be a boolean", d.Key)
+		}
+		return b, nil
+
+	case OrganizationSettingTypeInt:
+		var n int64
+		switch v := value.(type) {
+		case float64:
+			if v != math.Trunc(v) || math.Abs(v) > math.MaxInt64 {
+				return nil, fmt.Errorf("%s must be an integer", d.Key)
+			}
+			n = int64(v)
+		case int:
+			n = int64(v)
+		case int64:
+			n = v
+		default:
+			return nil, fmt.Errorf("%s must be an integer", d.Key)
+		}
+		if n < d.Min || n > d.Max {
+			return nil, fmt.Errorf("%s must be between %d and %d", d.Key, d.Min, d.Max)
+		}
+		return n, nil
+
+	case OrganizationSettingTypeString:
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s must be a string", d.Key)
+		}
+		if len(d.Allowed) > 0 {
+			for _, allowed := range d.Allowed {
+				if s == allowed {
+					return s, nil
+				}
+			}
+			return nil, fmt.Errorf("%s must be one of %v", d.Key, d.Allowed)
+		}
+		return s, nil
+	}
+
+	return nil, fmt.Errorf("%s has unsupported type %s", d.Key, d.Type)
+}
+
+// OrganizationSetting stores the value of one setting for one organization.
+// Organizations without a row for a known key use the key's default.
+type OrganizationSetting struct {
+	*base.BaseModel
+	OrganizationID string `json:"organization_id" gorm:"type:varchar(255);not null;uniqueIndex:idx_org_settings_org_key,priority:1"`
+	Key            string `json:"key" gorm:"size:100;not null;uniqueIndex:idx_org_settings_org_key,priority:2"`
+	Value          string `json:"value" gorm:"type:jsonb;not null"` // JSON-encoded value
+
+	// Relationships
+	Organization *Organization `json:"organization,omitempty" gorm:"foreignKey:OrganizationID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
+
+// NewOrganizationSetting creates a new OrganizationSetting with a JSON-encoded value
+func NewOrganizationSetting(organizationID, key string, value interface{}) (*OrganizationSetting, error) {
+	setting := &OrganizationSetting{
+		BaseModel:      base.NewBaseModel("ORGS", hash.Medium),
+		OrganizationID: organizationID,
+		Key:            key,
+	}
+	if err := setting.SetValue(value); err != nil {
+		return nil, err
+	}
+	return setting, nil
+}
+
+// SetValue JSON-encodes value into the setting
+func (s *OrganizationSetting) SetValue(value interface{}) error {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode setting %s: %w", s.Key, err)
+	}
+	s.Value = string(encoded)
+	return nil
+}
+
+// DecodedValue returns the stored value decoded from JSON
+func (s *OrganizationSetting) DecodedValue() (interface{}, error) {
+	var value interface{}
+	if err := json.Unmarshal([]byte(s.Value), &value); err != nil {
+		return nil, fmt.Errorf("failed to decode setting %s: %w", s.Key, err)
+	}
+	return value, nil
+}
+
+func (s *OrganizationSetting) BeforeCreate() error     { return s.BaseModel.BeforeCreate() }
+func (s *OrganizationSetting) BeforeUpdate() error     { return s.BaseModel.BeforeUpdate() }
+func (s *OrganizationSetting) BeforeDelete() error     { return s.BaseModel.BeforeDelete() }
+func (s *OrganizationSetting) BeforeSoftDelete() error { return s.BaseModel.BeforeSoftDelete() }
+
+// GORM Hooks - These are for GORM compatibility
+// BeforeCreateGORM is called by GORM before creating a new record
+func (s *OrganizationSetting) BeforeCreateGORM(tx *gorm.DB) error {
+	return s.BeforeCreate()
+}
+
+// BeforeUpdateGORM is called by GORM before updating an existing record
+func (s *OrganizationSetting) BeforeUpdateGORM(tx *gorm.DB) error {
+	return s.BeforeUpdate()
+}
+
+// AfterFind initializes the embedded BaseModel pointer when GORM loads a record
+func (s *OrganizationSetting) AfterFind(tx *gorm.DB) error {
+	if s.BaseModel == nil {
+		s.BaseModel = &base.BaseModel{}
+	}
+	return nil
+}
+
+func (s *OrganizationSetting) GetTableIdentifier() string   { return "ORGS" }
+func (s *OrganizationSetting) GetTableSize() hash.TableSize { return hash.Medium }
+
+// TableName returns the GORM table name for this model
+func (s *OrganizationSetting) TableName() string { return "organization_settings" }
+
+// Explicit method implementations to satisfy linter
+func (s *OrganizationSetting) GetID() string   { return s.BaseModel.GetID() }
+func (s *OrganizationSetting) SetID(id string) { s.BaseModel.SetID(id) }
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanChangeOrganizationType(t *testing.T) {
+	assert.True(t, CanChangeOrganizationType(OrgTypeIndividual, OrgTypeEnterprise))
+	assert.True(t, CanChangeOrganizationType(OrgTypeFarmersGroup, OrgTypeFPO))
+	assert.True(t, CanChangeOrganizationType(OrgTypeTrader, OrgTypeAgribusiness))
+	assert.True(t, CanChangeOrganizationType(OrgTypeGovernment, OrgTypeGovernment), "keeping the type is always allowed")
+
+	assert.False(t, CanChangeOrganizationType(OrgTypeEnterprise, OrgTypeIndividual))
+	assert.False(t, CanChangeOrganizationType(OrgTypeFPO, OrgTypeFarmersGroup))
+	assert.False(t, CanChangeOrganizationType(OrgTypeGovernment, OrgTypeNGO))
+	assert.False(t, CanChangeOrganizationType(OrgTypeIndividual, "conglomerate"))
+	assert.False(t, CanChangeOrganizationType("conglomerate", "conglomerate"))
+}
+
+func TestOrganizationTypeTransitionsUseKnownTypes(t *testing.T) {
+	for from, targets := range organizationTypeTransitions {
+		assert.True(t, ValidOrganizationType(from), "unknown source type %q", from)
+		for _, to := range targets {
+			assert.True(t, ValidOrganizationType(to), "unknown target type %q from %q", to, from)
+			assert.NotEqual(t, from, to, "%q lists itself as a transition", from)
+		}
+	}
+}
@@ -0,0 +1,282 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/Kisanlink/kisanlink-db/pkg/base"
+	"github.com/Kisanlink/kisanlink-db/pkg/core/hash"
+	"gorm.io/gorm"
+)
+
+// UserAttributeType is the JSON type a custom user attribute must have
+type UserAttributeType string
+
+const (
+	UserAttributeTypeString  UserAttributeType = "string"
+	UserAttributeTypeNumber  UserAttributeType = "number"
+	UserAttributeTypeInteger UserAttributeType = "integer"
+	UserAttributeTypeBoolean UserAttributeType = "boolean"
+)
+
+// MaxUserAttributeKeyLength bounds the names of custom user attributes
+const MaxUserAttributeKeyLength = 100
+
+// UserAttributeDefinition describes one custom attribute an organization keeps on its users
+type UserAttributeDefinition struct {
+	Type        UserAttributeType `json:"type"`
+	Required    bool              `json:"required,omitempty"`
+	Description string            `json:"description,omitempty"`
+	// Allowed restricts string attributes to a fixed set of values
+	Allowed []string `json:"allowed,omitempty"`
+	// Min and Max bound number and integer attributes
+	Min *float64 `json:"min,omitempty"`
+	Max *float64 `json:"max,omitempty"`
+	// MaxLength bounds string attributes; 0 leaves them unbounded
+	MaxLength int `json:"max_length,omitempty"`
+}
+
+// UserAttributeSchema maps the name of each custom user attribute of an organization to its
+// definition. Attributes not in a non-empty schema are rejected.
+type UserAttributeSchema map[string]UserAttributeDefinition
+
+// Scan implements the Scanner interface for database reads
+func (s *UserAttributeSchema) Scan(value interface{}) error {
+	if value == nil {
+		*s = make(UserAttributeSchema)
+		return nil
+	}
+
+	switch v := value.(type) {
+	case []byte:
+		return json.Unmarshal(v, s)
+	case string:
+		return json.Unmarshal([]byte(v), s)
+	default:
+		return errors.New("cannot scan user attribute schema from database")
+	}
+}
+
+// Value implements the Valuer interface for database writes
+func (s UserAttributeSchema) Value() (driver.Value, error) {
+	if s == nil {
+		return "{}", nil
+	}
+	return json.Marshal(s)
+}
+
+// Check reports the problems of the schema's definitions, sorted
+func (s UserAttributeSchema) Check() []string {
+	var problems []string
+	for key, def := range s {
+		if problem := checkUserAttributeKey(key); problem != "" {
+			problems = append(problems, problem)
+			continue
+		}
+		switch def.Type {
+		case UserAttributeTypeString:
+			if def.MaxLength < 0 {
+				problems = append(problems, fmt.Sprintf("%s: max_length must not be negative", key))
+			}
+		case UserAttributeTypeNumber, UserAttributeTypeInteger:
+			if def.Min != nil && def.Max != nil && *def.Min > *def.Max {
+				problems = append(problems, fmt.Sprintf("%s: min must not exceed max", key))
+			}
+		case UserAttributeTypeBoolean:
+		default:
+			problems = append(problems, fmt.Sprintf("%s: type must be string, number, integer or boolean", key))
+			continue
+		}
+		if len(def.Allowed) > 0 && def.Type != UserAttributeTypeString {
+			problems = append(problems, fmt.Sprintf("%s: allowed values apply to string attributes only", key))
+		}
+	}
+	sort.Strings(problems)
+	return problems
+}
+
+// Validate reports, sorted, how attrs breaks the schema: unknown attributes, missing required
+// attributes and values of the wrong type or out of range. An empty schema accepts any attributes.
+func (s UserAttributeSchema) Validate(attrs map[string]interface{}) []string {
+	var problems []string
+	for key, value := range attrs {
+		if problem := checkUserAttributeKey(key); problem != "" {
+			problems = append(problems, problem)
+			continue
+		}
+		if len(s) == 0 {
+			continue
+		}
+		def, ok := s[key]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%s is not an attribute of this organization", key))
+			continue
+		}
+		if problem := def.check(key, value); problem != "" {
+			problems = append(problems, problem)
+		}
+	}
+	for key, def := range s {
+		if _, ok := attrs[key]; def.Required && !ok {
+			problems = append(problems, fmt.Sprintf("%s is required", key))
+		}
+	}
+	sort.Strings(problems)
+	return problems
+}
+
+// check reports how value, as decoded from JSON, breaks the definition
+func (d UserAttributeDefinition) check(key string, value interface{}) string {
+	if value == nil {
+		if d.Required {
+			return fmt.Sprintf("%s is required", key)
+		}
+		return ""
+	}
+
+	switch d.Type {
+	case UserAttributeTypeString:
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Sprintf("%s must be a string", key)
+		}
+		if d.MaxLength > 0 && len([]rune(s)) > d.MaxLength {
+			return fmt.Sprintf("%s must be at most %d characters", key, d.MaxLength)
+		}
+		if len(d.Allowed) > 0 {
+			for _, allowed := range d.Allowed {
+				if s == allowed {
+					return ""
+				}
+			}
+			return fmt.Sprintf("%s must be one of %v", key, d.Allowed)
+		}
+
+	case UserAttributeTypeNumber, UserAttributeTypeInteger:
+		n, ok := value.(float64)
+		if !ok {
+			return fmt.Sprintf("%s must be a number", key)
+		}
+		if d.Type == UserAttributeTypeInteger && n != math.Trunc(n) {
+			return fmt.Sprintf("%s must be an integer", key)
+		}
+		if d.Min != nil && n < *d.Min {
+			return fmt.Sprintf("%s must be at least %v", key, *d.Min)
+		}
+		if d.Max != nil && n > *d.Max {
+			return fmt.Sprintf("%s must be at most %v", key, *d.Max)
+		}
+
+	case UserAttributeTypeBoolean:
+		if _, ok := value.(bool); !ok {
+			return fmt.Sprintf("%s must be a boolean", key)
+		}
+	}
+	return ""
+}
+
+func checkUserAttributeKey(key string) string {
+	if key == "" {
+		return "attribute names cannot be empty"
+	}
+	if len(key) > MaxUserAttributeKeyLength {
+		return fmt.Sprintf("attribute name %.20s... is longer than %d characters", key, MaxUserAttributeKeyLength)
+	}
+	return ""
+}
+
+// OrganizationUserAttributeSchema stores the custom user attribute schema of one organization.
+// Organizations without one accept any attributes.
+type OrganizationUserAttributeSchema struct {
+	*base.BaseModel
+	OrganizationID string              `json:"organization_id" gorm:"type:varchar(255);not null;uniqueIndex:idx_org_user_attribute_schemas_org"`
+	Attributes     UserAttributeSchema `json:"attributes" gorm:"type:jsonb;not null"`
+
+	// Relationships
+	Organization *Organization `json:"organization,omitempty" gorm:"foreignKey:OrganizationID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
+
+// NewOrganizationUserAttributeSchema creates a new OrganizationUserAttributeSchema
+func NewOrganizationUserAttributeSchema(organizationID string, attributes UserAttributeSchema) *OrganizationUserAttributeSchema {
+	return &OrganizationUserAttributeSchema{
+		BaseModel:      base.NewBaseModel("OUAS", hash.Medium),
+		OrganizationID: organizationID,
+		Attributes:     attributes,
+	}
+}
+
+func (s *OrganizationUserAttributeSchema) BeforeCreate() error { return s.BaseModel.BeforeCreate() }
+func (s *OrganizationUserAttributeSchema) BeforeUpdate() error { return s.BaseModel.BeforeUpdate() }
+func (s *OrganizationUserAttributeSchema) BeforeDelete() error { return s.BaseModel.BeforeDelete() }
+func (s *OrganizationUserAttributeSchema) BeforeSoftDelete() error {
+	return s.BaseModel.BeforeSoftDelete()
+}
+
+// AfterFind initializes the embedded BaseModel pointer when GORM loads a record
+func (s *OrganizationUserAttributeSchema) AfterFind(tx *gorm.DB) error {
+	if s.BaseModel == nil {
+		s.BaseModel = &base.BaseModel{}
+	}
+	return nil
+}
+
+func (s *OrganizationUserAttributeSchema) GetTableIdentifier() string   { return "OUAS" }
+func (s *OrganizationUserAttributeSchema) GetTableSize() hash.TableSize { return hash.Medium }
+
+// TableName returns the GORM table name for this model
+func (s *OrganizationUserAttributeSchema) TableName() string {
+	return "organization_user_attribute_schemas"
+}
+
+// Explicit method implementations to satisfy linter
+func (s *OrganizationUserAttributeSchema) GetID() string   { return s.BaseModel.GetID() }
+func (s *OrganizationUserAttributeSchema) SetID(id string) { s.BaseModel.SetID(id) }
+
+// OrganizationUserAttributes stores the custom attributes one organization keeps on one user
+type OrganizationUserAttributes struct {
+	*base.BaseModel
+	OrganizationID string         `json:"organization_id" gorm:"type:varchar(255);not null;uniqueIndex:idx_org_user_attributes_org_user,priority:1"`
+	UserID         string         `json:"user_id" gorm:"type:varchar(255);not null;uniqueIndex:idx_org_user_attributes_org_user,priority:2;index"`
+	Attributes     AttributeValue `json:"attributes" gorm:"type:jsonb;not null"`
+
+	// Relationships
+	Organization *Organization `json:"organization,omitempty" gorm:"foreignKey:OrganizationID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	User         *User         `json:"user,omitempty" gorm:"foreignKey:UserID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
+
+// NewOrganizationUserAttributes creates a new OrganizationUserAttributes
+func NewOrganizationUserAttributes(organizationID, userID string, attributes map[string]interface{}) *OrganizationUserAttributes {
+	return &OrganizationUserAttributes{
+		BaseModel:      base.NewBaseModel("OUAV", hash.Medium),
+		OrganizationID: organizationID,
+		UserID:         userID,
+		Attributes:     AttributeValue(attributes),
+	}
+}
+
+func (a *OrganizationUserAttributes) BeforeCreate() error     { return a.BaseModel.BeforeCreate() }
+func (a *OrganizationUserAttributes) BeforeUpdate() error     { return a.BaseModel.BeforeUpdate() }
+func (a *OrganizationUserAttributes) BeforeDelete() error     { return a.BaseModel.BeforeDelete() }
+func (a *OrganizationUserAttributes) BeforeSoftDelete() error { return a.BaseModel.BeforeSoftDelete() }
+
+// AfterFind initializes the embedded BaseModel pointer when GORM loads a record
+func (a *OrganizationUserAttributes) AfterFind(tx *gorm.DB) error {
+	if a.BaseModel == nil {
+		a.BaseModel = &base.BaseModel{}
+	}
+	return nil
+}
+
+func (a *OrganizationUserAttributes) GetTableIdentifier() string   { return "OUAV" }
+func (a *OrganizationUserAttributes) GetTableSize() hash.TableSize { return hash.Medium }
+
+// TableName returns the GORM table name for this model
+func (a *OrganizationUserAttributes) TableName() string { return "organization_user_attributes" }
+
+// Explicit method implementations to satisfy linter
+func (a *OrganizationUserAttributes) GetID() string   { return a.BaseModel.GetID() }
+func (a *OrganizationUserAttributes) SetID(id string) { a.BaseModel.SetID(id) }
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserAttributeSchema_EmptySchemaAcceptsAnyAttributes(t *testing.T) {
+	var schema UserAttributeSchema
+
+	assert.Empty(t, schema.Check())
+	assert.Empty(t, schema.Validate(map[string]interface{}{"nickname": "Ravi", "acres": float64(3)}))
+	assert.Equal(t, []string{"attribute names cannot be empty"}, schema.Validate(map[string]interface{}{"": "x"}))
+	assert.Len(t, schema.Validate(map[string]interface{}{strings.Repeat("k", MaxUserAttributeKeyLength+1): "x"}), 1)
+}
+
+func TestUserAttributeSchema_Check(t *testing.T) {
+	schema := UserAttributeSchema{
+		"":       {Type: UserAttributeTypeString},
+		"code":   {Type: UserAttributeTypeString, MaxLength: -1},
+		"grade":  {Type: UserAttributeTypeString, Allowed: []string{"A", "B"}},
+		"rating": {Type: UserAttributeTypeInteger, Allowed: []string{"1"}},
+		"kind":   {},
+	}
+
+	assert.Equal(t, []string{
+		"attribute names cannot be empty",
+		"code: max_length must not be negative",
+		"kind: type must be string, number, integer or boolean",
+		"rating: allowed values apply to string attributes only",
+	}, schema.Check())
+}
+
+func TestUserAttributeSchema_ScanAndValue(t *testing.T) {
+	schema := UserAttributeSchema{"verified": {Type: UserAttributeTypeBoolean, Required: true}}
+
+	value, err := schema.Value()
+	assert.NoError(t, err)
+
+	var scanned UserAttributeSchema
+	assert.NoError(t, scanned.Scan(value))
+	assert.Equal(t, schema, scanned)
+
+	assert.NoError(t, scanned.Scan(nil))
+	assert.Empty(t, scanned)
+	assert.Error(t, scanned.Scan(42))
+}
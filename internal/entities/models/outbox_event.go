@@ -0,0 +1,90 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/utils/utc"
+	"github.com/Kisanlink/kisanlink-db/pkg/base"
+	"github.com/Kisanlink/kisanlink-db/pkg/core/hash"
+	"gorm.io/gorm"
+)
+
+// Outbox event statuses
+const (
+	// OutboxStatusPending events are waiting to be published, or to be retried
+	OutboxStatusPending = "pending"
+	// OutboxStatusSent events have been published
+	OutboxStatusSent = "sent"
+	// OutboxStatusFailed events ran out of publish attempts and need an operator
+	OutboxStatusFailed = "failed"
+)
+
+// OutboxEvent is an event written in the same transaction as the change it describes, so it is
+// recorded exactly when the change commits. The outbox relay publishes it afterwards, retrying
+// until the publisher accepts it, which gives at-least-once delivery.
+type OutboxEvent struct {
+	*base.BaseModel
+	Topic         string     `json:"topic" gorm:"size:100;not null"`
+	AggregateType string     `json:"aggregate_type" gorm:"size:50;not null"`
+	AggregateID   string     `json:"aggregate_id" gorm:"type:varchar(255);not null;index"`
+	Payload       string     `json:"payload" gorm:"type:jsonb;not null"`
+	Status        string     `json:"status" gorm:"size:20;not null;default:'pending';index:idx_outbox_events_status_next_attempt,priority:1"`
+	Attempts      int        `json:"attempts" gorm:"not null;default:0"`
+	NextAttemptAt time.Time  `json:"next_attempt_at" gorm:"not null;index:idx_outbox_events_status_next_attempt,priority:2"`
+	LockedUntil   *time.Time `json:"locked_until,omitempty"`
+	LastError     *string    `json:"last_error,omitempty" gorm:"type:text"`
+	SentAt        *time.Time `json:"sent_at,omitempty"`
+}
+
+// NewOutboxEvent creates a pending outbox event with a JSON-encoded payload
+func NewOutboxEvent(topic, aggregateType, aggregateID string, payload interface{}) (*OutboxEvent, error) {
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode payload of %s event: %w", topic, err)
+	}
+	return &OutboxEvent{
+		BaseModel:     base.NewBaseModel("OUTB", hash.Large),
+		Topic:         topic,
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		Payload:       string(encoded),
+		Status:        OutboxStatusPending,
+		NextAttemptAt: utc.Now(),
+	}, nil
+}
+
+func (e *OutboxEvent) BeforeCreate() error     { return e.BaseModel.BeforeCreate() }
+func (e *OutboxEvent) BeforeUpdate() error     { return e.BaseModel.BeforeUpdate() }
+func (e *OutboxEvent) BeforeDelete() error     { return e.BaseModel.BeforeDelete() }
+func (e *OutboxEvent) BeforeSoftDelete() error { return e.BaseModel.BeforeSoftDelete() }
+
+// GORM Hooks - These are for GORM compatibility
+// BeforeCreateGORM is called by GORM before creating a new record
+func (e *OutboxEvent) BeforeCreateGORM(tx *gorm.DB) error {
+	return e.BeforeCreate()
+}
+
+// BeforeUpdateGORM is called by GORM before updating an existing record
+func (e *OutboxEvent) BeforeUpdateGORM(tx *gorm.DB) error {
+	return e.BeforeUpdate()
+}
+
+// AfterFind initializes the embedded BaseModel pointer when GORM loads a record
+func (e *OutboxEvent) AfterFind(tx *gorm.DB) error {
+	if e.BaseModel == nil {
+		e.BaseModel = &base.BaseModel{}
+	}
+	return nil
+}
+
+func (e *OutboxEvent) GetTableIdentifier() string   { return "OUTB" }
+func (e *OutboxEvent) GetTableSize() hash.TableSize { return hash.Large }
+
+// TableName returns the GORM table name for this model
+func (e *OutboxEvent) TableName() string { return "outbox_events" }
+
+// Explicit method implementations to satisfy linter
+func (e *OutboxEvent) GetID() string   { return e.BaseModel.GetID() }
+func (e *OutboxEvent) SetID(id string) { e.BaseModel.SetID(id) }
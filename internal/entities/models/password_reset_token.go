@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/Kisanlink/aaa-service/v2/internal/utils/utc"
 	db "github.com/Kisanlink/kisanlink-db/pkg/base"
 	"gorm.io/gorm"
 )
@@ -54,6 +55,6 @@ func (p *PasswordResetToken) IsValid() bool {
 // MarkAsUsed marks the token as used
 func (p *PasswordResetToken) MarkAsUsed() {
 	p.Used = true
-	now := time.Now()
+	now := utc.Now()
 	p.UsedAt = &now
 }
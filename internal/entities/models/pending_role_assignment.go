@@ -0,0 +1,112 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/Kisanlink/kisanlink-db/pkg/base"
+	"github.com/Kisanlink/kisanlink-db/pkg/core/hash"
+	"gorm.io/gorm"
+)
+
+// Role metadata keys that gate a role on the KYC state of its holders
+const (
+	// RoleMetadataKYCGated marks a role that only users with a verified Aadhaar may hold
+	RoleMetadataKYCGated = "kyc_gated"
+	// RoleMetadataKYCGateAction selects what happens when a KYC-gated role is assigned to an
+	// unverified user; see the KYCGateAction constants
+	RoleMetadataKYCGateAction = "kyc_gate_action"
+)
+
+// KYCGateAction defines how assigning a KYC-gated role to an unverified user is handled
+type KYCGateAction string
+
+const (
+	// KYCGateActionPending records the assignment as pending and activates it once the user's
+	// Aadhaar is verified; this is the default
+	KYCGateActionPending KYCGateAction = "pending"
+	// KYCGateActionReject refuses the assignment
+	KYCGateActionReject KYCGateAction = "reject"
+)
+
+// ErrRoleAssignmentPendingKYC is returned when a role assignment was recorded as pending until the
+// user completes Aadhaar verification
+var ErrRoleAssignmentPendingKYC = fmt.Errorf("role assignment is pending KYC verification")
+
+// KYCGate reports whether the role is KYC-gated through its metadata, e.g.
+// {"kyc_gated": true, "kyc_gate_action": "reject"}, and how assignments to unverified users are
+// handled. Unreadable metadata leaves the role ungated.
+func (r *Role) KYCGate() (bool, KYCGateAction) {
+	if r.Metadata == nil || *r.Metadata == "" {
+		return false, ""
+	}
+
+	var metadata map[string]interface{}
+	if err := json.Unmarshal([]byte(*r.Metadata), &metadata); err != nil {
+		return false, ""
+	}
+	if gated, _ := metadata[RoleMetadataKYCGated].(bool); !gated {
+		return false, ""
+	}
+
+	if action, _ := metadata[RoleMetadataKYCGateAction].(string); KYCGateAction(action) == KYCGateActionReject {
+		return true, KYCGateActionReject
+	}
+	return true, KYCGateActionPending
+}
+
+// PendingRoleAssignment is an assignment of a KYC-gated role to a user whose Aadhaar is not yet
+// verified. It becomes an active user role when the verification completes.
+type PendingRoleAssignment struct {
+	*base.BaseModel
+	UserID      string `json:"user_id" gorm:"type:varchar(255);not null;index:idx_pending_role_assignment,priority:1"`
+	RoleID      string `json:"role_id" gorm:"type:varchar(255);not null;index:idx_pending_role_assignment,priority:2"`
+	RequestedBy string `json:"requested_by" gorm:"type:varchar(255)"`
+
+	// Relationships
+	Role *Role `json:"role,omitempty" gorm:"foreignKey:RoleID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
+
+// NewPendingRoleAssignment creates a new PendingRoleAssignment
+func NewPendingRoleAssignment(userID, roleID, requestedBy string) *PendingRoleAssignment {
+	return &PendingRoleAssignment{
+		BaseModel:   base.NewBaseModel("PRA", hash.Small),
+		UserID:      userID,
+		RoleID:      roleID,
+		RequestedBy: requestedBy,
+	}
+}
+
+func (p *PendingRoleAssignment) BeforeCreate() error     { return p.BaseModel.BeforeCreate() }
+func (p *PendingRoleAssignment) BeforeUpdate() error     { return p.BaseModel.BeforeUpdate() }
+func (p *PendingRoleAssignment) BeforeDelete() error     { return p.BaseModel.BeforeDelete() }
+func (p *PendingRoleAssignment) BeforeSoftDelete() error { return p.BaseModel.BeforeSoftDelete() }
+
+// GORM Hooks - These are for GORM compatibility
+// BeforeCreateGORM is called by GORM before creating a new record
+func (p *PendingRoleAssignment) BeforeCreateGORM(tx *gorm.DB) error {
+	return p.BeforeCreate()
+}
+
+// BeforeUpdateGORM is called by GORM before updating an existing record
+func (p *PendingRoleAssignment) BeforeUpdateGORM(tx *gorm.DB) error {
+	return p.BeforeUpdate()
+}
+
+// AfterFind initializes the embedded BaseModel pointer when GORM loads a record
+func (p *PendingRoleAssignment) AfterFind(tx *gorm.DB) error {
+	if p.BaseModel == nil {
+		p.BaseModel = &base.BaseModel{}
+	}
+	return nil
+}
+
+func (p *PendingRoleAssignment) GetTableIdentifier() string   { return "PRA" }
+func (p *PendingRoleAssignment) GetTableSize() hash.TableSize { return hash.Small }
+
+// TableName returns the GORM table name for this model
+func (p *PendingRoleAssignment) TableName() string { return "pending_role_assignments" }
+
+// Explicit method implementations to satisfy linter
+func (p *PendingRoleAssignment) GetID() string   { return p.BaseModel.GetID() }
+func (p *PendingRoleAssignment) SetID(id string) { p.BaseModel.SetID(id) }
@@ -1,6 +1,8 @@
 package models
 
 import (
+	"time"
+
 	"github.com/Kisanlink/kisanlink-db/pkg/base"
 	"github.com/Kisanlink/kisanlink-db/pkg/core/hash"
 	"gorm.io/gorm"
@@ -40,6 +42,11 @@ type Service struct {
 	IsActive       bool    `json:"is_active" gorm:"default:true"`
 	Metadata       *string `json:"metadata" gorm:"type:jsonb"`
 
+	// Credential rotation: the key replaced by the last rotation stays valid until PreviousAPIKeyExpiresAt
+	PreviousAPIKey          *string    `json:"-" gorm:"size:255;index"` // Hashed
+	PreviousAPIKeyExpiresAt *time.Time `json:"previous_api_key_expires_at,omitempty"`
+	APIKeyRotatedAt         *time.Time `json:"api_key_rotated_at,omitempty"`
+
 	// Relationships
 	Organization *Organization `json:"organization" gorm:"foreignKey:OrganizationID;references:ID"`
 	Principal    *Principal    `json:"principal" gorm:"foreignKey:ServiceID;references:ID"`
@@ -144,6 +151,19 @@ func (p *Principal) GetSubjectID() string {
 	return p.GetID()
 }
 
+// AcceptsAPIKey reports whether apiKeyHash is the service's current key, or the key it replaced
+// while that key's grace period has not ended
+func (s *Service) AcceptsAPIKey(apiKeyHash string, now time.Time) bool {
+	if apiKeyHash == "" {
+		return false
+	}
+	if s.APIKey == apiKeyHash {
+		return true
+	}
+	return s.PreviousAPIKey != nil && *s.PreviousAPIKey == apiKeyHash &&
+		s.PreviousAPIKeyExpiresAt != nil && now.Before(*s.PreviousAPIKeyExpiresAt)
+}
+
 // GetResourceType returns the resource type for services in PostgreSQL RBAC
 func (s *Service) GetResourceType() string {
 	return "aaa/service"
@@ -16,6 +16,9 @@ type Resource struct {
 	ParentID    *string `json:"parent_id" gorm:"type:varchar(255);default:null"` // For resource hierarchy
 	OwnerID     *string `json:"owner_id" gorm:"type:varchar(255);default:null"`  // Resource owner
 
+	// Attributes are free-form properties supplied by the service that registered the resource
+	Attributes AttributeValue `json:"attributes,omitempty" gorm:"type:jsonb"`
+
 	// Relationships
 	Parent   *Resource  `json:"parent" gorm:"foreignKey:ParentID;references:ID"`
 	Children []Resource `json:"children" gorm:"foreignKey:ParentID;references:ID"`
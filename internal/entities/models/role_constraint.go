@@ -0,0 +1,136 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/Kisanlink/kisanlink-db/pkg/base"
+	"github.com/Kisanlink/kisanlink-db/pkg/core/hash"
+	"gorm.io/gorm"
+)
+
+// RoleConstraintType defines how a role assignment constraint limits the roles it covers
+type RoleConstraintType string
+
+const (
+	// RoleConstraintMutuallyExclusive allows a user to hold at most one of the covered roles
+	RoleConstraintMutuallyExclusive RoleConstraintType = "mutually_exclusive"
+	// RoleConstraintMaxRolesPerUser allows a user to hold at most MaxCount of the covered roles
+	RoleConstraintMaxRolesPerUser RoleConstraintType = "max_roles_per_user"
+	// RoleConstraintMaxHolders allows at most MaxCount users to hold each covered role
+	RoleConstraintMaxHolders RoleConstraintType = "max_holders"
+)
+
+// IsValid reports whether the constraint type is known
+func (t RoleConstraintType) IsValid() bool {
+	switch t {
+	case RoleConstraintMutuallyExclusive, RoleConstraintMaxRolesPerUser, RoleConstraintMaxHolders:
+		return true
+	}
+	return false
+}
+
+// RoleConstraint restricts how the roles of an organization may be assigned to users, e.g. "a
+// user can't hold both approver and requester" or "at most one user holds the admin role".
+// Constraints are data, so governance rules change without a deployment.
+type RoleConstraint struct {
+	*base.BaseModel
+	OrganizationID string             `json:"organization_id" gorm:"type:varchar(255);not null;index"`
+	Name           string             `json:"name" gorm:"size:100;not null"`
+	Description    string             `json:"description" gorm:"type:text"`
+	Type           RoleConstraintType `json:"type" gorm:"size:30;not null"`
+	RoleIDs        string             `json:"-" gorm:"type:jsonb;not null"` // JSON-encoded list of covered role IDs
+	MaxCount       int                `json:"max_count" gorm:"default:0"`   // Unused by mutually_exclusive
+
+	// Relationships
+	Organization *Organization `json:"organization,omitempty" gorm:"foreignKey:OrganizationID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
+
+// NewRoleConstraint creates a new RoleConstraint covering the given roles
+func NewRoleConstraint(organizationID, name string, constraintType RoleConstraintType, roleIDs []string, maxCount int) (*RoleConstraint, error) {
+	constraint := &RoleConstraint{
+		BaseModel:      base.NewBaseModel("RCON", hash.Small),
+		OrganizationID: organizationID,
+		Name:           name,
+		Type:           constraintType,
+		MaxCount:       maxCount,
+	}
+	if err := constraint.SetRoleIDs(roleIDs); err != nil {
+		return nil, err
+	}
+	return constraint, nil
+}
+
+// SetRoleIDs JSON-encodes the covered role IDs into the constraint
+func (c *RoleConstraint) SetRoleIDs(roleIDs []string) error {
+	if roleIDs == nil {
+		roleIDs = []string{}
+	}
+	encoded, err := json.Marshal(roleIDs)
+	if err != nil {
+		return fmt.Errorf("failed to encode roles of constraint %s: %w", c.Name, err)
+	}
+	c.RoleIDs = string(encoded)
+	return nil
+}
+
+// GetRoleIDs returns the covered role IDs decoded from JSON
+func (c *RoleConstraint) GetRoleIDs() []string {
+	var roleIDs []string
+	if err := json.Unmarshal([]byte(c.RoleIDs), &roleIDs); err != nil {
+		return nil
+	}
+	return roleIDs
+}
+
+// Covers reports whether the constraint applies to the role
+func (c *RoleConstraint) Covers(roleID string) bool {
+	for _, id := range c.GetRoleIDs() {
+		if id == roleID {
+			return true
+		}
+	}
+	return false
+}
+
+// Limit returns the number of roles a user may hold, or of users a role may have, under the constraint
+func (c *RoleConstraint) Limit() int {
+	if c.Type == RoleConstraintMutuallyExclusive {
+		return 1
+	}
+	return c.MaxCount
+}
+
+func (c *RoleConstraint) BeforeCreate() error     { return c.BaseModel.BeforeCreate() }
+func (c *RoleConstraint) BeforeUpdate() error     { return c.BaseModel.BeforeUpdate() }
+func (c *RoleConstraint) BeforeDelete() error     { return c.BaseModel.BeforeDelete() }
+func (c *RoleConstraint) BeforeSoftDelete() error { return c.BaseModel.BeforeSoftDelete() }
+
+// GORM Hooks - These are for GORM compatibility
+// BeforeCreateGORM is called by GORM before creating a new record
+func (c *RoleConstraint) BeforeCreateGORM(tx *gorm.DB) error {
+	return c.BeforeCreate()
+}
+
+// BeforeUpdateGORM is called by GORM before updating an existing record
+func (c *RoleConstraint) BeforeUpdateGORM(tx *gorm.DB) error {
+	return c.BeforeUpdate()
+}
+
+// AfterFind initializes the embedded BaseModel pointer when GORM loads a record
+func (c *RoleConstraint) AfterFind(tx *gorm.DB) error {
+	if c.BaseModel == nil {
+		c.BaseModel = &base.BaseModel{}
+	}
+	return nil
+}
+
+func (c *RoleConstraint) GetTableIdentifier() string   { return "RCON" }
+func (c *RoleConstraint) GetTableSize() hash.TableSize { return hash.Small }
+
+// TableName returns the GORM table name for this model
+func (c *RoleConstraint) TableName() string { return "role_constraints" }
+
+// Explicit method implementations to satisfy linter
+func (c *RoleConstraint) GetID() string   { return c.BaseModel.GetID() }
+func (c *RoleConstraint) SetID(id string) { c.BaseModel.SetID(id) }
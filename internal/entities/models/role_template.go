@@ -0,0 +1,222 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+
+	"github.com/Kisanlink/kisanlink-db/pkg/base"
+	"github.com/Kisanlink/kisanlink-db/pkg/core/hash"
+	"gorm.io/gorm"
+)
+
+// Built-in role template names
+const (
+	RoleTemplateFarmManager = "farm_manager"
+	RoleTemplateFieldAgent  = "field_agent"
+	RoleTemplateOrgViewer   = "org_viewer"
+)
+
+// RoleTemplateRole describes one role a template creates and the permissions, by name, it receives
+type RoleTemplateRole struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Permissions []string `json:"permissions"`
+}
+
+// RoleTemplateRoles is the list of roles of a template. It is stored as JSONB.
+type RoleTemplateRoles []RoleTemplateRole
+
+// Value implements the driver.Valuer interface for GORM JSONB support
+func (r RoleTemplateRoles) Value() (driver.Value, error) {
+	return json.Marshal(r)
+}
+
+// Scan implements the sql.Scanner interface for GORM JSONB support
+func (r *RoleTemplateRoles) Scan(value interface{}) error {
+	var bytes []byte
+	switch v := value.(type) {
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return nil
+	}
+	return json.Unmarshal(bytes, r)
+}
+
+// RoleTemplateDefinition is a named bundle of roles that can be instantiated in an organization
+type RoleTemplateDefinition struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Roles       RoleTemplateRoles `json:"roles"`
+	IsBuiltin   bool              `json:"is_builtin"`
+}
+
+// BuiltinRoleTemplates lists the templates shipped with the service. Their permission names
+// match the farmers-module catalog seed.
+var BuiltinRoleTemplates = map[string]RoleTemplateDefinition{
+	RoleTemplateFarmManager: {
+		Name:        RoleTemplateFarmManager,
+		Description: "Manages farmers, farms and crop cycles of the organization",
+		IsBuiltin:   true,
+		Roles: RoleTemplateRoles{
+			{
+				Name:        RoleTemplateFarmManager,
+				Description: "Full management of the organization's farmers, farms, cycles and activities",
+				Permissions: []string{
+					"farmer:create", "farmer:read", "farmer:update", "farmer:list",
+					"farm:create", "farm:read", "farm:update", "farm:delete", "farm:list",
+					"cycle:create", "cycle:read", "cycle:update", "cycle:end", "cycle:list",
+					"activity:create", "activity:update", "activity:delete",
+				},
+			},
+		},
+	},
+	RoleTemplateFieldAgent: {
+		Name:        RoleTemplateFieldAgent,
+		Description: "Registers farmers and records field activities",
+		IsBuiltin:   true,
+		Roles: RoleTemplateRoles{
+			{
+				Name:        RoleTemplateFieldAgent,
+				Description: "Registers farmers and farms and records activities in the field",
+				Permissions: []string{
+					"farmer:create", "farmer:read", "farmer:list",
+					"farm:create", "farm:read", "farm:list",
+					"cycle:read", "cycle:list",
+					"activity:create", "activity:update",
+				},
+			},
+		},
+	},
+	RoleTemplateOrgViewer: {
+		Name:        RoleTemplateOrgViewer,
+		Description: "Read-only access to the organization's agricultural data",
+		IsBuiltin:   true,
+		Roles: RoleTemplateRoles{
+			{
+				Name:        RoleTemplateOrgViewer,
+				Description: "Reads farmers, farms and crop cycles of the organization",
+				Permissions: []string{
+					"farmer:read", "farmer:list",
+					"farm:read", "farm:list",
+					"cycle:read", "cycle:list",
+				},
+			},
+		},
+	},
+}
+
+// OrganizationRoleServiceID returns the service namespace of roles created for an organization
+// from templates. Role names are unique per service, so each organization gets its own namespace
+// and two organizations can both have a "farm_manager" role.
+func OrganizationRoleServiceID(organizationID string) string {
+	return "org:" + organizationID
+}
+
+// RoleTemplate stores an admin-defined role template. Built-in templates are not stored.
+type RoleTemplate struct {
+	*base.BaseModel
+	Name        string            `json:"name" gorm:"size:100;not null;uniqueIndex"`
+	Description string            `json:"description" gorm:"type:text"`
+	Roles       RoleTemplateRoles `json:"roles" gorm:"type:jsonb;not null"`
+}
+
+// NewRoleTemplate creates a new RoleTemplate instance
+func NewRoleTemplate(name, description string, roles RoleTemplateRoles) *RoleTemplate {
+	return &RoleTemplate{
+		BaseModel:   base.NewBaseModel("RTPL", hash.Small),
+		Name:        name,
+		Description: description,
+		Roles:       roles,
+	}
+}
+
+// Definition returns the template as a definition that can be applied to an organization
+func (t *RoleTemplate) Definition() RoleTemplateDefinition {
+	return RoleTemplateDefinition{
+		Name:        t.Name,
+		Description: t.Description,
+		Roles:       t.Roles,
+	}
+}
+
+// Validate checks that the definition has roles, that role names are unique and that every
+// role has at least one permission
+func (d RoleTemplateDefinition) Validate() error {
+	if len(d.Roles) == 0 {
+		return fmt.Errorf("role template %s must define at least one role", d.Name)
+	}
+	seen := make(map[string]bool, len(d.Roles))
+	for _, role := range d.Roles {
+		if role.Name == "" {
+			return fmt.Errorf("role template %s has a role without a name", d.Name)
+		}
+		if seen[role.Name] {
+			return fmt.Errorf("role template %s defines role %s more than once", d.Name, role.Name)
+		}
+		seen[role.Name] = true
+		if len(role.Permissions) == 0 {
+			return fmt.Errorf("role %s of template %s must have at least one permission", role.Name, d.Name)
+		}
+	}
+	return nil
+}
+
+// PermissionNames returns the distinct permission names used by the template's roles
+func (d RoleTemplateDefinition) PermissionNames() []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, role := range d.Roles {
+		for _, name := range role.Permissions {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// RoleTemplateApplication is the outcome of applying a template to an organization
+type RoleTemplateApplication struct {
+	CreatedRoles []*Role
+	// SkippedRoles are template roles the organization already had
+	SkippedRoles []string
+}
+
+func (t *RoleTemplate) BeforeCreate() error     { return t.BaseModel.BeforeCreate() }
+func (t *RoleTemplate) BeforeUpdate() error     { return t.BaseModel.BeforeUpdate() }
+func (t *RoleTemplate) BeforeDelete() error     { return t.BaseModel.BeforeDelete() }
+func (t *RoleTemplate) BeforeSoftDelete() error { return t.BaseModel.BeforeSoftDelete() }
+
+// GORM Hooks - These are for GORM compatibility
+// BeforeCreateGORM is called by GORM before creating a new record
+func (t *RoleTemplate) BeforeCreateGORM(tx *gorm.DB) error {
+	return t.BeforeCreate()
+}
+
+// BeforeUpdateGORM is called by GORM before updating an existing record
+func (t *RoleTemplate) BeforeUpdateGORM(tx *gorm.DB) error {
+	return t.BeforeUpdate()
+}
+
+// AfterFind initializes the embedded BaseModel pointer when GORM loads a record
+func (t *RoleTemplate) AfterFind(tx *gorm.DB) error {
+	if t.BaseModel == nil {
+		t.BaseModel = &base.BaseModel{}
+	}
+	return nil
+}
+
+func (t *RoleTemplate) GetTableIdentifier() string   { return "RTPL" }
+func (t *RoleTemplate) GetTableSize() hash.TableSize { return hash.Small }
+
+// TableName returns the GORM table name for this model
+func (t *RoleTemplate) TableName() string { return "role_templates" }
+
+// Explicit method implementations to satisfy linter
+func (t *RoleTemplate) GetID() string   { return t.BaseModel.GetID() }
+func (t *RoleTemplate) SetID(id string) { t.BaseModel.SetID(id) }
@@ -3,6 +3,7 @@ package models
 import (
 	"time"
 
+	"github.com/Kisanlink/aaa-service/v2/internal/utils/utc"
 	"github.com/Kisanlink/kisanlink-db/pkg/base"
 	"github.com/Kisanlink/kisanlink-db/pkg/core/hash"
 	"gorm.io/gorm"
@@ -48,7 +49,7 @@ func NewSMSDeliveryLog(messageType, maskedPhone string) *SMSDeliveryLog {
 		PhoneNumberMasked: maskedPhone,
 		MessageType:       messageType,
 		Status:            SMSStatusPending,
-		SentAt:            time.Now(),
+		SentAt:            utc.Now(),
 		RequestDetails:    make(map[string]interface{}),
 	}
 }
@@ -78,7 +79,7 @@ func (s *SMSDeliveryLog) GetTableSize() hash.TableSize {
 // BeforeCreate is called before creating a new SMS delivery log
 func (s *SMSDeliveryLog) BeforeCreate() error {
 	if s.SentAt.IsZero() {
-		s.SentAt = time.Now()
+		s.SentAt = utc.Now()
 	}
 	if s.RequestDetails == nil {
 		s.RequestDetails = make(map[string]interface{})
@@ -123,7 +124,7 @@ func (s *SMSDeliveryLog) MarkAsSent(snsMessageID string) {
 // MarkAsDelivered marks the SMS as delivered
 func (s *SMSDeliveryLog) MarkAsDelivered() {
 	s.Status = SMSStatusDelivered
-	now := time.Now()
+	now := utc.Now()
 	s.DeliveredAt = &now
 }
 
@@ -2,17 +2,30 @@ package models
 
 import (
 	"fmt"
+	"time"
 
+	"github.com/Kisanlink/aaa-service/v2/pkg/phonenumber"
 	"github.com/Kisanlink/kisanlink-db/pkg/base"
 	"github.com/Kisanlink/kisanlink-db/pkg/core/hash"
 	"gorm.io/gorm"
 )
 
+// UsersPhoneActiveUniqueIndex is the partial unique index that keeps (phone_number, country_code)
+// unique among non-deleted users. It is created by migrations.AddUsersPhonePartialUniqueIndex.
+const UsersPhoneActiveUniqueIndex = "idx_users_phone_country_active"
+
+// UsersUsernameLowerUniqueIndex is the partial unique index that keeps LOWER(username) unique among
+// non-deleted users. It is created by migrations.AddUsersUsernameCaseInsensitiveIndex.
+const UsersUsernameLowerUniqueIndex = "idx_users_username_lower_active"
+
 // User represents a user in the AAA service
 type User struct {
 	*base.BaseModel
-	PhoneNumber string  `json:"phone_number" gorm:"unique;not null;size:10;index:idx_users_phone_number;index:idx_users_phone_country_auth,priority:1;index:idx_users_phone_country_validated,priority:1;index:idx_users_search_phone" validate:"required,phone"`
+	PhoneNumber string  `json:"phone_number" gorm:"not null;size:15;index:idx_users_phone_number;index:idx_users_phone_country_auth,priority:1;index:idx_users_phone_country_validated,priority:1;index:idx_users_search_phone" validate:"required,phone"`
 	CountryCode string  `json:"country_code" gorm:"not null;size:10;default:'+91';index:idx_users_country_code;index:idx_users_phone_country_auth,priority:2;index:idx_users_phone_country_validated,priority:2" validate:"required"`
+	// PhoneE164 is the canonical E.164 form of CountryCode+PhoneNumber used for lookups.
+	// It is nil only for rows written before normalization that have not been backfilled yet.
+	PhoneE164   *string `json:"phone_e164,omitempty" gorm:"column:phone_e164;size:16;index:idx_users_phone_e164"`
 	Username    *string `json:"username" gorm:"unique;size:100;index:idx_users_username_search" validate:"omitempty,username"`
 	Password    string  `json:"password" gorm:"not null;size:255" validate:"required,min=8,max=128"`
 	MPin        *string `json:"mpin" gorm:"column:m_pin;size:255"`
@@ -27,6 +40,10 @@ type User struct {
 	Tokens             int  `json:"tokens" gorm:"default:1000"`
 	MustChangePassword bool `json:"must_change_password" gorm:"default:false;column:must_change_password"`
 
+	// Last successful sign-in, updated asynchronously on login
+	LastLoginAt *time.Time `json:"last_login_at,omitempty" gorm:"column:last_login_at"`
+	LastLoginIP *string    `json:"last_login_ip,omitempty" gorm:"column:last_login_ip;size:45"`
+
 	// Relationships
 	Profile  UserProfile `json:"profile" gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
 	Contacts []Contact   `json:"contacts" gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
@@ -56,6 +73,14 @@ func NewUserWithUsername(phoneNumber string, countryCode string, username string
 	return user
 }
 
+// SetPhone stores a normalized phone number in its split and E.164 forms
+func (u *User) SetPhone(number phonenumber.Number) {
+	e164 := number.E164()
+	u.PhoneNumber = number.NationalNumber
+	u.CountryCode = number.CountryCode
+	u.PhoneE164 = &e164
+}
+
 // BeforeCreate is called before creating a new user
 // Sets default status to "pending" if not already set
 // Validates required fields
@@ -75,6 +100,13 @@ func (u *User) BeforeCreate() error {
 		return fmt.Errorf("password is required")
 	}
 
+	// Canonicalize phone numbers from callers that did not normalize them; invalid input is left for the service layer to reject
+	if u.PhoneE164 == nil {
+		if number, err := phonenumber.Parse(u.PhoneNumber, u.CountryCode); err == nil {
+			u.SetPhone(number)
+		}
+	}
+
 	// Set default status ONLY if not already set
 	if u.Status == nil {
 		status := "pending"
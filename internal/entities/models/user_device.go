@@ -0,0 +1,76 @@
+package models
+
+import (
+	"time"
+
+	"github.com/Kisanlink/kisanlink-db/pkg/base"
+	"github.com/Kisanlink/kisanlink-db/pkg/core/hash"
+	"gorm.io/gorm"
+)
+
+// UserDevice binds an MPIN to one device of a user. A device MPIN is verified, locked out after
+// repeated failures, and revoked independently of the user's other devices; devices without one
+// fall back to the user's global MPIN.
+type UserDevice struct {
+	*base.BaseModel
+	UserID         string     `json:"user_id" gorm:"type:varchar(255);not null;uniqueIndex:idx_user_devices_user_device,priority:1"`
+	DeviceID       string     `json:"device_id" gorm:"type:varchar(255);not null;uniqueIndex:idx_user_devices_user_device,priority:2"`
+	DeviceName     string     `json:"device_name" gorm:"type:varchar(100)"`
+	MPinHash       string     `json:"-" gorm:"column:m_pin_hash;size:255;not null"`
+	FailedAttempts int        `json:"failed_attempts" gorm:"default:0"`
+	LockedUntil    *time.Time `json:"locked_until,omitempty"`
+	LastUsedAt     *time.Time `json:"last_used_at,omitempty"`
+
+	// Relationships
+	User *User `json:"user,omitempty" gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
+
+// NewUserDevice creates a new UserDevice instance
+func NewUserDevice(userID, deviceID, deviceName, mPinHash string) *UserDevice {
+	return &UserDevice{
+		BaseModel:  base.NewBaseModel("USR_DEV", hash.Medium),
+		UserID:     userID,
+		DeviceID:   deviceID,
+		DeviceName: deviceName,
+		MPinHash:   mPinHash,
+	}
+}
+
+// IsLocked reports whether the device MPIN is locked out at the given time
+func (d *UserDevice) IsLocked(now time.Time) bool {
+	return d.LockedUntil != nil && now.Before(*d.LockedUntil)
+}
+
+func (d *UserDevice) BeforeCreate() error     { return d.BaseModel.BeforeCreate() }
+func (d *UserDevice) BeforeUpdate() error     { return d.BaseModel.BeforeUpdate() }
+func (d *UserDevice) BeforeDelete() error     { return d.BaseModel.BeforeDelete() }
+func (d *UserDevice) BeforeSoftDelete() error { return d.BaseModel.BeforeSoftDelete() }
+
+// GORM Hooks - These are for GORM compatibility
+// BeforeCreateGORM is called by GORM before creating a new record
+func (d *UserDevice) BeforeCreateGORM(tx *gorm.DB) error {
+	return d.BeforeCreate()
+}
+
+// BeforeUpdateGORM is called by GORM before updating an existing record
+func (d *UserDevice) BeforeUpdateGORM(tx *gorm.DB) error {
+	return d.BeforeUpdate()
+}
+
+// AfterFind initializes the embedded BaseModel pointer when GORM loads a record
+func (d *UserDevice) AfterFind(tx *gorm.DB) error {
+	if d.BaseModel == nil {
+		d.BaseModel = &base.BaseModel{}
+	}
+	return nil
+}
+
+func (d *UserDevice) GetTableIdentifier() string   { return "USR_DEV" }
+func (d *UserDevice) GetTableSize() hash.TableSize { return hash.Medium }
+
+// TableName returns the GORM table name for this model
+func (d *UserDevice) TableName() string { return "user_devices" }
+
+// Explicit method implementations to satisfy linter
+func (d *UserDevice) GetID() string   { return d.BaseModel.GetID() }
+func (d *UserDevice) SetID(id string) { d.BaseModel.SetID(id) }
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"github.com/Kisanlink/kisanlink-db/pkg/base"
+	"github.com/Kisanlink/kisanlink-db/pkg/core/hash"
+	"gorm.io/gorm"
+)
+
+// UserIdentity links a user to an account at an external OIDC identity provider.
+// The (provider, subject) pair is the stable identifier issued by the IdP; it is stored
+// so that a later change of email at the IdP cannot be used to take over another account.
+type UserIdentity struct {
+	*base.BaseModel
+	UserID   string `json:"user_id" gorm:"type:varchar(255);not null;index:idx_user_identities_user_id"`
+	Provider string `json:"provider" gorm:"type:varchar(100);not null;uniqueIndex:idx_user_identities_provider_subject,priority:1"`
+	Subject  string `json:"subject" gorm:"type:varchar(255);not null;uniqueIndex:idx_user_identities_provider_subject,priority:2"`
+	Email    string `json:"email" gorm:"type:varchar(255)"`
+
+	// Relationships
+	User *User `json:"user,omitempty" gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
+
+// NewUserIdentity creates a new UserIdentity instance
+func NewUserIdentity(userID, provider, subject, email string) *UserIdentity {
+	return &UserIdentity{
+		BaseModel: base.NewBaseModel("USR_IDN", hash.Medium),
+		UserID:    userID,
+		Provider:  provider,
+		Subject:   subject,
+		Email:     email,
+	}
+}
+
+func (i *UserIdentity) BeforeCreate() error     { return i.BaseModel.BeforeCreate() }
+func (i *UserIdentity) BeforeUpdate() error     { return i.BaseModel.BeforeUpdate() }
+func (i *UserIdentity) BeforeDelete() error     { return i.BaseModel.BeforeDelete() }
+func (i *UserIdentity) BeforeSoftDelete() error { return i.BaseModel.BeforeSoftDelete() }
+
+// GORM Hooks - These are for GORM compatibility
+// BeforeCreateGORM is called by GORM before creating a new record
+func (i *UserIdentity) BeforeCreateGORM(tx *gorm.DB) error {
+	return i.BeforeCreate()
+}
+
+// BeforeUpdateGORM is called by GORM before updating an existing record
+func (i *UserIdentity) BeforeUpdateGORM(tx *gorm.DB) error {
+	return i.BeforeUpdate()
+}
+
+// AfterFind initializes the embedded BaseModel pointer when GORM loads a record
+func (i *UserIdentity) AfterFind(tx *gorm.DB) error {
+	if i.BaseModel == nil {
+		i.BaseModel = &base.BaseModel{}
+	}
+	return nil
+}
+
+func (i *UserIdentity) GetTableIdentifier() string   { return "USR_IDN" }
+func (i *UserIdentity) GetTableSize() hash.TableSize { return hash.Medium }
+
+// TableName returns the GORM table name for this model
+func (i *UserIdentity) TableName() string { return "user_identities" }
+
+// Explicit method implementations to satisfy linter
+func (i *UserIdentity) GetID() string   { return i.BaseModel.GetID() }
+func (i *UserIdentity) SetID(id string) { i.BaseModel.SetID(id) }
@@ -7,8 +7,8 @@ import (
 // CreateActionRequest represents a request to create a new action
 // @Description Define a new action that can be performed on resources
 type CreateActionRequest struct {
-	Name        string  `json:"name" binding:"required,min=1,max=100" validate:"required,min=1,max=100" example:"read"`
-	Description string  `json:"description" binding:"max=1000" validate:"max=1000" example:"Read or view data without making changes"`
+	Name        string  `json:"name" binding:"required,min=1,max=100" validate:"required,text,min=1,max=100" example:"read"`
+	Description string  `json:"description" binding:"max=1000" validate:"text,max=1000" example:"Read or view data without making changes"`
 	Category    string  `json:"category" binding:"required,min=1,max=50" validate:"required,min=1,max=50" example:"data_access"`
 	IsStatic    bool    `json:"is_static" validate:"omitempty" example:"true"`
 	ServiceID   *string `json:"service_id" binding:"omitempty,max=255" validate:"omitempty,max=255" example:"aaa-service"`
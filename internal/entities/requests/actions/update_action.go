@@ -6,8 +6,8 @@ import (
 
 // UpdateActionRequest represents a request to update an existing action
 type UpdateActionRequest struct {
-	Name        *string `json:"name,omitempty" binding:"omitempty,min=1,max=100" validate:"omitempty,min=1,max=100"`
-	Description *string `json:"description,omitempty" binding:"omitempty,max=1000" validate:"omitempty,max=1000"`
+	Name        *string `json:"name,omitempty" binding:"omitempty,min=1,max=100" validate:"omitempty,text,min=1,max=100"`
+	Description *string `json:"description,omitempty" binding:"omitempty,max=1000" validate:"omitempty,text,max=1000"`
 	Category    *string `json:"category,omitempty" binding:"omitempty,min=1,max=50" validate:"omitempty,min=1,max=50"`
 	IsStatic    *bool   `json:"is_static,omitempty" validate:"omitempty"`
 	ServiceID   *string `json:"service_id,omitempty" binding:"omitempty,max=255" validate:"omitempty,max=255"`
@@ -0,0 +1,6 @@
+package addresses
+
+// BulkCreateAddressesRequest carries the addresses of a bulk import
+type BulkCreateAddressesRequest struct {
+	Addresses []CreateAddressRequest `json:"addresses" binding:"required,min=1"`
+}
@@ -3,6 +3,8 @@ package requests
 import (
 	"fmt"
 	"regexp"
+
+	"github.com/Kisanlink/aaa-service/v2/pkg/phonenumber"
 )
 
 // LoginRequest represents a user login request supporting password, MPIN, and refresh token authentication
@@ -20,6 +22,10 @@ type LoginRequest struct {
 	IncludeProfile  *bool   `json:"include_profile,omitempty" example:"true"`
 	IncludeRoles    *bool   `json:"include_roles,omitempty" example:"true"`
 	IncludeContacts *bool   `json:"include_contacts,omitempty" example:"false"`
+	RememberMe      bool    `json:"remember_me,omitempty" example:"true"`
+	DeviceName      string  `json:"device_name,omitempty" validate:"omitempty,text,max=100" example:"Pixel 8"`
+	// DeviceID selects the device-bound MPIN to check in the refresh_token + mpin flow
+	DeviceID string `json:"device_id,omitempty" validate:"omitempty,max=255" example:"a1b2c3d4-pixel8"`
 }
 
 // Validate validates the LoginRequest
@@ -145,6 +151,8 @@ type RegisterRequest struct {
 	Username      *string `json:"username,omitempty" validate:"omitempty,username" example:"ramesh_kumar"`
 	AadhaarNumber *string `json:"aadhaar_number,omitempty" example:"1234 5678 9012"`
 	Name          *string `json:"name,omitempty" example:"Ramesh Kumar"`
+	// OrganizationID registers the user in an organization, which gives them its default roles
+	OrganizationID string `json:"organization_id,omitempty" example:"ORGN00000001"`
 }
 
 // Validate validates the RegisterRequest
@@ -153,20 +161,15 @@ func (r *RegisterRequest) Validate() error {
 	if r.PhoneNumber == "" {
 		return fmt.Errorf("phone number is required")
 	}
-	// Basic phone number validation (10 digits for Indian numbers)
-	phoneRegex := regexp.MustCompile(`^\d{10}$`)
-	if !phoneRegex.MatchString(r.PhoneNumber) {
-		return fmt.Errorf("phone number must be 10 digits")
-	}
 
 	// Validate country code
 	if r.CountryCode == "" {
 		return fmt.Errorf("country code is required")
 	}
-	// Basic country code validation
-	countryCodeRegex := regexp.MustCompile(`^\+\d{1,4}$`)
-	if !countryCodeRegex.MatchString(r.CountryCode) {
-		return fmt.Errorf("country code must start with + and contain 1-4 digits")
+
+	// Formatting such as spaces, dashes or a leading trunk zero is accepted; the number is normalized on write
+	if _, err := phonenumber.Parse(r.PhoneNumber, r.CountryCode); err != nil {
+		return err
 	}
 
 	// Validate optional username if provided
@@ -201,6 +204,8 @@ func (r *RegisterRequest) GetType() string {
 type RefreshTokenRequest struct {
 	RefreshToken string `json:"refresh_token" validate:"required" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
 	MPin         string `json:"mpin" validate:"required,len=4|len=6" example:"1234"`
+	// DeviceID selects the device-bound MPIN to check; without it the global MPIN is used
+	DeviceID string `json:"device_id,omitempty" validate:"omitempty,max=255" example:"a1b2c3d4-pixel8"`
 }
 
 // Validate validates the RefreshTokenRequest
@@ -288,6 +293,35 @@ func (r *ResetPasswordRequest) GetType() string {
 	return "reset_password"
 }
 
+// ResetMPinRequest completes an MPIN reset with the reset token and the OTP sent to the user
+type ResetMPinRequest struct {
+	TokenID string `json:"token_id" validate:"required" example:"CRST3f9a0c6e2b7d41e58c0a9d7f6b2e4c1a"`
+	OTP     string `json:"otp" validate:"required,len=6" example:"123456"`
+	NewMPin string `json:"new_mpin" validate:"required,len=4|len=6" example:"2580"`
+}
+
+// Validate validates the ResetMPinRequest
+func (r *ResetMPinRequest) Validate() error {
+	if r.TokenID == "" {
+		return fmt.Errorf("token ID is required")
+	}
+	if !regexp.MustCompile(`^\d{6}$`).MatchString(r.OTP) {
+		return fmt.Errorf("OTP must be 6 digits")
+	}
+	if len(r.NewMPin) != 4 && len(r.NewMPin) != 6 {
+		return fmt.Errorf("new mPin must be 4 or 6 digits")
+	}
+	if !regexp.MustCompile(`^\d+$`).MatchString(r.NewMPin) {
+		return fmt.Errorf("new mPin must contain only digits")
+	}
+	return nil
+}
+
+// GetType returns the request type
+func (r *ResetMPinRequest) GetType() string {
+	return "reset_mpin"
+}
+
 // ResetPasswordWithTokenRequest represents a legacy reset password request (deprecated)
 // Deprecated: Use ResetPasswordRequest with OTP instead
 type ResetPasswordWithTokenRequest struct {
@@ -343,6 +377,85 @@ func (r *SetMPinRequest) GetType() string {
 	return "set_mpin"
 }
 
+// SetDeviceMPinRequest represents a request to set the MPIN of one device
+type SetDeviceMPinRequest struct {
+	MPin       string `json:"mpin" validate:"required,len=4|len=6" example:"1234"`
+	Password   string `json:"password" validate:"required"`
+	DeviceName string `json:"device_name,omitempty" validate:"omitempty,text,max=100" example:"Pixel 8"`
+}
+
+// Validate validates the SetDeviceMPinRequest
+func (r *SetDeviceMPinRequest) Validate() error {
+	if r.MPin == "" {
+		return fmt.Errorf("mPin is required")
+	}
+	if len(r.MPin) != 4 && len(r.MPin) != 6 {
+		return fmt.Errorf("mPin must be 4 or 6 digits")
+	}
+	mPinRegex := regexp.MustCompile(`^\d+$`)
+	if !mPinRegex.MatchString(r.MPin) {
+		return fmt.Errorf("mPin must contain only digits")
+	}
+	if r.Password == "" {
+		return fmt.Errorf("password is required for verification")
+	}
+	return nil
+}
+
+// GetType returns the request type
+func (r *SetDeviceMPinRequest) GetType() string {
+	return "set_device_mpin"
+}
+
+// VerifyLoginMPinRequest completes a login that returned status "mpin_required"
+type VerifyLoginMPinRequest struct {
+	ChallengeToken string `json:"challenge_token" validate:"required" example:"3f9a0c6e2b7d41e58c0a9d7f6b2e4c1a3f9a0c6e2b7d41e58c0a9d7f6b2e4c1a"`
+	MPin           string `json:"mpin" validate:"required,len=4|len=6" example:"1234"`
+	RememberMe     bool   `json:"remember_me,omitempty" example:"true"`
+	DeviceName     string `json:"device_name,omitempty" validate:"omitempty,text,max=100" example:"Pixel 8"`
+}
+
+// Validate validates the VerifyLoginMPinRequest
+func (r *VerifyLoginMPinRequest) Validate() error {
+	if r.ChallengeToken == "" {
+		return fmt.Errorf("challenge_token is required")
+	}
+	if r.MPin == "" {
+		return fmt.Errorf("mPin is required")
+	}
+	if len(r.MPin) != 4 && len(r.MPin) != 6 {
+		return fmt.Errorf("mPin must be 4 or 6 digits")
+	}
+	mPinRegex := regexp.MustCompile(`^\d+$`)
+	if !mPinRegex.MatchString(r.MPin) {
+		return fmt.Errorf("mPin must contain only digits")
+	}
+	return nil
+}
+
+// GetType returns the request type
+func (r *VerifyLoginMPinRequest) GetType() string {
+	return "verify_login_mpin"
+}
+
+// ExchangeDeviceSessionRequest trades the token of a remembered device for new access tokens
+type ExchangeDeviceSessionRequest struct {
+	SessionToken string `json:"session_token" validate:"required" example:"9c1e4b7a2d6f40e3a8b5c7d9e1f3a5b79c1e4b7a2d6f40e3a8b5c7d9e1f3a5b7"`
+}
+
+// Validate validates the ExchangeDeviceSessionRequest
+func (r *ExchangeDeviceSessionRequest) Validate() error {
+	if r.SessionToken == "" {
+		return fmt.Errorf("session_token is required")
+	}
+	return nil
+}
+
+// GetType returns the request type
+func (r *ExchangeDeviceSessionRequest) GetType() string {
+	return "exchange_device_session"
+}
+
 // UpdateMPinRequest represents a request to update existing mPin
 type UpdateMPinRequest struct {
 	CurrentMPin string `json:"current_mpin" validate:"required,len=4|len=6"`
@@ -411,3 +524,45 @@ func (r *ChangePasswordRequest) Validate() error {
 func (r *ChangePasswordRequest) GetType() string {
 	return "change_password"
 }
+
+// DeleteAccountRequest asks for the signed-in user's account to be deleted
+type DeleteAccountRequest struct {
+	Password string `json:"password" validate:"required"`
+	Reason   string `json:"reason,omitempty" validate:"omitempty,text,max=500" example:"I no longer use the app"`
+}
+
+// Validate validates the DeleteAccountRequest
+func (r *DeleteAccountRequest) Validate() error {
+	if r.Password == "" {
+		return fmt.Errorf("password is required for verification")
+	}
+	return nil
+}
+
+// GetType returns the request type
+func (r *DeleteAccountRequest) GetType() string {
+	return "delete_account"
+}
+
+// CancelAccountDeletionRequest restores an account within the grace period of its deletion
+type CancelAccountDeletionRequest struct {
+	PhoneNumber string `json:"phone_number" validate:"required" example:"9876543210"`
+	CountryCode string `json:"country_code" validate:"required" example:"+91"`
+	Password    string `json:"password" validate:"required"`
+}
+
+// Validate validates the CancelAccountDeletionRequest
+func (r *CancelAccountDeletionRequest) Validate() error {
+	if r.PhoneNumber == "" || r.CountryCode == "" {
+		return fmt.Errorf("phone number and country code are required")
+	}
+	if r.Password == "" {
+		return fmt.Errorf("password is required")
+	}
+	return nil
+}
+
+// GetType returns the request type
+func (r *CancelAccountDeletionRequest) GetType() string {
+	return "cancel_account_deletion"
+}
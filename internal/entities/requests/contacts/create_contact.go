@@ -9,7 +9,7 @@ type CreateContactRequest struct {
 	UserID      string  `json:"user_id" binding:"required" validate:"required"`
 	Type        string  `json:"type" binding:"required,min=1,max=50" validate:"required,min=1,max=50"`
 	Value       string  `json:"value" binding:"required,min=1,max=255" validate:"required,min=1,max=255"`
-	Description *string `json:"description" binding:"omitempty,max=1000" validate:"omitempty,max=1000"`
+	Description *string `json:"description" binding:"omitempty,max=1000" validate:"omitempty,text,max=1000"`
 	IsPrimary   bool    `json:"is_primary" validate:"omitempty"`
 	IsActive    bool    `json:"is_active" validate:"omitempty"`
 	CountryCode *string `json:"country_code" binding:"omitempty,max=10" validate:"omitempty,max=10"`
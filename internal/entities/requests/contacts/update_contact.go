@@ -8,7 +8,7 @@ import (
 type UpdateContactRequest struct {
 	Type        *string `json:"type,omitempty" binding:"omitempty,min=1,max=50" validate:"omitempty,min=1,max=50"`
 	Value       *string `json:"value,omitempty" binding:"omitempty,min=1,max=255" validate:"omitempty,min=1,max=255"`
-	Description *string `json:"description,omitempty" binding:"omitempty,max=1000" validate:"omitempty,max=1000"`
+	Description *string `json:"description,omitempty" binding:"omitempty,max=1000" validate:"omitempty,text,max=1000"`
 	IsPrimary   *bool   `json:"is_primary,omitempty" validate:"omitempty"`
 	IsActive    *bool   `json:"is_active,omitempty" validate:"omitempty"`
 	CountryCode *string `json:"country_code,omitempty" binding:"omitempty,max=10" validate:"omitempty,max=10"`
@@ -0,0 +1,12 @@
+package contacts
+
+// ResendContactVerificationRequest asks for the verification OTP of a contact to be sent again
+type ResendContactVerificationRequest struct {
+	ChallengeID string `json:"challenge_id" binding:"required" validate:"required"`
+}
+
+// ConfirmContactVerificationRequest confirms a contact with the OTP sent to it
+type ConfirmContactVerificationRequest struct {
+	ChallengeID string `json:"challenge_id" binding:"required" validate:"required"`
+	OTP         string `json:"otp" binding:"required,min=4,max=8" validate:"required,min=4,max=8"`
+}
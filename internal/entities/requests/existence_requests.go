@@ -0,0 +1,48 @@
+package requests
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MaxExistenceCheckIDs caps the number of IDs checked by a single existence request
+const MaxExistenceCheckIDs = 500
+
+// ExistenceCheckRequest represents a request to check which of several IDs exist
+// @Description IDs to look up; soft-deleted entities are reported as missing unless include_deleted is set
+type ExistenceCheckRequest struct {
+	IDs            []string `json:"ids" validate:"required" example:"USER00000001,USER00000002"`
+	IncludeDeleted bool     `json:"include_deleted" example:"false"`
+}
+
+// Validate trims and de-duplicates the IDs, keeping their order, and enforces MaxExistenceCheckIDs
+func (r *ExistenceCheckRequest) Validate() error {
+	seen := make(map[string]struct{}, len(r.IDs))
+	ids := make([]string, 0, len(r.IDs))
+	for _, id := range r.IDs {
+		id = strings.TrimSpace(id)
+		if id == "" {
+			continue
+		}
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		ids = append(ids, id)
+	}
+
+	if len(ids) == 0 {
+		return fmt.Errorf("at least one ID is required")
+	}
+	if len(ids) > MaxExistenceCheckIDs {
+		return fmt.Errorf("at most %d IDs can be checked at once", MaxExistenceCheckIDs)
+	}
+
+	r.IDs = ids
+	return nil
+}
+
+// GetType returns the request type
+func (r *ExistenceCheckRequest) GetType() string {
+	return "existence_check"
+}
@@ -0,0 +1,22 @@
+package requests
+
+import "fmt"
+
+// StartImpersonationRequest represents a request by a support engineer to act as another user
+// @Description Start an audited impersonation session for the target user
+type StartImpersonationRequest struct {
+	TargetUserID string `json:"target_user_id" validate:"required" example:"USER00000001"`
+}
+
+// Validate validates the StartImpersonationRequest
+func (r *StartImpersonationRequest) Validate() error {
+	if r.TargetUserID == "" {
+		return fmt.Errorf("target_user_id is required")
+	}
+	return nil
+}
+
+// GetType returns the request type
+func (r *StartImpersonationRequest) GetType() string {
+	return "start_impersonation"
+}
@@ -3,6 +3,7 @@ package kyc
 import (
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"regexp"
 )
 
@@ -76,6 +77,8 @@ func (c Consent) String() string {
 type GenerateOTPRequest struct {
 	AadhaarNumber string  `json:"aadhaar_number" validate:"required,len=12,numeric"`
 	Consent       Consent `json:"consent" validate:"required,eq=Y"`
+	// CallbackURL, when set, receives a signed POST once the verification is VERIFIED or FAILED
+	CallbackURL string `json:"callback_url,omitempty" validate:"omitempty,max=2048"`
 }
 
 // Validate validates the GenerateOTPRequest
@@ -100,6 +103,23 @@ func (r *GenerateOTPRequest) Validate() error {
 		return fmt.Errorf("consent must be true, 'Y', or a consent object with purpose/timestamp/version")
 	}
 
+	// Validate callback URL
+	if r.CallbackURL != "" {
+		if len(r.CallbackURL) > 2048 {
+			return fmt.Errorf("callback_url must not exceed 2048 characters")
+		}
+		u, err := url.Parse(r.CallbackURL)
+		if err != nil || u.Host == "" {
+			return fmt.Errorf("callback_url must be an absolute URL")
+		}
+		if u.Scheme != "https" {
+			return fmt.Errorf("callback_url must use https")
+		}
+		if u.User != nil {
+			return fmt.Errorf("callback_url must not contain credentials")
+		}
+	}
+
 	return nil
 }
 
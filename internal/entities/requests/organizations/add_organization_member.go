@@ -0,0 +1,8 @@
+package organizations
+
+// AddOrganizationMemberRequest represents the request for adding a user directly to an organization
+// @Description Request body for adding a member to an organization
+type AddOrganizationMemberRequest struct {
+	UserID string `json:"user_id" validate:"required" example:"USER00000001"`                            // User to add
+	Role   string `json:"role,omitempty" validate:"omitempty,oneof=owner admin member" example:"member"` // Role at the organization, defaults to member
+}
@@ -0,0 +1,7 @@
+package organizations
+
+// MergeOrganizationsRequest represents the request for merging an organization into another
+// @Description Request body for merging the organization in the path into the target organization, which absorbs its children, groups, members, roles and settings
+type MergeOrganizationsRequest struct {
+	TargetID string `json:"target_id" validate:"required,org_id" example:"ORGN00000002"` // Organization that absorbs the merged one
+}
@@ -0,0 +1,7 @@
+package organizations
+
+// MoveOrganizationGroupRequest represents the request for moving a group under a different parent group
+// @Description Request body for moving a group, with its subtree, under another group in the same organization
+type MoveOrganizationGroupRequest struct {
+	ParentID string `json:"parent_id" validate:"required,group_id" example:"GRP9876543210987654321"` // New parent group ID
+}
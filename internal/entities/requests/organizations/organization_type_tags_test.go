@@ -0,0 +1,28 @@
+package organizations
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// The oneof lists of the type fields feed the Swagger enums and must match the known types
+func TestOrganizationTypeTagsMatchKnownTypes(t *testing.T) {
+	for _, request := range []interface{}{CreateOrganizationRequest{}, UpdateOrganizationRequest{}} {
+		field, ok := reflect.TypeOf(request).FieldByName("Type")
+		if !assert.True(t, ok) {
+			continue
+		}
+
+		var oneOf []string
+		for _, rule := range strings.Split(field.Tag.Get("validate"), ",") {
+			if values, found := strings.CutPrefix(rule, "oneof="); found {
+				oneOf = strings.Fields(values)
+			}
+		}
+		assert.Equal(t, models.OrganizationTypes(), oneOf, "%T type values", request)
+	}
+}
@@ -0,0 +1,11 @@
+package organizations
+
+// CreateRoleConstraintRequest represents the request for adding a role assignment constraint to an organization
+// @Description Request body for a role assignment constraint. mutually_exclusive lets a user hold at most one of the roles; max_roles_per_user lets a user hold at most max_count of them; max_holders lets at most max_count users hold each role.
+type CreateRoleConstraintRequest struct {
+	Name        string   `json:"name" validate:"required,min=2,max=100"`
+	Description string   `json:"description" validate:"max=1000"`
+	Type        string   `json:"type" validate:"required,oneof=mutually_exclusive max_roles_per_user max_holders"`
+	RoleIDs     []string `json:"role_ids" validate:"required,min=1,dive,required,max=255"` // Roles of the organization the constraint covers
+	MaxCount    int      `json:"max_count" validate:"min=0"`                               // Required by max_roles_per_user and max_holders
+}
@@ -0,0 +1,22 @@
+package organizations
+
+// ApplyRoleTemplateRequest represents the request for applying a role template to an organization
+// @Description Request body for instantiating a role template as organization-scoped roles
+type ApplyRoleTemplateRequest struct {
+	Template string `json:"template" validate:"required,max=100"` // Built-in or admin-defined template name
+}
+
+// RoleTemplateRoleRequest describes one role of a role template
+type RoleTemplateRoleRequest struct {
+	Name        string   `json:"name" validate:"required,min=2,max=100"`
+	Description string   `json:"description" validate:"max=1000"`
+	Permissions []string `json:"permissions" validate:"required,min=1,dive,required,max=100"` // Permission names, e.g. "farm:read"
+}
+
+// CreateRoleTemplateRequest represents the request for defining a new role template
+// @Description Request body for defining a role template that organizations can apply
+type CreateRoleTemplateRequest struct {
+	Name        string                    `json:"name" validate:"required,min=2,max=100"`
+	Description string                    `json:"description" validate:"max=1000"`
+	Roles       []RoleTemplateRoleRequest `json:"roles" validate:"required,min=1,dive"`
+}
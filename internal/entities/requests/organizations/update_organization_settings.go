@@ -0,0 +1,7 @@
+package organizations
+
+// UpdateOrganizationSettingsRequest represents the request for changing organization settings
+// @Description Request body for updating organization settings. Keys not present are left unchanged; a null value resets the key to its default.
+type UpdateOrganizationSettingsRequest struct {
+	Settings map[string]interface{} `json:"settings" validate:"required"` // Setting key to new value
+}
@@ -0,0 +1,15 @@
+package organizations
+
+import "github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+
+// SetUserAttributeSchemaRequest represents the request for replacing the custom user attribute schema of an organization
+// @Description Request body for the custom user attribute schema. Each attribute has a type (string, number, integer or boolean) and may be required or bounded; an empty schema removes validation.
+type SetUserAttributeSchemaRequest struct {
+	Attributes models.UserAttributeSchema `json:"attributes"` // Attribute name to definition
+}
+
+// SetUserAttributesRequest represents the request for replacing the custom attributes an organization keeps on a user
+// @Description Request body for a user's custom attributes in an organization. The attributes replace the stored ones and are validated against the organization's schema when it has one; null values are dropped.
+type SetUserAttributesRequest struct {
+	Attributes map[string]interface{} `json:"attributes"` // Attribute name to value
+}
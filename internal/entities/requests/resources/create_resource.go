@@ -8,9 +8,9 @@ import (
 // CreateResourceRequest represents the request to create a new resource
 // @Description Define a new resource that can be protected with permissions
 type CreateResourceRequest struct {
-	Name        string  `json:"name" validate:"required,min=3,max=100" example:"Crop Management"`
+	Name        string  `json:"name" validate:"required,text,min=3,max=100" example:"Crop Management"`
 	Type        string  `json:"type" validate:"required,min=3,max=100" example:"farm/crops"`
-	Description string  `json:"description" validate:"max=500" example:"Resource for managing farm crop inventory and cultivation records"`
+	Description string  `json:"description" validate:"text,max=500" example:"Resource for managing farm crop inventory and cultivation records"`
 	ParentID    *string `json:"parent_id,omitempty" validate:"omitempty,uuid" example:"RES1760615540005820899"`
 	OwnerID     *string `json:"owner_id,omitempty" validate:"omitempty,uuid" example:"USER00000001"`
 }
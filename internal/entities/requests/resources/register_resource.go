@@ -0,0 +1,44 @@
+package resources
+
+import (
+	"strings"
+
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+)
+
+// RegisterResourceRequest represents a service registering one of its resources and the actions
+// that can be granted on it
+// @Description Register (or re-register) a resource owned by a calling service
+type RegisterResourceRequest struct {
+	Type       string                 `json:"type" validate:"required,min=3,max=100" example:"farm/crop"`
+	ResourceID string                 `json:"resource_id" validate:"required,max=100" example:"farm-service:crop"`
+	ParentID   string                 `json:"parent_id,omitempty" validate:"omitempty,max=100" example:"farm-service:farm"`
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+	Actions    []string               `json:"actions" validate:"required,min=1" example:"read,update"`
+}
+
+// Validate validates the RegisterResourceRequest
+func (r *RegisterResourceRequest) Validate() error {
+	if strings.TrimSpace(r.Type) == "" {
+		return errors.NewValidationError("type is required")
+	}
+	if len(r.Type) > 100 {
+		return errors.NewValidationError("type must be at most 100 characters")
+	}
+
+	if strings.TrimSpace(r.ResourceID) == "" {
+		return errors.NewValidationError("resource_id is required")
+	}
+	if len(r.ResourceID) > 100 {
+		return errors.NewValidationError("resource_id must be at most 100 characters")
+	}
+	if len(r.ParentID) > 100 {
+		return errors.NewValidationError("parent_id must be at most 100 characters")
+	}
+
+	if len(r.Actions) == 0 {
+		return errors.NewValidationError("at least one action is required")
+	}
+
+	return nil
+}
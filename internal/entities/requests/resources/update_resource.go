@@ -7,9 +7,9 @@ import (
 // UpdateResourceRequest represents the request to update an existing resource
 // @Description Request payload for updating a resource
 type UpdateResourceRequest struct {
-	Name        *string `json:"name,omitempty" validate:"omitempty,min=3,max=100" example:"Updated User Management"`
+	Name        *string `json:"name,omitempty" validate:"omitempty,text,min=3,max=100" example:"Updated User Management"`
 	Type        *string `json:"type,omitempty" validate:"omitempty,min=3,max=100" example:"aaa/user"`
-	Description *string `json:"description,omitempty" validate:"omitempty,max=500" example:"Updated description"`
+	Description *string `json:"description,omitempty" validate:"omitempty,text,max=500" example:"Updated description"`
 	IsActive    *bool   `json:"is_active,omitempty" example:"true"`
 	ParentID    *string `json:"parent_id,omitempty" validate:"omitempty,uuid" example:"RES_abc123"`
 	OwnerID     *string `json:"owner_id,omitempty" validate:"omitempty,uuid" example:"USR_xyz789"`
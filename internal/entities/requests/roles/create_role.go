@@ -8,8 +8,8 @@ import (
 // @Description Create a new role with name, description, and optional permissions
 type CreateRoleRequest struct {
 	*requests.BaseRequest
-	Name        string   `json:"name" validate:"required,min=2,max=100" example:"farm_manager"`
-	Description *string  `json:"description" validate:"omitempty,max=500" example:"Manager role for farm operations and crop management"`
+	Name        string   `json:"name" validate:"required,text,min=2,max=100" example:"farm_manager"`
+	Description *string  `json:"description" validate:"omitempty,text,max=500" example:"Manager role for farm operations and crop management"`
 	Permissions []string `json:"permissions" validate:"omitempty" example:"PERM00000001,PERM00000002"`
 }
 
@@ -9,8 +9,8 @@ import (
 type UpdateRoleRequest struct {
 	*requests.BaseRequest
 	RoleID      string   `json:"role_id" validate:"required" example:"ROLE00000001"`
-	Name        *string  `json:"name" validate:"omitempty,min=2,max=100" example:"senior_farm_manager"`
-	Description *string  `json:"description" validate:"omitempty,max=500" example:"Senior manager role with full farm operation access"`
+	Name        *string  `json:"name" validate:"omitempty,text,min=2,max=100" example:"senior_farm_manager"`
+	Description *string  `json:"description" validate:"omitempty,text,max=500" example:"Senior manager role with full farm operation access"`
 	Permissions []string `json:"permissions" validate:"omitempty" example:"PERM00000001,PERM00000002,PERM00000003"`
 }
 
@@ -0,0 +1,49 @@
+package users
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MaxBatchUserIDs caps the number of user IDs resolved by a single batch lookup
+const MaxBatchUserIDs = 100
+
+// BatchGetUsersRequest represents a request to resolve several users by ID in one call
+type BatchGetUsersRequest struct {
+	IDs              []string `json:"ids" validate:"required"`
+	IncludeRoles     bool     `json:"include_roles"`
+	IncludeProfile   bool     `json:"include_profile"`
+	IncludeAddresses bool     `json:"include_addresses"`
+}
+
+// Validate trims and de-duplicates the requested IDs, keeping their order, and enforces MaxBatchUserIDs
+func (r *BatchGetUsersRequest) Validate() error {
+	seen := make(map[string]struct{}, len(r.IDs))
+	ids := make([]string, 0, len(r.IDs))
+	for _, id := range r.IDs {
+		id = strings.TrimSpace(id)
+		if id == "" {
+			continue
+		}
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		ids = append(ids, id)
+	}
+
+	if len(ids) == 0 {
+		return fmt.Errorf("at least one user ID is required")
+	}
+	if len(ids) > MaxBatchUserIDs {
+		return fmt.Errorf("at most %d user IDs can be requested at once", MaxBatchUserIDs)
+	}
+
+	r.IDs = ids
+	return nil
+}
+
+// GetType returns the type of request
+func (r *BatchGetUsersRequest) GetType() string {
+	return "batch_get_users"
+}
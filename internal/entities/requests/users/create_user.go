@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"regexp"
 	"strconv"
+
+	"github.com/Kisanlink/aaa-service/v2/pkg/phonenumber"
 )
 
 // CreateUserRequest represents the request to create a new user
@@ -18,28 +20,40 @@ type CreateUserRequest struct {
 	DateOfBirth        *string `json:"date_of_birth,omitempty"`
 	YearOfBirth        *string `json:"year_of_birth,omitempty"`
 	MustChangePassword bool    `json:"must_change_password,omitempty"`
+	// ReclaimMode decides what happens when the phone number belongs only to a soft-deleted account
+	ReclaimMode string `json:"reclaim_mode,omitempty"`
+	// OrganizationID is the organization the user registers in; its default roles are added to the global ones
+	OrganizationID string `json:"organization_id,omitempty"`
 }
 
+// Reclaim modes for a phone number held only by soft-deleted accounts
+const (
+	// ReclaimModeFresh creates a new account and leaves the deleted one untouched (default)
+	ReclaimModeFresh = "fresh"
+	// ReclaimModeRestore restores the most recently deleted account and updates it from the request.
+	// The account's MPIN and prior roles are cleared; it gets the default roles of a new user.
+	ReclaimModeRestore = "restore"
+)
+
 // Validate validates the CreateUserRequest
 func (r *CreateUserRequest) Validate() error {
 	// Validate phone number
 	if r.PhoneNumber == "" {
 		return fmt.Errorf("phone number is required")
 	}
-	// Basic phone number validation (10 digits for Indian numbers)
-	phoneRegex := regexp.MustCompile(`^\d{10}$`)
-	if !phoneRegex.MatchString(r.PhoneNumber) {
-		return fmt.Errorf("phone number must be 10 digits")
-	}
 
 	// Validate country code
 	if r.CountryCode == "" {
 		return fmt.Errorf("country code is required")
 	}
-	// Basic country code validation
-	countryCodeRegex := regexp.MustCompile(`^\+\d{1,4}$`)
-	if !countryCodeRegex.MatchString(r.CountryCode) {
-		return fmt.Errorf("country code must start with + and contain 1-4 digits")
+
+	// Formatting such as spaces, dashes or a leading trunk zero is accepted; the number is normalized on write
+	if _, err := phonenumber.Parse(r.PhoneNumber, r.CountryCode); err != nil {
+		return err
+	}
+
+	if r.ReclaimMode != "" && r.ReclaimMode != ReclaimModeFresh && r.ReclaimMode != ReclaimModeRestore {
+		return fmt.Errorf("reclaim mode must be %q or %q", ReclaimModeFresh, ReclaimModeRestore)
 	}
 
 	// Validate optional username if provided
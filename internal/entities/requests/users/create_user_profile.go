@@ -8,12 +8,12 @@ import (
 type CreateUserProfileRequest struct {
 	*requests.BaseRequest
 	UserID        string  `json:"user_id" validate:"required"`
-	Name          *string `json:"name" validate:"omitempty,min=2,max=255"`
+	Name          *string `json:"name" validate:"omitempty,text,min=2,max=255"`
 	CareOf        *string `json:"care_of" validate:"omitempty,max=255"`
 	DateOfBirth   *string `json:"date_of_birth" validate:"omitempty,len=10"`
 	Photo         *string `json:"photo"`
 	YearOfBirth   *string `json:"year_of_birth" validate:"omitempty,len=4"`
-	Message       *string `json:"message"`
+	Message       *string `json:"message" validate:"omitempty,text,max=1000"`
 	AadhaarNumber *string `json:"aadhaar_number" validate:"omitempty,len=12"`
 	EmailHash     *string `json:"email_hash" validate:"omitempty,email"`
 	ShareCode     *string `json:"share_code" validate:"omitempty,max=50"`
@@ -9,14 +9,14 @@ import (
 type UpdateUserRequest struct {
 	UserID       string  `json:"user_id" validate:"required"`
 	Status       *string `json:"status,omitempty"`
-	Name         *string `json:"name,omitempty"`
+	Name         *string `json:"name,omitempty" validate:"omitempty,text,max=255"`
 	CareOf       *string `json:"care_of,omitempty"`
 	DateOfBirth  *string `json:"date_of_birth,omitempty"`
 	Photo        *string `json:"photo,omitempty"`
 	EmailHash    *string `json:"email_hash,omitempty"`
 	ShareCode    *string `json:"share_code,omitempty"`
 	YearOfBirth  *string `json:"year_of_birth,omitempty"`
-	Message      *string `json:"message,omitempty"`
+	Message      *string `json:"message,omitempty" validate:"omitempty,text,max=1000"`
 	MobileNumber *uint64 `json:"mobile_number,omitempty"`
 	CountryCode  *string `json:"country_code,omitempty"`
 	Tokens       *int    `json:"tokens,omitempty"`
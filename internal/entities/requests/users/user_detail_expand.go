@@ -0,0 +1,79 @@
+package users
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Values of the expand parameter of GET /users/:id
+const (
+	UserExpandProfile   = "profile"
+	UserExpandContacts  = "contacts"
+	UserExpandAddresses = "addresses"
+	UserExpandRoles     = "roles"
+	UserExpandGroups    = "groups"
+	UserExpandAll       = "all"
+)
+
+// UserDetailExpand selects the related data assembled into a user detail
+type UserDetailExpand struct {
+	Profile   bool
+	Contacts  bool
+	Addresses bool
+	Roles     bool
+	Groups    bool // Group and organization memberships
+
+	// AttributesOrganizationID adds the custom attributes this organization keeps on the user
+	AttributesOrganizationID string
+}
+
+// AllUserDetails expands every section of a user detail
+func AllUserDetails() *UserDetailExpand {
+	return &UserDetailExpand{Profile: true, Contacts: true, Addresses: true, Roles: true, Groups: true}
+}
+
+// ParseUserDetailExpand parses a comma-separated expand parameter such as "profile,roles".
+// Values are case-insensitive; "all" expands every section.
+func ParseUserDetailExpand(expand string) (*UserDetailExpand, error) {
+	result := &UserDetailExpand{}
+	for _, value := range strings.Split(expand, ",") {
+		switch strings.ToLower(strings.TrimSpace(value)) {
+		case "":
+		case UserExpandProfile:
+			result.Profile = true
+		case UserExpandContacts:
+			result.Contacts = true
+		case UserExpandAddresses:
+			result.Addresses = true
+		case UserExpandRoles:
+			result.Roles = true
+		case UserExpandGroups:
+			result.Groups = true
+		case UserExpandAll:
+			return AllUserDetails(), nil
+		default:
+			return nil, fmt.Errorf("unknown expand value %q, expected one of profile, contacts, addresses, roles, groups, all", strings.TrimSpace(value))
+		}
+	}
+	return result, nil
+}
+
+// Names returns the expanded sections in a stable order
+func (e *UserDetailExpand) Names() []string {
+	names := make([]string, 0, 5)
+	for _, section := range []struct {
+		name     string
+		expanded bool
+	}{
+		{UserExpandProfile, e.Profile},
+		{UserExpandContacts, e.Contacts},
+		{UserExpandAddresses, e.Addresses},
+		{UserExpandRoles, e.Roles},
+		{UserExpandGroups, e.Groups},
+	} {
+		if section.expanded {
+			names = append(names, section.name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,27 @@
+package users
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseUserDetailExpand(t *testing.T) {
+	expand, err := ParseUserDetailExpand(" Profile, roles ,,roles")
+	require.NoError(t, err)
+	assert.Equal(t, &UserDetailExpand{Profile: true, Roles: true}, expand)
+	assert.Equal(t, []string{"profile", "roles"}, expand.Names())
+
+	expand, err = ParseUserDetailExpand("contacts,all")
+	require.NoError(t, err)
+	assert.Equal(t, AllUserDetails(), expand)
+	assert.Equal(t, []string{"profile", "contacts", "addresses", "roles", "groups"}, expand.Names())
+
+	expand, err = ParseUserDetailExpand("")
+	require.NoError(t, err)
+	assert.Empty(t, expand.Names(), "an empty expand returns the user fields only")
+
+	_, err = ParseUserDetailExpand("profile,permissions")
+	assert.ErrorContains(t, err, `"permissions"`)
+}
@@ -10,20 +10,22 @@ import (
 // AddressResponse represents an address response
 type AddressResponse struct {
 	responses.Response
-	ID          string  `json:"id"`
-	House       *string `json:"house,omitempty"`
-	Street      *string `json:"street,omitempty"`
-	Landmark    *string `json:"landmark,omitempty"`
-	PostOffice  *string `json:"post_office,omitempty"`
-	Subdistrict *string `json:"subdistrict,omitempty"`
-	District    *string `json:"district,omitempty"`
-	VTC         *string `json:"vtc,omitempty"` // Village/Town/City
-	State       *string `json:"state,omitempty"`
-	Country     *string `json:"country,omitempty"`
-	Pincode     *string `json:"pincode,omitempty"`
-	FullAddress *string `json:"full_address,omitempty"`
-	CreatedAt   string  `json:"created_at"`
-	UpdatedAt   string  `json:"updated_at"`
+	ID          string   `json:"id"`
+	House       *string  `json:"house,omitempty"`
+	Street      *string  `json:"street,omitempty"`
+	Landmark    *string  `json:"landmark,omitempty"`
+	PostOffice  *string  `json:"post_office,omitempty"`
+	Subdistrict *string  `json:"subdistrict,omitempty"`
+	District    *string  `json:"district,omitempty"`
+	VTC         *string  `json:"vtc,omitempty"` // Village/Town/City
+	State       *string  `json:"state,omitempty"`
+	Country     *string  `json:"country,omitempty"`
+	Pincode     *string  `json:"pincode,omitempty"`
+	FullAddress *string  `json:"full_address,omitempty"`
+	Latitude    *float64 `json:"latitude,omitempty"`
+	Longitude   *float64 `json:"longitude,omitempty"`
+	CreatedAt   string   `json:"created_at"`
+	UpdatedAt   string   `json:"updated_at"`
 }
 
 // NewAddressResponse creates a new AddressResponse from an Address model
@@ -41,6 +43,8 @@ func NewAddressResponse(address *models.Address) *AddressResponse {
 		Country:     address.Country,
 		Pincode:     address.Pincode,
 		FullAddress: address.FullAddress,
+		Latitude:    address.Latitude,
+		Longitude:   address.Longitude,
 		CreatedAt:   address.CreatedAt.Format(time.RFC3339),
 		UpdatedAt:   address.UpdatedAt.Format(time.RFC3339),
 	}
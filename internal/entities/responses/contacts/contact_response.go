@@ -29,3 +29,13 @@ type ContactListResponse struct {
 	Limit    int                `json:"limit"`
 	Offset   int                `json:"offset"`
 }
+
+// ContactVerificationResponse describes a verification OTP sent to a contact
+type ContactVerificationResponse struct {
+	ContactID   string    `json:"contact_id"`
+	ChallengeID string    `json:"challenge_id"`
+	Destination string    `json:"destination"` // masked
+	Delivered   bool      `json:"delivered"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	Message     string    `json:"message"`
+}
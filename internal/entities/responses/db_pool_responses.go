@@ -0,0 +1,38 @@
+package responses
+
+import "database/sql"
+
+// DBPoolStatsResponse reports the saturation of the database connection pool
+// @Description Connection pool counters; a growing wait count means requests queue for connections
+type DBPoolStatsResponse struct {
+	MaxOpenConnections int     `json:"max_open_connections" example:"10"`
+	OpenConnections    int     `json:"open_connections" example:"8"`
+	InUse              int     `json:"in_use" example:"6"`
+	Idle               int     `json:"idle" example:"2"`
+	WaitCount          int64   `json:"wait_count" example:"42"`
+	WaitDurationMs     int64   `json:"wait_duration_ms" example:"1250"`
+	MaxIdleClosed      int64   `json:"max_idle_closed" example:"3"`
+	MaxIdleTimeClosed  int64   `json:"max_idle_time_closed" example:"0"`
+	MaxLifetimeClosed  int64   `json:"max_lifetime_closed" example:"17"`
+	Utilization        float64 `json:"utilization" example:"0.6"`
+}
+
+// NewDBPoolStatsResponse converts database/sql pool statistics. Utilization is the share of
+// the connection limit in use, or 0 when the pool is unlimited.
+func NewDBPoolStatsResponse(stats sql.DBStats) *DBPoolStatsResponse {
+	response := &DBPoolStatsResponse{
+		MaxOpenConnections: stats.MaxOpenConnections,
+		OpenConnections:    stats.OpenConnections,
+		InUse:              stats.InUse,
+		Idle:               stats.Idle,
+		WaitCount:          stats.WaitCount,
+		WaitDurationMs:     stats.WaitDuration.Milliseconds(),
+		MaxIdleClosed:      stats.MaxIdleClosed,
+		MaxIdleTimeClosed:  stats.MaxIdleTimeClosed,
+		MaxLifetimeClosed:  stats.MaxLifetimeClosed,
+	}
+	if stats.MaxOpenConnections > 0 {
+		response.Utilization = float64(stats.InUse) / float64(stats.MaxOpenConnections)
+	}
+	return response
+}
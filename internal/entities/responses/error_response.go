@@ -53,17 +53,18 @@ func NewErrorResponse(error, message string, code string) *ErrorResponse {
 // NewErrorResponseFromError creates an error response from a custom error
 func NewErrorResponseFromError(err error, requestID string) *ErrorResponse {
 	if err == nil {
-		return NewErrorResponse("UNKNOWN_ERROR", "An unknown error occurred", "UNKNOWN_ERROR").
+		return NewErrorResponse("UNKNOWN_ERROR", "An unknown error occurred", string(errors.CodeUnknown)).
 			WithRequestID(requestID)
 	}
 
+	code := string(errors.CodeOf(err))
 	switch e := err.(type) {
 	case *errors.ValidationError:
 		response := &ValidationErrorResponse{
 			Success:   false,
 			Error:     "VALIDATION_ERROR",
 			Message:   e.Error(),
-			Code:      "VALIDATION_ERROR",
+			Code:      code,
 			Errors:    e.Details(),
 			Timestamp: time.Now().UTC(),
 			RequestID: requestID,
@@ -80,31 +81,36 @@ func NewErrorResponseFromError(err error, requestID string) *ErrorResponse {
 		}
 
 	case *errors.BadRequestError:
-		return NewErrorResponse("BAD_REQUEST", e.Error(), "BAD_REQUEST").
+		return NewErrorResponse("BAD_REQUEST", e.Error(), code).
 			WithRequestID(requestID).
 			WithDetails(map[string]interface{}{"errors": e.Details()})
 
 	case *errors.UnauthorizedError:
-		return NewSecurityErrorResponse("UNAUTHORIZED", e.Error(), "UNAUTHORIZED", requestID)
+		return NewSecurityErrorResponse("UNAUTHORIZED", e.Error(), code, requestID)
 
 	case *errors.ForbiddenError:
-		return NewSecurityErrorResponse("FORBIDDEN", e.Error(), "FORBIDDEN", requestID)
+		return NewSecurityErrorResponse("FORBIDDEN", e.Error(), code, requestID)
 
 	case *errors.NotFoundError:
-		return NewErrorResponse("NOT_FOUND", e.Error(), "NOT_FOUND").
+		return NewErrorResponse("NOT_FOUND", e.Error(), code).
 			WithRequestID(requestID)
 
 	case *errors.ConflictError:
-		return NewErrorResponse("CONFLICT", e.Error(), "CONFLICT").
+		return NewErrorResponse("CONFLICT", e.Error(), code).
 			WithRequestID(requestID)
 
+	case *errors.OptimisticLockError:
+		return NewErrorResponse("CONFLICT", e.Error(), code).
+			WithRequestID(requestID).
+			WithDetails(e.Details())
+
 	case *errors.InternalError:
 		// Never expose internal error details
-		return NewErrorResponse("INTERNAL_ERROR", "An internal server error occurred", "INTERNAL_ERROR").
+		return NewErrorResponse("INTERNAL_ERROR", "An internal server error occurred", code).
 			WithRequestID(requestID)
 
 	default:
-		return NewErrorResponse("GENERIC_ERROR", err.Error(), "GENERIC_ERROR").
+		return NewErrorResponse("GENERIC_ERROR", err.Error(), code).
 			WithRequestID(requestID)
 	}
 }
@@ -128,7 +134,7 @@ func NewValidationErrorResponse(message string, validationErrors []string, reque
 		Success:   false,
 		Error:     "VALIDATION_ERROR",
 		Message:   message,
-		Code:      "VALIDATION_ERROR",
+		Code:      string(errors.CodeValidationFailed),
 		Errors:    validationErrors,
 		Timestamp: time.Now().UTC(),
 		RequestID: requestID,
@@ -151,6 +157,12 @@ func (r *ErrorResponse) WithDetails(details map[string]interface{}) *ErrorRespon
 	return r
 }
 
+// WithCode sets the catalog error code of the response
+func (r *ErrorResponse) WithCode(code errors.ErrorCode) *ErrorResponse {
+	r.Code = string(code)
+	return r
+}
+
 // WithRequestID adds a request ID to the error response
 func (r *ErrorResponse) WithRequestID(requestID string) *ErrorResponse {
 	r.RequestID = requestID
@@ -187,9 +199,9 @@ func (r *ErrorResponse) ToJSON() map[string]interface{} {
 	return result
 }
 
-// GetHTTPStatusCode returns the appropriate HTTP status code for the error
+// GetHTTPStatusCode returns the appropriate HTTP status code for the error type
 func (r *ErrorResponse) GetHTTPStatusCode() int {
-	switch r.Code {
+	switch r.Error {
 	case "VALIDATION_ERROR", "BAD_REQUEST":
 		return 400
 	case "UNAUTHORIZED":
@@ -213,32 +225,32 @@ func (r *ErrorResponse) GetHTTPStatusCode() int {
 
 // NewBadRequestResponse creates a bad request error response
 func NewBadRequestResponse(message, requestID string) *ErrorResponse {
-	return NewErrorResponse("BAD_REQUEST", message, "BAD_REQUEST").WithRequestID(requestID)
+	return NewErrorResponse("BAD_REQUEST", message, string(errors.CodeBadRequest)).WithRequestID(requestID)
 }
 
 // NewUnauthorizedResponse creates an unauthorized error response
 func NewUnauthorizedResponse(message, requestID string) *ErrorResponse {
-	return NewSecurityErrorResponse("UNAUTHORIZED", message, "UNAUTHORIZED", requestID)
+	return NewSecurityErrorResponse("UNAUTHORIZED", message, string(errors.CodeUnauthorized), requestID)
 }
 
 // NewForbiddenResponse creates a forbidden error response
 func NewForbiddenResponse(message, requestID string) *ErrorResponse {
-	return NewSecurityErrorResponse("FORBIDDEN", message, "FORBIDDEN", requestID)
+	return NewSecurityErrorResponse("FORBIDDEN", message, string(errors.CodeForbidden), requestID)
 }
 
 // NewNotFoundResponse creates a not found error response
 func NewNotFoundResponse(message, requestID string) *ErrorResponse {
-	return NewErrorResponse("NOT_FOUND", message, "NOT_FOUND").WithRequestID(requestID)
+	return NewErrorResponse("NOT_FOUND", message, string(errors.CodeNotFound)).WithRequestID(requestID)
 }
 
 // NewConflictResponse creates a conflict error response
 func NewConflictResponse(message, requestID string) *ErrorResponse {
-	return NewErrorResponse("CONFLICT", message, "CONFLICT").WithRequestID(requestID)
+	return NewErrorResponse("CONFLICT", message, string(errors.CodeConflict)).WithRequestID(requestID)
 }
 
 // NewInternalErrorResponse creates an internal error response
 func NewInternalErrorResponse(requestID string) *ErrorResponse {
-	return NewErrorResponse("INTERNAL_ERROR", "An internal server error occurred", "INTERNAL_ERROR").WithRequestID(requestID)
+	return NewErrorResponse("INTERNAL_ERROR", "An internal server error occurred", string(errors.CodeInternal)).WithRequestID(requestID)
 }
 
 // NewRateLimitResponse creates a rate limit error response
@@ -247,7 +259,7 @@ func NewRateLimitResponse(message, retryAfter, requestID string) *ErrorResponse
 	if retryAfter != "" {
 		details["retry_after"] = retryAfter
 	}
-	return NewErrorResponse("RATE_LIMIT_EXCEEDED", message, "RATE_LIMIT_EXCEEDED").
+	return NewErrorResponse("RATE_LIMIT_EXCEEDED", message, string(errors.CodeRateLimited)).
 		WithRequestID(requestID).
 		WithDetails(details)
 }
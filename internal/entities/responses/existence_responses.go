@@ -0,0 +1,8 @@
+package responses
+
+// ExistenceCheckResponse reports which of the requested IDs exist
+// @Description Requested IDs split into those that exist and those that do not, each in request order
+type ExistenceCheckResponse struct {
+	Existing []string `json:"existing" example:"USER00000001"`
+	Missing  []string `json:"missing" example:"USER00000002"`
+}
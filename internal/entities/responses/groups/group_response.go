@@ -25,4 +25,8 @@ type GroupMembershipResponse struct {
 	IsActive      bool       `json:"is_active"`
 	AddedByID     string     `json:"added_by_id"`
 	CreatedAt     *time.Time `json:"created_at"`
+	// IsEffective reports whether now falls within the StartsAt-EndsAt validity window
+	IsEffective bool `json:"is_effective"`
+	// Roles are the roles the member holds through the group, when requested
+	Roles []*GroupRoleDetail `json:"roles,omitempty"`
 }
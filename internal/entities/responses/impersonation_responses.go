@@ -0,0 +1,15 @@
+package responses
+
+import "time"
+
+// ImpersonationResponse is returned when an impersonation session starts
+// @Description Short-lived access token that acts as the target user on behalf of the impersonator
+type ImpersonationResponse struct {
+	ImpersonationID string    `json:"impersonation_id" example:"IMP_1b4e28ba2fa1"`
+	AccessToken     string    `json:"access_token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	TokenType       string    `json:"token_type" example:"Bearer"`
+	ExpiresIn       int64     `json:"expires_in" example:"900"`
+	ExpiresAt       time.Time `json:"expires_at" example:"2024-01-15T10:45:00Z"`
+	ImpersonatorID  string    `json:"impersonator_id" example:"USER00000002"`
+	TargetUserID    string    `json:"target_user_id" example:"USER00000001"`
+}
@@ -0,0 +1,20 @@
+package organizations
+
+// AvailableRoleResponse represents a role that an organization can use, tagged by where it comes from
+type AvailableRoleResponse struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	ServiceID   string `json:"service_id"`
+	// Scope is ORG for the organization's own roles and GLOBAL for roles shared by every organization
+	Scope    string `json:"scope"`
+	IsActive bool   `json:"is_active"`
+	// Assignable tells whether members of the organization can be given the role right now
+	Assignable bool `json:"assignable"`
+}
+
+// AvailableRolesResponse lists the organization's own roles followed by the global roles
+type AvailableRolesResponse struct {
+	OrganizationID string                   `json:"organization_id"`
+	Roles          []*AvailableRoleResponse `json:"roles"`
+}
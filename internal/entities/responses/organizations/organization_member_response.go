@@ -0,0 +1,31 @@
+package organizations
+
+import "time"
+
+// OrganizationMemberResponse represents a user's direct membership in an organization
+type OrganizationMemberResponse struct {
+	ID             string     `json:"id"`
+	OrganizationID string     `json:"organization_id"`
+	UserID         string     `json:"user_id"`
+	Role           string     `json:"role"`
+	AddedByID      string     `json:"added_by_id,omitempty"`
+	CreatedAt      *time.Time `json:"created_at"`
+}
+
+// OrganizationMembersResponse represents the response for listing the members of an organization
+type OrganizationMembersResponse struct {
+	OrganizationID string                        `json:"organization_id"`
+	Members        []*OrganizationMemberResponse `json:"members"`
+	TotalCount     int64                         `json:"total_count"`
+	Limit          int                           `json:"limit"`
+	Offset         int                           `json:"offset"`
+}
+
+// OrganizationMemberRemovalResponse reports the outcome of removing a member from an organization.
+// GroupMemberships counts the organization groups the user still belongs to after removal.
+type OrganizationMemberRemovalResponse struct {
+	OrganizationID   string `json:"organization_id"`
+	UserID           string `json:"user_id"`
+	GroupMemberships int64  `json:"group_memberships"`
+	Warning          string `json:"warning,omitempty"`
+}
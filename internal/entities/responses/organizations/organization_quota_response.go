@@ -0,0 +1,21 @@
+package organizations
+
+import "time"
+
+// OrganizationQuotaWindow reports the use of one organization limit in its current window.
+// A zero limit is unlimited.
+type OrganizationQuotaWindow struct {
+	Limit     int64     `json:"limit"`
+	Used      int64     `json:"used"`
+	Remaining int64     `json:"remaining"`
+	ResetAt   time.Time `json:"reset_at"`
+}
+
+// OrganizationQuotaResponse represents the rate limit and monthly quota of an organization
+type OrganizationQuotaResponse struct {
+	OrganizationID   string                  `json:"organization_id"`
+	OrganizationType string                  `json:"organization_type"`
+	Enforced         bool                    `json:"enforced"`
+	RateLimit        OrganizationQuotaWindow `json:"rate_limit"`
+	MonthlyQuota     OrganizationQuotaWindow `json:"monthly_quota"`
+}
@@ -3,6 +3,7 @@ package organizations
 import (
 	"time"
 
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
 	groupResponses "github.com/Kisanlink/aaa-service/v2/internal/entities/responses/groups"
 )
 
@@ -16,21 +17,79 @@ type OrganizationResponse struct {
 	IsActive    bool       `json:"is_active"`
 	CreatedAt   *time.Time `json:"created_at"`
 	UpdatedAt   *time.Time `json:"updated_at"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
 }
 
-// GroupHierarchyNode represents a group with its hierarchy information
+// OrganizationHardDeleteConfirmationResponse carries the token that confirms a hard delete
+type OrganizationHardDeleteConfirmationResponse struct {
+	OrganizationID    string    `json:"organization_id"`
+	OrganizationName  string    `json:"organization_name"`
+	ConfirmationToken string    `json:"confirmation_token"`
+	ExpiresAt         time.Time `json:"expires_at"`
+}
+
+// OrganizationCascadeDeleteResponse counts what was soft-deleted together with an organization
+type OrganizationCascadeDeleteResponse struct {
+	OrganizationID   string    `json:"organization_id"`
+	Groups           int       `json:"groups"`
+	GroupMemberships int       `json:"group_memberships"`
+	GroupRoles       int       `json:"group_roles"`
+	DeletedAt        time.Time `json:"deleted_at"`
+}
+
+// OrganizationMergeResponse is the target organization after a merge and what the merge changed
+type OrganizationMergeResponse struct {
+	Organization *OrganizationResponse            `json:"organization"`
+	Summary      *models.OrganizationMergeSummary `json:"summary"`
+}
+
+// GroupHierarchyNode represents a group with its hierarchy information. ChildCount is the number
+// of active child groups, including those not loaded into Children.
 type GroupHierarchyNode struct {
-	Group    *groupResponses.GroupResponse     `json:"group"`
-	Roles    []*groupResponses.GroupRoleDetail `json:"roles"`
-	Children []*GroupHierarchyNode             `json:"children"`
+	Group      *groupResponses.GroupResponse     `json:"group"`
+	Roles      []*groupResponses.GroupRoleDetail `json:"roles"`
+	Children   []*GroupHierarchyNode             `json:"children"`
+	ChildCount int64                             `json:"child_count"`
 }
 
-// OrganizationHierarchyResponse represents the response for organization hierarchy
+// OrganizationHierarchyResponse represents the response for organization hierarchy. When the
+// organization has too many groups for a full tree, GroupsTruncated is set and Groups holds only
+// the first root groups; the rest are loaded level by level from the group children endpoint.
 type OrganizationHierarchyResponse struct {
-	Organization *OrganizationResponse   `json:"organization"`
-	Parents      []*OrganizationResponse `json:"parents"`
-	Children     []*OrganizationResponse `json:"children"`
-	Groups       []*GroupHierarchyNode   `json:"groups"`
+	Organization    *OrganizationResponse   `json:"organization"`
+	Parents         []*OrganizationResponse `json:"parents"`
+	Children        []*OrganizationResponse `json:"children"`
+	Groups          []*GroupHierarchyNode   `json:"groups"`
+	GroupsTruncated bool                    `json:"groups_truncated"`
+}
+
+// GroupChildrenResponse represents a page of one level of the group hierarchy of an organization:
+// the root groups, or the direct children of ParentGroupID
+type GroupChildrenResponse struct {
+	OrganizationID string                `json:"organization_id"`
+	ParentGroupID  string                `json:"parent_group_id,omitempty"`
+	Groups         []*GroupHierarchyNode `json:"groups"`
+	TotalCount     int64                 `json:"total_count"`
+	Limit          int                   `json:"limit"`
+	Offset         int                   `json:"offset"`
+}
+
+// OrganizationLineageNode is an organization in an ancestors or descendants list. Depth is its
+// distance from the queried organization: 1 for the parent or a direct child.
+type OrganizationLineageNode struct {
+	OrganizationResponse
+	Depth int `json:"depth"`
+}
+
+// OrganizationLineageResponse represents a page of the ancestors or descendants of an organization,
+// nearest first
+type OrganizationLineageResponse struct {
+	OrganizationID string                     `json:"organization_id"`
+	Organizations  []*OrganizationLineageNode `json:"organizations"`
+	MaxDepth       int                        `json:"max_depth"`
+	TotalCount     int64                      `json:"total_count"`
+	Limit          int                        `json:"limit"`
+	Offset         int                        `json:"offset"`
 }
 
 // OrganizationStatsResponse represents statistics about an organization
@@ -40,3 +99,20 @@ type OrganizationStatsResponse struct {
 	GroupCount     int64  `json:"group_count"`
 	UserCount      int64  `json:"user_count"`
 }
+
+// HierarchyDepthViolation is an organization or group nested deeper than the maximum hierarchy
+// depth. Depth counts levels from the root, which is at depth 1.
+type HierarchyDepthViolation struct {
+	ID             string `json:"id"`
+	Name           string `json:"name"`
+	OrganizationID string `json:"organization_id,omitempty"`
+	Depth          int    `json:"depth"`
+}
+
+// HierarchyDepthReport lists the organizations and groups exceeding a maximum hierarchy depth,
+// deepest first
+type HierarchyDepthReport struct {
+	MaxDepth      int                        `json:"max_depth"`
+	Organizations []*HierarchyDepthViolation `json:"organizations"`
+	Groups        []*HierarchyDepthViolation `json:"groups"`
+}
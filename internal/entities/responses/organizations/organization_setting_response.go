@@ -0,0 +1,16 @@
+package organizations
+
+// OrganizationSettingResponse represents the effective value of one organization setting
+type OrganizationSettingResponse struct {
+	Key         string      `json:"key"`
+	Value       interface{} `json:"value"`
+	Type        string      `json:"type"`
+	IsDefault   bool        `json:"is_default"`
+	Description string      `json:"description,omitempty"`
+}
+
+// OrganizationSettingsResponse represents every known setting of an organization, with defaults filled in
+type OrganizationSettingsResponse struct {
+	OrganizationID string                         `json:"organization_id"`
+	Settings       []*OrganizationSettingResponse `json:"settings"`
+}
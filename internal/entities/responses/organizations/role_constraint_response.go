@@ -0,0 +1,32 @@
+package organizations
+
+import "time"
+
+// RoleConstraintResponse represents a role assignment constraint of an organization
+type RoleConstraintResponse struct {
+	ID             string    `json:"id"`
+	OrganizationID string    `json:"organization_id"`
+	Name           string    `json:"name"`
+	Description    string    `json:"description,omitempty"`
+	Type           string    `json:"type"`
+	RoleIDs        []string  `json:"role_ids"`
+	MaxCount       int       `json:"max_count,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// RoleConstraintViolationResponse describes existing assignments that break a constraint, e.g.
+// because they were made before the constraint was added
+type RoleConstraintViolationResponse struct {
+	ConstraintID   string   `json:"constraint_id"`
+	ConstraintName string   `json:"constraint_name"`
+	Type           string   `json:"type"`
+	UserIDs        []string `json:"user_ids"` // The user holding too many roles, or the holders of an over-cap role
+	RoleIDs        []string `json:"role_ids"` // The conflicting roles, or the over-cap role
+	Message        string   `json:"message"`
+}
+
+// RoleConstraintViolationsResponse lists every existing violation of an organization's constraints
+type RoleConstraintViolationsResponse struct {
+	OrganizationID string                             `json:"organization_id"`
+	Violations     []*RoleConstraintViolationResponse `json:"violations"`
+}
@@ -0,0 +1,36 @@
+package organizations
+
+// RoleTemplateRoleResponse represents one role of a role template
+type RoleTemplateRoleResponse struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Permissions []string `json:"permissions"`
+}
+
+// RoleTemplateResponse represents a built-in or admin-defined role template
+type RoleTemplateResponse struct {
+	Name        string                      `json:"name"`
+	Description string                      `json:"description,omitempty"`
+	IsBuiltin   bool                        `json:"is_builtin"`
+	Roles       []*RoleTemplateRoleResponse `json:"roles"`
+}
+
+// RoleTemplatesResponse represents every role template that can be applied
+type RoleTemplatesResponse struct {
+	Templates []*RoleTemplateResponse `json:"templates"`
+}
+
+// AppliedTemplateRoleResponse represents a role created by applying a template
+type AppliedTemplateRoleResponse struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Permissions int    `json:"permissions"`
+}
+
+// ApplyRoleTemplateResponse represents the outcome of applying a role template to an organization
+type ApplyRoleTemplateResponse struct {
+	OrganizationID string                         `json:"organization_id"`
+	Template       string                         `json:"template"`
+	CreatedRoles   []*AppliedTemplateRoleResponse `json:"created_roles"`
+	SkippedRoles   []string                       `json:"skipped_roles"` // Roles the organization already had
+}
@@ -0,0 +1,22 @@
+package organizations
+
+import (
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+)
+
+// UserAttributeSchemaResponse represents the custom user attribute schema of an organization
+type UserAttributeSchemaResponse struct {
+	OrganizationID string                     `json:"organization_id"`
+	Attributes     models.UserAttributeSchema `json:"attributes"`
+	UpdatedAt      *time.Time                 `json:"updated_at,omitempty"`
+}
+
+// UserAttributesResponse represents the custom attributes an organization keeps on a user
+type UserAttributesResponse struct {
+	OrganizationID string                 `json:"organization_id"`
+	UserID         string                 `json:"user_id"`
+	Attributes     map[string]interface{} `json:"attributes"`
+	UpdatedAt      *time.Time             `json:"updated_at,omitempty"`
+}
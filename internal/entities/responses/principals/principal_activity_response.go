@@ -0,0 +1,25 @@
+package principals
+
+import "time"
+
+// EndpointActivityResponse reports the calls a principal made to one endpoint
+type EndpointActivityResponse struct {
+	Method       string    `json:"method"`
+	Endpoint     string    `json:"endpoint"`
+	Calls        int64     `json:"calls"`
+	Failures     int64     `json:"failures"`
+	FailureRate  float64   `json:"failure_rate"`
+	LastCalledAt time.Time `json:"last_called_at"`
+}
+
+// PrincipalActivityResponse summarizes how a principal used the API over a window
+type PrincipalActivityResponse struct {
+	PrincipalID string                     `json:"principal_id"`
+	WindowDays  int                        `json:"window_days"`
+	Since       time.Time                  `json:"since"`
+	LastUsedAt  *time.Time                 `json:"last_used_at"`
+	TotalCalls  int64                      `json:"total_calls"`
+	FailedCalls int64                      `json:"failed_calls"`
+	FailureRate float64                    `json:"failure_rate"`
+	Endpoints   []EndpointActivityResponse `json:"endpoints"`
+}
@@ -0,0 +1,17 @@
+package principals
+
+// ResourcePermissionGrantResponse is a permission a principal holds through one of its roles.
+// Source is "direct" for a resource permission assigned to the role and "role" for a named
+// permission of the role.
+type ResourcePermissionGrantResponse struct {
+	Source       string `json:"source"`
+	RoleID       string `json:"role_id"`
+	RoleName     string `json:"role_name"`
+	ResourceType string `json:"resource_type"`
+	ResourceID   string `json:"resource_id,omitempty"`
+	Action       string `json:"action,omitempty"`
+	Permission   string `json:"permission,omitempty"`
+	// Conditions limit where the grant applies: the organization_id and group_id scoping the
+	// role, and the source_group_id the role is inherited from
+	Conditions map[string]string `json:"conditions,omitempty"`
+}
@@ -27,3 +27,21 @@ type ServiceResponse struct {
 	CreatedAt      *time.Time `json:"created_at"`
 	UpdatedAt      *time.Time `json:"updated_at"`
 }
+
+// ServiceSummaryResponse is a service principal in a service listing, with the number of API keys
+// that authenticate it and when it last called the API
+type ServiceSummaryResponse struct {
+	ServiceResponse
+	KeyCount   int        `json:"key_count"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+}
+
+// RotateCredentialsResponse carries the new API key of a service principal. The key is only
+// returned here; the previous key keeps working until PreviousKeyExpiresAt.
+type RotateCredentialsResponse struct {
+	PrincipalID          string    `json:"principal_id"`
+	ServiceID            string    `json:"service_id"`
+	APIKey               string    `json:"api_key"`
+	RotatedAt            time.Time `json:"rotated_at"`
+	PreviousKeyExpiresAt time.Time `json:"previous_key_expires_at"`
+}
@@ -0,0 +1,21 @@
+package resources
+
+// RegisteredResourceResponse is returned when a service registers a resource
+// @Description Registered resource with the actions that can be granted on it
+type RegisteredResourceResponse struct {
+	Resource *ResourceResponse `json:"resource"`
+	Actions  []string          `json:"actions" example:"read,update"`
+	Created  bool              `json:"created" example:"true"`
+}
+
+// ResourceTypeResponse is one resource type and the number of active resources of that type
+type ResourceTypeResponse struct {
+	Type  string `json:"type" example:"farm/crop"`
+	Count int64  `json:"count" example:"12"`
+}
+
+// ResourceTypesResponse lists the resource types in use
+// @Description Resource types with active resource counts
+type ResourceTypesResponse struct {
+	Types []ResourceTypeResponse `json:"types"`
+}
@@ -9,16 +9,17 @@ import (
 // ResourceResponse represents a single resource in API responses
 // @Description Response structure for a single resource
 type ResourceResponse struct {
-	ID          string     `json:"id" example:"RES_abc123"`
-	Name        string     `json:"name" example:"User Management"`
-	Type        string     `json:"type" example:"aaa/user"`
-	Description string     `json:"description" example:"Resource for managing users"`
-	IsActive    bool       `json:"is_active" example:"true"`
-	ParentID    *string    `json:"parent_id,omitempty" example:"RES_parent123"`
-	OwnerID     *string    `json:"owner_id,omitempty" example:"USR_owner123"`
-	CreatedAt   time.Time  `json:"created_at" example:"2024-01-01T00:00:00Z"`
-	UpdatedAt   time.Time  `json:"updated_at" example:"2024-01-01T00:00:00Z"`
-	DeletedAt   *time.Time `json:"deleted_at,omitempty" example:"2024-01-01T00:00:00Z"`
+	ID          string                 `json:"id" example:"RES_abc123"`
+	Name        string                 `json:"name" example:"User Management"`
+	Type        string                 `json:"type" example:"aaa/user"`
+	Description string                 `json:"description" example:"Resource for managing users"`
+	IsActive    bool                   `json:"is_active" example:"true"`
+	ParentID    *string                `json:"parent_id,omitempty" example:"RES_parent123"`
+	OwnerID     *string                `json:"owner_id,omitempty" example:"USR_owner123"`
+	Attributes  map[string]interface{} `json:"attributes,omitempty"`
+	CreatedAt   time.Time              `json:"created_at" example:"2024-01-01T00:00:00Z"`
+	UpdatedAt   time.Time              `json:"updated_at" example:"2024-01-01T00:00:00Z"`
+	DeletedAt   *time.Time             `json:"deleted_at,omitempty" example:"2024-01-01T00:00:00Z"`
 }
 
 // NewResourceResponse creates a new ResourceResponse from a Resource model
@@ -35,6 +36,7 @@ func NewResourceResponse(resource *models.Resource) *ResourceResponse {
 		IsActive:    resource.IsActive,
 		ParentID:    resource.ParentID,
 		OwnerID:     resource.OwnerID,
+		Attributes:  resource.Attributes,
 		CreatedAt:   resource.CreatedAt,
 		UpdatedAt:   resource.UpdatedAt,
 		DeletedAt:   resource.DeletedAt,
@@ -0,0 +1,19 @@
+package users
+
+// ProfileCompletenessResponse scores how complete a user's profile is, for nudging users to fill
+// in what is missing
+type ProfileCompletenessResponse struct {
+	UserID string `json:"user_id"`
+	// Score is the weight of the completed items as a percentage of the total weight, 0 to 100
+	Score       int  `json:"score"`
+	KYCVerified bool `json:"kyc_verified"`
+	// CompletedFields and MissingFields name the weighed items, heaviest first
+	CompletedFields []string                  `json:"completed_fields"`
+	MissingFields   []ProfileCompletenessItem `json:"missing_fields"`
+}
+
+// ProfileCompletenessItem is an item the user has yet to complete and the score it is worth
+type ProfileCompletenessItem struct {
+	Field  string `json:"field"`
+	Weight int    `json:"weight"`
+}
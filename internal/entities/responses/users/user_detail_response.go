@@ -0,0 +1,149 @@
+package users
+
+import (
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+)
+
+// UserDetailResponse is a user assembled with the related data the caller expanded. Sections that
+// were not expanded are left out; Expanded lists the ones that were, so an expanded but empty
+// section can be told apart from one that was not requested.
+type UserDetailResponse struct {
+	ID                 string     `json:"id"`
+	PhoneNumber        string     `json:"phone_number"`
+	CountryCode        string     `json:"country_code"`
+	Username           *string    `json:"username,omitempty"`
+	IsValidated        bool       `json:"is_validated"`
+	Status             *string    `json:"status,omitempty"`
+	Tokens             int        `json:"tokens"`
+	HasMPin            bool       `json:"has_mpin"`
+	MustChangePassword bool       `json:"must_change_password"`
+	LastLoginAt        *time.Time `json:"last_login_at,omitempty"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+
+	Profile       *UserProfileDetail       `json:"profile,omitempty"`
+	Contacts      []UserContactDetail      `json:"contacts,omitempty"`
+	Addresses     []AddressResponse        `json:"addresses,omitempty"`
+	Roles         []UserRoleDetail         `json:"roles,omitempty"`
+	Organizations []UserOrganizationDetail `json:"organizations,omitempty"`
+	Groups        []UserGroupDetail        `json:"groups,omitempty"`
+	Expanded      []string                 `json:"expanded"`
+
+	// Custom attributes kept on the user by the organization the detail was requested in
+	AttributesOrganizationID string                 `json:"attributes_organization_id,omitempty"`
+	Attributes               map[string]interface{} `json:"attributes,omitempty"`
+}
+
+// UserProfileDetail is the profile section of a user detail
+type UserProfileDetail struct {
+	ID              string           `json:"id"`
+	Name            *string          `json:"name,omitempty"`
+	CareOf          *string          `json:"care_of,omitempty"`
+	DateOfBirth     *string          `json:"date_of_birth,omitempty"`
+	YearOfBirth     *string          `json:"year_of_birth,omitempty"`
+	Photo           *string          `json:"photo,omitempty"`
+	Message         *string          `json:"message,omitempty"`
+	AadhaarNumber   *string          `json:"aadhaar_number,omitempty"`
+	AadhaarVerified bool             `json:"aadhaar_verified"`
+	KYCStatus       string           `json:"kyc_status,omitempty"`
+	Address         *AddressResponse `json:"address,omitempty"`
+}
+
+// UserContactDetail is a contact in a user detail
+type UserContactDetail struct {
+	ID          string           `json:"id"`
+	Type        string           `json:"type"`
+	Value       string           `json:"value"`
+	CountryCode *string          `json:"country_code,omitempty"`
+	Description *string          `json:"description,omitempty"`
+	IsPrimary   bool             `json:"is_primary"`
+	IsVerified  bool             `json:"is_verified"`
+	IsActive    bool             `json:"is_active"`
+	VerifiedAt  *string          `json:"verified_at,omitempty"`
+	Address     *AddressResponse `json:"address,omitempty"`
+}
+
+// UserOrganizationDetail is an organization the user belongs to
+type UserOrganizationDetail struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// UserGroupDetail is a group the user is a member of
+type UserGroupDetail struct {
+	ID             string `json:"id"`
+	Name           string `json:"name"`
+	OrganizationID string `json:"organization_id"`
+}
+
+// GetType returns the type of response
+func (r *UserDetailResponse) GetType() string {
+	return "user_detail"
+}
+
+// IsSuccess returns whether the response indicates success
+func (r *UserDetailResponse) IsSuccess() bool {
+	return r.ID != ""
+}
+
+// FromModel fills the user fields of the detail. Related sections are added by the caller.
+func (r *UserDetailResponse) FromModel(user *models.User) {
+	r.ID = user.ID
+	r.PhoneNumber = user.PhoneNumber
+	r.CountryCode = user.CountryCode
+	r.Username = user.Username
+	r.IsValidated = user.IsValidated
+	r.Status = user.Status
+	r.Tokens = user.Tokens
+	r.HasMPin = user.HasMPin()
+	r.MustChangePassword = user.MustChangePassword
+	r.LastLoginAt = user.LastLoginAt
+	r.CreatedAt = user.CreatedAt
+	r.UpdatedAt = user.UpdatedAt
+}
+
+// NewUserProfileDetail converts a preloaded profile, or returns nil when the user has none
+func NewUserProfileDetail(profile *models.UserProfile) *UserProfileDetail {
+	if profile.BaseModel == nil || profile.ID == "" {
+		return nil
+	}
+	detail := &UserProfileDetail{
+		ID:              profile.ID,
+		Name:            profile.Name,
+		CareOf:          profile.CareOf,
+		DateOfBirth:     profile.DateOfBirth,
+		YearOfBirth:     profile.YearOfBirth,
+		Photo:           profile.Photo,
+		Message:         profile.Message,
+		AadhaarNumber:   profile.AadhaarNumber,
+		AadhaarVerified: profile.AadhaarVerified,
+		KYCStatus:       profile.KYCStatus,
+	}
+	if profile.Address.BaseModel != nil && profile.Address.ID != "" {
+		detail.Address = &AddressResponse{}
+		detail.Address.FromModel(&profile.Address)
+	}
+	return detail
+}
+
+// NewUserContactDetail converts a preloaded contact
+func NewUserContactDetail(contact *models.Contact) UserContactDetail {
+	detail := UserContactDetail{
+		ID:          contact.ID,
+		Type:        contact.Type,
+		Value:       contact.Value,
+		CountryCode: contact.CountryCode,
+		Description: contact.Description,
+		IsPrimary:   contact.IsPrimary,
+		IsVerified:  contact.IsVerified,
+		IsActive:    contact.IsActive,
+		VerifiedAt:  contact.VerifiedAt,
+	}
+	if contact.Address.BaseModel != nil && contact.Address.ID != "" {
+		detail.Address = &AddressResponse{}
+		detail.Address.FromModel(&contact.Address)
+	}
+	return detail
+}
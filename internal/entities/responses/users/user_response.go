@@ -32,8 +32,16 @@ type UserResponse struct {
 	Roles         []UserRoleDetail  `json:"roles"`
 	HasMPin            bool              `json:"has_mpin"`
 	MustChangePassword bool              `json:"must_change_password"`
+	// CreationPath reports whether a create call made a new account or restored a deleted one
+	CreationPath string `json:"creation_path,omitempty"`
 }
 
+// Creation paths reported by user creation
+const (
+	CreationPathCreated  = "created"
+	CreationPathRestored = "restored"
+)
+
 // GetType returns the type of response
 func (r *UserResponse) GetType() string {
 	return "user"
@@ -147,3 +155,9 @@ type UserRoleDetail struct {
 	Role     RoleDetail `json:"role"`
 	IsActive bool       `json:"is_active"`
 }
+
+// BatchUsersResponse represents the result of a batch user lookup
+type BatchUsersResponse struct {
+	Users      []*UserResponse `json:"users"`
+	MissingIDs []string        `json:"missing_ids"`
+}
@@ -0,0 +1,171 @@
+package grpc_server
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// rpcAuditLog records audited RPCs; it is implemented by services.AuditService
+type rpcAuditLog interface {
+	LogUserAction(ctx context.Context, userID, action, resource, resourceID string, details map[string]interface{})
+	LogUserActionWithError(ctx context.Context, userID, action, resource, resourceID string, err error, details map[string]interface{})
+}
+
+// skippedRPCPrefixes are not audited, like health checks and docs over HTTP
+var skippedRPCPrefixes = []string{
+	"/grpc.health.v1.Health/",
+	"/grpc.reflection.",
+}
+
+// rpcAuditor audits every RPC the way the HTTP audit middleware audits requests: one record per
+// call with the method, the caller, the outcome and the latency
+type rpcAuditor struct {
+	auditLog rpcAuditLog
+}
+
+func newRPCAuditor(auditLog rpcAuditLog) *rpcAuditor {
+	return &rpcAuditor{auditLog: auditLog}
+}
+
+// rpcCallKey holds the *rpcCall of the RPC being audited
+type rpcCallKey struct{}
+
+// rpcCall carries the context the handler ran with back to the audit interceptor, which runs
+// before authentication and so cannot see the principal otherwise
+type rpcCall struct {
+	ctx context.Context
+}
+
+// unary audits unary RPCs. It must run before the auth interceptor so rejected calls are audited
+// too, and needs recordPrincipal after it to attribute accepted calls.
+func (a *rpcAuditor) unary(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	if isSkippedRPC(info.FullMethod) {
+		return handler(ctx, req)
+	}
+
+	start := time.Now()
+	ctx = withRPCClient(ctx)
+	call := &rpcCall{ctx: ctx}
+	ctx = context.WithValue(ctx, rpcCallKey{}, call)
+
+	resp, err := handler(ctx, req)
+
+	a.record(call.ctx, info.FullMethod, "unary", time.Since(start), err)
+	return resp, err
+}
+
+// recordPrincipal hands the authenticated context of a unary RPC to the audit interceptor
+func (a *rpcAuditor) recordPrincipal(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	if call, ok := ctx.Value(rpcCallKey{}).(*rpcCall); ok {
+		call.ctx = ctx
+	}
+	return handler(ctx, req)
+}
+
+// stream audits streaming RPCs once the stream ends
+func (a *rpcAuditor) stream(
+	srv interface{},
+	ss grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	if isSkippedRPC(info.FullMethod) {
+		return handler(srv, ss)
+	}
+
+	start := time.Now()
+	wrapped := &auditedServerStream{ServerStream: ss, ctx: withRPCClient(ss.Context())}
+
+	err := handler(srv, wrapped)
+
+	a.record(wrapped.ctx, info.FullMethod, "stream", time.Since(start), err)
+	return err
+}
+
+// record writes the audit record of a finished RPC
+func (a *rpcAuditor) record(ctx context.Context, method, kind string, duration time.Duration, err error) {
+	userID := "anonymous"
+	if uid, ok := ctx.Value("user_id").(string); ok && uid != "" {
+		userID = uid
+	}
+
+	details := map[string]interface{}{
+		"method":      method,
+		"rpc_type":    kind,
+		"ip_address":  ctx.Value("ip_address"),
+		"user_agent":  ctx.Value("user_agent"),
+		"duration_ms": duration.Milliseconds(),
+		"status_code": status.Code(err).String(),
+	}
+	if principalType, ok := ctx.Value("principal_type").(string); ok {
+		details["principal_type"] = principalType
+	}
+
+	if err != nil {
+		a.auditLog.LogUserActionWithError(ctx, userID, models.AuditActionGRPCCall, "grpc", method,
+			fmt.Errorf("gRPC %s: %s", status.Code(err), status.Convert(err).Message()), details)
+		return
+	}
+	a.auditLog.LogUserAction(ctx, userID, models.AuditActionGRPCCall, "grpc", method, details)
+}
+
+// withRPCClient stores the caller's address and user agent under the keys the audit service reads,
+// as the HTTP auth middleware does for requests
+func withRPCClient(ctx context.Context) context.Context {
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		ctx = context.WithValue(ctx, "ip_address", peerIP(p.Addr.String()))
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if userAgent := md.Get("user-agent"); len(userAgent) > 0 {
+			ctx = context.WithValue(ctx, "user_agent", userAgent[0])
+		}
+	}
+	return ctx
+}
+
+// peerIP strips the port from a peer address
+func peerIP(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	return host
+}
+
+func isSkippedRPC(method string) bool {
+	for _, prefix := range skippedRPCPrefixes {
+		if strings.HasPrefix(method, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// auditedServerStream exposes the audit context to stream handlers
+type auditedServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *auditedServerStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,168 @@
+package grpc_server
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// auditedRPC is one audit record written by the auditor
+type auditedRPC struct {
+	userID   string
+	action   string
+	method   string
+	err      error
+	details  map[string]interface{}
+	clientIP interface{}
+}
+
+// recordingAuditLog keeps the audit records it is given
+type recordingAuditLog struct {
+	mu      sync.Mutex
+	records []auditedRPC
+}
+
+func (l *recordingAuditLog) LogUserAction(ctx context.Context, userID, action, resource, resourceID string, details map[string]interface{}) {
+	l.LogUserActionWithError(ctx, userID, action, resource, resourceID, nil, details)
+}
+
+func (l *recordingAuditLog) LogUserActionWithError(ctx context.Context, userID, action, resource, resourceID string, err error, details map[string]interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.records = append(l.records, auditedRPC{
+		userID: userID, action: action, method: resourceID, err: err, details: details, clientIP: ctx.Value("ip_address"),
+	})
+}
+
+func (l *recordingAuditLog) all() []auditedRPC {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]auditedRPC(nil), l.records...)
+}
+
+// tokenAuthInterceptor accepts calls carrying an authorization header, naming the caller after it
+func tokenAuthInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	token := md.Get("authorization")
+	if len(token) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "authorization token is required")
+	}
+	return handler(context.WithValue(ctx, "user_id", token[0]), req)
+}
+
+var auditTestService = &grpc.ServiceDesc{
+	ServiceName: "test.AuditService",
+	HandlerType: (*interface{})(nil),
+	Methods: []grpc.MethodDesc{{
+		MethodName: "Ping",
+		Handler: func(_ interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+			in := new(emptypb.Empty)
+			if err := dec(in); err != nil {
+				return nil, err
+			}
+			info := &grpc.UnaryServerInfo{FullMethod: "/test.AuditService/Ping"}
+			return interceptor(ctx, in, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+				return &emptypb.Empty{}, nil
+			})
+		},
+	}},
+	Streams: []grpc.StreamDesc{{
+		StreamName:    "Fail",
+		ServerStreams: true,
+		Handler: func(_ interface{}, stream grpc.ServerStream) error {
+			return status.Error(codes.PermissionDenied, "not allowed")
+		},
+	}},
+}
+
+func startAuditTestServer(t *testing.T, auditLog *recordingAuditLog) *grpc.ClientConn {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	auditor := newRPCAuditor(auditLog)
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(auditor.unary, tokenAuthInterceptor, auditor.recordPrincipal),
+		grpc.ChainStreamInterceptor(auditor.stream),
+	)
+	server.RegisterService(auditTestService, struct{}{})
+	go func() { _ = server.Serve(lis) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient(lis.Addr().String(),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUserAgent("audit-test"))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestRPCAuditor_Unary(t *testing.T) {
+	auditLog := &recordingAuditLog{}
+	conn := startAuditTestServer(t, auditLog)
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "USER1")
+	require.NoError(t, conn.Invoke(ctx, "/test.AuditService/Ping", &emptypb.Empty{}, &emptypb.Empty{}))
+
+	records := auditLog.all()
+	require.Len(t, records, 1)
+	record := records[0]
+	assert.Equal(t, "USER1", record.userID, "the principal set by authentication is audited")
+	assert.Equal(t, models.AuditActionGRPCCall, record.action)
+	assert.Equal(t, "/test.AuditService/Ping", record.method)
+	assert.NoError(t, record.err)
+	assert.Equal(t, "127.0.0.1", record.clientIP)
+	assert.Contains(t, record.details["user_agent"], "audit-test")
+	assert.Equal(t, "OK", record.details["status_code"])
+	assert.Contains(t, record.details, "duration_ms")
+}
+
+func TestRPCAuditor_UnaryRejectedByAuthentication(t *testing.T) {
+	auditLog := &recordingAuditLog{}
+	conn := startAuditTestServer(t, auditLog)
+
+	err := conn.Invoke(context.Background(), "/test.AuditService/Ping", &emptypb.Empty{}, &emptypb.Empty{})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	records := auditLog.all()
+	require.Len(t, records, 1)
+	assert.Equal(t, "anonymous", records[0].userID)
+	assert.Error(t, records[0].err)
+	assert.Equal(t, "Unauthenticated", records[0].details["status_code"])
+}
+
+func TestRPCAuditor_Stream(t *testing.T) {
+	auditLog := &recordingAuditLog{}
+	conn := startAuditTestServer(t, auditLog)
+
+	stream, err := conn.NewStream(context.Background(), &auditTestService.Streams[0], "/test.AuditService/Fail")
+	require.NoError(t, err)
+	require.NoError(t, stream.CloseSend())
+	err = stream.RecvMsg(&emptypb.Empty{})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	records := auditLog.all()
+	require.Len(t, records, 1)
+	assert.Equal(t, "/test.AuditService/Fail", records[0].method)
+	assert.Equal(t, "stream", records[0].details["rpc_type"])
+	assert.Equal(t, "PermissionDenied", records[0].details["status_code"])
+	assert.Equal(t, "127.0.0.1", records[0].clientIP)
+}
+
+func TestIsSkippedRPC(t *testing.T) {
+	assert.True(t, isSkippedRPC("/grpc.health.v1.Health/Check"))
+	assert.True(t, isSkippedRPC("/grpc.reflection.v1.ServerReflection/ServerReflectionInfo"))
+	assert.False(t, isSkippedRPC("/pb.UserService/GetUser"))
+}
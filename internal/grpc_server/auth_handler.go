@@ -2,14 +2,25 @@ package grpc_server
 
 import (
 	"context"
+	"strconv"
 
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/responses"
 	"github.com/Kisanlink/aaa-service/v2/internal/services"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
 	pb "github.com/Kisanlink/aaa-service/v2/pkg/proto"
 	"go.uber.org/zap"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 )
 
+// Trailers of a login that must be completed with the MPIN
+const (
+	loginChallengeTokenTrailer     = "x-login-challenge-token"
+	loginChallengeExpiresInTrailer = "x-login-challenge-expires-in"
+)
+
 // AuthHandler implements the UserServiceV2 gRPC service for authentication
 type AuthHandler struct {
 	pb.UnimplementedUserServiceServer
@@ -41,10 +52,10 @@ func (h *AuthHandler) Login(ctx context.Context, req *pb.LoginRequest) (*pb.Logi
 	response, err := h.authService.LoginWithUsername(ctx, loginReq)
 	if err != nil {
 		h.logger.Error("Login failed", zap.String("username", req.Username), zap.Error(err))
-		return &pb.LoginResponse{
-			StatusCode: 401,
-			Message:    "Authentication failed",
-		}, status.Error(codes.Unauthenticated, err.Error())
+		return loginFailedResponse(err)
+	}
+	if response.Challenge != nil {
+		return mpinRequiredResponse(ctx, response.Challenge)
 	}
 
 	// Convert service response to gRPC response
@@ -63,6 +74,38 @@ func (h *AuthHandler) Login(ctx context.Context, req *pb.LoginRequest) (*pb.Logi
 	return grpcResponse, nil
 }
 
+// loginFailedResponse answers a login the service refused. A login refused by the concurrent session
+// limit is forbidden rather than unauthenticated, since the credentials were valid.
+func loginFailedResponse(err error) (*pb.LoginResponse, error) {
+	if errors.IsForbiddenError(err) {
+		return &pb.LoginResponse{
+			StatusCode: 403,
+			Message:    err.Error(),
+		}, statusError(err, codes.PermissionDenied)
+	}
+	return &pb.LoginResponse{
+		StatusCode: 401,
+		Message:    "Authentication failed",
+	}, status.Error(codes.Unauthenticated, err.Error())
+}
+
+// mpinRequiredResponse answers a password login that the stepped login holds back until the MPIN is
+// verified. No tokens are issued; the challenge token travels in the x-login-challenge-token
+// trailer and is redeemed at POST /api/v1/auth/login/mpin.
+func mpinRequiredResponse(ctx context.Context, challenge *responses.LoginChallengeResponse) (*pb.LoginResponse, error) {
+	trailer := metadata.Pairs(
+		loginChallengeTokenTrailer, challenge.ChallengeToken,
+		loginChallengeExpiresInTrailer, strconv.FormatInt(challenge.ExpiresIn, 10),
+	)
+	if err := grpc.SetTrailer(ctx, trailer); err != nil {
+		return nil, status.Error(codes.Internal, "failed to send login challenge")
+	}
+	return &pb.LoginResponse{
+		StatusCode: 401,
+		Message:    challenge.Message,
+	}, status.Error(codes.Unauthenticated, "mpin verification required")
+}
+
 // Register creates a new user account
 func (h *AuthHandler) Register(ctx context.Context, req *pb.RegisterRequest) (*pb.RegisterResponse, error) {
 	h.logger.Info("gRPC Register request", zap.String("username", req.Username))
@@ -95,7 +138,7 @@ func (h *AuthHandler) Register(ctx context.Context, req *pb.RegisterRequest) (*p
 		return &pb.RegisterResponse{
 			StatusCode: 400,
 			Message:    "Registration failed",
-		}, status.Error(codes.InvalidArgument, err.Error())
+		}, statusError(err, codes.InvalidArgument)
 	}
 
 	// Convert service response to gRPC response
@@ -161,7 +204,7 @@ func (h *AuthHandler) Logout(ctx context.Context, req *pb.LogoutRequest) (*pb.Lo
 		return &pb.LogoutResponse{
 			StatusCode: 500,
 			Message:    "Logout failed",
-		}, status.Error(codes.Internal, err.Error())
+		}, statusError(err, codes.Internal)
 	}
 
 	grpcResponse := &pb.LogoutResponse{
@@ -0,0 +1,83 @@
+package grpc_server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/config"
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/internal/security"
+	"github.com/Kisanlink/aaa-service/v2/internal/services"
+	pb "github.com/Kisanlink/aaa-service/v2/pkg/proto"
+	"github.com/Kisanlink/aaa-service/v2/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type loginUserRepo struct {
+	interfaces.UserRepository
+	user *models.User
+}
+
+func (r *loginUserRepo) GetByUsername(ctx context.Context, username string) (*models.User, error) {
+	return r.user, nil
+}
+
+type loginCache struct {
+	interfaces.CacheService
+	values map[string]interface{}
+}
+
+func (c *loginCache) Get(key string) (interface{}, bool) {
+	value, ok := c.values[key]
+	return value, ok
+}
+
+func (c *loginCache) Set(key string, value interface{}, ttl int) error {
+	c.values[key] = value
+	return nil
+}
+
+// trailerStream records the trailer a handler sets
+type trailerStream struct {
+	grpc.ServerTransportStream
+	trailer metadata.MD
+}
+
+func (s *trailerStream) SetTrailer(md metadata.MD) error {
+	s.trailer = metadata.Join(s.trailer, md)
+	return nil
+}
+
+func TestAuthHandler_Login_RequiresMPinStepUp(t *testing.T) {
+	password, err := bcrypt.GenerateFromPassword([]byte("secret123"), bcrypt.MinCost)
+	require.NoError(t, err)
+	user := models.NewUser("9876543210", "+91", string(password))
+	user.ID = "USER1"
+	user.SetMPin("hashed-mpin")
+
+	authService, err := services.NewAuthService(&loginUserRepo{user: user}, nil, nil, &loginCache{values: map[string]interface{}{}}, nil, nil,
+		&services.AuthServiceConfig{JWTSecret: "test-secret"}, zap.NewNop(), utils.NewValidator(), nil)
+	require.NoError(t, err)
+	authService.SetCredentialHasher(security.NewCredentialHasher(bcrypt.MinCost, bcrypt.MinCost))
+	authService.SetLoginStepUpConfig(config.LoginStepUpConfig{RequireMPin: true, ChallengeTTL: time.Minute})
+
+	stream := &trailerStream{}
+	ctx := grpc.NewContextWithServerTransportStream(context.Background(), stream)
+	resp, err := NewAuthHandler(authService, zap.NewNop()).Login(ctx, &pb.LoginRequest{Username: "farmer1", Password: "secret123"})
+
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	assert.Empty(t, resp.AccessToken, "no tokens are issued before the MPIN is verified")
+	assert.Empty(t, resp.RefreshToken)
+	require.Len(t, stream.trailer.Get(loginChallengeTokenTrailer), 1)
+	assert.Len(t, stream.trailer.Get(loginChallengeTokenTrailer)[0], 64)
+	assert.Equal(t, []string{"60"}, stream.trailer.Get(loginChallengeExpiresInTrailer))
+}
@@ -152,7 +152,7 @@ func (ac *AuthorizationChecker) CheckSeedPermission(ctx context.Context, targetS
 			zap.String("resource", "catalog"),
 			zap.String("action", "seed"),
 			zap.Error(err))
-		return status.Errorf(codes.Internal, "authorization check failed: %v", err)
+		return statusError(fmt.Errorf("authorization check failed: %w", err), codes.Internal)
 	}
 
 	if !result.Allowed {
@@ -223,7 +223,7 @@ func (ac *AuthorizationChecker) checkServiceOwnership(
 		ac.logger.Error("Admin permission check failed",
 			zap.String("user_id", principalID),
 			zap.Error(err))
-		return status.Errorf(codes.Internal, "admin authorization check failed: %v", err)
+		return statusError(fmt.Errorf("admin authorization check failed: %w", err), codes.Internal)
 	}
 
 	if !result.Allowed {
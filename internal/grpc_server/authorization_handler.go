@@ -46,7 +46,7 @@ func (h *AuthorizationHandler) Check(ctx context.Context, req *pb.CheckRequest)
 	result, err := h.authzService.CheckPermission(ctx, permission)
 	if err != nil {
 		h.logger.Error("Permission check failed", zap.Error(err))
-		return nil, status.Errorf(codes.Internal, "permission check failed: %v", err)
+		return nil, statusError(fmt.Errorf("permission check failed: %w", err), codes.Internal)
 	}
 
 	h.logger.Info("Permission check completed",
@@ -90,7 +90,7 @@ func (h *AuthorizationHandler) BatchCheck(ctx context.Context, req *pb.BatchChec
 	bulkResult, err := h.authzService.CheckBulkPermissions(ctx, request)
 	if err != nil {
 		h.logger.Error("Bulk permission check failed", zap.Error(err))
-		return nil, status.Errorf(codes.Internal, "bulk permission check failed: %v", err)
+		return nil, statusError(fmt.Errorf("bulk permission check failed: %w", err), codes.Internal)
 	}
 
 	// Convert result to protobuf format
@@ -133,7 +133,7 @@ func (h *AuthorizationHandler) LookupResources(ctx context.Context, req *pb.Look
 	resources, err := h.authzService.LookupResources(ctx, req.PrincipalId, req.ResourceType, req.Action)
 	if err != nil {
 		h.logger.Error("LookupResources failed", zap.Error(err))
-		return nil, status.Errorf(codes.Internal, "lookup resources failed: %v", err)
+		return nil, statusError(fmt.Errorf("lookup resources failed: %w", err), codes.Internal)
 	}
 
 	h.logger.Info("LookupResources completed",
@@ -172,7 +172,7 @@ func (h *AuthorizationHandler) CheckColumns(ctx context.Context, req *pb.CheckCo
 	result, err := h.authzService.CheckPermission(ctx, permission)
 	if err != nil {
 		h.logger.Error("CheckColumns failed", zap.Error(err))
-		return nil, status.Errorf(codes.Internal, "check columns failed: %v", err)
+		return nil, statusError(fmt.Errorf("check columns failed: %w", err), codes.Internal)
 	}
 
 	// For now, if the user has table permission, allow all requested columns
@@ -210,7 +210,7 @@ func (h *AuthorizationHandler) ListAllowedColumns(ctx context.Context, req *pb.L
 	result, err := h.authzService.CheckPermission(ctx, permission)
 	if err != nil {
 		h.logger.Error("ListAllowedColumns failed", zap.Error(err))
-		return nil, status.Errorf(codes.Internal, "list allowed columns failed: %v", err)
+		return nil, statusError(fmt.Errorf("list allowed columns failed: %w", err), codes.Internal)
 	}
 
 	// For now, return a basic set of allowed columns if user has table permission
@@ -268,7 +268,7 @@ func (h *AuthorizationHandler) EvaluatePermission(ctx context.Context, req *pb.E
 	result, err := h.authzService.CheckPermission(ctx, permission)
 	if err != nil {
 		h.logger.Error("EvaluatePermission failed", zap.Error(err))
-		return nil, status.Errorf(codes.Internal, "evaluate permission failed: %v", err)
+		return nil, statusError(fmt.Errorf("evaluate permission failed: %w", err), codes.Internal)
 	}
 
 	// Create response with reasons
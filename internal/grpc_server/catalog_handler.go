@@ -210,7 +210,7 @@ func (ch *CatalogHandler) RegisterAction(
 		return &pb.RegisterActionResponse{
 			StatusCode: 500,
 			Message:    fmt.Sprintf("Failed to register action: %v", err),
-		}, status.Error(codes.Internal, err.Error())
+		}, statusError(err, codes.Internal)
 	}
 
 	// Convert to protobuf Action
@@ -264,7 +264,7 @@ func (ch *CatalogHandler) ListActions(
 		return &pb.ListActionsResponse{
 			StatusCode: 500,
 			Message:    fmt.Sprintf("Failed to list actions: %v", err),
-		}, status.Error(codes.Internal, err.Error())
+		}, statusError(err, codes.Internal)
 	}
 
 	// Convert to protobuf format
@@ -320,7 +320,7 @@ func (ch *CatalogHandler) RegisterResource(
 		return &pb.RegisterResourceResponse{
 			StatusCode: 500,
 			Message:    fmt.Sprintf("Failed to register resource: %v", err),
-		}, status.Error(codes.Internal, err.Error())
+		}, statusError(err, codes.Internal)
 	}
 
 	// Convert to protobuf Resource
@@ -371,7 +371,7 @@ func (ch *CatalogHandler) SetResourceParent(
 		return &pb.SetResourceParentResponse{
 			StatusCode: 500,
 			Message:    fmt.Sprintf("Failed to set resource parent: %v", err),
-		}, status.Error(codes.Internal, err.Error())
+		}, statusError(err, codes.Internal)
 	}
 
 	// Get updated resource
@@ -436,7 +436,7 @@ func (ch *CatalogHandler) ListResources(
 		return &pb.ListResourcesResponse{
 			StatusCode: 500,
 			Message:    fmt.Sprintf("Failed to list resources: %v", err),
-		}, status.Error(codes.Internal, err.Error())
+		}, statusError(err, codes.Internal)
 	}
 
 	// Convert to protobuf format
@@ -493,7 +493,7 @@ func (ch *CatalogHandler) CreateRole(
 		return &pb.CreateRoleResponse{
 			StatusCode: 500,
 			Message:    fmt.Sprintf("Failed to create role: %v", err),
-		}, status.Error(codes.Internal, err.Error())
+		}, statusError(err, codes.Internal)
 	}
 
 	// Convert to protobuf CatalogRole
@@ -546,7 +546,7 @@ func (ch *CatalogHandler) ListRoles(
 		return &pb.ListRolesResponse{
 			StatusCode: 500,
 			Message:    fmt.Sprintf("Failed to list roles: %v", err),
-		}, status.Error(codes.Internal, err.Error())
+		}, statusError(err, codes.Internal)
 	}
 
 	// Convert to protobuf format
@@ -609,7 +609,7 @@ func (ch *CatalogHandler) CreatePermission(
 		return &pb.CreatePermissionResponse{
 			StatusCode: 500,
 			Message:    fmt.Sprintf("Failed to create permission: %v", err),
-		}, status.Error(codes.Internal, err.Error())
+		}, statusError(err, codes.Internal)
 	}
 
 	// Convert to protobuf CatalogPermission
@@ -658,7 +658,7 @@ func (ch *CatalogHandler) AttachPermissions(
 		return &pb.AttachPermissionsResponse{
 			StatusCode: 500,
 			Message:    fmt.Sprintf("Failed to attach permissions: %v", err),
-		}, status.Error(codes.Internal, err.Error())
+		}, statusError(err, codes.Internal)
 	}
 
 	return &pb.AttachPermissionsResponse{
@@ -704,7 +704,7 @@ func (ch *CatalogHandler) ListPermissions(
 		return &pb.ListPermissionsResponse{
 			StatusCode: 500,
 			Message:    fmt.Sprintf("Failed to list permissions: %v", err),
-		}, status.Error(codes.Internal, err.Error())
+		}, statusError(err, codes.Internal)
 	}
 
 	// Convert to protobuf format
@@ -46,10 +46,10 @@ func (h *CombinedUserHandler) Login(ctx context.Context, req *pb.LoginRequest) (
 	response, err := h.authService.LoginWithUsername(ctx, loginReq)
 	if err != nil {
 		h.logger.Error("Login failed", zap.String("username", req.Username), zap.Error(err))
-		return &pb.LoginResponse{
-			StatusCode: 401,
-			Message:    "Authentication failed",
-		}, status.Error(codes.Unauthenticated, err.Error())
+		return loginFailedResponse(err)
+	}
+	if response.Challenge != nil {
+		return mpinRequiredResponse(ctx, response.Challenge)
 	}
 
 	// Convert service response to gRPC response
@@ -126,13 +126,13 @@ func (h *CombinedUserHandler) Register(ctx context.Context, req *pb.RegisterRequ
 			return &pb.RegisterResponse{
 				StatusCode: 409,
 				Message:    "User already exists",
-			}, status.Error(codes.AlreadyExists, err.Error())
+			}, statusError(err, codes.AlreadyExists)
 		}
 
 		return &pb.RegisterResponse{
 			StatusCode: 500,
 			Message:    "Internal server error",
-		}, status.Error(codes.Internal, err.Error())
+		}, statusError(err, codes.Internal)
 	}
 
 	// Convert to protobuf user
@@ -181,7 +181,7 @@ func (h *CombinedUserHandler) GetUser(ctx context.Context, req *pb.GetUserReques
 		return &pb.GetUserResponse{
 			StatusCode: 500,
 			Message:    "Internal server error",
-		}, status.Error(codes.Internal, err.Error())
+		}, statusError(err, codes.Internal)
 	}
 
 	// Convert to protobuf user
@@ -236,7 +236,7 @@ func (h *CombinedUserHandler) GetAllUsers(ctx context.Context, req *pb.GetAllUse
 		return &pb.GetAllUsersResponse{
 			StatusCode: 500,
 			Message:    "Internal server error",
-		}, status.Error(codes.Internal, err.Error())
+		}, statusError(err, codes.Internal)
 	}
 
 	// Convert interface{} to typed user response slice
@@ -304,7 +304,7 @@ func (h *CombinedUserHandler) GetUserByPhone(ctx context.Context, req *pb.GetUse
 		return &pb.GetUserResponse{
 			StatusCode: 500,
 			Message:    "Internal server error",
-		}, status.Error(codes.Internal, err.Error())
+		}, statusError(err, codes.Internal)
 	}
 
 	// Convert to protobuf user
@@ -419,7 +419,7 @@ func (h *CombinedUserHandler) UpdateUser(ctx context.Context, req *pb.UpdateUser
 		return &pb.UpdateUserResponse{
 			StatusCode: 500,
 			Message:    "Internal server error",
-		}, status.Error(codes.Internal, err.Error())
+		}, statusError(err, codes.Internal)
 	}
 
 	// Convert to protobuf user
@@ -467,7 +467,7 @@ func (h *CombinedUserHandler) DeleteUser(ctx context.Context, req *pb.DeleteUser
 		return &pb.DeleteUserResponse{
 			StatusCode: 500,
 			Message:    "Internal server error",
-		}, status.Error(codes.Internal, err.Error())
+		}, statusError(err, codes.Internal)
 	}
 
 	return &pb.DeleteUserResponse{
@@ -552,7 +552,7 @@ func (h *CombinedUserHandler) Logout(ctx context.Context, req *pb.LogoutRequest)
 		return &pb.LogoutResponse{
 			StatusCode: 500,
 			Message:    "Logout failed",
-		}, status.Error(codes.Internal, err.Error())
+		}, statusError(err, codes.Internal)
 	}
 
 	grpcResponse := &pb.LogoutResponse{
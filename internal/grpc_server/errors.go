@@ -0,0 +1,190 @@
+package grpc_server
+
+import (
+	"context"
+	stdErrors "errors"
+	"fmt"
+	"strings"
+
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+// errorDomain is the ErrorInfo domain of the errors this service returns
+const errorDomain = "aaa-service.kisanlink.com"
+
+// statusError converts an error returned by the service layer into a gRPC status error carrying
+// the same stable error code the HTTP API returns. Errors of the pkg/errors types, also when
+// wrapped, get the status code of their type and a google.rpc.ErrorInfo naming their code;
+// validation and bad request errors add google.rpc.BadRequest field violations. Other errors get
+// the fallback status code. Status errors are returned as they are.
+func statusError(err error, fallback codes.Code) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	typed := typedError(err)
+	if typed == nil {
+		return detailedStatus(fallback, err.Error(), errorInfo(errorCodeForStatus(fallback), nil), nil)
+	}
+
+	code := errors.CodeOf(typed)
+	switch e := typed.(type) {
+	case *errors.ValidationError:
+		return detailedStatus(codes.InvalidArgument, err.Error(), errorInfo(code, nil), badRequestDetails(e.Details()))
+	case *errors.BadRequestError:
+		if code == errors.CodeRateLimited {
+			return detailedStatus(codes.ResourceExhausted, err.Error(), errorInfo(code, nil), nil)
+		}
+		return detailedStatus(codes.InvalidArgument, err.Error(), errorInfo(code, nil), badRequestDetails(e.Details()))
+	case *errors.UnauthorizedError:
+		return detailedStatus(codes.Unauthenticated, err.Error(), errorInfo(code, nil), nil)
+	case *errors.ForbiddenError:
+		return detailedStatus(codes.PermissionDenied, err.Error(), errorInfo(code, nil), nil)
+	case *errors.NotFoundError:
+		return detailedStatus(codes.NotFound, err.Error(), errorInfo(code, nil), nil)
+	case *errors.ConflictError:
+		return detailedStatus(codes.AlreadyExists, err.Error(), errorInfo(code, nil), nil)
+	case *errors.OptimisticLockError:
+		metadata := make(map[string]string, len(e.Details()))
+		for key, value := range e.Details() {
+			metadata[key] = fmt.Sprint(value)
+		}
+		return detailedStatus(codes.Aborted, err.Error(), errorInfo(code, metadata), nil)
+	default:
+		// Never expose internal error details, as the HTTP API doesn't
+		return detailedStatus(codes.Internal, "an internal server error occurred", errorInfo(code, nil), nil)
+	}
+}
+
+// errorDetailsUnaryInterceptor gives every error a unary handler returns the error details of
+// statusError: service errors are converted, and status errors without details get an ErrorInfo
+// with the generic code of their status code
+func errorDetailsUnaryInterceptor(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	resp, err := handler(ctx, req)
+	return resp, withErrorDetails(err)
+}
+
+// errorDetailsStreamInterceptor is errorDetailsUnaryInterceptor for streaming RPCs
+func errorDetailsStreamInterceptor(
+	srv interface{},
+	ss grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	return withErrorDetails(handler(srv, ss))
+}
+
+func withErrorDetails(err error) error {
+	if err == nil {
+		return nil
+	}
+	st, ok := status.FromError(err)
+	if !ok {
+		return statusError(err, codes.Unknown)
+	}
+	if len(st.Details()) > 0 {
+		return err
+	}
+	return detailedStatus(st.Code(), st.Message(), errorInfo(errorCodeForStatus(st.Code()), nil), nil)
+}
+
+// typedError returns the first error of a pkg/errors type in the chain of err
+func typedError(err error) error {
+	for ; err != nil; err = stdErrors.Unwrap(err) {
+		if errors.CodeOf(err) != errors.CodeUnknown {
+			return err
+		}
+	}
+	return nil
+}
+
+// errorCodeForStatus returns the generic error code of a gRPC status code, for status errors
+// created without a typed error
+func errorCodeForStatus(code codes.Code) errors.ErrorCode {
+	switch code {
+	case codes.InvalidArgument, codes.OutOfRange, codes.FailedPrecondition:
+		return errors.CodeBadRequest
+	case codes.Unauthenticated:
+		return errors.CodeUnauthorized
+	case codes.PermissionDenied:
+		return errors.CodeForbidden
+	case codes.NotFound:
+		return errors.CodeNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return errors.CodeConflict
+	case codes.ResourceExhausted:
+		return errors.CodeRateLimited
+	case codes.DeadlineExceeded:
+		return errors.CodeRequestTimeout
+	case codes.Internal, codes.Unavailable, codes.DataLoss, codes.Unimplemented:
+		return errors.CodeInternal
+	default:
+		return errors.CodeUnknown
+	}
+}
+
+// badRequestDetails reports validation details as field violations. Details of the form
+// "field: description" name their field; others describe the request as a whole.
+func badRequestDetails(details []string) *errdetails.BadRequest {
+	if len(details) == 0 {
+		return nil
+	}
+	badRequest := &errdetails.BadRequest{}
+	for _, detail := range details {
+		violation := &errdetails.BadRequest_FieldViolation{Description: detail}
+		if field, description, ok := strings.Cut(detail, ": "); ok && field != "" && !strings.ContainsAny(field, " \t") {
+			violation.Field = field
+			violation.Description = description
+		}
+		badRequest.FieldViolations = append(badRequest.FieldViolations, violation)
+	}
+	return badRequest
+}
+
+// detailedStatus creates a status error with an ErrorInfo and, when there are field violations, a
+// BadRequest. Should the details fail to encode, the status is returned without them.
+func detailedStatus(code codes.Code, message string, info *errdetails.ErrorInfo, badRequest *errdetails.BadRequest) error {
+	details := []protoadapt.MessageV1{info}
+	if badRequest != nil {
+		details = append(details, badRequest)
+	}
+
+	st := status.New(code, message)
+	detailed, err := st.WithDetails(details...)
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
+
+// errorInfo names an error code the way the HTTP error-code catalog does: the reason is the
+// upper-cased catalog name and the code is in the metadata
+func errorInfo(code errors.ErrorCode, metadata map[string]string) *errdetails.ErrorInfo {
+	reason := strings.ToUpper(string(code))
+	if entry, ok := errors.LookupCode(code); ok {
+		reason = strings.ToUpper(entry.Name)
+	}
+
+	info := &errdetails.ErrorInfo{
+		Reason:   reason,
+		Domain:   errorDomain,
+		Metadata: map[string]string{"code": string(code)},
+	}
+	for key, value := range metadata {
+		info.Metadata[key] = value
+	}
+	return info
+}
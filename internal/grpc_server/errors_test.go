@@ -0,0 +1,129 @@
+package grpc_server
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errorDetailsOf returns the ErrorInfo and BadRequest details of a status error
+func errorDetailsOf(t *testing.T, err error) (*status.Status, *errdetails.ErrorInfo, *errdetails.BadRequest) {
+	t.Helper()
+	st, ok := status.FromError(err)
+	require.True(t, ok, "expected a status error, got %v", err)
+
+	var info *errdetails.ErrorInfo
+	var badRequest *errdetails.BadRequest
+	for _, detail := range st.Details() {
+		switch d := detail.(type) {
+		case *errdetails.ErrorInfo:
+			info = d
+		case *errdetails.BadRequest:
+			badRequest = d
+		}
+	}
+	require.NotNil(t, info, "every error carries an ErrorInfo")
+	assert.Equal(t, errorDomain, info.Domain)
+	return st, info, badRequest
+}
+
+func TestStatusError_ServiceErrorTypes(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantCode   codes.Code
+		wantReason string
+		wantError  errors.ErrorCode
+	}{
+		{"validation", errors.NewValidationError("invalid user data"), codes.InvalidArgument, "VALIDATION_FAILED", errors.CodeValidationFailed},
+		{"bad request", errors.NewBadRequestError("malformed filter"), codes.InvalidArgument, "BAD_REQUEST", errors.CodeBadRequest},
+		{"rate limited", errors.NewRateLimitError("60"), codes.ResourceExhausted, "RATE_LIMITED", errors.CodeRateLimited},
+		{"unauthorized", errors.NewTokenExpiredError(), codes.Unauthenticated, "TOKEN_EXPIRED", errors.CodeTokenExpired},
+		{"forbidden", errors.NewForbiddenError("not allowed"), codes.PermissionDenied, "FORBIDDEN", errors.CodeForbidden},
+		{"not found", errors.NewNotFoundError("role not found"), codes.NotFound, "ROLE_NOT_FOUND", errors.CodeRoleNotFound},
+		{"conflict", errors.NewConflictError("role already assigned to user"), codes.AlreadyExists, "CONFLICT", errors.CodeConflict},
+		{"version conflict", errors.NewOptimisticLockError("user", "USR1", 2, 3), codes.Aborted, "VERSION_CONFLICT", errors.CodeVersionConflict},
+		{"internal", errors.NewInternalError(fmt.Errorf("connection refused")), codes.Internal, "INTERNAL_ERROR", errors.CodeInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st, info, _ := errorDetailsOf(t, statusError(tt.err, codes.Unknown))
+
+			assert.Equal(t, tt.wantCode, st.Code())
+			assert.Equal(t, tt.wantReason, info.Reason)
+			assert.Equal(t, string(tt.wantError), info.Metadata["code"])
+		})
+	}
+}
+
+func TestStatusError_ValidationFieldViolations(t *testing.T) {
+	err := errors.NewValidationError("invalid user attributes", "district: must be a string", "at least one attribute is required")
+
+	st, _, badRequest := errorDetailsOf(t, statusError(err, codes.Internal))
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+	require.NotNil(t, badRequest)
+	require.Len(t, badRequest.FieldViolations, 2)
+	assert.Equal(t, "district", badRequest.FieldViolations[0].Field)
+	assert.Equal(t, "must be a string", badRequest.FieldViolations[0].Description)
+	assert.Empty(t, badRequest.FieldViolations[1].Field)
+	assert.Equal(t, "at least one attribute is required", badRequest.FieldViolations[1].Description)
+
+	_, _, badRequest = errorDetailsOf(t, statusError(errors.NewValidationError("role ID is required"), codes.Internal))
+	assert.Nil(t, badRequest, "validation errors without details have no field violations")
+}
+
+func TestStatusError_HidesInternalDetails(t *testing.T) {
+	st, _, _ := errorDetailsOf(t, statusError(errors.NewInternalError(fmt.Errorf("pq: password authentication failed")), codes.Internal))
+	assert.NotContains(t, st.Message(), "password")
+}
+
+func TestStatusError_WrappedAndUntypedErrors(t *testing.T) {
+	wrapped := fmt.Errorf("failed to get role: %w", errors.NewNotFoundError("role not found"))
+	st, info, _ := errorDetailsOf(t, statusError(wrapped, codes.Internal))
+	assert.Equal(t, codes.NotFound, st.Code(), "typed errors are found through wrapping")
+	assert.Equal(t, string(errors.CodeRoleNotFound), info.Metadata["code"])
+	assert.Equal(t, wrapped.Error(), st.Message())
+
+	st, info, _ = errorDetailsOf(t, statusError(fmt.Errorf("database unavailable"), codes.Internal))
+	assert.Equal(t, codes.Internal, st.Code(), "untyped errors keep the fallback code")
+	assert.Equal(t, string(errors.CodeInternal), info.Metadata["code"])
+	assert.Equal(t, "database unavailable", st.Message())
+
+	existing := status.Error(codes.FailedPrecondition, "organization is not active")
+	assert.Equal(t, existing, statusError(existing, codes.Internal), "status errors are returned as they are")
+
+	assert.NoError(t, statusError(nil, codes.Internal))
+}
+
+func TestErrorDetailsUnaryInterceptor(t *testing.T) {
+	info := &grpc.UnaryServerInfo{FullMethod: "/pb.RoleService/AssignRole"}
+	call := func(err error) error {
+		_, got := errorDetailsUnaryInterceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, err
+		})
+		return got
+	}
+
+	st, details, _ := errorDetailsOf(t, call(status.Error(codes.InvalidArgument, "user_id is required")))
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+	assert.Equal(t, "user_id is required", st.Message())
+	assert.Equal(t, string(errors.CodeBadRequest), details.Metadata["code"])
+
+	st, details, _ = errorDetailsOf(t, call(errors.NewConflictError("role already assigned to user")))
+	assert.Equal(t, codes.AlreadyExists, st.Code(), "service errors returned as they are are converted")
+	assert.Equal(t, string(errors.CodeConflict), details.Metadata["code"])
+
+	detailed := statusError(errors.NewForbiddenError("not allowed"), codes.Internal)
+	assert.Equal(t, detailed, call(detailed), "errors with details are kept")
+
+	assert.NoError(t, call(nil))
+}
@@ -56,7 +56,7 @@ func (h *GroupHandler) CreateGroup(ctx context.Context, req *pb.CreateGroupReque
 	result, err := h.groupService.CreateGroup(ctx, serviceReq)
 	if err != nil {
 		h.logger.Error("Failed to create group", zap.Error(err))
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, statusError(err, codes.Internal)
 	}
 
 	// Convert response
@@ -89,7 +89,7 @@ func (h *GroupHandler) GetGroup(ctx context.Context, req *pb.GetGroupRequest) (*
 	result, err := h.groupService.GetGroup(ctx, req.Id)
 	if err != nil {
 		h.logger.Error("Failed to get group", zap.Error(err))
-		return nil, status.Error(codes.NotFound, err.Error())
+		return nil, statusError(err, codes.NotFound)
 	}
 
 	groupResp, ok := result.(*groupResponses.GroupResponse)
@@ -125,7 +125,7 @@ func (h *GroupHandler) ListGroups(ctx context.Context, req *pb.ListGroupsRequest
 	result, err := h.groupService.ListGroups(ctx, limit, offset, req.OrganizationId, req.IncludeInactive)
 	if err != nil {
 		h.logger.Error("Failed to list groups", zap.Error(err))
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, statusError(err, codes.Internal)
 	}
 
 	groups, ok := result.([]*groupResponses.GroupResponse)
@@ -215,7 +215,7 @@ func (h *GroupHandler) AddGroupMember(ctx context.Context, req *pb.AddGroupMembe
 		return &pb.AddGroupMemberResponse{
 			StatusCode: 500,
 			Message:    err.Error(),
-		}, status.Error(codes.Internal, err.Error())
+		}, statusError(err, codes.Internal)
 	}
 
 	memberResp, ok := result.(*groupResponses.GroupMembershipResponse)
@@ -269,7 +269,7 @@ func (h *GroupHandler) RemoveGroupMember(ctx context.Context, req *pb.RemoveGrou
 		return &pb.RemoveGroupMemberResponse{
 			StatusCode: 500,
 			Message:    err.Error(),
-		}, status.Error(codes.Internal, err.Error())
+		}, statusError(err, codes.Internal)
 	}
 
 	h.logger.Info("Member removed from group successfully",
@@ -299,7 +299,7 @@ func (h *GroupHandler) ListGroupMembers(ctx context.Context, req *pb.ListGroupMe
 	result, err := h.groupService.GetGroupMembers(ctx, req.GroupId, limit, offset)
 	if err != nil {
 		h.logger.Error("Failed to list group members", zap.Error(err))
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, statusError(err, codes.Internal)
 	}
 
 	members, ok := result.([]*groupResponses.GroupMembershipResponse)
@@ -342,7 +342,7 @@ func (h *GroupHandler) UpdateGroup(ctx context.Context, req *pb.UpdateGroupReque
 	result, err := h.groupService.UpdateGroup(ctx, req.Id, serviceReq)
 	if err != nil {
 		h.logger.Error("Failed to update group", zap.Error(err))
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, statusError(err, codes.Internal)
 	}
 
 	groupResp, ok := result.(*groupResponses.GroupResponse)
@@ -372,7 +372,7 @@ func (h *GroupHandler) DeleteGroup(ctx context.Context, req *pb.DeleteGroupReque
 	err := h.groupService.DeleteGroup(ctx, req.Id, "system")
 	if err != nil {
 		h.logger.Error("Failed to delete group", zap.Error(err))
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, statusError(err, codes.Internal)
 	}
 
 	return &pb.DeleteGroupResponse{
@@ -429,7 +429,7 @@ func (h *GroupHandler) AssignRoleToGroup(ctx context.Context, req *pb.AssignRole
 		return &pb.AssignRoleToGroupResponse{
 			StatusCode: 500,
 			Message:    err.Error(),
-		}, status.Error(codes.Internal, err.Error())
+		}, statusError(err, codes.Internal)
 	}
 
 	groupRoleResp, ok := result.(*groupResponses.GroupRoleResponse)
@@ -483,7 +483,7 @@ func (h *GroupHandler) RemoveRoleFromGroup(ctx context.Context, req *pb.RemoveRo
 		return &pb.RemoveRoleFromGroupResponse{
 			StatusCode: 500,
 			Message:    err.Error(),
-		}, status.Error(codes.Internal, err.Error())
+		}, statusError(err, codes.Internal)
 	}
 
 	h.logger.Info("Role removed from group successfully",
@@ -514,7 +514,7 @@ func (h *GroupHandler) GetGroupRoles(ctx context.Context, req *pb.GetGroupRolesR
 		return &pb.GetGroupRolesResponse{
 			StatusCode: 500,
 			Message:    err.Error(),
-		}, status.Error(codes.Internal, err.Error())
+		}, statusError(err, codes.Internal)
 	}
 
 	roles, ok := result.([]*groupResponses.GroupRoleDetail)
@@ -2,6 +2,7 @@ package grpc_server
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
 	"time"
@@ -10,6 +11,7 @@ import (
 	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
 	"github.com/Kisanlink/aaa-service/v2/internal/middleware"
 	auditRepo "github.com/Kisanlink/aaa-service/v2/internal/repositories/audit"
+	"github.com/Kisanlink/aaa-service/v2/internal/security"
 	"github.com/Kisanlink/aaa-service/v2/internal/services"
 	"github.com/Kisanlink/aaa-service/v2/internal/services/catalog"
 	pb "github.com/Kisanlink/aaa-service/v2/pkg/proto"
@@ -17,6 +19,8 @@ import (
 	"github.com/Kisanlink/kisanlink-db/pkg/db"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 	"gorm.io/gorm"
 )
@@ -24,6 +28,7 @@ import (
 // GRPCServer represents the gRPC server with authentication and authorization
 type GRPCServer struct {
 	server              *grpc.Server
+	health              *health.Server
 	logger              *zap.Logger
 	authService         *services.AuthService
 	authzService        *services.AuthorizationService
@@ -37,6 +42,7 @@ type GRPCServer struct {
 	addressService      interfaces.AddressService
 	serviceRepository   interfaces.ServiceRepository
 	dbManager           db.DBManager
+	signingKeys         *security.SigningKeyRing
 	port                string
 	listener            net.Listener
 }
@@ -147,6 +153,12 @@ func NewGRPCServer(
 	}, nil
 }
 
+// SetSigningKeys signs and verifies the tokens of gRPC calls with the RS256 keys of keys
+func (s *GRPCServer) SetSigningKeys(keys *security.SigningKeyRing) {
+	s.signingKeys = keys
+	s.authService.SetSigningKeys(keys)
+}
+
 // Start starts the enhanced gRPC server
 func (s *GRPCServer) Start() error {
 	lis, err := net.Listen("tcp", ":"+s.port)
@@ -158,13 +170,28 @@ func (s *GRPCServer) Start() error {
 	// Create gRPC server with interceptors
 	// Build unified auth middleware for gRPC
 	jwtCfg := cfg.LoadJWTConfigFromEnv()
-	authMW := middleware.NewAuthMiddleware(s.authService, s.authzService, s.auditService, s.serviceRepository, s.logger, middleware.NewHS256Verifier(), jwtCfg)
+	var verifier middleware.JWTVerifier = middleware.NewHS256Verifier()
+	if s.signingKeys != nil {
+		verifier = middleware.NewSigningKeyVerifier(s.signingKeys)
+	}
+	authMW := middleware.NewAuthMiddleware(s.authService, s.authzService, s.auditService, s.serviceRepository, s.logger, verifier, jwtCfg)
+
+	// Auditing runs before authentication so rejected calls are audited as well
+	auditor := newRPCAuditor(s.auditService)
 
+	// Error details are added outside auditing and authentication so every error a client
+	// receives carries the stable error code
 	opts := []grpc.ServerOption{
 		grpc.ChainUnaryInterceptor(
 			s.loggingInterceptor,
+			errorDetailsUnaryInterceptor,
+			auditor.unary,
 			authMW.GRPCAuthInterceptor(),
-			s.auditInterceptor,
+			auditor.recordPrincipal,
+		),
+		grpc.ChainStreamInterceptor(
+			errorDetailsStreamInterceptor,
+			auditor.stream,
 		),
 	}
 
@@ -173,6 +200,10 @@ func (s *GRPCServer) Start() error {
 	// Register services
 	s.registerServices()
 
+	// Register the standard health service so load balancers can drain the server on shutdown
+	s.health = health.NewServer()
+	healthpb.RegisterHealthServer(s.server, s.health)
+
 	// Enable reflection for development
 	reflection.Register(s.server)
 
@@ -188,14 +219,31 @@ func (s *GRPCServer) Start() error {
 	return nil
 }
 
-// Stop gracefully stops the gRPC server
-func (s *GRPCServer) Stop() {
+// Stop gracefully stops the gRPC server. The health service reports NOT_SERVING first so
+// load balancers stop routing new calls, then in-flight calls are allowed to finish until ctx
+// is done, after which the server is stopped hard.
+func (s *GRPCServer) Stop(ctx context.Context) {
 	s.logger.Info("Stopping enhanced gRPC server")
+	if s.health != nil {
+		s.health.Shutdown()
+	}
 	if s.server != nil {
-		s.server.GracefulStop()
+		stopped := make(chan struct{})
+		go func() {
+			s.server.GracefulStop()
+			close(stopped)
+		}()
+
+		select {
+		case <-stopped:
+			s.logger.Info("gRPC server stopped gracefully")
+		case <-ctx.Done():
+			s.logger.Warn("gRPC graceful stop timed out, forcing shutdown", zap.Error(ctx.Err()))
+			s.server.Stop()
+		}
 	}
 	if s.listener != nil {
-		if err := s.listener.Close(); err != nil {
+		if err := s.listener.Close(); err != nil && !errors.Is(err, net.ErrClosed) {
 			s.logger.Warn("Failed to close listener", zap.Error(err))
 		}
 	}
@@ -233,6 +281,10 @@ func (s *GRPCServer) registerServices() {
 	)
 	pb.RegisterTokenServiceServer(s.server, tokenHandler)
 
+	// Register IdentityService so authenticated callers can inspect their own identity
+	identityHandler := NewIdentityHandler(s.logger)
+	pb.RegisterIdentityServiceServer(s.server, identityHandler)
+
 	// Register OrganizationService for organization management
 	orgHandler := NewOrganizationHandler(
 		s.organizationService,
@@ -273,7 +325,7 @@ func (s *GRPCServer) registerServices() {
 
 	s.logger.Info("gRPC services registered successfully",
 		zap.String("primary_service", "AAAService"),
-		zap.Strings("services", []string{"UserServiceV2", "AuthorizationService", "TokenService", "OrganizationService", "CatalogService", "AddressService", "AddressServiceV2", "RoleService", "GroupService"}))
+		zap.Strings("services", []string{"UserServiceV2", "AuthorizationService", "TokenService", "IdentityService", "OrganizationService", "CatalogService", "AddressService", "AddressServiceV2", "RoleService", "GroupService"}))
 }
 
 // loggingInterceptor logs gRPC requests
@@ -306,42 +358,6 @@ func (s *GRPCServer) loggingInterceptor(
 	return resp, err
 }
 
-// auditInterceptor logs gRPC requests for audit purposes
-func (s *GRPCServer) auditInterceptor(
-	ctx context.Context,
-	req interface{},
-	info *grpc.UnaryServerInfo,
-	handler grpc.UnaryHandler,
-) (interface{}, error) {
-	// Extract user ID from context (set by auth interceptor)
-	userID := "unknown"
-	if userIDValue := ctx.Value("user_id"); userIDValue != nil {
-		if uid, ok := userIDValue.(string); ok {
-			userID = uid
-		}
-	}
-
-	// Call the handler
-	resp, err := handler(ctx, req)
-
-	// Log to audit service
-	success := err == nil
-	if s.auditService != nil {
-		if err != nil {
-			s.auditService.LogUserActionWithError(ctx, userID, "grpc_call", "grpc", info.FullMethod, err, map[string]interface{}{
-				"method": info.FullMethod,
-			})
-		} else {
-			s.auditService.LogUserAction(ctx, userID, "grpc_call", "grpc", info.FullMethod, map[string]interface{}{
-				"method": info.FullMethod,
-			})
-		}
-	}
-
-	_ = success // Mark as used
-	return resp, err
-}
-
 // GetServer returns the underlying gRPC server
 func (s *GRPCServer) GetServer() *grpc.Server {
 	return s.server
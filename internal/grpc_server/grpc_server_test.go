@@ -0,0 +1,130 @@
+package grpc_server
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// slowService is a minimal unary service whose only method blocks until released
+type slowService struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (s *slowService) desc() *grpc.ServiceDesc {
+	return &grpc.ServiceDesc{
+		ServiceName: "test.SlowService",
+		HandlerType: (*interface{})(nil),
+		Methods: []grpc.MethodDesc{{
+			MethodName: "Wait",
+			Handler: func(_ interface{}, ctx context.Context, dec func(interface{}) error, _ grpc.UnaryServerInterceptor) (interface{}, error) {
+				in := new(emptypb.Empty)
+				if err := dec(in); err != nil {
+					return nil, err
+				}
+				close(s.started)
+				select {
+				case <-s.release:
+					return &emptypb.Empty{}, nil
+				case <-ctx.Done():
+					return nil, ctx.Err()
+				}
+			},
+		}},
+	}
+}
+
+// startStopTestServer serves the slow service and the health service on a loopback listener
+func startStopTestServer(t *testing.T, slow *slowService) (*GRPCServer, *grpc.ClientConn) {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	s := &GRPCServer{
+		server:   grpc.NewServer(),
+		health:   health.NewServer(),
+		logger:   zap.NewNop(),
+		listener: lis,
+	}
+	s.server.RegisterService(slow.desc(), struct{}{})
+	healthpb.RegisterHealthServer(s.server, s.health)
+	go func() { _ = s.server.Serve(lis) }()
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return s, conn
+}
+
+func TestGRPCServer_Stop_DrainsInFlightCalls(t *testing.T) {
+	slow := &slowService{started: make(chan struct{}), release: make(chan struct{})}
+	s, conn := startStopTestServer(t, slow)
+
+	callErr := make(chan error, 1)
+	go func() {
+		callErr <- conn.Invoke(context.Background(), "/test.SlowService/Wait", &emptypb.Empty{}, &emptypb.Empty{})
+	}()
+	<-slow.started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stopped := make(chan struct{})
+	go func() {
+		s.Stop(ctx)
+		close(stopped)
+	}()
+
+	// The health service flips to NOT_SERVING while the call is still in flight
+	require.Eventually(t, func() bool {
+		resp, err := s.health.Check(context.Background(), &healthpb.HealthCheckRequest{})
+		return err == nil && resp.Status == healthpb.HealthCheckResponse_NOT_SERVING
+	}, time.Second, 10*time.Millisecond)
+
+	select {
+	case <-stopped:
+		t.Fatal("Stop returned before the in-flight call finished")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(slow.release)
+	assert.NoError(t, <-callErr, "a call started before shutdown completes successfully")
+
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Stop did not return after the in-flight call finished")
+	}
+}
+
+func TestGRPCServer_Stop_ForcesShutdownAfterDeadline(t *testing.T) {
+	slow := &slowService{started: make(chan struct{}), release: make(chan struct{})}
+	defer close(slow.release)
+	s, conn := startStopTestServer(t, slow)
+
+	callErr := make(chan error, 1)
+	go func() {
+		callErr <- conn.Invoke(context.Background(), "/test.SlowService/Wait", &emptypb.Empty{}, &emptypb.Empty{})
+	}()
+	<-slow.started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	s.Stop(ctx)
+	assert.Less(t, time.Since(start), 5*time.Second, "Stop falls back to a hard stop once the deadline passes")
+	assert.Error(t, <-callErr, "a call still running at the deadline is cancelled")
+}
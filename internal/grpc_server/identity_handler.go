@@ -0,0 +1,102 @@
+package grpc_server
+
+import (
+	"context"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	pb "github.com/Kisanlink/aaa-service/v2/pkg/proto"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// IdentityHandler implements the IdentityService gRPC service
+type IdentityHandler struct {
+	pb.UnimplementedIdentityServiceServer
+	logger *zap.Logger
+}
+
+// NewIdentityHandler creates a new IdentityHandler instance
+func NewIdentityHandler(logger *zap.Logger) *IdentityHandler {
+	return &IdentityHandler{
+		logger: logger,
+	}
+}
+
+// WhoAmI returns the identity the auth interceptor established for the caller. Service
+// principals are reported with their service ID; user tokens with the user ID, roles and the
+// token's permissions as scopes.
+func (h *IdentityHandler) WhoAmI(ctx context.Context, req *pb.WhoAmIRequest) (*pb.WhoAmIResponse, error) {
+	if contextString(ctx, "principal_type") == "service" {
+		serviceID := contextString(ctx, "service_id")
+		response := &pb.WhoAmIResponse{
+			StatusCode:    200,
+			Message:       "Caller identified",
+			PrincipalType: "service",
+			PrincipalId:   serviceID,
+			ServiceName:   contextString(ctx, "service_name"),
+		}
+		// The interceptor replaces user_id with the acting user when a user token accompanies the API key
+		if userID := contextString(ctx, "user_id"); userID != "" && userID != serviceID {
+			response.ActingUserId = userID
+		}
+
+		h.logger.Debug("gRPC WhoAmI request", zap.String("service_id", serviceID))
+		return response, nil
+	}
+
+	userID := contextString(ctx, "user_id")
+	if userID == "" {
+		return &pb.WhoAmIResponse{
+			StatusCode: 401,
+			Message:    "Caller is not authenticated",
+		}, status.Error(codes.Unauthenticated, "no authenticated principal found in context")
+	}
+
+	response := &pb.WhoAmIResponse{
+		StatusCode:    200,
+		Message:       "Caller identified",
+		PrincipalType: "user",
+		PrincipalId:   userID,
+		Username:      contextString(ctx, "username"),
+		Roles:         roleNames(ctx.Value("roles")),
+	}
+	if validated, ok := ctx.Value("is_validated").(bool); ok {
+		response.IsValidated = validated
+	}
+	if permissions, ok := ctx.Value("permissions").([]string); ok {
+		response.Scopes = permissions
+	}
+
+	h.logger.Debug("gRPC WhoAmI request", zap.String("user_id", userID))
+	return response, nil
+}
+
+// contextString safely extracts a string value set by the auth interceptor
+func contextString(ctx context.Context, key string) string {
+	if value, ok := ctx.Value(key).(string); ok {
+		return value
+	}
+	return ""
+}
+
+// roleNames returns the names of the active roles carried in the token claims
+func roleNames(value interface{}) []string {
+	userRoles, ok := value.([]*models.UserRole)
+	if !ok {
+		return nil
+	}
+
+	names := make([]string, 0, len(userRoles))
+	for _, userRole := range userRoles {
+		if userRole == nil || !userRole.IsActive {
+			continue
+		}
+		if userRole.Role.Name != "" {
+			names = append(names, userRole.Role.Name)
+		} else {
+			names = append(names, userRole.RoleID)
+		}
+	}
+	return names
+}
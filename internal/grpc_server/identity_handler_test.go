@@ -0,0 +1,71 @@
+package grpc_server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	pb "github.com/Kisanlink/aaa-service/v2/pkg/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestIdentityHandler_WhoAmI_User(t *testing.T) {
+	handler := NewIdentityHandler(zap.NewNop())
+
+	ctx := context.WithValue(context.Background(), "user_id", "USR123")
+	ctx = context.WithValue(ctx, "username", "farmer1")
+	ctx = context.WithValue(ctx, "is_validated", true)
+	ctx = context.WithValue(ctx, "roles", []*models.UserRole{
+		{RoleID: "ROL1", IsActive: true, Role: models.Role{Name: "admin"}},
+		{RoleID: "ROL2", IsActive: false, Role: models.Role{Name: "viewer"}},
+		{RoleID: "ROL3", IsActive: true},
+	})
+	ctx = context.WithValue(ctx, "permissions", []string{"user:read", "user:update"})
+
+	resp, err := handler.WhoAmI(ctx, &pb.WhoAmIRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, int32(200), resp.StatusCode)
+	assert.Equal(t, "user", resp.PrincipalType)
+	assert.Equal(t, "USR123", resp.PrincipalId)
+	assert.Equal(t, "farmer1", resp.Username)
+	assert.True(t, resp.IsValidated)
+	assert.Equal(t, []string{"admin", "ROL3"}, resp.Roles)
+	assert.Equal(t, []string{"user:read", "user:update"}, resp.Scopes)
+	assert.Empty(t, resp.ActingUserId)
+}
+
+func TestIdentityHandler_WhoAmI_Service(t *testing.T) {
+	handler := NewIdentityHandler(zap.NewNop())
+
+	ctx := context.WithValue(context.Background(), "principal_type", "service")
+	ctx = context.WithValue(ctx, "service_id", "SVC1")
+	ctx = context.WithValue(ctx, "service_name", "farmers-module")
+	ctx = context.WithValue(ctx, "user_id", "SVC1")
+
+	resp, err := handler.WhoAmI(ctx, &pb.WhoAmIRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, "service", resp.PrincipalType)
+	assert.Equal(t, "SVC1", resp.PrincipalId)
+	assert.Equal(t, "farmers-module", resp.ServiceName)
+	assert.Empty(t, resp.ActingUserId)
+	assert.Empty(t, resp.Roles)
+
+	// A user token sent with the API key identifies the acting user
+	resp, err = handler.WhoAmI(context.WithValue(ctx, "user_id", "USR123"), &pb.WhoAmIRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, "SVC1", resp.PrincipalId)
+	assert.Equal(t, "USR123", resp.ActingUserId)
+}
+
+func TestIdentityHandler_WhoAmI_Unauthenticated(t *testing.T) {
+	handler := NewIdentityHandler(zap.NewNop())
+
+	resp, err := handler.WhoAmI(context.Background(), &pb.WhoAmIRequest{})
+	require.Error(t, err)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	assert.Equal(t, int32(401), resp.StatusCode)
+}
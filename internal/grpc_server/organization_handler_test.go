@@ -5,6 +5,7 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
 	pb "github.com/Kisanlink/aaa-service/v2/pkg/proto"
 	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
@@ -68,6 +69,18 @@ func (m *mockOrganizationService) GetOrganizationHierarchy(ctx context.Context,
 	return nil, errors.New("not implemented")
 }
 
+func (m *mockOrganizationService) GetOrganizationAncestors(ctx context.Context, orgID string, maxDepth, limit, offset int) (interface{}, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockOrganizationService) GetOrganizationDescendants(ctx context.Context, orgID string, maxDepth, limit, offset int) (interface{}, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockOrganizationService) GetGroupChildren(ctx context.Context, orgID, parentGroupID string, limit, offset int) (interface{}, error) {
+	return nil, errors.New("not implemented")
+}
+
 func (m *mockOrganizationService) ActivateOrganization(ctx context.Context, orgID string) error {
 	return errors.New("not implemented")
 }
@@ -120,6 +133,50 @@ func (m *mockOrganizationService) GetGroupRolesInOrganization(ctx context.Contex
 	return nil, errors.New("not implemented")
 }
 
+func (m *mockOrganizationService) AddUserToOrganization(ctx context.Context, orgID string, req interface{}, addedBy string) (interface{}, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockOrganizationService) RemoveUserFromOrganization(ctx context.Context, orgID, userID string, removedBy string) (interface{}, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockOrganizationService) ListOrganizationMembers(ctx context.Context, orgID string, limit, offset int) (interface{}, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockOrganizationService) GetOrganizationSettings(ctx context.Context, orgID string) (interface{}, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockOrganizationService) UpdateOrganizationSettings(ctx context.Context, orgID string, req interface{}, updatedBy string) (interface{}, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockOrganizationService) GetSetting(ctx context.Context, orgID, key string) (interface{}, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockOrganizationService) ListRoleTemplates(ctx context.Context) (interface{}, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockOrganizationService) CreateRoleTemplate(ctx context.Context, req interface{}, createdBy string) (interface{}, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockOrganizationService) DeleteRoleTemplate(ctx context.Context, name string, deletedBy string) error {
+	return errors.New("not implemented")
+}
+
+func (m *mockOrganizationService) ApplyRoleTemplate(ctx context.Context, orgID, templateName, appliedBy string) (interface{}, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockOrganizationService) ListAvailableRoles(ctx context.Context, orgID string) (interface{}, error) {
+	return nil, errors.New("not implemented")
+}
+
 func (m *mockOrganizationService) GetUserEffectiveRolesInOrganization(ctx context.Context, orgID, userID string) (interface{}, error) {
 	return nil, errors.New("not implemented")
 }
@@ -128,6 +185,38 @@ func (m *mockOrganizationService) CountOrganizations(ctx context.Context, includ
 	return 0, errors.New("not implemented")
 }
 
+func (m *mockOrganizationService) RequestOrganizationHardDelete(ctx context.Context, orgID string, requestedBy string) (interface{}, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockOrganizationService) HardDeleteOrganization(ctx context.Context, orgID string, deletedBy string, confirmationToken string) error {
+	return errors.New("not implemented")
+}
+
+func (m *mockOrganizationService) DeleteOrganizationCascade(ctx context.Context, orgID string, deletedBy string) (interface{}, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockOrganizationService) RestoreOrganization(ctx context.Context, orgID string, restoredBy string) (interface{}, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockOrganizationService) RestoreOrganizationCascade(ctx context.Context, orgID string, restoredBy string) (interface{}, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockOrganizationService) MergeOrganizations(ctx context.Context, sourceID, targetID, mergedBy string) (interface{}, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockOrganizationService) ListOrganizationsWithDeleted(ctx context.Context, limit, offset int) ([]interface{}, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockOrganizationService) CountOrganizationsWithDeleted(ctx context.Context) (int64, error) {
+	return 0, errors.New("not implemented")
+}
+
 // Mock GroupService
 type mockGroupService struct {
 	getUserGroupsFunc         func(ctx context.Context, orgID, userID string, limit, offset int) (interface{}, error)
@@ -156,6 +245,10 @@ func (m *mockGroupService) GetUserEffectiveRoles(ctx context.Context, orgID, use
 	return []interface{}{}, nil
 }
 
+func (m *mockGroupService) GetUserScopedEffectiveRoles(ctx context.Context, orgID, userID string, scope interfaces.EffectiveRoleScope) (interface{}, error) {
+	return nil, errors.New("not implemented")
+}
+
 // Implement remaining interface methods as stubs
 func (m *mockGroupService) CreateGroup(ctx context.Context, req interface{}) (interface{}, error) {
 	return nil, errors.New("not implemented")
@@ -173,10 +266,18 @@ func (m *mockGroupService) DeleteGroup(ctx context.Context, groupID string, dele
 	return errors.New("not implemented")
 }
 
+func (m *mockGroupService) MoveGroup(ctx context.Context, groupID, newParentID, movedBy string) (interface{}, error) {
+	return nil, errors.New("not implemented")
+}
+
 func (m *mockGroupService) ListGroups(ctx context.Context, limit, offset int, organizationID string, includeInactive bool) (interface{}, error) {
 	return nil, errors.New("not implemented")
 }
 
+func (m *mockGroupService) ListOrganizationGroups(ctx context.Context, organizationID string, filter interfaces.GroupFilter, limit, offset int) (interface{}, int64, error) {
+	return nil, 0, errors.New("not implemented")
+}
+
 func (m *mockGroupService) AddMemberToGroup(ctx context.Context, req interface{}) (interface{}, error) {
 	return nil, errors.New("not implemented")
 }
@@ -201,6 +302,14 @@ func (m *mockGroupService) CountGroupMembers(ctx context.Context, groupID string
 	return 0, errors.New("not implemented")
 }
 
+func (m *mockGroupService) ListGroupMembers(ctx context.Context, groupID string, filter interfaces.GroupMemberFilter, limit, offset int) (interface{}, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockGroupService) CountGroupMembersByType(ctx context.Context, groupID, principalType string) (int64, error) {
+	return 0, errors.New("not implemented")
+}
+
 func (m *mockGroupService) CountGroups(ctx context.Context, organizationID string, includeInactive bool) (int64, error) {
 	return 0, errors.New("not implemented")
 }
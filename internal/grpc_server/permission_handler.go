@@ -186,7 +186,7 @@ func (h *PermissionHandler) GetUserEffectivePermissions(ctx context.Context, req
 		return &pb.GetUserEffectivePermissionsResponse{
 			StatusCode: 500,
 			Message:    "Failed to retrieve user permissions",
-		}, status.Error(codes.Internal, err.Error())
+		}, statusError(err, codes.Internal)
 	}
 
 	// Collect unique permissions and roles
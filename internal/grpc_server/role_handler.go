@@ -2,7 +2,9 @@ package grpc_server
 
 import (
 	"context"
+	"errors"
 
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
 	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
 	pb "github.com/Kisanlink/aaa-service/v2/pkg/proto"
 	"go.uber.org/zap"
@@ -59,12 +61,18 @@ func (h *RoleHandler) AssignRole(ctx context.Context, req *pb.AssignRoleRequest)
 
 	// Assign role to user
 	err = h.roleService.AssignRoleToUser(ctx, req.UserId, role.GetID())
+	if errors.Is(err, models.ErrRoleAssignmentPendingKYC) {
+		return &pb.AssignRoleResponse{
+			StatusCode: 202,
+			Message:    "Role assignment pending KYC verification",
+		}, nil
+	}
 	if err != nil {
 		h.logger.Error("Failed to assign role", zap.Error(err))
 		return &pb.AssignRoleResponse{
 			StatusCode: 500,
 			Message:    "Failed to assign role",
-		}, status.Error(codes.Internal, err.Error())
+		}, statusError(err, codes.Internal)
 	}
 
 	h.logger.Info("Role assigned successfully",
@@ -96,7 +104,7 @@ func (h *RoleHandler) CheckUserRole(ctx context.Context, req *pb.CheckUserRoleRe
 		h.logger.Error("Failed to get user roles", zap.Error(err))
 		return &pb.CheckUserRoleResponse{
 			HasRole: false,
-		}, status.Error(codes.Internal, err.Error())
+		}, statusError(err, codes.Internal)
 	}
 
 	// Check if user has the role
@@ -151,7 +159,7 @@ func (h *RoleHandler) RemoveRole(ctx context.Context, req *pb.RemoveRoleRequest)
 		return &pb.RemoveRoleResponse{
 			StatusCode: 500,
 			Message:    "Failed to remove role",
-		}, status.Error(codes.Internal, err.Error())
+		}, statusError(err, codes.Internal)
 	}
 
 	h.logger.Info("Role removed successfully",
@@ -183,7 +191,7 @@ func (h *RoleHandler) GetUserRoles(ctx context.Context, req *pb.GetUserRolesRequ
 		return &pb.GetUserRolesResponse{
 			StatusCode: 500,
 			Message:    "Failed to retrieve user roles",
-		}, status.Error(codes.Internal, err.Error())
+		}, statusError(err, codes.Internal)
 	}
 
 	// Convert to protobuf format
@@ -65,9 +65,18 @@ func (h *TokenHandler) ValidateToken(ctx context.Context, req *pb.ValidateTokenR
 			Valid:      false,
 		}, nil
 	}
+	// Tokens revoked by their user or an admin are rejected until they expire
+	if claims.ID != "" && h.authService.IsTokenRevoked(claims.ID) {
+		h.logger.Warn("Rejected revoked token", zap.String("user_id", claims.UserID))
+		return &pb.ValidateTokenResponse{
+			StatusCode: 401,
+			Message:    "Token has been revoked",
+			Valid:      false,
+		}, nil
+	}
 
 	// Parse full token context to extract organization info from user_context
-	tokenContext, err := helper.ValidateTokenWithContext(req.Token)
+	tokenContext, err := helper.ValidateTokenWithContextAndSigner(h.authService, req.Token)
 	if err != nil {
 		h.logger.Warn("Failed to parse full token context", zap.Error(err))
 		// Continue with basic validation, but without organization context
@@ -375,7 +384,7 @@ func (h *TokenHandler) CreateToken(ctx context.Context, req *pb.CreateTokenReque
 		return &pb.CreateTokenResponse{
 			StatusCode: 404,
 			Message:    "User not found",
-		}, status.Errorf(codes.NotFound, "user not found: %v", err)
+		}, statusError(fmt.Errorf("user not found: %w", err), codes.NotFound)
 	}
 
 	// This is a simplified implementation
@@ -5,7 +5,9 @@ import (
 	"strconv"
 
 	"github.com/Kisanlink/aaa-service/v2/internal/entities/requests/addresses"
+	addressResponses "github.com/Kisanlink/aaa-service/v2/internal/entities/responses/addresses"
 	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
@@ -77,6 +79,72 @@ func (h *AddressHandler) CreateAddress(c *gin.Context) {
 	h.responder.SendSuccess(c, http.StatusCreated, map[string]string{"message": "Address created successfully"})
 }
 
+// CreateAddresses handles POST /addresses/bulk
+//
+//	@Summary		Create addresses in bulk
+//	@Description	Create many addresses at once, e.g. during a bulk user import. Each address is created independently and the response reports the outcome per address. Addresses are geocoded when a geocoder is configured; geocoding failures leave the coordinates empty.
+//	@Tags			addresses
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		addresses.BulkCreateAddressesRequest	true	"Addresses to create"
+//	@Success		200		{object}	interfaces.BulkAddressCreateResult
+//	@Failure		400		{object}	map[string]interface{}
+//	@Failure		500		{object}	map[string]interface{}
+//	@Router			/api/v1/addresses/bulk [post]
+func (h *AddressHandler) CreateAddresses(c *gin.Context) {
+	var req addresses.BulkCreateAddressesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Failed to bind request", zap.Error(err))
+		h.responder.SendValidationError(c, []string{err.Error()})
+		return
+	}
+
+	h.logger.Info("Creating addresses in bulk", zap.Int("count", len(req.Addresses)))
+
+	result, err := h.addressService.CreateAddresses(c.Request.Context(), req.Addresses)
+	if err != nil {
+		h.logger.Error("Bulk address creation failed", zap.Error(err))
+		if errors.IsValidationError(err) {
+			h.responder.SendValidationError(c, []string{err.Error()})
+			return
+		}
+		h.responder.SendInternalError(c, err)
+		return
+	}
+
+	h.responder.SendSuccess(c, http.StatusOK, result)
+}
+
+// GeocodeAddress handles POST /addresses/:id/geocode
+//
+//	@Summary		Geocode address
+//	@Description	Look up and store the coordinates of an existing address, e.g. to backfill addresses created without them
+//	@Tags			addresses
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string	true	"Address ID"
+//	@Success		200	{object}	addressResponses.AddressResponse
+//	@Failure		404	{object}	map[string]interface{}
+//	@Failure		500	{object}	map[string]interface{}
+//	@Router			/api/v1/addresses/{id}/geocode [post]
+func (h *AddressHandler) GeocodeAddress(c *gin.Context) {
+	addressID := c.Param("id")
+	h.logger.Info("Geocoding address", zap.String("addressID", addressID))
+
+	address, err := h.addressService.Geocode(c.Request.Context(), addressID)
+	if err != nil {
+		h.logger.Error("Failed to geocode address", zap.String("addressID", addressID), zap.Error(err))
+		if errors.IsNotFoundError(err) {
+			h.responder.SendError(c, http.StatusNotFound, err.Error(), err)
+			return
+		}
+		h.responder.SendInternalError(c, err)
+		return
+	}
+
+	h.responder.SendSuccess(c, http.StatusOK, addressResponses.NewAddressResponse(address))
+}
+
 // GetAddress handles GET /addresses/:id
 //
 //	@Summary		Get address by ID
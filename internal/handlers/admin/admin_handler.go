@@ -4,17 +4,26 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/responses"
 	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/internal/utils/utc"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
 
 // AdminHandler handles admin-related HTTP requests
 type AdminHandler struct {
-	maintenanceService interfaces.MaintenanceService
-	validator          interfaces.Validator
-	responder          interfaces.Responder
-	logger             *zap.Logger
+	maintenanceService   interfaces.MaintenanceService
+	impersonationService interfaces.ImpersonationService
+	cacheTiers           interfaces.CacheTierStatsProvider
+	cacheDegradation     interfaces.CacheDegradation
+	dbPool               interfaces.DBPoolStatsProvider
+	signingKeys          interfaces.SigningKeyManager
+	rbacSeedPreview      interfaces.RBACSeedPreviewer
+	hierarchyDepth       interfaces.HierarchyDepthChecker
+	validator            interfaces.Validator
+	responder            interfaces.Responder
+	logger               *zap.Logger
 }
 
 // NewAdminHandler creates a new AdminHandler instance
@@ -32,6 +41,16 @@ func NewAdminHandler(
 	}
 }
 
+// SetCacheTierStats adds the L1/L2 hit counters of a tiered cache to the metrics endpoint
+func (h *AdminHandler) SetCacheTierStats(source interfaces.CacheTierStatsProvider) {
+	h.cacheTiers = source
+}
+
+// SetCacheDegradation adds the state of the cache circuit breaker to the metrics endpoint
+func (h *AdminHandler) SetCacheDegradation(source interfaces.CacheDegradation) {
+	h.cacheDegradation = source
+}
+
 // DetailedHealthCheck handles GET /api/v1/admin/health/detailed
 //
 //	@Summary		Detailed health check
@@ -148,6 +167,16 @@ func (h *AdminHandler) Metrics(c *gin.Context) {
 		},
 	}
 
+	if h.cacheTiers != nil {
+		metrics["cache_tiers"] = h.cacheTiers.Stats()
+	}
+	if h.cacheDegradation != nil {
+		metrics["cache_degradation"] = h.cacheDegradation.DegradationStats()
+	}
+	if h.dbPool != nil {
+		metrics["database_pool"] = responses.NewDBPoolStatsResponse(h.dbPool.Stats())
+	}
+
 	h.logger.Info("System metrics retrieved")
 	h.responder.SendSuccess(c, http.StatusOK, metrics)
 }
@@ -295,7 +324,7 @@ func (h *AdminHandler) MaintenanceMode(c *gin.Context) {
 
 		if req.EndTime != nil && *req.EndTime != "" {
 			// Parse end time if provided
-			endTime, err := time.Parse(time.RFC3339, *req.EndTime)
+			endTime, err := utc.Parse(*req.EndTime)
 			if err != nil {
 				h.responder.SendValidationError(c, []string{"invalid end_time format, use RFC3339"})
 				return
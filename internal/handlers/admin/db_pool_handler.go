@@ -0,0 +1,32 @@
+package admin
+
+import (
+	"net/http"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/responses"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/gin-gonic/gin"
+)
+
+// SetDBPoolStats adds the connection pool of the primary database to the metrics endpoints
+func (h *AdminHandler) SetDBPoolStats(source interfaces.DBPoolStatsProvider) {
+	h.dbPool = source
+}
+
+// DBPoolStats handles GET /api/v1/admin/db/pool
+//
+//	@Summary		Database pool statistics
+//	@Description	Get the connection pool counters of the primary database: connections in use and idle, and how often and how long requests waited for a connection
+//	@Tags			admin
+//	@Produce		json
+//	@Success		200	{object}	responses.DBPoolStatsResponse
+//	@Failure		503	{object}	map[string]interface{}
+//	@Router			/api/v1/admin/db/pool [get]
+func (h *AdminHandler) DBPoolStats(c *gin.Context) {
+	if h.dbPool == nil {
+		h.responder.SendError(c, http.StatusServiceUnavailable, "database pool statistics are not available", nil)
+		return
+	}
+
+	h.responder.SendSuccess(c, http.StatusOK, responses.NewDBPoolStatsResponse(h.dbPool.Stats()))
+}
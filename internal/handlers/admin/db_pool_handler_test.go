@@ -0,0 +1,86 @@
+package admin
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type fixedPoolStats sql.DBStats
+
+func (s fixedPoolStats) Stats() sql.DBStats { return sql.DBStats(s) }
+
+type testResponder struct {
+	interfaces.Responder
+}
+
+func (r *testResponder) SendSuccess(c *gin.Context, statusCode int, data interface{}) {
+	c.JSON(statusCode, gin.H{"data": data})
+}
+
+func (r *testResponder) SendError(c *gin.Context, statusCode int, message string, err error) {
+	c.JSON(statusCode, gin.H{"error": message})
+}
+
+func (r *testResponder) SendValidationError(c *gin.Context, errors []string) {
+	c.JSON(http.StatusBadRequest, gin.H{"errors": errors})
+}
+
+func getDBPoolStats(handler *AdminHandler) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/v1/admin/db/pool", handler.DBPoolStats)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/admin/db/pool", nil))
+	return w
+}
+
+func TestAdminHandler_DBPoolStats(t *testing.T) {
+	handler := NewAdminHandler(nil, nil, &testResponder{}, zap.NewNop())
+	handler.SetDBPoolStats(fixedPoolStats{
+		MaxOpenConnections: 10,
+		OpenConnections:    9,
+		InUse:              8,
+		Idle:               1,
+		WaitCount:          42,
+		WaitDuration:       1500 * time.Millisecond,
+		MaxLifetimeClosed:  3,
+	})
+
+	w := getDBPoolStats(handler)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var body struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, map[string]interface{}{
+		"max_open_connections": 10.0,
+		"open_connections":     9.0,
+		"in_use":               8.0,
+		"idle":                 1.0,
+		"wait_count":           42.0,
+		"wait_duration_ms":     1500.0,
+		"max_idle_closed":      0.0,
+		"max_idle_time_closed": 0.0,
+		"max_lifetime_closed":  3.0,
+		"utilization":          0.8,
+	}, body.Data)
+}
+
+func TestAdminHandler_DBPoolStats_Unavailable(t *testing.T) {
+	handler := NewAdminHandler(nil, nil, &testResponder{}, zap.NewNop())
+
+	w := getDBPoolStats(handler)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
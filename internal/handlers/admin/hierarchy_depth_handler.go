@@ -0,0 +1,58 @@
+package admin
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// SetHierarchyDepthChecker enables the hierarchy depth check endpoint
+func (h *AdminHandler) SetHierarchyDepthChecker(checker interfaces.HierarchyDepthChecker) {
+	h.hierarchyDepth = checker
+}
+
+// CheckHierarchyDepth handles GET /api/v1/admin/hierarchy/depth
+//
+//	@Summary		Check hierarchy depth
+//	@Description	List the organizations and groups nested deeper than the maximum hierarchy depth, deepest first. max_depth overrides the configured limit, so existing structures can be checked before a limit is enabled.
+//	@Tags			admin
+//	@Produce		json
+//	@Param			max_depth	query		int	false	"Maximum depth to check against, a root being at depth 1; defaults to the configured limit"
+//	@Success		200			{object}	organizations.HierarchyDepthReport
+//	@Failure		400			{object}	responses.ErrorResponse
+//	@Failure		500			{object}	responses.ErrorResponse
+//	@Failure		503			{object}	responses.ErrorResponse
+//	@Router			/api/v1/admin/hierarchy/depth [get]
+func (h *AdminHandler) CheckHierarchyDepth(c *gin.Context) {
+	if h.hierarchyDepth == nil {
+		h.responder.SendError(c, http.StatusServiceUnavailable, "hierarchy depth check is not available", nil)
+		return
+	}
+
+	maxDepth := 0
+	if depthStr := c.Query("max_depth"); depthStr != "" {
+		depth, err := strconv.Atoi(depthStr)
+		if err != nil || depth < 1 {
+			h.responder.SendValidationError(c, []string{"max_depth must be a positive integer"})
+			return
+		}
+		maxDepth = depth
+	}
+
+	report, err := h.hierarchyDepth.CheckHierarchyDepth(c.Request.Context(), maxDepth)
+	if err != nil {
+		if errors.IsValidationError(err) {
+			h.responder.SendValidationError(c, []string{err.Error()})
+			return
+		}
+		h.logger.Error("Failed to check hierarchy depth", zap.String("user_id", c.GetString("user_id")), zap.Error(err))
+		h.responder.SendInternalError(c, err)
+		return
+	}
+
+	h.responder.SendSuccess(c, http.StatusOK, report)
+}
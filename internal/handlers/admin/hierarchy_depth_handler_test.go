@@ -0,0 +1,48 @@
+package admin
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+// recordingDepthChecker records the maximum depth it was asked to check against
+type recordingDepthChecker struct {
+	maxDepth int
+}
+
+func (r *recordingDepthChecker) CheckHierarchyDepth(ctx context.Context, maxDepth int) (interface{}, error) {
+	r.maxDepth = maxDepth
+	return gin.H{"max_depth": maxDepth}, nil
+}
+
+func getHierarchyDepth(handler *AdminHandler, query string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/v1/admin/hierarchy/depth", handler.CheckHierarchyDepth)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/admin/hierarchy/depth"+query, nil))
+	return w
+}
+
+func TestAdminHandler_CheckHierarchyDepth(t *testing.T) {
+	handler := NewAdminHandler(nil, nil, &testResponder{}, zap.NewNop())
+	assert.Equal(t, http.StatusServiceUnavailable, getHierarchyDepth(handler, "").Code)
+
+	checker := &recordingDepthChecker{}
+	handler.SetHierarchyDepthChecker(checker)
+
+	assert.Equal(t, http.StatusOK, getHierarchyDepth(handler, "?max_depth=5").Code)
+	assert.Equal(t, 5, checker.maxDepth)
+
+	assert.Equal(t, http.StatusOK, getHierarchyDepth(handler, "").Code)
+	assert.Zero(t, checker.maxDepth, "the configured limit is used by default")
+
+	assert.Equal(t, http.StatusBadRequest, getHierarchyDepth(handler, "?max_depth=0").Code)
+}
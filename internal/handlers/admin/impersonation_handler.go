@@ -0,0 +1,130 @@
+package admin
+
+import (
+	"net/http"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/requests"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// SetImpersonationService enables the impersonation endpoints
+func (h *AdminHandler) SetImpersonationService(impersonationService interfaces.ImpersonationService) {
+	h.impersonationService = impersonationService
+}
+
+// StartImpersonation handles POST /api/v1/admin/impersonate
+//
+//	@Summary		Start impersonating a user
+//	@Description	Issue a short-lived token that acts as the target user. Every action taken with it is audited with the real actor. Requires the system impersonate permission; super admins can only be impersonated by super admins.
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		requests.StartImpersonationRequest	true	"Target user"
+//	@Success		201		{object}	responses.ImpersonationResponse
+//	@Failure		400		{object}	responses.ErrorResponse
+//	@Failure		401		{object}	responses.ErrorResponse
+//	@Failure		403		{object}	responses.ErrorResponse
+//	@Failure		404		{object}	responses.ErrorResponse
+//	@Failure		500		{object}	responses.ErrorResponse
+//	@Router			/api/v1/admin/impersonate [post]
+func (h *AdminHandler) StartImpersonation(c *gin.Context) {
+	if h.impersonationService == nil {
+		h.responder.SendError(c, http.StatusServiceUnavailable, "impersonation is not available", nil)
+		return
+	}
+
+	// An impersonation token cannot be used to start another impersonation
+	if _, impersonating := c.Get("impersonator_id"); impersonating {
+		h.responder.SendError(c, http.StatusForbidden, "cannot start impersonation while impersonating", nil)
+		return
+	}
+
+	var req requests.StartImpersonationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.responder.SendValidationError(c, []string{err.Error()})
+		return
+	}
+	if err := req.Validate(); err != nil {
+		h.responder.SendValidationError(c, []string{err.Error()})
+		return
+	}
+
+	adminID := c.GetString("user_id")
+	if adminID == "" {
+		h.responder.SendError(c, http.StatusUnauthorized, "User authentication required", nil)
+		return
+	}
+
+	session, err := h.impersonationService.StartImpersonation(c.Request.Context(), adminID, req.TargetUserID)
+	if err != nil {
+		h.logger.Warn("Failed to start impersonation",
+			zap.String("admin_id", adminID),
+			zap.String("target_user_id", req.TargetUserID),
+			zap.Error(err))
+		h.sendImpersonationError(c, err)
+		return
+	}
+
+	h.responder.SendSuccess(c, http.StatusCreated, session)
+}
+
+// StopImpersonation handles DELETE /api/v1/admin/impersonate/:id
+//
+//	@Summary		Stop impersonating a user
+//	@Description	End an impersonation session before it expires; its token is rejected from then on
+//	@Tags			admin
+//	@Produce		json
+//	@Param			id	path		string	true	"Impersonation ID"
+//	@Success		200	{object}	map[string]interface{}
+//	@Failure		401	{object}	responses.ErrorResponse
+//	@Failure		403	{object}	responses.ErrorResponse
+//	@Failure		404	{object}	responses.ErrorResponse
+//	@Failure		500	{object}	responses.ErrorResponse
+//	@Router			/api/v1/admin/impersonate/{id} [delete]
+func (h *AdminHandler) StopImpersonation(c *gin.Context) {
+	if h.impersonationService == nil {
+		h.responder.SendError(c, http.StatusServiceUnavailable, "impersonation is not available", nil)
+		return
+	}
+
+	// Sessions are ended with the admin's own token, or with the impersonation token itself
+	adminID := c.GetString("impersonator_id")
+	if adminID == "" {
+		adminID = c.GetString("user_id")
+	}
+	if adminID == "" {
+		h.responder.SendError(c, http.StatusUnauthorized, "User authentication required", nil)
+		return
+	}
+
+	impersonationID := c.Param("id")
+	if err := h.impersonationService.StopImpersonation(c.Request.Context(), adminID, impersonationID); err != nil {
+		h.logger.Warn("Failed to stop impersonation",
+			zap.String("admin_id", adminID),
+			zap.String("impersonation_id", impersonationID),
+			zap.Error(err))
+		h.sendImpersonationError(c, err)
+		return
+	}
+
+	h.responder.SendSuccess(c, http.StatusOK, map[string]interface{}{
+		"impersonation_id": impersonationID,
+		"message":          "Impersonation session ended",
+	})
+}
+
+func (h *AdminHandler) sendImpersonationError(c *gin.Context, err error) {
+	switch {
+	case errors.IsValidationError(err):
+		h.responder.SendValidationError(c, []string{err.Error()})
+	case errors.IsNotFoundError(err):
+		h.responder.SendError(c, http.StatusNotFound, err.Error(), err)
+	case errors.IsForbiddenError(err):
+		h.responder.SendError(c, http.StatusForbidden, err.Error(), err)
+	default:
+		h.responder.SendInternalError(c, err)
+	}
+}
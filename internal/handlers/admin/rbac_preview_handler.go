@@ -0,0 +1,40 @@
+package admin
+
+import (
+	"net/http"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// SetRBACSeedPreview enables the RBAC seed preview endpoint
+func (h *AdminHandler) SetRBACSeedPreview(previewer interfaces.RBACSeedPreviewer) {
+	h.rbacSeedPreview = previewer
+}
+
+// PreviewRBACSeed handles GET /api/v1/admin/rbac/preview
+//
+//	@Summary		Preview RBAC seed changes
+//	@Description	Run the comprehensive RBAC seed in diff mode against the live database and list the resources, permissions and role permissions it would create, with the roles and actions it would skip as missing. Nothing is written, so it is safe to call before a deploy.
+//	@Tags			admin
+//	@Produce		json
+//	@Success		200	{object}	migrations.RBACSeedPreview
+//	@Failure		500	{object}	responses.ErrorResponse
+//	@Failure		503	{object}	responses.ErrorResponse
+//	@Router			/api/v1/admin/rbac/preview [get]
+func (h *AdminHandler) PreviewRBACSeed(c *gin.Context) {
+	if h.rbacSeedPreview == nil {
+		h.responder.SendError(c, http.StatusServiceUnavailable, "RBAC seed preview is not available", nil)
+		return
+	}
+
+	preview, err := h.rbacSeedPreview.PreviewRBACSeed(c.Request.Context())
+	if err != nil {
+		h.logger.Error("Failed to preview RBAC seed", zap.String("user_id", c.GetString("user_id")), zap.Error(err))
+		h.responder.SendInternalError(c, err)
+		return
+	}
+
+	h.responder.SendSuccess(c, http.StatusOK, preview)
+}
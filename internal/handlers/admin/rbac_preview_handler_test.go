@@ -0,0 +1,59 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Kisanlink/aaa-service/v2/migrations"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type fixedRBACSeedPreview migrations.RBACSeedPreview
+
+func (p fixedRBACSeedPreview) PreviewRBACSeed(ctx context.Context) (*migrations.RBACSeedPreview, error) {
+	preview := migrations.RBACSeedPreview(p)
+	return &preview, nil
+}
+
+func getRBACSeedPreview(handler *AdminHandler) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/v1/admin/rbac/preview", handler.PreviewRBACSeed)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/admin/rbac/preview", nil))
+	return w
+}
+
+func TestAdminHandler_PreviewRBACSeed(t *testing.T) {
+	handler := NewAdminHandler(nil, nil, &testResponder{}, zap.NewNop())
+	handler.SetRBACSeedPreview(fixedRBACSeedPreview{
+		Resources:       []migrations.RBACSeedResource{{Name: "group", Type: "aaa/group"}},
+		Permissions:     []migrations.RBACSeedPermission{{Name: "group:read", Resource: "group", Action: "read"}},
+		RolePermissions: []migrations.RBACSeedRolePermission{{Role: "viewer", Permission: "group:read"}},
+	})
+
+	w := getRBACSeedPreview(handler)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var body struct {
+		Data migrations.RBACSeedPreview `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "group", body.Data.Resources[0].Name)
+	assert.Equal(t, "group:read", body.Data.Permissions[0].Name)
+	assert.Equal(t, migrations.RBACSeedRolePermission{Role: "viewer", Permission: "group:read"}, body.Data.RolePermissions[0])
+}
+
+func TestAdminHandler_PreviewRBACSeed_Unavailable(t *testing.T) {
+	handler := NewAdminHandler(nil, nil, &testResponder{}, zap.NewNop())
+
+	w := getRBACSeedPreview(handler)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
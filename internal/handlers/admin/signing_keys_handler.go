@@ -0,0 +1,66 @@
+package admin
+
+import (
+	"net/http"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// SetSigningKeys enables the signing key endpoints
+func (h *AdminHandler) SetSigningKeys(signingKeys interfaces.SigningKeyManager) {
+	h.signingKeys = signingKeys
+}
+
+// ListSigningKeys handles GET /api/v1/admin/signing-keys
+//
+//	@Summary		List token signing keys
+//	@Description	List the keys tokens are verified with: the current signing key and the retired keys still inside their overlap, with their creation and retirement times. Key material is not returned.
+//	@Tags			admin
+//	@Produce		json
+//	@Success		200	{object}	map[string]interface{}
+//	@Failure		503	{object}	responses.ErrorResponse
+//	@Router			/api/v1/admin/signing-keys [get]
+func (h *AdminHandler) ListSigningKeys(c *gin.Context) {
+	if h.signingKeys == nil {
+		h.responder.SendError(c, http.StatusServiceUnavailable, "signing keys are not configured", nil)
+		return
+	}
+
+	h.responder.SendSuccess(c, http.StatusOK, map[string]interface{}{
+		"keys": h.signingKeys.Keys(),
+	})
+}
+
+// RotateSigningKey handles POST /api/v1/admin/signing-keys/rotate
+//
+//	@Summary		Rotate the token signing key
+//	@Description	Promote a newly generated key to sign tokens. The replaced key stays in the JWKS and keeps verifying the tokens it signed until its overlap ends.
+//	@Tags			admin
+//	@Produce		json
+//	@Success		201	{object}	map[string]interface{}
+//	@Failure		500	{object}	responses.ErrorResponse
+//	@Failure		503	{object}	responses.ErrorResponse
+//	@Router			/api/v1/admin/signing-keys/rotate [post]
+func (h *AdminHandler) RotateSigningKey(c *gin.Context) {
+	if h.signingKeys == nil {
+		h.responder.SendError(c, http.StatusServiceUnavailable, "signing keys are not configured", nil)
+		return
+	}
+
+	key, err := h.signingKeys.Rotate()
+	if err != nil {
+		h.logger.Error("Failed to rotate signing key", zap.String("user_id", c.GetString("user_id")), zap.Error(err))
+		h.responder.SendInternalError(c, err)
+		return
+	}
+
+	h.logger.Info("Signing key rotated on demand",
+		zap.String("kid", key.KeyID),
+		zap.String("user_id", c.GetString("user_id")))
+	h.responder.SendSuccess(c, http.StatusCreated, map[string]interface{}{
+		"key":  key,
+		"keys": h.signingKeys.Keys(),
+	})
+}
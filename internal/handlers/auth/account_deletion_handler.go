@@ -0,0 +1,133 @@
+package auth
+
+import (
+	"net/http"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/requests"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// SetAccountDeletionService enables self-service account deletion
+func (h *AuthHandler) SetAccountDeletionService(accountDeletion interfaces.AccountDeletionService) {
+	h.accountDeletion = accountDeletion
+}
+
+// DeleteMyAccount handles POST /api/v1/users/me/deletion
+//
+//	@Summary		Delete my account
+//	@Description	Delete the authenticated user's account after confirming their password. The account is deactivated at once and can no longer sign in; it is permanently deleted, with its role assignments and devices, once the grace period is over. Until then the user can restore it with POST /api/v1/auth/account-deletion/cancel.
+//	@Tags			auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		requests.DeleteAccountRequest	true	"Password and optional reason"
+//	@Success		202		{object}	map[string]interface{}			"Deletion scheduled"
+//	@Failure		400		{object}	responses.ErrorResponseSwagger	"Invalid request"
+//	@Failure		401		{object}	responses.ErrorResponseSwagger	"Invalid password"
+//	@Failure		404		{object}	responses.ErrorResponseSwagger	"User not found"
+//	@Failure		500		{object}	responses.ErrorResponseSwagger	"Internal server error"
+//	@Router			/api/v1/users/me/deletion [post]
+//	@Security		Bearer
+func (h *AuthHandler) DeleteMyAccount(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		h.responder.SendError(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+
+	var req requests.DeleteAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Failed to bind delete account request", zap.Error(err))
+		h.responder.SendValidationError(c, []string{err.Error()})
+		return
+	}
+	if err := req.Validate(); err != nil {
+		h.responder.SendValidationError(c, []string{err.Error()})
+		return
+	}
+	if err := h.validator.ValidateStruct(&req); err != nil {
+		h.responder.SendValidationError(c, []string{err.Error()})
+		return
+	}
+
+	request, err := h.accountDeletion.RequestAccountDeletion(c.Request.Context(), userID, req.Password, req.Reason)
+	if err != nil {
+		h.logger.Error("Failed to request account deletion", zap.String("user_id", userID), zap.Error(err))
+		switch e := err.(type) {
+		case *errors.UnauthorizedError:
+			h.responder.SendError(c, http.StatusUnauthorized, "Invalid password", e)
+		case *errors.ValidationError:
+			h.responder.SendValidationError(c, []string{e.Error()})
+		case *errors.NotFoundError:
+			h.responder.SendError(c, http.StatusNotFound, e.Error(), e)
+		default:
+			h.responder.SendInternalError(c, err)
+		}
+		return
+	}
+
+	h.responder.SendSuccess(c, http.StatusAccepted, map[string]interface{}{
+		"success":       true,
+		"request_id":    request.GetID(),
+		"scheduled_for": request.ScheduledFor,
+		"message":       "Account deactivated; it will be permanently deleted at scheduled_for unless the deletion is cancelled",
+	})
+}
+
+// CancelAccountDeletion handles POST /api/v1/auth/account-deletion/cancel
+//
+//	@Summary		Cancel the deletion of my account
+//	@Description	Restore an account deleted by its user while the grace period lasts. The account cannot sign in while it awaits deletion, so the user identifies themselves with their phone number and password. The account comes back with its roles; the user then signs in as usual.
+//	@Tags			auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		requests.CancelAccountDeletionRequest	true	"Phone number and password"
+//	@Success		200		{object}	map[string]interface{}					"Account restored"
+//	@Failure		400		{object}	responses.ErrorResponseSwagger			"Invalid request"
+//	@Failure		401		{object}	responses.ErrorResponseSwagger			"Invalid credentials"
+//	@Failure		404		{object}	responses.ErrorResponseSwagger			"No deletion pending"
+//	@Failure		409		{object}	responses.ErrorResponseSwagger			"Grace period over"
+//	@Failure		500		{object}	responses.ErrorResponseSwagger			"Internal server error"
+//	@Router			/api/v1/auth/account-deletion/cancel [post]
+func (h *AuthHandler) CancelAccountDeletion(c *gin.Context) {
+	var req requests.CancelAccountDeletionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Failed to bind cancel account deletion request", zap.Error(err))
+		h.responder.SendValidationError(c, []string{err.Error()})
+		return
+	}
+	if err := req.Validate(); err != nil {
+		h.responder.SendValidationError(c, []string{err.Error()})
+		return
+	}
+	if err := h.validator.ValidateStruct(&req); err != nil {
+		h.responder.SendValidationError(c, []string{err.Error()})
+		return
+	}
+
+	request, err := h.accountDeletion.CancelAccountDeletion(c.Request.Context(), req.PhoneNumber, req.CountryCode, req.Password)
+	if err != nil {
+		switch e := err.(type) {
+		case *errors.UnauthorizedError:
+			h.responder.SendError(c, http.StatusUnauthorized, "Invalid credentials", e)
+		case *errors.ValidationError:
+			h.responder.SendValidationError(c, []string{e.Error()})
+		case *errors.NotFoundError:
+			h.responder.SendError(c, http.StatusNotFound, e.Error(), e)
+		case *errors.ConflictError:
+			h.responder.SendError(c, http.StatusConflict, e.Error(), e)
+		default:
+			h.logger.Error("Failed to cancel account deletion", zap.Error(err))
+			h.responder.SendInternalError(c, err)
+		}
+		return
+	}
+
+	h.responder.SendSuccess(c, http.StatusOK, map[string]interface{}{
+		"success":    true,
+		"request_id": request.GetID(),
+		"message":    "Account deletion cancelled; you can sign in again",
+	})
+}
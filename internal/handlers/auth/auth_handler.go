@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"strings"
@@ -20,10 +21,18 @@ import (
 
 // AuthHandler handles authentication-related HTTP requests
 type AuthHandler struct {
-	userService interfaces.UserService
-	validator   interfaces.Validator
-	responder   interfaces.Responder
-	logger      *zap.Logger
+	userService     interfaces.UserService
+	loginChallenges interfaces.LoginChallengeService  // Optional: stepped login
+	deviceSessions  interfaces.DeviceSessionService   // Optional: remembered devices
+	deviceMPins     interfaces.DeviceMPinService      // Optional: device-bound MPINs
+	tokenSessions   interfaces.TokenSessionService    // Optional: listing and revoking issued tokens
+	accountDeletion interfaces.AccountDeletionService // Optional: self-service account deletion
+	credentialReset interfaces.CredentialResetService // Optional: OTP-based MPIN reset
+	loginRecorder   interfaces.LoginRecorder          // Optional: login history and last login
+	tokenSigner     helper.TokenSigner                // Optional: signing keys; the HS256 secret otherwise
+	validator       interfaces.Validator
+	responder       interfaces.Responder
+	logger          *zap.Logger
 }
 
 // NewAuthHandler creates a new AuthHandler instance
@@ -41,6 +50,27 @@ func NewAuthHandler(
 	}
 }
 
+// SetLoginChallengeService enables the stepped login, in which a password login can require the MPIN
+func (h *AuthHandler) SetLoginChallengeService(loginChallenges interfaces.LoginChallengeService) {
+	h.loginChallenges = loginChallenges
+}
+
+// SetDeviceSessionService enables "remember me" logins and the long-lived sessions they open
+func (h *AuthHandler) SetDeviceSessionService(deviceSessions interfaces.DeviceSessionService) {
+	h.deviceSessions = deviceSessions
+}
+
+// SetLoginRecorder records logins, successful or not, in the user's login history and last login
+func (h *AuthHandler) SetLoginRecorder(loginRecorder interfaces.LoginRecorder) {
+	h.loginRecorder = loginRecorder
+}
+
+// SetTokenSigner signs and verifies the tokens the handler issues with signer, so they carry the
+// current key of a rotated key ring. Without it they are signed with the HS256 secret.
+func (h *AuthHandler) SetTokenSigner(signer helper.TokenSigner) {
+	h.tokenSigner = signer
+}
+
 // setAuthCookies sets HTTP-only cookies for access and refresh tokens
 // This provides secure cookie-based authentication while maintaining backward compatibility
 // with JSON response tokens for other clients
@@ -167,6 +197,7 @@ func isCrossOriginDevelopment() bool {
 //	@Success		200		{object}	responses.LoginSuccessResponse	"Successful login with tokens and user info"
 //	@Failure		400		{object}	responses.ErrorResponseSwagger	"Invalid request data or validation error"
 //	@Failure		401		{object}	responses.ErrorResponseSwagger	"Invalid credentials or authentication failed"
+//	@Failure		403		{object}	responses.ErrorResponseSwagger	"Concurrent session limit reached (reject mode)"
 //	@Failure		500		{object}	responses.ErrorResponseSwagger	"Internal server error"
 //	@Router			/api/v1/auth/login [post]
 func (h *AuthHandler) Login(c *gin.Context) {
@@ -204,7 +235,7 @@ func (h *AuthHandler) Login(c *gin.Context) {
 
 		// Validate refresh token and get user ID
 		var userID string
-		userID, err = helper.ValidateToken(req.GetRefreshToken())
+		userID, err = helper.ValidateTokenWithSigner(h.tokenSigner, req.GetRefreshToken())
 		if err != nil {
 			h.logger.Error("Invalid refresh token", zap.Error(err))
 			h.responder.SendError(c, http.StatusUnauthorized, "Invalid refresh token", err)
@@ -212,7 +243,7 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		}
 
 		// Verify mPin
-		err = h.userService.VerifyMPin(c.Request.Context(), userID, req.GetMPin())
+		err = h.verifyMPin(c, userID, req.DeviceID, req.GetMPin())
 		if err != nil {
 			h.logger.Error("Failed to verify mPin", zap.Error(err))
 			h.responder.SendError(c, http.StatusUnauthorized, "Invalid mPin", err)
@@ -251,18 +282,254 @@ func (h *AuthHandler) Login(c *gin.Context) {
 				return
 			}
 			if unauthorizedErr, ok := err.(*errors.UnauthorizedError); ok {
+				h.recordLoginFailure(c, req.PhoneNumber, req.CountryCode, authMethod, "invalid_"+authMethod)
 				h.responder.SendError(c, http.StatusUnauthorized, "Invalid credentials", unauthorizedErr)
 				return
 			}
 			if badRequestErr, ok := err.(*errors.BadRequestError); ok {
+				h.recordLoginFailure(c, req.PhoneNumber, req.CountryCode, authMethod, "mpin_not_set")
 				h.responder.SendError(c, http.StatusBadRequest, badRequestErr.Error(), badRequestErr)
 				return
 			}
 			h.responder.SendInternalError(c, err)
 			return
 		}
+
+		// Stepped login: a verified password is not enough for users with an MPIN
+		if h.loginChallenges != nil {
+			challenge, err := h.loginChallenges.LoginStepUp(c.Request.Context(), userResponse.ID, authMethod, userResponse.HasMPin)
+			if err != nil {
+				h.logger.Error("Failed to create login challenge", zap.Error(err))
+				h.responder.SendInternalError(c, err)
+				return
+			}
+			if challenge != nil {
+				h.logger.Info("Login requires MPIN verification", zap.String("userID", userResponse.ID))
+				h.responder.SendSuccess(c, http.StatusOK, challenge)
+				return
+			}
+		}
+	}
+
+	h.completeLogin(c, userResponse, authMethod, rememberDeviceFor(req.RememberMe, req.DeviceName))
+}
+
+// VerifyLoginMPIN handles POST /api/v1/auth/login/mpin
+//
+//	@Summary		Complete a login with the MPIN
+//	@Description	Redeem the challenge token returned by a login with status "mpin_required". The challenge is single-use and expires after a few minutes; after too many wrong MPINs the login must be started again.
+//	@Tags			auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		requests.VerifyLoginMPinRequest	true	"Challenge token and MPIN"
+//	@Success		200		{object}	responses.LoginSuccessResponse	"Successful login with tokens and user info"
+//	@Failure		400		{object}	responses.ErrorResponseSwagger	"Invalid request data or validation error"
+//	@Failure		401		{object}	responses.ErrorResponseSwagger	"Invalid MPIN or expired challenge"
+//	@Failure		403		{object}	responses.ErrorResponseSwagger	"Concurrent session limit reached (reject mode)"
+//	@Failure		500		{object}	responses.ErrorResponseSwagger	"Internal server error"
+//	@Router			/api/v1/auth/login/mpin [post]
+func (h *AuthHandler) VerifyLoginMPIN(c *gin.Context) {
+	if h.loginChallenges == nil {
+		h.responder.SendError(c, http.StatusNotFound, "Stepped login is not enabled", nil)
+		return
+	}
+
+	var req requests.VerifyLoginMPinRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Failed to bind login MPIN request", zap.Error(err))
+		h.responder.SendValidationError(c, []string{err.Error()})
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		h.logger.Error("Login MPIN request validation failed", zap.Error(err))
+		h.responder.SendValidationError(c, []string{err.Error()})
+		return
+	}
+
+	userID, err := h.loginChallenges.VerifyLoginMPIN(c.Request.Context(), req.ChallengeToken, req.MPin)
+	if err != nil {
+		h.logger.Warn("Failed to verify login MPIN", zap.Error(err))
+		if unauthorizedErr, ok := err.(*errors.UnauthorizedError); ok {
+			h.responder.SendError(c, http.StatusUnauthorized, unauthorizedErr.Error(), unauthorizedErr)
+			return
+		}
+		if validationErr, ok := err.(*errors.ValidationError); ok {
+			h.responder.SendValidationError(c, []string{validationErr.Error()})
+			return
+		}
+		h.responder.SendInternalError(c, err)
+		return
+	}
+
+	userResponse, err := h.userService.GetUserByID(c.Request.Context(), userID)
+	if err != nil {
+		h.logger.Error("Failed to get user for MPIN login", zap.Error(err))
+		h.responder.SendInternalError(c, err)
+		return
+	}
+
+	h.completeLogin(c, userResponse, "password_mpin", rememberDeviceFor(req.RememberMe, req.DeviceName))
+}
+
+// ExchangeDeviceSession handles POST /api/v1/auth/session/token
+//
+//	@Summary		Get new tokens for a remembered device
+//	@Description	Exchange the session token of a "remember me" login for a new access and refresh token without credentials. The session stays valid until it expires or is revoked.
+//	@Tags			auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		requests.ExchangeDeviceSessionRequest	true	"Session token of the device"
+//	@Success		200		{object}	responses.LoginSuccessResponse			"New tokens and user info"
+//	@Failure		400		{object}	responses.ErrorResponseSwagger			"Invalid request data or validation error"
+//	@Failure		401		{object}	responses.ErrorResponseSwagger			"Session expired or revoked"
+//	@Failure		403		{object}	responses.ErrorResponseSwagger			"Concurrent session limit reached (reject mode)"
+//	@Failure		500		{object}	responses.ErrorResponseSwagger			"Internal server error"
+//	@Router			/api/v1/auth/session/token [post]
+func (h *AuthHandler) ExchangeDeviceSession(c *gin.Context) {
+	if h.deviceSessions == nil {
+		h.responder.SendError(c, http.StatusNotFound, "Device sessions are not enabled", nil)
+		return
+	}
+
+	var req requests.ExchangeDeviceSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Failed to bind device session request", zap.Error(err))
+		h.responder.SendValidationError(c, []string{err.Error()})
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		h.logger.Error("Device session request validation failed", zap.Error(err))
+		h.responder.SendValidationError(c, []string{err.Error()})
+		return
+	}
+
+	userID, err := h.deviceSessions.ExchangeDeviceSession(deviceContext(c), req.SessionToken)
+	if err != nil {
+		h.logger.Warn("Failed to exchange device session", zap.Error(err))
+		if unauthorizedErr, ok := err.(*errors.UnauthorizedError); ok {
+			h.responder.SendError(c, http.StatusUnauthorized, unauthorizedErr.Error(), unauthorizedErr)
+			return
+		}
+		if validationErr, ok := err.(*errors.ValidationError); ok {
+			h.responder.SendValidationError(c, []string{validationErr.Error()})
+			return
+		}
+		h.responder.SendInternalError(c, err)
+		return
+	}
+
+	userResponse, err := h.userService.GetUserByID(c.Request.Context(), userID)
+	if err != nil {
+		h.logger.Error("Failed to get user for device session", zap.Error(err))
+		h.responder.SendInternalError(c, err)
+		return
+	}
+
+	h.completeLogin(c, userResponse, "device_session", nil)
+}
+
+// ListMySessions handles GET /api/v1/users/me/sessions
+//
+//	@Summary		List my remembered devices
+//	@Description	List the long-lived sessions of the authenticated user's remembered devices, most recently used first
+//	@Tags			auth
+//	@Produce		json
+//	@Success		200	{array}		responses.DeviceSessionResponse	"Remembered device sessions"
+//	@Failure		401	{object}	responses.ErrorResponseSwagger	"Unauthorized"
+//	@Failure		500	{object}	responses.ErrorResponseSwagger	"Internal server error"
+//	@Router			/api/v1/users/me/sessions [get]
+//	@Security		Bearer
+func (h *AuthHandler) ListMySessions(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		h.responder.SendError(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+
+	sessions, err := h.deviceSessions.ListDeviceSessions(c.Request.Context(), userID)
+	if err != nil {
+		h.logger.Error("Failed to list device sessions", zap.String("user_id", userID), zap.Error(err))
+		h.responder.SendInternalError(c, err)
+		return
+	}
+
+	h.responder.SendSuccess(c, http.StatusOK, sessions)
+}
+
+// RevokeMySession handles DELETE /api/v1/users/me/sessions/:id
+//
+//	@Summary		Sign out a remembered device
+//	@Description	Revoke one long-lived session of the authenticated user. Its session token can no longer be exchanged; access tokens already issued stay valid until they expire.
+//	@Tags			auth
+//	@Produce		json
+//	@Param			id	path		string							true	"Session ID"
+//	@Success		200	{object}	map[string]interface{}			"Session revoked"
+//	@Failure		401	{object}	responses.ErrorResponseSwagger	"Unauthorized"
+//	@Failure		404	{object}	responses.ErrorResponseSwagger	"Session not found"
+//	@Failure		500	{object}	responses.ErrorResponseSwagger	"Internal server error"
+//	@Router			/api/v1/users/me/sessions/{id} [delete]
+//	@Security		Bearer
+func (h *AuthHandler) RevokeMySession(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		h.responder.SendError(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+
+	sessionID := c.Param("id")
+	if err := h.deviceSessions.RevokeDeviceSession(c.Request.Context(), userID, sessionID); err != nil {
+		if notFoundErr, ok := err.(*errors.NotFoundError); ok {
+			h.responder.SendError(c, http.StatusNotFound, notFoundErr.Error(), notFoundErr)
+			return
+		}
+		if validationErr, ok := err.(*errors.ValidationError); ok {
+			h.responder.SendValidationError(c, []string{validationErr.Error()})
+			return
+		}
+		h.logger.Error("Failed to revoke device session", zap.String("user_id", userID), zap.Error(err))
+		h.responder.SendInternalError(c, err)
+		return
+	}
+
+	h.responder.SendSuccess(c, http.StatusOK, map[string]interface{}{
+		"success":    true,
+		"session_id": sessionID,
+		"message":    "Session revoked",
+	})
+}
+
+// rememberDevice asks completeLogin to open a long-lived session for the device
+type rememberDevice struct {
+	name string
+}
+
+func rememberDeviceFor(rememberMe bool, deviceName string) *rememberDevice {
+	if !rememberMe {
+		return nil
+	}
+	return &rememberDevice{name: deviceName}
+}
+
+// deviceContext carries the client's IP address and user agent to the services the handler calls,
+// as the auth middleware does for protected routes
+func deviceContext(c *gin.Context) context.Context {
+	ctx := context.WithValue(c.Request.Context(), "ip_address", c.ClientIP())
+	return context.WithValue(ctx, "user_agent", c.GetHeader("User-Agent"))
+}
+
+// recordLoginFailure adds a failed credential login to the login history of the account holding the phone number
+func (h *AuthHandler) recordLoginFailure(c *gin.Context, phoneNumber, countryCode, method, reason string) {
+	if h.loginRecorder == nil {
+		return
 	}
+	h.loginRecorder.RecordLoginFailure(deviceContext(c), phoneNumber, countryCode, method, reason)
+}
 
+// completeLogin issues the tokens of an authenticated user and sends the login response. With
+// remember set, it also opens a long-lived session for the device.
+func (h *AuthHandler) completeLogin(c *gin.Context, userResponse *userResponses.UserResponse, authMethod string, remember *rememberDevice) {
 	// Convert user roles for token generation with complete Role data
 	var userRoles []models.UserRole
 	for _, roleDetail := range userResponse.Roles {
@@ -320,7 +587,8 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	if userResponse.Username != nil {
 		username = *userResponse.Username
 	}
-	accessToken, err := helper.GenerateAccessTokenWithContext(
+	accessToken, err := helper.GenerateAccessTokenWithSigner(
+		h.tokenSigner,
 		userResponse.ID,
 		userRoles,
 		username,
@@ -336,18 +604,24 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	refreshToken, err := helper.GenerateRefreshToken(userResponse.ID, userRoles, username, userResponse.IsValidated)
+	refreshToken, err := helper.GenerateRefreshTokenWithSigner(h.tokenSigner, userResponse.ID, userRoles, username, userResponse.IsValidated)
 	if err != nil {
 		h.logger.Error("Failed to generate refresh token", zap.Error(err))
 		h.responder.SendInternalError(c, err)
 		return
 	}
+	if err := h.trackIssuedTokens(c, userResponse.ID, "", accessToken, refreshToken); err != nil {
+		h.logger.Warn("Login refused by the concurrent session limit", zap.String("userID", userResponse.ID))
+		h.responder.SendError(c, http.StatusForbidden, err.Error(), err)
+		return
+	}
 
 	// Convert user service response to auth response format
 	authUserInfo := h.convertToAuthUserInfo(userResponse)
 
 	// Create enhanced login response
 	loginResponse := &responses.LoginResponse{
+		Status:       responses.LoginStatusSuccess,
 		AccessToken:  accessToken,
 		RefreshToken: refreshToken,
 		TokenType:    "Bearer",
@@ -356,6 +630,22 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		Message:      "Login successful",
 	}
 
+	if remember != nil && h.deviceSessions != nil {
+		session, err := h.deviceSessions.CreateDeviceSession(deviceContext(c), userResponse.ID, remember.name)
+		if err != nil {
+			// The tokens are valid without the session; the client can ask to be remembered next time
+			h.logger.Warn("Failed to create device session, continuing without it",
+				zap.String("userID", userResponse.ID),
+				zap.Error(err))
+		} else {
+			loginResponse.Session = session
+		}
+	}
+
+	if h.loginRecorder != nil {
+		h.loginRecorder.RecordLogin(deviceContext(c), userResponse.ID, authMethod)
+	}
+
 	// Set HTTP-only cookies for browser clients (backward compatible - JSON response still sent)
 	h.setAuthCookies(c, accessToken, refreshToken)
 
@@ -369,7 +659,7 @@ func (h *AuthHandler) Login(c *gin.Context) {
 // Register handles POST /api/v1/auth/register
 //
 //	@Summary		Register new user account
-//	@Description	Create a new user account with phone number, password, and optional profile information
+//	@Description	Create a new user account with phone number, password, and optional profile information. The user gets the configured default roles, plus those of the organization given in organization_id.
 //	@Tags			auth
 //	@Accept			json
 //	@Produce		json
@@ -459,6 +749,7 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 
 	var refreshToken string
 	var mpin string
+	var deviceID string
 
 	// Try cookie first (primary source for browser clients)
 	if cookie, err := c.Request.Cookie("refresh_token"); err == nil && cookie.Value != "" {
@@ -473,6 +764,7 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 			return
 		}
 		mpin = req.MPin
+		deviceID = req.DeviceID
 	} else {
 		// Fall back to request body (backward compatibility for API clients)
 		var req requests.RefreshTokenRequest
@@ -490,6 +782,7 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 		}
 		refreshToken = req.RefreshToken
 		mpin = req.MPin
+		deviceID = req.DeviceID
 	}
 
 	// Validate MPin
@@ -499,7 +792,7 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 	}
 
 	// Validate refresh token and get user ID
-	userID, err := helper.ValidateToken(refreshToken)
+	userID, err := helper.ValidateTokenWithSigner(h.tokenSigner, refreshToken)
 	if err != nil {
 		h.logger.Error("Invalid refresh token", zap.Error(err))
 		h.responder.SendError(c, http.StatusUnauthorized, "Invalid refresh token", err)
@@ -507,13 +800,21 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 	}
 
 	// Verify mPin
-	err = h.userService.VerifyMPin(c.Request.Context(), userID, mpin)
+	err = h.verifyMPin(c, userID, deviceID, mpin)
 	if err != nil {
 		h.logger.Error("Failed to verify mPin", zap.Error(err))
 		h.responder.SendError(c, http.StatusUnauthorized, "Invalid mPin", err)
 		return
 	}
 
+	// Rotate the refresh token: it is revoked, and the new pair joins its family
+	familyID, err := h.rotateRefreshToken(c, userID, refreshToken)
+	if err != nil {
+		h.logger.Warn("Rejected refresh token", zap.String("user_id", userID), zap.Error(err))
+		h.responder.SendError(c, http.StatusUnauthorized, "Refresh token has been revoked", err)
+		return
+	}
+
 	// Get user details
 	userResponse, err := h.userService.GetUserByID(c.Request.Context(), userID)
 	if err != nil {
@@ -579,7 +880,8 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 	if userResponse.Username != nil {
 		username = *userResponse.Username
 	}
-	newAccessToken, err := helper.GenerateAccessTokenWithContext(
+	newAccessToken, err := helper.GenerateAccessTokenWithSigner(
+		h.tokenSigner,
 		userResponse.ID,
 		userRoles,
 		username,
@@ -595,12 +897,18 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 		return
 	}
 
-	newRefreshToken, err := helper.GenerateRefreshToken(userResponse.ID, userRoles, username, userResponse.IsValidated)
+	newRefreshToken, err := helper.GenerateRefreshTokenWithSigner(h.tokenSigner, userResponse.ID, userRoles, username, userResponse.IsValidated)
 	if err != nil {
 		h.logger.Error("Failed to generate new refresh token", zap.Error(err))
 		h.responder.SendInternalError(c, err)
 		return
 	}
+	// A refresh token issued before tracking began starts a new family, which counts as a login
+	if err := h.trackIssuedTokens(c, userID, familyID, newAccessToken, newRefreshToken); err != nil {
+		h.logger.Warn("Token refresh refused by the concurrent session limit", zap.String("userID", userID))
+		h.responder.SendError(c, http.StatusForbidden, err.Error(), err)
+		return
+	}
 
 	refreshResponse := &responses.RefreshTokenResponse{
 		AccessToken:  newAccessToken,
@@ -667,7 +975,7 @@ func (h *AuthHandler) ForgotPassword(c *gin.Context) {
 
 	// Initiate password reset - this will create an OTP and send via SMS
 	tokenID, err := h.userService.InitiatePasswordReset(
-		c.Request.Context(),
+		deviceContext(c),
 		req.PhoneNumber,
 		req.CountryCode,
 		req.Username,
@@ -675,6 +983,10 @@ func (h *AuthHandler) ForgotPassword(c *gin.Context) {
 	)
 	if err != nil {
 		h.logger.Error("Failed to initiate password reset", zap.Error(err))
+		if errors.CodeOf(err) == errors.CodeRateLimited {
+			h.responder.SendError(c, http.StatusTooManyRequests, err.Error(), err)
+			return
+		}
 		// Still return success to prevent user enumeration
 	}
 
@@ -718,7 +1030,7 @@ func (h *AuthHandler) ResetPassword(c *gin.Context) {
 	}
 
 	// Reset the password using token ID and OTP
-	err := h.userService.ResetPassword(c.Request.Context(), req.TokenID, req.OTP, req.NewPassword)
+	err := h.userService.ResetPassword(deviceContext(c), req.TokenID, req.OTP, req.NewPassword)
 	if err != nil {
 		h.logger.Error("Failed to reset password", zap.Error(err))
 		h.responder.SendError(c, http.StatusBadRequest, "Failed to reset password. OTP may be invalid or expired.", err)
@@ -973,12 +1285,13 @@ func (h *AuthHandler) ChangePassword(c *gin.Context) {
 // convertToCreateUserRequest converts a RegisterRequest to a CreateUserRequest
 func (h *AuthHandler) convertToCreateUserRequest(req *requests.RegisterRequest) *users.CreateUserRequest {
 	return &users.CreateUserRequest{
-		PhoneNumber:   req.PhoneNumber,
-		CountryCode:   req.CountryCode,
-		Password:      req.Password,
-		Username:      req.Username,
-		AadhaarNumber: req.AadhaarNumber,
-		Name:          req.Name,
+		PhoneNumber:    req.PhoneNumber,
+		CountryCode:    req.CountryCode,
+		Password:       req.Password,
+		Username:       req.Username,
+		AadhaarNumber:  req.AadhaarNumber,
+		Name:           req.Name,
+		OrganizationID: req.OrganizationID,
 	}
 }
 
@@ -10,20 +10,28 @@ import (
 	"testing"
 	"time"
 
+	"github.com/Kisanlink/aaa-service/v2/helper"
+	"github.com/Kisanlink/aaa-service/v2/internal/config"
 	"github.com/Kisanlink/aaa-service/v2/internal/entities/requests"
 	userRequests "github.com/Kisanlink/aaa-service/v2/internal/entities/requests/users"
 	"github.com/Kisanlink/aaa-service/v2/internal/entities/responses"
 	userResponses "github.com/Kisanlink/aaa-service/v2/internal/entities/responses/users"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/internal/security"
+	"github.com/Kisanlink/aaa-service/v2/internal/services"
 	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
 	"github.com/gin-gonic/gin"
+	jwt "github.com/golang-jwt/jwt/v4"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 )
 
 // MockUserService is a mock implementation of UserService
 type MockUserService struct {
 	mock.Mock
+	interfaces.UserService
 }
 
 func (m *MockUserService) VerifyUserCredentials(ctx context.Context, phone, countryCode string, password, mpin *string) (*userResponses.UserResponse, error) {
@@ -119,6 +127,12 @@ func (m *MockUserService) GetUserByPhoneNumber(ctx context.Context, phoneNumber,
 func (m *MockUserService) GetUserByEmail(ctx context.Context, email string) (*userResponses.UserResponse, error) {
 	return nil, nil
 }
+func (m *MockUserService) GetUsersByIDs(ctx context.Context, req *userRequests.BatchGetUsersRequest) (*userResponses.BatchUsersResponse, error) {
+	return nil, nil
+}
+func (m *MockUserService) GetUserDetail(ctx context.Context, userID string, expand *userRequests.UserDetailExpand) (*userResponses.UserDetailResponse, error) {
+	return nil, nil
+}
 func (m *MockUserService) InitiatePasswordReset(ctx context.Context, phoneNumber, countryCode, username, email *string) (string, error) {
 	return "", nil
 }
@@ -970,6 +984,180 @@ func TestUpdateMPin_InternalError(t *testing.T) {
 	mockResponder.AssertExpectations(t)
 }
 
+// MockLoginRecorder is a mock implementation of LoginRecorder
+type MockLoginRecorder struct {
+	mock.Mock
+}
+
+func (m *MockLoginRecorder) RecordLogin(ctx context.Context, userID, method string) {
+	m.Called(ctx, userID, method)
+}
+
+func (m *MockLoginRecorder) RecordLoginFailure(ctx context.Context, phoneNumber, countryCode, method, reason string) {
+	m.Called(ctx, phoneNumber, countryCode, method, reason)
+}
+
+// loginFromDevice matches the context of a login made from the test request's client
+func loginFromDevice(ctx context.Context) bool {
+	return ctx.Value("ip_address") == "192.0.2.10" && ctx.Value("user_agent") == "FarmerApp/2.1"
+}
+
+func TestLogin_RecordsLoginHistory(t *testing.T) {
+	tests := []struct {
+		name        string
+		verifyErr   error
+		expectCall  string
+		expectArgs  []interface{}
+		errorStatus int
+		errorType   string
+	}{
+		{
+			name:       "successful login",
+			expectCall: "RecordLogin",
+			expectArgs: []interface{}{mock.MatchedBy(loginFromDevice), "user-123", "password"},
+		},
+		{
+			name:        "wrong password",
+			verifyErr:   errors.NewUnauthorizedError("invalid credentials"),
+			expectCall:  "RecordLoginFailure",
+			expectArgs:  []interface{}{mock.MatchedBy(loginFromDevice), "1234567890", "+91", "password", "invalid_password"},
+			errorStatus: http.StatusUnauthorized,
+			errorType:   "*errors.UnauthorizedError",
+		},
+		{
+			name:        "unknown phone number",
+			verifyErr:   errors.NewNotFoundError("user not found"),
+			errorStatus: http.StatusUnauthorized,
+			errorType:   "*errors.NotFoundError",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, mockUserService, mockValidator, mockResponder := setupTestHandler()
+			recorder := &MockLoginRecorder{}
+			handler.SetLoginRecorder(recorder)
+
+			password := "testpassword123"
+			loginReq := requests.LoginRequest{
+				PhoneNumber: "1234567890",
+				CountryCode: "+91",
+				Password:    &password,
+			}
+			userResponse := &userResponses.UserResponse{
+				ID:          "user-123",
+				PhoneNumber: "1234567890",
+				CountryCode: "+91",
+				Username:    stringPtr("testuser"),
+				IsValidated: true,
+				Roles:       []userResponses.UserRoleDetail{},
+			}
+
+			mockValidator.On("ValidateStruct", &loginReq).Return(nil)
+			if tt.verifyErr != nil {
+				mockUserService.On("VerifyUserCredentials", mock.Anything, "1234567890", "+91", &password, (*string)(nil)).Return(nil, tt.verifyErr)
+				mockResponder.On("SendError", mock.Anything, tt.errorStatus, "Invalid credentials", mock.AnythingOfType(tt.errorType)).Return()
+			} else {
+				mockUserService.On("VerifyUserCredentials", mock.Anything, "1234567890", "+91", &password, (*string)(nil)).Return(userResponse, nil)
+				mockUserService.On("GetUserOrganizations", mock.Anything, "user-123").Return([]map[string]interface{}{}, nil)
+				mockUserService.On("GetUserGroups", mock.Anything, "user-123").Return([]map[string]interface{}{}, nil)
+				mockResponder.On("SendSuccess", mock.Anything, http.StatusOK, mock.AnythingOfType("*responses.LoginResponse")).Return()
+			}
+			if tt.expectCall != "" {
+				recorder.On(tt.expectCall, tt.expectArgs...).Return().Once()
+			}
+
+			reqBody, _ := json.Marshal(loginReq)
+			req := httptest.NewRequest("POST", "/v2/auth/login", bytes.NewBuffer(reqBody))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("User-Agent", "FarmerApp/2.1")
+			req.RemoteAddr = "192.0.2.10:52100"
+			w := httptest.NewRecorder()
+
+			gin.SetMode(gin.TestMode)
+			c, _ := gin.CreateTestContext(w)
+			c.Request = req
+
+			handler.Login(c)
+
+			mockUserService.AssertExpectations(t)
+			mockResponder.AssertExpectations(t)
+			recorder.AssertExpectations(t)
+			if tt.expectCall == "" {
+				recorder.AssertNotCalled(t, "RecordLoginFailure", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+				recorder.AssertNotCalled(t, "RecordLogin", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestLogin_SignsTokensWithCurrentSigningKey(t *testing.T) {
+	t.Setenv("AAA_JWT_SIGNING_KEYS_DIR", t.TempDir())
+	signingKeyConfig := config.LoadSigningKeyConfig()
+	require.True(t, signingKeyConfig.Enabled())
+	keys, err := security.LoadSigningKeyRing(signingKeyConfig.Dir, signingKeyConfig.Overlap)
+	require.NoError(t, err)
+
+	authService, err := services.NewAuthService(nil, nil, nil, nil, nil, nil,
+		&services.AuthServiceConfig{JWTSecret: "test-secret"}, zap.NewNop(), nil, config.LoadJWTConfigFromEnv())
+	require.NoError(t, err)
+	authService.SetSigningKeys(keys)
+
+	handler, mockUserService, mockValidator, mockResponder := setupTestHandler()
+	handler.SetTokenSigner(authService)
+
+	password := "testpassword123"
+	loginReq := requests.LoginRequest{
+		PhoneNumber: "1234567890",
+		CountryCode: "+91",
+		Password:    &password,
+	}
+	userResponse := &userResponses.UserResponse{
+		ID:          "user-123",
+		PhoneNumber: "1234567890",
+		CountryCode: "+91",
+		Username:    stringPtr("testuser"),
+		IsValidated: true,
+		Roles:       []userResponses.UserRoleDetail{},
+	}
+
+	var loginResponse *responses.LoginResponse
+	mockValidator.On("ValidateStruct", &loginReq).Return(nil)
+	mockUserService.On("VerifyUserCredentials", mock.Anything, "1234567890", "+91", &password, (*string)(nil)).Return(userResponse, nil)
+	mockUserService.On("GetUserOrganizations", mock.Anything, "user-123").Return([]map[string]interface{}{}, nil)
+	mockUserService.On("GetUserGroups", mock.Anything, "user-123").Return([]map[string]interface{}{}, nil)
+	mockResponder.On("SendSuccess", mock.Anything, http.StatusOK, mock.AnythingOfType("*responses.LoginResponse")).
+		Run(func(args mock.Arguments) { loginResponse = args.Get(2).(*responses.LoginResponse) }).Return()
+
+	reqBody, _ := json.Marshal(loginReq)
+	req := httptest.NewRequest("POST", "/v2/auth/login", bytes.NewBuffer(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	handler.Login(c)
+
+	mockResponder.AssertExpectations(t)
+	require.NotNil(t, loginResponse)
+	for _, token := range []string{loginResponse.AccessToken, loginResponse.RefreshToken} {
+		parsed, _, err := jwt.NewParser().ParseUnverified(token, jwt.MapClaims{})
+		require.NoError(t, err)
+		assert.Equal(t, security.SigningKeyAlgorithm, parsed.Method.Alg())
+		assert.Equal(t, keys.Current().ID, parsed.Header["kid"])
+	}
+
+	// The refresh token is accepted by the handler's refresh flows, and the access token by the service
+	userID, err := helper.ValidateTokenWithSigner(authService, loginResponse.RefreshToken)
+	require.NoError(t, err)
+	assert.Equal(t, "user-123", userID)
+	claims, err := authService.ValidateToken(loginResponse.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, "user-123", claims.UserID)
+}
+
 // Helper function
 func stringPtr(s string) *string {
 	return &s
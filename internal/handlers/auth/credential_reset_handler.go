@@ -0,0 +1,108 @@
+package auth
+
+import (
+	"net/http"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/requests"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// SetCredentialResetService enables the OTP-based MPIN reset
+func (h *AuthHandler) SetCredentialResetService(credentialReset interfaces.CredentialResetService) {
+	h.credentialReset = credentialReset
+}
+
+// ForgotMPIN handles POST /api/v1/auth/forgot-mpin
+//
+//	@Summary		Start an MPIN reset
+//	@Description	Send an OTP to a verified contact of the account named by phone number, username or email, and return the reset token to complete the reset with POST /api/v1/auth/reset-mpin. The response is the same whether or not the account exists. Resets are rate limited per account and per client IP address.
+//	@Tags			auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		requests.ForgotPasswordRequest	true	"Account identifier"
+//	@Success		200		{object}	map[string]interface{}			"Reset started"
+//	@Failure		400		{object}	responses.ErrorResponseSwagger	"Invalid request"
+//	@Failure		429		{object}	responses.ErrorResponseSwagger	"Too many resets"
+//	@Failure		500		{object}	responses.ErrorResponseSwagger	"Internal server error"
+//	@Router			/api/v1/auth/forgot-mpin [post]
+func (h *AuthHandler) ForgotMPIN(c *gin.Context) {
+	var req requests.ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Failed to bind forgot mPin request", zap.Error(err))
+		h.responder.SendValidationError(c, []string{err.Error()})
+		return
+	}
+	if err := req.Validate(); err != nil {
+		h.responder.SendValidationError(c, []string{err.Error()})
+		return
+	}
+
+	tokenID, err := h.credentialReset.InitiateMPINReset(deviceContext(c), req.PhoneNumber, req.CountryCode, req.Username, req.Email)
+	if err != nil {
+		switch e := err.(type) {
+		case *errors.ValidationError:
+			h.responder.SendValidationError(c, []string{e.Error()})
+		case *errors.BadRequestError:
+			if errors.CodeOf(e) == errors.CodeRateLimited {
+				h.responder.SendError(c, http.StatusTooManyRequests, e.Error(), e)
+				return
+			}
+			h.responder.SendError(c, http.StatusBadRequest, e.Error(), e)
+		default:
+			h.logger.Error("Failed to initiate mPin reset", zap.Error(err))
+			h.responder.SendInternalError(c, err)
+		}
+		return
+	}
+
+	h.responder.SendSuccess(c, http.StatusOK, map[string]interface{}{
+		"message":  "If the account exists, an MPIN reset code has been sent to its verified contact",
+		"token_id": tokenID,
+	})
+}
+
+// ResetMPIN handles POST /api/v1/auth/reset-mpin
+//
+//	@Summary		Complete an MPIN reset
+//	@Description	Set a new MPIN with the reset token from POST /api/v1/auth/forgot-mpin and the OTP sent to the user. The user is signed out of every session. The token expires with its OTP, which is discarded after too many wrong attempts.
+//	@Tags			auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		requests.ResetMPinRequest		true	"Reset token, OTP and new MPIN"
+//	@Success		200		{object}	map[string]interface{}			"MPIN reset"
+//	@Failure		400		{object}	responses.ErrorResponseSwagger	"Invalid request, OTP, or expired reset token"
+//	@Failure		500		{object}	responses.ErrorResponseSwagger	"Internal server error"
+//	@Router			/api/v1/auth/reset-mpin [post]
+func (h *AuthHandler) ResetMPIN(c *gin.Context) {
+	var req requests.ResetMPinRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Failed to bind reset mPin request", zap.Error(err))
+		h.responder.SendValidationError(c, []string{err.Error()})
+		return
+	}
+	if err := req.Validate(); err != nil {
+		h.responder.SendValidationError(c, []string{err.Error()})
+		return
+	}
+
+	if err := h.credentialReset.CompleteReset(deviceContext(c), req.TokenID, req.OTP, req.NewMPin); err != nil {
+		switch e := err.(type) {
+		case *errors.ValidationError:
+			h.responder.SendValidationError(c, []string{e.Error()})
+		case *errors.NotFoundError:
+			h.responder.SendError(c, http.StatusBadRequest, "Failed to reset MPIN. The reset token may be invalid or expired.", e)
+		default:
+			h.logger.Error("Failed to reset mPin", zap.Error(err))
+			h.responder.SendInternalError(c, err)
+		}
+		return
+	}
+
+	h.responder.SendSuccess(c, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"message": "MPIN reset successfully. Sign in again to continue.",
+	})
+}
@@ -0,0 +1,152 @@
+package auth
+
+import (
+	"net/http"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/requests"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// SetDeviceMPinService enables MPINs bound to a single device of a user
+func (h *AuthHandler) SetDeviceMPinService(deviceMPins interfaces.DeviceMPinService) {
+	h.deviceMPins = deviceMPins
+}
+
+// verifyMPin checks an MPIN against the named device's MPIN when device MPINs are enabled, and
+// against the user's global MPIN otherwise
+func (h *AuthHandler) verifyMPin(c *gin.Context, userID, deviceID, mpin string) error {
+	if deviceID != "" && h.deviceMPins != nil {
+		return h.deviceMPins.VerifyDeviceMPIN(deviceContext(c), userID, deviceID, mpin)
+	}
+	return h.userService.VerifyMPin(c.Request.Context(), userID, mpin)
+}
+
+// ListMyDevices handles GET /api/v1/users/me/devices
+//
+//	@Summary		List my devices with their own MPIN
+//	@Description	List the devices of the authenticated user that have a device-bound MPIN, with their lockout state
+//	@Tags			auth
+//	@Produce		json
+//	@Success		200	{array}		responses.UserDeviceResponse	"Devices with a device-bound MPIN"
+//	@Failure		401	{object}	responses.ErrorResponseSwagger	"Unauthorized"
+//	@Failure		500	{object}	responses.ErrorResponseSwagger	"Internal server error"
+//	@Router			/api/v1/users/me/devices [get]
+//	@Security		Bearer
+func (h *AuthHandler) ListMyDevices(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		h.responder.SendError(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+
+	devices, err := h.deviceMPins.ListUserDevices(c.Request.Context(), userID)
+	if err != nil {
+		h.logger.Error("Failed to list user devices", zap.String("user_id", userID), zap.Error(err))
+		h.responder.SendInternalError(c, err)
+		return
+	}
+
+	h.responder.SendSuccess(c, http.StatusOK, devices)
+}
+
+// SetMyDeviceMPin handles PUT /api/v1/users/me/devices/:device_id/mpin
+//
+//	@Summary		Set the MPIN of one of my devices
+//	@Description	Set or replace the MPIN bound to one device of the authenticated user and clear its lockout. The device then unlocks with this MPIN, passed with its device_id on refresh; other devices keep their own MPIN or the global one.
+//	@Tags			auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			device_id	path		string							true	"Client-generated device identifier"
+//	@Param			request		body		requests.SetDeviceMPinRequest	true	"MPIN and current password"
+//	@Success		200			{object}	responses.UserDeviceResponse
+//	@Failure		400			{object}	responses.ErrorResponseSwagger	"Invalid request"
+//	@Failure		401			{object}	responses.ErrorResponseSwagger	"Invalid password"
+//	@Failure		500			{object}	responses.ErrorResponseSwagger	"Internal server error"
+//	@Router			/api/v1/users/me/devices/{device_id}/mpin [put]
+//	@Security		Bearer
+func (h *AuthHandler) SetMyDeviceMPin(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		h.responder.SendError(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+
+	var req requests.SetDeviceMPinRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Failed to bind set device mPin request", zap.Error(err))
+		h.responder.SendValidationError(c, []string{err.Error()})
+		return
+	}
+	if err := req.Validate(); err != nil {
+		h.responder.SendValidationError(c, []string{err.Error()})
+		return
+	}
+	if err := h.validator.ValidateStruct(&req); err != nil {
+		h.responder.SendValidationError(c, []string{err.Error()})
+		return
+	}
+
+	deviceID := c.Param("device_id")
+	device, err := h.deviceMPins.SetDeviceMPIN(deviceContext(c), userID, deviceID, req.DeviceName, req.MPin, req.Password)
+	if err != nil {
+		h.logger.Error("Failed to set device mPin", zap.String("user_id", userID), zap.Error(err))
+		switch e := err.(type) {
+		case *errors.UnauthorizedError:
+			h.responder.SendError(c, http.StatusUnauthorized, "Invalid password", e)
+		case *errors.ValidationError:
+			h.responder.SendValidationError(c, []string{e.Error()})
+		case *errors.NotFoundError:
+			h.responder.SendError(c, http.StatusNotFound, e.Error(), e)
+		default:
+			h.responder.SendInternalError(c, err)
+		}
+		return
+	}
+
+	h.responder.SendSuccess(c, http.StatusOK, device)
+}
+
+// RevokeMyDeviceMPin handles DELETE /api/v1/users/me/devices/:device_id/mpin
+//
+//	@Summary		Revoke the MPIN of one of my devices
+//	@Description	Remove the MPIN bound to one device of the authenticated user. The device falls back to the global MPIN; other devices are unaffected.
+//	@Tags			auth
+//	@Produce		json
+//	@Param			device_id	path		string							true	"Client-generated device identifier"
+//	@Success		200			{object}	map[string]interface{}			"Device MPIN revoked"
+//	@Failure		401			{object}	responses.ErrorResponseSwagger	"Unauthorized"
+//	@Failure		404			{object}	responses.ErrorResponseSwagger	"Device not found"
+//	@Failure		500			{object}	responses.ErrorResponseSwagger	"Internal server error"
+//	@Router			/api/v1/users/me/devices/{device_id}/mpin [delete]
+//	@Security		Bearer
+func (h *AuthHandler) RevokeMyDeviceMPin(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		h.responder.SendError(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+
+	deviceID := c.Param("device_id")
+	if err := h.deviceMPins.RevokeDeviceMPIN(deviceContext(c), userID, deviceID); err != nil {
+		if notFoundErr, ok := err.(*errors.NotFoundError); ok {
+			h.responder.SendError(c, http.StatusNotFound, notFoundErr.Error(), notFoundErr)
+			return
+		}
+		if validationErr, ok := err.(*errors.ValidationError); ok {
+			h.responder.SendValidationError(c, []string{validationErr.Error()})
+			return
+		}
+		h.logger.Error("Failed to revoke device mPin", zap.String("user_id", userID), zap.Error(err))
+		h.responder.SendInternalError(c, err)
+		return
+	}
+
+	h.responder.SendSuccess(c, http.StatusOK, map[string]interface{}{
+		"success":   true,
+		"device_id": deviceID,
+		"message":   "Device MPIN revoked",
+	})
+}
@@ -0,0 +1,35 @@
+package auth
+
+import (
+	"net/http"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/gin-gonic/gin"
+)
+
+// jwksMaxAge is how long, in seconds, clients may cache the JWKS. It is far shorter than the
+// overlap of retired keys, so clients see a new key well before the old one stops verifying.
+const jwksMaxAge = "300"
+
+// JWKSHandler publishes the public keys tokens can be verified with
+type JWKSHandler struct {
+	signingKeys interfaces.SigningKeyManager
+}
+
+// NewJWKSHandler creates a new JWKSHandler instance
+func NewJWKSHandler(signingKeys interfaces.SigningKeyManager) *JWKSHandler {
+	return &JWKSHandler{signingKeys: signingKeys}
+}
+
+// JWKS handles GET /.well-known/jwks.json
+//
+//	@Summary		JSON Web Key Set
+//	@Description	Public keys of the current token signing key and of the retired keys still verifying tokens, selected by the kid header of a token
+//	@Tags			auth
+//	@Produce		json
+//	@Success		200	{object}	security.JSONWebKeySet
+//	@Router			/.well-known/jwks.json [get]
+func (h *JWKSHandler) JWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age="+jwksMaxAge)
+	c.JSON(http.StatusOK, h.signingKeys.JWKS())
+}
@@ -0,0 +1,111 @@
+package auth
+
+import (
+	"net/http"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/internal/services"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// OIDCHandler handles login through external OpenID Connect identity providers
+type OIDCHandler struct {
+	oidcService *services.OIDCService
+	responder   interfaces.Responder
+	logger      *zap.Logger
+}
+
+// NewOIDCHandler creates a new OIDCHandler instance
+func NewOIDCHandler(
+	oidcService *services.OIDCService,
+	responder interfaces.Responder,
+	logger *zap.Logger,
+) *OIDCHandler {
+	return &OIDCHandler{
+		oidcService: oidcService,
+		responder:   responder,
+		logger:      logger,
+	}
+}
+
+// Login handles GET /api/v1/auth/oidc/:provider/login
+//
+//	@Summary		Start OIDC login
+//	@Description	Redirect the user agent to the external identity provider's authorization endpoint
+//	@Tags			auth
+//	@Param			provider	path	string	true	"Configured identity provider name"
+//	@Success		302			"Redirect to identity provider"
+//	@Failure		404			{object}	responses.ErrorResponseSwagger	"Identity provider not configured"
+//	@Failure		500			{object}	responses.ErrorResponseSwagger	"Internal server error"
+//	@Router			/api/v1/auth/oidc/{provider}/login [get]
+func (h *OIDCHandler) Login(c *gin.Context) {
+	provider := c.Param("provider")
+
+	redirectURL, err := h.oidcService.AuthorizationURL(c.Request.Context(), provider)
+	if err != nil {
+		h.logger.Error("Failed to start OIDC login", zap.String("provider", provider), zap.Error(err))
+		if errors.IsNotFoundError(err) {
+			h.responder.SendError(c, http.StatusNotFound, "identity provider not found", err)
+		} else {
+			h.responder.SendInternalError(c, err)
+		}
+		return
+	}
+
+	c.Redirect(http.StatusFound, redirectURL)
+}
+
+// Callback handles GET /api/v1/auth/oidc/:provider/callback
+//
+//	@Summary		Complete OIDC login
+//	@Description	Exchange the authorization code, validate the ID token, link or provision the user and issue a token pair
+//	@Tags			auth
+//	@Produce		json
+//	@Param			provider	path		string	true	"Configured identity provider name"
+//	@Param			code		query		string	true	"Authorization code"
+//	@Param			state		query		string	true	"State returned by the identity provider"
+//	@Success		200			{object}	services.OIDCLoginResult
+//	@Failure		400			{object}	responses.ErrorResponseSwagger	"Missing or invalid parameters"
+//	@Failure		401			{object}	responses.ErrorResponseSwagger	"Invalid state or ID token"
+//	@Failure		403			{object}	responses.ErrorResponseSwagger	"No account could be linked or created"
+//	@Failure		404			{object}	responses.ErrorResponseSwagger	"Identity provider not configured"
+//	@Failure		409			{object}	responses.ErrorResponseSwagger	"Account already linked to a different identity"
+//	@Failure		500			{object}	responses.ErrorResponseSwagger	"Internal server error"
+//	@Router			/api/v1/auth/oidc/{provider}/callback [get]
+func (h *OIDCHandler) Callback(c *gin.Context) {
+	provider := c.Param("provider")
+
+	if idpError := c.Query("error"); idpError != "" {
+		h.logger.Warn("Identity provider returned an error",
+			zap.String("provider", provider),
+			zap.String("error", idpError),
+			zap.String("description", c.Query("error_description")))
+		h.responder.SendError(c, http.StatusUnauthorized, "identity provider denied the login", nil)
+		return
+	}
+
+	result, err := h.oidcService.HandleCallback(c.Request.Context(), provider, c.Query("code"), c.Query("state"))
+	if err != nil {
+		h.logger.Error("OIDC callback failed", zap.String("provider", provider), zap.Error(err))
+
+		switch {
+		case errors.IsNotFoundError(err):
+			h.responder.SendError(c, http.StatusNotFound, "identity provider not found", err)
+		case errors.IsBadRequestError(err):
+			h.responder.SendError(c, http.StatusBadRequest, err.Error(), err)
+		case errors.IsUnauthorizedError(err):
+			h.responder.SendError(c, http.StatusUnauthorized, err.Error(), err)
+		case errors.IsForbiddenError(err):
+			h.responder.SendError(c, http.StatusForbidden, err.Error(), err)
+		case errors.IsConflictError(err):
+			h.responder.SendError(c, http.StatusConflict, err.Error(), err)
+		default:
+			h.responder.SendInternalError(c, err)
+		}
+		return
+	}
+
+	h.responder.SendSuccess(c, http.StatusOK, result)
+}
@@ -0,0 +1,182 @@
+package auth
+
+import (
+	"net/http"
+
+	"github.com/Kisanlink/aaa-service/v2/helper"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// SetTokenSessionService enables tracking of issued tokens, so users and admins can list and revoke them
+func (h *AuthHandler) SetTokenSessionService(tokenSessions interfaces.TokenSessionService) {
+	h.tokenSessions = tokenSessions
+}
+
+// issuedToken reads the ID, type and lifetime of a token this service signed
+func (h *AuthHandler) issuedToken(token string) (interfaces.IssuedToken, error) {
+	tokenContext, err := helper.ValidateTokenWithContextAndSigner(h.tokenSigner, token)
+	if err != nil {
+		return interfaces.IssuedToken{}, err
+	}
+	return interfaces.IssuedToken{
+		JTI:       tokenContext.JTI,
+		TokenType: tokenContext.TokenType,
+		IssuedAt:  tokenContext.IssuedAt,
+		ExpiresAt: tokenContext.ExpiresAt,
+	}, nil
+}
+
+// trackIssuedTokens records a newly issued token pair in the given family, or in a new family when
+// familyID is empty. The tokens are valid without the record, so a failure is only logged; the
+// error returned is the refusal of a new login by the concurrent session limit, and the tokens
+// must then not be handed out.
+func (h *AuthHandler) trackIssuedTokens(c *gin.Context, userID, familyID string, tokens ...string) error {
+	if h.tokenSessions == nil {
+		return nil
+	}
+	issued := make([]interfaces.IssuedToken, 0, len(tokens))
+	for _, token := range tokens {
+		info, err := h.issuedToken(token)
+		if err != nil {
+			h.logger.Warn("Failed to read issued token", zap.String("user_id", userID), zap.Error(err))
+			continue
+		}
+		issued = append(issued, info)
+	}
+	if _, err := h.tokenSessions.TrackIssuedTokens(deviceContext(c), userID, familyID, issued...); err != nil {
+		if errors.IsForbiddenError(err) {
+			return err
+		}
+		h.logger.Warn("Failed to record issued tokens", zap.String("user_id", userID), zap.Error(err))
+	}
+	return nil
+}
+
+// rotateRefreshToken revokes a refresh token being exchanged and returns the family of the new
+// token pair. It fails for a refresh token that has been revoked.
+func (h *AuthHandler) rotateRefreshToken(c *gin.Context, userID, refreshToken string) (string, error) {
+	if h.tokenSessions == nil {
+		return "", nil
+	}
+	info, err := h.issuedToken(refreshToken)
+	if err != nil {
+		return "", errors.NewUnauthorizedError("invalid refresh token")
+	}
+	return h.tokenSessions.RotateRefreshToken(c.Request.Context(), userID, info)
+}
+
+// ListUserSessions handles GET /api/v1/users/:id/sessions
+//
+//	@Summary		List a user's active tokens
+//	@Description	List the access and refresh tokens issued to the user that have neither expired nor been revoked, most recently issued first. Tokens of one login share a family_id. Users can list their own tokens; super_admin can list anyone's.
+//	@Tags			auth
+//	@Produce		json
+//	@Param			id	path		string							true	"User ID"
+//	@Success		200	{array}		responses.TokenSessionResponse	"Active tokens"
+//	@Failure		401	{object}	responses.ErrorResponseSwagger	"Unauthorized"
+//	@Failure		403	{object}	responses.ErrorResponseSwagger	"Not the user or a super_admin"
+//	@Failure		500	{object}	responses.ErrorResponseSwagger	"Internal server error"
+//	@Router			/api/v1/users/{id}/sessions [get]
+//	@Security		Bearer
+func (h *AuthHandler) ListUserSessions(c *gin.Context) {
+	userID, ok := h.sessionOwner(c)
+	if !ok {
+		return
+	}
+
+	sessions, err := h.tokenSessions.ListTokenSessions(c.Request.Context(), userID)
+	if err != nil {
+		h.logger.Error("Failed to list token sessions", zap.String("user_id", userID), zap.Error(err))
+		h.responder.SendInternalError(c, err)
+		return
+	}
+
+	h.responder.SendSuccess(c, http.StatusOK, sessions)
+}
+
+// RevokeUserSession handles DELETE /api/v1/users/:id/sessions/:jti
+//
+//	@Summary		Revoke one of a user's tokens
+//	@Description	Revoke a token issued to the user; it is rejected from then on. Revoking a refresh token signs its login out: every token of its family is revoked and it can no longer be refreshed. Revoking an access token revokes only that token. Users can revoke their own tokens; super_admin can revoke anyone's.
+//	@Tags			auth
+//	@Produce		json
+//	@Param			id	path		string							true	"User ID"
+//	@Param			jti	path		string							true	"Token ID (jti)"
+//	@Success		200	{object}	map[string]interface{}			"Token revoked"
+//	@Failure		401	{object}	responses.ErrorResponseSwagger	"Unauthorized"
+//	@Failure		403	{object}	responses.ErrorResponseSwagger	"Not the user or a super_admin"
+//	@Failure		404	{object}	responses.ErrorResponseSwagger	"Token not found"
+//	@Failure		500	{object}	responses.ErrorResponseSwagger	"Internal server error"
+//	@Router			/api/v1/users/{id}/sessions/{jti} [delete]
+//	@Security		Bearer
+func (h *AuthHandler) RevokeUserSession(c *gin.Context) {
+	userID, ok := h.sessionOwner(c)
+	if !ok {
+		return
+	}
+
+	jti := c.Param("jti")
+	if err := h.tokenSessions.RevokeTokenSession(c.Request.Context(), userID, jti); err != nil {
+		if notFoundErr, ok := err.(*errors.NotFoundError); ok {
+			h.responder.SendError(c, http.StatusNotFound, notFoundErr.Error(), notFoundErr)
+			return
+		}
+		if validationErr, ok := err.(*errors.ValidationError); ok {
+			h.responder.SendValidationError(c, []string{validationErr.Error()})
+			return
+		}
+		h.logger.Error("Failed to revoke token session", zap.String("user_id", userID), zap.Error(err))
+		h.responder.SendInternalError(c, err)
+		return
+	}
+
+	h.logger.Info("Token session revoked",
+		zap.String("user_id", userID),
+		zap.String("jti", jti),
+		zap.String("revoked_by", c.GetString("user_id")))
+	h.responder.SendSuccess(c, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"jti":     jti,
+		"message": "Token revoked",
+	})
+}
+
+// sessionOwner returns the user whose tokens are addressed, after checking that the caller is that
+// user or a super_admin. On failure the response has been sent.
+func (h *AuthHandler) sessionOwner(c *gin.Context) (string, bool) {
+	callerID := c.GetString("user_id")
+	if callerID == "" {
+		h.responder.SendError(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return "", false
+	}
+
+	userID := c.Param("id")
+	if userID != callerID && !hasRole(c, "super_admin") {
+		h.logger.Warn("Token sessions of another user requested",
+			zap.String("user_id", userID),
+			zap.String("requested_by", callerID))
+		h.responder.SendError(c, http.StatusForbidden, "only the user or a super_admin can manage these sessions", nil)
+		return "", false
+	}
+	return userID, true
+}
+
+func hasRole(c *gin.Context, role string) bool {
+	roles, ok := c.Get("roles")
+	if !ok {
+		return false
+	}
+	names, ok := roles.([]string)
+	if !ok {
+		return false
+	}
+	for _, name := range names {
+		if name == role {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,252 @@
+package authz
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/services"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// AccessReporter enumerates a user's effective permissions; it is implemented by
+// services.AuthorizationService
+type AccessReporter interface {
+	WriteAccessReport(ctx context.Context, userID, orgID string, emit func(*services.AccessGrant) error) error
+}
+
+// accessReportAdminRoles may read the access report of any user
+var accessReportAdminRoles = []string{"super_admin", "admin"}
+
+// accessReportColumns is the header row of the CSV access report
+var accessReportColumns = []string{
+	"permission", "resource_type", "resource_id", "action", "rule",
+	"role_id", "role_name", "path", "group_id", "via_role", "wildcard",
+}
+
+// SetAccessReporter enables the access report endpoint
+func (h *Handler) SetAccessReporter(reporter AccessReporter) {
+	h.reporter = reporter
+}
+
+// HasAccessReporter reports whether the access report endpoint is enabled
+func (h *Handler) HasAccessReporter() bool {
+	return h.reporter != nil
+}
+
+// AccessReport handles GET /api/v1/users/:id/access-report
+//
+//	@Summary		Export a user's effective access
+//	@Description	List every permission the user holds and how: the role granting it, whether the user holds that role directly, through a group or as the parent of another role, and whether the grant is a wildcard. Roles are those permission checks evaluate; organization_id keeps global roles and roles of that organization. The report is streamed, as a JSON document or as CSV with format=csv. Users can export their own report; super_admin and admin can export anyone's.
+//	@Tags			authorization
+//	@Produce		json
+//	@Produce		text/csv
+//	@Param			id				path		string	true	"User ID"
+//	@Param			format			query		string	false	"Report format"	Enums(json, csv)	default(json)
+//	@Param			organization_id	query		string	false	"Organization the report is scoped to"
+//	@Success		200				{object}	AccessReportResponse
+//	@Failure		400				{object}	responses.ErrorResponseSwagger	"Unknown format"
+//	@Failure		401				{object}	responses.ErrorResponseSwagger	"Unauthorized"
+//	@Failure		403				{object}	responses.ErrorResponseSwagger	"Not the user or an admin"
+//	@Failure		404				{object}	responses.ErrorResponseSwagger	"User not found"
+//	@Failure		500				{object}	responses.ErrorResponseSwagger	"Internal server error"
+//	@Router			/api/v1/users/{id}/access-report [get]
+//	@Security		Bearer
+func (h *Handler) AccessReport(c *gin.Context) {
+	callerID := c.GetString("user_id")
+	if callerID == "" {
+		h.responder.SendError(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+
+	userID := c.Param("id")
+	if userID != callerID && !hasAnyRole(c, accessReportAdminRoles) {
+		h.logger.Warn("Access report of another user requested",
+			zap.String("user_id", userID),
+			zap.String("requested_by", callerID))
+		h.responder.SendError(c, http.StatusForbidden, "only the user or an admin can export this access report", nil)
+		return
+	}
+
+	var report accessReportWriter
+	switch format := c.DefaultQuery("format", "json"); format {
+	case "json":
+		report = &jsonAccessReport{c: c, userID: userID}
+	case "csv":
+		report = &csvAccessReport{c: c, userID: userID}
+	default:
+		h.responder.SendValidationError(c, []string{"format must be json or csv"})
+		return
+	}
+
+	grants := 0
+	err := h.reporter.WriteAccessReport(c.Request.Context(), userID, c.Query("organization_id"), func(grant *services.AccessGrant) error {
+		grants++
+		return report.write(grant)
+	})
+	if err != nil && !report.started() {
+		if errors.IsNotFoundError(err) {
+			h.responder.SendError(c, http.StatusNotFound, err.Error(), err)
+			return
+		}
+		h.logger.Error("Failed to build access report", zap.String("user_id", userID), zap.Error(err))
+		h.responder.SendInternalError(c, err)
+		return
+	}
+	if err != nil {
+		// The report is partly sent and cannot be turned into an error response; it is left
+		// unterminated so the client does not take it for a complete report
+		h.logger.Error("Access report stopped early", zap.String("user_id", userID), zap.Int("grants", grants), zap.Error(err))
+		return
+	}
+	if err := report.finish(); err != nil {
+		h.logger.Warn("Failed to finish access report", zap.String("user_id", userID), zap.Error(err))
+		return
+	}
+
+	h.logger.Info("Access report exported",
+		zap.String("user_id", userID),
+		zap.String("requested_by", callerID),
+		zap.Int("grants", grants))
+}
+
+// AccessReportResponse documents the JSON access report
+type AccessReportResponse struct {
+	UserID      string                 `json:"user_id"`
+	GeneratedAt time.Time              `json:"generated_at"`
+	Grants      []services.AccessGrant `json:"grants"`
+}
+
+// accessReportWriter streams an access report; the response headers are sent with the first
+// grant so errors found before any output can still be returned as JSON
+type accessReportWriter interface {
+	write(grant *services.AccessGrant) error
+	finish() error
+	started() bool
+}
+
+type jsonAccessReport struct {
+	c       *gin.Context
+	userID  string
+	grants  int
+	opened  bool
+	encoder *json.Encoder
+}
+
+func (r *jsonAccessReport) start() error {
+	r.opened = true
+	r.c.Header("Content-Type", "application/json; charset=utf-8")
+	r.c.Status(http.StatusOK)
+	r.encoder = json.NewEncoder(r.c.Writer)
+
+	userID, err := json.Marshal(r.userID)
+	if err != nil {
+		return err
+	}
+	generatedAt, err := json.Marshal(time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	_, err = r.c.Writer.WriteString(`{"user_id":` + string(userID) + `,"generated_at":` + string(generatedAt) + `,"grants":[`)
+	return err
+}
+
+func (r *jsonAccessReport) write(grant *services.AccessGrant) error {
+	if !r.opened {
+		if err := r.start(); err != nil {
+			return err
+		}
+	}
+	if r.grants > 0 {
+		if _, err := r.c.Writer.WriteString(","); err != nil {
+			return err
+		}
+	}
+	r.grants++
+	if err := r.encoder.Encode(grant); err != nil {
+		return err
+	}
+	r.c.Writer.Flush()
+	return nil
+}
+
+func (r *jsonAccessReport) finish() error {
+	if !r.opened {
+		if err := r.start(); err != nil {
+			return err
+		}
+	}
+	_, err := r.c.Writer.WriteString("]}\n")
+	return err
+}
+
+func (r *jsonAccessReport) started() bool { return r.opened }
+
+type csvAccessReport struct {
+	c      *gin.Context
+	userID string
+	writer *csv.Writer
+}
+
+func (r *csvAccessReport) start() error {
+	r.c.Header("Content-Type", "text/csv; charset=utf-8")
+	r.c.Header("Content-Disposition", `attachment; filename="access-report-`+r.userID+`.csv"`)
+	r.c.Status(http.StatusOK)
+	r.writer = csv.NewWriter(r.c.Writer)
+	return r.writer.Write(accessReportColumns)
+}
+
+func (r *csvAccessReport) write(grant *services.AccessGrant) error {
+	if r.writer == nil {
+		if err := r.start(); err != nil {
+			return err
+		}
+	}
+	err := r.writer.Write([]string{
+		grant.Permission, grant.ResourceType, grant.ResourceID, grant.Action, grant.Rule,
+		grant.RoleID, grant.RoleName, grant.Path, grant.GroupID, grant.ViaRole,
+		strconv.FormatBool(grant.Wildcard),
+	})
+	if err != nil {
+		return err
+	}
+	r.writer.Flush()
+	r.c.Writer.Flush()
+	return r.writer.Error()
+}
+
+func (r *csvAccessReport) finish() error {
+	if r.writer == nil {
+		if err := r.start(); err != nil {
+			return err
+		}
+	}
+	r.writer.Flush()
+	return r.writer.Error()
+}
+
+func (r *csvAccessReport) started() bool { return r.writer != nil }
+
+func hasAnyRole(c *gin.Context, allowed []string) bool {
+	roles, ok := c.Get("roles")
+	if !ok {
+		return false
+	}
+	names, ok := roles.([]string)
+	if !ok {
+		return false
+	}
+	for _, name := range names {
+		for _, role := range allowed {
+			if name == role {
+				return true
+			}
+		}
+	}
+	return false
+}
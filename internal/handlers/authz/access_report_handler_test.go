@@ -0,0 +1,147 @@
+package authz
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/services"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeReporter emits fixed grants, optionally failing after them
+type fakeReporter struct {
+	grants []*services.AccessGrant
+	err    error
+	gotOrg string
+}
+
+func (f *fakeReporter) WriteAccessReport(ctx context.Context, userID, orgID string, emit func(*services.AccessGrant) error) error {
+	f.gotOrg = orgID
+	for _, grant := range f.grants {
+		if err := emit(grant); err != nil {
+			return err
+		}
+	}
+	return f.err
+}
+
+type reportResponder struct {
+	testResponder
+}
+
+func (r *reportResponder) SendError(c *gin.Context, statusCode int, message string, err error) {
+	c.JSON(statusCode, gin.H{"error": message})
+}
+
+func reportGrants() []*services.AccessGrant {
+	return []*services.AccessGrant{
+		{Permission: "user:read", ResourceType: "user", Action: "read", Rule: services.DecisionRuleRolePermission,
+			RoleID: "ROLE1", RoleName: "viewer", Path: services.AccessPathDirect},
+		{Permission: "organization:update", ResourceType: "organization", ResourceID: "ORG*", Action: "update",
+			Rule: services.DecisionRuleResourcePermission, RoleID: "ROLE2", RoleName: "org_editor",
+			Path: services.AccessPathGroup, GroupID: "GRP1", Wildcard: true},
+	}
+}
+
+func performAccessReport(t *testing.T, reporter *fakeReporter, callerID string, roles []string, target, query string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	handler := NewHandler(&fakeChecker{}, &reportResponder{}, zap.NewNop())
+	handler.SetAccessReporter(reporter)
+
+	router := gin.New()
+	router.GET("/api/v1/users/:id/access-report", func(c *gin.Context) {
+		c.Set("user_id", callerID)
+		c.Set("roles", roles)
+	}, handler.AccessReport)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users/"+target+"/access-report"+query, nil)
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestHandler_AccessReport_JSON(t *testing.T) {
+	reporter := &fakeReporter{grants: reportGrants()}
+	w := performAccessReport(t, reporter, "USER1", nil, "USER1", "?organization_id=ORG1")
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var report AccessReportResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	assert.Equal(t, "USER1", report.UserID)
+	assert.False(t, report.GeneratedAt.IsZero())
+	require.Len(t, report.Grants, 2)
+	assert.Equal(t, "GRP1", report.Grants[1].GroupID)
+	assert.Equal(t, "ORG1", reporter.gotOrg)
+}
+
+func TestHandler_AccessReport_EmptyJSON(t *testing.T) {
+	w := performAccessReport(t, &fakeReporter{}, "USER1", nil, "USER1", "")
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var report AccessReportResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	assert.Empty(t, report.Grants)
+}
+
+func TestHandler_AccessReport_CSV(t *testing.T) {
+	w := performAccessReport(t, &fakeReporter{grants: reportGrants()}, "ADMIN1", []string{"admin"}, "USER1", "?format=csv")
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/csv")
+	rows, err := csv.NewReader(strings.NewReader(w.Body.String())).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+	assert.Equal(t, accessReportColumns, rows[0])
+	assert.Equal(t, []string{"organization:update", "organization", "ORG*", "update", "resource_permission",
+		"ROLE2", "org_editor", "group", "GRP1", "", "true"}, rows[2])
+}
+
+func TestHandler_AccessReport_Authorization(t *testing.T) {
+	tests := []struct {
+		name     string
+		callerID string
+		roles    []string
+		expected int
+	}{
+		{"self", "USER1", nil, http.StatusOK},
+		{"super_admin", "ADMIN1", []string{"super_admin"}, http.StatusOK},
+		{"admin", "ADMIN1", []string{"viewer", "admin"}, http.StatusOK},
+		{"another user", "USER2", []string{"viewer"}, http.StatusForbidden},
+		{"unauthenticated", "", nil, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := performAccessReport(t, &fakeReporter{grants: reportGrants()}, tt.callerID, tt.roles, "USER1", "")
+			assert.Equal(t, tt.expected, w.Code)
+		})
+	}
+}
+
+func TestHandler_AccessReport_Errors(t *testing.T) {
+	w := performAccessReport(t, &fakeReporter{}, "USER1", nil, "USER1", "?format=pdf")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = performAccessReport(t, &fakeReporter{err: errors.NewNotFoundError("user not found")}, "ADMIN1", []string{"admin"}, "USER9", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = performAccessReport(t, &fakeReporter{err: assert.AnError}, "USER1", nil, "USER1", "")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestHandler_AccessReport_FailureAfterStreamingLeavesReportIncomplete(t *testing.T) {
+	w := performAccessReport(t, &fakeReporter{grants: reportGrants(), err: assert.AnError}, "USER1", nil, "USER1", "")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var report AccessReportResponse
+	assert.Error(t, json.Unmarshal(w.Body.Bytes(), &report), "a report cut short is not valid JSON")
+}
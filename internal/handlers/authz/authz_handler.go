@@ -0,0 +1,93 @@
+package authz
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/internal/services"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// PermissionChecker decides permission checks; it is implemented by services.AuthorizationService
+type PermissionChecker interface {
+	CanPerform(ctx context.Context, perm *services.Permission) (bool, error)
+}
+
+// Handler answers permission checks on behalf of other services and exports users' access
+type Handler struct {
+	checker   PermissionChecker
+	reporter  AccessReporter
+	responder interfaces.Responder
+	logger    *zap.Logger
+}
+
+// NewHandler creates a new authorization check handler
+func NewHandler(checker PermissionChecker, responder interfaces.Responder, logger *zap.Logger) *Handler {
+	return &Handler{
+		checker:   checker,
+		responder: responder,
+		logger:    logger,
+	}
+}
+
+// CanResponse is the decision of a permission check
+type CanResponse struct {
+	Allowed bool `json:"allowed"`
+}
+
+// Can handles GET /api/v1/auth/can
+//
+//	@Summary		Check a user's permission
+//	@Description	Decide whether a user may perform an action on a resource, for gateways and other services enforcing access on their own routes. A denial is a 200 with allowed set to false. Resource wildcards apply, and organization_id limits the check to global roles and roles of that organization. Requires a service token with the auth:check scope.
+//	@Tags			authorization
+//	@Produce		json
+//	@Param			user_id			query		string	true	"User ID"
+//	@Param			resource		query		string	true	"Resource type"
+//	@Param			action			query		string	true	"Action"
+//	@Param			resource_id		query		string	false	"Resource ID (default: any resource of the type)"
+//	@Param			organization_id	query		string	false	"Organization the check is scoped to"
+//	@Success		200				{object}	CanResponse
+//	@Failure		400				{object}	map[string]interface{}
+//	@Failure		403				{object}	map[string]interface{}
+//	@Failure		500				{object}	map[string]interface{}
+//	@Router			/api/v1/auth/can [get]
+func (h *Handler) Can(c *gin.Context) {
+	perm := &services.Permission{
+		UserID:         c.Query("user_id"),
+		Resource:       c.Query("resource"),
+		ResourceID:     c.Query("resource_id"),
+		Action:         c.Query("action"),
+		OrganizationID: c.Query("organization_id"),
+	}
+
+	var missing []string
+	if perm.UserID == "" {
+		missing = append(missing, "user_id is required")
+	}
+	if perm.Resource == "" {
+		missing = append(missing, "resource is required")
+	}
+	if perm.Action == "" {
+		missing = append(missing, "action is required")
+	}
+	if len(missing) > 0 {
+		h.responder.SendValidationError(c, missing)
+		return
+	}
+
+	allowed, err := h.checker.CanPerform(c.Request.Context(), perm)
+	if err != nil {
+		h.logger.Error("Failed to check permission",
+			zap.String("service_id", c.GetString("service_id")),
+			zap.String("user_id", perm.UserID),
+			zap.String("resource", perm.Resource),
+			zap.String("action", perm.Action),
+			zap.Error(err))
+		h.responder.SendInternalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, CanResponse{Allowed: allowed})
+}
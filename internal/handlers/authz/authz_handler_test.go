@@ -0,0 +1,99 @@
+package authz
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeChecker records the permission it was asked about and returns a fixed decision
+type fakeChecker struct {
+	allowed bool
+	err     error
+	got     *services.Permission
+}
+
+func (f *fakeChecker) CanPerform(ctx context.Context, perm *services.Permission) (bool, error) {
+	f.got = perm
+	return f.allowed, f.err
+}
+
+type testResponder struct {
+	interfaces.Responder
+}
+
+func (r *testResponder) SendValidationError(c *gin.Context, errors []string) {
+	c.JSON(http.StatusBadRequest, gin.H{"errors": errors})
+}
+
+func (r *testResponder) SendInternalError(c *gin.Context, err error) {
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+}
+
+func performCan(t *testing.T, checker *fakeChecker, query string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/v1/auth/can", NewHandler(checker, &testResponder{}, zap.NewNop()).Can)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/auth/can?"+query, nil)
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestHandler_Can(t *testing.T) {
+	tests := []struct {
+		name    string
+		allowed bool
+	}{
+		{"allowed", true},
+		{"denied is still a 200", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := &fakeChecker{allowed: tt.allowed}
+			w := performCan(t, checker, "user_id=USR1&resource=organization&action=read&resource_id=ORG1&organization_id=ORG1")
+
+			require.Equal(t, http.StatusOK, w.Code)
+			var body CanResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			assert.Equal(t, tt.allowed, body.Allowed)
+			assert.Equal(t, &services.Permission{
+				UserID:         "USR1",
+				Resource:       "organization",
+				ResourceID:     "ORG1",
+				Action:         "read",
+				OrganizationID: "ORG1",
+			}, checker.got)
+		})
+	}
+}
+
+func TestHandler_Can_MissingParameters(t *testing.T) {
+	checker := &fakeChecker{allowed: true}
+	w := performCan(t, checker, "resource=organization")
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "user_id is required")
+	assert.Contains(t, w.Body.String(), "action is required")
+	assert.Nil(t, checker.got, "the check is not evaluated")
+}
+
+func TestHandler_Can_CheckFailure(t *testing.T) {
+	checker := &fakeChecker{err: errors.New("database unavailable")}
+	w := performCan(t, checker, "user_id=USR1&resource=organization&action=read")
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.NotContains(t, w.Body.String(), "allowed")
+}
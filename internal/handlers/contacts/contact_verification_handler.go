@@ -0,0 +1,142 @@
+package contacts
+
+import (
+	"net/http"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/requests/contacts"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// StartContactVerification handles POST /contacts/:id/verification
+//
+//	@Summary		Send a contact verification OTP
+//	@Description	Send a one-time password to the contact: by SMS for a mobile number, by email for an email address, or as configured per contact type. If delivery fails the OTP stays valid, delivered is false, and the resend endpoint retries delivery.
+//	@Tags			contacts
+//	@Produce		json
+//	@Param			id	path		string	true	"Contact ID"
+//	@Success		201	{object}	contacts.ContactVerificationResponse
+//	@Failure		400	{object}	responses.ErrorResponse
+//	@Failure		404	{object}	responses.ErrorResponse
+//	@Failure		409	{object}	responses.ErrorResponse	"Contact already verified"
+//	@Failure		500	{object}	responses.ErrorResponse
+//	@Router			/api/v1/contacts/{id}/verification [post]
+func (h *ContactHandler) StartContactVerification(c *gin.Context) {
+	contactID := c.Param("id")
+	if contactID == "" {
+		h.responder.SendValidationError(c, []string{"contact ID is required"})
+		return
+	}
+
+	verification, err := h.contactService.StartVerification(c.Request.Context(), contactID)
+	if err != nil {
+		h.logger.Error("Failed to start contact verification", zap.String("contactID", contactID), zap.Error(err))
+		h.sendVerificationError(c, err)
+		return
+	}
+
+	h.responder.SendSuccess(c, http.StatusCreated, verification)
+}
+
+// ResendContactVerification handles POST /contacts/:id/verification/resend
+//
+//	@Summary		Resend a contact verification OTP
+//	@Description	Send the verification OTP again. An OTP that could not be delivered is sent again unchanged; an OTP that was delivered is replaced by a new one.
+//	@Tags			contacts
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string										true	"Contact ID"
+//	@Param			request	body		contacts.ResendContactVerificationRequest	true	"Pending verification"
+//	@Success		200		{object}	contacts.ContactVerificationResponse
+//	@Failure		400		{object}	responses.ErrorResponse
+//	@Failure		404		{object}	responses.ErrorResponse	"Contact or verification not found"
+//	@Failure		429		{object}	responses.ErrorResponse	"Resent too recently"
+//	@Failure		500		{object}	responses.ErrorResponse
+//	@Router			/api/v1/contacts/{id}/verification/resend [post]
+func (h *ContactHandler) ResendContactVerification(c *gin.Context) {
+	contactID := c.Param("id")
+	if contactID == "" {
+		h.responder.SendValidationError(c, []string{"contact ID is required"})
+		return
+	}
+
+	var req contacts.ResendContactVerificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.responder.SendValidationError(c, []string{err.Error()})
+		return
+	}
+
+	verification, err := h.contactService.ResendVerification(c.Request.Context(), contactID, req.ChallengeID)
+	if err != nil {
+		h.logger.Warn("Failed to resend contact verification", zap.String("contactID", contactID), zap.Error(err))
+		h.sendVerificationError(c, err)
+		return
+	}
+
+	h.responder.SendSuccess(c, http.StatusOK, verification)
+}
+
+// ConfirmContactVerification handles POST /contacts/:id/verification/confirm
+//
+//	@Summary		Confirm a contact with its OTP
+//	@Description	Check the OTP sent to the contact and mark the contact verified. The verification is discarded after too many wrong OTPs.
+//	@Tags			contacts
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string										true	"Contact ID"
+//	@Param			request	body		contacts.ConfirmContactVerificationRequest	true	"Verification and OTP"
+//	@Success		200		{object}	contacts.ContactResponse
+//	@Failure		400		{object}	responses.ErrorResponse	"Invalid OTP"
+//	@Failure		404		{object}	responses.ErrorResponse	"Contact or verification not found"
+//	@Failure		409		{object}	responses.ErrorResponse	"Contact already verified or changed"
+//	@Failure		500		{object}	responses.ErrorResponse
+//	@Router			/api/v1/contacts/{id}/verification/confirm [post]
+func (h *ContactHandler) ConfirmContactVerification(c *gin.Context) {
+	contactID := c.Param("id")
+	if contactID == "" {
+		h.responder.SendValidationError(c, []string{"contact ID is required"})
+		return
+	}
+
+	userID := c.GetString("user_id")
+	if userID == "" {
+		h.responder.SendError(c, http.StatusUnauthorized, "user ID not found in context", nil)
+		return
+	}
+
+	var req contacts.ConfirmContactVerificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.responder.SendValidationError(c, []string{err.Error()})
+		return
+	}
+
+	contactResponse, err := h.contactService.ConfirmVerification(c.Request.Context(), contactID, req.ChallengeID, req.OTP, userID)
+	if err != nil {
+		h.sendVerificationError(c, err)
+		return
+	}
+
+	h.logger.Info("Contact verified", zap.String("contactID", contactID), zap.String("verifiedBy", userID))
+	h.responder.SendSuccess(c, http.StatusOK, contactResponse)
+}
+
+// sendVerificationError maps contact verification errors to HTTP responses
+func (h *ContactHandler) sendVerificationError(c *gin.Context, err error) {
+	switch e := err.(type) {
+	case *errors.ValidationError:
+		h.responder.SendValidationError(c, []string{e.Error()})
+	case *errors.BadRequestError:
+		if errors.CodeOf(e) == errors.CodeRateLimited {
+			h.responder.SendError(c, http.StatusTooManyRequests, e.Error(), e)
+			return
+		}
+		h.responder.SendError(c, http.StatusBadRequest, e.Error(), e)
+	case *errors.NotFoundError:
+		h.responder.SendError(c, http.StatusNotFound, e.Error(), e)
+	case *errors.ConflictError:
+		h.responder.SendError(c, http.StatusConflict, e.Error(), e)
+	default:
+		h.responder.SendInternalError(c, err)
+	}
+}
@@ -0,0 +1,165 @@
+package existence
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/requests"
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/responses"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// Resources whose IDs can be checked
+const (
+	ResourceUser         = "user"
+	ResourceOrganization = "organization"
+	ResourceRole         = "role"
+	ResourceGroup        = "group"
+)
+
+// Checker reports which IDs exist in one query; it is implemented by the user, organization, role
+// and group repositories
+type Checker interface {
+	ExistingIDs(ctx context.Context, ids []string, includeDeleted bool) ([]string, error)
+}
+
+// Handler serves batch existence checks, letting other services validate references without
+// fetching each entity
+type Handler struct {
+	checkers  map[string]Checker
+	responder interfaces.Responder
+	logger    *zap.Logger
+}
+
+// NewHandler creates a new existence check handler with no resources enabled
+func NewHandler(responder interfaces.Responder, logger *zap.Logger) *Handler {
+	return &Handler{
+		checkers:  make(map[string]Checker),
+		responder: responder,
+		logger:    logger,
+	}
+}
+
+// SetChecker enables existence checks for resource
+func (h *Handler) SetChecker(resource string, checker Checker) {
+	h.checkers[resource] = checker
+}
+
+// HasChecker reports whether existence checks are enabled for resource
+func (h *Handler) HasChecker(resource string) bool {
+	return h.checkers[resource] != nil
+}
+
+// UsersExist handles POST /api/v1/users/exists
+//
+//	@Summary		Check which users exist
+//	@Description	Check up to 500 user IDs in one call. Soft-deleted users are reported as missing unless include_deleted is set.
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		requests.ExistenceCheckRequest	true	"User IDs"
+//	@Success		200		{object}	responses.ExistenceCheckResponse
+//	@Failure		400		{object}	responses.ErrorResponse
+//	@Failure		500		{object}	responses.ErrorResponse
+//	@Router			/api/v1/users/exists [post]
+//	@Security		Bearer
+func (h *Handler) UsersExist(c *gin.Context) {
+	h.check(c, ResourceUser)
+}
+
+// OrganizationsExist handles POST /api/v1/organizations/exists
+//
+//	@Summary		Check which organizations exist
+//	@Description	Check up to 500 organization IDs in one call. Soft-deleted organizations are reported as missing unless include_deleted is set.
+//	@Tags			organizations
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		requests.ExistenceCheckRequest	true	"Organization IDs"
+//	@Success		200		{object}	responses.ExistenceCheckResponse
+//	@Failure		400		{object}	responses.ErrorResponse
+//	@Failure		500		{object}	responses.ErrorResponse
+//	@Router			/api/v1/organizations/exists [post]
+//	@Security		Bearer
+func (h *Handler) OrganizationsExist(c *gin.Context) {
+	h.check(c, ResourceOrganization)
+}
+
+// RolesExist handles POST /api/v1/roles/exists
+//
+//	@Summary		Check which roles exist
+//	@Description	Check up to 500 role IDs in one call. Soft-deleted roles are reported as missing unless include_deleted is set.
+//	@Tags			roles
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		requests.ExistenceCheckRequest	true	"Role IDs"
+//	@Success		200		{object}	responses.ExistenceCheckResponse
+//	@Failure		400		{object}	responses.ErrorResponse
+//	@Failure		500		{object}	responses.ErrorResponse
+//	@Router			/api/v1/roles/exists [post]
+//	@Security		Bearer
+func (h *Handler) RolesExist(c *gin.Context) {
+	h.check(c, ResourceRole)
+}
+
+// GroupsExist handles POST /api/v1/groups/exists
+//
+//	@Summary		Check which groups exist
+//	@Description	Check up to 500 group IDs in one call. Soft-deleted groups are reported as missing unless include_deleted is set.
+//	@Tags			groups
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		requests.ExistenceCheckRequest	true	"Group IDs"
+//	@Success		200		{object}	responses.ExistenceCheckResponse
+//	@Failure		400		{object}	responses.ErrorResponse
+//	@Failure		500		{object}	responses.ErrorResponse
+//	@Router			/api/v1/groups/exists [post]
+//	@Security		Bearer
+func (h *Handler) GroupsExist(c *gin.Context) {
+	h.check(c, ResourceGroup)
+}
+
+func (h *Handler) check(c *gin.Context, resource string) {
+	var req requests.ExistenceCheckRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.responder.SendValidationError(c, []string{err.Error()})
+		return
+	}
+	if err := req.Validate(); err != nil {
+		h.responder.SendValidationError(c, []string{err.Error()})
+		return
+	}
+
+	existing, err := h.checkers[resource].ExistingIDs(c.Request.Context(), req.IDs, req.IncludeDeleted)
+	if err != nil {
+		h.logger.Error("Failed to check existence",
+			zap.String("resource", resource),
+			zap.Int("count", len(req.IDs)),
+			zap.Error(err))
+		h.responder.SendInternalError(c, err)
+		return
+	}
+
+	found := make(map[string]bool, len(existing))
+	for _, id := range existing {
+		found[id] = true
+	}
+	result := &responses.ExistenceCheckResponse{
+		Existing: make([]string, 0, len(existing)),
+		Missing:  []string{},
+	}
+	for _, id := range req.IDs {
+		if found[id] {
+			result.Existing = append(result.Existing, id)
+		} else {
+			result.Missing = append(result.Missing, id)
+		}
+	}
+
+	h.logger.Debug("Existence checked",
+		zap.String("resource", resource),
+		zap.Int("existing", len(result.Existing)),
+		zap.Int("missing", len(result.Missing)))
+	h.responder.SendSuccess(c, http.StatusOK, result)
+}
@@ -0,0 +1,133 @@
+package existence
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/requests"
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/responses"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeChecker knows a fixed set of live and deleted IDs and counts its queries
+type fakeChecker struct {
+	live    []string
+	deleted []string
+	calls   int
+	gotIDs  []string
+	err     error
+}
+
+func (f *fakeChecker) ExistingIDs(ctx context.Context, ids []string, includeDeleted bool) ([]string, error) {
+	f.calls++
+	f.gotIDs = ids
+	if f.err != nil {
+		return nil, f.err
+	}
+	known := append([]string(nil), f.live...)
+	if includeDeleted {
+		known = append(known, f.deleted...)
+	}
+	var existing []string
+	for _, id := range ids {
+		for _, k := range known {
+			if id == k {
+				existing = append(existing, id)
+			}
+		}
+	}
+	return existing, nil
+}
+
+type testResponder struct {
+	interfaces.Responder
+}
+
+func (r *testResponder) SendSuccess(c *gin.Context, statusCode int, data interface{}) {
+	c.JSON(statusCode, data)
+}
+
+func (r *testResponder) SendValidationError(c *gin.Context, errors []string) {
+	c.JSON(http.StatusBadRequest, gin.H{"errors": errors})
+}
+
+func (r *testResponder) SendInternalError(c *gin.Context, err error) {
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+}
+
+func performExists(t *testing.T, checker *fakeChecker, body string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	handler := NewHandler(&testResponder{}, zap.NewNop())
+	handler.SetChecker(ResourceRole, checker)
+
+	router := gin.New()
+	router.POST("/api/v1/roles/exists", handler.RolesExist)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/roles/exists", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestHandler_Exists(t *testing.T) {
+	checker := &fakeChecker{live: []string{"ROLE1", "ROLE3"}, deleted: []string{"ROLE2"}}
+	w := performExists(t, checker, `{"ids":["ROLE3"," ROLE1","ROLE2","ROLE1",""]}`)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var result responses.ExistenceCheckResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, []string{"ROLE3", "ROLE1"}, result.Existing)
+	assert.Equal(t, []string{"ROLE2"}, result.Missing)
+	assert.Equal(t, 1, checker.calls, "all IDs are checked in one query")
+	assert.Equal(t, []string{"ROLE3", "ROLE1", "ROLE2"}, checker.gotIDs)
+}
+
+func TestHandler_Exists_IncludeDeleted(t *testing.T) {
+	checker := &fakeChecker{live: []string{"ROLE1"}, deleted: []string{"ROLE2"}}
+	w := performExists(t, checker, `{"ids":["ROLE1","ROLE2","ROLE9"],"include_deleted":true}`)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var result responses.ExistenceCheckResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, []string{"ROLE1", "ROLE2"}, result.Existing)
+	assert.Equal(t, []string{"ROLE9"}, result.Missing)
+}
+
+func TestHandler_Exists_Errors(t *testing.T) {
+	ids := make([]string, requests.MaxExistenceCheckIDs+1)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("ROLE%d", i)
+	}
+	tooMany, err := json.Marshal(requests.ExistenceCheckRequest{IDs: ids})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		checker  *fakeChecker
+		body     string
+		expected int
+	}{
+		{"no IDs", &fakeChecker{}, `{"ids":[]}`, http.StatusBadRequest},
+		{"blank IDs", &fakeChecker{}, `{"ids":[" "]}`, http.StatusBadRequest},
+		{"malformed body", &fakeChecker{}, `{"ids":`, http.StatusBadRequest},
+		{"too many IDs", &fakeChecker{}, string(tooMany), http.StatusBadRequest},
+		{"repository failure", &fakeChecker{err: assert.AnError}, `{"ids":["ROLE1"]}`, http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := performExists(t, tt.checker, tt.body)
+			assert.Equal(t, tt.expected, w.Code)
+		})
+	}
+}
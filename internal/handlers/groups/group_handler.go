@@ -6,6 +6,9 @@ import (
 
 	groupRequests "github.com/Kisanlink/aaa-service/v2/internal/entities/requests/groups"
 	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/internal/repositories/sorting"
+	"github.com/Kisanlink/aaa-service/v2/internal/utils/pagination"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
@@ -120,24 +123,26 @@ func (h *Handler) DeleteGroup(c *gin.Context) {
 // ListGroups handles GET /groups
 func (h *Handler) ListGroups(c *gin.Context) {
 	// Parse query parameters
-	limitStr := c.DefaultQuery("limit", "10")
-	offsetStr := c.DefaultQuery("offset", "0")
 	organizationID := c.Query("organization_id")
 	includeInactiveStr := c.DefaultQuery("include_inactive", "false")
 
-	limit, err := strconv.Atoi(limitStr)
-	if err != nil || limit <= 0 {
-		limit = 10
-	}
-
-	offset, err := strconv.Atoi(offsetStr)
-	if err != nil || offset < 0 {
-		offset = 0
+	paging, err := pagination.ParsePagination(c, pagination.Standard())
+	if err != nil {
+		h.responder.SendValidationError(c, []string{err.Error()})
+		return
 	}
+	limit, offset := paging.Limit, paging.Offset
 
 	includeInactive := includeInactiveStr == "true"
 
-	response, err := h.groupService.ListGroups(c.Request.Context(), limit, offset, organizationID, includeInactive)
+	order, err := sorting.Parse(c.Query("sort"), c.Query("order"), sorting.GroupFields)
+	if err != nil {
+		h.responder.SendValidationError(c, []string{err.Error()})
+		return
+	}
+	ctx := sorting.WithOrder(c.Request.Context(), order)
+
+	response, err := h.groupService.ListGroups(ctx, limit, offset, organizationID, includeInactive)
 	if err != nil {
 		h.logger.Error("Failed to list groups", zap.Error(err))
 		h.handleServiceError(c, err)
@@ -210,6 +215,7 @@ func (h *Handler) RemoveMemberFromGroup(c *gin.Context) {
 }
 
 // GetGroupMembers handles GET /groups/:id/members
+// Optional query parameters: principal_type (user or service) and include_roles
 func (h *Handler) GetGroupMembers(c *gin.Context) {
 	groupID := c.Param("id")
 	if groupID == "" {
@@ -218,27 +224,31 @@ func (h *Handler) GetGroupMembers(c *gin.Context) {
 	}
 
 	// Parse query parameters
-	limitStr := c.DefaultQuery("limit", "10")
-	offsetStr := c.DefaultQuery("offset", "0")
-
-	limit, err := strconv.Atoi(limitStr)
-	if err != nil || limit <= 0 {
-		limit = 10
+	paging, err := pagination.ParsePagination(c, pagination.Standard())
+	if err != nil {
+		h.responder.SendValidationError(c, []string{err.Error()})
+		return
 	}
+	limit, offset := paging.Limit, paging.Offset
 
-	offset, err := strconv.Atoi(offsetStr)
-	if err != nil || offset < 0 {
-		offset = 0
+	includeRoles, _ := strconv.ParseBool(c.DefaultQuery("include_roles", "false"))
+	filter := interfaces.GroupMemberFilter{
+		PrincipalType: c.Query("principal_type"),
+		IncludeRoles:  includeRoles,
 	}
 
-	response, err := h.groupService.GetGroupMembers(c.Request.Context(), groupID, limit, offset)
+	response, err := h.groupService.ListGroupMembers(c.Request.Context(), groupID, filter, limit, offset)
 	if err != nil {
 		h.logger.Error("Failed to get group members", zap.Error(err))
+		if errors.IsValidationError(err) {
+			h.responder.SendValidationError(c, []string{err.Error()})
+			return
+		}
 		h.handleServiceError(c, err)
 		return
 	}
 
-	total, err := h.groupService.CountGroupMembers(c.Request.Context(), groupID)
+	total, err := h.groupService.CountGroupMembersByType(c.Request.Context(), groupID, filter.PrincipalType)
 	if err != nil {
 		h.logger.Error("Failed to count group members", zap.Error(err))
 		h.handleServiceError(c, err)
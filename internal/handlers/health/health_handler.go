@@ -2,38 +2,125 @@ package health
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
 	"github.com/Kisanlink/kisanlink-db/pkg/db"
+	"github.com/aws/smithy-go"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
 
+const (
+	// readinessCheckTimeout bounds each dependency probe so a hung backend cannot stall the probe
+	readinessCheckTimeout = 2 * time.Second
+
+	// s3ProbeKey is looked up (never created) to confirm the bucket answers requests
+	s3ProbeKey = "healthz/probe"
+)
+
+// Dependency status values reported by the readiness probe
+const (
+	DependencyStatusUp       = "up"
+	DependencyStatusDown     = "down"
+	DependencyStatusDisabled = "disabled"
+)
+
+// DependencyStatus is the readiness result for a single downstream dependency
+type DependencyStatus struct {
+	Status    string `json:"status"`
+	Critical  bool   `json:"critical"`
+	LatencyMS int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+// dependencyCheck probes one dependency; a nil probe means the dependency is not enabled
+type dependencyCheck struct {
+	name     string
+	critical bool
+	probe    func(ctx context.Context) error
+}
+
+// cachePinger is implemented by cache backends that hold a live connection (Redis)
+type cachePinger interface {
+	Ping(ctx context.Context) error
+}
+
 // HealthHandler handles health check requests
 type HealthHandler struct {
 	dbManager    *db.DatabaseManager
 	cacheService interfaces.CacheService
+	s3Manager    *db.S3Manager
 	responder    interfaces.Responder
 	logger       *zap.Logger
+	checkTimeout time.Duration
 }
 
-// NewHealthHandler creates a new HealthHandler instance
+// NewHealthHandler creates a new HealthHandler instance.
+// s3Manager may be nil when object storage is not configured.
 func NewHealthHandler(
 	dbManager *db.DatabaseManager,
 	cacheService interfaces.CacheService,
+	s3Manager *db.S3Manager,
 	responder interfaces.Responder,
 	logger *zap.Logger,
 ) *HealthHandler {
 	return &HealthHandler{
 		dbManager:    dbManager,
 		cacheService: cacheService,
+		s3Manager:    s3Manager,
 		responder:    responder,
 		logger:       logger,
+		checkTimeout: readinessCheckTimeout,
 	}
 }
 
+// Healthz handles GET /healthz
+//
+//	@Summary		Liveness probe
+//	@Description	Returns 200 while the process is up; does not check dependencies
+//	@Tags			health
+//	@Produce		json
+//	@Success		200	{object}	map[string]interface{}
+//	@Router			/healthz [get]
+func (h *HealthHandler) Healthz(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"status":    "ok",
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+// Readyz handles GET /readyz
+//
+//	@Summary		Readiness probe
+//	@Description	Checks Postgres, Redis and S3 (when configured); returns 503 if a critical dependency is down
+//	@Tags			health
+//	@Produce		json
+//	@Success		200	{object}	map[string]interface{}
+//	@Failure		503	{object}	map[string]interface{}
+//	@Router			/readyz [get]
+func (h *HealthHandler) Readyz(c *gin.Context) {
+	results, ready := h.runDependencyChecks(c.Request.Context(), h.dependencyChecks())
+
+	status := "ready"
+	statusCode := http.StatusOK
+	if !ready {
+		status = "not_ready"
+		statusCode = http.StatusServiceUnavailable
+		h.logger.Warn("Readiness check failed", zap.Any("checks", results))
+	}
+
+	c.JSON(statusCode, gin.H{
+		"status":    status,
+		"checks":    results,
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
 // BasicHealth handles GET /health
 //
 //	@Summary		Basic health check
@@ -50,7 +137,7 @@ func (h *HealthHandler) BasicHealth(c *gin.Context) {
 		"status":    "healthy",
 		"service":   "aaa-service",
 		"version":   "2.0.0",
-		"timestamp": time.Now().Format("2006-01-02T15:04:05Z07:00"),
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
 	}
 
 	h.responder.SendSuccess(c, http.StatusOK, response)
@@ -97,7 +184,7 @@ func (h *HealthHandler) ReadinessCheck(c *gin.Context) {
 	response := map[string]interface{}{
 		"status":    overallStatus,
 		"checks":    checks,
-		"timestamp": time.Now().Format("2006-01-02T15:04:05Z07:00"),
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
 	}
 
 	statusCode := http.StatusOK
@@ -122,7 +209,7 @@ func (h *HealthHandler) LivenessCheck(c *gin.Context) {
 
 	response := map[string]interface{}{
 		"status":    "alive",
-		"timestamp": time.Now().Format("2006-01-02T15:04:05Z07:00"),
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
 	}
 
 	h.responder.SendSuccess(c, http.StatusOK, response)
@@ -221,3 +308,122 @@ func (h *HealthHandler) checkCacheHealth(ctx context.Context) (map[string]interf
 		"response_time": responseTime.String(),
 	}, true
 }
+
+// dependencyChecks builds the probe set for /readyz. Postgres and Redis are critical;
+// S3 is reported but never fails readiness since only photo uploads depend on it.
+func (h *HealthHandler) dependencyChecks() []dependencyCheck {
+	checks := []dependencyCheck{
+		{name: "postgres", critical: true, probe: h.probePostgres},
+	}
+
+	cacheCheck := dependencyCheck{name: "redis", critical: true}
+	if pinger, ok := h.cacheService.(cachePinger); ok {
+		cacheCheck.probe = pinger.Ping
+	}
+	checks = append(checks, cacheCheck)
+
+	if h.s3Manager != nil {
+		checks = append(checks, dependencyCheck{name: "s3", critical: false, probe: h.probeS3})
+	}
+
+	return checks
+}
+
+// runDependencyChecks runs all probes concurrently, each bounded by checkTimeout.
+// It reports false if any critical dependency is down.
+func (h *HealthHandler) runDependencyChecks(ctx context.Context, checks []dependencyCheck) (map[string]DependencyStatus, bool) {
+	results := make(map[string]DependencyStatus, len(checks))
+	ready := true
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, check := range checks {
+		wg.Add(1)
+		go func(check dependencyCheck) {
+			defer wg.Done()
+			result := h.runDependencyCheck(ctx, check)
+
+			mu.Lock()
+			defer mu.Unlock()
+			results[check.name] = result
+			if check.critical && result.Status == DependencyStatusDown {
+				ready = false
+			}
+		}(check)
+	}
+	wg.Wait()
+
+	return results, ready
+}
+
+func (h *HealthHandler) runDependencyCheck(ctx context.Context, check dependencyCheck) DependencyStatus {
+	if check.probe == nil {
+		return DependencyStatus{Status: DependencyStatusDisabled, Critical: check.critical}
+	}
+
+	checkCtx, cancel := context.WithTimeout(ctx, h.checkTimeout)
+	defer cancel()
+
+	startTime := time.Now()
+
+	// Run the probe in its own goroutine so a client that ignores the context cannot exceed the timeout
+	errCh := make(chan error, 1)
+	go func() { errCh <- check.probe(checkCtx) }()
+
+	var err error
+	select {
+	case err = <-errCh:
+	case <-checkCtx.Done():
+		err = fmt.Errorf("timed out after %s", h.checkTimeout)
+	}
+
+	result := DependencyStatus{
+		Status:    DependencyStatusUp,
+		Critical:  check.critical,
+		LatencyMS: time.Since(startTime).Milliseconds(),
+	}
+	if err != nil {
+		result.Status = DependencyStatusDown
+		result.Error = err.Error()
+	}
+
+	return result
+}
+
+func (h *HealthHandler) probePostgres(ctx context.Context) error {
+	if h.dbManager == nil {
+		return fmt.Errorf("database manager not configured")
+	}
+
+	pgManager := h.dbManager.GetPostgresManager()
+	if pgManager == nil {
+		return fmt.Errorf("postgres manager not configured")
+	}
+
+	gormDB, err := pgManager.GetDB(ctx, false)
+	if err != nil {
+		return err
+	}
+
+	return gormDB.WithContext(ctx).Exec("SELECT 1").Error
+}
+
+// probeS3 issues a HEAD for a key that need not exist. Any response from S3, including
+// NotFound or AccessDenied, proves the bucket endpoint is reachable.
+func (h *HealthHandler) probeS3(ctx context.Context) error {
+	if !h.s3Manager.IsConnected() {
+		return fmt.Errorf("s3 client not connected")
+	}
+
+	err := h.s3Manager.GetByKey(ctx, s3ProbeKey, &db.S3File{})
+	if err == nil {
+		return nil
+	}
+
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		return nil
+	}
+
+	return err
+}
@@ -0,0 +1,136 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/internal/services"
+	"github.com/Kisanlink/aaa-service/v2/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newTestHealthHandler() *HealthHandler {
+	return &HealthHandler{logger: zap.NewNop(), checkTimeout: 50 * time.Millisecond}
+}
+
+func TestRunDependencyChecks(t *testing.T) {
+	up := func(ctx context.Context) error { return nil }
+	down := func(ctx context.Context) error { return errors.New("connection refused") }
+	hang := func(ctx context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	}
+
+	tests := []struct {
+		name      string
+		checks    []dependencyCheck
+		wantReady bool
+		want      map[string]string
+	}{
+		{
+			name:      "all up",
+			checks:    []dependencyCheck{{name: "postgres", critical: true, probe: up}, {name: "redis", critical: true, probe: up}},
+			wantReady: true,
+			want:      map[string]string{"postgres": DependencyStatusUp, "redis": DependencyStatusUp},
+		},
+		{
+			name:      "critical dependency down",
+			checks:    []dependencyCheck{{name: "postgres", critical: true, probe: down}, {name: "redis", critical: true, probe: up}},
+			wantReady: false,
+			want:      map[string]string{"postgres": DependencyStatusDown, "redis": DependencyStatusUp},
+		},
+		{
+			name:      "non-critical dependency down",
+			checks:    []dependencyCheck{{name: "postgres", critical: true, probe: up}, {name: "s3", critical: false, probe: down}},
+			wantReady: true,
+			want:      map[string]string{"postgres": DependencyStatusUp, "s3": DependencyStatusDown},
+		},
+		{
+			name:      "disabled dependency",
+			checks:    []dependencyCheck{{name: "postgres", critical: true, probe: up}, {name: "redis", critical: true}},
+			wantReady: true,
+			want:      map[string]string{"postgres": DependencyStatusUp, "redis": DependencyStatusDisabled},
+		},
+		{
+			name:      "hung dependency times out",
+			checks:    []dependencyCheck{{name: "postgres", critical: true, probe: hang}},
+			wantReady: false,
+			want:      map[string]string{"postgres": DependencyStatusDown},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHealthHandler()
+
+			start := time.Now()
+			results, ready := h.runDependencyChecks(context.Background(), tt.checks)
+
+			assert.Less(t, time.Since(start), 500*time.Millisecond, "checks must be time-bounded")
+			assert.Equal(t, tt.wantReady, ready)
+			require.Len(t, results, len(tt.want))
+			for name, status := range tt.want {
+				assert.Equal(t, status, results[name].Status, name)
+			}
+		})
+	}
+}
+
+func TestHealthzAndReadyz(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	// No database manager and no Redis-backed cache: postgres is down, redis is disabled
+	router.GET("/healthz", newTestHealthHandler().Healthz)
+	router.GET("/readyz", newTestHealthHandler().Readyz)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	var body struct {
+		Status string                      `json:"status"`
+		Checks map[string]DependencyStatus `json:"checks"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "not_ready", body.Status)
+	assert.Equal(t, DependencyStatusDown, body.Checks["postgres"].Status)
+	assert.True(t, body.Checks["postgres"].Critical)
+	assert.Equal(t, DependencyStatusDisabled, body.Checks["redis"].Status)
+	assert.NotContains(t, body.Checks, "s3")
+}
+
+// unreachableCache is a Redis-backed cache whose server is down
+type unreachableCache struct {
+	interfaces.CacheService
+}
+
+func (unreachableCache) Ping(ctx context.Context) error {
+	return errors.New("dial tcp: connection refused")
+}
+
+func TestReadyz_ChecksRedisBehindL1Cache(t *testing.T) {
+	h := newTestHealthHandler()
+	h.cacheService = services.NewTieredCacheService(unreachableCache{}, services.L1CacheConfig{
+		Enabled:    true,
+		MaxEntries: 100,
+		TTL:        time.Second,
+	}, utils.NewLoggerAdapter(zap.NewNop()))
+
+	results, ready := h.runDependencyChecks(context.Background(), h.dependencyChecks())
+	assert.False(t, ready)
+	assert.Equal(t, DependencyStatusDown, results["redis"].Status)
+	assert.Contains(t, results["redis"].Error, "connection refused")
+}
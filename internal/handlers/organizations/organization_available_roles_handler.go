@@ -0,0 +1,42 @@
+package organizations
+
+import (
+	"net/http"
+
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// ListAvailableRoles handles GET /organizations/:id/available-roles
+//
+//	@Summary		List roles available in an organization
+//	@Description	List the organization's own roles followed by the global roles, each tagged with its scope and whether it can currently be assigned in the organization
+//	@Tags			organizations
+//	@Produce		json
+//	@Param			id	path		string	true	"Organization ID"
+//	@Success		200	{object}	organizations.AvailableRolesResponse
+//	@Failure		400	{object}	responses.ErrorResponse
+//	@Failure		404	{object}	responses.ErrorResponse
+//	@Failure		500	{object}	responses.ErrorResponse
+//	@Router			/api/v1/organizations/{id}/available-roles [get]
+func (h *Handler) ListAvailableRoles(c *gin.Context) {
+	orgID := c.Param("id")
+	if orgID == "" {
+		h.responder.SendError(c, http.StatusBadRequest, "organization ID is required", nil)
+		return
+	}
+
+	roles, err := h.orgService.ListAvailableRoles(c.Request.Context(), orgID)
+	if err != nil {
+		h.logger.Error("Failed to list available roles", zap.Error(err), zap.String("org_id", orgID))
+		if errors.IsNotFoundError(err) {
+			h.responder.SendError(c, http.StatusNotFound, "organization not found", err)
+		} else {
+			h.responder.SendInternalError(c, err)
+		}
+		return
+	}
+
+	h.responder.SendSuccess(c, http.StatusOK, roles)
+}
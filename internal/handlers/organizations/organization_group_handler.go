@@ -5,9 +5,14 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 
 	groupRequests "github.com/Kisanlink/aaa-service/v2/internal/entities/requests/groups"
 	orgRequests "github.com/Kisanlink/aaa-service/v2/internal/entities/requests/organizations"
+	groupResponses "github.com/Kisanlink/aaa-service/v2/internal/entities/responses/groups"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/internal/repositories/sorting"
+	"github.com/Kisanlink/aaa-service/v2/internal/utils/pagination"
 	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
@@ -17,13 +22,19 @@ import (
 // GetOrganizationGroups handles GET /organizations/:orgId/groups
 //
 //	@Summary		Get organization groups
-//	@Description	Retrieve all groups within an organization with pagination
+//	@Description	Retrieve the groups within an organization with filtering, sorting and pagination. Filters combine with AND. parent_id=null lists the root groups.
 //	@Tags			organizations
 //	@Produce		json
 //	@Param			orgId				path		string	true	"Organization ID"
 //	@Param			limit				query		int		false	"Number of groups to return (default: 10, max: 100)"
 //	@Param			offset				query		int		false	"Number of groups to skip (default: 0)"
-//	@Param			include_inactive	query		bool	false	"Include inactive groups (default: false)"
+//	@Param			name				query		string	false	"Keep groups whose name contains this text"
+//	@Param			is_active			query		bool	false	"Keep only active or only inactive groups; overrides include_inactive"
+//	@Param			parent_id			query		string	false	"Keep the direct children of this group; null keeps the root groups"
+//	@Param			has_role			query		string	false	"Keep groups holding this role"
+//	@Param			include_inactive	query		bool	false	"Include inactive groups when is_active is not set (default: false)"
+//	@Param			sort				query		string	false	"Sort field: created_at, updated_at or name"
+//	@Param			order				query		string	false	"Sort order: asc or desc (default: asc)"
 //	@Success		200					{object}	organizations.OrganizationGroupListResponse
 //	@Failure		400					{object}	responses.ErrorResponse
 //	@Failure		404					{object}	responses.ErrorResponse
@@ -37,30 +48,33 @@ func (h *Handler) GetOrganizationGroups(c *gin.Context) {
 	}
 
 	// Parse query parameters
-	limitStr := c.DefaultQuery("limit", "10")
-	offsetStr := c.DefaultQuery("offset", "0")
 	includeInactiveStr := c.DefaultQuery("include_inactive", "false")
 
-	limit, err := strconv.Atoi(limitStr)
-	if err != nil || limit <= 0 {
-		limit = 10
-	}
-
-	offset, err := strconv.Atoi(offsetStr)
-	if err != nil || offset < 0 {
-		offset = 0
+	paging, err := pagination.ParsePagination(c, pagination.Standard())
+	if err != nil {
+		h.responder.SendValidationError(c, []string{err.Error()})
+		return
 	}
+	limit, offset := paging.Limit, paging.Offset
 
 	includeInactive, err := strconv.ParseBool(includeInactiveStr)
 	if err != nil {
 		includeInactive = false
 	}
 
-	// Cap limit to prevent abuse
-	if limit > 100 {
-		limit = 100
+	filter, err := parseGroupFilter(c, includeInactive)
+	if err != nil {
+		h.responder.SendValidationError(c, []string{err.Error()})
+		return
 	}
 
+	order, err := sorting.Parse(c.Query("sort"), c.Query("order"), sorting.GroupFields)
+	if err != nil {
+		h.responder.SendValidationError(c, []string{err.Error()})
+		return
+	}
+	ctx := sorting.WithOrder(c.Request.Context(), order)
+
 	// Verify organization exists
 	_, err = h.orgService.GetOrganization(c.Request.Context(), orgID)
 	if err != nil {
@@ -74,21 +88,51 @@ func (h *Handler) GetOrganizationGroups(c *gin.Context) {
 	}
 
 	// Get groups for the organization
-	groups, err := h.groupService.ListGroups(c.Request.Context(), limit, offset, orgID, includeInactive)
+	groups, total, err := h.groupService.ListOrganizationGroups(ctx, orgID, filter, limit, offset)
 	if err != nil {
 		h.logger.Error("Failed to retrieve organization groups", zap.Error(err), zap.String("org_id", orgID))
-		h.responder.SendInternalError(c, err)
+		if errors.IsValidationError(err) {
+			h.responder.SendValidationError(c, []string{err.Error()})
+		} else {
+			h.responder.SendInternalError(c, err)
+		}
 		return
 	}
 
-	total, err := h.groupService.CountGroups(c.Request.Context(), orgID, includeInactive)
-	if err != nil {
-		h.logger.Error("Failed to count organization groups", zap.Error(err), zap.String("org_id", orgID))
-		h.responder.SendInternalError(c, err)
-		return
+	h.responder.SendPaginatedResponse(c, groups, int(total), limit, offset)
+}
+
+// parseGroupFilter reads the group listing filters from the query string. Without is_active only
+// active groups are listed unless includeInactive is set.
+func parseGroupFilter(c *gin.Context, includeInactive bool) (interfaces.GroupFilter, error) {
+	filter := interfaces.GroupFilter{
+		NameContains: strings.TrimSpace(c.Query("name")),
+		HasRole:      strings.TrimSpace(c.Query("has_role")),
 	}
 
-	h.responder.SendPaginatedResponse(c, groups, int(total), limit, offset)
+	if isActiveStr, ok := c.GetQuery("is_active"); ok {
+		isActive, err := strconv.ParseBool(isActiveStr)
+		if err != nil {
+			return filter, fmt.Errorf("invalid is_active %q: must be true or false", isActiveStr)
+		}
+		filter.IsActive = &isActive
+	} else if !includeInactive {
+		active := true
+		filter.IsActive = &active
+	}
+
+	if parentID, ok := c.GetQuery("parent_id"); ok {
+		parentID = strings.TrimSpace(parentID)
+		switch parentID {
+		case "":
+			return filter, fmt.Errorf("parent_id must be a group ID or null")
+		case "null":
+			parentID = ""
+		}
+		filter.ParentID = &parentID
+	}
+
+	return filter, nil
 }
 
 // CreateGroupInOrganization handles POST /organizations/:orgId/groups
@@ -463,6 +507,128 @@ func (h *Handler) DeleteGroupInOrganization(c *gin.Context) {
 	h.responder.SendSuccess(c, http.StatusOK, "group deleted successfully")
 }
 
+// MoveGroupInOrganization handles POST /organizations/:orgId/groups/:groupId/move
+//
+//	@Summary		Move group in organization
+//	@Description	Move a group, together with its subtree, under a different parent group in the same organization (super_admin only)
+//	@Tags			organizations
+//	@Accept			json
+//	@Produce		json
+//	@Param			orgId	path		string										true	"Organization ID"
+//	@Param			groupId	path		string										true	"Group ID"
+//	@Param			request	body		organizations.MoveOrganizationGroupRequest	true	"New parent group"
+//	@Success		200		{object}	organizations.OrganizationGroupResponse
+//	@Failure		400		{object}	responses.ErrorResponse
+//	@Failure		401		{object}	responses.ErrorResponse
+//	@Failure		403		{object}	responses.ErrorResponse
+//	@Failure		404		{object}	responses.ErrorResponse
+//	@Failure		500		{object}	responses.ErrorResponse
+//	@Router			/api/v1/organizations/{orgId}/groups/{groupId}/move [post]
+//	@Security		BearerAuth
+func (h *Handler) MoveGroupInOrganization(c *gin.Context) {
+	orgID := c.Param("id")
+	groupID := c.Param("groupId")
+
+	if orgID == "" {
+		h.responder.SendError(c, http.StatusBadRequest, "organization ID is required", nil)
+		return
+	}
+
+	if groupID == "" {
+		h.responder.SendError(c, http.StatusBadRequest, "group ID is required", nil)
+		return
+	}
+
+	var req orgRequests.MoveOrganizationGroupRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Failed to bind JSON for group move", zap.Error(err))
+		h.responder.SendError(c, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	if req.ParentID == "" {
+		h.responder.SendValidationError(c, []string{"parent_id is required"})
+		return
+	}
+
+	// Extract user ID from context (set by auth middleware)
+	userID, exists := c.Get("user_id")
+	if !exists {
+		h.logger.Error("User ID not found in context")
+		h.responder.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	// Verify organization exists
+	_, err := h.orgService.GetOrganization(c.Request.Context(), orgID)
+	if err != nil {
+		h.logger.Error("Organization not found", zap.Error(err), zap.String("org_id", orgID))
+		if errors.IsNotFoundError(err) {
+			h.responder.SendError(c, http.StatusNotFound, "organization not found", err)
+		} else {
+			h.responder.SendInternalError(c, err)
+		}
+		return
+	}
+
+	// Verify group exists and belongs to the organization
+	existingGroup, err := h.groupService.GetGroup(c.Request.Context(), groupID)
+	if err != nil {
+		h.logger.Error("Group not found", zap.Error(err), zap.String("group_id", groupID))
+		if errors.IsNotFoundError(err) {
+			h.responder.SendError(c, http.StatusNotFound, "group not found", err)
+		} else {
+			h.responder.SendInternalError(c, err)
+		}
+		return
+	}
+
+	var groupOrgID interface{}
+	switch group := existingGroup.(type) {
+	case *groupResponses.GroupResponse:
+		groupOrgID = group.OrganizationID
+	case map[string]interface{}:
+		groupOrgID = group["organization_id"]
+	}
+	if groupOrgID != nil && groupOrgID != orgID {
+		h.logger.Warn("Group does not belong to specified organization",
+			zap.String("group_id", groupID),
+			zap.String("group_org_id", fmt.Sprintf("%v", groupOrgID)),
+			zap.String("requested_org_id", orgID))
+		h.responder.SendError(c, http.StatusNotFound, "group not found in this organization", nil)
+		return
+	}
+
+	// The group service rejects parents from other organizations and moves that would form a cycle
+	movedGroup, err := h.groupService.MoveGroup(c.Request.Context(), groupID, req.ParentID, userID.(string))
+	if err != nil {
+		h.logger.Error("Failed to move group",
+			zap.Error(err),
+			zap.String("group_id", groupID),
+			zap.String("parent_id", req.ParentID),
+			zap.String("org_id", orgID))
+
+		switch {
+		case errors.IsValidationError(err):
+			h.responder.SendValidationError(c, []string{err.Error()})
+		case errors.IsNotFoundError(err):
+			h.responder.SendError(c, http.StatusNotFound, err.Error(), err)
+		default:
+			h.responder.SendInternalError(c, err)
+		}
+		return
+	}
+
+	h.logger.Info("Group moved successfully in organization",
+		zap.String("group_id", groupID),
+		zap.String("parent_id", req.ParentID),
+		zap.String("org_id", orgID),
+		zap.String("moved_by", userID.(string)))
+
+	h.responder.SendSuccess(c, http.StatusOK, movedGroup)
+}
+
 // AddUserToGroupInOrganization handles POST /organizations/:orgId/groups/:groupId/users
 //
 //	@Summary		Add user to group in organization
@@ -717,8 +883,10 @@ func (h *Handler) RemoveUserFromGroupInOrganization(c *gin.Context) {
 //	@Produce		json
 //	@Param			orgId	path		string	true	"Organization ID"
 //	@Param			groupId	path		string	true	"Group ID"
-//	@Param			limit	query		int		false	"Number of users to return (default: 10, max: 100)"
-//	@Param			offset	query		int		false	"Number of users to skip (default: 0)"
+//	@Param			limit			query		int		false	"Number of users to return (default: 10, max: 100)"
+//	@Param			offset			query		int		false	"Number of users to skip (default: 0)"
+//	@Param			principal_type	query		string	false	"Only members of this principal type"	Enums(user, service)
+//	@Param			include_roles	query		bool	false	"Include the roles each member holds through the group (default: false)"
 //	@Success		200		{object}	organizations.OrganizationGroupMembersResponse
 //	@Failure		400		{object}	responses.ErrorResponse
 //	@Failure		404		{object}	responses.ErrorResponse
@@ -739,23 +907,12 @@ func (h *Handler) GetGroupUsersInOrganization(c *gin.Context) {
 	}
 
 	// Parse query parameters
-	limitStr := c.DefaultQuery("limit", "10")
-	offsetStr := c.DefaultQuery("offset", "0")
-
-	limit, err := strconv.Atoi(limitStr)
-	if err != nil || limit <= 0 {
-		limit = 10
-	}
-
-	offset, err := strconv.Atoi(offsetStr)
-	if err != nil || offset < 0 {
-		offset = 0
-	}
-
-	// Cap limit to prevent abuse
-	if limit > 100 {
-		limit = 100
+	paging, err := pagination.ParsePagination(c, pagination.Standard())
+	if err != nil {
+		h.responder.SendValidationError(c, []string{err.Error()})
+		return
 	}
+	limit, offset := paging.Limit, paging.Offset
 
 	// Verify organization exists
 	_, err = h.orgService.GetOrganization(c.Request.Context(), orgID)
@@ -796,9 +953,18 @@ func (h *Handler) GetGroupUsersInOrganization(c *gin.Context) {
 	}
 
 	// Get group members
-	members, err := h.groupService.GetGroupMembers(c.Request.Context(), groupID, limit, offset)
+	includeRoles, _ := strconv.ParseBool(c.DefaultQuery("include_roles", "false"))
+	filter := interfaces.GroupMemberFilter{
+		PrincipalType: c.Query("principal_type"),
+		IncludeRoles:  includeRoles,
+	}
+	members, err := h.groupService.ListGroupMembers(c.Request.Context(), groupID, filter, limit, offset)
 	if err != nil {
 		h.logger.Error("Failed to retrieve group members", zap.Error(err), zap.String("group_id", groupID))
+		if errors.IsValidationError(err) {
+			h.responder.SendValidationError(c, []string{err.Error()})
+			return
+		}
 		h.responder.SendInternalError(c, err)
 		return
 	}
@@ -836,23 +1002,12 @@ func (h *Handler) GetUserGroupsInOrganization(c *gin.Context) {
 	}
 
 	// Parse query parameters
-	limitStr := c.DefaultQuery("limit", "10")
-	offsetStr := c.DefaultQuery("offset", "0")
-
-	limit, err := strconv.Atoi(limitStr)
-	if err != nil || limit <= 0 {
-		limit = 10
-	}
-
-	offset, err := strconv.Atoi(offsetStr)
-	if err != nil || offset < 0 {
-		offset = 0
-	}
-
-	// Cap limit to prevent abuse
-	if limit > 100 {
-		limit = 100
+	paging, err := pagination.ParsePagination(c, pagination.Standard())
+	if err != nil {
+		h.responder.SendValidationError(c, []string{err.Error()})
+		return
 	}
+	limit, offset := paging.Limit, paging.Offset
 
 	// Verify organization exists
 	_, err = h.orgService.GetOrganization(c.Request.Context(), orgID)
@@ -4,20 +4,29 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	orgRequests "github.com/Kisanlink/aaa-service/v2/internal/entities/requests/organizations"
+	orgResponses "github.com/Kisanlink/aaa-service/v2/internal/entities/responses/organizations"
 	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/internal/repositories/sorting"
+	"github.com/Kisanlink/aaa-service/v2/internal/utils/fieldsets"
+	"github.com/Kisanlink/aaa-service/v2/internal/utils/httpcache"
+	"github.com/Kisanlink/aaa-service/v2/internal/utils/pagination"
 	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/Kisanlink/aaa-service/v2/pkg/warnings"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
 
 // Handler handles HTTP requests for organization operations
 type Handler struct {
-	orgService   interfaces.OrganizationService
-	groupService interfaces.GroupService
-	logger       *zap.Logger
-	responder    interfaces.Responder
+	orgService      interfaces.OrganizationService
+	groupService    interfaces.GroupService
+	roleConstraints interfaces.RoleConstraintService
+	userAttributes  interfaces.UserAttributeService
+	logger          *zap.Logger
+	responder       interfaces.Responder
 }
 
 // NewOrganizationHandler creates a new organization handler instance
@@ -38,7 +47,7 @@ func NewOrganizationHandler(
 // CreateOrganization handles POST /organizations
 //
 //	@Summary		Create a new organization
-//	@Description	Create a new organization with the provided information
+//	@Description	Create a new organization with the provided information. Names nearly identical to an existing organization's are accepted and reported in the warnings list of the response.
 //	@Tags			organizations
 //	@Accept			json
 //	@Produce		json
@@ -65,8 +74,12 @@ func (h *Handler) CreateOrganization(c *gin.Context) {
 		return
 	}
 
+	// Collect non-fatal findings such as near-duplicate names for the response
+	ctx, _ := warnings.NewContext(c.Request.Context())
+	c.Request = c.Request.WithContext(ctx)
+
 	// Create organization
-	org, err := h.orgService.CreateOrganization(c.Request.Context(), &req)
+	org, err := h.orgService.CreateOrganization(ctx, &req)
 	if err != nil {
 		h.logger.Error("Failed to create organization", zap.Error(err))
 
@@ -93,11 +106,14 @@ func (h *Handler) CreateOrganization(c *gin.Context) {
 //	@Description	Retrieve an organization by its ID
 //	@Tags			organizations
 //	@Produce		json
-//	@Param			id	path		string	true	"Organization ID"
-//	@Success		200	{object}	organizations.OrganizationResponse
-//	@Failure		400	{object}	responses.ErrorResponse
-//	@Failure		404	{object}	responses.ErrorResponse
-//	@Failure		500	{object}	responses.ErrorResponse
+//	@Param			id		path		string	true	"Organization ID"
+//	@Param			fields	query		string	false	"Comma-separated response fields to return, e.g. id,name,is_active"
+//	@Param			If-None-Match	header	string	false	"ETag of a previous response; 304 when unchanged"
+//	@Success		200		{object}	organizations.OrganizationResponse
+//	@Success		304		"Not Modified"
+//	@Failure		400		{object}	responses.ErrorResponse
+//	@Failure		404		{object}	responses.ErrorResponse
+//	@Failure		500		{object}	responses.ErrorResponse
 //	@Router			/api/v1/organizations/{id} [get]
 func (h *Handler) GetOrganization(c *gin.Context) {
 	orgID := c.Param("id")
@@ -106,6 +122,12 @@ func (h *Handler) GetOrganization(c *gin.Context) {
 		return
 	}
 
+	fields, err := fieldsets.Parse(c.Query("fields"), fieldsets.OrganizationFields)
+	if err != nil {
+		h.responder.SendValidationError(c, []string{err.Error()})
+		return
+	}
+
 	org, err := h.orgService.GetOrganization(c.Request.Context(), orgID)
 	if err != nil {
 		h.logger.Error("Failed to retrieve organization", zap.Error(err), zap.String("org_id", orgID))
@@ -118,20 +140,34 @@ func (h *Handler) GetOrganization(c *gin.Context) {
 		return
 	}
 
-	h.responder.SendSuccess(c, http.StatusOK, org)
+	projected, err := fieldsets.Project(org, fields)
+	if err != nil {
+		h.responder.SendInternalError(c, err)
+		return
+	}
+
+	var lastModified time.Time
+	if resp, ok := org.(*orgResponses.OrganizationResponse); ok && resp.UpdatedAt != nil {
+		lastModified = *resp.UpdatedAt
+	}
+	if httpcache.NotModified(c, projected, lastModified) {
+		return
+	}
+
+	h.responder.SendSuccess(c, http.StatusOK, projected)
 }
 
 // UpdateOrganization handles PUT /organizations/:id
 //
 //	@Summary		Update organization
-//	@Description	Update an existing organization with the provided information
+//	@Description	Update an existing organization with the provided information. The type can only change to a type the current type may become: organizations can formalize (e.g. individual to enterprise, farmers_group to fpo) or change line of business, but not go back to a less formal type; ngo, government and research_institute keep their type.
 //	@Tags			organizations
 //	@Accept			json
 //	@Produce		json
 //	@Param			id				path		string									true	"Organization ID"
 //	@Param			organization	body		organizations.UpdateOrganizationRequest	true	"Organization update data"
 //	@Success		200				{object}	organizations.OrganizationResponse
-//	@Failure		400				{object}	responses.ErrorResponse
+//	@Failure		400				{object}	responses.ErrorResponse	"Invalid type or type change"
 //	@Failure		404				{object}	responses.ErrorResponse
 //	@Failure		409				{object}	responses.ErrorResponse
 //	@Failure		500				{object}	responses.ErrorResponse
@@ -187,15 +223,20 @@ func (h *Handler) UpdateOrganization(c *gin.Context) {
 // DeleteOrganization handles DELETE /organizations/:id
 //
 //	@Summary		Delete organization
-//	@Description	Delete an organization by its ID
+//	@Description	Soft delete an organization by its ID. It is refused while the organization has active groups unless cascade=true, which soft-deletes its groups, their memberships and group role assignments with it in one transaction. With hard=true the organization and everything it owns are permanently deleted (super_admin only); this needs the token from POST /organizations/{id}/hard-delete-token in the X-Confirmation-Token header.
 //	@Tags			organizations
 //	@Produce		json
-//	@Param			id	path		string	true	"Organization ID"
-//	@Success		200	{object}	responses.SuccessResponse
-//	@Failure		400	{object}	responses.ErrorResponse
-//	@Failure		401	{object}	responses.ErrorResponse
-//	@Failure		404	{object}	responses.ErrorResponse
-//	@Failure		500	{object}	responses.ErrorResponse
+//	@Param			id						path		string	true	"Organization ID"
+//	@Param			cascade					query		bool	false	"Also soft delete the groups of the organization, their memberships and role assignments (default: false)"
+//	@Param			hard					query		bool	false	"Permanently delete the organization (default: false)"
+//	@Param			X-Confirmation-Token	header		string	false	"Hard delete confirmation token"
+//	@Success		200						{object}	responses.SuccessResponse
+//	@Failure		400						{object}	responses.ErrorResponse
+//	@Failure		401						{object}	responses.ErrorResponse
+//	@Failure		403						{object}	responses.ErrorResponse
+//	@Failure		404						{object}	responses.ErrorResponse
+//	@Failure		409						{object}	responses.ErrorResponse
+//	@Failure		500						{object}	responses.ErrorResponse
 //	@Router			/api/v1/organizations/{id} [delete]
 func (h *Handler) DeleteOrganization(c *gin.Context) {
 	orgID := c.Param("id")
@@ -212,6 +253,20 @@ func (h *Handler) DeleteOrganization(c *gin.Context) {
 		return
 	}
 
+	hard, _ := strconv.ParseBool(c.DefaultQuery("hard", "false"))
+	cascade, _ := strconv.ParseBool(c.DefaultQuery("cascade", "false"))
+	switch {
+	case hard && cascade:
+		h.responder.SendValidationError(c, []string{"hard and cascade cannot be combined; a hard delete always removes the groups"})
+		return
+	case hard:
+		h.hardDeleteOrganization(c, orgID, userID.(string))
+		return
+	case cascade:
+		h.deleteOrganizationCascade(c, orgID, userID.(string))
+		return
+	}
+
 	// Delete organization
 	err := h.orgService.DeleteOrganization(c.Request.Context(), orgID, userID.(string))
 	if err != nil {
@@ -244,39 +299,53 @@ func (h *Handler) DeleteOrganization(c *gin.Context) {
 //	@Param			limit				query		int		false	"Number of organizations to return (default: 10, max: 100)"
 //	@Param			offset				query		int		false	"Number of organizations to skip (default: 0)"
 //	@Param			include_inactive	query		bool	false	"Include inactive organizations (default: false)"
+//	@Param			include_deleted		query		bool	false	"Include soft-deleted organizations, super_admin only (default: false); cannot be combined with type"
 //	@Param			type				query		string	false	"Filter by organization type (enterprise, small_business, individual, fpo, cooperative, agribusiness, farmers_group, shg, ngo, government, input_supplier, trader, processing_unit, research_institute)"
+//	@Param			sort				query		string	false	"Sort field (created_at, updated_at, name, type)"
+//	@Param			order				query		string	false	"Sort direction (asc, desc)"	default(asc)
+//	@Param			fields				query		string	false	"Comma-separated response fields to return, e.g. id,name,is_active"
 //	@Success		200					{array}		organizations.OrganizationResponse
+//	@Failure		400					{object}	responses.ErrorResponse
+//	@Failure		403					{object}	responses.ErrorResponse
 //	@Failure		500					{object}	responses.ErrorResponse
 //	@Router			/api/v1/organizations [get]
 func (h *Handler) ListOrganizations(c *gin.Context) {
 	// Parse query parameters
-	limitStr := c.DefaultQuery("limit", "10")
-	offsetStr := c.DefaultQuery("offset", "0")
 	includeInactiveStr := c.DefaultQuery("include_inactive", "false")
 	orgType := c.Query("type")
 
-	limit, err := strconv.Atoi(limitStr)
-	if err != nil || limit <= 0 {
-		limit = 10
-	}
-
-	offset, err := strconv.Atoi(offsetStr)
-	if err != nil || offset < 0 {
-		offset = 0
+	paging, err := pagination.ParsePagination(c, pagination.Standard())
+	if err != nil {
+		h.responder.SendValidationError(c, []string{err.Error()})
+		return
 	}
+	limit, offset := paging.Limit, paging.Offset
 
 	includeInactive, err := strconv.ParseBool(includeInactiveStr)
 	if err != nil {
 		includeInactive = false
 	}
 
-	// Cap limit to prevent abuse
-	if limit > 100 {
-		limit = 100
+	fields, err := fieldsets.Parse(c.Query("fields"), fieldsets.OrganizationFields)
+	if err != nil {
+		h.responder.SendValidationError(c, []string{err.Error()})
+		return
+	}
+
+	if includeDeleted, _ := strconv.ParseBool(c.DefaultQuery("include_deleted", "false")); includeDeleted {
+		h.listOrganizationsWithDeleted(c, limit, offset, orgType, fields)
+		return
+	}
+
+	order, err := sorting.Parse(c.Query("sort"), c.Query("order"), sorting.OrganizationFields)
+	if err != nil {
+		h.responder.SendValidationError(c, []string{err.Error()})
+		return
 	}
+	ctx := sorting.WithOrder(c.Request.Context(), order)
 
 	// List organizations
-	orgs, err := h.orgService.ListOrganizations(c.Request.Context(), limit, offset, includeInactive, orgType)
+	orgs, err := h.orgService.ListOrganizations(ctx, limit, offset, includeInactive, orgType)
 	if err != nil {
 		h.logger.Error("Failed to list organizations", zap.Error(err))
 		h.responder.SendError(c, http.StatusInternalServerError, "failed to list organizations", nil)
@@ -290,7 +359,45 @@ func (h *Handler) ListOrganizations(c *gin.Context) {
 		return
 	}
 
-	h.responder.SendPaginatedResponse(c, orgs, int(total), limit, offset)
+	h.sendOrganizationPage(c, orgs, fields, int(total), limit, offset)
+}
+
+// listOrganizationsWithDeleted handles GET /organizations?include_deleted=true
+func (h *Handler) listOrganizationsWithDeleted(c *gin.Context, limit, offset int, orgType string, fields []string) {
+	if !hasRole(c, "super_admin") {
+		h.responder.SendError(c, http.StatusForbidden, "listing deleted organizations requires the super_admin role", nil)
+		return
+	}
+	if orgType != "" {
+		h.responder.SendValidationError(c, []string{"type cannot be combined with include_deleted"})
+		return
+	}
+
+	orgs, err := h.orgService.ListOrganizationsWithDeleted(c.Request.Context(), limit, offset)
+	if err != nil {
+		h.logger.Error("Failed to list organizations with deleted", zap.Error(err))
+		h.responder.SendError(c, http.StatusInternalServerError, "failed to list organizations", nil)
+		return
+	}
+
+	total, err := h.orgService.CountOrganizationsWithDeleted(c.Request.Context())
+	if err != nil {
+		h.logger.Error("Failed to count organizations with deleted", zap.Error(err))
+		h.responder.SendError(c, http.StatusInternalServerError, "failed to count organizations", nil)
+		return
+	}
+
+	h.sendOrganizationPage(c, orgs, fields, int(total), limit, offset)
+}
+
+// sendOrganizationPage sends a page of organizations trimmed to the requested fields
+func (h *Handler) sendOrganizationPage(c *gin.Context, orgs interface{}, fields []string, total, limit, offset int) {
+	projected, err := fieldsets.Project(orgs, fields)
+	if err != nil {
+		h.responder.SendInternalError(c, err)
+		return
+	}
+	h.responder.SendPaginatedResponse(c, projected, total, limit, offset)
 }
 
 // GetOrganizationHierarchy handles GET /organizations/:id/hierarchy
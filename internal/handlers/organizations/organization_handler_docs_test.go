@@ -0,0 +1,22 @@
+package organizations
+
+import (
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The Swagger description of the type filter lists the known organization types
+func TestListOrganizationsTypeParamListsKnownTypes(t *testing.T) {
+	source, err := os.ReadFile("organization_handler.go")
+	require.NoError(t, err)
+
+	match := regexp.MustCompile(`"Filter by organization type \(([^)]*)\)"`).FindSubmatch(source)
+	require.NotNil(t, match, "type filter parameter not documented")
+	assert.Equal(t, models.OrganizationTypes(), strings.Split(string(match[1]), ", "))
+}
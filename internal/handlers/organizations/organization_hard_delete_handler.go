@@ -0,0 +1,172 @@
+package organizations
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// HardDeleteConfirmationHeader carries the token confirming DELETE /organizations/:id?hard=true
+const HardDeleteConfirmationHeader = "X-Confirmation-Token"
+
+// RequestOrganizationHardDelete handles POST /organizations/:id/hard-delete-token
+//
+//	@Summary		Request an organization hard delete
+//	@Description	Check that an organization can be permanently deleted and issue the single-use token confirming it (super_admin only). The token expires after five minutes and must be sent in the X-Confirmation-Token header of DELETE /organizations/{id}?hard=true by the same user.
+//	@Tags			organizations
+//	@Produce		json
+//	@Param			id	path		string	true	"Organization ID"
+//	@Success		200	{object}	organizations.OrganizationHardDeleteConfirmationResponse
+//	@Failure		400	{object}	responses.ErrorResponse
+//	@Failure		401	{object}	responses.ErrorResponse
+//	@Failure		403	{object}	responses.ErrorResponse
+//	@Failure		404	{object}	responses.ErrorResponse
+//	@Failure		409	{object}	responses.ErrorResponse
+//	@Failure		500	{object}	responses.ErrorResponse
+//	@Router			/api/v1/organizations/{id}/hard-delete-token [post]
+func (h *Handler) RequestOrganizationHardDelete(c *gin.Context) {
+	orgID := c.Param("id")
+	if orgID == "" {
+		h.responder.SendError(c, http.StatusBadRequest, "organization ID is required", nil)
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		h.logger.Error("User ID not found in context")
+		h.responder.SendError(c, http.StatusUnauthorized, "user not authenticated", nil)
+		return
+	}
+
+	confirmation, err := h.orgService.RequestOrganizationHardDelete(c.Request.Context(), orgID, userID.(string))
+	if err != nil {
+		h.logger.Error("Failed to request organization hard delete", zap.Error(err), zap.String("org_id", orgID))
+		h.sendHardDeleteError(c, err)
+		return
+	}
+
+	h.responder.SendSuccess(c, http.StatusOK, confirmation)
+}
+
+// hardDeleteOrganization handles DELETE /organizations/:id?hard=true
+func (h *Handler) hardDeleteOrganization(c *gin.Context, orgID, userID string) {
+	if !hasRole(c, "super_admin") {
+		h.logger.Warn("Hard delete attempted without super_admin role",
+			zap.String("org_id", orgID),
+			zap.String("user_id", userID))
+		h.responder.SendError(c, http.StatusForbidden, "hard delete requires the super_admin role", nil)
+		return
+	}
+
+	err := h.orgService.HardDeleteOrganization(c.Request.Context(), orgID, userID, c.GetHeader(HardDeleteConfirmationHeader))
+	if err != nil {
+		h.logger.Error("Failed to hard delete organization", zap.Error(err), zap.String("org_id", orgID))
+		h.sendHardDeleteError(c, err)
+		return
+	}
+
+	h.logger.Info("Organization hard deleted successfully",
+		zap.String("org_id", orgID),
+		zap.String("deleted_by", userID))
+
+	h.responder.SendSuccess(c, http.StatusOK, "organization permanently deleted")
+}
+
+// deleteOrganizationCascade handles DELETE /organizations/:id?cascade=true
+func (h *Handler) deleteOrganizationCascade(c *gin.Context, orgID, userID string) {
+	result, err := h.orgService.DeleteOrganizationCascade(c.Request.Context(), orgID, userID)
+	if err != nil {
+		h.logger.Error("Failed to delete organization with its groups", zap.Error(err), zap.String("org_id", orgID))
+		h.sendHardDeleteError(c, err)
+		return
+	}
+
+	h.logger.Info("Organization deleted with its groups",
+		zap.String("org_id", orgID),
+		zap.String("deleted_by", userID))
+
+	h.responder.SendSuccess(c, http.StatusOK, result)
+}
+
+// RestoreOrganization handles POST /organizations/:id/restore
+//
+//	@Summary		Restore organization
+//	@Description	Restore a soft-deleted organization (super_admin only). The parent organization, if any, must not be deleted. With cascade=true the groups, memberships and group role assignments deleted with the organization by a cascading delete are restored too.
+//	@Tags			organizations
+//	@Produce		json
+//	@Param			id		path		string	true	"Organization ID"
+//	@Param			cascade	query		bool	false	"Also restore what a cascading delete removed with the organization (default: false)"
+//	@Success		200		{object}	organizations.OrganizationResponse
+//	@Failure		400		{object}	responses.ErrorResponse
+//	@Failure		401		{object}	responses.ErrorResponse
+//	@Failure		403		{object}	responses.ErrorResponse
+//	@Failure		404		{object}	responses.ErrorResponse
+//	@Failure		409		{object}	responses.ErrorResponse
+//	@Failure		500		{object}	responses.ErrorResponse
+//	@Router			/api/v1/organizations/{id}/restore [post]
+func (h *Handler) RestoreOrganization(c *gin.Context) {
+	orgID := c.Param("id")
+	if orgID == "" {
+		h.responder.SendError(c, http.StatusBadRequest, "organization ID is required", nil)
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		h.logger.Error("User ID not found in context")
+		h.responder.SendError(c, http.StatusUnauthorized, "user not authenticated", nil)
+		return
+	}
+
+	restore := h.orgService.RestoreOrganization
+	if cascade, _ := strconv.ParseBool(c.DefaultQuery("cascade", "false")); cascade {
+		restore = h.orgService.RestoreOrganizationCascade
+	}
+
+	org, err := restore(c.Request.Context(), orgID, userID.(string))
+	if err != nil {
+		h.logger.Error("Failed to restore organization", zap.Error(err), zap.String("org_id", orgID))
+		h.sendHardDeleteError(c, err)
+		return
+	}
+
+	h.logger.Info("Organization restored successfully",
+		zap.String("org_id", orgID),
+		zap.String("restored_by", userID.(string)))
+
+	h.responder.SendSuccess(c, http.StatusOK, org)
+}
+
+func (h *Handler) sendHardDeleteError(c *gin.Context, err error) {
+	switch {
+	case errors.IsValidationError(err):
+		h.responder.SendValidationError(c, []string{err.Error()})
+	case errors.IsNotFoundError(err):
+		h.responder.SendError(c, http.StatusNotFound, "organization not found", err)
+	case errors.IsConflictError(err):
+		h.responder.SendError(c, http.StatusConflict, err.Error(), err)
+	default:
+		h.responder.SendInternalError(c, err)
+	}
+}
+
+// hasRole reports whether the authenticated user holds the role, using the roles set by the auth middleware
+func hasRole(c *gin.Context, role string) bool {
+	roles, ok := c.Get("roles")
+	if !ok {
+		return false
+	}
+	names, ok := roles.([]string)
+	if !ok {
+		return false
+	}
+	for _, name := range names {
+		if name == role {
+			return true
+		}
+	}
+	return false
+}
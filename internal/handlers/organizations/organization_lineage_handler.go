@@ -0,0 +1,147 @@
+package organizations
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/utils/pagination"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// lineageLoader is GetOrganizationAncestors or GetOrganizationDescendants
+type lineageLoader func(ctx context.Context, orgID string, maxDepth, limit, offset int) (interface{}, error)
+
+// GetOrganizationAncestors handles GET /organizations/:id/ancestors
+//
+//	@Summary		List organization ancestors
+//	@Description	Retrieve the ancestors of an organization as a flat list, its parent first. Each entry carries its depth relative to the organization.
+//	@Tags			organizations
+//	@Produce		json
+//	@Param			id		path		string	true	"Organization ID"
+//	@Param			depth	query		int		false	"Number of levels to include (default: all, max: 32)"
+//	@Param			limit	query		int		false	"Number of organizations to return (default: 10, max: 100)"
+//	@Param			offset	query		int		false	"Number of organizations to skip (default: 0)"
+//	@Success		200		{object}	organizations.OrganizationLineageResponse
+//	@Failure		400		{object}	responses.ErrorResponse
+//	@Failure		404		{object}	responses.ErrorResponse
+//	@Failure		500		{object}	responses.ErrorResponse
+//	@Router			/api/v1/organizations/{id}/ancestors [get]
+func (h *Handler) GetOrganizationAncestors(c *gin.Context) {
+	h.getOrganizationLineage(c, "ancestors", h.orgService.GetOrganizationAncestors)
+}
+
+// GetOrganizationDescendants handles GET /organizations/:id/descendants
+//
+//	@Summary		List organization descendants
+//	@Description	Retrieve the descendants of an organization as a flat list ordered level by level, its direct children first. Each entry carries its depth relative to the organization.
+//	@Tags			organizations
+//	@Produce		json
+//	@Param			id		path		string	true	"Organization ID"
+//	@Param			depth	query		int		false	"Number of levels to include (default: all, max: 32)"
+//	@Param			limit	query		int		false	"Number of organizations to return (default: 10, max: 100)"
+//	@Param			offset	query		int		false	"Number of organizations to skip (default: 0)"
+//	@Success		200		{object}	organizations.OrganizationLineageResponse
+//	@Failure		400		{object}	responses.ErrorResponse
+//	@Failure		404		{object}	responses.ErrorResponse
+//	@Failure		500		{object}	responses.ErrorResponse
+//	@Router			/api/v1/organizations/{id}/descendants [get]
+func (h *Handler) GetOrganizationDescendants(c *gin.Context) {
+	h.getOrganizationLineage(c, "descendants", h.orgService.GetOrganizationDescendants)
+}
+
+func (h *Handler) getOrganizationLineage(c *gin.Context, direction string, load lineageLoader) {
+	orgID := c.Param("id")
+	if orgID == "" {
+		h.responder.SendError(c, http.StatusBadRequest, "organization ID is required", nil)
+		return
+	}
+
+	maxDepth := 0
+	if depthStr := c.Query("depth"); depthStr != "" {
+		depth, err := strconv.Atoi(depthStr)
+		if err != nil || depth < 1 {
+			h.responder.SendValidationError(c, []string{"depth must be a positive integer"})
+			return
+		}
+		maxDepth = depth
+	}
+
+	paging, err := pagination.ParsePagination(c, pagination.Standard())
+	if err != nil {
+		h.responder.SendValidationError(c, []string{err.Error()})
+		return
+	}
+
+	lineage, err := load(c.Request.Context(), orgID, maxDepth, paging.Limit, paging.Offset)
+	if err != nil {
+		h.logger.Error("Failed to retrieve organization lineage",
+			zap.Error(err),
+			zap.String("org_id", orgID),
+			zap.String("direction", direction))
+
+		switch {
+		case errors.IsValidationError(err):
+			h.responder.SendValidationError(c, []string{err.Error()})
+		case errors.IsNotFoundError(err):
+			h.responder.SendError(c, http.StatusNotFound, "organization not found", err)
+		default:
+			h.responder.SendInternalError(c, err)
+		}
+		return
+	}
+
+	h.responder.SendSuccess(c, http.StatusOK, lineage)
+}
+
+// GetGroupChildren handles GET /organizations/:id/group-hierarchy
+//
+//	@Summary		List one level of the organization group hierarchy
+//	@Description	Retrieve the root groups of an organization, or the direct children of parent_id, one page at a time. Each group carries its child count so the hierarchy can be expanded on demand.
+//	@Tags			organizations
+//	@Produce		json
+//	@Param			id			path		string	true	"Organization ID"
+//	@Param			parent_id	query		string	false	"Group whose children to list (default: the root groups)"
+//	@Param			limit		query		int		false	"Number of groups to return (default: 10, max: 100)"
+//	@Param			offset		query		int		false	"Number of groups to skip (default: 0)"
+//	@Success		200			{object}	organizations.GroupChildrenResponse
+//	@Failure		400			{object}	responses.ErrorResponse
+//	@Failure		404			{object}	responses.ErrorResponse
+//	@Failure		500			{object}	responses.ErrorResponse
+//	@Router			/api/v1/organizations/{id}/group-hierarchy [get]
+func (h *Handler) GetGroupChildren(c *gin.Context) {
+	orgID := c.Param("id")
+	if orgID == "" {
+		h.responder.SendError(c, http.StatusBadRequest, "organization ID is required", nil)
+		return
+	}
+	parentGroupID := c.Query("parent_id")
+
+	paging, err := pagination.ParsePagination(c, pagination.Standard())
+	if err != nil {
+		h.responder.SendValidationError(c, []string{err.Error()})
+		return
+	}
+
+	level, err := h.orgService.GetGroupChildren(c.Request.Context(), orgID, parentGroupID, paging.Limit, paging.Offset)
+	if err != nil {
+		h.logger.Error("Failed to retrieve group hierarchy level",
+			zap.Error(err),
+			zap.String("org_id", orgID),
+			zap.String("parent_group_id", parentGroupID))
+
+		switch {
+		case errors.IsValidationError(err):
+			h.responder.SendValidationError(c, []string{err.Error()})
+		case errors.IsNotFoundError(err):
+			h.responder.SendError(c, http.StatusNotFound, err.Error(), err)
+		default:
+			h.responder.SendInternalError(c, err)
+		}
+		return
+	}
+
+	h.responder.SendSuccess(c, http.StatusOK, level)
+}
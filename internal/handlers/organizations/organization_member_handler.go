@@ -0,0 +1,177 @@
+package organizations
+
+import (
+	"net/http"
+
+	orgRequests "github.com/Kisanlink/aaa-service/v2/internal/entities/requests/organizations"
+	"github.com/Kisanlink/aaa-service/v2/internal/utils/pagination"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// AddOrganizationMember handles POST /organizations/:id/members
+//
+//	@Summary		Add member to organization
+//	@Description	Make a user a direct member of an organization, independent of group membership
+//	@Tags			organizations
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string										true	"Organization ID"
+//	@Param			request	body		organizations.AddOrganizationMemberRequest	true	"Member data"
+//	@Success		201		{object}	organizations.OrganizationMemberResponse
+//	@Failure		400		{object}	responses.ErrorResponse
+//	@Failure		401		{object}	responses.ErrorResponse
+//	@Failure		404		{object}	responses.ErrorResponse
+//	@Failure		409		{object}	responses.ErrorResponse
+//	@Failure		500		{object}	responses.ErrorResponse
+//	@Router			/api/v1/organizations/{id}/members [post]
+func (h *Handler) AddOrganizationMember(c *gin.Context) {
+	orgID := c.Param("id")
+	if orgID == "" {
+		h.responder.SendError(c, http.StatusBadRequest, "organization ID is required", nil)
+		return
+	}
+
+	var req orgRequests.AddOrganizationMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Failed to bind JSON for organization member", zap.Error(err), zap.String("org_id", orgID))
+		h.responder.SendError(c, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	// Extract user ID from context (set by auth middleware)
+	currentUserID, exists := c.Get("user_id")
+	if !exists {
+		h.logger.Error("User ID not found in context")
+		h.responder.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	member, err := h.orgService.AddUserToOrganization(c.Request.Context(), orgID, &req, currentUserID.(string))
+	if err != nil {
+		h.logger.Error("Failed to add organization member", zap.Error(err), zap.String("org_id", orgID), zap.String("user_id", req.UserID))
+
+		switch {
+		case errors.IsValidationError(err):
+			h.responder.SendValidationError(c, []string{err.Error()})
+		case errors.IsNotFoundError(err):
+			h.responder.SendError(c, http.StatusNotFound, err.Error(), err)
+		case errors.IsConflictError(err):
+			h.responder.SendError(c, http.StatusConflict, "conflict", err)
+		default:
+			h.responder.SendInternalError(c, err)
+		}
+		return
+	}
+
+	h.logger.Info("Organization member added successfully",
+		zap.String("org_id", orgID),
+		zap.String("user_id", req.UserID),
+		zap.String("added_by", currentUserID.(string)))
+
+	h.responder.SendSuccess(c, http.StatusCreated, member)
+}
+
+// RemoveOrganizationMember handles DELETE /organizations/:id/members/:userId
+//
+//	@Summary		Remove member from organization
+//	@Description	End a user's direct membership of an organization. Depending on ORG_MEMBER_REMOVAL_POLICY, users who still belong to organization groups are either removed with a warning or refused with 409.
+//	@Tags			organizations
+//	@Produce		json
+//	@Param			id		path		string	true	"Organization ID"
+//	@Param			userId	path		string	true	"User ID"
+//	@Success		200		{object}	organizations.OrganizationMemberRemovalResponse
+//	@Failure		400		{object}	responses.ErrorResponse
+//	@Failure		401		{object}	responses.ErrorResponse
+//	@Failure		404		{object}	responses.ErrorResponse
+//	@Failure		409		{object}	responses.ErrorResponse
+//	@Failure		500		{object}	responses.ErrorResponse
+//	@Router			/api/v1/organizations/{id}/members/{userId} [delete]
+func (h *Handler) RemoveOrganizationMember(c *gin.Context) {
+	orgID := c.Param("id")
+	userID := c.Param("userId")
+
+	if orgID == "" {
+		h.responder.SendError(c, http.StatusBadRequest, "organization ID is required", nil)
+		return
+	}
+
+	if userID == "" {
+		h.responder.SendError(c, http.StatusBadRequest, "user ID is required", nil)
+		return
+	}
+
+	// Extract user ID from context (set by auth middleware)
+	currentUserID, exists := c.Get("user_id")
+	if !exists {
+		h.logger.Error("User ID not found in context")
+		h.responder.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	result, err := h.orgService.RemoveUserFromOrganization(c.Request.Context(), orgID, userID, currentUserID.(string))
+	if err != nil {
+		h.logger.Error("Failed to remove organization member", zap.Error(err), zap.String("org_id", orgID), zap.String("user_id", userID))
+
+		switch {
+		case errors.IsNotFoundError(err):
+			h.responder.SendError(c, http.StatusNotFound, err.Error(), err)
+		case errors.IsConflictError(err):
+			h.responder.SendError(c, http.StatusConflict, err.Error(), err)
+		default:
+			h.responder.SendInternalError(c, err)
+		}
+		return
+	}
+
+	h.logger.Info("Organization member removed successfully",
+		zap.String("org_id", orgID),
+		zap.String("user_id", userID),
+		zap.String("removed_by", currentUserID.(string)))
+
+	h.responder.SendSuccess(c, http.StatusOK, result)
+}
+
+// ListOrganizationMembers handles GET /organizations/:id/members
+//
+//	@Summary		List organization members
+//	@Description	Retrieve the users who are direct members of an organization
+//	@Tags			organizations
+//	@Produce		json
+//	@Param			id		path		string	true	"Organization ID"
+//	@Param			limit	query		int		false	"Number of members to return (default: 10, max: 100)"
+//	@Param			offset	query		int		false	"Number of members to skip (default: 0)"
+//	@Success		200		{object}	organizations.OrganizationMembersResponse
+//	@Failure		400		{object}	responses.ErrorResponse
+//	@Failure		404		{object}	responses.ErrorResponse
+//	@Failure		500		{object}	responses.ErrorResponse
+//	@Router			/api/v1/organizations/{id}/members [get]
+func (h *Handler) ListOrganizationMembers(c *gin.Context) {
+	orgID := c.Param("id")
+	if orgID == "" {
+		h.responder.SendError(c, http.StatusBadRequest, "organization ID is required", nil)
+		return
+	}
+
+	paging, err := pagination.ParsePagination(c, pagination.Standard())
+	if err != nil {
+		h.responder.SendValidationError(c, []string{err.Error()})
+		return
+	}
+	limit, offset := paging.Limit, paging.Offset
+
+	members, err := h.orgService.ListOrganizationMembers(c.Request.Context(), orgID, limit, offset)
+	if err != nil {
+		h.logger.Error("Failed to list organization members", zap.Error(err), zap.String("org_id", orgID))
+
+		if errors.IsNotFoundError(err) {
+			h.responder.SendError(c, http.StatusNotFound, "organization not found", err)
+		} else {
+			h.responder.SendInternalError(c, err)
+		}
+		return
+	}
+
+	h.responder.SendSuccess(c, http.StatusOK, members)
+}
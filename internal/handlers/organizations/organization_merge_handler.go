@@ -0,0 +1,79 @@
+package organizations
+
+import (
+	"net/http"
+
+	orgRequests "github.com/Kisanlink/aaa-service/v2/internal/entities/requests/organizations"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// MergeOrganization handles POST /organizations/:id/merge
+//
+//	@Summary		Merge an organization into another
+//	@Description	Merge the organization into the target organization (super_admin only), in one transaction. The target takes over its child organizations, groups, members, roles, role constraints and settings, and the organization is soft-deleted. Groups whose name the target already uses are renamed with the merged organization's name as a suffix; roles whose name the target already uses are folded into the target role, which keeps its own permissions; users who are already members keep the higher of their two member roles; settings the target already has keep the target value. Neither organization may be an ancestor of the other.
+//	@Tags			organizations
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string									true	"Organization ID to merge"
+//	@Param			request	body		organizations.MergeOrganizationsRequest	true	"Target organization"
+//	@Success		200		{object}	organizations.OrganizationMergeResponse
+//	@Failure		400		{object}	responses.ErrorResponse
+//	@Failure		401		{object}	responses.ErrorResponse
+//	@Failure		403		{object}	responses.ErrorResponse
+//	@Failure		404		{object}	responses.ErrorResponse
+//	@Failure		409		{object}	responses.ErrorResponse
+//	@Failure		500		{object}	responses.ErrorResponse
+//	@Router			/api/v1/organizations/{id}/merge [post]
+func (h *Handler) MergeOrganization(c *gin.Context) {
+	sourceID := c.Param("id")
+	if sourceID == "" {
+		h.responder.SendError(c, http.StatusBadRequest, "organization ID is required", nil)
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		h.logger.Error("User ID not found in context")
+		h.responder.SendError(c, http.StatusUnauthorized, "user not authenticated", nil)
+		return
+	}
+
+	var req orgRequests.MergeOrganizationsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Failed to bind JSON for organization merge", zap.Error(err), zap.String("org_id", sourceID))
+		h.responder.SendError(c, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+	if req.TargetID == "" {
+		h.responder.SendValidationError(c, []string{"target_id is required"})
+		return
+	}
+
+	result, err := h.orgService.MergeOrganizations(c.Request.Context(), sourceID, req.TargetID, userID.(string))
+	if err != nil {
+		h.logger.Error("Failed to merge organizations",
+			zap.Error(err),
+			zap.String("org_id", sourceID),
+			zap.String("target_id", req.TargetID))
+		switch {
+		case errors.IsValidationError(err):
+			h.responder.SendValidationError(c, []string{err.Error()})
+		case errors.IsNotFoundError(err):
+			h.responder.SendError(c, http.StatusNotFound, err.Error(), err)
+		case errors.IsConflictError(err):
+			h.responder.SendError(c, http.StatusConflict, err.Error(), err)
+		default:
+			h.responder.SendInternalError(c, err)
+		}
+		return
+	}
+
+	h.logger.Info("Organization merged successfully",
+		zap.String("org_id", sourceID),
+		zap.String("target_id", req.TargetID),
+		zap.String("merged_by", userID.(string)))
+
+	h.responder.SendSuccess(c, http.StatusOK, result)
+}
@@ -0,0 +1,59 @@
+package organizations
+
+import (
+	"net/http"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// QuotaHandler handles HTTP requests for organization rate limits and quotas
+type QuotaHandler struct {
+	quotas    interfaces.OrganizationQuotaService
+	logger    *zap.Logger
+	responder interfaces.Responder
+}
+
+// NewQuotaHandler creates a new organization quota handler instance
+func NewQuotaHandler(quotas interfaces.OrganizationQuotaService, logger *zap.Logger, responder interfaces.Responder) *QuotaHandler {
+	return &QuotaHandler{
+		quotas:    quotas,
+		logger:    logger,
+		responder: responder,
+	}
+}
+
+// GetOrganizationQuota handles GET /organizations/:id/quota
+//
+//	@Summary		Get organization quota
+//	@Description	Retrieve the per-minute rate limit and monthly API quota of an organization, with the use of each in its current window. Limits follow the organization type; a limit of 0 is unlimited. enforced=false means quotas are disabled or not backed by Redis.
+//	@Tags			organizations
+//	@Produce		json
+//	@Param			id	path		string	true	"Organization ID"
+//	@Success		200	{object}	organizations.OrganizationQuotaResponse
+//	@Failure		400	{object}	responses.ErrorResponse
+//	@Failure		404	{object}	responses.ErrorResponse
+//	@Failure		500	{object}	responses.ErrorResponse
+//	@Router			/api/v1/organizations/{id}/quota [get]
+func (h *QuotaHandler) GetOrganizationQuota(c *gin.Context) {
+	orgID := c.Param("id")
+	if orgID == "" {
+		h.responder.SendError(c, http.StatusBadRequest, "organization ID is required", nil)
+		return
+	}
+
+	status, err := h.quotas.GetQuotaStatus(c.Request.Context(), orgID)
+	if err != nil {
+		h.logger.Error("Failed to get organization quota", zap.Error(err), zap.String("org_id", orgID))
+		if errors.IsNotFoundError(err) {
+			h.responder.SendError(c, http.StatusNotFound, "organization not found", err)
+			return
+		}
+		h.responder.SendInternalError(c, err)
+		return
+	}
+
+	h.responder.SendSuccess(c, http.StatusOK, status)
+}
@@ -0,0 +1,167 @@
+package organizations
+
+import (
+	"net/http"
+
+	orgRequests "github.com/Kisanlink/aaa-service/v2/internal/entities/requests/organizations"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// SetRoleConstraintService enables the role assignment constraint endpoints
+func (h *Handler) SetRoleConstraintService(roleConstraints interfaces.RoleConstraintService) {
+	h.roleConstraints = roleConstraints
+}
+
+// ListRoleConstraints handles GET /organizations/:id/role-constraints
+//
+//	@Summary		List organization role constraints
+//	@Description	List the role assignment constraints of an organization: mutually exclusive roles, per-user role caps and per-role holder caps
+//	@Tags			organizations
+//	@Produce		json
+//	@Param			id	path		string	true	"Organization ID"
+//	@Success		200	{array}		organizations.RoleConstraintResponse
+//	@Failure		400	{object}	responses.ErrorResponse
+//	@Failure		500	{object}	responses.ErrorResponse
+//	@Router			/api/v1/organizations/{id}/role-constraints [get]
+func (h *Handler) ListRoleConstraints(c *gin.Context) {
+	orgID := c.Param("id")
+	if !h.roleConstraintsAvailable(c, orgID) {
+		return
+	}
+
+	constraints, err := h.roleConstraints.ListRoleConstraints(c.Request.Context(), orgID)
+	if err != nil {
+		h.logger.Error("Failed to list role constraints", zap.Error(err), zap.String("org_id", orgID))
+		h.sendRoleConstraintError(c, err)
+		return
+	}
+
+	h.responder.SendSuccess(c, http.StatusOK, constraints)
+}
+
+// CreateRoleConstraint handles POST /organizations/:id/role-constraints
+//
+//	@Summary		Add an organization role constraint
+//	@Description	Add a role assignment constraint to an organization. Assignments that would break it are rejected from then on; existing assignments are not changed and can be checked with the violations endpoint.
+//	@Tags			organizations
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string										true	"Organization ID"
+//	@Param			request	body		organizations.CreateRoleConstraintRequest	true	"Constraint definition"
+//	@Success		201		{object}	organizations.RoleConstraintResponse
+//	@Failure		400		{object}	responses.ErrorResponse
+//	@Failure		401		{object}	responses.ErrorResponse
+//	@Failure		403		{object}	responses.ErrorResponse
+//	@Failure		500		{object}	responses.ErrorResponse
+//	@Router			/api/v1/organizations/{id}/role-constraints [post]
+func (h *Handler) CreateRoleConstraint(c *gin.Context) {
+	orgID := c.Param("id")
+	if !h.roleConstraintsAvailable(c, orgID) {
+		return
+	}
+
+	var req orgRequests.CreateRoleConstraintRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Failed to bind JSON for role constraint", zap.Error(err), zap.String("org_id", orgID))
+		h.responder.SendError(c, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	constraint, err := h.roleConstraints.CreateRoleConstraint(c.Request.Context(), orgID, &req)
+	if err != nil {
+		h.logger.Error("Failed to create role constraint", zap.Error(err), zap.String("org_id", orgID))
+		h.sendRoleConstraintError(c, err)
+		return
+	}
+
+	h.responder.SendSuccess(c, http.StatusCreated, constraint)
+}
+
+// DeleteRoleConstraint handles DELETE /organizations/:id/role-constraints/:constraintId
+//
+//	@Summary		Remove an organization role constraint
+//	@Description	Remove a role assignment constraint from an organization
+//	@Tags			organizations
+//	@Produce		json
+//	@Param			id				path		string	true	"Organization ID"
+//	@Param			constraintId	path		string	true	"Constraint ID"
+//	@Success		200				{object}	map[string]interface{}
+//	@Failure		401				{object}	responses.ErrorResponse
+//	@Failure		403				{object}	responses.ErrorResponse
+//	@Failure		404				{object}	responses.ErrorResponse
+//	@Failure		500				{object}	responses.ErrorResponse
+//	@Router			/api/v1/organizations/{id}/role-constraints/{constraintId} [delete]
+func (h *Handler) DeleteRoleConstraint(c *gin.Context) {
+	orgID := c.Param("id")
+	if !h.roleConstraintsAvailable(c, orgID) {
+		return
+	}
+
+	constraintID := c.Param("constraintId")
+	if err := h.roleConstraints.DeleteRoleConstraint(c.Request.Context(), orgID, constraintID); err != nil {
+		h.logger.Error("Failed to delete role constraint",
+			zap.Error(err),
+			zap.String("org_id", orgID),
+			zap.String("constraint_id", constraintID))
+		h.sendRoleConstraintError(c, err)
+		return
+	}
+
+	h.responder.SendSuccess(c, http.StatusOK, map[string]interface{}{
+		"message":       "Role constraint deleted successfully",
+		"constraint_id": constraintID,
+	})
+}
+
+// ListRoleConstraintViolations handles GET /organizations/:id/role-constraints/violations
+//
+//	@Summary		List existing role constraint violations
+//	@Description	Report the current role assignments of an organization that break its constraints, typically made before a constraint was added. Nothing is revoked.
+//	@Tags			organizations
+//	@Produce		json
+//	@Param			id	path		string	true	"Organization ID"
+//	@Success		200	{object}	organizations.RoleConstraintViolationsResponse
+//	@Failure		400	{object}	responses.ErrorResponse
+//	@Failure		500	{object}	responses.ErrorResponse
+//	@Router			/api/v1/organizations/{id}/role-constraints/violations [get]
+func (h *Handler) ListRoleConstraintViolations(c *gin.Context) {
+	orgID := c.Param("id")
+	if !h.roleConstraintsAvailable(c, orgID) {
+		return
+	}
+
+	violations, err := h.roleConstraints.FindRoleConstraintViolations(c.Request.Context(), orgID)
+	if err != nil {
+		h.logger.Error("Failed to check role constraint violations", zap.Error(err), zap.String("org_id", orgID))
+		h.sendRoleConstraintError(c, err)
+		return
+	}
+
+	h.responder.SendSuccess(c, http.StatusOK, violations)
+}
+
+func (h *Handler) roleConstraintsAvailable(c *gin.Context, orgID string) bool {
+	if orgID == "" {
+		h.responder.SendError(c, http.StatusBadRequest, "organization ID is required", nil)
+		return false
+	}
+	if h.roleConstraints == nil {
+		h.responder.SendError(c, http.StatusServiceUnavailable, "role constraints are not enabled", nil)
+		return false
+	}
+	return true
+}
+
+func (h *Handler) sendRoleConstraintError(c *gin.Context, err error) {
+	switch e := err.(type) {
+	case *errors.ValidationError:
+		h.responder.SendValidationError(c, []string{e.Error()})
+	case *errors.NotFoundError:
+		h.responder.SendError(c, http.StatusNotFound, e.Error(), e)
+	default:
+		h.responder.SendInternalError(c, err)
+	}
+}
@@ -3,10 +3,11 @@ package organizations
 import (
 	"fmt"
 	"net/http"
-	"strconv"
 
 	orgRequests "github.com/Kisanlink/aaa-service/v2/internal/entities/requests/organizations"
 	groupResponses "github.com/Kisanlink/aaa-service/v2/internal/entities/responses/groups"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/internal/utils/pagination"
 	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -290,27 +291,14 @@ func (h *Handler) GetGroupRolesInOrganization(c *gin.Context) {
 		return
 	}
 
-	// Parse query parameters
-	limitStr := c.DefaultQuery("limit", "10")
-	offsetStr := c.DefaultQuery("offset", "0")
-
-	limit, err := strconv.Atoi(limitStr)
-	if err != nil || limit <= 0 {
-		limit = 10
-	}
-
-	offset, err := strconv.Atoi(offsetStr)
-	if err != nil || offset < 0 {
-		offset = 0
-	}
-
-	// Cap limit to prevent abuse
-	if limit > 100 {
-		limit = 100
+	// Group roles are not paginated yet, but malformed parameters are still rejected
+	if _, err := pagination.ParsePagination(c, pagination.Standard()); err != nil {
+		h.responder.SendValidationError(c, []string{err.Error()})
+		return
 	}
 
 	// Verify organization exists
-	_, err = h.orgService.GetOrganization(c.Request.Context(), orgID)
+	_, err := h.orgService.GetOrganization(c.Request.Context(), orgID)
 	if err != nil {
 		h.logger.Error("Organization not found", zap.Error(err), zap.String("org_id", orgID))
 		if errors.IsNotFoundError(err) {
@@ -361,11 +349,14 @@ func (h *Handler) GetGroupRolesInOrganization(c *gin.Context) {
 // GetUserEffectiveRolesInOrganization handles GET /organizations/:orgId/users/:userId/effective-roles
 //
 //	@Summary		Get user's effective roles in organization
-//	@Description	Retrieve all effective roles for a user within an organization, including inherited roles from group hierarchy
+//	@Description	Retrieve all effective roles for a user within an organization, including inherited roles from group hierarchy. The optional filters keep only the roles assigned within a group's subtree or granting a permission on a resource.
 //	@Tags			organizations
 //	@Produce		json
-//	@Param			orgId	path		string	true	"Organization ID"
-//	@Param			userId	path		string	true	"User ID"
+//	@Param			orgId			path		string	true	"Organization ID"
+//	@Param			userId			path		string	true	"User ID"
+//	@Param			group_id		query		string	false	"Keep roles assigned to this group or its descendants"
+//	@Param			resource_type	query		string	false	"Keep roles granting a permission on this resource type"
+//	@Param			resource_id		query		string	false	"With resource_type, keep roles granting a permission on this resource"
 //	@Success		200		{object}	organizations.UserEffectiveRolesResponse
 //	@Failure		400		{object}	responses.ErrorResponse
 //	@Failure		404		{object}	responses.ErrorResponse
@@ -397,8 +388,18 @@ func (h *Handler) GetUserEffectiveRolesInOrganization(c *gin.Context) {
 		return
 	}
 
-	// Get user's effective roles using the group service
-	effectiveRoles, err := h.groupService.GetUserEffectiveRoles(c.Request.Context(), orgID, userID)
+	// Get user's effective roles using the group service, narrowed to the requested scope
+	scope := interfaces.EffectiveRoleScope{
+		GroupID:      c.Query("group_id"),
+		ResourceType: c.Query("resource_type"),
+		ResourceID:   c.Query("resource_id"),
+	}
+	var effectiveRoles interface{}
+	if scope.IsEmpty() {
+		effectiveRoles, err = h.groupService.GetUserEffectiveRoles(c.Request.Context(), orgID, userID)
+	} else {
+		effectiveRoles, err = h.groupService.GetUserScopedEffectiveRoles(c.Request.Context(), orgID, userID, scope)
+	}
 	if err != nil {
 		h.logger.Error("Failed to retrieve user effective roles",
 			zap.Error(err),
@@ -0,0 +1,177 @@
+package organizations
+
+import (
+	"net/http"
+
+	orgRequests "github.com/Kisanlink/aaa-service/v2/internal/entities/requests/organizations"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// ApplyRoleTemplate handles POST /organizations/:id/apply-template
+//
+//	@Summary		Apply a role template to an organization
+//	@Description	Create the roles of a built-in or admin-defined role template as organization-scoped roles with their permissions, in one transaction. Roles the organization already has are skipped, so the call is idempotent.
+//	@Tags			organizations
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string									true	"Organization ID"
+//	@Param			request	body		organizations.ApplyRoleTemplateRequest	true	"Template to apply"
+//	@Success		200		{object}	organizations.ApplyRoleTemplateResponse
+//	@Failure		400		{object}	responses.ErrorResponse
+//	@Failure		401		{object}	responses.ErrorResponse
+//	@Failure		403		{object}	responses.ErrorResponse
+//	@Failure		404		{object}	responses.ErrorResponse
+//	@Failure		500		{object}	responses.ErrorResponse
+//	@Router			/api/v1/organizations/{id}/apply-template [post]
+func (h *Handler) ApplyRoleTemplate(c *gin.Context) {
+	orgID := c.Param("id")
+	if orgID == "" {
+		h.responder.SendError(c, http.StatusBadRequest, "organization ID is required", nil)
+		return
+	}
+
+	var req orgRequests.ApplyRoleTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Failed to bind JSON for role template", zap.Error(err), zap.String("org_id", orgID))
+		h.responder.SendError(c, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+	if req.Template == "" {
+		h.responder.SendValidationError(c, []string{"template is required"})
+		return
+	}
+
+	// Extract user ID from context (set by auth middleware)
+	currentUserID, exists := c.Get("user_id")
+	if !exists {
+		h.logger.Error("User ID not found in context")
+		h.responder.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	result, err := h.orgService.ApplyRoleTemplate(c.Request.Context(), orgID, req.Template, currentUserID.(string))
+	if err != nil {
+		h.logger.Error("Failed to apply role template",
+			zap.Error(err),
+			zap.String("org_id", orgID),
+			zap.String("template", req.Template))
+		h.sendRoleTemplateError(c, err)
+		return
+	}
+
+	h.responder.SendSuccess(c, http.StatusOK, result)
+}
+
+// ListRoleTemplates handles GET /role-templates
+//
+//	@Summary		List role templates
+//	@Description	List the built-in role templates followed by the admin-defined ones
+//	@Tags			organizations
+//	@Produce		json
+//	@Success		200	{object}	organizations.RoleTemplatesResponse
+//	@Failure		500	{object}	responses.ErrorResponse
+//	@Router			/api/v1/role-templates [get]
+func (h *Handler) ListRoleTemplates(c *gin.Context) {
+	templates, err := h.orgService.ListRoleTemplates(c.Request.Context())
+	if err != nil {
+		h.logger.Error("Failed to list role templates", zap.Error(err))
+		h.sendRoleTemplateError(c, err)
+		return
+	}
+
+	h.responder.SendSuccess(c, http.StatusOK, templates)
+}
+
+// CreateRoleTemplate handles POST /role-templates
+//
+//	@Summary		Define a role template
+//	@Description	Store an admin-defined role template that organizations can apply. Names of built-in templates are reserved.
+//	@Tags			organizations
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		organizations.CreateRoleTemplateRequest	true	"Role template"
+//	@Success		201		{object}	organizations.RoleTemplateResponse
+//	@Failure		400		{object}	responses.ErrorResponse
+//	@Failure		401		{object}	responses.ErrorResponse
+//	@Failure		403		{object}	responses.ErrorResponse
+//	@Failure		409		{object}	responses.ErrorResponse
+//	@Failure		500		{object}	responses.ErrorResponse
+//	@Router			/api/v1/role-templates [post]
+func (h *Handler) CreateRoleTemplate(c *gin.Context) {
+	var req orgRequests.CreateRoleTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Failed to bind JSON for role template", zap.Error(err))
+		h.responder.SendError(c, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	// Extract user ID from context (set by auth middleware)
+	currentUserID, exists := c.Get("user_id")
+	if !exists {
+		h.logger.Error("User ID not found in context")
+		h.responder.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	template, err := h.orgService.CreateRoleTemplate(c.Request.Context(), &req, currentUserID.(string))
+	if err != nil {
+		h.logger.Error("Failed to create role template", zap.Error(err), zap.String("name", req.Name))
+		h.sendRoleTemplateError(c, err)
+		return
+	}
+
+	h.responder.SendSuccess(c, http.StatusCreated, template)
+}
+
+// DeleteRoleTemplate handles DELETE /role-templates/:name
+//
+//	@Summary		Delete a role template
+//	@Description	Delete an admin-defined role template. Roles already created from it are kept; built-in templates cannot be deleted.
+//	@Tags			organizations
+//	@Produce		json
+//	@Param			name	path		string	true	"Template name"
+//	@Success		200		{object}	map[string]interface{}
+//	@Failure		400		{object}	responses.ErrorResponse
+//	@Failure		401		{object}	responses.ErrorResponse
+//	@Failure		403		{object}	responses.ErrorResponse
+//	@Failure		404		{object}	responses.ErrorResponse
+//	@Failure		500		{object}	responses.ErrorResponse
+//	@Router			/api/v1/role-templates/{name} [delete]
+func (h *Handler) DeleteRoleTemplate(c *gin.Context) {
+	name := c.Param("name")
+
+	// Extract user ID from context (set by auth middleware)
+	currentUserID, exists := c.Get("user_id")
+	if !exists {
+		h.logger.Error("User ID not found in context")
+		h.responder.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	if err := h.orgService.DeleteRoleTemplate(c.Request.Context(), name, currentUserID.(string)); err != nil {
+		h.logger.Error("Failed to delete role template", zap.Error(err), zap.String("name", name))
+		h.sendRoleTemplateError(c, err)
+		return
+	}
+
+	h.responder.SendSuccess(c, http.StatusOK, gin.H{"message": "role template deleted"})
+}
+
+func (h *Handler) sendRoleTemplateError(c *gin.Context, err error) {
+	switch e := err.(type) {
+	case *errors.ValidationError:
+		details := e.Details()
+		if len(details) == 0 {
+			details = []string{e.Error()}
+		}
+		h.responder.SendValidationError(c, details)
+	case *errors.NotFoundError:
+		h.responder.SendError(c, http.StatusNotFound, e.Error(), e)
+	case *errors.ConflictError:
+		h.responder.SendError(c, http.StatusConflict, e.Error(), e)
+	default:
+		h.responder.SendInternalError(c, err)
+	}
+}
@@ -0,0 +1,106 @@
+package organizations
+
+import (
+	"net/http"
+
+	orgRequests "github.com/Kisanlink/aaa-service/v2/internal/entities/requests/organizations"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// GetOrganizationSettings handles GET /organizations/:id/settings
+//
+//	@Summary		Get organization settings
+//	@Description	Retrieve every known setting of an organization. Settings that were never set report the default for the organization type and is_default=true.
+//	@Tags			organizations
+//	@Produce		json
+//	@Param			id	path		string	true	"Organization ID"
+//	@Success		200	{object}	organizations.OrganizationSettingsResponse
+//	@Failure		400	{object}	responses.ErrorResponse
+//	@Failure		404	{object}	responses.ErrorResponse
+//	@Failure		500	{object}	responses.ErrorResponse
+//	@Router			/api/v1/organizations/{id}/settings [get]
+func (h *Handler) GetOrganizationSettings(c *gin.Context) {
+	orgID := c.Param("id")
+	if orgID == "" {
+		h.responder.SendError(c, http.StatusBadRequest, "organization ID is required", nil)
+		return
+	}
+
+	settings, err := h.orgService.GetOrganizationSettings(c.Request.Context(), orgID)
+	if err != nil {
+		h.logger.Error("Failed to get organization settings", zap.Error(err), zap.String("org_id", orgID))
+		h.sendSettingsError(c, err)
+		return
+	}
+
+	h.responder.SendSuccess(c, http.StatusOK, settings)
+}
+
+// UpdateOrganizationSettings handles PUT /organizations/:id/settings
+//
+//	@Summary		Update organization settings
+//	@Description	Set one or more organization settings. Unknown keys and values of the wrong type are rejected and nothing is written; a null value resets the setting to its default.
+//	@Tags			organizations
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string											true	"Organization ID"
+//	@Param			request	body		organizations.UpdateOrganizationSettingsRequest	true	"Settings to change"
+//	@Success		200		{object}	organizations.OrganizationSettingsResponse
+//	@Failure		400		{object}	responses.ErrorResponse
+//	@Failure		401		{object}	responses.ErrorResponse
+//	@Failure		403		{object}	responses.ErrorResponse
+//	@Failure		404		{object}	responses.ErrorResponse
+//	@Failure		500		{object}	responses.ErrorResponse
+//	@Router			/api/v1/organizations/{id}/settings [put]
+func (h *Handler) UpdateOrganizationSettings(c *gin.Context) {
+	orgID := c.Param("id")
+	if orgID == "" {
+		h.responder.SendError(c, http.StatusBadRequest, "organization ID is required", nil)
+		return
+	}
+
+	var req orgRequests.UpdateOrganizationSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Failed to bind JSON for organization settings", zap.Error(err), zap.String("org_id", orgID))
+		h.responder.SendError(c, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	// Extract user ID from context (set by auth middleware)
+	currentUserID, exists := c.Get("user_id")
+	if !exists {
+		h.logger.Error("User ID not found in context")
+		h.responder.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	settings, err := h.orgService.UpdateOrganizationSettings(c.Request.Context(), orgID, &req, currentUserID.(string))
+	if err != nil {
+		h.logger.Error("Failed to update organization settings", zap.Error(err), zap.String("org_id", orgID))
+		h.sendSettingsError(c, err)
+		return
+	}
+
+	h.logger.Info("Organization settings updated successfully",
+		zap.String("org_id", orgID),
+		zap.String("updated_by", currentUserID.(string)))
+
+	h.responder.SendSuccess(c, http.StatusOK, settings)
+}
+
+func (h *Handler) sendSettingsError(c *gin.Context, err error) {
+	switch e := err.(type) {
+	case *errors.ValidationError:
+		details := e.Details()
+		if len(details) == 0 {
+			details = []string{e.Error()}
+		}
+		h.responder.SendValidationError(c, details)
+	case *errors.NotFoundError:
+		h.responder.SendError(c, http.StatusNotFound, e.Error(), e)
+	default:
+		h.responder.SendInternalError(c, err)
+	}
+}
@@ -0,0 +1,203 @@
+package organizations
+
+import (
+	"net/http"
+
+	orgRequests "github.com/Kisanlink/aaa-service/v2/internal/entities/requests/organizations"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// SetUserAttributeService enables the custom user attribute endpoints
+func (h *Handler) SetUserAttributeService(userAttributes interfaces.UserAttributeService) {
+	h.userAttributes = userAttributes
+}
+
+// GetUserAttributeSchema handles GET /organizations/:id/user-attribute-schema
+//
+//	@Summary		Get the custom user attribute schema of an organization
+//	@Description	Get the definitions the custom attributes an organization keeps on its users are validated against. An empty schema accepts any attributes.
+//	@Tags			organizations
+//	@Produce		json
+//	@Param			id	path		string	true	"Organization ID"
+//	@Success		200	{object}	organizations.UserAttributeSchemaResponse
+//	@Failure		400	{object}	responses.ErrorResponse
+//	@Failure		404	{object}	responses.ErrorResponse
+//	@Failure		500	{object}	responses.ErrorResponse
+//	@Router			/api/v1/organizations/{id}/user-attribute-schema [get]
+func (h *Handler) GetUserAttributeSchema(c *gin.Context) {
+	orgID := c.Param("id")
+	if !h.userAttributesAvailable(c, orgID) {
+		return
+	}
+
+	schema, err := h.userAttributes.GetUserAttributeSchema(c.Request.Context(), orgID)
+	if err != nil {
+		h.logger.Error("Failed to get user attribute schema", zap.Error(err), zap.String("org_id", orgID))
+		h.sendUserAttributeError(c, err)
+		return
+	}
+
+	h.responder.SendSuccess(c, http.StatusOK, schema)
+}
+
+// SetUserAttributeSchema handles PUT /organizations/:id/user-attribute-schema
+//
+//	@Summary		Replace the custom user attribute schema of an organization
+//	@Description	Replace the definitions of the custom attributes an organization keeps on its users. Attributes already stored are not revalidated; they must match the new schema the next time they are set.
+//	@Tags			organizations
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string										true	"Organization ID"
+//	@Param			request	body		organizations.SetUserAttributeSchemaRequest	true	"Attribute definitions"
+//	@Success		200		{object}	organizations.UserAttributeSchemaResponse
+//	@Failure		400		{object}	responses.ErrorResponse
+//	@Failure		401		{object}	responses.ErrorResponse
+//	@Failure		403		{object}	responses.ErrorResponse
+//	@Failure		404		{object}	responses.ErrorResponse
+//	@Failure		500		{object}	responses.ErrorResponse
+//	@Router			/api/v1/organizations/{id}/user-attribute-schema [put]
+func (h *Handler) SetUserAttributeSchema(c *gin.Context) {
+	orgID := c.Param("id")
+	if !h.userAttributesAvailable(c, orgID) {
+		return
+	}
+
+	var req orgRequests.SetUserAttributeSchemaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Failed to bind JSON for user attribute schema", zap.Error(err), zap.String("org_id", orgID))
+		h.responder.SendError(c, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	// Extract user ID from context (set by auth middleware)
+	currentUserID, exists := c.Get("user_id")
+	if !exists {
+		h.logger.Error("User ID not found in context")
+		h.responder.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	schema, err := h.userAttributes.SetUserAttributeSchema(c.Request.Context(), orgID, req.Attributes, currentUserID.(string))
+	if err != nil {
+		h.logger.Error("Failed to set user attribute schema", zap.Error(err), zap.String("org_id", orgID))
+		h.sendUserAttributeError(c, err)
+		return
+	}
+
+	h.responder.SendSuccess(c, http.StatusOK, schema)
+}
+
+// GetUserAttributes handles GET /organizations/:id/users/:userId/attributes
+//
+//	@Summary		Get a user's custom attributes in an organization
+//	@Description	Get the custom attributes an organization keeps on a user. They are empty when none have been set.
+//	@Tags			organizations
+//	@Produce		json
+//	@Param			id		path		string	true	"Organization ID"
+//	@Param			userId	path		string	true	"User ID"
+//	@Success		200		{object}	organizations.UserAttributesResponse
+//	@Failure		400		{object}	responses.ErrorResponse
+//	@Failure		404		{object}	responses.ErrorResponse
+//	@Failure		500		{object}	responses.ErrorResponse
+//	@Router			/api/v1/organizations/{id}/users/{userId}/attributes [get]
+func (h *Handler) GetUserAttributes(c *gin.Context) {
+	orgID := c.Param("id")
+	if !h.userAttributesAvailable(c, orgID) {
+		return
+	}
+
+	userID := c.Param("userId")
+	attributes, err := h.userAttributes.GetUserAttributes(c.Request.Context(), orgID, userID)
+	if err != nil {
+		h.logger.Error("Failed to get user attributes",
+			zap.Error(err),
+			zap.String("org_id", orgID),
+			zap.String("user_id", userID))
+		h.sendUserAttributeError(c, err)
+		return
+	}
+
+	h.responder.SendSuccess(c, http.StatusOK, attributes)
+}
+
+// SetUserAttributes handles PUT /organizations/:id/users/:userId/attributes
+//
+//	@Summary		Replace a user's custom attributes in an organization
+//	@Description	Replace the custom attributes an organization keeps on one of its members. They are validated against the organization's schema when it has one and nothing is written unless all are valid; null values are dropped.
+//	@Tags			organizations
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string									true	"Organization ID"
+//	@Param			userId	path		string									true	"User ID"
+//	@Param			request	body		organizations.SetUserAttributesRequest	true	"Attribute values"
+//	@Success		200		{object}	organizations.UserAttributesResponse
+//	@Failure		400		{object}	responses.ErrorResponse
+//	@Failure		401		{object}	responses.ErrorResponse
+//	@Failure		403		{object}	responses.ErrorResponse
+//	@Failure		404		{object}	responses.ErrorResponse
+//	@Failure		500		{object}	responses.ErrorResponse
+//	@Router			/api/v1/organizations/{id}/users/{userId}/attributes [put]
+func (h *Handler) SetUserAttributes(c *gin.Context) {
+	orgID := c.Param("id")
+	if !h.userAttributesAvailable(c, orgID) {
+		return
+	}
+
+	var req orgRequests.SetUserAttributesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Failed to bind JSON for user attributes", zap.Error(err), zap.String("org_id", orgID))
+		h.responder.SendError(c, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	// Extract user ID from context (set by auth middleware)
+	currentUserID, exists := c.Get("user_id")
+	if !exists {
+		h.logger.Error("User ID not found in context")
+		h.responder.SendError(c, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	userID := c.Param("userId")
+	attributes, err := h.userAttributes.SetUserAttributes(c.Request.Context(), orgID, userID, req.Attributes, currentUserID.(string))
+	if err != nil {
+		h.logger.Error("Failed to set user attributes",
+			zap.Error(err),
+			zap.String("org_id", orgID),
+			zap.String("user_id", userID))
+		h.sendUserAttributeError(c, err)
+		return
+	}
+
+	h.responder.SendSuccess(c, http.StatusOK, attributes)
+}
+
+func (h *Handler) userAttributesAvailable(c *gin.Context, orgID string) bool {
+	if orgID == "" {
+		h.responder.SendError(c, http.StatusBadRequest, "organization ID is required", nil)
+		return false
+	}
+	if h.userAttributes == nil {
+		h.responder.SendError(c, http.StatusServiceUnavailable, "custom user attributes are not enabled", nil)
+		return false
+	}
+	return true
+}
+
+func (h *Handler) sendUserAttributeError(c *gin.Context, err error) {
+	switch e := err.(type) {
+	case *errors.ValidationError:
+		details := e.Details()
+		if len(details) == 0 {
+			details = []string{e.Error()}
+		}
+		h.responder.SendValidationError(c, details)
+	case *errors.NotFoundError:
+		h.responder.SendError(c, http.StatusNotFound, e.Error(), e)
+	default:
+		h.responder.SendInternalError(c, err)
+	}
+}
@@ -13,6 +13,7 @@ import (
 
 	groupRequests "github.com/Kisanlink/aaa-service/v2/internal/entities/requests/groups"
 	groupResponses "github.com/Kisanlink/aaa-service/v2/internal/entities/responses/groups"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -54,11 +55,66 @@ func (m *MockOrganizationService) CountOrganizations(ctx context.Context, includ
 	return args.Get(0).(int64), args.Error(1)
 }
 
+func (m *MockOrganizationService) RequestOrganizationHardDelete(ctx context.Context, orgID string, requestedBy string) (interface{}, error) {
+	args := m.Called(ctx, orgID, requestedBy)
+	return args.Get(0), args.Error(1)
+}
+
+func (m *MockOrganizationService) HardDeleteOrganization(ctx context.Context, orgID string, deletedBy string, confirmationToken string) error {
+	args := m.Called(ctx, orgID, deletedBy, confirmationToken)
+	return args.Error(0)
+}
+
+func (m *MockOrganizationService) DeleteOrganizationCascade(ctx context.Context, orgID string, deletedBy string) (interface{}, error) {
+	args := m.Called(ctx, orgID, deletedBy)
+	return args.Get(0), args.Error(1)
+}
+
+func (m *MockOrganizationService) RestoreOrganization(ctx context.Context, orgID string, restoredBy string) (interface{}, error) {
+	args := m.Called(ctx, orgID, restoredBy)
+	return args.Get(0), args.Error(1)
+}
+
+func (m *MockOrganizationService) RestoreOrganizationCascade(ctx context.Context, orgID string, restoredBy string) (interface{}, error) {
+	args := m.Called(ctx, orgID, restoredBy)
+	return args.Get(0), args.Error(1)
+}
+
+func (m *MockOrganizationService) MergeOrganizations(ctx context.Context, sourceID, targetID, mergedBy string) (interface{}, error) {
+	args := m.Called(ctx, sourceID, targetID, mergedBy)
+	return args.Get(0), args.Error(1)
+}
+
+func (m *MockOrganizationService) ListOrganizationsWithDeleted(ctx context.Context, limit, offset int) ([]interface{}, error) {
+	args := m.Called(ctx, limit, offset)
+	return args.Get(0).([]interface{}), args.Error(1)
+}
+
+func (m *MockOrganizationService) CountOrganizationsWithDeleted(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 func (m *MockOrganizationService) GetOrganizationHierarchy(ctx context.Context, orgID string) (interface{}, error) {
 	args := m.Called(ctx, orgID)
 	return args.Get(0), args.Error(1)
 }
 
+func (m *MockOrganizationService) GetOrganizationAncestors(ctx context.Context, orgID string, maxDepth, limit, offset int) (interface{}, error) {
+	args := m.Called(ctx, orgID, maxDepth, limit, offset)
+	return args.Get(0), args.Error(1)
+}
+
+func (m *MockOrganizationService) GetOrganizationDescendants(ctx context.Context, orgID string, maxDepth, limit, offset int) (interface{}, error) {
+	args := m.Called(ctx, orgID, maxDepth, limit, offset)
+	return args.Get(0), args.Error(1)
+}
+
+func (m *MockOrganizationService) GetGroupChildren(ctx context.Context, orgID, parentGroupID string, limit, offset int) (interface{}, error) {
+	args := m.Called(ctx, orgID, parentGroupID, limit, offset)
+	return args.Get(0), args.Error(1)
+}
+
 func (m *MockOrganizationService) ActivateOrganization(ctx context.Context, orgID string) error {
 	args := m.Called(ctx, orgID)
 	return args.Error(0)
@@ -139,6 +195,61 @@ func (m *MockOrganizationService) GetGroupRolesInOrganization(ctx context.Contex
 	return args.Get(0), args.Error(1)
 }
 
+func (m *MockOrganizationService) AddUserToOrganization(ctx context.Context, orgID string, req interface{}, addedBy string) (interface{}, error) {
+	args := m.Called(ctx, orgID, req, addedBy)
+	return args.Get(0), args.Error(1)
+}
+
+func (m *MockOrganizationService) RemoveUserFromOrganization(ctx context.Context, orgID, userID string, removedBy string) (interface{}, error) {
+	args := m.Called(ctx, orgID, userID, removedBy)
+	return args.Get(0), args.Error(1)
+}
+
+func (m *MockOrganizationService) ListOrganizationMembers(ctx context.Context, orgID string, limit, offset int) (interface{}, error) {
+	args := m.Called(ctx, orgID, limit, offset)
+	return args.Get(0), args.Error(1)
+}
+
+func (m *MockOrganizationService) GetOrganizationSettings(ctx context.Context, orgID string) (interface{}, error) {
+	args := m.Called(ctx, orgID)
+	return args.Get(0), args.Error(1)
+}
+
+func (m *MockOrganizationService) UpdateOrganizationSettings(ctx context.Context, orgID string, req interface{}, updatedBy string) (interface{}, error) {
+	args := m.Called(ctx, orgID, req, updatedBy)
+	return args.Get(0), args.Error(1)
+}
+
+func (m *MockOrganizationService) GetSetting(ctx context.Context, orgID, key string) (interface{}, error) {
+	args := m.Called(ctx, orgID, key)
+	return args.Get(0), args.Error(1)
+}
+
+func (m *MockOrganizationService) ListRoleTemplates(ctx context.Context) (interface{}, error) {
+	args := m.Called(ctx)
+	return args.Get(0), args.Error(1)
+}
+
+func (m *MockOrganizationService) CreateRoleTemplate(ctx context.Context, req interface{}, createdBy string) (interface{}, error) {
+	args := m.Called(ctx, req, createdBy)
+	return args.Get(0), args.Error(1)
+}
+
+func (m *MockOrganizationService) DeleteRoleTemplate(ctx context.Context, name string, deletedBy string) error {
+	args := m.Called(ctx, name, deletedBy)
+	return args.Error(0)
+}
+
+func (m *MockOrganizationService) ApplyRoleTemplate(ctx context.Context, orgID, templateName, appliedBy string) (interface{}, error) {
+	args := m.Called(ctx, orgID, templateName, appliedBy)
+	return args.Get(0), args.Error(1)
+}
+
+func (m *MockOrganizationService) ListAvailableRoles(ctx context.Context, orgID string) (interface{}, error) {
+	args := m.Called(ctx, orgID)
+	return args.Get(0), args.Error(1)
+}
+
 func (m *MockOrganizationService) GetUserEffectiveRolesInOrganization(ctx context.Context, orgID, userID string) (interface{}, error) {
 	args := m.Called(ctx, orgID, userID)
 	return args.Get(0), args.Error(1)
@@ -169,11 +280,21 @@ func (m *MockGroupService) DeleteGroup(ctx context.Context, groupID string, dele
 	return args.Error(0)
 }
 
+func (m *MockGroupService) MoveGroup(ctx context.Context, groupID, newParentID, movedBy string) (interface{}, error) {
+	args := m.Called(ctx, groupID, newParentID, movedBy)
+	return args.Get(0), args.Error(1)
+}
+
 func (m *MockGroupService) ListGroups(ctx context.Context, limit, offset int, organizationID string, includeInactive bool) (interface{}, error) {
 	args := m.Called(ctx, limit, offset, organizationID, includeInactive)
 	return args.Get(0), args.Error(1)
 }
 
+func (m *MockGroupService) ListOrganizationGroups(ctx context.Context, organizationID string, filter interfaces.GroupFilter, limit, offset int) (interface{}, int64, error) {
+	args := m.Called(ctx, organizationID, filter, limit, offset)
+	return args.Get(0), args.Get(1).(int64), args.Error(2)
+}
+
 func (m *MockGroupService) AddMemberToGroup(ctx context.Context, req interface{}) (interface{}, error) {
 	args := m.Called(ctx, req)
 	return args.Get(0), args.Error(1)
@@ -209,6 +330,11 @@ func (m *MockGroupService) GetUserEffectiveRoles(ctx context.Context, orgID, use
 	return args.Get(0), args.Error(1)
 }
 
+func (m *MockGroupService) GetUserScopedEffectiveRoles(ctx context.Context, orgID, userID string, scope interfaces.EffectiveRoleScope) (interface{}, error) {
+	args := m.Called(ctx, orgID, userID, scope)
+	return args.Get(0), args.Error(1)
+}
+
 func (m *MockGroupService) CountGroups(ctx context.Context, organizationID string, includeInactive bool) (int64, error) {
 	args := m.Called(ctx, organizationID, includeInactive)
 	return args.Get(0).(int64), args.Error(1)
@@ -219,6 +345,16 @@ func (m *MockGroupService) CountGroupMembers(ctx context.Context, groupID string
 	return args.Get(0).(int64), args.Error(1)
 }
 
+func (m *MockGroupService) ListGroupMembers(ctx context.Context, groupID string, filter interfaces.GroupMemberFilter, limit, offset int) (interface{}, error) {
+	args := m.Called(ctx, groupID, filter, limit, offset)
+	return args.Get(0), args.Error(1)
+}
+
+func (m *MockGroupService) CountGroupMembersByType(ctx context.Context, groupID, principalType string) (int64, error) {
+	args := m.Called(ctx, groupID, principalType)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 func (m *MockGroupService) GetUserGroupsInOrganization(ctx context.Context, orgID, userID string, limit, offset int) (interface{}, error) {
 	args := m.Called(ctx, orgID, userID, limit, offset)
 	return args.Get(0), args.Error(1)
@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	reqRoleAssignments "github.com/Kisanlink/aaa-service/v2/internal/entities/requests/role_assignments"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
@@ -183,6 +184,7 @@ func (h *PermissionHandler) AssignResourcesToRole(c *gin.Context) {
 
 	// Assign resources through service (using individual assignment calls)
 	assignedCount := 0
+	var rejected []string
 	for _, assignment := range req.Assignments {
 		for _, action := range assignment.Actions {
 			err := h.roleAssignmentService.AssignResourceActionToRole(
@@ -193,7 +195,9 @@ func (h *PermissionHandler) AssignResourcesToRole(c *gin.Context) {
 				action,
 				assignedBy,
 			)
-			if err != nil {
+			if errors.IsValidationError(err) {
+				rejected = append(rejected, err.Error())
+			} else if err != nil {
 				h.logger.Warn("Failed to assign resource action",
 					zap.Error(err),
 					zap.String("roleID", roleID),
@@ -205,6 +209,12 @@ func (h *PermissionHandler) AssignResourcesToRole(c *gin.Context) {
 		}
 	}
 
+	// Nothing was assigned because every resource was rejected in strict mode
+	if assignedCount == 0 && len(rejected) > 0 {
+		h.responder.SendValidationError(c, rejected)
+		return
+	}
+
 	h.logger.Info("Resources assigned successfully",
 		zap.String("roleID", roleID),
 		zap.Int("assigned", assignedCount))
@@ -215,6 +225,9 @@ func (h *PermissionHandler) AssignResourcesToRole(c *gin.Context) {
 		"total_requested": len(req.Assignments),
 		"message":         "Resources assigned successfully",
 	}
+	if len(rejected) > 0 {
+		response["rejected"] = rejected
+	}
 
 	h.responder.SendSuccess(c, http.StatusOK, response)
 }
@@ -47,6 +47,11 @@ func (h *PermissionHandler) CreatePermission(c *gin.Context) {
 	if err := h.permissionService.CreatePermission(c.Request.Context(), permission); err != nil {
 		h.logger.Error("Failed to create permission", zap.Error(err))
 
+		if errors.IsValidationError(err) {
+			h.responder.SendValidationError(c, []string{err.Error()})
+			return
+		}
+
 		// Handle conflict error (duplicate permission)
 		if conflictErr, ok := err.(*errors.ConflictError); ok {
 			h.responder.SendError(c, http.StatusConflict, "Permission already exists", conflictErr)
@@ -0,0 +1,84 @@
+package permissions
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	roleAssignmentService "github.com/Kisanlink/aaa-service/v2/internal/services/role_assignments"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// GetRolePermissionHistory handles GET /api/v1/roles/:id/permission-history
+//
+//	@Summary		Get role permission history
+//	@Description	Get the permissions granted to and revoked from a role over time, oldest first, reconstructed from the audit log. Both permissions and resource-actions are included. When more than limit changes fall in the range the most recent are returned.
+//	@Tags			permissions
+//	@Accept			json
+//	@Produce		json
+//	@Param			id			path		string	true	"Role ID"
+//	@Param			start_date	query		string	false	"Start of the range (RFC3339)"
+//	@Param			end_date	query		string	false	"End of the range (RFC3339)"
+//	@Param			limit		query		int		false	"Maximum number of changes (default 500, max 1000)"
+//	@Success		200			{object}	map[string]interface{}
+//	@Failure		400			{object}	map[string]interface{}
+//	@Failure		500			{object}	map[string]interface{}
+//	@Router			/api/v1/roles/{id}/permission-history [get]
+func (h *PermissionHandler) GetRolePermissionHistory(c *gin.Context) {
+	roleID := c.Param("id")
+	if roleID == "" {
+		h.responder.SendValidationError(c, []string{"role ID is required"})
+		return
+	}
+
+	from, to, err := parseHistoryRange(c)
+	if err != nil {
+		h.responder.SendValidationError(c, []string{err.Error()})
+		return
+	}
+
+	limit := roleAssignmentService.DefaultPermissionHistoryLimit
+	if limitStr, ok := c.GetQuery("limit"); ok {
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 || limit > roleAssignmentService.MaxPermissionHistoryLimit {
+			h.responder.SendValidationError(c, []string{fmt.Sprintf("limit must be between 1 and %d", roleAssignmentService.MaxPermissionHistoryLimit)})
+			return
+		}
+	}
+
+	events, err := h.roleAssignmentService.GetPermissionHistory(c.Request.Context(), roleID, from, to, limit)
+	if err != nil {
+		h.logger.Error("Failed to get role permission history", zap.Error(err), zap.String("roleID", roleID))
+		h.responder.SendError(c, http.StatusInternalServerError, "Failed to get role permission history", err)
+		return
+	}
+
+	h.responder.SendSuccess(c, http.StatusOK, map[string]interface{}{
+		"role_id": roleID,
+		"events":  events,
+		"count":   len(events),
+	})
+}
+
+// parseHistoryRange reads the optional start_date and end_date of a history query
+func parseHistoryRange(c *gin.Context) (*time.Time, *time.Time, error) {
+	var bounds [2]*time.Time
+	for i, param := range []string{"start_date", "end_date"} {
+		raw, ok := c.GetQuery(param)
+		if !ok || strings.TrimSpace(raw) == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, strings.TrimSpace(raw))
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid %s %q: must be an RFC 3339 time", param, raw)
+		}
+		bounds[i] = &parsed
+	}
+	if bounds[0] != nil && bounds[1] != nil && bounds[1].Before(*bounds[0]) {
+		return nil, nil, fmt.Errorf("end_date must not be before start_date")
+	}
+	return bounds[0], bounds[1], nil
+}
@@ -5,6 +5,7 @@ import (
 
 	reqPermissions "github.com/Kisanlink/aaa-service/v2/internal/entities/requests/permissions"
 	respPermissions "github.com/Kisanlink/aaa-service/v2/internal/entities/responses/permissions"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
@@ -80,6 +81,10 @@ func (h *PermissionHandler) UpdatePermission(c *gin.Context) {
 	// Update permission through service
 	if err := h.permissionService.UpdatePermission(c.Request.Context(), permission); err != nil {
 		h.logger.Error("Failed to update permission", zap.Error(err), zap.String("permissionID", permissionID))
+		if errors.IsValidationError(err) {
+			h.responder.SendValidationError(c, []string{err.Error()})
+			return
+		}
 		h.responder.SendError(c, http.StatusInternalServerError, "Failed to update permission", err)
 		return
 	}
@@ -0,0 +1,30 @@
+package permissions
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// ValidatePermissions handles GET /api/v1/permissions/validation
+//
+//	@Summary		Find dangling resource references
+//	@Description	Scan permissions and role resource permissions for resource IDs that do not refer to an existing resource. Wildcard resource IDs are never reported.
+//	@Tags			permissions
+//	@Produce		json
+//	@Success		200	{object}	map[string]interface{}	"Validation report"
+//	@Failure		401	{object}	map[string]interface{}
+//	@Failure		403	{object}	map[string]interface{}
+//	@Failure		500	{object}	map[string]interface{}
+//	@Router			/api/v1/permissions/validation [get]
+func (h *PermissionHandler) ValidatePermissions(c *gin.Context) {
+	report, err := h.permissionService.ValidatePermissions(c.Request.Context())
+	if err != nil {
+		h.logger.Error("Failed to validate permissions", zap.Error(err))
+		h.responder.SendInternalError(c, err)
+		return
+	}
+
+	h.responder.SendSuccess(c, http.StatusOK, report)
+}
@@ -1,12 +1,17 @@
 package principals
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	principalRequests "github.com/Kisanlink/aaa-service/v2/internal/entities/requests/principals"
 	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
 	"github.com/Kisanlink/aaa-service/v2/internal/services/principals"
+	"github.com/Kisanlink/aaa-service/v2/internal/utils/pagination"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
@@ -100,6 +105,129 @@ func (h *Handler) GetPrincipal(c *gin.Context) {
 	h.responder.SendSuccess(c, http.StatusOK, response)
 }
 
+// GetPrincipalActivity handles GET /api/v1/principals/:id/activity
+//
+//	@Summary		Get principal activity
+//	@Description	Usage of a service principal over a window, aggregated from the audit trail: when it was last used, calls per endpoint and failure rate. Helps spot unused or abused principals whose keys should be rotated.
+//	@Tags			principals
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		string	true	"Principal or service ID"
+//	@Param			days	query		int		false	"Window in days (max 90)"	default(7)
+//	@Success		200		{object}	map[string]interface{}	"Principal activity"
+//	@Failure		400		{object}	map[string]interface{}	"Invalid window"
+//	@Failure		403		{object}	map[string]interface{}	"Admin role required"
+//	@Failure		404		{object}	map[string]interface{}	"Principal not found"
+//	@Failure		500		{object}	map[string]interface{}	"Internal server error"
+//	@Router			/api/v1/principals/{id}/activity [get]
+func (h *Handler) GetPrincipalActivity(c *gin.Context) {
+	principalID := c.Param("id")
+
+	days := principals.DefaultActivityWindowDays
+	if raw := c.Query("days"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 {
+			h.responder.SendValidationError(c, []string{"days must be a positive integer"})
+			return
+		}
+		days = parsed
+	}
+
+	response, err := h.principalService.GetPrincipalActivity(c.Request.Context(), principalID, days)
+	if err != nil {
+		h.logger.Error("Failed to get principal activity", zap.String("principal_id", principalID), zap.Error(err))
+		switch {
+		case errors.IsValidationError(err):
+			h.responder.SendValidationError(c, []string{err.Error()})
+		case errors.IsNotFoundError(err):
+			h.responder.SendError(c, http.StatusNotFound, err.Error(), err)
+		default:
+			h.handleServiceError(c, err)
+		}
+		return
+	}
+
+	h.responder.SendSuccess(c, http.StatusOK, response)
+}
+
+// ListPrincipalResourcePermissions handles GET /api/v1/principals/:id/resource-permissions
+//
+//	@Summary		List principal resource permissions
+//	@Description	Permissions a user principal holds through its roles, for debugging fine-grained access: resource permissions assigned to the roles (source direct) and named permissions of the roles (source role), with the role scope and inheriting group as conditions.
+//	@Tags			principals
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id				path		string	true	"Principal ID"
+//	@Param			resource_type	query		string	false	"Filter by resource type"
+//	@Param			limit			query		int		false	"Number of items to return"
+//	@Param			offset			query		int		false	"Number of items to skip"
+//	@Success		200				{object}	map[string]interface{}	"Paginated grants"
+//	@Failure		400				{object}	map[string]interface{}	"Not a user principal or invalid pagination"
+//	@Failure		403				{object}	map[string]interface{}	"Admin role required"
+//	@Failure		404				{object}	map[string]interface{}	"Principal not found"
+//	@Failure		500				{object}	map[string]interface{}	"Internal server error"
+//	@Router			/api/v1/principals/{id}/resource-permissions [get]
+func (h *Handler) ListPrincipalResourcePermissions(c *gin.Context) {
+	principalID := c.Param("id")
+
+	paging, err := pagination.ParsePagination(c, pagination.Standard())
+	if err != nil {
+		h.responder.SendValidationError(c, []string{err.Error()})
+		return
+	}
+
+	grants, total, err := h.principalService.ListResourcePermissions(c.Request.Context(), principalID, c.Query("resource_type"), paging.Limit, paging.Offset)
+	if err != nil {
+		h.logger.Error("Failed to list principal resource permissions", zap.String("principal_id", principalID), zap.Error(err))
+		switch {
+		case errors.IsValidationError(err):
+			h.responder.SendValidationError(c, []string{err.Error()})
+		case errors.IsNotFoundError(err):
+			h.responder.SendError(c, http.StatusNotFound, err.Error(), err)
+		default:
+			h.handleServiceError(c, err)
+		}
+		return
+	}
+
+	h.responder.SendPaginatedResponse(c, grants, int(total), paging.Limit, paging.Offset)
+}
+
+// RotateServiceCredentials handles POST /api/v1/principals/:id/rotate
+//
+//	@Summary		Rotate service principal credentials
+//	@Description	Issue a new API key for a service principal. The new key is only returned in this response; the previous key keeps working until previous_key_expires_at so dependent services can switch without downtime.
+//	@Tags			principals
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		string	true	"Principal ID"
+//	@Success		200	{object}	map[string]interface{}	"New credentials"
+//	@Failure		400	{object}	map[string]interface{}	"Not a service principal"
+//	@Failure		403	{object}	map[string]interface{}	"Admin role required"
+//	@Failure		404	{object}	map[string]interface{}	"Principal not found"
+//	@Failure		500	{object}	map[string]interface{}	"Internal server error"
+//	@Router			/api/v1/principals/{id}/rotate [post]
+func (h *Handler) RotateServiceCredentials(c *gin.Context) {
+	principalID := c.Param("id")
+
+	response, err := h.principalService.RotateServiceCredentials(c.Request.Context(), principalID)
+	if err != nil {
+		h.logger.Error("Failed to rotate service credentials", zap.String("principal_id", principalID), zap.Error(err))
+		switch {
+		case errors.IsValidationError(err):
+			h.responder.SendValidationError(c, []string{err.Error()})
+		case errors.IsNotFoundError(err):
+			h.responder.SendError(c, http.StatusNotFound, err.Error(), err)
+		default:
+			h.handleServiceError(c, err)
+		}
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	h.responder.SendSuccess(c, http.StatusOK, response)
+}
+
 // GetService handles GET /api/v1/services/:id
 //
 //	@Summary		Get service by ID
@@ -294,21 +422,95 @@ func (h *Handler) ListServices(c *gin.Context) {
 		limit = 100
 	}
 
-	response, err := h.principalService.ListServices(c.Request.Context(), limit, offset, organizationID)
+	active := true
+	filter := interfaces.ServiceFilter{OrganizationID: organizationID, IsActive: &active}
+	response, total, err := h.principalService.ListServices(c.Request.Context(), filter, limit, offset)
 	if err != nil {
 		h.logger.Error("Failed to list services", zap.Error(err))
 		h.handleServiceError(c, err)
 		return
 	}
 
-	total, err := h.principalService.CountServices(c.Request.Context(), organizationID)
+	h.responder.SendPaginatedResponse(c, response, int(total), limit, offset)
+}
+
+// ListServicePrincipals handles GET /api/v1/principals/services
+//
+//	@Summary		List service principals
+//	@Description	Get a paginated list of service principals sorted by name, with the number of API keys authenticating each (two during the grace period after a rotation) and when it last called the API, taken from the audit trail. Filters combine with AND.
+//	@Tags			principals
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			organization_id	query		string	false	"Keep services of this organization"
+//	@Param			name			query		string	false	"Keep services whose name contains this text"
+//	@Param			is_active		query		bool	false	"Keep only active or only inactive services"
+//	@Param			used_since		query		string	false	"Keep services that called the API at or after this RFC 3339 time"
+//	@Param			unused_since	query		string	false	"Keep services that have not called the API since this RFC 3339 time, including those that never did"
+//	@Param			limit			query		int		false	"Number of items to return"
+//	@Param			offset			query		int		false	"Number of items to skip"
+//	@Success		200				{object}	map[string]interface{}	"Paginated service principals"
+//	@Failure		400				{object}	map[string]interface{}	"Invalid filter or pagination"
+//	@Failure		403				{object}	map[string]interface{}	"Admin role required"
+//	@Failure		500				{object}	map[string]interface{}	"Internal server error"
+//	@Router			/api/v1/principals/services [get]
+func (h *Handler) ListServicePrincipals(c *gin.Context) {
+	paging, err := pagination.ParsePagination(c, pagination.Standard())
 	if err != nil {
-		h.logger.Error("Failed to count services", zap.Error(err))
-		h.handleServiceError(c, err)
+		h.responder.SendValidationError(c, []string{err.Error()})
 		return
 	}
 
-	h.responder.SendPaginatedResponse(c, response, int(total), limit, offset)
+	filter, err := parseServiceFilter(c)
+	if err != nil {
+		h.responder.SendValidationError(c, []string{err.Error()})
+		return
+	}
+
+	services, total, err := h.principalService.ListServices(c.Request.Context(), filter, paging.Limit, paging.Offset)
+	if err != nil {
+		h.logger.Error("Failed to list service principals", zap.Error(err))
+		if errors.IsValidationError(err) {
+			h.responder.SendValidationError(c, []string{err.Error()})
+		} else {
+			h.handleServiceError(c, err)
+		}
+		return
+	}
+
+	h.responder.SendPaginatedResponse(c, services, int(total), paging.Limit, paging.Offset)
+}
+
+// parseServiceFilter reads the service principal listing filters from the query string
+func parseServiceFilter(c *gin.Context) (interfaces.ServiceFilter, error) {
+	filter := interfaces.ServiceFilter{
+		OrganizationID: strings.TrimSpace(c.Query("organization_id")),
+		NameContains:   strings.TrimSpace(c.Query("name")),
+	}
+
+	if isActiveStr, ok := c.GetQuery("is_active"); ok {
+		isActive, err := strconv.ParseBool(isActiveStr)
+		if err != nil {
+			return filter, fmt.Errorf("invalid is_active %q: must be true or false", isActiveStr)
+		}
+		filter.IsActive = &isActive
+	}
+
+	for param, target := range map[string]**time.Time{
+		"used_since":   &filter.UsedSince,
+		"unused_since": &filter.UnusedSince,
+	} {
+		raw, ok := c.GetQuery(param)
+		if !ok {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, strings.TrimSpace(raw))
+		if err != nil {
+			return filter, fmt.Errorf("invalid %s %q: must be an RFC 3339 time", param, raw)
+		}
+		*target = &parsed
+	}
+
+	return filter, nil
 }
 
 // GenerateAPIKey handles POST /api/v1/services/generate-api-key
@@ -0,0 +1,98 @@
+package resources
+
+import (
+	"net/http"
+
+	reqResources "github.com/Kisanlink/aaa-service/v2/internal/entities/requests/resources"
+	respResources "github.com/Kisanlink/aaa-service/v2/internal/entities/responses/resources"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// RegisterResource handles POST /api/v1/resources/register
+//
+//	@Summary		Register a service resource
+//	@Description	Idempotently create or update a resource owned by the calling service and the actions allowed on it. Requires a service token with the resources:write scope.
+//	@Tags			resources
+//	@Accept			json
+//	@Produce		json
+//	@Param			resource	body		reqResources.RegisterResourceRequest	true	"Resource registration data"
+//	@Success		200			{object}	respResources.RegisteredResourceResponse	"Resource already registered and updated"
+//	@Success		201			{object}	respResources.RegisteredResourceResponse	"Resource registered"
+//	@Failure		400			{object}	map[string]interface{}
+//	@Failure		403			{object}	map[string]interface{}
+//	@Failure		404			{object}	map[string]interface{}
+//	@Failure		409			{object}	map[string]interface{}
+//	@Failure		500			{object}	map[string]interface{}
+//	@Router			/api/v1/resources/register [post]
+func (h *ResourceHandler) RegisterResource(c *gin.Context) {
+	var req reqResources.RegisterResourceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Failed to bind request", zap.Error(err))
+		h.responder.SendValidationError(c, []string{err.Error()})
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		h.logger.Error("Request validation failed", zap.Error(err))
+		h.responder.SendValidationError(c, []string{err.Error()})
+		return
+	}
+
+	h.logger.Info("Registering resource",
+		zap.String("service_id", c.GetString("service_id")),
+		zap.String("resource_id", req.ResourceID),
+		zap.String("type", req.Type))
+
+	registered, err := h.resourceService.RegisterResource(
+		c.Request.Context(), req.Type, req.ResourceID, req.ParentID, req.Attributes, req.Actions)
+	if err != nil {
+		h.logger.Error("Failed to register resource", zap.Error(err), zap.String("resource_id", req.ResourceID))
+		switch {
+		case errors.IsValidationError(err):
+			h.responder.SendValidationError(c, append([]string{err.Error()}, err.(*errors.ValidationError).Details()...))
+		case errors.IsNotFoundError(err):
+			h.responder.SendError(c, http.StatusNotFound, err.Error(), err)
+		case errors.IsConflictError(err):
+			h.responder.SendError(c, http.StatusConflict, err.Error(), err)
+		default:
+			h.responder.SendError(c, http.StatusInternalServerError, "Failed to register resource", err)
+		}
+		return
+	}
+
+	status := http.StatusOK
+	if registered.Created {
+		status = http.StatusCreated
+	}
+	h.responder.SendSuccess(c, status, &respResources.RegisteredResourceResponse{
+		Resource: respResources.NewResourceResponse(registered.Resource),
+		Actions:  registered.Actions,
+		Created:  registered.Created,
+	})
+}
+
+// ListResourceTypes handles GET /api/v1/resources/types
+//
+//	@Summary		List resource types
+//	@Description	List the resource types in use with the number of active resources of each type
+//	@Tags			resources
+//	@Produce		json
+//	@Success		200	{object}	respResources.ResourceTypesResponse
+//	@Failure		500	{object}	map[string]interface{}
+//	@Router			/api/v1/resources/types [get]
+func (h *ResourceHandler) ListResourceTypes(c *gin.Context) {
+	counts, err := h.resourceService.ListResourceTypes(c.Request.Context())
+	if err != nil {
+		h.logger.Error("Failed to list resource types", zap.Error(err))
+		h.responder.SendError(c, http.StatusInternalServerError, "Failed to list resource types", err)
+		return
+	}
+
+	response := &respResources.ResourceTypesResponse{Types: make([]respResources.ResourceTypeResponse, 0, len(counts))}
+	for _, count := range counts {
+		response.Types = append(response.Types, respResources.ResourceTypeResponse{Type: count.Type, Count: count.Count})
+	}
+	h.responder.SendSuccess(c, http.StatusOK, response)
+}
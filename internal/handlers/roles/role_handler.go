@@ -2,15 +2,20 @@ package roles
 
 import (
 	"context"
+	stdErrors "errors"
 	"fmt"
 	"net/http"
-	"strconv"
 
 	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
 	"github.com/Kisanlink/aaa-service/v2/internal/entities/requests/roles"
 	responses "github.com/Kisanlink/aaa-service/v2/internal/entities/responses"
 	roleResponses "github.com/Kisanlink/aaa-service/v2/internal/entities/responses/roles"
+	userResponses "github.com/Kisanlink/aaa-service/v2/internal/entities/responses/users"
 	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/internal/repositories/sorting"
+	"github.com/Kisanlink/aaa-service/v2/internal/utils/fieldsets"
+	"github.com/Kisanlink/aaa-service/v2/internal/utils/httpcache"
+	"github.com/Kisanlink/aaa-service/v2/internal/utils/pagination"
 	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -126,11 +131,14 @@ func (h *RoleHandler) CreateRole(c *gin.Context) {
 //	@Tags			roles
 //	@Accept			json
 //	@Produce		json
-//	@Param			id	path		string	true	"Role ID"
-//	@Success		200	{object}	map[string]interface{}
-//	@Failure		400	{object}	responses.ErrorResponseSwagger
-//	@Failure		404	{object}	responses.ErrorResponseSwagger
-//	@Failure		500	{object}	responses.ErrorResponseSwagger
+//	@Param			id		path		string	true	"Role ID"
+//	@Param			fields	query		string	false	"Comma-separated response fields to return, e.g. id,name,is_active"
+//	@Param			If-None-Match	header	string	false	"ETag of a previous response; 304 when unchanged"
+//	@Success		200		{object}	map[string]interface{}
+//	@Success		304		"Not Modified"
+//	@Failure		400		{object}	responses.ErrorResponseSwagger
+//	@Failure		404		{object}	responses.ErrorResponseSwagger
+//	@Failure		500		{object}	responses.ErrorResponseSwagger
 //	@Router			/api/v1/roles/{id} [get]
 func (h *RoleHandler) GetRole(c *gin.Context) {
 	roleID := c.Param("id")
@@ -141,6 +149,12 @@ func (h *RoleHandler) GetRole(c *gin.Context) {
 		return
 	}
 
+	fields, err := fieldsets.Parse(c.Query("fields"), fieldsets.RoleFields)
+	if err != nil {
+		h.responder.SendValidationError(c, []string{err.Error()})
+		return
+	}
+
 	// Get role through service
 	result, err := h.roleService.GetRoleByID(c.Request.Context(), roleID)
 	if err != nil {
@@ -149,8 +163,18 @@ func (h *RoleHandler) GetRole(c *gin.Context) {
 		return
 	}
 
+	projected, err := fieldsets.Project(result, fields)
+	if err != nil {
+		h.responder.SendInternalError(c, err)
+		return
+	}
+
+	if httpcache.NotModified(c, projected, result.UpdatedAt) {
+		return
+	}
+
 	h.logger.Info("Role retrieved successfully", zap.String("roleID", roleID))
-	h.responder.SendSuccess(c, http.StatusOK, result)
+	h.responder.SendSuccess(c, http.StatusOK, projected)
 }
 
 // UpdateRole handles PUT /v1/roles/:id
@@ -284,8 +308,11 @@ func (h *RoleHandler) DeleteRole(c *gin.Context) {
 //	@Tags			roles
 //	@Accept			json
 //	@Produce		json
-//	@Param			limit	query		int	false	"Number of roles to return"	default(10)
-//	@Param			offset	query		int	false	"Number of roles to skip"	default(0)
+//	@Param			limit	query		int		false	"Number of roles to return"	default(10)
+//	@Param			offset	query		int		false	"Number of roles to skip"	default(0)
+//	@Param			sort	query		string	false	"Sort field (created_at, updated_at, name)"
+//	@Param			order	query		string	false	"Sort direction (asc, desc)"	default(asc)
+//	@Param			fields	query		string	false	"Comma-separated response fields to return, e.g. id,name,is_active"
 //	@Success		200		{object}	map[string]interface{}
 //	@Failure		400		{object}	map[string]interface{}
 //	@Failure		500		{object}	map[string]interface{}
@@ -294,23 +321,28 @@ func (h *RoleHandler) ListRoles(c *gin.Context) {
 	h.logger.Info("Listing roles")
 
 	// Parse pagination parameters
-	limitStr := c.DefaultQuery("limit", "10")
-	offsetStr := c.DefaultQuery("offset", "0")
+	paging, err := pagination.ParsePagination(c, pagination.Standard())
+	if err != nil {
+		h.responder.SendValidationError(c, []string{err.Error()})
+		return
+	}
+	limit, offset := paging.Limit, paging.Offset
 
-	limit, err := strconv.Atoi(limitStr)
+	order, err := sorting.Parse(c.Query("sort"), c.Query("order"), sorting.RoleFields)
 	if err != nil {
-		h.responder.SendValidationError(c, []string{"invalid limit parameter"})
+		h.responder.SendValidationError(c, []string{err.Error()})
 		return
 	}
+	ctx := sorting.WithOrder(c.Request.Context(), order)
 
-	offset, err := strconv.Atoi(offsetStr)
+	fields, err := fieldsets.Parse(c.Query("fields"), fieldsets.RoleFields)
 	if err != nil {
-		h.responder.SendValidationError(c, []string{"invalid offset parameter"})
+		h.responder.SendValidationError(c, []string{err.Error()})
 		return
 	}
 
 	// List roles through service
-	result, err := h.roleService.ListRoles(c.Request.Context(), limit, offset)
+	result, err := h.roleService.ListRoles(ctx, limit, offset)
 	if err != nil {
 		h.logger.Error("Failed to list roles", zap.Error(err))
 		h.responder.SendInternalError(c, err)
@@ -325,8 +357,62 @@ func (h *RoleHandler) ListRoles(c *gin.Context) {
 		return
 	}
 
+	projected, err := fieldsets.Project(result, fields)
+	if err != nil {
+		h.responder.SendInternalError(c, err)
+		return
+	}
+
 	h.logger.Info("Roles listed successfully", zap.Int64("total", total))
-	h.responder.SendPaginatedResponse(c, result, int(total), limit, offset)
+	h.responder.SendPaginatedResponse(c, projected, int(total), limit, offset)
+}
+
+// GetRoleUsers handles GET /v1/roles/:id/users
+//
+//	@Summary		List users holding a role
+//	@Description	Get a paginated list of the users currently holding a role, directly or through a group. Inherited assignments count only while the group role and group membership are valid.
+//	@Tags			roles
+//	@Produce		json
+//	@Param			id		path		string	true	"Role ID"
+//	@Param			limit	query		int		false	"Number of users to return"	default(10)
+//	@Param			offset	query		int		false	"Number of users to skip"	default(0)
+//	@Success		200		{object}	map[string]interface{}
+//	@Failure		400		{object}	responses.ErrorResponseSwagger
+//	@Failure		404		{object}	responses.ErrorResponseSwagger
+//	@Failure		500		{object}	responses.ErrorResponseSwagger
+//	@Router			/api/v1/roles/{id}/users [get]
+func (h *RoleHandler) GetRoleUsers(c *gin.Context) {
+	roleID := c.Param("id")
+	h.logger.Info("Listing users by role", zap.String("roleID", roleID))
+
+	paging, err := pagination.ParsePagination(c, pagination.Standard())
+	if err != nil {
+		h.responder.SendValidationError(c, []string{err.Error()})
+		return
+	}
+
+	users, total, err := h.roleService.GetUsersByRole(c.Request.Context(), roleID, paging.Limit, paging.Offset)
+	if err != nil {
+		h.logger.Error("Failed to list users by role", zap.String("roleID", roleID), zap.Error(err))
+		switch {
+		case errors.IsValidationError(err):
+			h.responder.SendValidationError(c, []string{err.Error()})
+		case errors.IsNotFoundError(err):
+			h.responder.SendError(c, http.StatusNotFound, "role not found", err)
+		default:
+			h.responder.SendInternalError(c, err)
+		}
+		return
+	}
+
+	result := make([]*userResponses.UserResponse, len(users))
+	for i, user := range users {
+		result[i] = &userResponses.UserResponse{}
+		result[i].FromModel(user)
+	}
+
+	h.logger.Info("Users by role listed successfully", zap.String("roleID", roleID), zap.Int64("total", total))
+	h.responder.SendPaginatedResponse(c, result, int(total), paging.Limit, paging.Offset)
 }
 
 // CreateRoleV2 handles POST /v2/roles
@@ -581,14 +667,16 @@ func (h *RoleHandler) AddChildRoleV2(c *gin.Context) {
 // AssignRole handles POST /users/{id}/roles
 //
 //	@Summary		Assign role to user
-//	@Description	Assign a role to an existing user
+//	@Description	Assign a role to an existing user. Assigning a KYC-gated role to a user whose Aadhaar is not verified is accepted as pending, or refused when the role rejects unverified users.
 //	@Tags			roles
 //	@Accept			json
 //	@Produce		json
 //	@Param			id		path		string					true	"User ID"
 //	@Param			role	body		roles.AssignRoleRequest	true	"Role assignment data"
 //	@Success		200		{object}	responses.AssignRoleResponse
+//	@Success		202		{object}	map[string]interface{}
 //	@Failure		400		{object}	responses.ErrorResponseSwagger
+//	@Failure		403		{object}	responses.ErrorResponseSwagger
 //	@Failure		404		{object}	responses.ErrorResponseSwagger
 //	@Failure		409		{object}	responses.ErrorResponseSwagger
 //	@Failure		500		{object}	responses.ErrorResponseSwagger
@@ -660,6 +748,19 @@ func (h *RoleHandler) AssignRole(c *gin.Context) {
 
 	// Assign role through service
 	err := h.roleService.AssignRoleToUser(c.Request.Context(), userID, req.RoleID)
+	if stdErrors.Is(err, models.ErrRoleAssignmentPendingKYC) {
+		// The pending assignment is audited by the role service
+		h.logger.Info("Role assignment pending KYC verification",
+			zap.String("userID", userID),
+			zap.String("roleID", req.RoleID),
+			zap.String("actorID", actorID))
+		h.responder.SendSuccess(c, http.StatusAccepted, map[string]interface{}{
+			"message": "Role assignment pending KYC verification",
+			"user_id": userID,
+			"role_id": req.RoleID,
+		})
+		return
+	}
 	if err != nil {
 		h.logger.Error("Failed to assign role to user",
 			zap.String("userID", userID),
@@ -677,6 +778,10 @@ func (h *RoleHandler) AssignRole(c *gin.Context) {
 			})
 
 		// Handle specific error types
+		if forbiddenErr, ok := err.(*errors.ForbiddenError); ok {
+			h.responder.SendError(c, http.StatusForbidden, forbiddenErr.Error(), forbiddenErr)
+			return
+		}
 		if err.Error() == "role not found" {
 			h.responder.SendError(c, http.StatusNotFound, "Role not found", err)
 			return
@@ -753,6 +858,66 @@ func (h *RoleHandler) AssignRole(c *gin.Context) {
 	h.responder.SendSuccess(c, http.StatusOK, response)
 }
 
+// BulkAssignRoleRequest lists the users a role should be granted to
+type BulkAssignRoleRequest struct {
+	UserIDs []string `json:"user_ids" binding:"required,min=1"`
+}
+
+// AssignRoleBulk handles POST /roles/{id}/assign-bulk
+//
+//	@Summary		Assign role to multiple users
+//	@Description	Assign a role to many users at once. Users who already hold the role are skipped, and a KYC-gated role is left pending for users whose Aadhaar is not verified; the response reports the outcome per user.
+//	@Tags			roles
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string					true	"Role ID"
+//	@Param			request	body		BulkAssignRoleRequest	true	"Users to assign"
+//	@Success		200		{object}	interfaces.BulkRoleAssignmentResult
+//	@Failure		400		{object}	responses.ErrorResponseSwagger
+//	@Failure		401		{object}	responses.ErrorResponseSwagger
+//	@Failure		404		{object}	responses.ErrorResponseSwagger
+//	@Failure		500		{object}	responses.ErrorResponseSwagger
+//	@Router			/api/v1/roles/{id}/assign-bulk [post]
+func (h *RoleHandler) AssignRoleBulk(c *gin.Context) {
+	roleID := c.Param("id")
+
+	actorID, ok := c.Get("user_id")
+	assignedBy, _ := actorID.(string)
+	if !ok || assignedBy == "" {
+		h.auditService.LogAccessDenied(c.Request.Context(), "anonymous", "assign_role_bulk", "role", roleID, "not authenticated")
+		h.responder.SendError(c, http.StatusUnauthorized, "Authentication required", fmt.Errorf("authentication required"))
+		return
+	}
+
+	var req BulkAssignRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Failed to bind request", zap.Error(err))
+		h.responder.SendValidationError(c, []string{err.Error()})
+		return
+	}
+
+	h.logger.Info("Assigning role to users in bulk",
+		zap.String("roleID", roleID),
+		zap.Int("userCount", len(req.UserIDs)),
+		zap.String("actorID", assignedBy))
+
+	result, err := h.roleService.AssignRoleToUsers(c.Request.Context(), roleID, req.UserIDs, assignedBy)
+	if err != nil {
+		h.logger.Error("Bulk role assignment failed", zap.String("roleID", roleID), zap.Error(err))
+		switch {
+		case errors.IsValidationError(err):
+			h.responder.SendError(c, http.StatusBadRequest, err.Error(), err)
+		case errors.IsNotFoundError(err):
+			h.responder.SendError(c, http.StatusNotFound, err.Error(), err)
+		default:
+			h.responder.SendInternalError(c, err)
+		}
+		return
+	}
+
+	h.responder.SendSuccess(c, http.StatusOK, result)
+}
+
 // RemoveRole handles DELETE /users/{id}/roles/{role_id}
 //
 //	@Summary		Remove role from user
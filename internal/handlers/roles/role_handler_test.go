@@ -12,6 +12,7 @@ import (
 
 	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
 	"github.com/Kisanlink/aaa-service/v2/internal/entities/requests/roles"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/mock"
 	"go.uber.org/zap"
@@ -93,6 +94,38 @@ func (m *MockRoleService) GetRoleWithChildren(ctx context.Context, roleID string
 	return nil, nil
 }
 
+func (m *MockRoleService) AssignRoleToUsers(ctx context.Context, roleID string, userIDs []string, assignedBy string) (*interfaces.BulkRoleAssignmentResult, error) {
+	args := m.Called(ctx, roleID, userIDs, assignedBy)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*interfaces.BulkRoleAssignmentResult), args.Error(1)
+}
+
+func (m *MockRoleService) SetUserRoles(ctx context.Context, userID string, roleIDs []string, actor string) (*interfaces.UserRoleSetResult, error) {
+	args := m.Called(ctx, userID, roleIDs, actor)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*interfaces.UserRoleSetResult), args.Error(1)
+}
+
+func (m *MockRoleService) GetUsersByRole(ctx context.Context, roleID string, limit, offset int) ([]*models.User, int64, error) {
+	args := m.Called(ctx, roleID, limit, offset)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]*models.User), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockRoleService) GetUsersByRoleName(ctx context.Context, name, orgID string, limit, offset int) ([]*models.User, int64, error) {
+	args := m.Called(ctx, name, orgID, limit, offset)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]*models.User), args.Get(1).(int64), args.Error(2)
+}
+
 func (m *MockRoleService) ValidateRoleAssignment(ctx context.Context, userID, roleID string) error {
 	return nil
 }
@@ -675,3 +708,41 @@ func TestRoleHandler_RemoveRole_ServiceError(t *testing.T) {
 	mockRoleService.AssertExpectations(t)
 	mockResponder.AssertExpectations(t)
 }
+
+func TestRoleHandler_GetRole_NotModified(t *testing.T) {
+	handler, mockRoleService, _, mockResponder, _ := setupTestHandler()
+
+	roleID := "role-456"
+	role := models.NewRole("Test Role", "Test role description", models.RoleScopeOrg)
+	role.SetID(roleID)
+
+	mockRoleService.On("GetRoleByID", mock.Anything, roleID).Return(role, nil)
+	mockResponder.On("SendSuccess", mock.Anything, http.StatusOK, mock.Anything).Return().Once()
+
+	getRole := func(etag string) *httptest.ResponseRecorder {
+		gin.SetMode(gin.TestMode)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/roles/"+roleID, nil)
+		if etag != "" {
+			c.Request.Header.Set("If-None-Match", etag)
+		}
+		c.Params = gin.Params{gin.Param{Key: "id", Value: roleID}}
+		handler.GetRole(c)
+		return w
+	}
+
+	first := getRole("")
+	etag := first.Header().Get("ETag")
+	if first.Code != http.StatusOK || etag == "" || first.Header().Get("Last-Modified") == "" {
+		t.Fatalf("expected 200 with validators, got %d, ETag %q", first.Code, etag)
+	}
+
+	second := getRole(etag)
+	if second.Code != http.StatusNotModified || second.Body.Len() != 0 {
+		t.Fatalf("expected an empty 304 for the returned ETag, got %d", second.Code)
+	}
+
+	// The responder is only reached by the first request
+	mockResponder.AssertExpectations(t)
+}
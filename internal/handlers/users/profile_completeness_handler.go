@@ -0,0 +1,48 @@
+package users
+
+import (
+	"net/http"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// SetProfileCompletenessService enables the profile completeness endpoint
+func (h *UserHandler) SetProfileCompletenessService(completion interfaces.ProfileCompletenessService) {
+	h.completion = completion
+}
+
+// GetMyProfileCompleteness handles GET /api/v1/users/me/profile-completeness
+//
+//	@Summary		Get my profile completeness
+//	@Description	Score how complete the caller's profile is, from 0 to 100, and list the missing items heaviest first so clients can nudge the user to fill them in. The items are the name, date of birth and photo of the profile, a verified contact, an address and a verified Aadhaar, weighed as configured with AAA_PROFILE_COMPLETENESS_WEIGHTS.
+//	@Tags			users
+//	@Produce		json
+//	@Success		200	{object}	userResponses.ProfileCompletenessResponse
+//	@Failure		401	{object}	responses.ErrorResponse	"Unauthorized"
+//	@Failure		404	{object}	responses.ErrorResponse	"User not found"
+//	@Failure		500	{object}	responses.ErrorResponse	"Internal server error"
+//	@Router			/api/v1/users/me/profile-completeness [get]
+//	@Security		Bearer
+func (h *UserHandler) GetMyProfileCompleteness(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		h.responder.SendError(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+
+	completeness, err := h.completion.GetProfileCompleteness(c.Request.Context(), userID)
+	if err != nil {
+		if errors.IsNotFoundError(err) {
+			h.responder.SendError(c, http.StatusNotFound, err.Error(), err)
+			return
+		}
+		h.logger.Error("Failed to get profile completeness", zap.String("user_id", userID), zap.Error(err))
+		h.responder.SendInternalError(c, err)
+		return
+	}
+
+	h.responder.SendSuccess(c, http.StatusOK, completeness)
+}
@@ -0,0 +1,160 @@
+package users
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	userResponses "github.com/Kisanlink/aaa-service/v2/internal/entities/responses/users"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// dataExportAdminRoles may export the data of any user
+var dataExportAdminRoles = []string{"super_admin", "admin"}
+
+// SetDataExportService enables the user data export endpoint
+func (h *UserHandler) SetDataExportService(exporter interfaces.UserDataExportService) {
+	h.exporter = exporter
+}
+
+// UserDataExportResponse documents the user data export
+type UserDataExportResponse struct {
+	UserID     string                            `json:"user_id"`
+	ExportedAt time.Time                         `json:"exported_at"`
+	Account    *userResponses.UserDetailResponse `json:"account"`
+	AuditTrail []models.AuditLog                 `json:"audit_trail"`
+}
+
+// ExportUserData handles GET /api/v1/users/:id/export
+//
+//	@Summary		Download a user's data
+//	@Description	Export the data held about a user as a downloadable JSON file: the account with its profile, contacts, addresses, roles and group memberships, and the audit trail of the actions the user performed and that others performed on their behalf, most recent first. The IDs and personal details of other users appearing in audit entries are redacted. The file is streamed. Users can export their own data; super_admin and admin can export anyone's.
+//	@Tags			users
+//	@Produce		json
+//	@Param			id	path		string	true	"User ID"
+//	@Success		200	{object}	UserDataExportResponse
+//	@Failure		401	{object}	responses.ErrorResponse	"Unauthorized"
+//	@Failure		403	{object}	responses.ErrorResponse	"Not the user or an admin"
+//	@Failure		404	{object}	responses.ErrorResponse	"User not found"
+//	@Failure		500	{object}	responses.ErrorResponse	"Internal server error"
+//	@Router			/api/v1/users/{id}/export [get]
+//	@Security		Bearer
+func (h *UserHandler) ExportUserData(c *gin.Context) {
+	callerID := c.GetString("user_id")
+	if callerID == "" {
+		h.responder.SendError(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+
+	userID := c.Param("id")
+	if userID != callerID && !callerHasAnyRole(c, dataExportAdminRoles) {
+		h.logger.Warn("Data export of another user requested",
+			zap.String("user_id", userID),
+			zap.String("requested_by", callerID))
+		h.responder.SendError(c, http.StatusForbidden, "only the user or an admin can export this user's data", nil)
+		return
+	}
+
+	export := &jsonUserDataExport{c: c, userID: userID}
+	err := h.exporter.ExportUserData(c.Request.Context(), userID, export)
+	if err != nil && !export.started() {
+		if errors.IsNotFoundError(err) {
+			h.responder.SendError(c, http.StatusNotFound, err.Error(), err)
+			return
+		}
+		h.logger.Error("Failed to export user data", zap.String("user_id", userID), zap.Error(err))
+		h.responder.SendInternalError(c, err)
+		return
+	}
+	if err != nil {
+		// The file is partly sent; it is left unterminated so it does not pass for a complete export
+		h.logger.Error("User data export stopped early", zap.String("user_id", userID), zap.Int("audit_entries", export.entries), zap.Error(err))
+		return
+	}
+	if err := export.finish(); err != nil {
+		h.logger.Warn("Failed to finish user data export", zap.String("user_id", userID), zap.Error(err))
+		return
+	}
+
+	h.logger.Info("User data export downloaded",
+		zap.String("user_id", userID),
+		zap.String("requested_by", callerID),
+		zap.Int("audit_entries", export.entries))
+}
+
+// jsonUserDataExport streams a user data export as a JSON document. The response headers are sent
+// with the account, so errors found before it can still be returned as JSON.
+type jsonUserDataExport struct {
+	c       *gin.Context
+	userID  string
+	entries int
+	encoder *json.Encoder
+}
+
+func (e *jsonUserDataExport) WriteAccount(account *userResponses.UserDetailResponse) error {
+	e.c.Header("Content-Type", "application/json; charset=utf-8")
+	e.c.Header("Content-Disposition", `attachment; filename="user-data-`+e.userID+`.json"`)
+	e.c.Status(http.StatusOK)
+	e.encoder = json.NewEncoder(e.c.Writer)
+
+	userID, err := json.Marshal(e.userID)
+	if err != nil {
+		return err
+	}
+	exportedAt, err := json.Marshal(time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	if _, err := e.c.Writer.WriteString(`{"user_id":` + string(userID) + `,"exported_at":` + string(exportedAt) + `,"account":`); err != nil {
+		return err
+	}
+	if err := e.encoder.Encode(account); err != nil {
+		return err
+	}
+	_, err = e.c.Writer.WriteString(`,"audit_trail":[`)
+	return err
+}
+
+func (e *jsonUserDataExport) WriteAuditEntry(entry *models.AuditLog) error {
+	if e.entries > 0 {
+		if _, err := e.c.Writer.WriteString(","); err != nil {
+			return err
+		}
+	}
+	e.entries++
+	if err := e.encoder.Encode(entry); err != nil {
+		return err
+	}
+	e.c.Writer.Flush()
+	return nil
+}
+
+func (e *jsonUserDataExport) finish() error {
+	_, err := e.c.Writer.WriteString("]}\n")
+	return err
+}
+
+func (e *jsonUserDataExport) started() bool { return e.encoder != nil }
+
+func callerHasAnyRole(c *gin.Context, allowed []string) bool {
+	roles, ok := c.Get("roles")
+	if !ok {
+		return false
+	}
+	names, ok := roles.([]string)
+	if !ok {
+		return false
+	}
+	for _, name := range names {
+		for _, role := range allowed {
+			if name == role {
+				return true
+			}
+		}
+	}
+	return false
+}
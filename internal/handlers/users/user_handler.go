@@ -2,15 +2,24 @@ package users
 
 import (
 	"context"
+	stdErrors "errors"
 	"net/http"
 	"strconv"
 	"strings"
 
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
 	"github.com/Kisanlink/aaa-service/v2/internal/entities/requests/users"
 	"github.com/Kisanlink/aaa-service/v2/internal/entities/responses"
+	userResponses "github.com/Kisanlink/aaa-service/v2/internal/entities/responses/users"
 
 	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/internal/middleware"
+	"github.com/Kisanlink/aaa-service/v2/internal/repositories/sorting"
+	"github.com/Kisanlink/aaa-service/v2/internal/utils/fieldsets"
+	"github.com/Kisanlink/aaa-service/v2/internal/utils/httpcache"
+	"github.com/Kisanlink/aaa-service/v2/internal/utils/pagination"
 	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/Kisanlink/aaa-service/v2/pkg/warnings"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
@@ -18,6 +27,7 @@ import (
 // references to satisfy imports for Swagger comment parsing
 var (
 	_ responses.ErrorResponse
+	_ userResponses.BatchUsersResponse
 )
 
 // orgScopeResult holds the result of organization scope check
@@ -93,6 +103,9 @@ func (h *UserHandler) getOrgScope(c *gin.Context) (*orgScopeResult, error) {
 type UserHandler struct {
 	userService interfaces.UserService
 	roleService interfaces.RoleService
+	importer    interfaces.UserImportService
+	exporter    interfaces.UserDataExportService
+	completion  interfaces.ProfileCompletenessService
 	validator   interfaces.Validator
 	responder   interfaces.Responder
 	logger      *zap.Logger
@@ -118,7 +131,7 @@ func NewUserHandler(
 // CreateUser handles POST /users
 //
 //	@Summary		Create a new user
-//	@Description	Create a new user with the provided information
+//	@Description	Create a new user with the provided information. Usernames nearly identical to an existing one are accepted and reported in the warnings list of the response. When the phone number belongs only to a soft-deleted account, reclaim_mode "restore" restores that account with the new credentials and default roles, clearing its MPIN and prior roles, instead of creating a new one; creation_path reports which happened.
 //	@Tags			users
 //	@Accept			json
 //	@Produce		json
@@ -153,7 +166,11 @@ func (h *UserHandler) CreateUser(c *gin.Context) {
 	}
 
 	// Create user through service
-	userResponse, err := h.userService.CreateUser(c.Request.Context(), &req)
+	// Collect non-fatal findings such as near-duplicate usernames for the response
+	ctx, _ := warnings.NewContext(c.Request.Context())
+	c.Request = c.Request.WithContext(ctx)
+
+	userResponse, err := h.userService.CreateUser(ctx, &req)
 	if err != nil {
 		h.logger.Error("Failed to create user", zap.Error(err))
 		if validationErr, ok := err.(*errors.ValidationError); ok {
@@ -175,15 +192,21 @@ func (h *UserHandler) CreateUser(c *gin.Context) {
 // GetUserByID handles GET /users/:id
 //
 //	@Summary		Get user by ID
-//	@Description	Retrieve a user by their unique identifier with calculated roles (direct and inherited)
+//	@Description	Retrieve a user by their unique identifier with calculated roles (direct and inherited). With expand, returns a user detail with only the listed sections: profile, contacts, addresses, roles (direct, active), groups (group and organization memberships) or all.
 //	@Tags			users
 //	@Accept			json
 //	@Produce		json
-//	@Param			id	path		string	true	"User ID"
-//	@Success		200	{object}	responses.UserDetailResponse
-//	@Failure		400	{object}	responses.ErrorResponse
-//	@Failure		404	{object}	responses.ErrorResponse
-//	@Failure		500	{object}	responses.ErrorResponse
+//	@Param			id		path		string	true	"User ID"
+//	@Param			expand	query		string	false	"Comma-separated sections to include: profile, contacts, addresses, roles, groups, all"
+//	@Param			fields	query		string	false	"Comma-separated response fields to return, e.g. id,name,status; cannot be combined with expand"
+//	@Param			organization_id	query	string	false	"With expand, add the custom attributes this organization keeps on the user; defaults to the X-Organization-ID header"
+//	@Param			If-None-Match	header	string	false	"ETag of a previous response; 304 when unchanged"
+//	@Success		200		{object}	responses.UserDetailResponse
+//	@Success		304		"Not Modified"
+//	@Failure		400		{object}	responses.ErrorResponse
+//	@Failure		403		{object}	responses.ErrorResponse
+//	@Failure		404		{object}	responses.ErrorResponse
+//	@Failure		500		{object}	responses.ErrorResponse
 //	@Router			/api/v1/users/{id} [get]
 func (h *UserHandler) GetUserByID(c *gin.Context) {
 	userID := c.Param("id")
@@ -194,6 +217,21 @@ func (h *UserHandler) GetUserByID(c *gin.Context) {
 		return
 	}
 
+	fields, err := fieldsets.Parse(c.Query("fields"), fieldsets.UserFields)
+	if err != nil {
+		h.responder.SendValidationError(c, []string{err.Error()})
+		return
+	}
+
+	if expand, ok := c.GetQuery("expand"); ok {
+		if len(fields) > 0 {
+			h.responder.SendValidationError(c, []string{"fields cannot be combined with expand"})
+			return
+		}
+		h.getUserDetail(c, userID, expand)
+		return
+	}
+
 	// Get user with roles (includes both direct and inherited roles)
 	userResponse, err := h.userService.GetUserWithRoles(c.Request.Context(), userID)
 	if err != nil {
@@ -206,8 +244,80 @@ func (h *UserHandler) GetUserByID(c *gin.Context) {
 		return
 	}
 
+	projected, err := fieldsets.Project(userResponse, fields)
+	if err != nil {
+		h.responder.SendInternalError(c, err)
+		return
+	}
+
+	if httpcache.NotModified(c, projected, userResponse.UpdatedAt) {
+		return
+	}
+
 	h.logger.Info("User retrieved successfully", zap.String("userID", userID))
-	h.responder.SendSuccess(c, http.StatusOK, userResponse)
+	h.responder.SendSuccess(c, http.StatusOK, projected)
+}
+
+// getUserDetail sends the user detail with the sections named by expand
+func (h *UserHandler) getUserDetail(c *gin.Context, userID, expand string) {
+	sections, err := users.ParseUserDetailExpand(expand)
+	if err != nil {
+		h.responder.SendValidationError(c, []string{err.Error()})
+		return
+	}
+
+	if sections.AttributesOrganizationID, err = h.detailOrganizationID(c); err != nil {
+		h.responder.SendError(c, http.StatusForbidden, err.Error(), err)
+		return
+	}
+
+	detail, err := h.userService.GetUserDetail(c.Request.Context(), userID, sections)
+	if err != nil {
+		h.logger.Error("Failed to get user detail", zap.String("userID", userID), zap.Error(err))
+		if notFoundErr, ok := err.(*errors.NotFoundError); ok {
+			h.responder.SendError(c, http.StatusNotFound, notFoundErr.Error(), notFoundErr)
+			return
+		}
+		if validationErr, ok := err.(*errors.ValidationError); ok {
+			h.responder.SendValidationError(c, []string{validationErr.Error()})
+			return
+		}
+		h.responder.SendInternalError(c, err)
+		return
+	}
+
+	h.responder.SendSuccess(c, http.StatusOK, detail)
+}
+
+// detailOrganizationID returns the organization whose custom attributes are added to a user
+// detail: the organization_id parameter, else the X-Organization-ID header. Callers must belong
+// to the organization named by the parameter; a header naming another organization is ignored.
+func (h *UserHandler) detailOrganizationID(c *gin.Context) (string, error) {
+	orgID := strings.TrimSpace(c.Query("organization_id"))
+	explicit := orgID != ""
+	if !explicit {
+		orgID = strings.TrimSpace(c.GetHeader(middleware.OrganizationHeader))
+	}
+	if orgID == "" {
+		return "", nil
+	}
+
+	scope, err := h.getOrgScope(c)
+	if err != nil {
+		return "", err
+	}
+	if scope.IsSuperAdmin {
+		return orgID, nil
+	}
+	for _, id := range scope.OrganizationIDs {
+		if id == orgID {
+			return orgID, nil
+		}
+	}
+	if explicit {
+		return "", errors.NewForbiddenError("you are not a member of the requested organization")
+	}
+	return "", nil
 }
 
 // UpdateUser handles PUT /users/:id
@@ -373,8 +483,11 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 //	@Tags			users
 //	@Accept			json
 //	@Produce		json
-//	@Param			limit	query		int	false	"Number of users to return"	default(10)
-//	@Param			offset	query		int	false	"Number of users to skip"	default(0)
+//	@Param			limit	query		int		false	"Number of users to return"	default(10)
+//	@Param			offset	query		int		false	"Number of users to skip"	default(0)
+//	@Param			sort	query		string	false	"Sort field (created_at, updated_at, username, phone_number, status)"
+//	@Param			order	query		string	false	"Sort direction (asc, desc)"	default(asc)
+//	@Param			fields	query		string	false	"Comma-separated response fields to return, e.g. id,name,status"
 //	@Success		200		{object}	map[string]interface{}
 //	@Failure		400		{object}	map[string]interface{}
 //	@Failure		500		{object}	map[string]interface{}
@@ -383,18 +496,23 @@ func (h *UserHandler) ListUsers(c *gin.Context) {
 	h.logger.Info("Listing users")
 
 	// Parse pagination parameters
-	limitStr := c.DefaultQuery("limit", "10")
-	offsetStr := c.DefaultQuery("offset", "0")
+	paging, err := pagination.ParsePagination(c, pagination.Standard())
+	if err != nil {
+		h.responder.SendValidationError(c, []string{err.Error()})
+		return
+	}
+	limit, offset := paging.Limit, paging.Offset
 
-	limit, err := strconv.Atoi(limitStr)
+	order, err := sorting.Parse(c.Query("sort"), c.Query("order"), sorting.UserFields)
 	if err != nil {
-		h.responder.SendValidationError(c, []string{"invalid limit parameter"})
+		h.responder.SendValidationError(c, []string{err.Error()})
 		return
 	}
+	ctx := sorting.WithOrder(c.Request.Context(), order)
 
-	offset, err := strconv.Atoi(offsetStr)
+	fields, err := fieldsets.Parse(c.Query("fields"), fieldsets.UserFields)
 	if err != nil {
-		h.responder.SendValidationError(c, []string{"invalid offset parameter"})
+		h.responder.SendValidationError(c, []string{err.Error()})
 		return
 	}
 
@@ -415,12 +533,12 @@ func (h *UserHandler) ListUsers(c *gin.Context) {
 			h.responder.SendPaginatedResponse(c, []interface{}{}, 0, limit, offset)
 			return
 		}
-		h.responder.SendPaginatedResponse(c, []interface{}{selfUser}, 1, limit, offset)
+		h.sendUserPage(c, []interface{}{selfUser}, fields, 1, limit, offset)
 		return
 	}
 
 	// Use SearchUsersWithOrgScope with empty keyword to list all users in scope
-	result, err := h.userService.SearchUsersWithOrgScope(c.Request.Context(), "", scope.OrganizationIDs, limit, offset)
+	result, err := h.userService.SearchUsersWithOrgScope(ctx, "", scope.OrganizationIDs, limit, offset)
 	if err != nil {
 		h.logger.Error("Failed to list users", zap.Error(err))
 		h.responder.SendInternalError(c, err)
@@ -428,7 +546,17 @@ func (h *UserHandler) ListUsers(c *gin.Context) {
 	}
 
 	h.logger.Info("Users listed successfully")
-	h.responder.SendPaginatedResponse(c, result.Data, int(result.Total), limit, offset)
+	h.sendUserPage(c, result.Data, fields, int(result.Total), limit, offset)
+}
+
+// sendUserPage sends a page of users trimmed to the requested fields
+func (h *UserHandler) sendUserPage(c *gin.Context, page interface{}, fields []string, total, limit, offset int) {
+	projected, err := fieldsets.Project(page, fields)
+	if err != nil {
+		h.responder.SendInternalError(c, err)
+		return
+	}
+	h.responder.SendPaginatedResponse(c, projected, total, limit, offset)
 }
 
 // SearchUsers handles GET /users/search
@@ -461,20 +589,12 @@ func (h *UserHandler) SearchUsers(c *gin.Context) {
 	}
 
 	// Parse pagination parameters
-	limitStr := c.DefaultQuery("limit", "10")
-	offsetStr := c.DefaultQuery("offset", "0")
-
-	limit, err := strconv.Atoi(limitStr)
-	if err != nil {
-		h.responder.SendValidationError(c, []string{"invalid limit parameter"})
-		return
-	}
-
-	offset, err := strconv.Atoi(offsetStr)
+	paging, err := pagination.ParsePagination(c, pagination.Standard())
 	if err != nil {
-		h.responder.SendValidationError(c, []string{"invalid offset parameter"})
+		h.responder.SendValidationError(c, []string{err.Error()})
 		return
 	}
+	limit, offset := paging.Limit, paging.Offset
 
 	// Get organization scope from DB
 	scope, err := h.getOrgScope(c)
@@ -522,6 +642,91 @@ func (h *UserHandler) SearchUsers(c *gin.Context) {
 	h.responder.SendPaginatedResponse(c, result.Data, int(result.Total), limit, offset)
 }
 
+// BatchGetUsers handles GET /users/batch
+//
+//	@Summary		Get users by IDs
+//	@Description	Resolve up to 100 users in one call. Returns the users found, in request order, and the IDs that were not found.
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Param			ids					query		string	true	"Comma-separated user IDs"
+//	@Param			include_roles		query		bool	false	"Include directly assigned roles"	default(false)
+//	@Param			include_profile		query		bool	false	"Include profile"					default(false)
+//	@Param			include_addresses	query		bool	false	"Include addresses"					default(false)
+//	@Success		200					{object}	userResponses.BatchUsersResponse
+//	@Failure		400					{object}	responses.ErrorResponse
+//	@Failure		500					{object}	responses.ErrorResponse
+//	@Router			/api/v1/users/batch [get]
+func (h *UserHandler) BatchGetUsers(c *gin.Context) {
+	req := users.BatchGetUsersRequest{}
+	if ids := c.Query("ids"); ids != "" {
+		req.IDs = strings.Split(ids, ",")
+	}
+
+	var errs []string
+	for name, target := range map[string]*bool{
+		"include_roles":     &req.IncludeRoles,
+		"include_profile":   &req.IncludeProfile,
+		"include_addresses": &req.IncludeAddresses,
+	} {
+		value, err := strconv.ParseBool(c.DefaultQuery(name, "false"))
+		if err != nil {
+			errs = append(errs, "invalid "+name+" parameter")
+			continue
+		}
+		*target = value
+	}
+	if len(errs) > 0 {
+		h.responder.SendValidationError(c, errs)
+		return
+	}
+
+	h.getUsersByIDs(c, &req)
+}
+
+// BatchGetUsersByBody handles POST /users/batch
+//
+//	@Summary		Get users by IDs (request body)
+//	@Description	Same as GET /users/batch, for ID lists that are too long for a query string
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		users.BatchGetUsersRequest	true	"User IDs and relationships to include"
+//	@Success		200		{object}	userResponses.BatchUsersResponse
+//	@Failure		400		{object}	responses.ErrorResponse
+//	@Failure		500		{object}	responses.ErrorResponse
+//	@Router			/api/v1/users/batch [post]
+func (h *UserHandler) BatchGetUsersByBody(c *gin.Context) {
+	var req users.BatchGetUsersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Failed to bind request", zap.Error(err))
+		h.responder.SendValidationError(c, []string{err.Error()})
+		return
+	}
+
+	h.getUsersByIDs(c, &req)
+}
+
+func (h *UserHandler) getUsersByIDs(c *gin.Context, req *users.BatchGetUsersRequest) {
+	if err := req.Validate(); err != nil {
+		h.responder.SendValidationError(c, []string{err.Error()})
+		return
+	}
+
+	result, err := h.userService.GetUsersByIDs(c.Request.Context(), req)
+	if err != nil {
+		h.logger.Error("Failed to get users by IDs", zap.Error(err))
+		if validationErr, ok := err.(*errors.ValidationError); ok {
+			h.responder.SendValidationError(c, []string{validationErr.Error()})
+			return
+		}
+		h.responder.SendInternalError(c, err)
+		return
+	}
+
+	h.responder.SendSuccess(c, http.StatusOK, result)
+}
+
 // ValidateUser handles POST /users/:id/validate
 //
 //	@Summary		Validate user
@@ -568,14 +773,16 @@ func (h *UserHandler) ValidateUser(c *gin.Context) {
 // AssignRole handles POST /users/:id/roles/:roleId
 //
 //	@Summary		Assign role to user
-//	@Description	Assign a role to a specific user
+//	@Description	Assign a role to a specific user. Assigning a KYC-gated role to a user whose Aadhaar is not verified is accepted as pending, or refused when the role rejects unverified users.
 //	@Tags			users
 //	@Accept			json
 //	@Produce		json
 //	@Param			id		path		string	true	"User ID"
 //	@Param			roleId	path		string	true	"Role ID"
 //	@Success		200		{object}	map[string]interface{}
+//	@Success		202		{object}	map[string]interface{}
 //	@Failure		400		{object}	map[string]interface{}
+//	@Failure		403		{object}	map[string]interface{}
 //	@Failure		404		{object}	map[string]interface{}
 //	@Failure		409		{object}	map[string]interface{}
 //	@Failure		500		{object}	map[string]interface{}
@@ -596,8 +803,21 @@ func (h *UserHandler) AssignRole(c *gin.Context) {
 
 	// Assign role through service
 	err := h.roleService.AssignRoleToUser(c.Request.Context(), userID, roleID)
+	if stdErrors.Is(err, models.ErrRoleAssignmentPendingKYC) {
+		h.logger.Info("Role assignment pending KYC verification", zap.String("userID", userID), zap.String("roleID", roleID))
+		h.responder.SendSuccess(c, http.StatusAccepted, map[string]interface{}{
+			"message": "Role assignment pending KYC verification",
+			"user_id": userID,
+			"role_id": roleID,
+		})
+		return
+	}
 	if err != nil {
 		h.logger.Error("Failed to assign role", zap.Error(err))
+		if forbiddenErr, ok := err.(*errors.ForbiddenError); ok {
+			h.responder.SendError(c, http.StatusForbidden, forbiddenErr.Error(), forbiddenErr)
+			return
+		}
 		if notFoundErr, ok := err.(*errors.NotFoundError); ok {
 			h.responder.SendError(c, http.StatusNotFound, notFoundErr.Error(), notFoundErr)
 			return
@@ -703,14 +923,16 @@ func (h *UserHandler) GetUserRoles(c *gin.Context) {
 // AssignRoleToUser handles POST /users/:id/roles
 //
 //	@Summary		Assign role to user
-//	@Description	Assign a role to a specific user using request body
+//	@Description	Assign a role to a specific user using request body. Assigning a KYC-gated role to a user whose Aadhaar is not verified is accepted as pending, or refused when the role rejects unverified users.
 //	@Tags			users
 //	@Accept			json
 //	@Produce		json
 //	@Param			id		path		string				true	"User ID"
 //	@Param			role	body		map[string]string	true	"Role assignment data"
 //	@Success		200		{object}	map[string]interface{}
+//	@Success		202		{object}	map[string]interface{}
 //	@Failure		400		{object}	responses.ErrorResponse
+//	@Failure		403		{object}	responses.ErrorResponse
 //	@Failure		404		{object}	responses.ErrorResponse
 //	@Failure		409		{object}	responses.ErrorResponse
 //	@Failure		500		{object}	responses.ErrorResponse
@@ -740,8 +962,21 @@ func (h *UserHandler) AssignRoleToUser(c *gin.Context) {
 
 	// Assign role through service
 	err := h.roleService.AssignRoleToUser(c.Request.Context(), userID, req.RoleID)
+	if stdErrors.Is(err, models.ErrRoleAssignmentPendingKYC) {
+		h.logger.Info("Role assignment pending KYC verification", zap.String("userID", userID), zap.String("roleID", req.RoleID))
+		h.responder.SendSuccess(c, http.StatusAccepted, map[string]interface{}{
+			"message": "Role assignment pending KYC verification",
+			"user_id": userID,
+			"role_id": req.RoleID,
+		})
+		return
+	}
 	if err != nil {
 		h.logger.Error("Failed to assign role", zap.Error(err))
+		if forbiddenErr, ok := err.(*errors.ForbiddenError); ok {
+			h.responder.SendError(c, http.StatusForbidden, forbiddenErr.Error(), forbiddenErr)
+			return
+		}
 		if notFoundErr, ok := err.(*errors.NotFoundError); ok {
 			h.responder.SendError(c, http.StatusNotFound, notFoundErr.Error(), notFoundErr)
 			return
@@ -810,6 +1045,69 @@ func (h *UserHandler) RemoveRoleFromUser(c *gin.Context) {
 	})
 }
 
+// SetUserRoles handles PUT /users/:id/roles
+//
+//	@Summary		Set user roles
+//	@Description	Make the given roles exactly the roles assigned directly to a user. Roles missing from the list are removed and new ones assigned, together or not at all; roles the user inherits from groups are not affected. An empty list removes every direct role. KYC-gated roles are reported as pending until the user's Aadhaar is verified, or refused when the role rejects unverified users.
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string					true	"User ID"
+//	@Param			request	body		SetUserRolesRequest		true	"Roles the user should hold"
+//	@Success		200		{object}	interfaces.UserRoleSetResult
+//	@Failure		400		{object}	responses.ErrorResponse
+//	@Failure		401		{object}	responses.ErrorResponse
+//	@Failure		403		{object}	responses.ErrorResponse
+//	@Failure		404		{object}	responses.ErrorResponse
+//	@Failure		409		{object}	responses.ErrorResponse
+//	@Failure		500		{object}	responses.ErrorResponse
+//	@Router			/api/v1/users/{id}/roles [put]
+func (h *UserHandler) SetUserRoles(c *gin.Context) {
+	userID := c.Param("id")
+	if userID == "" {
+		h.responder.SendValidationError(c, []string{"user ID is required"})
+		return
+	}
+
+	actor := c.GetString("user_id")
+	if actor == "" {
+		h.responder.SendError(c, http.StatusUnauthorized, "Authentication required", nil)
+		return
+	}
+
+	var req SetUserRolesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Failed to bind request", zap.Error(err))
+		h.responder.SendValidationError(c, []string{err.Error()})
+		return
+	}
+
+	result, err := h.roleService.SetUserRoles(c.Request.Context(), userID, req.RoleIDs, actor)
+	if err != nil {
+		h.logger.Error("Failed to set user roles", zap.String("userID", userID), zap.Error(err))
+		switch {
+		case errors.IsValidationError(err):
+			h.responder.SendValidationError(c, []string{err.Error()})
+		case errors.IsNotFoundError(err):
+			h.responder.SendError(c, http.StatusNotFound, err.Error(), err)
+		case errors.IsForbiddenError(err):
+			h.responder.SendError(c, http.StatusForbidden, err.Error(), err)
+		case errors.IsConflictError(err):
+			h.responder.SendError(c, http.StatusConflict, err.Error(), err)
+		default:
+			h.responder.SendInternalError(c, err)
+		}
+		return
+	}
+
+	h.responder.SendSuccess(c, http.StatusOK, result)
+}
+
+// SetUserRolesRequest lists the roles a user should hold directly
+type SetUserRolesRequest struct {
+	RoleIDs []string `json:"role_ids" binding:"required"`
+}
+
 // GetUserOrganizations handles GET /users/:id/organizations
 //
 //	@Summary		Get user organizations
@@ -902,3 +1200,37 @@ func (h *UserHandler) GetMyOrganizations(c *gin.Context) {
 		"organizations": organizations,
 	})
 }
+
+// GetMyGroups handles GET /me/groups
+//
+//	@Summary		Get current user's groups
+//	@Description	Get all groups the authenticated user is an active member of (no special permission required). Access tokens carry at most a capped number of group IDs; this returns the full set.
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	map[string]interface{}
+//	@Failure		401	{object}	responses.ErrorResponse
+//	@Failure		500	{object}	responses.ErrorResponse
+//	@Router			/api/v1/me/groups [get]
+func (h *UserHandler) GetMyGroups(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		h.responder.SendError(c, http.StatusUnauthorized, "User not authenticated", nil)
+		return
+	}
+
+	groups, err := h.userService.GetUserGroups(c.Request.Context(), userID)
+	if err != nil {
+		h.logger.Error("Failed to get user groups", zap.String("userID", userID), zap.Error(err))
+		if validationErr, ok := err.(*errors.ValidationError); ok {
+			h.responder.SendValidationError(c, []string{validationErr.Error()})
+			return
+		}
+		h.responder.SendInternalError(c, err)
+		return
+	}
+
+	h.responder.SendSuccess(c, http.StatusOK, map[string]interface{}{
+		"groups": groups,
+	})
+}
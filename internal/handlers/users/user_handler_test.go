@@ -13,6 +13,7 @@ import (
 	"github.com/Kisanlink/aaa-service/v2/internal/entities/requests/users"
 	"github.com/Kisanlink/aaa-service/v2/internal/entities/responses"
 	userResponses "github.com/Kisanlink/aaa-service/v2/internal/entities/responses/users"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
 	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -211,6 +212,22 @@ func (m *MockUserService) GetUserByEmail(ctx context.Context, email string) (*us
 	return nil, nil
 }
 
+func (m *MockUserService) GetUsersByIDs(ctx context.Context, req *users.BatchGetUsersRequest) (*userResponses.BatchUsersResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userResponses.BatchUsersResponse), args.Error(1)
+}
+
+func (m *MockUserService) GetUserDetail(ctx context.Context, userID string, expand *users.UserDetailExpand) (*userResponses.UserDetailResponse, error) {
+	args := m.Called(ctx, userID, expand)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*userResponses.UserDetailResponse), args.Error(1)
+}
+
 func (m *MockUserService) InitiatePasswordReset(ctx context.Context, phoneNumber, countryCode, username, email *string) (string, error) {
 	return "", nil
 }
@@ -315,6 +332,38 @@ func (m *MockRoleService) GetRoleWithChildren(ctx context.Context, roleID string
 	return nil, nil
 }
 
+func (m *MockRoleService) AssignRoleToUsers(ctx context.Context, roleID string, userIDs []string, assignedBy string) (*interfaces.BulkRoleAssignmentResult, error) {
+	args := m.Called(ctx, roleID, userIDs, assignedBy)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*interfaces.BulkRoleAssignmentResult), args.Error(1)
+}
+
+func (m *MockRoleService) SetUserRoles(ctx context.Context, userID string, roleIDs []string, actor string) (*interfaces.UserRoleSetResult, error) {
+	args := m.Called(ctx, userID, roleIDs, actor)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*interfaces.UserRoleSetResult), args.Error(1)
+}
+
+func (m *MockRoleService) GetUsersByRole(ctx context.Context, roleID string, limit, offset int) ([]*models.User, int64, error) {
+	args := m.Called(ctx, roleID, limit, offset)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]*models.User), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockRoleService) GetUsersByRoleName(ctx context.Context, name, orgID string, limit, offset int) ([]*models.User, int64, error) {
+	args := m.Called(ctx, name, orgID, limit, offset)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]*models.User), args.Get(1).(int64), args.Error(2)
+}
+
 func (m *MockRoleService) ValidateRoleAssignment(ctx context.Context, userID, roleID string) error {
 	return nil
 }
@@ -0,0 +1,152 @@
+package users
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// maxImportFieldSize bounds the non-file form fields of an import upload
+const maxImportFieldSize = 64 * 1024
+
+// SetImportService enables the CSV user import endpoint
+func (h *UserHandler) SetImportService(importer interfaces.UserImportService) {
+	h.importer = importer
+}
+
+// ImportUsers handles POST /users/import
+//
+//	@Summary		Import users from CSV
+//	@Description	Create users from an uploaded CSV file. The header row names the columns phone_number, password and optionally country_code (default +91), username and must_change_password; other headers can be mapped to these fields with the mapping form field, e.g. {"Mobile":"phone_number"}. The file is streamed and created in chunks, and the response is a CSV repeating every row with status (created, valid, duplicate, invalid or failed), user_id and error columns. With dry_run=true rows are only validated. The dry_run and mapping fields must precede the file in the form.
+//	@Tags			users
+//	@Accept			multipart/form-data
+//	@Produce		text/csv
+//	@Param			file	formData	file	true	"CSV file with a header row"
+//	@Param			dry_run	formData	bool	false	"Validate rows without creating users"
+//	@Param			mapping	formData	string	false	"JSON object mapping CSV headers to fields"
+//	@Success		200		{file}		file	"Result CSV"
+//	@Failure		400		{object}	responses.ErrorResponse
+//	@Failure		401		{object}	responses.ErrorResponse
+//	@Failure		403		{object}	responses.ErrorResponse
+//	@Failure		500		{object}	responses.ErrorResponse
+//	@Router			/api/v1/users/import [post]
+//	@Security		Bearer
+func (h *UserHandler) ImportUsers(c *gin.Context) {
+	reader, err := c.Request.MultipartReader()
+	if err != nil {
+		h.responder.SendValidationError(c, []string{"a multipart/form-data upload with a CSV file is required"})
+		return
+	}
+
+	opts := interfaces.UserImportOptions{}
+	if value := c.Query("dry_run"); value != "" {
+		if opts.DryRun, err = strconv.ParseBool(value); err != nil {
+			h.responder.SendValidationError(c, []string{"invalid dry_run parameter"})
+			return
+		}
+	}
+
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			h.responder.SendValidationError(c, []string{"file is required"})
+			return
+		}
+		if err != nil {
+			h.responder.SendValidationError(c, []string{fmt.Sprintf("invalid multipart upload: %v", err)})
+			return
+		}
+
+		switch part.FormName() {
+		case "dry_run":
+			value, err := readImportField(part)
+			if err == nil {
+				opts.DryRun, err = strconv.ParseBool(value)
+			}
+			if err != nil {
+				h.responder.SendValidationError(c, []string{"invalid dry_run field"})
+				return
+			}
+		case "mapping":
+			value, err := readImportField(part)
+			if err == nil && value != "" {
+				err = json.Unmarshal([]byte(value), &opts.Mapping)
+			}
+			if err != nil {
+				h.responder.SendValidationError(c, []string{"mapping must be a JSON object of CSV header to field"})
+				return
+			}
+		case "file":
+			h.importUsers(c, part, opts)
+			return
+		}
+	}
+}
+
+// importUsers streams the uploaded CSV through the import and the result CSV back to the caller
+func (h *UserHandler) importUsers(c *gin.Context, file io.Reader, opts interfaces.UserImportOptions) {
+	out := &importResultWriter{c: c}
+	summary, err := h.importer.ImportUsersCSV(c.Request.Context(), file, out, opts)
+	if err != nil && !out.started {
+		h.logger.Error("CSV user import rejected", zap.Error(err))
+		if validationErr, ok := err.(*errors.ValidationError); ok {
+			details := validationErr.Details()
+			if len(details) == 0 {
+				details = []string{validationErr.Error()}
+			}
+			h.responder.SendValidationError(c, details)
+			return
+		}
+		h.responder.SendInternalError(c, err)
+		return
+	}
+	if err != nil {
+		// The result is partly sent; the rows it lists were processed
+		h.logger.Error("CSV user import stopped early", zap.Error(err))
+	}
+
+	if summary != nil {
+		h.logger.Info("CSV user import finished",
+			zap.String("imported_by", c.GetString("user_id")),
+			zap.Bool("dry_run", summary.DryRun),
+			zap.Int("rows", summary.Rows),
+			zap.Int("created", summary.Created),
+			zap.Int("duplicates", summary.Duplicates),
+			zap.Int("invalid", summary.Invalid),
+			zap.Int("failed", summary.Failed))
+	}
+}
+
+// importResultWriter sends the CSV response headers on the first write, so that errors found
+// before any output can still be returned as JSON
+type importResultWriter struct {
+	c       *gin.Context
+	started bool
+}
+
+func (w *importResultWriter) Write(p []byte) (int, error) {
+	if !w.started {
+		w.started = true
+		w.c.Header("Content-Type", "text/csv; charset=utf-8")
+		w.c.Header("Content-Disposition", `attachment; filename="user-import-result.csv"`)
+		w.c.Status(http.StatusOK)
+	}
+	n, err := w.c.Writer.Write(p)
+	w.c.Writer.Flush()
+	return n, err
+}
+
+func readImportField(r io.Reader) (string, error) {
+	value, err := io.ReadAll(io.LimitReader(r, maxImportFieldSize))
+	if err != nil {
+		return "", err
+	}
+	return string(value), nil
+}
@@ -2,12 +2,20 @@ package interfaces
 
 import (
 	"context"
+	"database/sql"
+	"io"
+	"net/netip"
 	"time"
 
 	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	addressRequests "github.com/Kisanlink/aaa-service/v2/internal/entities/requests/addresses"
+	orgRequests "github.com/Kisanlink/aaa-service/v2/internal/entities/requests/organizations"
 	userRequests "github.com/Kisanlink/aaa-service/v2/internal/entities/requests/users"
 	"github.com/Kisanlink/aaa-service/v2/internal/entities/responses"
+	orgResponses "github.com/Kisanlink/aaa-service/v2/internal/entities/responses/organizations"
 	userResponses "github.com/Kisanlink/aaa-service/v2/internal/entities/responses/users"
+	"github.com/Kisanlink/aaa-service/v2/internal/security"
+	"github.com/Kisanlink/aaa-service/v2/migrations"
 	"github.com/Kisanlink/kisanlink-db/pkg/base"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -38,6 +46,94 @@ type CacheService interface {
 	Close() error
 }
 
+// CacheTierStats counts where reads of a cache with an in-process L1 tier were served from
+type CacheTierStats struct {
+	L1Hits      int64 `json:"l1_hits"`
+	L2Hits      int64 `json:"l2_hits"`
+	Misses      int64 `json:"misses"`
+	L1Evictions int64 `json:"l1_evictions"`
+	L1Entries   int   `json:"l1_entries"`
+}
+
+// CacheTierStatsProvider is implemented by caches that keep an L1 tier
+type CacheTierStatsProvider interface {
+	Stats() CacheTierStats
+}
+
+// CheckedCache is implemented by caches that report why an operation failed. The CacheService
+// methods of such caches treat an unreachable backend as a miss or a skipped write.
+type CheckedCache interface {
+	GetChecked(key string) (interface{}, bool, error)
+	SetChecked(key string, value interface{}, ttl int) error
+	DeleteChecked(key string) error
+	Ping(ctx context.Context) error
+}
+
+// CacheDegradationStats reports the circuit breaker of a cache that stops using an unreachable
+// backend
+type CacheDegradationStats struct {
+	Policy string `json:"policy"`
+	// State is closed, open or half_open
+	State               string     `json:"state"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	Trips               int64      `json:"trips"`
+	BypassedReads       int64      `json:"bypassed_reads"`
+	RejectedOperations  int64      `json:"rejected_operations"`
+	DegradedSince       *time.Time `json:"degraded_since,omitempty"`
+}
+
+// CacheDegradation is implemented by caches that stop using an unreachable backend
+type CacheDegradation interface {
+	// RequireAvailable returns an error while the backend is unreachable and the policy fails
+	// closed; operations that must not proceed without the cache call it first
+	RequireAvailable() error
+	DegradationStats() CacheDegradationStats
+}
+
+// DBPoolStatsProvider reports the state of a database connection pool; *sql.DB implements it
+type DBPoolStatsProvider interface {
+	Stats() sql.DBStats
+}
+
+// CacheInvalidation names the cache entries dropped by a permission or role change
+type CacheInvalidation struct {
+	Keys     []string `json:"keys,omitempty"`
+	Patterns []string `json:"patterns,omitempty"`
+	// Origin identifies the publishing instance, which has already applied the invalidation
+	Origin string `json:"origin,omitempty"`
+}
+
+// InvalidationBroadcaster fans cache invalidations out to every service instance. Publishers
+// invalidate their own caches first; subscribers are called for invalidations published elsewhere.
+type InvalidationBroadcaster interface {
+	Publish(ctx context.Context, invalidation CacheInvalidation) error
+	Subscribe(handler func(ctx context.Context, invalidation CacheInvalidation))
+	Close() error
+}
+
+// Organization limits reported in OrganizationQuotaDecision.Exceeded
+const (
+	OrganizationQuotaRateLimit = "rate_limit"
+	OrganizationQuotaMonthly   = "monthly_quota"
+)
+
+// OrganizationQuotaDecision is the outcome of counting one request against an organization's limits
+type OrganizationQuotaDecision struct {
+	Allowed bool
+	// Exceeded names the limit that rejected the request
+	Exceeded     string
+	RateLimit    orgResponses.OrganizationQuotaWindow
+	MonthlyQuota orgResponses.OrganizationQuotaWindow
+}
+
+// OrganizationQuotaService enforces the per-organization rate limits and monthly API quotas
+type OrganizationQuotaService interface {
+	// Enabled reports whether requests are counted at all
+	Enabled() bool
+	ConsumeRequest(ctx context.Context, orgID string) (*OrganizationQuotaDecision, error)
+	GetQuotaStatus(ctx context.Context, orgID string) (*orgResponses.OrganizationQuotaResponse, error)
+}
+
 // Validator interface for input validation
 type Validator interface {
 	ValidateStruct(s interface{}) error
@@ -91,6 +187,8 @@ type UserService interface {
 	ResetPasswordWithToken(ctx context.Context, token, newPassword string) error // Deprecated: Use ResetPassword with OTP
 	ChangePassword(ctx context.Context, userID, oldPassword, newPassword string) error
 	GetUserByEmail(ctx context.Context, email string) (*userResponses.UserResponse, error)
+	GetUsersByIDs(ctx context.Context, req *userRequests.BatchGetUsersRequest) (*userResponses.BatchUsersResponse, error)
+	GetUserDetail(ctx context.Context, userID string, expand *userRequests.UserDetailExpand) (*userResponses.UserDetailResponse, error)
 }
 
 // AddressService interface for address-related operations
@@ -103,6 +201,41 @@ type AddressService interface {
 	GetAddressesByUserID(ctx context.Context, userID string) ([]*models.Address, error)
 	SearchAddresses(ctx context.Context, query string, limit, offset int) ([]*models.Address, error)
 	SearchAddressesCount(ctx context.Context, query string) (int64, error)
+	CreateAddresses(ctx context.Context, reqs []addressRequests.CreateAddressRequest) (*BulkAddressCreateResult, error)
+	Geocode(ctx context.Context, addressID string) (*models.Address, error)
+}
+
+// Per-item outcomes of a bulk address creation
+const (
+	AddressCreateStatusCreated = "created"
+	AddressCreateStatusFailed  = "failed"
+)
+
+// AddressCreateResult reports the outcome for one address in a bulk creation
+type AddressCreateResult struct {
+	Index     int    `json:"index"`
+	Status    string `json:"status"`
+	AddressID string `json:"address_id,omitempty"`
+	Geocoded  bool   `json:"geocoded"`
+	Error     string `json:"error,omitempty"`
+}
+
+// BulkAddressCreateResult summarizes a bulk address creation
+type BulkAddressCreateResult struct {
+	Created int                   `json:"created"`
+	Failed  int                   `json:"failed"`
+	Results []AddressCreateResult `json:"results"`
+}
+
+// GeoPoint is a latitude/longitude pair in decimal degrees
+type GeoPoint struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// Geocoder resolves an address to coordinates. A nil point with a nil error means the provider found no match.
+type Geocoder interface {
+	Geocode(ctx context.Context, address *models.Address) (*GeoPoint, error)
 }
 
 // RoleService interface for role management operations
@@ -122,6 +255,89 @@ type RoleService interface {
 	AddChildRole(ctx context.Context, parentRoleID, childRoleID string) error
 	RemoveChildRole(ctx context.Context, parentRoleID, childRoleID string) error
 	GetRoleWithChildren(ctx context.Context, roleID string) (*models.Role, error)
+	AssignRoleToUsers(ctx context.Context, roleID string, userIDs []string, assignedBy string) (*BulkRoleAssignmentResult, error)
+	SetUserRoles(ctx context.Context, userID string, roleIDs []string, actor string) (*UserRoleSetResult, error)
+	GetUsersByRole(ctx context.Context, roleID string, limit, offset int) ([]*models.User, int64, error)
+	GetUsersByRoleName(ctx context.Context, name, orgID string, limit, offset int) ([]*models.User, int64, error)
+}
+
+// RoleConstraintService manages the role assignment constraints of organizations and reports
+// existing assignments that break them
+type RoleConstraintService interface {
+	CreateRoleConstraint(ctx context.Context, orgID string, req *orgRequests.CreateRoleConstraintRequest) (*orgResponses.RoleConstraintResponse, error)
+	ListRoleConstraints(ctx context.Context, orgID string) ([]*orgResponses.RoleConstraintResponse, error)
+	DeleteRoleConstraint(ctx context.Context, orgID, constraintID string) error
+	FindRoleConstraintViolations(ctx context.Context, orgID string) (*orgResponses.RoleConstraintViolationsResponse, error)
+}
+
+// UserAttributeService stores the custom attributes organizations keep on their members,
+// validated against the organization's schema when it has one
+type UserAttributeService interface {
+	GetUserAttributeSchema(ctx context.Context, orgID string) (*orgResponses.UserAttributeSchemaResponse, error)
+	SetUserAttributeSchema(ctx context.Context, orgID string, schema models.UserAttributeSchema, updatedBy string) (*orgResponses.UserAttributeSchemaResponse, error)
+	GetUserAttributes(ctx context.Context, orgID, userID string) (*orgResponses.UserAttributesResponse, error)
+	SetUserAttributes(ctx context.Context, orgID, userID string, attrs map[string]interface{}, updatedBy string) (*orgResponses.UserAttributesResponse, error)
+}
+
+// Per-user outcomes of a bulk role assignment
+const (
+	RoleAssignmentStatusAssigned = "assigned"
+	RoleAssignmentStatusSkipped  = "skipped"
+	RoleAssignmentStatusFailed   = "failed"
+	// RoleAssignmentStatusPendingKYC marks a KYC-gated role held back until the user's Aadhaar is verified
+	RoleAssignmentStatusPendingKYC = "pending_kyc"
+)
+
+// UserRoleAssignmentResult reports the outcome for one user in a bulk role assignment
+type UserRoleAssignmentResult struct {
+	UserID string `json:"user_id"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// BulkRoleAssignmentResult summarizes a bulk role assignment
+type BulkRoleAssignmentResult struct {
+	RoleID     string                     `json:"role_id"`
+	Assigned   int                        `json:"assigned"`
+	Skipped    int                        `json:"skipped"`
+	PendingKYC int                        `json:"pending_kyc"`
+	Failed     int                        `json:"failed"`
+	Results    []UserRoleAssignmentResult `json:"results"`
+}
+
+// UserRoleSetResult reports the net change made by replacing the roles assigned directly to a user
+type UserRoleSetResult struct {
+	UserID     string   `json:"user_id"`
+	RoleIDs    []string `json:"role_ids"` // the roles assigned directly to the user afterwards
+	Assigned   []string `json:"assigned"`
+	Removed    []string `json:"removed"`
+	PendingKYC []string `json:"pending_kyc"` // KYC-gated roles held back until the user's Aadhaar is verified
+}
+
+// GroupMemberFilter narrows and enriches a group member listing
+type GroupMemberFilter struct {
+	PrincipalType string // "user" or "service"; empty lists both
+	IncludeRoles  bool   // attach the roles each member holds through the group
+}
+
+// GroupFilter narrows a group listing within an organization; zero fields do not filter
+type GroupFilter struct {
+	NameContains string  // keep groups whose name contains this text
+	IsActive     *bool   // keep only active or only inactive groups
+	ParentID     *string // keep the direct children of this group; an empty ID keeps the root groups
+	HasRole      string  // keep groups holding this role
+}
+
+// EffectiveRoleScope narrows an effective role calculation; empty fields do not filter
+type EffectiveRoleScope struct {
+	GroupID      string // keep roles assigned within this group's subtree
+	ResourceType string // keep roles granting a permission on this resource type
+	ResourceID   string // with ResourceType, keep roles granting a permission on this resource or on all of its type
+}
+
+// IsEmpty reports whether the scope does not filter
+func (s EffectiveRoleScope) IsEmpty() bool {
+	return s.GroupID == "" && s.ResourceType == "" && s.ResourceID == ""
 }
 
 // GroupService interface for group management operations
@@ -130,12 +346,16 @@ type GroupService interface {
 	GetGroup(ctx context.Context, groupID string) (interface{}, error)
 	UpdateGroup(ctx context.Context, groupID string, req interface{}) (interface{}, error)
 	DeleteGroup(ctx context.Context, groupID string, deletedBy string) error
+	MoveGroup(ctx context.Context, groupID, newParentID, movedBy string) (interface{}, error)
 	ListGroups(ctx context.Context, limit, offset int, organizationID string, includeInactive bool) (interface{}, error)
 	CountGroups(ctx context.Context, organizationID string, includeInactive bool) (int64, error)
+	ListOrganizationGroups(ctx context.Context, organizationID string, filter GroupFilter, limit, offset int) (interface{}, int64, error)
 	AddMemberToGroup(ctx context.Context, req interface{}) (interface{}, error)
 	RemoveMemberFromGroup(ctx context.Context, groupID, principalID string, removedBy string) error
 	GetGroupMembers(ctx context.Context, groupID string, limit, offset int) (interface{}, error)
+	ListGroupMembers(ctx context.Context, groupID string, filter GroupMemberFilter, limit, offset int) (interface{}, error)
 	CountGroupMembers(ctx context.Context, groupID string) (int64, error)
+	CountGroupMembersByType(ctx context.Context, groupID, principalType string) (int64, error)
 	GetUserGroupsInOrganization(ctx context.Context, orgID, userID string, limit, offset int) (interface{}, error)
 
 	// Role assignment methods for organization-scoped group operations
@@ -143,6 +363,7 @@ type GroupService interface {
 	RemoveRoleFromGroup(ctx context.Context, groupID, roleID string) error
 	GetGroupRoles(ctx context.Context, groupID string) (interface{}, error)
 	GetUserEffectiveRoles(ctx context.Context, orgID, userID string) (interface{}, error)
+	GetUserScopedEffectiveRoles(ctx context.Context, orgID, userID string, scope EffectiveRoleScope) (interface{}, error)
 }
 
 // OrganizationService interface for organization management operations
@@ -153,11 +374,42 @@ type OrganizationService interface {
 	DeleteOrganization(ctx context.Context, orgID string, deletedBy string) error
 	ListOrganizations(ctx context.Context, limit, offset int, includeInactive bool, orgType string) ([]interface{}, error)
 	CountOrganizations(ctx context.Context, includeInactive bool, orgType string) (int64, error)
+
+	// Hard delete and restore of organizations
+	RequestOrganizationHardDelete(ctx context.Context, orgID string, requestedBy string) (interface{}, error)
+	HardDeleteOrganization(ctx context.Context, orgID string, deletedBy string, confirmationToken string) error
+	DeleteOrganizationCascade(ctx context.Context, orgID string, deletedBy string) (interface{}, error)
+	RestoreOrganization(ctx context.Context, orgID string, restoredBy string) (interface{}, error)
+	RestoreOrganizationCascade(ctx context.Context, orgID string, restoredBy string) (interface{}, error)
+	MergeOrganizations(ctx context.Context, sourceID, targetID, mergedBy string) (interface{}, error)
+	ListOrganizationsWithDeleted(ctx context.Context, limit, offset int) ([]interface{}, error)
+	CountOrganizationsWithDeleted(ctx context.Context) (int64, error)
 	GetOrganizationHierarchy(ctx context.Context, orgID string) (interface{}, error)
+	GetOrganizationAncestors(ctx context.Context, orgID string, maxDepth, limit, offset int) (interface{}, error)
+	GetOrganizationDescendants(ctx context.Context, orgID string, maxDepth, limit, offset int) (interface{}, error)
 	ActivateOrganization(ctx context.Context, orgID string) error
 	DeactivateOrganization(ctx context.Context, orgID string) error
 	GetOrganizationStats(ctx context.Context, orgID string) (interface{}, error)
 
+	// Direct user membership management
+	AddUserToOrganization(ctx context.Context, orgID string, req interface{}, addedBy string) (interface{}, error)
+	RemoveUserFromOrganization(ctx context.Context, orgID, userID string, removedBy string) (interface{}, error)
+	ListOrganizationMembers(ctx context.Context, orgID string, limit, offset int) (interface{}, error)
+
+	// Per-organization settings
+	GetOrganizationSettings(ctx context.Context, orgID string) (interface{}, error)
+	UpdateOrganizationSettings(ctx context.Context, orgID string, req interface{}, updatedBy string) (interface{}, error)
+	GetSetting(ctx context.Context, orgID, key string) (interface{}, error)
+
+	// Role templates
+	ListRoleTemplates(ctx context.Context) (interface{}, error)
+	CreateRoleTemplate(ctx context.Context, req interface{}, createdBy string) (interface{}, error)
+	DeleteRoleTemplate(ctx context.Context, name string, deletedBy string) error
+	ApplyRoleTemplate(ctx context.Context, orgID, templateName, appliedBy string) (interface{}, error)
+
+	// Roles assignable within an organization: its own roles plus the global ones
+	ListAvailableRoles(ctx context.Context, orgID string) (interface{}, error)
+
 	// New group management methods within organization context
 	GetOrganizationGroups(ctx context.Context, orgID string, limit, offset int, includeInactive bool) (interface{}, error)
 	CreateGroupInOrganization(ctx context.Context, orgID string, req interface{}) (interface{}, error)
@@ -165,6 +417,7 @@ type OrganizationService interface {
 	UpdateGroupInOrganization(ctx context.Context, orgID, groupID string, req interface{}) (interface{}, error)
 	DeleteGroupInOrganization(ctx context.Context, orgID, groupID string, deletedBy string) error
 	GetGroupHierarchyInOrganization(ctx context.Context, orgID, groupID string) (interface{}, error)
+	GetGroupChildren(ctx context.Context, orgID, parentGroupID string, limit, offset int) (interface{}, error)
 
 	// User-group management within organization context
 	AddUserToGroupInOrganization(ctx context.Context, orgID, groupID, userID string, req interface{}) (interface{}, error)
@@ -195,7 +448,9 @@ type AuthService interface {
 type UserRepository interface {
 	base.Repository[*models.User]
 	GetByUsername(ctx context.Context, username string) (*models.User, error)
+	GetByUsernameIgnoreCase(ctx context.Context, username string) (*models.User, error)
 	GetByPhoneNumber(ctx context.Context, phoneNumber string, countryCode string) (*models.User, error)
+	GetByPhoneNumberIncludingDeleted(ctx context.Context, phoneNumber string, countryCode string) ([]*models.User, error)
 	GetByMobileNumber(ctx context.Context, mobileNumber uint64) (*models.User, error)
 	GetByAadhaarNumber(ctx context.Context, aadhaarNumber string) (*models.User, error)
 	GetByEmail(ctx context.Context, email string) (*models.User, error)
@@ -208,6 +463,40 @@ type UserRepository interface {
 	ListAll(ctx context.Context) ([]*models.User, error)
 	GetWithAddress(ctx context.Context, userID string) (*models.User, error)
 	GetWithProfile(ctx context.Context, userID string) (*models.User, error)
+	GetWithDetails(ctx context.Context, userID string, includeProfile, includeContacts, includeAddresses, includeRoles bool) (*models.User, error)
+	UpdateLastLogin(ctx context.Context, userID, ipAddress string) error
+	GetUsersWithRelationships(ctx context.Context, userIDs []string, includeRoles, includeProfile, includeAddresses bool) ([]*models.User, error)
+	// CreateWithRoles creates a user together with their initial role assignments
+	CreateWithRoles(ctx context.Context, user *models.User, userRoles []*models.UserRole) error
+	// RestoreWithRoles restores a soft-deleted user, replacing its prior role assignments with userRoles
+	RestoreWithRoles(ctx context.Context, user *models.User, userRoles []*models.UserRole) error
+	ExistingIDs(ctx context.Context, ids []string, includeDeleted bool) ([]string, error)
+}
+
+// UserIdentityRepository interface for external identity provider links
+type UserIdentityRepository interface {
+	Create(ctx context.Context, identity *models.UserIdentity) error
+	GetByProviderAndSubject(ctx context.Context, provider, subject string) (*models.UserIdentity, error)
+	GetByUserID(ctx context.Context, userID string) ([]*models.UserIdentity, error)
+	ExistsByUserAndProvider(ctx context.Context, userID, provider string) (bool, error)
+	GetUserIDByVerifiedEmail(ctx context.Context, email string) (string, error)
+}
+
+// UserDeviceRepository interface for device-bound MPINs
+type UserDeviceRepository interface {
+	Create(ctx context.Context, device *models.UserDevice) error
+	Update(ctx context.Context, device *models.UserDevice) error
+	GetByUserAndDevice(ctx context.Context, userID, deviceID string) (*models.UserDevice, error)
+	ListByUserID(ctx context.Context, userID string) ([]*models.UserDevice, error)
+	Delete(ctx context.Context, id string) error
+}
+
+// AccountDeletionRepository interface for user-initiated account deletion requests
+type AccountDeletionRepository interface {
+	Create(ctx context.Context, request *models.AccountDeletionRequest) error
+	Update(ctx context.Context, request *models.AccountDeletionRequest) error
+	GetPendingByUserID(ctx context.Context, userID string) (*models.AccountDeletionRequest, error)
+	ListDue(ctx context.Context, before time.Time, limit int) ([]*models.AccountDeletionRequest, error)
 }
 
 // ServiceRepository interface for service data operations (for service-to-service auth)
@@ -228,6 +517,7 @@ type AddressRepository interface {
 type RoleRepository interface {
 	base.Repository[*models.Role]
 	GetByName(ctx context.Context, name string) (*models.Role, error)
+	GetByNameInOrganization(ctx context.Context, name, organizationID string) (*models.Role, error)
 	GetActive(ctx context.Context, limit, offset int) ([]*models.Role, error)
 	Search(ctx context.Context, query string, limit, offset int) ([]*models.Role, error)
 	ExistsByName(ctx context.Context, name string) (bool, error)
@@ -235,6 +525,12 @@ type RoleRepository interface {
 	GetAll(ctx context.Context) ([]*models.Role, error)
 }
 
+// OrganizationRoleRepository lists the roles that can be assigned within an organization
+type OrganizationRoleRepository interface {
+	GetGlobalRoles(ctx context.Context) ([]*models.Role, error)
+	GetByOrganization(ctx context.Context, organizationID string) ([]*models.Role, error)
+}
+
 // UserRoleRepository interface for user-role relationship operations
 type UserRoleRepository interface {
 	base.Repository[*models.UserRole]
@@ -249,6 +545,44 @@ type UserRoleRepository interface {
 	AssignRole(ctx context.Context, userID, roleID string) error
 	RemoveRole(ctx context.Context, userID, roleID string) error
 	IsRoleAssigned(ctx context.Context, userID, roleID string) (bool, error)
+	ListUsersByRole(ctx context.Context, roleID string, limit, offset int) ([]*models.User, int64, error)
+	ReplaceDirectRoles(ctx context.Context, userID string, assign, remove []string) error
+}
+
+// RoleConstraintRepository interface for organization role assignment constraints
+type RoleConstraintRepository interface {
+	Create(ctx context.Context, constraint *models.RoleConstraint) error
+	GetByID(ctx context.Context, id string) (*models.RoleConstraint, error)
+	ListByOrganization(ctx context.Context, organizationID string) ([]*models.RoleConstraint, error)
+	Delete(ctx context.Context, id string) error
+}
+
+// PendingRoleAssignmentRepository interface for assignments of KYC-gated roles awaiting the user's
+// Aadhaar verification
+type PendingRoleAssignmentRepository interface {
+	Create(ctx context.Context, assignment *models.PendingRoleAssignment) error
+	Exists(ctx context.Context, userID, roleID string) (bool, error)
+	ListByUser(ctx context.Context, userID string) ([]*models.PendingRoleAssignment, error)
+	Delete(ctx context.Context, id string) error
+}
+
+// UserProfileReader reads the profile holding a user's KYC state
+type UserProfileReader interface {
+	GetByUserID(ctx context.Context, userID string) (*models.UserProfile, error)
+}
+
+// OutboxRepository interface for the transactional outbox. Claimed events are leased to one relay
+// until they are marked sent or rescheduled, or the lease runs out.
+type OutboxRepository interface {
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*models.OutboxEvent, error)
+	MarkSent(ctx context.Context, id string, sentAt time.Time) error
+	MarkRetry(ctx context.Context, id string, attempts int, nextAttemptAt time.Time, lastError string) error
+	MarkFailed(ctx context.Context, id string, attempts int, lastError string) error
+}
+
+// EventPublisher delivers outbox events to an external system such as a message broker or a webhook
+type EventPublisher interface {
+	Publish(ctx context.Context, event *models.OutboxEvent) error
 }
 
 // ContactRepository interface for contact data operations
@@ -275,6 +609,12 @@ type GroupRepository interface {
 	GetGroupMembers(ctx context.Context, groupID string, limit, offset int) ([]*models.GroupMembership, error)
 }
 
+// GroupTreeRepository interface for loading the group hierarchy of an organization level by level
+type GroupTreeRepository interface {
+	GetChildrenInOrganization(ctx context.Context, organizationID, parentID string, limit, offset int) ([]*models.Group, int64, error)
+	CountChildren(ctx context.Context, parentIDs []string) (map[string]int64, error)
+}
+
 // GroupRoleRepository interface for group-role relationship operations
 type GroupRoleRepository interface {
 	base.Repository[*models.GroupRole]
@@ -335,11 +675,18 @@ type EmailService interface {
 // SMSService interface for SMS operations
 type SMSService interface {
 	SendOTP(ctx context.Context, phoneNumber, otp string) error
+	SendVerificationOTP(ctx context.Context, phoneNumber, otp string) error
 	SendSecurityAlert(ctx context.Context, phoneNumber, message string) error
 	SendBulkSMS(ctx context.Context, phoneNumbers []string, message string) error
 	ValidatePhoneNumber(phoneNumber string) error
 }
 
+// OTPSender delivers a one-time password to a destination: a phone number in E.164 format or an
+// email address, depending on the channel. Generating and validating the OTP is not its concern.
+type OTPSender interface {
+	SendOTP(ctx context.Context, destination, otp string) error
+}
+
 // NotificationService interface for notifications
 type NotificationService interface {
 	SendNotification(ctx context.Context, userID, message string) error
@@ -349,6 +696,153 @@ type NotificationService interface {
 	GetUnreadCount(ctx context.Context, userID string) (int, error)
 }
 
+// Security event types users are notified about
+const (
+	SecurityEventPasswordChanged = "password_changed"
+	SecurityEventMPinChanged     = "mpin_changed"
+	SecurityEventNewDeviceLogin  = "new_device_login"
+	SecurityEventAccountLocked   = "account_locked"
+	SecurityEventSessionLimit    = "session_limit_reached"
+)
+
+// SecurityEvent describes a security-relevant change to a user's account
+type SecurityEvent struct {
+	Type       string
+	UserID     string
+	OccurredAt time.Time
+	IPAddress  string
+	UserAgent  string
+	Details    map[string]interface{}
+}
+
+// SecurityNotifier notifies users about security events on their account.
+// NotifySecurityEvent must not block the caller.
+type SecurityNotifier interface {
+	NotifySecurityEvent(ctx context.Context, event SecurityEvent)
+}
+
+// LoginChallengeService issues and redeems the short-lived challenges of a stepped login
+type LoginChallengeService interface {
+	// LoginStepUp starts the MPIN step of a login that needs one and returns nil otherwise
+	LoginStepUp(ctx context.Context, userID, method string, hasMPin bool) (*responses.LoginChallengeResponse, error)
+	VerifyLoginMPIN(ctx context.Context, challengeToken, mpin string) (string, error)
+}
+
+// DeviceSessionService manages the long-lived sessions of remembered devices
+type DeviceSessionService interface {
+	CreateDeviceSession(ctx context.Context, userID, deviceName string) (*responses.DeviceSessionTokenResponse, error)
+	ExchangeDeviceSession(ctx context.Context, sessionToken string) (string, error)
+	ListDeviceSessions(ctx context.Context, userID string) ([]*responses.DeviceSessionResponse, error)
+	RevokeDeviceSession(ctx context.Context, userID, sessionID string) error
+}
+
+// IssuedToken describes a token handed to a user, as recorded for listing and revocation
+type IssuedToken struct {
+	JTI       string
+	TokenType string // "access" or "refresh"
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+}
+
+// TokenSessionService tracks the access and refresh tokens issued to users so they can be listed
+// and revoked. The tokens of one login form a family that refreshing carries forward.
+type TokenSessionService interface {
+	TrackIssuedTokens(ctx context.Context, userID, familyID string, tokens ...IssuedToken) (string, error)
+	RotateRefreshToken(ctx context.Context, userID string, refreshToken IssuedToken) (string, error)
+	ListTokenSessions(ctx context.Context, userID string) ([]*responses.TokenSessionResponse, error)
+	RevokeTokenSession(ctx context.Context, userID, jti string) error
+	IsTokenRevoked(jti string) bool
+}
+
+// SessionLimitResolver finds the concurrent session limit a user's organizations set
+type SessionLimitResolver interface {
+	// MaxConcurrentSessions returns the lowest max_concurrent_sessions setting of the user's
+	// organizations, or 0 when none sets one
+	MaxConcurrentSessions(ctx context.Context, userID string) (int, error)
+}
+
+// Per-row outcomes of a CSV user import
+const (
+	UserImportStatusCreated   = "created"
+	UserImportStatusValid     = "valid" // Dry run: the row would be created
+	UserImportStatusDuplicate = "duplicate"
+	UserImportStatusInvalid   = "invalid"
+	UserImportStatusFailed    = "failed"
+)
+
+// UserImportOptions controls a CSV user import
+type UserImportOptions struct {
+	DryRun  bool              // Validate every row without creating users
+	Mapping map[string]string // CSV header to import field, for headers that don't name a field
+}
+
+// UserImportSummary counts the rows of a CSV user import by outcome
+type UserImportSummary struct {
+	DryRun     bool `json:"dry_run"`
+	Rows       int  `json:"rows"`
+	Created    int  `json:"created"`
+	Valid      int  `json:"valid"`
+	Duplicates int  `json:"duplicates"`
+	Invalid    int  `json:"invalid"`
+	Failed     int  `json:"failed"`
+}
+
+// UserImportService creates users from a CSV stream, writing a result CSV that annotates each row
+type UserImportService interface {
+	ImportUsersCSV(ctx context.Context, in io.Reader, out io.Writer, opts UserImportOptions) (*UserImportSummary, error)
+}
+
+// UserDataExportWriter receives the sections of a user's data export as they are loaded
+type UserDataExportWriter interface {
+	// WriteAccount receives the profile, contacts, addresses, roles and group memberships
+	WriteAccount(account *userResponses.UserDetailResponse) error
+	// WriteAuditEntry receives each entry of the user's audit trail, most recent first
+	WriteAuditEntry(entry *models.AuditLog) error
+}
+
+// UserDataExportService exports the data held about a user, with the data of other users that
+// appears in it redacted
+type UserDataExportService interface {
+	ExportUserData(ctx context.Context, userID string, out UserDataExportWriter) error
+}
+
+// ProfileCompletenessService scores how complete a user's profile is from their profile, verified
+// contacts, addresses and KYC status
+type ProfileCompletenessService interface {
+	GetProfileCompleteness(ctx context.Context, userID string) (*userResponses.ProfileCompletenessResponse, error)
+}
+
+// CredentialResetService lets users who forgot their password or MPIN set a new one. An OTP is sent
+// to a verified contact of the account, and the returned reset token and the OTP complete the reset.
+type CredentialResetService interface {
+	InitiatePasswordReset(ctx context.Context, phoneNumber, countryCode, username, email *string) (string, error)
+	InitiateMPINReset(ctx context.Context, phoneNumber, countryCode, username, email *string) (string, error)
+	CompleteReset(ctx context.Context, token, otp, newCredential string) error
+}
+
+// SessionRevoker signs a user out everywhere
+type SessionRevoker interface {
+	// RevokeAllSessions revokes the issued tokens and remembered devices of the user and returns
+	// how many were revoked
+	RevokeAllSessions(ctx context.Context, userID string) (int, error)
+}
+
+// AccountDeletionService lets users delete their own account. The account is soft-deleted at once,
+// which blocks login, and hard deleted after a grace period during which the user can cancel by
+// signing in with their password.
+type AccountDeletionService interface {
+	RequestAccountDeletion(ctx context.Context, userID, password, reason string) (*models.AccountDeletionRequest, error)
+	CancelAccountDeletion(ctx context.Context, phoneNumber, countryCode, password string) (*models.AccountDeletionRequest, error)
+}
+
+// DeviceMPinService manages MPINs bound to a single device of a user
+type DeviceMPinService interface {
+	SetDeviceMPIN(ctx context.Context, userID, deviceID, deviceName, mPin, currentPassword string) (*responses.UserDeviceResponse, error)
+	VerifyDeviceMPIN(ctx context.Context, userID, deviceID, mPin string) error
+	ListUserDevices(ctx context.Context, userID string) ([]*responses.UserDeviceResponse, error)
+	RevokeDeviceMPIN(ctx context.Context, userID, deviceID string) error
+}
+
 // MaintenanceService interface for maintenance mode management
 type MaintenanceService interface {
 	IsMaintenanceMode(ctx context.Context) (bool, interface{}, error)
@@ -359,6 +853,30 @@ type MaintenanceService interface {
 	UpdateMaintenanceMessage(ctx context.Context, message string, updatedBy string) error
 }
 
+// ImpersonationService lets support engineers act as another user through a short-lived, audited token
+type ImpersonationService interface {
+	StartImpersonation(ctx context.Context, adminID, targetUserID string) (*responses.ImpersonationResponse, error)
+	StopImpersonation(ctx context.Context, adminID, impersonationID string) error
+}
+
+// SigningKeyManager lists, publishes and rotates the keys tokens are signed with
+type SigningKeyManager interface {
+	Keys() []security.SigningKeyInfo
+	JWKS() security.JSONWebKeySet
+	Rotate() (security.SigningKeyInfo, error)
+}
+
+// RBACSeedPreviewer reports what the RBAC seed would create in the database, without writing to it
+type RBACSeedPreviewer interface {
+	PreviewRBACSeed(ctx context.Context) (*migrations.RBACSeedPreview, error)
+}
+
+// HierarchyDepthChecker reports organizations and groups nested deeper than a maximum depth; a
+// maxDepth of 0 uses the configured limit
+type HierarchyDepthChecker interface {
+	CheckHierarchyDepth(ctx context.Context, maxDepth int) (interface{}, error)
+}
+
 // HealthService interface for health check operations
 type HealthService interface {
 	CheckDatabaseHealth(ctx context.Context) error
@@ -478,6 +996,7 @@ type AuditService interface {
 // AuditRepository defines the interface for audit log repository operations
 type AuditRepository interface {
 	Create(ctx context.Context, auditLog *models.AuditLog) error
+	CreateBatch(ctx context.Context, auditLogs []*models.AuditLog) error
 	GetByID(ctx context.Context, id string) (*models.AuditLog, error)
 	Update(ctx context.Context, auditLog *models.AuditLog) error
 	Delete(ctx context.Context, id string) error
@@ -501,6 +1020,100 @@ type AuditRepository interface {
 	ArchiveOldLogs(ctx context.Context, cutoffDate time.Time) (int64, error)
 	ValidateIntegrity(ctx context.Context, auditLogID string) (*models.AuditLog, error)
 	ListByResourceAndActions(ctx context.Context, resourceType, resourceID string, actions []string, limit, offset int) ([]*models.AuditLog, error)
+	ListByUserAndActions(ctx context.Context, userID string, actions []string, limit, offset int) ([]*models.AuditLog, error)
+	ListByFilter(ctx context.Context, filter AuditLogFilter, limit, offset int) ([]*models.AuditLog, error)
+	CountByFilter(ctx context.Context, filter AuditLogFilter) (int64, error)
+}
+
+// EndpointUsage aggregates the calls made to one endpoint
+type EndpointUsage struct {
+	Method       string    `json:"method"`
+	Endpoint     string    `json:"endpoint"`
+	Calls        int64     `json:"calls"`
+	Failures     int64     `json:"failures"`
+	LastCalledAt time.Time `json:"last_called_at"`
+}
+
+// PrincipalActivityRepository aggregates the audit trail of service principals, identified by the
+// principal_id audit detail
+type PrincipalActivityRepository interface {
+	GetPrincipalEndpointUsage(ctx context.Context, principalIDs []string, since time.Time) ([]EndpointUsage, error)
+	GetPrincipalLastActivity(ctx context.Context, principalIDs []string) (*time.Time, error)
+	// GetPrincipalsLastActivity returns when each of the given principals last appeared in the
+	// audit trail; principals that never did are absent
+	GetPrincipalsLastActivity(ctx context.Context, principalIDs []string) (map[string]time.Time, error)
+}
+
+// ServiceFilter narrows a service principal listing; zero fields do not filter
+type ServiceFilter struct {
+	OrganizationID string
+	NameContains   string     // keep services whose name contains this text
+	IsActive       *bool      // keep only active or only inactive services
+	UsedSince      *time.Time // keep services that called the API at or after this time
+	UnusedSince    *time.Time // keep services that have not called the API since this time, including those that never did
+}
+
+// ServiceListRepository lists service principals. The last-used fields of a ServiceFilter are
+// matched against the audit trail by the caller, not by the repository.
+type ServiceListRepository interface {
+	ListFiltered(ctx context.Context, filter ServiceFilter, limit, offset int) ([]*models.Service, int64, error)
+}
+
+// Sources of a PrincipalResourceGrant
+const (
+	// GrantSourceDirect is a resource permission assigned to the role (resource_permissions)
+	GrantSourceDirect = "direct"
+	// GrantSourceRole is a named permission of the role (role_permissions)
+	GrantSourceRole = "role"
+)
+
+// PrincipalResourceGrant is a permission a user holds through one of their active roles
+type PrincipalResourceGrant struct {
+	Source       string
+	RoleID       string
+	RoleName     string
+	ResourceType string
+	// ResourceID is empty for named permissions, which cover every resource of the type
+	ResourceID string
+	Action     string
+	// Permission is the permission name of role-derived grants
+	Permission string
+	// RoleOrganizationID and RoleGroupID scope the role; SourceGroupID is the group the role is
+	// inherited from, nil when it is assigned to the user directly
+	RoleOrganizationID *string
+	RoleGroupID        *string
+	SourceGroupID      *string
+}
+
+// PrincipalGrantRepository lists the resource permissions and role permissions of a user's roles
+type PrincipalGrantRepository interface {
+	ListUserResourceGrants(ctx context.Context, userID, resourceType string, limit, offset int) ([]PrincipalResourceGrant, int64, error)
+}
+
+// ResourceReferenceValidator checks that a permission's resource_id refers to an existing resource.
+// A dangling reference is reported as a validation error.
+type ResourceReferenceValidator interface {
+	ValidateResourceReference(ctx context.Context, resourceType, resourceID string) error
+}
+
+// AuditLogFilter combines audit log conditions. Empty fields are ignored; a log must match every
+// non-empty field, and any one of the values of a multi-valued field.
+type AuditLogFilter struct {
+	OrganizationID string
+	UserID         string
+	ActorID        string
+	OnBehalfOfID   string
+	Actions        []string
+	ResourceTypes  []string
+	ResourceID     string
+	Status         string
+	StartTime      *time.Time
+	EndTime        *time.Time
+	// IPAddress matches the client address exactly; IPRange matches every address in a network
+	IPAddress string
+	IPRange   *netip.Prefix
+	// UserAgent matches user agents containing it, ignoring case
+	UserAgent string
 }
 
 // OrganizationRepository interface for organization data operations
@@ -515,6 +1128,12 @@ type OrganizationRepository interface {
 	Exists(ctx context.Context, id string) (bool, error)
 	SoftDelete(ctx context.Context, id string, deletedBy string) error
 	Restore(ctx context.Context, id string) error
+	ListWithDeleted(ctx context.Context, limit, offset int) ([]*models.Organization, error)
+	CountWithDeleted(ctx context.Context) (int64, error)
+	HardDeleteCascade(ctx context.Context, orgID string) (*models.OrganizationDeletionSnapshot, error)
+	SoftDeleteCascade(ctx context.Context, orgID, deletedBy string) (*models.OrganizationCascadeSummary, error)
+	RestoreCascade(ctx context.Context, orgID string) (*models.OrganizationCascadeSummary, error)
+	MergeInto(ctx context.Context, sourceID, targetID, mergedBy string) (*models.OrganizationMergeSummary, error)
 	GetByName(ctx context.Context, name string) (*models.Organization, error)
 	GetByType(ctx context.Context, orgType string, limit, offset int) ([]*models.Organization, error)
 	ListActive(ctx context.Context, limit, offset int) ([]*models.Organization, error)
@@ -532,6 +1151,41 @@ type OrganizationRepository interface {
 	GetRootOrganizations(ctx context.Context, limit, offset int) ([]*models.Organization, error)
 }
 
+// OrganizationMemberRepository interface for direct user-organization memberships
+type OrganizationMemberRepository interface {
+	Create(ctx context.Context, member *models.OrganizationMember) error
+	GetByOrganizationAndUser(ctx context.Context, orgID, userID string) (*models.OrganizationMember, error)
+	ListByOrganization(ctx context.Context, orgID string, limit, offset int) ([]*models.OrganizationMember, error)
+	CountByOrganization(ctx context.Context, orgID string) (int64, error)
+	SoftDelete(ctx context.Context, id string, deletedBy string) error
+	CountActiveGroupMemberships(ctx context.Context, orgID, userID string) (int64, error)
+}
+
+// OrganizationSettingRepository interface for per-organization settings
+type OrganizationSettingRepository interface {
+	ListByOrganization(ctx context.Context, orgID string) ([]*models.OrganizationSetting, error)
+	Upsert(ctx context.Context, setting *models.OrganizationSetting) error
+	DeleteByOrganizationAndKey(ctx context.Context, orgID, key string) error
+}
+
+// OrganizationUserAttributeRepository interface for the custom attributes organizations keep on
+// their users and the schemas they validate them against
+type OrganizationUserAttributeRepository interface {
+	GetSchema(ctx context.Context, orgID string) (*models.OrganizationUserAttributeSchema, error)
+	UpsertSchema(ctx context.Context, schema *models.OrganizationUserAttributeSchema) error
+	GetAttributes(ctx context.Context, orgID, userID string) (*models.OrganizationUserAttributes, error)
+	UpsertAttributes(ctx context.Context, attributes *models.OrganizationUserAttributes) error
+}
+
+// RoleTemplateRepository interface for admin-defined role templates and applying templates to organizations
+type RoleTemplateRepository interface {
+	List(ctx context.Context) ([]*models.RoleTemplate, error)
+	GetByName(ctx context.Context, name string) (*models.RoleTemplate, error)
+	Create(ctx context.Context, template *models.RoleTemplate) error
+	Delete(ctx context.Context, template *models.RoleTemplate) error
+	ApplyToOrganization(ctx context.Context, orgID string, template models.RoleTemplateDefinition, appliedBy string) (*models.RoleTemplateApplication, error)
+}
+
 // UserRepositoryInterface interface for user data operations (renamed to avoid conflict)
 type UserRepositoryInterface interface {
 	// Basic CRUD operations
@@ -560,3 +1214,11 @@ type UserRepositoryInterface interface {
 	UpdateLastLogin(ctx context.Context, userID string) error
 	VerifyPassword(ctx context.Context, userID, password string) (bool, error)
 }
+
+// LoginRecorder records sign-ins completed by the HTTP login endpoints in the user's login history
+type LoginRecorder interface {
+	// RecordLogin audits a successful sign-in and updates the user's last login time and IP
+	RecordLogin(ctx context.Context, userID, method string)
+	// RecordLoginFailure audits a failed sign-in against the account holding the phone number, if any
+	RecordLoginFailure(ctx context.Context, phoneNumber, countryCode, method, reason string)
+}
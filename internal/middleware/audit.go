@@ -8,6 +8,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
 	"github.com/Kisanlink/aaa-service/v2/internal/services"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -68,6 +69,13 @@ func (m *AuditMiddleware) HTTPAuditMiddleware() gin.HandlerFunc {
 		// Calculate duration
 		duration := time.Since(start)
 
+		// Authentication runs after this middleware, so the caller is only known now
+		if uid, exists := c.Get("user_id"); exists {
+			if userIDStr, ok := uid.(string); ok && userIDStr != "" {
+				userID = userIDStr
+			}
+		}
+
 		// Prepare audit details
 		details := map[string]interface{}{
 			"method":      c.Request.Method,
@@ -98,19 +106,19 @@ func (m *AuditMiddleware) HTTPAuditMiddleware() gin.HandlerFunc {
 		if statusCode == http.StatusUnauthorized && len(c.Request.URL.Path) >= len("/api/v1/auth/login") && c.Request.URL.Path[:len("/api/v1/auth/login")] == "/api/v1/auth/login" {
 			if !success {
 				err := fmt.Errorf("HTTP %d", statusCode)
-				m.auditService.LogUserActionWithError(c.Request.Context(), userID, "http_request", "api", c.Request.URL.Path, err, details)
+				m.auditService.LogUserActionWithError(c.Request.Context(), userID, models.AuditActionHTTPRequest, "api", c.Request.URL.Path, err, details)
 			} else {
-				m.auditService.LogUserAction(c.Request.Context(), userID, "http_request", "api", c.Request.URL.Path, details)
+				m.auditService.LogUserAction(c.Request.Context(), userID, models.AuditActionHTTPRequest, "api", c.Request.URL.Path, details)
 			}
 			return
 		}
 
 		// Log to audit service
 		if success {
-			m.auditService.LogUserAction(c.Request.Context(), userID, "http_request", "api", c.Request.URL.Path, details)
+			m.auditService.LogUserAction(c.Request.Context(), userID, models.AuditActionHTTPRequest, "api", c.Request.URL.Path, details)
 		} else {
 			err := fmt.Errorf("HTTP %d", statusCode)
-			m.auditService.LogUserActionWithError(c.Request.Context(), userID, "http_request", "api", c.Request.URL.Path, err, details)
+			m.auditService.LogUserActionWithError(c.Request.Context(), userID, models.AuditActionHTTPRequest, "api", c.Request.URL.Path, err, details)
 		}
 
 		// Log detailed audit for sensitive operations
@@ -181,6 +189,7 @@ func (w *auditResponseWriter) Write(data []byte) (int, error) {
 func (m *AuditMiddleware) isSkippedEndpoint(path string) bool {
 	skippedEndpoints := []string{
 		"/health",
+		"/readyz",
 		"/docs",
 		"/favicon.ico",
 		"/metrics",
@@ -29,6 +29,11 @@ type AuthMiddleware struct {
 	logger            *zap.Logger
 	jwtVerifier       JWTVerifier
 	jwtCfg            *config.JWTConfig
+	tenantSchemas     TenantSchemaSelector
+
+	routePolicies       *RoutePolicies
+	strictRoutePolicies bool
+	concealedResources  map[string]bool
 }
 
 // ServiceRepository defines methods for service authentication (imported from interfaces package)
@@ -61,7 +66,7 @@ func NewAuthMiddleware(
 func (m *AuthMiddleware) HTTPAuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Skip authentication for public endpoints
-		if m.isPublicEndpoint(c.Request.URL.Path) {
+		if m.isPublicEndpoint(c.Request.URL.Path) || m.isDeclaredPublicRoute(c) {
 			m.logger.Debug("Skipping authentication for public endpoint", zap.String("path", c.Request.URL.Path))
 			c.Next()
 			return
@@ -111,6 +116,42 @@ func (m *AuthMiddleware) HTTPAuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		// Service-to-service tokens are restricted by audience and scope instead of user roles
+		if isServiceToken(claims) {
+			m.authenticateServiceToken(c, claims)
+			return
+		}
+
+		// Tokens revoked by their user or an admin are rejected until they expire
+		if jti, _ := claims.Raw["jti"].(string); jti != "" && m.authService != nil && m.authService.IsTokenRevoked(jti) {
+			m.logger.Warn("Rejected revoked token",
+				zap.String("user_id", claims.Sub),
+				zap.String("jti", jti))
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error":   "invalid token",
+				"message": "token has been revoked",
+			})
+			return
+		}
+
+		// Impersonation tokens name the real actor in "act" and only work while the session is open
+		impersonatorID, impersonationID := impersonationClaims(claims)
+		if impersonatorID != "" {
+			if m.authService == nil || !m.authService.IsImpersonationActive(impersonationID, impersonatorID) {
+				m.logger.Warn("Rejected token for ended impersonation session",
+					zap.String("user_id", claims.Sub),
+					zap.String("impersonator_id", impersonatorID),
+					zap.String("impersonation_id", impersonationID))
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+					"error":   "invalid token",
+					"message": "impersonation session has ended",
+				})
+				return
+			}
+			c.Set("impersonator_id", impersonatorID)
+			c.Set("impersonation_id", impersonationID)
+		}
+
 		// Set user context used by downstream handlers
 		c.Set("user_id", claims.Sub)
 
@@ -183,12 +224,28 @@ func (m *AuthMiddleware) HTTPAuthMiddleware() gin.HandlerFunc {
 		ctx := context.WithValue(c.Request.Context(), "user_id", claims.Sub)
 		ctx = context.WithValue(ctx, "ip_address", c.ClientIP())
 		ctx = context.WithValue(ctx, "user_agent", c.GetHeader("User-Agent"))
+		if impersonatorID != "" {
+			ctx = context.WithValue(ctx, "impersonator_id", impersonatorID)
+			ctx = context.WithValue(ctx, "impersonation_id", impersonationID)
+		}
+		ctx = m.withTenantSchema(ctx, c, organizationIDs)
 		c.Request = c.Request.WithContext(ctx)
 
 		c.Next()
 	}
 }
 
+// impersonationClaims returns the real actor and session of an impersonation token, or empty strings
+func impersonationClaims(claims *JWTClaims) (string, string) {
+	act, ok := claims.Raw["act"].(map[string]interface{})
+	if !ok {
+		return "", ""
+	}
+	actorID, _ := act["sub"].(string)
+	impersonationID, _ := claims.Raw["impersonation_id"].(string)
+	return actorID, impersonationID
+}
+
 // JWTClaims captures the verified JWT data
 type JWTClaims struct {
 	Sub string
@@ -214,6 +271,22 @@ func (m *AuthMiddleware) HTTPAuthzMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		// Routes with a declared policy are authorized by it instead of the path convention
+		if policy, ok := m.routePolicy(c); ok {
+			m.authorizeRoutePolicy(c, policy)
+			return
+		}
+		if m.strictRoutePolicies && m.routePolicies != nil && c.FullPath() != "" {
+			m.logger.Error("Rejected request to route without an authorization policy",
+				zap.String("method", c.Request.Method),
+				zap.String("route", c.FullPath()))
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error":   "forbidden",
+				"message": "route has no authorization policy",
+			})
+			return
+		}
+
 		// Allow authenticated users to call logout without additional authorization
 		if c.Request.URL.Path == "/api/v1/auth/logout" {
 			c.Next()
@@ -223,7 +296,13 @@ func (m *AuthMiddleware) HTTPAuthzMiddleware() gin.HandlerFunc {
 		// Allow authenticated users to access /me/* endpoints (self-access)
 		// These endpoints use user_id from JWT context, not URL parameters
 		// Authentication is still enforced by HTTPAuthMiddleware
-		if strings.HasPrefix(c.Request.URL.Path, "/api/v1/me/") {
+		if strings.HasPrefix(c.Request.URL.Path, "/api/v1/me/") || strings.HasPrefix(c.Request.URL.Path, "/api/v1/users/me/") {
+			c.Next()
+			return
+		}
+
+		// Service tokens are authorized by their scopes, which HTTPAuthMiddleware has already enforced
+		if isScopedServicePrincipal(c) {
 			c.Next()
 			return
 		}
@@ -415,12 +494,20 @@ func (m *AuthMiddleware) authenticateService(ctx context.Context, apiKey, method
 		return nil, status.Errorf(codes.Unauthenticated, "invalid API key")
 	}
 
-	if service == nil {
+	if service == nil || !service.AcceptsAPIKey(hashedAPIKey, time.Now()) {
 		m.logger.Warn("Invalid API key in gRPC request",
 			zap.String("method", method))
 		return nil, status.Errorf(codes.Unauthenticated, "invalid API key")
 	}
 
+	// The key replaced by a rotation is accepted until its grace period ends
+	if service.APIKey != hashedAPIKey {
+		m.logger.Info("Service authenticated with its previous API key",
+			zap.String("service_id", service.ID),
+			zap.Timep("previous_key_expires_at", service.PreviousAPIKeyExpiresAt),
+			zap.String("method", method))
+	}
+
 	// Check if service is active
 	if !service.IsActive {
 		m.logger.Warn("Inactive service attempted authentication",
@@ -596,7 +683,7 @@ func (m *AuthMiddleware) RequirePermission(resource, action string) gin.HandlerF
 func (m *AuthMiddleware) isPublicEndpoint(path string) bool {
 	// Exact-match endpoints
 	switch path {
-	case "/":
+	case "/", "/healthz", "/readyz":
 		return true
 	case "/api/v1/auth/login", "/api/v1/auth/register", "/api/v1/auth/refresh", "/api/v1/health":
 		return true
@@ -609,6 +696,11 @@ func (m *AuthMiddleware) isPublicEndpoint(path string) bool {
 		return true
 	}
 
+	// External identity provider login and callback
+	if strings.HasPrefix(path, "/api/v1/auth/oidc/") {
+		return true
+	}
+
 	return false
 }
 
@@ -0,0 +1,312 @@
+package middleware
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultCompressionMinSize is the smallest response body, in bytes, worth compressing
+const DefaultCompressionMinSize = 1024
+
+// CompressionConfig controls which responses ResponseCompression encodes
+type CompressionConfig struct {
+	// MinSize is the body size below which responses are sent uncompressed
+	MinSize int
+	// ContentTypes lists the media types eligible for compression
+	ContentTypes []string
+}
+
+// DefaultCompressionConfig compresses JSON and CSV bodies of at least DefaultCompressionMinSize bytes
+func DefaultCompressionConfig() CompressionConfig {
+	return CompressionConfig{
+		MinSize: DefaultCompressionMinSize,
+		ContentTypes: []string{
+			"application/json",
+			"application/problem+json",
+			"text/csv",
+		},
+	}
+}
+
+// compressionEncoder is a content coding the server can produce.
+// Encoders are listed in server preference order: brotli ("br") compresses JSON tighter than gzip,
+// so it wins when a client accepts both at the same q-value.
+type compressionEncoder struct {
+	name      string
+	newWriter func(w io.Writer) io.WriteCloser
+	release   func(wc io.WriteCloser)
+}
+
+var brotliWriterPool = sync.Pool{
+	New: func() interface{} {
+		return brotli.NewWriterLevel(io.Discard, brotli.DefaultCompression)
+	},
+}
+
+var gzipWriterPool = sync.Pool{
+	New: func() interface{} {
+		return gzip.NewWriter(io.Discard)
+	},
+}
+
+var compressionEncoders = []compressionEncoder{
+	{
+		name: "br",
+		newWriter: func(w io.Writer) io.WriteCloser {
+			br := brotliWriterPool.Get().(*brotli.Writer)
+			br.Reset(w)
+			return br
+		},
+		release: func(wc io.WriteCloser) {
+			brotliWriterPool.Put(wc)
+		},
+	},
+	{
+		name: "gzip",
+		newWriter: func(w io.Writer) io.WriteCloser {
+			gz := gzipWriterPool.Get().(*gzip.Writer)
+			gz.Reset(w)
+			return gz
+		},
+		release: func(wc io.WriteCloser) {
+			gzipWriterPool.Put(wc)
+		},
+	},
+}
+
+// ResponseCompression compresses responses with the default configuration
+func ResponseCompression() gin.HandlerFunc {
+	return ResponseCompressionWithConfig(DefaultCompressionConfig())
+}
+
+// ResponseCompressionWithConfig negotiates Accept-Encoding and compresses eligible responses.
+// Bodies are buffered until MinSize is reached so small responses stay uncompressed, responses that
+// already carry a Content-Encoding are never re-encoded, and event streams pass through untouched.
+func ResponseCompressionWithConfig(cfg CompressionConfig) gin.HandlerFunc {
+	if cfg.MinSize <= 0 {
+		cfg.MinSize = DefaultCompressionMinSize
+	}
+	allowed := make(map[string]bool, len(cfg.ContentTypes))
+	for _, contentType := range cfg.ContentTypes {
+		allowed[strings.ToLower(contentType)] = true
+	}
+
+	return func(c *gin.Context) {
+		if c.Request.Method == http.MethodHead || isEventStreamRequest(c.Request) {
+			c.Next()
+			return
+		}
+
+		encoder := negotiateEncoding(c.GetHeader("Accept-Encoding"))
+		if encoder == nil {
+			c.Next()
+			return
+		}
+
+		c.Writer.Header().Add("Vary", "Accept-Encoding")
+
+		cw := &compressResponseWriter{
+			ResponseWriter: c.Writer,
+			encoder:        encoder,
+			minSize:        cfg.MinSize,
+			allowed:        allowed,
+		}
+		c.Writer = cw
+		defer func() {
+			cw.finish()
+			c.Writer = cw.ResponseWriter
+		}()
+
+		c.Next()
+	}
+}
+
+// compressResponseWriter buffers the body until it can decide whether to compress
+type compressResponseWriter struct {
+	gin.ResponseWriter
+	encoder    *compressionEncoder
+	minSize    int
+	allowed    map[string]bool
+	buf        bytes.Buffer
+	decided    bool
+	compressor io.WriteCloser
+}
+
+func (w *compressResponseWriter) Write(data []byte) (int, error) {
+	if w.decided {
+		if w.compressor != nil {
+			return w.compressor.Write(data)
+		}
+		return w.ResponseWriter.Write(data)
+	}
+
+	// Headers were committed elsewhere (e.g. AbortWithStatus); compression can no longer be announced
+	if w.ResponseWriter.Written() || !w.eligible() {
+		if err := w.decide(false); err != nil {
+			return 0, err
+		}
+		return w.ResponseWriter.Write(data)
+	}
+
+	w.buf.Write(data)
+	if w.buf.Len() >= w.minSize {
+		if err := w.decide(true); err != nil {
+			return 0, err
+		}
+	}
+	return len(data), nil
+}
+
+func (w *compressResponseWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Written reports true once the handler has produced body bytes, even if they are still buffered
+func (w *compressResponseWriter) Written() bool {
+	return w.buf.Len() > 0 || w.ResponseWriter.Written()
+}
+
+// Flush signals a streaming response; anything buffered is sent as-is and compression is abandoned
+func (w *compressResponseWriter) Flush() {
+	if !w.decided {
+		_ = w.decide(false)
+	}
+	if flusher, ok := w.compressor.(interface{ Flush() error }); ok {
+		_ = flusher.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+func (w *compressResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if !w.decided {
+		_ = w.decide(false)
+	}
+	return w.ResponseWriter.Hijack()
+}
+
+// eligible reports whether the response headers permit compression
+func (w *compressResponseWriter) eligible() bool {
+	header := w.ResponseWriter.Header()
+	if header.Get("Content-Encoding") != "" {
+		return false
+	}
+
+	switch w.ResponseWriter.Status() {
+	case http.StatusNoContent, http.StatusNotModified, http.StatusPartialContent:
+		return false
+	}
+
+	mediaType, _, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		return false
+	}
+	return w.allowed[strings.ToLower(mediaType)]
+}
+
+// decide commits the encoding choice and writes any buffered body through it
+func (w *compressResponseWriter) decide(compress bool) error {
+	w.decided = true
+
+	if compress {
+		header := w.ResponseWriter.Header()
+		header.Set("Content-Encoding", w.encoder.name)
+		header.Del("Content-Length")
+		w.compressor = w.encoder.newWriter(w.ResponseWriter)
+	}
+
+	if w.buf.Len() == 0 {
+		return nil
+	}
+
+	var err error
+	if w.compressor != nil {
+		_, err = w.compressor.Write(w.buf.Bytes())
+	} else {
+		_, err = w.ResponseWriter.Write(w.buf.Bytes())
+	}
+	w.buf.Reset()
+	return err
+}
+
+// finish sends a small buffered body uncompressed, or closes the compressor
+func (w *compressResponseWriter) finish() {
+	if !w.decided {
+		_ = w.decide(false)
+	}
+	if w.compressor != nil {
+		_ = w.compressor.Close()
+		w.encoder.release(w.compressor)
+		w.compressor = nil
+	}
+}
+
+func isEventStreamRequest(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), "text/event-stream")
+}
+
+// negotiateEncoding picks the supported encoding with the highest q-value in Accept-Encoding.
+// Ties are broken by server preference; "*" matches any supported encoding and q=0 refuses one.
+func negotiateEncoding(acceptEncoding string) *compressionEncoder {
+	if acceptEncoding == "" {
+		return nil
+	}
+
+	weights := make(map[string]float64)
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		coding := strings.ToLower(strings.TrimSpace(fields[0]))
+		if coding == "" {
+			continue
+		}
+		q := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if parsed, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+		weights[coding] = q
+	}
+
+	type candidate struct {
+		encoder *compressionEncoder
+		q       float64
+		rank    int
+	}
+	var candidates []candidate
+	for i := range compressionEncoders {
+		encoder := &compressionEncoders[i]
+		q, ok := weights[encoder.name]
+		if !ok {
+			q, ok = weights["*"]
+		}
+		if ok && q > 0 {
+			candidates = append(candidates, candidate{encoder: encoder, q: q, rank: i})
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].q != candidates[j].q {
+			return candidates[i].q > candidates[j].q
+		}
+		return candidates[i].rank < candidates[j].rank
+	})
+	return candidates[0].encoder
+}
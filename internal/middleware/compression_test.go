@@ -0,0 +1,144 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newCompressionTestRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(ResponseCompression())
+
+	router.GET("/large", func(c *gin.Context) {
+		items := make([]map[string]string, 200)
+		for i := range items {
+			items[i] = map[string]string{"id": "ROLE_" + strings.Repeat("x", 10), "name": "field officer"}
+		}
+		c.JSON(http.StatusOK, gin.H{"items": items})
+	})
+	router.GET("/small", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	})
+	router.GET("/archive", func(c *gin.Context) {
+		c.Header("Content-Encoding", "gzip")
+		c.Data(http.StatusOK, "application/json", []byte(strings.Repeat("a", 4096)))
+	})
+	router.GET("/binary", func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/zip", []byte(strings.Repeat("z", 4096)))
+	})
+	router.GET("/events", func(c *gin.Context) {
+		c.Header("Content-Type", "text/event-stream")
+		c.String(http.StatusOK, "data: %s\n\n", strings.Repeat("e", 4096))
+		c.Writer.Flush()
+	})
+
+	return router
+}
+
+func TestResponseCompression_GzipLargeJSON(t *testing.T) {
+	router := newCompressionTestRouter()
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/large", nil)
+	req.Header.Set("Accept-Encoding", "br;q=0.5, gzip")
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	assert.Contains(t, w.Header().Values("Vary"), "Accept-Encoding")
+	assert.Empty(t, w.Header().Get("Content-Length"))
+
+	reader, err := gzip.NewReader(w.Body)
+	require.NoError(t, err)
+	body, err := io.ReadAll(reader)
+	require.NoError(t, err)
+
+	var decoded struct {
+		Items []map[string]string `json:"items"`
+	}
+	require.NoError(t, json.Unmarshal(body, &decoded))
+	assert.Len(t, decoded.Items, 200)
+	assert.Equal(t, "field officer", decoded.Items[199]["name"])
+}
+
+func TestResponseCompression_BrotliLargeJSON(t *testing.T) {
+	router := newCompressionTestRouter()
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/large", nil)
+	req.Header.Set("Accept-Encoding", "gzip, deflate, br")
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "br", w.Header().Get("Content-Encoding"))
+	assert.Contains(t, w.Header().Values("Vary"), "Accept-Encoding")
+
+	body, err := io.ReadAll(brotli.NewReader(w.Body))
+	require.NoError(t, err)
+
+	var decoded struct {
+		Items []map[string]string `json:"items"`
+	}
+	require.NoError(t, json.Unmarshal(body, &decoded))
+	assert.Len(t, decoded.Items, 200)
+	assert.Equal(t, "field officer", decoded.Items[199]["name"])
+}
+
+func TestResponseCompression_Skipped(t *testing.T) {
+	router := newCompressionTestRouter()
+
+	tests := []struct {
+		name           string
+		path           string
+		acceptEncoding string
+		accept         string
+		wantEncoding   string
+	}{
+		{name: "client does not accept gzip", path: "/large", acceptEncoding: "identity"},
+		{name: "gzip refused with q=0", path: "/large", acceptEncoding: "gzip;q=0, deflate"},
+		{name: "below size threshold", path: "/small", acceptEncoding: "gzip"},
+		{name: "content type not allowed", path: "/binary", acceptEncoding: "gzip"},
+		{name: "already encoded", path: "/archive", acceptEncoding: "gzip", wantEncoding: "gzip"},
+		{name: "event stream", path: "/events", acceptEncoding: "gzip", accept: "text/event-stream"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tt.wantEncoding, w.Header().Get("Content-Encoding"))
+			if tt.path == "/archive" {
+				assert.Equal(t, strings.Repeat("a", 4096), w.Body.String(), "pre-encoded body must not be compressed again")
+			}
+		})
+	}
+}
+
+func TestNegotiateEncoding(t *testing.T) {
+	assert.Nil(t, negotiateEncoding(""))
+	assert.Nil(t, negotiateEncoding("deflate"))
+	assert.Equal(t, "br", negotiateEncoding("br").name)
+	assert.Equal(t, "br", negotiateEncoding("gzip, deflate, br").name)
+	assert.Equal(t, "gzip", negotiateEncoding("gzip, br;q=0.8").name)
+	assert.Equal(t, "gzip", negotiateEncoding("br;q=0, *").name)
+	assert.Equal(t, "br", negotiateEncoding("*").name)
+	assert.Nil(t, negotiateEncoding("*;q=0"))
+}
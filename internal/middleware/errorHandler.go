@@ -245,7 +245,7 @@ func ErrorHandler(c *gin.Context) {
 		switch e := err.(type) {
 		case *helper.CustomError:
 			// Handle legacy CustomError with standardized response
-			errorResponse := responses.NewErrorResponse("REQUEST_ERROR", e.Message, "REQUEST_ERROR").
+			errorResponse := responses.NewErrorResponse("REQUEST_ERROR", e.Message, string(errors.CodeForStatus(e.Code))).
 				WithRequestID(requestID)
 			c.JSON(e.Code, errorResponse.ToJSON())
 		default:
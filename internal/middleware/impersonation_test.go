@@ -0,0 +1,102 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/config"
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type impersonationTestUserRepo struct {
+	interfaces.UserRepository
+}
+
+func (r *impersonationTestUserRepo) GetByID(ctx context.Context, id string, model *models.User) (*models.User, error) {
+	user := models.NewUser("9876543210", "+91", "hash")
+	user.ID = id
+	return user, nil
+}
+
+type impersonationTestUserRoleRepo struct {
+	interfaces.UserRoleRepository
+}
+
+func (r *impersonationTestUserRoleRepo) GetByUserID(ctx context.Context, userID string) ([]*models.UserRole, error) {
+	return []*models.UserRole{{UserID: userID, Role: models.Role{Name: "farmer"}}}, nil
+}
+
+type impersonationTestCache struct {
+	interfaces.CacheService
+	values map[string]interface{}
+}
+
+func (c *impersonationTestCache) Get(key string) (interface{}, bool) {
+	value, ok := c.values[key]
+	return value, ok
+}
+
+func (c *impersonationTestCache) Set(key string, value interface{}, ttl int) error {
+	c.values[key] = value
+	return nil
+}
+
+func (c *impersonationTestCache) Delete(key string) error {
+	delete(c.values, key)
+	return nil
+}
+
+func (c *impersonationTestCache) Exists(key string) bool {
+	_, ok := c.values[key]
+	return ok
+}
+
+func TestHTTPAuthMiddleware_ImpersonationToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	jwtCfg := &config.JWTConfig{Secret: "test-secret", Issuer: "aaa-service", TTL: time.Hour, Leeway: time.Minute}
+	authService, err := services.NewAuthService(&impersonationTestUserRepo{}, nil, &impersonationTestUserRoleRepo{},
+		&impersonationTestCache{values: map[string]interface{}{}}, nil, nil,
+		&services.AuthServiceConfig{JWTSecret: jwtCfg.Secret}, zap.NewNop(), nil, jwtCfg)
+	require.NoError(t, err)
+
+	router := gin.New()
+	router.Use(NewAuthMiddleware(authService, nil, nil, nil, zap.NewNop(), NewHS256Verifier(), jwtCfg).HTTPAuthMiddleware())
+	router.GET("/api/v1/users/:id", func(c *gin.Context) {
+		ctx := c.Request.Context()
+		c.JSON(http.StatusOK, gin.H{
+			"user_id":          c.GetString("user_id"),
+			"impersonator_id":  c.GetString("impersonator_id"),
+			"ctx_impersonator": ctx.Value("impersonator_id"),
+			"impersonation_id": ctx.Value("impersonation_id"),
+			"roles":            c.GetStringSlice("roles"),
+		})
+	})
+
+	session, err := authService.StartImpersonation(context.Background(), "ADMIN1", "USER1")
+	require.NoError(t, err)
+
+	rec := serveWithToken(router, http.MethodGet, "/api/v1/users/USER1", session.AccessToken)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, "USER1", body["user_id"])
+	assert.Equal(t, "ADMIN1", body["impersonator_id"])
+	assert.Equal(t, "ADMIN1", body["ctx_impersonator"])
+	assert.Equal(t, session.ImpersonationID, body["impersonation_id"])
+	assert.Equal(t, []interface{}{"farmer"}, body["roles"], "the token carries the target's roles")
+
+	require.NoError(t, authService.StopImpersonation(context.Background(), "ADMIN1", session.ImpersonationID))
+
+	rec = serveWithToken(router, http.MethodGet, "/api/v1/users/USER1", session.AccessToken)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code, "token is rejected once the session ends")
+}
@@ -0,0 +1,156 @@
+package middleware
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/config"
+	"github.com/Kisanlink/aaa-service/v2/internal/security"
+	jwt "github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func signTestToken(t *testing.T, secret string, claims jwt.MapClaims) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	require.NoError(t, err)
+	return token
+}
+
+func TestHS256Verifier_ClockSkewLeeway(t *testing.T) {
+	cfg := &config.JWTConfig{Secret: "test-secret", Issuer: "aaa-service", Leeway: config.DefaultJWTLeeway}
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		claims  jwt.MapClaims
+		wantErr string
+	}{
+		{
+			name:   "expired a few seconds ago",
+			claims: jwt.MapClaims{"exp": now.Add(-5 * time.Second).Unix(), "iat": now.Add(-time.Hour).Unix()},
+		},
+		{
+			name:    "expired minutes ago",
+			claims:  jwt.MapClaims{"exp": now.Add(-5 * time.Minute).Unix(), "iat": now.Add(-time.Hour).Unix()},
+			wantErr: "token expired",
+		},
+		{
+			name:   "not before a few seconds from now",
+			claims: jwt.MapClaims{"exp": now.Add(time.Hour).Unix(), "nbf": now.Add(10 * time.Second).Unix()},
+		},
+		{
+			name:    "not before minutes from now",
+			claims:  jwt.MapClaims{"exp": now.Add(time.Hour).Unix(), "nbf": now.Add(5 * time.Minute).Unix()},
+			wantErr: "token not yet valid",
+		},
+		{
+			name:   "issued a few seconds in the future",
+			claims: jwt.MapClaims{"exp": now.Add(time.Hour).Unix(), "iat": now.Add(10 * time.Second).Unix()},
+		},
+		{
+			name:    "issued minutes in the future",
+			claims:  jwt.MapClaims{"exp": now.Add(time.Hour).Unix(), "iat": now.Add(5 * time.Minute).Unix()},
+			wantErr: "token issued in the future",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.claims["sub"] = "USER1"
+			tt.claims["iss"] = "aaa-service"
+
+			claims, err := NewHS256Verifier().Verify(signTestToken(t, cfg.Secret, tt.claims), cfg)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "USER1", claims.Sub)
+		})
+	}
+}
+
+func TestHS256Verifier_ZeroLeewayIsStrict(t *testing.T) {
+	cfg := &config.JWTConfig{Secret: "test-secret"}
+	token := signTestToken(t, cfg.Secret, jwt.MapClaims{"sub": "USER1", "exp": time.Now().Add(-5 * time.Second).Unix()})
+
+	_, err := NewHS256Verifier().Verify(token, cfg)
+	assert.Error(t, err)
+}
+
+func TestHS256Verifier_RejectsBadSignature(t *testing.T) {
+	cfg := &config.JWTConfig{Secret: "test-secret", Leeway: config.DefaultJWTLeeway}
+	token := signTestToken(t, "other-secret", jwt.MapClaims{"sub": "USER1", "exp": time.Now().Add(time.Hour).Unix()})
+
+	_, err := NewHS256Verifier().Verify(token, cfg)
+	assert.Error(t, err)
+}
+
+func TestSigningKeyVerifier_PreviousKeyVerifiesAfterRotation(t *testing.T) {
+	cfg := &config.JWTConfig{Secret: "test-secret", Issuer: "aaa-service", Leeway: config.DefaultJWTLeeway}
+	keys, err := security.LoadSigningKeyRing(t.TempDir(), time.Hour)
+	require.NoError(t, err)
+	verifier := NewSigningKeyVerifier(keys)
+
+	signWithCurrentKey := func() string {
+		key := keys.Current()
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+			"sub": "USER1",
+			"iss": "aaa-service",
+			"exp": time.Now().Add(time.Hour).Unix(),
+		})
+		token.Header["kid"] = key.ID
+		signed, err := token.SignedString(key.PrivateKey)
+		require.NoError(t, err)
+		return signed
+	}
+
+	oldToken := signWithCurrentKey()
+	_, err = keys.Rotate()
+	require.NoError(t, err)
+	newToken := signWithCurrentKey()
+
+	for _, token := range []string{oldToken, newToken} {
+		claims, err := verifier.Verify(token, cfg)
+		require.NoError(t, err)
+		assert.Equal(t, "USER1", claims.Sub)
+	}
+
+	// Tokens signed with the secret are refused unless the migration window is open
+	legacyToken := signTestToken(t, cfg.Secret, jwt.MapClaims{"sub": "USER2", "exp": time.Now().Add(time.Hour).Unix()})
+	_, err = verifier.Verify(legacyToken, cfg)
+	assert.Error(t, err)
+
+	keys.AcceptHS256Until(time.Now().Add(time.Hour))
+	claims, err := verifier.Verify(legacyToken, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, "USER2", claims.Sub)
+
+	keys.AcceptHS256Until(time.Now().Add(-time.Minute))
+	_, err = verifier.Verify(legacyToken, cfg)
+	assert.Error(t, err, "the window has closed")
+}
+
+func TestSigningKeyVerifier_RejectsUnknownKey(t *testing.T) {
+	cfg := &config.JWTConfig{Issuer: "aaa-service", Leeway: config.DefaultJWTLeeway}
+	keys, err := security.LoadSigningKeyRing(t.TempDir(), time.Hour)
+	require.NoError(t, err)
+	// A key ring the verifier does not know, e.g. of another deployment
+	foreign, err := security.LoadSigningKeyRing(t.TempDir(), time.Hour)
+	require.NoError(t, err)
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{"sub": "USER1", "exp": time.Now().Add(time.Hour).Unix()})
+	token.Header["kid"] = foreign.Current().ID
+	signed, err := token.SignedString(foreign.Current().PrivateKey)
+	require.NoError(t, err)
+
+	_, err = NewSigningKeyVerifier(keys).Verify(signed, cfg)
+	assert.ErrorContains(t, err, "unknown signing key")
+
+	// Without a secret, HS256 tokens are rejected
+	_, err = NewSigningKeyVerifier(keys).Verify(signTestToken(t, "guessed", jwt.MapClaims{"sub": "USER1"}), cfg)
+	assert.Error(t, err)
+}
@@ -1,7 +1,6 @@
 package middleware
 
 import (
-	"context"
 	"fmt"
 	"net/http"
 	"strings"
@@ -10,6 +9,7 @@ import (
 	"github.com/Kisanlink/aaa-service/v2/helper"
 	"github.com/Kisanlink/aaa-service/v2/internal/config"
 	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/internal/security"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -30,12 +30,12 @@ func RequestID() gin.HandlerFunc {
 	}
 }
 
-// Logger logs request details
+// Logger logs request details. Sensitive query parameters such as tokens and OTPs are redacted.
 func Logger(logger interfaces.Logger) gin.HandlerFunc {
 	return gin.LoggerWithFormatter(func(param gin.LogFormatterParams) string {
 		logger.Info("HTTP Request",
 			zap.String("method", param.Method),
-			zap.String("path", param.Path),
+			zap.String("path", security.DefaultRedactor().RedactPath(param.Path)),
 			zap.Int("status", param.StatusCode),
 			zap.Duration("latency", param.Latency),
 			zap.String("client_ip", param.ClientIP),
@@ -369,31 +369,9 @@ func min(a, b int) int {
 }
 
 // Timeout adds a timeout to requests
+// Deprecated: Use RequestTimeout, which also supports per-route overrides and slow-request logging
 func Timeout(timeout time.Duration) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
-		defer cancel()
-
-		c.Request = c.Request.WithContext(ctx)
-
-		done := make(chan bool, 1)
-		go func() {
-			c.Next()
-			done <- true
-		}()
-
-		select {
-		case <-done:
-			return
-		case <-ctx.Done():
-			c.JSON(http.StatusRequestTimeout, gin.H{
-				"error":   "Request timeout",
-				"message": fmt.Sprintf("Request timed out after %v", timeout),
-			})
-			c.Abort()
-			return
-		}
-	}
+	return RequestTimeout(RequestTimeoutConfig{Timeout: timeout})
 }
 
 // Auth handles authentication middleware
@@ -536,40 +514,69 @@ func PanicRecoveryHandler(logger interfaces.Logger) gin.HandlerFunc {
 	}
 }
 
-// SecurityHeaders adds comprehensive security headers to responses
+// SecurityHeaders adds comprehensive security headers to responses, configured from the environment
 func SecurityHeaders() gin.HandlerFunc {
+	return SecurityHeadersWithConfig(config.LoadSecurityConfig().SecurityHeaders)
+}
+
+// SecurityHeadersWithConfig adds the security headers enabled in cfg to responses. The API
+// reference UI under /docs gets its own Content-Security-Policy so its CDN assets still load.
+func SecurityHeadersWithConfig(cfg config.SecurityHeadersConfig) gin.HandlerFunc {
+	hsts := fmt.Sprintf("max-age=%d", cfg.HSTSMaxAge)
+	if cfg.HSTSIncludeSubdomains {
+		hsts += "; includeSubDomains"
+	}
+	if cfg.HSTSPreload {
+		hsts += "; preload"
+	}
+
 	return func(c *gin.Context) {
+		if !cfg.Enabled {
+			c.Next()
+			return
+		}
+
 		// Prevent MIME type sniffing
-		c.Header("X-Content-Type-Options", "nosniff")
+		if cfg.EnableContentTypeNoSniff {
+			c.Header("X-Content-Type-Options", "nosniff")
+		}
 
 		// Prevent clickjacking
-		c.Header("X-Frame-Options", "DENY")
+		if cfg.EnableFrameOptions && cfg.FrameOptions != "" {
+			c.Header("X-Frame-Options", cfg.FrameOptions)
+		}
 
 		// Enable XSS protection
-		c.Header("X-XSS-Protection", "1; mode=block")
+		if cfg.EnableXSSProtection {
+			c.Header("X-XSS-Protection", "1; mode=block")
+		}
 
-		// Force HTTPS (only in production)
-		if gin.Mode() == gin.ReleaseMode {
-			c.Header("Strict-Transport-Security", "max-age=31536000; includeSubDomains; preload")
+		// Force HTTPS on connections that are already secure, or always in production
+		secure := c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https"
+		if cfg.EnableHSTS && (gin.Mode() == gin.ReleaseMode || secure) {
+			c.Header("Strict-Transport-Security", hsts)
 		}
 
 		// Content Security Policy
-		csp := "default-src 'self'; " +
-			"script-src 'self' 'unsafe-inline' 'unsafe-eval' https://cdn.jsdelivr.net https://unpkg.com; " +
-			"style-src 'self' 'unsafe-inline' https://cdn.jsdelivr.net https://unpkg.com; " +
-			"img-src 'self' data: https:; " +
-			"font-src 'self' https://cdn.jsdelivr.net https://unpkg.com; " +
-			"connect-src 'self'; " +
-			"frame-ancestors 'none'; " +
-			"base-uri 'self'; " +
-			"form-action 'self'"
-		c.Header("Content-Security-Policy", csp)
+		if cfg.EnableCSP {
+			csp := cfg.ContentSecurityPolicy
+			if isDocsPath(c.Request.URL.Path) && cfg.DocsContentSecurityPolicy != "" {
+				csp = cfg.DocsContentSecurityPolicy
+			}
+			if csp != "" {
+				c.Header("Content-Security-Policy", csp)
+			}
+		}
 
 		// Referrer Policy
-		c.Header("Referrer-Policy", "strict-origin-when-cross-origin")
+		if cfg.EnableReferrerPolicy && cfg.ReferrerPolicy != "" {
+			c.Header("Referrer-Policy", cfg.ReferrerPolicy)
+		}
 
 		// Permissions Policy (formerly Feature Policy)
-		c.Header("Permissions-Policy", "camera=(), microphone=(), geolocation=()")
+		if cfg.PermissionsPolicy != "" {
+			c.Header("Permissions-Policy", cfg.PermissionsPolicy)
+		}
 
 		// Remove server information
 		c.Header("Server", "")
@@ -585,6 +592,11 @@ func SecurityHeaders() gin.HandlerFunc {
 	}
 }
 
+// isDocsPath checks if a path belongs to the API reference UI
+func isDocsPath(path string) bool {
+	return path == "/docs" || strings.HasPrefix(path, "/docs/")
+}
+
 // isSensitiveEndpoint checks if an endpoint handles sensitive data
 func isSensitiveEndpoint(path string) bool {
 	sensitiveEndpoints := []string{
@@ -615,14 +627,6 @@ func RequestSizeLimit(maxSize int64) gin.HandlerFunc {
 	}
 }
 
-// ResponseCompression adds gzip compression to responses
-func ResponseCompression() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		// This is a placeholder - implement actual compression
-		c.Next()
-	}
-}
-
 // Metrics adds metrics collection
 func Metrics(logger interfaces.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
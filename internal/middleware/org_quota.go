@@ -0,0 +1,139 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/responses"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// RateLimitViolationRecorder records requests rejected by a rate limit or quota
+type RateLimitViolationRecorder interface {
+	LogRateLimitViolation(ctx context.Context, userID, endpoint, ipAddress, userAgent string, details map[string]interface{})
+}
+
+// OrganizationQuota counts authenticated requests against the rate limit and monthly quota of
+// the organization they act in (see actingOrganizationID) and answers 429 once either is used up.
+// It must run after the auth middleware. Callers belonging to several organizations must name one
+// in the X-Organization-ID header, and may only name their own, so that no request escapes the
+// quota. Callers outside any organization are not counted, and requests are let through when the
+// quota counters cannot be reached.
+func OrganizationQuota(quotas interfaces.OrganizationQuotaService, violations RateLimitViolationRecorder, logger *zap.Logger) gin.HandlerFunc {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+
+	return func(c *gin.Context) {
+		if quotas == nil || !quotas.Enabled() {
+			c.Next()
+			return
+		}
+
+		organizationIDs := c.GetStringSlice("organization_ids")
+		orgID := actingOrganizationID(c, organizationIDs)
+		if orgID == "" {
+			if rejectUnchargedRequest(c, organizationIDs) {
+				return
+			}
+			c.Next()
+			return
+		}
+
+		decision, err := quotas.ConsumeRequest(c.Request.Context(), orgID)
+		if err != nil {
+			logger.Warn("Failed to check organization quota, allowing request",
+				zap.String("org_id", orgID),
+				zap.Error(err))
+			c.Next()
+			return
+		}
+
+		setQuotaHeaders(c, decision)
+		if decision.Allowed {
+			c.Next()
+			return
+		}
+
+		rejectOverQuota(c, orgID, decision, violations, logger)
+	}
+}
+
+// rejectUnchargedRequest refuses a request of an organization member that names no organization to
+// charge, or one the caller does not belong to, and reports whether it did
+func rejectUnchargedRequest(c *gin.Context, organizationIDs []string) bool {
+	if strings.TrimSpace(c.GetHeader(OrganizationHeader)) != "" {
+		c.AbortWithStatusJSON(http.StatusForbidden, responses.NewForbiddenResponse(
+			"You are not a member of the organization named in the "+OrganizationHeader+" header", c.GetString("request_id")))
+		return true
+	}
+	if len(organizationIDs) > 1 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, responses.NewBadRequestResponse(
+			"The "+OrganizationHeader+" header is required for members of several organizations", c.GetString("request_id")))
+		return true
+	}
+	return false
+}
+
+// setQuotaHeaders reports the limited windows: X-RateLimit-* for the per-minute rate limit and
+// X-Quota-* for the monthly quota. Reset times are Unix seconds.
+func setQuotaHeaders(c *gin.Context, decision *interfaces.OrganizationQuotaDecision) {
+	if window := decision.RateLimit; window.Limit > 0 {
+		c.Header("X-RateLimit-Limit", strconv.FormatInt(window.Limit, 10))
+		c.Header("X-RateLimit-Remaining", strconv.FormatInt(window.Remaining, 10))
+		c.Header("X-RateLimit-Reset", strconv.FormatInt(window.ResetAt.Unix(), 10))
+	}
+	if window := decision.MonthlyQuota; window.Limit > 0 {
+		c.Header("X-Quota-Limit", strconv.FormatInt(window.Limit, 10))
+		c.Header("X-Quota-Remaining", strconv.FormatInt(window.Remaining, 10))
+		c.Header("X-Quota-Reset", strconv.FormatInt(window.ResetAt.Unix(), 10))
+	}
+}
+
+func rejectOverQuota(c *gin.Context, orgID string, decision *interfaces.OrganizationQuotaDecision, violations RateLimitViolationRecorder, logger *zap.Logger) {
+	window := decision.RateLimit
+	message := "Organization rate limit exceeded"
+	if decision.Exceeded == interfaces.OrganizationQuotaMonthly {
+		window = decision.MonthlyQuota
+		message = "Organization monthly API quota exhausted"
+	}
+
+	retryAfter := int64(time.Until(window.ResetAt).Seconds()) + 1
+	if retryAfter < 1 {
+		retryAfter = 1
+	}
+	c.Header("Retry-After", strconv.FormatInt(retryAfter, 10))
+
+	route := c.FullPath()
+	if route == "" {
+		route = c.Request.URL.Path
+	}
+	details := map[string]interface{}{
+		"organization_id": orgID,
+		"quota":           decision.Exceeded,
+		"limit":           window.Limit,
+		"reset_at":        window.ResetAt.UTC().Format(time.RFC3339),
+	}
+
+	logger.Warn("Organization quota exceeded",
+		zap.String("org_id", orgID),
+		zap.String("quota", decision.Exceeded),
+		zap.String("method", c.Request.Method),
+		zap.String("route", route))
+	if violations != nil {
+		violations.LogRateLimitViolation(c.Request.Context(), c.GetString("user_id"), fmt.Sprintf("%s %s", c.Request.Method, route),
+			c.ClientIP(), c.Request.UserAgent(), details)
+	}
+
+	response := responses.NewRateLimitResponse(message, strconv.FormatInt(retryAfter, 10), c.GetString("request_id"))
+	for key, value := range details {
+		response.Details[key] = value
+	}
+	c.AbortWithStatusJSON(http.StatusTooManyRequests, response)
+}
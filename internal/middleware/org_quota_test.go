@@ -0,0 +1,143 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	orgResponses "github.com/Kisanlink/aaa-service/v2/internal/entities/responses/organizations"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// quotaTestService allows a fixed number of requests per organization
+type quotaTestService struct {
+	limit    int64
+	used     map[string]int64
+	resetAt  time.Time
+	disabled bool
+}
+
+func (q *quotaTestService) Enabled() bool {
+	return !q.disabled
+}
+
+func (q *quotaTestService) ConsumeRequest(ctx context.Context, orgID string) (*interfaces.OrganizationQuotaDecision, error) {
+	decision := &interfaces.OrganizationQuotaDecision{Allowed: q.used[orgID] < q.limit}
+	if decision.Allowed {
+		q.used[orgID]++
+	} else {
+		decision.Exceeded = interfaces.OrganizationQuotaMonthly
+	}
+	decision.MonthlyQuota = orgResponses.OrganizationQuotaWindow{
+		Limit:     q.limit,
+		Used:      q.used[orgID],
+		Remaining: q.limit - q.used[orgID],
+		ResetAt:   q.resetAt,
+	}
+	return decision, nil
+}
+
+func (q *quotaTestService) GetQuotaStatus(ctx context.Context, orgID string) (*orgResponses.OrganizationQuotaResponse, error) {
+	return nil, nil
+}
+
+type quotaTestViolations struct {
+	details []map[string]interface{}
+}
+
+func (v *quotaTestViolations) LogRateLimitViolation(ctx context.Context, userID, endpoint, ipAddress, userAgent string, details map[string]interface{}) {
+	v.details = append(v.details, details)
+}
+
+func newOrganizationQuotaTestRouter(quotas interfaces.OrganizationQuotaService, violations RateLimitViolationRecorder) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("user_id", "USER1")
+		c.Set("organization_ids", []string{"ORGN1", "ORGN2"})
+		c.Next()
+	})
+	router.Use(OrganizationQuota(quotas, violations, nil))
+	router.GET("/api/v1/users", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	})
+	return router
+}
+
+func quotaTestRequest(router *gin.Engine, orgID string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users", nil)
+	if orgID != "" {
+		req.Header.Set(OrganizationHeader, orgID)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestOrganizationQuota_RejectsOnceExhausted(t *testing.T) {
+	resetAt := time.Now().Add(time.Hour).Truncate(time.Second)
+	quotas := &quotaTestService{limit: 1, used: map[string]int64{}, resetAt: resetAt}
+	violations := &quotaTestViolations{}
+	router := newOrganizationQuotaTestRouter(quotas, violations)
+
+	w := quotaTestRequest(router, "ORGN1")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "1", w.Header().Get("X-Quota-Limit"))
+	assert.Equal(t, "0", w.Header().Get("X-Quota-Remaining"))
+
+	w = quotaTestRequest(router, "ORGN1")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, string(errors.CodeRateLimited), body["code"])
+	details := body["details"].(map[string]interface{})
+	assert.Equal(t, interfaces.OrganizationQuotaMonthly, details["quota"])
+	assert.Equal(t, resetAt.UTC().Format(time.RFC3339), details["reset_at"])
+
+	require.Len(t, violations.details, 1)
+	assert.Equal(t, "ORGN1", violations.details[0]["organization_id"])
+
+	// Other organizations of the caller have their own quota
+	assert.Equal(t, http.StatusOK, quotaTestRequest(router, "ORGN2").Code)
+}
+
+func TestOrganizationQuota_RequiresAnOrganizationToCharge(t *testing.T) {
+	quotas := &quotaTestService{limit: 5, used: map[string]int64{}}
+	router := newOrganizationQuotaTestRouter(quotas, nil)
+
+	// The caller belongs to several organizations and names none, or one it is not a member of
+	assert.Equal(t, http.StatusBadRequest, quotaTestRequest(router, "").Code)
+	assert.Equal(t, http.StatusForbidden, quotaTestRequest(router, "ORGN9").Code)
+	assert.Empty(t, quotas.used)
+}
+
+func TestOrganizationQuota_SkipsRequestsOutsideAnOrganization(t *testing.T) {
+	quotas := &quotaTestService{limit: 0, used: map[string]int64{}}
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(OrganizationQuota(quotas, nil, nil))
+	router.GET("/api/v1/users", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	})
+
+	assert.Equal(t, http.StatusOK, quotaTestRequest(router, "").Code)
+	assert.Empty(t, quotas.used)
+}
+
+func TestOrganizationQuota_DisabledLetsEverythingThrough(t *testing.T) {
+	quotas := &quotaTestService{limit: 0, used: map[string]int64{}, disabled: true}
+	router := newOrganizationQuotaTestRouter(quotas, nil)
+
+	w := quotaTestRequest(router, "ORGN1")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("X-Quota-Limit"))
+}
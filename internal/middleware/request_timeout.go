@@ -0,0 +1,268 @@
+package middleware
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/responses"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// Request timeout defaults
+const (
+	DefaultRequestTimeout       = 30 * time.Second
+	DefaultSlowRequestThreshold = 2 * time.Second
+)
+
+// SlowRequestRecorder records requests that took longer than the slow-request threshold
+type SlowRequestRecorder interface {
+	RecordSlowRequest(ctx context.Context, userID, method, route string, statusCode int, duration time.Duration)
+}
+
+// RequestTimeoutConfig controls RequestTimeout
+type RequestTimeoutConfig struct {
+	// Timeout is the time a request may take before it is answered with 504; 0 disables timeouts
+	Timeout time.Duration
+	// SlowRequestThreshold is the duration above which a request is logged as slow; 0 disables it
+	SlowRequestThreshold time.Duration
+	// RouteTimeouts overrides Timeout per route, keyed by "METHOD /route/pattern". A zero value
+	// exempts the route, which streaming and export endpoints need.
+	RouteTimeouts map[string]time.Duration
+	// Recorder, when set, receives every slow request
+	Recorder SlowRequestRecorder
+	Logger   *zap.Logger
+}
+
+// DefaultRequestTimeoutConfig returns the default timeouts, exempting the streaming CSV endpoints
+func DefaultRequestTimeoutConfig(logger *zap.Logger) RequestTimeoutConfig {
+	return RequestTimeoutConfig{
+		Timeout:              DefaultRequestTimeout,
+		SlowRequestThreshold: DefaultSlowRequestThreshold,
+		RouteTimeouts: map[string]time.Duration{
+			"POST /api/v1/users/import":           0,
+			"GET /api/v1/users/:id/access-report": 0,
+		},
+		Logger: logger,
+	}
+}
+
+// timeoutFor returns the timeout of the route matched by c
+func (cfg RequestTimeoutConfig) timeoutFor(c *gin.Context) time.Duration {
+	if timeout, ok := cfg.RouteTimeouts[c.Request.Method+" "+c.FullPath()]; ok {
+		return timeout
+	}
+	return cfg.Timeout
+}
+
+// RequestTimeout cancels the request context after the configured timeout and answers 504 if the
+// handler has not finished by then. Handlers write into a buffer that is sent once they return, so
+// a late handler cannot corrupt the timeout response. Event streams and exempt routes are not
+// buffered or timed out. Requests slower than the threshold are logged and recorded.
+func RequestTimeout(cfg RequestTimeoutConfig) gin.HandlerFunc {
+	if cfg.Logger == nil {
+		cfg.Logger = zap.NewNop()
+	}
+
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		timeout := cfg.timeoutFor(c)
+		if timeout <= 0 || isEventStreamRequest(c.Request) {
+			c.Next()
+		} else {
+			runWithTimeout(c, timeout)
+		}
+
+		if cfg.SlowRequestThreshold > 0 {
+			if duration := time.Since(start); duration >= cfg.SlowRequestThreshold {
+				cfg.recordSlowRequest(c, duration)
+			}
+		}
+	}
+}
+
+func (cfg RequestTimeoutConfig) recordSlowRequest(c *gin.Context, duration time.Duration) {
+	route := c.FullPath()
+	if route == "" {
+		route = c.Request.URL.Path
+	}
+	statusCode := c.Writer.Status()
+	userID := c.GetString("user_id")
+
+	cfg.Logger.Warn("Slow request",
+		zap.String("request_id", c.GetString("request_id")),
+		zap.String("method", c.Request.Method),
+		zap.String("route", route),
+		zap.Int("status_code", statusCode),
+		zap.Duration("duration", duration),
+		zap.Duration("threshold", cfg.SlowRequestThreshold))
+
+	if cfg.Recorder != nil {
+		// The request context may already be cancelled by the timeout
+		cfg.Recorder.RecordSlowRequest(context.WithoutCancel(c.Request.Context()), userID, c.Request.Method, route, statusCode, duration)
+	}
+}
+
+// runWithTimeout runs the rest of the handler chain with a deadline. On timeout the 504 is sent
+// right away, but this still waits for the handler to return, since gin reuses the context once
+// the middleware chain returns.
+func runWithTimeout(c *gin.Context, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+	defer cancel()
+	c.Request = c.Request.WithContext(ctx)
+
+	requestID := c.GetString("request_id")
+	underlying := c.Writer
+	tw := &timeoutResponseWriter{
+		ResponseWriter: underlying,
+		header:         underlying.Header().Clone(),
+		status:         http.StatusOK,
+	}
+	c.Writer = tw
+
+	done := make(chan struct{})
+	var panicValue interface{}
+	go func() {
+		defer close(done)
+		defer func() {
+			panicValue = recover()
+		}()
+		c.Next()
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		if ctx.Err() == context.DeadlineExceeded {
+			tw.timeout(requestID, timeout)
+		}
+		<-done
+	}
+
+	c.Writer = underlying
+	if panicValue != nil {
+		panic(panicValue)
+	}
+	tw.flush()
+}
+
+// timeoutResponseWriter buffers a handler's response until it finishes within its deadline
+type timeoutResponseWriter struct {
+	gin.ResponseWriter
+
+	mu          sync.Mutex
+	header      http.Header
+	body        bytes.Buffer
+	status      int
+	wroteHeader bool
+	timedOut    bool
+}
+
+func (w *timeoutResponseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *timeoutResponseWriter) WriteHeader(code int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.timedOut || w.wroteHeader || code <= 0 {
+		return
+	}
+	w.status = code
+}
+
+func (w *timeoutResponseWriter) WriteHeaderNow() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.wroteHeader = true
+}
+
+func (w *timeoutResponseWriter) Write(data []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	w.wroteHeader = true
+	return w.body.Write(data)
+}
+
+func (w *timeoutResponseWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *timeoutResponseWriter) Status() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.status
+}
+
+func (w *timeoutResponseWriter) Size() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.wroteHeader {
+		return -1
+	}
+	return w.body.Len()
+}
+
+func (w *timeoutResponseWriter) Written() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.wroteHeader
+}
+
+// Flush is a no-op: the response is sent when the handler returns
+func (w *timeoutResponseWriter) Flush() {}
+
+func (w *timeoutResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return nil, nil, fmt.Errorf("connection hijacking is not supported on routes with a request timeout")
+}
+
+// timeout sends the 504 response and discards anything the handler writes afterwards
+func (w *timeoutResponseWriter) timeout(requestID string, timeout time.Duration) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.timedOut = true
+
+	body, _ := json.Marshal(responses.NewErrorResponse(
+		"REQUEST_TIMEOUT",
+		fmt.Sprintf("Request timed out after %v", timeout),
+		string(errors.CodeRequestTimeout),
+	).WithRequestID(requestID))
+
+	w.ResponseWriter.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.ResponseWriter.WriteHeader(http.StatusGatewayTimeout)
+	_, _ = w.ResponseWriter.Write(body)
+	w.ResponseWriter.Flush()
+}
+
+// flush sends the buffered response of a handler that finished in time
+func (w *timeoutResponseWriter) flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.timedOut {
+		return
+	}
+
+	dst := w.ResponseWriter.Header()
+	for key, values := range w.header {
+		dst[key] = values
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+	if w.wroteHeader {
+		w.ResponseWriter.WriteHeaderNow()
+	}
+	if w.body.Len() > 0 {
+		_, _ = w.ResponseWriter.Write(w.body.Bytes())
+	}
+}
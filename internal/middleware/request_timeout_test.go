@@ -0,0 +1,105 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type slowRequestTestRecorder struct {
+	mu     sync.Mutex
+	routes []string
+	status []int
+}
+
+func (r *slowRequestTestRecorder) RecordSlowRequest(ctx context.Context, userID, method, route string, statusCode int, duration time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.routes = append(r.routes, method+" "+route)
+	r.status = append(r.status, statusCode)
+}
+
+func newRequestTimeoutTestRouter(recorder SlowRequestRecorder, handlerDone chan<- error) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequestTimeout(RequestTimeoutConfig{
+		Timeout:              50 * time.Millisecond,
+		SlowRequestThreshold: 20 * time.Millisecond,
+		RouteTimeouts:        map[string]time.Duration{"GET /export/:id": 0},
+		Recorder:             recorder,
+	}))
+
+	// slow waits far longer than the timeout unless its context is cancelled
+	router.GET("/slow", func(c *gin.Context) {
+		select {
+		case <-c.Request.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+		c.JSON(http.StatusOK, gin.H{"status": "late"})
+		handlerDone <- c.Request.Context().Err()
+	})
+	router.GET("/fast", func(c *gin.Context) {
+		c.Header("X-Handler", "fast")
+		c.JSON(http.StatusCreated, gin.H{"status": "ok"})
+	})
+	router.GET("/export/:id", func(c *gin.Context) {
+		time.Sleep(80 * time.Millisecond)
+		c.String(http.StatusOK, "id,name\n")
+	})
+	return router
+}
+
+func TestRequestTimeout_SlowHandlerTimesOut(t *testing.T) {
+	recorder := &slowRequestTestRecorder{}
+	handlerDone := make(chan error, 1)
+	router := newRequestTimeoutTestRouter(recorder, handlerDone)
+
+	start := time.Now()
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/slow", nil))
+
+	assert.Less(t, time.Since(start), time.Second, "the timeout cancels the handler's context")
+	assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+	assert.Equal(t, context.DeadlineExceeded, <-handlerDone)
+
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body), "the handler's late write is discarded")
+	assert.Equal(t, string(errors.CodeRequestTimeout), body["code"])
+
+	assert.Equal(t, []string{"GET /slow"}, recorder.routes)
+	assert.Equal(t, []int{http.StatusGatewayTimeout}, recorder.status)
+}
+
+func TestRequestTimeout_FastHandlerPassesThrough(t *testing.T) {
+	recorder := &slowRequestTestRecorder{}
+	router := newRequestTimeoutTestRouter(recorder, nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/fast", nil))
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "fast", w.Header().Get("X-Handler"))
+	assert.JSONEq(t, `{"status":"ok"}`, w.Body.String())
+	assert.Empty(t, recorder.routes)
+}
+
+func TestRequestTimeout_ExemptRouteIsNotCutOff(t *testing.T) {
+	recorder := &slowRequestTestRecorder{}
+	router := newRequestTimeoutTestRouter(recorder, nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/export/42", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "id,name\n", w.Body.String())
+	assert.Equal(t, []string{"GET /export/:id"}, recorder.routes, "exempt routes are still logged when slow")
+}
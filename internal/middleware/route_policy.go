@@ -0,0 +1,222 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/responses"
+	"github.com/Kisanlink/aaa-service/v2/internal/services"
+	"github.com/Kisanlink/aaa-service/v2/utils"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// RoutePolicy declares what a route requires of its caller. A policy without a resource lets any
+// authenticated caller through, leaving finer checks to the handler.
+type RoutePolicy struct {
+	// Public routes skip authentication and authorization
+	Public bool
+	// Resource and Action name the permission a user needs to call the route
+	Resource string
+	Action   string
+	// ResourceIDParam names the path parameter holding the ID the permission is checked against;
+	// when empty the permission is checked for the resource type
+	ResourceIDParam string
+	// OwnerParam names a path parameter holding a user ID; that user may call the route without
+	// the permission
+	OwnerParam string
+	// Scope is required of service tokens on top of the scope derived from the path
+	Scope string
+	// ServiceOnly restricts the route to service tokens
+	ServiceOnly bool
+}
+
+// RoutePolicies maps routes, by method and gin route pattern, to their declared policy. Policies
+// are declared at startup, before the router serves requests, and only read afterwards.
+type RoutePolicies struct {
+	policies map[string]RoutePolicy
+}
+
+// NewRoutePolicies creates an empty route policy registry
+func NewRoutePolicies() *RoutePolicies {
+	return &RoutePolicies{policies: make(map[string]RoutePolicy)}
+}
+
+func routePolicyKey(method, path string) string {
+	return method + " " + path
+}
+
+// Declare sets the policy of the route registered for method and path, e.g. "/api/v1/users/:id"
+func (p *RoutePolicies) Declare(method, path string, policy RoutePolicy) {
+	p.policies[routePolicyKey(method, path)] = policy
+}
+
+// Lookup returns the policy declared for the route registered for method and path
+func (p *RoutePolicies) Lookup(method, path string) (RoutePolicy, bool) {
+	policy, ok := p.policies[routePolicyKey(method, path)]
+	return policy, ok
+}
+
+// Undeclared lists the registered routes, as "METHOD path", that have no declared policy
+func (p *RoutePolicies) Undeclared(routes gin.RoutesInfo) []string {
+	var undeclared []string
+	for _, route := range routes {
+		if _, ok := p.Lookup(route.Method, route.Path); !ok {
+			undeclared = append(undeclared, routePolicyKey(route.Method, route.Path))
+		}
+	}
+	sort.Strings(undeclared)
+	return undeclared
+}
+
+// SetRoutePolicies makes HTTPAuthMiddleware and HTTPAuthzMiddleware enforce the declared policy of
+// each route. Routes without a policy fall back to the path convention of ValidateAPIEndpointAccess,
+// or are denied when strict is set.
+func (m *AuthMiddleware) SetRoutePolicies(policies *RoutePolicies, strict bool) {
+	m.routePolicies = policies
+	m.strictRoutePolicies = strict
+}
+
+// SetConcealedResources makes route policies answer 404 instead of 403 when access to a specific
+// resource of one of these types is denied, so callers cannot probe which IDs exist. The request is
+// marked with utils.ConcealForbiddenKey, so forbidden errors the handler sends through the shared
+// responder answer 404 too. The denial is still audited as access_denied by the permission check.
+func (m *AuthMiddleware) SetConcealedResources(resources []string) {
+	m.concealedResources = make(map[string]bool)
+	for _, resource := range resources {
+		if resource = strings.TrimSpace(resource); resource != "" {
+			m.concealedResources[resource] = true
+		}
+	}
+}
+
+// routePolicy returns the policy declared for the route matched by c
+func (m *AuthMiddleware) routePolicy(c *gin.Context) (RoutePolicy, bool) {
+	if m.routePolicies == nil || c.FullPath() == "" {
+		return RoutePolicy{}, false
+	}
+	return m.routePolicies.Lookup(c.Request.Method, c.FullPath())
+}
+
+// isDeclaredPublicRoute reports whether the route matched by c is declared public
+func (m *AuthMiddleware) isDeclaredPublicRoute(c *gin.Context) bool {
+	policy, ok := m.routePolicy(c)
+	return ok && policy.Public
+}
+
+// authorizeRoutePolicy enforces the declared policy of the matched route
+func (m *AuthMiddleware) authorizeRoutePolicy(c *gin.Context, policy RoutePolicy) {
+	if policy.Public {
+		c.Next()
+		return
+	}
+
+	// Service tokens are authorized by their scopes
+	if isScopedServicePrincipal(c) {
+		if policy.Scope != "" {
+			scopes, _ := c.Get("scopes")
+			granted, _ := scopes.([]string)
+			if !services.ServiceScopeCovers(granted, policy.Scope) {
+				m.logger.Warn("Service token scope does not cover route policy",
+					zap.String("service_id", c.GetString("service_id")),
+					zap.Strings("scopes", granted),
+					zap.String("required", policy.Scope),
+					zap.String("route", c.FullPath()))
+				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+					"error":   "insufficient_scope",
+					"message": "token scope does not cover " + policy.Scope,
+				})
+				return
+			}
+		}
+		c.Next()
+		return
+	}
+
+	if policy.ServiceOnly {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+			"error":   "forbidden",
+			"message": "this endpoint requires a service token",
+		})
+		return
+	}
+
+	userID := c.GetString("user_id")
+	if userID == "" {
+		m.logger.Error("User ID not found in context for route policy check", zap.String("route", c.FullPath()))
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error":   "internal_error",
+			"message": "Authentication context not found",
+		})
+		return
+	}
+
+	if policy.Resource == "" {
+		c.Next()
+		return
+	}
+
+	if policy.OwnerParam != "" && c.Param(policy.OwnerParam) == userID {
+		c.Next()
+		return
+	}
+
+	perm := &services.Permission{
+		UserID:   userID,
+		Resource: policy.Resource,
+		Action:   policy.Action,
+	}
+	if policy.ResourceIDParam != "" {
+		perm.ResourceID = c.Param(policy.ResourceIDParam)
+	}
+	conceal := perm.ResourceID != "" && m.concealedResources[policy.Resource]
+	if conceal {
+		c.Set(utils.ConcealForbiddenKey, true)
+	}
+
+	result, err := m.authzService.CheckPermission(c.Request.Context(), perm)
+	if err != nil {
+		m.logger.Error("Route policy check failed",
+			zap.String("user_id", userID),
+			zap.String("route", c.FullPath()),
+			zap.String("resource", policy.Resource),
+			zap.String("action", policy.Action),
+			zap.Error(err))
+		if errors.Is(err, services.ErrCacheUnavailable) {
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{
+				"error":   "service_unavailable",
+				"message": "Authorization is temporarily unavailable",
+			})
+			return
+		}
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error":   "internal_error",
+			"message": "Authorization check failed",
+		})
+		return
+	}
+
+	if !result.Allowed {
+		m.logger.Warn("Access denied by route policy",
+			zap.String("user_id", userID),
+			zap.String("method", c.Request.Method),
+			zap.String("route", c.FullPath()),
+			zap.String("resource", policy.Resource),
+			zap.String("action", policy.Action),
+			zap.String("resource_id", perm.ResourceID))
+		if conceal {
+			c.AbortWithStatusJSON(http.StatusNotFound,
+				responses.NewNotFoundResponse("Resource not found", c.GetString("request_id")))
+			return
+		}
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+			"error":   "forbidden",
+			"message": "Insufficient permissions to access this resource",
+		})
+		return
+	}
+
+	c.Next()
+}
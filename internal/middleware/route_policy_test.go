@@ -0,0 +1,209 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/internal/services"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/Kisanlink/aaa-service/v2/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// newRoutePolicyRouter authenticates requests from the X-Test-User and X-Test-Scopes headers and
+// authorizes them with HTTPAuthzMiddleware and the given policies
+func newRoutePolicyRouter(policies *RoutePolicies, strict bool) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	m := NewAuthMiddleware(nil, nil, nil, nil, zap.NewNop(), nil, nil)
+	m.SetRoutePolicies(policies, strict)
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		if user := c.GetHeader("X-Test-User"); user != "" {
+			c.Set("user_id", user)
+		}
+		if scope := c.GetHeader("X-Test-Scopes"); scope != "" {
+			c.Set("service_id", "SVC1")
+			c.Set("principal_type", "service")
+			c.Set("scopes", []string{scope})
+		}
+		c.Next()
+	})
+	router.Use(m.HTTPAuthzMiddleware())
+
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	router.GET("/api/v1/widgets", ok)
+	router.GET("/api/v1/users/:id/report", ok)
+	router.POST("/api/v1/widgets/register", ok)
+	router.GET("/api/v1/undeclared", ok)
+	return router
+}
+
+func serveRoutePolicy(router *gin.Engine, method, path, user, scopes string) int {
+	req := httptest.NewRequest(method, path, nil)
+	if user != "" {
+		req.Header.Set("X-Test-User", user)
+	}
+	if scopes != "" {
+		req.Header.Set("X-Test-Scopes", scopes)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func TestHTTPAuthzMiddleware_RoutePolicies(t *testing.T) {
+	policies := NewRoutePolicies()
+	policies.Declare(http.MethodGet, "/api/v1/widgets", RoutePolicy{})
+	policies.Declare(http.MethodGet, "/api/v1/users/:id/report", RoutePolicy{Resource: "users", Action: "get", ResourceIDParam: "id", OwnerParam: "id"})
+	policies.Declare(http.MethodPost, "/api/v1/widgets/register", RoutePolicy{Scope: "widgets:write", ServiceOnly: true})
+	router := newRoutePolicyRouter(policies, true)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		user   string
+		scopes string
+		want   int
+	}{
+		{"authenticated route", http.MethodGet, "/api/v1/widgets", "USER1", "", http.StatusOK},
+		{"owner skips the permission check", http.MethodGet, "/api/v1/users/USER1/report", "USER1", "", http.StatusOK},
+		{"service only route rejects users", http.MethodPost, "/api/v1/widgets/register", "USER1", "", http.StatusForbidden},
+		{"service token with scope", http.MethodPost, "/api/v1/widgets/register", "SVC1", "widgets:write", http.StatusOK},
+		{"service token without scope", http.MethodPost, "/api/v1/widgets/register", "SVC1", "widgets:read", http.StatusForbidden},
+		{"strict mode rejects undeclared routes", http.MethodGet, "/api/v1/undeclared", "USER1", "", http.StatusForbidden},
+		{"missing authentication context", http.MethodGet, "/api/v1/widgets", "", "", http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, serveRoutePolicy(router, tt.method, tt.path, tt.user, tt.scopes))
+		})
+	}
+}
+
+func TestRoutePolicies_Undeclared(t *testing.T) {
+	policies := NewRoutePolicies()
+	policies.Declare(http.MethodGet, "/api/v1/widgets", RoutePolicy{})
+
+	routes := gin.RoutesInfo{
+		{Method: http.MethodGet, Path: "/api/v1/widgets"},
+		{Method: http.MethodPost, Path: "/api/v1/widgets"},
+		{Method: http.MethodDelete, Path: "/api/v1/gadgets/:id"},
+	}
+	assert.Equal(t, []string{"DELETE /api/v1/gadgets/:id", "POST /api/v1/widgets"}, policies.Undeclared(routes))
+}
+
+type routePolicyAuditRepo struct {
+	interfaces.AuditRepository
+	logs []*models.AuditLog
+}
+
+func (r *routePolicyAuditRepo) Create(ctx context.Context, auditLog *models.AuditLog) error {
+	r.logs = append(r.logs, auditLog)
+	return nil
+}
+
+func TestHTTPAuthzMiddleware_ConcealedResources(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	policies := NewRoutePolicies()
+	policies.Declare(http.MethodGet, "/api/v1/users/:id", RoutePolicy{Resource: "users", Action: "get", ResourceIDParam: "id"})
+	policies.Declare(http.MethodGet, "/api/v1/users", RoutePolicy{Resource: "users", Action: "list"})
+
+	// USER2 may not read USER1, who exists; the denial is served from the permission cache
+	cache := &impersonationTestCache{values: map[string]interface{}{
+		"permission:USER2:users:USER1:get": &services.PermissionResult{Allowed: false, Reason: "no permission"},
+		"permission:USER2:users::list":     &services.PermissionResult{Allowed: false, Reason: "no permission"},
+	}}
+
+	tests := []struct {
+		name      string
+		concealed []string
+		path      string
+		want      int
+	}{
+		{"concealed resource answers not found", []string{"organizations", " users "}, "/api/v1/users/USER1", http.StatusNotFound},
+		{"other resources stay forbidden", []string{"organizations"}, "/api/v1/users/USER1", http.StatusForbidden},
+		{"denials without a resource ID stay forbidden", []string{"users"}, "/api/v1/users", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auditRepo := &routePolicyAuditRepo{}
+			auditService := services.NewAuditService(nil, auditRepo, cache, zap.NewNop())
+			authzService, err := services.NewAuthorizationService(cache, auditService, &services.AuthorizationServiceConfig{}, zap.NewNop())
+			require.NoError(t, err)
+
+			m := NewAuthMiddleware(nil, authzService, auditService, nil, zap.NewNop(), nil, nil)
+			m.SetRoutePolicies(policies, true)
+			m.SetConcealedResources(tt.concealed)
+
+			router := gin.New()
+			router.Use(func(c *gin.Context) {
+				c.Set("user_id", "USER2")
+				c.Next()
+			})
+			router.Use(m.HTTPAuthzMiddleware())
+			ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+			router.GET("/api/v1/users/:id", ok)
+			router.GET("/api/v1/users", ok)
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			assert.Equal(t, tt.want, rec.Code)
+
+			require.Len(t, auditRepo.logs, 1, "the denial is audited whatever the status")
+			assert.Equal(t, models.AuditActionAccessDenied, auditRepo.logs[0].Action)
+		})
+	}
+}
+
+func TestHTTPAuthzMiddleware_ConcealsForbiddenFromHandlers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	policies := NewRoutePolicies()
+	policies.Declare(http.MethodDelete, "/api/v1/users/:id", RoutePolicy{Resource: "users", Action: "delete", ResourceIDParam: "id"})
+
+	// The route policy lets USER2 through; the handler's own check then refuses
+	cache := &impersonationTestCache{values: map[string]interface{}{
+		"permission:USER2:users:USER1:delete": &services.PermissionResult{Allowed: true},
+	}}
+	authzService, err := services.NewAuthorizationService(cache, nil, &services.AuthorizationServiceConfig{}, zap.NewNop())
+	require.NoError(t, err)
+	responder := utils.NewResponder(utils.NewLoggerAdapter(zap.NewNop()))
+
+	for _, tt := range []struct {
+		name      string
+		concealed []string
+		want      int
+	}{
+		{"concealed resource", []string{"users"}, http.StatusNotFound},
+		{"other resources", nil, http.StatusForbidden},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewAuthMiddleware(nil, authzService, nil, nil, zap.NewNop(), nil, nil)
+			m.SetRoutePolicies(policies, true)
+			m.SetConcealedResources(tt.concealed)
+
+			router := gin.New()
+			router.Use(func(c *gin.Context) {
+				c.Set("user_id", "USER2")
+				c.Next()
+			})
+			router.Use(m.HTTPAuthzMiddleware())
+			router.DELETE("/api/v1/users/:id", func(c *gin.Context) {
+				responder.SendError(c, http.StatusForbidden, "Cannot delete a user with higher privileges",
+					errors.NewForbiddenError("target outranks the caller"))
+			})
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/api/v1/users/USER1", nil))
+			assert.Equal(t, tt.want, rec.Code)
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/config"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func securityHeadersRecorder(cfg config.SecurityHeadersConfig, path string, secure bool) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(SecurityHeadersWithConfig(cfg))
+	router.GET(path, func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if secure {
+		req.TLS = &tls.ConnectionState{}
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestSecurityHeadersWithConfig(t *testing.T) {
+	cfg := config.LoadSecurityConfig().SecurityHeaders
+
+	w := securityHeadersRecorder(cfg, "/api/v1/health", true)
+	assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+	assert.Equal(t, "DENY", w.Header().Get("X-Frame-Options"))
+	assert.Equal(t, "strict-origin-when-cross-origin", w.Header().Get("Referrer-Policy"))
+	assert.Equal(t, "max-age=31536000; includeSubDomains", w.Header().Get("Strict-Transport-Security"))
+	assert.Equal(t, cfg.ContentSecurityPolicy, w.Header().Get("Content-Security-Policy"))
+
+	// HSTS is only sent over HTTPS outside release mode
+	w = securityHeadersRecorder(cfg, "/api/v1/health", false)
+	assert.Empty(t, w.Header().Get("Strict-Transport-Security"))
+
+	// The docs UI gets the policy that admits its CDN assets
+	w = securityHeadersRecorder(cfg, "/docs", false)
+	assert.Equal(t, cfg.DocsContentSecurityPolicy, w.Header().Get("Content-Security-Policy"))
+	assert.Contains(t, w.Header().Get("Content-Security-Policy"), "https://cdn.jsdelivr.net")
+}
+
+func TestSecurityHeadersWithConfig_Toggles(t *testing.T) {
+	cfg := config.LoadSecurityConfig().SecurityHeaders
+	cfg.EnableCSP = false
+	cfg.EnableFrameOptions = false
+	cfg.EnableReferrerPolicy = false
+	cfg.EnableHSTS = false
+
+	w := securityHeadersRecorder(cfg, "/api/v1/health", true)
+	assert.Empty(t, w.Header().Get("Content-Security-Policy"))
+	assert.Empty(t, w.Header().Get("X-Frame-Options"))
+	assert.Empty(t, w.Header().Get("Referrer-Policy"))
+	assert.Empty(t, w.Header().Get("Strict-Transport-Security"))
+	assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+
+	cfg.Enabled = false
+	w = securityHeadersRecorder(cfg, "/api/v1/health", true)
+	assert.Empty(t, w.Header().Get("X-Content-Type-Options"))
+}
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/responses"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/time/rate"
+)
+
+// ServiceRateLimit limits each service principal to requestsPerMinute requests, in bursts of up to a
+// minute's allowance. It must run after RequireServiceScope; requests without a service principal
+// are limited by client IP. Limits are kept per instance.
+func ServiceRateLimit(requestsPerMinute int) gin.HandlerFunc {
+	if requestsPerMinute <= 0 {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	var mu sync.Mutex
+	limiters := make(map[string]*rate.Limiter)
+	every := time.Minute / time.Duration(requestsPerMinute)
+
+	return func(c *gin.Context) {
+		key := c.GetString("service_id")
+		if key == "" {
+			key = "ip:" + c.ClientIP()
+		}
+
+		mu.Lock()
+		limiter, ok := limiters[key]
+		if !ok {
+			limiter = rate.NewLimiter(rate.Every(every), requestsPerMinute)
+			limiters[key] = limiter
+		}
+		reservation := limiter.Reserve()
+		delay := reservation.Delay()
+		if delay > 0 {
+			reservation.Cancel()
+		}
+		mu.Unlock()
+
+		if delay > 0 {
+			retryAfter := strconv.Itoa(int(math.Ceil(delay.Seconds())))
+			c.Header("Retry-After", retryAfter)
+			c.AbortWithStatusJSON(http.StatusTooManyRequests,
+				responses.NewRateLimitResponse("Service rate limit exceeded", retryAfter, c.GetString("request_id")))
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func newServiceRateLimitTestRouter(requestsPerMinute int) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		if serviceID := c.GetHeader("X-Test-Service"); serviceID != "" {
+			c.Set("service_id", serviceID)
+		}
+		c.Next()
+	})
+	router.Use(ServiceRateLimit(requestsPerMinute))
+	router.POST("/ingest", func(c *gin.Context) {
+		c.Status(http.StatusCreated)
+	})
+	return router
+}
+
+func serviceRateLimitRequest(router *gin.Engine, serviceID string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/ingest", nil)
+	if serviceID != "" {
+		req.Header.Set("X-Test-Service", serviceID)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestServiceRateLimit_LimitsEachService(t *testing.T) {
+	router := newServiceRateLimitTestRouter(2)
+
+	assert.Equal(t, http.StatusCreated, serviceRateLimitRequest(router, "SVC1").Code)
+	assert.Equal(t, http.StatusCreated, serviceRateLimitRequest(router, "SVC1").Code)
+
+	w := serviceRateLimitRequest(router, "SVC1")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+
+	// Another service has its own allowance
+	assert.Equal(t, http.StatusCreated, serviceRateLimitRequest(router, "SVC2").Code)
+}
+
+func TestServiceRateLimit_FallsBackToClientIP(t *testing.T) {
+	router := newServiceRateLimitTestRouter(1)
+
+	assert.Equal(t, http.StatusCreated, serviceRateLimitRequest(router, "").Code)
+	assert.Equal(t, http.StatusTooManyRequests, serviceRateLimitRequest(router, "").Code)
+}
+
+func TestServiceRateLimit_Disabled(t *testing.T) {
+	router := newServiceRateLimitTestRouter(0)
+
+	for i := 0; i < 5; i++ {
+		assert.Equal(t, http.StatusCreated, serviceRateLimitRequest(router, "SVC1").Code)
+	}
+}
@@ -0,0 +1,148 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/services"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// isServiceToken reports whether verified claims belong to a service-to-service token
+func isServiceToken(claims *JWTClaims) bool {
+	tokenType, _ := claims.Raw["token_type"].(string)
+	return tokenType == services.ServiceTokenType
+}
+
+// serviceAudience is the audience service tokens must name to be accepted by this service
+func (m *AuthMiddleware) serviceAudience() string {
+	if m.jwtCfg.Audience != "" {
+		return m.jwtCfg.Audience
+	}
+	return m.jwtCfg.Issuer
+}
+
+// authenticateServiceToken enforces the audience and scope restrictions of a service token and
+// sets the service principal context. Unlike user tokens, a service token must always carry an
+// audience naming this service, even when AAA_JWT_AUDIENCE is not configured.
+func (m *AuthMiddleware) authenticateServiceToken(c *gin.Context, claims *JWTClaims) {
+	audiences := claimStrings(claims.Raw["aud"])
+	if !containsString(audiences, m.serviceAudience()) {
+		m.logger.Warn("Service token audience mismatch",
+			zap.String("service_id", claims.Sub),
+			zap.Strings("aud", audiences),
+			zap.String("want", m.serviceAudience()))
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+			"error": "invalid token",
+		})
+		return
+	}
+
+	scopeClaim, _ := claims.Raw["scope"].(string)
+	scopes := strings.Fields(scopeClaim)
+	required := services.RequiredServiceScope(c.Request.Method, c.Request.URL.Path)
+	if !services.ServiceScopeCovers(scopes, required) {
+		m.logger.Warn("Service token scope does not cover route",
+			zap.String("service_id", claims.Sub),
+			zap.Strings("scopes", scopes),
+			zap.String("required", required),
+			zap.String("path", c.Request.URL.Path))
+
+		if m.auditService != nil {
+			m.auditService.LogAccessDenied(c.Request.Context(), claims.Sub, c.Request.Method, "api", c.Request.URL.Path, "insufficient scope")
+		}
+
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+			"error":   "insufficient_scope",
+			"message": "token scope does not cover " + required,
+		})
+		return
+	}
+
+	c.Set("user_id", claims.Sub)
+	c.Set("service_id", claims.Sub)
+	c.Set("principal_type", "service")
+	c.Set("scopes", scopes)
+
+	m.logger.Debug("Authenticated service token",
+		zap.String("service_id", claims.Sub),
+		zap.String("scope", required),
+		zap.String("path", c.Request.URL.Path))
+
+	ctx := context.WithValue(c.Request.Context(), "user_id", claims.Sub)
+	ctx = context.WithValue(ctx, "service_id", claims.Sub)
+	ctx = context.WithValue(ctx, "principal_type", "service")
+	ctx = context.WithValue(ctx, "ip_address", c.ClientIP())
+	ctx = context.WithValue(ctx, "user_agent", c.GetHeader("User-Agent"))
+	c.Request = c.Request.WithContext(ctx)
+
+	c.Next()
+}
+
+// isScopedServicePrincipal reports whether the request was authenticated with a service token,
+// whose scopes have already been checked against the route
+func isScopedServicePrincipal(c *gin.Context) bool {
+	principalType, _ := c.Get("principal_type")
+	_, hasScopes := c.Get("scopes")
+	return principalType == "service" && hasScopes
+}
+
+// RequireServiceScope restricts a route to service tokens whose scopes cover scope. It is for
+// endpoints meant for other services, such as resource self-registration, that users must not call.
+func (m *AuthMiddleware) RequireServiceScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !isScopedServicePrincipal(c) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error":   "forbidden",
+				"message": "this endpoint requires a service token",
+			})
+			return
+		}
+
+		scopes, _ := c.Get("scopes")
+		granted, _ := scopes.([]string)
+		if !services.ServiceScopeCovers(granted, scope) {
+			m.logger.Warn("Service token scope does not cover required scope",
+				zap.String("service_id", c.GetString("service_id")),
+				zap.Strings("scopes", granted),
+				zap.String("required", scope))
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error":   "insufficient_scope",
+				"message": "token scope does not cover " + scope,
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// claimStrings reads a claim that may be a single string or an array of strings
+func claimStrings(value any) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		out := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	case []string:
+		return v
+	}
+	return nil
+}
+
+func containsString(values []string, want string) bool {
+	for _, v := range values {
+		if v == want {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,134 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/config"
+	"github.com/Kisanlink/aaa-service/v2/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newServiceTokenTestSetup(t *testing.T) (*gin.Engine, *services.AuthService) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	jwtCfg := &config.JWTConfig{Secret: "test-secret", Issuer: "aaa-service", TTL: time.Hour, Leeway: time.Minute}
+	authService, err := services.NewAuthService(nil, nil, nil, nil, nil, nil,
+		&services.AuthServiceConfig{JWTSecret: jwtCfg.Secret}, zap.NewNop(), nil, jwtCfg)
+	require.NoError(t, err)
+
+	m := NewAuthMiddleware(nil, nil, nil, nil, zap.NewNop(), NewHS256Verifier(), jwtCfg)
+
+	router := gin.New()
+	router.Use(m.HTTPAuthMiddleware())
+	handler := func(c *gin.Context) {
+		principalType, _ := c.Get("principal_type")
+		c.JSON(http.StatusOK, gin.H{"user_id": c.GetString("user_id"), "principal_type": principalType})
+	}
+	router.GET("/api/v1/users/:id", handler)
+	router.POST("/api/v1/users", handler)
+	router.GET("/api/v1/roles", handler)
+
+	return router, authService
+}
+
+func serveWithToken(router *gin.Engine, method, path, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestHTTPAuthMiddleware_ServiceToken(t *testing.T) {
+	router, authService := newServiceTokenTestSetup(t)
+
+	tests := []struct {
+		name     string
+		audience string
+		scopes   []string
+		method   string
+		path     string
+		want     int
+	}{
+		{name: "scope covers read", audience: "aaa-service", scopes: []string{"users:read"}, method: http.MethodGet, path: "/api/v1/users/USER1", want: http.StatusOK},
+		{name: "resource wildcard covers write", audience: "aaa-service", scopes: []string{"users:*"}, method: http.MethodPost, path: "/api/v1/users", want: http.StatusOK},
+		{name: "global wildcard", audience: "aaa-service", scopes: []string{"*"}, method: http.MethodGet, path: "/api/v1/roles", want: http.StatusOK},
+		{name: "read scope does not cover write", audience: "aaa-service", scopes: []string{"users:read"}, method: http.MethodPost, path: "/api/v1/users", want: http.StatusForbidden},
+		{name: "scope for another resource", audience: "aaa-service", scopes: []string{"users:read"}, method: http.MethodGet, path: "/api/v1/roles", want: http.StatusForbidden},
+		{name: "token minted for another service", audience: "catalog-service", scopes: []string{"*"}, method: http.MethodGet, path: "/api/v1/users/USER1", want: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := authService.IssueServiceToken(context.Background(), "SVC1", tt.audience, tt.scopes, time.Minute)
+			require.NoError(t, err)
+
+			rec := serveWithToken(router, tt.method, tt.path, token)
+			assert.Equal(t, tt.want, rec.Code, rec.Body.String())
+			if tt.want == http.StatusOK {
+				assert.Contains(t, rec.Body.String(), `"principal_type":"service"`)
+				assert.Contains(t, rec.Body.String(), `"user_id":"SVC1"`)
+			}
+		})
+	}
+}
+
+func TestHTTPAuthMiddleware_ServiceTokenUsesConfiguredAudience(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	jwtCfg := &config.JWTConfig{Secret: "test-secret", Issuer: "aaa-service", Audience: "aaa-api", TTL: time.Hour}
+	authService, err := services.NewAuthService(nil, nil, nil, nil, nil, nil,
+		&services.AuthServiceConfig{JWTSecret: jwtCfg.Secret}, zap.NewNop(), nil, jwtCfg)
+	require.NoError(t, err)
+
+	router := gin.New()
+	router.Use(NewAuthMiddleware(nil, nil, nil, nil, zap.NewNop(), NewHS256Verifier(), jwtCfg).HTTPAuthMiddleware())
+	router.GET("/api/v1/users/:id", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	accepted, err := authService.IssueServiceToken(context.Background(), "SVC1", "aaa-api", []string{"users:read"}, 0)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, serveWithToken(router, http.MethodGet, "/api/v1/users/USER1", accepted).Code)
+
+	rejected, err := authService.IssueServiceToken(context.Background(), "SVC1", "aaa-service", []string{"users:read"}, 0)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, serveWithToken(router, http.MethodGet, "/api/v1/users/USER1", rejected).Code)
+}
+
+func TestRequireServiceScope(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	jwtCfg := &config.JWTConfig{Secret: "test-secret", Issuer: "aaa-service", TTL: time.Hour}
+	authService, err := services.NewAuthService(nil, nil, nil, nil, nil, nil,
+		&services.AuthServiceConfig{JWTSecret: jwtCfg.Secret}, zap.NewNop(), nil, jwtCfg)
+	require.NoError(t, err)
+
+	m := NewAuthMiddleware(nil, nil, nil, nil, zap.NewNop(), NewHS256Verifier(), jwtCfg)
+	router := gin.New()
+	router.Use(m.HTTPAuthMiddleware())
+	router.POST("/api/v1/resources/register", m.RequireServiceScope("resources:write"), func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.GET("/api/v1/resources/types", m.RequireServiceScope("catalog:read"), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	token, err := authService.IssueServiceToken(context.Background(), "SVC1", "aaa-service", []string{"resources:*"}, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, serveWithToken(router, http.MethodPost, "/api/v1/resources/register", token).Code)
+	assert.Equal(t, http.StatusForbidden, serveWithToken(router, http.MethodGet, "/api/v1/resources/types", token).Code,
+		"the route scope is checked on top of the path scope")
+
+	t.Run("rejects requests without a service principal", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(rec)
+		c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/resources/register", nil)
+		c.Set("user_id", "USER1")
+		c.Set("principal_type", "user")
+
+		m.RequireServiceScope("resources:write")(c)
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+		assert.True(t, c.IsAborted())
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/Kisanlink/aaa-service/v2/internal/security"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
@@ -71,7 +72,7 @@ func StructuredLoggingMiddleware(config *StructuredLoggingConfig) gin.HandlerFun
 			RequestID:     requestID,
 			Method:        c.Request.Method,
 			Path:          c.Request.URL.Path,
-			RawQuery:      c.Request.URL.RawQuery,
+			RawQuery:      security.DefaultRedactor().RedactQuery(c.Request.URL.RawQuery),
 			ClientIP:      c.ClientIP(),
 			UserAgent:     c.GetHeader("User-Agent"),
 			Referer:       c.GetHeader("Referer"),
@@ -103,6 +104,7 @@ func StructuredLoggingMiddleware(config *StructuredLoggingConfig) gin.HandlerFun
 				requestBody, _ = io.ReadAll(c.Request.Body)
 				c.Request.Body = io.NopCloser(bytes.NewBuffer(requestBody))
 				requestInfo.RequestBodySize = len(requestBody)
+				requestInfo.RequestBody = security.DefaultRedactor().RedactJSON(requestBody)
 			}
 		}
 
@@ -165,6 +167,7 @@ type RequestInfo struct {
 	ContentType     string
 	ContentLength   int64
 	RequestBodySize int
+	RequestBody     string // captured body with sensitive values redacted
 	Headers         map[string]string
 	UserID          string
 	StartTime       time.Time
@@ -225,6 +228,10 @@ func logRequestStart(logger *zap.Logger, req *RequestInfo) {
 		fields = append(fields, zap.Any("headers", req.Headers))
 	}
 
+	if req.RequestBody != "" {
+		fields = append(fields, zap.String("request_body", req.RequestBody))
+	}
+
 	logger.Info("Request started", fields...)
 }
 
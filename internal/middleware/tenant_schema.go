@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/repositories/tenancy"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// OrganizationHeader names the organization a request acts in when the caller belongs to several
+const OrganizationHeader = "X-Organization-ID"
+
+// TenantSchemaSelector reports which organizations keep their data in their own schema
+type TenantSchemaSelector interface {
+	IsIsolated(orgID string) bool
+}
+
+// SetTenantSchemaSelector routes the repository queries of requests made in an isolated
+// organization to that organization's schema
+func (m *AuthMiddleware) SetTenantSchemaSelector(selector TenantSchemaSelector) {
+	m.tenantSchemas = selector
+}
+
+// actingOrganizationID returns the organization the request acts in: the organization in the
+// X-Organization-ID header when the caller belongs to it, or the caller's only organization.
+// It returns "" when neither applies.
+func actingOrganizationID(c *gin.Context, organizationIDs []string) string {
+	if requested := strings.TrimSpace(c.GetHeader(OrganizationHeader)); requested != "" {
+		for _, id := range organizationIDs {
+			if id == requested {
+				return requested
+			}
+		}
+		return ""
+	}
+	if len(organizationIDs) == 1 {
+		return organizationIDs[0]
+	}
+	return ""
+}
+
+// withTenantSchema selects the schema of the organization the request acts in (see
+// actingOrganizationID). Requests in organizations without their own schema use the shared schema.
+func (m *AuthMiddleware) withTenantSchema(ctx context.Context, c *gin.Context, organizationIDs []string) context.Context {
+	if m.tenantSchemas == nil {
+		return ctx
+	}
+
+	orgID := actingOrganizationID(c, organizationIDs)
+	if orgID == "" || !m.tenantSchemas.IsIsolated(orgID) {
+		return ctx
+	}
+
+	m.logger.Debug("Routing request to tenant schema",
+		zap.String("org_id", orgID),
+		zap.String("schema", tenancy.SchemaName(orgID)))
+	return tenancy.WithOrgSchema(ctx, orgID)
+}
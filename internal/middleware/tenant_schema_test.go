@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/repositories/tenancy"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+type isolatedOrgs map[string]bool
+
+func (o isolatedOrgs) IsIsolated(orgID string) bool { return o[orgID] }
+
+func TestWithTenantSchema(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		selector   TenantSchemaSelector
+		header     string
+		orgs       []string
+		wantSchema string
+	}{
+		{name: "isolation disabled", orgs: []string{"ORG1"}},
+		{name: "only organization is isolated", selector: isolatedOrgs{"ORG1": true}, orgs: []string{"ORG1"}, wantSchema: "tenant_org1"},
+		{name: "only organization is shared", selector: isolatedOrgs{"ORG1": true}, orgs: []string{"ORG2"}},
+		{name: "several organizations without header", selector: isolatedOrgs{"ORG1": true}, orgs: []string{"ORG1", "ORG2"}},
+		{name: "header selects isolated organization", selector: isolatedOrgs{"ORG1": true}, header: "ORG1", orgs: []string{"ORG2", "ORG1"}, wantSchema: "tenant_org1"},
+		{name: "header names organization the caller is not in", selector: isolatedOrgs{"ORG1": true}, header: "ORG1", orgs: []string{"ORG2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewAuthMiddleware(nil, nil, nil, nil, zap.NewNop(), nil, nil)
+			if tt.selector != nil {
+				m.SetTenantSchemaSelector(tt.selector)
+			}
+
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest("GET", "/api/v1/users", nil)
+			if tt.header != "" {
+				c.Request.Header.Set(OrganizationHeader, tt.header)
+			}
+
+			schema, ok := tenancy.SchemaFromContext(m.withTenantSchema(context.Background(), c, tt.orgs))
+			assert.Equal(t, tt.wantSchema != "", ok)
+			assert.Equal(t, tt.wantSchema, schema)
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/config"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/internal/services"
+	"github.com/gin-gonic/gin"
+	jwt "github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestHTTPAuthMiddleware_RevokedToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	jwtCfg := &config.JWTConfig{Secret: "test-secret", Issuer: "aaa-service", TTL: time.Hour, Leeway: time.Minute}
+	authService, err := services.NewAuthService(&impersonationTestUserRepo{}, nil, nil,
+		&impersonationTestCache{values: map[string]interface{}{}}, nil, nil,
+		&services.AuthServiceConfig{JWTSecret: jwtCfg.Secret}, zap.NewNop(), nil, jwtCfg)
+	require.NoError(t, err)
+
+	router := gin.New()
+	router.Use(NewAuthMiddleware(authService, nil, nil, nil, zap.NewNop(), NewHS256Verifier(), jwtCfg).HTTPAuthMiddleware())
+	router.GET("/api/v1/users/:id", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	now := time.Now()
+	token := signTestToken(t, jwtCfg.Secret, jwt.MapClaims{
+		"sub": "USER1",
+		"iss": jwtCfg.Issuer,
+		"iat": now.Unix(),
+		"exp": now.Add(time.Hour).Unix(),
+		"jti": "JTI1",
+	})
+	get := func() int {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/users/USER1", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	_, err = authService.TrackIssuedTokens(context.Background(), "USER1", "", interfaces.IssuedToken{
+		JTI: "JTI1", TokenType: "access", IssuedAt: now, ExpiresAt: now.Add(time.Hour),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, get())
+
+	require.NoError(t, authService.RevokeTokenSession(context.Background(), "USER1", "JTI1"))
+	assert.Equal(t, http.StatusUnauthorized, get())
+}
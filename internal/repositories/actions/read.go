@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/repositories/softdelete"
 	"github.com/Kisanlink/kisanlink-db/pkg/base"
 )
 
@@ -28,7 +29,7 @@ func (r *ActionRepository) GetByName(ctx context.Context, name string) (*models.
 		return nil, fmt.Errorf("action name is required")
 	}
 
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("name", base.OpEqual, name).
 		Build()
 
@@ -79,7 +80,7 @@ func (r *ActionRepository) Exists(ctx context.Context, id string) (bool, error)
 	}
 
 	// Use a filter-based query to check existence in the database
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("id", base.OpEqual, id).
 		Limit(1, 0).
 		Build()
@@ -98,7 +99,7 @@ func (r *ActionRepository) GetByCategory(ctx context.Context, category string, l
 		return nil, fmt.Errorf("category is required")
 	}
 
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("category", base.OpEqual, category).
 		Limit(limit, offset).
 		Build()
@@ -113,7 +114,7 @@ func (r *ActionRepository) GetByCategory(ctx context.Context, category string, l
 
 // GetStaticActions retrieves all static (built-in) actions
 func (r *ActionRepository) GetStaticActions(ctx context.Context, limit, offset int) ([]*models.Action, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("is_static", base.OpEqual, true).
 		Limit(limit, offset).
 		Build()
@@ -128,7 +129,7 @@ func (r *ActionRepository) GetStaticActions(ctx context.Context, limit, offset i
 
 // GetDynamicActions retrieves all dynamic (service-defined) actions
 func (r *ActionRepository) GetDynamicActions(ctx context.Context, limit, offset int) ([]*models.Action, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("is_static", base.OpEqual, false).
 		Limit(limit, offset).
 		Build()
@@ -147,7 +148,7 @@ func (r *ActionRepository) GetByServiceID(ctx context.Context, serviceID string,
 		return nil, fmt.Errorf("service ID is required")
 	}
 
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("service_id", base.OpEqual, serviceID).
 		Limit(limit, offset).
 		Build()
@@ -162,7 +163,7 @@ func (r *ActionRepository) GetByServiceID(ctx context.Context, serviceID string,
 
 // GetActiveActions retrieves all active actions
 func (r *ActionRepository) GetActiveActions(ctx context.Context, limit, offset int) ([]*models.Action, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("is_active", base.OpEqual, true).
 		Limit(limit, offset).
 		Build()
@@ -164,16 +164,7 @@ func (a *UserRepositoryAdapter) ExistsByUsername(ctx context.Context, username s
 
 // UpdateLastLogin implements UserRepositoryInterface.UpdateLastLogin
 func (a *UserRepositoryAdapter) UpdateLastLogin(ctx context.Context, userID string) error {
-	// Get the user first
-	var user *models.User
-	user, err := a.repo.GetByID(ctx, userID, user)
-	if err != nil {
-		return err
-	}
-
-	// For now, this is a no-op since the User model doesn't have LastLoginAt field
-	// TODO: Add LastLoginAt field to User model or implement in a separate table
-	return nil
+	return a.repo.UpdateLastLogin(ctx, userID, "")
 }
 
 // UpdatePassword implements UserRepositoryInterface.UpdatePassword
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/repositories/softdelete"
 	"github.com/Kisanlink/kisanlink-db/pkg/base"
 	"github.com/Kisanlink/kisanlink-db/pkg/db"
 )
@@ -55,7 +56,7 @@ func (r *AddressRepository) Delete(ctx context.Context, id string, address *mode
 // List retrieves a list of addresses with pagination using database-level filtering
 func (r *AddressRepository) List(ctx context.Context, limit, offset int) ([]*models.Address, error) {
 	// Use base filterable repository for optimized database-level filtering
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Sort("id", "asc"). // Default sort by ID ascending
 		Limit(limit, offset).
 		Build()
@@ -76,7 +77,7 @@ func (r *AddressRepository) CountWithDeleted(ctx context.Context) (int64, error)
 
 // GetByUserID retrieves addresses by user ID using database-level filtering
 func (r *AddressRepository) GetByUserID(ctx context.Context, userID string) ([]*models.Address, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("user_id", base.OpEqual, userID).
 		Build()
 
@@ -85,7 +86,7 @@ func (r *AddressRepository) GetByUserID(ctx context.Context, userID string) ([]*
 
 // Search searches addresses by keyword using database-level filtering
 func (r *AddressRepository) Search(ctx context.Context, query string, limit, offset int) ([]*models.Address, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("full_address", base.OpContains, query).
 		Limit(limit, offset).
 		Build()
@@ -95,7 +96,7 @@ func (r *AddressRepository) Search(ctx context.Context, query string, limit, off
 
 // SearchCount returns the count of addresses matching the search query
 func (r *AddressRepository) SearchCount(ctx context.Context, query string) (int64, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("full_address", base.OpContains, query).
 		Build()
 
@@ -104,7 +105,7 @@ func (r *AddressRepository) SearchCount(ctx context.Context, query string) (int6
 
 // GetByFullAddress retrieves an address by exact full_address match
 func (r *AddressRepository) GetByFullAddress(ctx context.Context, fullAddress string) (*models.Address, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("full_address", base.OpEqual, fullAddress).
 		Limit(1, 0).
 		Build()
@@ -123,7 +124,7 @@ func (r *AddressRepository) GetByFullAddress(ctx context.Context, fullAddress st
 
 // GetByType retrieves addresses by type using database-level filtering
 func (r *AddressRepository) GetByType(ctx context.Context, addressType string, limit, offset int) ([]*models.Address, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("type", base.OpEqual, addressType).
 		Limit(limit, offset).
 		Build()
@@ -133,7 +134,7 @@ func (r *AddressRepository) GetByType(ctx context.Context, addressType string, l
 
 // GetByCity retrieves addresses by city using database-level filtering
 func (r *AddressRepository) GetByCity(ctx context.Context, city string, limit, offset int) ([]*models.Address, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("city", base.OpEqual, city).
 		Limit(limit, offset).
 		Build()
@@ -143,7 +144,7 @@ func (r *AddressRepository) GetByCity(ctx context.Context, city string, limit, o
 
 // GetByState retrieves addresses by state using database-level filtering
 func (r *AddressRepository) GetByState(ctx context.Context, state string, limit, offset int) ([]*models.Address, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("state", base.OpEqual, state).
 		Limit(limit, offset).
 		Build()
@@ -153,7 +154,7 @@ func (r *AddressRepository) GetByState(ctx context.Context, state string, limit,
 
 // GetByPostalCode retrieves addresses by postal code using database-level filtering
 func (r *AddressRepository) GetByPostalCode(ctx context.Context, postalCode string, limit, offset int) ([]*models.Address, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("postal_code", base.OpEqual, postalCode).
 		Limit(limit, offset).
 		Build()
@@ -163,7 +164,7 @@ func (r *AddressRepository) GetByPostalCode(ctx context.Context, postalCode stri
 
 // GetByCountry retrieves addresses by country using database-level filtering
 func (r *AddressRepository) GetByCountry(ctx context.Context, country string, limit, offset int) ([]*models.Address, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("country", base.OpEqual, country).
 		Limit(limit, offset).
 		Build()
@@ -173,7 +174,7 @@ func (r *AddressRepository) GetByCountry(ctx context.Context, country string, li
 
 // GetActiveAddresses retrieves active addresses using database-level filtering
 func (r *AddressRepository) GetActiveAddresses(ctx context.Context, limit, offset int) ([]*models.Address, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("is_active", base.OpEqual, true).
 		Limit(limit, offset).
 		Build()
@@ -183,7 +184,7 @@ func (r *AddressRepository) GetActiveAddresses(ctx context.Context, limit, offse
 
 // GetDefaultAddresses retrieves default addresses using database-level filtering
 func (r *AddressRepository) GetDefaultAddresses(ctx context.Context, limit, offset int) ([]*models.Address, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("is_default", base.OpEqual, true).
 		Limit(limit, offset).
 		Build()
@@ -193,7 +194,7 @@ func (r *AddressRepository) GetDefaultAddresses(ctx context.Context, limit, offs
 
 // GetAddressesByDateRange retrieves addresses created within a date range using database-level filtering
 func (r *AddressRepository) GetAddressesByDateRange(ctx context.Context, startDate, endDate string, limit, offset int) ([]*models.Address, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		WhereBetween("created_at", startDate, endDate).
 		Limit(limit, offset).
 		Build()
@@ -203,7 +204,7 @@ func (r *AddressRepository) GetAddressesByDateRange(ctx context.Context, startDa
 
 // GetAddressesByUserAndType retrieves addresses by user ID and type using database-level filtering
 func (r *AddressRepository) GetAddressesByUserAndType(ctx context.Context, userID, addressType string, limit, offset int) ([]*models.Address, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("user_id", base.OpEqual, userID).
 		Where("type", base.OpEqual, addressType).
 		Limit(limit, offset).
@@ -214,7 +215,7 @@ func (r *AddressRepository) GetAddressesByUserAndType(ctx context.Context, userI
 
 // GetAddressesByUserAndCity retrieves addresses by user ID and city using database-level filtering
 func (r *AddressRepository) GetAddressesByUserAndCity(ctx context.Context, userID, city string, limit, offset int) ([]*models.Address, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("user_id", base.OpEqual, userID).
 		Where("city", base.OpEqual, city).
 		Limit(limit, offset).
@@ -225,7 +226,7 @@ func (r *AddressRepository) GetAddressesByUserAndCity(ctx context.Context, userI
 
 // GetAddressesByUserAndState retrieves addresses by user ID and state using database-level filtering
 func (r *AddressRepository) GetAddressesByUserAndState(ctx context.Context, userID, state string, limit, offset int) ([]*models.Address, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("user_id", base.OpEqual, userID).
 		Where("state", base.OpEqual, state).
 		Limit(limit, offset).
@@ -236,7 +237,7 @@ func (r *AddressRepository) GetAddressesByUserAndState(ctx context.Context, user
 
 // GetAddressesByUserAndCountry retrieves addresses by user ID and country using database-level filtering
 func (r *AddressRepository) GetAddressesByUserAndCountry(ctx context.Context, userID, country string, limit, offset int) ([]*models.Address, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("user_id", base.OpEqual, userID).
 		Where("country", base.OpEqual, country).
 		Limit(limit, offset).
@@ -247,7 +248,7 @@ func (r *AddressRepository) GetAddressesByUserAndCountry(ctx context.Context, us
 
 // GetAddressesByUserAndPostalCode retrieves addresses by user ID and postal code using database-level filtering
 func (r *AddressRepository) GetAddressesByUserAndPostalCode(ctx context.Context, userID, postalCode string, limit, offset int) ([]*models.Address, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("user_id", base.OpEqual, userID).
 		Where("postal_code", base.OpEqual, postalCode).
 		Limit(limit, offset).
@@ -258,7 +259,7 @@ func (r *AddressRepository) GetAddressesByUserAndPostalCode(ctx context.Context,
 
 // GetAddressesByUserAndActive retrieves active addresses by user ID using database-level filtering
 func (r *AddressRepository) GetAddressesByUserAndActive(ctx context.Context, userID string, limit, offset int) ([]*models.Address, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("user_id", base.OpEqual, userID).
 		Where("is_active", base.OpEqual, true).
 		Limit(limit, offset).
@@ -269,7 +270,7 @@ func (r *AddressRepository) GetAddressesByUserAndActive(ctx context.Context, use
 
 // GetAddressesByUserAndDefault retrieves default addresses by user ID using database-level filtering
 func (r *AddressRepository) GetAddressesByUserAndDefault(ctx context.Context, userID string, limit, offset int) ([]*models.Address, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("user_id", base.OpEqual, userID).
 		Where("is_default", base.OpEqual, true).
 		Limit(limit, offset).
@@ -280,7 +281,7 @@ func (r *AddressRepository) GetAddressesByUserAndDefault(ctx context.Context, us
 
 // GetAddressesByUserAndDateRange retrieves addresses by user ID and date range using database-level filtering
 func (r *AddressRepository) GetAddressesByUserAndDateRange(ctx context.Context, userID, startDate, endDate string, limit, offset int) ([]*models.Address, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("user_id", base.OpEqual, userID).
 		WhereBetween("created_at", startDate, endDate).
 		Limit(limit, offset).
@@ -291,7 +292,7 @@ func (r *AddressRepository) GetAddressesByUserAndDateRange(ctx context.Context,
 
 // GetAddressesByUserAndSearch retrieves addresses by user ID and search query using database-level filtering
 func (r *AddressRepository) GetAddressesByUserAndSearch(ctx context.Context, userID, query string, limit, offset int) ([]*models.Address, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("user_id", base.OpEqual, userID).
 		Where("full_address", base.OpContains, query).
 		Limit(limit, offset).
@@ -302,7 +303,7 @@ func (r *AddressRepository) GetAddressesByUserAndSearch(ctx context.Context, use
 
 // GetAddressesByUserAndTypeAndActive retrieves active addresses by user ID and type using database-level filtering
 func (r *AddressRepository) GetAddressesByUserAndTypeAndActive(ctx context.Context, userID, addressType string, limit, offset int) ([]*models.Address, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("user_id", base.OpEqual, userID).
 		Where("type", base.OpEqual, addressType).
 		Where("is_active", base.OpEqual, true).
@@ -314,7 +315,7 @@ func (r *AddressRepository) GetAddressesByUserAndTypeAndActive(ctx context.Conte
 
 // GetAddressesByUserAndTypeAndDefault retrieves default addresses by user ID and type using database-level filtering
 func (r *AddressRepository) GetAddressesByUserAndTypeAndDefault(ctx context.Context, userID, addressType string, limit, offset int) ([]*models.Address, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("user_id", base.OpEqual, userID).
 		Where("type", base.OpEqual, addressType).
 		Where("is_default", base.OpEqual, true).
@@ -326,7 +327,7 @@ func (r *AddressRepository) GetAddressesByUserAndTypeAndDefault(ctx context.Cont
 
 // GetAddressesByUserAndTypeAndDateRange retrieves addresses by user ID, type and date range using database-level filtering
 func (r *AddressRepository) GetAddressesByUserAndTypeAndDateRange(ctx context.Context, userID, addressType, startDate, endDate string, limit, offset int) ([]*models.Address, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("user_id", base.OpEqual, userID).
 		Where("type", base.OpEqual, addressType).
 		WhereBetween("created_at", startDate, endDate).
@@ -338,7 +339,7 @@ func (r *AddressRepository) GetAddressesByUserAndTypeAndDateRange(ctx context.Co
 
 // GetAddressesByUserAndTypeAndSearch retrieves addresses by user ID, type and search query using database-level filtering
 func (r *AddressRepository) GetAddressesByUserAndTypeAndSearch(ctx context.Context, userID, addressType, query string, limit, offset int) ([]*models.Address, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("user_id", base.OpEqual, userID).
 		Where("type", base.OpEqual, addressType).
 		Where("full_address", base.OpContains, query).
@@ -350,7 +351,7 @@ func (r *AddressRepository) GetAddressesByUserAndTypeAndSearch(ctx context.Conte
 
 // GetAddressesByUserAndTypeAndActiveAndDefault retrieves active default addresses by user ID and type using database-level filtering
 func (r *AddressRepository) GetAddressesByUserAndTypeAndActiveAndDefault(ctx context.Context, userID, addressType string, limit, offset int) ([]*models.Address, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("user_id", base.OpEqual, userID).
 		Where("type", base.OpEqual, addressType).
 		Where("is_active", base.OpEqual, true).
@@ -363,7 +364,7 @@ func (r *AddressRepository) GetAddressesByUserAndTypeAndActiveAndDefault(ctx con
 
 // GetAddressesByUserAndTypeAndActiveAndDateRange retrieves active addresses by user ID, type and date range using database-level filtering
 func (r *AddressRepository) GetAddressesByUserAndTypeAndActiveAndDateRange(ctx context.Context, userID, addressType, startDate, endDate string, limit, offset int) ([]*models.Address, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("user_id", base.OpEqual, userID).
 		Where("type", base.OpEqual, addressType).
 		Where("is_active", base.OpEqual, true).
@@ -376,7 +377,7 @@ func (r *AddressRepository) GetAddressesByUserAndTypeAndActiveAndDateRange(ctx c
 
 // GetAddressesByUserAndTypeAndActiveAndSearch retrieves active addresses by user ID, type and search query using database-level filtering
 func (r *AddressRepository) GetAddressesByUserAndTypeAndActiveAndSearch(ctx context.Context, userID, addressType, query string, limit, offset int) ([]*models.Address, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("user_id", base.OpEqual, userID).
 		Where("type", base.OpEqual, addressType).
 		Where("is_active", base.OpEqual, true).
@@ -389,7 +390,7 @@ func (r *AddressRepository) GetAddressesByUserAndTypeAndActiveAndSearch(ctx cont
 
 // GetAddressesByUserAndTypeAndDefaultAndDateRange retrieves default addresses by user ID, type and date range using database-level filtering
 func (r *AddressRepository) GetAddressesByUserAndTypeAndDefaultAndDateRange(ctx context.Context, userID, addressType, startDate, endDate string, limit, offset int) ([]*models.Address, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("user_id", base.OpEqual, userID).
 		Where("type", base.OpEqual, addressType).
 		Where("is_default", base.OpEqual, true).
@@ -402,7 +403,7 @@ func (r *AddressRepository) GetAddressesByUserAndTypeAndDefaultAndDateRange(ctx
 
 // GetAddressesByUserAndTypeAndDefaultAndSearch retrieves default addresses by user ID, type and search query using database-level filtering
 func (r *AddressRepository) GetAddressesByUserAndTypeAndDefaultAndSearch(ctx context.Context, userID, addressType, query string, limit, offset int) ([]*models.Address, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("user_id", base.OpEqual, userID).
 		Where("type", base.OpEqual, addressType).
 		Where("is_default", base.OpEqual, true).
@@ -415,7 +416,7 @@ func (r *AddressRepository) GetAddressesByUserAndTypeAndDefaultAndSearch(ctx con
 
 // GetAddressesByUserAndTypeAndDateRangeAndSearch retrieves addresses by user ID, type, date range and search query using database-level filtering
 func (r *AddressRepository) GetAddressesByUserAndTypeAndDateRangeAndSearch(ctx context.Context, userID, addressType, startDate, endDate, query string, limit, offset int) ([]*models.Address, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("user_id", base.OpEqual, userID).
 		Where("type", base.OpEqual, addressType).
 		WhereBetween("created_at", startDate, endDate).
@@ -428,7 +429,7 @@ func (r *AddressRepository) GetAddressesByUserAndTypeAndDateRangeAndSearch(ctx c
 
 // GetAddressesByUserAndTypeAndActiveAndDefaultAndDateRange retrieves active default addresses by user ID, type and date range using database-level filtering
 func (r *AddressRepository) GetAddressesByUserAndTypeAndActiveAndDefaultAndDateRange(ctx context.Context, userID, addressType, startDate, endDate string, limit, offset int) ([]*models.Address, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("user_id", base.OpEqual, userID).
 		Where("type", base.OpEqual, addressType).
 		Where("is_active", base.OpEqual, true).
@@ -442,7 +443,7 @@ func (r *AddressRepository) GetAddressesByUserAndTypeAndActiveAndDefaultAndDateR
 
 // GetAddressesByUserAndTypeAndActiveAndDefaultAndSearch retrieves active default addresses by user ID, type and search query using database-level filtering
 func (r *AddressRepository) GetAddressesByUserAndTypeAndActiveAndDefaultAndSearch(ctx context.Context, userID, addressType, query string, limit, offset int) ([]*models.Address, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("user_id", base.OpEqual, userID).
 		Where("type", base.OpEqual, addressType).
 		Where("is_active", base.OpEqual, true).
@@ -456,7 +457,7 @@ func (r *AddressRepository) GetAddressesByUserAndTypeAndActiveAndDefaultAndSearc
 
 // GetAddressesByUserAndTypeAndActiveAndDateRangeAndSearch retrieves active addresses by user ID, type, date range and search query using database-level filtering
 func (r *AddressRepository) GetAddressesByUserAndTypeAndActiveAndDateRangeAndSearch(ctx context.Context, userID, addressType, startDate, endDate, query string, limit, offset int) ([]*models.Address, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("user_id", base.OpEqual, userID).
 		Where("type", base.OpEqual, addressType).
 		Where("is_active", base.OpEqual, true).
@@ -470,7 +471,7 @@ func (r *AddressRepository) GetAddressesByUserAndTypeAndActiveAndDateRangeAndSea
 
 // GetAddressesByUserAndTypeAndDefaultAndDateRangeAndSearch retrieves default addresses by user ID, type, date range and search query using database-level filtering
 func (r *AddressRepository) GetAddressesByUserAndTypeAndDefaultAndDateRangeAndSearch(ctx context.Context, userID, addressType, startDate, endDate, query string, limit, offset int) ([]*models.Address, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("user_id", base.OpEqual, userID).
 		Where("type", base.OpEqual, addressType).
 		Where("is_default", base.OpEqual, true).
@@ -484,7 +485,7 @@ func (r *AddressRepository) GetAddressesByUserAndTypeAndDefaultAndDateRangeAndSe
 
 // GetAddressesByUserAndTypeAndActiveAndDefaultAndDateRangeAndSearch retrieves active default addresses by user ID, type, date range and search query using database-level filtering
 func (r *AddressRepository) GetAddressesByUserAndTypeAndActiveAndDefaultAndDateRangeAndSearch(ctx context.Context, userID, addressType, startDate, endDate, query string, limit, offset int) ([]*models.Address, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("user_id", base.OpEqual, userID).
 		Where("type", base.OpEqual, addressType).
 		Where("is_active", base.OpEqual, true).
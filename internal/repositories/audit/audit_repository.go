@@ -2,12 +2,17 @@ package audit
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
+	"net/netip"
+	"strings"
 	"time"
 
 	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
 	"github.com/Kisanlink/kisanlink-db/pkg/base"
 	"github.com/Kisanlink/kisanlink-db/pkg/db"
+	"gorm.io/gorm"
 )
 
 // AuditRepository handles database operations for AuditLog entities
@@ -31,6 +36,21 @@ func (r *AuditRepository) Create(ctx context.Context, auditLog *models.AuditLog)
 	return r.BaseFilterableRepository.Create(ctx, auditLog)
 }
 
+// CreateBatch creates audit logs in a single transaction, so either all of them are stored or none
+func (r *AuditRepository) CreateBatch(ctx context.Context, auditLogs []*models.AuditLog) error {
+	if len(auditLogs) == 0 {
+		return nil
+	}
+
+	gormDB, err := r.getDB(ctx, false)
+	if err != nil {
+		return fmt.Errorf("failed to get database connection: %w", err)
+	}
+	return gormDB.Transaction(func(tx *gorm.DB) error {
+		return tx.CreateInBatches(auditLogs, 100).Error
+	})
+}
+
 // GetByID retrieves an audit log by ID using the base repository
 func (r *AuditRepository) GetByID(ctx context.Context, id string) (*models.AuditLog, error) {
 	auditLog := &models.AuditLog{}
@@ -382,6 +402,236 @@ func (r *AuditRepository) ListByResourceAndActions(ctx context.Context, resource
 	return results, err
 }
 
+// ListByUserAndActions retrieves audit logs for a specific user with specific actions, newest first
+func (r *AuditRepository) ListByUserAndActions(ctx context.Context, userID string, actions []string, limit, offset int) ([]*models.AuditLog, error) {
+	var results []*models.AuditLog
+	filter := &base.Filter{
+		Group: base.FilterGroup{
+			Conditions: []base.FilterCondition{
+				{Field: "user_id", Operator: base.OpEqual, Value: userID},
+				{Field: "action", Operator: base.OpIn, Value: actions},
+			},
+			Logic: base.LogicAnd,
+		},
+		Limit:  limit,
+		Offset: offset,
+		Sort: []base.SortField{
+			{Field: "timestamp", Direction: "desc"},
+		},
+	}
+	err := r.dbManager.List(ctx, filter, &results)
+	return results, err
+}
+
+// ListByFilter retrieves audit logs matching every condition of the filter, newest first
+func (r *AuditRepository) ListByFilter(ctx context.Context, auditFilter interfaces.AuditLogFilter, limit, offset int) ([]*models.AuditLog, error) {
+	var results []*models.AuditLog
+	filter := &base.Filter{
+		Group: base.FilterGroup{
+			Conditions: auditFilterConditions(auditFilter),
+			Logic:      base.LogicAnd,
+		},
+		Limit:  limit,
+		Offset: offset,
+		Sort: []base.SortField{
+			{Field: "timestamp", Direction: "desc"},
+		},
+	}
+	err := r.dbManager.List(ctx, filter, &results)
+	return results, err
+}
+
+// CountByFilter counts audit logs matching every condition of the filter
+func (r *AuditRepository) CountByFilter(ctx context.Context, auditFilter interfaces.AuditLogFilter) (int64, error) {
+	filter := &base.Filter{
+		Group: base.FilterGroup{
+			Conditions: auditFilterConditions(auditFilter),
+			Logic:      base.LogicAnd,
+		},
+	}
+	var model models.AuditLog
+	return r.dbManager.Count(ctx, filter, &model)
+}
+
+// auditFilterConditions translates an AuditLogFilter into database conditions.
+// Multi-valued fields become IN clauses, or a plain equality when they hold a single value.
+func auditFilterConditions(f interfaces.AuditLogFilter) []base.FilterCondition {
+	var conditions []base.FilterCondition
+
+	if f.OrganizationID != "" {
+		conditions = append(conditions, base.FilterCondition{Field: "details->>'organization_id'", Operator: base.OpEqual, Value: f.OrganizationID})
+	}
+	if f.UserID != "" {
+		conditions = append(conditions, base.FilterCondition{Field: "user_id", Operator: base.OpEqual, Value: f.UserID})
+	}
+	if f.ActorID != "" {
+		conditions = append(conditions, base.FilterCondition{Field: "actor_id", Operator: base.OpEqual, Value: f.ActorID})
+	}
+	if f.OnBehalfOfID != "" {
+		conditions = append(conditions, base.FilterCondition{Field: "on_behalf_of_id", Operator: base.OpEqual, Value: f.OnBehalfOfID})
+	}
+	if condition, ok := anyOfCondition("action", f.Actions); ok {
+		conditions = append(conditions, condition)
+	}
+	if condition, ok := anyOfCondition("resource_type", f.ResourceTypes); ok {
+		conditions = append(conditions, condition)
+	}
+	if f.ResourceID != "" {
+		conditions = append(conditions, base.FilterCondition{Field: "resource_id", Operator: base.OpEqual, Value: f.ResourceID})
+	}
+	if f.Status != "" {
+		conditions = append(conditions, base.FilterCondition{Field: "status", Operator: base.OpEqual, Value: f.Status})
+	}
+	if f.StartTime != nil {
+		conditions = append(conditions, base.FilterCondition{Field: "timestamp", Operator: base.OpGreaterEqual, Value: *f.StartTime})
+	}
+	if f.EndTime != nil {
+		conditions = append(conditions, base.FilterCondition{Field: "timestamp", Operator: base.OpLessEqual, Value: *f.EndTime})
+	}
+	if f.IPAddress != "" {
+		conditions = append(conditions, base.FilterCondition{Field: "ip_address", Operator: base.OpEqual, Value: f.IPAddress})
+	}
+	if f.IPRange != nil {
+		first, last := ipRangeBounds(*f.IPRange)
+		conditions = append(conditions,
+			base.FilterCondition{Field: auditIPExpression, Operator: base.OpGreaterEqual, Value: first.String()},
+			base.FilterCondition{Field: auditIPExpression, Operator: base.OpLessEqual, Value: last.String()},
+		)
+	}
+	if f.UserAgent != "" {
+		conditions = append(conditions, base.FilterCondition{Field: "LOWER(user_agent)", Operator: base.OpLike, Value: "%" + escapeLike(strings.ToLower(f.UserAgent)) + "%"})
+	}
+
+	return conditions
+}
+
+// auditIPExpression is ip_address as an inet, or NULL when it does not look like an IP address, so
+// rows with a malformed address cannot fail the cast. It must match the expression of the
+// idx_audit_logs_ip_inet index for range queries to use it.
+const auditIPExpression = `(CASE WHEN ip_address ~ '^[0-9A-Fa-f:.]+$' THEN ip_address::inet END)`
+
+// ipRangeBounds returns the first and last addresses of a network. inet values order by family
+// first, so the bounds only match addresses of the network's family.
+func ipRangeBounds(network netip.Prefix) (netip.Addr, netip.Addr) {
+	network = network.Masked()
+	first := network.Addr()
+	bytes := first.AsSlice()
+	for bit := network.Bits(); bit < len(bytes)*8; bit++ {
+		bytes[bit/8] |= 1 << (7 - bit%8)
+	}
+	last, _ := netip.AddrFromSlice(bytes)
+	return first, last
+}
+
+// escapeLike escapes the LIKE wildcards in a value matched literally
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
+
+func anyOfCondition(field string, values []string) (base.FilterCondition, bool) {
+	switch len(values) {
+	case 0:
+		return base.FilterCondition{}, false
+	case 1:
+		return base.FilterCondition{Field: field, Operator: base.OpEqual, Value: values[0]}, true
+	default:
+		return base.FilterCondition{Field: field, Operator: base.OpIn, Value: values}, true
+	}
+}
+
+// GetPrincipalEndpointUsage counts the API calls of the given principals per endpoint since a point in
+// time, busiest endpoints first. Aggregation happens in the database.
+func (r *AuditRepository) GetPrincipalEndpointUsage(ctx context.Context, principalIDs []string, since time.Time) ([]interfaces.EndpointUsage, error) {
+	database, err := r.getDB(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var usage []interfaces.EndpointUsage
+	if err := database.WithContext(ctx).
+		Model(&models.AuditLog{}).
+		Select(`COALESCE(details->>'method', details->>'http_method', '') AS method,
+			COALESCE(resource_id, '') AS endpoint,
+			COUNT(*) AS calls,
+			SUM(CASE WHEN status = ? THEN 1 ELSE 0 END) AS failures,
+			MAX(timestamp) AS last_called_at`, models.AuditStatusFailure).
+		Where("details->>'principal_id' IN ?", principalIDs).
+		Where("action IN ?", []string{models.AuditActionHTTPRequest, models.AuditActionAPICall}).
+		Where("timestamp >= ?", since).
+		Group("1, 2").
+		Order("calls DESC, endpoint").
+		Scan(&usage).Error; err != nil {
+		return nil, fmt.Errorf("failed to aggregate principal endpoint usage: %w", err)
+	}
+	return usage, nil
+}
+
+// GetPrincipalLastActivity returns when any of the given principals last appeared in the audit trail,
+// or nil if they never did
+func (r *AuditRepository) GetPrincipalLastActivity(ctx context.Context, principalIDs []string) (*time.Time, error) {
+	database, err := r.getDB(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var lastActivity sql.NullTime
+	if err := database.WithContext(ctx).
+		Model(&models.AuditLog{}).
+		Select("MAX(timestamp)").
+		Where("details->>'principal_id' IN ?", principalIDs).
+		Row().Scan(&lastActivity); err != nil {
+		return nil, fmt.Errorf("failed to get principal last activity: %w", err)
+	}
+	if !lastActivity.Valid {
+		return nil, nil
+	}
+	return &lastActivity.Time, nil
+}
+
+// GetPrincipalsLastActivity returns when each of the given principals last appeared in the audit
+// trail, in a single grouped query. Principals that never did are absent from the result.
+func (r *AuditRepository) GetPrincipalsLastActivity(ctx context.Context, principalIDs []string) (map[string]time.Time, error) {
+	lastActivity := make(map[string]time.Time, len(principalIDs))
+	if len(principalIDs) == 0 {
+		return lastActivity, nil
+	}
+
+	database, err := r.getDB(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []struct {
+		PrincipalID  string
+		LastActivity time.Time
+	}
+	if err := database.WithContext(ctx).
+		Model(&models.AuditLog{}).
+		Select("details->>'principal_id' AS principal_id, MAX(timestamp) AS last_activity").
+		Where("details->>'principal_id' IN ?", principalIDs).
+		Group("1").
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to get principals last activity: %w", err)
+	}
+	for _, row := range rows {
+		lastActivity[row.PrincipalID] = row.LastActivity
+	}
+	return lastActivity, nil
+}
+
+func (r *AuditRepository) getDB(ctx context.Context, readOnly bool) (*gorm.DB, error) {
+	if postgresMgr, ok := r.dbManager.(interface {
+		GetDB(context.Context, bool) (*gorm.DB, error)
+	}); ok {
+		gormDB, err := postgresMgr.GetDB(ctx, readOnly)
+		if err != nil {
+			return nil, err
+		}
+		return gormDB.WithContext(ctx), nil
+	}
+	return nil, fmt.Errorf("database manager does not support GetDB method")
+}
+
 // ValidateIntegrity performs basic integrity checks on audit logs
 func (r *AuditRepository) ValidateIntegrity(ctx context.Context, auditLogID string) (*models.AuditLog, error) {
 	auditLog, err := r.GetByID(ctx, auditLogID)
@@ -0,0 +1,55 @@
+package audit
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/kisanlink-db/pkg/base"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditFilterConditions_ExactIP(t *testing.T) {
+	conditions := auditFilterConditions(interfaces.AuditLogFilter{IPAddress: "203.0.113.7"})
+
+	assert.Equal(t, []base.FilterCondition{
+		{Field: "ip_address", Operator: base.OpEqual, Value: "203.0.113.7"},
+	}, conditions)
+}
+
+func TestAuditFilterConditions_CIDR(t *testing.T) {
+	network := netip.MustParsePrefix("10.20.0.0/16")
+	conditions := auditFilterConditions(interfaces.AuditLogFilter{IPRange: &network})
+
+	assert.Equal(t, []base.FilterCondition{
+		{Field: auditIPExpression, Operator: base.OpGreaterEqual, Value: "10.20.0.0"},
+		{Field: auditIPExpression, Operator: base.OpLessEqual, Value: "10.20.255.255"},
+	}, conditions)
+}
+
+func TestIPRangeBounds(t *testing.T) {
+	tests := []struct {
+		network     string
+		first, last string
+	}{
+		{"192.168.1.77/24", "192.168.1.0", "192.168.1.255"},
+		{"10.0.0.0/8", "10.0.0.0", "10.255.255.255"},
+		{"172.16.0.0/12", "172.16.0.0", "172.31.255.255"},
+		{"2001:db8:abcd::/48", "2001:db8:abcd::", "2001:db8:abcd:ffff:ffff:ffff:ffff:ffff"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.network, func(t *testing.T) {
+			first, last := ipRangeBounds(netip.MustParsePrefix(tt.network))
+			assert.Equal(t, tt.first, first.String())
+			assert.Equal(t, tt.last, last.String())
+		})
+	}
+}
+
+func TestAuditFilterConditions_UserAgentEscapesWildcards(t *testing.T) {
+	conditions := auditFilterConditions(interfaces.AuditLogFilter{UserAgent: "Agent_100%"})
+
+	assert.Equal(t, []base.FilterCondition{
+		{Field: "LOWER(user_agent)", Operator: base.OpLike, Value: `%agent\_100\%%`},
+	}, conditions)
+}
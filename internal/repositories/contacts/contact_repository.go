@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/repositories/softdelete"
 	"github.com/Kisanlink/kisanlink-db/pkg/base"
 	"github.com/Kisanlink/kisanlink-db/pkg/db"
 )
@@ -48,7 +49,7 @@ func (r *ContactRepository) Delete(ctx context.Context, id string) error {
 
 // List retrieves contacts with pagination using database-level filtering
 func (r *ContactRepository) List(ctx context.Context, limit, offset int) ([]*models.Contact, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Sort("id", "asc"). // Default sort by ID ascending
 		Limit(limit, offset).
 		Build()
@@ -92,19 +93,29 @@ func (r *ContactRepository) ExistsWithDeleted(ctx context.Context, id string) (b
 	return r.BaseFilterableRepository.ExistsWithDeleted(ctx, id)
 }
 
-// GetByCreatedBy gets contacts by creator using the base repository
+// GetByCreatedBy gets non-deleted contacts by creator using database-level filtering
 func (r *ContactRepository) GetByCreatedBy(ctx context.Context, createdBy string, limit, offset int) ([]*models.Contact, error) {
-	return r.BaseFilterableRepository.GetByCreatedBy(ctx, createdBy, limit, offset)
+	filter := softdelete.Live().
+		Where("created_by", base.OpEqual, createdBy).
+		Limit(limit, offset).
+		Build()
+
+	return r.BaseFilterableRepository.Find(ctx, filter)
 }
 
-// GetByUpdatedBy gets contacts by updater using the base repository
+// GetByUpdatedBy gets non-deleted contacts by updater using database-level filtering
 func (r *ContactRepository) GetByUpdatedBy(ctx context.Context, updatedBy string, limit, offset int) ([]*models.Contact, error) {
-	return r.BaseFilterableRepository.GetByUpdatedBy(ctx, updatedBy, limit, offset)
+	filter := softdelete.Live().
+		Where("updated_by", base.OpEqual, updatedBy).
+		Limit(limit, offset).
+		Build()
+
+	return r.BaseFilterableRepository.Find(ctx, filter)
 }
 
 // GetByUserID retrieves contacts by user ID
 func (r *ContactRepository) GetByUserID(ctx context.Context, userID string, limit, offset int) ([]*models.Contact, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("user_id", base.OpEqual, userID).
 		Limit(limit, offset).
 		Build()
@@ -114,7 +125,7 @@ func (r *ContactRepository) GetByUserID(ctx context.Context, userID string, limi
 
 // GetByType retrieves contacts by type
 func (r *ContactRepository) GetByType(ctx context.Context, contactType string, limit, offset int) ([]*models.Contact, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("type", base.OpEqual, contactType).
 		Limit(limit, offset).
 		Build()
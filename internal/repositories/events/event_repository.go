@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/repositories/softdelete"
 	"github.com/Kisanlink/kisanlink-db/pkg/base"
 	"github.com/Kisanlink/kisanlink-db/pkg/db"
 )
@@ -48,7 +49,7 @@ func (r *EventRepository) Delete(ctx context.Context, id string) error {
 
 // List retrieves events with pagination using database-level filtering
 func (r *EventRepository) List(ctx context.Context, limit, offset int) ([]*models.Event, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Limit(limit, offset).
 		Build()
 
@@ -103,7 +104,7 @@ func (r *EventRepository) GetByUpdatedBy(ctx context.Context, updatedBy string,
 
 // GetByType retrieves events by type
 func (r *EventRepository) GetByType(ctx context.Context, eventType string, limit, offset int) ([]*models.Event, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("type", base.OpEqual, eventType).
 		Limit(limit, offset).
 		Build()
@@ -113,7 +114,7 @@ func (r *EventRepository) GetByType(ctx context.Context, eventType string, limit
 
 // GetByServiceName retrieves events by service name
 func (r *EventRepository) GetByServiceName(ctx context.Context, serviceName string, limit, offset int) ([]*models.Event, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("service_name", base.OpEqual, serviceName).
 		Limit(limit, offset).
 		Build()
@@ -123,7 +124,7 @@ func (r *EventRepository) GetByServiceName(ctx context.Context, serviceName stri
 
 // GetByUserID retrieves events by user ID
 func (r *EventRepository) GetByUserID(ctx context.Context, userID string, limit, offset int) ([]*models.Event, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("user_id", base.OpEqual, userID).
 		Limit(limit, offset).
 		Build()
@@ -133,7 +134,7 @@ func (r *EventRepository) GetByUserID(ctx context.Context, userID string, limit,
 
 // GetByDateRange retrieves events within a date range
 func (r *EventRepository) GetByDateRange(ctx context.Context, startDate, endDate string, limit, offset int) ([]*models.Event, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("created_at", base.OpGreaterEqual, startDate).
 		Where("created_at", base.OpLessEqual, endDate).
 		Limit(limit, offset).
@@ -0,0 +1,129 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/kisanlink-db/pkg/db"
+	"gorm.io/gorm"
+)
+
+// WriteOutboxEvent records an event in the outbox within tx, the transaction making the change the
+// event describes. The event is published by the outbox relay once the transaction commits, and is
+// discarded with it if the transaction rolls back.
+func WriteOutboxEvent(tx *gorm.DB, topic, aggregateType, aggregateID string, payload interface{}) error {
+	event, err := models.NewOutboxEvent(topic, aggregateType, aggregateID, payload)
+	if err != nil {
+		return err
+	}
+	if err := tx.Create(event).Error; err != nil {
+		return fmt.Errorf("failed to write %s event to outbox: %w", topic, err)
+	}
+	return nil
+}
+
+// OutboxRepository handles the outbox events read and updated by the outbox relay
+type OutboxRepository struct {
+	dbManager db.DBManager
+}
+
+// NewOutboxRepository creates a new OutboxRepository instance
+func NewOutboxRepository(dbManager db.DBManager) *OutboxRepository {
+	return &OutboxRepository{dbManager: dbManager}
+}
+
+// ClaimDue leases up to limit pending events whose next attempt is due, oldest first. Rows locked by
+// another relay are skipped, and a claimed event is not claimed again until lease has passed, so
+// concurrent relays publish each event once unless a relay stops mid-publish.
+func (r *OutboxRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*models.OutboxEvent, error) {
+	gormDB, err := r.getDB(ctx, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	var claimed []*models.OutboxEvent
+	now := time.Now()
+	err = gormDB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Raw(`SELECT * FROM outbox_events
+			WHERE status = ? AND next_attempt_at <= ? AND (locked_until IS NULL OR locked_until <= ?) AND deleted_at IS NULL
+			ORDER BY next_attempt_at ASC
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED`, models.OutboxStatusPending, now, now, limit).
+			Scan(&claimed).Error; err != nil {
+			return err
+		}
+		if len(claimed) == 0 {
+			return nil
+		}
+
+		ids := make([]string, len(claimed))
+		for i, event := range claimed {
+			ids[i] = event.ID
+		}
+		return tx.Model(&models.OutboxEvent{}).
+			Where("id IN ?", ids).
+			Update("locked_until", now.Add(lease)).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim outbox events: %w", err)
+	}
+	return claimed, nil
+}
+
+// MarkSent records that an event was published
+func (r *OutboxRepository) MarkSent(ctx context.Context, id string, sentAt time.Time) error {
+	return r.update(ctx, id, map[string]interface{}{
+		"status":       models.OutboxStatusSent,
+		"sent_at":      sentAt,
+		"locked_until": nil,
+		"last_error":   nil,
+	})
+}
+
+// MarkRetry records a failed publish attempt and when the event is next due
+func (r *OutboxRepository) MarkRetry(ctx context.Context, id string, attempts int, nextAttemptAt time.Time, lastError string) error {
+	return r.update(ctx, id, map[string]interface{}{
+		"attempts":        attempts,
+		"next_attempt_at": nextAttemptAt,
+		"locked_until":    nil,
+		"last_error":      lastError,
+	})
+}
+
+// MarkFailed stops retrying an event that ran out of publish attempts
+func (r *OutboxRepository) MarkFailed(ctx context.Context, id string, attempts int, lastError string) error {
+	return r.update(ctx, id, map[string]interface{}{
+		"status":       models.OutboxStatusFailed,
+		"attempts":     attempts,
+		"locked_until": nil,
+		"last_error":   lastError,
+	})
+}
+
+func (r *OutboxRepository) update(ctx context.Context, id string, updates map[string]interface{}) error {
+	gormDB, err := r.getDB(ctx, false)
+	if err != nil {
+		return fmt.Errorf("failed to get database connection: %w", err)
+	}
+	updates["updated_at"] = time.Now()
+	if err := gormDB.Model(&models.OutboxEvent{}).Where("id = ?", id).Updates(updates).Error; err != nil {
+		return fmt.Errorf("failed to update outbox event %s: %w", id, err)
+	}
+	return nil
+}
+
+// getDB is a helper method to get the database connection from the database manager
+func (r *OutboxRepository) getDB(ctx context.Context, readOnly bool) (*gorm.DB, error) {
+	if postgresMgr, ok := r.dbManager.(interface {
+		GetDB(context.Context, bool) (*gorm.DB, error)
+	}); ok {
+		gormDB, err := postgresMgr.GetDB(ctx, readOnly)
+		if err != nil {
+			return nil, err
+		}
+		return gormDB.WithContext(ctx), nil
+	}
+	return nil, fmt.Errorf("database manager does not support GetDB method")
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/repositories/softdelete"
 	pkgErrors "github.com/Kisanlink/aaa-service/v2/pkg/errors"
 	"github.com/Kisanlink/kisanlink-db/pkg/base"
 	"github.com/Kisanlink/kisanlink-db/pkg/db"
@@ -57,6 +58,7 @@ func (r *GroupMembershipRepository) UpdateWithVersion(ctx context.Context, membe
 		if err != nil {
 			return fmt.Errorf("failed to get database connection: %w", err)
 		}
+		db = db.WithContext(ctx)
 	} else {
 		return fmt.Errorf("database manager does not support GetDB method")
 	}
@@ -119,9 +121,21 @@ func (r *GroupMembershipRepository) Delete(ctx context.Context, id string) error
 
 // GetByGroupID retrieves all memberships for a specific group
 func (r *GroupMembershipRepository) GetByGroupID(ctx context.Context, groupID string, limit, offset int) ([]*models.GroupMembership, error) {
-	filter := base.NewFilterBuilder().
+	return r.GetByGroupIDAndType(ctx, groupID, "", limit, offset)
+}
+
+// GetByGroupIDAndType retrieves active memberships for a group, limited to one principal type
+// ("user" or "service") unless principalType is empty
+func (r *GroupMembershipRepository) GetByGroupIDAndType(ctx context.Context, groupID, principalType string, limit, offset int) ([]*models.GroupMembership, error) {
+	builder := softdelete.Live().
 		Where("group_id", base.OpEqual, groupID).
-		Where("is_active", base.OpEqual, true).
+		Where("is_active", base.OpEqual, true)
+	if principalType != "" {
+		builder = builder.Where("principal_type", base.OpEqual, principalType)
+	}
+	filter := builder.
+		Sort("created_at", "asc").
+		Sort("id", "asc").
 		Limit(limit, offset).
 		Build()
 
@@ -130,17 +144,25 @@ func (r *GroupMembershipRepository) GetByGroupID(ctx context.Context, groupID st
 
 // CountByGroupID returns the count of active memberships for a specific group
 func (r *GroupMembershipRepository) CountByGroupID(ctx context.Context, groupID string) (int64, error) {
-	filter := base.NewFilterBuilder().
+	return r.CountByGroupIDAndType(ctx, groupID, "")
+}
+
+// CountByGroupIDAndType returns the count of active memberships for a group, limited to one
+// principal type unless principalType is empty
+func (r *GroupMembershipRepository) CountByGroupIDAndType(ctx context.Context, groupID, principalType string) (int64, error) {
+	builder := softdelete.Live().
 		Where("group_id", base.OpEqual, groupID).
-		Where("is_active", base.OpEqual, true).
-		Build()
+		Where("is_active", base.OpEqual, true)
+	if principalType != "" {
+		builder = builder.Where("principal_type", base.OpEqual, principalType)
+	}
 
-	return r.BaseFilterableRepository.CountWithFilter(ctx, filter)
+	return r.BaseFilterableRepository.CountWithFilter(ctx, builder.Build())
 }
 
 // GetByPrincipalID retrieves all memberships for a specific principal (user/service)
 func (r *GroupMembershipRepository) GetByPrincipalID(ctx context.Context, principalID string, limit, offset int) ([]*models.GroupMembership, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("principal_id", base.OpEqual, principalID).
 		Where("is_active", base.OpEqual, true).
 		Limit(limit, offset).
@@ -151,7 +173,7 @@ func (r *GroupMembershipRepository) GetByPrincipalID(ctx context.Context, princi
 
 // GetByGroupAndPrincipal retrieves a specific group membership
 func (r *GroupMembershipRepository) GetByGroupAndPrincipal(ctx context.Context, groupID, principalID string) (*models.GroupMembership, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("group_id", base.OpEqual, groupID).
 		Where("principal_id", base.OpEqual, principalID).
 		Where("is_active", base.OpEqual, true).
@@ -175,7 +197,7 @@ func (r *GroupMembershipRepository) GetUserDirectGroups(ctx context.Context, org
 	// This can be optimized later with custom SQL queries if needed
 
 	// Get all memberships for the user
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("principal_id", base.OpEqual, userID).
 		Where("principal_type", base.OpEqual, "user").
 		Where("is_active", base.OpEqual, true).
@@ -218,7 +240,7 @@ func (r *GroupMembershipRepository) GetUserDirectGroups(ctx context.Context, org
 func (r *GroupMembershipRepository) GetEffectiveMemberships(ctx context.Context, groupID string, limit, offset int) ([]*models.GroupMembership, error) {
 	now := time.Now()
 
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("group_id", base.OpEqual, groupID).
 		Where("is_active", base.OpEqual, true).
 		Limit(limit, offset).
@@ -275,7 +297,7 @@ func (r *GroupMembershipRepository) CountUserGroupsInOrganization(ctx context.Co
 
 // ExistsByGroupAndPrincipal checks if a membership exists between a group and principal
 func (r *GroupMembershipRepository) ExistsByGroupAndPrincipal(ctx context.Context, groupID, principalID string) (bool, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("group_id", base.OpEqual, groupID).
 		Where("principal_id", base.OpEqual, principalID).
 		Where("is_active", base.OpEqual, true).
@@ -307,7 +329,7 @@ func (r *GroupMembershipRepository) DeactivateMembership(ctx context.Context, gr
 // ActivateMembership activates a previously deactivated group membership
 func (r *GroupMembershipRepository) ActivateMembership(ctx context.Context, groupID, principalID string) error {
 	// Find even inactive memberships
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("group_id", base.OpEqual, groupID).
 		Where("principal_id", base.OpEqual, principalID).
 		Build()
@@ -360,7 +382,7 @@ func (r *GroupMembershipRepository) GetGroupMembersWithDetails(ctx context.Conte
 
 // List retrieves group memberships with pagination using database-level filtering
 func (r *GroupMembershipRepository) List(ctx context.Context, limit, offset int) ([]*models.GroupMembership, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Limit(limit, offset).
 		Build()
 
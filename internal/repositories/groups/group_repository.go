@@ -2,11 +2,16 @@ package groups
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/repositories/softdelete"
+	"github.com/Kisanlink/aaa-service/v2/internal/repositories/sorting"
 	"github.com/Kisanlink/kisanlink-db/pkg/base"
 	"github.com/Kisanlink/kisanlink-db/pkg/db"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // GroupRepository handles database operations for Group entities
@@ -49,8 +54,7 @@ func (r *GroupRepository) Delete(ctx context.Context, id string) error {
 
 // List retrieves groups with pagination using database-level filtering
 func (r *GroupRepository) List(ctx context.Context, limit, offset int) ([]*models.Group, error) {
-	filter := base.NewFilterBuilder().
-		Sort("id", "asc"). // Default sort by ID ascending
+	filter := sorting.Apply(ctx, softdelete.Live(), sorting.GroupFields).
 		Limit(limit, offset).
 		Build()
 
@@ -65,7 +69,7 @@ func (r *GroupRepository) Count(ctx context.Context) (int64, error) {
 
 // CountActive returns the total number of active groups
 func (r *GroupRepository) CountActive(ctx context.Context) (int64, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("is_active", base.OpEqual, true).
 		Build()
 	return r.BaseFilterableRepository.CountWithFilter(ctx, filter)
@@ -73,7 +77,7 @@ func (r *GroupRepository) CountActive(ctx context.Context) (int64, error) {
 
 // CountByOrganization returns the count of groups for an organization
 func (r *GroupRepository) CountByOrganization(ctx context.Context, organizationID string, includeInactive bool) (int64, error) {
-	filterBuilder := base.NewFilterBuilder().
+	filterBuilder := softdelete.Live().
 		Where("organization_id", base.OpEqual, organizationID)
 	if !includeInactive {
 		filterBuilder.Where("is_active", base.OpEqual, true)
@@ -111,6 +115,25 @@ func (r *GroupRepository) ExistsWithDeleted(ctx context.Context, id string) (boo
 	return r.BaseFilterableRepository.ExistsWithDeleted(ctx, id)
 }
 
+// ExistingIDs returns the IDs among ids that belong to a group, in a single query. Soft-deleted
+// groups count only when includeDeleted is set.
+func (r *GroupRepository) ExistingIDs(ctx context.Context, ids []string, includeDeleted bool) ([]string, error) {
+	db, err := r.getDB(ctx, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	query := db.Model(&models.Group{}).Where("id IN ?", ids)
+	if !includeDeleted {
+		query = query.Where("deleted_at IS NULL")
+	}
+	var existing []string
+	if err := query.Pluck("id", &existing).Error; err != nil {
+		return nil, fmt.Errorf("failed to check group existence: %w", err)
+	}
+	return existing, nil
+}
+
 // GetByCreatedBy gets groups by creator using the base repository
 func (r *GroupRepository) GetByCreatedBy(ctx context.Context, createdBy string, limit, offset int) ([]*models.Group, error) {
 	return r.BaseFilterableRepository.GetByCreatedBy(ctx, createdBy, limit, offset)
@@ -123,7 +146,7 @@ func (r *GroupRepository) GetByUpdatedBy(ctx context.Context, updatedBy string,
 
 // GetByName retrieves a group by name
 func (r *GroupRepository) GetByName(ctx context.Context, name string) (*models.Group, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("name", base.OpEqual, name).
 		Build()
 
@@ -141,7 +164,7 @@ func (r *GroupRepository) GetByName(ctx context.Context, name string) (*models.G
 
 // GetByServiceName retrieves groups by service name
 func (r *GroupRepository) GetByServiceName(ctx context.Context, serviceName string, limit, offset int) ([]*models.Group, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("service_name", base.OpEqual, serviceName).
 		Limit(limit, offset).
 		Build()
@@ -151,7 +174,7 @@ func (r *GroupRepository) GetByServiceName(ctx context.Context, serviceName stri
 
 // GetByType retrieves groups by type
 func (r *GroupRepository) GetByType(ctx context.Context, groupType string, limit, offset int) ([]*models.Group, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("type", base.OpEqual, groupType).
 		Limit(limit, offset).
 		Build()
@@ -161,7 +184,7 @@ func (r *GroupRepository) GetByType(ctx context.Context, groupType string, limit
 
 // GetByNameAndOrganization retrieves a group by name within a specific organization
 func (r *GroupRepository) GetByNameAndOrganization(ctx context.Context, name, organizationID string) (*models.Group, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("name", base.OpEqual, name).
 		Where("organization_id", base.OpEqual, organizationID).
 		Build()
@@ -180,26 +203,80 @@ func (r *GroupRepository) GetByNameAndOrganization(ctx context.Context, name, or
 
 // GetByOrganization retrieves groups by organization with pagination
 func (r *GroupRepository) GetByOrganization(ctx context.Context, organizationID string, limit, offset int, includeInactive bool) ([]*models.Group, error) {
-	filter := base.NewFilterBuilder().
-		Where("organization_id", base.OpEqual, organizationID).
+	fb := softdelete.Live().
+		Where("organization_id", base.OpEqual, organizationID)
+	if !includeInactive {
+		fb.Where("is_active", base.OpEqual, true)
+	}
+	filter := sorting.Apply(ctx, fb, sorting.GroupFields).
 		Limit(limit, offset).
 		Build()
 
-	if !includeInactive {
-		filter = base.NewFilterBuilder().
-			Where("organization_id", base.OpEqual, organizationID).
-			Where("is_active", base.OpEqual, true).
-			Limit(limit, offset).
-			Build()
+	return r.BaseFilterableRepository.Find(ctx, filter)
+}
+
+// rootParentField selects the root groups with a single condition. Moving a group to the root
+// stores an empty parent_id while new root groups have none; an OR of the two cannot be combined
+// with the other conditions of a filter.
+const rootParentField = "COALESCE(parent_id, '')"
+
+// GroupListFilter narrows ListByOrganizationFiltered; zero fields do not filter
+type GroupListFilter struct {
+	NameContains string
+	IsActive     *bool
+	ParentID     *string  // an empty ID selects the root groups
+	GroupIDs     []string // keep only these groups when not nil; an empty slice keeps none
+}
+
+// ListByOrganizationFiltered retrieves a page of the groups of an organization matching filter,
+// in the order carried by ctx, and the total number of matches
+func (r *GroupRepository) ListByOrganizationFiltered(ctx context.Context, organizationID string, filter GroupListFilter, limit, offset int) ([]*models.Group, int64, error) {
+	if filter.GroupIDs != nil && len(filter.GroupIDs) == 0 {
+		return []*models.Group{}, 0, nil
 	}
 
-	return r.BaseFilterableRepository.Find(ctx, filter)
+	fb := softdelete.Live().
+		Where("organization_id", base.OpEqual, organizationID)
+	if filter.NameContains != "" {
+		fb.Where("name", base.OpContains, filter.NameContains)
+	}
+	if filter.IsActive != nil {
+		fb.Where("is_active", base.OpEqual, *filter.IsActive)
+	}
+	if filter.ParentID != nil {
+		if *filter.ParentID == "" {
+			fb.Where(rootParentField, base.OpEqual, "")
+		} else {
+			fb.Where("parent_id", base.OpEqual, *filter.ParentID)
+		}
+	}
+	if filter.GroupIDs != nil {
+		ids := make([]interface{}, len(filter.GroupIDs))
+		for i, id := range filter.GroupIDs {
+			ids[i] = id
+		}
+		fb.WhereIn("id", ids)
+	}
+
+	total, err := r.BaseFilterableRepository.CountWithFilter(ctx, fb.Build())
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count groups: %w", err)
+	}
+
+	groups, err := r.BaseFilterableRepository.Find(ctx, sorting.Apply(ctx, fb, sorting.GroupFields).
+		Limit(limit, offset).
+		Build())
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list groups: %w", err)
+	}
+	return groups, total, nil
 }
 
 // ListActive retrieves only active groups with pagination
 func (r *GroupRepository) ListActive(ctx context.Context, limit, offset int) ([]*models.Group, error) {
-	filter := base.NewFilterBuilder().
-		Where("is_active", base.OpEqual, true).
+	fb := softdelete.Live().
+		Where("is_active", base.OpEqual, true)
+	filter := sorting.Apply(ctx, fb, sorting.GroupFields).
 		Limit(limit, offset).
 		Build()
 
@@ -208,7 +285,7 @@ func (r *GroupRepository) ListActive(ctx context.Context, limit, offset int) ([]
 
 // GetChildren retrieves all child groups of a parent group
 func (r *GroupRepository) GetChildren(ctx context.Context, parentID string) ([]*models.Group, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("parent_id", base.OpEqual, parentID).
 		Where("is_active", base.OpEqual, true).
 		Build()
@@ -216,11 +293,70 @@ func (r *GroupRepository) GetChildren(ctx context.Context, parentID string) ([]*
 	return r.BaseFilterableRepository.Find(ctx, filter)
 }
 
+// GetChildrenInOrganization retrieves a page of the active direct children of a group within an
+// organization, ordered by name, and the total number of them. An empty parentID selects the root
+// groups of the organization.
+func (r *GroupRepository) GetChildrenInOrganization(ctx context.Context, organizationID, parentID string, limit, offset int) ([]*models.Group, int64, error) {
+	db, err := r.getDB(ctx, true)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	query := db.Model(&models.Group{}).
+		Where("organization_id = ? AND is_active = ? AND deleted_at IS NULL", organizationID, true)
+	if parentID == "" {
+		query = query.Where("parent_id IS NULL OR parent_id = ''")
+	} else {
+		query = query.Where("parent_id = ?", parentID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count child groups: %w", err)
+	}
+
+	var groups []*models.Group
+	if err := query.Order("name ASC, id ASC").Limit(limit).Offset(offset).Find(&groups).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to get child groups: %w", err)
+	}
+	return groups, total, nil
+}
+
+// CountChildren returns the number of active direct children of each of parentIDs in a single
+// query. Groups without children are absent from the result.
+func (r *GroupRepository) CountChildren(ctx context.Context, parentIDs []string) (map[string]int64, error) {
+	counts := make(map[string]int64, len(parentIDs))
+	if len(parentIDs) == 0 {
+		return counts, nil
+	}
+
+	db, err := r.getDB(ctx, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	var rows []struct {
+		ParentID string
+		Count    int64
+	}
+	if err := db.Model(&models.Group{}).
+		Select("parent_id, COUNT(*) AS count").
+		Where("parent_id IN ? AND is_active = ? AND deleted_at IS NULL", parentIDs, true).
+		Group("parent_id").
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to count child groups: %w", err)
+	}
+	for _, row := range rows {
+		counts[row.ParentID] = row.Count
+	}
+	return counts, nil
+}
+
 // HasActiveMembers checks if a group has any active members
 func (r *GroupRepository) HasActiveMembers(ctx context.Context, groupID string) (bool, error) {
 	// This would need to check the group_memberships table
 	// For now, we'll use a simple count approach
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("group_id", base.OpEqual, groupID).
 		Where("is_active", base.OpEqual, true).
 		Build()
@@ -264,3 +400,81 @@ func (r *GroupRepository) GetGroupMembers(ctx context.Context, groupID string, l
 	membershipRepo := NewGroupMembershipRepository(r.dbManager)
 	return membershipRepo.GetByGroupID(ctx, groupID, limit, offset)
 }
+
+// ErrGroupHierarchyCycle is returned by MoveToParent when the new parent is the group itself or one of its descendants
+var ErrGroupHierarchyCycle = errors.New("moving group would create a cycle in the group hierarchy")
+
+// MoveToParent sets a group's parent in a single transaction. The group row is locked and the new
+// parent's ancestry is re-checked inside the transaction so concurrent moves cannot form a cycle.
+// The hierarchy trigger recomputes the moved group's path and depth; the paths and depths of its
+// descendants are rewritten here.
+func (r *GroupRepository) MoveToParent(ctx context.Context, groupID, newParentID, movedBy string) error {
+	db, err := r.getDB(ctx, false)
+	if err != nil {
+		return fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var group models.Group
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND deleted_at IS NULL", groupID).
+			First(&group).Error; err != nil {
+			return fmt.Errorf("failed to lock group %s: %w", groupID, err)
+		}
+
+		visited := make(map[string]bool)
+		for currentID := newParentID; currentID != ""; {
+			if currentID == groupID || visited[currentID] {
+				return ErrGroupHierarchyCycle
+			}
+			visited[currentID] = true
+
+			var ancestor models.Group
+			if err := tx.Select("id", "parent_id").
+				Where("id = ? AND deleted_at IS NULL", currentID).
+				First(&ancestor).Error; err != nil {
+				return fmt.Errorf("failed to load ancestor group %s: %w", currentID, err)
+			}
+			currentID = ""
+			if ancestor.ParentID != nil {
+				currentID = *ancestor.ParentID
+			}
+		}
+
+		if err := tx.Model(&models.Group{}).
+			Where("id = ?", groupID).
+			Updates(map[string]interface{}{
+				"parent_id":  newParentID,
+				"updated_by": movedBy,
+				"updated_at": gorm.Expr("NOW()"),
+			}).Error; err != nil {
+			return fmt.Errorf("failed to update group parent: %w", err)
+		}
+
+		// Paths are only maintained once the hierarchy migration has run
+		if group.HierarchyPath == "" {
+			return nil
+		}
+
+		var moved models.Group
+		if err := tx.Select("id", "hierarchy_path", "hierarchy_depth").
+			Where("id = ?", groupID).
+			First(&moved).Error; err != nil {
+			return fmt.Errorf("failed to reload moved group: %w", err)
+		}
+		if moved.HierarchyPath == group.HierarchyPath {
+			return nil
+		}
+
+		if err := tx.Exec(
+			`UPDATE groups SET hierarchy_path = ? || substr(hierarchy_path, ?), hierarchy_depth = hierarchy_depth + ?
+			 WHERE hierarchy_path LIKE ? AND deleted_at IS NULL`,
+			moved.HierarchyPath, len(group.HierarchyPath)+1, moved.HierarchyDepth-group.HierarchyDepth,
+			group.HierarchyPath+"/%",
+		).Error; err != nil {
+			return fmt.Errorf("failed to update descendant hierarchy paths: %w", err)
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,155 @@
+package groups
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/repositories/sorting"
+	"github.com/Kisanlink/kisanlink-db/pkg/base"
+	"github.com/Kisanlink/kisanlink-db/pkg/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// shufflingDBManager serves List from memory like Postgres would: rows are only ordered by the
+// filter's sort fields, and rows that tie come back in arbitrary order on every query.
+type shufflingDBManager struct {
+	db.DBManager
+	groups []*models.Group
+	rng    *rand.Rand
+}
+
+func (m *shufflingDBManager) List(ctx context.Context, filter *base.Filter, model interface{}) error {
+	out, ok := model.(*[]*models.Group)
+	if !ok {
+		return fmt.Errorf("unexpected model %T", model)
+	}
+
+	rows := make([]*models.Group, len(m.groups))
+	copy(rows, m.groups)
+	m.rng.Shuffle(len(rows), func(i, j int) { rows[i], rows[j] = rows[j], rows[i] })
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		for _, field := range filter.Sort {
+			cmp := compareGroupField(rows[i], rows[j], field.Field)
+			if cmp == 0 {
+				continue
+			}
+			if field.Direction == "desc" {
+				return cmp > 0
+			}
+			return cmp < 0
+		}
+		return false
+	})
+
+	start := filter.Offset
+	if start > len(rows) {
+		start = len(rows)
+	}
+	end := start + filter.Limit
+	if end > len(rows) {
+		end = len(rows)
+	}
+	*out = rows[start:end]
+	return nil
+}
+
+func compareGroupField(a, b *models.Group, field string) int {
+	switch field {
+	case "created_at":
+		return a.CreatedAt.Compare(b.CreatedAt)
+	case "updated_at":
+		return a.UpdatedAt.Compare(b.UpdatedAt)
+	case "id":
+		return strings.Compare(a.ID, b.ID)
+	case "name":
+		return strings.Compare(a.Name, b.Name)
+	}
+	panic("unsupported sort field " + field)
+}
+
+func newPaginationTestRepository(count int) (*GroupRepository, []*models.Group) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	groups := make([]*models.Group, count)
+	for i := range groups {
+		group := models.NewGroup(fmt.Sprintf("Group %02d", i%4), "", "ORGN_TEST123")
+		group.ID = fmt.Sprintf("GRPN%04d", i)
+		// Only three distinct timestamps, so most rows tie on created_at
+		group.CreatedAt = start.Add(time.Duration(i%3) * time.Hour)
+		group.UpdatedAt = group.CreatedAt
+		groups[i] = group
+	}
+
+	manager := &shufflingDBManager{groups: groups, rng: rand.New(rand.NewSource(1))}
+	return NewGroupRepository(manager), groups
+}
+
+func paginateGroups(t *testing.T, ctx context.Context, repo *GroupRepository, pageSize int) []string {
+	var ids []string
+	for offset := 0; ; offset += pageSize {
+		page, err := repo.List(ctx, pageSize, offset)
+		require.NoError(t, err)
+		for _, group := range page {
+			ids = append(ids, group.ID)
+		}
+		if len(page) < pageSize {
+			return ids
+		}
+	}
+}
+
+func assertNoOverlapsOrGaps(t *testing.T, groups []*models.Group, ids []string) {
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		assert.False(t, seen[id], "group %s returned on more than one page", id)
+		seen[id] = true
+	}
+	for _, group := range groups {
+		assert.True(t, seen[group.ID], "group %s missing from every page", group.ID)
+	}
+	assert.Len(t, ids, len(groups))
+}
+
+func TestGroupRepositoryList_StablePagination(t *testing.T) {
+	repo, groups := newPaginationTestRepository(25)
+
+	ids := paginateGroups(t, context.Background(), repo, 4)
+
+	assertNoOverlapsOrGaps(t, groups, ids)
+	for i := 1; i < len(ids); i++ {
+		prev, cur := groups[indexOf(groups, ids[i-1])], groups[indexOf(groups, ids[i])]
+		assert.False(t, cur.CreatedAt.Before(prev.CreatedAt), "default order must be created_at ascending")
+	}
+}
+
+func TestGroupRepositoryList_CallerSortIsStable(t *testing.T) {
+	repo, groups := newPaginationTestRepository(25)
+
+	order, err := sorting.Parse("name", "desc", sorting.GroupFields)
+	require.NoError(t, err)
+	ctx := sorting.WithOrder(context.Background(), order)
+
+	ids := paginateGroups(t, ctx, repo, 4)
+
+	assertNoOverlapsOrGaps(t, groups, ids)
+	for i := 1; i < len(ids); i++ {
+		prev, cur := groups[indexOf(groups, ids[i-1])], groups[indexOf(groups, ids[i])]
+		assert.GreaterOrEqual(t, prev.Name, cur.Name, "caller order must be name descending")
+	}
+}
+
+func indexOf(groups []*models.Group, id string) int {
+	for i, group := range groups {
+		if group.ID == id {
+			return i
+		}
+	}
+	return -1
+}
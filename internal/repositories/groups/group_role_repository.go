@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/repositories/softdelete"
 	"github.com/Kisanlink/kisanlink-db/pkg/base"
 	"github.com/Kisanlink/kisanlink-db/pkg/db"
 )
@@ -49,7 +50,7 @@ func (r *GroupRoleRepository) Delete(ctx context.Context, id string) error {
 
 // GetByGroupAndRole retrieves a group role by group ID and role ID
 func (r *GroupRoleRepository) GetByGroupAndRole(ctx context.Context, groupID, roleID string) (*models.GroupRole, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("group_id", base.OpEqual, groupID).
 		Where("role_id", base.OpEqual, roleID).
 		Where("is_active", base.OpEqual, true).
@@ -69,7 +70,7 @@ func (r *GroupRoleRepository) GetByGroupAndRole(ctx context.Context, groupID, ro
 
 // GetByGroupID retrieves all roles assigned to a group
 func (r *GroupRoleRepository) GetByGroupID(ctx context.Context, groupID string) ([]*models.GroupRole, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("group_id", base.OpEqual, groupID).
 		Where("is_active", base.OpEqual, true).
 		Build()
@@ -79,7 +80,7 @@ func (r *GroupRoleRepository) GetByGroupID(ctx context.Context, groupID string)
 
 // GetByGroupIDWithRoles retrieves all roles assigned to a group with role details preloaded
 func (r *GroupRoleRepository) GetByGroupIDWithRoles(ctx context.Context, groupID string) ([]*models.GroupRole, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("group_id", base.OpEqual, groupID).
 		Where("is_active", base.OpEqual, true).
 		Build()
@@ -98,7 +99,7 @@ func (r *GroupRoleRepository) GetByGroupIDWithRoles(ctx context.Context, groupID
 
 // GetByOrganizationID retrieves all group roles within an organization
 func (r *GroupRoleRepository) GetByOrganizationID(ctx context.Context, organizationID string, limit, offset int) ([]*models.GroupRole, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("organization_id", base.OpEqual, organizationID).
 		Where("is_active", base.OpEqual, true).
 		Limit(limit, offset).
@@ -109,7 +110,7 @@ func (r *GroupRoleRepository) GetByOrganizationID(ctx context.Context, organizat
 
 // GetByOrganizationAndGroupID retrieves all roles assigned to a specific group within an organization
 func (r *GroupRoleRepository) GetByOrganizationAndGroupID(ctx context.Context, organizationID, groupID string, limit, offset int) ([]*models.GroupRole, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("organization_id", base.OpEqual, organizationID).
 		Where("group_id", base.OpEqual, groupID).
 		Where("is_active", base.OpEqual, true).
@@ -121,7 +122,7 @@ func (r *GroupRoleRepository) GetByOrganizationAndGroupID(ctx context.Context, o
 
 // GetByRoleID retrieves all groups that have a specific role assigned
 func (r *GroupRoleRepository) GetByRoleID(ctx context.Context, roleID string) ([]*models.GroupRole, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("role_id", base.OpEqual, roleID).
 		Where("is_active", base.OpEqual, true).
 		Build()
@@ -131,7 +132,7 @@ func (r *GroupRoleRepository) GetByRoleID(ctx context.Context, roleID string) ([
 
 // ExistsByGroupAndRole checks if a role is already assigned to a group
 func (r *GroupRoleRepository) ExistsByGroupAndRole(ctx context.Context, groupID, roleID string) (bool, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("group_id", base.OpEqual, groupID).
 		Where("role_id", base.OpEqual, roleID).
 		Where("is_active", base.OpEqual, true).
@@ -249,7 +250,7 @@ func (r *GroupRoleRepository) Restore(ctx context.Context, id string) error {
 
 // List retrieves group roles with pagination
 func (r *GroupRoleRepository) List(ctx context.Context, limit, offset int) ([]*models.GroupRole, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Limit(limit, offset).
 		Build()
 
@@ -337,7 +338,7 @@ func (r *GroupRoleRepository) BatchDeactivate(ctx context.Context, groupRoleIDs
 
 // GetActiveByGroupID retrieves only active roles assigned to a group
 func (r *GroupRoleRepository) GetActiveByGroupID(ctx context.Context, groupID string) ([]*models.GroupRole, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("group_id", base.OpEqual, groupID).
 		Where("is_active", base.OpEqual, true).
 		Build()
@@ -347,7 +348,7 @@ func (r *GroupRoleRepository) GetActiveByGroupID(ctx context.Context, groupID st
 
 // GetActiveByRoleID retrieves only active groups that have a specific role assigned
 func (r *GroupRoleRepository) GetActiveByRoleID(ctx context.Context, roleID string) ([]*models.GroupRole, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("role_id", base.OpEqual, roleID).
 		Where("is_active", base.OpEqual, true).
 		Build()
@@ -357,7 +358,7 @@ func (r *GroupRoleRepository) GetActiveByRoleID(ctx context.Context, roleID stri
 
 // CountByGroupID returns the number of roles assigned to a group
 func (r *GroupRoleRepository) CountByGroupID(ctx context.Context, groupID string) (int64, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("group_id", base.OpEqual, groupID).
 		Where("is_active", base.OpEqual, true).
 		Build()
@@ -367,7 +368,7 @@ func (r *GroupRoleRepository) CountByGroupID(ctx context.Context, groupID string
 
 // CountByRoleID returns the number of groups that have a specific role assigned
 func (r *GroupRoleRepository) CountByRoleID(ctx context.Context, roleID string) (int64, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("role_id", base.OpEqual, roleID).
 		Where("is_active", base.OpEqual, true).
 		Build()
@@ -377,7 +378,7 @@ func (r *GroupRoleRepository) CountByRoleID(ctx context.Context, roleID string)
 
 // GetByOrganizationIDActive retrieves only active group roles within an organization
 func (r *GroupRoleRepository) GetByOrganizationIDActive(ctx context.Context, organizationID string, limit, offset int) ([]*models.GroupRole, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("organization_id", base.OpEqual, organizationID).
 		Where("is_active", base.OpEqual, true).
 		Limit(limit, offset).
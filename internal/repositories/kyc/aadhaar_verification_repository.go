@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
 	"github.com/Kisanlink/kisanlink-db/pkg/db"
@@ -34,6 +35,9 @@ type AadhaarVerificationRepository interface {
 	// Increment verification attempts counter
 	IncrementAttempts(ctx context.Context, id string) error
 
+	// Update completion callback delivery status (DELIVERED, FAILED)
+	UpdateCallbackStatus(ctx context.Context, id string, status string, sentAt *time.Time) error
+
 	// Create OTP attempt record
 	CreateOTPAttempt(ctx context.Context, attempt *models.OTPAttempt) error
 
@@ -66,7 +70,11 @@ func (r *aadhaarVerificationRepository) getDB(ctx context.Context, readOnly bool
 	if postgresMgr, ok := r.dbManager.(interface {
 		GetDB(context.Context, bool) (*gorm.DB, error)
 	}); ok {
-		return postgresMgr.GetDB(ctx, readOnly)
+		gormDB, err := postgresMgr.GetDB(ctx, readOnly)
+		if err != nil {
+			return nil, err
+		}
+		return gormDB.WithContext(ctx), nil
 	}
 
 	return nil, fmt.Errorf("database manager does not support GetDB method")
@@ -286,6 +294,39 @@ func (r *aadhaarVerificationRepository) IncrementAttempts(ctx context.Context, i
 	return nil
 }
 
+// UpdateCallbackStatus records the outcome of the completion callback delivery
+func (r *aadhaarVerificationRepository) UpdateCallbackStatus(ctx context.Context, id string, status string, sentAt *time.Time) error {
+	db, err := r.getDB(ctx, false)
+	if err != nil {
+		r.logger.Error("Failed to get database connection",
+			zap.String("id", id),
+			zap.Error(err))
+		return fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	result := db.WithContext(ctx).
+		Model(&models.AadhaarVerification{}).
+		Where("id = ?", id).
+		UpdateColumns(map[string]interface{}{
+			"callback_status":  status,
+			"callback_sent_at": sentAt,
+		})
+
+	if result.Error != nil {
+		r.logger.Error("Failed to update callback status",
+			zap.String("id", id),
+			zap.String("status", status),
+			zap.Error(result.Error))
+		return fmt.Errorf("failed to update callback status: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("aadhaar verification not found with id: %s", id)
+	}
+
+	return nil
+}
+
 // CreateOTPAttempt creates a new OTP attempt record
 func (r *aadhaarVerificationRepository) CreateOTPAttempt(ctx context.Context, attempt *models.OTPAttempt) error {
 	db, err := r.getDB(ctx, false)
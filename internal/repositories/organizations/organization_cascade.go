@@ -0,0 +1,181 @@
+package organizations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrOrganizationNotCascadable is returned by SoftDeleteCascade when the organization is missing or
+// already deleted, and by RestoreCascade when it is missing or not deleted
+var ErrOrganizationNotCascadable = errors.New("organization not found in the expected state")
+
+// SoftDeleteCascade soft-deletes an organization together with its active groups, their memberships
+// and the roles assigned to them, in one transaction. Every row is stamped with the same deletion
+// time, which lets RestoreCascade bring back exactly these rows and not those deleted before. The
+// soft-deleted rows are returned so they can be recorded.
+func (r *OrganizationRepository) SoftDeleteCascade(ctx context.Context, orgID, deletedBy string) (*models.OrganizationCascadeSummary, error) {
+	db, err := r.getDB(ctx, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	// Postgres keeps microseconds; the restore matches rows on this exact value
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	summary := &models.OrganizationCascadeSummary{DeletedAt: now}
+	err = db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		org, err := lockCascadeOrganization(tx, orgID, "deleted_at IS NULL")
+		if err != nil {
+			return err
+		}
+		summary.Organization = org
+
+		if err := loadCascadeRows(tx, orgID, summary, "deleted_at IS NULL"); err != nil {
+			return err
+		}
+
+		values := map[string]interface{}{
+			"deleted_at": now,
+			"deleted_by": deletedBy,
+			"updated_at": now,
+		}
+		if err := updateCascadeRows(tx, summary, "deleted_at IS NULL", values); err != nil {
+			return err
+		}
+
+		org.DeletedAt = &now
+		org.DeletedBy = &deletedBy
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+// RestoreCascade restores a soft-deleted organization together with the groups, memberships and
+// group roles deleted with it by SoftDeleteCascade, in one transaction. Rows deleted at another time
+// stay deleted.
+func (r *OrganizationRepository) RestoreCascade(ctx context.Context, orgID string) (*models.OrganizationCascadeSummary, error) {
+	db, err := r.getDB(ctx, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	summary := &models.OrganizationCascadeSummary{}
+	err = db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		org, err := lockCascadeOrganization(tx, orgID, "deleted_at IS NOT NULL")
+		if err != nil {
+			return err
+		}
+		summary.Organization = org
+		summary.DeletedAt = *org.DeletedAt
+
+		if err := loadCascadeRows(tx, orgID, summary, "deleted_at = ?", *org.DeletedAt); err != nil {
+			return err
+		}
+
+		values := map[string]interface{}{
+			"deleted_at": nil,
+			"deleted_by": nil,
+			"updated_at": time.Now(),
+		}
+		if err := updateCascadeRows(tx, summary, "deleted_at IS NOT NULL", values); err != nil {
+			return err
+		}
+
+		org.DeletedAt = nil
+		org.DeletedBy = nil
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+// lockCascadeOrganization locks the organization for the cascade, provided it matches state
+func lockCascadeOrganization(tx *gorm.DB, orgID string, state string) (*models.Organization, error) {
+	var org models.Organization
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", orgID).
+		Where(state).
+		First(&org).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("%w: %s", ErrOrganizationNotCascadable, orgID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock organization %s: %w", orgID, err)
+	}
+	return &org, nil
+}
+
+// loadCascadeRows loads the groups of an organization, their memberships and group roles that
+// match state
+func loadCascadeRows(tx *gorm.DB, orgID string, summary *models.OrganizationCascadeSummary, state string, args ...interface{}) error {
+	if err := tx.Where("organization_id = ?", orgID).Where(state, args...).Find(&summary.Groups).Error; err != nil {
+		return fmt.Errorf("failed to load groups: %w", err)
+	}
+	if len(summary.Groups) == 0 {
+		return nil
+	}
+
+	groupIDs := cascadeGroupIDs(summary)
+	if err := tx.Where("group_id IN ?", groupIDs).Where(state, args...).Find(&summary.GroupMemberships).Error; err != nil {
+		return fmt.Errorf("failed to load group memberships: %w", err)
+	}
+	if err := tx.Where("group_id IN ?", groupIDs).Where(state, args...).Find(&summary.GroupRoles).Error; err != nil {
+		return fmt.Errorf("failed to load group roles: %w", err)
+	}
+	return nil
+}
+
+// updateCascadeRows sets values on the loaded rows, children before parents, and then on the
+// organization
+func updateCascadeRows(tx *gorm.DB, summary *models.OrganizationCascadeSummary, state string, values map[string]interface{}) error {
+	if len(summary.GroupMemberships) > 0 {
+		ids := make([]string, len(summary.GroupMemberships))
+		for i, membership := range summary.GroupMemberships {
+			ids[i] = membership.ID
+		}
+		if err := tx.Model(&models.GroupMembership{}).Where("id IN ?", ids).Where(state).Updates(values).Error; err != nil {
+			return fmt.Errorf("failed to update group memberships: %w", err)
+		}
+	}
+	if len(summary.GroupRoles) > 0 {
+		ids := make([]string, len(summary.GroupRoles))
+		for i, groupRole := range summary.GroupRoles {
+			ids[i] = groupRole.ID
+		}
+		if err := tx.Model(&models.GroupRole{}).Where("id IN ?", ids).Where(state).Updates(values).Error; err != nil {
+			return fmt.Errorf("failed to update group roles: %w", err)
+		}
+	}
+	if len(summary.Groups) > 0 {
+		if err := tx.Model(&models.Group{}).Where("id IN ?", cascadeGroupIDs(summary)).Where(state).Updates(values).Error; err != nil {
+			return fmt.Errorf("failed to update groups: %w", err)
+		}
+	}
+
+	result := tx.Model(&models.Organization{}).Where("id = ?", summary.Organization.ID).Where(state).Updates(values)
+	if result.Error != nil {
+		return fmt.Errorf("failed to update organization: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("%w: %s", ErrOrganizationNotCascadable, summary.Organization.ID)
+	}
+	return nil
+}
+
+func cascadeGroupIDs(summary *models.OrganizationCascadeSummary) []string {
+	ids := make([]string, len(summary.Groups))
+	for i, group := range summary.Groups {
+		ids[i] = group.ID
+	}
+	return ids
+}
@@ -0,0 +1,227 @@
+package organizations
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Kisanlink/kisanlink-db/pkg/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// cascadeDB is a database holding one organization with one group, membership and group role. It
+// records the statements run against it and fails the first one containing failOn.
+type cascadeDB struct {
+	mu         sync.Mutex
+	orgDeleted *time.Time
+	failOn     string
+	statements []string
+	committed  bool
+	rolledBack bool
+}
+
+func (d *cascadeDB) Connect(ctx context.Context) (driver.Conn, error) {
+	return &cascadeConn{db: d}, nil
+}
+func (d *cascadeDB) Driver() driver.Driver { return nil }
+
+func (d *cascadeDB) run(query string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.statements = append(d.statements, query)
+	if d.failOn != "" && strings.Contains(query, d.failOn) {
+		return errors.New("connection reset")
+	}
+	return nil
+}
+
+// updated returns the tables updated, in order
+func (d *cascadeDB) updated() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var tables []string
+	for _, statement := range d.statements {
+		if strings.HasPrefix(statement, "UPDATE ") {
+			tables = append(tables, strings.Trim(strings.Fields(statement)[1], `"`))
+		}
+	}
+	return tables
+}
+
+type cascadeConn struct {
+	db *cascadeDB
+}
+
+func (c *cascadeConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("prepared statements are not supported")
+}
+func (c *cascadeConn) Close() error              { return nil }
+func (c *cascadeConn) Begin() (driver.Tx, error) { return &cascadeTx{db: c.db}, nil }
+
+func (c *cascadeConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if err := c.db.run(query); err != nil {
+		return nil, err
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (c *cascadeConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if err := c.db.run(query); err != nil {
+		return nil, err
+	}
+	switch {
+	case strings.Contains(query, `FROM "organizations"`):
+		return &cascadeRows{columns: []string{"id", "name", "deleted_at"}, values: [][]driver.Value{{"ORG1", "Kisan FPO", c.db.orgDeleted}}}, nil
+	case strings.Contains(query, `FROM "groups"`):
+		return &cascadeRows{columns: []string{"id", "organization_id"}, values: [][]driver.Value{{"GRP1", "ORG1"}}}, nil
+	case strings.Contains(query, `FROM "group_memberships"`):
+		return &cascadeRows{columns: []string{"id", "group_id", "principal_id"}, values: [][]driver.Value{{"GM1", "GRP1", "USER1"}}}, nil
+	case strings.Contains(query, `FROM "group_roles"`):
+		return &cascadeRows{columns: []string{"id", "group_id", "role_id"}, values: [][]driver.Value{{"GR1", "GRP1", "ROLE1"}}}, nil
+	}
+	return &cascadeRows{}, nil
+}
+
+type cascadeTx struct {
+	db *cascadeDB
+}
+
+func (t *cascadeTx) Commit() error {
+	t.db.mu.Lock()
+	defer t.db.mu.Unlock()
+	t.db.committed = true
+	return nil
+}
+
+func (t *cascadeTx) Rollback() error {
+	t.db.mu.Lock()
+	defer t.db.mu.Unlock()
+	t.db.rolledBack = true
+	return nil
+}
+
+type cascadeRows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+func (r *cascadeRows) Columns() []string { return r.columns }
+func (r *cascadeRows) Close() error      { return nil }
+
+func (r *cascadeRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+// cascadeDBManager hands out the gorm connection of a cascadeDB
+type cascadeDBManager struct {
+	db.DBManager
+	gormDB *gorm.DB
+}
+
+func (m *cascadeDBManager) GetDB(ctx context.Context, readOnly bool) (*gorm.DB, error) {
+	return m.gormDB, nil
+}
+
+func newCascadeTestRepo(t *testing.T, database *cascadeDB) *OrganizationRepository {
+	t.Helper()
+	sqlDB := sql.OpenDB(database)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{SkipDefaultTransaction: true, Logger: logger.Discard})
+	require.NoError(t, err)
+	return NewOrganizationRepository(&cascadeDBManager{gormDB: gormDB})
+}
+
+func TestSoftDeleteCascade(t *testing.T) {
+	database := &cascadeDB{}
+	repo := newCascadeTestRepo(t, database)
+
+	summary, err := repo.SoftDeleteCascade(context.Background(), "ORG1", "ADMIN1")
+	require.NoError(t, err)
+
+	assert.True(t, database.committed)
+	assert.False(t, database.rolledBack)
+	assert.Equal(t, []string{"group_memberships", "group_roles", "groups", "organizations"}, database.updated(),
+		"children are deleted before their parents")
+
+	require.Len(t, summary.Groups, 1)
+	require.Len(t, summary.GroupMemberships, 1)
+	require.Len(t, summary.GroupRoles, 1)
+	assert.Equal(t, "USER1", summary.GroupMemberships[0].PrincipalID)
+	assert.Equal(t, "ROLE1", summary.GroupRoles[0].RoleID)
+	require.NotNil(t, summary.Organization.DeletedAt)
+	assert.Equal(t, summary.DeletedAt, *summary.Organization.DeletedAt)
+	assert.Equal(t, "ADMIN1", *summary.Organization.DeletedBy)
+}
+
+func TestSoftDeleteCascade_RollsBackOnFailure(t *testing.T) {
+	for _, failOn := range []string{
+		`FROM "group_memberships"`,
+		`FROM "group_roles"`,
+		`UPDATE "group_memberships"`,
+		`UPDATE "group_roles"`,
+		`UPDATE "groups"`,
+		`UPDATE "organizations"`,
+	} {
+		t.Run(failOn, func(t *testing.T) {
+			database := &cascadeDB{failOn: failOn}
+			repo := newCascadeTestRepo(t, database)
+
+			summary, err := repo.SoftDeleteCascade(context.Background(), "ORG1", "ADMIN1")
+			require.Error(t, err)
+			assert.Nil(t, summary)
+			assert.True(t, database.rolledBack, "the whole cascade is rolled back")
+			assert.False(t, database.committed)
+		})
+	}
+}
+
+func TestRestoreCascade(t *testing.T) {
+	deletedAt := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	database := &cascadeDB{orgDeleted: &deletedAt}
+	repo := newCascadeTestRepo(t, database)
+
+	summary, err := repo.RestoreCascade(context.Background(), "ORG1")
+	require.NoError(t, err)
+
+	assert.True(t, database.committed)
+	assert.Equal(t, []string{"group_memberships", "group_roles", "groups", "organizations"}, database.updated())
+	assert.Equal(t, deletedAt, summary.DeletedAt)
+	assert.Nil(t, summary.Organization.DeletedAt)
+	assert.Len(t, summary.Groups, 1)
+	assert.Len(t, summary.GroupMemberships, 1)
+	assert.Len(t, summary.GroupRoles, 1)
+
+	for _, statement := range database.statements {
+		if strings.Contains(statement, `FROM "groups"`) {
+			assert.Contains(t, statement, "deleted_at = ", "only rows deleted with the organization are restored")
+		}
+	}
+}
+
+func TestRestoreCascade_RollsBackOnFailure(t *testing.T) {
+	deletedAt := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	database := &cascadeDB{orgDeleted: &deletedAt, failOn: `UPDATE "organizations"`}
+	repo := newCascadeTestRepo(t, database)
+
+	_, err := repo.RestoreCascade(context.Background(), "ORG1")
+	require.Error(t, err)
+	assert.True(t, database.rolledBack)
+	assert.False(t, database.committed)
+	assert.Contains(t, database.updated(), "groups", "groups were restored before the failure and are rolled back")
+}
@@ -0,0 +1,132 @@
+package organizations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrOrganizationHasDependents is returned by HardDeleteCascade when rows the cascade does not
+// remove, such as child organizations or organization-scoped roles, still reference the organization
+var ErrOrganizationHasDependents = errors.New("organization is still referenced")
+
+// organizationDependents lists the tables outside the cascade that may reference an organization,
+// with the condition selecting the references. Soft-deleted rows count too: they keep their
+// foreign keys.
+var organizationDependents = []struct {
+	name  string
+	table string
+	where string
+}{
+	{"child organizations", "organizations", "parent_id = @org"},
+	{"roles", "roles", "organization_id = @org OR group_id IN (SELECT id FROM groups WHERE organization_id = @org)"},
+	{"principals", "principals", "organization_id = @org"},
+	{"services", "services", "organization_id = @org"},
+	{"bindings", "bindings", "organization_id = @org"},
+	{"column groups", "column_groups", "organization_id = @org"},
+	{"attributes", "attributes", "organization_id = @org"},
+}
+
+// HardDeleteCascade permanently deletes an organization, whether or not it is soft-deleted, together
+// with its groups, their memberships, inheritance and role assignments, its direct members and its
+// settings, in one transaction. The removed rows are returned so they can be recorded. The delete is
+// refused with ErrOrganizationHasDependents when anything outside the cascade references the
+// organization.
+func (r *OrganizationRepository) HardDeleteCascade(ctx context.Context, orgID string) (*models.OrganizationDeletionSnapshot, error) {
+	db, err := r.getDB(ctx, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	snapshot := &models.OrganizationDeletionSnapshot{}
+	err = db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var org models.Organization
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", orgID).
+			First(&org).Error; err != nil {
+			return fmt.Errorf("failed to lock organization %s: %w", orgID, err)
+		}
+		snapshot.Organization = &org
+
+		var blocking []string
+		for _, dependent := range organizationDependents {
+			var count int64
+			if err := tx.Table(dependent.table).
+				Where(dependent.where, map[string]interface{}{"org": orgID}).
+				Count(&count).Error; err != nil {
+				return fmt.Errorf("failed to count %s: %w", dependent.name, err)
+			}
+			if count > 0 {
+				blocking = append(blocking, fmt.Sprintf("%d %s", count, dependent.name))
+			}
+		}
+		if len(blocking) > 0 {
+			sort.Strings(blocking)
+			return fmt.Errorf("%w by %s", ErrOrganizationHasDependents, strings.Join(blocking, ", "))
+		}
+
+		if err := tx.Where("organization_id = ?", orgID).Find(&snapshot.Groups).Error; err != nil {
+			return fmt.Errorf("failed to load groups: %w", err)
+		}
+		groupIDs := make([]string, 0, len(snapshot.Groups))
+		for _, group := range snapshot.Groups {
+			groupIDs = append(groupIDs, group.ID)
+		}
+
+		if len(groupIDs) > 0 {
+			if err := tx.Where("group_id IN ?", groupIDs).Find(&snapshot.GroupMemberships).Error; err != nil {
+				return fmt.Errorf("failed to load group memberships: %w", err)
+			}
+			if err := tx.Where("parent_group_id IN ? OR child_group_id IN ?", groupIDs, groupIDs).
+				Find(&snapshot.GroupInheritance).Error; err != nil {
+				return fmt.Errorf("failed to load group inheritance: %w", err)
+			}
+		}
+		if err := tx.Where("organization_id = ?", orgID).Find(&snapshot.GroupRoles).Error; err != nil {
+			return fmt.Errorf("failed to load group roles: %w", err)
+		}
+		if err := tx.Where("organization_id = ?", orgID).Find(&snapshot.Members).Error; err != nil {
+			return fmt.Errorf("failed to load organization members: %w", err)
+		}
+		if err := tx.Where("organization_id = ?", orgID).Find(&snapshot.Settings).Error; err != nil {
+			return fmt.Errorf("failed to load organization settings: %w", err)
+		}
+
+		// Children before parents so no foreign key is left dangling
+		if len(groupIDs) > 0 {
+			if err := tx.Where("group_id IN ?", groupIDs).Delete(&models.GroupMembership{}).Error; err != nil {
+				return fmt.Errorf("failed to delete group memberships: %w", err)
+			}
+			if err := tx.Where("parent_group_id IN ? OR child_group_id IN ?", groupIDs, groupIDs).
+				Delete(&models.GroupInheritance{}).Error; err != nil {
+				return fmt.Errorf("failed to delete group inheritance: %w", err)
+			}
+		}
+		if err := tx.Where("organization_id = ?", orgID).Delete(&models.GroupRole{}).Error; err != nil {
+			return fmt.Errorf("failed to delete group roles: %w", err)
+		}
+		if err := tx.Where("organization_id = ?", orgID).Delete(&models.Group{}).Error; err != nil {
+			return fmt.Errorf("failed to delete groups: %w", err)
+		}
+		if err := tx.Where("organization_id = ?", orgID).Delete(&models.OrganizationMember{}).Error; err != nil {
+			return fmt.Errorf("failed to delete organization members: %w", err)
+		}
+		if err := tx.Where("organization_id = ?", orgID).Delete(&models.OrganizationSetting{}).Error; err != nil {
+			return fmt.Errorf("failed to delete organization settings: %w", err)
+		}
+		if err := tx.Where("id = ?", orgID).Delete(&models.Organization{}).Error; err != nil {
+			return fmt.Errorf("failed to delete organization: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
@@ -0,0 +1,115 @@
+package organizations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/repositories/softdelete"
+	"github.com/Kisanlink/kisanlink-db/pkg/base"
+	"github.com/Kisanlink/kisanlink-db/pkg/db"
+	"gorm.io/gorm"
+)
+
+// OrganizationMemberRepository handles database operations for direct user-organization memberships
+type OrganizationMemberRepository struct {
+	*base.BaseFilterableRepository[*models.OrganizationMember]
+	dbManager db.DBManager
+}
+
+// NewOrganizationMemberRepository creates a new OrganizationMemberRepository instance
+func NewOrganizationMemberRepository(dbManager db.DBManager) *OrganizationMemberRepository {
+	baseRepo := base.NewBaseFilterableRepository[*models.OrganizationMember]()
+	baseRepo.SetDBManager(dbManager)
+	return &OrganizationMemberRepository{
+		BaseFilterableRepository: baseRepo,
+		dbManager:                dbManager,
+	}
+}
+
+// Create adds a user to an organization
+func (r *OrganizationMemberRepository) Create(ctx context.Context, member *models.OrganizationMember) error {
+	return r.BaseFilterableRepository.Create(ctx, member)
+}
+
+// GetByOrganizationAndUser retrieves the live membership of a user in an organization
+func (r *OrganizationMemberRepository) GetByOrganizationAndUser(ctx context.Context, orgID, userID string) (*models.OrganizationMember, error) {
+	filter := softdelete.Live().
+		Where("organization_id", base.OpEqual, orgID).
+		Where("user_id", base.OpEqual, userID).
+		Build()
+
+	members, err := r.BaseFilterableRepository.Find(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get organization member: %w", err)
+	}
+
+	if len(members) == 0 {
+		return nil, fmt.Errorf("user %s is not a member of organization %s", userID, orgID)
+	}
+
+	return members[0], nil
+}
+
+// ListByOrganization retrieves the members of an organization, oldest first
+func (r *OrganizationMemberRepository) ListByOrganization(ctx context.Context, orgID string, limit, offset int) ([]*models.OrganizationMember, error) {
+	filter := softdelete.Live().
+		Where("organization_id", base.OpEqual, orgID).
+		Sort("created_at", "asc").
+		Limit(limit, offset).
+		Build()
+
+	members, err := r.BaseFilterableRepository.Find(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list organization members: %w", err)
+	}
+
+	return members, nil
+}
+
+// CountByOrganization returns the number of direct members of an organization
+func (r *OrganizationMemberRepository) CountByOrganization(ctx context.Context, orgID string) (int64, error) {
+	filter := softdelete.Live().
+		Where("organization_id", base.OpEqual, orgID).
+		Build()
+
+	count, err := r.BaseFilterableRepository.Count(ctx, filter, &models.OrganizationMember{})
+	if err != nil {
+		return 0, fmt.Errorf("failed to count organization members: %w", err)
+	}
+
+	return count, nil
+}
+
+// SoftDelete removes a membership while keeping it for audit history
+func (r *OrganizationMemberRepository) SoftDelete(ctx context.Context, id string, deletedBy string) error {
+	return r.BaseFilterableRepository.SoftDelete(ctx, id, deletedBy)
+}
+
+// CountActiveGroupMemberships returns how many active groups of the organization the user still belongs to
+func (r *OrganizationMemberRepository) CountActiveGroupMemberships(ctx context.Context, orgID, userID string) (int64, error) {
+	postgresMgr, ok := r.dbManager.(interface {
+		GetDB(context.Context, bool) (*gorm.DB, error)
+	})
+	if !ok {
+		return 0, fmt.Errorf("database manager does not support GetDB method")
+	}
+
+	gormDB, err := postgresMgr.GetDB(ctx, true)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	var count int64
+	err = gormDB.WithContext(ctx).
+		Table("group_memberships").
+		Joins("JOIN groups ON groups.id = group_memberships.group_id").
+		Where("groups.organization_id = ? AND groups.deleted_at IS NULL", orgID).
+		Where("group_memberships.principal_id = ? AND group_memberships.is_active = ? AND group_memberships.deleted_at IS NULL", userID, true).
+		Count(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to count group memberships in organization: %w", err)
+	}
+
+	return count, nil
+}
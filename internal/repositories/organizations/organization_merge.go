@@ -0,0 +1,530 @@
+package organizations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrOrganizationMergeCycle is returned by MergeInto when one organization is an ancestor of the other
+var ErrOrganizationMergeCycle = errors.New("organizations are in the same lineage")
+
+// ErrOrganizationMergeNotFound is returned by MergeInto when either organization is missing or deleted
+var ErrOrganizationMergeNotFound = errors.New("organization to merge not found")
+
+// ErrOrganizationMergeTooDeep is returned by MergeInto when reparenting the source's children under
+// the target would exceed the maximum hierarchy depth
+var ErrOrganizationMergeTooDeep = errors.New("merge would exceed the maximum organization hierarchy depth")
+
+// maxOrganizationDepth mirrors the depth limit enforced when organizations are created
+const maxOrganizationDepth = 10
+
+// mergedRecordTables lists the other organization-scoped tables whose rows simply move to the target
+var mergedRecordTables = []string{"principals", "services", "bindings", "column_groups", "attributes"}
+
+// memberRoleRank orders organization member roles by standing
+var memberRoleRank = map[string]int{
+	models.OrganizationMemberRoleMember: 1,
+	models.OrganizationMemberRoleAdmin:  2,
+	models.OrganizationMemberRoleOwner:  3,
+}
+
+// MergeInto merges the source organization into the target in one transaction and soft-deletes the
+// source. Conflicts are resolved as follows:
+//   - child organizations are reparented under the target, with their subtree's hierarchy paths
+//   - groups move to the target; a live group whose name the target already uses is renamed to
+//     "<name> (<source name>)"
+//   - members move to the target; a user who is already a member keeps the target membership,
+//     raised to the source membership's role when that ranks higher, and the source one is deleted
+//   - roles move to the target; a live role whose name the target already uses is folded into the
+//     target role: its user and group assignments are repointed, duplicates deactivated, and it is
+//     soft-deleted. The target role keeps its own permissions; those only the source role had are
+//     reported, not granted.
+//   - settings move to the target unless the target sets the same key, in which case the target
+//     value wins and the source setting stays with the source
+//   - role constraints move to the target with their roles remapped, and principals, services,
+//     bindings, column groups and attributes move to the target
+func (r *OrganizationRepository) MergeInto(ctx context.Context, sourceID, targetID, mergedBy string) (*models.OrganizationMergeSummary, error) {
+	db, err := r.getDB(ctx, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	summary := &models.OrganizationMergeSummary{
+		SourceID:            sourceID,
+		TargetID:            targetID,
+		RenamedGroups:       map[string]string{},
+		MergedRoles:         map[string]string{},
+		UnmergedPermissions: map[string][]string{},
+		MovedRecords:        map[string]int64{},
+	}
+	err = db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Lock in ID order so concurrent merges of the same pair cannot deadlock
+		var locked []*models.Organization
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id IN ? AND deleted_at IS NULL", []string{sourceID, targetID}).
+			Order("id").
+			Find(&locked).Error; err != nil {
+			return fmt.Errorf("failed to lock organizations: %w", err)
+		}
+		var source, target *models.Organization
+		for _, org := range locked {
+			if org.ID == sourceID {
+				source = org
+			} else {
+				target = org
+			}
+		}
+		if source == nil || target == nil {
+			return ErrOrganizationMergeNotFound
+		}
+
+		if err := checkMergeLineage(tx, source, target); err != nil {
+			return err
+		}
+		if err := mergeChildren(tx, source, target, mergedBy, summary); err != nil {
+			return err
+		}
+		if err := mergeGroups(tx, source, target, mergedBy, summary); err != nil {
+			return err
+		}
+		if err := mergeMembers(tx, source, target, mergedBy, summary); err != nil {
+			return err
+		}
+		roleMapping, err := mergeRoles(tx, source, target, mergedBy, summary)
+		if err != nil {
+			return err
+		}
+		if err := mergeRoleConstraints(tx, source, target, roleMapping); err != nil {
+			return err
+		}
+		if err := mergeSettings(tx, source, target, summary); err != nil {
+			return err
+		}
+
+		for _, table := range mergedRecordTables {
+			result := tx.Table(table).Where("organization_id = ?", source.ID).Update("organization_id", target.ID)
+			if result.Error != nil {
+				return fmt.Errorf("failed to move %s: %w", table, result.Error)
+			}
+			if result.RowsAffected > 0 {
+				summary.MovedRecords[table] = result.RowsAffected
+			}
+		}
+
+		if err := tx.Model(&models.Organization{}).
+			Where("id = ?", source.ID).
+			Updates(map[string]interface{}{
+				"is_active":  false,
+				"deleted_at": gorm.Expr("NOW()"),
+				"deleted_by": mergedBy,
+				"updated_by": mergedBy,
+				"updated_at": gorm.Expr("NOW()"),
+			}).Error; err != nil {
+			return fmt.Errorf("failed to delete source organization: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+// checkMergeLineage refuses a merge between an organization and one of its ancestors
+func checkMergeLineage(tx *gorm.DB, source, target *models.Organization) error {
+	for _, pair := range [][2]*models.Organization{{source, target}, {target, source}} {
+		descendant, ancestor := pair[0], pair[1]
+		visited := map[string]bool{}
+		for parentID := descendant.ParentID; parentID != nil && *parentID != ""; {
+			if *parentID == ancestor.ID {
+				return ErrOrganizationMergeCycle
+			}
+			if visited[*parentID] {
+				break
+			}
+			visited[*parentID] = true
+
+			var parent models.Organization
+			if err := tx.Select("id", "parent_id").Where("id = ?", *parentID).First(&parent).Error; err != nil {
+				return fmt.Errorf("failed to load ancestor organization %s: %w", *parentID, err)
+			}
+			parentID = parent.ParentID
+		}
+	}
+	return nil
+}
+
+func mergeChildren(tx *gorm.DB, source, target *models.Organization, mergedBy string, summary *models.OrganizationMergeSummary) error {
+	if err := tx.Model(&models.Organization{}).
+		Where("parent_id = ?", source.ID).
+		Order("id").
+		Pluck("id", &summary.ReparentedChildren).Error; err != nil {
+		return fmt.Errorf("failed to load child organizations: %w", err)
+	}
+	if len(summary.ReparentedChildren) == 0 {
+		return nil
+	}
+
+	// Paths are only maintained once the hierarchy migration has run
+	pathsMaintained := source.HierarchyPath != "" && target.HierarchyPath != ""
+	if pathsMaintained {
+		var deepest int
+		if err := tx.Model(&models.Organization{}).
+			Where("hierarchy_path LIKE ?", source.HierarchyPath+"/%").
+			Select("COALESCE(MAX(hierarchy_depth), 0)").
+			Scan(&deepest).Error; err != nil {
+			return fmt.Errorf("failed to measure child hierarchy: %w", err)
+		}
+		if deepest-source.HierarchyDepth+target.HierarchyDepth > maxOrganizationDepth {
+			return ErrOrganizationMergeTooDeep
+		}
+	}
+
+	if err := tx.Model(&models.Organization{}).
+		Where("parent_id = ?", source.ID).
+		Updates(map[string]interface{}{
+			"parent_id":  target.ID,
+			"updated_by": mergedBy,
+			"updated_at": gorm.Expr("NOW()"),
+		}).Error; err != nil {
+		return fmt.Errorf("failed to reparent child organizations: %w", err)
+	}
+
+	if !pathsMaintained {
+		return nil
+	}
+	if err := tx.Exec(
+		`UPDATE organizations SET hierarchy_path = ? || substr(hierarchy_path, ?), hierarchy_depth = hierarchy_depth + ?
+		 WHERE hierarchy_path LIKE ?`,
+		target.HierarchyPath, len(source.HierarchyPath)+1, target.HierarchyDepth-source.HierarchyDepth,
+		source.HierarchyPath+"/%",
+	).Error; err != nil {
+		return fmt.Errorf("failed to update descendant hierarchy paths: %w", err)
+	}
+	return nil
+}
+
+func mergeGroups(tx *gorm.DB, source, target *models.Organization, mergedBy string, summary *models.OrganizationMergeSummary) error {
+	var groups []*models.Group
+	if err := tx.Where("organization_id = ?", source.ID).Order("id").Find(&groups).Error; err != nil {
+		return fmt.Errorf("failed to load groups: %w", err)
+	}
+	if len(groups) == 0 {
+		return nil
+	}
+
+	var names []string
+	if err := tx.Model(&models.Group{}).
+		Where("organization_id = ? AND deleted_at IS NULL", target.ID).
+		Pluck("name", &names).Error; err != nil {
+		return fmt.Errorf("failed to load target group names: %w", err)
+	}
+	conflicting := make(map[string]bool, len(names))
+	for _, name := range names {
+		conflicting[name] = true
+	}
+	// New names must not collide with the target's groups nor with the source's own
+	taken := make(map[string]bool, len(names)+len(groups))
+	for _, name := range names {
+		taken[name] = true
+	}
+	for _, group := range groups {
+		if group.DeletedAt == nil {
+			taken[group.Name] = true
+		}
+	}
+
+	for _, group := range groups {
+		summary.MovedGroups = append(summary.MovedGroups, group.ID)
+		if group.DeletedAt != nil || !conflicting[group.Name] {
+			continue
+		}
+
+		renamed := mergedGroupName(group.Name, source.Name, taken)
+		if err := tx.Model(&models.Group{}).
+			Where("id = ?", group.ID).
+			Updates(map[string]interface{}{
+				"name":       renamed,
+				"updated_by": mergedBy,
+				"updated_at": gorm.Expr("NOW()"),
+			}).Error; err != nil {
+			return fmt.Errorf("failed to rename group %s: %w", group.ID, err)
+		}
+		taken[renamed] = true
+		summary.RenamedGroups[group.ID] = renamed
+	}
+
+	if err := tx.Model(&models.Group{}).
+		Where("organization_id = ?", source.ID).
+		Update("organization_id", target.ID).Error; err != nil {
+		return fmt.Errorf("failed to move groups: %w", err)
+	}
+	if err := tx.Model(&models.GroupRole{}).
+		Where("organization_id = ?", source.ID).
+		Update("organization_id", target.ID).Error; err != nil {
+		return fmt.Errorf("failed to move group roles: %w", err)
+	}
+	return nil
+}
+
+// mergedGroupName suffixes a group name with the source organization's name, and a counter if needed
+func mergedGroupName(name, sourceName string, taken map[string]bool) string {
+	const maxLength = 100
+	candidate := truncate(fmt.Sprintf("%s (%s)", name, sourceName), maxLength)
+	for i := 2; taken[candidate]; i++ {
+		candidate = truncate(fmt.Sprintf("%s (%s %d)", name, sourceName, i), maxLength)
+	}
+	return candidate
+}
+
+func truncate(s string, length int) string {
+	runes := []rune(s)
+	if len(runes) <= length {
+		return s
+	}
+	return string(runes[:length])
+}
+
+func mergeMembers(tx *gorm.DB, source, target *models.Organization, mergedBy string, summary *models.OrganizationMergeSummary) error {
+	var sourceMembers, targetMembers []*models.OrganizationMember
+	if err := tx.Where("organization_id = ? AND deleted_at IS NULL", source.ID).Order("user_id").Find(&sourceMembers).Error; err != nil {
+		return fmt.Errorf("failed to load source members: %w", err)
+	}
+	if len(sourceMembers) == 0 {
+		return nil
+	}
+	if err := tx.Where("organization_id = ? AND deleted_at IS NULL", target.ID).Find(&targetMembers).Error; err != nil {
+		return fmt.Errorf("failed to load target members: %w", err)
+	}
+	existing := make(map[string]*models.OrganizationMember, len(targetMembers))
+	for _, member := range targetMembers {
+		existing[member.UserID] = member
+	}
+
+	for _, member := range sourceMembers {
+		kept, ok := existing[member.UserID]
+		if !ok {
+			summary.MovedMembers = append(summary.MovedMembers, member.UserID)
+			continue
+		}
+		summary.MergedMembers = append(summary.MergedMembers, member.UserID)
+
+		if memberRoleRank[member.Role] > memberRoleRank[kept.Role] {
+			if err := tx.Model(&models.OrganizationMember{}).
+				Where("id = ?", kept.ID).
+				Updates(map[string]interface{}{
+					"role":       member.Role,
+					"updated_by": mergedBy,
+					"updated_at": gorm.Expr("NOW()"),
+				}).Error; err != nil {
+				return fmt.Errorf("failed to update membership of user %s: %w", member.UserID, err)
+			}
+		}
+		if err := tx.Model(&models.OrganizationMember{}).
+			Where("id = ?", member.ID).
+			Updates(map[string]interface{}{
+				"deleted_at": gorm.Expr("NOW()"),
+				"deleted_by": mergedBy,
+			}).Error; err != nil {
+			return fmt.Errorf("failed to delete duplicate membership of user %s: %w", member.UserID, err)
+		}
+	}
+
+	// Deleted memberships move too; the unique index only covers live ones
+	if err := tx.Model(&models.OrganizationMember{}).
+		Where("organization_id = ?", source.ID).
+		Update("organization_id", target.ID).Error; err != nil {
+		return fmt.Errorf("failed to move members: %w", err)
+	}
+	return nil
+}
+
+// mergeRoles moves or folds the source's roles and returns the mapping of folded role IDs
+func mergeRoles(tx *gorm.DB, source, target *models.Organization, mergedBy string, summary *models.OrganizationMergeSummary) (map[string]string, error) {
+	var sourceRoles, targetRoles []*models.Role
+	if err := tx.Where("organization_id = ? AND deleted_at IS NULL", source.ID).Order("id").Find(&sourceRoles).Error; err != nil {
+		return nil, fmt.Errorf("failed to load source roles: %w", err)
+	}
+	if len(sourceRoles) == 0 {
+		return nil, nil
+	}
+	if err := tx.Where("organization_id = ? AND deleted_at IS NULL", target.ID).Find(&targetRoles).Error; err != nil {
+		return nil, fmt.Errorf("failed to load target roles: %w", err)
+	}
+	byName := make(map[string]*models.Role, len(targetRoles))
+	for _, role := range targetRoles {
+		byName[role.Name] = role
+	}
+
+	mapping := map[string]string{}
+	for _, role := range sourceRoles {
+		kept, ok := byName[role.Name]
+		if !ok {
+			summary.MovedRoles = append(summary.MovedRoles, role.ID)
+			continue
+		}
+		mapping[role.ID] = kept.ID
+		summary.MergedRoles[role.ID] = kept.ID
+
+		if err := foldRole(tx, role.ID, kept.ID, mergedBy); err != nil {
+			return nil, err
+		}
+
+		unmerged, err := unmergedPermissions(tx, role.ID, kept.ID)
+		if err != nil {
+			return nil, err
+		}
+		if len(unmerged) > 0 {
+			summary.UnmergedPermissions[role.ID] = unmerged
+		}
+	}
+
+	// Role names are unique per service, so moved roles keep their service namespace
+	if err := tx.Model(&models.Role{}).
+		Where("organization_id = ? AND deleted_at IS NULL", source.ID).
+		Updates(map[string]interface{}{
+			"organization_id": target.ID,
+			"updated_by":      mergedBy,
+			"updated_at":      gorm.Expr("NOW()"),
+		}).Error; err != nil {
+		return nil, fmt.Errorf("failed to move roles: %w", err)
+	}
+	return mapping, nil
+}
+
+// foldRole repoints the assignments of a role to the role replacing it and soft-deletes it
+func foldRole(tx *gorm.DB, roleID, keptID, mergedBy string) error {
+	if err := tx.Model(&models.UserRole{}).
+		Where("role_id = ? AND is_active = ?", roleID, true).
+		Where("user_id IN (?)", tx.Model(&models.UserRole{}).
+			Select("user_id").
+			Where("role_id = ? AND is_active = ?", keptID, true)).
+		Update("is_active", false).Error; err != nil {
+		return fmt.Errorf("failed to deactivate duplicate user roles of %s: %w", roleID, err)
+	}
+	if err := tx.Model(&models.UserRole{}).
+		Where("role_id = ?", roleID).
+		Update("role_id", keptID).Error; err != nil {
+		return fmt.Errorf("failed to repoint user roles of %s: %w", roleID, err)
+	}
+
+	if err := tx.Model(&models.GroupRole{}).
+		Where("role_id = ? AND is_active = ?", roleID, true).
+		Where("group_id IN (?)", tx.Model(&models.GroupRole{}).
+			Select("group_id").
+			Where("role_id = ? AND is_active = ?", keptID, true)).
+		Update("is_active", false).Error; err != nil {
+		return fmt.Errorf("failed to deactivate duplicate group roles of %s: %w", roleID, err)
+	}
+	if err := tx.Model(&models.GroupRole{}).
+		Where("role_id = ?", roleID).
+		Update("role_id", keptID).Error; err != nil {
+		return fmt.Errorf("failed to repoint group roles of %s: %w", roleID, err)
+	}
+
+	if err := tx.Model(&models.Role{}).
+		Where("id = ?", roleID).
+		Updates(map[string]interface{}{
+			"is_active":  false,
+			"deleted_at": gorm.Expr("NOW()"),
+			"deleted_by": mergedBy,
+		}).Error; err != nil {
+		return fmt.Errorf("failed to delete merged role %s: %w", roleID, err)
+	}
+	return nil
+}
+
+// unmergedPermissions lists the active permissions of a role that the role replacing it lacks
+func unmergedPermissions(tx *gorm.DB, roleID, keptID string) ([]string, error) {
+	var permissionIDs []string
+	if err := tx.Model(&models.RolePermission{}).
+		Where("role_id = ? AND is_active = ?", roleID, true).
+		Where("permission_id NOT IN (?)", tx.Model(&models.RolePermission{}).
+			Select("permission_id").
+			Where("role_id = ? AND is_active = ?", keptID, true)).
+		Order("permission_id").
+		Pluck("permission_id", &permissionIDs).Error; err != nil {
+		return nil, fmt.Errorf("failed to compare permissions of %s: %w", roleID, err)
+	}
+	return permissionIDs, nil
+}
+
+func mergeRoleConstraints(tx *gorm.DB, source, target *models.Organization, roleMapping map[string]string) error {
+	var constraints []*models.RoleConstraint
+	if err := tx.Where("organization_id = ? AND deleted_at IS NULL", source.ID).Find(&constraints).Error; err != nil {
+		return fmt.Errorf("failed to load role constraints: %w", err)
+	}
+
+	for _, constraint := range constraints {
+		seen := map[string]bool{}
+		var roleIDs []string
+		for _, roleID := range constraint.GetRoleIDs() {
+			if kept, ok := roleMapping[roleID]; ok {
+				roleID = kept
+			}
+			if !seen[roleID] {
+				seen[roleID] = true
+				roleIDs = append(roleIDs, roleID)
+			}
+		}
+		if err := constraint.SetRoleIDs(roleIDs); err != nil {
+			return err
+		}
+		if err := tx.Model(&models.RoleConstraint{}).
+			Where("id = ?", constraint.ID).
+			Updates(map[string]interface{}{
+				"organization_id": target.ID,
+				"role_ids":        constraint.RoleIDs,
+			}).Error; err != nil {
+			return fmt.Errorf("failed to move role constraint %s: %w", constraint.ID, err)
+		}
+	}
+	return nil
+}
+
+func mergeSettings(tx *gorm.DB, source, target *models.Organization, summary *models.OrganizationMergeSummary) error {
+	var sourceKeys, targetKeys []string
+	if err := tx.Model(&models.OrganizationSetting{}).
+		Where("organization_id = ?", source.ID).
+		Pluck("key", &sourceKeys).Error; err != nil {
+		return fmt.Errorf("failed to load source settings: %w", err)
+	}
+	if len(sourceKeys) == 0 {
+		return nil
+	}
+	if err := tx.Model(&models.OrganizationSetting{}).
+		Where("organization_id = ?", target.ID).
+		Pluck("key", &targetKeys).Error; err != nil {
+		return fmt.Errorf("failed to load target settings: %w", err)
+	}
+	set := make(map[string]bool, len(targetKeys))
+	for _, key := range targetKeys {
+		set[key] = true
+	}
+
+	for _, key := range sourceKeys {
+		if set[key] {
+			summary.SkippedSettings = append(summary.SkippedSettings, key)
+		} else {
+			summary.MovedSettings = append(summary.MovedSettings, key)
+		}
+	}
+	sort.Strings(summary.MovedSettings)
+	sort.Strings(summary.SkippedSettings)
+
+	if len(summary.MovedSettings) == 0 {
+		return nil
+	}
+	if err := tx.Model(&models.OrganizationSetting{}).
+		Where("organization_id = ? AND key IN ?", source.ID, summary.MovedSettings).
+		Update("organization_id", target.ID).Error; err != nil {
+		return fmt.Errorf("failed to move settings: %w", err)
+	}
+	return nil
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 
 	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/repositories/softdelete"
+	"github.com/Kisanlink/aaa-service/v2/internal/repositories/sorting"
 	"github.com/Kisanlink/kisanlink-db/pkg/base"
 	"github.com/Kisanlink/kisanlink-db/pkg/db"
 )
@@ -49,8 +51,7 @@ func (r *OrganizationRepository) Delete(ctx context.Context, id string) error {
 
 // List retrieves organizations with pagination using database-level filtering
 func (r *OrganizationRepository) List(ctx context.Context, limit, offset int) ([]*models.Organization, error) {
-	filter := base.NewFilterBuilder().
-		Sort("id", "asc"). // Default sort by ID ascending
+	filter := sorting.Apply(ctx, softdelete.Live(), sorting.OrganizationFields).
 		Limit(limit, offset).
 		Build()
 
@@ -93,6 +94,25 @@ func (r *OrganizationRepository) ExistsWithDeleted(ctx context.Context, id strin
 	return r.BaseFilterableRepository.ExistsWithDeleted(ctx, id)
 }
 
+// ExistingIDs returns the IDs among ids that belong to a organization, in a single query. Soft-deleted
+// organizations count only when includeDeleted is set.
+func (r *OrganizationRepository) ExistingIDs(ctx context.Context, ids []string, includeDeleted bool) ([]string, error) {
+	db, err := r.getDB(ctx, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	query := db.Model(&models.Organization{}).Where("id IN ?", ids)
+	if !includeDeleted {
+		query = query.Where("deleted_at IS NULL")
+	}
+	var existing []string
+	if err := query.Pluck("id", &existing).Error; err != nil {
+		return nil, fmt.Errorf("failed to check organization existence: %w", err)
+	}
+	return existing, nil
+}
+
 // GetByCreatedBy gets organizations by creator using the base repository
 func (r *OrganizationRepository) GetByCreatedBy(ctx context.Context, createdBy string, limit, offset int) ([]*models.Organization, error) {
 	return r.BaseFilterableRepository.GetByCreatedBy(ctx, createdBy, limit, offset)
@@ -105,7 +125,7 @@ func (r *OrganizationRepository) GetByUpdatedBy(ctx context.Context, updatedBy s
 
 // GetByName retrieves an organization by name
 func (r *OrganizationRepository) GetByName(ctx context.Context, name string) (*models.Organization, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("name", base.OpEqual, name).
 		Build()
 
@@ -123,8 +143,9 @@ func (r *OrganizationRepository) GetByName(ctx context.Context, name string) (*m
 
 // GetByType retrieves organizations by type
 func (r *OrganizationRepository) GetByType(ctx context.Context, orgType string, limit, offset int) ([]*models.Organization, error) {
-	filter := base.NewFilterBuilder().
-		Where("type", base.OpEqual, orgType).
+	fb := softdelete.Live().
+		Where("type", base.OpEqual, orgType)
+	filter := sorting.Apply(ctx, fb, sorting.OrganizationFields).
 		Limit(limit, offset).
 		Build()
 
@@ -133,8 +154,9 @@ func (r *OrganizationRepository) GetByType(ctx context.Context, orgType string,
 
 // ListActive retrieves only active organizations
 func (r *OrganizationRepository) ListActive(ctx context.Context, limit, offset int) ([]*models.Organization, error) {
-	filter := base.NewFilterBuilder().
-		Where("is_active", base.OpEqual, true).
+	fb := softdelete.Live().
+		Where("is_active", base.OpEqual, true)
+	filter := sorting.Apply(ctx, fb, sorting.OrganizationFields).
 		Limit(limit, offset).
 		Build()
 
@@ -143,7 +165,7 @@ func (r *OrganizationRepository) ListActive(ctx context.Context, limit, offset i
 
 // CountActive returns the count of active organizations
 func (r *OrganizationRepository) CountActive(ctx context.Context) (int64, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("is_active", base.OpEqual, true).
 		Build()
 
@@ -152,7 +174,7 @@ func (r *OrganizationRepository) CountActive(ctx context.Context) (int64, error)
 
 // CountByType returns the count of organizations by type
 func (r *OrganizationRepository) CountByType(ctx context.Context, orgType string) (int64, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("type", base.OpEqual, orgType).
 		Build()
 
@@ -161,7 +183,7 @@ func (r *OrganizationRepository) CountByType(ctx context.Context, orgType string
 
 // GetChildren retrieves all child organizations
 func (r *OrganizationRepository) GetChildren(ctx context.Context, parentID string) ([]*models.Organization, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("parent_id", base.OpEqual, parentID).
 		Build()
 
@@ -170,7 +192,7 @@ func (r *OrganizationRepository) GetChildren(ctx context.Context, parentID strin
 
 // GetActiveChildren retrieves only active child organizations
 func (r *OrganizationRepository) GetActiveChildren(ctx context.Context, parentID string) ([]*models.Organization, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("parent_id", base.OpEqual, parentID).
 		Where("is_active", base.OpEqual, true).
 		Build()
@@ -203,7 +225,7 @@ func (r *OrganizationRepository) GetParentHierarchy(ctx context.Context, orgID s
 
 // CountChildren returns the number of child organizations
 func (r *OrganizationRepository) CountChildren(ctx context.Context, parentID string) (int64, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("parent_id", base.OpEqual, parentID).
 		Build()
 
@@ -217,11 +239,10 @@ func (r *OrganizationRepository) CountGroups(ctx context.Context, orgID string)
 	return 0, nil
 }
 
-// CountUsers returns the number of users in an organization
+// CountUsers returns the number of direct members of an organization.
+// Users who only reach the organization through group membership are not counted.
 func (r *OrganizationRepository) CountUsers(ctx context.Context, orgID string) (int64, error) {
-	// This would need to be implemented based on your user-organization relationship
-	// For now, returning 0 as placeholder
-	return 0, nil
+	return NewOrganizationMemberRepository(r.dbManager).CountByOrganization(ctx, orgID)
 }
 
 // HasActiveGroups checks if an organization has active groups
@@ -237,8 +258,9 @@ func (r *OrganizationRepository) Search(ctx context.Context, keyword string, lim
 		return r.List(ctx, limit, offset)
 	}
 
-	filter := base.NewFilterBuilder().
-		Where("name", base.OpContains, "%"+keyword+"%").
+	fb := softdelete.Live().
+		Where("name", base.OpContains, "%"+keyword+"%")
+	filter := sorting.Apply(ctx, fb, sorting.OrganizationFields).
 		Limit(limit, offset).
 		Build()
 
@@ -247,8 +269,9 @@ func (r *OrganizationRepository) Search(ctx context.Context, keyword string, lim
 
 // GetByStatus retrieves organizations by active status
 func (r *OrganizationRepository) GetByStatus(ctx context.Context, isActive bool, limit, offset int) ([]*models.Organization, error) {
-	filter := base.NewFilterBuilder().
-		Where("is_active", base.OpEqual, isActive).
+	fb := softdelete.Live().
+		Where("is_active", base.OpEqual, isActive)
+	filter := sorting.Apply(ctx, fb, sorting.OrganizationFields).
 		Limit(limit, offset).
 		Build()
 
@@ -257,7 +280,7 @@ func (r *OrganizationRepository) GetByStatus(ctx context.Context, isActive bool,
 
 // GetRootOrganizations retrieves organizations without parents
 func (r *OrganizationRepository) GetRootOrganizations(ctx context.Context, limit, offset int) ([]*models.Organization, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("parent_id", base.OpIsNull, nil).
 		Limit(limit, offset).
 		Build()
@@ -0,0 +1,95 @@
+package organizations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/repositories/softdelete"
+	"github.com/Kisanlink/kisanlink-db/pkg/base"
+	"github.com/Kisanlink/kisanlink-db/pkg/db"
+)
+
+// OrganizationSettingRepository handles database operations for per-organization settings
+type OrganizationSettingRepository struct {
+	*base.BaseFilterableRepository[*models.OrganizationSetting]
+	dbManager db.DBManager
+}
+
+// NewOrganizationSettingRepository creates a new OrganizationSettingRepository instance
+func NewOrganizationSettingRepository(dbManager db.DBManager) *OrganizationSettingRepository {
+	baseRepo := base.NewBaseFilterableRepository[*models.OrganizationSetting]()
+	baseRepo.SetDBManager(dbManager)
+	return &OrganizationSettingRepository{
+		BaseFilterableRepository: baseRepo,
+		dbManager:                dbManager,
+	}
+}
+
+// ListByOrganization retrieves every setting stored for an organization
+func (r *OrganizationSettingRepository) ListByOrganization(ctx context.Context, orgID string) ([]*models.OrganizationSetting, error) {
+	filter := softdelete.Live().
+		Where("organization_id", base.OpEqual, orgID).
+		Sort("key", "asc").
+		Build()
+
+	settings, err := r.BaseFilterableRepository.Find(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list organization settings: %w", err)
+	}
+
+	return settings, nil
+}
+
+// Upsert stores the value of a setting, replacing any previous value for the same key
+func (r *OrganizationSettingRepository) Upsert(ctx context.Context, setting *models.OrganizationSetting) error {
+	existing, err := r.getByOrganizationAndKey(ctx, setting.OrganizationID, setting.Key)
+	if err != nil {
+		return err
+	}
+
+	if existing == nil {
+		if err := r.BaseFilterableRepository.Create(ctx, setting); err != nil {
+			return fmt.Errorf("failed to create organization setting: %w", err)
+		}
+		return nil
+	}
+
+	existing.Value = setting.Value
+	existing.UpdatedBy = setting.UpdatedBy
+	if err := r.BaseFilterableRepository.Update(ctx, existing); err != nil {
+		return fmt.Errorf("failed to update organization setting: %w", err)
+	}
+	*setting = *existing
+	return nil
+}
+
+// DeleteByOrganizationAndKey removes a stored setting so the organization falls back to the default.
+// Deleting a setting that is not stored is not an error.
+func (r *OrganizationSettingRepository) DeleteByOrganizationAndKey(ctx context.Context, orgID, key string) error {
+	existing, err := r.getByOrganizationAndKey(ctx, orgID, key)
+	if err != nil || existing == nil {
+		return err
+	}
+
+	if err := r.BaseFilterableRepository.Delete(ctx, existing.ID, existing); err != nil {
+		return fmt.Errorf("failed to delete organization setting: %w", err)
+	}
+	return nil
+}
+
+func (r *OrganizationSettingRepository) getByOrganizationAndKey(ctx context.Context, orgID, key string) (*models.OrganizationSetting, error) {
+	filter := softdelete.Live().
+		Where("organization_id", base.OpEqual, orgID).
+		Where("key", base.OpEqual, key).
+		Build()
+
+	settings, err := r.BaseFilterableRepository.Find(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get organization setting: %w", err)
+	}
+	if len(settings) == 0 {
+		return nil, nil
+	}
+	return settings[0], nil
+}
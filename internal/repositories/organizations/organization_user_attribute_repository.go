@@ -0,0 +1,111 @@
+package organizations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/repositories/softdelete"
+	"github.com/Kisanlink/kisanlink-db/pkg/base"
+	"github.com/Kisanlink/kisanlink-db/pkg/db"
+)
+
+// OrganizationUserAttributeRepository handles database operations for the custom attributes
+// organizations keep on their users, and the schemas they validate them against
+type OrganizationUserAttributeRepository struct {
+	schemas    *base.BaseFilterableRepository[*models.OrganizationUserAttributeSchema]
+	attributes *base.BaseFilterableRepository[*models.OrganizationUserAttributes]
+	dbManager  db.DBManager
+}
+
+// NewOrganizationUserAttributeRepository creates a new OrganizationUserAttributeRepository instance
+func NewOrganizationUserAttributeRepository(dbManager db.DBManager) *OrganizationUserAttributeRepository {
+	schemas := base.NewBaseFilterableRepository[*models.OrganizationUserAttributeSchema]()
+	schemas.SetDBManager(dbManager)
+	attributes := base.NewBaseFilterableRepository[*models.OrganizationUserAttributes]()
+	attributes.SetDBManager(dbManager)
+	return &OrganizationUserAttributeRepository{
+		schemas:    schemas,
+		attributes: attributes,
+		dbManager:  dbManager,
+	}
+}
+
+// GetSchema returns the user attribute schema of an organization, or nil when it has none
+func (r *OrganizationUserAttributeRepository) GetSchema(ctx context.Context, orgID string) (*models.OrganizationUserAttributeSchema, error) {
+	filter := softdelete.Live().
+		Where("organization_id", base.OpEqual, orgID).
+		Build()
+
+	schemas, err := r.schemas.Find(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user attribute schema: %w", err)
+	}
+	if len(schemas) == 0 {
+		return nil, nil
+	}
+	return schemas[0], nil
+}
+
+// UpsertSchema stores the user attribute schema of an organization, replacing any previous one
+func (r *OrganizationUserAttributeRepository) UpsertSchema(ctx context.Context, schema *models.OrganizationUserAttributeSchema) error {
+	existing, err := r.GetSchema(ctx, schema.OrganizationID)
+	if err != nil {
+		return err
+	}
+
+	if existing == nil {
+		if err := r.schemas.Create(ctx, schema); err != nil {
+			return fmt.Errorf("failed to create user attribute schema: %w", err)
+		}
+		return nil
+	}
+
+	existing.Attributes = schema.Attributes
+	existing.UpdatedBy = schema.UpdatedBy
+	if err := r.schemas.Update(ctx, existing); err != nil {
+		return fmt.Errorf("failed to update user attribute schema: %w", err)
+	}
+	*schema = *existing
+	return nil
+}
+
+// GetAttributes returns the attributes an organization keeps on a user, or nil when it has set none
+func (r *OrganizationUserAttributeRepository) GetAttributes(ctx context.Context, orgID, userID string) (*models.OrganizationUserAttributes, error) {
+	filter := softdelete.Live().
+		Where("organization_id", base.OpEqual, orgID).
+		Where("user_id", base.OpEqual, userID).
+		Build()
+
+	attributes, err := r.attributes.Find(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user attributes: %w", err)
+	}
+	if len(attributes) == 0 {
+		return nil, nil
+	}
+	return attributes[0], nil
+}
+
+// UpsertAttributes stores the attributes an organization keeps on a user, replacing the previous ones
+func (r *OrganizationUserAttributeRepository) UpsertAttributes(ctx context.Context, attributes *models.OrganizationUserAttributes) error {
+	existing, err := r.GetAttributes(ctx, attributes.OrganizationID, attributes.UserID)
+	if err != nil {
+		return err
+	}
+
+	if existing == nil {
+		if err := r.attributes.Create(ctx, attributes); err != nil {
+			return fmt.Errorf("failed to create user attributes: %w", err)
+		}
+		return nil
+	}
+
+	existing.Attributes = attributes.Attributes
+	existing.UpdatedBy = attributes.UpdatedBy
+	if err := r.attributes.Update(ctx, existing); err != nil {
+		return fmt.Errorf("failed to update user attributes: %w", err)
+	}
+	*attributes = *existing
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/repositories/softdelete"
 	"github.com/Kisanlink/kisanlink-db/pkg/base"
 )
 
@@ -53,7 +54,7 @@ func (r *PermissionRepository) CreateBatch(ctx context.Context, permissions []*m
 
 // ExistsByName checks if a permission exists with the given name
 func (r *PermissionRepository) ExistsByName(ctx context.Context, name string) (bool, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("name", base.OpEqual, name).
 		Build()
 
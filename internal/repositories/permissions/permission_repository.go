@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/repositories/softdelete"
 	"github.com/Kisanlink/kisanlink-db/pkg/base"
 	"github.com/Kisanlink/kisanlink-db/pkg/db"
 )
@@ -58,7 +59,7 @@ func (r *PermissionRepository) Restore(ctx context.Context, id string) error {
 
 // List retrieves permissions with pagination
 func (r *PermissionRepository) List(ctx context.Context, limit, offset int) ([]*models.Permission, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Sort("id", "asc"). // Default sort by ID ascending
 		Limit(limit, offset).
 		Build()
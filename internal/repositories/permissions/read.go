@@ -5,12 +5,13 @@ import (
 	"fmt"
 
 	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/repositories/softdelete"
 	"github.com/Kisanlink/kisanlink-db/pkg/base"
 )
 
 // GetByName retrieves a permission by name
 func (r *PermissionRepository) GetByName(ctx context.Context, name string) (*models.Permission, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("name", base.OpEqual, name).
 		Build()
 
@@ -28,7 +29,7 @@ func (r *PermissionRepository) GetByName(ctx context.Context, name string) (*mod
 
 // GetByResourceID retrieves permissions by resource ID
 func (r *PermissionRepository) GetByResourceID(ctx context.Context, resourceID string, limit, offset int) ([]*models.Permission, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("resource_id", base.OpEqual, resourceID).
 		Limit(limit, offset).
 		Build()
@@ -38,7 +39,7 @@ func (r *PermissionRepository) GetByResourceID(ctx context.Context, resourceID s
 
 // GetByActionID retrieves permissions by action ID
 func (r *PermissionRepository) GetByActionID(ctx context.Context, actionID string, limit, offset int) ([]*models.Permission, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("action_id", base.OpEqual, actionID).
 		Limit(limit, offset).
 		Build()
@@ -48,7 +49,7 @@ func (r *PermissionRepository) GetByActionID(ctx context.Context, actionID strin
 
 // GetByResourceAndAction retrieves a permission by resource and action IDs
 func (r *PermissionRepository) GetByResourceAndAction(ctx context.Context, resourceID, actionID string) (*models.Permission, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("resource_id", base.OpEqual, resourceID).
 		Where("action_id", base.OpEqual, actionID).
 		Build()
@@ -67,7 +68,7 @@ func (r *PermissionRepository) GetByResourceAndAction(ctx context.Context, resou
 
 // GetActive retrieves all active permissions with pagination
 func (r *PermissionRepository) GetActive(ctx context.Context, limit, offset int) ([]*models.Permission, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("is_active", base.OpEqual, true).
 		Limit(limit, offset).
 		Build()
@@ -77,7 +78,7 @@ func (r *PermissionRepository) GetActive(ctx context.Context, limit, offset int)
 
 // Search searches permissions by name using LIKE operator
 func (r *PermissionRepository) Search(ctx context.Context, query string, limit, offset int) ([]*models.Permission, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("name", base.OpContains, query).
 		Limit(limit, offset).
 		Build()
@@ -92,7 +93,7 @@ func (r *PermissionRepository) FindByFilter(ctx context.Context, filter *base.Fi
 
 // CountByResourceID returns the count of permissions for a specific resource
 func (r *PermissionRepository) CountByResourceID(ctx context.Context, resourceID string) (int64, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("resource_id", base.OpEqual, resourceID).
 		Build()
 
@@ -101,7 +102,7 @@ func (r *PermissionRepository) CountByResourceID(ctx context.Context, resourceID
 
 // CountByActionID returns the count of permissions for a specific action
 func (r *PermissionRepository) CountByActionID(ctx context.Context, actionID string) (int64, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("action_id", base.OpEqual, actionID).
 		Build()
 
@@ -110,7 +111,7 @@ func (r *PermissionRepository) CountByActionID(ctx context.Context, actionID str
 
 // GetActiveByResource retrieves active permissions for a specific resource
 func (r *PermissionRepository) GetActiveByResource(ctx context.Context, resourceID string, limit, offset int) ([]*models.Permission, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("resource_id", base.OpEqual, resourceID).
 		Where("is_active", base.OpEqual, true).
 		Limit(limit, offset).
@@ -2,8 +2,12 @@ package principals
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/internal/repositories/softdelete"
 	"github.com/Kisanlink/kisanlink-db/pkg/base"
 	"github.com/Kisanlink/kisanlink-db/pkg/db"
 )
@@ -71,7 +75,7 @@ func (r *PrincipalRepository) SoftDelete(ctx context.Context, id string, deleted
 
 // GetByUserID retrieves a principal by user ID
 func (r *PrincipalRepository) GetByUserID(ctx context.Context, userID string) (*models.Principal, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("user_id", base.OpEqual, userID).
 		Where("is_active", base.OpEqual, true).
 		Build()
@@ -90,7 +94,7 @@ func (r *PrincipalRepository) GetByUserID(ctx context.Context, userID string) (*
 
 // GetByServiceID retrieves a principal by service ID
 func (r *PrincipalRepository) GetByServiceID(ctx context.Context, serviceID string) (*models.Principal, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("service_id", base.OpEqual, serviceID).
 		Where("is_active", base.OpEqual, true).
 		Build()
@@ -109,7 +113,7 @@ func (r *PrincipalRepository) GetByServiceID(ctx context.Context, serviceID stri
 
 // GetByType retrieves principals by type with pagination
 func (r *PrincipalRepository) GetByType(ctx context.Context, principalType string, limit, offset int) ([]*models.Principal, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("type", base.OpEqual, principalType).
 		Where("is_active", base.OpEqual, true).
 		Limit(limit, offset).
@@ -120,7 +124,7 @@ func (r *PrincipalRepository) GetByType(ctx context.Context, principalType strin
 
 // GetByOrganization retrieves principals by organization with pagination
 func (r *PrincipalRepository) GetByOrganization(ctx context.Context, organizationID string, limit, offset int) ([]*models.Principal, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("organization_id", base.OpEqual, organizationID).
 		Where("is_active", base.OpEqual, true).
 		Limit(limit, offset).
@@ -131,7 +135,7 @@ func (r *PrincipalRepository) GetByOrganization(ctx context.Context, organizatio
 
 // List retrieves principals with pagination using database-level filtering
 func (r *PrincipalRepository) List(ctx context.Context, limit, offset int) ([]*models.Principal, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Sort("id", "asc"). // Default sort by ID ascending
 		Limit(limit, offset).
 		Build()
@@ -141,7 +145,7 @@ func (r *PrincipalRepository) List(ctx context.Context, limit, offset int) ([]*m
 
 // ListActive retrieves only active principals with pagination
 func (r *PrincipalRepository) ListActive(ctx context.Context, limit, offset int) ([]*models.Principal, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("is_active", base.OpEqual, true).
 		Sort("id", "asc"). // Default sort by ID ascending
 		Limit(limit, offset).
@@ -158,7 +162,7 @@ func (r *PrincipalRepository) Count(ctx context.Context) (int64, error) {
 
 // CountActive returns the count of active principals
 func (r *PrincipalRepository) CountActive(ctx context.Context) (int64, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("is_active", base.OpEqual, true).
 		Build()
 	return r.BaseFilterableRepository.CountWithFilter(ctx, filter)
@@ -166,7 +170,7 @@ func (r *PrincipalRepository) CountActive(ctx context.Context) (int64, error) {
 
 // CountByType returns the count of principals by type
 func (r *PrincipalRepository) CountByType(ctx context.Context, principalType string) (int64, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("type", base.OpEqual, principalType).
 		Where("is_active", base.OpEqual, true).
 		Build()
@@ -175,7 +179,7 @@ func (r *PrincipalRepository) CountByType(ctx context.Context, principalType str
 
 // CountByOrganization returns the count of principals by organization
 func (r *PrincipalRepository) CountByOrganization(ctx context.Context, organizationID string) (int64, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("organization_id", base.OpEqual, organizationID).
 		Where("is_active", base.OpEqual, true).
 		Build()
@@ -184,7 +188,7 @@ func (r *PrincipalRepository) CountByOrganization(ctx context.Context, organizat
 
 // CountByTypeAndOrganization returns the count of principals by type and organization
 func (r *PrincipalRepository) CountByTypeAndOrganization(ctx context.Context, principalType, organizationID string) (int64, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("type", base.OpEqual, principalType).
 		Where("organization_id", base.OpEqual, organizationID).
 		Where("is_active", base.OpEqual, true).
@@ -228,7 +232,7 @@ func (r *ServiceRepository) SoftDelete(ctx context.Context, id string, deletedBy
 
 // GetByName retrieves a service by name
 func (r *ServiceRepository) GetByName(ctx context.Context, name string) (*models.Service, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("name", base.OpEqual, name).
 		Where("is_active", base.OpEqual, true).
 		Build()
@@ -247,7 +251,7 @@ func (r *ServiceRepository) GetByName(ctx context.Context, name string) (*models
 
 // GetByOrganization retrieves services by organization with pagination
 func (r *ServiceRepository) GetByOrganization(ctx context.Context, organizationID string, limit, offset int) ([]*models.Service, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("organization_id", base.OpEqual, organizationID).
 		Where("is_active", base.OpEqual, true).
 		Limit(limit, offset).
@@ -256,9 +260,10 @@ func (r *ServiceRepository) GetByOrganization(ctx context.Context, organizationI
 	return r.BaseFilterableRepository.Find(ctx, filter)
 }
 
-// GetByAPIKey retrieves a service by API key hash
+// GetByAPIKey retrieves a service by API key hash. During the grace period after a rotation the
+// replaced key also matches, so dependent services can switch to the new key without downtime.
 func (r *ServiceRepository) GetByAPIKey(ctx context.Context, apiKeyHash string) (*models.Service, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("api_key", base.OpEqual, apiKeyHash).
 		Where("is_active", base.OpEqual, true).
 		Build()
@@ -268,6 +273,21 @@ func (r *ServiceRepository) GetByAPIKey(ctx context.Context, apiKeyHash string)
 		return nil, err
 	}
 
+	if len(services) > 0 {
+		return services[0], nil
+	}
+
+	filter = softdelete.Live().
+		Where("previous_api_key", base.OpEqual, apiKeyHash).
+		Where("previous_api_key_expires_at", base.OpGreaterThan, time.Now()).
+		Where("is_active", base.OpEqual, true).
+		Build()
+
+	services, err = r.BaseFilterableRepository.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
 	if len(services) == 0 {
 		return nil, nil
 	}
@@ -277,7 +297,7 @@ func (r *ServiceRepository) GetByAPIKey(ctx context.Context, apiKeyHash string)
 
 // List retrieves services with pagination using database-level filtering
 func (r *ServiceRepository) List(ctx context.Context, limit, offset int) ([]*models.Service, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Sort("id", "asc"). // Default sort by ID ascending
 		Limit(limit, offset).
 		Build()
@@ -287,7 +307,7 @@ func (r *ServiceRepository) List(ctx context.Context, limit, offset int) ([]*mod
 
 // ListActive retrieves only active services with pagination
 func (r *ServiceRepository) ListActive(ctx context.Context, limit, offset int) ([]*models.Service, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("is_active", base.OpEqual, true).
 		Sort("id", "asc"). // Default sort by ID ascending
 		Limit(limit, offset).
@@ -296,6 +316,36 @@ func (r *ServiceRepository) ListActive(ctx context.Context, limit, offset int) (
 	return r.BaseFilterableRepository.Find(ctx, filter)
 }
 
+// ListFiltered retrieves a page of the services matching filter, sorted by name, and the total
+// number of matches. The last-used fields of the filter are not applied here. A limit of 0
+// returns every match.
+func (r *ServiceRepository) ListFiltered(ctx context.Context, filter interfaces.ServiceFilter, limit, offset int) ([]*models.Service, int64, error) {
+	fb := softdelete.Live()
+	if filter.OrganizationID != "" {
+		fb.Where("organization_id", base.OpEqual, filter.OrganizationID)
+	}
+	if filter.NameContains != "" {
+		fb.Where("name", base.OpContains, filter.NameContains)
+	}
+	if filter.IsActive != nil {
+		fb.Where("is_active", base.OpEqual, *filter.IsActive)
+	}
+
+	total, err := r.BaseFilterableRepository.CountWithFilter(ctx, fb.Build())
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count services: %w", err)
+	}
+
+	services, err := r.BaseFilterableRepository.Find(ctx, fb.
+		Sort("name", "asc").
+		Limit(limit, offset).
+		Build())
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list services: %w", err)
+	}
+	return services, total, nil
+}
+
 // Count returns the total number of services using database-level counting
 func (r *ServiceRepository) Count(ctx context.Context) (int64, error) {
 	filter := base.NewFilter()
@@ -304,7 +354,7 @@ func (r *ServiceRepository) Count(ctx context.Context) (int64, error) {
 
 // CountActive returns the count of active services
 func (r *ServiceRepository) CountActive(ctx context.Context) (int64, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("is_active", base.OpEqual, true).
 		Build()
 	return r.BaseFilterableRepository.CountWithFilter(ctx, filter)
@@ -312,7 +362,7 @@ func (r *ServiceRepository) CountActive(ctx context.Context) (int64, error) {
 
 // CountByOrganization returns the count of services by organization
 func (r *ServiceRepository) CountByOrganization(ctx context.Context, organizationID string) (int64, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("organization_id", base.OpEqual, organizationID).
 		Where("is_active", base.OpEqual, true).
 		Build()
@@ -328,7 +378,7 @@ func (r *ServiceRepository) Exists(ctx context.Context, id string) (bool, error)
 
 // GetByName retrieves a principal by name
 func (r *PrincipalRepository) GetByName(ctx context.Context, name string) (*models.Principal, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("name", base.OpEqual, name).
 		Where("is_active", base.OpEqual, true).
 		Build()
@@ -347,7 +397,7 @@ func (r *PrincipalRepository) GetByName(ctx context.Context, name string) (*mode
 
 // GetByNameAndOrganization retrieves a principal by name within a specific organization
 func (r *PrincipalRepository) GetByNameAndOrganization(ctx context.Context, name, organizationID string) (*models.Principal, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("name", base.OpEqual, name).
 		Where("organization_id", base.OpEqual, organizationID).
 		Where("is_active", base.OpEqual, true).
@@ -367,7 +417,7 @@ func (r *PrincipalRepository) GetByNameAndOrganization(ctx context.Context, name
 
 // SearchPrincipals searches principals by keyword with pagination
 func (r *PrincipalRepository) SearchPrincipals(ctx context.Context, keyword string, limit, offset int) ([]*models.Principal, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("name", base.OpLike, "%"+keyword+"%").
 		Where("is_active", base.OpEqual, true).
 		Limit(limit, offset).
@@ -378,7 +428,7 @@ func (r *PrincipalRepository) SearchPrincipals(ctx context.Context, keyword stri
 
 // GetPrincipalsByTypeAndOrganization retrieves principals by type and organization
 func (r *PrincipalRepository) GetPrincipalsByTypeAndOrganization(ctx context.Context, principalType, organizationID string, limit, offset int) ([]*models.Principal, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("type", base.OpEqual, principalType).
 		Where("organization_id", base.OpEqual, organizationID).
 		Where("is_active", base.OpEqual, true).
@@ -392,7 +442,7 @@ func (r *PrincipalRepository) GetPrincipalsByTypeAndOrganization(ctx context.Con
 func (r *PrincipalRepository) GetPrincipalsWithMetadata(ctx context.Context, metadataKey, metadataValue string, limit, offset int) ([]*models.Principal, error) {
 	// This would need JSONB query support in the base repository
 	// For now, we'll use a simple approach
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("metadata", base.OpLike, "%"+metadataKey+"%").
 		Where("is_active", base.OpEqual, true).
 		Limit(limit, offset).
@@ -405,7 +455,7 @@ func (r *PrincipalRepository) GetPrincipalsWithMetadata(ctx context.Context, met
 
 // SearchServices searches services by keyword with pagination
 func (r *ServiceRepository) SearchServices(ctx context.Context, keyword string, limit, offset int) ([]*models.Service, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("name", base.OpLike, "%"+keyword+"%").
 		Where("is_active", base.OpEqual, true).
 		Limit(limit, offset).
@@ -418,7 +468,7 @@ func (r *ServiceRepository) SearchServices(ctx context.Context, keyword string,
 func (r *ServiceRepository) GetServicesByMetadata(ctx context.Context, metadataKey, metadataValue string, limit, offset int) ([]*models.Service, error) {
 	// This would need JSONB query support in the base repository
 	// For now, we'll use a simple approach
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("metadata", base.OpLike, "%"+metadataKey+"%").
 		Where("is_active", base.OpEqual, true).
 		Limit(limit, offset).
@@ -429,7 +479,7 @@ func (r *ServiceRepository) GetServicesByMetadata(ctx context.Context, metadataK
 
 // GetServicesByStatus retrieves services by active status
 func (r *ServiceRepository) GetServicesByStatus(ctx context.Context, isActive bool, limit, offset int) ([]*models.Service, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("is_active", base.OpEqual, isActive).
 		Limit(limit, offset).
 		Build()
@@ -491,7 +541,7 @@ func (r *ServiceRepository) BulkUpdateServices(ctx context.Context, services []*
 
 // IsNameUnique checks if a principal name is unique within an organization
 func (r *PrincipalRepository) IsNameUnique(ctx context.Context, name, organizationID string, excludeID string) (bool, error) {
-	filterBuilder := base.NewFilterBuilder().
+	filterBuilder := softdelete.Live().
 		Where("name", base.OpEqual, name).
 		Where("organization_id", base.OpEqual, organizationID).
 		Where("is_active", base.OpEqual, true)
@@ -512,7 +562,7 @@ func (r *PrincipalRepository) IsNameUnique(ctx context.Context, name, organizati
 
 // IsServiceNameUnique checks if a service name is unique within an organization
 func (r *ServiceRepository) IsServiceNameUnique(ctx context.Context, name, organizationID string, excludeID string) (bool, error) {
-	filterBuilder := base.NewFilterBuilder().
+	filterBuilder := softdelete.Live().
 		Where("name", base.OpEqual, name).
 		Where("organization_id", base.OpEqual, organizationID).
 		Where("is_active", base.OpEqual, true)
@@ -588,7 +638,7 @@ func (r *ServiceRepository) GetServiceStats(ctx context.Context, organizationID
 	stats["total_count"] = totalCount
 
 	// Active count
-	activeFilter := base.NewFilterBuilder().
+	activeFilter := softdelete.Live().
 		Where("is_active", base.OpEqual, true).
 		Build()
 	activeCount, err := r.BaseFilterableRepository.CountWithFilter(ctx, activeFilter)
@@ -0,0 +1,85 @@
+// Package querytimeout bounds how long a single database statement may run. Repositories run
+// their queries with the request context, so a client disconnect aborts the query; the timeout
+// also bounds statements whose context has no deadline, such as background jobs, and slow queries
+// of requests that are still waiting. A context with an earlier deadline keeps it.
+package querytimeout
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	settingKey    = "aaa:query_timeout"
+	startCallback = "aaa:query_timeout_start"
+	endCallback   = "aaa:query_timeout_end"
+)
+
+// deadline is the per-statement state kept between the start and end callbacks
+type deadline struct {
+	parent context.Context
+	cancel context.CancelFunc
+}
+
+// Register bounds every statement run through gormDB by timeout. A timeout of zero or less
+// leaves statements unbounded.
+func Register(gormDB *gorm.DB, timeout time.Duration) error {
+	if timeout <= 0 {
+		return nil
+	}
+
+	callbacks := gormDB.Callback()
+	start := startDeadline(timeout)
+	return errors.Join(
+		callbacks.Create().Before("*").Register(startCallback, start),
+		callbacks.Create().After("*").Register(endCallback, endDeadline(true)),
+		callbacks.Query().Before("*").Register(startCallback, start),
+		callbacks.Query().After("*").Register(endCallback, endDeadline(true)),
+		callbacks.Update().Before("*").Register(startCallback, start),
+		callbacks.Update().After("*").Register(endCallback, endDeadline(true)),
+		callbacks.Delete().Before("*").Register(startCallback, start),
+		callbacks.Delete().After("*").Register(endCallback, endDeadline(true)),
+		callbacks.Raw().Before("*").Register(startCallback, start),
+		callbacks.Raw().After("*").Register(endCallback, endDeadline(true)),
+		// Row and Rows hand their rows to the caller after the callbacks ran, so the deadline
+		// must outlive them; it is released when it expires instead
+		callbacks.Row().Before("*").Register(startCallback, start),
+		callbacks.Row().After("*").Register(endCallback, endDeadline(false)),
+	)
+}
+
+// startDeadline runs the statement with a context that expires after timeout
+func startDeadline(timeout time.Duration) func(*gorm.DB) {
+	return func(tx *gorm.DB) {
+		parent := tx.Statement.Context
+		if parent == nil {
+			parent = context.Background()
+		}
+		ctx, cancel := context.WithTimeout(parent, timeout)
+		tx.Statement.Context = ctx
+		tx.InstanceSet(settingKey, deadline{parent: parent, cancel: cancel})
+	}
+}
+
+// endDeadline restores the statement's own context, so a chain reused for another statement does
+// not inherit the deadline, and releases the deadline when release is set
+func endDeadline(release bool) func(*gorm.DB) {
+	return func(tx *gorm.DB) {
+		value, ok := tx.InstanceGet(settingKey)
+		if !ok {
+			return
+		}
+		d, ok := value.(deadline)
+		if !ok || d.cancel == nil {
+			return
+		}
+		if release {
+			d.cancel()
+		}
+		tx.Statement.Context = d.parent
+		tx.InstanceSet(settingKey, deadline{})
+	}
+}
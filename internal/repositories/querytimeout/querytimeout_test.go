@@ -0,0 +1,142 @@
+package querytimeout
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// blockingDriver opens connections whose statements run until their context ends, like a query
+// stuck behind a lock
+type blockingDriver struct{}
+
+func (blockingDriver) Open(name string) (driver.Conn, error) { return blockingConn{}, nil }
+
+type blockingConn struct{}
+
+func (blockingConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("prepared statements are not supported")
+}
+func (blockingConn) Close() error              { return nil }
+func (blockingConn) Begin() (driver.Tx, error) { return blockingTx{}, nil }
+
+func (blockingConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (blockingConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+type blockingTx struct{}
+
+func (blockingTx) Commit() error   { return nil }
+func (blockingTx) Rollback() error { return nil }
+
+func init() {
+	sql.Register("querytimeout_blocking", blockingDriver{})
+}
+
+type record struct {
+	ID   string
+	Name string
+}
+
+func openBlockingDB(t *testing.T, timeout time.Duration) *gorm.DB {
+	t.Helper()
+	sqlDB, err := sql.Open("querytimeout_blocking", "")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{SkipDefaultTransaction: true})
+	require.NoError(t, err)
+	require.NoError(t, Register(gormDB, timeout))
+	return gormDB
+}
+
+// elapsed runs fn and returns how long it took
+func elapsed(fn func()) time.Duration {
+	started := time.Now()
+	fn()
+	return time.Since(started)
+}
+
+func TestCanceledContextAbortsQueryPromptly(t *testing.T) {
+	gormDB := openBlockingDB(t, time.Minute)
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	var err error
+	took := elapsed(func() {
+		var records []record
+		err = gormDB.WithContext(ctx).Where("name = ?", "x").Find(&records).Error
+	})
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Less(t, took, time.Second, "the query stops when the request is canceled, not at the statement timeout")
+}
+
+func TestStatementTimeoutBoundsQueriesWithoutDeadline(t *testing.T) {
+	gormDB := openBlockingDB(t, 50*time.Millisecond)
+	ctx := context.Background()
+
+	tests := map[string]func(*gorm.DB) error{
+		"query": func(tx *gorm.DB) error {
+			var records []record
+			return tx.Find(&records).Error
+		},
+		"create": func(tx *gorm.DB) error { return tx.Create(&record{ID: "1"}).Error },
+		"update": func(tx *gorm.DB) error {
+			return tx.Model(&record{}).Where("id = ?", "1").Update("name", "x").Error
+		},
+		"delete": func(tx *gorm.DB) error { return tx.Where("id = ?", "1").Delete(&record{}).Error },
+		"raw":    func(tx *gorm.DB) error { return tx.Exec("SELECT pg_sleep(60)").Error },
+		"row":    func(tx *gorm.DB) error { return tx.Raw("SELECT pg_sleep(60)").Row().Err() },
+	}
+
+	for name, run := range tests {
+		t.Run(name, func(t *testing.T) {
+			var err error
+			took := elapsed(func() { err = run(gormDB.WithContext(ctx)) })
+
+			assert.ErrorIs(t, err, context.DeadlineExceeded)
+			assert.Less(t, took, time.Second)
+		})
+	}
+}
+
+func TestEarlierRequestDeadlineIsKept(t *testing.T) {
+	gormDB := openBlockingDB(t, time.Minute)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	var err error
+	took := elapsed(func() {
+		var records []record
+		err = gormDB.WithContext(ctx).Find(&records).Error
+	})
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, took, time.Second)
+}
+
+func TestRegisterWithoutTimeoutLeavesCallbacksUnchanged(t *testing.T) {
+	sqlDB, err := sql.Open("querytimeout_blocking", "")
+	require.NoError(t, err)
+	defer sqlDB.Close()
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{})
+	require.NoError(t, err)
+
+	require.NoError(t, Register(gormDB, 0))
+	assert.Nil(t, gormDB.Callback().Query().Get(startCallback))
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/Kisanlink/aaa-service/v2/internal/repositories/softdelete"
 	"github.com/Kisanlink/kisanlink-db/pkg/base"
 )
 
@@ -27,7 +28,7 @@ func (r *ResourcePermissionRepository) HasPermission(ctx context.Context, roleID
 	}
 
 	// Check for exact match
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("role_id", base.OpEqual, roleID).
 		Where("resource_type", base.OpEqual, resourceType).
 		Where("resource_id", base.OpEqual, resourceID).
@@ -95,7 +96,7 @@ func (r *ResourcePermissionRepository) GetAllowedActions(ctx context.Context, ro
 		return nil, fmt.Errorf("resource ID is required")
 	}
 
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("role_id", base.OpEqual, roleID).
 		Where("resource_type", base.OpEqual, resourceType).
 		Where("resource_id", base.OpEqual, resourceID).
@@ -135,7 +136,7 @@ func (r *ResourcePermissionRepository) GetAllowedActionsForRoles(ctx context.Con
 		roleIDInterfaces[i] = roleID
 	}
 
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		WhereIn("role_id", roleIDInterfaces).
 		Where("resource_type", base.OpEqual, resourceType).
 		Where("resource_id", base.OpEqual, resourceID).
@@ -175,7 +176,7 @@ func (r *ResourcePermissionRepository) GetResourcesByRoleAndAction(ctx context.C
 		return nil, fmt.Errorf("action is required")
 	}
 
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("role_id", base.OpEqual, roleID).
 		Where("resource_type", base.OpEqual, resourceType).
 		Where("action", base.OpEqual, action).
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/repositories/softdelete"
 	"github.com/Kisanlink/kisanlink-db/pkg/base"
 )
 
@@ -14,7 +15,7 @@ func (r *ResourcePermissionRepository) GetByRoleID(ctx context.Context, roleID s
 		return nil, fmt.Errorf("role ID is required")
 	}
 
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("role_id", base.OpEqual, roleID).
 		Build()
 
@@ -36,7 +37,7 @@ func (r *ResourcePermissionRepository) GetByResource(ctx context.Context, resour
 		return nil, fmt.Errorf("resource ID is required")
 	}
 
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("resource_type", base.OpEqual, resourceType).
 		Where("resource_id", base.OpEqual, resourceID).
 		Build()
@@ -55,7 +56,7 @@ func (r *ResourcePermissionRepository) GetByResourceType(ctx context.Context, re
 		return nil, fmt.Errorf("resource type is required")
 	}
 
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("resource_type", base.OpEqual, resourceType).
 		Build()
 
@@ -73,7 +74,7 @@ func (r *ResourcePermissionRepository) GetByAction(ctx context.Context, action s
 		return nil, fmt.Errorf("action is required")
 	}
 
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("action", base.OpEqual, action).
 		Build()
 
@@ -109,7 +110,7 @@ func (r *ResourcePermissionRepository) GetRolePermissions(ctx context.Context, r
 		return nil, fmt.Errorf("resource type is required")
 	}
 
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("role_id", base.OpEqual, roleID).
 		Where("resource_type", base.OpEqual, resourceType).
 		Build()
@@ -128,7 +129,7 @@ func (r *ResourcePermissionRepository) GetActive(ctx context.Context, roleID str
 		return nil, fmt.Errorf("role ID is required")
 	}
 
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("role_id", base.OpEqual, roleID).
 		Where("is_active", base.OpEqual, true).
 		Build()
@@ -141,13 +142,28 @@ func (r *ResourcePermissionRepository) GetActive(ctx context.Context, roleID str
 	return permissions, nil
 }
 
+// ListActive retrieves the active resource permissions of every role with pagination
+func (r *ResourcePermissionRepository) ListActive(ctx context.Context, limit, offset int) ([]*models.ResourcePermission, error) {
+	filter := softdelete.Live().
+		Where("is_active", base.OpEqual, true).
+		Limit(limit, offset).
+		Build()
+
+	permissions, err := r.BaseFilterableRepository.Find(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list active resource permissions: %w", err)
+	}
+
+	return permissions, nil
+}
+
 // GetInactive retrieves all inactive resource permissions for a role
 func (r *ResourcePermissionRepository) GetInactive(ctx context.Context, roleID string) ([]*models.ResourcePermission, error) {
 	if roleID == "" {
 		return nil, fmt.Errorf("role ID is required")
 	}
 
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("role_id", base.OpEqual, roleID).
 		Where("is_active", base.OpEqual, false).
 		Build()
@@ -198,7 +214,7 @@ func (r *ResourcePermissionRepository) CountByRole(ctx context.Context, roleID s
 		return 0, fmt.Errorf("role ID is required")
 	}
 
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("role_id", base.OpEqual, roleID).
 		Build()
 
@@ -220,7 +236,7 @@ func (r *ResourcePermissionRepository) CountByResource(ctx context.Context, reso
 		return 0, fmt.Errorf("resource ID is required")
 	}
 
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("resource_type", base.OpEqual, resourceType).
 		Where("resource_id", base.OpEqual, resourceID).
 		Build()
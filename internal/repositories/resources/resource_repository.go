@@ -5,8 +5,10 @@ import (
 	"fmt"
 
 	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/repositories/softdelete"
 	"github.com/Kisanlink/kisanlink-db/pkg/base"
 	"github.com/Kisanlink/kisanlink-db/pkg/db"
+	"gorm.io/gorm"
 )
 
 // ResourceRepository handles database operations for Resource entities
@@ -49,7 +51,7 @@ func (r *ResourceRepository) Delete(ctx context.Context, id string) error {
 
 // List retrieves resources with pagination using database-level filtering
 func (r *ResourceRepository) List(ctx context.Context, limit, offset int) ([]*models.Resource, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Sort("id", "asc"). // Default sort by ID ascending
 		Limit(limit, offset).
 		Build()
@@ -105,7 +107,7 @@ func (r *ResourceRepository) GetByUpdatedBy(ctx context.Context, updatedBy strin
 
 // GetByName retrieves a resource by name
 func (r *ResourceRepository) GetByName(ctx context.Context, name string) (*models.Resource, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("name", base.OpEqual, name).
 		Build()
 
@@ -123,7 +125,7 @@ func (r *ResourceRepository) GetByName(ctx context.Context, name string) (*model
 
 // GetByServiceName retrieves resources by service name
 func (r *ResourceRepository) GetByServiceName(ctx context.Context, serviceName string, limit, offset int) ([]*models.Resource, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("service_name", base.OpEqual, serviceName).
 		Limit(limit, offset).
 		Build()
@@ -133,7 +135,7 @@ func (r *ResourceRepository) GetByServiceName(ctx context.Context, serviceName s
 
 // GetByType retrieves resources by type
 func (r *ResourceRepository) GetByType(ctx context.Context, resourceType string, limit, offset int) ([]*models.Resource, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("type", base.OpEqual, resourceType).
 		Limit(limit, offset).
 		Build()
@@ -143,7 +145,7 @@ func (r *ResourceRepository) GetByType(ctx context.Context, resourceType string,
 
 // GetChildren retrieves all child resources for a given parent ID
 func (r *ResourceRepository) GetChildren(ctx context.Context, parentID string) ([]*models.Resource, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("parent_id", base.OpEqual, parentID).
 		Build()
 
@@ -152,7 +154,7 @@ func (r *ResourceRepository) GetChildren(ctx context.Context, parentID string) (
 
 // GetByParentID retrieves resources by parent ID with pagination
 func (r *ResourceRepository) GetByParentID(ctx context.Context, parentID string, limit, offset int) ([]*models.Resource, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("parent_id", base.OpEqual, parentID).
 		Limit(limit, offset).
 		Build()
@@ -162,7 +164,7 @@ func (r *ResourceRepository) GetByParentID(ctx context.Context, parentID string,
 
 // GetByOwnerID retrieves resources by owner ID
 func (r *ResourceRepository) GetByOwnerID(ctx context.Context, ownerID string, limit, offset int) ([]*models.Resource, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("owner_id", base.OpEqual, ownerID).
 		Limit(limit, offset).
 		Build()
@@ -172,7 +174,7 @@ func (r *ResourceRepository) GetByOwnerID(ctx context.Context, ownerID string, l
 
 // GetActive retrieves only active resources
 func (r *ResourceRepository) GetActive(ctx context.Context, limit, offset int) ([]*models.Resource, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("is_active", base.OpEqual, true).
 		Limit(limit, offset).
 		Build()
@@ -182,9 +184,41 @@ func (r *ResourceRepository) GetActive(ctx context.Context, limit, offset int) (
 
 // CountChildren returns the number of child resources for a parent
 func (r *ResourceRepository) CountChildren(ctx context.Context, parentID string) (int64, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("parent_id", base.OpEqual, parentID).
 		Build()
 
 	return r.BaseFilterableRepository.CountWithFilter(ctx, filter)
 }
+
+// ResourceTypeCount is the number of active resources of one type
+type ResourceTypeCount struct {
+	Type  string `json:"type"`
+	Count int64  `json:"count"`
+}
+
+// CountByType returns the number of active, non-deleted resources of each type, ordered by type
+func (r *ResourceRepository) CountByType(ctx context.Context) ([]ResourceTypeCount, error) {
+	postgresMgr, ok := r.dbManager.(interface {
+		GetDB(context.Context, bool) (*gorm.DB, error)
+	})
+	if !ok {
+		return nil, fmt.Errorf("database manager does not support GetDB method")
+	}
+	database, err := postgresMgr.GetDB(ctx, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database: %w", err)
+	}
+
+	var counts []ResourceTypeCount
+	if err := database.WithContext(ctx).
+		Model(&models.Resource{}).
+		Select("type, COUNT(*) AS count").
+		Where("is_active = ? AND deleted_at IS NULL", true).
+		Group("type").
+		Order("type").
+		Scan(&counts).Error; err != nil {
+		return nil, fmt.Errorf("failed to count resources by type: %w", err)
+	}
+	return counts, nil
+}
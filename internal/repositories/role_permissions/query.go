@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/repositories/softdelete"
 	"github.com/Kisanlink/kisanlink-db/pkg/base"
 )
 
@@ -14,7 +15,7 @@ func (r *RolePermissionRepository) GetByRoleID(ctx context.Context, roleID strin
 		return nil, fmt.Errorf("role ID is required")
 	}
 
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("role_id", base.OpEqual, roleID).
 		Build()
 
@@ -32,7 +33,7 @@ func (r *RolePermissionRepository) GetByPermissionID(ctx context.Context, permis
 		return nil, fmt.Errorf("permission ID is required")
 	}
 
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("permission_id", base.OpEqual, permissionID).
 		Build()
 
@@ -54,7 +55,7 @@ func (r *RolePermissionRepository) GetByRoleAndPermission(ctx context.Context, r
 		return nil, fmt.Errorf("permission ID is required")
 	}
 
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("role_id", base.OpEqual, roleID).
 		Where("permission_id", base.OpEqual, permissionID).
 		Build()
@@ -95,7 +96,7 @@ func (r *RolePermissionRepository) CountByRole(ctx context.Context, roleID strin
 		return 0, fmt.Errorf("role ID is required")
 	}
 
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("role_id", base.OpEqual, roleID).
 		Build()
 
@@ -113,7 +114,7 @@ func (r *RolePermissionRepository) CountByPermission(ctx context.Context, permis
 		return 0, fmt.Errorf("permission ID is required")
 	}
 
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("permission_id", base.OpEqual, permissionID).
 		Build()
 
@@ -131,7 +132,7 @@ func (r *RolePermissionRepository) GetPermissionsByRoles(ctx context.Context, ro
 		return nil, fmt.Errorf("no role IDs provided")
 	}
 
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		WhereIn("role_id", convertToInterfaces(roleIDs)).
 		Build()
 
@@ -149,7 +150,7 @@ func (r *RolePermissionRepository) GetRolesByPermissions(ctx context.Context, pe
 		return nil, fmt.Errorf("no permission IDs provided")
 	}
 
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		WhereIn("permission_id", convertToInterfaces(permissionIDs)).
 		Build()
 
@@ -167,7 +168,7 @@ func (r *RolePermissionRepository) GetActive(ctx context.Context, roleID string)
 		return nil, fmt.Errorf("role ID is required")
 	}
 
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("role_id", base.OpEqual, roleID).
 		Where("is_active", base.OpEqual, true).
 		Build()
@@ -186,7 +187,7 @@ func (r *RolePermissionRepository) GetInactive(ctx context.Context, roleID strin
 		return nil, fmt.Errorf("role ID is required")
 	}
 
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("role_id", base.OpEqual, roleID).
 		Where("is_active", base.OpEqual, false).
 		Build()
@@ -205,7 +206,7 @@ func (r *RolePermissionRepository) GetActiveByPermission(ctx context.Context, pe
 		return nil, fmt.Errorf("permission ID is required")
 	}
 
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("permission_id", base.OpEqual, permissionID).
 		Where("is_active", base.OpEqual, true).
 		Build()
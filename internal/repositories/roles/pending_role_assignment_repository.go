@@ -0,0 +1,66 @@
+package roles
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/repositories/softdelete"
+	"github.com/Kisanlink/kisanlink-db/pkg/base"
+	"github.com/Kisanlink/kisanlink-db/pkg/db"
+)
+
+// PendingRoleAssignmentRepository handles database operations for assignments of KYC-gated roles
+// awaiting the user's Aadhaar verification
+type PendingRoleAssignmentRepository struct {
+	*base.BaseFilterableRepository[*models.PendingRoleAssignment]
+	dbManager db.DBManager
+}
+
+// NewPendingRoleAssignmentRepository creates a new PendingRoleAssignmentRepository instance
+func NewPendingRoleAssignmentRepository(dbManager db.DBManager) *PendingRoleAssignmentRepository {
+	baseRepo := base.NewBaseFilterableRepository[*models.PendingRoleAssignment]()
+	baseRepo.SetDBManager(dbManager)
+	return &PendingRoleAssignmentRepository{
+		BaseFilterableRepository: baseRepo,
+		dbManager:                dbManager,
+	}
+}
+
+// Create stores a new pending assignment
+func (r *PendingRoleAssignmentRepository) Create(ctx context.Context, assignment *models.PendingRoleAssignment) error {
+	return r.BaseFilterableRepository.Create(ctx, assignment)
+}
+
+// Exists reports whether the role is already pending for the user
+func (r *PendingRoleAssignmentRepository) Exists(ctx context.Context, userID, roleID string) (bool, error) {
+	filter := softdelete.Live().
+		Where("user_id", base.OpEqual, userID).
+		Where("role_id", base.OpEqual, roleID).
+		Build()
+
+	count, err := r.BaseFilterableRepository.CountWithFilter(ctx, filter)
+	if err != nil {
+		return false, fmt.Errorf("failed to check pending role assignment: %w", err)
+	}
+	return count > 0, nil
+}
+
+// ListByUser retrieves the pending assignments of a user, oldest first
+func (r *PendingRoleAssignmentRepository) ListByUser(ctx context.Context, userID string) ([]*models.PendingRoleAssignment, error) {
+	filter := softdelete.Live().
+		Where("user_id", base.OpEqual, userID).
+		Sort("created_at", "asc").
+		Build()
+
+	assignments, err := r.BaseFilterableRepository.Find(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pending role assignments: %w", err)
+	}
+	return assignments, nil
+}
+
+// Delete removes a pending assignment
+func (r *PendingRoleAssignmentRepository) Delete(ctx context.Context, id string) error {
+	return r.BaseFilterableRepository.Delete(ctx, id, &models.PendingRoleAssignment{})
+}
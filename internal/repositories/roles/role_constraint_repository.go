@@ -0,0 +1,67 @@
+package roles
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/repositories/softdelete"
+	"github.com/Kisanlink/kisanlink-db/pkg/base"
+	"github.com/Kisanlink/kisanlink-db/pkg/db"
+)
+
+// RoleConstraintRepository handles database operations for organization role assignment constraints
+type RoleConstraintRepository struct {
+	*base.BaseFilterableRepository[*models.RoleConstraint]
+	dbManager db.DBManager
+}
+
+// NewRoleConstraintRepository creates a new RoleConstraintRepository instance
+func NewRoleConstraintRepository(dbManager db.DBManager) *RoleConstraintRepository {
+	baseRepo := base.NewBaseFilterableRepository[*models.RoleConstraint]()
+	baseRepo.SetDBManager(dbManager)
+	return &RoleConstraintRepository{
+		BaseFilterableRepository: baseRepo,
+		dbManager:                dbManager,
+	}
+}
+
+// Create stores a new constraint
+func (r *RoleConstraintRepository) Create(ctx context.Context, constraint *models.RoleConstraint) error {
+	return r.BaseFilterableRepository.Create(ctx, constraint)
+}
+
+// GetByID retrieves a constraint by ID
+func (r *RoleConstraintRepository) GetByID(ctx context.Context, id string) (*models.RoleConstraint, error) {
+	filter := softdelete.Live().
+		Where("id", base.OpEqual, id).
+		Build()
+
+	constraints, err := r.BaseFilterableRepository.Find(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get role constraint: %w", err)
+	}
+	if len(constraints) == 0 {
+		return nil, fmt.Errorf("role constraint not found: %s", id)
+	}
+	return constraints[0], nil
+}
+
+// ListByOrganization retrieves the constraints of an organization, oldest first
+func (r *RoleConstraintRepository) ListByOrganization(ctx context.Context, organizationID string) ([]*models.RoleConstraint, error) {
+	filter := softdelete.Live().
+		Where("organization_id", base.OpEqual, organizationID).
+		Sort("created_at", "asc").
+		Build()
+
+	constraints, err := r.BaseFilterableRepository.Find(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list role constraints: %w", err)
+	}
+	return constraints, nil
+}
+
+// Delete removes a constraint
+func (r *RoleConstraintRepository) Delete(ctx context.Context, id string) error {
+	return r.BaseFilterableRepository.Delete(ctx, id, &models.RoleConstraint{})
+}
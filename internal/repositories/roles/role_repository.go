@@ -5,8 +5,11 @@ import (
 	"fmt"
 
 	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/repositories/softdelete"
+	"github.com/Kisanlink/aaa-service/v2/internal/repositories/sorting"
 	"github.com/Kisanlink/kisanlink-db/pkg/base"
 	"github.com/Kisanlink/kisanlink-db/pkg/db"
+	"gorm.io/gorm"
 )
 
 // RoleRepository handles role-related database operations
@@ -65,10 +68,9 @@ func (r *RoleRepository) Restore(ctx context.Context, id string) error {
 func (r *RoleRepository) List(ctx context.Context, limit, offset int) ([]*models.Role, error) {
 	// Use base filterable repository for optimized database-level filtering
 	// Only return active roles (not deleted)
-	filter := base.NewFilterBuilder().
-		Where("is_active", base.OpEqual, true).
-		WhereNull("deleted_at").
-		Sort("id", "asc"). // Default sort by ID ascending
+	fb := softdelete.Live().
+		Where("is_active", base.OpEqual, true)
+	filter := sorting.Apply(ctx, fb, sorting.RoleFields).
 		Limit(limit, offset).
 		Build()
 
@@ -88,10 +90,9 @@ func (r *RoleRepository) CountWithDeleted(ctx context.Context) (int64, error) {
 
 // GetByName retrieves an active, non-deleted role by name using base filterable repository
 func (r *RoleRepository) GetByName(ctx context.Context, name string) (*models.Role, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("name", base.OpEqual, name).
 		Where("is_active", base.OpEqual, true).
-		WhereNull("deleted_at").
 		Build()
 
 	roles, err := r.BaseFilterableRepository.Find(ctx, filter)
@@ -106,14 +107,42 @@ func (r *RoleRepository) GetByName(ctx context.Context, name string) (*models.Ro
 	return roles[0], nil
 }
 
+// GetByNameInOrganization retrieves the active, non-deleted role with a name that can be assigned
+// within an organization. A role of the organization takes precedence over a global one; an empty
+// organizationID selects the global role.
+func (r *RoleRepository) GetByNameInOrganization(ctx context.Context, name, organizationID string) (*models.Role, error) {
+	filter := softdelete.Live().
+		Where("name", base.OpEqual, name).
+		Where("is_active", base.OpEqual, true).
+		Build()
+
+	roles, err := r.BaseFilterableRepository.Find(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get role by name: %w", err)
+	}
+
+	var global *models.Role
+	for _, role := range roles {
+		if role.OrganizationID != nil && *role.OrganizationID == organizationID {
+			return role, nil
+		}
+		if role.OrganizationID == nil && global == nil {
+			global = role
+		}
+	}
+	if global == nil {
+		return nil, fmt.Errorf("role not found")
+	}
+	return global, nil
+}
+
 // GetByServiceAndName retrieves an active, non-deleted role by service ID and name using base filterable repository
 // This enforces the composite unique constraint on (service_id, name)
 func (r *RoleRepository) GetByServiceAndName(ctx context.Context, serviceID, name string) (*models.Role, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("service_id", base.OpEqual, serviceID).
 		Where("name", base.OpEqual, name).
 		Where("is_active", base.OpEqual, true).
-		WhereNull("deleted_at").
 		Build()
 
 	roles, err := r.BaseFilterableRepository.Find(ctx, filter)
@@ -132,8 +161,7 @@ func (r *RoleRepository) GetByServiceAndName(ctx context.Context, serviceID, nam
 func (r *RoleRepository) GetActive(ctx context.Context, limit, offset int) ([]*models.Role, error) {
 	// For now, we'll consider all non-deleted roles as active
 	// In the future, you might want to add an "active" field to the Role model
-	filter := base.NewFilterBuilder().
-		Where("is_deleted", base.OpEqual, false).
+	filter := sorting.Apply(ctx, softdelete.Live(), sorting.RoleFields).
 		Limit(limit, offset).
 		Build()
 
@@ -142,8 +170,9 @@ func (r *RoleRepository) GetActive(ctx context.Context, limit, offset int) ([]*m
 
 // Search searches roles by keyword using database-level filtering
 func (r *RoleRepository) Search(ctx context.Context, query string, limit, offset int) ([]*models.Role, error) {
-	filter := base.NewFilterBuilder().
-		Where("name", base.OpContains, query).
+	fb := softdelete.Live().
+		Where("name", base.OpContains, query)
+	filter := sorting.Apply(ctx, fb, sorting.RoleFields).
 		Limit(limit, offset).
 		Build()
 
@@ -152,7 +181,7 @@ func (r *RoleRepository) Search(ctx context.Context, query string, limit, offset
 
 // ExistsByName checks if a role exists with the given name using database-level filtering
 func (r *RoleRepository) ExistsByName(ctx context.Context, name string) (bool, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("name", base.OpEqual, name).
 		Build()
 
@@ -164,9 +193,28 @@ func (r *RoleRepository) ExistsByName(ctx context.Context, name string) (bool, e
 	return len(roles) > 0, nil
 }
 
+// ExistingIDs returns the IDs among ids that belong to a role, in a single query. Soft-deleted
+// roles count only when includeDeleted is set.
+func (r *RoleRepository) ExistingIDs(ctx context.Context, ids []string, includeDeleted bool) ([]string, error) {
+	db, err := r.getDB(ctx, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	query := db.Model(&models.Role{}).Where("id IN ?", ids)
+	if !includeDeleted {
+		query = query.Where("deleted_at IS NULL")
+	}
+	var existing []string
+	if err := query.Pluck("id", &existing).Error; err != nil {
+		return nil, fmt.Errorf("failed to check role existence: %w", err)
+	}
+	return existing, nil
+}
+
 // GetByDescription retrieves roles by description using database-level filtering
 func (r *RoleRepository) GetByDescription(ctx context.Context, description string, limit, offset int) ([]*models.Role, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("description", base.OpContains, description).
 		Limit(limit, offset).
 		Build()
@@ -176,7 +224,7 @@ func (r *RoleRepository) GetByDescription(ctx context.Context, description strin
 
 // GetByPermission retrieves roles by permission using database-level filtering
 func (r *RoleRepository) GetByPermission(ctx context.Context, permission string, limit, offset int) ([]*models.Role, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("permissions", base.OpContains, permission).
 		Limit(limit, offset).
 		Build()
@@ -186,7 +234,7 @@ func (r *RoleRepository) GetByPermission(ctx context.Context, permission string,
 
 // GetByCreatedBy retrieves roles by creator using database-level filtering
 func (r *RoleRepository) GetByCreatedBy(ctx context.Context, createdBy string, limit, offset int) ([]*models.Role, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("created_by", base.OpEqual, createdBy).
 		Limit(limit, offset).
 		Build()
@@ -196,7 +244,7 @@ func (r *RoleRepository) GetByCreatedBy(ctx context.Context, createdBy string, l
 
 // GetByUpdatedBy retrieves roles by updater using database-level filtering
 func (r *RoleRepository) GetByUpdatedBy(ctx context.Context, updatedBy string, limit, offset int) ([]*models.Role, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("updated_by", base.OpEqual, updatedBy).
 		Limit(limit, offset).
 		Build()
@@ -206,7 +254,7 @@ func (r *RoleRepository) GetByUpdatedBy(ctx context.Context, updatedBy string, l
 
 // GetByDeletedBy retrieves roles by deleter using database-level filtering
 func (r *RoleRepository) GetByDeletedBy(ctx context.Context, deletedBy string, limit, offset int) ([]*models.Role, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Filter(true).
 		Where("deleted_by", base.OpEqual, deletedBy).
 		Limit(limit, offset).
 		Build()
@@ -216,7 +264,7 @@ func (r *RoleRepository) GetByDeletedBy(ctx context.Context, deletedBy string, l
 
 // GetByDateRange retrieves roles created within a date range using database-level filtering
 func (r *RoleRepository) GetByDateRange(ctx context.Context, startDate, endDate string, limit, offset int) ([]*models.Role, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		WhereBetween("created_at", startDate, endDate).
 		Limit(limit, offset).
 		Build()
@@ -226,7 +274,7 @@ func (r *RoleRepository) GetByDateRange(ctx context.Context, startDate, endDate
 
 // GetByUpdatedDateRange retrieves roles updated within a date range using database-level filtering
 func (r *RoleRepository) GetByUpdatedDateRange(ctx context.Context, startDate, endDate string, limit, offset int) ([]*models.Role, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		WhereBetween("updated_at", startDate, endDate).
 		Limit(limit, offset).
 		Build()
@@ -236,7 +284,7 @@ func (r *RoleRepository) GetByUpdatedDateRange(ctx context.Context, startDate, e
 
 // GetByDeletedDateRange retrieves roles deleted within a date range using database-level filtering
 func (r *RoleRepository) GetByDeletedDateRange(ctx context.Context, startDate, endDate string, limit, offset int) ([]*models.Role, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Filter(true).
 		WhereBetween("deleted_at", startDate, endDate).
 		Limit(limit, offset).
 		Build()
@@ -246,7 +294,7 @@ func (r *RoleRepository) GetByDeletedDateRange(ctx context.Context, startDate, e
 
 // GetByNameAndDescription retrieves roles by name and description using database-level filtering
 func (r *RoleRepository) GetByNameAndDescription(ctx context.Context, name, description string, limit, offset int) ([]*models.Role, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("name", base.OpEqual, name).
 		Where("description", base.OpContains, description).
 		Limit(limit, offset).
@@ -257,7 +305,7 @@ func (r *RoleRepository) GetByNameAndDescription(ctx context.Context, name, desc
 
 // GetByNameAndPermission retrieves roles by name and permission using database-level filtering
 func (r *RoleRepository) GetByNameAndPermission(ctx context.Context, name, permission string, limit, offset int) ([]*models.Role, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("name", base.OpEqual, name).
 		Where("permissions", base.OpContains, permission).
 		Limit(limit, offset).
@@ -268,7 +316,7 @@ func (r *RoleRepository) GetByNameAndPermission(ctx context.Context, name, permi
 
 // GetByNameAndCreatedBy retrieves roles by name and creator using database-level filtering
 func (r *RoleRepository) GetByNameAndCreatedBy(ctx context.Context, name, createdBy string, limit, offset int) ([]*models.Role, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("name", base.OpEqual, name).
 		Where("created_by", base.OpEqual, createdBy).
 		Limit(limit, offset).
@@ -279,7 +327,7 @@ func (r *RoleRepository) GetByNameAndCreatedBy(ctx context.Context, name, create
 
 // GetByNameAndUpdatedBy retrieves roles by name and updater using database-level filtering
 func (r *RoleRepository) GetByNameAndUpdatedBy(ctx context.Context, name, updatedBy string, limit, offset int) ([]*models.Role, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("name", base.OpEqual, name).
 		Where("updated_by", base.OpEqual, updatedBy).
 		Limit(limit, offset).
@@ -290,7 +338,7 @@ func (r *RoleRepository) GetByNameAndUpdatedBy(ctx context.Context, name, update
 
 // GetByNameAndDeletedBy retrieves roles by name and deleter using database-level filtering
 func (r *RoleRepository) GetByNameAndDeletedBy(ctx context.Context, name, deletedBy string, limit, offset int) ([]*models.Role, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Filter(true).
 		Where("name", base.OpEqual, name).
 		Where("deleted_by", base.OpEqual, deletedBy).
 		Limit(limit, offset).
@@ -301,7 +349,7 @@ func (r *RoleRepository) GetByNameAndDeletedBy(ctx context.Context, name, delete
 
 // GetByNameAndDateRange retrieves roles by name and date range using database-level filtering
 func (r *RoleRepository) GetByNameAndDateRange(ctx context.Context, name, startDate, endDate string, limit, offset int) ([]*models.Role, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("name", base.OpEqual, name).
 		WhereBetween("created_at", startDate, endDate).
 		Limit(limit, offset).
@@ -312,7 +360,7 @@ func (r *RoleRepository) GetByNameAndDateRange(ctx context.Context, name, startD
 
 // GetByNameAndUpdatedDateRange retrieves roles by name and updated date range using database-level filtering
 func (r *RoleRepository) GetByNameAndUpdatedDateRange(ctx context.Context, name, startDate, endDate string, limit, offset int) ([]*models.Role, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("name", base.OpEqual, name).
 		WhereBetween("updated_at", startDate, endDate).
 		Limit(limit, offset).
@@ -323,7 +371,7 @@ func (r *RoleRepository) GetByNameAndUpdatedDateRange(ctx context.Context, name,
 
 // GetByNameAndDeletedDateRange retrieves roles by name and deleted date range using database-level filtering
 func (r *RoleRepository) GetByNameAndDeletedDateRange(ctx context.Context, name, startDate, endDate string, limit, offset int) ([]*models.Role, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Filter(true).
 		Where("name", base.OpEqual, name).
 		WhereBetween("deleted_at", startDate, endDate).
 		Limit(limit, offset).
@@ -334,7 +382,7 @@ func (r *RoleRepository) GetByNameAndDeletedDateRange(ctx context.Context, name,
 
 // GetByDescriptionAndPermission retrieves roles by description and permission using database-level filtering
 func (r *RoleRepository) GetByDescriptionAndPermission(ctx context.Context, description, permission string, limit, offset int) ([]*models.Role, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("description", base.OpContains, description).
 		Where("permissions", base.OpContains, permission).
 		Limit(limit, offset).
@@ -345,7 +393,7 @@ func (r *RoleRepository) GetByDescriptionAndPermission(ctx context.Context, desc
 
 // GetByDescriptionAndCreatedBy retrieves roles by description and creator using database-level filtering
 func (r *RoleRepository) GetByDescriptionAndCreatedBy(ctx context.Context, description, createdBy string, limit, offset int) ([]*models.Role, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("description", base.OpContains, description).
 		Where("created_by", base.OpEqual, createdBy).
 		Limit(limit, offset).
@@ -356,7 +404,7 @@ func (r *RoleRepository) GetByDescriptionAndCreatedBy(ctx context.Context, descr
 
 // GetByDescriptionAndUpdatedBy retrieves roles by description and updater using database-level filtering
 func (r *RoleRepository) GetByDescriptionAndUpdatedBy(ctx context.Context, description, updatedBy string, limit, offset int) ([]*models.Role, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("description", base.OpContains, description).
 		Where("updated_by", base.OpEqual, updatedBy).
 		Limit(limit, offset).
@@ -367,7 +415,7 @@ func (r *RoleRepository) GetByDescriptionAndUpdatedBy(ctx context.Context, descr
 
 // GetByDescriptionAndDeletedBy retrieves roles by description and deleter using database-level filtering
 func (r *RoleRepository) GetByDescriptionAndDeletedBy(ctx context.Context, description, deletedBy string, limit, offset int) ([]*models.Role, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Filter(true).
 		Where("description", base.OpContains, description).
 		Where("deleted_by", base.OpEqual, deletedBy).
 		Limit(limit, offset).
@@ -378,7 +426,7 @@ func (r *RoleRepository) GetByDescriptionAndDeletedBy(ctx context.Context, descr
 
 // GetByDescriptionAndDateRange retrieves roles by description and date range using database-level filtering
 func (r *RoleRepository) GetByDescriptionAndDateRange(ctx context.Context, description, startDate, endDate string, limit, offset int) ([]*models.Role, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("description", base.OpContains, description).
 		WhereBetween("created_at", startDate, endDate).
 		Limit(limit, offset).
@@ -389,7 +437,7 @@ func (r *RoleRepository) GetByDescriptionAndDateRange(ctx context.Context, descr
 
 // GetByDescriptionAndUpdatedDateRange retrieves roles by description and updated date range using database-level filtering
 func (r *RoleRepository) GetByDescriptionAndUpdatedDateRange(ctx context.Context, description, startDate, endDate string, limit, offset int) ([]*models.Role, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("description", base.OpContains, description).
 		WhereBetween("updated_at", startDate, endDate).
 		Limit(limit, offset).
@@ -400,7 +448,7 @@ func (r *RoleRepository) GetByDescriptionAndUpdatedDateRange(ctx context.Context
 
 // GetByDescriptionAndDeletedDateRange retrieves roles by description and deleted date range using database-level filtering
 func (r *RoleRepository) GetByDescriptionAndDeletedDateRange(ctx context.Context, description, startDate, endDate string, limit, offset int) ([]*models.Role, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Filter(true).
 		Where("description", base.OpContains, description).
 		WhereBetween("deleted_at", startDate, endDate).
 		Limit(limit, offset).
@@ -411,7 +459,7 @@ func (r *RoleRepository) GetByDescriptionAndDeletedDateRange(ctx context.Context
 
 // GetByPermissionAndCreatedBy retrieves roles by permission and creator using database-level filtering
 func (r *RoleRepository) GetByPermissionAndCreatedBy(ctx context.Context, permission, createdBy string, limit, offset int) ([]*models.Role, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("permissions", base.OpContains, permission).
 		Where("created_by", base.OpEqual, createdBy).
 		Limit(limit, offset).
@@ -422,7 +470,7 @@ func (r *RoleRepository) GetByPermissionAndCreatedBy(ctx context.Context, permis
 
 // GetByPermissionAndUpdatedBy retrieves roles by permission and updater using database-level filtering
 func (r *RoleRepository) GetByPermissionAndUpdatedBy(ctx context.Context, permission, updatedBy string, limit, offset int) ([]*models.Role, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("permissions", base.OpContains, permission).
 		Where("updated_by", base.OpEqual, updatedBy).
 		Limit(limit, offset).
@@ -433,7 +481,7 @@ func (r *RoleRepository) GetByPermissionAndUpdatedBy(ctx context.Context, permis
 
 // GetByPermissionAndDeletedBy retrieves roles by permission and deleter using database-level filtering
 func (r *RoleRepository) GetByPermissionAndDeletedBy(ctx context.Context, permission, deletedBy string, limit, offset int) ([]*models.Role, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Filter(true).
 		Where("permissions", base.OpContains, permission).
 		Where("deleted_by", base.OpEqual, deletedBy).
 		Limit(limit, offset).
@@ -444,7 +492,7 @@ func (r *RoleRepository) GetByPermissionAndDeletedBy(ctx context.Context, permis
 
 // GetByPermissionAndDateRange retrieves roles by permission and date range using database-level filtering
 func (r *RoleRepository) GetByPermissionAndDateRange(ctx context.Context, permission, startDate, endDate string, limit, offset int) ([]*models.Role, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("permissions", base.OpContains, permission).
 		WhereBetween("created_at", startDate, endDate).
 		Limit(limit, offset).
@@ -455,7 +503,7 @@ func (r *RoleRepository) GetByPermissionAndDateRange(ctx context.Context, permis
 
 // GetByPermissionAndUpdatedDateRange retrieves roles by permission and updated date range using database-level filtering
 func (r *RoleRepository) GetByPermissionAndUpdatedDateRange(ctx context.Context, permission, startDate, endDate string, limit, offset int) ([]*models.Role, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("permissions", base.OpContains, permission).
 		WhereBetween("updated_at", startDate, endDate).
 		Limit(limit, offset).
@@ -466,7 +514,7 @@ func (r *RoleRepository) GetByPermissionAndUpdatedDateRange(ctx context.Context,
 
 // GetByPermissionAndDeletedDateRange retrieves roles by permission and deleted date range using database-level filtering
 func (r *RoleRepository) GetByPermissionAndDeletedDateRange(ctx context.Context, permission, startDate, endDate string, limit, offset int) ([]*models.Role, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Filter(true).
 		Where("permissions", base.OpContains, permission).
 		WhereBetween("deleted_at", startDate, endDate).
 		Limit(limit, offset).
@@ -477,7 +525,7 @@ func (r *RoleRepository) GetByPermissionAndDeletedDateRange(ctx context.Context,
 
 // GetByCreatedByAndUpdatedBy retrieves roles by creator and updater using database-level filtering
 func (r *RoleRepository) GetByCreatedByAndUpdatedBy(ctx context.Context, createdBy, updatedBy string, limit, offset int) ([]*models.Role, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("created_by", base.OpEqual, createdBy).
 		Where("updated_by", base.OpEqual, updatedBy).
 		Limit(limit, offset).
@@ -488,7 +536,7 @@ func (r *RoleRepository) GetByCreatedByAndUpdatedBy(ctx context.Context, created
 
 // GetByCreatedByAndDeletedBy retrieves roles by creator and deleter using database-level filtering
 func (r *RoleRepository) GetByCreatedByAndDeletedBy(ctx context.Context, createdBy, deletedBy string, limit, offset int) ([]*models.Role, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Filter(true).
 		Where("created_by", base.OpEqual, createdBy).
 		Where("deleted_by", base.OpEqual, deletedBy).
 		Limit(limit, offset).
@@ -499,7 +547,7 @@ func (r *RoleRepository) GetByCreatedByAndDeletedBy(ctx context.Context, created
 
 // GetByCreatedByAndDateRange retrieves roles by creator and date range using database-level filtering
 func (r *RoleRepository) GetByCreatedByAndDateRange(ctx context.Context, createdBy, startDate, endDate string, limit, offset int) ([]*models.Role, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("created_by", base.OpEqual, createdBy).
 		WhereBetween("created_at", startDate, endDate).
 		Limit(limit, offset).
@@ -510,7 +558,7 @@ func (r *RoleRepository) GetByCreatedByAndDateRange(ctx context.Context, created
 
 // GetByCreatedByAndUpdatedDateRange retrieves roles by creator and updated date range using database-level filtering
 func (r *RoleRepository) GetByCreatedByAndUpdatedDateRange(ctx context.Context, createdBy, startDate, endDate string, limit, offset int) ([]*models.Role, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("created_by", base.OpEqual, createdBy).
 		WhereBetween("updated_at", startDate, endDate).
 		Limit(limit, offset).
@@ -521,7 +569,7 @@ func (r *RoleRepository) GetByCreatedByAndUpdatedDateRange(ctx context.Context,
 
 // GetByCreatedByAndDeletedDateRange retrieves roles by creator and deleted date range using database-level filtering
 func (r *RoleRepository) GetByCreatedByAndDeletedDateRange(ctx context.Context, createdBy, startDate, endDate string, limit, offset int) ([]*models.Role, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Filter(true).
 		Where("created_by", base.OpEqual, createdBy).
 		WhereBetween("deleted_at", startDate, endDate).
 		Limit(limit, offset).
@@ -532,7 +580,7 @@ func (r *RoleRepository) GetByCreatedByAndDeletedDateRange(ctx context.Context,
 
 // GetByUpdatedByAndDeletedBy retrieves roles by updater and deleter using database-level filtering
 func (r *RoleRepository) GetByUpdatedByAndDeletedBy(ctx context.Context, updatedBy, deletedBy string, limit, offset int) ([]*models.Role, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Filter(true).
 		Where("updated_by", base.OpEqual, updatedBy).
 		Where("deleted_by", base.OpEqual, deletedBy).
 		Limit(limit, offset).
@@ -543,7 +591,7 @@ func (r *RoleRepository) GetByUpdatedByAndDeletedBy(ctx context.Context, updated
 
 // GetByUpdatedByAndDateRange retrieves roles by updater and date range using database-level filtering
 func (r *RoleRepository) GetByUpdatedByAndDateRange(ctx context.Context, updatedBy, startDate, endDate string, limit, offset int) ([]*models.Role, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("updated_by", base.OpEqual, updatedBy).
 		WhereBetween("created_at", startDate, endDate).
 		Limit(limit, offset).
@@ -554,7 +602,7 @@ func (r *RoleRepository) GetByUpdatedByAndDateRange(ctx context.Context, updated
 
 // GetByUpdatedByAndUpdatedDateRange retrieves roles by updater and updated date range using database-level filtering
 func (r *RoleRepository) GetByUpdatedByAndUpdatedDateRange(ctx context.Context, updatedBy, startDate, endDate string, limit, offset int) ([]*models.Role, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("updated_by", base.OpEqual, updatedBy).
 		WhereBetween("updated_at", startDate, endDate).
 		Limit(limit, offset).
@@ -565,7 +613,7 @@ func (r *RoleRepository) GetByUpdatedByAndUpdatedDateRange(ctx context.Context,
 
 // GetByUpdatedByAndDeletedDateRange retrieves roles by updater and deleted date range using database-level filtering
 func (r *RoleRepository) GetByUpdatedByAndDeletedDateRange(ctx context.Context, updatedBy, startDate, endDate string, limit, offset int) ([]*models.Role, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Filter(true).
 		Where("updated_by", base.OpEqual, updatedBy).
 		WhereBetween("deleted_at", startDate, endDate).
 		Limit(limit, offset).
@@ -576,7 +624,7 @@ func (r *RoleRepository) GetByUpdatedByAndDeletedDateRange(ctx context.Context,
 
 // GetByDeletedByAndDateRange retrieves roles by deleter and date range using database-level filtering
 func (r *RoleRepository) GetByDeletedByAndDateRange(ctx context.Context, deletedBy, startDate, endDate string, limit, offset int) ([]*models.Role, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Filter(true).
 		Where("deleted_by", base.OpEqual, deletedBy).
 		WhereBetween("created_at", startDate, endDate).
 		Limit(limit, offset).
@@ -587,7 +635,7 @@ func (r *RoleRepository) GetByDeletedByAndDateRange(ctx context.Context, deleted
 
 // GetByDeletedByAndUpdatedDateRange retrieves roles by deleter and updated date range using database-level filtering
 func (r *RoleRepository) GetByDeletedByAndUpdatedDateRange(ctx context.Context, deletedBy, startDate, endDate string, limit, offset int) ([]*models.Role, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Filter(true).
 		Where("deleted_by", base.OpEqual, deletedBy).
 		WhereBetween("updated_at", startDate, endDate).
 		Limit(limit, offset).
@@ -598,7 +646,7 @@ func (r *RoleRepository) GetByDeletedByAndUpdatedDateRange(ctx context.Context,
 
 // GetByDeletedByAndDeletedDateRange retrieves roles by deleter and deleted date range using database-level filtering
 func (r *RoleRepository) GetByDeletedByAndDeletedDateRange(ctx context.Context, deletedBy, startDate, endDate string, limit, offset int) ([]*models.Role, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Filter(true).
 		Where("deleted_by", base.OpEqual, deletedBy).
 		WhereBetween("deleted_at", startDate, endDate).
 		Limit(limit, offset).
@@ -609,9 +657,29 @@ func (r *RoleRepository) GetByDeletedByAndDeletedDateRange(ctx context.Context,
 
 // GetChildRoles retrieves all direct children of a role
 func (r *RoleRepository) GetChildRoles(ctx context.Context, parentRoleID string) ([]*models.Role, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("parent_id", base.OpEqual, parentRoleID).
-		WhereNull("deleted_at").
+		Build()
+
+	return r.BaseFilterableRepository.Find(ctx, filter)
+}
+
+// GetGlobalRoles retrieves all non-deleted roles with global scope ordered by name
+func (r *RoleRepository) GetGlobalRoles(ctx context.Context) ([]*models.Role, error) {
+	filter := softdelete.Live().
+		Where("scope", base.OpEqual, string(models.RoleScopeGlobal)).
+		Sort("name", "asc").
+		Build()
+
+	return r.BaseFilterableRepository.Find(ctx, filter)
+}
+
+// GetByOrganization retrieves all non-deleted roles scoped to an organization ordered by name
+func (r *RoleRepository) GetByOrganization(ctx context.Context, organizationID string) ([]*models.Role, error) {
+	filter := softdelete.Live().
+		Where("scope", base.OpEqual, string(models.RoleScopeOrg)).
+		Where("organization_id", base.OpEqual, organizationID).
+		Sort("name", "asc").
 		Build()
 
 	return r.BaseFilterableRepository.Find(ctx, filter)
@@ -619,9 +687,22 @@ func (r *RoleRepository) GetChildRoles(ctx context.Context, parentRoleID string)
 
 // GetAll retrieves all roles (including deleted ones if specified)
 func (r *RoleRepository) GetAll(ctx context.Context) ([]*models.Role, error) {
-	filter := base.NewFilterBuilder().
-		WhereNull("deleted_at").
+	filter := softdelete.Live().
 		Build()
 
 	return r.BaseFilterableRepository.Find(ctx, filter)
 }
+
+// getDB is a helper method to get the GORM database connection from the database manager
+func (r *RoleRepository) getDB(ctx context.Context, readOnly bool) (*gorm.DB, error) {
+	if postgresMgr, ok := r.dbManager.(interface {
+		GetDB(context.Context, bool) (*gorm.DB, error)
+	}); ok {
+		gormDB, err := postgresMgr.GetDB(ctx, readOnly)
+		if err != nil {
+			return nil, err
+		}
+		return gormDB.WithContext(ctx), nil
+	}
+	return nil, fmt.Errorf("database manager does not support GetDB method")
+}
@@ -0,0 +1,79 @@
+package roles
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/kisanlink-db/pkg/base"
+	"github.com/Kisanlink/kisanlink-db/pkg/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// filterRecordingDBManager records the filter of every List and returns no rows
+type filterRecordingDBManager struct {
+	db.DBManager
+	filters []*base.Filter
+}
+
+func (m *filterRecordingDBManager) List(ctx context.Context, filter *base.Filter, model interface{}) error {
+	if _, ok := model.(*[]*models.Role); !ok {
+		return fmt.Errorf("unexpected model %T", model)
+	}
+	m.filters = append(m.filters, filter)
+	return nil
+}
+
+func excludesDeleted(filter *base.Filter) bool {
+	for _, condition := range filter.Group.Conditions {
+		if condition.Field == "deleted_at" && condition.Operator == base.OpIsNull {
+			return true
+		}
+	}
+	return false
+}
+
+func TestRoleRepository_StandardReadsExcludeSoftDeletedRoles(t *testing.T) {
+	ctx := context.Background()
+	reads := map[string]func(*RoleRepository) error{
+		"GetByNameAndDescription": func(r *RoleRepository) error {
+			_, err := r.GetByNameAndDescription(ctx, "admin", "administrators", 10, 0)
+			return err
+		},
+		"GetByNameAndPermission": func(r *RoleRepository) error {
+			_, err := r.GetByNameAndPermission(ctx, "admin", "users:read", 10, 0)
+			return err
+		},
+		"GetByNameAndCreatedBy": func(r *RoleRepository) error {
+			_, err := r.GetByNameAndCreatedBy(ctx, "admin", "USER1", 10, 0)
+			return err
+		},
+		"GetActive": func(r *RoleRepository) error {
+			_, err := r.GetActive(ctx, 10, 0)
+			return err
+		},
+		"GetByOrganization": func(r *RoleRepository) error {
+			_, err := r.GetByOrganization(ctx, "ORG1")
+			return err
+		},
+	}
+
+	for name, read := range reads {
+		t.Run(name, func(t *testing.T) {
+			manager := &filterRecordingDBManager{}
+			require.NoError(t, read(NewRoleRepository(manager)))
+			require.Len(t, manager.filters, 1)
+			assert.True(t, excludesDeleted(manager.filters[0]))
+		})
+	}
+}
+
+func TestRoleRepository_DeletedByReadsIncludeSoftDeletedRoles(t *testing.T) {
+	manager := &filterRecordingDBManager{}
+	_, err := NewRoleRepository(manager).GetByDeletedBy(context.Background(), "USER1", 10, 0)
+	require.NoError(t, err)
+	require.Len(t, manager.filters, 1)
+	assert.False(t, excludesDeleted(manager.filters[0]))
+}
@@ -0,0 +1,165 @@
+package roles
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/repositories/softdelete"
+	"github.com/Kisanlink/kisanlink-db/pkg/base"
+	"github.com/Kisanlink/kisanlink-db/pkg/db"
+	"gorm.io/gorm"
+)
+
+// ErrRoleTemplatePermissionsMissing is returned by ApplyToOrganization when the template refers to
+// permissions that do not exist. Nothing is created in that case.
+var ErrRoleTemplatePermissionsMissing = errors.New("role template references unknown permissions")
+
+// RoleTemplateRepository handles database operations for admin-defined role templates
+type RoleTemplateRepository struct {
+	*base.BaseFilterableRepository[*models.RoleTemplate]
+	dbManager db.DBManager
+}
+
+// NewRoleTemplateRepository creates a new RoleTemplateRepository instance
+func NewRoleTemplateRepository(dbManager db.DBManager) *RoleTemplateRepository {
+	baseRepo := base.NewBaseFilterableRepository[*models.RoleTemplate]()
+	baseRepo.SetDBManager(dbManager)
+	return &RoleTemplateRepository{
+		BaseFilterableRepository: baseRepo,
+		dbManager:                dbManager,
+	}
+}
+
+// List retrieves every stored role template ordered by name
+func (r *RoleTemplateRepository) List(ctx context.Context) ([]*models.RoleTemplate, error) {
+	filter := softdelete.Live().
+		Sort("name", "asc").
+		Build()
+
+	templates, err := r.BaseFilterableRepository.Find(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list role templates: %w", err)
+	}
+	return templates, nil
+}
+
+// GetByName retrieves a stored role template by name; it returns nil when there is none
+func (r *RoleTemplateRepository) GetByName(ctx context.Context, name string) (*models.RoleTemplate, error) {
+	filter := softdelete.Live().
+		Where("name", base.OpEqual, name).
+		Build()
+
+	templates, err := r.BaseFilterableRepository.Find(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get role template: %w", err)
+	}
+	if len(templates) == 0 {
+		return nil, nil
+	}
+	return templates[0], nil
+}
+
+// Create stores a new role template
+func (r *RoleTemplateRepository) Create(ctx context.Context, template *models.RoleTemplate) error {
+	if err := r.BaseFilterableRepository.Create(ctx, template); err != nil {
+		return fmt.Errorf("failed to create role template: %w", err)
+	}
+	return nil
+}
+
+// Delete permanently removes a stored role template. Roles already created from it are kept.
+func (r *RoleTemplateRepository) Delete(ctx context.Context, template *models.RoleTemplate) error {
+	if err := r.BaseFilterableRepository.Delete(ctx, template.ID, template); err != nil {
+		return fmt.Errorf("failed to delete role template: %w", err)
+	}
+	return nil
+}
+
+// ApplyToOrganization creates the template's roles as organization-scoped roles with their
+// permissions, in one transaction. Roles the organization already has, including soft-deleted
+// ones, are skipped so applying a template twice is harmless. The transaction is rolled back
+// with ErrRoleTemplatePermissionsMissing when a permission named by the template does not exist.
+func (r *RoleTemplateRepository) ApplyToOrganization(ctx context.Context, orgID string, template models.RoleTemplateDefinition, appliedBy string) (*models.RoleTemplateApplication, error) {
+	db, err := r.getDB(ctx, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	serviceID := models.OrganizationRoleServiceID(orgID)
+	result := &models.RoleTemplateApplication{}
+
+	err = db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		names := template.PermissionNames()
+		var permissions []models.Permission
+		if err := tx.Where("name IN ? AND deleted_at IS NULL", names).Find(&permissions).Error; err != nil {
+			return fmt.Errorf("failed to load permissions: %w", err)
+		}
+		permissionIDs := make(map[string]string, len(permissions))
+		for _, permission := range permissions {
+			permissionIDs[permission.Name] = permission.ID
+		}
+		var missing []string
+		for _, name := range names {
+			if _, ok := permissionIDs[name]; !ok {
+				missing = append(missing, name)
+			}
+		}
+		if len(missing) > 0 {
+			sort.Strings(missing)
+			return fmt.Errorf("%w: %s", ErrRoleTemplatePermissionsMissing, strings.Join(missing, ", "))
+		}
+
+		for _, templateRole := range template.Roles {
+			var count int64
+			if err := tx.Model(&models.Role{}).
+				Where("service_id = ? AND name = ?", serviceID, templateRole.Name).
+				Count(&count).Error; err != nil {
+				return fmt.Errorf("failed to check role %s: %w", templateRole.Name, err)
+			}
+			if count > 0 {
+				result.SkippedRoles = append(result.SkippedRoles, templateRole.Name)
+				continue
+			}
+
+			role := models.NewOrgRole(templateRole.Name, templateRole.Description, orgID)
+			role.ServiceID = serviceID
+			role.CreatedBy = appliedBy
+			role.UpdatedBy = appliedBy
+			if err := tx.Create(role).Error; err != nil {
+				return fmt.Errorf("failed to create role %s: %w", templateRole.Name, err)
+			}
+
+			for _, name := range templateRole.Permissions {
+				rolePermission := models.NewRolePermission(role.ID, permissionIDs[name])
+				rolePermission.CreatedBy = appliedBy
+				if err := tx.Create(rolePermission).Error; err != nil {
+					return fmt.Errorf("failed to attach permission %s to role %s: %w", name, templateRole.Name, err)
+				}
+			}
+			result.CreatedRoles = append(result.CreatedRoles, role)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// getDB retrieves the database connection from the DBManager
+func (r *RoleTemplateRepository) getDB(ctx context.Context, readOnly bool) (*gorm.DB, error) {
+	if postgresMgr, ok := r.dbManager.(interface {
+		GetDB(context.Context, bool) (*gorm.DB, error)
+	}); ok {
+		gormDB, err := postgresMgr.GetDB(ctx, readOnly)
+		if err != nil {
+			return nil, err
+		}
+		return gormDB.WithContext(ctx), nil
+	}
+	return nil, fmt.Errorf("database manager does not support GetDB method")
+}
@@ -3,8 +3,10 @@ package roles
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/repositories/softdelete"
 	"github.com/Kisanlink/kisanlink-db/pkg/base"
 	"github.com/Kisanlink/kisanlink-db/pkg/db"
 	"gorm.io/gorm"
@@ -59,7 +61,7 @@ func (r *UserRoleRepository) Delete(ctx context.Context, id string, userRole *mo
 
 // List retrieves user roles with pagination
 func (r *UserRoleRepository) List(ctx context.Context, limit, offset int) ([]*models.UserRole, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Limit(limit, offset).
 		Build()
 
@@ -80,7 +82,7 @@ func (r *UserRoleRepository) CountWithDeleted(ctx context.Context) (int64, error
 
 // GetByUserID retrieves all roles for a user using base filterable repository
 func (r *UserRoleRepository) GetByUserID(ctx context.Context, userID string) ([]*models.UserRole, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("user_id", base.OpEqual, userID).
 		Where("is_active", base.OpEqual, true).
 		Build()
@@ -90,7 +92,7 @@ func (r *UserRoleRepository) GetByUserID(ctx context.Context, userID string) ([]
 
 // GetByRoleID retrieves all users for a role using database-level filtering
 func (r *UserRoleRepository) GetByRoleID(ctx context.Context, roleID string) ([]*models.UserRole, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("role_id", base.OpEqual, roleID).
 		Where("is_active", base.OpEqual, true).
 		Build()
@@ -100,7 +102,7 @@ func (r *UserRoleRepository) GetByRoleID(ctx context.Context, roleID string) ([]
 
 // GetByUserAndRole retrieves a specific user role assignment using database-level filtering
 func (r *UserRoleRepository) GetByUserAndRole(ctx context.Context, userID, roleID string) (*models.UserRole, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("user_id", base.OpEqual, userID).
 		Where("role_id", base.OpEqual, roleID).
 		Where("is_active", base.OpEqual, true).
@@ -250,9 +252,35 @@ func (r *UserRoleRepository) RemoveRole(ctx context.Context, userID, roleID stri
 	})
 }
 
+// ReplaceDirectRoles assigns the roles in assign to a user and deactivates the user's direct
+// assignments of the roles in remove, in a single transaction. Roles inherited from groups are
+// left alone.
+func (r *UserRoleRepository) ReplaceDirectRoles(ctx context.Context, userID string, assign, remove []string) error {
+	db, err := r.getDB(ctx, false)
+	if err != nil {
+		return fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if len(remove) > 0 {
+			if err := tx.Model(&models.UserRole{}).
+				Where("user_id = ? AND role_id IN ? AND source_group_id IS NULL AND is_active = ? AND deleted_at IS NULL", userID, remove, true).
+				Update("is_active", false).Error; err != nil {
+				return fmt.Errorf("failed to deactivate user role assignments: %w", err)
+			}
+		}
+		for _, roleID := range assign {
+			if err := tx.Create(models.NewUserRole(userID, roleID)).Error; err != nil {
+				return fmt.Errorf("failed to create user role assignment for role %s: %w", roleID, err)
+			}
+		}
+		return nil
+	})
+}
+
 // IsRoleAssigned checks if a role is currently assigned to a user (active assignment)
 func (r *UserRoleRepository) IsRoleAssigned(ctx context.Context, userID, roleID string) (bool, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("user_id", base.OpEqual, userID).
 		Where("role_id", base.OpEqual, roleID).
 		Where("is_active", base.OpEqual, true).
@@ -266,13 +294,61 @@ func (r *UserRoleRepository) IsRoleAssigned(ctx context.Context, userID, roleID
 	return len(userRoles) > 0, nil
 }
 
+// ListUsersByRole retrieves a page of the users currently holding a role, ordered by user ID, and
+// the total number of them. Direct assignments count while active; assignments inherited from a
+// group also need the group role and the group membership to be active and within their validity
+// windows. Soft-deleted users, roles and assignments are excluded.
+func (r *UserRoleRepository) ListUsersByRole(ctx context.Context, roleID string, limit, offset int) ([]*models.User, int64, error) {
+	db, err := r.getDB(ctx, true)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	now := time.Now()
+	query := db.Model(&models.User{}).
+		Where("users.deleted_at IS NULL").
+		Where(`EXISTS (
+			SELECT 1 FROM user_roles ur
+			JOIN roles ON roles.id = ur.role_id AND roles.deleted_at IS NULL
+			WHERE ur.user_id = users.id AND ur.role_id = ? AND ur.is_active = true AND ur.deleted_at IS NULL
+			AND (ur.source_group_id IS NULL OR (
+				EXISTS (
+					SELECT 1 FROM group_roles gr
+					WHERE gr.group_id = ur.source_group_id AND gr.role_id = ur.role_id
+					AND gr.is_active = true AND gr.deleted_at IS NULL
+					AND (gr.starts_at IS NULL OR gr.starts_at <= ?) AND (gr.ends_at IS NULL OR gr.ends_at > ?)
+				) AND EXISTS (
+					SELECT 1 FROM group_memberships gm
+					WHERE gm.group_id = ur.source_group_id AND gm.principal_id = ur.user_id
+					AND gm.is_active = true AND gm.deleted_at IS NULL
+					AND (gm.starts_at IS NULL OR gm.starts_at <= ?) AND (gm.ends_at IS NULL OR gm.ends_at > ?)
+				)
+			))
+		)`, roleID, now, now, now, now)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count users by role: %w", err)
+	}
+
+	var users []*models.User
+	if err := query.Order("users.id ASC").Limit(limit).Offset(offset).Find(&users).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list users by role: %w", err)
+	}
+	return users, total, nil
+}
+
 // getDB is a helper method to get the database connection from the database manager
 func (r *UserRoleRepository) getDB(ctx context.Context, readOnly bool) (*gorm.DB, error) {
 	// Try to get the database from the database manager
 	if postgresMgr, ok := r.dbManager.(interface {
 		GetDB(context.Context, bool) (*gorm.DB, error)
 	}); ok {
-		return postgresMgr.GetDB(ctx, readOnly)
+		gormDB, err := postgresMgr.GetDB(ctx, readOnly)
+		if err != nil {
+			return nil, err
+		}
+		return gormDB.WithContext(ctx), nil
 	}
 
 	return nil, fmt.Errorf("database manager does not support GetDB method")
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/repositories/softdelete"
 	"github.com/Kisanlink/kisanlink-db/pkg/base"
 	"github.com/Kisanlink/kisanlink-db/pkg/db"
 	"gorm.io/gorm"
@@ -34,7 +35,11 @@ func (r *ServiceRoleMappingRepository) getDB(ctx context.Context, readOnly bool)
 	if postgresMgr, ok := r.dbManager.(interface {
 		GetDB(context.Context, bool) (*gorm.DB, error)
 	}); ok {
-		return postgresMgr.GetDB(ctx, readOnly)
+		gormDB, err := postgresMgr.GetDB(ctx, readOnly)
+		if err != nil {
+			return nil, err
+		}
+		return gormDB.WithContext(ctx), nil
 	}
 
 	return nil, fmt.Errorf("database manager does not support GetDB method")
@@ -64,7 +69,7 @@ func (r *ServiceRoleMappingRepository) Delete(ctx context.Context, id string) er
 
 // GetByServiceID retrieves all mappings for a specific service
 func (r *ServiceRoleMappingRepository) GetByServiceID(ctx context.Context, serviceID string) ([]*models.ServiceRoleMapping, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("service_id", base.OpEqual, serviceID).
 		Where("is_active", base.OpEqual, true).
 		Build()
@@ -74,7 +79,7 @@ func (r *ServiceRoleMappingRepository) GetByServiceID(ctx context.Context, servi
 
 // GetByRoleID retrieves all mappings for a specific role
 func (r *ServiceRoleMappingRepository) GetByRoleID(ctx context.Context, roleID string) ([]*models.ServiceRoleMapping, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("role_id", base.OpEqual, roleID).
 		Where("is_active", base.OpEqual, true).
 		Build()
@@ -84,7 +89,7 @@ func (r *ServiceRoleMappingRepository) GetByRoleID(ctx context.Context, roleID s
 
 // GetByServiceAndRole retrieves a specific service-role mapping
 func (r *ServiceRoleMappingRepository) GetByServiceAndRole(ctx context.Context, serviceID, roleID string) (*models.ServiceRoleMapping, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("service_id", base.OpEqual, serviceID).
 		Where("role_id", base.OpEqual, roleID).
 		Where("is_active", base.OpEqual, true).
@@ -104,7 +109,7 @@ func (r *ServiceRoleMappingRepository) GetByServiceAndRole(ctx context.Context,
 
 // ExistsByServiceAndRole checks if a service-role mapping exists
 func (r *ServiceRoleMappingRepository) ExistsByServiceAndRole(ctx context.Context, serviceID, roleID string) (bool, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("service_id", base.OpEqual, serviceID).
 		Where("role_id", base.OpEqual, roleID).
 		Where("is_active", base.OpEqual, true).
@@ -120,7 +125,7 @@ func (r *ServiceRoleMappingRepository) ExistsByServiceAndRole(ctx context.Contex
 
 // GetByServiceName retrieves all mappings for a specific service name
 func (r *ServiceRoleMappingRepository) GetByServiceName(ctx context.Context, serviceName string) ([]*models.ServiceRoleMapping, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("service_name", base.OpEqual, serviceName).
 		Where("is_active", base.OpEqual, true).
 		Build()
@@ -203,7 +208,7 @@ func (r *ServiceRoleMappingRepository) DeleteByServiceID(ctx context.Context, se
 
 // List retrieves all active service role mappings with pagination
 func (r *ServiceRoleMappingRepository) List(ctx context.Context, limit, offset int) ([]*models.ServiceRoleMapping, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("is_active", base.OpEqual, true).
 		Limit(limit, offset).
 		Build()
@@ -213,7 +218,7 @@ func (r *ServiceRoleMappingRepository) List(ctx context.Context, limit, offset i
 
 // Count returns the total number of active service role mappings
 func (r *ServiceRoleMappingRepository) Count(ctx context.Context) (int64, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("is_active", base.OpEqual, true).
 		Build()
 
@@ -30,7 +30,11 @@ func (r *SMSDeliveryRepository) getDB(ctx context.Context, readOnly bool) (*gorm
 	if postgresMgr, ok := r.dbManager.(interface {
 		GetDB(context.Context, bool) (*gorm.DB, error)
 	}); ok {
-		return postgresMgr.GetDB(ctx, readOnly)
+		gormDB, err := postgresMgr.GetDB(ctx, readOnly)
+		if err != nil {
+			return nil, err
+		}
+		return gormDB.WithContext(ctx), nil
 	}
 	return nil, fmt.Errorf("database manager does not support GetDB method")
 }
@@ -0,0 +1,51 @@
+// Package softdelete keeps soft-deleted rows out of standard reads. Every read through a
+// repository excludes rows with a deleted_at unless the caller explicitly asks to include them,
+// so a lookup by username, phone, Aadhaar or mobile number treats a soft-deleted account the same
+// way. Reads built on a FilterBuilder start from Filter; reads built directly on GORM add Scope.
+//
+// The Postgres database manager's List and Count exclude soft-deleted rows on their own, so reads
+// that include them (restore lookups, deletion reports) go through GORM with Scope(true).
+//
+// A few repository methods deliberately do not start from Live:
+//   - GetByID reads by primary key and returns soft-deleted rows, because callers such as Restore
+//     check DeletedAt to tell a deleted record from a missing one.
+//   - Reads by deleted_by or a deleted_at range start from Filter(true), since they ask for
+//     soft-deleted rows.
+//   - ExistingIDs takes includeDeleted from its caller.
+//   - Revocations, hard deletes, merges and cascades locate the rows they change and are writes,
+//     not standard reads.
+package softdelete
+
+import (
+	"github.com/Kisanlink/kisanlink-db/pkg/base"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Column is the column that marks a row as soft-deleted
+const Column = "deleted_at"
+
+// Filter starts a filter for a standard read, excluding soft-deleted rows unless includeDeleted is set
+func Filter(includeDeleted bool) *base.FilterBuilder {
+	fb := base.NewFilterBuilder()
+	if !includeDeleted {
+		fb.WhereNull(Column)
+	}
+	return fb
+}
+
+// Live starts a filter for a standard read of rows that are not soft-deleted
+func Live() *base.FilterBuilder {
+	return Filter(false)
+}
+
+// Scope excludes the soft-deleted rows of the queried table unless includeDeleted is set. The
+// column is qualified with the table, so the scope stays unambiguous in queries with joins.
+func Scope(includeDeleted bool) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if includeDeleted {
+			return db
+		}
+		return db.Where(clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: Column}, Value: nil})
+	}
+}
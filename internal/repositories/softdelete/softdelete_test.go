@@ -0,0 +1,46 @@
+package softdelete
+
+import (
+	"testing"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/kisanlink-db/pkg/base"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// dryRunDB returns a GORM connection that builds SQL without running it
+func dryRunDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost dbname=aaa"}), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+		Logger:               logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	return db
+}
+
+func TestFilter(t *testing.T) {
+	live := Live().Where("username", base.OpEqual, "ramesh").Build()
+	require.Len(t, live.Group.Conditions, 2)
+	assert.Equal(t, base.FilterCondition{Field: "deleted_at", Operator: base.OpIsNull}, live.Group.Conditions[0])
+
+	all := Filter(true).Where("username", base.OpEqual, "ramesh").Build()
+	require.Len(t, all.Group.Conditions, 1)
+	assert.Equal(t, "username", all.Group.Conditions[0].Field)
+}
+
+func TestScope(t *testing.T) {
+	db := dryRunDB(t)
+
+	var users []models.User
+	stmt := db.Scopes(Scope(false)).Where("status = ?", "active").Find(&users).Statement
+	assert.Contains(t, stmt.SQL.String(), `"users"."deleted_at" IS NULL`)
+
+	stmt = db.Scopes(Scope(true)).Where("status = ?", "active").Find(&users).Statement
+	assert.NotContains(t, stmt.SQL.String(), "deleted_at")
+}
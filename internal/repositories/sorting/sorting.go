@@ -0,0 +1,127 @@
+// Package sorting gives list queries a deterministic order. Offset pagination over an
+// unordered (or partially ordered) result set lets rows shift between pages, so every list
+// query ends with created_at and id as tie-breakers. Callers may choose a leading sort field
+// from a per-resource allowlist; the choice travels in the request context from the handler
+// to the repository, which re-checks it against the allowlist before it reaches SQL.
+package sorting
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Kisanlink/kisanlink-db/pkg/base"
+)
+
+// Sort directions accepted by the order query parameter
+const (
+	Asc  = "asc"
+	Desc = "desc"
+)
+
+// Fields sortable through the sort query parameter, per resource.
+// Names are column names; anything not listed is rejected.
+var (
+	UserFields         = []string{"created_at", "updated_at", "username", "phone_number", "status"}
+	RoleFields         = []string{"created_at", "updated_at", "name"}
+	OrganizationFields = []string{"created_at", "updated_at", "name", "type"}
+	GroupFields        = []string{"created_at", "updated_at", "name"}
+)
+
+type contextKey struct{}
+
+// Order is a caller-chosen sort for a list query
+type Order struct {
+	Field     string
+	Direction string
+}
+
+// Parse validates the sort and order query parameters against allowed.
+// An empty sort returns the zero Order, which keeps the default created_at, id ordering.
+func Parse(sort, order string, allowed []string) (Order, error) {
+	sort = strings.TrimSpace(sort)
+	order = strings.ToLower(strings.TrimSpace(order))
+
+	if sort == "" {
+		if order != "" {
+			return Order{}, fmt.Errorf("order requires a sort field")
+		}
+		return Order{}, nil
+	}
+	if !contains(allowed, sort) {
+		return Order{}, fmt.Errorf("invalid sort field %q: must be one of %s", sort, strings.Join(allowed, ", "))
+	}
+
+	switch order {
+	case "":
+		order = Asc
+	case Asc, Desc:
+	default:
+		return Order{}, fmt.Errorf("invalid order %q: must be asc or desc", order)
+	}
+
+	return Order{Field: sort, Direction: order}, nil
+}
+
+// WithOrder returns a context carrying order for the list queries made with it
+func WithOrder(ctx context.Context, order Order) context.Context {
+	if order.Field == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, contextKey{}, order)
+}
+
+// FromContext returns the order set by WithOrder, if any
+func FromContext(ctx context.Context) (Order, bool) {
+	order, ok := ctx.Value(contextKey{}).(Order)
+	return order, ok
+}
+
+// Fields returns the full sort for a list query: the order from ctx when its field is in
+// allowed, followed by the created_at and id tie-breakers.
+func Fields(ctx context.Context, allowed []string) []base.SortField {
+	fields := make([]base.SortField, 0, 3)
+	if order, ok := FromContext(ctx); ok && contains(allowed, order.Field) {
+		direction := Asc
+		if order.Direction == Desc {
+			direction = Desc
+		}
+		fields = append(fields, base.SortField{Field: order.Field, Direction: direction})
+	}
+
+	for _, tieBreaker := range []string{"created_at", "id"} {
+		if len(fields) > 0 && fields[0].Field == tieBreaker {
+			continue
+		}
+		fields = append(fields, base.SortField{Field: tieBreaker, Direction: Asc})
+	}
+	return fields
+}
+
+// Apply adds the sort returned by Fields to a filter builder
+func Apply(ctx context.Context, fb *base.FilterBuilder, allowed []string) *base.FilterBuilder {
+	for _, field := range Fields(ctx, allowed) {
+		fb.Sort(field.Field, field.Direction)
+	}
+	return fb
+}
+
+// OrderClause renders the sort returned by Fields as a GORM Order clause, qualifying each
+// column with table for queries that join other tables
+func OrderClause(ctx context.Context, allowed []string, table string) string {
+	fields := Fields(ctx, allowed)
+	parts := make([]string, len(fields))
+	for i, field := range fields {
+		parts[i] = fmt.Sprintf("%s.%s %s", table, field.Field, strings.ToUpper(field.Direction))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func contains(values []string, want string) bool {
+	for _, v := range values {
+		if v == want {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,71 @@
+package sorting
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Kisanlink/kisanlink-db/pkg/base"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		sort    string
+		order   string
+		want    Order
+		wantErr bool
+	}{
+		{name: "no sort keeps default", want: Order{}},
+		{name: "field defaults to ascending", sort: "name", want: Order{Field: "name", Direction: Asc}},
+		{name: "direction is case insensitive", sort: "name", order: "DESC", want: Order{Field: "name", Direction: Desc}},
+		{name: "field outside allowlist", sort: "password_hash", wantErr: true},
+		{name: "sql in field", sort: "name; DROP TABLE groups", wantErr: true},
+		{name: "invalid direction", sort: "name", order: "sideways", wantErr: true},
+		{name: "order without sort", order: "desc", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.sort, tt.order, GroupFields)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFields(t *testing.T) {
+	t.Run("default is created_at then id", func(t *testing.T) {
+		assert.Equal(t, []base.SortField{
+			{Field: "created_at", Direction: Asc},
+			{Field: "id", Direction: Asc},
+		}, Fields(context.Background(), GroupFields))
+	})
+
+	t.Run("caller field leads, tie-breakers follow", func(t *testing.T) {
+		ctx := WithOrder(context.Background(), Order{Field: "name", Direction: Desc})
+		assert.Equal(t, []base.SortField{
+			{Field: "name", Direction: Desc},
+			{Field: "created_at", Direction: Asc},
+			{Field: "id", Direction: Asc},
+		}, Fields(ctx, GroupFields))
+	})
+
+	t.Run("created_at is not repeated", func(t *testing.T) {
+		ctx := WithOrder(context.Background(), Order{Field: "created_at", Direction: Desc})
+		assert.Equal(t, []base.SortField{
+			{Field: "created_at", Direction: Desc},
+			{Field: "id", Direction: Asc},
+		}, Fields(ctx, GroupFields))
+	})
+
+	t.Run("field not allowed for the resource is ignored", func(t *testing.T) {
+		ctx := WithOrder(context.Background(), Order{Field: "username", Direction: Asc})
+		assert.Equal(t, "groups.created_at ASC, groups.id ASC", OrderClause(ctx, GroupFields, "groups"))
+	})
+}
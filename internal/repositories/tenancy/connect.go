@@ -0,0 +1,34 @@
+package tenancy
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Kisanlink/kisanlink-db/pkg/db"
+	"go.uber.org/zap"
+)
+
+// ConnectSchema opens a connection pool whose sessions search the tenant schema before public,
+// so every query of the pool, including preloads and raw SQL, resolves isolated tables in the
+// tenant schema
+func ConnectSchema(ctx context.Context, cfg db.Config, schema string, maxConns int, logger *zap.Logger) (db.DBManager, error) {
+	if !ValidSchemaName(schema) {
+		return nil, fmt.Errorf("invalid tenant schema name %q", schema)
+	}
+
+	// kisanlink-db builds a keyword/value DSN that ends with sslmode; pgx sends the extra
+	// search_path keyword as a run-time parameter when it opens each connection
+	cfg.PostgresSSLMode = fmt.Sprintf("%s search_path=%s,public", cfg.PostgresSSLMode, schema)
+	if maxConns > 0 {
+		cfg.PostgresMaxConns = maxConns
+		if cfg.PostgresIdleConns > maxConns {
+			cfg.PostgresIdleConns = maxConns
+		}
+	}
+
+	manager := db.NewPostgresManager(&cfg, logger.With(zap.String("tenant_schema", schema)))
+	if err := manager.Connect(ctx); err != nil {
+		return nil, fmt.Errorf("failed to connect tenant schema %s: %w", schema, err)
+	}
+	return manager, nil
+}
@@ -0,0 +1,249 @@
+package tenancy
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/Kisanlink/kisanlink-db/pkg/base"
+	"github.com/Kisanlink/kisanlink-db/pkg/db"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// Router is a db.DBManager that runs each operation on the database manager of the tenant schema
+// selected in the context, and on the shared manager otherwise. A context naming a schema the
+// router has no manager for fails instead of falling back to the shared schema.
+type Router struct {
+	db.DBManager
+	tenants map[string]db.DBManager
+	logger  *zap.Logger
+}
+
+// NewRouter creates a router over the shared manager and the managers of the isolated schemas,
+// keyed by schema name
+func NewRouter(shared db.DBManager, tenants map[string]db.DBManager, logger *zap.Logger) *Router {
+	if tenants == nil {
+		tenants = map[string]db.DBManager{}
+	}
+	return &Router{
+		DBManager: shared,
+		tenants:   tenants,
+		logger:    logger,
+	}
+}
+
+// IsIsolated reports whether the organization's data lives in its own schema
+func (r *Router) IsIsolated(orgID string) bool {
+	if orgID == "" {
+		return false
+	}
+	_, ok := r.tenants[SchemaName(orgID)]
+	return ok
+}
+
+// Schemas returns the isolated schemas the router serves
+func (r *Router) Schemas() []string {
+	schemas := make([]string, 0, len(r.tenants))
+	for schema := range r.tenants {
+		schemas = append(schemas, schema)
+	}
+	sort.Strings(schemas)
+	return schemas
+}
+
+// manager returns the database manager for the schema selected in the context
+func (r *Router) manager(ctx context.Context) (db.DBManager, error) {
+	schema, ok := SchemaFromContext(ctx)
+	if !ok {
+		return r.DBManager, nil
+	}
+	tenant, ok := r.tenants[schema]
+	if !ok {
+		r.logger.Error("No database configured for tenant schema", zap.String("schema", schema))
+		return nil, fmt.Errorf("no database configured for tenant schema %s", schema)
+	}
+	return tenant, nil
+}
+
+// GetDB returns the connection of the schema selected in the context
+func (r *Router) GetDB(ctx context.Context, readOnly bool) (*gorm.DB, error) {
+	m, err := r.manager(ctx)
+	if err != nil {
+		return nil, err
+	}
+	gormManager, ok := m.(interface {
+		GetDB(context.Context, bool) (*gorm.DB, error)
+	})
+	if !ok {
+		return nil, fmt.Errorf("database manager does not support GetDB method")
+	}
+	return gormManager.GetDB(ctx, readOnly)
+}
+
+// Close closes the tenant connections. The shared manager is closed by its owner.
+func (r *Router) Close() error {
+	var firstErr error
+	for schema, tenant := range r.tenants {
+		if err := tenant.Close(); err != nil {
+			r.logger.Warn("Failed to close tenant database", zap.String("schema", schema), zap.Error(err))
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	return firstErr
+}
+
+// Create creates a record in the selected schema
+func (r *Router) Create(ctx context.Context, model interface{}) error {
+	m, err := r.manager(ctx)
+	if err != nil {
+		return err
+	}
+	return m.Create(ctx, model)
+}
+
+// GetByID retrieves a record from the selected schema
+func (r *Router) GetByID(ctx context.Context, id interface{}, model interface{}) error {
+	m, err := r.manager(ctx)
+	if err != nil {
+		return err
+	}
+	return m.GetByID(ctx, id, model)
+}
+
+// Update updates a record in the selected schema
+func (r *Router) Update(ctx context.Context, model interface{}) error {
+	m, err := r.manager(ctx)
+	if err != nil {
+		return err
+	}
+	return m.Update(ctx, model)
+}
+
+// Delete deletes a record from the selected schema
+func (r *Router) Delete(ctx context.Context, id interface{}, model interface{}) error {
+	m, err := r.manager(ctx)
+	if err != nil {
+		return err
+	}
+	return m.Delete(ctx, id, model)
+}
+
+// SoftDelete soft deletes a record in the selected schema
+func (r *Router) SoftDelete(ctx context.Context, id interface{}, model interface{}, deletedBy string) error {
+	m, err := r.manager(ctx)
+	if err != nil {
+		return err
+	}
+	return m.SoftDelete(ctx, id, model, deletedBy)
+}
+
+// Restore restores a soft deleted record in the selected schema
+func (r *Router) Restore(ctx context.Context, id interface{}, model interface{}) error {
+	m, err := r.manager(ctx)
+	if err != nil {
+		return err
+	}
+	return m.Restore(ctx, id, model)
+}
+
+// List lists records of the selected schema
+func (r *Router) List(ctx context.Context, filter *base.Filter, model interface{}) error {
+	m, err := r.manager(ctx)
+	if err != nil {
+		return err
+	}
+	return m.List(ctx, filter, model)
+}
+
+// Count counts records of the selected schema
+func (r *Router) Count(ctx context.Context, filter *base.Filter, model interface{}) (int64, error) {
+	m, err := r.manager(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return m.Count(ctx, filter, model)
+}
+
+// ListWithDeleted lists records of the selected schema including soft deleted ones
+func (r *Router) ListWithDeleted(ctx context.Context, limit, offset int, models interface{}) error {
+	m, err := r.manager(ctx)
+	if err != nil {
+		return err
+	}
+	return m.ListWithDeleted(ctx, limit, offset, models)
+}
+
+// CountWithDeleted counts records of the selected schema including soft deleted ones
+func (r *Router) CountWithDeleted(ctx context.Context, model interface{}) (int64, error) {
+	m, err := r.manager(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return m.CountWithDeleted(ctx, model)
+}
+
+// ExistsWithDeleted checks whether a record exists in the selected schema including soft deleted ones
+func (r *Router) ExistsWithDeleted(ctx context.Context, id interface{}) (bool, error) {
+	m, err := r.manager(ctx)
+	if err != nil {
+		return false, err
+	}
+	return m.ExistsWithDeleted(ctx, id)
+}
+
+// GetByCreatedBy lists records of the selected schema created by a user
+func (r *Router) GetByCreatedBy(ctx context.Context, createdBy interface{}, limit, offset int, models interface{}) error {
+	m, err := r.manager(ctx)
+	if err != nil {
+		return err
+	}
+	return m.GetByCreatedBy(ctx, createdBy, limit, offset, models)
+}
+
+// GetByUpdatedBy lists records of the selected schema updated by a user
+func (r *Router) GetByUpdatedBy(ctx context.Context, updatedBy interface{}, limit, offset int, models interface{}) error {
+	m, err := r.manager(ctx)
+	if err != nil {
+		return err
+	}
+	return m.GetByUpdatedBy(ctx, updatedBy, limit, offset, models)
+}
+
+// GetByDeletedBy lists records of the selected schema deleted by a user
+func (r *Router) GetByDeletedBy(ctx context.Context, deletedBy interface{}, limit, offset int, models interface{}) error {
+	m, err := r.manager(ctx)
+	if err != nil {
+		return err
+	}
+	return m.GetByDeletedBy(ctx, deletedBy, limit, offset, models)
+}
+
+// CreateMany creates records in the selected schema
+func (r *Router) CreateMany(ctx context.Context, models []interface{}) error {
+	m, err := r.manager(ctx)
+	if err != nil {
+		return err
+	}
+	return m.CreateMany(ctx, models)
+}
+
+// UpdateMany updates records in the selected schema
+func (r *Router) UpdateMany(ctx context.Context, models []interface{}) error {
+	m, err := r.manager(ctx)
+	if err != nil {
+		return err
+	}
+	return m.UpdateMany(ctx, models)
+}
+
+// DeleteMany deletes records from the selected schema
+func (r *Router) DeleteMany(ctx context.Context, ids []interface{}) error {
+	m, err := r.manager(ctx)
+	if err != nil {
+		return err
+	}
+	return m.DeleteMany(ctx, ids)
+}
@@ -0,0 +1,55 @@
+// Package tenancy routes repository queries of isolated organizations to their own Postgres schema.
+//
+// Single-schema mode is the default: every organization shares the public schema. An organization
+// listed in AAA_TENANT_SCHEMA_ORGS keeps its isolated tables in the schema named by SchemaName,
+// provisioned with migrations.ProvisionTenantSchema. Requests carry the schema in their context
+// (WithOrgSchema) and repositories built on a Router run their queries in it. The tenant schema
+// is searched before public, so tables that are not isolated stay shared.
+package tenancy
+
+import (
+	"context"
+	"regexp"
+	"strings"
+)
+
+// SchemaPrefix starts the name of every tenant schema
+const SchemaPrefix = "tenant_"
+
+// maxSchemaNameLength is the Postgres identifier limit
+const maxSchemaNameLength = 63
+
+var (
+	invalidSchemaChars = regexp.MustCompile(`[^a-z0-9_]+`)
+	schemaNamePattern  = regexp.MustCompile(`^tenant_[a-z0-9_]+$`)
+)
+
+type schemaContextKey struct{}
+
+// SchemaName returns the schema that holds the isolated data of an organization
+func SchemaName(orgID string) string {
+	name := SchemaPrefix + invalidSchemaChars.ReplaceAllString(strings.ToLower(strings.TrimSpace(orgID)), "_")
+	if len(name) > maxSchemaNameLength {
+		name = name[:maxSchemaNameLength]
+	}
+	return name
+}
+
+// ValidSchemaName reports whether name is a tenant schema name that is safe to use unquoted in SQL
+func ValidSchemaName(name string) bool {
+	return len(name) <= maxSchemaNameLength && name != SchemaPrefix && schemaNamePattern.MatchString(name)
+}
+
+// WithOrgSchema returns a context whose repository queries run in the organization's schema
+func WithOrgSchema(ctx context.Context, orgID string) context.Context {
+	if strings.TrimSpace(orgID) == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, schemaContextKey{}, SchemaName(orgID))
+}
+
+// SchemaFromContext returns the tenant schema selected for the context, if any
+func SchemaFromContext(ctx context.Context) (string, bool) {
+	schema, ok := ctx.Value(schemaContextKey{}).(string)
+	return schema, ok && schema != ""
+}
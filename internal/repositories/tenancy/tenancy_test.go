@@ -0,0 +1,109 @@
+package tenancy
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/Kisanlink/kisanlink-db/pkg/base"
+	"github.com/Kisanlink/kisanlink-db/pkg/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// recordingManager records the operations routed to it
+type recordingManager struct {
+	db.DBManager
+	calls  []string
+	closed bool
+}
+
+func (m *recordingManager) Create(ctx context.Context, model interface{}) error {
+	m.calls = append(m.calls, "create")
+	return nil
+}
+
+func (m *recordingManager) List(ctx context.Context, filter *base.Filter, model interface{}) error {
+	m.calls = append(m.calls, "list")
+	return nil
+}
+
+func (m *recordingManager) Close() error {
+	m.closed = true
+	return nil
+}
+
+func TestSchemaName(t *testing.T) {
+	tests := []struct {
+		orgID string
+		want  string
+	}{
+		{"ORGN00000001", "tenant_orgn00000001"},
+		{" org-42.eu ", "tenant_org_42_eu"},
+		{"org'; DROP SCHEMA public; --", "tenant_org_drop_schema_public_"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.orgID, func(t *testing.T) {
+			schema := SchemaName(tt.orgID)
+			assert.Equal(t, tt.want, schema)
+			assert.True(t, ValidSchemaName(schema))
+		})
+	}
+
+	assert.Len(t, SchemaName(strings.Repeat("a", 100)), maxSchemaNameLength)
+	assert.False(t, ValidSchemaName("public"))
+	assert.False(t, ValidSchemaName("tenant_"))
+	assert.False(t, ValidSchemaName("tenant_a;b"))
+}
+
+func TestWithOrgSchema(t *testing.T) {
+	_, ok := SchemaFromContext(context.Background())
+	assert.False(t, ok)
+
+	_, ok = SchemaFromContext(WithOrgSchema(context.Background(), "  "))
+	assert.False(t, ok, "a blank organization keeps the shared schema")
+
+	schema, ok := SchemaFromContext(WithOrgSchema(context.Background(), "ORG1"))
+	require.True(t, ok)
+	assert.Equal(t, "tenant_org1", schema)
+}
+
+func TestRouter_RoutesBySchemaInContext(t *testing.T) {
+	shared := &recordingManager{}
+	tenant := &recordingManager{}
+	router := NewRouter(shared, map[string]db.DBManager{SchemaName("ORG1"): tenant}, zap.NewNop())
+
+	assert.True(t, router.IsIsolated("ORG1"))
+	assert.False(t, router.IsIsolated("ORG2"))
+	assert.False(t, router.IsIsolated(""))
+	assert.Equal(t, []string{"tenant_org1"}, router.Schemas())
+
+	require.NoError(t, router.Create(context.Background(), struct{}{}))
+	require.NoError(t, router.List(WithOrgSchema(context.Background(), "ORG1"), &base.Filter{}, nil))
+
+	assert.Equal(t, []string{"create"}, shared.calls)
+	assert.Equal(t, []string{"list"}, tenant.calls)
+}
+
+func TestRouter_UnknownSchemaDoesNotFallBack(t *testing.T) {
+	shared := &recordingManager{}
+	router := NewRouter(shared, nil, zap.NewNop())
+	ctx := WithOrgSchema(context.Background(), "ORG2")
+
+	err := router.Create(ctx, struct{}{})
+	assert.Error(t, err)
+	_, err = router.GetDB(ctx, false)
+	assert.Error(t, err)
+	assert.Empty(t, shared.calls, "the shared schema must never receive another tenant's data")
+}
+
+func TestRouter_CloseClosesTenantsOnly(t *testing.T) {
+	shared := &recordingManager{}
+	tenant := &recordingManager{}
+	router := NewRouter(shared, map[string]db.DBManager{SchemaName("ORG1"): tenant}, zap.NewNop())
+
+	require.NoError(t, router.Close())
+	assert.True(t, tenant.closed)
+	assert.False(t, shared.closed, "the shared manager is closed by its owner")
+}
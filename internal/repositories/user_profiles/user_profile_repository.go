@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/repositories/softdelete"
 	"github.com/Kisanlink/kisanlink-db/pkg/base"
 	"github.com/Kisanlink/kisanlink-db/pkg/db"
 )
@@ -49,7 +50,7 @@ func (r *UserProfileRepository) Delete(ctx context.Context, id string) error {
 
 // List retrieves user profiles with pagination using database-level filtering
 func (r *UserProfileRepository) List(ctx context.Context, limit, offset int) ([]*models.UserProfile, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Limit(limit, offset).
 		Build()
 
@@ -92,19 +93,29 @@ func (r *UserProfileRepository) ExistsWithDeleted(ctx context.Context, id string
 	return r.BaseFilterableRepository.ExistsWithDeleted(ctx, id)
 }
 
-// GetByCreatedBy gets user profiles by creator using the base repository
+// GetByCreatedBy gets non-deleted user profiles by creator using database-level filtering
 func (r *UserProfileRepository) GetByCreatedBy(ctx context.Context, createdBy string, limit, offset int) ([]*models.UserProfile, error) {
-	return r.BaseFilterableRepository.GetByCreatedBy(ctx, createdBy, limit, offset)
+	filter := softdelete.Live().
+		Where("created_by", base.OpEqual, createdBy).
+		Limit(limit, offset).
+		Build()
+
+	return r.BaseFilterableRepository.Find(ctx, filter)
 }
 
-// GetByUpdatedBy gets user profiles by updater using the base repository
+// GetByUpdatedBy gets non-deleted user profiles by updater using database-level filtering
 func (r *UserProfileRepository) GetByUpdatedBy(ctx context.Context, updatedBy string, limit, offset int) ([]*models.UserProfile, error) {
-	return r.BaseFilterableRepository.GetByUpdatedBy(ctx, updatedBy, limit, offset)
+	filter := softdelete.Live().
+		Where("updated_by", base.OpEqual, updatedBy).
+		Limit(limit, offset).
+		Build()
+
+	return r.BaseFilterableRepository.Find(ctx, filter)
 }
 
 // GetByUserID retrieves a user profile by user ID
 func (r *UserProfileRepository) GetByUserID(ctx context.Context, userID string) (*models.UserProfile, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("user_id", base.OpEqual, userID).
 		Build()
 
@@ -122,7 +133,7 @@ func (r *UserProfileRepository) GetByUserID(ctx context.Context, userID string)
 
 // GetByAadhaarNumber retrieves a user profile by Aadhaar number
 func (r *UserProfileRepository) GetByAadhaarNumber(ctx context.Context, aadhaarNumber string) (*models.UserProfile, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("aadhaar_number", base.OpEqual, aadhaarNumber).
 		Build()
 
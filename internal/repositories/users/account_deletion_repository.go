@@ -0,0 +1,77 @@
+package users
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/repositories/softdelete"
+	"github.com/Kisanlink/kisanlink-db/pkg/base"
+	"github.com/Kisanlink/kisanlink-db/pkg/db"
+)
+
+// AccountDeletionRepository handles database operations for user-initiated account deletion requests
+type AccountDeletionRepository struct {
+	*base.BaseFilterableRepository[*models.AccountDeletionRequest]
+	dbManager db.DBManager
+}
+
+// NewAccountDeletionRepository creates a new AccountDeletionRepository instance
+func NewAccountDeletionRepository(dbManager db.DBManager) *AccountDeletionRepository {
+	baseRepo := base.NewBaseFilterableRepository[*models.AccountDeletionRequest]()
+	baseRepo.SetDBManager(dbManager) // Connect the base repository to the actual database
+	return &AccountDeletionRepository{
+		BaseFilterableRepository: baseRepo,
+		dbManager:                dbManager,
+	}
+}
+
+// Create records a deletion request
+func (r *AccountDeletionRepository) Create(ctx context.Context, request *models.AccountDeletionRequest) error {
+	return r.BaseFilterableRepository.Create(ctx, request)
+}
+
+// Update saves the status of a deletion request
+func (r *AccountDeletionRepository) Update(ctx context.Context, request *models.AccountDeletionRequest) error {
+	return r.BaseFilterableRepository.Update(ctx, request)
+}
+
+// GetPendingByUserID retrieves the most recent pending deletion request of a user
+func (r *AccountDeletionRepository) GetPendingByUserID(ctx context.Context, userID string) (*models.AccountDeletionRequest, error) {
+	filter := softdelete.Live().
+		Where("user_id", base.OpEqual, userID).
+		Where("status", base.OpEqual, models.AccountDeletionStatusPending).
+		Sort("created_at", "desc").
+		Limit(1, 0).
+		Build()
+
+	requests, err := r.BaseFilterableRepository.Find(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending deletion request: %w", err)
+	}
+
+	if len(requests) == 0 {
+		return nil, fmt.Errorf("no pending deletion request for user: %s", userID)
+	}
+
+	return requests[0], nil
+}
+
+// ListDue retrieves up to limit pending deletion requests whose grace period ended by before,
+// longest overdue first
+func (r *AccountDeletionRepository) ListDue(ctx context.Context, before time.Time, limit int) ([]*models.AccountDeletionRequest, error) {
+	filter := softdelete.Live().
+		Where("status", base.OpEqual, models.AccountDeletionStatusPending).
+		Where("scheduled_for", base.OpLessEqual, before).
+		Sort("scheduled_for", "asc").
+		Limit(limit, 0).
+		Build()
+
+	requests, err := r.BaseFilterableRepository.Find(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list due deletion requests: %w", err)
+	}
+
+	return requests, nil
+}
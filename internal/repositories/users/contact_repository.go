@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/repositories/softdelete"
 	"github.com/Kisanlink/kisanlink-db/pkg/base"
 	"github.com/Kisanlink/kisanlink-db/pkg/db"
 )
@@ -36,7 +37,7 @@ func (r *ContactRepository) GetByID(ctx context.Context, id string) (*models.Con
 
 // GetByUserID retrieves contacts by user ID using database-level filtering
 func (r *ContactRepository) GetByUserID(ctx context.Context, userID string) ([]*models.Contact, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("user_id", base.OpEqual, userID).
 		Build()
 
@@ -45,7 +46,7 @@ func (r *ContactRepository) GetByUserID(ctx context.Context, userID string) ([]*
 
 // GetByMobileNumber retrieves a contact by mobile number using database-level filtering
 func (r *ContactRepository) GetByMobileNumber(ctx context.Context, mobileNumber uint64) (*models.Contact, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("mobile_number", base.OpEqual, mobileNumber).
 		Build()
 
@@ -74,7 +75,7 @@ func (r *ContactRepository) Delete(ctx context.Context, id string) error {
 
 // List retrieves a list of contacts with pagination using database-level filtering
 func (r *ContactRepository) List(ctx context.Context, limit, offset int) ([]*models.Contact, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Limit(limit, offset).
 		Build()
 
@@ -92,7 +93,7 @@ func (r *ContactRepository) ExistsByMobileNumber(ctx context.Context, mobileNumb
 
 // ExistsByUserIDAndMobileNumber checks if a contact exists for the given user ID and mobile number
 func (r *ContactRepository) ExistsByUserIDAndMobileNumber(ctx context.Context, userID string, mobileNumber uint64) (bool, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("user_id", base.OpEqual, userID).
 		Where("mobile_number", base.OpEqual, mobileNumber).
 		Build()
@@ -0,0 +1,76 @@
+package users
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/repositories/softdelete"
+	"github.com/Kisanlink/kisanlink-db/pkg/base"
+	"github.com/Kisanlink/kisanlink-db/pkg/db"
+)
+
+// UserDeviceRepository handles database operations for device-bound MPINs
+type UserDeviceRepository struct {
+	*base.BaseFilterableRepository[*models.UserDevice]
+	dbManager db.DBManager
+}
+
+// NewUserDeviceRepository creates a new UserDeviceRepository instance
+func NewUserDeviceRepository(dbManager db.DBManager) *UserDeviceRepository {
+	baseRepo := base.NewBaseFilterableRepository[*models.UserDevice]()
+	baseRepo.SetDBManager(dbManager) // Connect the base repository to the actual database
+	return &UserDeviceRepository{
+		BaseFilterableRepository: baseRepo,
+		dbManager:                dbManager,
+	}
+}
+
+// Create registers a device of a user
+func (r *UserDeviceRepository) Create(ctx context.Context, device *models.UserDevice) error {
+	return r.BaseFilterableRepository.Create(ctx, device)
+}
+
+// Update saves the MPIN and attempt state of a device
+func (r *UserDeviceRepository) Update(ctx context.Context, device *models.UserDevice) error {
+	return r.BaseFilterableRepository.Update(ctx, device)
+}
+
+// GetByUserAndDevice retrieves a device of a user by the client's device identifier
+func (r *UserDeviceRepository) GetByUserAndDevice(ctx context.Context, userID, deviceID string) (*models.UserDevice, error) {
+	filter := softdelete.Live().
+		Where("user_id", base.OpEqual, userID).
+		Where("device_id", base.OpEqual, deviceID).
+		Build()
+
+	devices, err := r.BaseFilterableRepository.Find(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get device by user and device ID: %w", err)
+	}
+
+	if len(devices) == 0 {
+		return nil, fmt.Errorf("device not found: %s", deviceID)
+	}
+
+	return devices[0], nil
+}
+
+// ListByUserID retrieves the devices of a user, most recently registered first
+func (r *UserDeviceRepository) ListByUserID(ctx context.Context, userID string) ([]*models.UserDevice, error) {
+	filter := softdelete.Live().
+		Where("user_id", base.OpEqual, userID).
+		Sort("created_at", "desc").
+		Build()
+
+	devices, err := r.BaseFilterableRepository.Find(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list devices by user ID: %w", err)
+	}
+
+	return devices, nil
+}
+
+// Delete removes a device and its MPIN
+func (r *UserDeviceRepository) Delete(ctx context.Context, id string) error {
+	return r.BaseFilterableRepository.Delete(ctx, id, &models.UserDevice{})
+}
@@ -0,0 +1,112 @@
+package users
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/repositories/softdelete"
+	"github.com/Kisanlink/kisanlink-db/pkg/base"
+	"github.com/Kisanlink/kisanlink-db/pkg/db"
+	"gorm.io/gorm"
+)
+
+// UserIdentityRepository handles database operations for external identity links
+type UserIdentityRepository struct {
+	*base.BaseFilterableRepository[*models.UserIdentity]
+	dbManager db.DBManager
+}
+
+// NewUserIdentityRepository creates a new UserIdentityRepository instance
+func NewUserIdentityRepository(dbManager db.DBManager) *UserIdentityRepository {
+	baseRepo := base.NewBaseFilterableRepository[*models.UserIdentity]()
+	baseRepo.SetDBManager(dbManager) // Connect the base repository to the actual database
+	return &UserIdentityRepository{
+		BaseFilterableRepository: baseRepo,
+		dbManager:                dbManager,
+	}
+}
+
+// Create links a user to an external identity
+func (r *UserIdentityRepository) Create(ctx context.Context, identity *models.UserIdentity) error {
+	return r.BaseFilterableRepository.Create(ctx, identity)
+}
+
+// GetByProviderAndSubject retrieves the identity link for an IdP subject
+func (r *UserIdentityRepository) GetByProviderAndSubject(ctx context.Context, provider, subject string) (*models.UserIdentity, error) {
+	filter := softdelete.Live().
+		Where("provider", base.OpEqual, provider).
+		Where("subject", base.OpEqual, subject).
+		Build()
+
+	identities, err := r.BaseFilterableRepository.Find(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get identity by provider and subject: %w", err)
+	}
+
+	if len(identities) == 0 {
+		return nil, fmt.Errorf("identity not found for provider %s", provider)
+	}
+
+	return identities[0], nil
+}
+
+// GetByUserID retrieves all external identities linked to a user
+func (r *UserIdentityRepository) GetByUserID(ctx context.Context, userID string) ([]*models.UserIdentity, error) {
+	filter := softdelete.Live().
+		Where("user_id", base.OpEqual, userID).
+		Sort("created_at", "asc").
+		Build()
+
+	identities, err := r.BaseFilterableRepository.Find(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get identities by user ID: %w", err)
+	}
+
+	return identities, nil
+}
+
+// ExistsByUserAndProvider checks whether a user already has an identity linked at a provider
+func (r *UserIdentityRepository) ExistsByUserAndProvider(ctx context.Context, userID, provider string) (bool, error) {
+	filter := softdelete.Live().
+		Where("user_id", base.OpEqual, userID).
+		Where("provider", base.OpEqual, provider).
+		Build()
+
+	count, err := r.BaseFilterableRepository.Count(ctx, filter, &models.UserIdentity{})
+	if err != nil {
+		return false, fmt.Errorf("failed to check identity existence: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+// GetUserIDByVerifiedEmail finds the owner of a verified email contact.
+// Unverified contacts are ignored so that an unconfirmed address can never be used to link an account.
+func (r *UserIdentityRepository) GetUserIDByVerifiedEmail(ctx context.Context, email string) (string, error) {
+	postgresMgr, ok := r.dbManager.(interface {
+		GetDB(context.Context, bool) (*gorm.DB, error)
+	})
+	if !ok {
+		return "", fmt.Errorf("database manager does not support GetDB method")
+	}
+
+	gormDB, err := postgresMgr.GetDB(ctx, true)
+	if err != nil {
+		return "", fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	var contact models.Contact
+	err = gormDB.WithContext(ctx).
+		Where("type = ? AND LOWER(value) = LOWER(?) AND is_verified = ? AND deleted_at IS NULL", models.ContactTypeEmail, email, true).
+		Order("created_at ASC").
+		First(&contact).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return "", fmt.Errorf("no verified contact found with email: %s", email)
+		}
+		return "", fmt.Errorf("failed to search verified email in contacts: %w", err)
+	}
+
+	return contact.UserID, nil
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/repositories/softdelete"
 	"github.com/Kisanlink/kisanlink-db/pkg/base"
 	"github.com/Kisanlink/kisanlink-db/pkg/db"
 )
@@ -38,7 +39,7 @@ func (r *UserProfileRepository) GetByID(ctx context.Context, id string) (*models
 
 // GetByUserID retrieves a user profile by user ID using database-level filtering
 func (r *UserProfileRepository) GetByUserID(ctx context.Context, userID string) (*models.UserProfile, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("user_id", base.OpEqual, userID).
 		Build()
 
@@ -67,7 +68,7 @@ func (r *UserProfileRepository) Delete(ctx context.Context, id string) error {
 
 // List retrieves a list of user profiles with pagination using database-level filtering
 func (r *UserProfileRepository) List(ctx context.Context, limit, offset int) ([]*models.UserProfile, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Limit(limit, offset).
 		Build()
 
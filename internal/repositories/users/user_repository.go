@@ -7,6 +7,9 @@ import (
 	"time"
 
 	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/repositories/softdelete"
+	"github.com/Kisanlink/aaa-service/v2/internal/repositories/sorting"
+	"github.com/Kisanlink/aaa-service/v2/pkg/phonenumber"
 	"github.com/Kisanlink/kisanlink-db/pkg/base"
 	"github.com/Kisanlink/kisanlink-db/pkg/db"
 	"golang.org/x/crypto/bcrypt"
@@ -39,6 +42,59 @@ func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
 	return r.BaseFilterableRepository.Create(ctx, user)
 }
 
+// CreateWithRoles creates a user and their initial role assignments in one transaction, so a new
+// user is never left without the roles they were registered with
+func (r *UserRepository) CreateWithRoles(ctx context.Context, user *models.User, userRoles []*models.UserRole) error {
+	db, err := r.getDB(ctx, false)
+	if err != nil {
+		return fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+		for _, userRole := range userRoles {
+			if err := tx.Create(userRole).Error; err != nil {
+				return fmt.Errorf("failed to create user role assignment: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// RestoreWithRoles restores a soft-deleted user with the fields of user, deactivates the role
+// assignments the account held before it was deleted and creates userRoles, in one transaction, so a
+// reclaimed account never comes back with half its old state or its old roles
+func (r *UserRepository) RestoreWithRoles(ctx context.Context, user *models.User, userRoles []*models.UserRole) error {
+	db, err := r.getDB(ctx, false)
+	if err != nil {
+		return fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		// Select("*") writes the cleared columns too, such as deleted_at and m_pin
+		result := tx.Model(user).Where("deleted_at IS NOT NULL").Select("*").Updates(user)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("soft-deleted record with id %v not found in table users", user.ID)
+		}
+		if err := tx.Model(&models.UserRole{}).
+			Where("user_id = ? AND is_active = ? AND deleted_at IS NULL", user.ID, true).
+			Update("is_active", false).Error; err != nil {
+			return fmt.Errorf("failed to deactivate prior role assignments: %w", err)
+		}
+		for _, userRole := range userRoles {
+			if err := tx.Create(userRole).Error; err != nil {
+				return fmt.Errorf("failed to create user role assignment: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
 // GetByID retrieves a user by ID with active roles preloaded
 func (r *UserRepository) GetByID(ctx context.Context, id string, user *models.User) (*models.User, error) {
 	// Use GetWithActiveRoles for efficient loading with preloaded roles
@@ -96,9 +152,8 @@ func (r *UserRepository) Restore(ctx context.Context, id string) error {
 // List retrieves active (non-deleted) users with pagination
 // Note: Roles are NOT preloaded for performance. Use GetUserByID for full role details.
 func (r *UserRepository) List(ctx context.Context, limit, offset int) ([]*models.User, error) {
-	filter := base.NewFilterBuilder().
-		WhereNull("deleted_at"). // Only get users that are not soft-deleted
-		Sort("id", "asc").       // Default sort by ID ascending
+	fb := softdelete.Live()
+	filter := sorting.Apply(ctx, fb, sorting.UserFields).
 		Limit(limit, offset).
 		Build()
 
@@ -113,9 +168,9 @@ func (r *UserRepository) List(ctx context.Context, limit, offset int) ([]*models
 // ListAll retrieves all active (non-deleted) users
 // Note: Roles are NOT preloaded for performance. Use GetUserByID for full role details.
 func (r *UserRepository) ListAll(ctx context.Context) ([]*models.User, error) {
-	filter := base.NewFilterBuilder().
-		WhereNull("deleted_at"). // Only get users that are not soft-deleted
-		Page(1, 1000).           // Get up to 1000 users
+	fb := softdelete.Live()
+	filter := sorting.Apply(ctx, fb, sorting.UserFields).
+		Page(1, 1000). // Get up to 1000 users
 		Build()
 
 	users, err := r.BaseFilterableRepository.Find(ctx, filter)
@@ -152,17 +207,46 @@ func (r *UserRepository) ExistsWithDeleted(ctx context.Context, id string) (bool
 	return r.BaseFilterableRepository.ExistsWithDeleted(ctx, id)
 }
 
-// GetByCreatedBy gets users by creator using the base repository
+// ExistingIDs returns the IDs among ids that belong to a user, in a single query. Soft-deleted
+// users count only when includeDeleted is set.
+func (r *UserRepository) ExistingIDs(ctx context.Context, ids []string, includeDeleted bool) ([]string, error) {
+	db, err := r.getDB(ctx, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	query := db.Model(&models.User{}).Where("id IN ?", ids)
+	if !includeDeleted {
+		query = query.Where("deleted_at IS NULL")
+	}
+	var existing []string
+	if err := query.Pluck("id", &existing).Error; err != nil {
+		return nil, fmt.Errorf("failed to check user existence: %w", err)
+	}
+	return existing, nil
+}
+
+// GetByCreatedBy gets non-deleted users by creator using database-level filtering
 func (r *UserRepository) GetByCreatedBy(ctx context.Context, createdBy string, limit, offset int) ([]*models.User, error) {
-	return r.BaseFilterableRepository.GetByCreatedBy(ctx, createdBy, limit, offset)
+	filter := softdelete.Live().
+		Where("created_by", base.OpEqual, createdBy).
+		Limit(limit, offset).
+		Build()
+
+	return r.BaseFilterableRepository.Find(ctx, filter)
 }
 
-// GetByUpdatedBy gets users by updater using the base repository
+// GetByUpdatedBy gets non-deleted users by updater using database-level filtering
 func (r *UserRepository) GetByUpdatedBy(ctx context.Context, updatedBy string, limit, offset int) ([]*models.User, error) {
-	return r.BaseFilterableRepository.GetByUpdatedBy(ctx, updatedBy, limit, offset)
+	filter := softdelete.Live().
+		Where("updated_by", base.OpEqual, updatedBy).
+		Limit(limit, offset).
+		Build()
+
+	return r.BaseFilterableRepository.Find(ctx, filter)
 }
 
-// GetByDeletedBy gets users by deleter using the base repository
+// GetByDeletedBy gets soft-deleted users by deleter using the base repository
 func (r *UserRepository) GetByDeletedBy(ctx context.Context, deletedBy string, limit, offset int) ([]*models.User, error) {
 	return r.BaseFilterableRepository.GetByDeletedBy(ctx, deletedBy, limit, offset)
 }
@@ -190,7 +274,11 @@ func (r *UserRepository) getDB(ctx context.Context, readOnly bool) (*gorm.DB, er
 	if postgresMgr, ok := r.dbManager.(interface {
 		GetDB(context.Context, bool) (*gorm.DB, error)
 	}); ok {
-		return postgresMgr.GetDB(ctx, readOnly)
+		gormDB, err := postgresMgr.GetDB(ctx, readOnly)
+		if err != nil {
+			return nil, err
+		}
+		return gormDB.WithContext(ctx), nil
 	}
 
 	return nil, fmt.Errorf("database manager does not support GetDB method")
@@ -198,9 +286,8 @@ func (r *UserRepository) getDB(ctx context.Context, readOnly bool) (*gorm.DB, er
 
 // GetByUsername retrieves an active (non-deleted) user by username with active roles preloaded
 func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*models.User, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("username", base.OpEqual, username).
-		WhereNull("deleted_at"). // Only get users that are not soft-deleted
 		Build()
 
 	// Use the base repository's Find method
@@ -217,12 +304,40 @@ func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*m
 	return r.GetWithActiveRoles(ctx, users[0].ID)
 }
 
-// GetByPhoneNumber retrieves an active (non-deleted) user by phone number with active roles preloaded
+// GetByUsernameIgnoreCase retrieves an active (non-deleted) user whose username matches regardless of case,
+// with active roles preloaded
+func (r *UserRepository) GetByUsernameIgnoreCase(ctx context.Context, username string) (*models.User, error) {
+	db, err := r.getDB(ctx, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	var ids []string
+	if err := db.Model(&models.User{}).
+		Where("LOWER(username) = LOWER(?)", username).
+		Where("deleted_at IS NULL").
+		Limit(1).
+		Pluck("id", &ids).Error; err != nil {
+		return nil, fmt.Errorf("failed to get user by username: %w", err)
+	}
+
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("user not found with username: %s", username)
+	}
+
+	return r.GetWithActiveRoles(ctx, ids[0])
+}
+
+// GetByPhoneNumber retrieves an active (non-deleted) user by phone number with active roles preloaded.
+// The input is normalized first, so "098765-43210" and "+91 98765 43210" find the same user.
 func (r *UserRepository) GetByPhoneNumber(ctx context.Context, phoneNumber string, countryCode string) (*models.User, error) {
-	filter := base.NewFilterBuilder().
-		Where("phone_number", base.OpEqual, phoneNumber).
-		Where("country_code", base.OpEqual, countryCode).
-		WhereNull("deleted_at"). // Only get users that are not soft-deleted
+	number, err := phonenumber.Parse(phoneNumber, countryCode)
+	if err != nil {
+		return nil, fmt.Errorf("user not found with phone number: %s%s: %w", countryCode, phoneNumber, err)
+	}
+
+	filter := softdelete.Live().
+		Where("phone_e164", base.OpEqual, number.E164()).
 		Build()
 
 	// Use the base repository's Find method
@@ -231,17 +346,56 @@ func (r *UserRepository) GetByPhoneNumber(ctx context.Context, phoneNumber strin
 		return nil, fmt.Errorf("failed to get user by phone number: %w", err)
 	}
 
+	// Rows written before normalization have no phone_e164 until they are backfilled
 	if len(users) == 0 {
-		return nil, fmt.Errorf("user not found with phone number: %s%s", countryCode, phoneNumber)
+		filter = softdelete.Live().
+			Where("phone_number", base.OpEqual, number.NationalNumber).
+			Where("country_code", base.OpEqual, number.CountryCode).
+			WhereNull("phone_e164").
+			Build()
+
+		users, err = r.BaseFilterableRepository.Find(ctx, filter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get user by phone number: %w", err)
+		}
+	}
+
+	if len(users) == 0 {
+		return nil, fmt.Errorf("user not found with phone number: %s", number.E164())
 	}
 
 	// Get the user with active roles preloaded
 	return r.GetWithActiveRoles(ctx, users[0].ID)
 }
 
+// GetByPhoneNumberIncludingDeleted retrieves every user, live or soft-deleted, that has held a phone number.
+// Results are newest first so admins can find and restore a prior account instead of creating a duplicate.
+func (r *UserRepository) GetByPhoneNumberIncludingDeleted(ctx context.Context, phoneNumber string, countryCode string) ([]*models.User, error) {
+	number, err := phonenumber.Parse(phoneNumber, countryCode)
+	if err != nil {
+		return nil, fmt.Errorf("invalid phone number %s%s: %w", countryCode, phoneNumber, err)
+	}
+
+	db, err := r.getDB(ctx, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	var users []*models.User
+	err = db.WithContext(ctx).
+		Where("phone_e164 = ? OR (phone_e164 IS NULL AND phone_number = ? AND country_code = ?)", number.E164(), number.NationalNumber, number.CountryCode).
+		Order("created_at DESC").
+		Find(&users).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get users by phone number including deleted: %w", err)
+	}
+
+	return users, nil
+}
+
 // GetByMobileNumber retrieves a user by mobile number using database-level filtering
 func (r *UserRepository) GetByMobileNumber(ctx context.Context, mobileNumber uint64) (*models.User, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("mobile_number", base.OpEqual, mobileNumber).
 		Build()
 
@@ -259,7 +413,7 @@ func (r *UserRepository) GetByMobileNumber(ctx context.Context, mobileNumber uin
 
 // GetByAadhaarNumber retrieves a user by Aadhaar number using database-level filtering
 func (r *UserRepository) GetByAadhaarNumber(ctx context.Context, aadhaarNumber string) (*models.User, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("aadhaar_number", base.OpEqual, aadhaarNumber).
 		Build()
 
@@ -277,10 +431,11 @@ func (r *UserRepository) GetByAadhaarNumber(ctx context.Context, aadhaarNumber s
 
 // ListActive retrieves all active users with preloaded active roles
 func (r *UserRepository) ListActive(ctx context.Context, limit, offset int) ([]*models.User, error) {
-	filter := base.NewFilterBuilder().
+	fb := softdelete.Live().
 		Where("status", base.OpEqual, "active").
-		Preload("Roles", "is_active = ?", true).      // Preload only active user roles
-		Preload("Roles.Role", "is_active = ?", true). // Preload only active roles
+		Preload("Roles", "is_active = ?", true).     // Preload only active user roles
+		Preload("Roles.Role", "is_active = ?", true) // Preload only active roles
+	filter := sorting.Apply(ctx, fb, sorting.UserFields).
 		Limit(limit, offset).
 		Build()
 
@@ -306,16 +461,14 @@ func (r *UserRepository) ListActive(ctx context.Context, limit, offset int) ([]*
 // CountActive returns the total number of active (non-deleted) users using database-level counting
 // This matches the filter used in List() to ensure accurate pagination
 func (r *UserRepository) CountActive(ctx context.Context) (int64, error) {
-	filter := base.NewFilterBuilder().
-		WhereNull("deleted_at"). // Match the List() filter - only non-deleted users
-		Build()
+	filter := softdelete.Live().Build()
 
 	return r.BaseFilterableRepository.CountWithFilter(ctx, filter)
 }
 
 // GetWithAddress retrieves a user with their addresses using efficient preloading
 func (r *UserRepository) GetWithAddress(ctx context.Context, userID string) (*models.User, error) {
-	// Get the GORM DB instance from the postgres manager
+	// Get the GORM DB instance from the database manager
 	db, err := r.getDB(ctx, true) // Use read-only for efficiency
 	if err != nil {
 		return nil, fmt.Errorf("failed to get database connection: %w", err)
@@ -345,7 +498,7 @@ func (r *UserRepository) GetWithAddress(ctx context.Context, userID string) (*mo
 
 // GetWithProfile retrieves a user with their profile using efficient preloading
 func (r *UserRepository) GetWithProfile(ctx context.Context, userID string) (*models.User, error) {
-	// Get the GORM DB instance from the postgres manager
+	// Get the GORM DB instance from the database manager
 	db, err := r.getDB(ctx, true) // Use read-only for efficiency
 	if err != nil {
 		return nil, fmt.Errorf("failed to get database connection: %w", err)
@@ -376,6 +529,43 @@ func (r *UserRepository) GetWithProfile(ctx context.Context, userID string) (*mo
 	return user, nil
 }
 
+// GetWithDetails retrieves a user with the requested relationships in a single preloaded query.
+// Addresses are those of the profile and the contacts; roles are the active direct assignments.
+func (r *UserRepository) GetWithDetails(ctx context.Context, userID string, includeProfile, includeContacts, includeAddresses, includeRoles bool) (*models.User, error) {
+	db, err := r.getDB(ctx, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	// Initialize user pointer with BaseModel to allow GORM to scan into it
+	user := &models.User{
+		BaseModel: &base.BaseModel{},
+	}
+
+	query := db.WithContext(ctx)
+	if includeProfile || includeAddresses {
+		query = query.Preload("Profile").Preload("Profile.Address")
+	}
+	if includeContacts || includeAddresses {
+		query = query.Preload("Contacts", "deleted_at IS NULL").Preload("Contacts.Address")
+	}
+	if includeRoles {
+		query = query.
+			Preload("Roles", "is_active = ?", true).
+			Preload("Roles.Role", "is_active = ?", true)
+	}
+
+	err = query.Where("id = ? AND deleted_at IS NULL", userID).First(user).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("user with id %s not found", userID)
+		}
+		return nil, fmt.Errorf("failed to get user with details: %w", err)
+	}
+
+	return user, nil
+}
+
 // Search searches for users by keyword in username using the BaseFilterableRepository
 // with case-insensitive contains and proper pagination.
 // Note: Roles are NOT preloaded for performance. Use GetUserByID for full role details.
@@ -394,12 +584,13 @@ func (r *UserRepository) Search(ctx context.Context, keyword string, limit, offs
 	}
 
 	// Use database-level search with BaseFilterableRepository
-	filter := base.NewFilterBuilder().
+	fb := softdelete.Live().
 		Or(
 			base.FilterCondition{Field: "username", Operator: base.OpContains, Value: keyword},
 			base.FilterCondition{Field: "phone_number", Operator: base.OpContains, Value: keyword},
 		).
-		WhereNull("deleted_at"). // Only get users that are not soft-deleted
+		WhereNull("deleted_at") // Only get users that are not soft-deleted
+	filter := sorting.Apply(ctx, fb, sorting.UserFields).
 		Limit(limit, offset).
 		Build()
 
@@ -419,25 +610,24 @@ func (r *UserRepository) SearchCount(ctx context.Context, keyword string) (int64
 	}
 
 	// Create filter with same search criteria but without pagination
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Or(
 			base.FilterCondition{Field: "username", Operator: base.OpContains, Value: keyword},
 			base.FilterCondition{Field: "phone_number", Operator: base.OpContains, Value: keyword},
 		).
-		WhereNull("deleted_at").
 		Build()
 
 	return r.BaseFilterableRepository.CountWithFilter(ctx, filter)
 }
 
-// GetUsersWithRelationships efficiently loads multiple users with their relationships using goroutines
+// GetUsersWithRelationships efficiently loads multiple non-deleted users with their relationships using goroutines
 func (r *UserRepository) GetUsersWithRelationships(ctx context.Context, userIDs []string, includeRoles, includeProfile, includeAddresses bool) ([]*models.User, error) {
 	if len(userIDs) == 0 {
 		return []*models.User{}, nil
 	}
 
-	// Get the GORM DB instance from the postgres manager
-	db, err := r.dbManager.(*db.PostgresManager).GetDB(ctx, false)
+	// Get the GORM DB instance from the database manager
+	db, err := r.getDB(ctx, false)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get database connection: %w", err)
 	}
@@ -448,7 +638,7 @@ func (r *UserRepository) GetUsersWithRelationships(ctx context.Context, userIDs
 
 	go func() {
 		var users []models.User
-		query := db.Where("id IN ?", userIDs)
+		query := db.Scopes(softdelete.Scope(false)).Where("id IN ?", userIDs)
 
 		// Add preloads based on requested relationships
 		if includeRoles {
@@ -541,8 +731,8 @@ func (r *UserRepository) BulkValidateUsers(ctx context.Context, userIDs []string
 		return nil
 	}
 
-	// Get the GORM DB instance from the postgres manager
-	db, err := r.dbManager.(*db.PostgresManager).GetDB(ctx, false)
+	// Get the GORM DB instance from the database manager
+	db, err := r.getDB(ctx, false)
 	if err != nil {
 		return fmt.Errorf("failed to get database connection: %w", err)
 	}
@@ -587,7 +777,37 @@ func (r *UserRepository) BulkValidateUsers(ctx context.Context, userIDs []string
 	return nil
 }
 
-// GetByEmail retrieves a user by email using database-level filtering
+// UpdateLastLogin records the time and client IP of a successful sign-in.
+// Only the two columns are written so a concurrent profile update is not overwritten;
+// an empty ipAddress leaves the stored IP unchanged.
+func (r *UserRepository) UpdateLastLogin(ctx context.Context, userID, ipAddress string) error {
+	db, err := r.getDB(ctx, false)
+	if err != nil {
+		return fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	updates := map[string]interface{}{
+		"last_login_at": time.Now(),
+	}
+	if ipAddress != "" {
+		updates["last_login_ip"] = ipAddress
+	}
+
+	result := db.WithContext(ctx).
+		Model(&models.User{}).
+		Where("id = ? AND deleted_at IS NULL", userID).
+		UpdateColumns(updates)
+	if result.Error != nil {
+		return fmt.Errorf("failed to update last login: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("user not found: %s", userID)
+	}
+
+	return nil
+}
+
+// GetByEmail retrieves a non-deleted user by a non-deleted email contact using database-level filtering
 func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
 	// Get database connection
 	db, err := r.getDB(ctx, true)
@@ -595,25 +815,24 @@ func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*models.
 		return nil, fmt.Errorf("failed to get database connection: %w", err)
 	}
 
-	// Search for user by email in contacts table
-	var contact models.Contact
-	err = db.WithContext(ctx).
-		Where("type = ? AND value = ?", "email", email).
-		First(&contact).Error
+	// Search for the user owning the email in the contacts table
+	contactUsers := db.Model(&models.Contact{}).
+		Scopes(softdelete.Scope(false)).
+		Select("user_id").
+		Where("type = ? AND value = ?", "email", email)
 
+	// Initialize user pointer with BaseModel to allow GORM to scan into it
+	user := &models.User{
+		BaseModel: &base.BaseModel{},
+	}
+	err = db.Scopes(softdelete.Scope(false)).
+		Where("id IN (?)", contactUsers).
+		First(user).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, fmt.Errorf("user not found with email: %s", email)
 		}
-		return nil, fmt.Errorf("failed to search email in contacts: %w", err)
-	}
-
-	// Get the user by ID - Initialize user pointer with BaseModel
-	user := &models.User{
-		BaseModel: &base.BaseModel{},
-	}
-	if err := r.dbManager.GetByID(ctx, contact.UserID, user); err != nil {
-		return nil, fmt.Errorf("failed to get user: %w", err)
+		return nil, fmt.Errorf("failed to get user by email: %w", err)
 	}
 
 	return user, nil
@@ -621,7 +840,7 @@ func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*models.
 
 // GetByStatus retrieves users by status using database-level filtering
 func (r *UserRepository) GetByStatus(ctx context.Context, status string, limit, offset int) ([]*models.User, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("status", base.OpEqual, status).
 		Limit(limit, offset).
 		Build()
@@ -631,7 +850,7 @@ func (r *UserRepository) GetByStatus(ctx context.Context, status string, limit,
 
 // GetByValidationStatus retrieves users by validation status using database-level filtering
 func (r *UserRepository) GetByValidationStatus(ctx context.Context, isValidated bool, limit, offset int) ([]*models.User, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("is_validated", base.OpEqual, isValidated).
 		Limit(limit, offset).
 		Build()
@@ -641,7 +860,7 @@ func (r *UserRepository) GetByValidationStatus(ctx context.Context, isValidated
 
 // GetByDateRange retrieves users created within a date range using database-level filtering
 func (r *UserRepository) GetByDateRange(ctx context.Context, startDate, endDate string, limit, offset int) ([]*models.User, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		WhereBetween("created_at", startDate, endDate).
 		Limit(limit, offset).
 		Build()
@@ -651,7 +870,7 @@ func (r *UserRepository) GetByDateRange(ctx context.Context, startDate, endDate
 
 // GetByUpdatedDateRange retrieves users updated within a date range using database-level filtering
 func (r *UserRepository) GetByUpdatedDateRange(ctx context.Context, startDate, endDate string, limit, offset int) ([]*models.User, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		WhereBetween("updated_at", startDate, endDate).
 		Limit(limit, offset).
 		Build()
@@ -661,17 +880,12 @@ func (r *UserRepository) GetByUpdatedDateRange(ctx context.Context, startDate, e
 
 // GetByDeletedDateRange retrieves users deleted within a date range using database-level filtering
 func (r *UserRepository) GetByDeletedDateRange(ctx context.Context, startDate, endDate string, limit, offset int) ([]*models.User, error) {
-	filter := base.NewFilterBuilder().
-		WhereBetween("deleted_at", startDate, endDate).
-		Limit(limit, offset).
-		Build()
-
-	return r.BaseFilterableRepository.Find(ctx, filter)
+	return r.findDeletedBetween(ctx, nil, startDate, endDate, limit, offset)
 }
 
 // GetByUsernameAndStatus retrieves users by username and status using database-level filtering
 func (r *UserRepository) GetByUsernameAndStatus(ctx context.Context, username, status string, limit, offset int) ([]*models.User, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("username", base.OpEqual, username).
 		Where("status", base.OpEqual, status).
 		Limit(limit, offset).
@@ -682,7 +896,7 @@ func (r *UserRepository) GetByUsernameAndStatus(ctx context.Context, username, s
 
 // GetByUsernameAndValidationStatus retrieves users by username and validation status using database-level filtering
 func (r *UserRepository) GetByUsernameAndValidationStatus(ctx context.Context, username string, isValidated bool, limit, offset int) ([]*models.User, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("username", base.OpEqual, username).
 		Where("is_validated", base.OpEqual, isValidated).
 		Limit(limit, offset).
@@ -693,7 +907,7 @@ func (r *UserRepository) GetByUsernameAndValidationStatus(ctx context.Context, u
 
 // GetByUsernameAndDateRange retrieves users by username and date range using database-level filtering
 func (r *UserRepository) GetByUsernameAndDateRange(ctx context.Context, username, startDate, endDate string, limit, offset int) ([]*models.User, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("username", base.OpEqual, username).
 		WhereBetween("created_at", startDate, endDate).
 		Limit(limit, offset).
@@ -704,7 +918,7 @@ func (r *UserRepository) GetByUsernameAndDateRange(ctx context.Context, username
 
 // GetByUsernameAndUpdatedDateRange retrieves users by username and updated date range using database-level filtering
 func (r *UserRepository) GetByUsernameAndUpdatedDateRange(ctx context.Context, username, startDate, endDate string, limit, offset int) ([]*models.User, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("username", base.OpEqual, username).
 		WhereBetween("updated_at", startDate, endDate).
 		Limit(limit, offset).
@@ -715,18 +929,12 @@ func (r *UserRepository) GetByUsernameAndUpdatedDateRange(ctx context.Context, u
 
 // GetByUsernameAndDeletedDateRange retrieves users by username and deleted date range using database-level filtering
 func (r *UserRepository) GetByUsernameAndDeletedDateRange(ctx context.Context, username, startDate, endDate string, limit, offset int) ([]*models.User, error) {
-	filter := base.NewFilterBuilder().
-		Where("username", base.OpEqual, username).
-		WhereBetween("deleted_at", startDate, endDate).
-		Limit(limit, offset).
-		Build()
-
-	return r.BaseFilterableRepository.Find(ctx, filter)
+	return r.findDeletedBetween(ctx, map[string]interface{}{"username": username}, startDate, endDate, limit, offset)
 }
 
 // GetByStatusAndValidationStatus retrieves users by status and validation status using database-level filtering
 func (r *UserRepository) GetByStatusAndValidationStatus(ctx context.Context, status string, isValidated bool, limit, offset int) ([]*models.User, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("status", base.OpEqual, status).
 		Where("is_validated", base.OpEqual, isValidated).
 		Limit(limit, offset).
@@ -737,7 +945,7 @@ func (r *UserRepository) GetByStatusAndValidationStatus(ctx context.Context, sta
 
 // GetByStatusAndDateRange retrieves users by status and date range using database-level filtering
 func (r *UserRepository) GetByStatusAndDateRange(ctx context.Context, status, startDate, endDate string, limit, offset int) ([]*models.User, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("status", base.OpEqual, status).
 		WhereBetween("created_at", startDate, endDate).
 		Limit(limit, offset).
@@ -748,7 +956,7 @@ func (r *UserRepository) GetByStatusAndDateRange(ctx context.Context, status, st
 
 // GetByStatusAndUpdatedDateRange retrieves users by status and updated date range using database-level filtering
 func (r *UserRepository) GetByStatusAndUpdatedDateRange(ctx context.Context, status, startDate, endDate string, limit, offset int) ([]*models.User, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("status", base.OpEqual, status).
 		WhereBetween("updated_at", startDate, endDate).
 		Limit(limit, offset).
@@ -759,18 +967,12 @@ func (r *UserRepository) GetByStatusAndUpdatedDateRange(ctx context.Context, sta
 
 // GetByStatusAndDeletedDateRange retrieves users by status and deleted date range using database-level filtering
 func (r *UserRepository) GetByStatusAndDeletedDateRange(ctx context.Context, status, startDate, endDate string, limit, offset int) ([]*models.User, error) {
-	filter := base.NewFilterBuilder().
-		Where("status", base.OpEqual, status).
-		WhereBetween("deleted_at", startDate, endDate).
-		Limit(limit, offset).
-		Build()
-
-	return r.BaseFilterableRepository.Find(ctx, filter)
+	return r.findDeletedBetween(ctx, map[string]interface{}{"status": status}, startDate, endDate, limit, offset)
 }
 
 // GetByValidationStatusAndDateRange retrieves users by validation status and date range using database-level filtering
 func (r *UserRepository) GetByValidationStatusAndDateRange(ctx context.Context, isValidated bool, startDate, endDate string, limit, offset int) ([]*models.User, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("is_validated", base.OpEqual, isValidated).
 		WhereBetween("created_at", startDate, endDate).
 		Limit(limit, offset).
@@ -781,7 +983,7 @@ func (r *UserRepository) GetByValidationStatusAndDateRange(ctx context.Context,
 
 // GetByValidationStatusAndUpdatedDateRange retrieves users by validation status and updated date range using database-level filtering
 func (r *UserRepository) GetByValidationStatusAndUpdatedDateRange(ctx context.Context, isValidated bool, startDate, endDate string, limit, offset int) ([]*models.User, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("is_validated", base.OpEqual, isValidated).
 		WhereBetween("updated_at", startDate, endDate).
 		Limit(limit, offset).
@@ -792,13 +994,34 @@ func (r *UserRepository) GetByValidationStatusAndUpdatedDateRange(ctx context.Co
 
 // GetByValidationStatusAndDeletedDateRange retrieves users by validation status and deleted date range using database-level filtering
 func (r *UserRepository) GetByValidationStatusAndDeletedDateRange(ctx context.Context, isValidated bool, startDate, endDate string, limit, offset int) ([]*models.User, error) {
-	filter := base.NewFilterBuilder().
-		Where("is_validated", base.OpEqual, isValidated).
-		WhereBetween("deleted_at", startDate, endDate).
-		Limit(limit, offset).
-		Build()
+	return r.findDeletedBetween(ctx, map[string]interface{}{"is_validated": isValidated}, startDate, endDate, limit, offset)
+}
 
-	return r.BaseFilterableRepository.Find(ctx, filter)
+// findDeletedBetween retrieves users soft-deleted within a date range that match the conditions.
+// The database manager's reads always exclude soft-deleted users, so this queries GORM directly.
+func (r *UserRepository) findDeletedBetween(ctx context.Context, conditions map[string]interface{}, startDate, endDate string, limit, offset int) ([]*models.User, error) {
+	db, err := r.getDB(ctx, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	query := db.Scopes(softdelete.Scope(true)).
+		Where("deleted_at BETWEEN ? AND ?", startDate, endDate)
+	if len(conditions) > 0 {
+		query = query.Where(conditions)
+	}
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if offset > 0 {
+		query = query.Offset(offset)
+	}
+
+	var users []*models.User
+	if err := query.Order("deleted_at DESC").Find(&users).Error; err != nil {
+		return nil, fmt.Errorf("failed to get deleted users: %w", err)
+	}
+	return users, nil
 }
 
 // SoftDeleteWithCascade performs a soft delete of a user with proper cascade operations for related entities
@@ -956,7 +1179,7 @@ func (r *UserRepository) VerifyMPin(ctx context.Context, userID, plainMPin strin
 
 // GetByUsernameAndStatusAndValidationStatus retrieves users by username, status and validation status using database-level filtering
 func (r *UserRepository) GetByUsernameAndStatusAndValidationStatus(ctx context.Context, username, status string, isValidated bool, limit, offset int) ([]*models.User, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("username", base.OpEqual, username).
 		Where("status", base.OpEqual, status).
 		Where("is_validated", base.OpEqual, isValidated).
@@ -968,7 +1191,7 @@ func (r *UserRepository) GetByUsernameAndStatusAndValidationStatus(ctx context.C
 
 // GetByUsernameAndStatusAndDateRange retrieves users by username, status and date range using database-level filtering
 func (r *UserRepository) GetByUsernameAndStatusAndDateRange(ctx context.Context, username, status, startDate, endDate string, limit, offset int) ([]*models.User, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("username", base.OpEqual, username).
 		Where("status", base.OpEqual, status).
 		WhereBetween("created_at", startDate, endDate).
@@ -980,7 +1203,7 @@ func (r *UserRepository) GetByUsernameAndStatusAndDateRange(ctx context.Context,
 
 // GetByUsernameAndStatusAndUpdatedDateRange retrieves users by username, status and updated date range using database-level filtering
 func (r *UserRepository) GetByUsernameAndStatusAndUpdatedDateRange(ctx context.Context, username, status, startDate, endDate string, limit, offset int) ([]*models.User, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("username", base.OpEqual, username).
 		Where("status", base.OpEqual, status).
 		WhereBetween("updated_at", startDate, endDate).
@@ -992,19 +1215,12 @@ func (r *UserRepository) GetByUsernameAndStatusAndUpdatedDateRange(ctx context.C
 
 // GetByUsernameAndStatusAndDeletedDateRange retrieves users by username, status and deleted date range using database-level filtering
 func (r *UserRepository) GetByUsernameAndStatusAndDeletedDateRange(ctx context.Context, username, status, startDate, endDate string, limit, offset int) ([]*models.User, error) {
-	filter := base.NewFilterBuilder().
-		Where("username", base.OpEqual, username).
-		Where("status", base.OpEqual, status).
-		WhereBetween("deleted_at", startDate, endDate).
-		Limit(limit, offset).
-		Build()
-
-	return r.BaseFilterableRepository.Find(ctx, filter)
+	return r.findDeletedBetween(ctx, map[string]interface{}{"username": username, "status": status}, startDate, endDate, limit, offset)
 }
 
 // GetByUsernameAndValidationStatusAndDateRange retrieves users by username, validation status and date range using database-level filtering
 func (r *UserRepository) GetByUsernameAndValidationStatusAndDateRange(ctx context.Context, username string, isValidated bool, startDate, endDate string, limit, offset int) ([]*models.User, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("username", base.OpEqual, username).
 		Where("is_validated", base.OpEqual, isValidated).
 		WhereBetween("created_at", startDate, endDate).
@@ -1016,7 +1232,7 @@ func (r *UserRepository) GetByUsernameAndValidationStatusAndDateRange(ctx contex
 
 // GetByUsernameAndValidationStatusAndUpdatedDateRange retrieves users by username, validation status and updated date range using database-level filtering
 func (r *UserRepository) GetByUsernameAndValidationStatusAndUpdatedDateRange(ctx context.Context, username string, isValidated bool, startDate, endDate string, limit, offset int) ([]*models.User, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("username", base.OpEqual, username).
 		Where("is_validated", base.OpEqual, isValidated).
 		WhereBetween("updated_at", startDate, endDate).
@@ -1028,19 +1244,12 @@ func (r *UserRepository) GetByUsernameAndValidationStatusAndUpdatedDateRange(ctx
 
 // GetByUsernameAndValidationStatusAndDeletedDateRange retrieves users by username, validation status and deleted date range using database-level filtering
 func (r *UserRepository) GetByUsernameAndValidationStatusAndDeletedDateRange(ctx context.Context, username string, isValidated bool, startDate, endDate string, limit, offset int) ([]*models.User, error) {
-	filter := base.NewFilterBuilder().
-		Where("username", base.OpEqual, username).
-		Where("is_validated", base.OpEqual, isValidated).
-		WhereBetween("deleted_at", startDate, endDate).
-		Limit(limit, offset).
-		Build()
-
-	return r.BaseFilterableRepository.Find(ctx, filter)
+	return r.findDeletedBetween(ctx, map[string]interface{}{"username": username, "is_validated": isValidated}, startDate, endDate, limit, offset)
 }
 
 // GetByStatusAndValidationStatusAndDateRange retrieves users by status, validation status and date range using database-level filtering
 func (r *UserRepository) GetByStatusAndValidationStatusAndDateRange(ctx context.Context, status string, isValidated bool, startDate, endDate string, limit, offset int) ([]*models.User, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("status", base.OpEqual, status).
 		Where("is_validated", base.OpEqual, isValidated).
 		WhereBetween("created_at", startDate, endDate).
@@ -1052,7 +1261,7 @@ func (r *UserRepository) GetByStatusAndValidationStatusAndDateRange(ctx context.
 
 // GetByStatusAndValidationStatusAndUpdatedDateRange retrieves users by status, validation status and updated date range using database-level filtering
 func (r *UserRepository) GetByStatusAndValidationStatusAndUpdatedDateRange(ctx context.Context, status string, isValidated bool, startDate, endDate string, limit, offset int) ([]*models.User, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("status", base.OpEqual, status).
 		Where("is_validated", base.OpEqual, isValidated).
 		WhereBetween("updated_at", startDate, endDate).
@@ -1064,19 +1273,12 @@ func (r *UserRepository) GetByStatusAndValidationStatusAndUpdatedDateRange(ctx c
 
 // GetByStatusAndValidationStatusAndDeletedDateRange retrieves users by status, validation status and deleted date range using database-level filtering
 func (r *UserRepository) GetByStatusAndValidationStatusAndDeletedDateRange(ctx context.Context, status string, isValidated bool, startDate, endDate string, limit, offset int) ([]*models.User, error) {
-	filter := base.NewFilterBuilder().
-		Where("status", base.OpEqual, status).
-		Where("is_validated", base.OpEqual, isValidated).
-		WhereBetween("deleted_at", startDate, endDate).
-		Limit(limit, offset).
-		Build()
-
-	return r.BaseFilterableRepository.Find(ctx, filter)
+	return r.findDeletedBetween(ctx, map[string]interface{}{"status": status, "is_validated": isValidated}, startDate, endDate, limit, offset)
 }
 
 // GetByUsernameAndStatusAndValidationStatusAndDateRange retrieves users by username, status, validation status and date range using database-level filtering
 func (r *UserRepository) GetByUsernameAndStatusAndValidationStatusAndDateRange(ctx context.Context, username, status string, isValidated bool, startDate, endDate string, limit, offset int) ([]*models.User, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("username", base.OpEqual, username).
 		Where("status", base.OpEqual, status).
 		Where("is_validated", base.OpEqual, isValidated).
@@ -1089,7 +1291,7 @@ func (r *UserRepository) GetByUsernameAndStatusAndValidationStatusAndDateRange(c
 
 // GetByUsernameAndStatusAndValidationStatusAndUpdatedDateRange retrieves users by username, status, validation status and updated date range using database-level filtering
 func (r *UserRepository) GetByUsernameAndStatusAndValidationStatusAndUpdatedDateRange(ctx context.Context, username, status string, isValidated bool, startDate, endDate string, limit, offset int) ([]*models.User, error) {
-	filter := base.NewFilterBuilder().
+	filter := softdelete.Live().
 		Where("username", base.OpEqual, username).
 		Where("status", base.OpEqual, status).
 		Where("is_validated", base.OpEqual, isValidated).
@@ -1102,15 +1304,7 @@ func (r *UserRepository) GetByUsernameAndStatusAndValidationStatusAndUpdatedDate
 
 // GetByUsernameAndStatusAndValidationStatusAndDeletedDateRange retrieves users by username, status, validation status and deleted date range using database-level filtering
 func (r *UserRepository) GetByUsernameAndStatusAndValidationStatusAndDeletedDateRange(ctx context.Context, username, status string, isValidated bool, startDate, endDate string, limit, offset int) ([]*models.User, error) {
-	filter := base.NewFilterBuilder().
-		Where("username", base.OpEqual, username).
-		Where("status", base.OpEqual, status).
-		Where("is_validated", base.OpEqual, isValidated).
-		WhereBetween("deleted_at", startDate, endDate).
-		Limit(limit, offset).
-		Build()
-
-	return r.BaseFilterableRepository.Find(ctx, filter)
+	return r.findDeletedBetween(ctx, map[string]interface{}{"username": username, "status": status, "is_validated": isValidated}, startDate, endDate, limit, offset)
 }
 
 // SearchWithOrgScope searches for users by keyword filtered by organization membership
@@ -1127,7 +1321,7 @@ func (r *UserRepository) SearchWithOrgScope(ctx context.Context, keyword string,
 	}
 
 	// Get the GORM DB instance for complex query
-	gormDB, err := r.dbManager.(*db.PostgresManager).GetDB(ctx, true)
+	gormDB, err := r.getDB(ctx, true)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get database connection: %w", err)
 	}
@@ -1155,7 +1349,7 @@ func (r *UserRepository) SearchWithOrgScope(ctx context.Context, keyword string,
 	}
 
 	// Add pagination and ordering
-	query = query.Order("users.id ASC").Offset(offset).Limit(limit)
+	query = query.Order(sorting.OrderClause(ctx, sorting.UserFields, "users")).Offset(offset).Limit(limit)
 
 	var users []*models.User
 	if err := query.Find(&users).Error; err != nil {
@@ -1169,7 +1363,7 @@ func (r *UserRepository) SearchWithOrgScope(ctx context.Context, keyword string,
 // Performance optimized: Uses EXISTS subquery for consistency with SearchWithOrgScope
 func (r *UserRepository) SearchCountWithOrgScope(ctx context.Context, keyword string, organizationIDs []string) (int64, error) {
 	// Get the GORM DB instance for complex query
-	gormDB, err := r.dbManager.(*db.PostgresManager).GetDB(ctx, true)
+	gormDB, err := r.getDB(ctx, true)
 	if err != nil {
 		return 0, fmt.Errorf("failed to get database connection: %w", err)
 	}
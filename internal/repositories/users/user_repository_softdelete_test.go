@@ -0,0 +1,243 @@
+package users
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/kisanlink-db/pkg/base"
+	"github.com/Kisanlink/kisanlink-db/pkg/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// softDeleteDBManager serves List from memory without dropping soft-deleted rows itself, so only
+// the repository's own filter keeps them out. GORM queries are built but not run, and their SQL is
+// recorded.
+type softDeleteDBManager struct {
+	db.DBManager
+	users   []*models.User
+	filters []*base.Filter
+	gormDB  *gorm.DB
+	queries []string
+}
+
+func newSoftDeleteDBManager(t *testing.T) *softDeleteDBManager {
+	t.Helper()
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost dbname=aaa"}), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+		Logger:               logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+
+	deletedAt := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	deleted := models.NewUser("9876543210", "+91", "password")
+	deleted.ID = "USER_DELETED"
+	deleted.DeletedAt = &deletedAt
+	live := models.NewUser("9876543210", "+91", "password")
+	live.ID = "USER_LIVE"
+
+	m := &softDeleteDBManager{users: []*models.User{deleted, live}, gormDB: gormDB}
+	err = gormDB.Callback().Query().After("gorm:query").Register("test:record_sql", func(tx *gorm.DB) {
+		m.queries = append(m.queries, tx.Statement.SQL.String())
+	})
+	require.NoError(t, err)
+	return m
+}
+
+func (m *softDeleteDBManager) List(ctx context.Context, filter *base.Filter, model interface{}) error {
+	out, ok := model.(*[]*models.User)
+	if !ok {
+		return fmt.Errorf("unexpected model %T", model)
+	}
+	m.filters = append(m.filters, filter)
+
+	rows := []*models.User{}
+	for _, user := range m.users {
+		if user.DeletedAt != nil && excludesDeleted(filter) {
+			continue
+		}
+		rows = append(rows, user)
+	}
+	*out = rows
+	return nil
+}
+
+func (m *softDeleteDBManager) GetDB(ctx context.Context, readOnly bool) (*gorm.DB, error) {
+	return m.gormDB, nil
+}
+
+func excludesDeleted(filter *base.Filter) bool {
+	for _, condition := range filter.Group.Conditions {
+		if condition.Field == "deleted_at" && condition.Operator == base.OpIsNull {
+			return true
+		}
+	}
+	return false
+}
+
+func userIDs(users []*models.User) []string {
+	ids := []string{}
+	for _, user := range users {
+		ids = append(ids, user.ID)
+	}
+	return ids
+}
+
+func TestUserRepository_GetByExcludesSoftDeleted(t *testing.T) {
+	const from, to = "2025-01-01", "2025-12-31"
+	one := func(user *models.User, err error) ([]*models.User, error) {
+		if err != nil {
+			return nil, err
+		}
+		return []*models.User{user}, nil
+	}
+
+	tests := []struct {
+		name string
+		read func(ctx context.Context, r *UserRepository) ([]*models.User, error)
+	}{
+		{"GetByMobileNumber", func(ctx context.Context, r *UserRepository) ([]*models.User, error) {
+			return one(r.GetByMobileNumber(ctx, 9876543210))
+		}},
+		{"GetByAadhaarNumber", func(ctx context.Context, r *UserRepository) ([]*models.User, error) {
+			return one(r.GetByAadhaarNumber(ctx, "123456789012"))
+		}},
+		{"GetByCreatedBy", func(ctx context.Context, r *UserRepository) ([]*models.User, error) {
+			return r.GetByCreatedBy(ctx, "ADMIN1", 10, 0)
+		}},
+		{"GetByUpdatedBy", func(ctx context.Context, r *UserRepository) ([]*models.User, error) {
+			return r.GetByUpdatedBy(ctx, "ADMIN1", 10, 0)
+		}},
+		{"GetByStatus", func(ctx context.Context, r *UserRepository) ([]*models.User, error) {
+			return r.GetByStatus(ctx, "active", 10, 0)
+		}},
+		{"GetByValidationStatus", func(ctx context.Context, r *UserRepository) ([]*models.User, error) {
+			return r.GetByValidationStatus(ctx, true, 10, 0)
+		}},
+		{"GetByDateRange", func(ctx context.Context, r *UserRepository) ([]*models.User, error) {
+			return r.GetByDateRange(ctx, from, to, 10, 0)
+		}},
+		{"GetByUpdatedDateRange", func(ctx context.Context, r *UserRepository) ([]*models.User, error) {
+			return r.GetByUpdatedDateRange(ctx, from, to, 10, 0)
+		}},
+		{"GetByUsernameAndStatus", func(ctx context.Context, r *UserRepository) ([]*models.User, error) {
+			return r.GetByUsernameAndStatus(ctx, "ramesh", "active", 10, 0)
+		}},
+		{"GetByUsernameAndValidationStatus", func(ctx context.Context, r *UserRepository) ([]*models.User, error) {
+			return r.GetByUsernameAndValidationStatus(ctx, "ramesh", true, 10, 0)
+		}},
+		{"GetByUsernameAndDateRange", func(ctx context.Context, r *UserRepository) ([]*models.User, error) {
+			return r.GetByUsernameAndDateRange(ctx, "ramesh", from, to, 10, 0)
+		}},
+		{"GetByUsernameAndUpdatedDateRange", func(ctx context.Context, r *UserRepository) ([]*models.User, error) {
+			return r.GetByUsernameAndUpdatedDateRange(ctx, "ramesh", from, to, 10, 0)
+		}},
+		{"GetByStatusAndValidationStatus", func(ctx context.Context, r *UserRepository) ([]*models.User, error) {
+			return r.GetByStatusAndValidationStatus(ctx, "active", true, 10, 0)
+		}},
+		{"GetByStatusAndDateRange", func(ctx context.Context, r *UserRepository) ([]*models.User, error) {
+			return r.GetByStatusAndDateRange(ctx, "active", from, to, 10, 0)
+		}},
+		{"GetByStatusAndUpdatedDateRange", func(ctx context.Context, r *UserRepository) ([]*models.User, error) {
+			return r.GetByStatusAndUpdatedDateRange(ctx, "active", from, to, 10, 0)
+		}},
+		{"GetByValidationStatusAndDateRange", func(ctx context.Context, r *UserRepository) ([]*models.User, error) {
+			return r.GetByValidationStatusAndDateRange(ctx, true, from, to, 10, 0)
+		}},
+		{"GetByValidationStatusAndUpdatedDateRange", func(ctx context.Context, r *UserRepository) ([]*models.User, error) {
+			return r.GetByValidationStatusAndUpdatedDateRange(ctx, true, from, to, 10, 0)
+		}},
+		{"GetByUsernameAndStatusAndValidationStatus", func(ctx context.Context, r *UserRepository) ([]*models.User, error) {
+			return r.GetByUsernameAndStatusAndValidationStatus(ctx, "ramesh", "active", true, 10, 0)
+		}},
+		{"GetByUsernameAndStatusAndDateRange", func(ctx context.Context, r *UserRepository) ([]*models.User, error) {
+			return r.GetByUsernameAndStatusAndDateRange(ctx, "ramesh", "active", from, to, 10, 0)
+		}},
+		{"GetByUsernameAndStatusAndUpdatedDateRange", func(ctx context.Context, r *UserRepository) ([]*models.User, error) {
+			return r.GetByUsernameAndStatusAndUpdatedDateRange(ctx, "ramesh", "active", from, to, 10, 0)
+		}},
+		{"GetByUsernameAndValidationStatusAndDateRange", func(ctx context.Context, r *UserRepository) ([]*models.User, error) {
+			return r.GetByUsernameAndValidationStatusAndDateRange(ctx, "ramesh", true, from, to, 10, 0)
+		}},
+		{"GetByUsernameAndValidationStatusAndUpdatedDateRange", func(ctx context.Context, r *UserRepository) ([]*models.User, error) {
+			return r.GetByUsernameAndValidationStatusAndUpdatedDateRange(ctx, "ramesh", true, from, to, 10, 0)
+		}},
+		{"GetByStatusAndValidationStatusAndDateRange", func(ctx context.Context, r *UserRepository) ([]*models.User, error) {
+			return r.GetByStatusAndValidationStatusAndDateRange(ctx, "active", true, from, to, 10, 0)
+		}},
+		{"GetByStatusAndValidationStatusAndUpdatedDateRange", func(ctx context.Context, r *UserRepository) ([]*models.User, error) {
+			return r.GetByStatusAndValidationStatusAndUpdatedDateRange(ctx, "active", true, from, to, 10, 0)
+		}},
+		{"GetByUsernameAndStatusAndValidationStatusAndDateRange", func(ctx context.Context, r *UserRepository) ([]*models.User, error) {
+			return r.GetByUsernameAndStatusAndValidationStatusAndDateRange(ctx, "ramesh", "active", true, from, to, 10, 0)
+		}},
+		{"GetByUsernameAndStatusAndValidationStatusAndUpdatedDateRange", func(ctx context.Context, r *UserRepository) ([]*models.User, error) {
+			return r.GetByUsernameAndStatusAndValidationStatusAndUpdatedDateRange(ctx, "ramesh", "active", true, from, to, 10, 0)
+		}},
+		{"ListActive", func(ctx context.Context, r *UserRepository) ([]*models.User, error) {
+			return r.ListActive(ctx, 10, 0)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := newSoftDeleteDBManager(t)
+			users, err := tt.read(context.Background(), NewUserRepository(manager))
+			require.NoError(t, err)
+
+			assert.Equal(t, []string{"USER_LIVE"}, userIDs(users))
+			require.Len(t, manager.filters, 1)
+			assert.True(t, excludesDeleted(manager.filters[0]))
+		})
+	}
+}
+
+func TestUserRepository_LookupsExcludeSoftDeleted(t *testing.T) {
+	ctx := context.Background()
+
+	manager := newSoftDeleteDBManager(t)
+	_, _ = NewUserRepository(manager).GetByUsername(ctx, "ramesh")
+	require.NotEmpty(t, manager.filters)
+	assert.True(t, excludesDeleted(manager.filters[0]))
+
+	manager = newSoftDeleteDBManager(t)
+	_, _ = NewUserRepository(manager).GetByPhoneNumber(ctx, "9876543210", "+91")
+	require.NotEmpty(t, manager.filters)
+	for _, filter := range manager.filters {
+		assert.True(t, excludesDeleted(filter))
+	}
+
+	manager = newSoftDeleteDBManager(t)
+	_, err := NewUserRepository(manager).GetByEmail(ctx, "farmer@example.com")
+	require.NoError(t, err)
+	require.NotEmpty(t, manager.queries)
+	query := manager.queries[len(manager.queries)-1]
+	assert.Contains(t, query, `"users"."deleted_at" IS NULL`)
+	assert.Contains(t, query, `"contacts"."deleted_at" IS NULL`, "deleted email contacts do not match")
+
+	manager = newSoftDeleteDBManager(t)
+	_, err = NewUserRepository(manager).GetUsersWithRelationships(ctx, []string{"USER_LIVE", "USER_DELETED"}, false, false, false)
+	require.NoError(t, err)
+	require.Len(t, manager.queries, 1)
+	assert.Contains(t, manager.queries[0], `"users"."deleted_at" IS NULL`)
+}
+
+func TestUserRepository_GetByDeletedDateRangeIncludesSoftDeleted(t *testing.T) {
+	manager := newSoftDeleteDBManager(t)
+	_, err := NewUserRepository(manager).GetByUsernameAndStatusAndDeletedDateRange(context.Background(), "ramesh", "active", "2025-01-01", "2025-12-31", 10, 0)
+	require.NoError(t, err)
+
+	assert.Empty(t, manager.filters, "the database manager's reads always exclude soft-deleted users")
+	require.Len(t, manager.queries, 1)
+	assert.Contains(t, manager.queries[0], "deleted_at BETWEEN")
+	assert.Contains(t, manager.queries[0], `"status" = `)
+	assert.Contains(t, manager.queries[0], `"username" = `)
+	assert.NotContains(t, manager.queries[0], "IS NULL")
+}
@@ -0,0 +1,99 @@
+// Package utctime keeps every timestamp in UTC. Models set their timestamps with the server's
+// local time and requests carry the client's offset; converting them as they are written keeps
+// stored values, and the models returned after a write, in UTC whatever the timezone of the
+// server or the client. Rows read are converted too, as the driver returns timestamps in the
+// server's local time.
+package utctime
+
+import (
+	"errors"
+	"reflect"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+const callbackName = "aaa:utc_timestamps"
+
+var (
+	timeType    = reflect.TypeOf(time.Time{})
+	timePtrType = reflect.TypeOf(&time.Time{})
+)
+
+// Register converts the timestamps of every row created, updated or read through gormDB to UTC,
+// and makes the timestamps GORM sets itself, such as updated_at, UTC
+func Register(gormDB *gorm.DB) error {
+	gormDB.Config.NowFunc = func() time.Time {
+		return time.Now().UTC()
+	}
+
+	callbacks := gormDB.Callback()
+	return errors.Join(
+		callbacks.Create().Before("gorm:create").Register(callbackName, toUTC),
+		callbacks.Update().Before("gorm:update").Register(callbackName, toUTC),
+		callbacks.Query().After("gorm:query").Register(callbackName, toUTC),
+	)
+}
+
+// toUTC converts the timestamps of the statement's model, or models, and of the values of a map
+// of updates
+func toUTC(tx *gorm.DB) {
+	if updates, ok := tx.Statement.Dest.(map[string]interface{}); ok {
+		for column, value := range updates {
+			switch v := value.(type) {
+			case time.Time:
+				updates[column] = v.UTC()
+			case *time.Time:
+				if v != nil {
+					converted := v.UTC()
+					updates[column] = &converted
+				}
+			}
+		}
+	}
+
+	if tx.Statement.Schema == nil || !tx.Statement.ReflectValue.IsValid() {
+		return
+	}
+	switch rv := tx.Statement.ReflectValue; rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			convertFields(tx, tx.Statement.Schema, reflect.Indirect(rv.Index(i)))
+		}
+	case reflect.Struct:
+		convertFields(tx, tx.Statement.Schema, rv)
+	}
+}
+
+func convertFields(tx *gorm.DB, s *schema.Schema, rv reflect.Value) {
+	if rv.Kind() != reflect.Struct {
+		return
+	}
+	ctx := tx.Statement.Context
+	for _, field := range s.Fields {
+		if field.FieldType != timeType && field.FieldType != timePtrType {
+			continue
+		}
+		value, isZero := field.ValueOf(ctx, rv)
+		if isZero {
+			continue
+		}
+		var err error
+		switch v := value.(type) {
+		case time.Time:
+			if v.Location() != time.UTC {
+				err = field.Set(ctx, rv, v.UTC())
+			}
+		case *time.Time:
+			if v != nil && v.Location() != time.UTC {
+				// The pointer may be shared with the caller's other values; store a copy
+				converted := v.UTC()
+				err = field.Set(ctx, rv, &converted)
+			}
+		}
+		if err != nil {
+			_ = tx.AddError(err)
+		}
+	}
+}
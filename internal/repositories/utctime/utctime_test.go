@@ -0,0 +1,144 @@
+package utctime
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// recordingDriver opens connections that accept every statement and record the arguments
+// written, standing in for the database
+type recordingDriver struct {
+	mu   sync.Mutex
+	args [][]driver.NamedValue
+}
+
+func (d *recordingDriver) Open(name string) (driver.Conn, error) { return recordingConn{d}, nil }
+
+func (d *recordingDriver) record(args []driver.NamedValue) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.args = append(d.args, args)
+}
+
+// timestamps returns the time arguments of the statements run
+func (d *recordingDriver) timestamps() []time.Time {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var timestamps []time.Time
+	for _, args := range d.args {
+		for _, arg := range args {
+			if t, ok := arg.Value.(time.Time); ok {
+				timestamps = append(timestamps, t)
+			}
+		}
+	}
+	return timestamps
+}
+
+type recordingConn struct{ d *recordingDriver }
+
+func (recordingConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("prepared statements are not supported")
+}
+func (recordingConn) Close() error              { return nil }
+func (recordingConn) Begin() (driver.Tx, error) { return recordingTx{}, nil }
+
+func (c recordingConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.d.record(args)
+	return emptyRows{}, nil
+}
+
+func (c recordingConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.d.record(args)
+	return driver.RowsAffected(1), nil
+}
+
+type recordingTx struct{}
+
+func (recordingTx) Commit() error   { return nil }
+func (recordingTx) Rollback() error { return nil }
+
+type emptyRows struct{}
+
+func (emptyRows) Columns() []string              { return nil }
+func (emptyRows) Close() error                   { return nil }
+func (emptyRows) Next(dest []driver.Value) error { return io.EOF }
+
+var recorder = &recordingDriver{}
+
+func init() {
+	sql.Register("utctime_recording", recorder)
+}
+
+type record struct {
+	ID        string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	ExpiresAt *time.Time
+}
+
+func openRecordingDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	recorder.mu.Lock()
+	recorder.args = nil
+	recorder.mu.Unlock()
+
+	sqlDB, err := sql.Open("utctime_recording", "")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{SkipDefaultTransaction: true})
+	require.NoError(t, err)
+	require.NoError(t, Register(gormDB))
+	return gormDB
+}
+
+func assertAllUTC(t *testing.T, timestamps []time.Time) {
+	t.Helper()
+	require.NotEmpty(t, timestamps)
+	for _, timestamp := range timestamps {
+		assert.Equal(t, time.UTC, timestamp.Location(), "timestamp %s is not stored in UTC", timestamp)
+	}
+}
+
+func TestStoredTimestampsAreUTCRegardlessOfInputTimezone(t *testing.T) {
+	kolkata := time.FixedZone("IST", 5*60*60+30*60)
+	losAngeles := time.FixedZone("PDT", -7*60*60)
+	created := time.Date(2025, 3, 1, 9, 30, 0, 0, kolkata)
+	expires := time.Date(2025, 3, 2, 18, 0, 0, 0, losAngeles)
+
+	gormDB := openRecordingDB(t)
+	row := &record{ID: "REC1", CreatedAt: created, UpdatedAt: created, ExpiresAt: &expires}
+	require.NoError(t, gormDB.Create(row).Error)
+
+	assertAllUTC(t, recorder.timestamps())
+	assert.True(t, row.CreatedAt.Equal(created), "the instant is kept")
+	assert.Equal(t, time.UTC, row.CreatedAt.Location(), "the created model is returned in UTC")
+	assert.Equal(t, losAngeles, expires.Location(), "the caller's timestamp is not modified")
+}
+
+func TestUpdatedTimestampsAreUTC(t *testing.T) {
+	local := time.FixedZone("CET", 60*60)
+	expires := time.Date(2025, 6, 1, 12, 0, 0, 0, local)
+
+	gormDB := openRecordingDB(t)
+	row := &record{ID: "REC1", CreatedAt: time.Date(2025, 1, 1, 0, 0, 0, 0, local), ExpiresAt: &expires}
+	require.NoError(t, gormDB.Save(row).Error)
+	require.NoError(t, gormDB.Model(&record{ID: "REC1"}).Updates(map[string]interface{}{
+		"expires_at": time.Date(2025, 7, 1, 12, 0, 0, 0, local),
+	}).Error)
+
+	assertAllUTC(t, recorder.timestamps())
+	assert.Equal(t, time.UTC, row.UpdatedAt.Location(), "timestamps GORM sets are UTC")
+}
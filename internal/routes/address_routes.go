@@ -24,9 +24,11 @@ func SetupAddressRoutes(
 	{
 		// Address CRUD operations
 		addressGroup.POST("", authMiddleware.RequirePermission("address", "create"), addressHandler.CreateAddress)
+		addressGroup.POST("/bulk", authMiddleware.RequirePermission("address", "create"), addressHandler.CreateAddresses)
 		addressGroup.GET("/:id", authMiddleware.RequirePermission("address", "read"), addressHandler.GetAddress)
 		addressGroup.PUT("/:id", authMiddleware.RequirePermission("address", "update"), addressHandler.UpdateAddress)
 		addressGroup.DELETE("/:id", authMiddleware.RequirePermission("address", "delete"), addressHandler.DeleteAddress)
+		addressGroup.POST("/:id/geocode", authMiddleware.RequirePermission("address", "update"), addressHandler.GeocodeAddress)
 
 		// Address search operations
 		addressGroup.GET("/search", authMiddleware.RequirePermission("address", "read"), addressHandler.SearchAddresses)
@@ -32,6 +32,7 @@ func SetupAdminRoutes(protectedAPI *gin.RouterGroup, adminHandler *admin.AdminHa
 		// Health and metrics endpoints
 		adminGroup.GET("/health/detailed", adminHandler.DetailedHealthCheck)
 		adminGroup.GET("/metrics", adminHandler.Metrics)
+		adminGroup.GET("/db/pool", adminHandler.DBPoolStats)
 
 		// System info endpoint
 		adminGroup.GET("/system", adminHandler.GetSystemInfo)
@@ -40,7 +41,24 @@ func SetupAdminRoutes(protectedAPI *gin.RouterGroup, adminHandler *admin.AdminHa
 		adminGroup.GET("/maintenance", adminHandler.GetMaintenanceStatus)
 		adminGroup.POST("/maintenance", adminHandler.MaintenanceMode)
 		adminGroup.PATCH("/maintenance/message", adminHandler.UpdateMaintenanceMessage)
+
+		// Token signing key endpoints
+		adminGroup.GET("/signing-keys", adminHandler.ListSigningKeys)
+		adminGroup.POST("/signing-keys/rotate", adminHandler.RotateSigningKey)
+
+		// RBAC seed preview endpoint
+		adminGroup.GET("/rbac/preview", adminHandler.PreviewRBACSeed)
+
+		// Hierarchy depth check endpoint
+		adminGroup.GET("/hierarchy/depth", adminHandler.CheckHierarchyDepth)
+
+		// Impersonation endpoint
+		adminGroup.POST("/impersonate", authMiddleware.RequirePermission("system", services.ImpersonatePermission), adminHandler.StartImpersonation)
 	}
+
+	// Ending a session is allowed with the impersonation token itself, which carries the target's roles;
+	// the service only lets the admin who started the session end it
+	protectedAPI.DELETE("/admin/impersonate/:id", adminHandler.StopImpersonation)
 }
 
 func createGrantPermissionHandler(authzService *services.AuthorizationService, logger *zap.Logger) gin.HandlerFunc {
@@ -1,14 +1,26 @@
 package routes
 
 import (
+	stdErrors "errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
 	"github.com/Kisanlink/aaa-service/v2/internal/middleware"
 	"github.com/Kisanlink/aaa-service/v2/internal/services"
+	"github.com/Kisanlink/aaa-service/v2/internal/utils/utc"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
 
 // SetupAuditRoutes configures audit routes
 func SetupAuditRoutes(protectedAPI *gin.RouterGroup, authMiddleware *middleware.AuthMiddleware, auditService *services.AuditService, logger *zap.Logger) {
+	// Self-access: any authenticated user can read their own sign-in history
+	protectedAPI.GET("/users/me/login-history", createGetMyLoginHistoryHandler(auditService, logger))
+
 	audit := protectedAPI.Group("/audit")
 	// Align with schema: audit log permission is "view" for reading logs
 	audit.Use(authMiddleware.RequirePermission("audit_log", "view"))
@@ -21,11 +33,172 @@ func SetupAuditRoutes(protectedAPI *gin.RouterGroup, authMiddleware *middleware.
 	}
 }
 
+// RegisterAuditIngestRoutes registers the endpoint other services record their audit events
+// through. Callers need a service token with the audit:write scope, and each service can send
+// requestsPerMinute batches a minute.
+func RegisterAuditIngestRoutes(router *gin.Engine, auditService *services.AuditService, authMiddleware *middleware.AuthMiddleware, requestsPerMinute int, logger *zap.Logger) {
+	audit := router.Group("/api/v1/audit")
+	audit.Use(authMiddleware.HTTPAuthMiddleware())
+	{
+		audit.POST("/ingest",
+			authMiddleware.RequireServiceScope("audit:write"),
+			middleware.ServiceRateLimit(requestsPerMinute),
+			createIngestAuditLogsHandler(auditService, logger))
+	}
+}
+
+// auditIngestRequest is the body of POST /api/v1/audit/ingest
+type auditIngestRequest struct {
+	Entries []services.AuditIngestEntry `json:"entries"`
+}
+
+// createIngestAuditLogsHandler handles POST /api/v1/audit/ingest
+//
+//	@Summary		Ingest audit events from another service
+//	@Description	Record a batch of up to 500 audit events (2 MiB) in the central audit store, stamped with the calling service as source_service. The batch is stored entirely or not at all. User IDs without an AAA account are kept as the external_user_id detail. Requires a service token with the audit:write scope.
+//	@Tags			audit
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		auditIngestRequest				true	"Audit events"
+//	@Success		201		{object}	map[string]interface{}
+//	@Failure		400		{object}	responses.ErrorResponseSwagger	"Invalid entries"
+//	@Failure		403		{object}	responses.ErrorResponseSwagger	"Not a service token with the audit:write scope"
+//	@Failure		413		{object}	responses.ErrorResponseSwagger	"Batch too large"
+//	@Failure		429		{object}	responses.ErrorResponseSwagger	"Rate limit exceeded"
+//	@Failure		500		{object}	responses.ErrorResponseSwagger	"Internal server error"
+//	@Router			/api/v1/audit/ingest [post]
+func createIngestAuditLogsHandler(auditService *services.AuditService, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, services.MaxAuditIngestBodyBytes)
+
+		var req auditIngestRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			var tooLarge *http.MaxBytesError
+			if stdErrors.As(err, &tooLarge) {
+				c.JSON(http.StatusRequestEntityTooLarge, gin.H{"success": false, "error": fmt.Sprintf("request body must be at most %d bytes", services.MaxAuditIngestBodyBytes)})
+				return
+			}
+			c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": err.Error()})
+			return
+		}
+
+		sourceID := c.GetString("service_id")
+		count, err := auditService.IngestAuditLogs(c.Request.Context(), sourceID, req.Entries)
+		if errors.IsValidationError(err) {
+			response := gin.H{"success": false, "error": err.Error()}
+			if details := err.(*errors.ValidationError).Details(); len(details) > 0 {
+				response["details"] = details
+			}
+			c.JSON(http.StatusBadRequest, response)
+			return
+		}
+		if err != nil {
+			logger.Error("Failed to ingest audit logs", zap.String("service_id", sourceID), zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "failed to ingest audit logs"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{"success": true, "data": gin.H{"ingested": count}})
+	}
+}
+
+// createGetAuditLogsHandler handles GET /api/v1/audit/logs
+//
+//	@Summary		Query audit logs
+//	@Description	Audit logs matching all given filters, newest first. action and resource accept several values, either repeated (?action=login&action=logout) or comma-separated (?action=login,logout); a log matches any of them.
+//	@Tags			audit
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			user_id		query		string		false	"Filter by user ID"
+//	@Param			actor_id	query		string		false	"Filter by who performed the action, including admins acting for another user and impersonators"
+//	@Param			on_behalf_of_id	query	string		false	"Filter by the user an action was performed on or for"
+//	@Param			action		query		[]string	false	"Filter by action (any of)"		collectionFormat(multi)
+//	@Param			resource	query		[]string	false	"Filter by resource type (any of)"	collectionFormat(multi)
+//	@Param			resource_id	query		string		false	"Filter by resource ID"
+//	@Param			success		query		bool		false	"Filter by outcome"
+//	@Param			ip_address	query		string		false	"Filter by client IP address, or by network in CIDR notation (e.g. 10.20.0.0/16)"
+//	@Param			user_agent	query		string		false	"Filter by user agent containing this text, ignoring case"
+//	@Param			start_time	query		string		false	"Only logs at or after this time (RFC3339)"
+//	@Param			end_time	query		string		false	"Only logs at or before this time (RFC3339)"
+//	@Param			page		query		int			false	"Page number"		default(1)
+//	@Param			per_page	query		int			false	"Logs per page"	default(50)
+//	@Success		200			{object}	services.AuditQueryResult
+//	@Failure		400			{object}	responses.ErrorResponseSwagger	"Invalid filter"
+//	@Failure		500			{object}	responses.ErrorResponseSwagger	"Internal server error"
+//	@Router			/api/v1/audit/logs [get]
 func createGetAuditLogsHandler(auditService *services.AuditService, logger *zap.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		logger.Info("Get audit logs endpoint accessed")
-		c.JSON(200, gin.H{"message": "Get audit logs endpoint - implementation needed"})
+		query := &services.AuditQuery{
+			UserID:       c.Query("user_id"),
+			ActorID:      c.Query("actor_id"),
+			OnBehalfOfID: c.Query("on_behalf_of_id"),
+			Actions:      queryValues(c, "action"),
+			Resources:    queryValues(c, "resource"),
+			ResourceID:   c.Query("resource_id"),
+			IPAddress:    c.Query("ip_address"),
+			UserAgent:    c.Query("user_agent"),
+		}
+		query.Page, _ = strconv.Atoi(c.DefaultQuery("page", "1"))
+		query.PerPage, _ = strconv.Atoi(c.DefaultQuery("per_page", "50"))
+
+		if raw := c.Query("success"); raw != "" {
+			success, err := strconv.ParseBool(raw)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "success must be true or false"})
+				return
+			}
+			query.Success = &success
+		}
+		var err error
+		if query.StartTime, err = timeQueryParam(c, "start_time"); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": err.Error()})
+			return
+		}
+		if query.EndTime, err = timeQueryParam(c, "end_time"); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": err.Error()})
+			return
+		}
+
+		result, err := auditService.QueryAuditLogs(c.Request.Context(), query)
+		if errors.IsValidationError(err) {
+			c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": err.Error()})
+			return
+		}
+		if err != nil {
+			logger.Error("Failed to query audit logs", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "failed to query audit logs"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"success": true, "data": result})
+	}
+}
+
+// timeQueryParam parses an optional RFC3339 query parameter, converted to UTC
+func timeQueryParam(c *gin.Context, key string) (*time.Time, error) {
+	raw := c.Query(key)
+	if raw == "" {
+		return nil, nil
+	}
+	parsed, err := utc.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be an RFC3339 timestamp", key)
+	}
+	return &parsed, nil
+}
+
+// queryValues collects a query parameter given several times and/or as a comma-separated list
+func queryValues(c *gin.Context, key string) []string {
+	var values []string
+	for _, raw := range c.QueryArray(key) {
+		for _, value := range strings.Split(raw, ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
 	}
+	return values
 }
 
 func createGetUserAuditTrailHandler(auditService *services.AuditService, logger *zap.Logger) gin.HandlerFunc {
@@ -64,3 +237,36 @@ func createGetAuditStatisticsHandler(auditService *services.AuditService, logger
 		c.JSON(200, gin.H{"message": "Get audit statistics endpoint - implementation needed"})
 	}
 }
+
+// createGetMyLoginHistoryHandler handles GET /api/v1/users/me/login-history
+//
+//	@Summary		Get my login history
+//	@Description	Recent successful and failed sign-in attempts for the authenticated user, including IP and user agent
+//	@Tags			users
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			limit	query		int	false	"Maximum entries to return (default 20, max 100)"
+//	@Success		200		{array}		services.LoginHistoryEntry
+//	@Failure		401		{object}	responses.ErrorResponseSwagger	"Unauthorized"
+//	@Failure		500		{object}	responses.ErrorResponseSwagger	"Internal server error"
+//	@Router			/api/v1/users/me/login-history [get]
+func createGetMyLoginHistoryHandler(auditService *services.AuditService, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.GetString("user_id")
+		if userID == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"success": false, "error": "unauthorized"})
+			return
+		}
+
+		limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+		history, err := auditService.GetLoginHistory(c.Request.Context(), userID, limit)
+		if err != nil {
+			logger.Error("Failed to get login history", zap.String("user_id", userID), zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "failed to retrieve login history"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"success": true, "data": history})
+	}
+}
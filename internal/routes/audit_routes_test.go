@@ -0,0 +1,143 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+type auditFilterRepo struct {
+	interfaces.AuditRepository
+	gotFilter interfaces.AuditLogFilter
+}
+
+func (r *auditFilterRepo) ListByFilter(ctx context.Context, filter interfaces.AuditLogFilter, limit, offset int) ([]*models.AuditLog, error) {
+	r.gotFilter = filter
+	return nil, nil
+}
+
+func (r *auditFilterRepo) CountByFilter(ctx context.Context, filter interfaces.AuditLogFilter) (int64, error) {
+	return 0, nil
+}
+
+func TestGetAuditLogsHandler_RepeatedAndCommaSeparatedFilters(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	repo := &auditFilterRepo{}
+	router := gin.New()
+	router.GET("/api/v1/audit/logs", createGetAuditLogsHandler(services.NewAuditService(nil, repo, nil, zap.NewNop()), zap.NewNop()))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/audit/logs?action=login,logout&action=access_denied&resource=aaa/user&success=false", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []string{"login", "logout", "access_denied"}, repo.gotFilter.Actions)
+	assert.Equal(t, []string{"aaa/user"}, repo.gotFilter.ResourceTypes)
+	assert.Equal(t, models.AuditStatusFailure, repo.gotFilter.Status)
+}
+
+func TestGetAuditLogsHandler_InvalidTime(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.GET("/api/v1/audit/logs", createGetAuditLogsHandler(services.NewAuditService(nil, &auditFilterRepo{}, nil, zap.NewNop()), zap.NewNop()))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/audit/logs?action=login&start_time=yesterday", nil))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "start_time")
+}
+
+func TestGetAuditLogsHandler_IPAndUserAgentFilters(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	repo := &auditFilterRepo{}
+	router := gin.New()
+	router.GET("/api/v1/audit/logs", createGetAuditLogsHandler(services.NewAuditService(nil, repo, nil, zap.NewNop()), zap.NewNop()))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/audit/logs?ip_address=10.20.0.0/16&user_agent=okhttp", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	if assert.NotNil(t, repo.gotFilter.IPRange) {
+		assert.Equal(t, "10.20.0.0/16", repo.gotFilter.IPRange.String())
+	}
+	assert.Equal(t, "okhttp", repo.gotFilter.UserAgent)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/audit/logs?ip_address=not-an-ip", nil))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "ip_address")
+}
+
+type auditIngestRepo struct {
+	interfaces.AuditRepository
+	batches [][]*models.AuditLog
+}
+
+func (r *auditIngestRepo) CreateBatch(ctx context.Context, auditLogs []*models.AuditLog) error {
+	r.batches = append(r.batches, auditLogs)
+	return nil
+}
+
+func newAuditIngestRouter(repo *auditIngestRepo) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.POST("/api/v1/audit/ingest", func(c *gin.Context) {
+		c.Set("service_id", "SVC1")
+		c.Next()
+	}, createIngestAuditLogsHandler(services.NewAuditService(nil, repo, nil, zap.NewNop()), zap.NewNop()))
+	return router
+}
+
+func TestIngestAuditLogsHandler(t *testing.T) {
+	repo := &auditIngestRepo{}
+	router := newAuditIngestRouter(repo)
+
+	body := `{"entries":[{"action":"create_order","resource_type":"orders/order"},{"action":"cancel_order","resource_type":"orders/order","status":"failure"}]}`
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/audit/ingest", strings.NewReader(body)))
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.JSONEq(t, `{"success":true,"data":{"ingested":2}}`, w.Body.String())
+	if assert.Len(t, repo.batches, 1) {
+		assert.Equal(t, "SVC1", repo.batches[0][0].Details["source_service"])
+	}
+}
+
+func TestIngestAuditLogsHandler_InvalidEntries(t *testing.T) {
+	repo := &auditIngestRepo{}
+	router := newAuditIngestRouter(repo)
+
+	body := `{"entries":[{"action":"create_order","resource_type":"orders/order"},{"resource_type":"orders/order"}]}`
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/audit/ingest", strings.NewReader(body)))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "entries[1]: action is required")
+	assert.Empty(t, repo.batches)
+}
+
+func TestIngestAuditLogsHandler_BodyTooLarge(t *testing.T) {
+	repo := &auditIngestRepo{}
+	router := newAuditIngestRouter(repo)
+
+	body := `{"entries":[{"action":"a","resource_type":"r","message":"` + strings.Repeat("x", services.MaxAuditIngestBodyBytes) + `"}]}`
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/audit/ingest", strings.NewReader(body)))
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	assert.Empty(t, repo.batches)
+}
@@ -1,6 +1,7 @@
 package routes
 
 import (
+	"github.com/Kisanlink/aaa-service/v2/helper"
 	"github.com/Kisanlink/aaa-service/v2/internal/handlers/auth"
 	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
 	"github.com/Kisanlink/aaa-service/v2/internal/middleware"
@@ -13,12 +14,36 @@ func SetupAuthRoutes(
 	publicAPI, protectedAPI *gin.RouterGroup,
 	authMiddleware *middleware.AuthMiddleware,
 	userService interfaces.UserService,
+	loginChallenges interfaces.LoginChallengeService,
+	deviceSessions interfaces.DeviceSessionService,
+	deviceMPins interfaces.DeviceMPinService,
+	tokenSessions interfaces.TokenSessionService,
+	loginRecorder interfaces.LoginRecorder,
+	tokenSigner helper.TokenSigner,
 	validator interfaces.Validator,
 	responder interfaces.Responder,
 	logger *zap.Logger,
 ) {
 	// Create AuthHandler instance
 	authHandler := auth.NewAuthHandler(userService, validator, responder, logger)
+	if loginChallenges != nil {
+		authHandler.SetLoginChallengeService(loginChallenges)
+	}
+	if deviceSessions != nil {
+		authHandler.SetDeviceSessionService(deviceSessions)
+	}
+	if deviceMPins != nil {
+		authHandler.SetDeviceMPinService(deviceMPins)
+	}
+	if tokenSessions != nil {
+		authHandler.SetTokenSessionService(tokenSessions)
+	}
+	if loginRecorder != nil {
+		authHandler.SetLoginRecorder(loginRecorder)
+	}
+	if tokenSigner != nil {
+		authHandler.SetTokenSigner(tokenSigner)
+	}
 
 	// Create input sanitization middleware
 	sanitizationMiddleware := middleware.NewInputSanitizationMiddleware(logger)
@@ -31,6 +56,8 @@ func SetupAuthRoutes(
 	authGroup.Use(middleware.ValidateJSONStructure(5, 20))            // JSON structure validation
 	{
 		authGroup.POST("/login", authHandler.Login)
+		authGroup.POST("/login/mpin", middleware.MPinRateLimit(), authHandler.VerifyLoginMPIN)
+		authGroup.POST("/session/token", authHandler.ExchangeDeviceSession)
 		authGroup.POST("/register", authHandler.Register)
 		authGroup.POST("/refresh", authHandler.RefreshToken)
 		authGroup.POST("/forgot-password", authHandler.ForgotPassword)
@@ -56,4 +83,38 @@ func SetupAuthRoutes(
 			mpinGroup.POST("/update-mpin", authHandler.UpdateMPin)
 		}
 	}
+
+	// Remembered devices of the signed-in user
+	if deviceSessions != nil {
+		protectedAPI.GET("/users/me/sessions", authHandler.ListMySessions)
+		protectedAPI.DELETE("/users/me/sessions/:id", authHandler.RevokeMySession)
+	}
+
+	// Active access and refresh tokens of a user, for the user or a super_admin
+	if tokenSessions != nil {
+		protectedAPI.GET("/users/:id/sessions", authHandler.ListUserSessions)
+		protectedAPI.DELETE("/users/:id/sessions/:jti", authHandler.RevokeUserSession)
+	}
+
+	// Device-bound MPINs of the signed-in user
+	if deviceMPins != nil {
+		protectedAPI.GET("/users/me/devices", authHandler.ListMyDevices)
+		protectedAPI.PUT("/users/me/devices/:device_id/mpin", middleware.MPinRateLimit(), authHandler.SetMyDeviceMPin)
+		protectedAPI.DELETE("/users/me/devices/:device_id/mpin", authHandler.RevokeMyDeviceMPin)
+	}
+
+	// Password and MPIN resets through an OTP sent to a verified contact
+	if credentialReset, ok := userService.(interfaces.CredentialResetService); ok {
+		authHandler.SetCredentialResetService(credentialReset)
+		authGroup.POST("/forgot-mpin", authHandler.ForgotMPIN)
+		authGroup.POST("/reset-mpin", middleware.MPinRateLimit(), authHandler.ResetMPIN)
+	}
+
+	// Self-service account deletion; the account cannot sign in while it awaits deletion, so
+	// cancelling is public and authenticated by the phone number and password
+	if accountDeletion, ok := userService.(interfaces.AccountDeletionService); ok {
+		authHandler.SetAccountDeletionService(accountDeletion)
+		protectedAPI.POST("/users/me/deletion", middleware.SensitiveOperationRateLimit(), authHandler.DeleteMyAccount)
+		authGroup.POST("/account-deletion/cancel", authHandler.CancelAccountDeletion)
+	}
 }
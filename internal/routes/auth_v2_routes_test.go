@@ -16,6 +16,8 @@ func TestAuthV2Routes_ExpectedEndpoints(t *testing.T) {
 		public bool
 	}{
 		{"POST", "/api/v1/auth/login", true},
+		{"POST", "/api/v1/auth/login/mpin", true},
+		{"POST", "/api/v1/auth/session/token", true},
 		{"POST", "/api/v1/auth/register", true},
 		{"POST", "/api/v1/auth/refresh", true},
 		{"POST", "/api/v1/auth/forgot-password", true},
@@ -1,6 +1,8 @@
 package routes
 
 import (
+	"github.com/Kisanlink/aaa-service/v2/internal/handlers/authz"
+	"github.com/Kisanlink/aaa-service/v2/internal/middleware"
 	"github.com/Kisanlink/aaa-service/v2/internal/services"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -16,6 +18,30 @@ func SetupAuthorizationRoutes(protectedAPI *gin.RouterGroup, authzService *servi
 	}
 }
 
+// RegisterPermissionCheckRoutes registers the permission check other services call on their hot
+// path. It sits outside protectedAPI to skip the sensitive operation rate limit, and only service
+// tokens with the auth:check scope may query arbitrary users' permissions.
+func RegisterPermissionCheckRoutes(router *gin.Engine, authzHandler *authz.Handler, authMiddleware *middleware.AuthMiddleware) {
+	auth := router.Group("/api/v1/auth")
+	auth.Use(authMiddleware.HTTPAuthMiddleware())
+	{
+		auth.GET("/can", authMiddleware.RequireServiceScope("auth:check"), authzHandler.Can)
+	}
+}
+
+// RegisterAccessReportRoutes registers the export of a user's effective access when the handler
+// has an access reporter; the handler lets users export their own report and admins anyone's
+func RegisterAccessReportRoutes(router *gin.Engine, authzHandler *authz.Handler, authMiddleware *middleware.AuthMiddleware) {
+	if !authzHandler.HasAccessReporter() {
+		return
+	}
+	users := router.Group("/api/v1/users")
+	users.Use(authMiddleware.HTTPAuthMiddleware())
+	{
+		users.GET("/:id/access-report", authzHandler.AccessReport)
+	}
+}
+
 func createCheckPermissionHandler(authzService *services.AuthorizationService, logger *zap.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		logger.Info("Check permission endpoint accessed")
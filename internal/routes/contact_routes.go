@@ -30,6 +30,11 @@ func SetupContactRoutes(
 		contacts.PUT("/:id", authMiddleware.RequirePermission("contact", "update"), contactHandler.UpdateContact)
 		contacts.DELETE("/:id", authMiddleware.RequirePermission("contact", "delete"), contactHandler.DeleteContact)
 
+		// Contact verification by OTP
+		contacts.POST("/:id/verification", authMiddleware.RequirePermission("contact", "update"), contactHandler.StartContactVerification)
+		contacts.POST("/:id/verification/resend", authMiddleware.RequirePermission("contact", "update"), contactHandler.ResendContactVerification)
+		contacts.POST("/:id/verification/confirm", authMiddleware.RequirePermission("contact", "update"), contactHandler.ConfirmContactVerification)
+
 		// User-specific contact operations
 		contacts.GET("/user/:userID", authMiddleware.RequirePermission("contact", "read"), contactHandler.GetContactsByUser)
 	}
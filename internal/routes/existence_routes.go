@@ -0,0 +1,28 @@
+package routes
+
+import (
+	"github.com/Kisanlink/aaa-service/v2/internal/handlers/existence"
+	"github.com/Kisanlink/aaa-service/v2/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterExistenceRoutes registers the batch existence checks of the resources enabled on the
+// handler. Each check requires the permission needed to read that resource.
+func RegisterExistenceRoutes(router *gin.Engine, handler *existence.Handler, authMiddleware *middleware.AuthMiddleware) {
+	api := router.Group("/api/v1")
+	api.Use(authMiddleware.HTTPAuthMiddleware())
+	{
+		if handler.HasChecker(existence.ResourceUser) {
+			api.POST("/users/exists", authMiddleware.RequirePermission("user", "read"), handler.UsersExist)
+		}
+		if handler.HasChecker(existence.ResourceOrganization) {
+			api.POST("/organizations/exists", handler.OrganizationsExist)
+		}
+		if handler.HasChecker(existence.ResourceRole) {
+			api.POST("/roles/exists", authMiddleware.RequirePermission("role", "view"), handler.RolesExist)
+		}
+		if handler.HasChecker(existence.ResourceGroup) {
+			api.POST("/groups/exists", handler.GroupsExist)
+		}
+	}
+}
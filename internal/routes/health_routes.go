@@ -3,6 +3,7 @@ package routes
 import (
 	"net/http"
 
+	"github.com/Kisanlink/aaa-service/v2/internal/handlers/health"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
@@ -12,6 +13,13 @@ func SetupHealthRoutes(publicAPI *gin.RouterGroup, logger *zap.Logger) {
 	publicAPI.GET("/health", createHealthHandler(logger))
 }
 
+// RegisterProbeRoutes registers the orchestrator liveness and readiness probes at the root,
+// outside the versioned API so load balancers need no API-specific configuration.
+func RegisterProbeRoutes(router *gin.Engine, healthHandler *health.HealthHandler) {
+	router.GET("/healthz", healthHandler.Healthz)
+	router.GET("/readyz", healthHandler.Readyz)
+}
+
 // HealthCheckV2 handles GET /v2/health
 //
 //	@Summary		Health check (V2)
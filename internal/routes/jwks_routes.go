@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"github.com/Kisanlink/aaa-service/v2/internal/handlers/auth"
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterJWKSRoutes publishes the token verification keys. The route is only registered when
+// tokens are signed with RS256 keys; HS256 secrets are never published.
+func RegisterJWKSRoutes(router *gin.Engine, jwksHandler *auth.JWKSHandler, enabled bool) {
+	if !enabled {
+		return
+	}
+
+	router.GET("/.well-known/jwks.json", jwksHandler.JWKS)
+}
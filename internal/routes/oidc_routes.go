@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"github.com/Kisanlink/aaa-service/v2/internal/handlers/auth"
+	"github.com/Kisanlink/aaa-service/v2/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterOIDCRoutes registers external identity provider login routes.
+// Routes are only registered when at least one provider is configured, so a deployment
+// without OIDC answers 404 on these paths.
+func RegisterOIDCRoutes(router *gin.Engine, oidcHandler *auth.OIDCHandler, enabled bool) {
+	if !enabled {
+		return
+	}
+
+	oidcRoutes := router.Group("/api/v1/auth/oidc")
+	oidcRoutes.Use(middleware.AuthenticationRateLimit())
+	{
+		oidcRoutes.GET("/:provider/login", oidcHandler.Login)
+		oidcRoutes.GET("/:provider/callback", oidcHandler.Callback)
+	}
+}
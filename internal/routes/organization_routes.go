@@ -19,10 +19,47 @@ func SetupOrganizationRoutes(apiGroup *gin.RouterGroup, orgHandler *organization
 		org.PUT("/:id", orgHandler.UpdateOrganization)
 		org.DELETE("/:id", orgHandler.DeleteOrganization)
 		org.GET("/:id/hierarchy", orgHandler.GetOrganizationHierarchy)
+		org.GET("/:id/ancestors", orgHandler.GetOrganizationAncestors)
+		org.GET("/:id/descendants", orgHandler.GetOrganizationDescendants)
+		org.GET("/:id/group-hierarchy", orgHandler.GetGroupChildren)
 		org.POST("/:id/activate", orgHandler.ActivateOrganization)
 		org.POST("/:id/deactivate", orgHandler.DeactivateOrganization)
 		org.GET("/:id/stats", orgHandler.GetOrganizationStats)
 
+		// Hard delete confirmation and restore of soft-deleted organizations - restricted to super_admin only
+		org.POST("/:id/hard-delete-token", authMiddleware.RequireRole("super_admin"), orgHandler.RequestOrganizationHardDelete)
+		org.POST("/:id/restore", authMiddleware.RequireRole("super_admin"), orgHandler.RestoreOrganization)
+
+		// Merging an organization into another for data cleanup - restricted to super_admin only
+		org.POST("/:id/merge", authMiddleware.RequireRole("super_admin"), orgHandler.MergeOrganization)
+
+		// Direct organization membership
+		org.GET("/:id/members", orgHandler.ListOrganizationMembers)
+		org.POST("/:id/members", orgHandler.AddOrganizationMember)
+		org.DELETE("/:id/members/:userId", orgHandler.RemoveOrganizationMember)
+
+		// Organization settings - readable by any caller allowed to see the organization, managed by super_admin
+		org.GET("/:id/settings", orgHandler.GetOrganizationSettings)
+		org.PUT("/:id/settings", authMiddleware.RequireRole("super_admin"), orgHandler.UpdateOrganizationSettings)
+
+		// Custom user attributes - the schema is managed by super_admin, values by callers allowed to update the organization
+		org.GET("/:id/user-attribute-schema", orgHandler.GetUserAttributeSchema)
+		org.PUT("/:id/user-attribute-schema", authMiddleware.RequireRole("super_admin"), orgHandler.SetUserAttributeSchema)
+		org.GET("/:id/users/:userId/attributes", orgHandler.GetUserAttributes)
+		org.PUT("/:id/users/:userId/attributes", orgHandler.SetUserAttributes)
+
+		// Role templates instantiate a predefined set of organization-scoped roles - restricted to super_admin only
+		org.POST("/:id/apply-template", authMiddleware.RequireRole("super_admin"), orgHandler.ApplyRoleTemplate)
+
+		// Role assignment constraints - readable by any caller allowed to see the organization, managed by super_admin
+		org.GET("/:id/role-constraints", orgHandler.ListRoleConstraints)
+		org.GET("/:id/role-constraints/violations", orgHandler.ListRoleConstraintViolations)
+		org.POST("/:id/role-constraints", authMiddleware.RequireRole("super_admin"), orgHandler.CreateRoleConstraint)
+		org.DELETE("/:id/role-constraints/:constraintId", authMiddleware.RequireRole("super_admin"), orgHandler.DeleteRoleConstraint)
+
+		// Roles that can be assigned within the organization: its own roles plus the global ones
+		org.GET("/:id/available-roles", orgHandler.ListAvailableRoles)
+
 		// Organization-scoped group management routes
 		org.GET("/:id/groups", orgHandler.GetOrganizationGroups)
 		org.POST("/:id/groups", orgHandler.CreateGroupInOrganization)
@@ -31,6 +68,7 @@ func SetupOrganizationRoutes(apiGroup *gin.RouterGroup, orgHandler *organization
 		// Group update/delete - restricted to super_admin only
 		org.PUT("/:id/groups/:groupId", authMiddleware.RequireRole("super_admin"), orgHandler.UpdateGroupInOrganization)
 		org.DELETE("/:id/groups/:groupId", authMiddleware.RequireRole("super_admin"), orgHandler.DeleteGroupInOrganization)
+		org.POST("/:id/groups/:groupId/move", authMiddleware.RequireRole("super_admin"), orgHandler.MoveGroupInOrganization)
 
 		// User-group management within organization context
 		org.POST("/:id/groups/:groupId/users", orgHandler.AddUserToGroupInOrganization)
@@ -44,4 +82,22 @@ func SetupOrganizationRoutes(apiGroup *gin.RouterGroup, orgHandler *organization
 		org.DELETE("/:id/groups/:groupId/roles/:roleId", orgHandler.RemoveRoleFromGroupInOrganization)
 		org.GET("/:id/groups/:groupId/roles", orgHandler.GetGroupRolesInOrganization)
 	}
+
+	// Role template catalog - listing is open to authenticated callers, definitions are managed by super_admin
+	templates := apiGroup.Group("/role-templates")
+	{
+		templates.GET("", orgHandler.ListRoleTemplates)
+		templates.POST("", authMiddleware.RequireRole("super_admin"), orgHandler.CreateRoleTemplate)
+		templates.DELETE("/:name", authMiddleware.RequireRole("super_admin"), orgHandler.DeleteRoleTemplate)
+	}
+}
+
+// RegisterOrganizationQuotaRoutes registers the quota status of an organization, readable by any
+// caller allowed to see the organization
+func RegisterOrganizationQuotaRoutes(router *gin.Engine, quotaHandler *organizations.QuotaHandler, authMiddleware *middleware.AuthMiddleware) {
+	org := router.Group("/api/v1/organizations")
+	org.Use(authMiddleware.HTTPAuthMiddleware())
+	{
+		org.GET("/:id/quota", quotaHandler.GetOrganizationQuota)
+	}
 }
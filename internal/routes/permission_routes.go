@@ -23,6 +23,9 @@ func SetupPermissionRoutes(protectedAPI *gin.RouterGroup, authMiddleware *middle
 
 		// Permission evaluation endpoints
 		perms.POST("/evaluate", permissionHandler.EvaluatePermission)
+
+		// Maintenance scan for permissions that reference missing resources
+		perms.GET("/validation", authMiddleware.RequireRole("super_admin", "admin"), permissionHandler.ValidatePermissions)
 	}
 
 	// User-specific permission evaluation
@@ -38,6 +41,7 @@ func SetupPermissionRoutes(protectedAPI *gin.RouterGroup, authMiddleware *middle
 		roles.POST("/:id/permissions", authMiddleware.RequirePermission("role", "update"), permissionHandler.AssignPermissionsToRole)
 		roles.DELETE("/:id/permissions/:permId", authMiddleware.RequirePermission("role", "update"), permissionHandler.RevokePermissionFromRole)
 		roles.GET("/:id/permissions", authMiddleware.RequirePermission("role", "read"), permissionHandler.GetRolePermissions)
+		roles.GET("/:id/permission-history", authMiddleware.RequirePermission("role", "read"), permissionHandler.GetRolePermissionHistory)
 
 		// Model 2: Resource-action assignments (resource_permissions table)
 		roles.POST("/:id/resources", authMiddleware.RequirePermission("role", "update"), permissionHandler.AssignResourcesToRole)
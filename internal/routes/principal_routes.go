@@ -22,6 +22,10 @@ func RegisterPrincipalRoutes(router *gin.Engine, principalHandler *principals.Ha
 			authenticated.POST("", principalHandler.CreatePrincipal)
 			authenticated.PUT("/:id", principalHandler.UpdatePrincipal)
 			authenticated.DELETE("/:id", principalHandler.DeletePrincipal)
+			authenticated.GET("/services", authMiddleware.RequireRole("super_admin", "admin"), principalHandler.ListServicePrincipals)
+			authenticated.GET("/:id/activity", authMiddleware.RequireRole("super_admin", "admin"), principalHandler.GetPrincipalActivity)
+			authenticated.GET("/:id/resource-permissions", authMiddleware.RequireRole("super_admin", "admin"), principalHandler.ListPrincipalResourcePermissions)
+			authenticated.POST("/:id/rotate", authMiddleware.RequireRole("super_admin", "admin"), principalHandler.RotateServiceCredentials)
 		}
 	}
 
@@ -21,6 +21,11 @@ func RegisterResourceRoutes(
 		// Resource CRUD operations
 		resources.POST("", authMiddleware.RequirePermission("resource", "create"), resourceHandler.CreateResource)
 		resources.GET("", authMiddleware.RequirePermission("resource", "read"), resourceHandler.ListResources)
+
+		// Resource registration by other services and type discovery
+		resources.POST("/register", authMiddleware.RequireServiceScope("resources:write"), resourceHandler.RegisterResource)
+		resources.GET("/types", authMiddleware.RequirePermission("resource", "read"), resourceHandler.ListResourceTypes)
+
 		resources.GET("/:id", authMiddleware.RequirePermission("resource", "read"), resourceHandler.GetResource)
 		resources.PUT("/:id", authMiddleware.RequirePermission("resource", "update"), resourceHandler.UpdateResource)
 		resources.DELETE("/:id", authMiddleware.RequirePermission("resource", "delete"), resourceHandler.DeleteResource)
@@ -17,6 +17,11 @@ func SetupRoleRoutes(protectedAPI *gin.RouterGroup, authMiddleware *middleware.A
 		roles.GET("/:id", authMiddleware.RequirePermission("role", "view"), roleHandler.GetRole)
 		roles.PUT("/:id", authMiddleware.RequirePermission("role", "update"), roleHandler.UpdateRole)
 		roles.DELETE("/:id", authMiddleware.RequirePermission("role", "delete"), roleHandler.DeleteRole)
+		roles.GET("/:id/users", authMiddleware.RequirePermission("role", "view"), roleHandler.GetRoleUsers)
+		roles.POST("/:id/assign-bulk",
+			middleware.SensitiveOperationRateLimit(),
+			authMiddleware.RequirePermission("role", "assign"),
+			roleHandler.AssignRoleBulk)
 	}
 }
 
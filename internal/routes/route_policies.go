@@ -0,0 +1,304 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/middleware"
+)
+
+var (
+	publicRoute        = middleware.RoutePolicy{Public: true}
+	authenticatedRoute = middleware.RoutePolicy{}
+)
+
+// permissionRoute requires the permission to perform action on resource, checked against the ID
+// in the idParam path parameter when one is given
+func permissionRoute(resource, action, idParam string) middleware.RoutePolicy {
+	return middleware.RoutePolicy{Resource: resource, Action: action, ResourceIDParam: idParam}
+}
+
+// ownerRoute requires the permission unless the caller is the user named by the ownerParam path
+// parameter
+func ownerRoute(resource, action, ownerParam string) middleware.RoutePolicy {
+	return middleware.RoutePolicy{Resource: resource, Action: action, ResourceIDParam: ownerParam, OwnerParam: ownerParam}
+}
+
+// serviceRoute restricts a route to service tokens granted scope
+func serviceRoute(scope string) middleware.RoutePolicy {
+	return middleware.RoutePolicy{Scope: scope, ServiceOnly: true}
+}
+
+// DeclareRoutePolicies declares the authorization policy of every route this service registers.
+// A new route must be added here; the server reports routes without a policy at startup and, with
+// AAA_ROUTE_POLICY_STRICT, refuses to start. Permission names follow the path convention the
+// authorization middleware used before policies were declared ("<path resource>:<http method>"),
+// so existing role grants keep working.
+func DeclareRoutePolicies(policies *middleware.RoutePolicies) {
+	// Probes, documentation, metrics and token verification keys
+	policies.Declare(http.MethodGet, "/", publicRoute)
+	policies.Declare(http.MethodGet, "/docs", publicRoute)
+	policies.Declare(http.MethodGet, "/docs/swagger.json", publicRoute)
+	policies.Declare(http.MethodHead, "/docs/swagger.json", publicRoute)
+	policies.Declare(http.MethodGet, "/docs/swagger.yaml", publicRoute)
+	policies.Declare(http.MethodHead, "/docs/swagger.yaml", publicRoute)
+	policies.Declare(http.MethodGet, "/favicon.ico", publicRoute)
+	policies.Declare(http.MethodGet, "/healthz", publicRoute)
+	policies.Declare(http.MethodGet, "/metrics", permissionRoute("api_endpoint", "get", ""))
+	policies.Declare(http.MethodGet, "/readyz", publicRoute)
+	policies.Declare(http.MethodGet, "/.well-known/jwks.json", publicRoute)
+
+	// Health
+	policies.Declare(http.MethodGet, "/api/v1/health", publicRoute)
+
+	// Authentication: login flows are public, account self-service needs only a session
+	policies.Declare(http.MethodPost, "/api/v1/auth/account-deletion/cancel", publicRoute)
+	policies.Declare(http.MethodGet, "/api/v1/auth/can", serviceRoute("auth:check"))
+	policies.Declare(http.MethodPost, "/api/v1/auth/change-password", authenticatedRoute)
+	policies.Declare(http.MethodPost, "/api/v1/auth/forgot-password", publicRoute)
+	policies.Declare(http.MethodPost, "/api/v1/auth/forgot-mpin", publicRoute)
+	policies.Declare(http.MethodPost, "/api/v1/auth/login", publicRoute)
+	policies.Declare(http.MethodPost, "/api/v1/auth/login/mpin", publicRoute)
+	policies.Declare(http.MethodPost, "/api/v1/auth/logout", authenticatedRoute)
+	policies.Declare(http.MethodGet, "/api/v1/auth/oidc/:provider/callback", publicRoute)
+	policies.Declare(http.MethodGet, "/api/v1/auth/oidc/:provider/login", publicRoute)
+	policies.Declare(http.MethodPost, "/api/v1/auth/refresh", publicRoute)
+	policies.Declare(http.MethodPost, "/api/v1/auth/register", publicRoute)
+	policies.Declare(http.MethodPost, "/api/v1/auth/reset-mpin", publicRoute)
+	policies.Declare(http.MethodPost, "/api/v1/auth/reset-password", publicRoute)
+	policies.Declare(http.MethodPost, "/api/v1/auth/session/token", publicRoute)
+	policies.Declare(http.MethodPost, "/api/v1/auth/set-mpin", authenticatedRoute)
+	policies.Declare(http.MethodPost, "/api/v1/auth/update-mpin", authenticatedRoute)
+
+	// Self-service
+	policies.Declare(http.MethodGet, "/api/v1/me/groups", authenticatedRoute)
+	policies.Declare(http.MethodGet, "/api/v1/me/organizations", authenticatedRoute)
+
+	// Users; /users/me routes act on the caller, sessions and access reports are also open to their user
+	policies.Declare(http.MethodGet, "/api/v1/users", permissionRoute("users", "get", ""))
+	policies.Declare(http.MethodPost, "/api/v1/users", permissionRoute("users", "post", ""))
+	policies.Declare(http.MethodGet, "/api/v1/users/:id", permissionRoute("users", "get", "id"))
+	policies.Declare(http.MethodPut, "/api/v1/users/:id", permissionRoute("users", "put", "id"))
+	policies.Declare(http.MethodDelete, "/api/v1/users/:id", permissionRoute("users", "delete", "id"))
+	policies.Declare(http.MethodGet, "/api/v1/users/:id/access-report", ownerRoute("users", "get", "id"))
+	policies.Declare(http.MethodPost, "/api/v1/users/:id/evaluate", permissionRoute("users", "post", "id"))
+	policies.Declare(http.MethodGet, "/api/v1/users/:id/export", ownerRoute("users", "get", "id"))
+	policies.Declare(http.MethodGet, "/api/v1/users/:id/organizations", permissionRoute("users", "get", "id"))
+	policies.Declare(http.MethodGet, "/api/v1/users/:id/roles", permissionRoute("users", "get", "id"))
+	policies.Declare(http.MethodPost, "/api/v1/users/:id/roles", permissionRoute("users", "post", "id"))
+	policies.Declare(http.MethodPut, "/api/v1/users/:id/roles", permissionRoute("users", "put", "id"))
+	policies.Declare(http.MethodPost, "/api/v1/users/:id/roles/:roleId", permissionRoute("users", "post", "id"))
+	policies.Declare(http.MethodDelete, "/api/v1/users/:id/roles/:roleId", permissionRoute("users", "delete", "id"))
+	policies.Declare(http.MethodDelete, "/api/v1/users/:id/roles/:roleId/legacy", permissionRoute("users", "delete", "id"))
+	policies.Declare(http.MethodGet, "/api/v1/users/:id/sessions", ownerRoute("users", "get", "id"))
+	policies.Declare(http.MethodDelete, "/api/v1/users/:id/sessions/:jti", ownerRoute("users", "delete", "id"))
+	policies.Declare(http.MethodPost, "/api/v1/users/:id/validate", permissionRoute("users", "post", "id"))
+	policies.Declare(http.MethodGet, "/api/v1/users/batch", permissionRoute("users", "get", ""))
+	policies.Declare(http.MethodPost, "/api/v1/users/batch", permissionRoute("users", "post", ""))
+	policies.Declare(http.MethodPost, "/api/v1/users/exists", permissionRoute("users", "post", ""))
+	policies.Declare(http.MethodPost, "/api/v1/users/import", permissionRoute("users", "post", ""))
+	policies.Declare(http.MethodPost, "/api/v1/users/me/deletion", authenticatedRoute)
+	policies.Declare(http.MethodGet, "/api/v1/users/me/devices", authenticatedRoute)
+	policies.Declare(http.MethodPut, "/api/v1/users/me/devices/:device_id/mpin", authenticatedRoute)
+	policies.Declare(http.MethodDelete, "/api/v1/users/me/devices/:device_id/mpin", authenticatedRoute)
+	policies.Declare(http.MethodGet, "/api/v1/users/me/login-history", authenticatedRoute)
+	policies.Declare(http.MethodGet, "/api/v1/users/me/profile-completeness", authenticatedRoute)
+	policies.Declare(http.MethodGet, "/api/v1/users/me/sessions", authenticatedRoute)
+	policies.Declare(http.MethodDelete, "/api/v1/users/me/sessions/:id", authenticatedRoute)
+	policies.Declare(http.MethodGet, "/api/v1/users/search", permissionRoute("users", "get", ""))
+
+	// Roles
+	policies.Declare(http.MethodGet, "/api/v1/roles", permissionRoute("roles", "get", ""))
+	policies.Declare(http.MethodPost, "/api/v1/roles", permissionRoute("roles", "post", ""))
+	policies.Declare(http.MethodGet, "/api/v1/roles/:id", permissionRoute("roles", "get", "id"))
+	policies.Declare(http.MethodPut, "/api/v1/roles/:id", permissionRoute("roles", "put", "id"))
+	policies.Declare(http.MethodDelete, "/api/v1/roles/:id", permissionRoute("roles", "delete", "id"))
+	policies.Declare(http.MethodPost, "/api/v1/roles/:id/assign-bulk", permissionRoute("roles", "post", "id"))
+	policies.Declare(http.MethodGet, "/api/v1/roles/:id/permissions", permissionRoute("roles", "get", "id"))
+	policies.Declare(http.MethodGet, "/api/v1/roles/:id/permission-history", permissionRoute("roles", "get", "id"))
+	policies.Declare(http.MethodPost, "/api/v1/roles/:id/permissions", permissionRoute("roles", "post", "id"))
+	policies.Declare(http.MethodDelete, "/api/v1/roles/:id/permissions/:permId", permissionRoute("roles", "delete", "id"))
+	policies.Declare(http.MethodGet, "/api/v1/roles/:id/resources", permissionRoute("roles", "get", "id"))
+	policies.Declare(http.MethodGet, "/api/v1/roles/:id/users", permissionRoute("roles", "get", "id"))
+	policies.Declare(http.MethodPost, "/api/v1/roles/:id/resources", permissionRoute("roles", "post", "id"))
+	policies.Declare(http.MethodDelete, "/api/v1/roles/:id/resources/:resId", permissionRoute("roles", "delete", "id"))
+	policies.Declare(http.MethodPost, "/api/v1/roles/exists", permissionRoute("roles", "post", ""))
+
+	// Permissions
+	policies.Declare(http.MethodGet, "/api/v1/permissions", permissionRoute("permissions", "get", ""))
+	policies.Declare(http.MethodPost, "/api/v1/permissions", permissionRoute("permissions", "post", ""))
+	policies.Declare(http.MethodGet, "/api/v1/permissions/:id", permissionRoute("permissions", "get", "id"))
+	policies.Declare(http.MethodPut, "/api/v1/permissions/:id", permissionRoute("permissions", "put", "id"))
+	policies.Declare(http.MethodDelete, "/api/v1/permissions/:id", permissionRoute("permissions", "delete", "id"))
+	policies.Declare(http.MethodPost, "/api/v1/permissions/evaluate", permissionRoute("permissions", "post", ""))
+	policies.Declare(http.MethodGet, "/api/v1/permissions/validation", permissionRoute("permissions", "get", ""))
+
+	// Role templates
+	policies.Declare(http.MethodGet, "/api/v1/role-templates", permissionRoute("role-templates", "get", ""))
+	policies.Declare(http.MethodPost, "/api/v1/role-templates", permissionRoute("role-templates", "post", ""))
+	policies.Declare(http.MethodDelete, "/api/v1/role-templates/:name", permissionRoute("role-templates", "delete", "name"))
+
+	// Permission checks
+	policies.Declare(http.MethodPost, "/api/v1/authz/bulk-check", permissionRoute("authz", "post", ""))
+	policies.Declare(http.MethodPost, "/api/v1/authz/check", permissionRoute("authz", "post", ""))
+	policies.Declare(http.MethodGet, "/api/v1/authz/user/:id/permissions", permissionRoute("authz", "get", ""))
+
+	// Audit trail
+	policies.Declare(http.MethodPost, "/api/v1/audit/ingest", serviceRoute("audit:write"))
+	policies.Declare(http.MethodGet, "/api/v1/audit/logs", permissionRoute("audit", "get", ""))
+	policies.Declare(http.MethodGet, "/api/v1/audit/resource/:type/:id/trail", permissionRoute("audit", "get", ""))
+	policies.Declare(http.MethodGet, "/api/v1/audit/security-events", permissionRoute("audit", "get", ""))
+	policies.Declare(http.MethodGet, "/api/v1/audit/statistics", permissionRoute("audit", "get", ""))
+	policies.Declare(http.MethodGet, "/api/v1/audit/user/:id/trail", permissionRoute("audit", "get", ""))
+
+	// Administration
+	policies.Declare(http.MethodPost, "/api/v1/admin/archive-logs", permissionRoute("admin", "post", ""))
+	policies.Declare(http.MethodPost, "/api/v1/admin/assign-role", permissionRoute("admin", "post", ""))
+	policies.Declare(http.MethodGet, "/api/v1/admin/audit", permissionRoute("admin", "get", ""))
+	policies.Declare(http.MethodGet, "/api/v1/admin/db/pool", permissionRoute("admin", "get", ""))
+	policies.Declare(http.MethodPost, "/api/v1/admin/grant-permission", permissionRoute("admin", "post", ""))
+	policies.Declare(http.MethodGet, "/api/v1/admin/health/detailed", permissionRoute("admin", "get", ""))
+	policies.Declare(http.MethodGet, "/api/v1/admin/hierarchy/depth", permissionRoute("admin", "get", ""))
+	policies.Declare(http.MethodPost, "/api/v1/admin/impersonate", permissionRoute("admin", "post", ""))
+	policies.Declare(http.MethodDelete, "/api/v1/admin/impersonate/:id", permissionRoute("admin", "delete", ""))
+	policies.Declare(http.MethodGet, "/api/v1/admin/maintenance", permissionRoute("admin", "get", ""))
+	policies.Declare(http.MethodPost, "/api/v1/admin/maintenance", permissionRoute("admin", "post", ""))
+	policies.Declare(http.MethodPatch, "/api/v1/admin/maintenance/message", permissionRoute("admin", "patch", ""))
+	policies.Declare(http.MethodGet, "/api/v1/admin/metrics", permissionRoute("admin", "get", ""))
+	policies.Declare(http.MethodGet, "/api/v1/admin/rbac/preview", permissionRoute("admin", "get", ""))
+	policies.Declare(http.MethodPost, "/api/v1/admin/remove-role", permissionRoute("admin", "post", ""))
+	policies.Declare(http.MethodPost, "/api/v1/admin/revoke-permission", permissionRoute("admin", "post", ""))
+	policies.Declare(http.MethodGet, "/api/v1/admin/signing-keys", permissionRoute("admin", "get", ""))
+	policies.Declare(http.MethodPost, "/api/v1/admin/signing-keys/rotate", permissionRoute("admin", "post", ""))
+	policies.Declare(http.MethodGet, "/api/v1/admin/system", permissionRoute("admin", "get", ""))
+
+	// Modules
+	policies.Declare(http.MethodGet, "/api/v1/modules", permissionRoute("modules", "get", ""))
+	policies.Declare(http.MethodGet, "/api/v1/modules/:service_name", permissionRoute("modules", "get", "service_name"))
+	policies.Declare(http.MethodGet, "/api/v1/modules/:service_name/health", permissionRoute("modules", "get", "service_name"))
+	policies.Declare(http.MethodPost, "/api/v1/modules/register", permissionRoute("modules", "post", ""))
+
+	// Contacts
+	policies.Declare(http.MethodGet, "/api/v1/contacts", permissionRoute("contacts", "get", ""))
+	policies.Declare(http.MethodPost, "/api/v1/contacts", permissionRoute("contacts", "post", ""))
+	policies.Declare(http.MethodGet, "/api/v1/contacts/:id", permissionRoute("contacts", "get", "id"))
+	policies.Declare(http.MethodPut, "/api/v1/contacts/:id", permissionRoute("contacts", "put", "id"))
+	policies.Declare(http.MethodDelete, "/api/v1/contacts/:id", permissionRoute("contacts", "delete", "id"))
+	policies.Declare(http.MethodPost, "/api/v1/contacts/:id/verification", permissionRoute("contacts", "post", "id"))
+	policies.Declare(http.MethodPost, "/api/v1/contacts/:id/verification/confirm", permissionRoute("contacts", "post", "id"))
+	policies.Declare(http.MethodPost, "/api/v1/contacts/:id/verification/resend", permissionRoute("contacts", "post", "id"))
+	policies.Declare(http.MethodGet, "/api/v1/contacts/user/:userID", permissionRoute("contacts", "get", ""))
+
+	// Addresses
+	policies.Declare(http.MethodPost, "/api/v1/addresses", permissionRoute("addresses", "post", ""))
+	policies.Declare(http.MethodGet, "/api/v1/addresses/:id", permissionRoute("addresses", "get", "id"))
+	policies.Declare(http.MethodPut, "/api/v1/addresses/:id", permissionRoute("addresses", "put", "id"))
+	policies.Declare(http.MethodDelete, "/api/v1/addresses/:id", permissionRoute("addresses", "delete", "id"))
+	policies.Declare(http.MethodPost, "/api/v1/addresses/:id/geocode", permissionRoute("addresses", "post", "id"))
+	policies.Declare(http.MethodPost, "/api/v1/addresses/bulk", permissionRoute("addresses", "post", ""))
+	policies.Declare(http.MethodGet, "/api/v1/addresses/search", permissionRoute("addresses", "get", ""))
+
+	// Organizations
+	policies.Declare(http.MethodGet, "/api/v1/organizations", permissionRoute("organizations", "get", ""))
+	policies.Declare(http.MethodPost, "/api/v1/organizations", permissionRoute("organizations", "post", ""))
+	policies.Declare(http.MethodGet, "/api/v1/organizations/:id", permissionRoute("organizations", "get", "id"))
+	policies.Declare(http.MethodPut, "/api/v1/organizations/:id", permissionRoute("organizations", "put", "id"))
+	policies.Declare(http.MethodDelete, "/api/v1/organizations/:id", permissionRoute("organizations", "delete", "id"))
+	policies.Declare(http.MethodPost, "/api/v1/organizations/:id/activate", permissionRoute("organizations", "post", "id"))
+	policies.Declare(http.MethodGet, "/api/v1/organizations/:id/ancestors", permissionRoute("organizations", "get", "id"))
+	policies.Declare(http.MethodPost, "/api/v1/organizations/:id/apply-template", permissionRoute("organizations", "post", "id"))
+	policies.Declare(http.MethodGet, "/api/v1/organizations/:id/available-roles", permissionRoute("organizations", "get", "id"))
+	policies.Declare(http.MethodPost, "/api/v1/organizations/:id/deactivate", permissionRoute("organizations", "post", "id"))
+	policies.Declare(http.MethodGet, "/api/v1/organizations/:id/descendants", permissionRoute("organizations", "get", "id"))
+	policies.Declare(http.MethodGet, "/api/v1/organizations/:id/group-hierarchy", permissionRoute("organizations", "get", "id"))
+	policies.Declare(http.MethodGet, "/api/v1/organizations/:id/groups", permissionRoute("organizations", "get", "id"))
+	policies.Declare(http.MethodPost, "/api/v1/organizations/:id/groups", permissionRoute("organizations", "post", "id"))
+	policies.Declare(http.MethodGet, "/api/v1/organizations/:id/groups/:groupId", permissionRoute("organizations", "get", "id"))
+	policies.Declare(http.MethodPut, "/api/v1/organizations/:id/groups/:groupId", permissionRoute("organizations", "put", "id"))
+	policies.Declare(http.MethodDelete, "/api/v1/organizations/:id/groups/:groupId", permissionRoute("organizations", "delete", "id"))
+	policies.Declare(http.MethodPost, "/api/v1/organizations/:id/groups/:groupId/move", permissionRoute("organizations", "post", "id"))
+	policies.Declare(http.MethodGet, "/api/v1/organizations/:id/groups/:groupId/roles", permissionRoute("organizations", "get", "id"))
+	policies.Declare(http.MethodPost, "/api/v1/organizations/:id/groups/:groupId/roles", permissionRoute("organizations", "post", "id"))
+	policies.Declare(http.MethodDelete, "/api/v1/organizations/:id/groups/:groupId/roles/:roleId", permissionRoute("organizations", "delete", "id"))
+	policies.Declare(http.MethodGet, "/api/v1/organizations/:id/groups/:groupId/users", permissionRoute("organizations", "get", "id"))
+	policies.Declare(http.MethodPost, "/api/v1/organizations/:id/groups/:groupId/users", permissionRoute("organizations", "post", "id"))
+	policies.Declare(http.MethodDelete, "/api/v1/organizations/:id/groups/:groupId/users/:userId", permissionRoute("organizations", "delete", "id"))
+	policies.Declare(http.MethodPost, "/api/v1/organizations/:id/hard-delete-token", permissionRoute("organizations", "post", "id"))
+	policies.Declare(http.MethodGet, "/api/v1/organizations/:id/hierarchy", permissionRoute("organizations", "get", "id"))
+	policies.Declare(http.MethodGet, "/api/v1/organizations/:id/members", permissionRoute("organizations", "get", "id"))
+	policies.Declare(http.MethodPost, "/api/v1/organizations/:id/members", permissionRoute("organizations", "post", "id"))
+	policies.Declare(http.MethodDelete, "/api/v1/organizations/:id/members/:userId", permissionRoute("organizations", "delete", "id"))
+	policies.Declare(http.MethodPost, "/api/v1/organizations/:id/merge", permissionRoute("organizations", "post", "id"))
+	policies.Declare(http.MethodPost, "/api/v1/organizations/:id/restore", permissionRoute("organizations", "post", "id"))
+	policies.Declare(http.MethodGet, "/api/v1/organizations/:id/role-constraints", permissionRoute("organizations", "get", "id"))
+	policies.Declare(http.MethodPost, "/api/v1/organizations/:id/role-constraints", permissionRoute("organizations", "post", "id"))
+	policies.Declare(http.MethodDelete, "/api/v1/organizations/:id/role-constraints/:constraintId", permissionRoute("organizations", "delete", "id"))
+	policies.Declare(http.MethodGet, "/api/v1/organizations/:id/role-constraints/violations", permissionRoute("organizations", "get", "id"))
+	policies.Declare(http.MethodGet, "/api/v1/organizations/:id/settings", permissionRoute("organizations", "get", "id"))
+	policies.Declare(http.MethodPut, "/api/v1/organizations/:id/settings", permissionRoute("organizations", "put", "id"))
+	policies.Declare(http.MethodGet, "/api/v1/organizations/:id/quota", permissionRoute("organizations", "get", "id"))
+	policies.Declare(http.MethodGet, "/api/v1/organizations/:id/stats", permissionRoute("organizations", "get", "id"))
+	policies.Declare(http.MethodGet, "/api/v1/organizations/:id/user-attribute-schema", permissionRoute("organizations", "get", "id"))
+	policies.Declare(http.MethodPut, "/api/v1/organizations/:id/user-attribute-schema", permissionRoute("organizations", "put", "id"))
+	policies.Declare(http.MethodGet, "/api/v1/organizations/:id/users/:userId/attributes", permissionRoute("organizations", "get", "id"))
+	policies.Declare(http.MethodPut, "/api/v1/organizations/:id/users/:userId/attributes", permissionRoute("organizations", "put", "id"))
+	policies.Declare(http.MethodGet, "/api/v1/organizations/:id/users/:userId/effective-roles", permissionRoute("organizations", "get", "id"))
+	policies.Declare(http.MethodGet, "/api/v1/organizations/:id/users/:userId/groups", permissionRoute("organizations", "get", "id"))
+	policies.Declare(http.MethodPost, "/api/v1/organizations/exists", permissionRoute("organizations", "post", ""))
+
+	// Groups
+	policies.Declare(http.MethodGet, "/api/v1/groups", permissionRoute("groups", "get", ""))
+	policies.Declare(http.MethodPost, "/api/v1/groups", permissionRoute("groups", "post", ""))
+	policies.Declare(http.MethodGet, "/api/v1/groups/:id", permissionRoute("groups", "get", "id"))
+	policies.Declare(http.MethodPut, "/api/v1/groups/:id", permissionRoute("groups", "put", "id"))
+	policies.Declare(http.MethodDelete, "/api/v1/groups/:id", permissionRoute("groups", "delete", "id"))
+	policies.Declare(http.MethodGet, "/api/v1/groups/:id/members", permissionRoute("groups", "get", "id"))
+	policies.Declare(http.MethodPost, "/api/v1/groups/:id/members", permissionRoute("groups", "post", "id"))
+	policies.Declare(http.MethodDelete, "/api/v1/groups/:id/members/:principal_id", permissionRoute("groups", "delete", "id"))
+	policies.Declare(http.MethodPost, "/api/v1/groups/exists", permissionRoute("groups", "post", ""))
+
+	// Role and permission catalog
+	policies.Declare(http.MethodPost, "/api/v1/catalog/seed", permissionRoute("catalog", "post", ""))
+	policies.Declare(http.MethodGet, "/api/v1/catalog/seed/status", permissionRoute("catalog", "get", ""))
+
+	// Principals
+	policies.Declare(http.MethodGet, "/api/v1/principals", permissionRoute("principals", "get", ""))
+	policies.Declare(http.MethodPost, "/api/v1/principals", permissionRoute("principals", "post", ""))
+	policies.Declare(http.MethodGet, "/api/v1/principals/services", permissionRoute("services", "get", ""))
+	policies.Declare(http.MethodGet, "/api/v1/principals/:id", permissionRoute("principals", "get", "id"))
+	policies.Declare(http.MethodPut, "/api/v1/principals/:id", permissionRoute("principals", "put", "id"))
+	policies.Declare(http.MethodDelete, "/api/v1/principals/:id", permissionRoute("principals", "delete", "id"))
+	policies.Declare(http.MethodGet, "/api/v1/principals/:id/activity", permissionRoute("principals", "get", "id"))
+	policies.Declare(http.MethodGet, "/api/v1/principals/:id/resource-permissions", permissionRoute("principals", "get", "id"))
+	policies.Declare(http.MethodPost, "/api/v1/principals/:id/rotate", permissionRoute("principals", "post", "id"))
+
+	// Service accounts
+	policies.Declare(http.MethodGet, "/api/v1/services", permissionRoute("services", "get", ""))
+	policies.Declare(http.MethodPost, "/api/v1/services", permissionRoute("services", "post", ""))
+	policies.Declare(http.MethodGet, "/api/v1/services/:id", permissionRoute("services", "get", "id"))
+	policies.Declare(http.MethodDelete, "/api/v1/services/:id", permissionRoute("services", "delete", "id"))
+	policies.Declare(http.MethodPost, "/api/v1/services/generate-api-key", permissionRoute("services", "post", ""))
+
+	// RBAC resources
+	policies.Declare(http.MethodGet, "/api/v1/resources", permissionRoute("resources", "get", ""))
+	policies.Declare(http.MethodPost, "/api/v1/resources", permissionRoute("resources", "post", ""))
+	policies.Declare(http.MethodGet, "/api/v1/resources/:id", permissionRoute("resources", "get", "id"))
+	policies.Declare(http.MethodPut, "/api/v1/resources/:id", permissionRoute("resources", "put", "id"))
+	policies.Declare(http.MethodDelete, "/api/v1/resources/:id", permissionRoute("resources", "delete", "id"))
+	policies.Declare(http.MethodGet, "/api/v1/resources/:id/children", permissionRoute("resources", "get", "id"))
+	policies.Declare(http.MethodGet, "/api/v1/resources/:id/hierarchy", permissionRoute("resources", "get", "id"))
+	policies.Declare(http.MethodPost, "/api/v1/resources/register", serviceRoute("resources:write"))
+	policies.Declare(http.MethodGet, "/api/v1/resources/types", permissionRoute("resources", "get", ""))
+
+	// RBAC actions
+	policies.Declare(http.MethodGet, "/api/v1/actions", permissionRoute("actions", "get", ""))
+	policies.Declare(http.MethodPost, "/api/v1/actions", permissionRoute("actions", "post", ""))
+	policies.Declare(http.MethodGet, "/api/v1/actions/:id", permissionRoute("actions", "get", "id"))
+	policies.Declare(http.MethodPut, "/api/v1/actions/:id", permissionRoute("actions", "put", "id"))
+	policies.Declare(http.MethodDelete, "/api/v1/actions/:id", permissionRoute("actions", "delete", "id"))
+	policies.Declare(http.MethodGet, "/api/v1/actions/service/:serviceName", permissionRoute("actions", "get", ""))
+
+	// KYC
+	policies.Declare(http.MethodPost, "/api/v1/kyc/aadhaar/otp", permissionRoute("kyc", "post", ""))
+	policies.Declare(http.MethodPost, "/api/v1/kyc/aadhaar/otp/verify", permissionRoute("kyc", "post", ""))
+	policies.Declare(http.MethodGet, "/api/v1/kyc/status/:user_id", permissionRoute("kyc", "get", ""))
+}
@@ -0,0 +1,126 @@
+package routes
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/handlers/actions"
+	"github.com/Kisanlink/aaa-service/v2/internal/handlers/admin"
+	"github.com/Kisanlink/aaa-service/v2/internal/handlers/auth"
+	"github.com/Kisanlink/aaa-service/v2/internal/handlers/authz"
+	"github.com/Kisanlink/aaa-service/v2/internal/handlers/existence"
+	"github.com/Kisanlink/aaa-service/v2/internal/handlers/groups"
+	"github.com/Kisanlink/aaa-service/v2/internal/handlers/health"
+	"github.com/Kisanlink/aaa-service/v2/internal/handlers/kyc"
+	"github.com/Kisanlink/aaa-service/v2/internal/handlers/organizations"
+	"github.com/Kisanlink/aaa-service/v2/internal/handlers/permissions"
+	"github.com/Kisanlink/aaa-service/v2/internal/handlers/principals"
+	resourceHandlers "github.com/Kisanlink/aaa-service/v2/internal/handlers/resources"
+	"github.com/Kisanlink/aaa-service/v2/internal/handlers/roles"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/internal/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+// importingUserService enables the CSV import route
+type importingUserService struct {
+	interfaces.UserService
+	interfaces.UserImportService
+}
+
+// policyTestServices enables the optional session and device routes
+type policyTestServices struct {
+	interfaces.LoginChallengeService
+	interfaces.DeviceSessionService
+	interfaces.DeviceMPinService
+	interfaces.TokenSessionService
+	interfaces.LoginRecorder
+}
+
+type policyTestReporter struct {
+	authz.AccessReporter
+}
+
+type policyTestChecker struct {
+	existence.Checker
+}
+
+// registerAllRoutes registers every route of the service, including the optional ones
+func registerAllRoutes(router *gin.Engine) {
+	authMiddleware := &middleware.AuthMiddleware{}
+	logger := zap.NewNop()
+	publicAPI := router.Group("/api/v1")
+	protectedAPI := router.Group("/api/v1")
+	optional := policyTestServices{}
+
+	SetupHealthRoutes(publicAPI, logger)
+	SetupAuthRoutes(publicAPI, protectedAPI, authMiddleware, &importingUserService{}, optional, optional, optional, optional, optional, nil, nil, nil, logger)
+	SetupUserRoutes(protectedAPI, authMiddleware, &importingUserService{}, nil, nil, nil, logger)
+	SetupRoleRoutes(protectedAPI, authMiddleware, &roles.RoleHandler{}, logger)
+	SetupPermissionRoutes(protectedAPI, authMiddleware, &permissions.PermissionHandler{}, logger)
+	SetupAuthorizationRoutes(protectedAPI, nil, logger)
+	SetupAuditRoutes(protectedAPI, authMiddleware, nil, logger)
+	RegisterAuditIngestRoutes(router, nil, authMiddleware, 60, logger)
+	SetupAdminRoutes(protectedAPI, &admin.AdminHandler{}, authMiddleware)
+	SetupModuleRoutes(protectedAPI, logger)
+	SetupContactRoutes(protectedAPI, authMiddleware, nil, nil, nil, logger)
+	SetupAddressRoutes(protectedAPI, authMiddleware, nil, nil, nil, logger)
+	SetupOrganizationRoutes(protectedAPI, &organizations.Handler{}, authMiddleware)
+	SetupCatalogRoutes(protectedAPI, authMiddleware, nil, logger)
+	RegisterGroupRoutes(router, &groups.Handler{}, authMiddleware)
+	RegisterPrincipalRoutes(router, &principals.Handler{}, authMiddleware)
+	RegisterResourceRoutes(router, &resourceHandlers.ResourceHandler{}, authMiddleware)
+	RegisterOrganizationQuotaRoutes(router, &organizations.QuotaHandler{}, authMiddleware)
+
+	authzHandler := &authz.Handler{}
+	authzHandler.SetAccessReporter(policyTestReporter{})
+	RegisterPermissionCheckRoutes(router, authzHandler, authMiddleware)
+	RegisterAccessReportRoutes(router, authzHandler, authMiddleware)
+	RegisterActionRoutes(router.Group("/api/v1"), &actions.ActionHandler{})
+	kyc.RegisterRoutes(router, &kyc.Handler{}, authMiddleware.HTTPAuthMiddleware())
+
+	existenceHandler := existence.NewHandler(nil, logger)
+	for _, resource := range []string{existence.ResourceUser, existence.ResourceOrganization, existence.ResourceRole, existence.ResourceGroup} {
+		existenceHandler.SetChecker(resource, policyTestChecker{})
+	}
+	RegisterExistenceRoutes(router, existenceHandler, authMiddleware)
+	RegisterProbeRoutes(router, &health.HealthHandler{})
+	RegisterOIDCRoutes(router, &auth.OIDCHandler{}, true)
+	RegisterJWKSRoutes(router, &auth.JWKSHandler{}, true)
+}
+
+func TestDeclareRoutePolicies_CoversEveryRoute(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	registerAllRoutes(router)
+
+	policies := middleware.NewRoutePolicies()
+	DeclareRoutePolicies(policies)
+
+	assert.Empty(t, policies.Undeclared(router.Routes()), "every route needs a policy in DeclareRoutePolicies")
+}
+
+func TestDeclareRoutePolicies(t *testing.T) {
+	policies := middleware.NewRoutePolicies()
+	DeclareRoutePolicies(policies)
+
+	tests := []struct {
+		method string
+		path   string
+		want   middleware.RoutePolicy
+	}{
+		{http.MethodPost, "/api/v1/auth/login/mpin", middleware.RoutePolicy{Public: true}},
+		{http.MethodPost, "/api/v1/auth/change-password", middleware.RoutePolicy{}},
+		{http.MethodGet, "/api/v1/users/:id", middleware.RoutePolicy{Resource: "users", Action: "get", ResourceIDParam: "id"}},
+		{http.MethodGet, "/api/v1/users/:id/access-report", middleware.RoutePolicy{Resource: "users", Action: "get", ResourceIDParam: "id", OwnerParam: "id"}},
+		{http.MethodPost, "/api/v1/resources/register", middleware.RoutePolicy{Scope: "resources:write", ServiceOnly: true}},
+	}
+	for _, tt := range tests {
+		policy, ok := policies.Lookup(tt.method, tt.path)
+		if assert.True(t, ok, "%s %s", tt.method, tt.path) {
+			assert.Equal(t, tt.want, policy, "%s %s", tt.method, tt.path)
+		}
+	}
+}
@@ -1,8 +1,7 @@
 package routes
 
 import (
-	"time"
-
+	"github.com/Kisanlink/aaa-service/v2/helper"
 	"github.com/Kisanlink/aaa-service/v2/internal/handlers/admin"
 	"github.com/Kisanlink/aaa-service/v2/internal/handlers/groups"
 	"github.com/Kisanlink/aaa-service/v2/internal/handlers/organizations"
@@ -44,7 +43,6 @@ func SetupAAA(router *gin.Engine, handlers RouteHandlers) {
 	router.Use(middleware.CORS())
 	router.Use(middleware.RequestID())
 	router.Use(middleware.RequestSizeLimit(10 * 1024 * 1024)) // 10MB limit
-	router.Use(middleware.Timeout(30 * time.Second))
 	if handlers.Logger != nil {
 		// Note: Using gin's default logger for now, can be enhanced later
 		router.Use(gin.Logger())
@@ -66,7 +64,21 @@ func SetupAAA(router *gin.Engine, handlers RouteHandlers) {
 
 	// Setup auth routes with AuthHandler
 	if handlers.UserService != nil && handlers.Validator != nil && handlers.Responder != nil {
-		SetupAuthRoutes(publicAPI, protectedAPI, handlers.AuthMiddleware, handlers.UserService, handlers.Validator, handlers.Responder, handlers.Logger)
+		var loginChallenges interfaces.LoginChallengeService
+		var deviceSessions interfaces.DeviceSessionService
+		var tokenSessions interfaces.TokenSessionService
+		var loginRecorder interfaces.LoginRecorder
+		var tokenSigner helper.TokenSigner
+		if handlers.AuthService != nil {
+			loginChallenges = handlers.AuthService
+			deviceSessions = handlers.AuthService
+			tokenSessions = handlers.AuthService
+			loginRecorder = handlers.AuthService
+			tokenSigner = handlers.AuthService
+		}
+		// The user service supports device-bound MPINs once it is given a device repository
+		deviceMPins, _ := handlers.UserService.(interfaces.DeviceMPinService)
+		SetupAuthRoutes(publicAPI, protectedAPI, handlers.AuthMiddleware, handlers.UserService, loginChallenges, deviceSessions, deviceMPins, tokenSessions, loginRecorder, tokenSigner, handlers.Validator, handlers.Responder, handlers.Logger)
 	} else {
 		handlers.Logger.Warn("Auth dependencies not available - auth routes will not be registered")
 	}
@@ -106,6 +118,12 @@ func SetupAAA(router *gin.Engine, handlers RouteHandlers) {
 			handlers.Logger,
 			handlers.Responder,
 		)
+		if roleConstraints, ok := handlers.RoleService.(interfaces.RoleConstraintService); ok {
+			orgHandler.SetRoleConstraintService(roleConstraints)
+		}
+		if userAttributes, ok := handlers.OrganizationService.(interfaces.UserAttributeService); ok {
+			orgHandler.SetUserAttributeService(userAttributes)
+		}
 		// Setup organization routes
 		SetupOrganizationRoutes(protectedAPI, orgHandler, handlers.AuthMiddleware)
 
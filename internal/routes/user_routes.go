@@ -26,6 +26,7 @@ func SetupUserRoutes(
 	meGroup := protectedAPI.Group("/me")
 	{
 		meGroup.GET("/organizations", userHandler.GetMyOrganizations)
+		meGroup.GET("/groups", userHandler.GetMyGroups)
 	}
 
 	users := protectedAPI.Group("/users")
@@ -37,8 +38,28 @@ func SetupUserRoutes(
 		users.PUT("/:id", authMiddleware.RequirePermission("user", "update"), userHandler.UpdateUser)
 		users.DELETE("/:id", authMiddleware.RequirePermission("user", "delete"), userHandler.DeleteUser)
 
+		// CSV import for spreadsheets; the user service streams it when it supports imports
+		if importer, ok := userService.(interfaces.UserImportService); ok {
+			userHandler.SetImportService(importer)
+			users.POST("/import", authMiddleware.RequirePermission("user", "create"), userHandler.ImportUsers)
+		}
+
+		// Data export ("download my data"); the handler lets users export their own data and admins anyone's
+		if exporter, ok := userService.(interfaces.UserDataExportService); ok {
+			userHandler.SetDataExportService(exporter)
+			users.GET("/:id/export", userHandler.ExportUserData)
+		}
+
+		// Profile completeness of the caller, for nudging them to fill in their profile
+		if scorer, ok := userService.(interfaces.ProfileCompletenessService); ok {
+			userHandler.SetProfileCompletenessService(scorer)
+			users.GET("/me/profile-completeness", userHandler.GetMyProfileCompleteness)
+		}
+
 		// User search and validation
 		users.GET("/search", authMiddleware.RequirePermission("user", "read"), userHandler.SearchUsers)
+		users.GET("/batch", authMiddleware.RequirePermission("user", "read"), userHandler.BatchGetUsers)
+		users.POST("/batch", authMiddleware.RequirePermission("user", "read"), userHandler.BatchGetUsersByBody)
 		users.POST("/:id/validate", authMiddleware.RequirePermission("user", "update"), userHandler.ValidateUser)
 
 		// User organizations - returns organizations the user belongs to (for multi-tenant frontends)
@@ -50,6 +71,10 @@ func SetupUserRoutes(
 			middleware.SensitiveOperationRateLimit(),
 			authMiddleware.RequirePermission("user", "update"),
 			userHandler.AssignRoleToUser)
+		users.PUT("/:id/roles",
+			middleware.SensitiveOperationRateLimit(),
+			authMiddleware.RequirePermission("user", "update"),
+			userHandler.SetUserRoles)
 		users.DELETE("/:id/roles/:roleId",
 			middleware.SensitiveOperationRateLimit(),
 			authMiddleware.RequirePermission("user", "update"),
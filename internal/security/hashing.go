@@ -0,0 +1,69 @@
+package security
+
+import (
+	"golang.org/x/crypto/bcrypt"
+)
+
+// CredentialHasher hashes passwords and MPINs with bcrypt at configurable costs. A stored hash
+// records the cost it was made with, so after a successful verification the caller can ask
+// whether the hash is weaker than the configured cost and rehash the credential; raising the
+// cost then takes effect as users sign in, without forcing resets.
+type CredentialHasher struct {
+	passwordCost int
+	mpinCost     int
+}
+
+// NewCredentialHasher creates a hasher with the given password and MPIN costs. A cost outside
+// bcrypt's range falls back to bcrypt.DefaultCost.
+func NewCredentialHasher(passwordCost, mpinCost int) *CredentialHasher {
+	return &CredentialHasher{
+		passwordCost: validCost(passwordCost),
+		mpinCost:     validCost(mpinCost),
+	}
+}
+
+// DefaultCredentialHasher returns a hasher that uses bcrypt.DefaultCost for both credentials
+func DefaultCredentialHasher() *CredentialHasher {
+	return NewCredentialHasher(bcrypt.DefaultCost, bcrypt.DefaultCost)
+}
+
+// HashPassword hashes a password at the configured password cost
+func (h *CredentialHasher) HashPassword(password string) (string, error) {
+	return hashAtCost(password, h.passwordCost)
+}
+
+// HashMPin hashes an MPIN at the configured MPIN cost
+func (h *CredentialHasher) HashMPin(mPin string) (string, error) {
+	return hashAtCost(mPin, h.mpinCost)
+}
+
+// PasswordNeedsRehash reports whether a password hash was made with a lower cost than the configured one
+func (h *CredentialHasher) PasswordNeedsRehash(hash string) bool {
+	return belowCost(hash, h.passwordCost)
+}
+
+// MPinNeedsRehash reports whether an MPIN hash was made with a lower cost than the configured one
+func (h *CredentialHasher) MPinNeedsRehash(hash string) bool {
+	return belowCost(hash, h.mpinCost)
+}
+
+func hashAtCost(secret string, cost int) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(secret), cost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// belowCost is false for a hash bcrypt cannot read, which is never rehashed
+func belowCost(hash string, cost int) bool {
+	hashCost, err := bcrypt.Cost([]byte(hash))
+	return err == nil && hashCost < cost
+}
+
+func validCost(cost int) int {
+	if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+		return bcrypt.DefaultCost
+	}
+	return cost
+}
@@ -0,0 +1,34 @@
+package security
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestCredentialHasher(t *testing.T) {
+	hasher := NewCredentialHasher(bcrypt.MinCost+1, bcrypt.MinCost)
+
+	password, err := hasher.HashPassword("Secret123!")
+	require.NoError(t, err)
+	cost, err := bcrypt.Cost([]byte(password))
+	require.NoError(t, err)
+	assert.Equal(t, bcrypt.MinCost+1, cost)
+	assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(password), []byte("Secret123!")))
+	assert.False(t, hasher.PasswordNeedsRehash(password))
+
+	mPin, err := hasher.HashMPin("1234")
+	require.NoError(t, err)
+	assert.False(t, hasher.MPinNeedsRehash(mPin))
+	assert.True(t, hasher.PasswordNeedsRehash(mPin), "a hash below the password cost needs rehashing")
+
+	assert.False(t, hasher.PasswordNeedsRehash("not-a-bcrypt-hash"))
+}
+
+func TestNewCredentialHasher_InvalidCost(t *testing.T) {
+	hasher := NewCredentialHasher(0, bcrypt.MaxCost+1)
+	assert.Equal(t, bcrypt.DefaultCost, hasher.passwordCost)
+	assert.Equal(t, bcrypt.DefaultCost, hasher.mpinCost)
+}
@@ -0,0 +1,182 @@
+package security
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// RedactedValue replaces the value of every sensitive key
+const RedactedValue = "[REDACTED]"
+
+// DefaultSensitiveKeys are always redacted from logs and audit details. Keys are compared
+// case-insensitively with underscores and hyphens ignored, and a key also matches when it ends
+// with a sensitive key, so "new_password" and "refresh_token" are covered by "password" and "token".
+var DefaultSensitiveKeys = []string{
+	"password",
+	"mpin",
+	"aadhaar_number",
+	"aadhaar",
+	"otp",
+	"token",
+	"secret",
+	"authorization",
+	"api_key",
+	"share_code",
+}
+
+// Redactor masks the values of sensitive keys in structured data before it is logged or stored
+type Redactor struct {
+	keys []string
+}
+
+// NewRedactor creates a Redactor for the given keys
+func NewRedactor(keys []string) *Redactor {
+	normalized := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if key = normalizeRedactionKey(key); key != "" {
+			normalized = append(normalized, key)
+		}
+	}
+	return &Redactor{keys: normalized}
+}
+
+var (
+	defaultRedactorMu sync.RWMutex
+	defaultRedactor   = NewRedactor(DefaultSensitiveKeys)
+)
+
+// DefaultRedactor returns the process-wide redactor used by the logging middleware and audit service
+func DefaultRedactor() *Redactor {
+	defaultRedactorMu.RLock()
+	defer defaultRedactorMu.RUnlock()
+	return defaultRedactor
+}
+
+// SetAdditionalSensitiveKeys makes the default redactor mask extra keys on top of DefaultSensitiveKeys
+func SetAdditionalSensitiveKeys(keys []string) {
+	all := append(append([]string{}, DefaultSensitiveKeys...), keys...)
+	redactor := NewRedactor(all)
+
+	defaultRedactorMu.Lock()
+	defaultRedactor = redactor
+	defaultRedactorMu.Unlock()
+}
+
+// IsSensitive reports whether values stored under key must be redacted
+func (r *Redactor) IsSensitive(key string) bool {
+	key = normalizeRedactionKey(key)
+	for _, sensitive := range r.keys {
+		if strings.HasSuffix(key, sensitive) {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactMap returns a copy of data with sensitive values masked at any depth. Struct values are
+// converted to their JSON form first so their fields are inspected by JSON name.
+func (r *Redactor) RedactMap(data map[string]interface{}) map[string]interface{} {
+	if data == nil {
+		return nil
+	}
+	redacted, _ := r.redactValue(data).(map[string]interface{})
+	return redacted
+}
+
+// RedactJSON returns body with sensitive values masked. Bodies that are not JSON are replaced
+// entirely, since there is no telling what they contain.
+func (r *Redactor) RedactJSON(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		return RedactedValue
+	}
+	encoded, err := json.Marshal(r.redactValue(decoded))
+	if err != nil {
+		return RedactedValue
+	}
+	return string(encoded)
+}
+
+// RedactQuery returns a raw query string with the values of sensitive parameters masked
+func (r *Redactor) RedactQuery(rawQuery string) string {
+	if rawQuery == "" {
+		return ""
+	}
+	values, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return RedactedValue
+	}
+	changed := false
+	for key, vals := range values {
+		if r.IsSensitive(key) {
+			for i := range vals {
+				vals[i] = RedactedValue
+			}
+			changed = true
+		}
+	}
+	if !changed {
+		return rawQuery
+	}
+	return values.Encode()
+}
+
+// RedactPath masks sensitive query parameters of a request path such as "/a/b?otp=123"
+func (r *Redactor) RedactPath(path string) string {
+	base, rawQuery, found := strings.Cut(path, "?")
+	if !found {
+		return path
+	}
+	return base + "?" + r.RedactQuery(rawQuery)
+}
+
+func (r *Redactor) redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case nil, string, bool, float64, int, int64, json.Number:
+		return v
+	case map[string]interface{}:
+		redacted := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			// Flags such as has_mpin say nothing secret and stay readable
+			if _, isFlag := item.(bool); !isFlag && r.IsSensitive(key) {
+				redacted[key] = RedactedValue
+				continue
+			}
+			redacted[key] = r.redactValue(item)
+		}
+		return redacted
+	case []interface{}:
+		redacted := make([]interface{}, len(v))
+		for i, item := range v {
+			redacted[i] = r.redactValue(item)
+		}
+		return redacted
+	default:
+		// Structs, typed maps and slices: inspect their JSON form
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return v
+		}
+		var decoded interface{}
+		if err := json.Unmarshal(encoded, &decoded); err != nil {
+			return v
+		}
+		switch decoded.(type) {
+		case map[string]interface{}, []interface{}:
+			return r.redactValue(decoded)
+		default:
+			return v
+		}
+	}
+}
+
+func normalizeRedactionKey(key string) string {
+	key = strings.ToLower(strings.TrimSpace(key))
+	key = strings.ReplaceAll(key, "_", "")
+	return strings.ReplaceAll(key, "-", "")
+}
@@ -0,0 +1,86 @@
+package security
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedactor_IsSensitive(t *testing.T) {
+	redactor := NewRedactor(DefaultSensitiveKeys)
+
+	for _, key := range []string{"password", "new_password", "m_pin", "mPin", "aadhaar_number", "otp", "access_token", "refresh-token", "client_secret", "Authorization"} {
+		assert.True(t, redactor.IsSensitive(key), key)
+	}
+	for _, key := range []string{"username", "phone_number", "tokens", "token_type", "otp_expires_at", "status"} {
+		assert.False(t, redactor.IsSensitive(key), key)
+	}
+}
+
+func TestRedactor_RedactJSON_Nested(t *testing.T) {
+	redactor := NewRedactor(DefaultSensitiveKeys)
+
+	body := `{
+		"username": "ravi",
+		"password": "hunter22",
+		"profile": {"aadhaar_number": "123412341234", "name": "Ravi", "has_mpin": true},
+		"devices": [{"id": "D1", "refresh_token": "rt-1"}, {"id": "D2", "m_pin": 1234}],
+		"verification": {"otp": "482913", "attempts": 2}
+	}`
+
+	var got map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(redactor.RedactJSON([]byte(body))), &got))
+
+	assert.Equal(t, map[string]interface{}{
+		"username": "ravi",
+		"password": RedactedValue,
+		"profile":  map[string]interface{}{"aadhaar_number": RedactedValue, "name": "Ravi", "has_mpin": true},
+		"devices": []interface{}{
+			map[string]interface{}{"id": "D1", "refresh_token": RedactedValue},
+			map[string]interface{}{"id": "D2", "m_pin": RedactedValue},
+		},
+		"verification": map[string]interface{}{"otp": RedactedValue, "attempts": float64(2)},
+	}, got)
+
+	assert.Equal(t, RedactedValue, redactor.RedactJSON([]byte("password=hunter22")), "non-JSON bodies are dropped")
+}
+
+func TestRedactor_RedactMap(t *testing.T) {
+	redactor := NewRedactor(DefaultSensitiveKeys)
+
+	type credentials struct {
+		Username string `json:"username"`
+		MPin     string `json:"mpin"`
+	}
+	details := map[string]interface{}{
+		"old_values": map[string]interface{}{"password": "old-secret", "email": "a@example.com"},
+		"new_values": credentials{Username: "ravi", MPin: "1234"},
+		"count":      3,
+	}
+
+	redacted := redactor.RedactMap(details)
+
+	assert.Equal(t, map[string]interface{}{"password": RedactedValue, "email": "a@example.com"}, redacted["old_values"])
+	assert.Equal(t, map[string]interface{}{"username": "ravi", "mpin": RedactedValue}, redacted["new_values"])
+	assert.Equal(t, 3, redacted["count"])
+	assert.Equal(t, "old-secret", details["old_values"].(map[string]interface{})["password"], "the input is not modified")
+}
+
+func TestRedactor_RedactQueryAndPath(t *testing.T) {
+	redactor := NewRedactor(DefaultSensitiveKeys)
+
+	assert.Equal(t, "/api/v1/kyc?otp=%5BREDACTED%5D&ref=R1", redactor.RedactPath("/api/v1/kyc?otp=123456&ref=R1"))
+	assert.Equal(t, "limit=10&offset=0", redactor.RedactQuery("limit=10&offset=0"))
+	assert.Equal(t, "/api/v1/users", redactor.RedactPath("/api/v1/users"))
+}
+
+func TestSetAdditionalSensitiveKeys(t *testing.T) {
+	t.Cleanup(func() { SetAdditionalSensitiveKeys(nil) })
+
+	assert.False(t, DefaultRedactor().IsSensitive("pan_number"))
+	SetAdditionalSensitiveKeys([]string{"pan_number", " "})
+	assert.True(t, DefaultRedactor().IsSensitive("pan_number"))
+	assert.True(t, DefaultRedactor().IsSensitive("password"), "built-in keys stay redacted")
+}
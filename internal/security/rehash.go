@@ -0,0 +1,50 @@
+package security
+
+import (
+	"context"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"go.uber.org/zap"
+)
+
+// UserStore stores a user whose credential was rehashed
+type UserStore interface {
+	Update(ctx context.Context, user *models.User) error
+}
+
+// RehashPasswordIfNeeded rehashes a just-verified password when its stored hash was made with a
+// lower cost than the configured one, and stores the user. A failure is logged; the stored hash
+// stays valid.
+func (h *CredentialHasher) RehashPasswordIfNeeded(ctx context.Context, store UserStore, user *models.User, password string, logger *zap.Logger) {
+	if !h.PasswordNeedsRehash(user.Password) {
+		return
+	}
+	h.rehash(ctx, store, user, &user.Password, password, h.HashPassword, "password", logger)
+}
+
+// RehashMPinIfNeeded is RehashPasswordIfNeeded for a just-verified MPIN
+func (h *CredentialHasher) RehashMPinIfNeeded(ctx context.Context, store UserStore, user *models.User, mPin string, logger *zap.Logger) {
+	if !user.HasMPin() || !h.MPinNeedsRehash(*user.MPin) {
+		return
+	}
+	h.rehash(ctx, store, user, user.MPin, mPin, h.HashMPin, "MPIN", logger)
+}
+
+// rehash replaces the hash at field and stores the user, restoring the previous hash when the
+// store fails
+func (h *CredentialHasher) rehash(ctx context.Context, store UserStore, user *models.User, field *string, secret string,
+	hash func(string) (string, error), credential string, logger *zap.Logger) {
+	hashed, err := hash(secret)
+	if err != nil {
+		logger.Warn("Failed to rehash credential", zap.String("credential", credential), zap.String("user_id", user.ID), zap.Error(err))
+		return
+	}
+	previous := *field
+	*field = hashed
+	if err := store.Update(ctx, user); err != nil {
+		*field = previous
+		logger.Warn("Failed to store rehashed credential", zap.String("credential", credential), zap.String("user_id", user.ID), zap.Error(err))
+		return
+	}
+	logger.Info("Credential rehashed with the configured cost", zap.String("credential", credential), zap.String("user_id", user.ID))
+}
@@ -0,0 +1,59 @@
+package security
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type recordingUserStore struct {
+	updates int
+	err     error
+}
+
+func (s *recordingUserStore) Update(ctx context.Context, user *models.User) error {
+	s.updates++
+	return s.err
+}
+
+func TestCredentialHasher_RehashIfNeeded(t *testing.T) {
+	weak := NewCredentialHasher(bcrypt.MinCost, bcrypt.MinCost)
+	password, err := weak.HashPassword("Secret123!")
+	require.NoError(t, err)
+	mPin, err := weak.HashMPin("1234")
+	require.NoError(t, err)
+	user := models.NewUser("9876543210", "+91", password)
+	user.SetMPin(mPin)
+
+	hasher := NewCredentialHasher(bcrypt.MinCost+1, bcrypt.MinCost+1)
+	store := &recordingUserStore{}
+	hasher.RehashPasswordIfNeeded(context.Background(), store, user, "Secret123!", zap.NewNop())
+	hasher.RehashMPinIfNeeded(context.Background(), store, user, "1234", zap.NewNop())
+
+	assert.Equal(t, 2, store.updates)
+	assert.False(t, hasher.PasswordNeedsRehash(user.Password))
+	assert.False(t, hasher.MPinNeedsRehash(*user.MPin))
+	assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(user.Password), []byte("Secret123!")))
+	assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(*user.MPin), []byte("1234")))
+
+	hasher.RehashPasswordIfNeeded(context.Background(), store, user, "Secret123!", zap.NewNop())
+	assert.Equal(t, 2, store.updates, "a hash at the configured cost is not rehashed")
+}
+
+func TestCredentialHasher_RehashKeepsHashWhenStoreFails(t *testing.T) {
+	password, err := NewCredentialHasher(bcrypt.MinCost, bcrypt.MinCost).HashPassword("Secret123!")
+	require.NoError(t, err)
+	user := models.NewUser("9876543210", "+91", password)
+
+	store := &recordingUserStore{err: errors.New("database unavailable")}
+	NewCredentialHasher(bcrypt.MinCost+1, bcrypt.MinCost).RehashPasswordIfNeeded(context.Background(), store, user, "Secret123!", zap.NewNop())
+
+	assert.Equal(t, 1, store.updates)
+	assert.Equal(t, password, user.Password)
+}
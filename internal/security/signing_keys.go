@@ -0,0 +1,478 @@
+package security
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// SigningKeyAlgorithm is the JWS algorithm tokens are signed with by a SigningKeyRing
+const SigningKeyAlgorithm = "RS256"
+
+const (
+	signingKeyBits = 2048
+	// keyRingManifestFile records the keys of a key ring and which one is current
+	keyRingManifestFile = "keyring.json"
+	// keyRingLockFile is held while a key is rotated, so instances sharing the directory do not
+	// rotate at once
+	keyRingLockFile = "keyring.lock"
+	// staleKeyRingLock is the age after which a lock left by a crashed rotation is ignored
+	staleKeyRingLock = time.Minute
+	// unknownKeyReloadInterval limits how often an unknown key ID reloads the key ring from disk
+	unknownKeyReloadInterval = 10 * time.Second
+)
+
+// ErrUnknownSigningKey is returned for a key ID that is not in the key ring or no longer verifies tokens
+var ErrUnknownSigningKey = errors.New("unknown signing key")
+
+// SigningKey is an RSA key of a SigningKeyRing
+type SigningKey struct {
+	ID        string
+	CreatedAt time.Time
+	// RetiredAt is when a newer key replaced this one; zero for the current key
+	RetiredAt  time.Time
+	PrivateKey *rsa.PrivateKey
+}
+
+// SigningKeyInfo describes a signing key without its key material
+type SigningKeyInfo struct {
+	KeyID     string     `json:"kid"`
+	Algorithm string     `json:"alg"`
+	Current   bool       `json:"current"`
+	CreatedAt time.Time  `json:"created_at"`
+	RetiredAt *time.Time `json:"retired_at,omitempty"`
+	// VerifiesUntil is when tokens signed by a retired key stop being accepted
+	VerifiesUntil *time.Time `json:"verifies_until,omitempty"`
+}
+
+// JSONWebKey is the public part of a signing key in JWK form (RFC 7517)
+type JSONWebKey struct {
+	KeyType   string `json:"kty"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+	Modulus   string `json:"n"`
+	Exponent  string `json:"e"`
+}
+
+// JSONWebKeySet is the JWKS document listing the keys tokens can be verified with
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// keyRingManifest is the content of keyring.json
+type keyRingManifest struct {
+	Current string                 `json:"current"`
+	Keys    []keyRingManifestEntry `json:"keys"`
+}
+
+type keyRingManifestEntry struct {
+	ID        string     `json:"kid"`
+	CreatedAt time.Time  `json:"created_at"`
+	RetiredAt *time.Time `json:"retired_at,omitempty"`
+}
+
+// SigningKeyRing holds the RSA keys tokens are signed and verified with. The current key signs new
+// tokens; the keys it replaced keep verifying tokens for the overlap after they were retired, so a
+// rotation invalidates no token that is still in use.
+//
+// Keys live in a directory, normally a mounted secret: one PEM private key per key ID, named
+// <kid>.pem, and a keyring.json manifest recording when each key was created and retired and which
+// one is current. Instances sharing the directory pick up each other's rotations.
+type SigningKeyRing struct {
+	dir     string
+	overlap time.Duration
+	now     func() time.Time
+
+	mu         sync.RWMutex
+	current    *SigningKey
+	keys       map[string]*SigningKey
+	loadedAt   time.Time
+	hs256Until time.Time
+}
+
+// LoadSigningKeyRing loads the key ring stored in dir, creating its first key when the directory
+// holds none. Retired keys verify tokens for overlap, which must cover the longest token lifetime.
+func LoadSigningKeyRing(dir string, overlap time.Duration) (*SigningKeyRing, error) {
+	return loadSigningKeyRing(dir, overlap, time.Now)
+}
+
+func loadSigningKeyRing(dir string, overlap time.Duration, now func() time.Time) (*SigningKeyRing, error) {
+	if dir == "" {
+		return nil, errors.New("signing key directory is not set")
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create signing key directory: %w", err)
+	}
+	r := &SigningKeyRing{dir: dir, overlap: overlap, now: now, keys: make(map[string]*SigningKey)}
+
+	if _, err := os.Stat(filepath.Join(dir, keyRingManifestFile)); errors.Is(err, os.ErrNotExist) {
+		if _, err := r.Rotate(); err != nil {
+			return nil, fmt.Errorf("failed to create first signing key: %w", err)
+		}
+		return r, nil
+	}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Overlap returns how long retired keys keep verifying tokens
+func (r *SigningKeyRing) Overlap() time.Duration {
+	return r.overlap
+}
+
+// AcceptHS256Until lets tokens signed with the HS256 secret verify until the given time, so tokens
+// issued before the ring was configured survive the switch to RS256. The zero time, the default,
+// refuses them.
+func (r *SigningKeyRing) AcceptHS256Until(until time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.hs256Until = until
+}
+
+// AcceptsHS256 reports whether tokens signed with the HS256 secret are still accepted
+func (r *SigningKeyRing) AcceptsHS256() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.now().Before(r.hs256Until)
+}
+
+// Current returns the key new tokens are signed with
+func (r *SigningKeyRing) Current() *SigningKey {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.current
+}
+
+// PublicKey returns the public key of the key ID tokens name in their kid header. A key ID the
+// ring does not know reloads it from disk first, in case another instance rotated.
+func (r *SigningKeyRing) PublicKey(kid string) (*rsa.PublicKey, error) {
+	if key := r.verifyingKey(kid); key != nil {
+		return &key.PrivateKey.PublicKey, nil
+	}
+
+	r.mu.RLock()
+	reload := r.now().Sub(r.loadedAt) >= unknownKeyReloadInterval
+	r.mu.RUnlock()
+	if reload {
+		if err := r.Reload(); err != nil {
+			return nil, err
+		}
+		if key := r.verifyingKey(kid); key != nil {
+			return &key.PrivateKey.PublicKey, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %q", ErrUnknownSigningKey, kid)
+}
+
+// verifyingKey returns the key with the ID if it still verifies tokens
+func (r *SigningKeyRing) verifyingKey(kid string) *SigningKey {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	key, ok := r.keys[kid]
+	if !ok || !r.verifies(key, r.now()) {
+		return nil
+	}
+	return key
+}
+
+// verifies reports whether tokens signed by key are still accepted at now
+func (r *SigningKeyRing) verifies(key *SigningKey, now time.Time) bool {
+	return key.RetiredAt.IsZero() || now.Before(key.RetiredAt.Add(r.overlap))
+}
+
+// Keys describes the keys that verify tokens, current key first
+func (r *SigningKeyRing) Keys() []SigningKeyInfo {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	now := r.now()
+	infos := make([]SigningKeyInfo, 0, len(r.keys))
+	for _, key := range r.sortedKeys() {
+		if !r.verifies(key, now) {
+			continue
+		}
+		info := SigningKeyInfo{
+			KeyID:     key.ID,
+			Algorithm: SigningKeyAlgorithm,
+			Current:   key == r.current,
+			CreatedAt: key.CreatedAt,
+		}
+		if !key.RetiredAt.IsZero() {
+			retiredAt := key.RetiredAt
+			verifiesUntil := key.RetiredAt.Add(r.overlap)
+			info.RetiredAt = &retiredAt
+			info.VerifiesUntil = &verifiesUntil
+		}
+		infos = append(infos, info)
+	}
+	return infos
+}
+
+// JWKS returns the public parts of the keys that verify tokens
+func (r *SigningKeyRing) JWKS() JSONWebKeySet {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	now := r.now()
+	set := JSONWebKeySet{Keys: make([]JSONWebKey, 0, len(r.keys))}
+	for _, key := range r.sortedKeys() {
+		if r.verifies(key, now) {
+			set.Keys = append(set.Keys, publicJWK(key.ID, &key.PrivateKey.PublicKey))
+		}
+	}
+	return set
+}
+
+// sortedKeys returns the keys newest first. The caller holds r.mu.
+func (r *SigningKeyRing) sortedKeys() []*SigningKey {
+	keys := make([]*SigningKey, 0, len(r.keys))
+	for _, key := range r.keys {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].CreatedAt.After(keys[j].CreatedAt)
+	})
+	return keys
+}
+
+// Reload reads the key ring from its directory again
+func (r *SigningKeyRing) Reload() error {
+	manifest, err := r.readManifest()
+	if err != nil {
+		return err
+	}
+
+	keys := make(map[string]*SigningKey, len(manifest.Keys))
+	for _, entry := range manifest.Keys {
+		privateKey, err := readPrivateKey(r.keyPath(entry.ID))
+		if err != nil {
+			return fmt.Errorf("failed to read signing key %s: %w", entry.ID, err)
+		}
+		key := &SigningKey{ID: entry.ID, CreatedAt: entry.CreatedAt, PrivateKey: privateKey}
+		if entry.RetiredAt != nil {
+			key.RetiredAt = *entry.RetiredAt
+		}
+		keys[entry.ID] = key
+	}
+
+	current, ok := keys[manifest.Current]
+	if !ok {
+		return fmt.Errorf("current signing key %q is not in the key ring", manifest.Current)
+	}
+
+	r.mu.Lock()
+	r.keys = keys
+	r.current = current
+	r.loadedAt = r.now()
+	r.mu.Unlock()
+	return nil
+}
+
+// Rotate promotes a newly generated key to current. The key it replaces keeps verifying tokens for
+// the overlap, and keys retired longer than that are deleted.
+func (r *SigningKeyRing) Rotate() (SigningKeyInfo, error) {
+	info, _, err := r.rotate(0)
+	return info, err
+}
+
+// RotateIfOlderThan rotates the key ring when its current key was created at least maxAge ago, and
+// reports whether it did. The key ring is reloaded first, so a rotation made by another instance
+// sharing the directory counts.
+func (r *SigningKeyRing) RotateIfOlderThan(maxAge time.Duration) (SigningKeyInfo, bool, error) {
+	if err := r.Reload(); err != nil {
+		return SigningKeyInfo{}, false, err
+	}
+	if current := r.Current(); r.now().Sub(current.CreatedAt) < maxAge {
+		return SigningKeyInfo{}, false, nil
+	}
+	return r.rotate(maxAge)
+}
+
+// rotate generates and promotes a new key under the directory lock, unless the current key on
+// disk is younger than maxAge
+func (r *SigningKeyRing) rotate(maxAge time.Duration) (SigningKeyInfo, bool, error) {
+	unlock, err := r.lock()
+	if err != nil {
+		return SigningKeyInfo{}, false, err
+	}
+	defer unlock()
+
+	manifest, err := r.readManifest()
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return SigningKeyInfo{}, false, err
+	}
+	if manifest == nil {
+		manifest = &keyRingManifest{}
+	}
+
+	now := r.now().UTC()
+	if maxAge > 0 {
+		for _, entry := range manifest.Keys {
+			if entry.ID == manifest.Current && now.Sub(entry.CreatedAt) < maxAge {
+				// Another instance rotated since the caller looked
+				return SigningKeyInfo{}, false, r.Reload()
+			}
+		}
+	}
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, signingKeyBits)
+	if err != nil {
+		return SigningKeyInfo{}, false, fmt.Errorf("failed to generate signing key: %w", err)
+	}
+	kid := keyThumbprint(&privateKey.PublicKey)
+	if err := writePrivateKey(r.keyPath(kid), privateKey); err != nil {
+		return SigningKeyInfo{}, false, err
+	}
+
+	entries := make([]keyRingManifestEntry, 0, len(manifest.Keys)+1)
+	entries = append(entries, keyRingManifestEntry{ID: kid, CreatedAt: now})
+	var expired []string
+	for _, entry := range manifest.Keys {
+		if entry.ID == manifest.Current {
+			retiredAt := now
+			entry.RetiredAt = &retiredAt
+		}
+		if entry.RetiredAt != nil && !now.Before(entry.RetiredAt.Add(r.overlap)) {
+			expired = append(expired, entry.ID)
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	if err := r.writeManifest(&keyRingManifest{Current: kid, Keys: entries}); err != nil {
+		return SigningKeyInfo{}, false, err
+	}
+	for _, id := range expired {
+		_ = os.Remove(r.keyPath(id))
+	}
+
+	if err := r.Reload(); err != nil {
+		return SigningKeyInfo{}, false, err
+	}
+	return SigningKeyInfo{KeyID: kid, Algorithm: SigningKeyAlgorithm, Current: true, CreatedAt: now}, true, nil
+}
+
+// lock takes the directory lock held during rotations
+func (r *SigningKeyRing) lock() (func(), error) {
+	path := filepath.Join(r.dir, keyRingLockFile)
+	for attempt := 0; attempt < 2; attempt++ {
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+		if err == nil {
+			_ = file.Close()
+			return func() { _ = os.Remove(path) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("failed to lock signing key directory: %w", err)
+		}
+		if stat, statErr := os.Stat(path); statErr == nil && time.Since(stat.ModTime()) > staleKeyRingLock {
+			_ = os.Remove(path)
+			continue
+		}
+		break
+	}
+	return nil, errors.New("a signing key rotation is already in progress")
+}
+
+func (r *SigningKeyRing) keyPath(kid string) string {
+	return filepath.Join(r.dir, kid+".pem")
+}
+
+func (r *SigningKeyRing) readManifest() (*keyRingManifest, error) {
+	data, err := os.ReadFile(filepath.Join(r.dir, keyRingManifestFile))
+	if err != nil {
+		return nil, err
+	}
+	var manifest keyRingManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("invalid signing key manifest: %w", err)
+	}
+	return &manifest, nil
+}
+
+// writeManifest replaces the manifest atomically, so readers never see a partial one
+func (r *SigningKeyRing) writeManifest(manifest *keyRingManifest) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(r.dir, keyRingManifestFile)
+	if err := os.WriteFile(path+".tmp", data, 0o600); err != nil {
+		return fmt.Errorf("failed to write signing key manifest: %w", err)
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		return fmt.Errorf("failed to write signing key manifest: %w", err)
+	}
+	return nil
+}
+
+// readPrivateKey reads an RSA private key from a PKCS#8 or PKCS#1 PEM file
+func readPrivateKey(path string) (*rsa.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM data found")
+	}
+
+	if block.Type == "RSA PRIVATE KEY" {
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	privateKey, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("not an RSA private key")
+	}
+	return privateKey, nil
+}
+
+func writePrivateKey(path string, privateKey *rsa.PrivateKey) error {
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return err
+	}
+	data := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write signing key: %w", err)
+	}
+	return nil
+}
+
+func publicJWK(kid string, publicKey *rsa.PublicKey) JSONWebKey {
+	return JSONWebKey{
+		KeyType:   "RSA",
+		Use:       "sig",
+		Algorithm: SigningKeyAlgorithm,
+		KeyID:     kid,
+		Modulus:   base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes()),
+		Exponent:  base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes()),
+	}
+}
+
+// keyThumbprint returns the RFC 7638 JWK thumbprint of the public key, used as its key ID
+func keyThumbprint(publicKey *rsa.PublicKey) string {
+	jwk := publicJWK("", publicKey)
+	canonical := fmt.Sprintf(`{"e":%q,"kty":"RSA","n":%q}`, jwk.Exponent, jwk.Modulus)
+	sum := sha256.Sum256([]byte(canonical))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,143 @@
+package security
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testClock struct {
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	return c.now
+}
+
+func TestSigningKeyRing_CreatesFirstKey(t *testing.T) {
+	dir := t.TempDir()
+	clock := &testClock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+
+	ring, err := loadSigningKeyRing(dir, 24*time.Hour, clock.Now)
+	require.NoError(t, err)
+
+	current := ring.Current()
+	require.NotNil(t, current)
+	assert.Equal(t, clock.now, current.CreatedAt)
+	assert.FileExists(t, filepath.Join(dir, current.ID+".pem"))
+
+	stat, err := os.Stat(filepath.Join(dir, current.ID+".pem"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), stat.Mode().Perm())
+
+	// A second instance loads the same key
+	other, err := loadSigningKeyRing(dir, 24*time.Hour, clock.Now)
+	require.NoError(t, err)
+	assert.Equal(t, current.ID, other.Current().ID)
+}
+
+func TestSigningKeyRing_RotateKeepsPreviousKeyForOverlap(t *testing.T) {
+	clock := &testClock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	ring, err := loadSigningKeyRing(t.TempDir(), 24*time.Hour, clock.Now)
+	require.NoError(t, err)
+	previous := ring.Current()
+
+	clock.now = clock.now.Add(time.Hour)
+	rotated, err := ring.Rotate()
+	require.NoError(t, err)
+	assert.NotEqual(t, previous.ID, rotated.KeyID)
+	assert.Equal(t, rotated.KeyID, ring.Current().ID)
+
+	jwks := ring.JWKS()
+	require.Len(t, jwks.Keys, 2)
+	assert.Equal(t, rotated.KeyID, jwks.Keys[0].KeyID)
+	assert.Equal(t, previous.ID, jwks.Keys[1].KeyID)
+	for _, key := range jwks.Keys {
+		assert.Equal(t, "RSA", key.KeyType)
+		assert.Equal(t, SigningKeyAlgorithm, key.Algorithm)
+	}
+
+	keys := ring.Keys()
+	require.Len(t, keys, 2)
+	assert.True(t, keys[0].Current)
+	assert.False(t, keys[1].Current)
+	require.NotNil(t, keys[1].VerifiesUntil)
+	assert.Equal(t, clock.now.Add(24*time.Hour), *keys[1].VerifiesUntil)
+
+	publicKey, err := ring.PublicKey(previous.ID)
+	require.NoError(t, err)
+	assert.Equal(t, previous.PrivateKey.PublicKey.N, publicKey.N)
+
+	// Once the overlap ends the previous key no longer verifies, and the next rotation deletes it
+	clock.now = clock.now.Add(25 * time.Hour)
+	_, err = ring.PublicKey(previous.ID)
+	assert.ErrorIs(t, err, ErrUnknownSigningKey)
+	assert.Len(t, ring.JWKS().Keys, 1)
+
+	_, err = ring.Rotate()
+	require.NoError(t, err)
+	assert.NoFileExists(t, filepath.Join(ring.dir, previous.ID+".pem"))
+}
+
+func TestSigningKeyRing_RotateIfOlderThan(t *testing.T) {
+	dir := t.TempDir()
+	clock := &testClock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	ring, err := loadSigningKeyRing(dir, 24*time.Hour, clock.Now)
+	require.NoError(t, err)
+	first := ring.Current().ID
+
+	clock.now = clock.now.Add(29 * 24 * time.Hour)
+	_, rotated, err := ring.RotateIfOlderThan(30 * 24 * time.Hour)
+	require.NoError(t, err)
+	assert.False(t, rotated)
+	assert.Equal(t, first, ring.Current().ID)
+
+	clock.now = clock.now.Add(24 * time.Hour)
+	key, rotated, err := ring.RotateIfOlderThan(30 * 24 * time.Hour)
+	require.NoError(t, err)
+	assert.True(t, rotated)
+	assert.Equal(t, key.KeyID, ring.Current().ID)
+
+	// Another instance sharing the directory sees the rotation as done
+	other, err := loadSigningKeyRing(dir, 24*time.Hour, clock.Now)
+	require.NoError(t, err)
+	_, rotated, err = other.RotateIfOlderThan(30 * 24 * time.Hour)
+	require.NoError(t, err)
+	assert.False(t, rotated)
+	assert.Equal(t, key.KeyID, other.Current().ID)
+}
+
+func TestSigningKeyRing_PublicKeyReloadsUnknownKey(t *testing.T) {
+	dir := t.TempDir()
+	clock := &testClock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	ring, err := loadSigningKeyRing(dir, 24*time.Hour, clock.Now)
+	require.NoError(t, err)
+	other, err := loadSigningKeyRing(dir, 24*time.Hour, clock.Now)
+	require.NoError(t, err)
+
+	rotated, err := other.Rotate()
+	require.NoError(t, err)
+
+	// Unknown key IDs reload the key ring at most every unknownKeyReloadInterval
+	_, err = ring.PublicKey(rotated.KeyID)
+	assert.ErrorIs(t, err, ErrUnknownSigningKey)
+
+	clock.now = clock.now.Add(unknownKeyReloadInterval)
+	_, err = ring.PublicKey(rotated.KeyID)
+	require.NoError(t, err)
+	assert.Equal(t, rotated.KeyID, ring.Current().ID)
+}
+
+func TestSigningKeyRing_RejectsConcurrentRotation(t *testing.T) {
+	dir := t.TempDir()
+	ring, err := LoadSigningKeyRing(dir, time.Hour)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, keyRingLockFile), nil, 0o600))
+	_, err = ring.Rotate()
+	assert.ErrorContains(t, err, "already in progress")
+}
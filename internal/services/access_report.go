@@ -0,0 +1,247 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+)
+
+// How a user holds the role behind an access report entry
+const (
+	AccessPathDirect     = "direct"
+	AccessPathGroup      = "group"
+	AccessPathParentRole = "parent_role"
+)
+
+// adminRoleNames are the roles that pass every permission check, see roleHasWildcardPermission
+var adminRoleNames = []string{"super_admin", "admin", "system_admin", "CEO"}
+
+// adminPermissionNames are the role permissions that pass every permission check
+var adminPermissionNames = []string{"manage", "admin", "super_admin", "*:*"}
+
+// AccessGrant is one entry of a user's access report: a permission the user holds and the
+// path through which they hold it
+type AccessGrant struct {
+	// Permission is resource_type:action, or the permission name when it does not follow that form
+	Permission   string `json:"permission"`
+	ResourceType string `json:"resource_type,omitempty"`
+	ResourceID   string `json:"resource_id,omitempty"`
+	Action       string `json:"action,omitempty"`
+	// Rule is the kind of rule that grants the permission, as reported by ExplainPermission
+	Rule     string `json:"rule"`
+	RoleID   string `json:"role_id"`
+	RoleName string `json:"role_name"`
+	// Path is how the user holds the role: direct, group or parent_role
+	Path    string `json:"path"`
+	GroupID string `json:"group_id,omitempty"`
+	// ViaRole names the role whose parent granted the permission when Path is parent_role
+	ViaRole string `json:"via_role,omitempty"`
+	// Wildcard is set when the grant covers more than one resource or action
+	Wildcard bool `json:"wildcard"`
+}
+
+// accessRole is a role a user holds with the path through which they hold it
+type accessRole struct {
+	role    models.Role
+	path    string
+	groupID string
+	viaRole string
+}
+
+// WriteAccessReport enumerates the effective permissions of a user, passing each to emit with
+// its granting path as soon as the role it comes from is read. The roles are the ones permission
+// checks evaluate; orgID, when set, keeps the global roles and roles of that organization.
+func (s *PostgresAuthorizationService) WriteAccessReport(ctx context.Context, userID, orgID string, emit func(*AccessGrant) error) error {
+	var users int64
+	if err := s.db.WithContext(ctx).Model(&models.User{}).Where("id = ?", userID).Count(&users).Error; err != nil {
+		return fmt.Errorf("failed to look up user: %w", err)
+	}
+	if users == 0 {
+		return errors.NewNotFoundError("user not found")
+	}
+
+	roles, err := s.loadAccessRoles(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	for _, held := range roles {
+		if orgID != "" && len(rolesInOrganization([]models.Role{held.role}, orgID)) == 0 {
+			continue
+		}
+		grants, err := s.roleAccessGrants(ctx, held)
+		if err != nil {
+			return err
+		}
+		for _, grant := range grants {
+			if err := emit(grant); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// loadAccessRoles loads the active roles of a user, ordered by role name, with their parent roles
+func (s *PostgresAuthorizationService) loadAccessRoles(ctx context.Context, userID string) ([]accessRole, error) {
+	var assignments []struct {
+		RoleID        string
+		SourceGroupID *string
+	}
+	err := s.db.WithContext(ctx).
+		Table("user_roles").
+		Select("role_id, source_group_id").
+		Where("user_id = ? AND is_active = ?", userID, true).
+		Order("source_group_id NULLS FIRST").
+		Scan(&assignments).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load user roles: %w", err)
+	}
+	if len(assignments) == 0 {
+		return nil, nil
+	}
+
+	roleIDs := make([]string, 0, len(assignments))
+	for _, assignment := range assignments {
+		roleIDs = append(roleIDs, assignment.RoleID)
+	}
+	var activeRoles []models.Role
+	if err := s.db.WithContext(ctx).Where("id IN ? AND is_active = ?", roleIDs, true).Find(&activeRoles).Error; err != nil {
+		return nil, fmt.Errorf("failed to load user roles: %w", err)
+	}
+	rolesByID := make(map[string]models.Role, len(activeRoles))
+	for _, role := range activeRoles {
+		rolesByID[role.ID] = role
+	}
+
+	roles := make([]accessRole, 0, len(assignments))
+	for _, assignment := range assignments {
+		role, ok := rolesByID[assignment.RoleID]
+		if !ok {
+			continue
+		}
+		held := accessRole{role: role, path: AccessPathDirect}
+		if assignment.SourceGroupID != nil && *assignment.SourceGroupID != "" {
+			held.path = AccessPathGroup
+			held.groupID = *assignment.SourceGroupID
+		}
+		roles = append(roles, held)
+	}
+	sort.SliceStable(roles, func(i, j int) bool { return roles[i].role.Name < roles[j].role.Name })
+
+	// Parent roles are granted through each role that names them, as in includeParentRoles
+	parents := make(map[string]*models.Role)
+	for _, held := range roles {
+		if held.role.ParentID == nil || *held.role.ParentID == "" {
+			continue
+		}
+		parent, loaded := parents[*held.role.ParentID]
+		if !loaded {
+			var role models.Role
+			if err := s.db.WithContext(ctx).Where("id = ? AND is_active = ?", *held.role.ParentID, true).First(&role).Error; err == nil {
+				parent = &role
+			}
+			parents[*held.role.ParentID] = parent
+		}
+		if parent != nil {
+			roles = append(roles, accessRole{role: *parent, path: AccessPathParentRole, groupID: held.groupID, viaRole: held.role.Name})
+		}
+	}
+	return roles, nil
+}
+
+// roleAccessGrants lists what a held role grants: everything for admin roles, otherwise its
+// role permissions and resource permissions
+func (s *PostgresAuthorizationService) roleAccessGrants(ctx context.Context, held accessRole) ([]*AccessGrant, error) {
+	newGrant := func(rule string) *AccessGrant {
+		return &AccessGrant{
+			Rule:     rule,
+			RoleID:   held.role.ID,
+			RoleName: held.role.Name,
+			Path:     held.path,
+			GroupID:  held.groupID,
+			ViaRole:  held.viaRole,
+		}
+	}
+
+	if slices.Contains(adminRoleNames, held.role.Name) {
+		grant := newGrant(DecisionRuleAdminRole)
+		grant.Permission = "*:*"
+		grant.ResourceType = wildcard
+		grant.Action = wildcard
+		grant.Wildcard = true
+		return []*AccessGrant{grant}, nil
+	}
+
+	var permissionNames []string
+	err := s.db.WithContext(ctx).
+		Table("permissions").
+		Joins("JOIN role_permissions ON permissions.id = role_permissions.permission_id").
+		Where("role_permissions.role_id = ? AND role_permissions.is_active = ? AND permissions.is_active = ?", held.role.ID, true, true).
+		Order("permissions.name").
+		Distinct().
+		Pluck("permissions.name", &permissionNames).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load permissions of role %s: %w", held.role.Name, err)
+	}
+
+	var resourceGrants []ResourceGrant
+	err = s.db.WithContext(ctx).
+		Table("resource_permissions").
+		Select("role_id, resource_type, resource_id, action").
+		Where("role_id = ? AND is_active = ?", held.role.ID, true).
+		Order("resource_type, resource_id, action").
+		Scan(&resourceGrants).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load resource permissions of role %s: %w", held.role.Name, err)
+	}
+
+	grants := make([]*AccessGrant, 0, len(permissionNames)+len(resourceGrants))
+	for _, name := range permissionNames {
+		grant := newGrant(DecisionRuleRolePermission)
+		describePermission(grant, name)
+		grants = append(grants, grant)
+	}
+	for _, resourceGrant := range resourceGrants {
+		grant := newGrant(DecisionRuleResourcePermission)
+		grant.Permission = resourceGrant.ResourceType + ":" + resourceGrant.Action
+		grant.ResourceType = resourceGrant.ResourceType
+		grant.ResourceID = resourceGrant.ResourceID
+		grant.Action = resourceGrant.Action
+		grant.Wildcard = strings.HasSuffix(resourceGrant.ResourceID, wildcard) || resourceGrant.Action == wildcard
+		grants = append(grants, grant)
+	}
+	return grants, nil
+}
+
+// describePermission fills in a role permission grant from the permission name. Names of the
+// resource_type:action form cover every resource of the type; the admin permissions pass every
+// check.
+func describePermission(grant *AccessGrant, name string) {
+	grant.Permission = name
+	if slices.Contains(adminPermissionNames, name) {
+		grant.Rule = DecisionRuleAdminRole
+		grant.ResourceType = wildcard
+		grant.Action = wildcard
+		grant.Wildcard = true
+		return
+	}
+
+	resourceType, action, ok := strings.Cut(name, ":")
+	if !ok {
+		return
+	}
+	grant.ResourceType = resourceType
+	grant.Action = action
+	grant.Wildcard = action == wildcard
+}
+
+// WriteAccessReport enumerates a user's effective permissions with their granting path
+func (s *AuthorizationService) WriteAccessReport(ctx context.Context, userID, orgID string, emit func(*AccessGrant) error) error {
+	return s.postgresAuth.WriteAccessReport(ctx, userID, orgID, emit)
+}
@@ -0,0 +1,28 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDescribePermission(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected AccessGrant
+	}{
+		{"user:read", AccessGrant{Permission: "user:read", ResourceType: "user", Action: "read", Rule: DecisionRuleRolePermission}},
+		{"user:*", AccessGrant{Permission: "user:*", ResourceType: "user", Action: "*", Rule: DecisionRuleRolePermission, Wildcard: true}},
+		{"*:*", AccessGrant{Permission: "*:*", ResourceType: "*", Action: "*", Rule: DecisionRuleAdminRole, Wildcard: true}},
+		{"manage", AccessGrant{Permission: "manage", ResourceType: "*", Action: "*", Rule: DecisionRuleAdminRole, Wildcard: true}},
+		{"reports", AccessGrant{Permission: "reports", Rule: DecisionRuleRolePermission}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			grant := AccessGrant{Rule: DecisionRuleRolePermission}
+			describePermission(&grant, tt.name)
+			assert.Equal(t, tt.expected, grant)
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// accountDeletionBatchSize is the number of accounts hard deleted per pass
+const accountDeletionBatchSize = 100
+
+// accountDeletionPurger hard deletes the accounts whose deletion grace period is over
+type accountDeletionPurger interface {
+	PurgeDueAccountDeletions(ctx context.Context, limit int) (int, error)
+}
+
+// AccountDeletionWorker periodically hard deletes the accounts their users asked to delete once
+// the grace period of the request is over
+type AccountDeletionWorker struct {
+	purger   accountDeletionPurger
+	interval time.Duration
+	logger   *zap.Logger
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewAccountDeletionWorker creates a worker running purger every interval. Call Start to run it in
+// the background.
+func NewAccountDeletionWorker(purger accountDeletionPurger, interval time.Duration, logger *zap.Logger) *AccountDeletionWorker {
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	return &AccountDeletionWorker{
+		purger:   purger,
+		interval: interval,
+		logger:   logger.Named("account_deletion"),
+	}
+}
+
+// Start purges due account deletions in the background until Stop is called or ctx is done
+func (w *AccountDeletionWorker) Start(ctx context.Context) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.cancel != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	w.cancel = cancel
+	w.done = make(chan struct{})
+
+	go func(done chan struct{}) {
+		defer close(done)
+		w.run(ctx)
+	}(w.done)
+
+	w.logger.Info("Account deletion worker started", zap.Duration("interval", w.interval))
+}
+
+// Stop stops the worker and waits for the pass in flight to finish
+func (w *AccountDeletionWorker) Stop() {
+	w.mu.Lock()
+	cancel, done := w.cancel, w.done
+	w.cancel, w.done = nil, nil
+	w.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+	cancel()
+	<-done
+	w.logger.Info("Account deletion worker stopped")
+}
+
+func (w *AccountDeletionWorker) run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		w.PurgeOnce(ctx)
+	}
+}
+
+// PurgeOnce hard deletes the due accounts, a batch at a time, and returns how many were deleted
+func (w *AccountDeletionWorker) PurgeOnce(ctx context.Context) int {
+	total := 0
+	for ctx.Err() == nil {
+		purged, err := w.purger.PurgeDueAccountDeletions(ctx, accountDeletionBatchSize)
+		if err != nil {
+			w.logger.Warn("Failed to purge due account deletions", zap.Error(err))
+			break
+		}
+		total += purged
+		// Only a full batch of deletions may leave more due; failures wait for the next pass
+		if purged < accountDeletionBatchSize {
+			break
+		}
+	}
+	if total > 0 {
+		w.logger.Info("Deleted accounts whose grace period ended", zap.Int("accounts", total))
+	}
+	return total
+}
@@ -0,0 +1,177 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	addressRequests "github.com/Kisanlink/aaa-service/v2/internal/entities/requests/addresses"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// MaxBulkAddresses caps the number of addresses in a single bulk creation
+const MaxBulkAddresses = 500
+
+// bulkGeocodeConcurrency is how many addresses of a bulk creation are geocoded at a time
+const bulkGeocodeConcurrency = 8
+
+// bulkGeocodeBudget bounds the time a bulk creation spends geocoding, so that it finishes well within
+// the request timeout. Addresses not geocoded in time are stored without coordinates.
+var bulkGeocodeBudget = 15 * time.Second
+
+// CreateAddresses creates many addresses, typically during a bulk user import.
+// Each address is validated and created on its own, so one bad entry is reported in its result without
+// failing the others. Addresses are geocoded concurrently, within bulkGeocodeBudget, before they are
+// stored; a geocoding failure only leaves the coordinates empty, and they can be filled in later with
+// Geocode.
+func (s *AddressService) CreateAddresses(ctx context.Context, reqs []addressRequests.CreateAddressRequest) (*interfaces.BulkAddressCreateResult, error) {
+	s.logger.Info("Creating addresses in bulk", zap.Int("count", len(reqs)))
+
+	if len(reqs) == 0 {
+		return nil, errors.NewValidationError("at least one address is required")
+	}
+	if len(reqs) > MaxBulkAddresses {
+		return nil, errors.NewValidationError(fmt.Sprintf("at most %d addresses can be created at once", MaxBulkAddresses))
+	}
+
+	result := &interfaces.BulkAddressCreateResult{
+		Results: make([]interfaces.AddressCreateResult, len(reqs)),
+	}
+
+	// Invalid entries are left out as nil addresses
+	addresses := make([]*models.Address, len(reqs))
+	for i := range reqs {
+		result.Results[i] = interfaces.AddressCreateResult{Index: i, Status: interfaces.AddressCreateStatusFailed}
+		if err := reqs[i].Validate(); err != nil {
+			result.Results[i].Error = err.Error()
+			continue
+		}
+		addresses[i] = reqs[i].ToModel()
+		addresses[i].BuildFullAddress()
+	}
+
+	geocoded := s.geocodeAddresses(ctx, addresses)
+
+	for i, address := range addresses {
+		if address != nil {
+			s.createAddressInBulk(ctx, address, geocoded[i], &result.Results[i])
+		}
+		if result.Results[i].Status == interfaces.AddressCreateStatusCreated {
+			result.Created++
+		} else {
+			result.Failed++
+		}
+	}
+
+	s.logger.Info("Bulk address creation completed",
+		zap.Int("created", result.Created),
+		zap.Int("failed", result.Failed))
+
+	return result, nil
+}
+
+// createAddressInBulk stores a single address of a bulk request and records the outcome
+func (s *AddressService) createAddressInBulk(ctx context.Context, address *models.Address, geocoded bool, itemResult *interfaces.AddressCreateResult) {
+	if err := s.CreateAddress(ctx, address); err != nil {
+		itemResult.Error = err.Error()
+		return
+	}
+
+	itemResult.Status = interfaces.AddressCreateStatusCreated
+	itemResult.AddressID = address.ID
+	itemResult.Geocoded = geocoded
+}
+
+// geocodeAddresses sets the coordinates of the addresses of a bulk creation, a few at a time, and
+// reports which were geocoded. Nil entries are skipped, as are the addresses still waiting when
+// bulkGeocodeBudget runs out.
+func (s *AddressService) geocodeAddresses(ctx context.Context, addresses []*models.Address) []bool {
+	geocodeCtx, cancel := context.WithTimeout(ctx, bulkGeocodeBudget)
+	defer cancel()
+
+	geocoded := make([]bool, len(addresses))
+	semaphore := make(chan struct{}, bulkGeocodeConcurrency)
+	var wg sync.WaitGroup
+	for i, address := range addresses {
+		if address == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, address *models.Address) {
+			defer wg.Done()
+			semaphore <- struct{}{}        // Acquire
+			defer func() { <-semaphore }() // Release
+
+			if geocodeCtx.Err() != nil {
+				return
+			}
+			geocoded[i] = s.enrichWithCoordinates(geocodeCtx, address)
+		}(i, address)
+	}
+	wg.Wait()
+
+	if geocodeCtx.Err() == context.DeadlineExceeded {
+		s.logger.Warn("Bulk geocoding ran out of time, remaining addresses are stored without coordinates",
+			zap.Duration("budget", bulkGeocodeBudget))
+	}
+	return geocoded
+}
+
+// enrichWithCoordinates sets the address coordinates from the geocoder. Failures are logged and leave
+// the coordinates empty.
+func (s *AddressService) enrichWithCoordinates(ctx context.Context, address *models.Address) bool {
+	point, err := s.geocoder.Geocode(ctx, address)
+	if err != nil {
+		s.logger.Warn("Failed to geocode address, storing it without coordinates", zap.Error(err))
+		return false
+	}
+	if point == nil {
+		return false
+	}
+
+	address.Latitude = &point.Latitude
+	address.Longitude = &point.Longitude
+	return true
+}
+
+// Geocode looks up the coordinates of a stored address and saves them, for addresses created before
+// geocoding was enabled or whose geocoding failed
+func (s *AddressService) Geocode(ctx context.Context, addressID string) (*models.Address, error) {
+	s.logger.Info("Geocoding address", zap.String("addressID", addressID))
+
+	address := &models.Address{}
+	if _, err := s.addressRepo.GetByID(ctx, addressID, address); err != nil {
+		s.logger.Error("Failed to get address for geocoding", zap.String("addressID", addressID), zap.Error(err))
+		return nil, errors.NewNotFoundError("address not found")
+	}
+	if address.DeletedAt != nil {
+		return nil, errors.NewNotFoundError("address not found")
+	}
+
+	point, err := s.geocoder.Geocode(ctx, address)
+	if err != nil {
+		s.logger.Error("Failed to geocode address", zap.String("addressID", addressID), zap.Error(err))
+		return nil, fmt.Errorf("failed to geocode address: %w", err)
+	}
+	if point == nil {
+		return nil, errors.NewNotFoundError("no coordinates found for address")
+	}
+
+	address.Latitude = &point.Latitude
+	address.Longitude = &point.Longitude
+	if err := s.addressRepo.Update(ctx, address); err != nil {
+		s.logger.Error("Failed to save address coordinates", zap.String("addressID", addressID), zap.Error(err))
+		return nil, fmt.Errorf("failed to update address: %w", err)
+	}
+
+	if err := s.cacheService.Delete(fmt.Sprintf("address:%s", addressID)); err != nil {
+		s.logger.Error("Failed to delete address from cache", zap.Error(err))
+	}
+
+	s.logger.Info("Address geocoded successfully", zap.String("addressID", addressID))
+	return address, nil
+}
@@ -0,0 +1,180 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	addressRequests "github.com/Kisanlink/aaa-service/v2/internal/entities/requests/addresses"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/Kisanlink/aaa-service/v2/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type bulkAddressRepo struct {
+	interfaces.AddressRepository
+	addresses map[string]*models.Address
+	failFor   string
+}
+
+func (r *bulkAddressRepo) Create(ctx context.Context, address *models.Address) error {
+	if r.failFor != "" && address.District != nil && *address.District == r.failFor {
+		return fmt.Errorf("insert failed")
+	}
+	r.addresses[address.ID] = address
+	return nil
+}
+
+func (r *bulkAddressRepo) GetByID(ctx context.Context, id string, address *models.Address) (*models.Address, error) {
+	stored, ok := r.addresses[id]
+	if !ok {
+		return nil, fmt.Errorf("record not found")
+	}
+	*address = *stored
+	return address, nil
+}
+
+func (r *bulkAddressRepo) Update(ctx context.Context, address *models.Address) error {
+	r.addresses[address.ID] = address
+	return nil
+}
+
+// districtGeocoder resolves addresses by district and fails for the unreachable one
+type districtGeocoder struct {
+	points map[string]interfaces.GeoPoint
+}
+
+func (g *districtGeocoder) Geocode(ctx context.Context, address *models.Address) (*interfaces.GeoPoint, error) {
+	if address.District == nil {
+		return nil, nil
+	}
+	if *address.District == "Unreachable" {
+		return nil, fmt.Errorf("provider timeout")
+	}
+	point, ok := g.points[*address.District]
+	if !ok {
+		return nil, nil
+	}
+	return &point, nil
+}
+
+func newBulkAddressTestService(repo *bulkAddressRepo) *AddressService {
+	service := NewAddressService(repo, &memoryCache{values: map[string]interface{}{}}, utils.NewLoggerAdapter(zap.NewNop()), nil).(*AddressService)
+	service.SetGeocoder(&districtGeocoder{points: map[string]interfaces.GeoPoint{
+		"Guntur": {Latitude: 16.3067, Longitude: 80.4365},
+	}})
+	return service
+}
+
+func addressRequest(userID, district string) addressRequests.CreateAddressRequest {
+	return addressRequests.CreateAddressRequest{UserID: userID, District: &district}
+}
+
+func TestAddressService_CreateAddresses_PerItemResults(t *testing.T) {
+	repo := &bulkAddressRepo{addresses: map[string]*models.Address{}, failFor: "Broken"}
+	service := newBulkAddressTestService(repo)
+
+	result, err := service.CreateAddresses(context.Background(), []addressRequests.CreateAddressRequest{
+		addressRequest("USER1", "Guntur"),
+		addressRequest("", "Guntur"),
+		addressRequest("USER3", "Unreachable"),
+		addressRequest("USER4", "Broken"),
+		addressRequest("USER5", "Nowhere"),
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, result.Created)
+	assert.Equal(t, 2, result.Failed)
+	require.Len(t, result.Results, 5)
+
+	statuses := make([]string, len(result.Results))
+	for i, item := range result.Results {
+		assert.Equal(t, i, item.Index)
+		statuses[i] = item.Status
+	}
+	assert.Equal(t, []string{"created", "failed", "created", "failed", "created"}, statuses)
+	assert.Contains(t, result.Results[1].Error, "User ID")
+	assert.Contains(t, result.Results[3].Error, "insert failed")
+
+	geocoded := repo.addresses[result.Results[0].AddressID]
+	require.NotNil(t, geocoded.Latitude)
+	assert.Equal(t, 16.3067, *geocoded.Latitude)
+	assert.Equal(t, 80.4365, *geocoded.Longitude)
+	assert.True(t, result.Results[0].Geocoded)
+
+	// A geocoder failure still stores the address, without coordinates
+	unreachable := repo.addresses[result.Results[2].AddressID]
+	require.NotNil(t, unreachable)
+	assert.Nil(t, unreachable.Latitude)
+	assert.False(t, result.Results[2].Geocoded)
+}
+
+func TestAddressService_CreateAddresses_RejectsEmptyAndOversizedBatches(t *testing.T) {
+	service := newBulkAddressTestService(&bulkAddressRepo{addresses: map[string]*models.Address{}})
+
+	_, err := service.CreateAddresses(context.Background(), nil)
+	assert.True(t, errors.IsValidationError(err))
+
+	_, err = service.CreateAddresses(context.Background(), make([]addressRequests.CreateAddressRequest, MaxBulkAddresses+1))
+	assert.True(t, errors.IsValidationError(err))
+}
+
+func TestAddressService_Geocode_Backfill(t *testing.T) {
+	repo := &bulkAddressRepo{addresses: map[string]*models.Address{}}
+	service := newBulkAddressTestService(repo)
+	ctx := context.Background()
+
+	gunturReq, nowhereReq := addressRequest("USER1", "Guntur"), addressRequest("USER2", "Nowhere")
+	guntur, nowhere := gunturReq.ToModel(), nowhereReq.ToModel()
+	repo.addresses[guntur.ID] = guntur
+	repo.addresses[nowhere.ID] = nowhere
+
+	address, err := service.Geocode(ctx, guntur.ID)
+	require.NoError(t, err)
+	require.NotNil(t, address.Latitude)
+	assert.Equal(t, 16.3067, *repo.addresses[guntur.ID].Latitude)
+
+	_, err = service.Geocode(ctx, nowhere.ID)
+	assert.True(t, errors.IsNotFoundError(err))
+	assert.Nil(t, repo.addresses[nowhere.ID].Latitude)
+
+	_, err = service.Geocode(ctx, "ADDR404")
+	assert.True(t, errors.IsNotFoundError(err))
+}
+
+// blockingGeocoder answers only when the request gives up
+type blockingGeocoder struct{}
+
+func (blockingGeocoder) Geocode(ctx context.Context, address *models.Address) (*interfaces.GeoPoint, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestAddressService_CreateAddresses_BoundsGeocodingTime(t *testing.T) {
+	budget := bulkGeocodeBudget
+	bulkGeocodeBudget = 50 * time.Millisecond
+	defer func() { bulkGeocodeBudget = budget }()
+
+	repo := &bulkAddressRepo{addresses: map[string]*models.Address{}}
+	service := newBulkAddressTestService(repo)
+	service.SetGeocoder(blockingGeocoder{})
+
+	reqs := make([]addressRequests.CreateAddressRequest, 40)
+	for i := range reqs {
+		reqs[i] = addressRequest(fmt.Sprintf("USER%d", i), "Guntur")
+	}
+	start := time.Now()
+	result, err := service.CreateAddresses(context.Background(), reqs)
+	require.NoError(t, err)
+
+	assert.Less(t, time.Since(start), time.Second, "a slow geocoder does not hold up the batch")
+	assert.Equal(t, 40, result.Created, "addresses are stored without coordinates")
+	for _, item := range result.Results {
+		assert.False(t, item.Geocoded)
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
 	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/internal/services/geocoding"
 	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
 	"go.uber.org/zap"
 )
@@ -17,6 +18,7 @@ type AddressService struct {
 	cacheService interfaces.CacheService
 	logger       interfaces.Logger
 	validator    interfaces.Validator
+	geocoder     interfaces.Geocoder
 }
 
 // NewAddressService creates a new AddressService instance
@@ -31,9 +33,18 @@ func NewAddressService(
 		cacheService: cacheService,
 		logger:       logger,
 		validator:    validator,
+		geocoder:     geocoding.NewNoopGeocoder(),
 	}
 }
 
+// SetGeocoder replaces the default stub geocoder used to enrich addresses with coordinates
+func (s *AddressService) SetGeocoder(geocoder interfaces.Geocoder) {
+	if geocoder == nil {
+		geocoder = geocoding.NewNoopGeocoder()
+	}
+	s.geocoder = geocoder
+}
+
 // CreateAddress creates a new address
 func (s *AddressService) CreateAddress(ctx context.Context, address *models.Address) error {
 	s.logger.Info("Creating new address")
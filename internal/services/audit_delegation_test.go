@@ -0,0 +1,59 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestAuditService_RecordsActorAndOnBehalfOf(t *testing.T) {
+	auditRepo := &impersonationAuditRepo{}
+	auditService := NewAuditService(nil, auditRepo, nil, zap.NewNop())
+	ctx := context.Background()
+
+	auditService.LogRoleOperation(ctx, "ADMIN1", "USER1", "ROLE1", "assign", true, nil)
+	auditService.LogUserLifecycleEvent(ctx, "ADMIN1", "ADMIN1", "update", true, nil)
+	auditService.LogGroupMembershipChange(ctx, "SVC1", "add_group_member", "ORG1", "GRP1", "USER2", "added", true, nil)
+	auditService.LogUserAction(ctx, "USER3", "update_profile", "user", "USER3", nil)
+
+	require.Len(t, auditRepo.logs, 4)
+
+	roleLog := auditRepo.logs[0]
+	require.NotNil(t, roleLog.ActorID)
+	require.NotNil(t, roleLog.OnBehalfOfID)
+	assert.Equal(t, "ADMIN1", *roleLog.ActorID)
+	assert.Equal(t, "USER1", *roleLog.OnBehalfOfID)
+
+	selfLog := auditRepo.logs[1]
+	assert.Equal(t, "ADMIN1", *selfLog.ActorID)
+	assert.Nil(t, selfLog.OnBehalfOfID, "an action on oneself is not delegated")
+
+	serviceLog := auditRepo.logs[2]
+	assert.Nil(t, serviceLog.UserID)
+	require.NotNil(t, serviceLog.ActorID)
+	assert.Equal(t, "SVC1", *serviceLog.ActorID, "service principals are recorded as actors")
+	assert.Equal(t, "USER2", *serviceLog.OnBehalfOfID)
+
+	plainLog := auditRepo.logs[3]
+	require.NotNil(t, plainLog.ActorID)
+	assert.Equal(t, "USER3", *plainLog.ActorID, "without delegation the user is the actor")
+	assert.Nil(t, plainLog.OnBehalfOfID)
+}
+
+func TestAuditService_ImpersonatedActionsAreOnBehalfOfTheImpersonatedUser(t *testing.T) {
+	auditRepo := &impersonationAuditRepo{}
+	auditService := NewAuditService(nil, auditRepo, nil, zap.NewNop())
+	ctx := context.WithValue(context.Background(), "impersonator_id", "ADMIN1")
+
+	auditService.LogUserAction(ctx, "USER1", "update_profile", "user", "USER1", nil)
+	auditService.LogRoleOperation(ctx, "USER1", "USER2", "ROLE1", "assign", true, nil)
+
+	require.Len(t, auditRepo.logs, 2)
+	assert.Equal(t, "ADMIN1", *auditRepo.logs[0].ActorID)
+	assert.Equal(t, "USER1", *auditRepo.logs[0].OnBehalfOfID)
+	assert.Equal(t, "ADMIN1", *auditRepo.logs[1].ActorID)
+	assert.Equal(t, "USER2", *auditRepo.logs[1].OnBehalfOfID, "an explicit target is kept")
+}
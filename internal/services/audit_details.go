@@ -0,0 +1,178 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/kisanlink-db/pkg/db"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultMaxAuditDetailsBytes caps the serialized details of an audit log unless configured otherwise
+	DefaultMaxAuditDetailsBytes = 64 * 1024
+	// minAuditDetailsBytes leaves room for the truncation marker and the key fields
+	minAuditDetailsBytes = 4 * 1024
+	// maxListedDroppedDetails bounds the dropped keys named in the truncation marker
+	maxListedDroppedDetails = 20
+)
+
+// Detail keys of the marker added to truncated audit details
+const (
+	AuditDetailsTruncated    = "details_truncated"
+	AuditDetailsOriginalSize = "details_original_size"
+	AuditDetailsSHA256       = "details_sha256"
+	AuditDetailsRef          = "details_ref"
+	AuditDetailsDropped      = "details_dropped_keys"
+)
+
+// keyAuditDetails are kept ahead of other details when truncating, as long as they fit
+var keyAuditDetails = []string{
+	"request_id", "correlation_id", "session_id", "principal_id",
+	"impersonator_id", "impersonation_id", "operation", "method", "path", "endpoint",
+}
+
+// AuditDetailsStore keeps the full details of audit logs too large to store inline
+type AuditDetailsStore interface {
+	// StoreAuditDetails saves payload, the serialized details with the given SHA-256 digest, and
+	// returns a reference to it
+	StoreAuditDetails(ctx context.Context, digest string, payload []byte) (string, error)
+}
+
+// SetDetailsLimit caps the serialized size of audit log details at maxBytes; zero or less disables
+// the limit. Oversized details keep the entries that fit, smallest first after the key fields, and
+// record the size and SHA-256 digest of the full details. When store is set the full details are
+// saved there and referenced from the log.
+func (s *AuditService) SetDetailsLimit(maxBytes int, store AuditDetailsStore) {
+	if maxBytes > 0 && maxBytes < minAuditDetailsBytes {
+		maxBytes = minAuditDetailsBytes
+	}
+	s.maxDetailsBytes = maxBytes
+	s.detailsStore = store
+}
+
+// limitDetails truncates the details of auditLog to the configured size
+func (s *AuditService) limitDetails(ctx context.Context, auditLog *models.AuditLog) {
+	if s.maxDetailsBytes <= 0 || len(auditLog.Details) == 0 {
+		return
+	}
+	payload, err := json.Marshal(auditLog.Details)
+	if err != nil || len(payload) <= s.maxDetailsBytes {
+		return
+	}
+
+	sum := sha256.Sum256(payload)
+	digest := hex.EncodeToString(sum[:])
+	details := map[string]interface{}{
+		AuditDetailsTruncated:    true,
+		AuditDetailsOriginalSize: len(payload),
+		AuditDetailsSHA256:       digest,
+	}
+	if s.detailsStore != nil {
+		ref, err := s.detailsStore.StoreAuditDetails(ctx, digest, payload)
+		if err != nil {
+			s.logger.Warn("Failed to store full audit details; keeping truncated details only",
+				zap.String("action", auditLog.Action),
+				zap.String("details_sha256", digest),
+				zap.Error(err))
+		} else {
+			details[AuditDetailsRef] = ref
+		}
+	}
+
+	// Entries are added while they fit, key fields first and then the smallest
+	sizes := make(map[string]int, len(auditLog.Details))
+	var keys []string
+	for key, value := range auditLog.Details {
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			continue
+		}
+		sizes[key] = len(key) + len(encoded) + 4 // quotes, colon and comma
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		pi, pj := keyDetailRank(keys[i]), keyDetailRank(keys[j])
+		if pi != pj {
+			return pi < pj
+		}
+		if sizes[keys[i]] != sizes[keys[j]] {
+			return sizes[keys[i]] < sizes[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+
+	// Leave room for the marker, including the names of dropped keys
+	budget := s.maxDetailsBytes - encodedSize(details) - maxListedDroppedDetails*64
+	var dropped []string
+	for _, key := range keys {
+		if _, reserved := details[key]; reserved || sizes[key] > budget {
+			dropped = append(dropped, key)
+			continue
+		}
+		details[key] = auditLog.Details[key]
+		budget -= sizes[key]
+	}
+
+	sort.Strings(dropped)
+	listed := make([]string, 0, maxListedDroppedDetails)
+	for _, key := range dropped {
+		if len(listed) == maxListedDroppedDetails {
+			break
+		}
+		if len(key) > 60 {
+			key = key[:60]
+		}
+		listed = append(listed, key)
+	}
+	details[AuditDetailsDropped] = listed
+
+	s.logger.Warn("Audit details exceed the size limit and were truncated",
+		zap.String("action", auditLog.Action),
+		zap.Int("size", len(payload)),
+		zap.Int("limit", s.maxDetailsBytes),
+		zap.Int("dropped_keys", len(dropped)))
+	auditLog.Details = details
+}
+
+// keyDetailRank orders key fields by their position in keyAuditDetails, ahead of all other keys
+func keyDetailRank(key string) int {
+	for i, k := range keyAuditDetails {
+		if k == key {
+			return i
+		}
+	}
+	return len(keyAuditDetails)
+}
+
+func encodedSize(value interface{}) int {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return 0
+	}
+	return len(encoded)
+}
+
+// S3AuditDetailsStore keeps full audit details as JSON objects in S3, keyed by their digest
+type S3AuditDetailsStore struct {
+	s3Manager *db.S3Manager
+}
+
+// NewS3AuditDetailsStore creates an audit details store backed by s3Manager
+func NewS3AuditDetailsStore(s3Manager *db.S3Manager) *S3AuditDetailsStore {
+	return &S3AuditDetailsStore{s3Manager: s3Manager}
+}
+
+// StoreAuditDetails uploads payload to audit/details/{digest}.json and returns the object key
+func (s *S3AuditDetailsStore) StoreAuditDetails(ctx context.Context, digest string, payload []byte) (string, error) {
+	key := "audit/details/" + digest + ".json"
+	if err := s.s3Manager.UploadFile(ctx, key, bytes.NewReader(payload), "application/json", nil); err != nil {
+		return "", err
+	}
+	return key, nil
+}
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// recordingDetailsStore keeps the full details it is given
+type recordingDetailsStore struct {
+	payloads map[string][]byte
+	err      error
+}
+
+func (s *recordingDetailsStore) StoreAuditDetails(ctx context.Context, digest string, payload []byte) (string, error) {
+	if s.err != nil {
+		return "", s.err
+	}
+	if s.payloads == nil {
+		s.payloads = make(map[string][]byte)
+	}
+	s.payloads[digest] = payload
+	return "audit/details/" + digest + ".json", nil
+}
+
+// bulkUpdateValues builds old/new values of a bulk update large enough to exceed the limit
+func bulkUpdateValues(n int) map[string]interface{} {
+	values := make(map[string]interface{}, n)
+	for i := 0; i < n; i++ {
+		values[fmt.Sprintf("field_%03d", i)] = strings.Repeat("v", 200)
+	}
+	return values
+}
+
+func TestAuditService_TruncatesOversizedDetails(t *testing.T) {
+	repo := &impersonationAuditRepo{}
+	service := NewAuditService(nil, repo, nil, zap.NewNop())
+	store := &recordingDetailsStore{}
+	service.SetDetailsLimit(8*1024, store)
+
+	ctx := context.WithValue(context.Background(), "request_id", "REQ1")
+	oldValues, newValues := bulkUpdateValues(200), bulkUpdateValues(200)
+	service.LogDataAccess(ctx, "USER1", "bulk_update", "user", "USER2", oldValues, newValues)
+
+	require.Len(t, repo.logs, 1)
+	details := repo.logs[0].Details
+	encoded, err := json.Marshal(details)
+	require.NoError(t, err)
+	assert.LessOrEqual(t, len(encoded), 8*1024)
+
+	assert.Equal(t, true, details[AuditDetailsTruncated])
+	assert.Equal(t, "REQ1", details["request_id"], "key fields are kept")
+	assert.NotContains(t, details, "old_values")
+	assert.NotContains(t, details, "new_values")
+	assert.ElementsMatch(t, []string{"new_values", "old_values"}, details[AuditDetailsDropped])
+
+	// The digest identifies the full details, which the store keeps
+	digest, ok := details[AuditDetailsSHA256].(string)
+	require.True(t, ok)
+	payload, ok := store.payloads[digest]
+	require.True(t, ok)
+	sum := sha256.Sum256(payload)
+	assert.Equal(t, digest, hex.EncodeToString(sum[:]))
+	assert.Equal(t, len(payload), details[AuditDetailsOriginalSize])
+	assert.Equal(t, "audit/details/"+digest+".json", details[AuditDetailsRef])
+
+	var full map[string]interface{}
+	require.NoError(t, json.Unmarshal(payload, &full))
+	assert.Len(t, full["old_values"], 200)
+	assert.Equal(t, "REQ1", full["request_id"])
+}
+
+func TestAuditService_TruncationKeepsSmallDetailsWithoutStore(t *testing.T) {
+	repo := &impersonationAuditRepo{}
+	service := NewAuditService(nil, repo, nil, zap.NewNop())
+	service.SetDetailsLimit(4*1024, nil)
+
+	service.LogUserAction(context.Background(), "USER1", "import", "user", "USER1", map[string]interface{}{
+		"rows":    12,
+		"source":  "csv",
+		"payload": strings.Repeat("p", 10*1024),
+	})
+
+	require.Len(t, repo.logs, 1)
+	details := repo.logs[0].Details
+	assert.Equal(t, true, details[AuditDetailsTruncated])
+	assert.Equal(t, 12, details["rows"])
+	assert.Equal(t, "csv", details["source"])
+	assert.NotContains(t, details, "payload")
+	assert.NotContains(t, details, AuditDetailsRef)
+	assert.Equal(t, []string{"payload"}, details[AuditDetailsDropped])
+}
+
+func TestAuditService_StoreFailureStillTruncates(t *testing.T) {
+	repo := &impersonationAuditRepo{}
+	service := NewAuditService(nil, repo, nil, zap.NewNop())
+	service.SetDetailsLimit(4*1024, &recordingDetailsStore{err: assert.AnError})
+
+	service.LogUserAction(context.Background(), "USER1", "import", "user", "USER1", map[string]interface{}{
+		"payload": strings.Repeat("p", 10*1024),
+	})
+
+	require.Len(t, repo.logs, 1)
+	details := repo.logs[0].Details
+	assert.Equal(t, true, details[AuditDetailsTruncated])
+	assert.NotContains(t, details, AuditDetailsRef)
+	assert.Contains(t, details, AuditDetailsSHA256)
+}
+
+func TestAuditService_DetailsWithinLimitAreUnchanged(t *testing.T) {
+	repo := &impersonationAuditRepo{}
+	service := NewAuditService(nil, repo, nil, zap.NewNop())
+
+	service.LogUserAction(context.Background(), "USER1", "update", "user", "USER1", map[string]interface{}{"field": "name"})
+
+	require.Len(t, repo.logs, 1)
+	assert.Equal(t, "name", repo.logs[0].Details["field"])
+	assert.NotContains(t, repo.logs[0].Details, AuditDetailsTruncated)
+}
+
+func TestAuditService_SetDetailsLimit(t *testing.T) {
+	service := NewAuditService(nil, &impersonationAuditRepo{}, nil, zap.NewNop())
+	assert.Equal(t, DefaultMaxAuditDetailsBytes, service.maxDetailsBytes)
+
+	service.SetDetailsLimit(100, nil)
+	assert.Equal(t, minAuditDetailsBytes, service.maxDetailsBytes, "tiny limits leave room for the marker")
+
+	service.SetDetailsLimit(0, nil)
+	repo := &impersonationAuditRepo{}
+	service.auditRepo = repo
+	service.LogUserAction(context.Background(), "USER1", "import", "user", "USER1", map[string]interface{}{
+		"payload": strings.Repeat("p", 100*1024),
+	})
+	require.Len(t, repo.logs, 1)
+	assert.Contains(t, repo.logs[0].Details, "payload", "a zero limit disables truncation")
+}
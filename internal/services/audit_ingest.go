@@ -0,0 +1,215 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/security"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/Kisanlink/aaa-service/v2/utils"
+	"go.uber.org/zap"
+)
+
+// Size caps of an audit ingestion batch
+const (
+	MaxAuditIngestEntries   = 500
+	MaxAuditIngestBodyBytes = 2 << 20 // 2 MiB
+)
+
+// auditIngestMaxFutureSkew is how far in the future an ingested event may be timestamped, for
+// clock skew between services
+const auditIngestMaxFutureSkew = 5 * time.Minute
+
+// maxAuditLabelLength is the column size of audit log actions and resource types
+const maxAuditLabelLength = 100
+
+// AuditIngestEntry is an audit event another service records in the AAA audit store
+type AuditIngestEntry struct {
+	UserID       string                 `json:"user_id,omitempty"`
+	ActorID      string                 `json:"actor_id,omitempty"`
+	OnBehalfOfID string                 `json:"on_behalf_of_id,omitempty"`
+	Action       string                 `json:"action"`
+	ResourceType string                 `json:"resource_type"`
+	ResourceID   string                 `json:"resource_id,omitempty"`
+	Status       string                 `json:"status,omitempty"` // success (default), failure or warning
+	Message      string                 `json:"message,omitempty"`
+	IPAddress    string                 `json:"ip_address,omitempty"`
+	UserAgent    string                 `json:"user_agent,omitempty"`
+	Details      map[string]interface{} `json:"details,omitempty"`
+	Timestamp    *time.Time             `json:"timestamp,omitempty"` // when the event happened; defaults to now
+}
+
+// validate checks the entry and fills in its defaults
+func (e *AuditIngestEntry) validate(now time.Time) error {
+	switch {
+	case e.Action == "":
+		return fmt.Errorf("action is required")
+	case len(e.Action) > maxAuditLabelLength:
+		return fmt.Errorf("action must be at most %d characters", maxAuditLabelLength)
+	case e.ResourceType == "":
+		return fmt.Errorf("resource_type is required")
+	case len(e.ResourceType) > maxAuditLabelLength:
+		return fmt.Errorf("resource_type must be at most %d characters", maxAuditLabelLength)
+	}
+
+	switch e.Status {
+	case "":
+		e.Status = models.AuditStatusSuccess
+	case models.AuditStatusSuccess, models.AuditStatusFailure, models.AuditStatusWarning:
+	default:
+		return fmt.Errorf("status must be success, failure or warning")
+	}
+
+	if e.IPAddress != "" {
+		if _, err := netip.ParseAddr(e.IPAddress); err != nil {
+			return fmt.Errorf("ip_address is not a valid IP address")
+		}
+	}
+	if e.Timestamp != nil && e.Timestamp.After(now.Add(auditIngestMaxFutureSkew)) {
+		return fmt.Errorf("timestamp is in the future")
+	}
+	return nil
+}
+
+// auditUserLookup finds which IDs belong to users
+type auditUserLookup interface {
+	ExistingIDs(ctx context.Context, ids []string, includeDeleted bool) ([]string, error)
+}
+
+// SetUserLookup lets ingested audit events keep their user ID when it belongs to a user. Without
+// it, the user IDs of ingested events are only kept in their details.
+func (s *AuditService) SetUserLookup(users auditUserLookup) {
+	s.userLookup = users
+}
+
+// IngestAuditLogs records a batch of audit events sent by the service sourceID. The whole batch is
+// validated first and then written in one transaction, so it is stored entirely or not at all.
+// Events are stamped with their source service. User IDs that cannot be stored as user_id, because
+// they are anonymous, service principals or users of no AAA account, are kept in the details
+// instead, as the audit_logs.user_id foreign key would reject them.
+func (s *AuditService) IngestAuditLogs(ctx context.Context, sourceID string, entries []AuditIngestEntry) (int, error) {
+	if len(entries) == 0 {
+		return 0, errors.NewValidationError("entries must not be empty")
+	}
+	if len(entries) > MaxAuditIngestEntries {
+		return 0, errors.NewValidationError(fmt.Sprintf("at most %d entries can be ingested at once", MaxAuditIngestEntries))
+	}
+
+	now := time.Now()
+	var problems []string
+	for i := range entries {
+		if err := entries[i].validate(now); err != nil {
+			problems = append(problems, fmt.Sprintf("entries[%d]: %s", i, err.Error()))
+		}
+	}
+	if len(problems) > 0 {
+		return 0, errors.NewValidationError("invalid audit entries", problems...)
+	}
+
+	knownUsers, err := s.knownUsers(ctx, entries)
+	if err != nil {
+		s.logger.Error("Failed to look up users of ingested audit logs", zap.String("source", sourceID), zap.Error(err))
+		return 0, errors.NewInternalError(err)
+	}
+
+	requestID, _ := ctx.Value("request_id").(string)
+	auditLogs := make([]*models.AuditLog, 0, len(entries))
+	for _, entry := range entries {
+		auditLog := s.ingestedAuditLog(ctx, sourceID, entry, knownUsers)
+		if requestID != "" {
+			auditLog.AddDetail("ingest_request_id", requestID)
+		}
+		auditLogs = append(auditLogs, auditLog)
+	}
+
+	if err := s.auditRepo.CreateBatch(ctx, auditLogs); err != nil {
+		s.logger.Error("Failed to save ingested audit logs",
+			zap.String("source", sourceID),
+			zap.Int("count", len(auditLogs)),
+			zap.Error(err))
+		return 0, errors.NewInternalError(err)
+	}
+
+	s.logger.Info("Ingested audit logs", zap.String("source", sourceID), zap.Int("count", len(auditLogs)))
+	return len(auditLogs), nil
+}
+
+// knownUsers returns the user IDs of entries that belong to users
+func (s *AuditService) knownUsers(ctx context.Context, entries []AuditIngestEntry) (map[string]bool, error) {
+	known := make(map[string]bool)
+	if s.userLookup == nil {
+		return known, nil
+	}
+
+	seen := make(map[string]bool)
+	var ids []string
+	for _, entry := range entries {
+		if !isAnonymousUser(entry.UserID) && !seen[entry.UserID] {
+			seen[entry.UserID] = true
+			ids = append(ids, entry.UserID)
+		}
+	}
+	if len(ids) == 0 {
+		return known, nil
+	}
+
+	existing, err := s.userLookup.ExistingIDs(ctx, ids, true)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range existing {
+		known[id] = true
+	}
+	return known, nil
+}
+
+// ingestedAuditLog builds the audit log of a validated entry
+func (s *AuditService) ingestedAuditLog(ctx context.Context, sourceID string, entry AuditIngestEntry, knownUsers map[string]bool) *models.AuditLog {
+	auditLog := models.NewAuditLog(entry.Action, entry.ResourceType, entry.Status, entry.Message)
+	if entry.Timestamp != nil {
+		auditLog.Timestamp = *entry.Timestamp
+	}
+	for key, value := range entry.Details {
+		auditLog.AddDetail(key, value)
+	}
+	auditLog.AddDetail("source_service", sourceID)
+
+	switch {
+	case knownUsers[entry.UserID]:
+		userID := entry.UserID
+		auditLog.UserID = &userID
+	case isAnonymousUser(entry.UserID):
+		// Service principals are recorded like those calling the AAA service itself
+		if entry.UserID != "" && auditActorID(entry.UserID) != "" {
+			auditLog.AddDetail("principal_id", entry.UserID)
+		}
+	default:
+		auditLog.AddDetail("external_user_id", entry.UserID)
+	}
+
+	if actorID := auditActorID(entry.ActorID); actorID != "" {
+		auditLog.ActorID = &actorID
+	} else if auditLog.UserID != nil {
+		actorID := *auditLog.UserID
+		auditLog.ActorID = &actorID
+	}
+	if entry.OnBehalfOfID != "" {
+		onBehalfOfID := entry.OnBehalfOfID
+		auditLog.OnBehalfOfID = &onBehalfOfID
+	}
+	if entry.ResourceID != "" {
+		resourceID := entry.ResourceID
+		auditLog.ResourceID = &resourceID
+	}
+	auditLog.IPAddress = entry.IPAddress
+
+	// The same limits as events recorded by the AAA service itself
+	auditLog.Details = security.DefaultRedactor().RedactMap(auditLog.Details)
+	s.limitDetails(ctx, auditLog)
+	auditLog.Message = utils.TruncateText(entry.Message, maxAuditMessageLength)
+	auditLog.UserAgent = utils.TruncateText(entry.UserAgent, maxAuditUserAgentLength)
+	return auditLog
+}
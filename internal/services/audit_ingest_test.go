@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// batchAuditRepo records the batches written with CreateBatch
+type batchAuditRepo struct {
+	interfaces.AuditRepository
+	batches [][]*models.AuditLog
+	err     error
+}
+
+func (r *batchAuditRepo) CreateBatch(ctx context.Context, auditLogs []*models.AuditLog) error {
+	if r.err != nil {
+		return r.err
+	}
+	r.batches = append(r.batches, auditLogs)
+	return nil
+}
+
+type ingestUserLookup struct {
+	users map[string]bool
+}
+
+func (l *ingestUserLookup) ExistingIDs(ctx context.Context, ids []string, includeDeleted bool) ([]string, error) {
+	var existing []string
+	for _, id := range ids {
+		if l.users[id] {
+			existing = append(existing, id)
+		}
+	}
+	return existing, nil
+}
+
+func TestIngestAuditLogs_StampsSourceAndHandlesUserIDs(t *testing.T) {
+	repo := &batchAuditRepo{}
+	service := NewAuditService(nil, repo, nil, zap.NewNop())
+	service.SetUserLookup(&ingestUserLookup{users: map[string]bool{"USER1": true}})
+	happenedAt := time.Now().Add(-time.Hour).UTC()
+
+	count, err := service.IngestAuditLogs(context.Background(), "SVC1", []AuditIngestEntry{
+		{UserID: "USER1", Action: "create_order", ResourceType: "orders/order", ResourceID: "ORD1", Timestamp: &happenedAt,
+			Details: map[string]interface{}{"amount": 120, "password": "hunter22"}},
+		{UserID: "FARMER-42", Action: "view_order", ResourceType: "orders/order", Status: models.AuditStatusFailure},
+		{UserID: "SVC9", Action: "sync", ResourceType: "orders/order"},
+		{UserID: "anonymous", Action: "view_catalog", ResourceType: "catalog/item", IPAddress: "10.1.2.3"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 4, count)
+	require.Len(t, repo.batches, 1)
+	logs := repo.batches[0]
+	require.Len(t, logs, 4)
+
+	for _, auditLog := range logs {
+		assert.Equal(t, "SVC1", auditLog.Details["source_service"])
+	}
+
+	require.NotNil(t, logs[0].UserID)
+	assert.Equal(t, "USER1", *logs[0].UserID)
+	assert.Equal(t, "USER1", *logs[0].ActorID)
+	assert.Equal(t, happenedAt, logs[0].Timestamp)
+	assert.Equal(t, models.AuditStatusSuccess, logs[0].Status)
+	assert.NotEqual(t, "hunter22", logs[0].Details["password"])
+
+	// Users without an AAA account would violate the user_id foreign key
+	assert.Nil(t, logs[1].UserID)
+	assert.Nil(t, logs[1].ActorID)
+	assert.Equal(t, "FARMER-42", logs[1].Details["external_user_id"])
+	assert.Equal(t, models.AuditStatusFailure, logs[1].Status)
+
+	assert.Nil(t, logs[2].UserID)
+	assert.Equal(t, "SVC9", logs[2].Details["principal_id"])
+
+	assert.Nil(t, logs[3].UserID)
+	assert.NotContains(t, logs[3].Details, "external_user_id")
+	assert.Equal(t, "10.1.2.3", logs[3].IPAddress)
+}
+
+func TestIngestAuditLogs_WithoutUserLookupKeepsUserIDsInDetails(t *testing.T) {
+	repo := &batchAuditRepo{}
+	service := NewAuditService(nil, repo, nil, zap.NewNop())
+
+	_, err := service.IngestAuditLogs(context.Background(), "SVC1", []AuditIngestEntry{
+		{UserID: "USER1", Action: "create_order", ResourceType: "orders/order"},
+	})
+	require.NoError(t, err)
+	assert.Nil(t, repo.batches[0][0].UserID)
+	assert.Equal(t, "USER1", repo.batches[0][0].Details["external_user_id"])
+}
+
+func TestIngestAuditLogs_RejectsInvalidBatch(t *testing.T) {
+	future := time.Now().Add(time.Hour)
+	tooMany := make([]AuditIngestEntry, MaxAuditIngestEntries+1)
+	for i := range tooMany {
+		tooMany[i] = AuditIngestEntry{Action: "a", ResourceType: "r"}
+	}
+
+	tests := []struct {
+		name    string
+		entries []AuditIngestEntry
+		want    string
+	}{
+		{name: "empty", entries: nil, want: "must not be empty"},
+		{name: "too many", entries: tooMany, want: fmt.Sprintf("at most %d", MaxAuditIngestEntries)},
+		{name: "missing action", entries: []AuditIngestEntry{{ResourceType: "r"}}, want: "entries[0]: action is required"},
+		{name: "unknown status", entries: []AuditIngestEntry{{Action: "a", ResourceType: "r"}, {Action: "a", ResourceType: "r", Status: "done"}}, want: "entries[1]: status"},
+		{name: "invalid ip", entries: []AuditIngestEntry{{Action: "a", ResourceType: "r", IPAddress: "nowhere"}}, want: "ip_address"},
+		{name: "future timestamp", entries: []AuditIngestEntry{{Action: "a", ResourceType: "r", Timestamp: &future}}, want: "in the future"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &batchAuditRepo{}
+			service := NewAuditService(nil, repo, nil, zap.NewNop())
+
+			_, err := service.IngestAuditLogs(context.Background(), "SVC1", tt.entries)
+			require.Error(t, err)
+			require.True(t, errors.IsValidationError(err))
+			message := err.Error()
+			if validationErr, ok := err.(*errors.ValidationError); ok {
+				message = fmt.Sprint(message, validationErr.Details())
+			}
+			assert.Contains(t, message, tt.want)
+			assert.Empty(t, repo.batches, "nothing is written when any entry is invalid")
+		})
+	}
+}
+
+func TestIngestAuditLogs_WriteFailure(t *testing.T) {
+	service := NewAuditService(nil, &batchAuditRepo{err: fmt.Errorf("connection reset")}, nil, zap.NewNop())
+
+	_, err := service.IngestAuditLogs(context.Background(), "SVC1", []AuditIngestEntry{{Action: "a", ResourceType: "r"}})
+	require.Error(t, err)
+	assert.False(t, errors.IsValidationError(err))
+}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// loginHistoryAuditRepo stubs only the query used by GetLoginHistory
+type loginHistoryAuditRepo struct {
+	interfaces.AuditRepository
+	logs       []*models.AuditLog
+	gotUserID  string
+	gotActions []string
+	gotLimit   int
+}
+
+func (r *loginHistoryAuditRepo) ListByUserAndActions(ctx context.Context, userID string, actions []string, limit, offset int) ([]*models.AuditLog, error) {
+	r.gotUserID = userID
+	r.gotActions = actions
+	r.gotLimit = limit
+	return r.logs, nil
+}
+
+func TestAuditService_GetLoginHistory(t *testing.T) {
+	now := time.Now()
+
+	success := models.NewAuditLogWithUser("USER1", models.AuditActionLogin, models.ResourceTypeUser, models.AuditStatusSuccess, "ok")
+	success.Timestamp = now
+	success.IPAddress = "203.0.113.7"
+	success.UserAgent = "Mozilla/5.0"
+	success.AddDetail("method", "password")
+
+	failure := models.NewAuditLogWithUser("USER1", models.AuditActionLogin, models.ResourceTypeUser, models.AuditStatusFailure, "login failed: invalid_password")
+	failure.Timestamp = now.Add(-time.Minute)
+	failure.IPAddress = "198.51.100.9"
+	failure.AddDetail("failure_reason", "invalid_password")
+
+	repo := &loginHistoryAuditRepo{logs: []*models.AuditLog{success, failure}}
+	service := NewAuditService(nil, repo, nil, zap.NewNop())
+
+	history, err := service.GetLoginHistory(context.Background(), "USER1", 500)
+	require.NoError(t, err)
+
+	assert.Equal(t, "USER1", repo.gotUserID)
+	assert.Equal(t, []string{models.AuditActionLogin}, repo.gotActions)
+	assert.Equal(t, 100, repo.gotLimit, "limit should be capped")
+
+	require.Len(t, history, 2)
+	assert.True(t, history[0].Success)
+	assert.Equal(t, "203.0.113.7", history[0].IPAddress)
+	assert.Equal(t, "Mozilla/5.0", history[0].UserAgent)
+	assert.Equal(t, "password", history[0].Method)
+	assert.Empty(t, history[0].FailureReason)
+
+	assert.False(t, history[1].Success)
+	assert.Equal(t, "198.51.100.9", history[1].IPAddress)
+	assert.Equal(t, "invalid_password", history[1].FailureReason)
+}
+
+func TestAuditService_GetLoginHistory_DefaultLimit(t *testing.T) {
+	repo := &loginHistoryAuditRepo{}
+	service := NewAuditService(nil, repo, nil, zap.NewNop())
+
+	history, err := service.GetLoginHistory(context.Background(), "USER1", 0)
+	require.NoError(t, err)
+	assert.Empty(t, history)
+	assert.Equal(t, 20, repo.gotLimit)
+}
+
+// createdAuditRepo records the audit logs that are saved
+type createdAuditRepo struct {
+	interfaces.AuditRepository
+	created []*models.AuditLog
+}
+
+func (r *createdAuditRepo) Create(ctx context.Context, auditLog *models.AuditLog) error {
+	r.created = append(r.created, auditLog)
+	return nil
+}
+
+func TestAuditService_SanitizesMessages(t *testing.T) {
+	repo := &createdAuditRepo{}
+	service := NewAuditService(nil, repo, nil, zap.NewNop())
+
+	ctx := context.WithValue(context.Background(), "user_agent", "Agent\x00/"+strings.Repeat("x", 1000))
+	service.LogUserActionWithError(ctx, "USER1", "update_group", "group", "GRP1",
+		fmt.Errorf("invalid name %q\x00%s", "bad\x00name", strings.Repeat("e", 5000)), nil)
+
+	require.Len(t, repo.created, 1)
+	saved := repo.created[0]
+	assert.NotContains(t, saved.Message, "\x00")
+	assert.Equal(t, maxAuditMessageLength, len([]rune(saved.Message)))
+	assert.NotContains(t, saved.UserAgent, "\x00")
+	assert.Equal(t, maxAuditUserAgentLength, len([]rune(saved.UserAgent)))
+}
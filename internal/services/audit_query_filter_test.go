@@ -0,0 +1,143 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// filterAuditRepo records the filter of ListByFilter/CountByFilter and returns fixed logs
+type filterAuditRepo struct {
+	interfaces.AuditRepository
+	logs      []*models.AuditLog
+	gotFilter interfaces.AuditLogFilter
+	gotLimit  int
+	gotOffset int
+}
+
+func (r *filterAuditRepo) ListByFilter(ctx context.Context, filter interfaces.AuditLogFilter, limit, offset int) ([]*models.AuditLog, error) {
+	r.gotFilter = filter
+	r.gotLimit = limit
+	r.gotOffset = offset
+	return r.logs, nil
+}
+
+func (r *filterAuditRepo) CountByFilter(ctx context.Context, filter interfaces.AuditLogFilter) (int64, error) {
+	return int64(len(r.logs)), nil
+}
+
+func TestAuditService_QueryAuditLogs_MultipleActionsAndResources(t *testing.T) {
+	repo := &filterAuditRepo{logs: []*models.AuditLog{
+		models.NewAuditLogWithUser("USER1", models.AuditActionLogin, models.ResourceTypeUser, models.AuditStatusFailure, "bad password"),
+	}}
+	service := NewAuditService(nil, repo, nil, zap.NewNop())
+
+	start := time.Now().Add(-24 * time.Hour)
+	failed := false
+	result, err := service.QueryAuditLogs(context.Background(), &AuditQuery{
+		Action:    models.AuditActionLogin,
+		Actions:   []string{models.AuditActionLogin, models.AuditActionLogout, " ", models.AuditActionAccessDenied},
+		Resources: []string{models.ResourceTypeUser},
+		StartTime: &start,
+		Success:   &failed,
+		Page:      2,
+		PerPage:   10,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, interfaces.AuditLogFilter{
+		Actions:       []string{models.AuditActionLogin, models.AuditActionLogout, models.AuditActionAccessDenied},
+		ResourceTypes: []string{models.ResourceTypeUser},
+		Status:        models.AuditStatusFailure,
+		StartTime:     &start,
+	}, repo.gotFilter)
+	assert.Equal(t, 10, repo.gotLimit)
+	assert.Equal(t, 10, repo.gotOffset)
+	assert.Equal(t, int64(1), result.TotalCount)
+	assert.Len(t, result.Logs, 1)
+}
+
+func TestAuditService_QueryAuditLogs_SingleAction(t *testing.T) {
+	repo := &filterAuditRepo{}
+	service := NewAuditService(nil, repo, nil, zap.NewNop())
+
+	_, err := service.QueryAuditLogs(context.Background(), &AuditQuery{UserID: "USER1", Action: models.AuditActionLogin})
+	require.NoError(t, err)
+
+	assert.Equal(t, interfaces.AuditLogFilter{UserID: "USER1", Actions: []string{models.AuditActionLogin}}, repo.gotFilter)
+}
+
+func TestAuditService_QueryOrganizationAuditLogs_MultipleActions(t *testing.T) {
+	inOrg := models.NewAuditLogWithUser("USER1", models.AuditActionAssignRole, models.ResourceTypeRole, models.AuditStatusSuccess, "assigned")
+	inOrg.AddDetail("organization_id", "ORG1")
+	repo := &filterAuditRepo{logs: []*models.AuditLog{inOrg}}
+	service := NewAuditService(nil, repo, nil, zap.NewNop())
+
+	result, err := service.QueryOrganizationAuditLogs(context.Background(), "ORG1", &AuditQuery{
+		Actions: []string{models.AuditActionAssignRole, models.AuditActionRemoveRole},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "ORG1", repo.gotFilter.OrganizationID)
+	assert.Equal(t, []string{models.AuditActionAssignRole, models.AuditActionRemoveRole}, repo.gotFilter.Actions)
+	assert.Len(t, result.Logs, 1)
+}
+
+func TestAuditService_QueryAuditLogs_ActorAndOnBehalfOf(t *testing.T) {
+	repo := &filterAuditRepo{}
+	service := NewAuditService(nil, repo, nil, zap.NewNop())
+
+	_, err := service.QueryAuditLogs(context.Background(), &AuditQuery{ActorID: "ADMIN1", OnBehalfOfID: "USER1"})
+	require.NoError(t, err)
+
+	assert.Equal(t, interfaces.AuditLogFilter{ActorID: "ADMIN1", OnBehalfOfID: "USER1"}, repo.gotFilter,
+		"actor and on-behalf-of filters alone use the combined filter")
+}
+
+func TestAuditService_QueryAuditLogs_IPAddress(t *testing.T) {
+	tests := []struct {
+		name      string
+		ipAddress string
+		wantExact string
+		wantRange string
+	}{
+		{"exact IPv4", "203.0.113.7", "203.0.113.7", ""},
+		{"exact IPv6", "2001:DB8::1", "2001:db8::1", ""},
+		{"CIDR", "10.20.30.40/16", "", "10.20.0.0/16"},
+		{"single-address CIDR", "203.0.113.7/32", "203.0.113.7", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &filterAuditRepo{}
+			service := NewAuditService(nil, repo, nil, zap.NewNop())
+
+			_, err := service.QueryAuditLogs(context.Background(), &AuditQuery{IPAddress: tt.ipAddress, UserAgent: " okhttp "})
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.wantExact, repo.gotFilter.IPAddress)
+			if tt.wantRange == "" {
+				assert.Nil(t, repo.gotFilter.IPRange)
+			} else {
+				require.NotNil(t, repo.gotFilter.IPRange)
+				assert.Equal(t, tt.wantRange, repo.gotFilter.IPRange.String())
+			}
+			assert.Equal(t, "okhttp", repo.gotFilter.UserAgent)
+		})
+	}
+}
+
+func TestAuditService_QueryAuditLogs_InvalidIPAddress(t *testing.T) {
+	service := NewAuditService(nil, &filterAuditRepo{}, nil, zap.NewNop())
+
+	for _, ipAddress := range []string{"10.0.0", "10.0.0.0/33", "example.com"} {
+		_, err := service.QueryAuditLogs(context.Background(), &AuditQuery{IPAddress: ipAddress})
+		assert.True(t, errors.IsValidationError(err), ipAddress)
+	}
+}
@@ -0,0 +1,29 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/security"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestAuditService_LogDataAccess_RedactsSensitiveValues(t *testing.T) {
+	repo := &impersonationAuditRepo{}
+	service := NewAuditService(nil, repo, nil, zap.NewNop())
+
+	service.LogDataAccess(context.Background(), "USER1", "update", "user", "USER1",
+		map[string]interface{}{"m_pin": "1234", "profile": map[string]interface{}{"aadhaar_number": "123412341234"}},
+		map[string]interface{}{"m_pin": "5678", "name": "Ravi"},
+	)
+
+	require.Len(t, repo.logs, 1)
+	details := repo.logs[0].Details
+	assert.Equal(t, map[string]interface{}{
+		"m_pin":   security.RedactedValue,
+		"profile": map[string]interface{}{"aadhaar_number": security.RedactedValue},
+	}, details["old_values"])
+	assert.Equal(t, map[string]interface{}{"m_pin": security.RedactedValue, "name": "Ravi"}, details["new_values"])
+}
@@ -0,0 +1,273 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"go.uber.org/zap"
+)
+
+// Cache keys of audit logs waiting to be written again, and of those given up on
+const (
+	failedAuditLogKeyPrefix = "failed_audit_log:"
+	deadAuditLogKeyPrefix   = "dead_audit_log:"
+)
+
+// Cache TTLs, in seconds, of failed and dead-lettered audit logs
+const (
+	failedAuditLogTTL = 86400     // 24 hours
+	deadAuditLogTTL   = 7 * 86400 // 7 days, to recover them by hand
+)
+
+// failedAuditLog is an audit log whose database write failed, kept in the cache until it is retried
+type failedAuditLog struct {
+	AuditLog  *models.AuditLog `json:"audit_log"`
+	Attempts  int              `json:"attempts"`
+	LastError string           `json:"last_error,omitempty"`
+}
+
+// decodeFailedAuditLog reads a failed audit log back from the cache, which returns it decoded as
+// generic JSON. Entries cached before attempts were tracked hold the bare audit log.
+func decodeFailedAuditLog(value interface{}) (*failedAuditLog, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var entry failedAuditLog
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, err
+	}
+	if entry.AuditLog == nil {
+		var auditLog models.AuditLog
+		if err := json.Unmarshal(data, &auditLog); err != nil {
+			return nil, err
+		}
+		entry = failedAuditLog{AuditLog: &auditLog, Attempts: 1}
+	}
+	if entry.AuditLog.BaseModel == nil || entry.AuditLog.ID == "" {
+		return nil, fmt.Errorf("cached audit log has no ID")
+	}
+	return &entry, nil
+}
+
+// AuditRetryConfig tunes how the audit retry worker re-attempts failed audit writes
+type AuditRetryConfig struct {
+	// Interval is the wait between passes over the failed audit logs
+	Interval time.Duration
+	// MaxAttempts is the number of write attempts, including the original one, before an audit
+	// log is dead-lettered
+	MaxAttempts int
+	// BatchSize is the number of audit logs retried per pass
+	BatchSize int
+}
+
+// DefaultAuditRetryConfig returns the retry settings used when none are configured
+func DefaultAuditRetryConfig() AuditRetryConfig {
+	return AuditRetryConfig{
+		Interval:    30 * time.Second,
+		MaxAttempts: 10,
+		BatchSize:   500,
+	}
+}
+
+// auditLogWriter persists audit logs
+type auditLogWriter interface {
+	Create(ctx context.Context, auditLog *models.AuditLog) error
+}
+
+// AuditRetryWorker writes again the audit logs AuditService cached after a failed database write.
+// Logs that still fail after MaxAttempts are dead-lettered: logged in full at error level and
+// moved to a dead_audit_log:* key for manual recovery. Retries run in a background context, so
+// logs of isolated tenants are written to the shared schema.
+type AuditRetryWorker struct {
+	cache  interfaces.CacheService
+	repo   auditLogWriter
+	config AuditRetryConfig
+	logger *zap.Logger
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewAuditRetryWorker creates a worker retrying the failed audit logs in cache with repo. Call
+// Start to run it in the background.
+func NewAuditRetryWorker(cache interfaces.CacheService, repo auditLogWriter, config AuditRetryConfig, logger *zap.Logger) *AuditRetryWorker {
+	defaults := DefaultAuditRetryConfig()
+	if config.Interval <= 0 {
+		config.Interval = defaults.Interval
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = defaults.MaxAttempts
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = defaults.BatchSize
+	}
+
+	return &AuditRetryWorker{
+		cache:  cache,
+		repo:   repo,
+		config: config,
+		logger: logger.Named("audit_retry"),
+	}
+}
+
+// Start retries failed audit logs in the background until Stop is called or ctx is done
+func (w *AuditRetryWorker) Start(ctx context.Context) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.cancel != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	w.cancel = cancel
+	w.done = make(chan struct{})
+
+	go func(done chan struct{}) {
+		defer close(done)
+		w.run(ctx)
+	}(w.done)
+
+	w.logger.Info("Audit retry worker started",
+		zap.Duration("interval", w.config.Interval),
+		zap.Int("max_attempts", w.config.MaxAttempts))
+}
+
+// Stop stops the worker and waits for the pass in flight to finish
+func (w *AuditRetryWorker) Stop() {
+	w.mu.Lock()
+	cancel, done := w.cancel, w.done
+	w.cancel, w.done = nil, nil
+	w.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+	cancel()
+	<-done
+	w.logger.Info("Audit retry worker stopped")
+}
+
+func (w *AuditRetryWorker) run(ctx context.Context) {
+	ticker := time.NewTicker(w.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if _, err := w.RetryOnce(ctx); err != nil {
+			w.logger.Warn("Failed to retry audit logs", zap.Error(err))
+		}
+	}
+}
+
+// RetryOnce makes one pass over the failed audit logs and returns how many were persisted
+func (w *AuditRetryWorker) RetryOnce(ctx context.Context) (int, error) {
+	keys, err := w.cache.Keys(failedAuditLogKeyPrefix + "*")
+	if err != nil {
+		return 0, fmt.Errorf("failed to list failed audit logs: %w", err)
+	}
+	if len(keys) > w.config.BatchSize {
+		keys = keys[:w.config.BatchSize]
+	}
+
+	persisted := 0
+	for _, key := range keys {
+		if ctx.Err() != nil {
+			break
+		}
+		if w.retry(ctx, key) {
+			persisted++
+		}
+	}
+	return persisted, nil
+}
+
+// retry writes the audit log cached under key and reports whether it is now persisted
+func (w *AuditRetryWorker) retry(ctx context.Context, key string) bool {
+	value, ok := w.cache.Get(key)
+	if !ok {
+		// Expired, or taken by another instance
+		return false
+	}
+
+	entry, err := decodeFailedAuditLog(value)
+	if err != nil {
+		w.logger.Error("Dropping unreadable failed audit log", zap.String("key", key), zap.Error(err))
+		_ = w.cache.Delete(key)
+		return false
+	}
+
+	err = w.repo.Create(ctx, entry.AuditLog)
+	// A duplicate key means an earlier attempt, or another instance, already wrote it
+	if err == nil || isDuplicateKeyError(err) {
+		if delErr := w.cache.Delete(key); delErr != nil {
+			w.logger.Warn("Failed to remove retried audit log from cache", zap.String("key", key), zap.Error(delErr))
+		}
+		w.logger.Info("Persisted audit log on retry",
+			zap.String("audit_id", entry.AuditLog.ID),
+			zap.Int("attempts", entry.Attempts+1))
+		return true
+	}
+
+	entry.Attempts++
+	entry.LastError = err.Error()
+	if entry.Attempts >= w.config.MaxAttempts {
+		w.deadLetter(key, entry)
+		return false
+	}
+
+	if setErr := w.cache.Set(key, entry, failedAuditLogTTL); setErr != nil {
+		w.logger.Warn("Failed to record audit log retry", zap.String("key", key), zap.Error(setErr))
+	}
+	return false
+}
+
+// deadLetter gives up on an audit log: it is logged in full and kept under a dead-letter key
+func (w *AuditRetryWorker) deadLetter(key string, entry *failedAuditLog) {
+	auditLog := entry.AuditLog
+	userID := ""
+	if auditLog.UserID != nil {
+		userID = *auditLog.UserID
+	}
+	resourceID := ""
+	if auditLog.ResourceID != nil {
+		resourceID = *auditLog.ResourceID
+	}
+
+	w.logger.Error("Audit log dead-lettered after repeated write failures",
+		zap.String("audit_id", auditLog.ID),
+		zap.Int("attempts", entry.Attempts),
+		zap.String("last_error", entry.LastError),
+		zap.String("user_id", userID),
+		zap.String("action", auditLog.Action),
+		zap.String("resource_type", auditLog.ResourceType),
+		zap.String("resource_id", resourceID),
+		zap.String("status", auditLog.Status),
+		zap.String("message", auditLog.Message),
+		zap.Time("timestamp", auditLog.Timestamp),
+		zap.Any("details", auditLog.Details))
+
+	if err := w.cache.Set(deadAuditLogKeyPrefix+auditLog.ID, entry, deadAuditLogTTL); err != nil {
+		w.logger.Warn("Failed to keep dead-lettered audit log", zap.String("audit_id", auditLog.ID), zap.Error(err))
+	}
+	_ = w.cache.Delete(key)
+}
+
+func isDuplicateKeyError(err error) bool {
+	message := err.Error()
+	return strings.Contains(message, "duplicate key") || strings.Contains(message, "unique constraint")
+}
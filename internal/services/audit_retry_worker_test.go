@@ -0,0 +1,136 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// flakyAuditRepo fails the given number of writes before accepting them
+type flakyAuditRepo struct {
+	interfaces.AuditRepository
+	mu       sync.Mutex
+	failures int
+	created  []*models.AuditLog
+}
+
+func (r *flakyAuditRepo) Create(ctx context.Context, auditLog *models.AuditLog) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.failures > 0 {
+		r.failures--
+		return fmt.Errorf("dial tcp 10.0.0.5:5432: connection refused")
+	}
+	r.created = append(r.created, auditLog)
+	return nil
+}
+
+func (r *flakyAuditRepo) createdIDs() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var ids []string
+	for _, auditLog := range r.created {
+		ids = append(ids, auditLog.ID)
+	}
+	return ids
+}
+
+// logFailedAudit writes one audit log through AuditService while the database is failing
+func logFailedAudit(t *testing.T, repo *flakyAuditRepo, cache *memoryCache) string {
+	service := NewAuditService(nil, repo, cache, zap.NewNop())
+	service.LogUserAction(context.Background(), "USER1", "update_profile", "user", "USER1", map[string]interface{}{"field": "name"})
+
+	keys, err := cache.Keys(failedAuditLogKeyPrefix + "*")
+	require.NoError(t, err)
+	require.Len(t, keys, 1, "the failed write is cached for retry")
+	return keys[0][len(failedAuditLogKeyPrefix):]
+}
+
+func TestAuditRetryWorker_PersistsAfterTransientFailure(t *testing.T) {
+	repo := &flakyAuditRepo{failures: 2}
+	cache := &memoryCache{values: map[string]interface{}{}}
+	auditID := logFailedAudit(t, repo, cache)
+	worker := NewAuditRetryWorker(cache, repo, AuditRetryConfig{MaxAttempts: 5}, zap.NewNop())
+
+	persisted, err := worker.RetryOnce(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, persisted, "the database is still failing")
+	entry, err := decodeFailedAuditLog(cache.values[failedAuditLogKeyPrefix+auditID])
+	require.NoError(t, err)
+	assert.Equal(t, 2, entry.Attempts)
+
+	persisted, err = worker.RetryOnce(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, persisted)
+	assert.Equal(t, []string{auditID}, repo.createdIDs())
+	assert.Equal(t, "name", repo.created[0].Details["field"])
+	assert.Empty(t, cache.values)
+}
+
+func TestAuditRetryWorker_DeadLettersAfterMaxAttempts(t *testing.T) {
+	repo := &flakyAuditRepo{failures: 100}
+	cache := &memoryCache{values: map[string]interface{}{}}
+	auditID := logFailedAudit(t, repo, cache)
+	worker := NewAuditRetryWorker(cache, repo, AuditRetryConfig{MaxAttempts: 3}, zap.NewNop())
+
+	for i := 0; i < 3; i++ {
+		_, err := worker.RetryOnce(context.Background())
+		require.NoError(t, err)
+	}
+
+	assert.Empty(t, repo.createdIDs())
+	assert.NotContains(t, cache.values, failedAuditLogKeyPrefix+auditID)
+	require.Contains(t, cache.values, deadAuditLogKeyPrefix+auditID)
+	entry, err := decodeFailedAuditLog(cache.values[deadAuditLogKeyPrefix+auditID])
+	require.NoError(t, err)
+	assert.Equal(t, 3, entry.Attempts)
+	assert.Contains(t, entry.LastError, "connection refused")
+}
+
+func TestAuditRetryWorker_RunsInBackground(t *testing.T) {
+	repo := &flakyAuditRepo{failures: 3}
+	cache := &memoryCache{values: map[string]interface{}{}}
+	auditID := logFailedAudit(t, repo, cache)
+	worker := NewAuditRetryWorker(cache, repo, AuditRetryConfig{Interval: 5 * time.Millisecond, MaxAttempts: 10}, zap.NewNop())
+
+	worker.Start(context.Background())
+	require.Eventually(t, func() bool {
+		return len(repo.createdIDs()) == 1
+	}, time.Second, 5*time.Millisecond)
+	worker.Stop()
+
+	assert.Equal(t, []string{auditID}, repo.createdIDs())
+	assert.Empty(t, cache.values)
+}
+
+func TestDecodeFailedAuditLog_ReadsRedisJSON(t *testing.T) {
+	auditLog := models.NewAuditLog("update_profile", "user", models.AuditStatusSuccess, "updated")
+
+	// Redis returns values decoded into generic JSON, with or without the retry envelope
+	for name, cached := range map[string]interface{}{
+		"envelope": &failedAuditLog{AuditLog: auditLog, Attempts: 4},
+		"bare":     auditLog,
+	} {
+		data, err := json.Marshal(cached)
+		require.NoError(t, err)
+		var generic interface{}
+		require.NoError(t, json.Unmarshal(data, &generic))
+
+		entry, err := decodeFailedAuditLog(generic)
+		require.NoError(t, err, name)
+		assert.Equal(t, auditLog.ID, entry.AuditLog.ID, name)
+		assert.Equal(t, "update_profile", entry.AuditLog.Action, name)
+	}
+
+	_, err := decodeFailedAuditLog(map[string]interface{}{"action": "login"})
+	assert.Error(t, err, "entries without an ID cannot be written")
+}
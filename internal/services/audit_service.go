@@ -3,22 +3,38 @@ package services
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"net/netip"
+	"strings"
 	"time"
 
 	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
 	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/internal/security"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/Kisanlink/aaa-service/v2/utils"
 	"github.com/Kisanlink/kisanlink-db/pkg/db"
 	"go.uber.org/zap"
 )
 
 // AuditService provides audit logging services
 type AuditService struct {
-	dbManager    db.DBManager
-	auditRepo    interfaces.AuditRepository
-	cacheService interfaces.CacheService
-	logger       *zap.Logger
+	dbManager       db.DBManager
+	auditRepo       interfaces.AuditRepository
+	cacheService    interfaces.CacheService
+	logger          *zap.Logger
+	maxDetailsBytes int               // Serialized details above this size are truncated; 0 disables
+	detailsStore    AuditDetailsStore // Optional: keeps the full details of truncated logs
+	userLookup      auditUserLookup   // Optional: keeps the user IDs of ingested logs that belong to users
 }
 
+const (
+	// maxAuditMessageLength caps the message of an audit log
+	maxAuditMessageLength = 2000
+	// maxAuditUserAgentLength caps the user agent recorded with an audit log
+	maxAuditUserAgentLength = 500
+)
+
 // AuditLogEntry is an alias for the models.AuditLog for compatibility
 type AuditLogEntry = models.AuditLog
 
@@ -34,17 +50,107 @@ type AuditEventDetails struct {
 	TransactionID string                 `json:"transaction_id,omitempty"`
 }
 
-// AuditQuery represents a query for audit logs
+// AuditQuery represents a query for audit logs.
+// Action and Actions are combined, and so are Resource and Resources: a log matches when its action
+// (or resource type) is any of the given values. ActorID matches who performed the action and
+// OnBehalfOfID the user it was performed on, e.g. every change made to a user by an admin.
 type AuditQuery struct {
-	UserID     string     `json:"user_id,omitempty"`
-	Action     string     `json:"action,omitempty"`
-	Resource   string     `json:"resource,omitempty"`
-	ResourceID string     `json:"resource_id,omitempty"`
-	StartTime  *time.Time `json:"start_time,omitempty"`
-	EndTime    *time.Time `json:"end_time,omitempty"`
-	Success    *bool      `json:"success,omitempty"`
-	Page       int        `json:"page"`
-	PerPage    int        `json:"per_page"`
+	UserID       string     `json:"user_id,omitempty"`
+	ActorID      string     `json:"actor_id,omitempty"`
+	OnBehalfOfID string     `json:"on_behalf_of_id,omitempty"`
+	Action       string     `json:"action,omitempty"`
+	Actions      []string   `json:"actions,omitempty"`
+	Resource     string     `json:"resource,omitempty"`
+	Resources    []string   `json:"resources,omitempty"`
+	ResourceID   string     `json:"resource_id,omitempty"`
+	StartTime    *time.Time `json:"start_time,omitempty"`
+	EndTime      *time.Time `json:"end_time,omitempty"`
+	Success      *bool      `json:"success,omitempty"`
+	IPAddress    string     `json:"ip_address,omitempty"` // an IP address, or a CIDR network
+	UserAgent    string     `json:"user_agent,omitempty"` // matched as a case-insensitive substring
+	Page         int        `json:"page"`
+	PerPage      int        `json:"per_page"`
+}
+
+// ActionValues returns the distinct actions the query matches, from Action and Actions
+func (q *AuditQuery) ActionValues() []string {
+	return distinctAuditValues(q.Action, q.Actions)
+}
+
+// ResourceValues returns the distinct resource types the query matches, from Resource and Resources
+func (q *AuditQuery) ResourceValues() []string {
+	return distinctAuditValues(q.Resource, q.Resources)
+}
+
+// usesLogFilter reports whether the query needs the combined repository filter rather than one of
+// the single-condition repository queries
+func (q *AuditQuery) usesLogFilter() bool {
+	return len(q.ActionValues()) > 0 || len(q.ResourceValues()) > 0 || q.ActorID != "" || q.OnBehalfOfID != "" ||
+		q.IPAddress != "" || q.UserAgent != ""
+}
+
+// ipFilter parses IPAddress into an exact address or a network. A network holding a single
+// address is matched exactly.
+func (q *AuditQuery) ipFilter() (string, *netip.Prefix, error) {
+	raw := strings.TrimSpace(q.IPAddress)
+	if raw == "" {
+		return "", nil, nil
+	}
+	if strings.Contains(raw, "/") {
+		network, err := netip.ParsePrefix(raw)
+		if err != nil {
+			return "", nil, errors.NewValidationError("ip_address must be an IP address or a CIDR network", raw)
+		}
+		network = network.Masked()
+		if network.IsSingleIP() {
+			return network.Addr().String(), nil, nil
+		}
+		return "", &network, nil
+	}
+	addr, err := netip.ParseAddr(raw)
+	if err != nil {
+		return "", nil, errors.NewValidationError("ip_address must be an IP address or a CIDR network", raw)
+	}
+	return addr.String(), nil, nil
+}
+
+// logFilter converts the query into a repository filter
+func (q *AuditQuery) logFilter() interfaces.AuditLogFilter {
+	filter := interfaces.AuditLogFilter{
+		UserID:        q.UserID,
+		ActorID:       q.ActorID,
+		OnBehalfOfID:  q.OnBehalfOfID,
+		Actions:       q.ActionValues(),
+		ResourceTypes: q.ResourceValues(),
+		ResourceID:    q.ResourceID,
+		StartTime:     q.StartTime,
+		EndTime:       q.EndTime,
+		UserAgent:     strings.TrimSpace(q.UserAgent),
+	}
+	// Queries are validated before they are converted
+	filter.IPAddress, filter.IPRange, _ = q.ipFilter()
+	if q.Success != nil {
+		if *q.Success {
+			filter.Status = models.AuditStatusSuccess
+		} else {
+			filter.Status = models.AuditStatusFailure
+		}
+	}
+	return filter
+}
+
+func distinctAuditValues(single string, many []string) []string {
+	var values []string
+	seen := make(map[string]bool)
+	for _, value := range append([]string{single}, many...) {
+		value = strings.TrimSpace(value)
+		if value == "" || seen[value] {
+			continue
+		}
+		seen[value] = true
+		values = append(values, value)
+	}
+	return values
 }
 
 // AuditQueryResult represents the result of an audit query
@@ -55,6 +161,16 @@ type AuditQueryResult struct {
 	PerPage    int               `json:"per_page"`
 }
 
+// LoginHistoryEntry is a single sign-in attempt shown to the account owner
+type LoginHistoryEntry struct {
+	Timestamp     time.Time `json:"timestamp"`
+	Success       bool      `json:"success"`
+	IPAddress     string    `json:"ip_address"`
+	UserAgent     string    `json:"user_agent"`
+	Method        string    `json:"method,omitempty"`
+	FailureReason string    `json:"failure_reason,omitempty"`
+}
+
 // NewAuditService creates a new audit service
 func NewAuditService(
 	dbManager db.DBManager,
@@ -66,27 +182,38 @@ func NewAuditService(
 	// is handled elsewhere in the application startup
 
 	return &AuditService{
-		dbManager:    dbManager,
-		auditRepo:    auditRepo,
-		cacheService: cacheService,
-		logger:       logger,
+		dbManager:       dbManager,
+		auditRepo:       auditRepo,
+		cacheService:    cacheService,
+		logger:          logger,
+		maxDetailsBytes: DefaultMaxAuditDetailsBytes,
 	}
 }
 
 // isAnonymousUser checks if the userID represents an anonymous user or a service principal
-// Service IDs (starting with "SVC") are treated as anonymous since they don't exist in the users table
+// Service and principal IDs (starting with "SVC" or "PRN") are treated as anonymous since they don't
+// exist in the users table; logEvent records them as the principal_id detail instead
 func isAnonymousUser(userID string) bool {
 	if userID == "anonymous" || userID == "unknown" || userID == "" {
 		return true
 	}
 	// Service IDs start with "SVC" and should not be used as user_id in audit_logs
 	// because audit_logs.user_id has a foreign key constraint to the users table
-	if len(userID) >= 3 && userID[:3] == "SVC" {
+	if strings.HasPrefix(userID, "SVC") || strings.HasPrefix(userID, "PRN") {
 		return true
 	}
 	return false
 }
 
+// auditActorID returns the ID to record as the actor of a delegated action. Service principals are
+// kept, as actor_id has no foreign key to users; placeholder IDs of anonymous callers are not.
+func auditActorID(userID string) string {
+	if userID == "anonymous" || userID == "unknown" {
+		return ""
+	}
+	return userID
+}
+
 // LogUserAction logs a user action
 func (s *AuditService) LogUserAction(ctx context.Context, userID, action, resource, resourceID string, details map[string]interface{}) {
 	if isAnonymousUser(userID) {
@@ -153,6 +280,32 @@ func (s *AuditService) LogAPIAccess(ctx context.Context, userID, method, endpoin
 	}
 }
 
+// RecordSlowRequest records a request that exceeded the slow-request threshold as an API call
+// audit log carrying its duration, so it shows up in the performance metrics
+func (s *AuditService) RecordSlowRequest(ctx context.Context, userID, method, route string, statusCode int, duration time.Duration) {
+	status := models.AuditStatusSuccess
+	if statusCode >= http.StatusInternalServerError {
+		status = models.AuditStatusFailure
+	}
+	message := fmt.Sprintf("Slow request: %s %s took %dms", method, route, duration.Milliseconds())
+
+	var auditLog *models.AuditLog
+	if isAnonymousUser(userID) {
+		auditLog = models.NewAuditLog(models.AuditActionAPICall, models.ResourceTypeAPIEndpoint, status, message)
+	} else {
+		auditLog = models.NewAuditLogWithUser(userID, models.AuditActionAPICall, models.ResourceTypeAPIEndpoint, status, message)
+	}
+	auditLog.ResourceID = &route
+
+	s.logEvent(ctx, auditLog, map[string]interface{}{
+		"http_method":  method,
+		"endpoint":     route,
+		"status_code":  statusCode,
+		"duration_ms":  duration.Milliseconds(),
+		"slow_request": true,
+	})
+}
+
 // LogAccessDenied logs access denied events
 func (s *AuditService) LogAccessDenied(ctx context.Context, userID, action, resource, resourceID, reason string) {
 	if isAnonymousUser(userID) {
@@ -330,6 +483,7 @@ func (s *AuditService) LogRoleOperation(ctx context.Context, actorUserID, target
 	} else {
 		auditLog = models.NewAuditLogWithUserAndResource(actorUserID, action, models.ResourceTypeRole, roleID, status, message)
 	}
+	auditLog.SetDelegation(auditActorID(actorUserID), targetUserID)
 
 	// Add role operation specific details
 	if details == nil {
@@ -371,6 +525,10 @@ func (s *AuditService) LogMPINOperation(ctx context.Context, userID, operation,
 		"mpin_operation": operation,
 		"success":        success,
 	}
+	// Operations on a device-bound MPIN name the device
+	if deviceID, ok := ctx.Value("device_id").(string); ok && deviceID != "" {
+		details["device_id"] = deviceID
+	}
 
 	if !success && failureReason != "" {
 		details["failure_reason"] = failureReason
@@ -399,6 +557,7 @@ func (s *AuditService) LogUserLifecycleEvent(ctx context.Context, actorUserID, t
 	} else {
 		auditLog = models.NewAuditLogWithUserAndResource(actorUserID, action, models.ResourceTypeUser, targetUserID, status, message)
 	}
+	auditLog.SetDelegation(auditActorID(actorUserID), targetUserID)
 
 	// Add user lifecycle specific details
 	if details == nil {
@@ -480,6 +639,10 @@ func (s *AuditService) LogSystemEvent(ctx context.Context, action, resource stri
 
 // QueryAuditLogs queries audit logs with filtering
 func (s *AuditService) QueryAuditLogs(ctx context.Context, query *AuditQuery) (*AuditQueryResult, error) {
+	if _, _, err := query.ipFilter(); err != nil {
+		return nil, err
+	}
+
 	// Validate and set defaults for pagination
 	if query.Page <= 0 {
 		query.Page = 1
@@ -529,7 +692,13 @@ func (s *AuditService) QueryAuditLogs(ctx context.Context, query *AuditQuery) (*
 	var err error
 
 	// Apply filters based on query parameters
-	if query.StartTime != nil && query.EndTime != nil {
+	if query.usesLogFilter() {
+		filter := query.logFilter()
+		logs, err = s.auditRepo.ListByFilter(ctx, filter, query.PerPage, offset)
+		if err == nil {
+			totalCount, err = s.auditRepo.CountByFilter(ctx, filter)
+		}
+	} else if query.StartTime != nil && query.EndTime != nil {
 		if query.UserID != "" {
 			logs, err = s.auditRepo.ListByUserAndTimeRange(ctx, query.UserID, *query.StartTime, *query.EndTime, query.PerPage, offset)
 			if err == nil {
@@ -546,10 +715,6 @@ func (s *AuditService) QueryAuditLogs(ctx context.Context, query *AuditQuery) (*
 		if err == nil {
 			totalCount, err = s.auditRepo.CountByUser(ctx, query.UserID)
 		}
-	} else if query.Action != "" {
-		logs, err = s.auditRepo.ListByAction(ctx, query.Action, query.PerPage, offset)
-	} else if query.Resource != "" {
-		logs, err = s.auditRepo.ListByResourceType(ctx, query.Resource, query.PerPage, offset)
 	} else {
 		logs, err = s.auditRepo.List(ctx, query.PerPage, offset)
 	}
@@ -569,8 +734,8 @@ func (s *AuditService) QueryAuditLogs(ctx context.Context, query *AuditQuery) (*
 
 	s.logger.Debug("Querying audit logs completed",
 		zap.String("user_id", query.UserID),
-		zap.String("action", query.Action),
-		zap.String("resource", query.Resource),
+		zap.Strings("actions", query.ActionValues()),
+		zap.Strings("resources", query.ResourceValues()),
 		zap.Int("page", query.Page),
 		zap.Int("per_page", query.PerPage),
 		zap.Int("results", len(logSlice)))
@@ -597,6 +762,50 @@ func (s *AuditService) GetUserAuditTrail(ctx context.Context, userID string, day
 	return s.QueryAuditLogs(ctx, query)
 }
 
+// GetLoginHistory returns the most recent sign-in attempts, successful and failed, for a user
+func (s *AuditService) GetLoginHistory(ctx context.Context, userID string, limit int) ([]LoginHistoryEntry, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	logs, err := s.auditRepo.ListByUserAndActions(ctx, userID, []string{models.AuditActionLogin}, limit, 0)
+	if err != nil {
+		s.logger.Error("Failed to query login history", zap.String("user_id", userID), zap.Error(err))
+		return nil, err
+	}
+
+	history := make([]LoginHistoryEntry, 0, len(logs))
+	for _, log := range logs {
+		if log == nil {
+			continue
+		}
+		entry := LoginHistoryEntry{
+			Timestamp: log.Timestamp,
+			Success:   log.Status == models.AuditStatusSuccess,
+			IPAddress: log.IPAddress,
+			UserAgent: log.UserAgent,
+		}
+		if method, ok := log.Details["method"].(string); ok {
+			entry.Method = method
+		} else if method, ok := log.Details["authentication_method"].(string); ok {
+			entry.Method = method
+		}
+		if !entry.Success {
+			if reason, ok := log.Details["failure_reason"].(string); ok {
+				entry.FailureReason = reason
+			} else {
+				entry.FailureReason = log.Message
+			}
+		}
+		history = append(history, entry)
+	}
+
+	return history, nil
+}
+
 // GetResourceAuditTrail gets audit trail for a specific resource
 func (s *AuditService) GetResourceAuditTrail(ctx context.Context, resource, resourceID string, days int, page, perPage int) (*AuditQueryResult, error) {
 	startTime := time.Now().AddDate(0, 0, -days)
@@ -697,6 +906,36 @@ func (s *AuditService) logEvent(ctx context.Context, auditLog *models.AuditLog,
 		}
 	}
 
+	// Attribute calls made by service principals, whose IDs cannot be stored as user_id
+	if principalType, _ := ctx.Value("principal_type").(string); principalType == "service" {
+		if serviceID, ok := ctx.Value("service_id").(string); ok && serviceID != "" {
+			auditLog.AddDetail("principal_id", serviceID)
+			if auditLog.ActorID == nil {
+				auditLog.ActorID = &serviceID
+			}
+		}
+	}
+
+	// Attribute actions taken with an impersonation token to the real actor, on behalf of the
+	// impersonated user unless the action names another target
+	if impersonatorID, ok := ctx.Value("impersonator_id").(string); ok && impersonatorID != "" {
+		if auditLog.OnBehalfOfID == nil && auditLog.UserID != nil {
+			impersonatedID := *auditLog.UserID
+			auditLog.OnBehalfOfID = &impersonatedID
+		}
+		auditLog.ActorID = &impersonatorID
+		auditLog.AddDetail("impersonator_id", impersonatorID)
+		if impersonationID, ok := ctx.Value("impersonation_id").(string); ok {
+			auditLog.AddDetail("impersonation_id", impersonationID)
+		}
+	}
+
+	// Without delegation the user is the actor
+	if auditLog.ActorID == nil && auditLog.UserID != nil {
+		actorID := *auditLog.UserID
+		auditLog.ActorID = &actorID
+	}
+
 	// Add request ID for traceability
 	if requestID := ctx.Value("request_id"); requestID != nil {
 		if rid, ok := requestID.(string); ok {
@@ -718,6 +957,16 @@ func (s *AuditService) logEvent(ctx context.Context, auditLog *models.AuditLog,
 		}
 	}
 
+	// Details can carry request payloads and old/new values; never store secrets in them
+	auditLog.Details = security.DefaultRedactor().RedactMap(auditLog.Details)
+
+	// Large old/new values would bloat the row; redaction runs first so the full copy holds no secrets
+	s.limitDetails(ctx, auditLog)
+
+	// Messages and user agents can carry client input; keep them storable and bounded
+	auditLog.Message = utils.TruncateText(auditLog.Message, maxAuditMessageLength)
+	auditLog.UserAgent = utils.TruncateText(auditLog.UserAgent, maxAuditUserAgentLength)
+
 	// Save audit log to database using repository
 	err := s.auditRepo.Create(ctx, auditLog)
 	if err != nil {
@@ -728,7 +977,7 @@ func (s *AuditService) logEvent(ctx context.Context, auditLog *models.AuditLog,
 			zap.String("action", auditLog.Action))
 
 		// Try to cache the audit log for later retry
-		s.cacheFailedAuditLog(auditLog)
+		s.cacheFailedAuditLog(auditLog, err)
 	}
 
 	// Log to application logger with structured information
@@ -876,16 +1125,17 @@ func (s *AuditService) logPerformanceMetrics(auditLog *models.AuditLog) {
 	s.logger.Debug("Performance metrics", performanceFields...)
 }
 
-// cacheFailedAuditLog caches audit logs that failed to save to database for retry
-func (s *AuditService) cacheFailedAuditLog(auditLog *models.AuditLog) {
+// cacheFailedAuditLog caches audit logs that failed to save to database; AuditRetryWorker
+// writes them again
+func (s *AuditService) cacheFailedAuditLog(auditLog *models.AuditLog, saveErr error) {
 	if s.cacheService == nil {
 		return
 	}
 
-	cacheKey := fmt.Sprintf("failed_audit_log:%s", auditLog.ID)
+	cacheKey := failedAuditLogKeyPrefix + auditLog.ID
+	entry := &failedAuditLog{AuditLog: auditLog, Attempts: 1, LastError: saveErr.Error()}
 
-	// Cache for 24 hours (86400 seconds)
-	err := s.cacheService.Set(cacheKey, auditLog, 86400)
+	err := s.cacheService.Set(cacheKey, entry, failedAuditLogTTL)
 	if err != nil {
 		s.logger.Error("Failed to cache failed audit log",
 			zap.Error(err),
@@ -906,8 +1156,12 @@ func (s *AuditService) isSecuritySensitiveAction(action string) bool {
 		models.AuditActionRemoveRole,
 		models.AuditActionGrantPermission,
 		models.AuditActionRevokePermission,
+		models.AuditActionPermissionGrant,
+		models.AuditActionPermissionRevoke,
 		models.AuditActionAccessDenied,
 		models.AuditActionSecurityEvent,
+		models.AuditActionStartImpersonation,
+		models.AuditActionStopImpersonation,
 		"mpin_setup",
 		"mpin_update",
 		"mpin_verification",
@@ -1038,6 +1292,7 @@ func (s *AuditService) LogGroupMembershipChange(ctx context.Context, actorUserID
 	} else {
 		auditLog = models.NewAuditLogWithUserAndResource(actorUserID, action, models.ResourceTypeGroup, groupID, status, message)
 	}
+	auditLog.SetDelegation(auditActorID(actorUserID), targetUserID)
 
 	// Add membership-specific details
 	if details == nil {
@@ -1068,6 +1323,7 @@ func (s *AuditService) LogGroupRoleAssignment(ctx context.Context, actorUserID,
 	} else {
 		auditLog = models.NewAuditLogWithUserAndResource(actorUserID, action, models.ResourceTypeGroupRole, groupID, status, message)
 	}
+	auditLog.SetDelegation(auditActorID(actorUserID), "")
 
 	// Add role assignment specific details
 	if details == nil {
@@ -1185,6 +1441,10 @@ func (s *AuditService) isCriticalStructureChange(action string) bool {
 
 // QueryOrganizationAuditLogs queries audit logs scoped to a specific organization
 func (s *AuditService) QueryOrganizationAuditLogs(ctx context.Context, orgID string, query *AuditQuery) (*AuditQueryResult, error) {
+	if _, _, err := query.ipFilter(); err != nil {
+		return nil, err
+	}
+
 	// Validate and set defaults for pagination
 	if query.Page <= 0 {
 		query.Page = 1
@@ -1235,7 +1495,14 @@ func (s *AuditService) QueryOrganizationAuditLogs(ctx context.Context, orgID str
 	var err error
 
 	// Apply organization-scoped filters based on query parameters
-	if query.StartTime != nil && query.EndTime != nil {
+	if query.usesLogFilter() {
+		filter := query.logFilter()
+		filter.OrganizationID = orgID
+		logs, err = s.auditRepo.ListByFilter(ctx, filter, query.PerPage, offset)
+		if err == nil {
+			totalCount, err = s.auditRepo.CountByFilter(ctx, filter)
+		}
+	} else if query.StartTime != nil && query.EndTime != nil {
 		logs, err = s.auditRepo.ListByOrganizationAndTimeRange(ctx, orgID, *query.StartTime, *query.EndTime, query.PerPage, offset)
 		if err == nil {
 			totalCount, err = s.auditRepo.CountByOrganizationAndTimeRange(ctx, orgID, *query.StartTime, *query.EndTime)
@@ -1281,8 +1548,8 @@ func (s *AuditService) QueryOrganizationAuditLogs(ctx context.Context, orgID str
 	s.logger.Debug("Querying organization audit logs with security filters",
 		zap.String("org_id", orgID),
 		zap.String("user_id", query.UserID),
-		zap.String("action", query.Action),
-		zap.String("resource", query.Resource),
+		zap.Strings("actions", query.ActionValues()),
+		zap.Strings("resources", query.ResourceValues()),
 		zap.Int("page", query.Page),
 		zap.Int("per_page", query.PerPage),
 		zap.Int("total_results", len(validatedLogs)))
@@ -0,0 +1,36 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/security"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestAuthService_VerifyMPin_UpgradesWeakHash(t *testing.T) {
+	weak, err := bcrypt.GenerateFromPassword([]byte("1234"), bcrypt.MinCost)
+	require.NoError(t, err)
+	user := models.NewUser("9876543210", "+91", "hash")
+	user.ID = "USER1"
+	user.SetMPin(string(weak))
+	repo := &impersonationUserRepo{users: map[string]*models.User{"USER1": user}}
+
+	service, err := NewAuthService(repo, nil, nil, nil, nil, nil, &AuthServiceConfig{JWTSecret: "test-secret"}, zap.NewNop(), nil, nil)
+	require.NoError(t, err)
+	service.SetCredentialHasher(security.NewCredentialHasher(bcrypt.MinCost+1, bcrypt.MinCost+1))
+
+	require.Error(t, service.VerifyMPin(context.Background(), "USER1", "9999"))
+	assert.Equal(t, string(weak), *repo.users["USER1"].MPin, "a failed verification leaves the hash alone")
+
+	require.NoError(t, service.VerifyMPin(context.Background(), "USER1", "1234"))
+	cost, err := bcrypt.Cost([]byte(*repo.users["USER1"].MPin))
+	require.NoError(t, err)
+	assert.Equal(t, bcrypt.MinCost+1, cost)
+
+	require.NoError(t, service.VerifyMPin(context.Background(), "USER1", "1234"), "the mPin still verifies against the new hash")
+}
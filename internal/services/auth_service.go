@@ -7,8 +7,12 @@ import (
 
 	configPkg "github.com/Kisanlink/aaa-service/v2/internal/config"
 	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/responses"
 	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/internal/security"
+	"github.com/Kisanlink/aaa-service/v2/internal/services/notifications"
 	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/Kisanlink/aaa-service/v2/pkg/phonenumber"
 	jwt "github.com/golang-jwt/jwt/v4"
 	"go.uber.org/zap"
 	"golang.org/x/crypto/bcrypt"
@@ -22,6 +26,7 @@ type AuthService struct {
 	cacheService       interfaces.CacheService
 	authzService       *AuthorizationService
 	auditService       *AuditService
+	notifier           interfaces.SecurityNotifier // Optional: security event notifications
 
 	logger        *zap.Logger
 	validator     interfaces.Validator
@@ -29,6 +34,12 @@ type AuthService struct {
 	tokenExpiry   time.Duration
 	refreshExpiry time.Duration
 	jwtCfg        *configPkg.JWTConfig
+	loginStepUp   configPkg.LoginStepUpConfig
+	sessions      configPkg.DeviceSessionConfig
+	sessionLimit  configPkg.SessionLimitConfig
+	sessionLimits interfaces.SessionLimitResolver // Optional: per-organization session limits
+	hasher        *security.CredentialHasher
+	signingKeys   *security.SigningKeyRing // Optional: RS256 signing keys, see SetSigningKeys
 }
 
 // AuthServiceConfig contains configuration for AuthService
@@ -65,6 +76,7 @@ func NewAuthService(
 		tokenExpiry:   cfg.TokenExpiry,
 		refreshExpiry: cfg.RefreshExpiry,
 		jwtCfg:        jwtCfg,
+		hasher:        security.DefaultCredentialHasher(),
 	}, nil
 }
 
@@ -91,6 +103,9 @@ type LoginResponse struct {
 	ExpiresIn    int64       `json:"expires_in"`
 	User         models.User `json:"user"`
 	Permissions  []string    `json:"permissions"`
+	// Challenge is set instead of the tokens when the login must be completed with the MPIN,
+	// see VerifyLoginMPIN
+	Challenge *responses.LoginChallengeResponse `json:"challenge,omitempty"`
 }
 
 // RegisterRequest represents a registration request
@@ -138,12 +153,15 @@ func (s *AuthService) Login(ctx context.Context, req *LoginRequest) (*LoginRespo
 	// Verify password
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
 		s.logger.Warn("Login attempt with invalid password", zap.String("user_id", user.ID))
+		s.recordLoginFailure(ctx, user.ID, "password", "invalid_password")
 		return nil, errors.NewUnauthorizedError("invalid credentials")
 	}
+	s.hasher.RehashPasswordIfNeeded(ctx, s.userRepository, user, req.Password, s.logger)
 
 	// Check if user is active
 	if user.Status != nil && *user.Status != "active" {
 		s.logger.Warn("Login attempt for inactive user", zap.String("user_id", user.ID), zap.String("status", *user.Status))
+		s.recordLoginFailure(ctx, user.ID, "password", "account_not_active")
 		return nil, errors.NewUnauthorizedError("account is not active")
 	}
 
@@ -153,11 +171,23 @@ func (s *AuthService) Login(ctx context.Context, req *LoginRequest) (*LoginRespo
 			s.logger.Warn("MFA validation failed",
 				zap.String("user_id", user.ID),
 				zap.Error(err))
+			s.recordLoginFailure(ctx, user.ID, "password", "invalid_mfa_code")
 			return nil, errors.NewUnauthorizedError("invalid MFA code")
 		}
 		s.logger.Info("MFA validation successful", zap.String("user_id", user.ID))
 	}
 
+	// Stepped login: a verified password is not enough for users with an MPIN
+	challenge, err := s.LoginStepUp(ctx, user.ID, "password", user.HasMPin())
+	if err != nil {
+		s.logger.Error("Failed to create login challenge", zap.String("user_id", user.ID), zap.Error(err))
+		return nil, err
+	}
+	if challenge != nil {
+		s.logger.Info("Login requires MPIN verification", zap.String("user_id", user.ID))
+		return &LoginResponse{Challenge: challenge}, nil
+	}
+
 	// Get user roles and permissions
 	userRoles, err := s.userRoleRepository.GetByUserID(ctx, user.ID)
 	if err != nil {
@@ -173,16 +203,10 @@ func (s *AuthService) Login(ctx context.Context, req *LoginRequest) (*LoginRespo
 	}
 
 	// Generate tokens
-	accessToken, err := s.generateAccessToken(user, userRoles, permissions)
+	accessToken, refreshToken, err := s.issueTokens(ctx, user, userRoles, permissions, "")
 	if err != nil {
-		s.logger.Error("Failed to generate access token", zap.String("user_id", user.ID), zap.Error(err))
-		return nil, errors.NewInternalError(fmt.Errorf("failed to generate access token: %w", err))
-	}
-
-	refreshToken, err := s.generateRefreshToken(user, userRoles, permissions)
-	if err != nil {
-		s.logger.Error("Failed to generate refresh token", zap.String("user_id", user.ID), zap.Error(err))
-		return nil, errors.NewInternalError(fmt.Errorf("failed to generate refresh token: %w", err))
+		s.logger.Error("Failed to generate tokens", zap.String("user_id", user.ID), zap.Error(err))
+		return nil, err
 	}
 
 	// Store refresh token in cache
@@ -192,11 +216,15 @@ func (s *AuthService) Login(ctx context.Context, req *LoginRequest) (*LoginRespo
 	}
 
 	// Audit login
+	loginAt := time.Now()
 	if s.auditService != nil {
 		s.auditService.LogUserAction(ctx, user.ID, "login", "user", user.ID, map[string]interface{}{
 			"username": user.Username,
+			"method":   "password",
 		})
 	}
+	s.recordLastLogin(ctx, user.ID)
+	s.notifyIfNewDevice(ctx, user.ID, loginAt)
 
 	username := ""
 	if user.Username != nil {
@@ -236,12 +264,15 @@ func (s *AuthService) LoginWithUsername(ctx context.Context, req *UsernameLoginR
 	// Verify password
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
 		s.logger.Warn("Login attempt with invalid password", zap.String("user_id", user.ID))
+		s.recordLoginFailure(ctx, user.ID, "password", "invalid_password")
 		return nil, errors.NewUnauthorizedError("invalid credentials")
 	}
+	s.hasher.RehashPasswordIfNeeded(ctx, s.userRepository, user, req.Password, s.logger)
 
 	// Check if user is active
 	if user.Status != nil && *user.Status != "active" {
 		s.logger.Warn("Login attempt for inactive user", zap.String("user_id", user.ID), zap.String("status", *user.Status))
+		s.recordLoginFailure(ctx, user.ID, "password", "account_not_active")
 		return nil, errors.NewUnauthorizedError("account is not active")
 	}
 
@@ -251,11 +282,23 @@ func (s *AuthService) LoginWithUsername(ctx context.Context, req *UsernameLoginR
 			s.logger.Warn("MFA validation failed",
 				zap.String("user_id", user.ID),
 				zap.Error(err))
+			s.recordLoginFailure(ctx, user.ID, "password", "invalid_mfa_code")
 			return nil, errors.NewUnauthorizedError("invalid MFA code")
 		}
 		s.logger.Info("MFA validation successful", zap.String("user_id", user.ID))
 	}
 
+	// Stepped login: a verified password is not enough for users with an MPIN
+	challenge, err := s.LoginStepUp(ctx, user.ID, "password", user.HasMPin())
+	if err != nil {
+		s.logger.Error("Failed to create login challenge", zap.String("user_id", user.ID), zap.Error(err))
+		return nil, err
+	}
+	if challenge != nil {
+		s.logger.Info("Login requires MPIN verification", zap.String("user_id", user.ID))
+		return &LoginResponse{Challenge: challenge}, nil
+	}
+
 	// Get user roles and permissions
 	userRoles, err := s.userRoleRepository.GetByUserID(ctx, user.ID)
 	if err != nil {
@@ -271,16 +314,10 @@ func (s *AuthService) LoginWithUsername(ctx context.Context, req *UsernameLoginR
 	}
 
 	// Generate tokens
-	accessToken, err := s.generateAccessToken(user, userRoles, permissions)
-	if err != nil {
-		s.logger.Error("Failed to generate access token", zap.String("user_id", user.ID), zap.Error(err))
-		return nil, errors.NewInternalError(fmt.Errorf("failed to generate access token: %w", err))
-	}
-
-	refreshToken, err := s.generateRefreshToken(user, userRoles, permissions)
+	accessToken, refreshToken, err := s.issueTokens(ctx, user, userRoles, permissions, "")
 	if err != nil {
-		s.logger.Error("Failed to generate refresh token", zap.String("user_id", user.ID), zap.Error(err))
-		return nil, errors.NewInternalError(fmt.Errorf("failed to generate refresh token: %w", err))
+		s.logger.Error("Failed to generate tokens", zap.String("user_id", user.ID), zap.Error(err))
+		return nil, err
 	}
 
 	// Store refresh token in cache
@@ -290,11 +327,15 @@ func (s *AuthService) LoginWithUsername(ctx context.Context, req *UsernameLoginR
 	}
 
 	// Audit login
+	loginAt := time.Now()
 	if s.auditService != nil {
 		s.auditService.LogUserAction(ctx, user.ID, "login", "user", user.ID, map[string]interface{}{
 			"username": user.Username,
+			"method":   "password",
 		})
 	}
+	s.recordLastLogin(ctx, user.ID)
+	s.notifyIfNewDevice(ctx, user.ID, loginAt)
 
 	username := ""
 	if user.Username != nil {
@@ -318,10 +359,15 @@ func (s *AuthService) Register(ctx context.Context, req *RegisterRequest) (*Logi
 		return nil, errors.NewValidationError("invalid registration request", err.Error())
 	}
 
+	phone, err := phonenumber.Parse(req.PhoneNumber, req.CountryCode)
+	if err != nil {
+		return nil, errors.NewValidationError("invalid phone number", err.Error())
+	}
+
 	// Check if user already exists by phone number
-	existingUser, err := s.userRepository.GetByPhoneNumber(ctx, req.PhoneNumber, req.CountryCode)
+	existingUser, err := s.userRepository.GetByPhoneNumber(ctx, phone.NationalNumber, phone.CountryCode)
 	if err == nil && existingUser != nil {
-		return nil, errors.NewConflictError("user with this phone number already exists")
+		return nil, errors.NewConflictError(fmt.Sprintf("user with phone number %s already exists", phone.E164()))
 	}
 
 	// Check if username is taken (if provided)
@@ -336,13 +382,14 @@ func (s *AuthService) Register(ctx context.Context, req *RegisterRequest) (*Logi
 	// In a production system, you'd need to add this method to the interface
 
 	// Hash password
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	hashedPassword, err := s.hasher.HashPassword(req.Password)
 	if err != nil {
 		return nil, errors.NewInternalError(fmt.Errorf("failed to hash password: %w", err))
 	}
 
 	// Create user using the model's constructor
-	user := models.NewUser(req.PhoneNumber, req.CountryCode, string(hashedPassword))
+	user := models.NewUser(phone.NationalNumber, phone.CountryCode, hashedPassword)
+	user.SetPhone(phone)
 	if req.Username != nil && *req.Username != "" {
 		user.Username = req.Username
 	}
@@ -445,6 +492,7 @@ func (s *AuthService) RefreshToken(ctx context.Context, refreshToken string, mPi
 		s.logger.Warn("Invalid mPin during token refresh", zap.String("user_id", user.ID))
 		return nil, errors.NewUnauthorizedError("invalid mPin")
 	}
+	s.hasher.RehashMPinIfNeeded(ctx, s.userRepository, user, mPin, s.logger)
 
 	// Check if user is still active
 	if user.Status != nil && *user.Status != "active" {
@@ -464,15 +512,14 @@ func (s *AuthService) RefreshToken(ctx context.Context, refreshToken string, mPi
 		return nil, errors.NewInternalError(fmt.Errorf("failed to retrieve user permissions: %w", err))
 	}
 
-	// Generate new tokens
-	accessToken, err := s.generateAccessToken(user, userRoles, permissions)
+	// Generate new tokens in the family of the refresh token they replace
+	familyID, err := s.RotateRefreshToken(ctx, user.ID, issuedTokenOf(claims))
 	if err != nil {
-		return nil, errors.NewInternalError(fmt.Errorf("failed to generate new access token: %w", err))
+		return nil, err
 	}
-
-	newRefreshToken, err := s.generateRefreshToken(user, userRoles, permissions)
+	accessToken, newRefreshToken, err := s.issueTokens(ctx, user, userRoles, permissions, familyID)
 	if err != nil {
-		return nil, errors.NewInternalError(fmt.Errorf("failed to generate new refresh token: %w", err))
+		return nil, err
 	}
 
 	// Update refresh token in cache
@@ -490,6 +537,158 @@ func (s *AuthService) RefreshToken(ctx context.Context, refreshToken string, mPi
 	}, nil
 }
 
+// IssueTokensForUser issues an access/refresh token pair for a user that has already been
+// authenticated by other means (for example an external OIDC identity provider).
+// The caller is responsible for verifying that the user is allowed to sign in.
+func (s *AuthService) IssueTokensForUser(ctx context.Context, user *models.User) (*LoginResponse, error) {
+	userRoles, err := s.userRoleRepository.GetByUserID(ctx, user.ID)
+	if err != nil {
+		s.logger.Error("Failed to get user roles", zap.String("user_id", user.ID), zap.Error(err))
+		return nil, errors.NewInternalError(fmt.Errorf("failed to retrieve user roles: %w", err))
+	}
+
+	permissions, err := s.authzService.GetUserPermissions(ctx, user.ID)
+	if err != nil {
+		s.logger.Error("Failed to get user permissions", zap.String("user_id", user.ID), zap.Error(err))
+		return nil, errors.NewInternalError(fmt.Errorf("failed to retrieve user permissions: %w", err))
+	}
+
+	accessToken, refreshToken, err := s.issueTokens(ctx, user, userRoles, permissions, "")
+	if err != nil {
+		return nil, err
+	}
+
+	cacheKey := fmt.Sprintf("refresh_token:%s", user.ID)
+	if err := s.cacheService.Set(cacheKey, refreshToken, int(s.refreshExpiry.Seconds())); err != nil {
+		s.logger.Warn("Failed to cache refresh token", zap.String("user_id", user.ID), zap.Error(err))
+	}
+
+	s.recordLastLogin(ctx, user.ID)
+
+	return &LoginResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(s.tokenExpiry.Seconds()),
+		User:         *user,
+		Permissions:  permissions,
+	}, nil
+}
+
+// recordLastLogin updates the user's last-login time and IP in the background
+// so the extra write does not delay the login response
+func (s *AuthService) recordLastLogin(ctx context.Context, userID string) {
+	ipAddress, _ := ctx.Value("ip_address").(string)
+
+	go func() {
+		updateCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if err := s.userRepository.UpdateLastLogin(updateCtx, userID, ipAddress); err != nil {
+			s.logger.Warn("Failed to update last login", zap.String("user_id", userID), zap.Error(err))
+		}
+	}()
+}
+
+// SetCredentialHasher sets the hasher, and with it the bcrypt costs, used for passwords and MPINs
+func (s *AuthService) SetCredentialHasher(hasher *security.CredentialHasher) {
+	s.hasher = hasher
+}
+
+// SetSecurityNotifier injects the notifier that tells users about security events on their account
+func (s *AuthService) SetSecurityNotifier(notifier interfaces.SecurityNotifier) {
+	s.notifier = notifier
+}
+
+// notifySecurityEvent notifies the user about a security event without waiting for delivery
+func (s *AuthService) notifySecurityEvent(ctx context.Context, eventType, userID string, details map[string]interface{}) {
+	if s.notifier == nil {
+		return
+	}
+	s.notifier.NotifySecurityEvent(ctx, notifications.EventFromContext(ctx, eventType, userID, details))
+}
+
+// notifyIfNewDevice notifies the user when they sign in from a device (user agent) with no earlier
+// successful sign-in before loginAt in their login history. The history lookup runs in the
+// background; a first sign-in is not reported.
+func (s *AuthService) notifyIfNewDevice(ctx context.Context, userID string, loginAt time.Time) {
+	if s.notifier == nil || s.auditService == nil {
+		return
+	}
+	event := notifications.EventFromContext(ctx, interfaces.SecurityEventNewDeviceLogin, userID, nil)
+	if event.UserAgent == "" {
+		return
+	}
+	event.OccurredAt = loginAt
+
+	go func() {
+		historyCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		history, err := s.auditService.GetLoginHistory(historyCtx, userID, 100)
+		if err != nil {
+			s.logger.Warn("Failed to load login history for new device check", zap.String("user_id", userID), zap.Error(err))
+			return
+		}
+		if isNewDevice(history, event.UserAgent, loginAt) {
+			s.notifier.NotifySecurityEvent(historyCtx, event)
+		}
+	}()
+}
+
+// isNewDevice reports whether a sign-in at loginAt from userAgent follows earlier successful
+// sign-ins, none of them from that user agent
+func isNewDevice(history []LoginHistoryEntry, userAgent string, loginAt time.Time) bool {
+	earlierSignIn := false
+	for _, entry := range history {
+		if !entry.Success || !entry.Timestamp.Before(loginAt) {
+			continue
+		}
+		if entry.UserAgent == userAgent {
+			return false
+		}
+		earlierSignIn = true
+	}
+	return earlierSignIn
+}
+
+// recordLoginFailure audits a failed sign-in against a known account so it shows up in the owner's login history
+func (s *AuthService) recordLoginFailure(ctx context.Context, userID, method, reason string) {
+	if s.auditService == nil {
+		return
+	}
+	s.auditService.LogUserActionWithError(ctx, userID, "login", "user", userID, fmt.Errorf("login failed: %s", reason), map[string]interface{}{
+		"method":         method,
+		"failure_reason": reason,
+	})
+}
+
+// RecordLogin audits a sign-in completed by the HTTP login endpoints, updates the user's last login
+// and notifies the user when it came from a new device
+func (s *AuthService) RecordLogin(ctx context.Context, userID, method string) {
+	loginAt := time.Now()
+	if s.auditService != nil {
+		s.auditService.LogUserAction(ctx, userID, "login", "user", userID, map[string]interface{}{
+			"method": method,
+		})
+	}
+	s.recordLastLogin(ctx, userID)
+	s.notifyIfNewDevice(ctx, userID, loginAt)
+}
+
+// RecordLoginFailure audits a failed sign-in by phone number against the account holding that
+// number. Failures for numbers without an account are not recorded.
+func (s *AuthService) RecordLoginFailure(ctx context.Context, phoneNumber, countryCode, method, reason string) {
+	if s.auditService == nil {
+		return
+	}
+	user, err := s.userRepository.GetByPhoneNumber(ctx, phoneNumber, countryCode)
+	if err != nil || user == nil {
+		return
+	}
+	s.recordLoginFailure(ctx, user.ID, method, reason)
+}
+
 // Logout invalidates user tokens
 func (s *AuthService) Logout(ctx context.Context, userID string) error {
 	// Remove refresh token from cache
@@ -533,6 +732,7 @@ func (s *AuthService) generateAccessToken(user *models.User, roles []*models.Use
 		Permissions: permissions,
 		TokenType:   "access",
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        newTokenID(),
 			ExpiresAt: &jwt.NumericDate{Time: exp},
 			IssuedAt:  &jwt.NumericDate{Time: iat},
 			NotBefore: &jwt.NumericDate{Time: nbf},
@@ -542,8 +742,7 @@ func (s *AuthService) generateAccessToken(user *models.User, roles []*models.Use
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(s.jwtCfg.Secret))
+	return s.signToken(claims)
 }
 
 // generateRefreshToken generates a JWT refresh token
@@ -565,6 +764,7 @@ func (s *AuthService) generateRefreshToken(user *models.User, roles []*models.Us
 		Permissions: permissions,
 		TokenType:   "refresh",
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        newTokenID(),
 			ExpiresAt: &jwt.NumericDate{Time: exp},
 			IssuedAt:  &jwt.NumericDate{Time: iat},
 			NotBefore: &jwt.NumericDate{Time: nbf},
@@ -574,19 +774,12 @@ func (s *AuthService) generateRefreshToken(user *models.User, roles []*models.Us
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(s.jwtCfg.Secret))
+	return s.signToken(claims)
 }
 
 // validateToken validates a JWT token and returns claims
 func (s *AuthService) validateToken(tokenString string) (*TokenClaims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &TokenClaims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		// Use the same secret as token generation (jwtCfg.Secret)
-		return []byte(s.jwtCfg.Secret), nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, &TokenClaims{}, s.tokenKey)
 
 	if err != nil {
 		return nil, err
@@ -750,13 +943,13 @@ func (s *AuthService) SetMPin(ctx context.Context, userID string, mPin string, c
 	}
 
 	// Hash the mPin
-	hashedMPin, err := bcrypt.GenerateFromPassword([]byte(mPin), bcrypt.DefaultCost)
+	hashedMPin, err := s.hasher.HashMPin(mPin)
 	if err != nil {
 		return errors.NewInternalError(fmt.Errorf("failed to hash mPin: %w", err))
 	}
 
 	// Update user's mPin
-	user.SetMPin(string(hashedMPin))
+	user.SetMPin(hashedMPin)
 	if err := s.userRepository.Update(ctx, user); err != nil {
 		return errors.NewInternalError(fmt.Errorf("failed to update mPin: %w", err))
 	}
@@ -768,6 +961,8 @@ func (s *AuthService) SetMPin(ctx context.Context, userID string, mPin string, c
 		})
 	}
 
+	s.notifySecurityEvent(ctx, interfaces.SecurityEventMPinChanged, userID, map[string]interface{}{"change": "set"})
+
 	s.logger.Info("mPin set successfully", zap.String("user_id", userID))
 	return nil
 }
@@ -798,13 +993,13 @@ func (s *AuthService) UpdateMPin(ctx context.Context, userID string, currentMPin
 	}
 
 	// Hash the new mPin
-	hashedMPin, err := bcrypt.GenerateFromPassword([]byte(newMPin), bcrypt.DefaultCost)
+	hashedMPin, err := s.hasher.HashMPin(newMPin)
 	if err != nil {
 		return errors.NewInternalError(fmt.Errorf("failed to hash new mPin: %w", err))
 	}
 
 	// Update user's mPin
-	user.SetMPin(string(hashedMPin))
+	user.SetMPin(hashedMPin)
 	if err := s.userRepository.Update(ctx, user); err != nil {
 		return errors.NewInternalError(fmt.Errorf("failed to update mPin: %w", err))
 	}
@@ -816,6 +1011,8 @@ func (s *AuthService) UpdateMPin(ctx context.Context, userID string, currentMPin
 		})
 	}
 
+	s.notifySecurityEvent(ctx, interfaces.SecurityEventMPinChanged, userID, map[string]interface{}{"change": "update"})
+
 	s.logger.Info("mPin updated successfully", zap.String("user_id", userID))
 	return nil
 }
@@ -844,6 +1041,7 @@ func (s *AuthService) VerifyMPin(ctx context.Context, userID string, mPin string
 		s.logger.Warn("Invalid mPin verification attempt", zap.String("user_id", userID))
 		return errors.NewUnauthorizedError("invalid mPin")
 	}
+	s.hasher.RehashMPinIfNeeded(ctx, s.userRepository, user, mPin, s.logger)
 
 	return nil
 }
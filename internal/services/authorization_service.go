@@ -53,6 +53,8 @@ type Permission struct {
 	Resource   string `json:"resource"`
 	ResourceID string `json:"resource_id"`
 	Action     string `json:"action"`
+	// OrganizationID limits the check to global roles and roles of this organization when set
+	OrganizationID string `json:"organization_id,omitempty"`
 }
 
 // PermissionResult represents the result of a permission check
@@ -62,6 +64,29 @@ type PermissionResult struct {
 	Permissions      []string `json:"permissions,omitempty"`
 	DecisionID       string   `json:"decision_id,omitempty"`
 	ConsistencyToken string   `json:"consistency_token,omitempty"`
+	// Rule and RoleName record how the check was decided for auditing; responses don't expose them
+	Rule     string `json:"-"`
+	RoleName string `json:"-"`
+}
+
+// Rules that can decide a permission check
+const (
+	DecisionRuleResourcePermission = "resource_permission"
+	DecisionRuleRolePermission     = "role_permission"
+	DecisionRuleAdminRole          = "admin_role"
+)
+
+// PermissionDecision explains how a permission check was decided
+type PermissionDecision struct {
+	Allowed bool   `json:"allowed"`
+	Reason  string `json:"reason"`
+	// Rule is the kind of rule that decided the check; empty when nothing matched
+	Rule     string `json:"rule,omitempty"`
+	RoleName string `json:"role_name,omitempty"`
+	// MatchedGrant is the winning resource permission when Rule is resource_permission
+	MatchedGrant *GrantMatch `json:"matched_grant,omitempty"`
+	// Candidates lists every matching resource permission in precedence order
+	Candidates []GrantMatch `json:"candidates,omitempty"`
 }
 
 // BulkPermissionRequest represents a bulk permission check request
@@ -80,6 +105,19 @@ func (s *AuthorizationService) CheckPermission(ctx context.Context, perm *Permis
 	return s.postgresAuth.CheckPermission(ctx, perm)
 }
 
+// CanPerform is the minimal permission check for callers that only need the decision. Its result
+// is cached for CanPerformCacheTTL, which is shorter than the CheckPermission cache, and denials
+// are not audited since the caller enforces them.
+func (s *AuthorizationService) CanPerform(ctx context.Context, perm *Permission) (bool, error) {
+	return s.postgresAuth.CanPerform(ctx, perm)
+}
+
+// ExplainPermission evaluates a permission check without the cache and reports which rule
+// decided it, including every matching resource permission in precedence order
+func (s *AuthorizationService) ExplainPermission(ctx context.Context, perm *Permission) (*PermissionDecision, error) {
+	return s.postgresAuth.evaluatePermission(ctx, perm)
+}
+
 // CheckBulkPermissions checks multiple permissions for a user
 func (s *AuthorizationService) CheckBulkPermissions(ctx context.Context, req *BulkPermissionRequest) (*BulkPermissionResult, error) {
 	return s.postgresAuth.CheckBulkPermissions(ctx, req)
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/go-redis/redis/v8"
+)
+
+// localCacheMu serializes the read-modify-write fallbacks below on caches that are not backed by
+// Redis. Such caches live in this process, so a process-wide lock makes the updates atomic.
+var localCacheMu sync.Mutex
+
+// incrementCounterScript increments a counter and starts its expiry when it is created
+var incrementCounterScript = redis.NewScript(`
+local count = redis.call('INCR', KEYS[1])
+if count == 1 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[1])
+end
+return count
+`)
+
+// incrementCounter atomically increments the counter at key and returns its new value. The counter
+// expires ttl after it is created.
+func incrementCounter(ctx context.Context, cache interfaces.CacheService, key string, ttl time.Duration) (int64, error) {
+	if redisCache, ok := redisCacheOf(cache); ok {
+		return incrementCounterScript.Run(ctx, redisCache.client, []string{key}, ttl.Milliseconds()).Int64()
+	}
+
+	localCacheMu.Lock()
+	defer localCacheMu.Unlock()
+	var count int64
+	if value, ok := cache.Get(key); ok {
+		if s, ok := value.(string); ok {
+			count, _ = strconv.ParseInt(s, 10, 64)
+		}
+	}
+	count++
+	if err := cache.Set(key, strconv.FormatInt(count, 10), int(ttl.Seconds())); err != nil {
+		return 0, fmt.Errorf("failed to store counter: %w", err)
+	}
+	return count, nil
+}
+
+// takeValueScript reads and deletes a key in one step, so a value can be taken only once
+var takeValueScript = redis.NewScript(`
+local value = redis.call('GET', KEYS[1])
+if value then
+	redis.call('DEL', KEYS[1])
+end
+return value
+`)
+
+// takeValue atomically reads and deletes the value at key, so of concurrent callers only one gets
+// it. The value is decoded as the cache's Get would decode it.
+func takeValue(ctx context.Context, cache interfaces.CacheService, key string) (interface{}, bool, error) {
+	if redisCache, ok := redisCacheOf(cache); ok {
+		raw, err := takeValueScript.Run(ctx, redisCache.client, []string{key}).Text()
+		if err == redis.Nil {
+			return nil, false, nil
+		}
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to take value: %w", err)
+		}
+		// a tiered cache may still hold the value in its local tier
+		if err := cache.Delete(key); err != nil {
+			return nil, false, fmt.Errorf("failed to delete taken value: %w", err)
+		}
+		var value interface{}
+		if err := json.Unmarshal([]byte(raw), &value); err != nil {
+			return raw, true, nil
+		}
+		return value, true, nil
+	}
+
+	localCacheMu.Lock()
+	defer localCacheMu.Unlock()
+	value, ok := cache.Get(key)
+	if !ok {
+		return nil, false, nil
+	}
+	if err := cache.Delete(key); err != nil {
+		return nil, false, fmt.Errorf("failed to delete taken value: %w", err)
+	}
+	return value, true, nil
+}
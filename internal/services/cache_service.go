@@ -3,6 +3,7 @@ package services
 import (
 	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
@@ -12,6 +13,9 @@ import (
 	"golang.org/x/net/context"
 )
 
+// errUnencodableValue marks a value that cannot be cached, which says nothing about Redis
+var errUnencodableValue = errors.New("value cannot be encoded")
+
 // CacheService implements the CacheService interface using Redis
 type CacheService struct {
 	client *redis.Client
@@ -78,24 +82,33 @@ func (c *CacheService) Get(key string) (interface{}, bool) {
 		return nil, false // Return cache miss when Redis is not available
 	}
 
-	val, err := c.client.Get(ctx, key).Result()
+	value, exists, err := c.GetChecked(key)
+	if err != nil {
+		c.logger.Error("Failed to get from cache", zap.String("key", key), zap.Error(err))
+		return nil, false
+	}
+	return value, exists
+}
+
+// GetChecked retrieves a value from cache, reporting a failed read as an error rather than a miss
+func (c *CacheService) GetChecked(key string) (interface{}, bool, error) {
+	val, err := c.client.Get(context.Background(), key).Result()
 	if err != nil {
 		if err == redis.Nil {
 			// Key doesn't exist
-			return nil, false
+			return nil, false, nil
 		}
-		c.logger.Error("Failed to get from cache", zap.String("key", key), zap.Error(err))
-		return nil, false
+		return nil, false, fmt.Errorf("failed to get from cache: %w", err)
 	}
 
 	// Try to unmarshal JSON
 	var result interface{}
 	if err := json.Unmarshal([]byte(val), &result); err != nil {
 		// If JSON unmarshal fails, return as string
-		return val, true
+		return val, true, nil
 	}
 
-	return result, true
+	return result, true, nil
 }
 
 // Set stores a value in cache with TTL
@@ -108,15 +121,20 @@ func (c *CacheService) Set(key string, value interface{}, ttl int) error {
 		return nil // Don't fail the operation, just skip caching
 	}
 
+	return c.SetChecked(key, value, ttl)
+}
+
+// SetChecked stores a value in cache with TTL without first checking that Redis is reachable
+func (c *CacheService) SetChecked(key string, value interface{}, ttl int) error {
 	// Marshal value to JSON
 	data, err := json.Marshal(value)
 	if err != nil {
 		c.logger.Error("Failed to marshal value for cache", zap.String("key", key), zap.Error(err))
-		return fmt.Errorf("failed to marshal value: %w", err)
+		return fmt.Errorf("failed to marshal value: %w: %w", errUnencodableValue, err)
 	}
 
 	duration := time.Duration(ttl) * time.Second
-	if err := c.client.Set(ctx, key, data, duration).Err(); err != nil {
+	if err := c.client.Set(context.Background(), key, data, duration).Err(); err != nil {
 		c.logger.Error("Failed to set cache", zap.String("key", key), zap.Error(err))
 		return fmt.Errorf("failed to set cache: %w", err)
 	}
@@ -134,7 +152,12 @@ func (c *CacheService) Delete(key string) error {
 		return nil // Don't fail the operation, just skip caching
 	}
 
-	if err := c.client.Del(ctx, key).Err(); err != nil {
+	return c.DeleteChecked(key)
+}
+
+// DeleteChecked removes a key from cache without first checking that Redis is reachable
+func (c *CacheService) DeleteChecked(key string) error {
+	if err := c.client.Del(context.Background(), key).Err(); err != nil {
 		c.logger.Error("Failed to delete from cache", zap.String("key", key), zap.Error(err))
 		return fmt.Errorf("failed to delete from cache: %w", err)
 	}
@@ -202,6 +225,14 @@ func (c *CacheService) TTL(key string) (int, error) {
 	return int(duration.Seconds()), nil
 }
 
+// Ping checks that the Redis server is reachable
+func (c *CacheService) Ping(ctx context.Context) error {
+	if err := c.client.Ping(ctx).Err(); err != nil {
+		return fmt.Errorf("redis ping failed: %w", err)
+	}
+	return nil
+}
+
 // Close closes the cache connection
 func (c *CacheService) Close() error {
 	if err := c.client.Close(); err != nil {
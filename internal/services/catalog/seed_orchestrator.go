@@ -47,7 +47,11 @@ func (so *SeedOrchestrator) getDB(ctx context.Context, readOnly bool) (*gorm.DB,
 	if postgresMgr, ok := so.dbManager.(interface {
 		GetDB(context.Context, bool) (*gorm.DB, error)
 	}); ok {
-		return postgresMgr.GetDB(ctx, readOnly)
+		gormDB, err := postgresMgr.GetDB(ctx, readOnly)
+		if err != nil {
+			return nil, err
+		}
+		return gormDB.WithContext(ctx), nil
 	}
 
 	return nil, fmt.Errorf("database manager does not support GetDB method")
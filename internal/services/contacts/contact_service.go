@@ -9,6 +9,7 @@ import (
 	contactResponses "github.com/Kisanlink/aaa-service/v2/internal/entities/responses/contacts"
 	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
 	contactRepo "github.com/Kisanlink/aaa-service/v2/internal/repositories/contacts"
+	"github.com/Kisanlink/aaa-service/v2/internal/services/otp"
 	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
 	"go.uber.org/zap"
 )
@@ -19,6 +20,7 @@ type ContactService struct {
 	cache       interfaces.CacheService
 	logger      interfaces.Logger
 	validator   interfaces.Validator
+	otpService  *otp.Service // Optional: for contact verification
 }
 
 // NewContactService creates a new ContactService instance
@@ -0,0 +1,163 @@
+package contacts
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	contactResponses "github.com/Kisanlink/aaa-service/v2/internal/entities/responses/contacts"
+	"github.com/Kisanlink/aaa-service/v2/internal/services/otp"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/Kisanlink/aaa-service/v2/pkg/phonenumber"
+	"go.uber.org/zap"
+)
+
+// contactVerificationPurpose scopes the OTP challenges issued for contact verification
+const contactVerificationPurpose = "contact_verification"
+
+// SetOTPService enables contact verification with OTPs delivered to the contact
+func (s *ContactService) SetOTPService(otpService *otp.Service) {
+	s.otpService = otpService
+}
+
+// StartVerification sends a verification OTP to the contact. The OTP is valid even when delivery
+// fails; the response then reports it as not delivered and ResendVerification retries.
+func (s *ContactService) StartVerification(ctx context.Context, contactID string) (*contactResponses.ContactVerificationResponse, error) {
+	contact, err := s.verifiableContact(ctx, contactID)
+	if err != nil {
+		return nil, err
+	}
+
+	destination, err := verificationDestination(contact)
+	if err != nil {
+		return nil, err
+	}
+
+	challenge, err := s.otpService.Issue(ctx, contactVerificationPurpose, contact.ID, contact.Type, destination)
+	if err != nil {
+		s.logger.Error("Failed to issue contact verification OTP", zap.String("contact_id", contact.ID), zap.Error(err))
+		return nil, err
+	}
+
+	s.logger.Info("Contact verification OTP issued",
+		zap.String("contact_id", contact.ID),
+		zap.String("challenge_id", challenge.ID),
+		zap.Bool("delivered", challenge.Delivered))
+	return verificationResponse(challenge), nil
+}
+
+// ResendVerification sends the verification OTP of a pending challenge again
+func (s *ContactService) ResendVerification(ctx context.Context, contactID, challengeID string) (*contactResponses.ContactVerificationResponse, error) {
+	contact, err := s.verifiableContact(ctx, contactID)
+	if err != nil {
+		return nil, err
+	}
+
+	challenge, err := s.otpService.Resend(ctx, contactVerificationPurpose, contact.ID, challengeID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Contact verification OTP resent",
+		zap.String("contact_id", contact.ID),
+		zap.String("challenge_id", challenge.ID),
+		zap.Bool("delivered", challenge.Delivered))
+	return verificationResponse(challenge), nil
+}
+
+// ConfirmVerification checks the OTP sent to the contact and marks the contact verified
+func (s *ContactService) ConfirmVerification(ctx context.Context, contactID, challengeID, code, verifiedBy string) (*contactResponses.ContactResponse, error) {
+	contact, err := s.verifiableContact(ctx, contactID)
+	if err != nil {
+		return nil, err
+	}
+
+	challenge, err := s.otpService.Verify(ctx, contactVerificationPurpose, contact.ID, challengeID, code)
+	if err != nil {
+		s.logger.Warn("Contact verification OTP rejected", zap.String("contact_id", contact.ID), zap.Error(err))
+		return nil, err
+	}
+
+	// The contact must still have the value the OTP was sent to
+	if destination, err := verificationDestination(contact); err != nil || destination != challenge.Destination {
+		return nil, errors.NewConflictError("contact changed since the OTP was sent, request a new OTP")
+	}
+
+	contact.Verify(verifiedBy)
+	if err := s.contactRepo.Update(ctx, contact); err != nil {
+		s.logger.Error("Failed to mark contact verified", zap.String("contact_id", contact.ID), zap.Error(err))
+		return nil, errors.NewInternalError(err)
+	}
+
+	if err := s.cache.Delete(fmt.Sprintf("contact:%s", contact.ID)); err != nil {
+		s.logger.Warn("Failed to delete contact cache", zap.Error(err))
+	}
+
+	s.logger.Info("Contact verified", zap.String("contact_id", contact.ID), zap.String("verified_by", verifiedBy))
+	return &contactResponses.ContactResponse{
+		ID:          contact.ID,
+		UserID:      contact.UserID,
+		Type:        contact.Type,
+		Value:       contact.Value,
+		Description: contact.Description,
+		IsPrimary:   contact.IsPrimary,
+		IsActive:    contact.IsActive,
+		IsVerified:  contact.IsVerified,
+		VerifiedAt:  contact.VerifiedAt,
+		VerifiedBy:  contact.VerifiedBy,
+		CountryCode: contact.CountryCode,
+		CreatedAt:   contact.CreatedAt,
+		UpdatedAt:   contact.UpdatedAt,
+	}, nil
+}
+
+// verifiableContact loads an unverified contact whose type can receive OTPs
+func (s *ContactService) verifiableContact(ctx context.Context, contactID string) (*models.Contact, error) {
+	if s.otpService == nil {
+		return nil, errors.NewBadRequestError("contact verification is not configured")
+	}
+
+	contact, err := s.contactRepo.GetByID(ctx, contactID)
+	if err != nil || contact == nil {
+		return nil, errors.NewNotFoundError("contact not found")
+	}
+	if contact.IsVerified {
+		return nil, errors.NewConflictError("contact is already verified")
+	}
+	if !s.otpService.CanDeliver(contact.Type) {
+		return nil, errors.NewValidationError(fmt.Sprintf("contacts of type %q cannot be verified by OTP", contact.Type))
+	}
+	return contact, nil
+}
+
+// verificationDestination returns where the contact's OTP is sent: the address of an email contact,
+// or the E.164 form of a phone number
+func verificationDestination(contact *models.Contact) (string, error) {
+	if contact.IsEmail() {
+		return contact.Value, nil
+	}
+	countryCode := ""
+	if contact.CountryCode != nil {
+		countryCode = *contact.CountryCode
+	}
+	e164, err := phonenumber.Normalize(contact.Value, countryCode)
+	if err != nil {
+		return "", errors.NewValidationError("contact number is not a valid phone number", err.Error())
+	}
+	return e164, nil
+}
+
+func verificationResponse(challenge *otp.Challenge) *contactResponses.ContactVerificationResponse {
+	message := "Verification code sent"
+	if !challenge.Delivered {
+		message = "Verification code could not be delivered, request a resend"
+	}
+	return &contactResponses.ContactVerificationResponse{
+		ContactID:   challenge.SubjectID,
+		ChallengeID: challenge.ID,
+		Destination: otp.MaskDestination(challenge.Destination),
+		Delivered:   challenge.Delivered,
+		ExpiresAt:   challenge.ExpiresAt,
+		Message:     message,
+	}
+}
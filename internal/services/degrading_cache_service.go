@@ -0,0 +1,384 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/config"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"go.uber.org/zap"
+)
+
+// ErrCacheUnavailable is returned while the cache backend is unreachable and the operation cannot
+// be skipped, or the degradation policy fails closed
+var ErrCacheUnavailable = errors.New("cache unavailable")
+
+// Circuit breaker states
+const (
+	breakerClosed   = "closed"
+	breakerOpen     = "open"
+	breakerHalfOpen = "half_open"
+)
+
+const (
+	// cacheProbeTimeout bounds the ping that decides whether an open breaker closes again
+	cacheProbeTimeout = 2 * time.Second
+	// maxPendingCacheDeletes bounds the deletes remembered while the breaker is open
+	maxPendingCacheDeletes = 10000
+)
+
+// DegradingCacheService stops using a cache backend that keeps failing. After FailureThreshold
+// consecutive errors its circuit breaker opens: reads are served as misses so callers fall back to
+// the database, and the backend is not contacted again until ProbeInterval has passed and a ping
+// succeeds. Writes are skipped under the fail-open policy and rejected under fail-closed, where
+// RequireAvailable also rejects the operations that must not proceed without the cache. Deletes
+// that could not reach the backend are repeated before the breaker closes again, so invalidated
+// entries are not served afterwards. Opening and closing the breaker is audited.
+type DegradingCacheService struct {
+	backend      interfaces.CacheService
+	checked      interfaces.CheckedCache
+	config       config.CacheDegradationConfig
+	logger       interfaces.Logger
+	auditService interfaces.AuditService
+	now          func() time.Time
+
+	mu             sync.Mutex
+	state          string
+	failures       int
+	degradedSince  time.Time
+	nextProbe      time.Time
+	pendingDeletes map[string]struct{}
+
+	trips         int64
+	bypassedReads int64
+	rejected      int64
+}
+
+// NewDegradingCacheService wraps backend with a circuit breaker. backend is returned unchanged
+// when it cannot report its errors.
+func NewDegradingCacheService(backend interfaces.CacheService, cfg config.CacheDegradationConfig, logger interfaces.Logger) interfaces.CacheService {
+	checked, ok := backend.(interfaces.CheckedCache)
+	if !ok {
+		return backend
+	}
+	if cfg.FailureThreshold < 1 {
+		cfg.FailureThreshold = 1
+	}
+
+	logger.Info("Cache circuit breaker enabled",
+		zap.String("policy", cfg.Policy),
+		zap.Int("failure_threshold", cfg.FailureThreshold),
+		zap.Duration("probe_interval", cfg.ProbeInterval))
+
+	return &DegradingCacheService{
+		backend:        backend,
+		checked:        checked,
+		config:         cfg,
+		logger:         logger,
+		now:            time.Now,
+		state:          breakerClosed,
+		pendingDeletes: make(map[string]struct{}),
+	}
+}
+
+// DegradingCacheOf returns the circuit breaker of cache, looking behind an L1 tier
+func DegradingCacheOf(cache interfaces.CacheService) (*DegradingCacheService, bool) {
+	if tiered, ok := cache.(*TieredCacheService); ok {
+		cache = tiered.L2()
+	}
+	degrading, ok := cache.(*DegradingCacheService)
+	return degrading, ok
+}
+
+// redisCacheOf returns the Redis cache behind cache's L1 tier and circuit breaker
+func redisCacheOf(cache interfaces.CacheService) (*CacheService, bool) {
+	if tiered, ok := cache.(*TieredCacheService); ok {
+		cache = tiered.L2()
+	}
+	if degrading, ok := cache.(*DegradingCacheService); ok {
+		cache = degrading.backend
+	}
+	redisCache, ok := cache.(*CacheService)
+	return redisCache, ok
+}
+
+// SetAuditService records the breaker opening and closing in the audit log
+func (c *DegradingCacheService) SetAuditService(auditService interfaces.AuditService) {
+	c.auditService = auditService
+}
+
+// Get returns a miss without contacting the backend while the breaker is open
+func (c *DegradingCacheService) Get(key string) (interface{}, bool) {
+	if !c.allow() {
+		atomic.AddInt64(&c.bypassedReads, 1)
+		return nil, false
+	}
+
+	value, exists, err := c.checked.GetChecked(key)
+	if err != nil {
+		c.recordFailure(err)
+		atomic.AddInt64(&c.bypassedReads, 1)
+		return nil, false
+	}
+	c.recordSuccess()
+	return value, exists
+}
+
+// Set skips or, failing closed, rejects the write while the breaker is open
+func (c *DegradingCacheService) Set(key string, value interface{}, ttl int) error {
+	if !c.allow() {
+		return c.skipWrite()
+	}
+	return c.record(c.checked.SetChecked(key, value, ttl))
+}
+
+// Delete remembers the key while the backend is unreachable and deletes it once it recovers
+func (c *DegradingCacheService) Delete(key string) error {
+	if !c.allow() {
+		c.rememberDelete(key)
+		return c.skipWrite()
+	}
+	err := c.record(c.checked.DeleteChecked(key))
+	if err != nil {
+		c.rememberDelete(key)
+	}
+	return err
+}
+
+// Exists reports false while the breaker is open
+func (c *DegradingCacheService) Exists(key string) bool {
+	if !c.allow() {
+		return false
+	}
+	return c.backend.Exists(key)
+}
+
+// Clear fails while the breaker is open
+func (c *DegradingCacheService) Clear() error {
+	if !c.allow() {
+		return ErrCacheUnavailable
+	}
+	return c.record(c.backend.Clear())
+}
+
+// Keys fails while the breaker is open; an empty result would silently skip pattern invalidations
+func (c *DegradingCacheService) Keys(pattern string) ([]string, error) {
+	if !c.allow() {
+		return nil, ErrCacheUnavailable
+	}
+	keys, err := c.backend.Keys(pattern)
+	return keys, c.record(err)
+}
+
+// Expire fails while the breaker is open
+func (c *DegradingCacheService) Expire(key string, ttl int) error {
+	if !c.allow() {
+		return ErrCacheUnavailable
+	}
+	return c.record(c.backend.Expire(key, ttl))
+}
+
+// TTL fails while the breaker is open
+func (c *DegradingCacheService) TTL(key string) (int, error) {
+	if !c.allow() {
+		return 0, ErrCacheUnavailable
+	}
+	ttl, err := c.backend.TTL(key)
+	return ttl, c.record(err)
+}
+
+// Ping checks the backend whatever the state of the breaker
+func (c *DegradingCacheService) Ping(ctx context.Context) error {
+	return c.checked.Ping(ctx)
+}
+
+// Close closes the backend
+func (c *DegradingCacheService) Close() error {
+	return c.backend.Close()
+}
+
+// RequireAvailable returns ErrCacheUnavailable while the breaker is open and the policy fails closed
+func (c *DegradingCacheService) RequireAvailable() error {
+	if c.config.Policy != config.CacheDegradationFailClosed || c.allow() {
+		return nil
+	}
+	atomic.AddInt64(&c.rejected, 1)
+	return ErrCacheUnavailable
+}
+
+// DegradationStats reports the state of the breaker and what it has bypassed or rejected
+func (c *DegradingCacheService) DegradationStats() interfaces.CacheDegradationStats {
+	c.mu.Lock()
+	stats := interfaces.CacheDegradationStats{
+		Policy:              c.config.Policy,
+		State:               c.state,
+		ConsecutiveFailures: c.failures,
+	}
+	if c.state != breakerClosed {
+		since := c.degradedSince
+		stats.DegradedSince = &since
+	}
+	c.mu.Unlock()
+
+	stats.Trips = atomic.LoadInt64(&c.trips)
+	stats.BypassedReads = atomic.LoadInt64(&c.bypassedReads)
+	stats.RejectedOperations = atomic.LoadInt64(&c.rejected)
+	return stats
+}
+
+// allow reports whether an operation may use the backend. Once ProbeInterval has passed, the
+// first caller to find the breaker open probes the backend while the others keep bypassing it.
+func (c *DegradingCacheService) allow() bool {
+	c.mu.Lock()
+	if c.state == breakerClosed {
+		c.mu.Unlock()
+		return true
+	}
+	if c.state == breakerHalfOpen || c.now().Before(c.nextProbe) {
+		c.mu.Unlock()
+		return false
+	}
+	c.state = breakerHalfOpen
+	c.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), cacheProbeTimeout)
+	defer cancel()
+	if err := c.checked.Ping(ctx); err != nil {
+		c.reopenBreaker()
+		c.logger.Warn("Cache still unavailable", zap.Error(err))
+		return false
+	}
+	if err := c.closeBreaker(); err != nil {
+		c.reopenBreaker()
+		c.logger.Warn("Cache still unavailable, failed to repeat the deletes missed during the outage", zap.Error(err))
+		return false
+	}
+	return true
+}
+
+// reopenBreaker returns a half-open breaker to open until the next probe
+func (c *DegradingCacheService) reopenBreaker() {
+	c.mu.Lock()
+	c.state = breakerOpen
+	c.nextProbe = c.now().Add(c.config.ProbeInterval)
+	c.mu.Unlock()
+}
+
+// record counts the outcome of a backend operation and returns its error
+func (c *DegradingCacheService) record(err error) error {
+	switch {
+	case err == nil:
+		c.recordSuccess()
+	case !errors.Is(err, errUnencodableValue):
+		c.recordFailure(err)
+	}
+	return err
+}
+
+func (c *DegradingCacheService) recordSuccess() {
+	c.mu.Lock()
+	c.failures = 0
+	c.mu.Unlock()
+}
+
+// recordFailure opens the breaker when the backend has failed FailureThreshold times in a row
+func (c *DegradingCacheService) recordFailure(cause error) {
+	c.mu.Lock()
+	c.failures++
+	if c.state != breakerClosed || c.failures < c.config.FailureThreshold {
+		c.mu.Unlock()
+		return
+	}
+	failures := c.failures
+	c.state = breakerOpen
+	c.degradedSince = c.now()
+	c.nextProbe = c.degradedSince.Add(c.config.ProbeInterval)
+	c.mu.Unlock()
+	atomic.AddInt64(&c.trips, 1)
+
+	c.logger.Error("Cache unavailable, bypassing it until it recovers",
+		zap.String("policy", c.config.Policy),
+		zap.Int("consecutive_failures", failures),
+		zap.Error(cause))
+	c.audit("cache_degraded", false, map[string]interface{}{
+		"policy":               c.config.Policy,
+		"consecutive_failures": failures,
+		"error":                cause.Error(),
+	})
+}
+
+// closeBreaker repeats the deletes missed while the breaker was open, then resumes using the
+// backend. The breaker stays half-open until every delete has succeeded, so no other caller reads
+// an entry that should have been invalidated; a failed delete stays pending for the next probe.
+func (c *DegradingCacheService) closeBreaker() error {
+	repeated := 0
+	for {
+		c.mu.Lock()
+		if len(c.pendingDeletes) == 0 {
+			degradedFor := c.now().Sub(c.degradedSince)
+			c.state = breakerClosed
+			c.failures = 0
+			c.mu.Unlock()
+
+			c.logger.Info("Cache recovered",
+				zap.Duration("degraded_for", degradedFor),
+				zap.Int("pending_deletes", repeated))
+			c.audit("cache_recovered", true, map[string]interface{}{
+				"policy":          c.config.Policy,
+				"degraded_for":    degradedFor.String(),
+				"pending_deletes": repeated,
+			})
+			return nil
+		}
+		pending := make([]string, 0, len(c.pendingDeletes))
+		for key := range c.pendingDeletes {
+			pending = append(pending, key)
+		}
+		c.mu.Unlock()
+
+		// Deletes missed while these are repeated are picked up by the next pass
+		for _, key := range pending {
+			if err := c.checked.DeleteChecked(key); err != nil {
+				return err
+			}
+			c.mu.Lock()
+			delete(c.pendingDeletes, key)
+			c.mu.Unlock()
+			repeated++
+		}
+	}
+}
+
+// rememberDelete keeps a delete that failed while the breaker is not closed for closeBreaker
+func (c *DegradingCacheService) rememberDelete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.state == breakerClosed {
+		return
+	}
+	if len(c.pendingDeletes) >= maxPendingCacheDeletes {
+		c.logger.Warn("Too many cache deletes missed while the cache is unavailable, dropping", zap.String("key", key))
+		return
+	}
+	c.pendingDeletes[key] = struct{}{}
+}
+
+// skipWrite returns the result of a write the breaker kept from the backend
+func (c *DegradingCacheService) skipWrite() error {
+	if c.config.Policy == config.CacheDegradationFailClosed {
+		atomic.AddInt64(&c.rejected, 1)
+		return ErrCacheUnavailable
+	}
+	return nil
+}
+
+func (c *DegradingCacheService) audit(action string, success bool, details map[string]interface{}) {
+	if c.auditService == nil {
+		return
+	}
+	c.auditService.LogSystemEvent(context.Background(), action, "cache", success, details)
+}
@@ -0,0 +1,212 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/config"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// outageCache is a cache backend that can be taken down, counting the calls that reach it
+type outageCache struct {
+	interfaces.CacheService
+	down        bool
+	failDeletes bool
+	data        map[string]interface{}
+	calls       int
+	pings       int
+}
+
+func newOutageCache() *outageCache {
+	return &outageCache{data: make(map[string]interface{})}
+}
+
+func (c *outageCache) GetChecked(key string) (interface{}, bool, error) {
+	c.calls++
+	if c.down {
+		return nil, false, errors.New("connection refused")
+	}
+	value, ok := c.data[key]
+	return value, ok, nil
+}
+
+func (c *outageCache) SetChecked(key string, value interface{}, ttl int) error {
+	c.calls++
+	if c.down {
+		return errors.New("connection refused")
+	}
+	c.data[key] = value
+	return nil
+}
+
+func (c *outageCache) DeleteChecked(key string) error {
+	c.calls++
+	if c.down || c.failDeletes {
+		return errors.New("connection refused")
+	}
+	delete(c.data, key)
+	return nil
+}
+
+func (c *outageCache) Ping(ctx context.Context) error {
+	c.pings++
+	if c.down {
+		return errors.New("connection refused")
+	}
+	return nil
+}
+
+// systemEventRecorder records the system events written to the audit log
+type systemEventRecorder struct {
+	interfaces.AuditService
+	actions []string
+}
+
+func (r *systemEventRecorder) LogSystemEvent(ctx context.Context, action, resource string, success bool, details map[string]interface{}) {
+	r.actions = append(r.actions, action)
+}
+
+// newDegradingTestCache returns a breaker that opens after two failures, and a clock to move it on
+func newDegradingTestCache(policy string) (*DegradingCacheService, *outageCache, *systemEventRecorder, *time.Time) {
+	backend := newOutageCache()
+	cache := NewDegradingCacheService(backend, config.CacheDegradationConfig{
+		Policy:           policy,
+		FailureThreshold: 2,
+		ProbeInterval:    10 * time.Second,
+	}, utils.NewLoggerAdapter(zap.NewNop())).(*DegradingCacheService)
+
+	audit := &systemEventRecorder{}
+	cache.SetAuditService(audit)
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache.now = func() time.Time { return now }
+	return cache, backend, audit, &now
+}
+
+// tripBreaker takes the backend down and fails enough reads to open the breaker
+func tripBreaker(t *testing.T, cache *DegradingCacheService, backend *outageCache) {
+	t.Helper()
+	backend.down = true
+	for i := 0; i < 2; i++ {
+		_, ok := cache.Get("user:1")
+		require.False(t, ok)
+	}
+	require.Equal(t, breakerOpen, cache.DegradationStats().State)
+}
+
+func TestDegradingCache_FailOpenBypassesDeadBackend(t *testing.T) {
+	cache, backend, audit, _ := newDegradingTestCache(config.CacheDegradationFailOpen)
+	require.NoError(t, cache.Set("user:1", "cached", 60))
+
+	tripBreaker(t, cache, backend)
+	assert.Equal(t, []string{"cache_degraded"}, audit.actions)
+
+	calls := backend.calls
+	_, ok := cache.Get("user:1")
+	assert.False(t, ok, "reads fall through to the database")
+	assert.NoError(t, cache.Set("user:1", "fresh", 60), "writes are skipped")
+	assert.NoError(t, cache.RequireAvailable(), "permission checks go on")
+	assert.Equal(t, calls, backend.calls, "the dead backend is not contacted")
+	assert.Zero(t, backend.pings, "the backend is not probed before the probe interval")
+
+	stats := cache.DegradationStats()
+	assert.Equal(t, int64(1), stats.Trips)
+	assert.Equal(t, int64(3), stats.BypassedReads)
+	assert.Zero(t, stats.RejectedOperations)
+	assert.NotNil(t, stats.DegradedSince)
+}
+
+func TestDegradingCache_FailClosedRejectsSensitiveOperations(t *testing.T) {
+	cache, backend, audit, _ := newDegradingTestCache(config.CacheDegradationFailClosed)
+	assert.NoError(t, cache.RequireAvailable())
+
+	tripBreaker(t, cache, backend)
+	assert.Equal(t, []string{"cache_degraded"}, audit.actions)
+
+	_, ok := cache.Get("user:1")
+	assert.False(t, ok, "reads still fall through to the database")
+	assert.ErrorIs(t, cache.Set("user:1", "fresh", 60), ErrCacheUnavailable)
+	assert.ErrorIs(t, cache.RequireAvailable(), ErrCacheUnavailable)
+
+	authz := NewPostgresAuthorizationService(nil, NewTieredCacheService(cache, L1CacheConfig{
+		Enabled: true, MaxEntries: 10, TTL: time.Second,
+	}, utils.NewLoggerAdapter(zap.NewNop())), nil, zap.NewNop())
+	_, err := authz.CheckPermission(context.Background(), &Permission{UserID: "USER1", Resource: "user", Action: "read"})
+	assert.ErrorIs(t, err, ErrCacheUnavailable, "the guard is found behind the L1 tier")
+	_, err = authz.CanPerform(context.Background(), &Permission{UserID: "USER1", Resource: "user", Action: "read"})
+	assert.ErrorIs(t, err, ErrCacheUnavailable)
+
+	assert.Equal(t, int64(4), cache.DegradationStats().RejectedOperations)
+}
+
+func TestDegradingCache_ProbesAndRecovers(t *testing.T) {
+	cache, backend, audit, now := newDegradingTestCache(config.CacheDegradationFailClosed)
+	require.NoError(t, cache.Set("permission:USER1:user", true, 60))
+
+	tripBreaker(t, cache, backend)
+	assert.ErrorIs(t, cache.Delete("permission:USER1:user"), ErrCacheUnavailable)
+
+	*now = now.Add(10 * time.Second)
+	assert.ErrorIs(t, cache.RequireAvailable(), ErrCacheUnavailable, "the backend is still down")
+	assert.Equal(t, 1, backend.pings)
+	assert.ErrorIs(t, cache.RequireAvailable(), ErrCacheUnavailable)
+	assert.Equal(t, 1, backend.pings, "probes wait for the next interval")
+
+	backend.down = false
+	*now = now.Add(10 * time.Second)
+	assert.NoError(t, cache.RequireAvailable())
+	assert.Equal(t, 2, backend.pings)
+	assert.Equal(t, breakerClosed, cache.DegradationStats().State)
+	assert.Nil(t, cache.DegradationStats().DegradedSince)
+	assert.Equal(t, []string{"cache_degraded", "cache_recovered"}, audit.actions)
+
+	_, ok := backend.data["permission:USER1:user"]
+	assert.False(t, ok, "the delete missed during the outage is repeated")
+	require.NoError(t, cache.Set("user:1", "fresh", 60))
+	value, ok := cache.Get("user:1")
+	assert.True(t, ok)
+	assert.Equal(t, "fresh", value)
+}
+
+func TestDegradingCache_StaysOpenUntilMissedDeletesSucceed(t *testing.T) {
+	cache, backend, audit, now := newDegradingTestCache(config.CacheDegradationFailOpen)
+	require.NoError(t, cache.Set("permission:USER1:user", true, 60))
+
+	tripBreaker(t, cache, backend)
+	require.NoError(t, cache.Delete("permission:USER1:user"))
+
+	backend.down = false
+	backend.failDeletes = true
+	*now = now.Add(10 * time.Second)
+	_, ok := cache.Get("permission:USER1:user")
+	assert.False(t, ok, "the stale entry is not served while its delete is pending")
+	assert.Equal(t, breakerOpen, cache.DegradationStats().State)
+	assert.Equal(t, []string{"cache_degraded"}, audit.actions)
+
+	backend.failDeletes = false
+	*now = now.Add(10 * time.Second)
+	_, ok = cache.Get("permission:USER1:user")
+	assert.False(t, ok, "the delete is repeated before the breaker closes")
+	assert.Equal(t, breakerClosed, cache.DegradationStats().State)
+	assert.Equal(t, []string{"cache_degraded", "cache_recovered"}, audit.actions)
+}
+
+func TestDegradingCache_IntermittentFailuresDoNotTrip(t *testing.T) {
+	cache, backend, audit, _ := newDegradingTestCache(config.CacheDegradationFailOpen)
+
+	for i := 0; i < 3; i++ {
+		backend.down = true
+		cache.Get("user:1")
+		backend.down = false
+		cache.Get("user:1")
+	}
+
+	assert.Equal(t, breakerClosed, cache.DegradationStats().State)
+	assert.Empty(t, audit.actions)
+}
@@ -0,0 +1,311 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	configPkg "github.com/Kisanlink/aaa-service/v2/internal/config"
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/responses"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// Remembered devices
+//
+// A login with remember_me opens a long-lived session for the device, separate from the
+// access/refresh token pair. The session token is exchanged for new tokens without credentials
+// until it expires or the user revokes the session. Sessions live in the cache:
+//
+//	device_session:<user id>:<session id>   session record (device, IP, last use, token hash)
+//	device_session_token:<token hash>       "<user id>:<session id>" of the token's session
+//
+// Only the SHA-256 hash of a session token is stored.
+
+const (
+	// DefaultDeviceSessionTTL is how long a remembered device stays signed in when not configured
+	DefaultDeviceSessionTTL = 30 * 24 * time.Hour
+	// DefaultMaxDeviceSessions is the number of remembered devices per user when not configured
+	DefaultMaxDeviceSessions = 10
+)
+
+// deviceSession is the cached state of a remembered device
+type deviceSession struct {
+	ID         string    `json:"id"`
+	UserID     string    `json:"user_id"`
+	DeviceName string    `json:"device_name,omitempty"`
+	UserAgent  string    `json:"user_agent,omitempty"`
+	IPAddress  string    `json:"ip_address,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	TokenHash  string    `json:"token_hash"`
+}
+
+// SetDeviceSessionConfig configures the sessions of remembered devices
+func (s *AuthService) SetDeviceSessionConfig(cfg configPkg.DeviceSessionConfig) {
+	s.sessions = cfg
+}
+
+// CreateDeviceSession remembers the device of a user who just logged in. The device's user agent
+// and IP address are taken from the request context. When the user already holds the maximum
+// number of sessions, the least recently used one is revoked.
+func (s *AuthService) CreateDeviceSession(ctx context.Context, userID, deviceName string) (*responses.DeviceSessionTokenResponse, error) {
+	if userID == "" {
+		return nil, errors.NewValidationError("user ID is required")
+	}
+
+	token, err := generateChallengeToken()
+	if err != nil {
+		return nil, errors.NewInternalError(fmt.Errorf("failed to generate device session: %w", err))
+	}
+	sessionID, err := generateChallengeToken()
+	if err != nil {
+		return nil, errors.NewInternalError(fmt.Errorf("failed to generate device session: %w", err))
+	}
+
+	userAgent, _ := ctx.Value("user_agent").(string)
+	ipAddress, _ := ctx.Value("ip_address").(string)
+
+	now := time.Now()
+	session := &deviceSession{
+		ID:         "DSES" + sessionID[:16],
+		UserID:     userID,
+		DeviceName: strings.TrimSpace(deviceName),
+		UserAgent:  userAgent,
+		IPAddress:  ipAddress,
+		CreatedAt:  now,
+		LastUsedAt: now,
+		ExpiresAt:  now.Add(s.deviceSessionTTL()),
+		TokenHash:  hashSessionToken(token),
+	}
+
+	s.evictDeviceSessions(userID, s.maxDeviceSessions()-1)
+
+	if err := s.saveDeviceSession(session); err != nil {
+		return nil, errors.NewInternalError(fmt.Errorf("failed to store device session: %w", err))
+	}
+	ttl := int(time.Until(session.ExpiresAt).Seconds())
+	if err := s.cacheService.Set(deviceSessionTokenKey(session.TokenHash), userID+":"+session.ID, ttl); err != nil {
+		_ = s.cacheService.Delete(deviceSessionKey(userID, session.ID))
+		return nil, errors.NewInternalError(fmt.Errorf("failed to store device session: %w", err))
+	}
+
+	s.logger.Info("Device session created",
+		zap.String("user_id", userID),
+		zap.String("session_id", session.ID),
+		zap.String("device_name", session.DeviceName))
+
+	return &responses.DeviceSessionTokenResponse{
+		SessionID:    session.ID,
+		SessionToken: token,
+		ExpiresAt:    session.ExpiresAt,
+	}, nil
+}
+
+// ExchangeDeviceSession checks a session token and returns the ID of the user to issue new tokens
+// for. The session records the use; its expiry does not move. Sessions of users who are no longer
+// active are revoked. Every failure is reported as unauthorized.
+func (s *AuthService) ExchangeDeviceSession(ctx context.Context, sessionToken string) (string, error) {
+	if sessionToken == "" {
+		return "", errors.NewValidationError("session token is required")
+	}
+
+	tokenHash := hashSessionToken(sessionToken)
+	value, ok := s.cacheService.Get(deviceSessionTokenKey(tokenHash))
+	ref, isString := value.(string)
+	if !ok || !isString {
+		return "", errors.NewUnauthorizedError("session expired or revoked")
+	}
+	userID, sessionID, found := strings.Cut(ref, ":")
+	if !found {
+		return "", errors.NewUnauthorizedError("session expired or revoked")
+	}
+
+	session, ok := s.loadDeviceSession(deviceSessionKey(userID, sessionID))
+	if !ok || session.TokenHash != tokenHash || !time.Now().Before(session.ExpiresAt) {
+		s.deleteDeviceSession(userID, sessionID, tokenHash)
+		return "", errors.NewUnauthorizedError("session expired or revoked")
+	}
+
+	user, err := s.userRepository.GetByID(ctx, userID, &models.User{})
+	if err != nil || user == nil || user.DeletedAt != nil || !user.IsActive() {
+		s.deleteDeviceSession(userID, sessionID, tokenHash)
+		s.logger.Warn("Device session revoked for inactive user",
+			zap.String("user_id", userID),
+			zap.String("session_id", sessionID))
+		return "", errors.NewUnauthorizedError("session expired or revoked")
+	}
+
+	session.LastUsedAt = time.Now()
+	if ipAddress, _ := ctx.Value("ip_address").(string); ipAddress != "" {
+		session.IPAddress = ipAddress
+	}
+	if userAgent, _ := ctx.Value("user_agent").(string); userAgent != "" {
+		session.UserAgent = userAgent
+	}
+	if err := s.saveDeviceSession(session); err != nil {
+		s.logger.Warn("Failed to record device session use",
+			zap.String("user_id", userID),
+			zap.String("session_id", sessionID),
+			zap.Error(err))
+	}
+
+	s.logger.Info("Device session exchanged", zap.String("user_id", userID), zap.String("session_id", sessionID))
+	return userID, nil
+}
+
+// ListDeviceSessions returns the remembered devices of a user, most recently used first
+func (s *AuthService) ListDeviceSessions(ctx context.Context, userID string) ([]*responses.DeviceSessionResponse, error) {
+	if userID == "" {
+		return nil, errors.NewValidationError("user ID is required")
+	}
+
+	sessions, err := s.userDeviceSessions(userID)
+	if err != nil {
+		return nil, errors.NewInternalError(fmt.Errorf("failed to list device sessions: %w", err))
+	}
+
+	result := make([]*responses.DeviceSessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		result = append(result, &responses.DeviceSessionResponse{
+			ID:         session.ID,
+			DeviceName: session.DeviceName,
+			UserAgent:  session.UserAgent,
+			IPAddress:  session.IPAddress,
+			CreatedAt:  session.CreatedAt,
+			LastUsedAt: session.LastUsedAt,
+			ExpiresAt:  session.ExpiresAt,
+		})
+	}
+	return result, nil
+}
+
+// RevokeDeviceSession signs a remembered device out. Users can only revoke their own sessions;
+// the session of another user is reported as not found.
+func (s *AuthService) RevokeDeviceSession(ctx context.Context, userID, sessionID string) error {
+	if userID == "" || sessionID == "" {
+		return errors.NewValidationError("user ID and session ID are required")
+	}
+
+	session, ok := s.loadDeviceSession(deviceSessionKey(userID, sessionID))
+	if !ok || session.UserID != userID {
+		return errors.NewNotFoundError("session not found")
+	}
+
+	s.deleteDeviceSession(userID, session.ID, session.TokenHash)
+	s.logger.Info("Device session revoked", zap.String("user_id", userID), zap.String("session_id", sessionID))
+	return nil
+}
+
+// userDeviceSessions loads the unexpired sessions of a user, most recently used first
+func (s *AuthService) userDeviceSessions(userID string) ([]*deviceSession, error) {
+	keys, err := s.cacheService.Keys(deviceSessionKey(userID, "*"))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	sessions := make([]*deviceSession, 0, len(keys))
+	for _, key := range keys {
+		session, ok := s.loadDeviceSession(key)
+		if !ok || session.UserID != userID || !now.Before(session.ExpiresAt) {
+			continue
+		}
+		sessions = append(sessions, session)
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LastUsedAt.After(sessions[j].LastUsedAt)
+	})
+	return sessions, nil
+}
+
+// evictDeviceSessions revokes the least recently used sessions of a user until at most keep remain
+func (s *AuthService) evictDeviceSessions(userID string, keep int) {
+	sessions, err := s.userDeviceSessions(userID)
+	if err != nil {
+		s.logger.Warn("Failed to load device sessions for eviction", zap.String("user_id", userID), zap.Error(err))
+		return
+	}
+	if keep < 0 {
+		keep = 0
+	}
+	for i := keep; i < len(sessions); i++ {
+		s.deleteDeviceSession(userID, sessions[i].ID, sessions[i].TokenHash)
+		s.logger.Info("Device session evicted",
+			zap.String("user_id", userID),
+			zap.String("session_id", sessions[i].ID))
+	}
+}
+
+func (s *AuthService) deleteDeviceSession(userID, sessionID, tokenHash string) {
+	if err := s.cacheService.Delete(deviceSessionTokenKey(tokenHash)); err != nil {
+		s.logger.Warn("Failed to delete device session token", zap.String("session_id", sessionID), zap.Error(err))
+	}
+	if err := s.cacheService.Delete(deviceSessionKey(userID, sessionID)); err != nil {
+		s.logger.Warn("Failed to delete device session", zap.String("session_id", sessionID), zap.Error(err))
+	}
+}
+
+func (s *AuthService) deviceSessionTTL() time.Duration {
+	if s.sessions.TTL > 0 {
+		return s.sessions.TTL
+	}
+	return DefaultDeviceSessionTTL
+}
+
+func (s *AuthService) maxDeviceSessions() int {
+	if s.sessions.MaxPerUser > 0 {
+		return s.sessions.MaxPerUser
+	}
+	return DefaultMaxDeviceSessions
+}
+
+// saveDeviceSession caches the session until it expires, as a JSON string like the login challenges
+func (s *AuthService) saveDeviceSession(session *deviceSession) error {
+	key := deviceSessionKey(session.UserID, session.ID)
+	ttl := int(time.Until(session.ExpiresAt).Seconds())
+	if ttl <= 0 {
+		return s.cacheService.Delete(key)
+	}
+	data, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+	return s.cacheService.Set(key, string(data), ttl)
+}
+
+func (s *AuthService) loadDeviceSession(key string) (*deviceSession, bool) {
+	value, ok := s.cacheService.Get(key)
+	if !ok {
+		return nil, false
+	}
+	data, ok := value.(string)
+	if !ok {
+		return nil, false
+	}
+	var session deviceSession
+	if err := json.Unmarshal([]byte(data), &session); err != nil || session.UserID == "" || session.ID == "" {
+		return nil, false
+	}
+	return &session, true
+}
+
+func deviceSessionKey(userID, sessionID string) string {
+	return fmt.Sprintf("device_session:%s:%s", userID, sessionID)
+}
+
+func deviceSessionTokenKey(tokenHash string) string {
+	return fmt.Sprintf("device_session_token:%s", tokenHash)
+}
+
+func hashSessionToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
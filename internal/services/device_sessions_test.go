@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/config"
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newDeviceSessionTestService(t *testing.T, cache *memoryCache, maxPerUser int) (*AuthService, *impersonationUserRepo) {
+	t.Helper()
+	userRepo := &impersonationUserRepo{users: map[string]*models.User{}}
+	for _, id := range []string{"USER1", "USER2"} {
+		user := models.NewUser("9876543210", "+91", "hash")
+		user.ID = id
+		status := "active"
+		user.Status = &status
+		userRepo.users[id] = user
+	}
+
+	jwtCfg := &config.JWTConfig{Secret: "test-secret", Issuer: "aaa-service", TTL: time.Hour, Leeway: time.Minute}
+	service, err := NewAuthService(userRepo, nil, nil, cache, nil, nil,
+		&AuthServiceConfig{JWTSecret: jwtCfg.Secret}, zap.NewNop(), nil, jwtCfg)
+	require.NoError(t, err)
+	service.SetDeviceSessionConfig(config.DeviceSessionConfig{TTL: time.Hour, MaxPerUser: maxPerUser})
+	return service, userRepo
+}
+
+func deviceRequestContext(ip, userAgent string) context.Context {
+	ctx := context.WithValue(context.Background(), "ip_address", ip)
+	return context.WithValue(ctx, "user_agent", userAgent)
+}
+
+func TestDeviceSession_CreateAndExchange(t *testing.T) {
+	cache := &memoryCache{values: map[string]interface{}{}}
+	service, _ := newDeviceSessionTestService(t, cache, 5)
+
+	session, err := service.CreateDeviceSession(deviceRequestContext("203.0.113.1", "App/1.0"), "USER1", " Pixel 8 ")
+	require.NoError(t, err)
+	assert.NotEmpty(t, session.SessionToken)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), session.ExpiresAt, 5*time.Second)
+	for key := range cache.values {
+		assert.NotContains(t, key, session.SessionToken, "only the token hash is stored")
+	}
+
+	userID, err := service.ExchangeDeviceSession(deviceRequestContext("198.51.100.7", "App/1.1"), session.SessionToken)
+	require.NoError(t, err)
+	assert.Equal(t, "USER1", userID)
+
+	sessions, err := service.ListDeviceSessions(context.Background(), "USER1")
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, session.SessionID, sessions[0].ID)
+	assert.Equal(t, "Pixel 8", sessions[0].DeviceName)
+	assert.Equal(t, "198.51.100.7", sessions[0].IPAddress, "the session records the IP of its last use")
+	assert.Equal(t, "App/1.1", sessions[0].UserAgent)
+	assert.True(t, sessions[0].LastUsedAt.After(sessions[0].CreatedAt))
+	assert.Equal(t, session.ExpiresAt.Unix(), sessions[0].ExpiresAt.Unix(), "use does not extend the session")
+
+	_, err = service.ExchangeDeviceSession(context.Background(), "not-a-session")
+	assert.True(t, errors.IsUnauthorizedError(err))
+}
+
+func TestDeviceSession_Revoke(t *testing.T) {
+	cache := &memoryCache{values: map[string]interface{}{}}
+	service, _ := newDeviceSessionTestService(t, cache, 5)
+
+	session, err := service.CreateDeviceSession(context.Background(), "USER1", "Phone")
+	require.NoError(t, err)
+
+	err = service.RevokeDeviceSession(context.Background(), "USER2", session.SessionID)
+	assert.True(t, errors.IsNotFoundError(err), "users cannot revoke the sessions of others")
+
+	require.NoError(t, service.RevokeDeviceSession(context.Background(), "USER1", session.SessionID))
+	_, err = service.ExchangeDeviceSession(context.Background(), session.SessionToken)
+	assert.True(t, errors.IsUnauthorizedError(err))
+	assert.Empty(t, cache.values)
+
+	err = service.RevokeDeviceSession(context.Background(), "USER1", session.SessionID)
+	assert.True(t, errors.IsNotFoundError(err))
+}
+
+func TestDeviceSession_EvictsLeastRecentlyUsed(t *testing.T) {
+	cache := &memoryCache{values: map[string]interface{}{}}
+	service, _ := newDeviceSessionTestService(t, cache, 2)
+
+	first, err := service.CreateDeviceSession(context.Background(), "USER1", "first")
+	require.NoError(t, err)
+	second, err := service.CreateDeviceSession(context.Background(), "USER1", "second")
+	require.NoError(t, err)
+
+	// Using the first session makes the second the least recently used
+	time.Sleep(time.Millisecond)
+	_, err = service.ExchangeDeviceSession(context.Background(), first.SessionToken)
+	require.NoError(t, err)
+
+	_, err = service.CreateDeviceSession(context.Background(), "USER1", "third")
+	require.NoError(t, err)
+
+	sessions, err := service.ListDeviceSessions(context.Background(), "USER1")
+	require.NoError(t, err)
+	require.Len(t, sessions, 2)
+	assert.Equal(t, "third", sessions[0].DeviceName)
+	assert.Equal(t, "first", sessions[1].DeviceName)
+
+	_, err = service.ExchangeDeviceSession(context.Background(), second.SessionToken)
+	assert.True(t, errors.IsUnauthorizedError(err))
+}
+
+func TestDeviceSession_InactiveUserIsSignedOut(t *testing.T) {
+	cache := &memoryCache{values: map[string]interface{}{}}
+	service, userRepo := newDeviceSessionTestService(t, cache, 5)
+
+	session, err := service.CreateDeviceSession(context.Background(), "USER1", "Phone")
+	require.NoError(t, err)
+
+	suspended := "suspended"
+	userRepo.users["USER1"].Status = &suspended
+
+	_, err = service.ExchangeDeviceSession(context.Background(), session.SessionToken)
+	assert.True(t, errors.IsUnauthorizedError(err))
+
+	sessions, err := service.ListDeviceSessions(context.Background(), "USER1")
+	require.NoError(t, err)
+	assert.Empty(t, sessions, "the session of an inactive user is revoked")
+}
@@ -0,0 +1,164 @@
+package geocoding
+
+import (
+	"context"
+	"encoding/json"
+	stdErrors "errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+)
+
+// Supported geocoding providers
+const (
+	ProviderNone   = "none"
+	ProviderGoogle = "google"
+)
+
+const (
+	defaultGoogleBaseURL = "https://maps.googleapis.com/maps/api/geocode/json"
+	defaultTimeout       = 5 * time.Second
+)
+
+// Config selects and configures the geocoding provider
+type Config struct {
+	Provider string
+	APIKey   string
+	BaseURL  string
+	Timeout  time.Duration
+}
+
+// NewGeocoder returns the geocoder for the configured provider. An empty provider or "none" returns
+// the stub, which never finds coordinates.
+func NewGeocoder(cfg Config) (interfaces.Geocoder, error) {
+	switch strings.ToLower(strings.TrimSpace(cfg.Provider)) {
+	case "", ProviderNone:
+		return NewNoopGeocoder(), nil
+	case ProviderGoogle:
+		if cfg.APIKey == "" {
+			return nil, fmt.Errorf("geocoder provider %q requires an API key", ProviderGoogle)
+		}
+		return NewGoogleGeocoder(cfg.APIKey, cfg.BaseURL, cfg.Timeout), nil
+	default:
+		return nil, fmt.Errorf("unknown geocoder provider %q", cfg.Provider)
+	}
+}
+
+// NoopGeocoder is the default geocoder; it never resolves coordinates
+type NoopGeocoder struct{}
+
+// NewNoopGeocoder creates a geocoder that leaves every address without coordinates
+func NewNoopGeocoder() *NoopGeocoder {
+	return &NoopGeocoder{}
+}
+
+// Geocode always reports no match
+func (g *NoopGeocoder) Geocode(ctx context.Context, address *models.Address) (*interfaces.GeoPoint, error) {
+	return nil, nil
+}
+
+// GoogleGeocoder resolves addresses with the Google Maps Geocoding API
+type GoogleGeocoder struct {
+	apiKey  string
+	baseURL string
+	client  *http.Client
+}
+
+// NewGoogleGeocoder creates a Google Maps geocoder. An empty baseURL uses the public endpoint.
+func NewGoogleGeocoder(apiKey, baseURL string, timeout time.Duration) *GoogleGeocoder {
+	if baseURL == "" {
+		baseURL = defaultGoogleBaseURL
+	}
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	return &GoogleGeocoder{
+		apiKey:  apiKey,
+		baseURL: baseURL,
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+type googleGeocodeResponse struct {
+	Status       string `json:"status"`
+	ErrorMessage string `json:"error_message"`
+	Results      []struct {
+		Geometry struct {
+			Location struct {
+				Lat float64 `json:"lat"`
+				Lng float64 `json:"lng"`
+			} `json:"location"`
+		} `json:"geometry"`
+	} `json:"results"`
+}
+
+// Geocode looks up the address and returns the coordinates of the best match
+func (g *GoogleGeocoder) Geocode(ctx context.Context, address *models.Address) (*interfaces.GeoPoint, error) {
+	query := geocodeQuery(address)
+	if query == "" {
+		return nil, nil
+	}
+
+	params := url.Values{}
+	params.Set("address", query)
+	params.Set("region", "in")
+	params.Set("key", g.apiKey)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, g.baseURL+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build geocode request: %w", err)
+	}
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		// The request URL carries the API key and the address, so only the cause is reported
+		var urlErr *url.Error
+		if stdErrors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return nil, fmt.Errorf("geocode request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("geocode request failed with status %d", resp.StatusCode)
+	}
+
+	var body googleGeocodeResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode geocode response: %w", err)
+	}
+
+	switch body.Status {
+	case "OK":
+		if len(body.Results) == 0 {
+			return nil, nil
+		}
+		location := body.Results[0].Geometry.Location
+		return &interfaces.GeoPoint{Latitude: location.Lat, Longitude: location.Lng}, nil
+	case "ZERO_RESULTS":
+		return nil, nil
+	default:
+		if body.ErrorMessage != "" {
+			return nil, fmt.Errorf("geocoder returned %s: %s", body.Status, body.ErrorMessage)
+		}
+		return nil, fmt.Errorf("geocoder returned %s", body.Status)
+	}
+}
+
+// geocodeQuery is the text sent to the provider: the full address, or the components when it has not been built
+func geocodeQuery(address *models.Address) string {
+	if address == nil {
+		return ""
+	}
+	if address.FullAddress != nil && strings.TrimSpace(*address.FullAddress) != "" {
+		return strings.TrimSpace(*address.FullAddress)
+	}
+	copied := *address
+	return copied.BuildFullAddress()
+}
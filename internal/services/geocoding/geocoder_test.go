@@ -0,0 +1,80 @@
+package geocoding
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewGeocoder(t *testing.T) {
+	geocoder, err := NewGeocoder(Config{})
+	require.NoError(t, err)
+	assert.IsType(t, &NoopGeocoder{}, geocoder)
+
+	geocoder, err = NewGeocoder(Config{Provider: "Google", APIKey: "key"})
+	require.NoError(t, err)
+	assert.IsType(t, &GoogleGeocoder{}, geocoder)
+
+	_, err = NewGeocoder(Config{Provider: ProviderGoogle})
+	assert.Error(t, err)
+	_, err = NewGeocoder(Config{Provider: "osm"})
+	assert.Error(t, err)
+}
+
+func TestGoogleGeocoder_Geocode(t *testing.T) {
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query().Get("address")
+		switch query {
+		case "Guntur, Andhra Pradesh":
+			w.Write([]byte(`{"status":"OK","results":[{"geometry":{"location":{"lat":16.3067,"lng":80.4365}}}]}`))
+		case "Nowhere":
+			w.Write([]byte(`{"status":"ZERO_RESULTS","results":[]}`))
+		default:
+			w.Write([]byte(`{"status":"REQUEST_DENIED","error_message":"API key invalid"}`))
+		}
+	}))
+	defer server.Close()
+
+	geocoder := NewGoogleGeocoder("key", server.URL, time.Second)
+	ctx := context.Background()
+
+	district, state := "Guntur", "Andhra Pradesh"
+	address := models.NewAddress()
+	address.District = &district
+	address.State = &state
+
+	point, err := geocoder.Geocode(ctx, address)
+	require.NoError(t, err)
+	require.NotNil(t, point)
+	assert.Equal(t, 16.3067, point.Latitude)
+	assert.Equal(t, 80.4365, point.Longitude)
+	assert.Nil(t, address.FullAddress, "geocoding does not modify the address")
+
+	nowhere := "Nowhere"
+	point, err = geocoder.Geocode(ctx, &models.Address{FullAddress: &nowhere})
+	require.NoError(t, err)
+	assert.Nil(t, point)
+
+	denied := "Denied"
+	_, err = geocoder.Geocode(ctx, &models.Address{FullAddress: &denied})
+	assert.ErrorContains(t, err, "REQUEST_DENIED")
+}
+
+func TestGoogleGeocoder_TransportErrorHidesRequestURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Close()
+
+	geocoder := NewGoogleGeocoder("secret-api-key", server.URL, time.Second)
+	address := "12 Main Road, Guntur"
+	_, err := geocoder.Geocode(context.Background(), &models.Address{FullAddress: &address})
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "secret-api-key")
+	assert.NotContains(t, err.Error(), "Guntur")
+}
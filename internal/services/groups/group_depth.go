@@ -0,0 +1,74 @@
+package groups
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// SetMaxHierarchyDepth bounds how many levels a group hierarchy may span, a root group being at
+// depth 1. Creating or moving a group below a parent fails with a validation error when it, or the
+// deepest group under it, would exceed the limit. 0 disables the limit.
+func (s *Service) SetMaxHierarchyDepth(depth int) {
+	s.maxHierarchyDepth = depth
+}
+
+// checkHierarchyDepth rejects placing a group and its subtree under parentID when that would
+// exceed the maximum depth; groupID is empty for a group being created
+func (s *Service) checkHierarchyDepth(ctx context.Context, groupID, parentID string) error {
+	if s.maxHierarchyDepth <= 0 || parentID == "" {
+		return nil
+	}
+
+	// The parent and its ancestors, and the group itself
+	depth := len(s.ancestorIDs(ctx, parentID)) + 1
+
+	if groupID != "" && depth <= s.maxHierarchyDepth {
+		height, err := s.subtreeHeight(ctx, groupID, s.maxHierarchyDepth-depth+1)
+		if err != nil {
+			return errors.NewInternalError(fmt.Errorf("failed to load group subtree: %w", err))
+		}
+		depth += height - 1
+	}
+
+	if depth > s.maxHierarchyDepth {
+		s.logger.Warn("Group hierarchy depth limit exceeded",
+			zap.String("group_id", groupID),
+			zap.String("parent_id", parentID),
+			zap.Int("depth", depth),
+			zap.Int("max_depth", s.maxHierarchyDepth))
+		return errors.NewValidationError(fmt.Sprintf("group hierarchy would be %d levels deep, exceeding the maximum of %d", depth, s.maxHierarchyDepth))
+	}
+	return nil
+}
+
+// subtreeHeight counts the levels of the subtree under groupID, the group itself being one.
+// Loading stops one level past limit, which is enough to tell the limit is exceeded.
+func (s *Service) subtreeHeight(ctx context.Context, groupID string, limit int) (int, error) {
+	height := 1
+	visited := map[string]bool{groupID: true}
+	level := []string{groupID}
+	for height <= limit {
+		var next []string
+		for _, id := range level {
+			children, err := s.groupRepo.GetChildren(ctx, id)
+			if err != nil {
+				return 0, err
+			}
+			for _, child := range children {
+				if !visited[child.ID] {
+					visited[child.ID] = true
+					next = append(next, child.ID)
+				}
+			}
+		}
+		if len(next) == 0 {
+			break
+		}
+		height++
+		level = next
+	}
+	return height, nil
+}
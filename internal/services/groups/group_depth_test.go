@@ -0,0 +1,106 @@
+package groups
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/repositories/groups"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/Kisanlink/kisanlink-db/pkg/base"
+	"github.com/Kisanlink/kisanlink-db/pkg/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// depthDBManager serves groups from memory by ID and by parent
+type depthDBManager struct {
+	db.DBManager
+	groups map[string]*models.Group
+}
+
+func (m *depthDBManager) GetByID(ctx context.Context, id interface{}, model interface{}) error {
+	out, ok := model.(*models.Group)
+	if !ok {
+		return fmt.Errorf("unexpected model %T", model)
+	}
+	group, ok := m.groups[id.(string)]
+	if !ok {
+		return fmt.Errorf("group %v not found", id)
+	}
+	*out = *group
+	return nil
+}
+
+func (m *depthDBManager) List(ctx context.Context, filter *base.Filter, model interface{}) error {
+	out, ok := model.(*[]*models.Group)
+	if !ok {
+		return fmt.Errorf("unexpected model %T", model)
+	}
+	var rows []*models.Group
+	for _, group := range m.groups {
+		parentID := ""
+		if group.ParentID != nil {
+			parentID = *group.ParentID
+		}
+		if matchesConditions(filter, map[string]interface{}{"parent_id": parentID, "is_active": group.IsActive}) {
+			rows = append(rows, group)
+		}
+	}
+	*out = rows
+	return nil
+}
+
+// newDepthTestService builds the chains G1 -> G2 -> G3 and M1 -> M2
+func newDepthTestService(maxDepth int) *Service {
+	dbManager := &depthDBManager{groups: map[string]*models.Group{}}
+	for _, g := range []struct{ id, parentID string }{
+		{"G1", ""}, {"G2", "G1"}, {"G3", "G2"}, {"M1", ""}, {"M2", "M1"},
+	} {
+		group := models.NewGroup(g.id, "", "ORG1")
+		group.ID = g.id
+		if g.parentID != "" {
+			parentID := g.parentID
+			group.ParentID = &parentID
+		}
+		dbManager.groups[g.id] = group
+	}
+
+	service := &Service{groupRepo: groups.NewGroupRepository(dbManager), logger: zap.NewNop()}
+	service.SetMaxHierarchyDepth(maxDepth)
+	return service
+}
+
+func TestService_CheckHierarchyDepth(t *testing.T) {
+	tests := []struct {
+		name     string
+		maxDepth int
+		groupID  string
+		parentID string
+		allowed  bool
+	}{
+		{"new group at the maximum depth", 3, "", "G2", true},
+		{"new group below the maximum depth", 3, "", "G3", false},
+		{"moved subtree reaching the maximum depth", 3, "M1", "G1", true},
+		{"moved subtree exceeding the maximum depth", 3, "M1", "G2", false},
+		{"moved leaf at the maximum depth", 3, "M2", "G2", true},
+		{"root group", 1, "", "", true},
+		{"limit disabled", 0, "M1", "G3", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newDepthTestService(tt.maxDepth)
+
+			err := service.checkHierarchyDepth(context.Background(), tt.groupID, tt.parentID)
+			if tt.allowed {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.True(t, errors.IsValidationError(err))
+			assert.Contains(t, err.Error(), "exceeding the maximum of 3")
+		})
+	}
+}
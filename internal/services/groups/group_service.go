@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
 	groupRequests "github.com/Kisanlink/aaa-service/v2/internal/entities/requests/groups"
@@ -13,6 +14,7 @@ import (
 	"github.com/Kisanlink/aaa-service/v2/internal/repositories/organizations"
 	"github.com/Kisanlink/aaa-service/v2/internal/repositories/roles"
 	"github.com/Kisanlink/aaa-service/v2/internal/services/user"
+	"github.com/Kisanlink/aaa-service/v2/internal/utils/actor"
 	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
 	"go.uber.org/zap"
 )
@@ -29,7 +31,9 @@ type Service struct {
 	cache               interfaces.CacheService
 	groupCache          *GroupCacheService
 	auditService        interfaces.AuditService
-	userService         interfaces.UserService // For invalidating user organizational cache
+	userService         interfaces.UserService                // For invalidating user organizational cache
+	resourcePermRepo    ResourcePermissionRepositoryInterface // For resource-scoped effective roles
+	maxHierarchyDepth   int                                   // Levels a group hierarchy may span; 0 disables the limit
 	logger              *zap.Logger
 }
 
@@ -69,6 +73,11 @@ func (s *Service) SetUserService(userService interfaces.UserService) {
 	s.logger.Debug("User service injected into group service for cache invalidation")
 }
 
+// SetResourcePermissionRepository sets the repository used to scope effective roles to a resource
+func (s *Service) SetResourcePermissionRepository(repo ResourcePermissionRepositoryInterface) {
+	s.resourcePermRepo = repo
+}
+
 // CreateGroup creates a new group with proper validation and business logic
 func (s *Service) CreateGroup(ctx context.Context, req interface{}) (interface{}, error) {
 	createReq, ok := req.(*groupRequests.CreateGroupRequest)
@@ -121,6 +130,9 @@ func (s *Service) CreateGroup(ctx context.Context, req interface{}) (interface{}
 				zap.String("req_org", createReq.OrganizationID))
 			return nil, errors.NewValidationError("parent group must belong to the same organization")
 		}
+		if err := s.checkHierarchyDepth(ctx, "", *createReq.ParentID); err != nil {
+			return nil, err
+		}
 	}
 
 	// Create group model
@@ -128,6 +140,7 @@ func (s *Service) CreateGroup(ctx context.Context, req interface{}) (interface{}
 	if createReq.ParentID != nil && *createReq.ParentID != "" {
 		group.ParentID = createReq.ParentID
 	}
+	actor.StampCreate(ctx, group)
 
 	// Save group to repository
 	err = s.groupRepo.Create(ctx, group)
@@ -141,7 +154,7 @@ func (s *Service) CreateGroup(ctx context.Context, req interface{}) (interface{}
 			"description": createReq.Description,
 			"error":       err.Error(),
 		}
-		s.auditService.LogGroupOperation(ctx, "system", models.AuditActionCreateGroup, createReq.OrganizationID, "", "Failed to create group", false, auditDetails)
+		s.auditService.LogGroupOperation(ctx, actor.OrSystem(ctx), models.AuditActionCreateGroup, createReq.OrganizationID, "", "Failed to create group", false, auditDetails)
 
 		if strings.Contains(err.Error(), "duplicate key") ||
 			strings.Contains(err.Error(), "unique constraint") {
@@ -157,7 +170,7 @@ func (s *Service) CreateGroup(ctx context.Context, req interface{}) (interface{}
 		"description": group.Description,
 		"is_active":   group.IsActive,
 	}
-	s.auditService.LogGroupOperation(ctx, "system", models.AuditActionCreateGroup, group.OrganizationID, group.ID, "Group created successfully", true, auditDetails)
+	s.auditService.LogGroupOperation(ctx, actor.OrSystem(ctx), models.AuditActionCreateGroup, group.OrganizationID, group.ID, "Group created successfully", true, auditDetails)
 
 	s.logger.Info("Group created successfully",
 		zap.String("group_id", group.ID),
@@ -254,6 +267,9 @@ func (s *Service) UpdateGroup(ctx context.Context, groupID string, req interface
 				s.logger.Warn("Circular reference detected", zap.Error(err))
 				return nil, errors.NewValidationError("circular reference detected in group hierarchy")
 			}
+			if err := s.checkHierarchyDepth(ctx, groupID, *updateReq.ParentID); err != nil {
+				return nil, err
+			}
 		}
 	}
 
@@ -302,6 +318,7 @@ func (s *Service) UpdateGroup(ctx context.Context, groupID string, req interface
 	}
 
 	// Save changes
+	actor.StampUpdate(ctx, group)
 	err = s.groupRepo.Update(ctx, group)
 	if err != nil {
 		s.logger.Error("Failed to update group", zap.Error(err))
@@ -312,7 +329,7 @@ func (s *Service) UpdateGroup(ctx context.Context, groupID string, req interface
 			"new_values": newValues,
 			"error":      err.Error(),
 		}
-		s.auditService.LogGroupOperation(ctx, "system", models.AuditActionUpdateGroup, group.OrganizationID, groupID, "Failed to update group", false, auditDetails)
+		s.auditService.LogGroupOperation(ctx, actor.OrSystem(ctx), models.AuditActionUpdateGroup, group.OrganizationID, groupID, "Failed to update group", false, auditDetails)
 
 		return nil, errors.NewInternalError(err)
 	}
@@ -322,11 +339,11 @@ func (s *Service) UpdateGroup(ctx context.Context, groupID string, req interface
 		"old_values": oldValues,
 		"new_values": newValues,
 	}
-	s.auditService.LogGroupOperation(ctx, "system", models.AuditActionUpdateGroup, group.OrganizationID, groupID, "Group updated successfully", true, auditDetails)
+	s.auditService.LogGroupOperation(ctx, actor.OrSystem(ctx), models.AuditActionUpdateGroup, group.OrganizationID, groupID, "Group updated successfully", true, auditDetails)
 
 	// Log hierarchy change separately if it occurred with comprehensive structure change logging
 	if hierarchyChanged {
-		s.auditService.LogHierarchyChange(ctx, "system", models.AuditActionChangeGroupHierarchy, models.ResourceTypeGroup, groupID, oldParentID, newParentID, "Group hierarchy changed", true, auditDetails)
+		s.auditService.LogHierarchyChange(ctx, actor.OrSystem(ctx), models.AuditActionChangeGroupHierarchy, models.ResourceTypeGroup, groupID, oldParentID, newParentID, "Group hierarchy changed", true, auditDetails)
 
 		// Also log comprehensive structure change for enhanced audit trail
 		hierarchyOldValues := map[string]interface{}{
@@ -335,7 +352,7 @@ func (s *Service) UpdateGroup(ctx context.Context, groupID string, req interface
 		hierarchyNewValues := map[string]interface{}{
 			"parent_id": newParentID,
 		}
-		s.auditService.LogOrganizationStructureChange(ctx, "system", models.AuditActionChangeGroupHierarchy, group.OrganizationID, models.ResourceTypeGroup, groupID, hierarchyOldValues, hierarchyNewValues, true, "Group hierarchy structure changed")
+		s.auditService.LogOrganizationStructureChange(ctx, actor.OrSystem(ctx), models.AuditActionChangeGroupHierarchy, group.OrganizationID, models.ResourceTypeGroup, groupID, hierarchyOldValues, hierarchyNewValues, true, "Group hierarchy structure changed")
 	}
 
 	s.logger.Info("Group updated successfully", zap.String("group_id", groupID))
@@ -354,6 +371,167 @@ func (s *Service) UpdateGroup(ctx context.Context, groupID string, req interface
 	return response, nil
 }
 
+// MoveGroup moves a group, together with its subtree, under a different parent group in the same
+// organization. Because roles are inherited bottom-up, the members of the old and new ancestor
+// chains gain or lose roles, so their cached effective roles are invalidated.
+func (s *Service) MoveGroup(ctx context.Context, groupID, newParentID, movedBy string) (interface{}, error) {
+	s.logger.Info("Moving group",
+		zap.String("group_id", groupID),
+		zap.String("new_parent_id", newParentID),
+		zap.String("moved_by", movedBy))
+
+	if groupID == "" {
+		return nil, errors.NewValidationError("group_id cannot be empty")
+	}
+	if newParentID == "" {
+		return nil, errors.NewValidationError("new_parent_id cannot be empty")
+	}
+	if movedBy == "" {
+		return nil, errors.NewValidationError("moved_by cannot be empty")
+	}
+	if groupID == newParentID {
+		return nil, errors.NewValidationError("a group cannot be its own parent")
+	}
+
+	group, err := s.groupRepo.GetByID(ctx, groupID)
+	if err != nil || group == nil {
+		s.logger.Warn("Group not found for move", zap.String("group_id", groupID))
+		return nil, errors.NewNotFoundError("group not found")
+	}
+
+	parentGroup, err := s.groupRepo.GetByID(ctx, newParentID)
+	if err != nil || parentGroup == nil {
+		s.logger.Warn("Parent group not found", zap.String("parent_id", newParentID))
+		return nil, errors.NewNotFoundError("parent group not found")
+	}
+	if !parentGroup.IsActive {
+		s.logger.Warn("Parent group is inactive", zap.String("parent_id", newParentID))
+		return nil, errors.NewValidationError("parent group is inactive")
+	}
+	if parentGroup.OrganizationID != group.OrganizationID {
+		s.logger.Warn("Rejected move across organizations",
+			zap.String("group_id", groupID),
+			zap.String("group_org_id", group.OrganizationID),
+			zap.String("parent_org_id", parentGroup.OrganizationID))
+		return nil, errors.NewValidationError("cannot move a group to a parent in a different organization")
+	}
+
+	oldParentID := ""
+	if group.ParentID != nil {
+		oldParentID = *group.ParentID
+	}
+	if oldParentID == newParentID {
+		s.logger.Info("Group already has the requested parent", zap.String("group_id", groupID))
+		return s.toGroupResponse(group), nil
+	}
+
+	if err := s.checkCircularReference(ctx, groupID, newParentID); err != nil {
+		s.logger.Warn("Circular reference detected", zap.Error(err))
+		return nil, errors.NewValidationError("circular reference detected in group hierarchy")
+	}
+	if err := s.checkHierarchyDepth(ctx, groupID, newParentID); err != nil {
+		return nil, err
+	}
+
+	// Ancestors on both sides of the move are the groups whose inherited roles change
+	affectedGroupIDs := append(s.ancestorIDs(ctx, oldParentID), s.ancestorIDs(ctx, newParentID)...)
+
+	auditDetails := map[string]interface{}{
+		"organization_id": group.OrganizationID,
+		"old_parent_id":   oldParentID,
+		"new_parent_id":   newParentID,
+	}
+
+	if err := s.groupRepo.MoveToParent(ctx, groupID, newParentID, movedBy); err != nil {
+		auditDetails["error"] = err.Error()
+		s.auditService.LogHierarchyChange(ctx, movedBy, models.AuditActionChangeGroupHierarchy, models.ResourceTypeGroup, groupID, oldParentID, newParentID, "Failed to move group", false, auditDetails)
+
+		if err == groups.ErrGroupHierarchyCycle {
+			s.logger.Warn("Circular reference detected during move", zap.String("group_id", groupID))
+			return nil, errors.NewValidationError("circular reference detected in group hierarchy")
+		}
+		s.logger.Error("Failed to move group", zap.String("group_id", groupID), zap.Error(err))
+		return nil, errors.NewInternalError(err)
+	}
+
+	s.auditService.LogHierarchyChange(ctx, movedBy, models.AuditActionChangeGroupHierarchy, models.ResourceTypeGroup, groupID, oldParentID, newParentID, "Group moved to new parent", true, auditDetails)
+
+	_ = s.groupCache.InvalidateHierarchyCache(ctx, groupID, affectedGroupIDs)
+	s.invalidateMembersEffectiveRoles(ctx, group.OrganizationID, affectedGroupIDs)
+
+	group.ParentID = &newParentID
+	s.logger.Info("Group moved successfully",
+		zap.String("group_id", groupID),
+		zap.String("old_parent_id", oldParentID),
+		zap.String("new_parent_id", newParentID))
+
+	return s.toGroupResponse(group), nil
+}
+
+// ancestorIDs returns groupID followed by the IDs of its ancestors, nearest first
+func (s *Service) ancestorIDs(ctx context.Context, groupID string) []string {
+	var ids []string
+	visited := make(map[string]bool)
+	for currentID := groupID; currentID != "" && !visited[currentID]; {
+		visited[currentID] = true
+		ids = append(ids, currentID)
+
+		group, err := s.groupRepo.GetByID(ctx, currentID)
+		if err != nil || group == nil || group.ParentID == nil {
+			break
+		}
+		currentID = *group.ParentID
+	}
+	return ids
+}
+
+// invalidateMembersEffectiveRoles drops the cached effective roles of every user in the given groups
+func (s *Service) invalidateMembersEffectiveRoles(ctx context.Context, orgID string, groupIDs []string) {
+	const pageSize = 100
+	invalidated := make(map[string]bool)
+
+	for _, groupID := range groupIDs {
+		for offset := 0; ; offset += pageSize {
+			memberships, err := s.groupMembershipRepo.GetByGroupID(ctx, groupID, pageSize, offset)
+			if err != nil {
+				s.logger.Warn("Failed to list group members for cache invalidation",
+					zap.String("group_id", groupID),
+					zap.Error(err))
+				break
+			}
+			for _, membership := range memberships {
+				if membership.PrincipalType != "user" || invalidated[membership.PrincipalID] {
+					continue
+				}
+				invalidated[membership.PrincipalID] = true
+				_ = s.groupCache.InvalidateUserEffectiveRolesCache(ctx, orgID, membership.PrincipalID)
+			}
+			if len(memberships) < pageSize {
+				break
+			}
+		}
+	}
+
+	s.logger.Debug("Invalidated effective roles after group move",
+		zap.String("org_id", orgID),
+		zap.Int("affected_groups", len(groupIDs)),
+		zap.Int("affected_users", len(invalidated)))
+}
+
+// toGroupResponse converts a group model to its API response
+func (s *Service) toGroupResponse(group *models.Group) *groupResponses.GroupResponse {
+	return &groupResponses.GroupResponse{
+		ID:             group.ID,
+		Name:           group.Name,
+		Description:    group.Description,
+		OrganizationID: group.OrganizationID,
+		ParentID:       group.ParentID,
+		IsActive:       group.IsActive,
+		CreatedAt:      &group.CreatedAt,
+		UpdatedAt:      &group.UpdatedAt,
+	}
+}
+
 // DeleteGroup deletes a group
 func (s *Service) DeleteGroup(ctx context.Context, groupID string, deletedBy string) error {
 	s.logger.Info("Deleting group", zap.String("group_id", groupID))
@@ -460,6 +638,58 @@ func (s *Service) ListGroups(ctx context.Context, limit, offset int, organizatio
 	return responses, nil
 }
 
+// ListOrganizationGroups retrieves a page of the groups of an organization matching filter and the
+// total number of matches
+func (s *Service) ListOrganizationGroups(ctx context.Context, organizationID string, filter interfaces.GroupFilter, limit, offset int) (interface{}, int64, error) {
+	s.logger.Info("Listing organization groups",
+		zap.String("organization_id", organizationID),
+		zap.Int("limit", limit),
+		zap.Int("offset", offset))
+
+	if organizationID == "" {
+		return nil, 0, errors.NewValidationError("organization ID is required")
+	}
+
+	listFilter := groups.GroupListFilter{
+		NameContains: filter.NameContains,
+		IsActive:     filter.IsActive,
+		ParentID:     filter.ParentID,
+	}
+	if filter.HasRole != "" {
+		groupRoles, err := s.groupRoleRepo.GetByRoleID(ctx, filter.HasRole)
+		if err != nil {
+			s.logger.Error("Failed to get groups holding role", zap.String("role_id", filter.HasRole), zap.Error(err))
+			return nil, 0, errors.NewInternalError(err)
+		}
+		listFilter.GroupIDs = make([]string, 0, len(groupRoles))
+		for _, groupRole := range groupRoles {
+			listFilter.GroupIDs = append(listFilter.GroupIDs, groupRole.GroupID)
+		}
+	}
+
+	orgGroups, total, err := s.groupRepo.ListByOrganizationFiltered(ctx, organizationID, listFilter, limit, offset)
+	if err != nil {
+		s.logger.Error("Failed to list organization groups", zap.String("organization_id", organizationID), zap.Error(err))
+		return nil, 0, errors.NewInternalError(err)
+	}
+
+	responses := make([]*groupResponses.GroupResponse, len(orgGroups))
+	for i, group := range orgGroups {
+		responses[i] = &groupResponses.GroupResponse{
+			ID:             group.ID,
+			Name:           group.Name,
+			Description:    group.Description,
+			OrganizationID: group.OrganizationID,
+			ParentID:       group.ParentID,
+			IsActive:       group.IsActive,
+			CreatedAt:      &group.CreatedAt,
+			UpdatedAt:      &group.UpdatedAt,
+		}
+	}
+
+	return responses, total, nil
+}
+
 // CountGroups returns total count of groups matching the filters
 func (s *Service) CountGroups(ctx context.Context, organizationID string, includeInactive bool) (int64, error) {
 	if organizationID != "" {
@@ -868,7 +1098,7 @@ func (s *Service) RemoveRoleFromGroup(ctx context.Context, groupID, roleID strin
 		if role != nil {
 			auditDetails["role_name"] = role.Name
 		}
-		s.auditService.LogGroupRoleAssignment(ctx, "system", models.AuditActionRemoveGroupRole, group.OrganizationID, groupID, roleID, "Failed to remove role from group", false, auditDetails)
+		s.auditService.LogGroupRoleAssignment(ctx, actor.OrSystem(ctx), models.AuditActionRemoveGroupRole, group.OrganizationID, groupID, roleID, "Failed to remove role from group", false, auditDetails)
 
 		return errors.NewInternalError(err)
 	}
@@ -882,7 +1112,7 @@ func (s *Service) RemoveRoleFromGroup(ctx context.Context, groupID, roleID strin
 	if role != nil {
 		auditDetails["role_name"] = role.Name
 	}
-	s.auditService.LogGroupRoleAssignment(ctx, "system", models.AuditActionRemoveGroupRole, group.OrganizationID, groupID, roleID, "Role removed from group successfully", true, auditDetails)
+	s.auditService.LogGroupRoleAssignment(ctx, actor.OrSystem(ctx), models.AuditActionRemoveGroupRole, group.OrganizationID, groupID, roleID, "Role removed from group successfully", true, auditDetails)
 
 	// Invalidate relevant caches after successful role removal
 	_ = s.groupCache.InvalidateRoleAssignmentCache(ctx, group.OrganizationID, groupID, roleID)
@@ -966,24 +1196,52 @@ func (s *Service) GetGroupRoles(ctx context.Context, groupID string) (interface{
 
 // GetGroupMembers retrieves all members of a group
 func (s *Service) GetGroupMembers(ctx context.Context, groupID string, limit, offset int) (interface{}, error) {
-	s.logger.Info("Retrieving group members", zap.String("group_id", groupID))
+	return s.ListGroupMembers(ctx, groupID, interfaces.GroupMemberFilter{}, limit, offset)
+}
+
+// ListGroupMembers retrieves the active members of a group, optionally only users or only services.
+// Each member carries its validity window; with IncludeRoles it also carries the roles it holds
+// through the group.
+func (s *Service) ListGroupMembers(ctx context.Context, groupID string, filter interfaces.GroupMemberFilter, limit, offset int) (interface{}, error) {
+	s.logger.Info("Retrieving group members",
+		zap.String("group_id", groupID),
+		zap.String("principal_type", filter.PrincipalType),
+		zap.Bool("include_roles", filter.IncludeRoles))
+
+	if err := validatePrincipalTypeFilter(filter.PrincipalType); err != nil {
+		return nil, err
+	}
 
-	// For small result sets, check cache first (only if limit <= 100 to avoid caching large datasets)
+	// For small result sets, check cache first (only if limit <= 100 to avoid caching large datasets).
+	// Role enrichment is not cached: role assignments do not invalidate the member cache.
 	cacheKey := fmt.Sprintf("%s_%d_%d", groupID, limit, offset)
-	if limit <= 100 {
+	if filter.PrincipalType != "" {
+		cacheKey = fmt.Sprintf("%s_%s", cacheKey, filter.PrincipalType)
+	}
+	useCache := limit <= 100 && !filter.IncludeRoles
+	if useCache {
 		if cached, found := s.groupCache.GetCachedGroupMembers(ctx, cacheKey, true); found {
 			s.logger.Debug("Returning cached group members", zap.String("group_id", groupID))
 			return cached, nil
 		}
 	}
 
-	memberships, err := s.groupRepo.GetGroupMembers(ctx, groupID, limit, offset)
+	memberships, err := s.groupMembershipRepo.GetByGroupIDAndType(ctx, groupID, filter.PrincipalType, limit, offset)
 	if err != nil {
 		s.logger.Error("Failed to retrieve group members", zap.Error(err))
 		return nil, errors.NewInternalError(err)
 	}
 
+	var roles []*groupResponses.GroupRoleDetail
+	if filter.IncludeRoles && len(memberships) > 0 {
+		if roles, err = s.groupRoleDetails(ctx, groupID); err != nil {
+			s.logger.Error("Failed to retrieve group roles for members", zap.Error(err))
+			return nil, errors.NewInternalError(err)
+		}
+	}
+
 	// Convert to response format
+	now := time.Now()
 	responses := make([]*groupResponses.GroupMembershipResponse, len(memberships))
 	for i, membership := range memberships {
 		responses[i] = &groupResponses.GroupMembershipResponse{
@@ -996,22 +1254,66 @@ func (s *Service) GetGroupMembers(ctx context.Context, groupID string, limit, of
 			IsActive:      membership.IsActive,
 			AddedByID:     membership.AddedByID,
 			CreatedAt:     &membership.CreatedAt,
+			IsEffective:   membership.IsEffective(now),
+			Roles:         roles,
 		}
 	}
 
 	// Cache the results for small result sets
-	if limit <= 100 {
+	if useCache {
 		_ = s.groupCache.CacheGroupMembers(ctx, cacheKey, responses, true)
 	}
 
 	return responses, nil
 }
 
+// groupRoleDetails loads the active roles assigned to a group with their role details. Every
+// member of the group holds these roles while both the membership and the assignment are effective.
+func (s *Service) groupRoleDetails(ctx context.Context, groupID string) ([]*groupResponses.GroupRoleDetail, error) {
+	groupRoles, err := s.groupRoleRepo.GetByGroupID(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	details := make([]*groupResponses.GroupRoleDetail, 0, len(groupRoles))
+	for _, groupRole := range groupRoles {
+		detail := groupResponses.NewGroupRoleDetail(groupRole)
+		role, err := s.roleRepo.GetByID(ctx, groupRole.RoleID, &models.Role{})
+		if err != nil {
+			s.logger.Warn("Failed to load role details",
+				zap.String("role_id", groupRole.RoleID),
+				zap.Error(err))
+		} else if role != nil {
+			detail.Role = groupResponses.NewRoleDetail(role)
+		}
+		details = append(details, &detail)
+	}
+	return details, nil
+}
+
+// validatePrincipalTypeFilter accepts an empty principal type or one of the known types
+func validatePrincipalTypeFilter(principalType string) error {
+	switch models.PrincipalType(principalType) {
+	case "", models.PrincipalTypeUser, models.PrincipalTypeService:
+		return nil
+	}
+	return errors.NewValidationError(fmt.Sprintf("principal_type must be %q or %q", models.PrincipalTypeUser, models.PrincipalTypeService))
+}
+
 // CountGroupMembers returns the count of active members in a group
 func (s *Service) CountGroupMembers(ctx context.Context, groupID string) (int64, error) {
 	return s.groupMembershipRepo.CountByGroupID(ctx, groupID)
 }
 
+// CountGroupMembersByType returns the count of active members in a group of one principal type,
+// or of all types when principalType is empty
+func (s *Service) CountGroupMembersByType(ctx context.Context, groupID, principalType string) (int64, error) {
+	if err := validatePrincipalTypeFilter(principalType); err != nil {
+		return 0, err
+	}
+	return s.groupMembershipRepo.CountByGroupIDAndType(ctx, groupID, principalType)
+}
+
 // GetUserEffectiveRoles calculates and returns all effective roles for a user in an organization
 // using the role inheritance engine with upward inheritance
 func (s *Service) GetUserEffectiveRoles(ctx context.Context, orgID, userID string) (interface{}, error) {
@@ -1074,6 +1376,70 @@ func (s *Service) GetUserEffectiveRoles(ctx context.Context, orgID, userID strin
 	return effectiveRoles, nil
 }
 
+// GetUserScopedEffectiveRoles returns the effective roles of a user in an organization that are
+// relevant to scope: those assigned within a group's subtree and/or granting a permission on a
+// resource. An empty scope returns all effective roles.
+func (s *Service) GetUserScopedEffectiveRoles(ctx context.Context, orgID, userID string, scope interfaces.EffectiveRoleScope) (interface{}, error) {
+	if scope.IsEmpty() {
+		return s.GetUserEffectiveRoles(ctx, orgID, userID)
+	}
+
+	s.logger.Info("Getting user scoped effective roles",
+		zap.String("org_id", orgID),
+		zap.String("user_id", userID),
+		zap.String("group_id", scope.GroupID),
+		zap.String("resource_type", scope.ResourceType),
+		zap.String("resource_id", scope.ResourceID))
+
+	if orgID == "" {
+		return nil, errors.NewValidationError("org_id cannot be empty")
+	}
+	if userID == "" {
+		return nil, errors.NewValidationError("user_id cannot be empty")
+	}
+	if scope.ResourceType != "" && s.resourcePermRepo == nil {
+		return nil, errors.NewValidationError("filtering by resource is not supported")
+	}
+
+	org, err := s.orgRepo.GetByID(ctx, orgID)
+	if err != nil || org == nil {
+		s.logger.Warn("Organization not found", zap.String("org_id", orgID))
+		return nil, errors.NewNotFoundError("organization not found")
+	}
+	if !org.IsActive {
+		s.logger.Warn("Organization is inactive", zap.String("org_id", orgID))
+		return nil, errors.NewValidationError("cannot get roles for inactive organization")
+	}
+
+	inheritanceEngine := NewRoleInheritanceEngineWithRepos(
+		s.groupRepo,
+		s.groupRoleRepo,
+		s.roleRepo,
+		s.groupMembershipRepo,
+		s.cache,
+		s.logger,
+	)
+	if s.resourcePermRepo != nil {
+		inheritanceEngine.SetResourcePermissionRepository(s.resourcePermRepo)
+	}
+
+	effectiveRoles, err := inheritanceEngine.CalculateScopedEffectiveRoles(ctx, orgID, userID, scope)
+	if err != nil {
+		if errors.IsValidationError(err) || errors.IsNotFoundError(err) {
+			return nil, err
+		}
+		s.logger.Error("Failed to calculate scoped effective roles", zap.Error(err))
+		return nil, errors.NewInternalError(err)
+	}
+
+	s.logger.Info("User scoped effective roles calculated successfully",
+		zap.String("org_id", orgID),
+		zap.String("user_id", userID),
+		zap.Int("role_count", len(effectiveRoles)))
+
+	return effectiveRoles, nil
+}
+
 // checkCircularReference checks if setting a parent would create a circular reference
 func (s *Service) checkCircularReference(ctx context.Context, groupID, newParentID string) error {
 	// Start from the new parent and traverse up the hierarchy
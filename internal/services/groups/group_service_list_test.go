@@ -0,0 +1,225 @@
+package groups
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	groupResponses "github.com/Kisanlink/aaa-service/v2/internal/entities/responses/groups"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/internal/repositories/groups"
+	"github.com/Kisanlink/aaa-service/v2/internal/repositories/sorting"
+	"github.com/Kisanlink/kisanlink-db/pkg/base"
+	"github.com/Kisanlink/kisanlink-db/pkg/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// groupListDBManager serves groups and group roles from memory, evaluating the conditions the
+// group listing filters on the way Postgres would
+type groupListDBManager struct {
+	db.DBManager
+	groups     []*models.Group
+	groupRoles []*models.GroupRole
+}
+
+func (m *groupListDBManager) List(ctx context.Context, filter *base.Filter, model interface{}) error {
+	switch out := model.(type) {
+	case *[]*models.Group:
+		rows, err := m.matchingGroups(filter)
+		if err != nil {
+			return err
+		}
+		sort.SliceStable(rows, func(i, j int) bool {
+			for _, field := range filter.Sort {
+				cmp := strings.Compare(groupColumns(rows[i])[field.Field].(string), groupColumns(rows[j])[field.Field].(string))
+				if cmp == 0 {
+					continue
+				}
+				if field.Direction == "desc" {
+					return cmp > 0
+				}
+				return cmp < 0
+			}
+			return false
+		})
+		start, end := pageBounds(len(rows), filter)
+		*out = rows[start:end]
+	case *[]*models.GroupRole:
+		var rows []*models.GroupRole
+		for _, groupRole := range m.groupRoles {
+			if matchesConditions(filter, map[string]interface{}{
+				"role_id":   groupRole.RoleID,
+				"is_active": groupRole.IsActive,
+			}) {
+				rows = append(rows, groupRole)
+			}
+		}
+		*out = rows
+	default:
+		return fmt.Errorf("unexpected model %T", model)
+	}
+	return nil
+}
+
+func (m *groupListDBManager) Count(ctx context.Context, filter *base.Filter, model interface{}) (int64, error) {
+	rows, err := m.matchingGroups(filter)
+	return int64(len(rows)), err
+}
+
+func (m *groupListDBManager) matchingGroups(filter *base.Filter) ([]*models.Group, error) {
+	var rows []*models.Group
+	for _, group := range m.groups {
+		columns := groupColumns(group)
+		matches := true
+		for _, condition := range filter.Group.Conditions {
+			value, ok := columns[condition.Field]
+			if !ok {
+				return nil, fmt.Errorf("unknown column %q", condition.Field)
+			}
+			switch condition.Operator {
+			case base.OpEqual:
+				matches = matches && value == condition.Value
+			case base.OpContains:
+				matches = matches && strings.Contains(value.(string), condition.Value.(string))
+			case base.OpIsNull:
+				matches = matches && value == nil
+			case base.OpIn:
+				in := false
+				for _, candidate := range condition.Value.([]interface{}) {
+					in = in || candidate == value
+				}
+				matches = matches && in
+			default:
+				return nil, fmt.Errorf("unsupported operator %s", condition.Operator)
+			}
+		}
+		if matches {
+			rows = append(rows, group)
+		}
+	}
+	return rows, nil
+}
+
+func groupColumns(group *models.Group) map[string]interface{} {
+	var parentID, deletedAt interface{}
+	coalescedParentID := ""
+	if group.ParentID != nil {
+		parentID = *group.ParentID
+		coalescedParentID = *group.ParentID
+	}
+	if group.DeletedAt != nil {
+		deletedAt = *group.DeletedAt
+	}
+	return map[string]interface{}{
+		"id":                      group.ID,
+		"organization_id":         group.OrganizationID,
+		"name":                    group.Name,
+		"is_active":               group.IsActive,
+		"parent_id":               parentID,
+		"COALESCE(parent_id, '')": coalescedParentID,
+		"created_at":              group.CreatedAt.Format("2006-01-02T15:04:05.000000000"),
+		"updated_at":              group.UpdatedAt.Format("2006-01-02T15:04:05.000000000"),
+		"deleted_at":              deletedAt,
+	}
+}
+
+func newGroupListTestService() *Service {
+	emptyParent := ""
+	engineering := "GRP_ENG"
+	newGroup := func(id, name, orgID string, parentID *string, active bool) *models.Group {
+		group := models.NewGroup(name, "", orgID)
+		group.ID = id
+		group.ParentID = parentID
+		group.IsActive = active
+		return group
+	}
+
+	dbManager := &groupListDBManager{
+		groups: []*models.Group{
+			newGroup("GRP_ENG", "Engineering", "ORG1", nil, true),
+			newGroup("GRP_OPS", "Operations", "ORG1", &emptyParent, true), // moved back to the root
+			newGroup("GRP_OLD", "Old Engineering", "ORG1", nil, false),
+			newGroup("GRP_BE", "Backend Engineering", "ORG1", &engineering, true),
+			newGroup("GRP_FE", "Frontend Engineering", "ORG1", &engineering, true),
+			newGroup("GRP_QA", "QA", "ORG1", &engineering, false),
+			newGroup("GRP_OTHER", "Engineering", "ORG2", nil, true),
+		},
+		groupRoles: []*models.GroupRole{
+			models.NewGroupRole("GRP_BE", "ROLE_DEPLOY", "ORG1", "ADMIN"),
+			models.NewGroupRole("GRP_ENG", "ROLE_DEPLOY", "ORG1", "ADMIN"),
+			models.NewGroupRole("GRP_OTHER", "ROLE_DEPLOY", "ORG2", "ADMIN"),
+			models.NewGroupRole("GRP_FE", "ROLE_VIEW", "ORG1", "ADMIN"),
+		},
+	}
+
+	return &Service{
+		groupRepo:     groups.NewGroupRepository(dbManager),
+		groupRoleRepo: groups.NewGroupRoleRepository(dbManager),
+		logger:        zap.NewNop(),
+	}
+}
+
+func listedGroupIDs(t *testing.T, result interface{}) []string {
+	t.Helper()
+	responses, ok := result.([]*groupResponses.GroupResponse)
+	require.True(t, ok, "unexpected result %T", result)
+	ids := make([]string, len(responses))
+	for i, response := range responses {
+		ids[i] = response.ID
+	}
+	return ids
+}
+
+func TestService_ListOrganizationGroups_CombinedFilters(t *testing.T) {
+	service := newGroupListTestService()
+	active := true
+	engineering := "GRP_ENG"
+
+	order, err := sorting.Parse("name", "desc", sorting.GroupFields)
+	require.NoError(t, err)
+	ctx := sorting.WithOrder(context.Background(), order)
+
+	result, total, err := service.ListOrganizationGroups(ctx, "ORG1", interfaces.GroupFilter{
+		NameContains: "Engineering",
+		IsActive:     &active,
+		ParentID:     &engineering,
+	}, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Equal(t, []string{"GRP_FE", "GRP_BE"}, listedGroupIDs(t, result))
+
+	result, total, err = service.ListOrganizationGroups(ctx, "ORG1", interfaces.GroupFilter{
+		NameContains: "Engineering",
+		HasRole:      "ROLE_DEPLOY",
+	}, 1, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total, "the total counts every match, not the page")
+	assert.Equal(t, []string{"GRP_ENG"}, listedGroupIDs(t, result), "groups of other organizations holding the role are left out")
+
+	result, total, err = service.ListOrganizationGroups(ctx, "ORG1", interfaces.GroupFilter{HasRole: "ROLE_NONE"}, 10, 0)
+	require.NoError(t, err)
+	assert.Zero(t, total)
+	assert.Empty(t, listedGroupIDs(t, result))
+}
+
+func TestService_ListOrganizationGroups_RootGroups(t *testing.T) {
+	service := newGroupListTestService()
+	root := ""
+
+	result, total, err := service.ListOrganizationGroups(context.Background(), "ORG1", interfaces.GroupFilter{ParentID: &root}, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	assert.ElementsMatch(t, []string{"GRP_ENG", "GRP_OPS", "GRP_OLD"}, listedGroupIDs(t, result),
+		"root groups have no parent_id or an empty one")
+
+	inactive := false
+	result, total, err = service.ListOrganizationGroups(context.Background(), "ORG1", interfaces.GroupFilter{ParentID: &root, IsActive: &inactive}, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, []string{"GRP_OLD"}, listedGroupIDs(t, result))
+}
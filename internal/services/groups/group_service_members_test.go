@@ -0,0 +1,225 @@
+package groups
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	groupResponses "github.com/Kisanlink/aaa-service/v2/internal/entities/responses/groups"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/internal/repositories/groups"
+	"github.com/Kisanlink/aaa-service/v2/internal/repositories/roles"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/Kisanlink/kisanlink-db/pkg/base"
+	"github.com/Kisanlink/kisanlink-db/pkg/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// memberDBManager serves group memberships, group roles and roles from memory, applying the
+// equality conditions the repositories filter on
+type memberDBManager struct {
+	db.DBManager
+	memberships []*models.GroupMembership
+	groupRoles  []*models.GroupRole
+	roles       map[string]*models.Role
+}
+
+func (m *memberDBManager) List(ctx context.Context, filter *base.Filter, model interface{}) error {
+	switch out := model.(type) {
+	case *[]*models.GroupMembership:
+		rows := matchingMemberships(m.memberships, filter)
+		start, end := pageBounds(len(rows), filter)
+		*out = rows[start:end]
+	case *[]*models.GroupRole:
+		var rows []*models.GroupRole
+		for _, groupRole := range m.groupRoles {
+			if matchesConditions(filter, map[string]interface{}{
+				"group_id":  groupRole.GroupID,
+				"is_active": groupRole.IsActive,
+			}) {
+				rows = append(rows, groupRole)
+			}
+		}
+		*out = rows
+	default:
+		return fmt.Errorf("unexpected model %T", model)
+	}
+	return nil
+}
+
+func (m *memberDBManager) Count(ctx context.Context, filter *base.Filter, model interface{}) (int64, error) {
+	return int64(len(matchingMemberships(m.memberships, filter))), nil
+}
+
+func (m *memberDBManager) GetByID(ctx context.Context, id interface{}, model interface{}) error {
+	out, ok := model.(*models.Role)
+	if !ok {
+		return fmt.Errorf("unexpected model %T", model)
+	}
+	role, ok := m.roles[id.(string)]
+	if !ok {
+		return fmt.Errorf("role %v not found", id)
+	}
+	*out = *role
+	return nil
+}
+
+// memberCache is a map-backed cache for the member list cache
+type memberCache struct {
+	interfaces.CacheService
+	values map[string]interface{}
+}
+
+func (c *memberCache) Get(key string) (interface{}, bool) {
+	value, ok := c.values[key]
+	return value, ok
+}
+
+func (c *memberCache) Set(key string, value interface{}, ttl int) error {
+	c.values[key] = value
+	return nil
+}
+
+func matchingMemberships(memberships []*models.GroupMembership, filter *base.Filter) []*models.GroupMembership {
+	var rows []*models.GroupMembership
+	for _, membership := range memberships {
+		if matchesConditions(filter, map[string]interface{}{
+			"group_id":       membership.GroupID,
+			"principal_type": membership.PrincipalType,
+			"is_active":      membership.IsActive,
+		}) {
+			rows = append(rows, membership)
+		}
+	}
+	return rows
+}
+
+func matchesConditions(filter *base.Filter, fields map[string]interface{}) bool {
+	for _, condition := range filter.Group.Conditions {
+		if condition.Operator != base.OpEqual {
+			continue
+		}
+		if value, ok := fields[condition.Field]; ok && value != condition.Value {
+			return false
+		}
+	}
+	return true
+}
+
+func pageBounds(count int, filter *base.Filter) (int, int) {
+	start := filter.Offset
+	if start > count {
+		start = count
+	}
+	end := count
+	if filter.Limit > 0 && start+filter.Limit < count {
+		end = start + filter.Limit
+	}
+	return start, end
+}
+
+func newMembersTestService() *Service {
+	groupID := "GRP1"
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+
+	alice := models.NewGroupMembership(groupID, "USER1", string(models.PrincipalTypeUser), "ADMIN")
+	bob := models.NewGroupMembership(groupID, "USER2", string(models.PrincipalTypeUser), "ADMIN")
+	bob.StartsAt = &future
+	billing := models.NewGroupMembership(groupID, "SVC1", string(models.PrincipalTypeService), "ADMIN")
+	billing.EndsAt = &past
+	removed := models.NewGroupMembership(groupID, "USER3", string(models.PrincipalTypeUser), "ADMIN")
+	removed.IsActive = false
+	other := models.NewGroupMembership("GRP2", "USER4", string(models.PrincipalTypeUser), "ADMIN")
+
+	role := models.NewRole("auditor", "Reads audit logs", models.RoleScopeOrg)
+	groupRole := models.NewGroupRole(groupID, role.ID, "ORG1", "ADMIN")
+
+	dbManager := &memberDBManager{
+		memberships: []*models.GroupMembership{alice, bob, billing, removed, other},
+		groupRoles:  []*models.GroupRole{groupRole},
+		roles:       map[string]*models.Role{role.ID: role},
+	}
+
+	return &Service{
+		groupRoleRepo:       groups.NewGroupRoleRepository(dbManager),
+		groupMembershipRepo: groups.NewGroupMembershipRepository(dbManager),
+		roleRepo:            roles.NewRoleRepository(dbManager),
+		groupCache:          NewGroupCacheService(&memberCache{values: map[string]interface{}{}}, zap.NewNop()),
+		logger:              zap.NewNop(),
+	}
+}
+
+func principalIDs(members []*groupResponses.GroupMembershipResponse) []string {
+	ids := make([]string, len(members))
+	for i, member := range members {
+		ids[i] = member.PrincipalID
+	}
+	return ids
+}
+
+func TestService_ListGroupMembers_FiltersByPrincipalType(t *testing.T) {
+	service := newMembersTestService()
+	ctx := context.Background()
+
+	tests := []struct {
+		principalType string
+		expected      []string
+	}{
+		{"", []string{"USER1", "USER2", "SVC1"}},
+		{"user", []string{"USER1", "USER2"}},
+		{"service", []string{"SVC1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run("type="+tt.principalType, func(t *testing.T) {
+			result, err := service.ListGroupMembers(ctx, "GRP1", interfaces.GroupMemberFilter{PrincipalType: tt.principalType}, 10, 0)
+			require.NoError(t, err)
+			members := result.([]*groupResponses.GroupMembershipResponse)
+			assert.ElementsMatch(t, tt.expected, principalIDs(members))
+
+			count, err := service.CountGroupMembersByType(ctx, "GRP1", tt.principalType)
+			require.NoError(t, err)
+			assert.Equal(t, int64(len(tt.expected)), count)
+		})
+	}
+}
+
+func TestService_ListGroupMembers_RejectsUnknownPrincipalType(t *testing.T) {
+	service := newMembersTestService()
+	ctx := context.Background()
+
+	_, err := service.ListGroupMembers(ctx, "GRP1", interfaces.GroupMemberFilter{PrincipalType: "robot"}, 10, 0)
+	assert.True(t, errors.IsValidationError(err))
+
+	_, err = service.CountGroupMembersByType(ctx, "GRP1", "robot")
+	assert.True(t, errors.IsValidationError(err))
+}
+
+func TestService_ListGroupMembers_ReportsEffectivenessAndRoles(t *testing.T) {
+	service := newMembersTestService()
+
+	result, err := service.ListGroupMembers(context.Background(), "GRP1", interfaces.GroupMemberFilter{IncludeRoles: true}, 10, 0)
+	require.NoError(t, err)
+	members := result.([]*groupResponses.GroupMembershipResponse)
+	require.Len(t, members, 3)
+
+	effective := map[string]bool{}
+	for _, member := range members {
+		effective[member.PrincipalID] = member.IsEffective
+		require.Len(t, member.Roles, 1)
+		assert.Equal(t, "auditor", member.Roles[0].Role.Name)
+	}
+	assert.Equal(t, map[string]bool{"USER1": true, "USER2": false, "SVC1": false}, effective,
+		"memberships that have not started or have ended are not effective")
+
+	plain, err := service.ListGroupMembers(context.Background(), "GRP1", interfaces.GroupMemberFilter{}, 10, 0)
+	require.NoError(t, err)
+	for _, member := range plain.([]*groupResponses.GroupMembershipResponse) {
+		assert.Empty(t, member.Roles, "roles are only attached on request")
+	}
+}
@@ -0,0 +1,63 @@
+package groups
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestService_MoveGroup_Validation(t *testing.T) {
+	// Validation happens before any repository access, so nil dependencies are safe
+	service := &Service{
+		logger: zap.NewNop(),
+	}
+
+	tests := []struct {
+		name          string
+		groupID       string
+		newParentID   string
+		movedBy       string
+		expectedError string
+	}{
+		{
+			name:          "empty group ID",
+			groupID:       "",
+			newParentID:   "GRP_PARENT",
+			movedBy:       "user-789",
+			expectedError: "group_id cannot be empty",
+		},
+		{
+			name:          "empty new parent ID",
+			groupID:       "GRP_CHILD",
+			newParentID:   "",
+			movedBy:       "user-789",
+			expectedError: "new_parent_id cannot be empty",
+		},
+		{
+			name:          "empty moved by",
+			groupID:       "GRP_CHILD",
+			newParentID:   "GRP_PARENT",
+			movedBy:       "",
+			expectedError: "moved_by cannot be empty",
+		},
+		{
+			name:          "group as its own parent",
+			groupID:       "GRP_CHILD",
+			newParentID:   "GRP_CHILD",
+			movedBy:       "user-789",
+			expectedError: "a group cannot be its own parent",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := service.MoveGroup(context.Background(), tt.groupID, tt.newParentID, tt.movedBy)
+
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expectedError)
+			assert.Nil(t, result)
+		})
+	}
+}
@@ -33,6 +33,11 @@ type GroupMembershipRepositoryInterface interface {
 	GetUserDirectGroups(ctx context.Context, orgID, userID string) ([]*models.Group, error)
 }
 
+// ResourcePermissionRepositoryInterface defines the interface for resource permission repository operations
+type ResourcePermissionRepositoryInterface interface {
+	GetByRoleID(ctx context.Context, roleID string) ([]*models.ResourcePermission, error)
+}
+
 // RoleInheritanceEngine handles role inheritance calculations for hierarchical groups.
 //
 // INHERITANCE MODEL: Bottom-Up (Upward) Inheritance Only
@@ -67,6 +72,7 @@ type RoleInheritanceEngine struct {
 	groupRoleRepo       GroupRoleRepositoryInterface
 	roleRepo            RoleRepositoryInterface
 	groupMembershipRepo GroupMembershipRepositoryInterface
+	resourcePermRepo    ResourcePermissionRepositoryInterface // Optional, for resource-scoped calculations
 	cache               interfaces.CacheService
 	logger              *zap.Logger
 }
@@ -109,6 +115,11 @@ func NewRoleInheritanceEngineWithRepos(
 	}
 }
 
+// SetResourcePermissionRepository sets the repository used to filter effective roles by resource scope
+func (r *RoleInheritanceEngine) SetResourcePermissionRepository(repo ResourcePermissionRepositoryInterface) {
+	r.resourcePermRepo = repo
+}
+
 // EffectiveRole represents a role with its inheritance path and precedence
 type EffectiveRole struct {
 	Role            *models.Role `json:"role"`
@@ -187,7 +198,7 @@ func (r *RoleInheritanceEngine) CalculateEffectiveRoles(ctx context.Context, org
 			zap.String("group_name", directGroup.Name),
 			zap.String("user_id", userID))
 
-		groupRoles, err := r.calculateBottomUpRoles(ctx, directGroup, 0)
+		groupRoles, err := r.calculateBottomUpRoles(ctx, directGroup, 0, nil)
 		if err != nil {
 			r.logger.Error("Failed to calculate roles for group hierarchy",
 				zap.String("group_id", directGroup.ID),
@@ -199,40 +210,11 @@ func (r *RoleInheritanceEngine) CalculateEffectiveRoles(ctx context.Context, org
 			zap.String("group_id", directGroup.ID),
 			zap.Int("role_count", len(groupRoles)))
 
-		// Merge roles with conflict resolution (most specific wins)
-		// This ensures that when the same role exists at multiple levels,
-		// the one with the shortest distance (most specific) is kept
-		for roleID, effectiveRole := range groupRoles {
-			if existing, exists := allEffectiveRoles[roleID]; exists {
-				// Keep the role with the shortest distance (most specific)
-				if effectiveRole.Distance < existing.Distance {
-					r.logger.Debug("Role conflict resolved - keeping more specific role",
-						zap.String("role_id", roleID),
-						zap.Int("new_distance", effectiveRole.Distance),
-						zap.Int("old_distance", existing.Distance),
-						zap.String("winning_group", effectiveRole.GroupID))
-					allEffectiveRoles[roleID] = effectiveRole
-				} else {
-					r.logger.Debug("Role conflict resolved - keeping existing role",
-						zap.String("role_id", roleID),
-						zap.Int("existing_distance", existing.Distance),
-						zap.Int("new_distance", effectiveRole.Distance),
-						zap.String("winning_group", existing.GroupID))
-				}
-			} else {
-				allEffectiveRoles[roleID] = effectiveRole
-			}
-		}
+		r.mergeEffectiveRoles(allEffectiveRoles, groupRoles)
 	}
 
 	// 3. Convert map to slice and sort by precedence
-	effectiveRoles := make([]*EffectiveRole, 0, len(allEffectiveRoles))
-	for _, role := range allEffectiveRoles {
-		effectiveRoles = append(effectiveRoles, role)
-	}
-
-	// Sort by distance (most specific first), then by role name for consistency
-	r.sortEffectiveRolesByPrecedence(effectiveRoles)
+	effectiveRoles := r.sortedEffectiveRoles(allEffectiveRoles)
 
 	// Cache the result
 	err = r.cache.Set(cacheKey, effectiveRoles, 300) // Cache for 5 minutes
@@ -265,11 +247,23 @@ func (r *RoleInheritanceEngine) CalculateEffectiveRoles(ctx context.Context, org
 //
 // This allows executives to inherit permissions from their subordinates,
 // enabling them to perform any action their team members can perform.
-func (r *RoleInheritanceEngine) calculateBottomUpRoles(ctx context.Context, group *models.Group, currentDistance int) (map[string]*EffectiveRole, error) {
+//
+// A non-nil scope restricts the traversal to the subtree of the scope group: groups above it
+// contribute no roles of their own and only the children leading to it are visited.
+func (r *RoleInheritanceEngine) calculateBottomUpRoles(ctx context.Context, group *models.Group, currentDistance int, scope *groupScope) (map[string]*EffectiveRole, error) {
 	roles := make(map[string]*EffectiveRole)
 
-	// 1. Get direct roles assigned to this group
-	directRoles, err := r.getDirectGroupRoles(ctx, group.ID)
+	// Everything below the scope group is in scope
+	if scope != nil && group.ID == scope.groupID {
+		scope = nil
+	}
+
+	// 1. Get direct roles assigned to this group, unless it lies above the scope group
+	var directRoles []*models.GroupRole
+	var err error
+	if scope == nil {
+		directRoles, err = r.getDirectGroupRoles(ctx, group.ID)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get direct roles for group %s: %w", group.ID, err)
 	}
@@ -316,8 +310,11 @@ func (r *RoleInheritanceEngine) calculateBottomUpRoles(ctx context.Context, grou
 				zap.String("parent_group_id", group.ID))
 			continue
 		}
+		if scope != nil && !scope.leadsTo(childGroup.ID) {
+			continue
+		}
 
-		childRoles, err := r.calculateBottomUpRoles(ctx, childGroup, currentDistance+1)
+		childRoles, err := r.calculateBottomUpRoles(ctx, childGroup, currentDistance+1, scope)
 		if err != nil {
 			r.logger.Warn("Failed to calculate roles for child group",
 				zap.String("child_group_id", childGroup.ID),
@@ -354,6 +351,44 @@ func (r *RoleInheritanceEngine) calculateBottomUpRoles(ctx context.Context, grou
 	return roles, nil
 }
 
+// mergeEffectiveRoles merges the roles collected from one direct group into all, with conflict
+// resolution (most specific wins). This ensures that when the same role exists at multiple levels,
+// the one with the shortest distance (most specific) is kept.
+func (r *RoleInheritanceEngine) mergeEffectiveRoles(all, groupRoles map[string]*EffectiveRole) {
+	for roleID, effectiveRole := range groupRoles {
+		if existing, exists := all[roleID]; exists {
+			// Keep the role with the shortest distance (most specific)
+			if effectiveRole.Distance < existing.Distance {
+				r.logger.Debug("Role conflict resolved - keeping more specific role",
+					zap.String("role_id", roleID),
+					zap.Int("new_distance", effectiveRole.Distance),
+					zap.Int("old_distance", existing.Distance),
+					zap.String("winning_group", effectiveRole.GroupID))
+				all[roleID] = effectiveRole
+			} else {
+				r.logger.Debug("Role conflict resolved - keeping existing role",
+					zap.String("role_id", roleID),
+					zap.Int("existing_distance", existing.Distance),
+					zap.Int("new_distance", effectiveRole.Distance),
+					zap.String("winning_group", existing.GroupID))
+			}
+		} else {
+			all[roleID] = effectiveRole
+		}
+	}
+}
+
+// sortedEffectiveRoles returns the roles sorted by distance (most specific first), then by role
+// name for consistency
+func (r *RoleInheritanceEngine) sortedEffectiveRoles(all map[string]*EffectiveRole) []*EffectiveRole {
+	effectiveRoles := make([]*EffectiveRole, 0, len(all))
+	for _, role := range all {
+		effectiveRoles = append(effectiveRoles, role)
+	}
+	r.sortEffectiveRolesByPrecedence(effectiveRoles)
+	return effectiveRoles
+}
+
 // getUserDirectGroups gets all groups that a user is directly a member of
 func (r *RoleInheritanceEngine) getUserDirectGroups(ctx context.Context, orgID, userID string) ([]*models.Group, error) {
 	r.logger.Debug("Getting user's direct groups",
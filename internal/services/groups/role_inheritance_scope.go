@@ -0,0 +1,194 @@
+package groups
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// groupLookup is implemented by group repositories that load a single group; the engine needs it
+// to place a scope group in the hierarchy
+type groupLookup interface {
+	GetByID(ctx context.Context, id string) (*models.Group, error)
+}
+
+// groupScope prunes a role traversal to the subtree of one group
+type groupScope struct {
+	groupID   string
+	ancestors map[string]bool // the groups above the scope group
+}
+
+// leadsTo reports whether the traversal must visit groupID to reach the scope group's subtree
+func (s *groupScope) leadsTo(groupID string) bool {
+	return groupID == s.groupID || s.ancestors[groupID]
+}
+
+// CalculateScopedEffectiveRoles calculates the effective roles of a user like
+// CalculateEffectiveRoles, keeping only those relevant to scope:
+//   - GroupID: roles assigned to that group or its descendants. The traversal is pruned to the
+//     scope group's subtree, so other subtrees are never loaded.
+//   - ResourceType and ResourceID: roles holding an active permission on that resource, or on
+//     all resources of its type. This requires SetResourcePermissionRepository.
+//
+// Distances and inheritance paths are those of the full calculation. Scoped results are not cached.
+func (r *RoleInheritanceEngine) CalculateScopedEffectiveRoles(ctx context.Context, orgID, userID string, scope interfaces.EffectiveRoleScope) ([]*EffectiveRole, error) {
+	if scope.IsEmpty() {
+		return r.CalculateEffectiveRoles(ctx, orgID, userID)
+	}
+	if scope.ResourceID != "" && scope.ResourceType == "" {
+		return nil, errors.NewValidationError("resource_type is required with resource_id")
+	}
+	if scope.ResourceType != "" && r.resourcePermRepo == nil {
+		return nil, fmt.Errorf("resource scope requires a resource permission repository")
+	}
+
+	r.logger.Info("Calculating scoped effective roles for user",
+		zap.String("org_id", orgID),
+		zap.String("user_id", userID),
+		zap.String("group_id", scope.GroupID),
+		zap.String("resource_type", scope.ResourceType),
+		zap.String("resource_id", scope.ResourceID))
+
+	var traversalScope *groupScope
+	if scope.GroupID != "" {
+		var err error
+		traversalScope, err = r.loadGroupScope(ctx, orgID, scope.GroupID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	directGroups, err := r.getUserDirectGroups(ctx, orgID, userID)
+	if err != nil {
+		r.logger.Error("Failed to get user's direct groups", zap.Error(err))
+		return nil, err
+	}
+
+	allEffectiveRoles := make(map[string]*EffectiveRole)
+	for _, directGroup := range directGroups {
+		startScope := traversalScope
+		if startScope != nil && !startScope.leadsTo(directGroup.ID) {
+			// A direct group off the path to the scope group is in scope only below it
+			below, err := r.isDescendantOf(ctx, directGroup, startScope.groupID)
+			if err != nil {
+				r.logger.Warn("Failed to place direct group in the hierarchy",
+					zap.String("group_id", directGroup.ID),
+					zap.Error(err))
+				continue
+			}
+			if !below {
+				continue
+			}
+			startScope = nil
+		}
+
+		groupRoles, err := r.calculateBottomUpRoles(ctx, directGroup, 0, startScope)
+		if err != nil {
+			r.logger.Error("Failed to calculate roles for group hierarchy",
+				zap.String("group_id", directGroup.ID),
+				zap.Error(err))
+			continue
+		}
+		r.mergeEffectiveRoles(allEffectiveRoles, groupRoles)
+	}
+
+	if scope.ResourceType != "" {
+		for roleID := range allEffectiveRoles {
+			grants, err := r.grantsOnResource(ctx, roleID, scope.ResourceType, scope.ResourceID)
+			if err != nil {
+				return nil, err
+			}
+			if !grants {
+				delete(allEffectiveRoles, roleID)
+			}
+		}
+	}
+
+	effectiveRoles := r.sortedEffectiveRoles(allEffectiveRoles)
+
+	r.logger.Info("Calculated scoped effective roles for user",
+		zap.String("user_id", userID),
+		zap.Int("role_count", len(effectiveRoles)))
+
+	return effectiveRoles, nil
+}
+
+// loadGroupScope loads the scope group of an organization and the groups above it
+func (r *RoleInheritanceEngine) loadGroupScope(ctx context.Context, orgID, groupID string) (*groupScope, error) {
+	lookup, ok := r.groupRepo.(groupLookup)
+	if !ok {
+		return nil, fmt.Errorf("group scope requires a group repository that loads single groups")
+	}
+
+	group, err := lookup.GetByID(ctx, groupID)
+	if err != nil || group == nil || group.OrganizationID != orgID {
+		return nil, errors.NewNotFoundError("group not found in organization")
+	}
+
+	scope := &groupScope{groupID: groupID, ancestors: make(map[string]bool)}
+	for parentID := group.ParentID; parentID != nil && *parentID != ""; {
+		if scope.ancestors[*parentID] || *parentID == groupID {
+			return nil, fmt.Errorf("circular reference detected above group %s", groupID)
+		}
+		scope.ancestors[*parentID] = true
+
+		parent, err := lookup.GetByID(ctx, *parentID)
+		if err != nil || parent == nil {
+			break
+		}
+		parentID = parent.ParentID
+	}
+	return scope, nil
+}
+
+// isDescendantOf reports whether ancestorID is above group in the hierarchy
+func (r *RoleInheritanceEngine) isDescendantOf(ctx context.Context, group *models.Group, ancestorID string) (bool, error) {
+	lookup, ok := r.groupRepo.(groupLookup)
+	if !ok {
+		return false, fmt.Errorf("group scope requires a group repository that loads single groups")
+	}
+
+	visited := map[string]bool{group.ID: true}
+	for parentID := group.ParentID; parentID != nil && *parentID != ""; {
+		if *parentID == ancestorID {
+			return true, nil
+		}
+		if visited[*parentID] {
+			return false, fmt.Errorf("circular reference detected above group %s", group.ID)
+		}
+		visited[*parentID] = true
+
+		parent, err := lookup.GetByID(ctx, *parentID)
+		if err != nil {
+			return false, err
+		}
+		if parent == nil {
+			return false, nil
+		}
+		parentID = parent.ParentID
+	}
+	return false, nil
+}
+
+// grantsOnResource reports whether a role holds an active permission on a resource, or on all
+// resources of its type; an empty resourceID matches any resource of the type
+func (r *RoleInheritanceEngine) grantsOnResource(ctx context.Context, roleID, resourceType, resourceID string) (bool, error) {
+	permissions, err := r.resourcePermRepo.GetByRoleID(ctx, roleID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get resource permissions for role %s: %w", roleID, err)
+	}
+
+	for _, permission := range permissions {
+		if !permission.IsActive || permission.ResourceType != resourceType {
+			continue
+		}
+		if resourceID == "" || permission.ResourceID == resourceID || permission.ResourceID == "*" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,207 @@
+package groups
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// scopeGroupRepo is a group hierarchy that records the groups whose children were loaded
+type scopeGroupRepo struct {
+	groups   map[string]*models.Group
+	expanded []string
+}
+
+func (r *scopeGroupRepo) GetChildren(ctx context.Context, parentID string) ([]*models.Group, error) {
+	r.expanded = append(r.expanded, parentID)
+	var children []*models.Group
+	for _, group := range r.groups {
+		if group.ParentID != nil && *group.ParentID == parentID {
+			children = append(children, group)
+		}
+	}
+	return children, nil
+}
+
+func (r *scopeGroupRepo) GetByID(ctx context.Context, id string) (*models.Group, error) {
+	group, ok := r.groups[id]
+	if !ok {
+		return nil, fmt.Errorf("group %s not found", id)
+	}
+	return group, nil
+}
+
+type scopeGroupRoleRepo map[string][]*models.GroupRole
+
+func (r scopeGroupRoleRepo) GetByGroupID(ctx context.Context, groupID string) ([]*models.GroupRole, error) {
+	return r[groupID], nil
+}
+
+type scopeRoleRepo map[string]*models.Role
+
+func (r scopeRoleRepo) GetByID(ctx context.Context, id string, role *models.Role) (*models.Role, error) {
+	found, ok := r[id]
+	if !ok {
+		return nil, fmt.Errorf("role %s not found", id)
+	}
+	return found, nil
+}
+
+type scopeMembershipRepo []*models.Group
+
+func (r scopeMembershipRepo) GetUserDirectGroups(ctx context.Context, orgID, userID string) ([]*models.Group, error) {
+	return r, nil
+}
+
+type scopePermissionRepo map[string][]*models.ResourcePermission
+
+func (r scopePermissionRepo) GetByRoleID(ctx context.Context, roleID string) ([]*models.ResourcePermission, error) {
+	return r[roleID], nil
+}
+
+// newScopeTestEngine builds the hierarchy
+//
+//	HQ (hq-role)
+//	├── SALES (sales-role)
+//	│   └── SALES_EU (eu-role)
+//	└── OPS (ops-role)
+//
+// with the user a direct member of directGroupIDs
+func newScopeTestEngine(directGroupIDs ...string) (*RoleInheritanceEngine, *scopeGroupRepo) {
+	groups := &scopeGroupRepo{groups: map[string]*models.Group{}}
+	groupRoles := scopeGroupRoleRepo{}
+	roles := scopeRoleRepo{}
+	for _, g := range []struct{ id, parentID, roleID string }{
+		{"HQ", "", "hq-role"},
+		{"SALES", "HQ", "sales-role"},
+		{"SALES_EU", "SALES", "eu-role"},
+		{"OPS", "HQ", "ops-role"},
+	} {
+		group := models.NewGroup(g.id, "", "ORG1")
+		group.ID = g.id
+		if g.parentID != "" {
+			parentID := g.parentID
+			group.ParentID = &parentID
+		}
+		groups.groups[g.id] = group
+
+		role := models.NewRole(g.roleID, "", models.RoleScopeOrg)
+		role.ID = g.roleID
+		roles[g.roleID] = role
+		groupRoles[g.id] = []*models.GroupRole{models.NewGroupRole(g.id, g.roleID, "ORG1", "ADMIN1")}
+	}
+
+	var direct scopeMembershipRepo
+	for _, id := range directGroupIDs {
+		direct = append(direct, groups.groups[id])
+	}
+
+	engine := NewRoleInheritanceEngine(groups, groupRoles, roles, direct, &memberCache{values: map[string]interface{}{}}, zap.NewNop())
+	engine.SetResourcePermissionRepository(scopePermissionRepo{
+		"hq-role":    {models.NewResourcePermission("*", "aaa/user", "hq-role", "read")},
+		"sales-role": {models.NewResourcePermission("*", "farmers/farm", "sales-role", "read")},
+		"eu-role":    {models.NewResourcePermission("FARM1", "farmers/farm", "eu-role", "write")},
+		"ops-role":   {models.NewResourcePermission("FARM2", "farmers/farm", "ops-role", "write")},
+	})
+	return engine, groups
+}
+
+func effectiveRoleIDs(roles []*EffectiveRole) []string {
+	ids := make([]string, len(roles))
+	for i, role := range roles {
+		ids[i] = role.Role.ID
+	}
+	return ids
+}
+
+func TestCalculateScopedEffectiveRoles_GroupSubtree(t *testing.T) {
+	engine, groups := newScopeTestEngine("HQ")
+
+	all, err := engine.CalculateEffectiveRoles(context.Background(), "ORG1", "USER1")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"hq-role", "sales-role", "eu-role", "ops-role"}, effectiveRoleIDs(all))
+
+	groups.expanded = nil
+	roles, err := engine.CalculateScopedEffectiveRoles(context.Background(), "ORG1", "USER1", interfaces.EffectiveRoleScope{GroupID: "SALES"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"sales-role", "eu-role"}, effectiveRoleIDs(roles))
+	assert.Equal(t, 1, roles[0].Distance, "distances are those of the full calculation")
+	assert.Equal(t, []string{"HQ", "SALES", "SALES_EU"}, roles[1].InheritancePath)
+	assert.NotContains(t, groups.expanded, "OPS", "subtrees outside the scope are pruned")
+}
+
+func TestCalculateScopedEffectiveRoles_DirectGroupsInSeveralSubtrees(t *testing.T) {
+	engine, _ := newScopeTestEngine("SALES_EU", "OPS")
+
+	roles, err := engine.CalculateScopedEffectiveRoles(context.Background(), "ORG1", "USER1", interfaces.EffectiveRoleScope{GroupID: "SALES"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"eu-role"}, effectiveRoleIDs(roles))
+	assert.True(t, roles[0].IsDirectRole)
+
+	roles, err = engine.CalculateScopedEffectiveRoles(context.Background(), "ORG1", "USER1", interfaces.EffectiveRoleScope{GroupID: "OPS"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ops-role"}, effectiveRoleIDs(roles))
+
+	roles, err = engine.CalculateScopedEffectiveRoles(context.Background(), "ORG1", "USER1", interfaces.EffectiveRoleScope{GroupID: "HQ"})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"eu-role", "ops-role"}, effectiveRoleIDs(roles), "only roles the user holds are returned")
+}
+
+func TestCalculateScopedEffectiveRoles_Resource(t *testing.T) {
+	tests := []struct {
+		name     string
+		scope    interfaces.EffectiveRoleScope
+		expected []string
+	}{
+		{
+			name:     "resource type",
+			scope:    interfaces.EffectiveRoleScope{ResourceType: "farmers/farm"},
+			expected: []string{"sales-role", "eu-role", "ops-role"},
+		},
+		{
+			name:     "resource, including grants on all resources of the type",
+			scope:    interfaces.EffectiveRoleScope{ResourceType: "farmers/farm", ResourceID: "FARM1"},
+			expected: []string{"sales-role", "eu-role"},
+		},
+		{
+			name:     "resource within a group subtree",
+			scope:    interfaces.EffectiveRoleScope{GroupID: "OPS", ResourceType: "farmers/farm", ResourceID: "FARM1"},
+			expected: []string{},
+		},
+		{
+			name:     "resource no role grants",
+			scope:    interfaces.EffectiveRoleScope{ResourceType: "aaa/role"},
+			expected: []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine, _ := newScopeTestEngine("HQ")
+
+			roles, err := engine.CalculateScopedEffectiveRoles(context.Background(), "ORG1", "USER1", tt.scope)
+			require.NoError(t, err)
+			assert.ElementsMatch(t, tt.expected, effectiveRoleIDs(roles))
+		})
+	}
+}
+
+func TestCalculateScopedEffectiveRoles_InvalidScope(t *testing.T) {
+	engine, groups := newScopeTestEngine("HQ")
+	otherOrgGroup := models.NewGroup("Other", "", "ORG2")
+	otherOrgGroup.ID = "OTHER"
+	groups.groups["OTHER"] = otherOrgGroup
+
+	_, err := engine.CalculateScopedEffectiveRoles(context.Background(), "ORG1", "USER1", interfaces.EffectiveRoleScope{GroupID: "OTHER"})
+	assert.True(t, errors.IsNotFoundError(err), "groups of another organization are not a valid scope")
+
+	_, err = engine.CalculateScopedEffectiveRoles(context.Background(), "ORG1", "USER1", interfaces.EffectiveRoleScope{ResourceID: "FARM1"})
+	assert.True(t, errors.IsValidationError(err))
+}
@@ -0,0 +1,202 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/responses"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	jwt "github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	// ImpersonatePermission is the system permission required to start impersonation
+	ImpersonatePermission = "impersonate"
+	// ImpersonationTokenTTL is the lifetime of an impersonation token and its session
+	ImpersonationTokenTTL = 15 * time.Minute
+)
+
+// ActorClaim identifies the party acting on behalf of the token subject (RFC 8693 "act" claim)
+type ActorClaim struct {
+	Sub string `json:"sub"`
+}
+
+// ImpersonationTokenClaims are the claims of an impersonation token: sub is the impersonated user
+// and act.sub the admin who is really making the requests
+type ImpersonationTokenClaims struct {
+	TokenClaims
+	Act             ActorClaim `json:"act"`
+	ImpersonationID string     `json:"impersonation_id"`
+}
+
+// StartImpersonation mints a short-lived access token for targetUserID on behalf of adminID.
+// Callers must already have checked that adminID holds the system "impersonate" permission.
+// Super admins can only be impersonated by other super admins. The session is kept in the cache
+// so StopImpersonation can end it before the token expires.
+func (s *AuthService) StartImpersonation(ctx context.Context, adminID, targetUserID string) (*responses.ImpersonationResponse, error) {
+	if adminID == "" || targetUserID == "" {
+		return nil, errors.NewValidationError("admin ID and target user ID are required")
+	}
+	if adminID == targetUserID {
+		return nil, errors.NewValidationError("cannot impersonate yourself")
+	}
+
+	target, err := s.userRepository.GetByID(ctx, targetUserID, &models.User{})
+	if err != nil || target == nil {
+		return nil, errors.NewNotFoundError("user not found")
+	}
+
+	targetRoles, err := s.userRoleRepository.GetByUserID(ctx, targetUserID)
+	if err != nil {
+		s.logger.Error("Failed to get target user roles", zap.String("user_id", targetUserID), zap.Error(err))
+		return nil, errors.NewInternalError(fmt.Errorf("failed to retrieve user roles: %w", err))
+	}
+
+	if hasRoleNamed(targetRoles, "super_admin") {
+		adminRoles, err := s.userRoleRepository.GetByUserID(ctx, adminID)
+		if err != nil {
+			s.logger.Error("Failed to get impersonator roles", zap.String("user_id", adminID), zap.Error(err))
+			return nil, errors.NewInternalError(fmt.Errorf("failed to retrieve user roles: %w", err))
+		}
+		if !hasRoleNamed(adminRoles, "super_admin") {
+			s.logger.Warn("Impersonation of super admin refused",
+				zap.String("admin_id", adminID),
+				zap.String("target_user_id", targetUserID))
+			if s.auditService != nil {
+				s.auditService.LogAccessDenied(ctx, adminID, models.AuditActionStartImpersonation, "user", targetUserID, "only super admins can impersonate super admins")
+			}
+			return nil, errors.NewForbiddenError("only super admins can impersonate super admins")
+		}
+	}
+
+	impersonationID := "IMP_" + uuid.NewString()
+	if err := s.cacheService.Set(impersonationCacheKey(impersonationID), adminID, int(ImpersonationTokenTTL.Seconds())); err != nil {
+		return nil, errors.NewInternalError(fmt.Errorf("failed to store impersonation session: %w", err))
+	}
+	if !s.cacheService.Exists(impersonationCacheKey(impersonationID)) {
+		// Without a session record the token would be rejected on first use
+		return nil, errors.NewInternalError(fmt.Errorf("impersonation requires a cache backend"))
+	}
+
+	token, expiresAt, err := s.generateImpersonationToken(target, targetRoles, adminID, impersonationID)
+	if err != nil {
+		_ = s.cacheService.Delete(impersonationCacheKey(impersonationID))
+		return nil, errors.NewInternalError(fmt.Errorf("failed to generate impersonation token: %w", err))
+	}
+
+	if s.auditService != nil {
+		s.auditService.LogUserAction(ctx, adminID, models.AuditActionStartImpersonation, "user", targetUserID, map[string]interface{}{
+			"impersonation_id": impersonationID,
+			"expires_at":       expiresAt,
+		})
+	}
+
+	s.logger.Warn("Impersonation started",
+		zap.String("admin_id", adminID),
+		zap.String("target_user_id", targetUserID),
+		zap.String("impersonation_id", impersonationID))
+
+	return &responses.ImpersonationResponse{
+		ImpersonationID: impersonationID,
+		AccessToken:     token,
+		TokenType:       "Bearer",
+		ExpiresIn:       int64(ImpersonationTokenTTL.Seconds()),
+		ExpiresAt:       expiresAt,
+		ImpersonatorID:  adminID,
+		TargetUserID:    targetUserID,
+	}, nil
+}
+
+// StopImpersonation ends an impersonation session started by adminID; its token is rejected from then on
+func (s *AuthService) StopImpersonation(ctx context.Context, adminID, impersonationID string) error {
+	owner, ok := s.impersonationOwner(impersonationID)
+	if !ok {
+		return errors.NewNotFoundError("impersonation session not found or already ended")
+	}
+	if owner != adminID {
+		return errors.NewForbiddenError("impersonation session belongs to another user")
+	}
+
+	if err := s.cacheService.Delete(impersonationCacheKey(impersonationID)); err != nil {
+		return errors.NewInternalError(fmt.Errorf("failed to end impersonation session: %w", err))
+	}
+
+	if s.auditService != nil {
+		s.auditService.LogUserAction(ctx, adminID, models.AuditActionStopImpersonation, "impersonation", impersonationID, nil)
+	}
+
+	s.logger.Info("Impersonation stopped",
+		zap.String("admin_id", adminID),
+		zap.String("impersonation_id", impersonationID))
+
+	return nil
+}
+
+// IsImpersonationActive reports whether an impersonation session is still open for the given actor
+func (s *AuthService) IsImpersonationActive(impersonationID, actorID string) bool {
+	owner, ok := s.impersonationOwner(impersonationID)
+	return ok && owner == actorID
+}
+
+func (s *AuthService) impersonationOwner(impersonationID string) (string, bool) {
+	if impersonationID == "" {
+		return "", false
+	}
+	value, ok := s.cacheService.Get(impersonationCacheKey(impersonationID))
+	if !ok {
+		return "", false
+	}
+	owner, ok := value.(string)
+	return owner, ok
+}
+
+// generateImpersonationToken generates an access token for user that names actorID in the "act" claim.
+// Permissions are left out: they are resolved server-side against sub on every request.
+func (s *AuthService) generateImpersonationToken(user *models.User, roles []*models.UserRole, actorID, impersonationID string) (string, time.Time, error) {
+	username := ""
+	if user.Username != nil {
+		username = *user.Username
+	}
+	now := time.Now()
+	exp := now.Add(ImpersonationTokenTTL)
+
+	claims := &ImpersonationTokenClaims{
+		TokenClaims: TokenClaims{
+			UserID:      user.ID,
+			Username:    username,
+			IsValidated: user.IsValidated,
+			Roles:       roles,
+			TokenType:   "access",
+			RegisteredClaims: jwt.RegisteredClaims{
+				ExpiresAt: &jwt.NumericDate{Time: exp},
+				IssuedAt:  &jwt.NumericDate{Time: now.Add(-s.jwtCfg.Leeway / 2)},
+				NotBefore: &jwt.NumericDate{Time: now.Add(-s.jwtCfg.Leeway / 2)},
+				Subject:   user.ID,
+				Issuer:    s.jwtCfg.Issuer,
+				Audience:  []string{s.jwtCfg.Audience},
+			},
+		},
+		Act:             ActorClaim{Sub: actorID},
+		ImpersonationID: impersonationID,
+	}
+
+	token, err := s.signToken(claims)
+	return token, exp, err
+}
+
+func impersonationCacheKey(impersonationID string) string {
+	return fmt.Sprintf("impersonation:%s", impersonationID)
+}
+
+func hasRoleNamed(userRoles []*models.UserRole, name string) bool {
+	for _, userRole := range userRoles {
+		if userRole.Role.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,212 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/config"
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	jwt "github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type impersonationUserRepo struct {
+	interfaces.UserRepository
+	users map[string]*models.User
+}
+
+func (r *impersonationUserRepo) GetByID(ctx context.Context, id string, model *models.User) (*models.User, error) {
+	user, ok := r.users[id]
+	if !ok {
+		return nil, fmt.Errorf("user not found")
+	}
+	return user, nil
+}
+
+func (r *impersonationUserRepo) Update(ctx context.Context, user *models.User) error {
+	r.users[user.ID] = user
+	return nil
+}
+
+type impersonationUserRoleRepo struct {
+	interfaces.UserRoleRepository
+	roles map[string][]string
+}
+
+func (r *impersonationUserRoleRepo) GetByUserID(ctx context.Context, userID string) ([]*models.UserRole, error) {
+	var userRoles []*models.UserRole
+	for _, name := range r.roles[userID] {
+		userRoles = append(userRoles, &models.UserRole{UserID: userID, Role: models.Role{Name: name}})
+	}
+	return userRoles, nil
+}
+
+// memoryCache is a minimal in-process CacheService
+type memoryCache struct {
+	interfaces.CacheService
+	values map[string]interface{}
+}
+
+func (c *memoryCache) Get(key string) (interface{}, bool) {
+	value, ok := c.values[key]
+	return value, ok
+}
+
+func (c *memoryCache) Set(key string, value interface{}, ttl int) error {
+	c.values[key] = value
+	return nil
+}
+
+func (c *memoryCache) Delete(key string) error {
+	delete(c.values, key)
+	return nil
+}
+
+func (c *memoryCache) Exists(key string) bool {
+	_, ok := c.values[key]
+	return ok
+}
+
+func (c *memoryCache) Keys(pattern string) ([]string, error) {
+	var keys []string
+	for key := range c.values {
+		if ok, _ := path.Match(pattern, key); ok {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+type impersonationAuditRepo struct {
+	interfaces.AuditRepository
+	logs []*models.AuditLog
+}
+
+func (r *impersonationAuditRepo) Create(ctx context.Context, log *models.AuditLog) error {
+	r.logs = append(r.logs, log)
+	return nil
+}
+
+func newImpersonationTestService(t *testing.T, cache interfaces.CacheService, auditRepo *impersonationAuditRepo) *AuthService {
+	t.Helper()
+	userRepo := &impersonationUserRepo{users: map[string]*models.User{}}
+	for _, id := range []string{"ADMIN1", "ROOT1", "USER1", "ROOT2"} {
+		user := models.NewUser("9876543210", "+91", "hash")
+		user.ID = id
+		userRepo.users[id] = user
+	}
+	roleRepo := &impersonationUserRoleRepo{roles: map[string][]string{
+		"ADMIN1": {"admin"},
+		"ROOT1":  {"super_admin"},
+		"ROOT2":  {"super_admin"},
+		"USER1":  {"farmer"},
+	}}
+
+	jwtCfg := &config.JWTConfig{Secret: "test-secret", Issuer: "aaa-service", TTL: time.Hour, Leeway: time.Minute}
+	service, err := NewAuthService(userRepo, nil, roleRepo, cache, nil, NewAuditService(nil, auditRepo, nil, zap.NewNop()),
+		&AuthServiceConfig{JWTSecret: jwtCfg.Secret}, zap.NewNop(), nil, jwtCfg)
+	require.NoError(t, err)
+	return service
+}
+
+func TestStartImpersonation(t *testing.T) {
+	auditRepo := &impersonationAuditRepo{}
+	service := newImpersonationTestService(t, &memoryCache{values: map[string]interface{}{}}, auditRepo)
+
+	session, err := service.StartImpersonation(context.Background(), "ADMIN1", "USER1")
+	require.NoError(t, err)
+	assert.Equal(t, "ADMIN1", session.ImpersonatorID)
+	assert.Equal(t, "USER1", session.TargetUserID)
+	assert.Equal(t, int64(ImpersonationTokenTTL.Seconds()), session.ExpiresIn)
+
+	claims := &ImpersonationTokenClaims{}
+	_, err = jwt.ParseWithClaims(session.AccessToken, claims, func(*jwt.Token) (interface{}, error) { return []byte("test-secret"), nil })
+	require.NoError(t, err)
+	assert.Equal(t, "USER1", claims.Subject)
+	assert.Equal(t, "ADMIN1", claims.Act.Sub)
+	assert.Equal(t, session.ImpersonationID, claims.ImpersonationID)
+	assert.WithinDuration(t, time.Now().Add(ImpersonationTokenTTL), claims.ExpiresAt.Time, 5*time.Second)
+
+	assert.True(t, service.IsImpersonationActive(session.ImpersonationID, "ADMIN1"))
+	assert.False(t, service.IsImpersonationActive(session.ImpersonationID, "USER1"), "session is bound to its actor")
+
+	require.Len(t, auditRepo.logs, 1)
+	assert.Equal(t, models.AuditActionStartImpersonation, auditRepo.logs[0].Action)
+	assert.Equal(t, "ADMIN1", *auditRepo.logs[0].UserID)
+	assert.Equal(t, "USER1", *auditRepo.logs[0].ResourceID)
+}
+
+func TestStartImpersonation_SuperAdminTarget(t *testing.T) {
+	service := newImpersonationTestService(t, &memoryCache{values: map[string]interface{}{}}, &impersonationAuditRepo{})
+
+	_, err := service.StartImpersonation(context.Background(), "ADMIN1", "ROOT1")
+	require.Error(t, err)
+	assert.True(t, errors.IsForbiddenError(err), "admins cannot impersonate super admins")
+
+	_, err = service.StartImpersonation(context.Background(), "ROOT2", "ROOT1")
+	assert.NoError(t, err, "super admins can impersonate super admins")
+}
+
+func TestStartImpersonation_Rejected(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+		cache  interfaces.CacheService
+		check  func(error) bool
+	}{
+		{name: "self", target: "ADMIN1", cache: &memoryCache{values: map[string]interface{}{}}, check: errors.IsValidationError},
+		{name: "unknown user", target: "USER404", cache: &memoryCache{values: map[string]interface{}{}}, check: errors.IsNotFoundError},
+		{name: "no cache backend", target: "USER1", cache: &NoOpCacheService{}, check: errors.IsInternalError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newImpersonationTestService(t, tt.cache, &impersonationAuditRepo{})
+
+			_, err := service.StartImpersonation(context.Background(), "ADMIN1", tt.target)
+			require.Error(t, err)
+			assert.True(t, tt.check(err), err.Error())
+		})
+	}
+}
+
+func TestStopImpersonation(t *testing.T) {
+	auditRepo := &impersonationAuditRepo{}
+	service := newImpersonationTestService(t, &memoryCache{values: map[string]interface{}{}}, auditRepo)
+
+	session, err := service.StartImpersonation(context.Background(), "ADMIN1", "USER1")
+	require.NoError(t, err)
+
+	err = service.StopImpersonation(context.Background(), "ROOT1", session.ImpersonationID)
+	assert.True(t, errors.IsForbiddenError(err), "only the admin who started the session can stop it")
+
+	require.NoError(t, service.StopImpersonation(context.Background(), "ADMIN1", session.ImpersonationID))
+	assert.False(t, service.IsImpersonationActive(session.ImpersonationID, "ADMIN1"))
+	assert.Equal(t, models.AuditActionStopImpersonation, auditRepo.logs[len(auditRepo.logs)-1].Action)
+
+	err = service.StopImpersonation(context.Background(), "ADMIN1", session.ImpersonationID)
+	assert.True(t, errors.IsNotFoundError(err))
+}
+
+func TestAuditService_AttributesImpersonatedActions(t *testing.T) {
+	auditRepo := &impersonationAuditRepo{}
+	auditService := NewAuditService(nil, auditRepo, nil, zap.NewNop())
+
+	ctx := context.WithValue(context.Background(), "impersonator_id", "ADMIN1")
+	ctx = context.WithValue(ctx, "impersonation_id", "IMP_1")
+	auditService.LogUserAction(ctx, "USER1", models.AuditActionUpdateUser, "user", "USER1", nil)
+
+	require.Len(t, auditRepo.logs, 1)
+	log := auditRepo.logs[0]
+	assert.Equal(t, "USER1", *log.UserID)
+	require.NotNil(t, log.ActorID)
+	assert.Equal(t, "ADMIN1", *log.ActorID)
+	assert.Equal(t, "IMP_1", log.Details["impersonation_id"])
+}
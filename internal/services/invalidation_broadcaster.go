@@ -0,0 +1,203 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/go-redis/redis/v8"
+	"go.uber.org/zap"
+)
+
+// InvalidationChannel is the Redis pub/sub channel carrying cache invalidations between instances
+const InvalidationChannel = "aaa:cache:invalidations"
+
+// NewInvalidationBroadcaster returns a Redis pub/sub broadcaster sharing the cache's connection, or
+// a no-op broadcaster when the cache is not backed by Redis. Received invalidations are applied to
+// the cache, including its L1 tier if it has one, before subscribers run.
+func NewInvalidationBroadcaster(cache interfaces.CacheService, logger interfaces.Logger) interfaces.InvalidationBroadcaster {
+	redisCache, ok := redisCacheOf(cache)
+	if !ok {
+		logger.Info("Cache is not backed by Redis, cache invalidations stay local")
+		return NewNoOpInvalidationBroadcaster()
+	}
+	return NewRedisInvalidationBroadcaster(redisCache.client, cache, logger)
+}
+
+// RedisInvalidationBroadcaster publishes cache invalidations on InvalidationChannel and applies the
+// ones published by other instances
+type RedisInvalidationBroadcaster struct {
+	client     *redis.Client
+	cache      interfaces.CacheService
+	logger     interfaces.Logger
+	instanceID string
+
+	mu       sync.RWMutex
+	handlers []func(ctx context.Context, invalidation interfaces.CacheInvalidation)
+	pubsub   *redis.PubSub
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+// NewRedisInvalidationBroadcaster creates a broadcaster on the given Redis client. Call Start to
+// begin receiving invalidations from other instances.
+func NewRedisInvalidationBroadcaster(client *redis.Client, cache interfaces.CacheService, logger interfaces.Logger) *RedisInvalidationBroadcaster {
+	return &RedisInvalidationBroadcaster{
+		client:     client,
+		cache:      cache,
+		logger:     logger.Named("invalidation_broadcaster"),
+		instanceID: newInstanceID(),
+	}
+}
+
+// Start subscribes to InvalidationChannel and applies incoming invalidations until Close is called.
+// The subscription is re-established by the Redis client if the connection drops.
+func (b *RedisInvalidationBroadcaster) Start(ctx context.Context) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.pubsub != nil {
+		return
+	}
+
+	pubsub := b.client.Subscribe(ctx, InvalidationChannel)
+	ctx, cancel := context.WithCancel(ctx)
+	b.pubsub = pubsub
+	b.cancel = cancel
+	b.done = make(chan struct{})
+
+	go func(done chan struct{}) {
+		defer close(done)
+		for msg := range pubsub.Channel() {
+			b.receive(ctx, msg.Payload)
+		}
+	}(b.done)
+
+	b.logger.Info("Subscribed to cache invalidations",
+		zap.String("channel", InvalidationChannel),
+		zap.String("instance_id", b.instanceID))
+}
+
+// Publish sends an invalidation to the other instances
+func (b *RedisInvalidationBroadcaster) Publish(ctx context.Context, invalidation interfaces.CacheInvalidation) error {
+	if len(invalidation.Keys) == 0 && len(invalidation.Patterns) == 0 {
+		return nil
+	}
+
+	invalidation.Origin = b.instanceID
+	data, err := json.Marshal(invalidation)
+	if err != nil {
+		return fmt.Errorf("failed to marshal cache invalidation: %w", err)
+	}
+
+	if err := b.client.Publish(ctx, InvalidationChannel, data).Err(); err != nil {
+		b.logger.Warn("Failed to publish cache invalidation",
+			zap.Strings("keys", invalidation.Keys),
+			zap.Strings("patterns", invalidation.Patterns),
+			zap.Error(err))
+		return fmt.Errorf("failed to publish cache invalidation: %w", err)
+	}
+	return nil
+}
+
+// Subscribe registers a handler for invalidations published by other instances, such as one
+// evicting an in-process cache
+func (b *RedisInvalidationBroadcaster) Subscribe(handler func(ctx context.Context, invalidation interfaces.CacheInvalidation)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers = append(b.handlers, handler)
+}
+
+// Close stops receiving invalidations
+func (b *RedisInvalidationBroadcaster) Close() error {
+	b.mu.Lock()
+	pubsub, cancel, done := b.pubsub, b.cancel, b.done
+	b.pubsub, b.cancel, b.done = nil, nil, nil
+	b.mu.Unlock()
+
+	if pubsub == nil {
+		return nil
+	}
+
+	cancel()
+	err := pubsub.Close()
+	<-done
+	return err
+}
+
+// receive applies an invalidation published by another instance. The deletes are repeated on the
+// shared cache so entries written back while the publisher was invalidating are dropped as well.
+func (b *RedisInvalidationBroadcaster) receive(ctx context.Context, payload string) {
+	var invalidation interfaces.CacheInvalidation
+	if err := json.Unmarshal([]byte(payload), &invalidation); err != nil {
+		b.logger.Warn("Ignoring malformed cache invalidation", zap.Error(err))
+		return
+	}
+	if invalidation.Origin == b.instanceID {
+		return
+	}
+
+	for _, key := range invalidation.Keys {
+		if err := b.cache.Delete(key); err != nil {
+			b.logger.Warn("Failed to delete cache key", zap.String("key", key), zap.Error(err))
+		}
+	}
+	for _, pattern := range invalidation.Patterns {
+		keys, err := b.cache.Keys(pattern)
+		if err != nil {
+			b.logger.Warn("Failed to get cache keys", zap.String("pattern", pattern), zap.Error(err))
+			continue
+		}
+		for _, key := range keys {
+			if err := b.cache.Delete(key); err != nil {
+				b.logger.Warn("Failed to delete cache key", zap.String("key", key), zap.Error(err))
+			}
+		}
+	}
+
+	b.mu.RLock()
+	handlers := append([]func(context.Context, interfaces.CacheInvalidation){}, b.handlers...)
+	b.mu.RUnlock()
+	for _, handler := range handlers {
+		handler(ctx, invalidation)
+	}
+
+	b.logger.Debug("Applied cache invalidation",
+		zap.String("origin", invalidation.Origin),
+		zap.Int("keys", len(invalidation.Keys)),
+		zap.Int("patterns", len(invalidation.Patterns)))
+}
+
+// NoOpInvalidationBroadcaster is used without Redis: there is no other instance to notify
+type NoOpInvalidationBroadcaster struct{}
+
+// NewNoOpInvalidationBroadcaster creates a broadcaster that does nothing
+func NewNoOpInvalidationBroadcaster() interfaces.InvalidationBroadcaster {
+	return NoOpInvalidationBroadcaster{}
+}
+
+// Publish does nothing
+func (NoOpInvalidationBroadcaster) Publish(ctx context.Context, invalidation interfaces.CacheInvalidation) error {
+	return nil
+}
+
+// Subscribe does nothing; no invalidation ever arrives from elsewhere
+func (NoOpInvalidationBroadcaster) Subscribe(handler func(ctx context.Context, invalidation interfaces.CacheInvalidation)) {
+}
+
+// Close does nothing
+func (NoOpInvalidationBroadcaster) Close() error {
+	return nil
+}
+
+func newInstanceID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("instance-%p", &b)
+	}
+	return hex.EncodeToString(b)
+}
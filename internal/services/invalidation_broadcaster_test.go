@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type recordingBroadcaster struct {
+	interfaces.InvalidationBroadcaster
+	published []interfaces.CacheInvalidation
+}
+
+func (b *recordingBroadcaster) Publish(ctx context.Context, invalidation interfaces.CacheInvalidation) error {
+	b.published = append(b.published, invalidation)
+	return nil
+}
+
+func invalidationPayload(t *testing.T, invalidation interfaces.CacheInvalidation) string {
+	t.Helper()
+	data, err := json.Marshal(invalidation)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestRedisInvalidationBroadcaster_AppliesInvalidationsFromOtherInstances(t *testing.T) {
+	cache := &memoryCache{values: map[string]interface{}{
+		"role:ROLE1:permissions":        "[]",
+		"permission:USER1:farm:F1:view": "true",
+		"permission:USER1:farm:F2:edit": "false",
+		"permission:USER2:farm:F1:view": "true",
+	}}
+	broadcaster := NewRedisInvalidationBroadcaster(nil, cache, utils.NewLoggerAdapter(zap.NewNop()))
+
+	var received []interfaces.CacheInvalidation
+	broadcaster.Subscribe(func(ctx context.Context, invalidation interfaces.CacheInvalidation) {
+		received = append(received, invalidation)
+	})
+
+	broadcaster.receive(context.Background(), invalidationPayload(t, interfaces.CacheInvalidation{
+		Keys:     []string{"role:ROLE1:permissions"},
+		Patterns: []string{"permission:USER1:*"},
+		Origin:   "other-instance",
+	}))
+
+	assert.Equal(t, map[string]interface{}{"permission:USER2:farm:F1:view": "true"}, cache.values)
+	require.Len(t, received, 1)
+	assert.Equal(t, "other-instance", received[0].Origin)
+}
+
+func TestRedisInvalidationBroadcaster_IgnoresOwnAndMalformedMessages(t *testing.T) {
+	cache := &memoryCache{values: map[string]interface{}{"role:ROLE1": "{}"}}
+	broadcaster := NewRedisInvalidationBroadcaster(nil, cache, utils.NewLoggerAdapter(zap.NewNop()))
+
+	calls := 0
+	broadcaster.Subscribe(func(ctx context.Context, invalidation interfaces.CacheInvalidation) {
+		calls++
+	})
+
+	broadcaster.receive(context.Background(), invalidationPayload(t, interfaces.CacheInvalidation{
+		Keys:   []string{"role:ROLE1"},
+		Origin: broadcaster.instanceID,
+	}))
+	broadcaster.receive(context.Background(), "not json")
+
+	assert.Contains(t, cache.values, "role:ROLE1", "the publisher has already invalidated its own caches")
+	assert.Zero(t, calls)
+}
+
+func TestNewInvalidationBroadcaster_NoOpWithoutRedis(t *testing.T) {
+	loggerAdapter := utils.NewLoggerAdapter(zap.NewNop())
+	broadcaster := NewInvalidationBroadcaster(NewNoOpCacheService(loggerAdapter), loggerAdapter)
+
+	assert.IsType(t, NoOpInvalidationBroadcaster{}, broadcaster)
+	assert.NoError(t, broadcaster.Publish(context.Background(), interfaces.CacheInvalidation{Keys: []string{"role:ROLE1"}}))
+	assert.NoError(t, broadcaster.Close())
+}
+
+func TestRoleService_BroadcastsRoleAssignmentInvalidations(t *testing.T) {
+	userRoles := &bulkUserRoleRepo{assigned: map[string]bool{}}
+	service := newBulkRoleTestService(userRoles, &bulkAuditRecorder{})
+	broadcaster := &recordingBroadcaster{}
+	service.SetInvalidationBroadcaster(broadcaster)
+
+	_, err := service.AssignRoleToUsers(context.Background(), "ROLE1", []string{"USER1"}, "ADMIN1")
+	require.NoError(t, err)
+
+	require.Len(t, broadcaster.published, 2)
+	assert.Equal(t, []string{"user_roles:USER1", "user_with_roles:USER1"}, broadcaster.published[0].Keys)
+	assert.Equal(t, []string{"user:USER1:*", "permission:USER1:*"}, broadcaster.published[1].Patterns)
+}
@@ -0,0 +1,264 @@
+package kyc
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"go.uber.org/zap"
+)
+
+const (
+	// Callback event types
+	callbackEventVerified = "kyc.aadhaar.verified"
+	callbackEventFailed   = "kyc.aadhaar.failed"
+
+	// Callback delivery status stored on the verification record
+	callbackStatusPending   = "PENDING"
+	callbackStatusDelivered = "DELIVERED"
+	callbackStatusFailed    = "FAILED"
+
+	// Headers carrying the callback signature
+	callbackSignatureHeader = "X-KYC-Signature"
+	callbackTimestampHeader = "X-KYC-Timestamp"
+
+	defaultCallbackMaxAttempts = 5
+	defaultCallbackTimeout     = 10 * time.Second
+	callbackInitialBackoff     = 2 * time.Second
+)
+
+// CallbackPayload is the JSON body POSTed to a registered callback URL when a
+// verification reaches a terminal state. It intentionally carries no Aadhaar data;
+// consumers fetch details through the authenticated status endpoint.
+type CallbackPayload struct {
+	Event              string     `json:"event"`
+	VerificationID     string     `json:"verification_id"`
+	ReferenceID        string     `json:"reference_id"`
+	UserID             string     `json:"user_id"`
+	VerificationStatus string     `json:"verification_status"`
+	KYCStatus          string     `json:"kyc_status"`
+	Reason             string     `json:"reason,omitempty"`
+	VerifiedAt         *time.Time `json:"verified_at,omitempty"`
+	Timestamp          int64      `json:"timestamp"`
+}
+
+// CallbackSender delivers signed KYC completion callbacks with retry and backoff.
+// Callback URLs are supplied by API clients, so the sender only connects to public IP
+// addresses, never follows redirects, and, when allowed hosts are configured, only
+// calls those hosts.
+type CallbackSender struct {
+	secret         []byte
+	allowedHosts   map[string]bool
+	client         *http.Client
+	maxAttempts    int
+	initialBackoff time.Duration
+	logger         *zap.Logger
+}
+
+// NewCallbackSender creates a callback sender signing payloads with the given secret.
+// allowedHosts, when not empty, restricts callbacks to those host names.
+func NewCallbackSender(secret string, maxAttempts int, timeout time.Duration, allowedHosts []string, logger *zap.Logger) *CallbackSender {
+	if maxAttempts <= 0 {
+		maxAttempts = defaultCallbackMaxAttempts
+	}
+	if timeout <= 0 {
+		timeout = defaultCallbackTimeout
+	}
+
+	var hosts map[string]bool
+	if len(allowedHosts) > 0 {
+		hosts = make(map[string]bool, len(allowedHosts))
+		for _, host := range allowedHosts {
+			hosts[strings.ToLower(host)] = true
+		}
+	}
+
+	dialer := &net.Dialer{Timeout: timeout, Control: publicAddressOnly}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &CallbackSender{
+		secret:       []byte(secret),
+		allowedHosts: hosts,
+		client: &http.Client{
+			Timeout:   timeout,
+			Transport: transport,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		maxAttempts:    maxAttempts,
+		initialBackoff: callbackInitialBackoff,
+		logger:         logger,
+	}
+}
+
+// CheckURL reports whether callbacks may be sent to callbackURL
+func (c *CallbackSender) CheckURL(callbackURL string) error {
+	u, err := url.Parse(callbackURL)
+	if err != nil {
+		return fmt.Errorf("invalid callback URL: %w", err)
+	}
+	if c.allowedHosts != nil && !c.allowedHosts[strings.ToLower(u.Hostname())] {
+		return fmt.Errorf("callback host %q is not allowed", u.Hostname())
+	}
+	return nil
+}
+
+// publicAddressOnly is a dialer Control refusing connections to loopback, private,
+// link-local (including cloud metadata endpoints), multicast and unspecified
+// addresses. It runs after name resolution, so host names resolving to such
+// addresses are refused too.
+func publicAddressOnly(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return fmt.Errorf("callback address %q is not an IP address", host)
+	}
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() || isSharedAddress(ip) {
+		return fmt.Errorf("callback address %s is not a public address", ip)
+	}
+	return nil
+}
+
+// sharedAddressSpace is the carrier-grade NAT range 100.64.0.0/10, which net.IP does not
+// count as private
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+func isSharedAddress(ip net.IP) bool {
+	return sharedAddressSpace.Contains(ip)
+}
+
+// Sign computes the hex HMAC-SHA256 of "<timestamp>.<body>". Receivers recompute it
+// with the shared secret and compare against the X-KYC-Signature header.
+func (c *CallbackSender) Sign(timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Send POSTs the payload to callbackURL, retrying with exponential backoff on
+// transport errors, 5xx and 429 responses. Other 4xx responses are not retried.
+func (c *CallbackSender) Send(ctx context.Context, callbackURL string, payload *CallbackPayload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal callback payload: %w", err)
+	}
+
+	if err := c.CheckURL(callbackURL); err != nil {
+		return err
+	}
+
+	timestamp := strconv.FormatInt(payload.Timestamp, 10)
+	signature := c.Sign(timestamp, body)
+	backoff := c.initialBackoff
+
+	var lastErr error
+	for attempt := 0; attempt < c.maxAttempts; attempt++ {
+		// Wait for backoff period before retry (skip on first attempt)
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("callback cancelled: %w", ctx.Err())
+			case <-time.After(backoff):
+				backoff *= 2
+			}
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, callbackURL, bytes.NewReader(body))
+		if err != nil {
+			return fmt.Errorf("failed to create callback request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(callbackTimestampHeader, timestamp)
+		req.Header.Set(callbackSignatureHeader, signature)
+
+		resp, err := c.client.Do(req)
+		if err != nil {
+			lastErr = err
+		} else {
+			_ = resp.Body.Close()
+			if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+				return nil
+			}
+			lastErr = fmt.Errorf("callback endpoint returned status %d", resp.StatusCode)
+			if resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+				return lastErr
+			}
+		}
+
+		c.logger.Warn("KYC callback delivery failed",
+			zap.String("verification_id", payload.VerificationID),
+			zap.Int("attempt", attempt+1),
+			zap.Int("max_attempts", c.maxAttempts),
+			zap.Error(lastErr))
+	}
+
+	return fmt.Errorf("callback failed after %d attempts: %w", c.maxAttempts, lastErr)
+}
+
+// dispatchCallback delivers the terminal-state callback for a verification in the
+// background and records the delivery outcome. It is a no-op when no callback URL
+// was registered or callbacks are not configured.
+func (s *Service) dispatchCallback(verification *models.AadhaarVerification, reason string) {
+	if verification.CallbackURL == "" || s.callbackSender == nil {
+		return
+	}
+
+	event := callbackEventVerified
+	if verification.VerificationStatus != "VERIFIED" {
+		event = callbackEventFailed
+	}
+
+	payload := &CallbackPayload{
+		Event:              event,
+		VerificationID:     verification.ID,
+		ReferenceID:        verification.ReferenceID,
+		UserID:             verification.UserID,
+		VerificationStatus: verification.VerificationStatus,
+		KYCStatus:          verification.KYCStatus,
+		Reason:             reason,
+		VerifiedAt:         verification.OTPVerifiedAt,
+		Timestamp:          time.Now().Unix(),
+	}
+	callbackURL := verification.CallbackURL
+
+	go func() {
+		// Detached from the request context so delivery outlives the HTTP call
+		ctx := context.Background()
+
+		status := callbackStatusDelivered
+		if err := s.callbackSender.Send(ctx, callbackURL, payload); err != nil {
+			status = callbackStatusFailed
+			s.logger.Error("Giving up on KYC callback delivery",
+				zap.String("verification_id", payload.VerificationID),
+				zap.String("event", payload.Event),
+				zap.Error(err))
+		}
+
+		if err := s.aadhaarRepo.UpdateCallbackStatus(ctx, payload.VerificationID, status, timePtr(time.Now())); err != nil {
+			s.logger.Error("Failed to record KYC callback status",
+				zap.String("verification_id", payload.VerificationID),
+				zap.Error(err))
+		}
+	}()
+}
@@ -0,0 +1,168 @@
+package kyc
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// newTestCallbackSender returns a sender that may call the loopback test servers
+func newTestCallbackSender(maxAttempts int) *CallbackSender {
+	sender := NewCallbackSender("test-secret", maxAttempts, time.Second, nil, zap.NewNop())
+	sender.initialBackoff = time.Millisecond
+	sender.client.Transport = http.DefaultTransport
+	return sender
+}
+
+func TestCallbackSender_SendSignsPayload(t *testing.T) {
+	sender := newTestCallbackSender(3)
+	payload := &CallbackPayload{
+		Event:              callbackEventVerified,
+		VerificationID:     "VERIFY1",
+		ReferenceID:        testReferenceID,
+		UserID:             "USER1",
+		VerificationStatus: "VERIFIED",
+		KYCStatus:          "VERIFIED",
+		Timestamp:          1700000000,
+	}
+
+	var received CallbackPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		timestamp := r.Header.Get(callbackTimestampHeader)
+		if timestamp != "1700000000" {
+			t.Errorf("unexpected timestamp header %q", timestamp)
+		}
+		if got, want := r.Header.Get(callbackSignatureHeader), sender.Sign(timestamp, body); got != want {
+			t.Errorf("signature mismatch: got %q, want %q", got, want)
+		}
+		if err := json.Unmarshal(body, &received); err != nil {
+			t.Errorf("invalid callback body: %v", err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	if err := sender.Send(context.Background(), server.URL, payload); err != nil {
+		t.Fatalf("Send returned error: %v", err)
+	}
+	if received.VerificationID != "VERIFY1" || received.Event != callbackEventVerified {
+		t.Errorf("unexpected payload received: %+v", received)
+	}
+}
+
+func TestCallbackSender_RetriesServerErrors(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	err := newTestCallbackSender(3).Send(context.Background(), server.URL, &CallbackPayload{VerificationID: "VERIFY1"})
+	if err != nil {
+		t.Fatalf("expected delivery on third attempt, got %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 attempts, got %d", calls)
+	}
+}
+
+func TestCallbackSender_DoesNotRetryClientErrors(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	err := newTestCallbackSender(3).Send(context.Background(), server.URL, &CallbackPayload{VerificationID: "VERIFY1"})
+	if err == nil {
+		t.Fatal("expected error for 400 response")
+	}
+	if calls != 1 {
+		t.Errorf("expected a single attempt, got %d", calls)
+	}
+}
+
+func TestCallbackSender_GivesUpAfterMaxAttempts(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	err := newTestCallbackSender(2).Send(context.Background(), server.URL, &CallbackPayload{VerificationID: "VERIFY1"})
+	if err == nil {
+		t.Fatal("expected error after exhausting attempts")
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 attempts, got %d", calls)
+	}
+}
+
+func TestCallbackSender_RefusesNonPublicAddresses(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+	}))
+	defer server.Close()
+
+	sender := NewCallbackSender("test-secret", 1, time.Second, nil, zap.NewNop())
+	if err := sender.Send(context.Background(), server.URL, &CallbackPayload{VerificationID: "VERIFY1"}); err == nil {
+		t.Fatal("expected a loopback callback to be refused")
+	}
+	if calls != 0 {
+		t.Errorf("expected no request to reach the loopback server, got %d", calls)
+	}
+
+	for _, address := range []string{"127.0.0.1:443", "10.0.0.1:443", "192.168.1.1:443", "169.254.169.254:80", "100.64.0.1:443", "[::1]:443", "[fd00::1]:443", "0.0.0.0:443"} {
+		if err := publicAddressOnly("tcp", address, nil); err == nil {
+			t.Errorf("expected %s to be refused", address)
+		}
+	}
+	if err := publicAddressOnly("tcp", "203.0.113.10:443", nil); err != nil {
+		t.Errorf("expected a public address to be allowed, got %v", err)
+	}
+}
+
+func TestCallbackSender_DoesNotFollowRedirects(t *testing.T) {
+	var redirected int32
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&redirected, 1)
+	}))
+	defer target.Close()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, target.URL, http.StatusTemporaryRedirect)
+	}))
+	defer server.Close()
+
+	err := newTestCallbackSender(3).Send(context.Background(), server.URL, &CallbackPayload{VerificationID: "VERIFY1"})
+	if err == nil {
+		t.Fatal("expected a redirect to fail the delivery")
+	}
+	if redirected != 0 {
+		t.Errorf("expected the redirect not to be followed, got %d requests", redirected)
+	}
+}
+
+func TestCallbackSender_AllowedHosts(t *testing.T) {
+	sender := NewCallbackSender("test-secret", 1, time.Second, []string{"hooks.example.com"}, zap.NewNop())
+	if err := sender.CheckURL("https://Hooks.Example.com/kyc"); err != nil {
+		t.Errorf("expected an allowed host to pass, got %v", err)
+	}
+	if err := sender.CheckURL("https://evil.example.com/kyc"); err == nil {
+		t.Error("expected a host outside the allowlist to be refused")
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
 	kycRequests "github.com/Kisanlink/aaa-service/v2/internal/entities/requests/kyc"
 	kycResponses "github.com/Kisanlink/aaa-service/v2/internal/entities/responses/kyc"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
 	"go.uber.org/zap"
 )
 
@@ -26,6 +27,16 @@ func (s *Service) generateOTP(ctx context.Context, req *kycRequests.GenerateOTPR
 		return nil, fmt.Errorf("validation error: %w", err)
 	}
 
+	// Callback URLs are only accepted when signed delivery is configured
+	if req.CallbackURL != "" && s.callbackSender == nil {
+		return nil, errors.NewValidationError("callback_url is not supported: KYC callbacks are not configured")
+	}
+	if req.CallbackURL != "" {
+		if err := s.callbackSender.CheckURL(req.CallbackURL); err != nil {
+			return nil, errors.NewValidationError("invalid callback_url", err.Error())
+		}
+	}
+
 	// 2. Check rate limits (check recent attempts for this Aadhaar number)
 	if err := s.checkRateLimit(ctx, req.AadhaarNumber); err != nil {
 		s.logger.Warn("Rate limit exceeded",
@@ -64,6 +75,10 @@ func (s *Service) generateOTP(ctx context.Context, req *kycRequests.GenerateOTPR
 		Attempts:           0,
 		CreatedBy:          userID,
 	}
+	if req.CallbackURL != "" {
+		verification.CallbackURL = req.CallbackURL
+		verification.CallbackStatus = callbackStatusPending
+	}
 
 	if err := s.aadhaarRepo.Create(ctx, verification); err != nil {
 		s.logger.Error("Failed to create verification record",
@@ -17,6 +17,13 @@ type Config struct {
 	OTPMaxAttempts       int
 	OTPCooldownSeconds   int
 	PhotoMaxSizeMB       int
+
+	// Completion callbacks; callbacks are disabled when CallbackSecret is empty
+	CallbackSecret         string
+	CallbackMaxAttempts    int
+	CallbackTimeoutSeconds int
+	// CallbackAllowedHosts, when not empty, are the only hosts callbacks are sent to
+	CallbackAllowedHosts []string
 }
 
 // Service implements KYC operations for Aadhaar verification
@@ -26,6 +33,8 @@ type Service struct {
 	addressService AddressService
 	sandboxClient  *SandboxClient
 	auditService   AuditService
+	callbackSender *CallbackSender
+	roleActivator  RoleActivator
 	logger         *zap.Logger
 	config         *Config
 }
@@ -40,17 +49,41 @@ func NewService(
 	logger *zap.Logger,
 	config *Config,
 ) *Service {
+	var callbackSender *CallbackSender
+	if config != nil && config.CallbackSecret != "" {
+		callbackSender = NewCallbackSender(
+			config.CallbackSecret,
+			config.CallbackMaxAttempts,
+			time.Duration(config.CallbackTimeoutSeconds)*time.Second,
+			config.CallbackAllowedHosts,
+			logger,
+		)
+	}
+
 	return &Service{
 		aadhaarRepo:    aadhaarRepo,
 		userService:    userService,
 		addressService: addressService,
 		sandboxClient:  sandboxClient,
 		auditService:   auditService,
+		callbackSender: callbackSender,
 		logger:         logger,
 		config:         config,
 	}
 }
 
+// SetRoleActivator sets where the role assignments held back until a user's Aadhaar is verified
+// are activated on successful verification
+func (s *Service) SetRoleActivator(roleActivator RoleActivator) {
+	s.roleActivator = roleActivator
+}
+
+// RoleActivator defines the interface for activating KYC-gated role assignments
+type RoleActivator interface {
+	// ActivatePendingRoleAssignments assigns a user the roles pending their Aadhaar verification
+	ActivatePendingRoleAssignments(ctx context.Context, userID string) (int, error)
+}
+
 // UserService defines the interface for user profile operations
 type UserService interface {
 	// Update updates user profile fields
@@ -81,6 +81,8 @@ func (s *Service) verifyOTP(ctx context.Context, req *kycRequests.VerifyOTPReque
 					"expiration_seconds": s.config.OTPExpirationSeconds,
 				})
 
+			s.markVerificationFailed(ctx, verification, "otp_expired")
+
 			return nil, errors.NewBadRequestError("OTP has expired, please generate a new OTP")
 		}
 	}
@@ -125,6 +127,11 @@ func (s *Service) verifyOTP(ctx context.Context, req *kycRequests.VerifyOTPReque
 			"attempts":     verification.Attempts + 1,
 		})
 
+		// The last allowed attempt failing is terminal for this verification
+		if verification.Attempts+1 >= s.config.OTPMaxAttempts {
+			s.markVerificationFailed(ctx, verification, "max_attempts_exceeded")
+		}
+
 		// Return the typed error directly from sandbox client
 		return nil, err
 	}
@@ -229,6 +236,12 @@ func (s *Service) verifyOTP(ctx context.Context, req *kycRequests.VerifyOTPReque
 		// Continue anyway, as user profile is already updated
 	}
 
+	// Activate the KYC-gated roles the user was assigned before verifying
+	s.activatePendingRoles(ctx, userID)
+
+	// Notify the registered callback, if any, that verification completed
+	s.dispatchCallback(verification, "")
+
 	// 11. Log audit event for successful verification
 	s.auditService.LogUserAction(ctx, userID, "aadhaar_verified", "aadhaar_verification", verification.ID, map[string]interface{}{
 		"reference_id": verification.ReferenceID,
@@ -271,6 +284,47 @@ func (s *Service) verifyOTP(ctx context.Context, req *kycRequests.VerifyOTPReque
 	}, nil
 }
 
+// markVerificationFailed moves a pending verification to FAILED and notifies its
+// callback. Verifications already in a terminal state are left untouched so the
+// callback fires at most once.
+func (s *Service) markVerificationFailed(ctx context.Context, verification *models.AadhaarVerification, reason string) {
+	if verification.VerificationStatus != "PENDING" {
+		return
+	}
+
+	if err := s.aadhaarRepo.UpdateStatus(ctx, verification.ID, "FAILED"); err != nil {
+		s.logger.Error("Failed to mark verification as failed",
+			zap.String("verification_id", verification.ID),
+			zap.String("reason", reason),
+			zap.Error(err))
+		return
+	}
+	verification.VerificationStatus = "FAILED"
+
+	s.dispatchCallback(verification, reason)
+}
+
+// activatePendingRoles activates the role assignments held back until the user's Aadhaar was
+// verified. Failures are logged; the verification itself has succeeded.
+func (s *Service) activatePendingRoles(ctx context.Context, userID string) {
+	if s.roleActivator == nil {
+		return
+	}
+
+	activated, err := s.roleActivator.ActivatePendingRoleAssignments(ctx, userID)
+	if err != nil {
+		s.logger.Error("Failed to activate pending role assignments",
+			zap.String("user_id", userID),
+			zap.Error(err))
+		return
+	}
+	if activated > 0 {
+		s.logger.Info("Activated pending role assignments",
+			zap.String("user_id", userID),
+			zap.Int("count", activated))
+	}
+}
+
 // updateUserProfile updates user profile with verified Aadhaar data
 func (s *Service) updateUserProfile(ctx context.Context, userID string, kycData *KYCData, photoURL string, addressID string) error {
 	s.logger.Info("Updating user profile with Aadhaar data",
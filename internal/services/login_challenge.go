@@ -0,0 +1,202 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	configPkg "github.com/Kisanlink/aaa-service/v2/internal/config"
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/responses"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// Stepped login
+//
+// With AAA_LOGIN_REQUIRE_MPIN enabled, a password login of a user who has an MPIN does not issue
+// tokens. It moves through these states:
+//
+//	credentials verified ──► mpin_required ──(correct MPIN)──► success: tokens issued, challenge consumed
+//	                               │
+//	                               ├──(wrong MPIN, attempts left)──► mpin_required
+//	                               ├──(wrong MPIN, last attempt)───► failed: challenge discarded
+//	                               └──(TTL elapsed)────────────────► expired: challenge discarded
+//
+// A failed or expired login starts over with the password. The challenge token is random, single-use
+// and only its SHA-256 hash is kept in the cache.
+
+const (
+	// DefaultLoginChallengeTTL is how long an MPIN challenge can be redeemed when not configured
+	DefaultLoginChallengeTTL = 5 * time.Minute
+	// DefaultLoginChallengeMaxAttempts is the number of wrong MPINs a challenge allows when not configured
+	DefaultLoginChallengeMaxAttempts = 5
+)
+
+// loginChallenge is the cached state of an MPIN challenge
+type loginChallenge struct {
+	UserID    string    `json:"user_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// SetLoginStepUpConfig configures the stepped login
+func (s *AuthService) SetLoginStepUpConfig(cfg configPkg.LoginStepUpConfig) {
+	s.loginStepUp = cfg
+}
+
+// MPinStepUpRequired reports whether a password login of a user with an MPIN must be completed
+// with VerifyLoginMPIN
+func (s *AuthService) MPinStepUpRequired() bool {
+	return s.loginStepUp.RequireMPin
+}
+
+// LoginStepUp decides whether a login needs the MPIN step before tokens are issued and, if so,
+// starts it. It returns nil when the login can complete: the stepped login is off, the login did
+// not use the password or the user has no MPIN. Every login path, HTTP and gRPC, goes through it.
+func (s *AuthService) LoginStepUp(ctx context.Context, userID, method string, hasMPin bool) (*responses.LoginChallengeResponse, error) {
+	if !s.loginStepUp.RequireMPin || method != "password" || !hasMPin {
+		return nil, nil
+	}
+	return s.CreateLoginChallenge(ctx, userID)
+}
+
+// CreateLoginChallenge starts the MPIN step of a login for a user whose password was verified
+func (s *AuthService) CreateLoginChallenge(ctx context.Context, userID string) (*responses.LoginChallengeResponse, error) {
+	if userID == "" {
+		return nil, errors.NewValidationError("user ID is required")
+	}
+
+	token, err := generateChallengeToken()
+	if err != nil {
+		return nil, errors.NewInternalError(fmt.Errorf("failed to generate login challenge: %w", err))
+	}
+
+	ttl := s.loginChallengeTTL()
+	challenge := &loginChallenge{UserID: userID, ExpiresAt: time.Now().Add(ttl)}
+	if err := s.saveLoginChallenge(loginChallengeCacheKey(token), challenge); err != nil {
+		return nil, errors.NewInternalError(fmt.Errorf("failed to store login challenge: %w", err))
+	}
+
+	s.logger.Info("Login challenge issued", zap.String("user_id", userID), zap.Duration("ttl", ttl))
+
+	return &responses.LoginChallengeResponse{
+		Status:         responses.LoginStatusMPinRequired,
+		ChallengeToken: token,
+		ExpiresIn:      int64(ttl.Seconds()),
+		ExpiresAt:      challenge.ExpiresAt,
+		Message:        "MPIN verification required",
+	}, nil
+}
+
+// VerifyLoginMPIN completes a login waiting on its MPIN and returns the ID of the user to issue
+// tokens for. The challenge is consumed on success and discarded once it expires or runs out of
+// attempts; every failure is reported as unauthorized.
+func (s *AuthService) VerifyLoginMPIN(ctx context.Context, challengeToken, mpin string) (string, error) {
+	if challengeToken == "" || mpin == "" {
+		return "", errors.NewValidationError("challenge token and mpin are required")
+	}
+
+	key := loginChallengeCacheKey(challengeToken)
+	challenge, ok := s.loadLoginChallenge(key)
+	if !ok || !time.Now().Before(challenge.ExpiresAt) {
+		if ok {
+			_ = s.cacheService.Delete(key)
+		}
+		return "", errors.NewUnauthorizedError("login challenge expired or invalid")
+	}
+
+	// Every attempt is counted atomically before the MPIN is checked, so concurrent guesses cannot
+	// exceed the limit. The counter outlives a discarded challenge until the challenge would expire.
+	attempts, err := incrementCounter(ctx, s.cacheService, loginChallengeAttemptsKey(key), time.Until(challenge.ExpiresAt))
+	if err != nil {
+		return "", errors.NewInternalError(fmt.Errorf("failed to count login challenge attempt: %w", err))
+	}
+	maxAttempts := int64(s.loginChallengeMaxAttempts())
+	if attempts > maxAttempts {
+		_ = s.cacheService.Delete(key)
+		return "", errors.NewUnauthorizedError("too many invalid attempts, please log in again")
+	}
+
+	if err := s.VerifyMPin(ctx, challenge.UserID, mpin); err != nil {
+		s.recordLoginFailure(ctx, challenge.UserID, "password_mpin", "invalid_mpin")
+
+		if attempts >= maxAttempts {
+			_ = s.cacheService.Delete(key)
+			s.logger.Warn("Login challenge discarded after too many wrong MPINs", zap.String("user_id", challenge.UserID))
+			return "", errors.NewUnauthorizedError("too many invalid attempts, please log in again")
+		}
+		return "", errors.NewUnauthorizedError("invalid mPin")
+	}
+
+	if err := s.cacheService.Delete(key); err != nil {
+		return "", errors.NewInternalError(fmt.Errorf("failed to consume login challenge: %w", err))
+	}
+
+	s.logger.Info("Login challenge completed", zap.String("user_id", challenge.UserID))
+	return challenge.UserID, nil
+}
+
+func (s *AuthService) loginChallengeTTL() time.Duration {
+	if s.loginStepUp.ChallengeTTL > 0 {
+		return s.loginStepUp.ChallengeTTL
+	}
+	return DefaultLoginChallengeTTL
+}
+
+func (s *AuthService) loginChallengeMaxAttempts() int {
+	if s.loginStepUp.MaxAttempts > 0 {
+		return s.loginStepUp.MaxAttempts
+	}
+	return DefaultLoginChallengeMaxAttempts
+}
+
+// saveLoginChallenge caches the challenge until it expires. It is stored as a JSON string so it
+// reads back the same from every cache backend.
+func (s *AuthService) saveLoginChallenge(key string, challenge *loginChallenge) error {
+	ttl := int(time.Until(challenge.ExpiresAt).Seconds())
+	if ttl <= 0 {
+		return s.cacheService.Delete(key)
+	}
+	data, err := json.Marshal(challenge)
+	if err != nil {
+		return err
+	}
+	return s.cacheService.Set(key, string(data), ttl)
+}
+
+func (s *AuthService) loadLoginChallenge(key string) (*loginChallenge, bool) {
+	value, ok := s.cacheService.Get(key)
+	if !ok {
+		return nil, false
+	}
+	data, ok := value.(string)
+	if !ok {
+		return nil, false
+	}
+	var challenge loginChallenge
+	if err := json.Unmarshal([]byte(data), &challenge); err != nil || challenge.UserID == "" {
+		return nil, false
+	}
+	return &challenge, true
+}
+
+func loginChallengeCacheKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return fmt.Sprintf("login_challenge:%s", hex.EncodeToString(sum[:]))
+}
+
+// loginChallengeAttemptsKey names the wrong-MPIN counter of the challenge cached at key
+func loginChallengeAttemptsKey(key string) string {
+	return key + ":attempts"
+}
+
+func generateChallengeToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,199 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/config"
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/responses"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/internal/security"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/Kisanlink/aaa-service/v2/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func newLoginChallengeTestService(t *testing.T, cache interfaces.CacheService) *AuthService {
+	t.Helper()
+	hashedMPin, err := bcrypt.GenerateFromPassword([]byte("1234"), bcrypt.MinCost)
+	require.NoError(t, err)
+	mpin := string(hashedMPin)
+
+	user := models.NewUser("9876543210", "+91", "hash")
+	user.ID = "USER1"
+	user.MPin = &mpin
+
+	jwtCfg := &config.JWTConfig{Secret: "test-secret", Issuer: "aaa-service", TTL: time.Hour}
+	service, err := NewAuthService(&impersonationUserRepo{users: map[string]*models.User{"USER1": user}}, nil, nil, cache, nil, nil,
+		&AuthServiceConfig{JWTSecret: jwtCfg.Secret}, zap.NewNop(), nil, jwtCfg)
+	require.NoError(t, err)
+	service.SetLoginStepUpConfig(config.LoginStepUpConfig{RequireMPin: true, ChallengeTTL: time.Minute, MaxAttempts: 2})
+	return service
+}
+
+func TestLoginChallenge_VerifyMPin(t *testing.T) {
+	cache := &memoryCache{values: map[string]interface{}{}}
+	service := newLoginChallengeTestService(t, cache)
+	assert.True(t, service.MPinStepUpRequired())
+
+	challenge, err := service.CreateLoginChallenge(context.Background(), "USER1")
+	require.NoError(t, err)
+	assert.Equal(t, responses.LoginStatusMPinRequired, challenge.Status)
+	assert.Len(t, challenge.ChallengeToken, 64)
+	assert.Equal(t, int64(60), challenge.ExpiresIn)
+	assert.NotContains(t, cache.values, "login_challenge:"+challenge.ChallengeToken, "only the token hash is cached")
+
+	userID, err := service.VerifyLoginMPIN(context.Background(), challenge.ChallengeToken, "1234")
+	require.NoError(t, err)
+	assert.Equal(t, "USER1", userID)
+
+	_, err = service.VerifyLoginMPIN(context.Background(), challenge.ChallengeToken, "1234")
+	assert.True(t, errors.IsUnauthorizedError(err), "a challenge can only be redeemed once")
+}
+
+func TestLoginChallenge_WrongMPinUsesUpAttempts(t *testing.T) {
+	service := newLoginChallengeTestService(t, &memoryCache{values: map[string]interface{}{}})
+
+	challenge, err := service.CreateLoginChallenge(context.Background(), "USER1")
+	require.NoError(t, err)
+
+	_, err = service.VerifyLoginMPIN(context.Background(), challenge.ChallengeToken, "9999")
+	require.True(t, errors.IsUnauthorizedError(err))
+	assert.Equal(t, "invalid mPin", err.Error())
+
+	_, err = service.VerifyLoginMPIN(context.Background(), challenge.ChallengeToken, "9999")
+	require.True(t, errors.IsUnauthorizedError(err))
+	assert.Contains(t, err.Error(), "too many invalid attempts")
+
+	_, err = service.VerifyLoginMPIN(context.Background(), challenge.ChallengeToken, "1234")
+	assert.True(t, errors.IsUnauthorizedError(err), "the correct MPIN no longer works once the challenge is discarded")
+}
+
+func TestLoginChallenge_Expired(t *testing.T) {
+	cache := &memoryCache{values: map[string]interface{}{}}
+	service := newLoginChallengeTestService(t, cache)
+
+	t.Run("expired before the cache evicted it", func(t *testing.T) {
+		challenge, err := service.CreateLoginChallenge(context.Background(), "USER1")
+		require.NoError(t, err)
+
+		key := loginChallengeCacheKey(challenge.ChallengeToken)
+		expired, err := json.Marshal(&loginChallenge{UserID: "USER1", ExpiresAt: time.Now().Add(-time.Second)})
+		require.NoError(t, err)
+		cache.values[key] = string(expired)
+
+		_, err = service.VerifyLoginMPIN(context.Background(), challenge.ChallengeToken, "1234")
+		require.True(t, errors.IsUnauthorizedError(err))
+		assert.Equal(t, "login challenge expired or invalid", err.Error())
+		assert.NotContains(t, cache.values, key, "an expired challenge is discarded")
+	})
+
+	t.Run("evicted by the cache", func(t *testing.T) {
+		challenge, err := service.CreateLoginChallenge(context.Background(), "USER1")
+		require.NoError(t, err)
+		delete(cache.values, loginChallengeCacheKey(challenge.ChallengeToken))
+
+		_, err = service.VerifyLoginMPIN(context.Background(), challenge.ChallengeToken, "1234")
+		assert.True(t, errors.IsUnauthorizedError(err))
+	})
+
+	t.Run("unknown token", func(t *testing.T) {
+		_, err := service.VerifyLoginMPIN(context.Background(), "not-a-challenge", "1234")
+		assert.True(t, errors.IsUnauthorizedError(err))
+	})
+}
+
+func TestLoginChallenge_DisabledByDefault(t *testing.T) {
+	service, err := NewAuthService(nil, nil, nil, nil, nil, nil, &AuthServiceConfig{JWTSecret: "test-secret"}, zap.NewNop(), nil, nil)
+	require.NoError(t, err)
+	assert.False(t, service.MPinStepUpRequired())
+}
+
+func TestLoginChallenge_ConcurrentWrongMPinsStayWithinLimit(t *testing.T) {
+	cache := &lockedCache{memoryCache: memoryCache{values: map[string]interface{}{}}}
+	service := newLoginChallengeTestService(t, cache)
+
+	challenge, err := service.CreateLoginChallenge(context.Background(), "USER1")
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	var invalid int32
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := service.VerifyLoginMPIN(context.Background(), challenge.ChallengeToken, "9999")
+			if err != nil && err.Error() == "invalid mPin" {
+				atomic.AddInt32(&invalid, 1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), invalid, "only the attempts below the limit are checked as plain wrong MPINs")
+	_, err = service.VerifyLoginMPIN(context.Background(), challenge.ChallengeToken, "1234")
+	assert.True(t, errors.IsUnauthorizedError(err), "the challenge is gone once the attempts are used up")
+}
+
+func TestAuthService_LoginWithUsername_StepsUpToMPin(t *testing.T) {
+	password, err := bcrypt.GenerateFromPassword([]byte("secret123"), bcrypt.MinCost)
+	require.NoError(t, err)
+	mpin := "hashed-mpin"
+	user := models.NewUser("9876543210", "+91", string(password))
+	user.ID = "USER1"
+	user.MPin = &mpin
+
+	service, err := NewAuthService(&usernameUserRepo{user: user}, nil, nil, &memoryCache{values: map[string]interface{}{}}, nil, nil,
+		&AuthServiceConfig{JWTSecret: "test-secret"}, zap.NewNop(), utils.NewValidator(), nil)
+	require.NoError(t, err)
+	service.SetCredentialHasher(security.NewCredentialHasher(bcrypt.MinCost, bcrypt.MinCost))
+	service.SetLoginStepUpConfig(config.LoginStepUpConfig{RequireMPin: true})
+
+	response, err := service.LoginWithUsername(context.Background(), &UsernameLoginRequest{Username: "farmer1", Password: "secret123"})
+	require.NoError(t, err)
+	require.NotNil(t, response.Challenge)
+	assert.Equal(t, responses.LoginStatusMPinRequired, response.Challenge.Status)
+	assert.Empty(t, response.AccessToken)
+	assert.Empty(t, response.RefreshToken)
+}
+
+type usernameUserRepo struct {
+	interfaces.UserRepository
+	user *models.User
+}
+
+func (r *usernameUserRepo) GetByUsername(ctx context.Context, username string) (*models.User, error) {
+	return r.user, nil
+}
+
+// lockedCache is a memoryCache safe for concurrent use
+type lockedCache struct {
+	memoryCache
+	mu sync.Mutex
+}
+
+func (c *lockedCache) Get(key string) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.memoryCache.Get(key)
+}
+
+func (c *lockedCache) Set(key string, value interface{}, ttl int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.memoryCache.Set(key, value, ttl)
+}
+
+func (c *lockedCache) Delete(key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.memoryCache.Delete(key)
+}
@@ -0,0 +1,69 @@
+package notifications
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"go.uber.org/zap"
+)
+
+// SMSChannel sends notifications as SMS security alerts. Without an SMS service it only logs
+// the notification, so the channel can be enabled before SMS delivery is configured.
+type SMSChannel struct {
+	smsService interfaces.SMSService
+	logger     *zap.Logger
+}
+
+// NewSMSChannel creates an SMS channel; smsService may be nil
+func NewSMSChannel(smsService interfaces.SMSService, logger *zap.Logger) *SMSChannel {
+	return &SMSChannel{smsService: smsService, logger: logger}
+}
+
+// Send sends message to the recipient's phone number
+func (c *SMSChannel) Send(ctx context.Context, recipient *Recipient, subject, message string) error {
+	if recipient.PhoneNumber == "" {
+		return nil
+	}
+	if c.smsService == nil {
+		c.logger.Info("SMS service not configured, logging security notification",
+			zap.String("user_id", recipient.UserID),
+			zap.String("phone_masked", models.MaskPhoneNumberE164(recipient.PhoneNumber)),
+			zap.String("subject", subject))
+		return nil
+	}
+	return c.smsService.SendSecurityAlert(ctx, recipient.PhoneNumber, message)
+}
+
+// EmailChannel is a stub email channel: the service has no email provider yet, so it logs the
+// notification it would send
+type EmailChannel struct {
+	logger *zap.Logger
+}
+
+// NewEmailChannel creates the stub email channel
+func NewEmailChannel(logger *zap.Logger) *EmailChannel {
+	return &EmailChannel{logger: logger}
+}
+
+// Send logs the email that would be sent to the recipient
+func (c *EmailChannel) Send(ctx context.Context, recipient *Recipient, subject, message string) error {
+	if recipient.Email == "" {
+		return nil
+	}
+	c.logger.Info("Email provider not configured, logging security notification",
+		zap.String("user_id", recipient.UserID),
+		zap.String("email_masked", maskEmail(recipient.Email)),
+		zap.String("subject", subject))
+	return nil
+}
+
+// maskEmail keeps the first character of the local part and the domain
+func maskEmail(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at <= 0 {
+		return "***"
+	}
+	return email[:1] + "***" + email[at:]
+}
@@ -0,0 +1,89 @@
+package notifications
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+)
+
+// Channel names accepted in the AAA_NOTIFY_* variables
+const (
+	ChannelSMS   = "sms"
+	ChannelEmail = "email"
+)
+
+// Config selects the channels each security event is delivered on
+type Config struct {
+	// EventChannels maps a security event type to the channels it is sent on.
+	// Events without channels are not sent.
+	EventChannels map[string][]string
+
+	// QueueSize bounds the number of events waiting for delivery; further events are dropped
+	QueueSize int
+}
+
+// eventEnvVars maps each security event type to the variable that configures its channels
+var eventEnvVars = map[string]string{
+	interfaces.SecurityEventPasswordChanged: "AAA_NOTIFY_PASSWORD_CHANGED",
+	interfaces.SecurityEventMPinChanged:     "AAA_NOTIFY_MPIN_CHANGED",
+	interfaces.SecurityEventNewDeviceLogin:  "AAA_NOTIFY_NEW_DEVICE_LOGIN",
+	interfaces.SecurityEventAccountLocked:   "AAA_NOTIFY_ACCOUNT_LOCKED",
+	interfaces.SecurityEventSessionLimit:    "AAA_NOTIFY_SESSION_LIMIT",
+}
+
+// LoadConfigFromEnv loads notification configuration from environment variables.
+// Each event variable takes a comma-separated channel list (e.g. "sms,email"); unset disables the event.
+// Variables:
+//
+//	AAA_NOTIFY_PASSWORD_CHANGED
+//	AAA_NOTIFY_MPIN_CHANGED
+//	AAA_NOTIFY_NEW_DEVICE_LOGIN
+//	AAA_NOTIFY_ACCOUNT_LOCKED
+//	AAA_NOTIFY_SESSION_LIMIT
+//	AAA_NOTIFY_QUEUE_SIZE (optional; default 100)
+func LoadConfigFromEnv() *Config {
+	cfg := &Config{
+		EventChannels: make(map[string][]string, len(eventEnvVars)),
+		QueueSize:     100,
+	}
+
+	for event, envVar := range eventEnvVars {
+		if channels := parseChannels(os.Getenv(envVar)); len(channels) > 0 {
+			cfg.EventChannels[event] = channels
+		}
+	}
+
+	if value := os.Getenv("AAA_NOTIFY_QUEUE_SIZE"); value != "" {
+		if size, err := strconv.Atoi(value); err == nil && size > 0 {
+			cfg.QueueSize = size
+		}
+	}
+
+	return cfg
+}
+
+// Enabled reports whether any event is configured to be sent
+func (c *Config) Enabled() bool {
+	for _, channels := range c.EventChannels {
+		if len(channels) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func parseChannels(value string) []string {
+	var channels []string
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(value, ",") {
+		channel := strings.ToLower(strings.TrimSpace(part))
+		if channel == "" || seen[channel] {
+			continue
+		}
+		seen[channel] = true
+		channels = append(channels, channel)
+	}
+	return channels
+}
@@ -0,0 +1,197 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type sentNotification struct {
+	recipient Recipient
+	subject   string
+	message   string
+}
+
+type fakeChannel struct {
+	mu   sync.Mutex
+	sent []sentNotification
+	done chan struct{}
+}
+
+func newFakeChannel() *fakeChannel {
+	return &fakeChannel{done: make(chan struct{}, 10)}
+}
+
+func (c *fakeChannel) Send(ctx context.Context, recipient *Recipient, subject, message string) error {
+	c.mu.Lock()
+	c.sent = append(c.sent, sentNotification{recipient: *recipient, subject: subject, message: message})
+	c.mu.Unlock()
+	c.done <- struct{}{}
+	return nil
+}
+
+func (c *fakeChannel) wait(t *testing.T) sentNotification {
+	t.Helper()
+	select {
+	case <-c.done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("notification was not delivered")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.sent[len(c.sent)-1]
+}
+
+type staticResolver struct {
+	recipient *Recipient
+	err       error
+}
+
+func (r staticResolver) ResolveRecipient(ctx context.Context, userID string) (*Recipient, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	recipient := *r.recipient
+	recipient.UserID = userID
+	return &recipient, nil
+}
+
+func TestLoadConfigFromEnv(t *testing.T) {
+	t.Setenv("AAA_NOTIFY_PASSWORD_CHANGED", " SMS, email,sms ")
+	t.Setenv("AAA_NOTIFY_MPIN_CHANGED", "sms")
+	t.Setenv("AAA_NOTIFY_NEW_DEVICE_LOGIN", "")
+	t.Setenv("AAA_NOTIFY_ACCOUNT_LOCKED", "")
+	t.Setenv("AAA_NOTIFY_QUEUE_SIZE", "25")
+
+	cfg := LoadConfigFromEnv()
+
+	assert.True(t, cfg.Enabled())
+	assert.Equal(t, []string{ChannelSMS, ChannelEmail}, cfg.EventChannels[interfaces.SecurityEventPasswordChanged])
+	assert.Equal(t, []string{ChannelSMS}, cfg.EventChannels[interfaces.SecurityEventMPinChanged])
+	assert.NotContains(t, cfg.EventChannels, interfaces.SecurityEventNewDeviceLogin)
+	assert.Equal(t, 25, cfg.QueueSize)
+}
+
+func TestLoadConfigFromEnv_DisabledByDefault(t *testing.T) {
+	for _, envVar := range eventEnvVars {
+		t.Setenv(envVar, "")
+	}
+	t.Setenv("AAA_NOTIFY_QUEUE_SIZE", "not-a-number")
+
+	cfg := LoadConfigFromEnv()
+
+	assert.False(t, cfg.Enabled())
+	assert.Equal(t, 100, cfg.QueueSize)
+}
+
+func TestNewNotifier_NoopWhenDisabled(t *testing.T) {
+	notifier := NewNotifier(&Config{}, staticResolver{recipient: &Recipient{}}, nil, zap.NewNop())
+	assert.IsType(t, NoopNotifier{}, notifier)
+}
+
+func TestRenderMessage(t *testing.T) {
+	event := interfaces.SecurityEvent{
+		Type:       interfaces.SecurityEventNewDeviceLogin,
+		OccurredAt: time.Date(2025, 3, 10, 17, 30, 0, 0, time.FixedZone("IST", 5*3600+1800)),
+		IPAddress:  "203.0.113.7",
+		UserAgent:  "KisanlinkApp/2.1",
+	}
+
+	subject, message := RenderMessage(event)
+
+	assert.Equal(t, "New sign-in to your account", subject)
+	assert.Contains(t, message, "10 Mar 2025 12:00 UTC")
+	assert.Contains(t, message, "from IP 203.0.113.7")
+	assert.Contains(t, message, "(device: KisanlinkApp/2.1)")
+}
+
+func TestNotifier_DeliversOnConfiguredChannels(t *testing.T) {
+	sms := newFakeChannel()
+	email := newFakeChannel()
+	cfg := &Config{EventChannels: map[string][]string{
+		interfaces.SecurityEventPasswordChanged: {ChannelEmail},
+	}}
+	resolver := staticResolver{recipient: &Recipient{PhoneNumber: "+919876543210", Email: "farmer@example.com"}}
+	notifier := NewNotifier(cfg, resolver, map[string]Channel{ChannelSMS: sms, ChannelEmail: email}, zap.NewNop())
+
+	ctx := context.WithValue(context.Background(), "ip_address", "198.51.100.4")
+	notifier.NotifySecurityEvent(ctx, EventFromContext(ctx, interfaces.SecurityEventPasswordChanged, "USER1", nil))
+
+	sent := email.wait(t)
+	assert.Equal(t, "USER1", sent.recipient.UserID)
+	assert.Equal(t, "Your password was changed", sent.subject)
+	assert.Contains(t, sent.message, "198.51.100.4")
+	assert.Empty(t, sms.sent, "SMS is not configured for password changes")
+}
+
+func TestNotifier_IgnoresUnconfiguredEvents(t *testing.T) {
+	sms := newFakeChannel()
+	cfg := &Config{EventChannels: map[string][]string{
+		interfaces.SecurityEventPasswordChanged: {ChannelSMS},
+	}}
+	notifier := NewNotifier(cfg, staticResolver{recipient: &Recipient{PhoneNumber: "+919876543210"}}, map[string]Channel{ChannelSMS: sms}, zap.NewNop())
+
+	notifier.NotifySecurityEvent(context.Background(), interfaces.SecurityEvent{Type: interfaces.SecurityEventMPinChanged, UserID: "USER1"})
+	notifier.NotifySecurityEvent(context.Background(), interfaces.SecurityEvent{Type: interfaces.SecurityEventPasswordChanged, UserID: "USER1"})
+
+	sent := sms.wait(t)
+	assert.Equal(t, "Your password was changed", sent.subject)
+	assert.Len(t, sms.sent, 1)
+}
+
+type fakeContacts struct {
+	contacts []*models.Contact
+}
+
+func (f fakeContacts) GetByUserID(ctx context.Context, userID string, limit, offset int) ([]*models.Contact, error) {
+	return f.contacts, nil
+}
+
+type fakeUsers struct {
+	user *models.User
+	err  error
+}
+
+func (f fakeUsers) GetByID(ctx context.Context, id string, user *models.User) (*models.User, error) {
+	return f.user, f.err
+}
+
+func TestContactRecipientResolver(t *testing.T) {
+	countryCode := "+91"
+	signInPhone := "+919000000001"
+	user := &models.User{PhoneNumber: "9000000001", CountryCode: "+91", PhoneE164: &signInPhone}
+
+	t.Run("primary mobile contact", func(t *testing.T) {
+		contacts := fakeContacts{contacts: []*models.Contact{
+			{Type: "mobile", Value: "9000000002", CountryCode: &countryCode, IsActive: true},
+			{Type: "mobile", Value: "9000000003", CountryCode: &countryCode, IsPrimary: true, IsActive: true},
+		}}
+		recipient, err := NewContactRecipientResolver(contacts, fakeUsers{user: user}).ResolveRecipient(context.Background(), "USER1")
+		require.NoError(t, err)
+		assert.Equal(t, "+919000000003", recipient.PhoneNumber)
+		assert.Empty(t, recipient.Email)
+	})
+
+	t.Run("primary email contact keeps the sign-in number for SMS", func(t *testing.T) {
+		contacts := fakeContacts{contacts: []*models.Contact{
+			{Type: "email", Value: "farmer@example.com", IsPrimary: true, IsActive: true},
+		}}
+		recipient, err := NewContactRecipientResolver(contacts, fakeUsers{user: user}).ResolveRecipient(context.Background(), "USER1")
+		require.NoError(t, err)
+		assert.Equal(t, "farmer@example.com", recipient.Email)
+		assert.Equal(t, signInPhone, recipient.PhoneNumber)
+	})
+
+	t.Run("no contact", func(t *testing.T) {
+		_, err := NewContactRecipientResolver(fakeContacts{}, fakeUsers{err: errors.New("not found")}).ResolveRecipient(context.Background(), "USER1")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,191 @@
+// Package notifications tells users about security events on their account, such as a
+// password change or a sign-in from a new device, through SMS and email.
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"go.uber.org/zap"
+)
+
+// deliveryTimeout bounds the time spent resolving the recipient and sending one event
+const deliveryTimeout = 30 * time.Second
+
+// Recipient is where a user's notifications are sent
+type Recipient struct {
+	UserID      string
+	PhoneNumber string // E.164
+	Email       string
+}
+
+// RecipientResolver finds where to send a user's notifications
+type RecipientResolver interface {
+	ResolveRecipient(ctx context.Context, userID string) (*Recipient, error)
+}
+
+// Channel delivers a notification. Channels skip recipients they have no address for.
+type Channel interface {
+	Send(ctx context.Context, recipient *Recipient, subject, message string) error
+}
+
+// Notifier delivers security events to the user's primary contact on the channels configured for
+// each event type. Events are queued and sent by a background worker so callers never wait on delivery.
+type Notifier struct {
+	config   *Config
+	resolver RecipientResolver
+	channels map[string]Channel
+	logger   *zap.Logger
+	queue    chan interfaces.SecurityEvent
+}
+
+// NoopNotifier drops every event; it is used when no event has a channel configured
+type NoopNotifier struct{}
+
+// NotifySecurityEvent does nothing
+func (NoopNotifier) NotifySecurityEvent(ctx context.Context, event interfaces.SecurityEvent) {}
+
+// NewNotifier creates a notifier and starts its delivery worker. It returns a NoopNotifier when
+// the configuration does not send any event.
+func NewNotifier(config *Config, resolver RecipientResolver, channels map[string]Channel, logger *zap.Logger) interfaces.SecurityNotifier {
+	if config == nil || !config.Enabled() || resolver == nil {
+		logger.Info("Security notifications disabled")
+		return NoopNotifier{}
+	}
+
+	for event, names := range config.EventChannels {
+		for _, name := range names {
+			if _, ok := channels[name]; !ok {
+				logger.Warn("Ignoring unknown notification channel",
+					zap.String("event", event),
+					zap.String("channel", name))
+			}
+		}
+	}
+
+	queueSize := config.QueueSize
+	if queueSize <= 0 {
+		queueSize = 100
+	}
+
+	n := &Notifier{
+		config:   config,
+		resolver: resolver,
+		channels: channels,
+		logger:   logger,
+		queue:    make(chan interfaces.SecurityEvent, queueSize),
+	}
+	go n.run()
+
+	logger.Info("Security notifications enabled", zap.Any("event_channels", config.EventChannels))
+	return n
+}
+
+// NotifySecurityEvent queues an event for delivery. It never blocks: events with no configured
+// channel are ignored and events arriving while the queue is full are dropped.
+func (n *Notifier) NotifySecurityEvent(ctx context.Context, event interfaces.SecurityEvent) {
+	if len(n.channelsFor(event.Type)) == 0 {
+		return
+	}
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+
+	select {
+	case n.queue <- event:
+	default:
+		n.logger.Warn("Security notification queue full, dropping event",
+			zap.String("event", event.Type),
+			zap.String("user_id", event.UserID))
+	}
+}
+
+func (n *Notifier) run() {
+	for event := range n.queue {
+		n.deliver(event)
+	}
+}
+
+func (n *Notifier) deliver(event interfaces.SecurityEvent) {
+	ctx, cancel := context.WithTimeout(context.Background(), deliveryTimeout)
+	defer cancel()
+
+	recipient, err := n.resolver.ResolveRecipient(ctx, event.UserID)
+	if err != nil {
+		n.logger.Warn("Failed to resolve security notification recipient",
+			zap.String("event", event.Type),
+			zap.String("user_id", event.UserID),
+			zap.Error(err))
+		return
+	}
+
+	subject, message := RenderMessage(event)
+	for _, name := range n.channelsFor(event.Type) {
+		if err := n.channels[name].Send(ctx, recipient, subject, message); err != nil {
+			n.logger.Warn("Failed to send security notification",
+				zap.String("event", event.Type),
+				zap.String("user_id", event.UserID),
+				zap.String("channel", name),
+				zap.Error(err))
+		}
+	}
+}
+
+// channelsFor returns the configured channels for an event type that are available
+func (n *Notifier) channelsFor(eventType string) []string {
+	var names []string
+	for _, name := range n.config.EventChannels[eventType] {
+		if _, ok := n.channels[name]; ok {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// eventSubjects gives each security event type its notification subject
+var eventSubjects = map[string]string{
+	interfaces.SecurityEventPasswordChanged: "Your password was changed",
+	interfaces.SecurityEventMPinChanged:     "Your mPIN was changed",
+	interfaces.SecurityEventNewDeviceLogin:  "New sign-in to your account",
+	interfaces.SecurityEventAccountLocked:   "Your account was locked",
+	interfaces.SecurityEventSessionLimit:    "Your account reached its limit of active sign-ins",
+}
+
+// RenderMessage builds the subject and body of a security event notification, including when
+// it happened and, when known, the IP address and device it came from
+func RenderMessage(event interfaces.SecurityEvent) (string, string) {
+	subject, ok := eventSubjects[event.Type]
+	if !ok {
+		subject = "Security alert for your account"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Kisanlink: %s on %s UTC", subject, event.OccurredAt.UTC().Format("02 Jan 2006 15:04"))
+	if event.IPAddress != "" {
+		fmt.Fprintf(&b, " from IP %s", event.IPAddress)
+	}
+	if event.UserAgent != "" {
+		fmt.Fprintf(&b, " (device: %s)", event.UserAgent)
+	}
+	b.WriteString(". If this wasn't you, contact support immediately.")
+
+	return subject, b.String()
+}
+
+// EventFromContext builds a security event that happened now, taking the client IP address and
+// user agent from the request context when the auth middleware put them there
+func EventFromContext(ctx context.Context, eventType, userID string, details map[string]interface{}) interfaces.SecurityEvent {
+	ipAddress, _ := ctx.Value("ip_address").(string)
+	userAgent, _ := ctx.Value("user_agent").(string)
+	return interfaces.SecurityEvent{
+		Type:       eventType,
+		UserID:     userID,
+		OccurredAt: time.Now(),
+		IPAddress:  ipAddress,
+		UserAgent:  userAgent,
+		Details:    details,
+	}
+}
@@ -0,0 +1,85 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/pkg/phonenumber"
+)
+
+// ContactLister lists a user's contacts
+type ContactLister interface {
+	GetByUserID(ctx context.Context, userID string, limit, offset int) ([]*models.Contact, error)
+}
+
+// UserGetter loads a user by ID
+type UserGetter interface {
+	GetByID(ctx context.Context, id string, user *models.User) (*models.User, error)
+}
+
+// ContactRecipientResolver sends notifications to the user's primary contact. When the primary
+// contact is not a phone number, SMS goes to the phone number the user signs in with.
+type ContactRecipientResolver struct {
+	contacts ContactLister
+	users    UserGetter
+}
+
+// NewContactRecipientResolver creates a resolver; either source may be nil
+func NewContactRecipientResolver(contacts ContactLister, users UserGetter) *ContactRecipientResolver {
+	return &ContactRecipientResolver{contacts: contacts, users: users}
+}
+
+// ResolveRecipient returns the addresses to notify the user at
+func (r *ContactRecipientResolver) ResolveRecipient(ctx context.Context, userID string) (*Recipient, error) {
+	recipient := &Recipient{UserID: userID}
+
+	if r.contacts != nil {
+		contacts, err := r.contacts.GetByUserID(ctx, userID, 100, 0)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load contacts: %w", err)
+		}
+		if primary := primaryContact(contacts); primary != nil {
+			switch {
+			case primary.IsEmail():
+				recipient.Email = primary.Value
+			case primary.IsMobile(), primary.IsPhone():
+				countryCode := ""
+				if primary.CountryCode != nil {
+					countryCode = *primary.CountryCode
+				}
+				// A primary number that does not parse falls back to the sign-in number below
+				if e164, err := phonenumber.Normalize(primary.Value, countryCode); err == nil {
+					recipient.PhoneNumber = e164
+				}
+			}
+		}
+	}
+
+	if recipient.PhoneNumber == "" && r.users != nil {
+		user, err := r.users.GetByID(ctx, userID, &models.User{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to load user: %w", err)
+		}
+		if user.PhoneE164 != nil && *user.PhoneE164 != "" {
+			recipient.PhoneNumber = *user.PhoneE164
+		} else if e164, err := phonenumber.Normalize(user.PhoneNumber, user.CountryCode); err == nil {
+			recipient.PhoneNumber = e164
+		}
+	}
+
+	if recipient.PhoneNumber == "" && recipient.Email == "" {
+		return nil, fmt.Errorf("no contact for user %s", userID)
+	}
+	return recipient, nil
+}
+
+// primaryContact returns the user's active primary contact, if any
+func primaryContact(contacts []*models.Contact) *models.Contact {
+	for _, contact := range contacts {
+		if contact != nil && contact.IsPrimary && contact.IsActive {
+			return contact
+		}
+	}
+	return nil
+}
@@ -0,0 +1,586 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	configPkg "github.com/Kisanlink/aaa-service/v2/internal/config"
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/Kisanlink/aaa-service/v2/pkg/phonenumber"
+	jwt "github.com/golang-jwt/jwt/v4"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	oidcStateTTLSeconds   = 600
+	oidcMetadataCacheTTL  = time.Hour
+	oidcHTTPClientTimeout = 10 * time.Second
+)
+
+// OIDCContactRepository is the subset of contact persistence needed to record a provisioned user's email
+type OIDCContactRepository interface {
+	Create(ctx context.Context, contact *models.Contact) error
+}
+
+// OIDCService implements login through external OpenID Connect identity providers.
+// It runs the authorization code flow, validates the returned ID token, provisions or
+// links a local user and then issues our own token pair through AuthService.
+type OIDCService struct {
+	providers          map[string]*configPkg.OIDCProviderConfig
+	authService        *AuthService
+	userRepository     interfaces.UserRepository
+	identityRepository interfaces.UserIdentityRepository
+	contactRepository  OIDCContactRepository
+	roleService        interfaces.RoleService
+	cacheService       interfaces.CacheService
+	auditService       *AuditService
+	httpClient         *http.Client
+	logger             *zap.Logger
+
+	mu       sync.RWMutex
+	metadata map[string]*oidcProviderMetadata
+}
+
+// OIDCLoginResult is the outcome of a successful OIDC callback
+type OIDCLoginResult struct {
+	*LoginResponse
+	Provider    string `json:"provider"`
+	Provisioned bool   `json:"provisioned"`
+}
+
+// oidcProviderMetadata caches the discovery document and signing keys of a provider
+type oidcProviderMetadata struct {
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+	Issuer                string `json:"issuer"`
+
+	keys      map[string]*rsa.PublicKey
+	fetchedAt time.Time
+}
+
+// oidcIDTokenClaims are the ID token claims we rely on
+type oidcIDTokenClaims struct {
+	Email               string `json:"email"`
+	EmailVerified       bool   `json:"email_verified"`
+	PhoneNumber         string `json:"phone_number"`
+	PhoneNumberVerified bool   `json:"phone_number_verified"`
+	Name                string `json:"name"`
+	Nonce               string `json:"nonce"`
+	jwt.RegisteredClaims
+}
+
+// NewOIDCService creates a new OIDC login service
+func NewOIDCService(
+	providers map[string]*configPkg.OIDCProviderConfig,
+	authService *AuthService,
+	userRepository interfaces.UserRepository,
+	identityRepository interfaces.UserIdentityRepository,
+	contactRepository OIDCContactRepository,
+	roleService interfaces.RoleService,
+	cacheService interfaces.CacheService,
+	auditService *AuditService,
+	logger *zap.Logger,
+) *OIDCService {
+	return &OIDCService{
+		providers:          providers,
+		authService:        authService,
+		userRepository:     userRepository,
+		identityRepository: identityRepository,
+		contactRepository:  contactRepository,
+		roleService:        roleService,
+		cacheService:       cacheService,
+		auditService:       auditService,
+		httpClient:         &http.Client{Timeout: oidcHTTPClientTimeout},
+		logger:             logger,
+		metadata:           make(map[string]*oidcProviderMetadata),
+	}
+}
+
+// Enabled reports whether at least one provider is configured
+func (s *OIDCService) Enabled() bool {
+	return len(s.providers) > 0
+}
+
+// HasProvider reports whether the named provider is configured
+func (s *OIDCService) HasProvider(provider string) bool {
+	_, ok := s.providers[provider]
+	return ok
+}
+
+// AuthorizationURL builds the IdP redirect URL and records the state/nonce pair for the callback
+func (s *OIDCService) AuthorizationURL(ctx context.Context, provider string) (string, error) {
+	cfg, ok := s.providers[provider]
+	if !ok {
+		return "", errors.NewNotFoundError("identity provider not found")
+	}
+
+	meta, err := s.getMetadata(ctx, cfg, false)
+	if err != nil {
+		return "", errors.NewInternalError(err)
+	}
+
+	state, err := randomToken()
+	if err != nil {
+		return "", errors.NewInternalError(err)
+	}
+	nonce, err := randomToken()
+	if err != nil {
+		return "", errors.NewInternalError(err)
+	}
+
+	if err := s.cacheService.Set(oidcStateKey(state), map[string]interface{}{
+		"provider": provider,
+		"nonce":    nonce,
+	}, oidcStateTTLSeconds); err != nil {
+		return "", errors.NewInternalError(fmt.Errorf("failed to store OIDC state: %w", err))
+	}
+
+	params := url.Values{}
+	params.Set("response_type", "code")
+	params.Set("client_id", cfg.ClientID)
+	params.Set("redirect_uri", cfg.RedirectURL)
+	params.Set("scope", strings.Join(cfg.Scopes, " "))
+	params.Set("state", state)
+	params.Set("nonce", nonce)
+
+	separator := "?"
+	if strings.Contains(meta.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	return meta.AuthorizationEndpoint + separator + params.Encode(), nil
+}
+
+// HandleCallback exchanges the authorization code, validates the ID token and signs the user in
+func (s *OIDCService) HandleCallback(ctx context.Context, provider, code, state string) (*OIDCLoginResult, error) {
+	cfg, ok := s.providers[provider]
+	if !ok {
+		return nil, errors.NewNotFoundError("identity provider not found")
+	}
+	if code == "" || state == "" {
+		return nil, errors.NewBadRequestError("code and state are required")
+	}
+
+	nonce, err := s.consumeState(ctx, state, provider)
+	if err != nil {
+		return nil, err
+	}
+
+	meta, err := s.getMetadata(ctx, cfg, false)
+	if err != nil {
+		return nil, errors.NewInternalError(err)
+	}
+
+	rawIDToken, err := s.exchangeCode(ctx, cfg, meta, code)
+	if err != nil {
+		s.logger.Warn("OIDC code exchange failed", zap.String("provider", provider), zap.Error(err))
+		return nil, errors.NewUnauthorizedError("failed to exchange authorization code")
+	}
+
+	claims, err := s.verifyIDToken(ctx, cfg, rawIDToken, nonce)
+	if err != nil {
+		s.logger.Warn("OIDC ID token validation failed", zap.String("provider", provider), zap.Error(err))
+		return nil, errors.NewUnauthorizedError("invalid ID token")
+	}
+
+	user, provisioned, err := s.resolveUser(ctx, provider, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.DeletedAt != nil {
+		return nil, errors.NewUnauthorizedError("invalid credentials")
+	}
+	if user.Status != nil && *user.Status != "active" {
+		return nil, errors.NewUnauthorizedError("account is not active")
+	}
+
+	loginResponse, err := s.authService.IssueTokensForUser(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.auditService != nil {
+		s.auditService.LogUserAction(ctx, user.ID, "login", "user", user.ID, map[string]interface{}{
+			"method":      "oidc",
+			"provider":    provider,
+			"provisioned": provisioned,
+		})
+	}
+
+	s.logger.Info("User logged in via OIDC",
+		zap.String("user_id", user.ID),
+		zap.String("provider", provider),
+		zap.Bool("provisioned", provisioned))
+
+	return &OIDCLoginResult{
+		LoginResponse: loginResponse,
+		Provider:      provider,
+		Provisioned:   provisioned,
+	}, nil
+}
+
+// resolveUser finds the local user for an IdP subject, linking or provisioning one if needed
+func (s *OIDCService) resolveUser(ctx context.Context, provider string, claims *oidcIDTokenClaims) (*models.User, bool, error) {
+	// 1. Existing link: the IdP subject is authoritative, the email is not consulted
+	if identity, err := s.identityRepository.GetByProviderAndSubject(ctx, provider, claims.Subject); err == nil && identity != nil {
+		user, err := s.userRepository.GetByID(ctx, identity.UserID, &models.User{})
+		if err != nil {
+			return nil, false, errors.NewUnauthorizedError("invalid credentials")
+		}
+		return user, false, nil
+	}
+
+	// 2. Link to an existing account by verified email
+	email := strings.ToLower(strings.TrimSpace(claims.Email))
+	if email != "" && claims.EmailVerified {
+		if userID, err := s.identityRepository.GetUserIDByVerifiedEmail(ctx, email); err == nil && userID != "" {
+			// A user may only hold one subject per provider. A second subject claiming the
+			// same email means the address moved between IdP accounts; refuse to re-link.
+			exists, err := s.identityRepository.ExistsByUserAndProvider(ctx, userID, provider)
+			if err != nil {
+				return nil, false, errors.NewInternalError(err)
+			}
+			if exists {
+				s.logger.Warn("Refusing to link second OIDC subject to user",
+					zap.String("user_id", userID),
+					zap.String("provider", provider))
+				return nil, false, errors.NewConflictError("account is already linked to a different identity at this provider")
+			}
+
+			user, err := s.userRepository.GetByID(ctx, userID, &models.User{})
+			if err != nil {
+				return nil, false, errors.NewUnauthorizedError("invalid credentials")
+			}
+			if err := s.identityRepository.Create(ctx, models.NewUserIdentity(user.ID, provider, claims.Subject, email)); err != nil {
+				return nil, false, errors.NewInternalError(fmt.Errorf("failed to link identity: %w", err))
+			}
+			s.logger.Info("Linked OIDC identity to existing user",
+				zap.String("user_id", user.ID),
+				zap.String("provider", provider))
+			return user, false, nil
+		}
+	}
+
+	// 3. Provision a new account. Users are keyed by phone number, so the IdP must supply a verified one.
+	user, err := s.provisionUser(ctx, provider, claims, email)
+	if err != nil {
+		return nil, false, err
+	}
+	return user, true, nil
+}
+
+// provisionUser creates a local user from verified IdP claims
+func (s *OIDCService) provisionUser(ctx context.Context, provider string, claims *oidcIDTokenClaims, email string) (*models.User, error) {
+	if claims.PhoneNumber == "" || !claims.PhoneNumberVerified {
+		return nil, errors.NewForbiddenError("no account is linked to this identity; a verified phone number is required to create one")
+	}
+
+	countryCode, phoneNumber, err := splitE164(claims.PhoneNumber)
+	if err != nil {
+		return nil, errors.NewBadRequestError("identity provider returned an invalid phone number")
+	}
+
+	if existing, err := s.userRepository.GetByPhoneNumber(ctx, phoneNumber, countryCode); err == nil && existing != nil {
+		return nil, errors.NewConflictError("an account with this phone number already exists; sign in and link the identity instead")
+	}
+
+	// OIDC users authenticate at the IdP; store an unusable random password hash
+	secret, err := randomToken()
+	if err != nil {
+		return nil, errors.NewInternalError(err)
+	}
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(secret), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, errors.NewInternalError(fmt.Errorf("failed to hash password: %w", err))
+	}
+
+	user := models.NewUser(phoneNumber, countryCode, string(hashedPassword))
+	status := "active"
+	user.Status = &status
+	if err := s.userRepository.Create(ctx, user); err != nil {
+		return nil, errors.NewInternalError(fmt.Errorf("failed to create user: %w", err))
+	}
+
+	if err := s.identityRepository.Create(ctx, models.NewUserIdentity(user.ID, provider, claims.Subject, email)); err != nil {
+		return nil, errors.NewInternalError(fmt.Errorf("failed to link identity: %w", err))
+	}
+
+	if email != "" && claims.EmailVerified && s.contactRepository != nil {
+		contact := models.NewEmailContact(user.ID, email)
+		contact.IsVerified = true
+		verifiedBy := "oidc:" + provider
+		contact.VerifiedBy = &verifiedBy
+		if err := s.contactRepository.Create(ctx, contact); err != nil {
+			s.logger.Warn("Failed to store email contact for OIDC user", zap.String("user_id", user.ID), zap.Error(err))
+		}
+	}
+
+	if s.roleService != nil {
+		if defaultRole, err := s.roleService.GetRoleByName(ctx, "user"); err == nil {
+			if err := s.roleService.AssignRoleToUser(ctx, user.ID, defaultRole.ID); err != nil {
+				s.logger.Warn("Failed to assign default role to OIDC user", zap.String("user_id", user.ID), zap.Error(err))
+			}
+		}
+	}
+
+	if s.auditService != nil {
+		s.auditService.LogUserAction(ctx, user.ID, "register", "user", user.ID, map[string]interface{}{
+			"method":   "oidc",
+			"provider": provider,
+		})
+	}
+
+	return user, nil
+}
+
+// consumeState validates and deletes a one-time state value, returning the stored nonce. The
+// state is read and deleted atomically, so concurrent callbacks cannot both redeem it.
+func (s *OIDCService) consumeState(ctx context.Context, state, provider string) (string, error) {
+	cached, exists, err := takeValue(ctx, s.cacheService, oidcStateKey(state))
+	if err != nil {
+		return "", errors.NewInternalError(fmt.Errorf("failed to consume OIDC state: %w", err))
+	}
+	if !exists {
+		return "", errors.NewUnauthorizedError("invalid or expired state")
+	}
+
+	data, ok := cached.(map[string]interface{})
+	if !ok {
+		return "", errors.NewUnauthorizedError("invalid or expired state")
+	}
+	storedProvider, _ := data["provider"].(string)
+	nonce, _ := data["nonce"].(string)
+	if storedProvider != provider || nonce == "" {
+		return "", errors.NewUnauthorizedError("invalid or expired state")
+	}
+	return nonce, nil
+}
+
+// exchangeCode redeems an authorization code at the token endpoint and returns the raw ID token
+func (s *OIDCService) exchangeCode(ctx context.Context, cfg *configPkg.OIDCProviderConfig, meta *oidcProviderMetadata, code string) (string, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", cfg.RedirectURL)
+	form.Set("client_id", cfg.ClientID)
+	form.Set("client_secret", cfg.ClientSecret)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, meta.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", fmt.Errorf("failed to read token response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("token endpoint returned status %d", resp.StatusCode)
+	}
+
+	var tokenResp struct {
+		IDToken string `json:"id_token"`
+	}
+	if err := json.Unmarshal(body, &tokenResp); err != nil {
+		return "", fmt.Errorf("failed to parse token response: %w", err)
+	}
+	if tokenResp.IDToken == "" {
+		return "", fmt.Errorf("token response did not include an id_token")
+	}
+	return tokenResp.IDToken, nil
+}
+
+// verifyIDToken validates the signature, issuer, audience, expiry and nonce of an ID token
+func (s *OIDCService) verifyIDToken(ctx context.Context, cfg *configPkg.OIDCProviderConfig, rawIDToken, nonce string) (*oidcIDTokenClaims, error) {
+	keyFunc := func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		kid, _ := token.Header["kid"].(string)
+		return s.signingKey(ctx, cfg, kid)
+	}
+
+	claims := &oidcIDTokenClaims{}
+	token, err := jwt.ParseWithClaims(rawIDToken, claims, keyFunc)
+	if err != nil {
+		return nil, err
+	}
+	if !token.Valid {
+		return nil, fmt.Errorf("invalid token")
+	}
+
+	if strings.TrimSuffix(claims.Issuer, "/") != cfg.Issuer {
+		return nil, fmt.Errorf("unexpected issuer %q", claims.Issuer)
+	}
+	if !claims.VerifyAudience(cfg.ClientID, true) {
+		return nil, fmt.Errorf("token audience does not include client")
+	}
+	if claims.ExpiresAt == nil {
+		return nil, fmt.Errorf("token has no expiry")
+	}
+	if claims.Nonce != nonce {
+		return nil, fmt.Errorf("nonce mismatch")
+	}
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("token has no subject")
+	}
+
+	return claims, nil
+}
+
+// signingKey returns the provider key for a kid, refreshing the JWKS once on a miss to follow key rotation
+func (s *OIDCService) signingKey(ctx context.Context, cfg *configPkg.OIDCProviderConfig, kid string) (*rsa.PublicKey, error) {
+	for _, refresh := range []bool{false, true} {
+		meta, err := s.getMetadata(ctx, cfg, refresh)
+		if err != nil {
+			return nil, err
+		}
+		if kid == "" && len(meta.keys) == 1 {
+			for _, key := range meta.keys {
+				return key, nil
+			}
+		}
+		if key, ok := meta.keys[kid]; ok {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("signing key %q not found", kid)
+}
+
+// getMetadata returns the cached discovery document and keys for a provider, fetching them when stale
+func (s *OIDCService) getMetadata(ctx context.Context, cfg *configPkg.OIDCProviderConfig, forceRefresh bool) (*oidcProviderMetadata, error) {
+	s.mu.RLock()
+	meta, ok := s.metadata[cfg.Name]
+	s.mu.RUnlock()
+	if ok && !forceRefresh && time.Since(meta.fetchedAt) < oidcMetadataCacheTTL {
+		return meta, nil
+	}
+
+	fresh := &oidcProviderMetadata{}
+	if err := s.getJSON(ctx, cfg.Issuer+"/.well-known/openid-configuration", fresh); err != nil {
+		return nil, fmt.Errorf("failed to fetch OIDC discovery document: %w", err)
+	}
+	if strings.TrimSuffix(fresh.Issuer, "/") != cfg.Issuer {
+		return nil, fmt.Errorf("discovery issuer %q does not match configured issuer", fresh.Issuer)
+	}
+
+	var jwks struct {
+		Keys []struct {
+			Kid string `json:"kid"`
+			Kty string `json:"kty"`
+			Use string `json:"use"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := s.getJSON(ctx, fresh.JWKSURI, &jwks); err != nil {
+		return nil, fmt.Errorf("failed to fetch JWKS: %w", err)
+	}
+
+	fresh.keys = make(map[string]*rsa.PublicKey)
+	for _, k := range jwks.Keys {
+		if k.Kty != "RSA" || (k.Use != "" && k.Use != "sig") {
+			continue
+		}
+		key, err := parseRSAJWK(k.N, k.E)
+		if err != nil {
+			s.logger.Warn("Skipping invalid JWK", zap.String("provider", cfg.Name), zap.String("kid", k.Kid), zap.Error(err))
+			continue
+		}
+		fresh.keys[k.Kid] = key
+	}
+	fresh.fetchedAt = time.Now()
+
+	s.mu.Lock()
+	s.metadata[cfg.Name] = fresh
+	s.mu.Unlock()
+
+	return fresh, nil
+}
+
+// getJSON performs a GET request and decodes a JSON response body
+func (s *OIDCService) getJSON(ctx context.Context, endpoint string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d from %s", resp.StatusCode, endpoint)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(out)
+}
+
+// parseRSAJWK builds an RSA public key from base64url-encoded modulus and exponent
+func parseRSAJWK(n, e string) (*rsa.PublicKey, error) {
+	nBytes, err := base64.RawURLEncoding.DecodeString(n)
+	if err != nil {
+		return nil, fmt.Errorf("invalid modulus: %w", err)
+	}
+	eBytes, err := base64.RawURLEncoding.DecodeString(e)
+	if err != nil {
+		return nil, fmt.Errorf("invalid exponent: %w", err)
+	}
+	exponent := new(big.Int).SetBytes(eBytes)
+	if !exponent.IsInt64() || exponent.Int64() > 1<<31-1 {
+		return nil, fmt.Errorf("exponent too large")
+	}
+	return &rsa.PublicKey{N: new(big.Int).SetBytes(nBytes), E: int(exponent.Int64())}, nil
+}
+
+// splitE164 splits an E.164 phone number into the country code and national number used by the
+// users table. The number must carry its own country code; no default region is assumed.
+func splitE164(phone string) (string, string, error) {
+	if !strings.HasPrefix(strings.TrimSpace(phone), "+") {
+		return "", "", fmt.Errorf("phone number must include a country code")
+	}
+	number, err := phonenumber.Parse(phone, "")
+	if err != nil {
+		return "", "", err
+	}
+	return number.CountryCode, number.NationalNumber, nil
+}
+
+// randomToken generates a URL-safe random token for state, nonce and placeholder secrets
+func randomToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate random token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func oidcStateKey(state string) string {
+	return fmt.Sprintf("oidc_state:%s", state)
+}
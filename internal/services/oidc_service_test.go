@@ -0,0 +1,164 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	configPkg "github.com/Kisanlink/aaa-service/v2/internal/config"
+	jwt "github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newTestOIDCProvider(t *testing.T) (*httptest.Server, *rsa.PrivateKey) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/.well-known/openid-configuration":
+			_ = json.NewEncoder(w).Encode(map[string]string{
+				"issuer":                 server.URL,
+				"authorization_endpoint": server.URL + "/authorize",
+				"token_endpoint":         server.URL + "/token",
+				"jwks_uri":               server.URL + "/jwks",
+			})
+		case "/jwks":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"keys": []map[string]string{{
+					"kid": "test-key",
+					"kty": "RSA",
+					"use": "sig",
+					"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+					"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+				}},
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server, key
+}
+
+func signTestIDToken(t *testing.T, key *rsa.PrivateKey, claims *oidcIDTokenClaims) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = "test-key"
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+	return signed
+}
+
+func TestOIDCService_VerifyIDToken(t *testing.T) {
+	server, key := newTestOIDCProvider(t)
+	cfg := &configPkg.OIDCProviderConfig{
+		Name:     "test",
+		Issuer:   server.URL,
+		ClientID: "client-123",
+	}
+	service := NewOIDCService(map[string]*configPkg.OIDCProviderConfig{"test": cfg}, nil, nil, nil, nil, nil, nil, nil, zap.NewNop())
+
+	validClaims := func() *oidcIDTokenClaims {
+		return &oidcIDTokenClaims{
+			Email:         "farmer@example.com",
+			EmailVerified: true,
+			Nonce:         "nonce-abc",
+			RegisteredClaims: jwt.RegisteredClaims{
+				Issuer:    server.URL,
+				Subject:   "subject-1",
+				Audience:  jwt.ClaimStrings{"client-123"},
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(5 * time.Minute)),
+				IssuedAt:  jwt.NewNumericDate(time.Now()),
+			},
+		}
+	}
+
+	t.Run("valid token", func(t *testing.T) {
+		claims, err := service.verifyIDToken(context.Background(), cfg, signTestIDToken(t, key, validClaims()), "nonce-abc")
+		require.NoError(t, err)
+		assert.Equal(t, "subject-1", claims.Subject)
+		assert.True(t, claims.EmailVerified)
+	})
+
+	tests := []struct {
+		name   string
+		mutate func(c *oidcIDTokenClaims)
+		nonce  string
+	}{
+		{name: "nonce mismatch", mutate: func(c *oidcIDTokenClaims) {}, nonce: "other"},
+		{name: "wrong audience", mutate: func(c *oidcIDTokenClaims) { c.Audience = jwt.ClaimStrings{"someone-else"} }, nonce: "nonce-abc"},
+		{name: "wrong issuer", mutate: func(c *oidcIDTokenClaims) { c.Issuer = "https://evil.example.com" }, nonce: "nonce-abc"},
+		{name: "expired", mutate: func(c *oidcIDTokenClaims) { c.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute)) }, nonce: "nonce-abc"},
+		{name: "missing expiry", mutate: func(c *oidcIDTokenClaims) { c.ExpiresAt = nil }, nonce: "nonce-abc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := validClaims()
+			tt.mutate(claims)
+			_, err := service.verifyIDToken(context.Background(), cfg, signTestIDToken(t, key, claims), tt.nonce)
+			assert.Error(t, err)
+		})
+	}
+
+	t.Run("foreign signing key", func(t *testing.T) {
+		otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+		_, err = service.verifyIDToken(context.Background(), cfg, signTestIDToken(t, otherKey, validClaims()), "nonce-abc")
+		assert.Error(t, err)
+	})
+}
+
+func TestSplitE164(t *testing.T) {
+	countryCode, number, err := splitE164("+919876543210")
+	require.NoError(t, err)
+	assert.Equal(t, "+91", countryCode)
+	assert.Equal(t, "9876543210", number)
+
+	_, _, err = splitE164("9876543210")
+	assert.Error(t, err)
+
+	countryCode, number, err = splitE164("+1 (415) 555-0132")
+	require.NoError(t, err)
+	assert.Equal(t, "+1", countryCode)
+	assert.Equal(t, "4155550132", number)
+
+	_, _, err = splitE164("+91 12345 67890")
+	assert.Error(t, err, "Indian mobile numbers start with 6-9")
+}
+
+func TestOIDCService_ConsumeStateOnlyOnce(t *testing.T) {
+	cache := &memoryCache{values: map[string]interface{}{}}
+	service := NewOIDCService(nil, nil, nil, nil, nil, nil, cache, nil, zap.NewNop())
+	require.NoError(t, cache.Set(oidcStateKey("state-1"), map[string]interface{}{"provider": "test", "nonce": "nonce-abc"}, 60))
+
+	var wg sync.WaitGroup
+	var redeemed atomic.Int32
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if nonce, err := service.consumeState(context.Background(), "state-1", "test"); err == nil && nonce == "nonce-abc" {
+				redeemed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), redeemed.Load())
+	assert.Empty(t, cache.values)
+}
@@ -0,0 +1,261 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/config"
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	orgResponses "github.com/Kisanlink/aaa-service/v2/internal/entities/responses/organizations"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/go-redis/redis/v8"
+	"go.uber.org/zap"
+)
+
+// organizationTypeCacheTTL is how long the type of an organization is kept before it is looked up again
+const organizationTypeCacheTTL = 5 * time.Minute
+
+// organizationGetter looks organizations up by ID
+type organizationGetter interface {
+	GetByID(ctx context.Context, id string) (*models.Organization, error)
+}
+
+// quotaCounter keeps the per-minute and monthly request counters of organizations
+type quotaCounter interface {
+	// consume counts a request unless that would exceed quota, and returns the limit that
+	// rejected it (empty when counted) with the counters afterwards
+	consume(ctx context.Context, keys quotaKeys, quota config.OrganizationQuota) (exceeded string, minute, month int64, err error)
+	usage(ctx context.Context, keys quotaKeys) (minute, month int64, err error)
+}
+
+// quotaKeys names the counters of one organization's current windows
+type quotaKeys struct {
+	minute    string
+	month     string
+	minuteTTL time.Duration
+	monthTTL  time.Duration
+}
+
+// OrganizationQuotaService enforces per-organization rate limits and monthly API quotas with
+// counters kept in Redis, so that every service instance counts against the same limits.
+// Quotas come from config.OrganizationQuotaConfig by organization type.
+type OrganizationQuotaService struct {
+	config  config.OrganizationQuotaConfig
+	counter quotaCounter
+	orgRepo organizationGetter
+	logger  *zap.Logger
+	now     func() time.Time
+
+	mu        sync.Mutex
+	orgTypes  map[string]string
+	typeUntil map[string]time.Time
+}
+
+// NewOrganizationQuotaService creates the quota service on the cache's Redis connection. Quotas
+// are not enforced when the cache is not backed by Redis.
+func NewOrganizationQuotaService(cfg config.OrganizationQuotaConfig, cache interfaces.CacheService, orgRepo organizationGetter, logger *zap.Logger) *OrganizationQuotaService {
+	var counter quotaCounter
+	if redisCache, ok := redisCacheOf(cache); ok {
+		counter = &redisQuotaCounter{client: redisCache.client}
+	} else if cfg.Enabled {
+		logger.Info("Cache is not backed by Redis, organization quotas are not enforced")
+	}
+	return newOrganizationQuotaService(cfg, counter, orgRepo, logger)
+}
+
+func newOrganizationQuotaService(cfg config.OrganizationQuotaConfig, counter quotaCounter, orgRepo organizationGetter, logger *zap.Logger) *OrganizationQuotaService {
+	return &OrganizationQuotaService{
+		config:    cfg,
+		counter:   counter,
+		orgRepo:   orgRepo,
+		logger:    logger,
+		now:       time.Now,
+		orgTypes:  make(map[string]string),
+		typeUntil: make(map[string]time.Time),
+	}
+}
+
+// Enabled reports whether quotas are configured and can be enforced
+func (s *OrganizationQuotaService) Enabled() bool {
+	return s.config.Enabled && s.counter != nil
+}
+
+// ConsumeRequest counts one request made in the organization, unless the organization has
+// exhausted its rate limit or monthly quota
+func (s *OrganizationQuotaService) ConsumeRequest(ctx context.Context, orgID string) (*interfaces.OrganizationQuotaDecision, error) {
+	if !s.Enabled() {
+		return &interfaces.OrganizationQuotaDecision{Allowed: true}, nil
+	}
+
+	orgType, err := s.organizationType(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	quota := s.config.QuotaFor(orgType)
+	if quota.Unlimited() {
+		return &interfaces.OrganizationQuotaDecision{Allowed: true}, nil
+	}
+
+	now := s.now().UTC()
+	keys, minuteReset, monthReset := organizationQuotaKeys(orgID, now)
+	exceeded, minute, month, err := s.counter.consume(ctx, keys, quota)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count request against organization quota: %w", err)
+	}
+
+	return &interfaces.OrganizationQuotaDecision{
+		Allowed:      exceeded == "",
+		Exceeded:     exceeded,
+		RateLimit:    quotaWindow(quota.RequestsPerMinute, minute, minuteReset),
+		MonthlyQuota: quotaWindow(quota.MonthlyRequests, month, monthReset),
+	}, nil
+}
+
+// GetQuotaStatus returns the limits of the organization and how much of them is used
+func (s *OrganizationQuotaService) GetQuotaStatus(ctx context.Context, orgID string) (*orgResponses.OrganizationQuotaResponse, error) {
+	org, err := s.orgRepo.GetByID(ctx, orgID)
+	if err != nil || org == nil {
+		return nil, errors.NewNotFoundError("organization not found")
+	}
+
+	quota := s.config.QuotaFor(org.Type)
+	now := s.now().UTC()
+	keys, minuteReset, monthReset := organizationQuotaKeys(orgID, now)
+
+	var minute, month int64
+	if s.Enabled() {
+		if minute, month, err = s.counter.usage(ctx, keys); err != nil {
+			s.logger.Error("Failed to read organization quota usage", zap.String("org_id", orgID), zap.Error(err))
+			return nil, errors.NewInternalError(err)
+		}
+	}
+
+	return &orgResponses.OrganizationQuotaResponse{
+		OrganizationID:   org.ID,
+		OrganizationType: org.Type,
+		Enforced:         s.Enabled(),
+		RateLimit:        quotaWindow(quota.RequestsPerMinute, minute, minuteReset),
+		MonthlyQuota:     quotaWindow(quota.MonthlyRequests, month, monthReset),
+	}, nil
+}
+
+// organizationType returns the type of the organization, cached for organizationTypeCacheTTL
+func (s *OrganizationQuotaService) organizationType(ctx context.Context, orgID string) (string, error) {
+	now := s.now()
+
+	s.mu.Lock()
+	orgType, ok := s.orgTypes[orgID]
+	fresh := ok && now.Before(s.typeUntil[orgID])
+	s.mu.Unlock()
+	if fresh {
+		return orgType, nil
+	}
+
+	org, err := s.orgRepo.GetByID(ctx, orgID)
+	if err != nil || org == nil {
+		return "", fmt.Errorf("failed to look up organization %s: %v", orgID, err)
+	}
+
+	s.mu.Lock()
+	s.orgTypes[orgID] = org.Type
+	s.typeUntil[orgID] = now.Add(organizationTypeCacheTTL)
+	s.mu.Unlock()
+	return org.Type, nil
+}
+
+// organizationQuotaKeys returns the counters of the minute and calendar month (UTC) containing
+// now, and when those windows end. The organization ID is a hash tag so that both counters live
+// on the same Redis Cluster slot.
+func organizationQuotaKeys(orgID string, now time.Time) (quotaKeys, time.Time, time.Time) {
+	minuteStart := now.Truncate(time.Minute)
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	minuteReset := minuteStart.Add(time.Minute)
+	monthReset := monthStart.AddDate(0, 1, 0)
+
+	return quotaKeys{
+		minute:    fmt.Sprintf("org_quota:{%s}:minute:%d", orgID, minuteStart.Unix()),
+		month:     fmt.Sprintf("org_quota:{%s}:month:%s", orgID, monthStart.Format("2006-01")),
+		minuteTTL: 2 * time.Minute,
+		monthTTL:  monthReset.Sub(now) + 24*time.Hour,
+	}, minuteReset, monthReset
+}
+
+func quotaWindow(limit, used int64, resetAt time.Time) orgResponses.OrganizationQuotaWindow {
+	window := orgResponses.OrganizationQuotaWindow{Limit: limit, Used: used, ResetAt: resetAt}
+	if limit > 0 && used < limit {
+		window.Remaining = limit - used
+	}
+	return window
+}
+
+// consumeQuotaScript checks both counters and increments them only if neither limit is reached,
+// so rejected requests do not count. A limit of 0 is unlimited.
+var consumeQuotaScript = redis.NewScript(`
+local minute = tonumber(redis.call('GET', KEYS[1]) or '0')
+local month = tonumber(redis.call('GET', KEYS[2]) or '0')
+local perMinute = tonumber(ARGV[1])
+local monthly = tonumber(ARGV[2])
+if perMinute > 0 and minute >= perMinute then
+	return {1, minute, month}
+end
+if monthly > 0 and month >= monthly then
+	return {2, minute, month}
+end
+minute = redis.call('INCR', KEYS[1])
+if minute == 1 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[3])
+end
+month = redis.call('INCR', KEYS[2])
+if month == 1 then
+	redis.call('PEXPIRE', KEYS[2], ARGV[4])
+end
+return {0, minute, month}
+`)
+
+// redisQuotaCounter keeps quota counters in Redis
+type redisQuotaCounter struct {
+	client *redis.Client
+}
+
+func (r *redisQuotaCounter) consume(ctx context.Context, keys quotaKeys, quota config.OrganizationQuota) (string, int64, int64, error) {
+	result, err := consumeQuotaScript.Run(ctx, r.client,
+		[]string{keys.minute, keys.month},
+		quota.RequestsPerMinute, quota.MonthlyRequests,
+		keys.minuteTTL.Milliseconds(), keys.monthTTL.Milliseconds(),
+	).Int64Slice()
+	if err != nil {
+		return "", 0, 0, err
+	}
+	if len(result) != 3 {
+		return "", 0, 0, fmt.Errorf("unexpected quota script result %v", result)
+	}
+
+	exceeded := ""
+	switch result[0] {
+	case 1:
+		exceeded = interfaces.OrganizationQuotaRateLimit
+	case 2:
+		exceeded = interfaces.OrganizationQuotaMonthly
+	}
+	return exceeded, result[1], result[2], nil
+}
+
+func (r *redisQuotaCounter) usage(ctx context.Context, keys quotaKeys) (int64, int64, error) {
+	values, err := r.client.MGet(ctx, keys.minute, keys.month).Result()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	counts := make([]int64, len(values))
+	for i, value := range values {
+		if s, ok := value.(string); ok {
+			if _, err := fmt.Sscan(s, &counts[i]); err != nil {
+				return 0, 0, fmt.Errorf("invalid quota counter %q: %w", s, err)
+			}
+		}
+	}
+	return counts[0], counts[1], nil
+}
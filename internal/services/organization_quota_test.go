@@ -0,0 +1,152 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/config"
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/Kisanlink/aaa-service/v2/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// quotaTestCounter keeps quota counters in memory, checking limits like the Redis script does
+type quotaTestCounter struct {
+	counts map[string]int64
+}
+
+func (q *quotaTestCounter) consume(ctx context.Context, keys quotaKeys, quota config.OrganizationQuota) (string, int64, int64, error) {
+	minute, month := q.counts[keys.minute], q.counts[keys.month]
+	if quota.RequestsPerMinute > 0 && minute >= quota.RequestsPerMinute {
+		return interfaces.OrganizationQuotaRateLimit, minute, month, nil
+	}
+	if quota.MonthlyRequests > 0 && month >= quota.MonthlyRequests {
+		return interfaces.OrganizationQuotaMonthly, minute, month, nil
+	}
+	q.counts[keys.minute]++
+	q.counts[keys.month]++
+	return "", q.counts[keys.minute], q.counts[keys.month], nil
+}
+
+func (q *quotaTestCounter) usage(ctx context.Context, keys quotaKeys) (int64, int64, error) {
+	return q.counts[keys.minute], q.counts[keys.month], nil
+}
+
+// quotaTestOrganizations looks organizations up in memory and counts the lookups
+type quotaTestOrganizations struct {
+	orgs    map[string]*models.Organization
+	lookups int
+}
+
+func (o *quotaTestOrganizations) GetByID(ctx context.Context, id string) (*models.Organization, error) {
+	o.lookups++
+	if org, ok := o.orgs[id]; ok {
+		return org, nil
+	}
+	return nil, fmt.Errorf("record not found")
+}
+
+func newQuotaTestService(t *testing.T) (*OrganizationQuotaService, *quotaTestOrganizations, *time.Time) {
+	org := models.NewOrganization("Sahyadri FPO", "", models.OrgTypeFPO)
+	org.ID = "ORGN1"
+	orgs := &quotaTestOrganizations{orgs: map[string]*models.Organization{org.ID: org}}
+
+	cfg := config.OrganizationQuotaConfig{
+		Enabled: true,
+		Default: config.OrganizationQuota{RequestsPerMinute: 100, MonthlyRequests: 1000},
+		TypeQuotas: map[string]config.OrganizationQuota{
+			models.OrgTypeFPO: {RequestsPerMinute: 2, MonthlyRequests: 3},
+		},
+	}
+	service := newOrganizationQuotaService(cfg, &quotaTestCounter{counts: map[string]int64{}}, orgs, zap.NewNop())
+	now := time.Date(2026, 10, 31, 23, 58, 30, 0, time.UTC)
+	service.now = func() time.Time { return now }
+	return service, orgs, &now
+}
+
+func TestOrganizationQuota_RateLimitPerMinute(t *testing.T) {
+	service, orgs, now := newQuotaTestService(t)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		decision, err := service.ConsumeRequest(ctx, "ORGN1")
+		require.NoError(t, err)
+		assert.True(t, decision.Allowed)
+	}
+
+	decision, err := service.ConsumeRequest(ctx, "ORGN1")
+	require.NoError(t, err)
+	assert.False(t, decision.Allowed)
+	assert.Equal(t, interfaces.OrganizationQuotaRateLimit, decision.Exceeded)
+	assert.Equal(t, int64(2), decision.RateLimit.Limit)
+	assert.Zero(t, decision.RateLimit.Remaining)
+	assert.Equal(t, time.Date(2026, 10, 31, 23, 59, 0, 0, time.UTC), decision.RateLimit.ResetAt)
+	assert.Equal(t, int64(2), decision.MonthlyQuota.Used, "rejected requests are not counted")
+	assert.Equal(t, 1, orgs.lookups, "the organization type is cached")
+
+	// The next minute starts a new window
+	*now = now.Add(time.Minute)
+	decision, err = service.ConsumeRequest(ctx, "ORGN1")
+	require.NoError(t, err)
+	assert.True(t, decision.Allowed)
+	assert.Equal(t, int64(1), decision.RateLimit.Remaining)
+}
+
+func TestOrganizationQuota_MonthlyQuotaResetsNextMonth(t *testing.T) {
+	service, _, now := newQuotaTestService(t)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		*now = now.Add(time.Second)
+		decision, err := service.ConsumeRequest(ctx, "ORGN1")
+		require.NoError(t, err)
+		require.True(t, decision.Allowed)
+		if i == 1 {
+			*now = now.Add(time.Minute)
+		}
+	}
+
+	decision, err := service.ConsumeRequest(ctx, "ORGN1")
+	require.NoError(t, err)
+	assert.False(t, decision.Allowed)
+	assert.Equal(t, interfaces.OrganizationQuotaMonthly, decision.Exceeded)
+	assert.Equal(t, time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC), decision.MonthlyQuota.ResetAt)
+
+	status, err := service.GetQuotaStatus(ctx, "ORGN1")
+	require.NoError(t, err)
+	assert.True(t, status.Enforced)
+	assert.Equal(t, models.OrgTypeFPO, status.OrganizationType)
+	assert.Equal(t, int64(3), status.MonthlyQuota.Used)
+	assert.Zero(t, status.MonthlyQuota.Remaining)
+
+	*now = time.Date(2026, 11, 1, 0, 0, 5, 0, time.UTC)
+	decision, err = service.ConsumeRequest(ctx, "ORGN1")
+	require.NoError(t, err)
+	assert.True(t, decision.Allowed)
+	assert.Equal(t, int64(1), decision.MonthlyQuota.Used)
+}
+
+func TestOrganizationQuota_DisabledWithoutRedis(t *testing.T) {
+	cfg := config.OrganizationQuotaConfig{Enabled: true, Default: config.OrganizationQuota{RequestsPerMinute: 1}}
+	service := NewOrganizationQuotaService(cfg, NewNoOpCacheService(utils.NewLoggerAdapter(zap.NewNop())), &quotaTestOrganizations{}, zap.NewNop())
+
+	assert.False(t, service.Enabled())
+	for i := 0; i < 3; i++ {
+		decision, err := service.ConsumeRequest(context.Background(), "ORGN1")
+		require.NoError(t, err)
+		assert.True(t, decision.Allowed)
+	}
+}
+
+func TestOrganizationQuota_StatusOfUnknownOrganization(t *testing.T) {
+	service, _, _ := newQuotaTestService(t)
+
+	_, err := service.GetQuotaStatus(context.Background(), "ORGN404")
+	assert.True(t, errors.IsNotFoundError(err))
+}
@@ -0,0 +1,150 @@
+package organizations
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type hierarchyTestOrgRepo struct {
+	interfaces.OrganizationRepository
+	orgs    map[string]*models.Organization
+	loads   atomic.Int32
+	started chan struct{}
+	release chan struct{}
+}
+
+func (r *hierarchyTestOrgRepo) GetByID(ctx context.Context, id string) (*models.Organization, error) {
+	if r.loads.Add(1) == 1 && r.started != nil {
+		close(r.started)
+		<-r.release
+	}
+	org, ok := r.orgs[id]
+	if !ok {
+		return nil, fmt.Errorf("organization not found")
+	}
+	return org, nil
+}
+
+func (r *hierarchyTestOrgRepo) GetParentHierarchy(ctx context.Context, orgID string) ([]*models.Organization, error) {
+	return nil, nil
+}
+
+func (r *hierarchyTestOrgRepo) GetChildren(ctx context.Context, parentID string) ([]*models.Organization, error) {
+	return nil, nil
+}
+
+type hierarchyTestGroupRepo struct {
+	interfaces.GroupRepository
+}
+
+func (r *hierarchyTestGroupRepo) GetByOrganization(ctx context.Context, organizationID string, limit, offset int, includeInactive bool) ([]*models.Group, error) {
+	return nil, nil
+}
+
+// hierarchyTestCache is a concurrency-safe in-memory cache that counts negative cache lookups
+type hierarchyTestCache struct {
+	interfaces.CacheService
+	mu              sync.Mutex
+	entries         map[string]interface{}
+	notFoundLookups atomic.Int32
+}
+
+func (c *hierarchyTestCache) Get(key string) (interface{}, bool) {
+	if strings.HasSuffix(key, ":not_found") {
+		c.notFoundLookups.Add(1)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	value, ok := c.entries[key]
+	return value, ok
+}
+
+func (c *hierarchyTestCache) Set(key string, value interface{}, expiration int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = value
+	return nil
+}
+
+func (c *hierarchyTestCache) Delete(key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, key)
+	return nil
+}
+
+func (c *hierarchyTestCache) Keys(pattern string) ([]string, error) {
+	return nil, nil
+}
+
+func newHierarchyTestService(orgRepo *hierarchyTestOrgRepo, cache *hierarchyTestCache) *Service {
+	return NewOrganizationService(orgRepo, nil, &hierarchyTestGroupRepo{}, nil, nil, cache, nil, zap.NewNop())
+}
+
+func TestService_GetOrganizationHierarchy_ConcurrentMissesLoadOnce(t *testing.T) {
+	org := models.NewOrganization("Kisan FPO", "", models.OrgTypeFPO)
+	orgRepo := &hierarchyTestOrgRepo{
+		orgs:    map[string]*models.Organization{"ORG1": org},
+		started: make(chan struct{}),
+		release: make(chan struct{}),
+	}
+	cache := &hierarchyTestCache{entries: map[string]interface{}{}}
+	service := newHierarchyTestService(orgRepo, cache)
+
+	const callers = 20
+	var wg sync.WaitGroup
+	errs := make([]error, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			hierarchy, err := service.GetOrganizationHierarchy(context.Background(), "ORG1")
+			if err == nil && hierarchy.Organization.Name != "Kisan FPO" {
+				err = fmt.Errorf("unexpected organization %s", hierarchy.Organization.Name)
+			}
+			errs[i] = err
+		}(i)
+	}
+
+	// Hold the first load until every caller has missed the cache
+	<-orgRepo.started
+	require.Eventually(t, func() bool { return cache.notFoundLookups.Load() >= callers }, time.Second, time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	close(orgRepo.release)
+	wg.Wait()
+
+	for _, err := range errs {
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, int32(1), orgRepo.loads.Load(), "concurrent misses share one load")
+}
+
+func TestService_GetOrganizationHierarchy_NegativeCache(t *testing.T) {
+	orgRepo := &hierarchyTestOrgRepo{orgs: map[string]*models.Organization{}}
+	cache := &hierarchyTestCache{entries: map[string]interface{}{}}
+	service := newHierarchyTestService(orgRepo, cache)
+
+	for i := 0; i < 3; i++ {
+		_, err := service.GetOrganizationHierarchy(context.Background(), "MISSING")
+		require.True(t, errors.IsNotFoundError(err))
+	}
+	assert.Equal(t, int32(1), orgRepo.loads.Load(), "a missing organization is only looked up once")
+	assert.Contains(t, cache.entries, "org:MISSING:not_found")
+
+	require.NoError(t, service.orgCache.InvalidateOrganizationCache(context.Background(), "MISSING"))
+	_, err := service.GetOrganizationHierarchy(context.Background(), "MISSING")
+	require.True(t, errors.IsNotFoundError(err))
+	assert.Equal(t, int32(2), orgRepo.loads.Load(), "invalidation clears the negative cache entry")
+}
@@ -0,0 +1,87 @@
+package organizations
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	orgRequests "github.com/Kisanlink/aaa-service/v2/internal/entities/requests/organizations"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/internal/utils/actor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// actorTestOrgRepo stores organizations in memory
+type actorTestOrgRepo struct {
+	interfaces.OrganizationRepository
+	orgs map[string]*models.Organization
+}
+
+func (r *actorTestOrgRepo) GetByName(ctx context.Context, name string) (*models.Organization, error) {
+	return nil, fmt.Errorf("record not found")
+}
+
+func (r *actorTestOrgRepo) Search(ctx context.Context, keyword string, limit, offset int) ([]*models.Organization, error) {
+	return nil, nil
+}
+
+func (r *actorTestOrgRepo) Create(ctx context.Context, org *models.Organization) error {
+	r.orgs[org.ID] = org
+	return nil
+}
+
+func (r *actorTestOrgRepo) GetByID(ctx context.Context, id string) (*models.Organization, error) {
+	if org, ok := r.orgs[id]; ok {
+		return org, nil
+	}
+	return nil, fmt.Errorf("record not found")
+}
+
+func (r *actorTestOrgRepo) GetActiveChildren(ctx context.Context, parentID string) ([]*models.Organization, error) {
+	return nil, nil
+}
+
+func (r *actorTestOrgRepo) Update(ctx context.Context, org *models.Organization) error {
+	r.orgs[org.ID] = org
+	return nil
+}
+
+// actorTestAuditService records the actor of each organization operation
+type actorTestAuditService struct {
+	interfaces.AuditService
+	actors []string
+}
+
+func (a *actorTestAuditService) LogOrganizationOperation(ctx context.Context, userID, action, orgID, message string, success bool, details map[string]interface{}) {
+	a.actors = append(a.actors, userID)
+}
+
+func TestService_RecordsActorOnCreateAndUpdate(t *testing.T) {
+	repo := &actorTestOrgRepo{orgs: map[string]*models.Organization{}}
+	audit := &actorTestAuditService{}
+	cache := &hierarchyTestCache{entries: map[string]interface{}{}}
+	service := NewOrganizationService(repo, nil, nil, nil, acceptingValidator{}, cache, audit, zap.NewNop())
+
+	creatorCtx := context.WithValue(context.Background(), "user_id", "USER1")
+	created, err := service.CreateOrganization(creatorCtx, &orgRequests.CreateOrganizationRequest{Name: "Green Valley FPO", Type: models.OrgTypeFPO})
+	require.NoError(t, err)
+
+	org := repo.orgs[created.ID]
+	assert.Equal(t, "USER1", org.CreatedBy)
+	assert.Equal(t, "USER1", org.UpdatedBy)
+
+	description := "Pune district"
+	editorCtx := context.WithValue(context.Background(), "user_id", "USER2")
+	_, err = service.UpdateOrganization(editorCtx, created.ID, &orgRequests.UpdateOrganizationRequest{Description: &description})
+	require.NoError(t, err)
+	assert.Equal(t, "USER1", org.CreatedBy, "the creator is kept")
+	assert.Equal(t, "USER2", org.UpdatedBy)
+
+	require.NoError(t, service.DeactivateOrganization(context.Background(), created.ID))
+	assert.Equal(t, actor.System, org.UpdatedBy, "changes outside a request are made by the system")
+
+	assert.Equal(t, []string{"USER1", "USER2", actor.System}, audit.actors)
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
 	"github.com/Kisanlink/aaa-service/v2/internal/entities/requests/organizations"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -257,6 +258,48 @@ func (m *MockOrganizationRepository) Restore(ctx context.Context, id string) err
 	return args.Error(0)
 }
 
+func (m *MockOrganizationRepository) ListWithDeleted(ctx context.Context, limit, offset int) ([]*models.Organization, error) {
+	args := m.Called(ctx, limit, offset)
+	return args.Get(0).([]*models.Organization), args.Error(1)
+}
+
+func (m *MockOrganizationRepository) CountWithDeleted(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockOrganizationRepository) HardDeleteCascade(ctx context.Context, orgID string) (*models.OrganizationDeletionSnapshot, error) {
+	args := m.Called(ctx, orgID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.OrganizationDeletionSnapshot), args.Error(1)
+}
+
+func (m *MockOrganizationRepository) SoftDeleteCascade(ctx context.Context, orgID, deletedBy string) (*models.OrganizationCascadeSummary, error) {
+	args := m.Called(ctx, orgID, deletedBy)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.OrganizationCascadeSummary), args.Error(1)
+}
+
+func (m *MockOrganizationRepository) RestoreCascade(ctx context.Context, orgID string) (*models.OrganizationCascadeSummary, error) {
+	args := m.Called(ctx, orgID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.OrganizationCascadeSummary), args.Error(1)
+}
+
+func (m *MockOrganizationRepository) MergeInto(ctx context.Context, sourceID, targetID, mergedBy string) (*models.OrganizationMergeSummary, error) {
+	args := m.Called(ctx, sourceID, targetID, mergedBy)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.OrganizationMergeSummary), args.Error(1)
+}
+
 func (m *MockOrganizationRepository) GetByType(ctx context.Context, orgType string, limit, offset int) ([]*models.Organization, error) {
 	args := m.Called(ctx, orgType, limit, offset)
 	return args.Get(0).([]*models.Organization), args.Error(1)
@@ -650,11 +693,21 @@ func (m *MockGroupService) DeleteGroup(ctx context.Context, groupID string, dele
 	return args.Error(0)
 }
 
+func (m *MockGroupService) MoveGroup(ctx context.Context, groupID, newParentID, movedBy string) (interface{}, error) {
+	args := m.Called(ctx, groupID, newParentID, movedBy)
+	return args.Get(0), args.Error(1)
+}
+
 func (m *MockGroupService) ListGroups(ctx context.Context, limit, offset int, organizationID string, includeInactive bool) (interface{}, error) {
 	args := m.Called(ctx, limit, offset, organizationID, includeInactive)
 	return args.Get(0), args.Error(1)
 }
 
+func (m *MockGroupService) ListOrganizationGroups(ctx context.Context, organizationID string, filter interfaces.GroupFilter, limit, offset int) (interface{}, int64, error) {
+	args := m.Called(ctx, organizationID, filter, limit, offset)
+	return args.Get(0), args.Get(1).(int64), args.Error(2)
+}
+
 func (m *MockGroupService) AddUserToGroup(ctx context.Context, groupID, userID string) error {
 	args := m.Called(ctx, groupID, userID)
 	return args.Error(0)
@@ -690,6 +743,11 @@ func (m *MockGroupService) GetUserEffectiveRoles(ctx context.Context, orgID, use
 	return args.Get(0), args.Error(1)
 }
 
+func (m *MockGroupService) GetUserScopedEffectiveRoles(ctx context.Context, orgID, userID string, scope interfaces.EffectiveRoleScope) (interface{}, error) {
+	args := m.Called(ctx, orgID, userID, scope)
+	return args.Get(0), args.Error(1)
+}
+
 func (m *MockGroupService) AddMemberToGroup(ctx context.Context, req interface{}) (interface{}, error) {
 	args := m.Called(ctx, req)
 	return args.Get(0), args.Error(1)
@@ -715,6 +773,16 @@ func (m *MockGroupService) CountGroupMembers(ctx context.Context, groupID string
 	return args.Get(0).(int64), args.Error(1)
 }
 
+func (m *MockGroupService) ListGroupMembers(ctx context.Context, groupID string, filter interfaces.GroupMemberFilter, limit, offset int) (interface{}, error) {
+	args := m.Called(ctx, groupID, filter, limit, offset)
+	return args.Get(0), args.Error(1)
+}
+
+func (m *MockGroupService) CountGroupMembersByType(ctx context.Context, groupID, principalType string) (int64, error) {
+	args := m.Called(ctx, groupID, principalType)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 func (m *MockGroupService) GetUserGroupsInOrganization(ctx context.Context, orgID, userID string, limit, offset int) (interface{}, error) {
 	args := m.Called(ctx, orgID, userID, limit, offset)
 	return args.Get(0), args.Error(1)
@@ -0,0 +1,72 @@
+package organizations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	organizationResponses "github.com/Kisanlink/aaa-service/v2/internal/entities/responses/organizations"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// SetRoleRepository sets the repository used to list the roles available in an organization
+func (s *Service) SetRoleRepository(roleRepo interfaces.OrganizationRoleRepository) {
+	s.roleRepo = roleRepo
+}
+
+// ListAvailableRoles returns the roles that can be used within an organization: its own
+// organization-scoped roles followed by the global roles. A role is assignable when it is
+// active and the organization is active.
+func (s *Service) ListAvailableRoles(ctx context.Context, orgID string) (*organizationResponses.AvailableRolesResponse, error) {
+	if s.roleRepo == nil {
+		return nil, errors.NewInternalError(fmt.Errorf("role repository not configured"))
+	}
+
+	org, err := s.orgRepo.GetByID(ctx, orgID)
+	if err != nil || org == nil {
+		s.logger.Error("Organization not found", zap.String("org_id", orgID))
+		return nil, errors.NewNotFoundError("organization not found")
+	}
+
+	orgRoles, err := s.roleRepo.GetByOrganization(ctx, orgID)
+	if err != nil {
+		s.logger.Error("Failed to list organization roles", zap.String("org_id", orgID), zap.Error(err))
+		return nil, errors.NewInternalError(err)
+	}
+	globalRoles, err := s.roleRepo.GetGlobalRoles(ctx)
+	if err != nil {
+		s.logger.Error("Failed to list global roles", zap.Error(err))
+		return nil, errors.NewInternalError(err)
+	}
+
+	response := &organizationResponses.AvailableRolesResponse{
+		OrganizationID: orgID,
+		Roles:          make([]*organizationResponses.AvailableRoleResponse, 0, len(orgRoles)+len(globalRoles)),
+	}
+	for _, role := range orgRoles {
+		// Guard against roles of other organizations should the repository filter ever widen
+		if role.OrganizationID == nil || *role.OrganizationID != orgID {
+			continue
+		}
+		response.Roles = append(response.Roles, availableRoleResponse(role, org.IsActive))
+	}
+	for _, role := range globalRoles {
+		response.Roles = append(response.Roles, availableRoleResponse(role, org.IsActive))
+	}
+
+	return response, nil
+}
+
+func availableRoleResponse(role *models.Role, orgActive bool) *organizationResponses.AvailableRoleResponse {
+	return &organizationResponses.AvailableRoleResponse{
+		ID:          role.ID,
+		Name:        role.Name,
+		Description: role.Description,
+		ServiceID:   role.ServiceID,
+		Scope:       string(role.Scope),
+		IsActive:    role.IsActive,
+		Assignable:  role.IsActive && orgActive,
+	}
+}
@@ -0,0 +1,127 @@
+package organizations
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	organizationResponses "github.com/Kisanlink/aaa-service/v2/internal/entities/responses/organizations"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/Kisanlink/aaa-service/v2/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// availableRolesTestRepo filters one shared role table by scope and organization, like the role repository
+type availableRolesTestRepo struct {
+	roles []*models.Role
+}
+
+func (r *availableRolesTestRepo) GetGlobalRoles(ctx context.Context) ([]*models.Role, error) {
+	var roles []*models.Role
+	for _, role := range r.roles {
+		if role.Scope == models.RoleScopeGlobal {
+			roles = append(roles, role)
+		}
+	}
+	return roles, nil
+}
+
+func (r *availableRolesTestRepo) GetByOrganization(ctx context.Context, organizationID string) ([]*models.Role, error) {
+	var roles []*models.Role
+	for _, role := range r.roles {
+		if role.Scope == models.RoleScopeOrg && role.OrganizationID != nil && *role.OrganizationID == organizationID {
+			roles = append(roles, role)
+		}
+	}
+	return roles, nil
+}
+
+func newAvailableRolesTestService() *Service {
+	orgA := models.NewOrganization("Kisan FPO", "", models.OrgTypeFPO)
+	orgA.IsActive = true
+	orgB := models.NewOrganization("Ryot Cooperative", "", models.OrgTypeCooperative)
+	orgB.IsActive = true
+	dormant := models.NewOrganization("Dormant FPO", "", models.OrgTypeFPO)
+	dormant.IsActive = false
+
+	disabledGlobal := models.NewGlobalRole("auditor", "Read-only auditor")
+	disabledGlobal.IsActive = false
+
+	service := NewOrganizationService(
+		&memberTestOrgRepo{orgs: map[string]*models.Organization{"ORGA": orgA, "ORGB": orgB, "ORGC": dormant}},
+		&memberTestUserRepo{},
+		nil,
+		nil,
+		utils.NewValidator(),
+		nil,
+		&memberTestAudit{},
+		zap.NewNop(),
+	)
+	service.SetRoleRepository(&availableRolesTestRepo{roles: []*models.Role{
+		models.NewGlobalRole("farmer", "Farmer"),
+		disabledGlobal,
+		models.NewOrgRole("farm_manager", "", "ORGA"),
+		models.NewOrgRole("collector", "", "ORGB"),
+		models.NewOrgRole("field_agent", "", "ORGC"),
+	}})
+	return service
+}
+
+func roleNamesByScope(response *organizationResponses.AvailableRolesResponse) map[string][]string {
+	names := map[string][]string{}
+	for _, role := range response.Roles {
+		names[role.Scope] = append(names[role.Scope], role.Name)
+	}
+	return names
+}
+
+func TestService_ListAvailableRoles_GlobalRolesInEveryOrganization(t *testing.T) {
+	service := newAvailableRolesTestService()
+	ctx := context.Background()
+
+	orgA, err := service.ListAvailableRoles(ctx, "ORGA")
+	require.NoError(t, err)
+	orgB, err := service.ListAvailableRoles(ctx, "ORGB")
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string][]string{
+		string(models.RoleScopeOrg):    {"farm_manager"},
+		string(models.RoleScopeGlobal): {"farmer", "auditor"},
+	}, roleNamesByScope(orgA))
+	assert.Equal(t, map[string][]string{
+		string(models.RoleScopeOrg):    {"collector"},
+		string(models.RoleScopeGlobal): {"farmer", "auditor"},
+	}, roleNamesByScope(orgB))
+
+	// Organization roles come first
+	assert.Equal(t, "farm_manager", orgA.Roles[0].Name)
+}
+
+func TestService_ListAvailableRoles_Assignable(t *testing.T) {
+	service := newAvailableRolesTestService()
+	ctx := context.Background()
+
+	active, err := service.ListAvailableRoles(ctx, "ORGA")
+	require.NoError(t, err)
+	assignable := map[string]bool{}
+	for _, role := range active.Roles {
+		assignable[role.Name] = role.Assignable
+	}
+	assert.Equal(t, map[string]bool{"farm_manager": true, "farmer": true, "auditor": false}, assignable)
+
+	dormant, err := service.ListAvailableRoles(ctx, "ORGC")
+	require.NoError(t, err)
+	require.NotEmpty(t, dormant.Roles)
+	for _, role := range dormant.Roles {
+		assert.False(t, role.Assignable, "%s is not assignable in an inactive organization", role.Name)
+	}
+}
+
+func TestService_ListAvailableRoles_UnknownOrganization(t *testing.T) {
+	service := newAvailableRolesTestService()
+
+	_, err := service.ListAvailableRoles(context.Background(), "ORG404")
+	assert.True(t, errors.IsNotFoundError(err), "unexpected error: %v", err)
+}
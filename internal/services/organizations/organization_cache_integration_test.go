@@ -205,6 +205,8 @@ func TestOrganizationCacheService_InvalidateCache(t *testing.T) {
 		"org:test-org-123:active_groups",
 		"org:test-org-123:group_hierarchy",
 		"org:test-org-123:stats",
+		"org:test-org-123:settings",
+		"org:test-org-123:not_found",
 	}
 
 	for _, key := range expectedKeys {
@@ -32,6 +32,8 @@ const (
 	OrgParentHierarchyPattern = "org:%s:parent_hierarchy"
 	OrgChildrenPattern        = "org:%s:children"
 	OrgActiveChildrenPattern  = "org:%s:active_children"
+	OrgAncestorsPattern       = "org:%s:ancestors"
+	OrgDescendantsPattern     = "org:%s:descendants"
 
 	// Organization groups caching
 	OrgGroupsPattern         = "org:%s:groups"
@@ -56,6 +58,12 @@ const (
 	// Organization stats
 	OrgStatsPattern = "org:%s:stats"
 
+	// Organization settings
+	OrgSettingsPattern = "org:%s:settings"
+
+	// Negative cache for organization IDs that do not exist
+	OrgNotFoundPattern = "org:%s:not_found"
+
 	// Cache TTL values (in seconds)
 	HierarchyCacheTTL      = 1800 // 30 minutes for hierarchy data
 	GroupsCacheTTL         = 900  // 15 minutes for group data
@@ -63,6 +71,8 @@ const (
 	EffectiveRolesCacheTTL = 300  // 5 minutes for effective roles (frequently changing)
 	RoleInheritanceTTL     = 1200 // 20 minutes for role inheritance patterns
 	StatsCacheTTL          = 300  // 5 minutes for stats
+	SettingsCacheTTL       = 600  // 10 minutes for settings
+	NotFoundCacheTTL       = 30   // 30 seconds for unknown organization IDs
 )
 
 // CacheOrganizationHierarchy caches the complete organization hierarchy
@@ -109,6 +119,28 @@ func (c *OrganizationCacheService) GetCachedOrganizationHierarchy(ctx context.Co
 	return nil, false
 }
 
+// CacheOrganizationNotFound remembers briefly that an organization does not exist, so repeated
+// lookups of a bad ID do not each reach the database
+func (c *OrganizationCacheService) CacheOrganizationNotFound(ctx context.Context, orgID string) error {
+	key := fmt.Sprintf(OrgNotFoundPattern, orgID)
+
+	if err := c.cache.Set(key, true, NotFoundCacheTTL); err != nil {
+		c.logger.Warn("Failed to cache missing organization",
+			zap.String("org_id", orgID),
+			zap.String("cache_key", key),
+			zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// IsOrganizationNotFoundCached reports whether the organization was recently looked up and not found
+func (c *OrganizationCacheService) IsOrganizationNotFoundCached(ctx context.Context, orgID string) bool {
+	_, found := c.cache.Get(fmt.Sprintf(OrgNotFoundPattern, orgID))
+	return found
+}
+
 // CacheOrganizationParentHierarchy caches the parent hierarchy for an organization
 func (c *OrganizationCacheService) CacheOrganizationParentHierarchy(ctx context.Context, orgID string, parents []*models.Organization) error {
 	key := fmt.Sprintf(OrgParentHierarchyPattern, orgID)
@@ -364,10 +396,14 @@ func (c *OrganizationCacheService) InvalidateOrganizationCache(ctx context.Conte
 		fmt.Sprintf(OrgParentHierarchyPattern, orgID),
 		fmt.Sprintf(OrgChildrenPattern, orgID),
 		fmt.Sprintf(OrgActiveChildrenPattern, orgID),
+		fmt.Sprintf(OrgAncestorsPattern, orgID),
+		fmt.Sprintf(OrgDescendantsPattern, orgID),
 		fmt.Sprintf(OrgGroupsPattern, orgID),
 		fmt.Sprintf(OrgActiveGroupsPattern, orgID),
 		fmt.Sprintf(OrgGroupHierarchyPattern, orgID),
 		fmt.Sprintf(OrgStatsPattern, orgID),
+		fmt.Sprintf(OrgSettingsPattern, orgID),
+		fmt.Sprintf(OrgNotFoundPattern, orgID),
 	}
 
 	for _, pattern := range patterns {
@@ -430,6 +466,71 @@ func (c *OrganizationCacheService) InvalidateUserGroupCache(ctx context.Context,
 	return nil
 }
 
+// InvalidateOrganizationStats invalidates the cached statistics for an organization
+func (c *OrganizationCacheService) InvalidateOrganizationStats(ctx context.Context, orgID string) error {
+	key := fmt.Sprintf(OrgStatsPattern, orgID)
+	if err := c.cache.Delete(key); err != nil {
+		c.logger.Warn("Failed to invalidate organization stats cache key",
+			zap.String("org_id", orgID),
+			zap.String("cache_key", key),
+			zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// CacheOrganizationSettings caches the effective settings of an organization
+func (c *OrganizationCacheService) CacheOrganizationSettings(ctx context.Context, orgID string, settings *organizationResponses.OrganizationSettingsResponse) error {
+	key := fmt.Sprintf(OrgSettingsPattern, orgID)
+
+	if err := c.cache.Set(key, settings, SettingsCacheTTL); err != nil {
+		c.logger.Warn("Failed to cache organization settings",
+			zap.String("org_id", orgID),
+			zap.String("cache_key", key),
+			zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// GetCachedOrganizationSettings retrieves the cached effective settings of an organization
+func (c *OrganizationCacheService) GetCachedOrganizationSettings(ctx context.Context, orgID string) (*organizationResponses.OrganizationSettingsResponse, bool) {
+	key := fmt.Sprintf(OrgSettingsPattern, orgID)
+
+	cached, found := c.cache.Get(key)
+	if !found {
+		return nil, false
+	}
+
+	if settings, ok := cached.(*organizationResponses.OrganizationSettingsResponse); ok {
+		return settings, true
+	}
+
+	c.logger.Warn("Invalid cached organization settings type",
+		zap.String("org_id", orgID),
+		zap.String("cache_key", key))
+
+	// Remove invalid cache entry
+	c.cache.Delete(key)
+	return nil, false
+}
+
+// InvalidateOrganizationSettings invalidates the cached settings of an organization
+func (c *OrganizationCacheService) InvalidateOrganizationSettings(ctx context.Context, orgID string) error {
+	key := fmt.Sprintf(OrgSettingsPattern, orgID)
+	if err := c.cache.Delete(key); err != nil {
+		c.logger.Warn("Failed to invalidate organization settings cache key",
+			zap.String("org_id", orgID),
+			zap.String("cache_key", key),
+			zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
 // InvalidateGroupCache invalidates cache entries for a specific group in an organization
 func (c *OrganizationCacheService) InvalidateGroupCache(ctx context.Context, orgID, groupID string) error {
 	patterns := []string{
@@ -725,20 +826,23 @@ func (c *OrganizationCacheService) InvalidateRoleRelatedCache(ctx context.Contex
 
 // InvalidateHierarchyRelatedCache invalidates hierarchy-related cache when structure changes
 func (c *OrganizationCacheService) InvalidateHierarchyRelatedCache(ctx context.Context, orgID string, affectedOrgIDs []string) error {
-	// Invalidate the main organization's hierarchy cache
 	hierarchyPatterns := []string{
-		fmt.Sprintf(OrgHierarchyKeyPattern, orgID),
-		fmt.Sprintf(OrgHierarchyTreePattern, orgID),
-		fmt.Sprintf(OrgParentHierarchyPattern, orgID),
-		fmt.Sprintf(OrgChildrenPattern, orgID),
-		fmt.Sprintf(OrgActiveChildrenPattern, orgID),
+		OrgHierarchyTreePattern,
+		OrgHierarchyKeyPattern,
+		OrgParentHierarchyPattern,
+		OrgChildrenPattern,
+		OrgActiveChildrenPattern,
+		OrgAncestorsPattern,
+		OrgDescendantsPattern,
 	}
 
+	// Invalidate the main organization's hierarchy cache
 	for _, pattern := range hierarchyPatterns {
-		if err := c.cache.Delete(pattern); err != nil {
+		key := fmt.Sprintf(pattern, orgID)
+		if err := c.cache.Delete(key); err != nil {
 			c.logger.Warn("Failed to invalidate hierarchy cache key",
 				zap.String("org_id", orgID),
-				zap.String("cache_key", pattern),
+				zap.String("cache_key", key),
 				zap.Error(err))
 		}
 	}
@@ -766,6 +870,59 @@ func (c *OrganizationCacheService) InvalidateHierarchyRelatedCache(ctx context.C
 	return nil
 }
 
+// CacheOrganizationLineage caches the full ancestors or descendants list of an organization
+func (c *OrganizationCacheService) CacheOrganizationLineage(ctx context.Context, orgID string, direction LineageDirection, nodes []*organizationResponses.OrganizationLineageNode) error {
+	key := lineageCacheKey(orgID, direction)
+
+	if err := c.cache.Set(key, nodes, HierarchyCacheTTL); err != nil {
+		c.logger.Warn("Failed to cache organization lineage",
+			zap.String("org_id", orgID),
+			zap.String("cache_key", key),
+			zap.Error(err))
+		return err
+	}
+
+	c.logger.Debug("Cached organization lineage",
+		zap.String("org_id", orgID),
+		zap.String("cache_key", key),
+		zap.Int("node_count", len(nodes)))
+
+	return nil
+}
+
+// GetCachedOrganizationLineage retrieves a cached ancestors or descendants list
+func (c *OrganizationCacheService) GetCachedOrganizationLineage(ctx context.Context, orgID string, direction LineageDirection) ([]*organizationResponses.OrganizationLineageNode, bool) {
+	key := lineageCacheKey(orgID, direction)
+
+	cached, found := c.cache.Get(key)
+	if !found {
+		return nil, false
+	}
+
+	if nodes, ok := cached.([]*organizationResponses.OrganizationLineageNode); ok {
+		c.logger.Debug("Retrieved cached organization lineage",
+			zap.String("org_id", orgID),
+			zap.String("cache_key", key),
+			zap.Int("node_count", len(nodes)))
+		return nodes, true
+	}
+
+	c.logger.Warn("Invalid cached lineage type",
+		zap.String("org_id", orgID),
+		zap.String("cache_key", key))
+
+	// Remove invalid cache entry
+	c.cache.Delete(key)
+	return nil, false
+}
+
+func lineageCacheKey(orgID string, direction LineageDirection) string {
+	if direction == LineageAncestors {
+		return fmt.Sprintf(OrgAncestorsPattern, orgID)
+	}
+	return fmt.Sprintf(OrgDescendantsPattern, orgID)
+}
+
 // ScheduleCacheWarming sets up periodic cache warming for frequently accessed organizations
 func (c *OrganizationCacheService) ScheduleCacheWarming(ctx context.Context, orgIDs []string, orgService interfaces.OrganizationService, interval time.Duration) {
 	ticker := time.NewTicker(interval)
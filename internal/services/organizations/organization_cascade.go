@@ -0,0 +1,190 @@
+package organizations
+
+import (
+	"context"
+	stdErrors "errors"
+	"fmt"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	organizationResponses "github.com/Kisanlink/aaa-service/v2/internal/entities/responses/organizations"
+	orgRepo "github.com/Kisanlink/aaa-service/v2/internal/repositories/organizations"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// DeleteOrganizationCascade soft-deletes an organization together with its active groups, their
+// memberships and group role assignments, in one transaction. Unlike DeleteOrganization it does
+// not require the groups to be removed first; child organizations still block it. Every removed
+// row is audited and the caches of the organization and its groups are invalidated.
+func (s *Service) DeleteOrganizationCascade(ctx context.Context, orgID, deletedBy string) (*organizationResponses.OrganizationCascadeDeleteResponse, error) {
+	s.logger.Info("Deleting organization with its groups", zap.String("org_id", orgID))
+
+	org, err := s.orgRepo.GetByID(ctx, orgID)
+	if err != nil || org == nil {
+		s.logger.Error("Organization not found for deletion", zap.String("org_id", orgID))
+		return nil, errors.NewNotFoundError("organization not found")
+	}
+	if org.DeletedAt != nil {
+		return nil, errors.NewValidationError("organization is already deleted")
+	}
+
+	if err := s.checkNoChildOrganizations(ctx, orgID); err != nil {
+		return nil, err
+	}
+
+	summary, err := s.orgRepo.SoftDeleteCascade(ctx, orgID, deletedBy)
+	if err != nil {
+		s.logger.Error("Failed to delete organization with its groups", zap.String("org_id", orgID), zap.Error(err))
+
+		auditDetails := map[string]interface{}{
+			"organization_name": org.Name,
+			"deleted_by":        deletedBy,
+			"cascade":           true,
+			"error":             err.Error(),
+		}
+		s.auditService.LogOrganizationOperation(ctx, deletedBy, models.AuditActionDeleteOrganization, orgID, "Failed to delete organization with its groups", false, auditDetails)
+
+		return nil, cascadeError(err)
+	}
+
+	cascadeDetails := map[string]interface{}{"cascade": true, "organization_id": orgID}
+	for _, membership := range summary.GroupMemberships {
+		s.auditService.LogGroupMembershipChange(ctx, deletedBy, models.AuditActionRemoveGroupMember, orgID, membership.GroupID, membership.PrincipalID,
+			"Group member removed with organization", true, copyDetails(cascadeDetails))
+	}
+	for _, groupRole := range summary.GroupRoles {
+		s.auditService.LogGroupRoleAssignment(ctx, deletedBy, models.AuditActionRemoveGroupRole, orgID, groupRole.GroupID, groupRole.RoleID,
+			"Group role removed with organization", true, copyDetails(cascadeDetails))
+	}
+	for _, group := range summary.Groups {
+		s.auditService.LogGroupOperation(ctx, deletedBy, models.AuditActionDeleteGroup, orgID, group.ID,
+			"Group deleted with organization", true, copyDetails(cascadeDetails))
+	}
+
+	auditDetails := map[string]interface{}{
+		"organization_name": org.Name,
+		"deleted_by":        deletedBy,
+		"cascade":           true,
+		"groups":            len(summary.Groups),
+		"group_memberships": len(summary.GroupMemberships),
+		"group_roles":       len(summary.GroupRoles),
+	}
+	s.auditService.LogOrganizationOperation(ctx, deletedBy, models.AuditActionDeleteOrganization, orgID, "Organization deleted with its groups", true, auditDetails)
+	s.invalidateCascade(ctx, org, summary)
+
+	s.logger.Info("Organization deleted with its groups",
+		zap.String("org_id", orgID),
+		zap.String("deleted_by", deletedBy),
+		zap.Int("groups", len(summary.Groups)),
+		zap.Int("group_memberships", len(summary.GroupMemberships)),
+		zap.Int("group_roles", len(summary.GroupRoles)))
+
+	return &organizationResponses.OrganizationCascadeDeleteResponse{
+		OrganizationID:   orgID,
+		Groups:           len(summary.Groups),
+		GroupMemberships: len(summary.GroupMemberships),
+		GroupRoles:       len(summary.GroupRoles),
+		DeletedAt:        summary.DeletedAt,
+	}, nil
+}
+
+// RestoreOrganizationCascade restores a soft-deleted organization together with the groups,
+// memberships and group role assignments DeleteOrganizationCascade deleted with it, in one
+// transaction. Rows deleted separately stay deleted.
+func (s *Service) RestoreOrganizationCascade(ctx context.Context, orgID, restoredBy string) (*organizationResponses.OrganizationResponse, error) {
+	s.logger.Info("Restoring organization with its groups", zap.String("org_id", orgID))
+
+	org, err := s.restorableOrganization(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	summary, err := s.orgRepo.RestoreCascade(ctx, orgID)
+	if err != nil {
+		s.logger.Error("Failed to restore organization with its groups", zap.String("org_id", orgID), zap.Error(err))
+
+		auditDetails := map[string]interface{}{
+			"organization_name": org.Name,
+			"restored_by":       restoredBy,
+			"cascade":           true,
+			"error":             err.Error(),
+		}
+		s.auditService.LogOrganizationOperation(ctx, restoredBy, models.AuditActionRestoreOrganization, orgID, "Failed to restore organization with its groups", false, auditDetails)
+
+		return nil, cascadeError(err)
+	}
+
+	cascadeDetails := map[string]interface{}{"cascade": true, "organization_id": orgID}
+	for _, group := range summary.Groups {
+		s.auditService.LogGroupOperation(ctx, restoredBy, models.AuditActionRestoreGroup, orgID, group.ID,
+			"Group restored with organization", true, copyDetails(cascadeDetails))
+	}
+	for _, membership := range summary.GroupMemberships {
+		s.auditService.LogGroupMembershipChange(ctx, restoredBy, models.AuditActionRestoreGroupMember, orgID, membership.GroupID, membership.PrincipalID,
+			"Group member restored with organization", true, copyDetails(cascadeDetails))
+	}
+	for _, groupRole := range summary.GroupRoles {
+		s.auditService.LogGroupRoleAssignment(ctx, restoredBy, models.AuditActionRestoreGroupRole, orgID, groupRole.GroupID, groupRole.RoleID,
+			"Group role restored with organization", true, copyDetails(cascadeDetails))
+	}
+
+	auditDetails := map[string]interface{}{
+		"organization_name": org.Name,
+		"restored_by":       restoredBy,
+		"deleted_at":        summary.DeletedAt,
+		"cascade":           true,
+		"groups":            len(summary.Groups),
+		"group_memberships": len(summary.GroupMemberships),
+		"group_roles":       len(summary.GroupRoles),
+	}
+	s.auditService.LogOrganizationOperation(ctx, restoredBy, models.AuditActionRestoreOrganization, orgID, "Organization restored with its groups", true, auditDetails)
+	s.invalidateCascade(ctx, org, summary)
+
+	s.logger.Info("Organization restored with its groups",
+		zap.String("org_id", orgID),
+		zap.Int("groups", len(summary.Groups)))
+	return s.GetOrganization(ctx, orgID)
+}
+
+// invalidateCascade drops the cached data of an organization and of the groups deleted or
+// restored with it, including the group roles and memberships of its users
+func (s *Service) invalidateCascade(ctx context.Context, org *models.Organization, summary *models.OrganizationCascadeSummary) {
+	s.orgCache.InvalidateOrganizationCache(ctx, org.ID)
+	s.orgCache.InvalidateRoleRelatedCache(ctx, org.ID)
+	for _, group := range summary.Groups {
+		s.orgCache.InvalidateGroupCache(ctx, org.ID, group.ID)
+		s.deleteCacheKeys(fmt.Sprintf("group:%s:*", group.ID))
+	}
+	s.invalidateLineage(ctx, org.ID, parentIDOf(org))
+}
+
+// deleteCacheKeys deletes the cache keys matching pattern
+func (s *Service) deleteCacheKeys(pattern string) {
+	keys, err := s.cache.Keys(pattern)
+	if err != nil {
+		s.logger.Warn("Failed to list cache keys for invalidation", zap.String("pattern", pattern), zap.Error(err))
+		return
+	}
+	for _, key := range keys {
+		if err := s.cache.Delete(key); err != nil {
+			s.logger.Warn("Failed to invalidate cache key", zap.String("cache_key", key), zap.Error(err))
+		}
+	}
+}
+
+// cascadeError maps a cascade failure to a service error
+func cascadeError(err error) error {
+	if stdErrors.Is(err, orgRepo.ErrOrganizationNotCascadable) {
+		return errors.NewConflictError("organization was changed concurrently")
+	}
+	return errors.NewInternalError(err)
+}
+
+// copyDetails returns a copy of details, as audit logging adds to the map it is given
+func copyDetails(details map[string]interface{}) map[string]interface{} {
+	copied := make(map[string]interface{}, len(details))
+	for key, value := range details {
+		copied[key] = value
+	}
+	return copied
+}
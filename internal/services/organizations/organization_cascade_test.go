@@ -0,0 +1,204 @@
+package organizations
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	orgRepo "github.com/Kisanlink/aaa-service/v2/internal/repositories/organizations"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/Kisanlink/aaa-service/v2/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type cascadeTestOrgRepo struct {
+	*hardDeleteTestOrgRepo
+	summary    *models.OrganizationCascadeSummary
+	cascadeErr error
+	deletedBy  string
+	restored   []string
+}
+
+func (r *cascadeTestOrgRepo) SoftDeleteCascade(ctx context.Context, orgID, deletedBy string) (*models.OrganizationCascadeSummary, error) {
+	if r.cascadeErr != nil {
+		return nil, r.cascadeErr
+	}
+	r.deletedBy = deletedBy
+	now := time.Now()
+	r.orgs[orgID].DeletedAt = &now
+	r.summary.DeletedAt = now
+	return r.summary, nil
+}
+
+func (r *cascadeTestOrgRepo) RestoreCascade(ctx context.Context, orgID string) (*models.OrganizationCascadeSummary, error) {
+	if r.cascadeErr != nil {
+		return nil, r.cascadeErr
+	}
+	r.restored = append(r.restored, orgID)
+	r.orgs[orgID].DeletedAt = nil
+	return r.summary, nil
+}
+
+type cascadeTestAudit struct {
+	hardDeleteTestAudit
+	groupEvents []string
+}
+
+func (a *cascadeTestAudit) LogGroupOperation(ctx context.Context, userID, action, orgID, groupID, message string, success bool, details map[string]interface{}) {
+	a.groupEvents = append(a.groupEvents, fmt.Sprintf("%s %s", action, groupID))
+}
+
+func (a *cascadeTestAudit) LogGroupMembershipChange(ctx context.Context, actorUserID, action, orgID, groupID, targetUserID, message string, success bool, details map[string]interface{}) {
+	a.groupEvents = append(a.groupEvents, fmt.Sprintf("%s %s %s", action, groupID, targetUserID))
+}
+
+func (a *cascadeTestAudit) LogGroupRoleAssignment(ctx context.Context, actorUserID, action, orgID, groupID, roleID, message string, success bool, details map[string]interface{}) {
+	a.groupEvents = append(a.groupEvents, fmt.Sprintf("%s %s %s", action, groupID, roleID))
+}
+
+// cascadeTestCache lists its keys by prefix pattern
+type cascadeTestCache struct {
+	settingTestCache
+}
+
+func (c *cascadeTestCache) Keys(pattern string) ([]string, error) {
+	var keys []string
+	for key := range c.entries {
+		if strings.HasPrefix(key, strings.TrimSuffix(pattern, "*")) {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+func newCascadeTestRepo() *cascadeTestOrgRepo {
+	group := models.NewGroup("Field Team", "", "ORG1")
+	group.ID = "GRP1"
+	membership := models.NewGroupMembership("GRP1", "USER1", "user", "ADMIN1")
+	groupRole := models.NewGroupRole("GRP1", "ROLE1", "ORG1", "ADMIN1")
+
+	return &cascadeTestOrgRepo{
+		hardDeleteTestOrgRepo: newHardDeleteTestRepo(),
+		summary: &models.OrganizationCascadeSummary{
+			Groups:           []*models.Group{group},
+			GroupMemberships: []*models.GroupMembership{membership},
+			GroupRoles:       []*models.GroupRole{groupRole},
+		},
+	}
+}
+
+func newCascadeTestService(repo *cascadeTestOrgRepo, cache *cascadeTestCache, audit *cascadeTestAudit) *Service {
+	return NewOrganizationService(
+		repo,
+		&memberTestUserRepo{},
+		nil,
+		nil,
+		utils.NewValidator(),
+		cache,
+		audit,
+		zap.NewNop(),
+	)
+}
+
+func TestDeleteOrganizationCascade(t *testing.T) {
+	repo := newCascadeTestRepo()
+	cache := &cascadeTestCache{settingTestCache{entries: map[string]interface{}{
+		"group:GRP1:members": "cached",
+		"group:GRP2:members": "cached",
+	}}}
+	audit := &cascadeTestAudit{}
+	service := newCascadeTestService(repo, cache, audit)
+
+	result, err := service.DeleteOrganizationCascade(context.Background(), "ORG1", "ADMIN1")
+	require.NoError(t, err)
+
+	assert.Equal(t, "ADMIN1", repo.deletedBy)
+	assert.Equal(t, 1, result.Groups)
+	assert.Equal(t, 1, result.GroupMemberships)
+	assert.Equal(t, 1, result.GroupRoles)
+	assert.Equal(t, []string{
+		"remove_group_member GRP1 USER1",
+		"remove_group_role GRP1 ROLE1",
+		"delete_group GRP1",
+	}, audit.groupEvents)
+	assert.Equal(t, []string{models.AuditActionDeleteOrganization}, audit.actions)
+	assert.Equal(t, []bool{true}, audit.success)
+
+	assert.NotContains(t, cache.entries, "group:GRP1:members", "caches of the deleted groups are dropped")
+	assert.Contains(t, cache.entries, "group:GRP2:members")
+}
+
+func TestDeleteOrganizationCascade_BlockedByChildOrganizations(t *testing.T) {
+	repo := newCascadeTestRepo()
+	repo.children["ORG1"] = []*models.Organization{models.NewOrganization("Branch", "", models.OrgTypeFPO)}
+	audit := &cascadeTestAudit{}
+	service := newCascadeTestService(repo, &cascadeTestCache{settingTestCache{entries: map[string]interface{}{}}}, audit)
+
+	_, err := service.DeleteOrganizationCascade(context.Background(), "ORG1", "ADMIN1")
+	require.Error(t, err)
+	assert.True(t, errors.IsValidationError(err))
+	assert.Empty(t, repo.deletedBy)
+	assert.Empty(t, audit.actions)
+}
+
+func TestDeleteOrganizationCascade_Failure(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		isExpected func(error) bool
+	}{
+		{name: "database error", err: fmt.Errorf("connection reset"), isExpected: errors.IsInternalError},
+		{name: "concurrent delete", err: fmt.Errorf("%w: ORG1", orgRepo.ErrOrganizationNotCascadable), isExpected: errors.IsConflictError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newCascadeTestRepo()
+			repo.cascadeErr = tt.err
+			audit := &cascadeTestAudit{}
+			cache := &cascadeTestCache{settingTestCache{entries: map[string]interface{}{"group:GRP1:members": "cached"}}}
+			service := newCascadeTestService(repo, cache, audit)
+
+			_, err := service.DeleteOrganizationCascade(context.Background(), "ORG1", "ADMIN1")
+			require.Error(t, err)
+			assert.True(t, tt.isExpected(err))
+			assert.Empty(t, audit.groupEvents, "nothing was deleted, so no group is audited")
+			assert.Equal(t, []bool{false}, audit.success)
+			assert.Contains(t, cache.entries, "group:GRP1:members")
+		})
+	}
+}
+
+func TestRestoreOrganizationCascade(t *testing.T) {
+	repo := newCascadeTestRepo()
+	deletedAt := time.Now().Add(-time.Hour)
+	repo.orgs["ORG1"].DeletedAt = &deletedAt
+	audit := &cascadeTestAudit{}
+	service := newCascadeTestService(repo, &cascadeTestCache{settingTestCache{entries: map[string]interface{}{}}}, audit)
+
+	org, err := service.RestoreOrganizationCascade(context.Background(), "ORG1", "ADMIN1")
+	require.NoError(t, err)
+
+	assert.Equal(t, "Kisan FPO", org.Name)
+	assert.Equal(t, []string{"ORG1"}, repo.restored)
+	assert.Equal(t, []string{
+		"restore_group GRP1",
+		"restore_group_member GRP1 USER1",
+		"restore_group_role GRP1 ROLE1",
+	}, audit.groupEvents)
+	assert.Equal(t, []string{models.AuditActionRestoreOrganization}, audit.actions)
+}
+
+func TestRestoreOrganizationCascade_RequiresDeletedOrganization(t *testing.T) {
+	repo := newCascadeTestRepo()
+	service := newCascadeTestService(repo, &cascadeTestCache{settingTestCache{entries: map[string]interface{}{}}}, &cascadeTestAudit{})
+
+	_, err := service.RestoreOrganizationCascade(context.Background(), "ORG1", "ADMIN1")
+	require.Error(t, err)
+	assert.True(t, errors.IsValidationError(err))
+	assert.Empty(t, repo.restored)
+}
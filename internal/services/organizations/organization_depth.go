@@ -0,0 +1,213 @@
+package organizations
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	organizationResponses "github.com/Kisanlink/aaa-service/v2/internal/entities/responses/organizations"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// hierarchyDepthPageSize is the page size used to scan organizations and groups for the depth report
+const hierarchyDepthPageSize = 500
+
+// SetMaxHierarchyDepth bounds how many levels the organization hierarchy may span, a root
+// organization being at depth 1. Creating or moving an organization below a parent fails with a
+// validation error when it, or the deepest organization under it, would exceed the limit.
+// 0 disables the limit.
+func (s *Service) SetMaxHierarchyDepth(depth int) {
+	s.maxHierarchyDepth = depth
+}
+
+// checkHierarchyDepth rejects placing an organization and its subtree under parentID when that
+// would exceed the maximum depth; orgID is empty for an organization being created
+func (s *Service) checkHierarchyDepth(ctx context.Context, orgID, parentID string) error {
+	if s.maxHierarchyDepth <= 0 || parentID == "" {
+		return nil
+	}
+
+	parents, err := s.orgRepo.GetParentHierarchy(ctx, parentID)
+	if err != nil {
+		return errors.NewInternalError(fmt.Errorf("failed to load parent hierarchy: %w", err))
+	}
+	// The parent's ancestors, the parent and the organization itself
+	depth := len(parents) + 2
+
+	if orgID != "" && depth <= s.maxHierarchyDepth {
+		height, err := s.subtreeHeight(ctx, orgID, s.maxHierarchyDepth-depth+1)
+		if err != nil {
+			return errors.NewInternalError(fmt.Errorf("failed to load organization subtree: %w", err))
+		}
+		depth += height - 1
+	}
+
+	if depth > s.maxHierarchyDepth {
+		s.logger.Warn("Organization hierarchy depth limit exceeded",
+			zap.String("org_id", orgID),
+			zap.String("parent_id", parentID),
+			zap.Int("depth", depth),
+			zap.Int("max_depth", s.maxHierarchyDepth))
+		return errors.NewValidationError(fmt.Sprintf("organization hierarchy would be %d levels deep, exceeding the maximum of %d", depth, s.maxHierarchyDepth))
+	}
+	return nil
+}
+
+// subtreeHeight counts the levels of the subtree under orgID, the organization itself being one.
+// Loading stops one level past limit, which is enough to tell the limit is exceeded.
+func (s *Service) subtreeHeight(ctx context.Context, orgID string, limit int) (int, error) {
+	height := 1
+	visited := map[string]bool{orgID: true}
+	level := []string{orgID}
+	for height <= limit {
+		var next []string
+		for _, id := range level {
+			children, err := s.orgRepo.GetChildren(ctx, id)
+			if err != nil {
+				return 0, err
+			}
+			for _, child := range children {
+				if !visited[child.ID] {
+					visited[child.ID] = true
+					next = append(next, child.ID)
+				}
+			}
+		}
+		if len(next) == 0 {
+			break
+		}
+		height++
+		level = next
+	}
+	return height, nil
+}
+
+// CheckHierarchyDepth reports the organizations and groups nested deeper than maxDepth, or than
+// the configured maximum when maxDepth is 0. It lets operators find existing structures that
+// exceed a limit before enabling it.
+func (s *Service) CheckHierarchyDepth(ctx context.Context, maxDepth int) (*organizationResponses.HierarchyDepthReport, error) {
+	if maxDepth < 0 {
+		return nil, errors.NewValidationError("max_depth must not be negative")
+	}
+	if maxDepth == 0 {
+		maxDepth = s.maxHierarchyDepth
+	}
+	if maxDepth <= 0 {
+		return nil, errors.NewValidationError("max_depth is required when no maximum hierarchy depth is configured")
+	}
+
+	report := &organizationResponses.HierarchyDepthReport{
+		MaxDepth:      maxDepth,
+		Organizations: []*organizationResponses.HierarchyDepthViolation{},
+		Groups:        []*organizationResponses.HierarchyDepthViolation{},
+	}
+
+	orgParents := make(map[string]string)
+	orgNames := make(map[string]string)
+	for offset := 0; ; offset += hierarchyDepthPageSize {
+		orgs, err := s.orgRepo.List(ctx, hierarchyDepthPageSize, offset)
+		if err != nil {
+			return nil, errors.NewInternalError(fmt.Errorf("failed to list organizations: %w", err))
+		}
+		for _, org := range orgs {
+			orgParents[org.ID] = ""
+			if org.ParentID != nil {
+				orgParents[org.ID] = *org.ParentID
+			}
+			orgNames[org.ID] = org.Name
+		}
+		if len(orgs) < hierarchyDepthPageSize {
+			break
+		}
+	}
+	for id, depth := range hierarchyDepths(orgParents) {
+		if depth > maxDepth {
+			report.Organizations = append(report.Organizations, &organizationResponses.HierarchyDepthViolation{
+				ID:    id,
+				Name:  orgNames[id],
+				Depth: depth,
+			})
+		}
+	}
+
+	if s.groupRepo != nil {
+		groupParents := make(map[string]string)
+		groupNames := make(map[string]string)
+		groupOrgs := make(map[string]string)
+		for offset := 0; ; offset += hierarchyDepthPageSize {
+			groups, err := s.groupRepo.List(ctx, hierarchyDepthPageSize, offset)
+			if err != nil {
+				return nil, errors.NewInternalError(fmt.Errorf("failed to list groups: %w", err))
+			}
+			for _, group := range groups {
+				groupParents[group.ID] = ""
+				if group.ParentID != nil {
+					groupParents[group.ID] = *group.ParentID
+				}
+				groupNames[group.ID] = group.Name
+				groupOrgs[group.ID] = group.OrganizationID
+			}
+			if len(groups) < hierarchyDepthPageSize {
+				break
+			}
+		}
+		for id, depth := range hierarchyDepths(groupParents) {
+			if depth > maxDepth {
+				report.Groups = append(report.Groups, &organizationResponses.HierarchyDepthViolation{
+					ID:             id,
+					Name:           groupNames[id],
+					OrganizationID: groupOrgs[id],
+					Depth:          depth,
+				})
+			}
+		}
+	}
+
+	sortDepthViolations(report.Organizations)
+	sortDepthViolations(report.Groups)
+
+	s.logger.Info("Checked hierarchy depth",
+		zap.Int("max_depth", maxDepth),
+		zap.Int("organization_violations", len(report.Organizations)),
+		zap.Int("group_violations", len(report.Groups)))
+
+	return report, nil
+}
+
+// hierarchyDepths computes the depth of every node from its parent link, a root being at depth 1.
+// A node whose parent is unknown counts as a root, and a cycle is cut where it is found.
+func hierarchyDepths(parents map[string]string) map[string]int {
+	depths := make(map[string]int, len(parents))
+	for id := range parents {
+		var path []string
+		onPath := make(map[string]bool)
+		depth := 0
+		for current := id; current != ""; current = parents[current] {
+			if known, ok := depths[current]; ok {
+				depth = known
+				break
+			}
+			if _, ok := parents[current]; !ok || onPath[current] {
+				break
+			}
+			onPath[current] = true
+			path = append(path, current)
+		}
+		for i := len(path) - 1; i >= 0; i-- {
+			depth++
+			depths[path[i]] = depth
+		}
+	}
+	return depths
+}
+
+// sortDepthViolations orders violations deepest first, then by ID
+func sortDepthViolations(violations []*organizationResponses.HierarchyDepthViolation) {
+	sort.Slice(violations, func(i, j int) bool {
+		if violations[i].Depth != violations[j].Depth {
+			return violations[i].Depth > violations[j].Depth
+		}
+		return violations[i].ID < violations[j].ID
+	})
+}
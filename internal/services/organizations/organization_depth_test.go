@@ -0,0 +1,120 @@
+package organizations
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// depthTestOrgRepo lists the lineage test organizations in pages
+type depthTestOrgRepo struct {
+	*lineageTestOrgRepo
+}
+
+func (r depthTestOrgRepo) List(ctx context.Context, limit, offset int) ([]*models.Organization, error) {
+	var orgs []*models.Organization
+	for i := offset; i < len(r.order) && i < offset+limit; i++ {
+		orgs = append(orgs, r.orgs[r.order[i]])
+	}
+	return orgs, nil
+}
+
+type depthTestGroupRepo struct {
+	interfaces.GroupRepository
+	groups []*models.Group
+}
+
+func (r *depthTestGroupRepo) List(ctx context.Context, limit, offset int) ([]*models.Group, error) {
+	if offset >= len(r.groups) {
+		return nil, nil
+	}
+	return r.groups[offset:], nil
+}
+
+func TestService_CheckHierarchyDepth_CreateAndMove(t *testing.T) {
+	// ROOT (1) -> A (2) -> A1 (3) -> A1X (4), A -> A2 (3) and ROOT -> B (2)
+	service, _ := newLineageTestService()
+	service.SetMaxHierarchyDepth(4)
+
+	tests := []struct {
+		name     string
+		orgID    string
+		parentID string
+		allowed  bool
+	}{
+		{"new organization at the maximum depth", "", "A1", true},
+		{"new organization below the maximum depth", "", "A1X", false},
+		{"moved subtree reaching the maximum depth", "A1", "B", true},
+		{"moved subtree exceeding the maximum depth", "A", "B", false},
+		{"root organization", "A", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := service.checkHierarchyDepth(context.Background(), tt.orgID, tt.parentID)
+			if tt.allowed {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.True(t, errors.IsValidationError(err))
+			assert.Contains(t, err.Error(), "exceeding the maximum of 4")
+		})
+	}
+
+	service.SetMaxHierarchyDepth(0)
+	assert.NoError(t, service.checkHierarchyDepth(context.Background(), "A", "A1X"), "the limit is disabled")
+}
+
+func TestService_CheckHierarchyDepth_Report(t *testing.T) {
+	service, repo := newLineageTestService()
+	service.orgRepo = depthTestOrgRepo{repo}
+
+	var groups []*models.Group
+	for _, g := range []struct{ id, parentID string }{{"G1", ""}, {"G2", "G1"}, {"G3", "G2"}, {"G4", "G3"}} {
+		group := models.NewGroup(g.id, "", "ROOT")
+		group.ID = g.id
+		if g.parentID != "" {
+			parentID := g.parentID
+			group.ParentID = &parentID
+		}
+		groups = append(groups, group)
+	}
+	service.groupRepo = &depthTestGroupRepo{groups: groups}
+
+	report, err := service.CheckHierarchyDepth(context.Background(), 3)
+	require.NoError(t, err)
+	assert.Equal(t, 3, report.MaxDepth)
+	require.Len(t, report.Organizations, 1)
+	assert.Equal(t, "A1X", report.Organizations[0].ID)
+	assert.Equal(t, 4, report.Organizations[0].Depth)
+	require.Len(t, report.Groups, 1)
+	assert.Equal(t, "G4", report.Groups[0].ID)
+	assert.Equal(t, "ROOT", report.Groups[0].OrganizationID)
+
+	report, err = service.CheckHierarchyDepth(context.Background(), 4)
+	require.NoError(t, err)
+	assert.Empty(t, report.Organizations, "structures at the maximum depth are within the limit")
+	assert.Empty(t, report.Groups)
+
+	_, err = service.CheckHierarchyDepth(context.Background(), 0)
+	assert.True(t, errors.IsValidationError(err), "a depth is required without a configured limit")
+
+	service.SetMaxHierarchyDepth(2)
+	report, err = service.CheckHierarchyDepth(context.Background(), 0)
+	require.NoError(t, err)
+	assert.Equal(t, 2, report.MaxDepth)
+	assert.Equal(t, []string{"A1X", "A1", "A2"}, []string{report.Organizations[0].ID, report.Organizations[1].ID, report.Organizations[2].ID})
+}
+
+func TestHierarchyDepths_CutsCycles(t *testing.T) {
+	depths := hierarchyDepths(map[string]string{"A": "B", "B": "A", "C": "A", "D": "MISSING"})
+	assert.Equal(t, 1, depths["D"], "a node whose parent is unknown is a root")
+	assert.Positive(t, depths["A"])
+	assert.Positive(t, depths["B"])
+	assert.Equal(t, depths["A"]+1, depths["C"])
+}
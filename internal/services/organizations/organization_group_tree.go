@@ -0,0 +1,110 @@
+package organizations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	organizationResponses "github.com/Kisanlink/aaa-service/v2/internal/entities/responses/organizations"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// MaxGroupHierarchyGroups bounds the number of groups the full group hierarchy of an organization
+// is built from. Larger organizations are browsed level by level with GetGroupChildren.
+const MaxGroupHierarchyGroups = 500
+
+// truncatedHierarchyRootGroups is the number of root groups a truncated hierarchy holds
+const truncatedHierarchyRootGroups = 100
+
+// SetGroupTreeRepository sets the repository the group hierarchy is loaded from level by level
+func (s *Service) SetGroupTreeRepository(groupTreeRepo interfaces.GroupTreeRepository) {
+	s.groupTreeRepo = groupTreeRepo
+}
+
+// GetGroupChildren retrieves a page of one level of the group hierarchy of an organization: its
+// root groups when parentGroupID is empty, otherwise the direct children of that group. Each group
+// carries its child count so clients can expand it on demand.
+func (s *Service) GetGroupChildren(ctx context.Context, orgID, parentGroupID string, limit, offset int) (*organizationResponses.GroupChildrenResponse, error) {
+	s.logger.Info("Retrieving group hierarchy level",
+		zap.String("org_id", orgID),
+		zap.String("parent_group_id", parentGroupID))
+
+	if s.groupTreeRepo == nil {
+		return nil, errors.NewInternalError(fmt.Errorf("group tree repository not configured"))
+	}
+
+	org, err := s.orgRepo.GetByID(ctx, orgID)
+	if err != nil || org == nil {
+		s.logger.Error("Organization not found", zap.String("org_id", orgID))
+		return nil, errors.NewNotFoundError("organization not found")
+	}
+
+	if parentGroupID != "" {
+		parent, err := s.groupRepo.GetByID(ctx, parentGroupID, &models.Group{})
+		if err != nil || parent == nil || parent.OrganizationID != orgID {
+			s.logger.Error("Parent group not found in organization",
+				zap.String("org_id", orgID),
+				zap.String("parent_group_id", parentGroupID))
+			return nil, errors.NewNotFoundError("group not found in organization")
+		}
+	}
+
+	nodes, total, err := s.loadGroupLevel(ctx, orgID, parentGroupID, limit, offset)
+	if err != nil {
+		s.logger.Error("Failed to load group hierarchy level",
+			zap.String("org_id", orgID),
+			zap.String("parent_group_id", parentGroupID),
+			zap.Error(err))
+		return nil, errors.NewInternalError(err)
+	}
+
+	return &organizationResponses.GroupChildrenResponse{
+		OrganizationID: orgID,
+		ParentGroupID:  parentGroupID,
+		Groups:         nodes,
+		TotalCount:     total,
+		Limit:          limit,
+		Offset:         offset,
+	}, nil
+}
+
+// truncatedGroupHierarchy returns the first root groups of an organization for a hierarchy too
+// large to build in full, or none when groups cannot be loaded level by level
+func (s *Service) truncatedGroupHierarchy(ctx context.Context, orgID string) ([]*organizationResponses.GroupHierarchyNode, error) {
+	if s.groupTreeRepo == nil {
+		return []*organizationResponses.GroupHierarchyNode{}, nil
+	}
+	nodes, _, err := s.loadGroupLevel(ctx, orgID, "", truncatedHierarchyRootGroups, 0)
+	return nodes, err
+}
+
+// loadGroupLevel loads a page of the children of parentGroupID, or of the root groups, as
+// hierarchy nodes without their children, and the total number of them
+func (s *Service) loadGroupLevel(ctx context.Context, orgID, parentGroupID string, limit, offset int) ([]*organizationResponses.GroupHierarchyNode, int64, error) {
+	groups, total, err := s.groupTreeRepo.GetChildrenInOrganization(ctx, orgID, parentGroupID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	ids := make([]string, len(groups))
+	for i, group := range groups {
+		ids[i] = group.ID
+	}
+	childCounts, err := s.groupTreeRepo.CountChildren(ctx, ids)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	nodes := make([]*organizationResponses.GroupHierarchyNode, 0, len(groups))
+	for _, group := range groups {
+		node, err := s.createGroupHierarchyNode(ctx, group)
+		if err != nil {
+			return nil, 0, err
+		}
+		node.ChildCount = childCounts[group.ID]
+		nodes = append(nodes, node)
+	}
+	return nodes, total, nil
+}
@@ -0,0 +1,168 @@
+package organizations
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	groupResponses "github.com/Kisanlink/aaa-service/v2/internal/entities/responses/groups"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// groupTreeTestRepo stores the groups of organizations in memory, in insertion order
+type groupTreeTestRepo struct {
+	interfaces.GroupRepository
+	groups []*models.Group
+}
+
+func (r *groupTreeTestRepo) add(id, orgID, parentID string) {
+	group := models.NewGroup(id, "", orgID)
+	group.ID = id
+	if parentID != "" {
+		group.ParentID = &parentID
+	}
+	r.groups = append(r.groups, group)
+}
+
+func (r *groupTreeTestRepo) GetByID(ctx context.Context, id string, group *models.Group) (*models.Group, error) {
+	for _, g := range r.groups {
+		if g.ID == id {
+			return g, nil
+		}
+	}
+	return nil, fmt.Errorf("group not found")
+}
+
+func (r *groupTreeTestRepo) GetByOrganization(ctx context.Context, organizationID string, limit, offset int, includeInactive bool) ([]*models.Group, error) {
+	var groups []*models.Group
+	for _, g := range r.groups {
+		if g.OrganizationID == organizationID && len(groups) < limit {
+			groups = append(groups, g)
+		}
+	}
+	return groups, nil
+}
+
+func (r *groupTreeTestRepo) GetChildrenInOrganization(ctx context.Context, organizationID, parentID string, limit, offset int) ([]*models.Group, int64, error) {
+	var children []*models.Group
+	for _, g := range r.groups {
+		if g.OrganizationID == organizationID && parentIDOfGroup(g) == parentID {
+			children = append(children, g)
+		}
+	}
+	total := int64(len(children))
+	if offset >= len(children) {
+		return nil, total, nil
+	}
+	return children[offset:min(offset+limit, len(children))], total, nil
+}
+
+func (r *groupTreeTestRepo) CountChildren(ctx context.Context, parentIDs []string) (map[string]int64, error) {
+	counts := map[string]int64{}
+	for _, id := range parentIDs {
+		for _, g := range r.groups {
+			if parentIDOfGroup(g) == id {
+				counts[id]++
+			}
+		}
+	}
+	return counts, nil
+}
+
+func parentIDOfGroup(group *models.Group) string {
+	if group.ParentID == nil {
+		return ""
+	}
+	return *group.ParentID
+}
+
+type groupTreeTestGroupService struct {
+	interfaces.GroupService
+}
+
+func (s *groupTreeTestGroupService) GetGroupRoles(ctx context.Context, groupID string) (interface{}, error) {
+	return []*groupResponses.GroupRoleDetail{}, nil
+}
+
+// newGroupTreeTestService builds ORG1 with root groups A and B, A -> A1, A -> A2 and A1 -> A1X
+func newGroupTreeTestService() (*Service, *groupTreeTestRepo) {
+	orgRepo := &lineageTestOrgRepo{orgs: map[string]*models.Organization{}}
+	orgRepo.add("ORG1", "")
+	orgRepo.add("ORG2", "")
+
+	groupRepo := &groupTreeTestRepo{}
+	groupRepo.add("A", "ORG1", "")
+	groupRepo.add("B", "ORG1", "")
+	groupRepo.add("A1", "ORG1", "A")
+	groupRepo.add("A2", "ORG1", "A")
+	groupRepo.add("A1X", "ORG1", "A1")
+	groupRepo.add("OTHER", "ORG2", "")
+
+	cache := &hierarchyTestCache{entries: map[string]interface{}{}}
+	service := NewOrganizationService(orgRepo, nil, groupRepo, nil, nil, cache, nil, zap.NewNop())
+	service.SetGroupService(&groupTreeTestGroupService{})
+	service.SetGroupTreeRepository(groupRepo)
+	return service, groupRepo
+}
+
+func TestService_GetGroupChildren(t *testing.T) {
+	service, _ := newGroupTreeTestService()
+	ctx := context.Background()
+
+	roots, err := service.GetGroupChildren(ctx, "ORG1", "", 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), roots.TotalCount)
+	require.Len(t, roots.Groups, 2)
+	assert.Equal(t, "A", roots.Groups[0].Group.ID)
+	assert.Equal(t, int64(2), roots.Groups[0].ChildCount)
+	assert.Empty(t, roots.Groups[0].Children)
+	assert.Equal(t, int64(0), roots.Groups[1].ChildCount)
+
+	children, err := service.GetGroupChildren(ctx, "ORG1", "A", 1, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "A", children.ParentGroupID)
+	assert.Equal(t, int64(2), children.TotalCount)
+	require.Len(t, children.Groups, 1)
+	assert.Equal(t, "A2", children.Groups[0].Group.ID)
+}
+
+func TestService_GetGroupChildren_ParentInOtherOrganization(t *testing.T) {
+	service, _ := newGroupTreeTestService()
+
+	_, err := service.GetGroupChildren(context.Background(), "ORG1", "OTHER", 10, 0)
+	assert.True(t, errors.IsNotFoundError(err))
+
+	_, err = service.GetGroupChildren(context.Background(), "MISSING", "", 10, 0)
+	assert.True(t, errors.IsNotFoundError(err))
+}
+
+func TestService_BuildGroupHierarchy(t *testing.T) {
+	service, _ := newGroupTreeTestService()
+
+	nodes, truncated, err := service.buildGroupHierarchy(context.Background(), "ORG1")
+	require.NoError(t, err)
+	assert.False(t, truncated)
+	require.Len(t, nodes, 2)
+	assert.Equal(t, int64(2), nodes[0].ChildCount)
+	require.Len(t, nodes[0].Children, 2)
+	assert.Equal(t, int64(1), nodes[0].Children[0].ChildCount)
+}
+
+func TestService_BuildGroupHierarchy_TruncatesLargeOrganizations(t *testing.T) {
+	service, groupRepo := newGroupTreeTestService()
+	for i := 0; i < MaxGroupHierarchyGroups; i++ {
+		groupRepo.add(fmt.Sprintf("A1X-%d", i), "ORG1", "A1X")
+	}
+
+	nodes, truncated, err := service.buildGroupHierarchy(context.Background(), "ORG1")
+	require.NoError(t, err)
+	assert.True(t, truncated)
+	require.Len(t, nodes, 2, "only the root groups are returned")
+	assert.Empty(t, nodes[0].Children)
+	assert.Equal(t, int64(2), nodes[0].ChildCount)
+}
@@ -0,0 +1,292 @@
+package organizations
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	stdErrors "errors"
+	"fmt"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	organizationResponses "github.com/Kisanlink/aaa-service/v2/internal/entities/responses/organizations"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	orgRepo "github.com/Kisanlink/aaa-service/v2/internal/repositories/organizations"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// HardDeleteConfirmationTTL is how long a hard delete confirmation token stays valid
+const HardDeleteConfirmationTTL = 5 * time.Minute
+
+// hardDeleteConfirmation is the cached state of a confirmation token. Only the token hash is kept.
+type hardDeleteConfirmation struct {
+	TokenHash   string    `json:"token_hash"`
+	RequestedBy string    `json:"requested_by"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// SetAuditRetention sets the audit log repository and the retention period, in days, that hard
+// deletes must respect. A retention of zero or less disables the check.
+func (s *Service) SetAuditRetention(auditRepo interfaces.AuditRepository, retentionDays int) {
+	s.auditRepo = auditRepo
+	s.auditRetentionDays = retentionDays
+}
+
+// RequestOrganizationHardDelete runs the hard delete checks for an organization and issues the
+// single-use token that confirms it. The token is bound to the requesting user and expires after
+// HardDeleteConfirmationTTL; requesting a new one replaces the previous token.
+func (s *Service) RequestOrganizationHardDelete(ctx context.Context, orgID, requestedBy string) (*organizationResponses.OrganizationHardDeleteConfirmationResponse, error) {
+	if requestedBy == "" {
+		return nil, errors.NewValidationError("requesting user is required")
+	}
+
+	org, err := s.orgRepo.GetByID(ctx, orgID)
+	if err != nil || org == nil {
+		return nil, errors.NewNotFoundError("organization not found")
+	}
+
+	if err := s.checkOrganizationDeletable(ctx, orgID); err != nil {
+		return nil, err
+	}
+	if err := s.checkAuditRetention(ctx, orgID); err != nil {
+		return nil, err
+	}
+
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return nil, errors.NewInternalError(fmt.Errorf("failed to generate confirmation token: %w", err))
+	}
+	token := hex.EncodeToString(b)
+
+	confirmation := hardDeleteConfirmation{
+		TokenHash:   hashConfirmationToken(token),
+		RequestedBy: requestedBy,
+		ExpiresAt:   time.Now().Add(HardDeleteConfirmationTTL),
+	}
+	data, err := json.Marshal(confirmation)
+	if err != nil {
+		return nil, errors.NewInternalError(err)
+	}
+	if err := s.cache.Set(hardDeleteConfirmationKey(orgID), string(data), int(HardDeleteConfirmationTTL.Seconds())); err != nil {
+		return nil, errors.NewInternalError(fmt.Errorf("failed to store confirmation token: %w", err))
+	}
+
+	s.logger.Info("Organization hard delete requested",
+		zap.String("org_id", orgID),
+		zap.String("requested_by", requestedBy))
+
+	return &organizationResponses.OrganizationHardDeleteConfirmationResponse{
+		OrganizationID:    orgID,
+		OrganizationName:  org.Name,
+		ConfirmationToken: token,
+		ExpiresAt:         confirmation.ExpiresAt,
+	}, nil
+}
+
+// HardDeleteOrganization permanently deletes an organization and everything it owns. It needs a
+// confirmation token from RequestOrganizationHardDelete issued to the same user, repeats the checks
+// of a soft delete and refuses while audit logs of the organization are inside the retention period.
+// What was removed is recorded as a system audit event.
+func (s *Service) HardDeleteOrganization(ctx context.Context, orgID, deletedBy, confirmationToken string) error {
+	s.logger.Info("Hard deleting organization", zap.String("org_id", orgID))
+
+	if confirmationToken == "" {
+		return errors.NewValidationError("confirmation token is required")
+	}
+	if !s.consumeHardDeleteConfirmation(orgID, deletedBy, confirmationToken) {
+		s.logger.Warn("Invalid hard delete confirmation token",
+			zap.String("org_id", orgID),
+			zap.String("deleted_by", deletedBy))
+		return errors.NewValidationError("confirmation token is invalid or expired")
+	}
+
+	org, err := s.orgRepo.GetByID(ctx, orgID)
+	if err != nil || org == nil {
+		return errors.NewNotFoundError("organization not found")
+	}
+
+	if err := s.checkOrganizationDeletable(ctx, orgID); err != nil {
+		return err
+	}
+	if err := s.checkAuditRetention(ctx, orgID); err != nil {
+		return err
+	}
+
+	snapshot, err := s.orgRepo.HardDeleteCascade(ctx, orgID)
+	if err != nil {
+		s.logger.Error("Failed to hard delete organization", zap.String("org_id", orgID), zap.Error(err))
+
+		auditDetails := map[string]interface{}{
+			"organization_name": org.Name,
+			"deleted_by":        deletedBy,
+			"error":             err.Error(),
+		}
+		s.auditService.LogOrganizationOperation(ctx, deletedBy, models.AuditActionHardDeleteOrganization, orgID, "Failed to hard delete organization", false, auditDetails)
+
+		if stdErrors.Is(err, orgRepo.ErrOrganizationHasDependents) {
+			return errors.NewConflictError(err.Error())
+		}
+		return errors.NewInternalError(err)
+	}
+
+	s.auditService.LogSystemEvent(ctx, models.AuditActionHardDeleteOrganization, models.ResourceTypeOrganization, true, map[string]interface{}{
+		"organization_id":   orgID,
+		"organization_name": org.Name,
+		"deleted_by":        deletedBy,
+		"snapshot":          snapshot,
+	})
+	s.orgCache.InvalidateOrganizationCache(ctx, orgID)
+	s.invalidateLineage(ctx, orgID, parentIDOf(org))
+
+	s.logger.Info("Organization hard deleted",
+		zap.String("org_id", orgID),
+		zap.String("deleted_by", deletedBy),
+		zap.Int("groups", len(snapshot.Groups)),
+		zap.Int("members", len(snapshot.Members)))
+	return nil
+}
+
+// RestoreOrganization brings back a soft-deleted organization. Its parent, if any, must not be
+// deleted.
+func (s *Service) RestoreOrganization(ctx context.Context, orgID, restoredBy string) (*organizationResponses.OrganizationResponse, error) {
+	s.logger.Info("Restoring organization", zap.String("org_id", orgID))
+
+	org, err := s.restorableOrganization(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.orgRepo.Restore(ctx, orgID); err != nil {
+		s.logger.Error("Failed to restore organization", zap.String("org_id", orgID), zap.Error(err))
+
+		auditDetails := map[string]interface{}{
+			"organization_name": org.Name,
+			"restored_by":       restoredBy,
+			"error":             err.Error(),
+		}
+		s.auditService.LogOrganizationOperation(ctx, restoredBy, models.AuditActionRestoreOrganization, orgID, "Failed to restore organization", false, auditDetails)
+
+		return nil, errors.NewInternalError(err)
+	}
+
+	auditDetails := map[string]interface{}{
+		"organization_name": org.Name,
+		"restored_by":       restoredBy,
+		"deleted_at":        org.DeletedAt,
+	}
+	s.auditService.LogOrganizationOperation(ctx, restoredBy, models.AuditActionRestoreOrganization, orgID, "Organization restored successfully", true, auditDetails)
+	s.orgCache.InvalidateOrganizationCache(ctx, orgID)
+	s.invalidateLineage(ctx, orgID, parentIDOf(org))
+
+	s.logger.Info("Organization restored successfully", zap.String("org_id", orgID))
+	return s.GetOrganization(ctx, orgID)
+}
+
+// restorableOrganization returns a soft-deleted organization whose parent, if any, is not deleted
+func (s *Service) restorableOrganization(ctx context.Context, orgID string) (*models.Organization, error) {
+	org, err := s.orgRepo.GetByID(ctx, orgID)
+	if err != nil || org == nil {
+		return nil, errors.NewNotFoundError("organization not found")
+	}
+	if org.DeletedAt == nil {
+		return nil, errors.NewValidationError("organization is not deleted")
+	}
+
+	if org.ParentID != nil {
+		parent, err := s.orgRepo.GetByID(ctx, *org.ParentID)
+		if err != nil || parent == nil || parent.DeletedAt != nil {
+			s.logger.Warn("Parent organization is deleted", zap.String("parent_id", *org.ParentID))
+			return nil, errors.NewValidationError("cannot restore organization with deleted parent")
+		}
+	}
+	return org, nil
+}
+
+// ListOrganizationsWithDeleted lists every organization, soft-deleted ones included, newest first
+func (s *Service) ListOrganizationsWithDeleted(ctx context.Context, limit, offset int) ([]*organizationResponses.OrganizationResponse, error) {
+	orgs, err := s.orgRepo.ListWithDeleted(ctx, limit, offset)
+	if err != nil {
+		s.logger.Error("Failed to list organizations with deleted", zap.Error(err))
+		return nil, errors.NewInternalError(err)
+	}
+
+	responses := make([]*organizationResponses.OrganizationResponse, len(orgs))
+	for i, org := range orgs {
+		responses[i] = &organizationResponses.OrganizationResponse{
+			ID:          org.ID,
+			Name:        org.Name,
+			Type:        org.Type,
+			Description: org.Description,
+			ParentID:    org.ParentID,
+			IsActive:    org.IsActive,
+			CreatedAt:   &org.CreatedAt,
+			UpdatedAt:   &org.UpdatedAt,
+			DeletedAt:   org.DeletedAt,
+		}
+	}
+	return responses, nil
+}
+
+// CountOrganizationsWithDeleted counts every organization, soft-deleted ones included
+func (s *Service) CountOrganizationsWithDeleted(ctx context.Context) (int64, error) {
+	return s.orgRepo.CountWithDeleted(ctx)
+}
+
+// checkAuditRetention refuses a hard delete while audit logs of the organization are younger than
+// the retention period: they must keep describing an organization that still exists.
+func (s *Service) checkAuditRetention(ctx context.Context, orgID string) error {
+	if s.auditRepo == nil || s.auditRetentionDays <= 0 {
+		return nil
+	}
+
+	now := time.Now()
+	count, err := s.auditRepo.CountByOrganizationAndTimeRange(ctx, orgID, now.AddDate(0, 0, -s.auditRetentionDays), now)
+	if err != nil {
+		s.logger.Error("Failed to check audit retention", zap.String("org_id", orgID), zap.Error(err))
+		return errors.NewInternalError(err)
+	}
+	if count > 0 {
+		s.logger.Warn("Hard delete blocked by audit retention",
+			zap.String("org_id", orgID),
+			zap.Int64("audit_logs", count),
+			zap.Int("retention_days", s.auditRetentionDays))
+		return errors.NewConflictError(fmt.Sprintf("organization has %d audit logs inside the %d day retention period", count, s.auditRetentionDays))
+	}
+	return nil
+}
+
+// consumeHardDeleteConfirmation checks a confirmation token and invalidates it. A wrong token
+// also invalidates the pending confirmation, so tokens cannot be guessed.
+func (s *Service) consumeHardDeleteConfirmation(orgID, userID, token string) bool {
+	key := hardDeleteConfirmationKey(orgID)
+	value, ok := s.cache.Get(key)
+	if !ok {
+		return false
+	}
+	_ = s.cache.Delete(key)
+
+	data, ok := value.(string)
+	if !ok {
+		return false
+	}
+	var confirmation hardDeleteConfirmation
+	if err := json.Unmarshal([]byte(data), &confirmation); err != nil {
+		return false
+	}
+	return confirmation.RequestedBy == userID &&
+		time.Now().Before(confirmation.ExpiresAt) &&
+		subtle.ConstantTimeCompare([]byte(confirmation.TokenHash), []byte(hashConfirmationToken(token))) == 1
+}
+
+func hardDeleteConfirmationKey(orgID string) string {
+	return fmt.Sprintf("org_hard_delete:%s", orgID)
+}
+
+func hashConfirmationToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
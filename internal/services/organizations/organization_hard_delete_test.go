@@ -0,0 +1,288 @@
+package organizations
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	orgRepo "github.com/Kisanlink/aaa-service/v2/internal/repositories/organizations"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/Kisanlink/aaa-service/v2/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type hardDeleteTestOrgRepo struct {
+	interfaces.OrganizationRepository
+	orgs       map[string]*models.Organization
+	children   map[string][]*models.Organization
+	cascadeTo  []string
+	cascadeErr error
+}
+
+func (r *hardDeleteTestOrgRepo) GetByID(ctx context.Context, id string) (*models.Organization, error) {
+	org, ok := r.orgs[id]
+	if !ok {
+		return nil, fmt.Errorf("organization not found")
+	}
+	return org, nil
+}
+
+func (r *hardDeleteTestOrgRepo) GetChildren(ctx context.Context, parentID string) ([]*models.Organization, error) {
+	return r.children[parentID], nil
+}
+
+func (r *hardDeleteTestOrgRepo) HasActiveGroups(ctx context.Context, orgID string) (bool, error) {
+	return false, nil
+}
+
+func (r *hardDeleteTestOrgRepo) HardDeleteCascade(ctx context.Context, orgID string) (*models.OrganizationDeletionSnapshot, error) {
+	if r.cascadeErr != nil {
+		return nil, r.cascadeErr
+	}
+	r.cascadeTo = append(r.cascadeTo, orgID)
+	org := r.orgs[orgID]
+	delete(r.orgs, orgID)
+	return &models.OrganizationDeletionSnapshot{
+		Organization: org,
+		Groups:       []*models.Group{models.NewGroup("Field Team", "", orgID)},
+	}, nil
+}
+
+func (r *hardDeleteTestOrgRepo) Restore(ctx context.Context, id string) error {
+	r.orgs[id].DeletedAt = nil
+	return nil
+}
+
+type hardDeleteTestAuditRepo struct {
+	interfaces.AuditRepository
+	recent int64
+	since  time.Time
+}
+
+func (r *hardDeleteTestAuditRepo) CountByOrganizationAndTimeRange(ctx context.Context, orgID string, startTime, endTime time.Time) (int64, error) {
+	r.since = startTime
+	return r.recent, nil
+}
+
+type hardDeleteTestAudit struct {
+	memberTestAudit
+	systemEvents []map[string]interface{}
+}
+
+func (a *hardDeleteTestAudit) LogSystemEvent(ctx context.Context, action, resource string, success bool, details map[string]interface{}) {
+	a.actions = append(a.actions, action)
+	a.success = append(a.success, success)
+	a.systemEvents = append(a.systemEvents, details)
+}
+
+type hardDeleteTestCache struct {
+	settingTestCache
+}
+
+func (c *hardDeleteTestCache) Keys(pattern string) ([]string, error) {
+	return nil, nil
+}
+
+func newHardDeleteTestService(repo *hardDeleteTestOrgRepo, auditRepo *hardDeleteTestAuditRepo, audit *hardDeleteTestAudit) *Service {
+	service := NewOrganizationService(
+		repo,
+		&memberTestUserRepo{},
+		nil,
+		nil,
+		utils.NewValidator(),
+		&hardDeleteTestCache{settingTestCache{entries: map[string]interface{}{}}},
+		audit,
+		zap.NewNop(),
+	)
+	service.SetAuditRetention(auditRepo, 90)
+	return service
+}
+
+func newHardDeleteTestRepo() *hardDeleteTestOrgRepo {
+	org := models.NewOrganization("Kisan FPO", "", models.OrgTypeFPO)
+	org.ID = "ORG1"
+	return &hardDeleteTestOrgRepo{
+		orgs:     map[string]*models.Organization{"ORG1": org},
+		children: map[string][]*models.Organization{},
+	}
+}
+
+func TestHardDeleteOrganization_WithConfirmation(t *testing.T) {
+	repo := newHardDeleteTestRepo()
+	auditRepo := &hardDeleteTestAuditRepo{}
+	audit := &hardDeleteTestAudit{}
+	service := newHardDeleteTestService(repo, auditRepo, audit)
+	ctx := context.Background()
+
+	confirmation, err := service.RequestOrganizationHardDelete(ctx, "ORG1", "ADMIN1")
+	require.NoError(t, err)
+	assert.Equal(t, "Kisan FPO", confirmation.OrganizationName)
+	assert.NotEmpty(t, confirmation.ConfirmationToken)
+	assert.WithinDuration(t, time.Now().Add(HardDeleteConfirmationTTL), confirmation.ExpiresAt, 5*time.Second)
+	assert.WithinDuration(t, time.Now().AddDate(0, 0, -90), auditRepo.since, 5*time.Second)
+
+	require.NoError(t, service.HardDeleteOrganization(ctx, "ORG1", "ADMIN1", confirmation.ConfirmationToken))
+	assert.Equal(t, []string{"ORG1"}, repo.cascadeTo)
+
+	require.Len(t, audit.systemEvents, 1)
+	assert.Equal(t, []string{models.AuditActionHardDeleteOrganization}, audit.actions)
+	assert.Equal(t, "ADMIN1", audit.systemEvents[0]["deleted_by"])
+	snapshot, ok := audit.systemEvents[0]["snapshot"].(*models.OrganizationDeletionSnapshot)
+	require.True(t, ok, "the audit event records what was removed")
+	assert.Equal(t, "Kisan FPO", snapshot.Organization.Name)
+	assert.Len(t, snapshot.Groups, 1)
+}
+
+func TestHardDeleteOrganization_ConfirmationToken(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("missing token", func(t *testing.T) {
+		repo := newHardDeleteTestRepo()
+		service := newHardDeleteTestService(repo, &hardDeleteTestAuditRepo{}, &hardDeleteTestAudit{})
+
+		err := service.HardDeleteOrganization(ctx, "ORG1", "ADMIN1", "")
+		assert.True(t, errors.IsValidationError(err))
+		assert.Empty(t, repo.cascadeTo)
+	})
+
+	t.Run("token is single use", func(t *testing.T) {
+		repo := newHardDeleteTestRepo()
+		service := newHardDeleteTestService(repo, &hardDeleteTestAuditRepo{}, &hardDeleteTestAudit{})
+
+		confirmation, err := service.RequestOrganizationHardDelete(ctx, "ORG1", "ADMIN1")
+		require.NoError(t, err)
+		repo.cascadeErr = fmt.Errorf("database unavailable")
+		err = service.HardDeleteOrganization(ctx, "ORG1", "ADMIN1", confirmation.ConfirmationToken)
+		require.Error(t, err)
+
+		repo.cascadeErr = nil
+		err = service.HardDeleteOrganization(ctx, "ORG1", "ADMIN1", confirmation.ConfirmationToken)
+		assert.True(t, errors.IsValidationError(err))
+		assert.Empty(t, repo.cascadeTo)
+	})
+
+	t.Run("token of another user", func(t *testing.T) {
+		repo := newHardDeleteTestRepo()
+		service := newHardDeleteTestService(repo, &hardDeleteTestAuditRepo{}, &hardDeleteTestAudit{})
+
+		confirmation, err := service.RequestOrganizationHardDelete(ctx, "ORG1", "ADMIN1")
+		require.NoError(t, err)
+		err = service.HardDeleteOrganization(ctx, "ORG1", "ADMIN2", confirmation.ConfirmationToken)
+		assert.True(t, errors.IsValidationError(err))
+		assert.Empty(t, repo.cascadeTo)
+	})
+
+	t.Run("wrong token discards the confirmation", func(t *testing.T) {
+		repo := newHardDeleteTestRepo()
+		service := newHardDeleteTestService(repo, &hardDeleteTestAuditRepo{}, &hardDeleteTestAudit{})
+
+		confirmation, err := service.RequestOrganizationHardDelete(ctx, "ORG1", "ADMIN1")
+		require.NoError(t, err)
+		err = service.HardDeleteOrganization(ctx, "ORG1", "ADMIN1", "guess")
+		assert.True(t, errors.IsValidationError(err))
+
+		err = service.HardDeleteOrganization(ctx, "ORG1", "ADMIN1", confirmation.ConfirmationToken)
+		assert.True(t, errors.IsValidationError(err))
+		assert.Empty(t, repo.cascadeTo)
+	})
+}
+
+func TestHardDeleteOrganization_Refusals(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("audit logs inside the retention period", func(t *testing.T) {
+		repo := newHardDeleteTestRepo()
+		service := newHardDeleteTestService(repo, &hardDeleteTestAuditRepo{recent: 3}, &hardDeleteTestAudit{})
+
+		_, err := service.RequestOrganizationHardDelete(ctx, "ORG1", "ADMIN1")
+		assert.True(t, errors.IsConflictError(err))
+		assert.Contains(t, err.Error(), "90 day retention period")
+	})
+
+	t.Run("retention checked again at delete time", func(t *testing.T) {
+		repo := newHardDeleteTestRepo()
+		auditRepo := &hardDeleteTestAuditRepo{}
+		service := newHardDeleteTestService(repo, auditRepo, &hardDeleteTestAudit{})
+
+		confirmation, err := service.RequestOrganizationHardDelete(ctx, "ORG1", "ADMIN1")
+		require.NoError(t, err)
+		auditRepo.recent = 1
+		err = service.HardDeleteOrganization(ctx, "ORG1", "ADMIN1", confirmation.ConfirmationToken)
+		assert.True(t, errors.IsConflictError(err))
+		assert.Empty(t, repo.cascadeTo)
+	})
+
+	t.Run("child organizations", func(t *testing.T) {
+		repo := newHardDeleteTestRepo()
+		repo.children["ORG1"] = []*models.Organization{models.NewOrganization("Village Unit", "", models.OrgTypeSHG)}
+		service := newHardDeleteTestService(repo, &hardDeleteTestAuditRepo{}, &hardDeleteTestAudit{})
+
+		_, err := service.RequestOrganizationHardDelete(ctx, "ORG1", "ADMIN1")
+		assert.True(t, errors.IsValidationError(err))
+	})
+
+	t.Run("references outside the cascade", func(t *testing.T) {
+		repo := newHardDeleteTestRepo()
+		audit := &hardDeleteTestAudit{}
+		service := newHardDeleteTestService(repo, &hardDeleteTestAuditRepo{}, audit)
+
+		confirmation, err := service.RequestOrganizationHardDelete(ctx, "ORG1", "ADMIN1")
+		require.NoError(t, err)
+		repo.cascadeErr = fmt.Errorf("%w by 2 roles", orgRepo.ErrOrganizationHasDependents)
+		err = service.HardDeleteOrganization(ctx, "ORG1", "ADMIN1", confirmation.ConfirmationToken)
+		assert.True(t, errors.IsConflictError(err))
+		assert.Equal(t, []bool{false}, audit.success)
+	})
+
+	t.Run("unknown organization", func(t *testing.T) {
+		service := newHardDeleteTestService(newHardDeleteTestRepo(), &hardDeleteTestAuditRepo{}, &hardDeleteTestAudit{})
+
+		_, err := service.RequestOrganizationHardDelete(ctx, "ORG9", "ADMIN1")
+		assert.True(t, errors.IsNotFoundError(err))
+	})
+}
+
+func TestRestoreOrganization(t *testing.T) {
+	ctx := context.Background()
+	deletedAt := time.Now().Add(-time.Hour)
+
+	t.Run("restores a soft-deleted organization", func(t *testing.T) {
+		repo := newHardDeleteTestRepo()
+		repo.orgs["ORG1"].DeletedAt = &deletedAt
+		audit := &hardDeleteTestAudit{}
+		service := newHardDeleteTestService(repo, &hardDeleteTestAuditRepo{}, audit)
+
+		org, err := service.RestoreOrganization(ctx, "ORG1", "ADMIN1")
+		require.NoError(t, err)
+		assert.Equal(t, "ORG1", org.ID)
+		assert.Nil(t, org.DeletedAt)
+		assert.Equal(t, []string{models.AuditActionRestoreOrganization}, audit.actions)
+	})
+
+	t.Run("organization is not deleted", func(t *testing.T) {
+		service := newHardDeleteTestService(newHardDeleteTestRepo(), &hardDeleteTestAuditRepo{}, &hardDeleteTestAudit{})
+
+		_, err := service.RestoreOrganization(ctx, "ORG1", "ADMIN1")
+		assert.True(t, errors.IsValidationError(err))
+	})
+
+	t.Run("parent is deleted", func(t *testing.T) {
+		repo := newHardDeleteTestRepo()
+		parent := models.NewOrganization("Kisan Federation", "", models.OrgTypeFPO)
+		parent.ID = "ORG0"
+		parent.DeletedAt = &deletedAt
+		repo.orgs["ORG0"] = parent
+		repo.orgs["ORG1"].ParentID = &parent.ID
+		repo.orgs["ORG1"].DeletedAt = &deletedAt
+		service := newHardDeleteTestService(repo, &hardDeleteTestAuditRepo{}, &hardDeleteTestAudit{})
+
+		_, err := service.RestoreOrganization(ctx, "ORG1", "ADMIN1")
+		assert.True(t, errors.IsValidationError(err))
+		assert.NotNil(t, repo.orgs["ORG1"].DeletedAt)
+	})
+}
@@ -0,0 +1,208 @@
+package organizations
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	organizationResponses "github.com/Kisanlink/aaa-service/v2/internal/entities/responses/organizations"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// LineageDirection selects the ancestors or the descendants of an organization
+type LineageDirection string
+
+const (
+	LineageAncestors   LineageDirection = "ancestors"
+	LineageDescendants LineageDirection = "descendants"
+)
+
+// MaxLineageDepth bounds the number of hierarchy levels an ancestors or descendants list spans
+const MaxLineageDepth = 32
+
+// GetOrganizationAncestors retrieves a page of the ancestors of an organization, its parent first.
+// A maxDepth of 0 includes every ancestor.
+func (s *Service) GetOrganizationAncestors(ctx context.Context, orgID string, maxDepth, limit, offset int) (*organizationResponses.OrganizationLineageResponse, error) {
+	return s.getOrganizationLineage(ctx, orgID, LineageAncestors, maxDepth, limit, offset)
+}
+
+// GetOrganizationDescendants retrieves a page of the descendants of an organization level by level,
+// its direct children first. A maxDepth of 0 includes every descendant.
+func (s *Service) GetOrganizationDescendants(ctx context.Context, orgID string, maxDepth, limit, offset int) (*organizationResponses.OrganizationLineageResponse, error) {
+	return s.getOrganizationLineage(ctx, orgID, LineageDescendants, maxDepth, limit, offset)
+}
+
+// getOrganizationLineage pages through the cached full lineage of an organization. The lineage is
+// ordered by depth, so the depth limit selects a prefix of it.
+func (s *Service) getOrganizationLineage(ctx context.Context, orgID string, direction LineageDirection, maxDepth, limit, offset int) (*organizationResponses.OrganizationLineageResponse, error) {
+	s.logger.Info("Retrieving organization lineage",
+		zap.String("org_id", orgID),
+		zap.String("direction", string(direction)),
+		zap.Int("max_depth", maxDepth))
+
+	if maxDepth < 0 || maxDepth > MaxLineageDepth {
+		return nil, errors.NewValidationError(fmt.Sprintf("depth must be between 1 and %d", MaxLineageDepth))
+	}
+	if maxDepth == 0 {
+		maxDepth = MaxLineageDepth
+	}
+
+	nodes, found := s.orgCache.GetCachedOrganizationLineage(ctx, orgID, direction)
+	if !found {
+		if s.orgCache.IsOrganizationNotFoundCached(ctx, orgID) {
+			return nil, errors.NewNotFoundError("organization not found")
+		}
+
+		org, err := s.orgRepo.GetByID(ctx, orgID)
+		if err != nil || org == nil {
+			s.logger.Error("Organization not found", zap.String("org_id", orgID))
+			if org == nil && (err == nil || strings.Contains(err.Error(), "not found")) {
+				s.orgCache.CacheOrganizationNotFound(ctx, orgID)
+			}
+			return nil, errors.NewNotFoundError("organization not found")
+		}
+
+		if direction == LineageAncestors {
+			nodes, err = s.loadAncestors(ctx, orgID)
+		} else {
+			nodes, err = s.loadDescendants(ctx, orgID)
+		}
+		if err != nil {
+			s.logger.Error("Failed to load organization lineage",
+				zap.String("org_id", orgID),
+				zap.String("direction", string(direction)),
+				zap.Error(err))
+			return nil, errors.NewInternalError(err)
+		}
+		s.orgCache.CacheOrganizationLineage(ctx, orgID, direction, nodes)
+	}
+
+	nodes = nodes[:sort.Search(len(nodes), func(i int) bool { return nodes[i].Depth > maxDepth })]
+
+	response := &organizationResponses.OrganizationLineageResponse{
+		OrganizationID: orgID,
+		Organizations:  make([]*organizationResponses.OrganizationLineageNode, 0),
+		MaxDepth:       maxDepth,
+		TotalCount:     int64(len(nodes)),
+		Limit:          limit,
+		Offset:         offset,
+	}
+	if offset < len(nodes) {
+		response.Organizations = append(response.Organizations, nodes[offset:min(offset+limit, len(nodes))]...)
+	}
+
+	return response, nil
+}
+
+// loadAncestors builds the ancestors of an organization, parent first, from the parent hierarchy
+func (s *Service) loadAncestors(ctx context.Context, orgID string) ([]*organizationResponses.OrganizationLineageNode, error) {
+	parents, found := s.orgCache.GetCachedOrganizationParentHierarchy(ctx, orgID)
+	if !found {
+		var err error
+		parents, err = s.orgRepo.GetParentHierarchy(ctx, orgID)
+		if err != nil {
+			return nil, err
+		}
+		s.orgCache.CacheOrganizationParentHierarchy(ctx, orgID, parents)
+	}
+
+	// The parent hierarchy lists the root first
+	nodes := make([]*organizationResponses.OrganizationLineageNode, 0, min(len(parents), MaxLineageDepth))
+	for i := len(parents) - 1; i >= 0 && len(nodes) < MaxLineageDepth; i-- {
+		nodes = append(nodes, toOrganizationLineageNode(parents[i], len(nodes)+1))
+	}
+	return nodes, nil
+}
+
+// loadDescendants builds the descendants of an organization breadth first, down to MaxLineageDepth
+// levels. An organization reached twice is listed once, at its smallest depth.
+func (s *Service) loadDescendants(ctx context.Context, orgID string) ([]*organizationResponses.OrganizationLineageNode, error) {
+	var nodes []*organizationResponses.OrganizationLineageNode
+	visited := map[string]bool{orgID: true}
+	level := []string{orgID}
+
+	for depth := 1; depth <= MaxLineageDepth && len(level) > 0; depth++ {
+		var next []string
+		for _, parentID := range level {
+			children, found := s.orgCache.GetCachedOrganizationChildren(ctx, parentID, false)
+			if !found {
+				var err error
+				children, err = s.orgRepo.GetChildren(ctx, parentID)
+				if err != nil {
+					return nil, err
+				}
+				s.orgCache.CacheOrganizationChildren(ctx, parentID, children, false)
+			}
+
+			for _, child := range children {
+				if visited[child.ID] {
+					continue
+				}
+				visited[child.ID] = true
+				nodes = append(nodes, toOrganizationLineageNode(child, depth))
+				next = append(next, child.ID)
+			}
+		}
+		level = next
+	}
+
+	return nodes, nil
+}
+
+// invalidateLineage clears the hierarchy caches made stale when an organization is created, moved
+// or deleted: those of the organization, its descendants, and each of parentIDs with its ancestors
+func (s *Service) invalidateLineage(ctx context.Context, orgID string, parentIDs ...string) {
+	var affected []string
+	for _, parentID := range parentIDs {
+		if parentID == "" {
+			continue
+		}
+		affected = append(affected, parentID)
+
+		ancestors, err := s.orgRepo.GetParentHierarchy(ctx, parentID)
+		if err != nil {
+			s.logger.Warn("Failed to load ancestors for cache invalidation", zap.String("org_id", parentID), zap.Error(err))
+			continue
+		}
+		for _, ancestor := range ancestors {
+			affected = append(affected, ancestor.ID)
+		}
+	}
+
+	descendants, err := s.loadDescendants(ctx, orgID)
+	if err != nil {
+		s.logger.Warn("Failed to load descendants for cache invalidation", zap.String("org_id", orgID), zap.Error(err))
+	}
+	for _, descendant := range descendants {
+		affected = append(affected, descendant.ID)
+	}
+
+	s.orgCache.InvalidateHierarchyRelatedCache(ctx, orgID, affected)
+}
+
+// parentIDOf returns the parent ID of an organization, or "" for a root organization
+func parentIDOf(org *models.Organization) string {
+	if org.ParentID == nil {
+		return ""
+	}
+	return *org.ParentID
+}
+
+func toOrganizationLineageNode(org *models.Organization, depth int) *organizationResponses.OrganizationLineageNode {
+	return &organizationResponses.OrganizationLineageNode{
+		OrganizationResponse: organizationResponses.OrganizationResponse{
+			ID:          org.ID,
+			Name:        org.Name,
+			Type:        org.Type,
+			Description: org.Description,
+			ParentID:    org.ParentID,
+			IsActive:    org.IsActive,
+			CreatedAt:   &org.CreatedAt,
+			UpdatedAt:   &org.UpdatedAt,
+		},
+		Depth: depth,
+	}
+}
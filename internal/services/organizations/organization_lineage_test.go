@@ -0,0 +1,174 @@
+package organizations
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	organizationResponses "github.com/Kisanlink/aaa-service/v2/internal/entities/responses/organizations"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// lineageTestOrgRepo stores organizations in memory and counts child lookups
+type lineageTestOrgRepo struct {
+	interfaces.OrganizationRepository
+	orgs          map[string]*models.Organization
+	order         []string
+	childrenLoads int
+}
+
+func (r *lineageTestOrgRepo) add(id, parentID string) {
+	org := models.NewOrganization(id, "", models.OrgTypeFPO)
+	org.ID = id
+	if parentID != "" {
+		org.ParentID = &parentID
+	}
+	r.orgs[id] = org
+	r.order = append(r.order, id)
+}
+
+func (r *lineageTestOrgRepo) GetByID(ctx context.Context, id string) (*models.Organization, error) {
+	org, ok := r.orgs[id]
+	if !ok {
+		return nil, fmt.Errorf("organization not found")
+	}
+	return org, nil
+}
+
+func (r *lineageTestOrgRepo) GetChildren(ctx context.Context, parentID string) ([]*models.Organization, error) {
+	r.childrenLoads++
+	var children []*models.Organization
+	for _, id := range r.order {
+		if org := r.orgs[id]; org.ParentID != nil && *org.ParentID == parentID {
+			children = append(children, org)
+		}
+	}
+	return children, nil
+}
+
+func (r *lineageTestOrgRepo) GetParentHierarchy(ctx context.Context, orgID string) ([]*models.Organization, error) {
+	var parents []*models.Organization
+	for org := r.orgs[orgID]; org != nil && org.ParentID != nil; {
+		org = r.orgs[*org.ParentID]
+		if org == nil {
+			break
+		}
+		parents = append([]*models.Organization{org}, parents...)
+	}
+	return parents, nil
+}
+
+// newLineageTestService builds ROOT -> A -> A1 -> A1X, A -> A2 and ROOT -> B
+func newLineageTestService() (*Service, *lineageTestOrgRepo) {
+	repo := &lineageTestOrgRepo{orgs: map[string]*models.Organization{}}
+	repo.add("ROOT", "")
+	repo.add("A", "ROOT")
+	repo.add("B", "ROOT")
+	repo.add("A1", "A")
+	repo.add("A2", "A")
+	repo.add("A1X", "A1")
+
+	cache := &hierarchyTestCache{entries: map[string]interface{}{}}
+	service := NewOrganizationService(repo, nil, nil, nil, nil, cache, nil, zap.NewNop())
+	return service, repo
+}
+
+func lineageIDs(lineage *organizationResponses.OrganizationLineageResponse) []string {
+	ids := make([]string, 0, len(lineage.Organizations))
+	for _, node := range lineage.Organizations {
+		ids = append(ids, fmt.Sprintf("%s@%d", node.ID, node.Depth))
+	}
+	return ids
+}
+
+func TestService_GetOrganizationAncestors(t *testing.T) {
+	service, _ := newLineageTestService()
+	ctx := context.Background()
+
+	lineage, err := service.GetOrganizationAncestors(ctx, "A1X", 0, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"A1@1", "A@2", "ROOT@3"}, lineageIDs(lineage))
+	assert.Equal(t, int64(3), lineage.TotalCount)
+	assert.Equal(t, MaxLineageDepth, lineage.MaxDepth)
+
+	lineage, err = service.GetOrganizationAncestors(ctx, "A1X", 2, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"A1@1", "A@2"}, lineageIDs(lineage))
+	assert.Equal(t, int64(2), lineage.TotalCount)
+
+	lineage, err = service.GetOrganizationAncestors(ctx, "ROOT", 0, 10, 0)
+	require.NoError(t, err)
+	assert.Empty(t, lineage.Organizations)
+	assert.NotNil(t, lineage.Organizations, "an empty page is an empty list, not null")
+}
+
+func TestService_GetOrganizationDescendants(t *testing.T) {
+	service, repo := newLineageTestService()
+	ctx := context.Background()
+
+	lineage, err := service.GetOrganizationDescendants(ctx, "ROOT", 0, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"A@1", "B@1", "A1@2", "A2@2", "A1X@3"}, lineageIDs(lineage))
+
+	loads := repo.childrenLoads
+	lineage, err = service.GetOrganizationDescendants(ctx, "ROOT", 2, 2, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"A1@2", "A2@2"}, lineageIDs(lineage))
+	assert.Equal(t, int64(4), lineage.TotalCount, "the total counts every descendant within the depth")
+	assert.Equal(t, loads, repo.childrenLoads, "the lineage is served from the cache")
+
+	lineage, err = service.GetOrganizationDescendants(ctx, "ROOT", 0, 10, 50)
+	require.NoError(t, err)
+	assert.Empty(t, lineage.Organizations)
+	assert.Equal(t, int64(5), lineage.TotalCount)
+}
+
+func TestService_GetOrganizationLineage_Errors(t *testing.T) {
+	service, _ := newLineageTestService()
+	ctx := context.Background()
+
+	_, err := service.GetOrganizationDescendants(ctx, "ROOT", MaxLineageDepth+1, 10, 0)
+	assert.True(t, errors.IsValidationError(err))
+
+	_, err = service.GetOrganizationAncestors(ctx, "ROOT", -1, 10, 0)
+	assert.True(t, errors.IsValidationError(err))
+
+	_, err = service.GetOrganizationAncestors(ctx, "MISSING", 0, 10, 0)
+	assert.True(t, errors.IsNotFoundError(err))
+}
+
+func TestService_InvalidateLineage(t *testing.T) {
+	service, repo := newLineageTestService()
+	ctx := context.Background()
+
+	_, err := service.GetOrganizationDescendants(ctx, "ROOT", 0, 10, 0)
+	require.NoError(t, err)
+	_, err = service.GetOrganizationAncestors(ctx, "A1X", 0, 10, 0)
+	require.NoError(t, err)
+
+	// Move A1 under B: ROOT's descendants keep their members but A1X gains a new ancestor chain
+	b := "B"
+	repo.orgs["A1"].ParentID = &b
+	service.invalidateLineage(ctx, "A1", "A", "B")
+
+	lineage, err := service.GetOrganizationAncestors(ctx, "A1X", 0, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"A1@1", "B@2", "ROOT@3"}, lineageIDs(lineage))
+
+	lineage, err = service.GetOrganizationDescendants(ctx, "ROOT", 0, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"A@1", "B@1", "A2@2", "A1@2", "A1X@3"}, lineageIDs(lineage))
+
+	// A new organization appears in the descendants of its parent chain
+	repo.add("A2Y", "A2")
+	service.invalidateLineage(ctx, "A2Y", "A2")
+
+	lineage, err = service.GetOrganizationDescendants(ctx, "ROOT", 0, 10, 0)
+	require.NoError(t, err)
+	assert.Contains(t, lineageIDs(lineage), "A2Y@3")
+}
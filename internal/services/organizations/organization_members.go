@@ -0,0 +1,263 @@
+package organizations
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/requests/organizations"
+	organizationResponses "github.com/Kisanlink/aaa-service/v2/internal/entities/responses/organizations"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// MemberRemovalPolicy decides what happens when a user being removed from an organization
+// still belongs to groups in that organization
+type MemberRemovalPolicy string
+
+const (
+	// MemberRemovalPolicyWarn removes the member and reports the remaining group memberships
+	MemberRemovalPolicyWarn MemberRemovalPolicy = "warn"
+	// MemberRemovalPolicyBlock refuses the removal until the user has left every group in the organization
+	MemberRemovalPolicyBlock MemberRemovalPolicy = "block"
+)
+
+// ParseMemberRemovalPolicy parses a policy name, falling back to MemberRemovalPolicyWarn for unknown values
+func ParseMemberRemovalPolicy(value string) MemberRemovalPolicy {
+	if MemberRemovalPolicy(strings.ToLower(strings.TrimSpace(value))) == MemberRemovalPolicyBlock {
+		return MemberRemovalPolicyBlock
+	}
+	return MemberRemovalPolicyWarn
+}
+
+// SetMemberRepository sets the repository backing direct organization membership
+func (s *Service) SetMemberRepository(memberRepo interfaces.OrganizationMemberRepository) {
+	s.memberRepo = memberRepo
+}
+
+// SetMemberRemovalPolicy sets how removals of users who still belong to organization groups are handled
+func (s *Service) SetMemberRemovalPolicy(policy MemberRemovalPolicy) {
+	s.memberRemovalPolicy = policy
+}
+
+// AddUserToOrganization makes a user a direct member of an organization
+func (s *Service) AddUserToOrganization(ctx context.Context, orgID string, req *organizations.AddOrganizationMemberRequest, addedBy string) (*organizationResponses.OrganizationMemberResponse, error) {
+	s.logger.Info("Adding user to organization",
+		zap.String("org_id", orgID),
+		zap.String("user_id", req.UserID),
+		zap.String("added_by", addedBy))
+
+	if s.memberRepo == nil {
+		return nil, errors.NewInternalError(fmt.Errorf("organization member repository not configured"))
+	}
+
+	if err := s.validator.ValidateStruct(req); err != nil {
+		s.logger.Error("Organization member validation failed", zap.Error(err))
+		return nil, errors.NewValidationError("invalid organization member data", err.Error())
+	}
+
+	role := req.Role
+	if role == "" {
+		role = models.OrganizationMemberRoleMember
+	}
+	if !models.ValidOrganizationMemberRole(role) {
+		return nil, errors.NewValidationError("invalid organization member role")
+	}
+
+	// Verify organization exists and can accept members
+	org, err := s.orgRepo.GetByID(ctx, orgID)
+	if err != nil || org == nil {
+		s.logger.Error("Organization not found", zap.String("org_id", orgID))
+		return nil, errors.NewNotFoundError("organization not found")
+	}
+	if !org.IsActive {
+		s.logger.Warn("Cannot add member to inactive organization", zap.String("org_id", orgID))
+		return nil, errors.NewValidationError("organization is inactive")
+	}
+
+	// Verify user exists
+	user, err := s.userRepo.GetByID(ctx, req.UserID)
+	if err != nil || user == nil {
+		s.logger.Error("User not found", zap.String("user_id", req.UserID))
+		return nil, errors.NewNotFoundError("user not found")
+	}
+
+	if existing, err := s.memberRepo.GetByOrganizationAndUser(ctx, orgID, req.UserID); err == nil && existing != nil {
+		s.logger.Warn("User is already a member of organization",
+			zap.String("org_id", orgID),
+			zap.String("user_id", req.UserID))
+		return nil, errors.NewConflictError("user is already a member of this organization")
+	}
+
+	member := models.NewOrganizationMember(orgID, req.UserID, role, addedBy)
+	if err := s.memberRepo.Create(ctx, member); err != nil {
+		s.logger.Error("Failed to add user to organization", zap.Error(err))
+
+		auditDetails := map[string]interface{}{
+			"user_id": req.UserID,
+			"role":    role,
+			"error":   err.Error(),
+		}
+		s.auditService.LogOrganizationOperation(ctx, addedBy, models.AuditActionAddOrganizationMember, orgID, "Failed to add organization member", false, auditDetails)
+
+		return nil, errors.NewInternalError(err)
+	}
+
+	auditDetails := map[string]interface{}{
+		"user_id":           req.UserID,
+		"role":              role,
+		"organization_name": org.Name,
+	}
+	s.auditService.LogOrganizationOperation(ctx, addedBy, models.AuditActionAddOrganizationMember, orgID, "Organization member added successfully", true, auditDetails)
+
+	s.orgCache.InvalidateOrganizationStats(ctx, orgID)
+
+	s.logger.Info("User added to organization successfully",
+		zap.String("org_id", orgID),
+		zap.String("user_id", req.UserID),
+		zap.String("member_id", member.ID))
+
+	return toOrganizationMemberResponse(member), nil
+}
+
+// RemoveUserFromOrganization ends a user's direct membership of an organization.
+// Group memberships in the organization are left untouched; depending on the configured
+// MemberRemovalPolicy they either block the removal or are reported back as a warning.
+func (s *Service) RemoveUserFromOrganization(ctx context.Context, orgID, userID string, removedBy string) (*organizationResponses.OrganizationMemberRemovalResponse, error) {
+	s.logger.Info("Removing user from organization",
+		zap.String("org_id", orgID),
+		zap.String("user_id", userID),
+		zap.String("removed_by", removedBy))
+
+	if s.memberRepo == nil {
+		return nil, errors.NewInternalError(fmt.Errorf("organization member repository not configured"))
+	}
+
+	// Verify organization exists
+	org, err := s.orgRepo.GetByID(ctx, orgID)
+	if err != nil || org == nil {
+		s.logger.Error("Organization not found", zap.String("org_id", orgID))
+		return nil, errors.NewNotFoundError("organization not found")
+	}
+
+	member, err := s.memberRepo.GetByOrganizationAndUser(ctx, orgID, userID)
+	if err != nil || member == nil {
+		s.logger.Warn("User is not a member of organization",
+			zap.String("org_id", orgID),
+			zap.String("user_id", userID))
+		return nil, errors.NewNotFoundError("user is not a member of this organization")
+	}
+
+	groupMemberships, err := s.memberRepo.CountActiveGroupMemberships(ctx, orgID, userID)
+	if err != nil {
+		s.logger.Error("Failed to check group memberships", zap.Error(err))
+		return nil, errors.NewInternalError(err)
+	}
+
+	response := &organizationResponses.OrganizationMemberRemovalResponse{
+		OrganizationID:   orgID,
+		UserID:           userID,
+		GroupMemberships: groupMemberships,
+	}
+
+	if groupMemberships > 0 {
+		if s.memberRemovalPolicy == MemberRemovalPolicyBlock {
+			s.logger.Warn("Cannot remove organization member who still belongs to organization groups",
+				zap.String("org_id", orgID),
+				zap.String("user_id", userID),
+				zap.Int64("group_memberships", groupMemberships))
+			return nil, errors.NewConflictError(fmt.Sprintf("user still belongs to %d group(s) in this organization; remove them from those groups first", groupMemberships))
+		}
+
+		response.Warning = fmt.Sprintf("user still belongs to %d group(s) in this organization", groupMemberships)
+		s.logger.Warn("Removing organization member who still belongs to organization groups",
+			zap.String("org_id", orgID),
+			zap.String("user_id", userID),
+			zap.Int64("group_memberships", groupMemberships))
+	}
+
+	if err := s.memberRepo.SoftDelete(ctx, member.ID, removedBy); err != nil {
+		s.logger.Error("Failed to remove user from organization", zap.Error(err))
+
+		auditDetails := map[string]interface{}{
+			"user_id": userID,
+			"error":   err.Error(),
+		}
+		s.auditService.LogOrganizationOperation(ctx, removedBy, models.AuditActionRemoveOrganizationMember, orgID, "Failed to remove organization member", false, auditDetails)
+
+		return nil, errors.NewInternalError(err)
+	}
+
+	auditDetails := map[string]interface{}{
+		"user_id":           userID,
+		"role":              member.Role,
+		"organization_name": org.Name,
+		"group_memberships": groupMemberships,
+	}
+	s.auditService.LogOrganizationOperation(ctx, removedBy, models.AuditActionRemoveOrganizationMember, orgID, "Organization member removed successfully", true, auditDetails)
+
+	s.orgCache.InvalidateOrganizationStats(ctx, orgID)
+
+	s.logger.Info("User removed from organization successfully",
+		zap.String("org_id", orgID),
+		zap.String("user_id", userID))
+
+	return response, nil
+}
+
+// ListOrganizationMembers retrieves the direct members of an organization with pagination
+func (s *Service) ListOrganizationMembers(ctx context.Context, orgID string, limit, offset int) (*organizationResponses.OrganizationMembersResponse, error) {
+	s.logger.Info("Listing organization members",
+		zap.String("org_id", orgID),
+		zap.Int("limit", limit),
+		zap.Int("offset", offset))
+
+	if s.memberRepo == nil {
+		return nil, errors.NewInternalError(fmt.Errorf("organization member repository not configured"))
+	}
+
+	// Verify organization exists
+	org, err := s.orgRepo.GetByID(ctx, orgID)
+	if err != nil || org == nil {
+		s.logger.Error("Organization not found", zap.String("org_id", orgID))
+		return nil, errors.NewNotFoundError("organization not found")
+	}
+
+	members, err := s.memberRepo.ListByOrganization(ctx, orgID, limit, offset)
+	if err != nil {
+		s.logger.Error("Failed to list organization members", zap.Error(err))
+		return nil, errors.NewInternalError(err)
+	}
+
+	total, err := s.memberRepo.CountByOrganization(ctx, orgID)
+	if err != nil {
+		s.logger.Error("Failed to count organization members", zap.Error(err))
+		return nil, errors.NewInternalError(err)
+	}
+
+	response := &organizationResponses.OrganizationMembersResponse{
+		OrganizationID: orgID,
+		Members:        make([]*organizationResponses.OrganizationMemberResponse, 0, len(members)),
+		TotalCount:     total,
+		Limit:          limit,
+		Offset:         offset,
+	}
+	for _, member := range members {
+		response.Members = append(response.Members, toOrganizationMemberResponse(member))
+	}
+
+	return response, nil
+}
+
+func toOrganizationMemberResponse(member *models.OrganizationMember) *organizationResponses.OrganizationMemberResponse {
+	return &organizationResponses.OrganizationMemberResponse{
+		ID:             member.ID,
+		OrganizationID: member.OrganizationID,
+		UserID:         member.UserID,
+		Role:           member.Role,
+		AddedByID:      member.AddedByID,
+		CreatedAt:      &member.CreatedAt,
+	}
+}
@@ -0,0 +1,233 @@
+package organizations
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/requests/organizations"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/Kisanlink/aaa-service/v2/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type memberTestOrgRepo struct {
+	interfaces.OrganizationRepository
+	orgs map[string]*models.Organization
+}
+
+func (r *memberTestOrgRepo) GetByID(ctx context.Context, id string) (*models.Organization, error) {
+	org, ok := r.orgs[id]
+	if !ok {
+		return nil, fmt.Errorf("organization not found")
+	}
+	return org, nil
+}
+
+type memberTestUserRepo struct {
+	interfaces.UserRepositoryInterface
+	users map[string]bool
+}
+
+func (r *memberTestUserRepo) GetByID(ctx context.Context, id string) (*models.User, error) {
+	if !r.users[id] {
+		return nil, fmt.Errorf("user not found")
+	}
+	return &models.User{}, nil
+}
+
+type memberTestMemberRepo struct {
+	members          map[string]*models.OrganizationMember
+	groupMemberships map[string]int64
+}
+
+func (r *memberTestMemberRepo) Create(ctx context.Context, member *models.OrganizationMember) error {
+	r.members[member.OrganizationID+"/"+member.UserID] = member
+	return nil
+}
+
+func (r *memberTestMemberRepo) GetByOrganizationAndUser(ctx context.Context, orgID, userID string) (*models.OrganizationMember, error) {
+	member, ok := r.members[orgID+"/"+userID]
+	if !ok {
+		return nil, fmt.Errorf("not a member")
+	}
+	return member, nil
+}
+
+func (r *memberTestMemberRepo) ListByOrganization(ctx context.Context, orgID string, limit, offset int) ([]*models.OrganizationMember, error) {
+	var members []*models.OrganizationMember
+	for _, member := range r.members {
+		if member.OrganizationID == orgID {
+			members = append(members, member)
+		}
+	}
+	return members, nil
+}
+
+func (r *memberTestMemberRepo) CountByOrganization(ctx context.Context, orgID string) (int64, error) {
+	members, _ := r.ListByOrganization(ctx, orgID, 0, 0)
+	return int64(len(members)), nil
+}
+
+func (r *memberTestMemberRepo) SoftDelete(ctx context.Context, id string, deletedBy string) error {
+	for key, member := range r.members {
+		if member.ID == id {
+			delete(r.members, key)
+			return nil
+		}
+	}
+	return fmt.Errorf("membership not found")
+}
+
+func (r *memberTestMemberRepo) CountActiveGroupMemberships(ctx context.Context, orgID, userID string) (int64, error) {
+	return r.groupMemberships[orgID+"/"+userID], nil
+}
+
+type memberTestCache struct {
+	interfaces.CacheService
+}
+
+func (c *memberTestCache) Delete(key string) error { return nil }
+
+type memberTestAudit struct {
+	interfaces.AuditService
+	actions []string
+	success []bool
+}
+
+func (a *memberTestAudit) LogOrganizationOperation(ctx context.Context, userID, action, orgID, message string, success bool, details map[string]interface{}) {
+	a.actions = append(a.actions, action)
+	a.success = append(a.success, success)
+}
+
+func newMemberTestService(memberRepo *memberTestMemberRepo, audit *memberTestAudit) *Service {
+	active := models.NewOrganization("Kisan FPO", "", models.OrgTypeFPO)
+	active.IsActive = true
+	inactive := models.NewOrganization("Dormant FPO", "", models.OrgTypeFPO)
+	inactive.IsActive = false
+
+	service := NewOrganizationService(
+		&memberTestOrgRepo{orgs: map[string]*models.Organization{"ORG1": active, "ORG2": inactive}},
+		&memberTestUserRepo{users: map[string]bool{"USER1": true, "USER2": true}},
+		nil,
+		nil,
+		utils.NewValidator(),
+		&memberTestCache{},
+		audit,
+		zap.NewNop(),
+	)
+	service.SetMemberRepository(memberRepo)
+	return service
+}
+
+func TestService_AddUserToOrganization(t *testing.T) {
+	memberRepo := &memberTestMemberRepo{members: map[string]*models.OrganizationMember{}}
+	audit := &memberTestAudit{}
+	service := newMemberTestService(memberRepo, audit)
+
+	member, err := service.AddUserToOrganization(context.Background(), "ORG1", &organizations.AddOrganizationMemberRequest{UserID: "USER1"}, "ADMIN1")
+	require.NoError(t, err)
+	assert.Equal(t, "USER1", member.UserID)
+	assert.Equal(t, models.OrganizationMemberRoleMember, member.Role, "role defaults to member")
+	assert.Equal(t, "ADMIN1", member.AddedByID)
+	assert.Equal(t, []string{models.AuditActionAddOrganizationMember}, audit.actions)
+
+	_, err = service.AddUserToOrganization(context.Background(), "ORG1", &organizations.AddOrganizationMemberRequest{UserID: "USER1", Role: models.OrganizationMemberRoleAdmin}, "ADMIN1")
+	assert.True(t, errors.IsConflictError(err), "a user can only be added once")
+
+	members, err := service.ListOrganizationMembers(context.Background(), "ORG1", 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), members.TotalCount)
+	require.Len(t, members.Members, 1)
+}
+
+func TestService_AddUserToOrganization_Rejected(t *testing.T) {
+	tests := []struct {
+		name  string
+		orgID string
+		req   *organizations.AddOrganizationMemberRequest
+		check func(error) bool
+	}{
+		{name: "unknown organization", orgID: "ORG404", req: &organizations.AddOrganizationMemberRequest{UserID: "USER1"}, check: errors.IsNotFoundError},
+		{name: "inactive organization", orgID: "ORG2", req: &organizations.AddOrganizationMemberRequest{UserID: "USER1"}, check: errors.IsValidationError},
+		{name: "unknown user", orgID: "ORG1", req: &organizations.AddOrganizationMemberRequest{UserID: "USER404"}, check: errors.IsNotFoundError},
+		{name: "invalid role", orgID: "ORG1", req: &organizations.AddOrganizationMemberRequest{UserID: "USER1", Role: "superuser"}, check: errors.IsValidationError},
+		{name: "missing user", orgID: "ORG1", req: &organizations.AddOrganizationMemberRequest{}, check: errors.IsValidationError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			memberRepo := &memberTestMemberRepo{members: map[string]*models.OrganizationMember{}}
+			service := newMemberTestService(memberRepo, &memberTestAudit{})
+
+			_, err := service.AddUserToOrganization(context.Background(), tt.orgID, tt.req, "ADMIN1")
+			require.Error(t, err)
+			assert.True(t, tt.check(err), err.Error())
+			assert.Empty(t, memberRepo.members)
+		})
+	}
+}
+
+func TestService_RemoveUserFromOrganization(t *testing.T) {
+	tests := []struct {
+		name             string
+		policy           MemberRemovalPolicy
+		groupMemberships int64
+		wantConflict     bool
+		wantWarning      bool
+	}{
+		{name: "no group memberships", policy: MemberRemovalPolicyBlock},
+		{name: "warn policy allows removal", policy: MemberRemovalPolicyWarn, groupMemberships: 2, wantWarning: true},
+		{name: "unset policy warns", groupMemberships: 1, wantWarning: true},
+		{name: "block policy refuses removal", policy: MemberRemovalPolicyBlock, groupMemberships: 2, wantConflict: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			memberRepo := &memberTestMemberRepo{
+				members:          map[string]*models.OrganizationMember{},
+				groupMemberships: map[string]int64{"ORG1/USER1": tt.groupMemberships},
+			}
+			audit := &memberTestAudit{}
+			service := newMemberTestService(memberRepo, audit)
+			service.SetMemberRemovalPolicy(tt.policy)
+
+			_, err := service.AddUserToOrganization(context.Background(), "ORG1", &organizations.AddOrganizationMemberRequest{UserID: "USER1"}, "ADMIN1")
+			require.NoError(t, err)
+
+			result, err := service.RemoveUserFromOrganization(context.Background(), "ORG1", "USER1", "ADMIN1")
+			if tt.wantConflict {
+				require.Error(t, err)
+				assert.True(t, errors.IsConflictError(err), err.Error())
+				assert.Len(t, memberRepo.members, 1, "membership is kept when removal is blocked")
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Empty(t, memberRepo.members)
+			assert.Equal(t, tt.groupMemberships, result.GroupMemberships)
+			assert.Equal(t, tt.wantWarning, result.Warning != "")
+			assert.Equal(t, models.AuditActionRemoveOrganizationMember, audit.actions[len(audit.actions)-1])
+		})
+	}
+}
+
+func TestService_RemoveUserFromOrganization_NotMember(t *testing.T) {
+	memberRepo := &memberTestMemberRepo{members: map[string]*models.OrganizationMember{}}
+	service := newMemberTestService(memberRepo, &memberTestAudit{})
+
+	_, err := service.RemoveUserFromOrganization(context.Background(), "ORG1", "USER2", "ADMIN1")
+	require.Error(t, err)
+	assert.True(t, errors.IsNotFoundError(err))
+}
+
+func TestParseMemberRemovalPolicy(t *testing.T) {
+	assert.Equal(t, MemberRemovalPolicyBlock, ParseMemberRemovalPolicy(" Block "))
+	assert.Equal(t, MemberRemovalPolicyWarn, ParseMemberRemovalPolicy("warn"))
+	assert.Equal(t, MemberRemovalPolicyWarn, ParseMemberRemovalPolicy(""))
+	assert.Equal(t, MemberRemovalPolicyWarn, ParseMemberRemovalPolicy("unknown"))
+}
@@ -0,0 +1,139 @@
+package organizations
+
+import (
+	"context"
+	stdErrors "errors"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	organizationResponses "github.com/Kisanlink/aaa-service/v2/internal/entities/responses/organizations"
+	orgRepo "github.com/Kisanlink/aaa-service/v2/internal/repositories/organizations"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// MergeOrganizations merges the source organization into the target: the target takes over the
+// source's child organizations, groups, members, roles, role constraints and settings, and the
+// source is soft-deleted, all in one transaction. Name and permission conflicts are resolved as
+// documented on OrganizationRepository.MergeInto. Neither organization may be an ancestor of the
+// other. The merge, each reparented child and each moved group are recorded as structure changes.
+func (s *Service) MergeOrganizations(ctx context.Context, sourceID, targetID, mergedBy string) (*organizationResponses.OrganizationMergeResponse, error) {
+	s.logger.Info("Merging organizations",
+		zap.String("source_id", sourceID),
+		zap.String("target_id", targetID),
+		zap.String("merged_by", mergedBy))
+
+	if sourceID == "" || targetID == "" {
+		return nil, errors.NewValidationError("source and target organizations are required")
+	}
+	if mergedBy == "" {
+		return nil, errors.NewValidationError("merging user is required")
+	}
+	if sourceID == targetID {
+		return nil, errors.NewValidationError("an organization cannot be merged into itself")
+	}
+
+	source, err := s.orgRepo.GetByID(ctx, sourceID)
+	if err != nil || source == nil || source.DeletedAt != nil {
+		return nil, errors.NewNotFoundError("organization not found")
+	}
+	target, err := s.orgRepo.GetByID(ctx, targetID)
+	if err != nil || target == nil || target.DeletedAt != nil {
+		return nil, errors.NewNotFoundError("target organization not found")
+	}
+
+	// Reparenting the source's children under a descendant would create a cycle; the repository
+	// repeats the check under lock
+	if s.checkCircularReference(ctx, sourceID, targetID) != nil || s.checkCircularReference(ctx, targetID, sourceID) != nil {
+		return nil, errors.NewValidationError("an organization cannot be merged with its ancestor or descendant")
+	}
+
+	summary, err := s.orgRepo.MergeInto(ctx, sourceID, targetID, mergedBy)
+	if err != nil {
+		s.logger.Error("Failed to merge organizations",
+			zap.String("source_id", sourceID),
+			zap.String("target_id", targetID),
+			zap.Error(err))
+
+		auditDetails := map[string]interface{}{
+			"organization_name": source.Name,
+			"target_id":         targetID,
+			"merged_by":         mergedBy,
+			"error":             err.Error(),
+		}
+		s.auditService.LogOrganizationOperation(ctx, mergedBy, models.AuditActionMergeOrganization, sourceID, "Failed to merge organization", false, auditDetails)
+
+		switch {
+		case stdErrors.Is(err, orgRepo.ErrOrganizationMergeCycle):
+			return nil, errors.NewValidationError("an organization cannot be merged with its ancestor or descendant")
+		case stdErrors.Is(err, orgRepo.ErrOrganizationMergeTooDeep):
+			return nil, errors.NewConflictError(err.Error())
+		case stdErrors.Is(err, orgRepo.ErrOrganizationMergeNotFound):
+			return nil, errors.NewNotFoundError("organization not found")
+		}
+		return nil, errors.NewInternalError(err)
+	}
+
+	s.auditMerge(ctx, source, target, mergedBy, summary)
+
+	for _, orgID := range append([]string{sourceID, targetID}, summary.ReparentedChildren...) {
+		s.orgCache.InvalidateOrganizationCache(ctx, orgID)
+	}
+	s.invalidateLineage(ctx, sourceID, parentIDOf(source))
+	s.invalidateLineage(ctx, targetID, parentIDOf(target))
+
+	s.logger.Info("Organizations merged",
+		zap.String("source_id", sourceID),
+		zap.String("target_id", targetID),
+		zap.Int("children", len(summary.ReparentedChildren)),
+		zap.Int("groups", len(summary.MovedGroups)),
+		zap.Int("members", len(summary.MovedMembers)+len(summary.MergedMembers)),
+		zap.Int("roles", len(summary.MovedRoles)+len(summary.MergedRoles)))
+
+	org, err := s.GetOrganization(ctx, targetID)
+	if err != nil {
+		return nil, err
+	}
+	return &organizationResponses.OrganizationMergeResponse{Organization: org, Summary: summary}, nil
+}
+
+// auditMerge records the merge on the target, then every child organization and group that moved
+func (s *Service) auditMerge(ctx context.Context, source, target *models.Organization, mergedBy string, summary *models.OrganizationMergeSummary) {
+	oldValues := map[string]interface{}{
+		"organization_id":   source.ID,
+		"organization_name": source.Name,
+		"parent_id":         source.ParentID,
+	}
+	newValues := map[string]interface{}{
+		"merged_into":          target.ID,
+		"reparented_children":  summary.ReparentedChildren,
+		"moved_groups":         summary.MovedGroups,
+		"renamed_groups":       summary.RenamedGroups,
+		"moved_members":        summary.MovedMembers,
+		"merged_members":       summary.MergedMembers,
+		"moved_roles":          summary.MovedRoles,
+		"merged_roles":         summary.MergedRoles,
+		"unmerged_permissions": summary.UnmergedPermissions,
+		"moved_settings":       summary.MovedSettings,
+		"skipped_settings":     summary.SkippedSettings,
+		"moved_records":        summary.MovedRecords,
+	}
+	s.auditService.LogOrganizationStructureChange(ctx, mergedBy, models.AuditActionMergeOrganization, target.ID, models.ResourceTypeOrganization, source.ID, oldValues, newValues, true, "Organization merged")
+
+	for _, childID := range summary.ReparentedChildren {
+		s.auditService.LogOrganizationStructureChange(ctx, mergedBy, models.AuditActionChangeOrganizationHierarchy, childID, models.ResourceTypeOrganization, childID,
+			map[string]interface{}{"parent_id": source.ID},
+			map[string]interface{}{"parent_id": target.ID},
+			true, "Organization reparented by merge")
+	}
+
+	for _, groupID := range summary.MovedGroups {
+		newGroupValues := map[string]interface{}{"organization_id": target.ID}
+		if name, ok := summary.RenamedGroups[groupID]; ok {
+			newGroupValues["name"] = name
+		}
+		s.auditService.LogOrganizationStructureChange(ctx, mergedBy, models.AuditActionMergeOrganization, target.ID, models.ResourceTypeGroup, groupID,
+			map[string]interface{}{"organization_id": source.ID},
+			newGroupValues,
+			true, "Group moved by organization merge")
+	}
+}
@@ -0,0 +1,144 @@
+package organizations
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	orgRepo "github.com/Kisanlink/aaa-service/v2/internal/repositories/organizations"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/Kisanlink/aaa-service/v2/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type mergeTestOrgRepo struct {
+	*hardDeleteTestOrgRepo
+	merged   [][2]string
+	mergeErr error
+}
+
+func (r *mergeTestOrgRepo) GetParentHierarchy(ctx context.Context, orgID string) ([]*models.Organization, error) {
+	return nil, nil
+}
+
+func (r *mergeTestOrgRepo) MergeInto(ctx context.Context, sourceID, targetID, mergedBy string) (*models.OrganizationMergeSummary, error) {
+	if r.mergeErr != nil {
+		return nil, r.mergeErr
+	}
+	r.merged = append(r.merged, [2]string{sourceID, targetID})
+	return &models.OrganizationMergeSummary{
+		SourceID:           sourceID,
+		TargetID:           targetID,
+		ReparentedChildren: []string{"ORG3"},
+		MovedGroups:        []string{"GRP1", "GRP2"},
+		RenamedGroups:      map[string]string{"GRP2": "Field Team (Kisan FPO)"},
+		MovedMembers:       []string{"USER1"},
+	}, nil
+}
+
+type mergeTestAudit struct {
+	memberTestAudit
+	changes []string
+}
+
+func (a *mergeTestAudit) LogOrganizationStructureChange(ctx context.Context, userID, action, orgID, resourceType, resourceID string, oldValues, newValues map[string]interface{}, success bool, message string) {
+	a.changes = append(a.changes, action+" "+resourceID)
+}
+
+// newMergeTestRepo holds ORG1 with child ORG3, and the unrelated ORG2
+func newMergeTestRepo() *mergeTestOrgRepo {
+	repo := newHardDeleteTestRepo()
+	for _, id := range []string{"ORG2", "ORG3"} {
+		org := models.NewOrganization("Org "+id, "", models.OrgTypeFPO)
+		org.ID = id
+		repo.orgs[id] = org
+	}
+	parentID := "ORG1"
+	repo.orgs["ORG3"].ParentID = &parentID
+	repo.children["ORG1"] = []*models.Organization{repo.orgs["ORG3"]}
+	return &mergeTestOrgRepo{hardDeleteTestOrgRepo: repo}
+}
+
+func newMergeTestService(repo *mergeTestOrgRepo, audit *mergeTestAudit) *Service {
+	return NewOrganizationService(
+		repo,
+		&memberTestUserRepo{},
+		nil,
+		nil,
+		utils.NewValidator(),
+		&hardDeleteTestCache{settingTestCache{entries: map[string]interface{}{}}},
+		audit,
+		zap.NewNop(),
+	)
+}
+
+func TestMergeOrganizations(t *testing.T) {
+	repo := newMergeTestRepo()
+	audit := &mergeTestAudit{}
+	service := newMergeTestService(repo, audit)
+
+	result, err := service.MergeOrganizations(context.Background(), "ORG1", "ORG2", "ADMIN1")
+	require.NoError(t, err)
+
+	assert.Equal(t, [][2]string{{"ORG1", "ORG2"}}, repo.merged)
+	assert.Equal(t, "ORG2", result.Organization.ID)
+	assert.Equal(t, "Field Team (Kisan FPO)", result.Summary.RenamedGroups["GRP2"])
+	assert.Equal(t, []string{
+		models.AuditActionMergeOrganization + " ORG1",
+		models.AuditActionChangeOrganizationHierarchy + " ORG3",
+		models.AuditActionMergeOrganization + " GRP1",
+		models.AuditActionMergeOrganization + " GRP2",
+	}, audit.changes, "the merge, each reparented child and each moved group are audited")
+}
+
+func TestMergeOrganizations_Rejected(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		source   string
+		target   string
+		mergedBy string
+		check    func(error) bool
+	}{
+		{"into itself", "ORG1", "ORG1", "ADMIN1", errors.IsValidationError},
+		{"without a merging user", "ORG1", "ORG2", "", errors.IsValidationError},
+		{"unknown target", "ORG1", "ORG9", "ADMIN1", errors.IsNotFoundError},
+		{"into a descendant", "ORG1", "ORG3", "ADMIN1", errors.IsValidationError},
+		{"into an ancestor", "ORG3", "ORG1", "ADMIN1", errors.IsValidationError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMergeTestRepo()
+			service := newMergeTestService(repo, &mergeTestAudit{})
+
+			_, err := service.MergeOrganizations(ctx, tt.source, tt.target, tt.mergedBy)
+			assert.True(t, tt.check(err), "got %v", err)
+			assert.Empty(t, repo.merged)
+		})
+	}
+
+	t.Run("deleted target", func(t *testing.T) {
+		repo := newMergeTestRepo()
+		service := newMergeTestService(repo, &mergeTestAudit{})
+		deletedAt := repo.orgs["ORG2"].CreatedAt
+		repo.orgs["ORG2"].DeletedAt = &deletedAt
+
+		_, err := service.MergeOrganizations(ctx, "ORG1", "ORG2", "ADMIN1")
+		assert.True(t, errors.IsNotFoundError(err))
+	})
+
+	t.Run("hierarchy too deep", func(t *testing.T) {
+		repo := newMergeTestRepo()
+		repo.mergeErr = orgRepo.ErrOrganizationMergeTooDeep
+		audit := &mergeTestAudit{}
+		service := newMergeTestService(repo, audit)
+
+		_, err := service.MergeOrganizations(ctx, "ORG1", "ORG2", "ADMIN1")
+		assert.True(t, errors.IsConflictError(err))
+		assert.Equal(t, []bool{false}, audit.success, "the failed merge is audited")
+		assert.Empty(t, audit.changes)
+	})
+}
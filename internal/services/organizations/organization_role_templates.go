@@ -0,0 +1,244 @@
+package organizations
+
+import (
+	"context"
+	stdErrors "errors"
+	"fmt"
+	"sort"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/requests/organizations"
+	organizationResponses "github.com/Kisanlink/aaa-service/v2/internal/entities/responses/organizations"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	roleRepo "github.com/Kisanlink/aaa-service/v2/internal/repositories/roles"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// SetRoleTemplateRepository sets the repository backing admin-defined role templates
+func (s *Service) SetRoleTemplateRepository(templateRepo interfaces.RoleTemplateRepository) {
+	s.roleTemplateRepo = templateRepo
+}
+
+// ListRoleTemplates returns the built-in role templates followed by the admin-defined ones
+func (s *Service) ListRoleTemplates(ctx context.Context) (*organizationResponses.RoleTemplatesResponse, error) {
+	builtinNames := make([]string, 0, len(models.BuiltinRoleTemplates))
+	for name := range models.BuiltinRoleTemplates {
+		builtinNames = append(builtinNames, name)
+	}
+	sort.Strings(builtinNames)
+
+	response := &organizationResponses.RoleTemplatesResponse{}
+	for _, name := range builtinNames {
+		response.Templates = append(response.Templates, roleTemplateResponse(models.BuiltinRoleTemplates[name]))
+	}
+
+	if s.roleTemplateRepo != nil {
+		stored, err := s.roleTemplateRepo.List(ctx)
+		if err != nil {
+			s.logger.Error("Failed to list role templates", zap.Error(err))
+			return nil, errors.NewInternalError(err)
+		}
+		for _, template := range stored {
+			response.Templates = append(response.Templates, roleTemplateResponse(template.Definition()))
+		}
+	}
+
+	return response, nil
+}
+
+// CreateRoleTemplate stores an admin-defined role template. Names of built-in templates are reserved.
+func (s *Service) CreateRoleTemplate(ctx context.Context, req *organizations.CreateRoleTemplateRequest, createdBy string) (*organizationResponses.RoleTemplateResponse, error) {
+	if s.roleTemplateRepo == nil {
+		return nil, errors.NewInternalError(fmt.Errorf("role template repository not configured"))
+	}
+	if err := s.validator.ValidateStruct(req); err != nil {
+		return nil, errors.NewValidationError("invalid role template", err.Error())
+	}
+	if _, ok := models.BuiltinRoleTemplates[req.Name]; ok {
+		return nil, errors.NewConflictError(fmt.Sprintf("role template %s is built in", req.Name))
+	}
+
+	roles := make(models.RoleTemplateRoles, 0, len(req.Roles))
+	for _, role := range req.Roles {
+		roles = append(roles, models.RoleTemplateRole{
+			Name:        role.Name,
+			Description: role.Description,
+			Permissions: role.Permissions,
+		})
+	}
+	template := models.NewRoleTemplate(req.Name, req.Description, roles)
+	if err := template.Definition().Validate(); err != nil {
+		return nil, errors.NewValidationError("invalid role template", err.Error())
+	}
+
+	existing, err := s.roleTemplateRepo.GetByName(ctx, req.Name)
+	if err != nil {
+		return nil, errors.NewInternalError(err)
+	}
+	if existing != nil {
+		return nil, errors.NewConflictError(fmt.Sprintf("role template %s already exists", req.Name))
+	}
+
+	template.CreatedBy = createdBy
+	template.UpdatedBy = createdBy
+	if err := s.roleTemplateRepo.Create(ctx, template); err != nil {
+		s.logger.Error("Failed to create role template", zap.String("name", req.Name), zap.Error(err))
+		return nil, errors.NewInternalError(err)
+	}
+
+	s.logger.Info("Role template created",
+		zap.String("name", template.Name),
+		zap.String("created_by", createdBy))
+
+	return roleTemplateResponse(template.Definition()), nil
+}
+
+// DeleteRoleTemplate removes an admin-defined role template. Roles created from it are kept.
+func (s *Service) DeleteRoleTemplate(ctx context.Context, name string, deletedBy string) error {
+	if s.roleTemplateRepo == nil {
+		return errors.NewInternalError(fmt.Errorf("role template repository not configured"))
+	}
+	if _, ok := models.BuiltinRoleTemplates[name]; ok {
+		return errors.NewValidationError(fmt.Sprintf("role template %s is built in and cannot be deleted", name))
+	}
+
+	template, err := s.roleTemplateRepo.GetByName(ctx, name)
+	if err != nil {
+		return errors.NewInternalError(err)
+	}
+	if template == nil {
+		return errors.NewNotFoundError("role template not found")
+	}
+
+	if err := s.roleTemplateRepo.Delete(ctx, template); err != nil {
+		s.logger.Error("Failed to delete role template", zap.String("name", name), zap.Error(err))
+		return errors.NewInternalError(err)
+	}
+
+	s.logger.Info("Role template deleted",
+		zap.String("name", name),
+		zap.String("deleted_by", deletedBy))
+	return nil
+}
+
+// ApplyRoleTemplate instantiates a role template as organization-scoped roles with their
+// permissions, in one transaction. Roles the organization already has are skipped, so applying
+// the same template again only fills in what is missing.
+func (s *Service) ApplyRoleTemplate(ctx context.Context, orgID, templateName, appliedBy string) (*organizationResponses.ApplyRoleTemplateResponse, error) {
+	s.logger.Info("Applying role template",
+		zap.String("org_id", orgID),
+		zap.String("template", templateName),
+		zap.String("applied_by", appliedBy))
+
+	if s.roleTemplateRepo == nil {
+		return nil, errors.NewInternalError(fmt.Errorf("role template repository not configured"))
+	}
+
+	template, err := s.resolveRoleTemplate(ctx, templateName)
+	if err != nil {
+		return nil, err
+	}
+
+	org, err := s.orgRepo.GetByID(ctx, orgID)
+	if err != nil || org == nil {
+		s.logger.Error("Organization not found", zap.String("org_id", orgID))
+		return nil, errors.NewNotFoundError("organization not found")
+	}
+	if !org.IsActive {
+		return nil, errors.NewValidationError("role templates cannot be applied to an inactive organization")
+	}
+
+	result, err := s.roleTemplateRepo.ApplyToOrganization(ctx, orgID, template, appliedBy)
+	if err != nil {
+		s.logger.Error("Failed to apply role template",
+			zap.String("org_id", orgID),
+			zap.String("template", templateName),
+			zap.Error(err))
+		s.auditService.LogOrganizationOperation(ctx, appliedBy, models.AuditActionApplyRoleTemplate, orgID, "Failed to apply role template", false, map[string]interface{}{
+			"template": templateName,
+			"error":    err.Error(),
+		})
+		if stdErrors.Is(err, roleRepo.ErrRoleTemplatePermissionsMissing) {
+			return nil, errors.NewValidationError("role template cannot be applied", err.Error())
+		}
+		return nil, errors.NewInternalError(err)
+	}
+
+	permissionCounts := make(map[string]int, len(template.Roles))
+	for _, role := range template.Roles {
+		permissionCounts[role.Name] = len(role.Permissions)
+	}
+
+	response := &organizationResponses.ApplyRoleTemplateResponse{
+		OrganizationID: orgID,
+		Template:       template.Name,
+		CreatedRoles:   make([]*organizationResponses.AppliedTemplateRoleResponse, 0, len(result.CreatedRoles)),
+		SkippedRoles:   result.SkippedRoles,
+	}
+	createdNames := make([]string, 0, len(result.CreatedRoles))
+	for _, role := range result.CreatedRoles {
+		response.CreatedRoles = append(response.CreatedRoles, &organizationResponses.AppliedTemplateRoleResponse{
+			ID:          role.ID,
+			Name:        role.Name,
+			Permissions: permissionCounts[role.Name],
+		})
+		createdNames = append(createdNames, role.Name)
+	}
+	if response.SkippedRoles == nil {
+		response.SkippedRoles = []string{}
+	}
+
+	s.auditService.LogOrganizationOperation(ctx, appliedBy, models.AuditActionApplyRoleTemplate, orgID, "Role template applied successfully", true, map[string]interface{}{
+		"template":          template.Name,
+		"created_roles":     createdNames,
+		"skipped_roles":     response.SkippedRoles,
+		"organization_name": org.Name,
+	})
+
+	s.logger.Info("Role template applied",
+		zap.String("org_id", orgID),
+		zap.String("template", template.Name),
+		zap.Int("created", len(response.CreatedRoles)),
+		zap.Int("skipped", len(response.SkippedRoles)))
+
+	return response, nil
+}
+
+// resolveRoleTemplate looks a template up among the built-in templates first, then the stored ones
+func (s *Service) resolveRoleTemplate(ctx context.Context, name string) (models.RoleTemplateDefinition, error) {
+	if template, ok := models.BuiltinRoleTemplates[name]; ok {
+		return template, nil
+	}
+
+	stored, err := s.roleTemplateRepo.GetByName(ctx, name)
+	if err != nil {
+		return models.RoleTemplateDefinition{}, errors.NewInternalError(err)
+	}
+	if stored == nil {
+		return models.RoleTemplateDefinition{}, errors.NewNotFoundError(fmt.Sprintf("role template %s not found", name))
+	}
+
+	template := stored.Definition()
+	if err := template.Validate(); err != nil {
+		return models.RoleTemplateDefinition{}, errors.NewValidationError("role template cannot be applied", err.Error())
+	}
+	return template, nil
+}
+
+func roleTemplateResponse(template models.RoleTemplateDefinition) *organizationResponses.RoleTemplateResponse {
+	response := &organizationResponses.RoleTemplateResponse{
+		Name:        template.Name,
+		Description: template.Description,
+		IsBuiltin:   template.IsBuiltin,
+		Roles:       make([]*organizationResponses.RoleTemplateRoleResponse, 0, len(template.Roles)),
+	}
+	for _, role := range template.Roles {
+		response.Roles = append(response.Roles, &organizationResponses.RoleTemplateRoleResponse{
+			Name:        role.Name,
+			Description: role.Description,
+			Permissions: role.Permissions,
+		})
+	}
+	return response
+}
@@ -0,0 +1,201 @@
+package organizations
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/requests/organizations"
+	roleRepo "github.com/Kisanlink/aaa-service/v2/internal/repositories/roles"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/Kisanlink/aaa-service/v2/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// templateTestRepo mimics the transactional apply: either every missing role is created or nothing is
+type templateTestRepo struct {
+	templates   map[string]*models.RoleTemplate
+	permissions map[string]bool
+	orgRoles    map[string]map[string]bool
+}
+
+func newTemplateTestRepo() *templateTestRepo {
+	permissions := map[string]bool{}
+	for _, template := range models.BuiltinRoleTemplates {
+		for _, name := range template.PermissionNames() {
+			permissions[name] = true
+		}
+	}
+	return &templateTestRepo{
+		templates:   map[string]*models.RoleTemplate{},
+		permissions: permissions,
+		orgRoles:    map[string]map[string]bool{},
+	}
+}
+
+func (r *templateTestRepo) List(ctx context.Context) ([]*models.RoleTemplate, error) {
+	var templates []*models.RoleTemplate
+	for _, template := range r.templates {
+		templates = append(templates, template)
+	}
+	return templates, nil
+}
+
+func (r *templateTestRepo) GetByName(ctx context.Context, name string) (*models.RoleTemplate, error) {
+	return r.templates[name], nil
+}
+
+func (r *templateTestRepo) Create(ctx context.Context, template *models.RoleTemplate) error {
+	r.templates[template.Name] = template
+	return nil
+}
+
+func (r *templateTestRepo) Delete(ctx context.Context, template *models.RoleTemplate) error {
+	delete(r.templates, template.Name)
+	return nil
+}
+
+func (r *templateTestRepo) ApplyToOrganization(ctx context.Context, orgID string, template models.RoleTemplateDefinition, appliedBy string) (*models.RoleTemplateApplication, error) {
+	var missing []string
+	for _, name := range template.PermissionNames() {
+		if !r.permissions[name] {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%w: %s", roleRepo.ErrRoleTemplatePermissionsMissing, strings.Join(missing, ", "))
+	}
+
+	if r.orgRoles[orgID] == nil {
+		r.orgRoles[orgID] = map[string]bool{}
+	}
+	result := &models.RoleTemplateApplication{}
+	for _, role := range template.Roles {
+		if r.orgRoles[orgID][role.Name] {
+			result.SkippedRoles = append(result.SkippedRoles, role.Name)
+			continue
+		}
+		r.orgRoles[orgID][role.Name] = true
+		result.CreatedRoles = append(result.CreatedRoles, models.NewOrgRole(role.Name, role.Description, orgID))
+	}
+	return result, nil
+}
+
+func newTemplateTestService(templateRepo *templateTestRepo, audit *memberTestAudit) *Service {
+	active := models.NewOrganization("Kisan FPO", "", models.OrgTypeFPO)
+	active.IsActive = true
+	inactive := models.NewOrganization("Dormant FPO", "", models.OrgTypeFPO)
+	inactive.IsActive = false
+
+	service := NewOrganizationService(
+		&memberTestOrgRepo{orgs: map[string]*models.Organization{"ORG1": active, "ORG2": inactive}},
+		&memberTestUserRepo{},
+		nil,
+		nil,
+		utils.NewValidator(),
+		nil,
+		audit,
+		zap.NewNop(),
+	)
+	service.SetRoleTemplateRepository(templateRepo)
+	return service
+}
+
+func TestService_ApplyRoleTemplate_Idempotent(t *testing.T) {
+	audit := &memberTestAudit{}
+	service := newTemplateTestService(newTemplateTestRepo(), audit)
+	ctx := context.Background()
+
+	first, err := service.ApplyRoleTemplate(ctx, "ORG1", models.RoleTemplateFarmManager, "ADMIN1")
+	require.NoError(t, err)
+	require.Len(t, first.CreatedRoles, 1)
+	assert.Equal(t, models.RoleTemplateFarmManager, first.CreatedRoles[0].Name)
+	assert.Equal(t, len(models.BuiltinRoleTemplates[models.RoleTemplateFarmManager].Roles[0].Permissions), first.CreatedRoles[0].Permissions)
+	assert.Empty(t, first.SkippedRoles)
+
+	second, err := service.ApplyRoleTemplate(ctx, "ORG1", models.RoleTemplateFarmManager, "ADMIN1")
+	require.NoError(t, err)
+	assert.Empty(t, second.CreatedRoles)
+	assert.Equal(t, []string{models.RoleTemplateFarmManager}, second.SkippedRoles)
+
+	assert.Equal(t, []string{models.AuditActionApplyRoleTemplate, models.AuditActionApplyRoleTemplate}, audit.actions)
+	assert.Equal(t, []bool{true, true}, audit.success)
+}
+
+func TestService_ApplyRoleTemplate_StoredTemplate(t *testing.T) {
+	templateRepo := newTemplateTestRepo()
+	service := newTemplateTestService(templateRepo, &memberTestAudit{})
+	ctx := context.Background()
+
+	_, err := service.CreateRoleTemplate(ctx, &organizations.CreateRoleTemplateRequest{
+		Name: "collection_center",
+		Roles: []organizations.RoleTemplateRoleRequest{
+			{Name: "collector", Permissions: []string{"farmer:read", "farm:read"}},
+			{Name: "supervisor", Permissions: []string{"farmer:read", "farmer:update"}},
+		},
+	}, "ADMIN1")
+	require.NoError(t, err)
+
+	applied, err := service.ApplyRoleTemplate(ctx, "ORG1", "collection_center", "ADMIN1")
+	require.NoError(t, err)
+	assert.Len(t, applied.CreatedRoles, 2)
+
+	templates, err := service.ListRoleTemplates(ctx)
+	require.NoError(t, err)
+	assert.Len(t, templates.Templates, len(models.BuiltinRoleTemplates)+1)
+}
+
+func TestService_ApplyRoleTemplate_Rejected(t *testing.T) {
+	templateRepo := newTemplateTestRepo()
+	templateRepo.templates["broken"] = models.NewRoleTemplate("broken", "", models.RoleTemplateRoles{
+		{Name: "auditor", Permissions: []string{"ledger:read"}},
+	})
+
+	tests := []struct {
+		name     string
+		orgID    string
+		template string
+		check    func(error) bool
+	}{
+		{name: "unknown template", orgID: "ORG1", template: "nope", check: errors.IsNotFoundError},
+		{name: "unknown organization", orgID: "ORG404", template: models.RoleTemplateFieldAgent, check: errors.IsNotFoundError},
+		{name: "inactive organization", orgID: "ORG2", template: models.RoleTemplateFieldAgent, check: errors.IsValidationError},
+		{name: "missing permissions", orgID: "ORG1", template: "broken", check: errors.IsValidationError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newTemplateTestService(templateRepo, &memberTestAudit{})
+			_, err := service.ApplyRoleTemplate(context.Background(), tt.orgID, tt.template, "ADMIN1")
+			require.Error(t, err)
+			assert.True(t, tt.check(err), "unexpected error: %v", err)
+		})
+	}
+}
+
+func TestService_CreateRoleTemplate_Rejected(t *testing.T) {
+	service := newTemplateTestService(newTemplateTestRepo(), &memberTestAudit{})
+	ctx := context.Background()
+
+	_, err := service.CreateRoleTemplate(ctx, &organizations.CreateRoleTemplateRequest{
+		Name:  models.RoleTemplateFarmManager,
+		Roles: []organizations.RoleTemplateRoleRequest{{Name: "manager", Permissions: []string{"farm:read"}}},
+	}, "ADMIN1")
+	assert.True(t, errors.IsConflictError(err), "built-in names are reserved: %v", err)
+
+	_, err = service.CreateRoleTemplate(ctx, &organizations.CreateRoleTemplateRequest{
+		Name: "duplicated_roles",
+		Roles: []organizations.RoleTemplateRoleRequest{
+			{Name: "manager", Permissions: []string{"farm:read"}},
+			{Name: "manager", Permissions: []string{"farm:list"}},
+		},
+	}, "ADMIN1")
+	assert.True(t, errors.IsValidationError(err), "role names must be unique: %v", err)
+
+	err = service.DeleteRoleTemplate(ctx, models.RoleTemplateFieldAgent, "ADMIN1")
+	assert.True(t, errors.IsValidationError(err), "built-in templates cannot be deleted: %v", err)
+}
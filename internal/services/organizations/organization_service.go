@@ -10,8 +10,10 @@ import (
 	groupResponses "github.com/Kisanlink/aaa-service/v2/internal/entities/responses/groups"
 	organizationResponses "github.com/Kisanlink/aaa-service/v2/internal/entities/responses/organizations"
 	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/internal/utils/actor"
 	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
 	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
 )
 
 // Service handles business logic for organization operations
@@ -25,6 +27,24 @@ type Service struct {
 	orgCache     *OrganizationCacheService
 	auditService interfaces.AuditService
 	logger       *zap.Logger
+
+	memberRepo          interfaces.OrganizationMemberRepository
+	memberRemovalPolicy MemberRemovalPolicy
+	settingRepo         interfaces.OrganizationSettingRepository
+	attributeRepo       interfaces.OrganizationUserAttributeRepository
+	roleTemplateRepo    interfaces.RoleTemplateRepository
+	roleRepo            interfaces.OrganizationRoleRepository
+	groupTreeRepo       interfaces.GroupTreeRepository
+
+	// auditRepo and auditRetentionDays enforce the audit retention policy on hard deletes
+	auditRepo          interfaces.AuditRepository
+	auditRetentionDays int
+
+	// hierarchyLoads shares one hierarchy load between concurrent cache misses for the same organization
+	hierarchyLoads singleflight.Group
+
+	// maxHierarchyDepth bounds the levels of the organization hierarchy; 0 disables the limit
+	maxHierarchyDepth int
 }
 
 // NewOrganizationService creates a new organization service instance
@@ -85,6 +105,9 @@ func (s *Service) CreateOrganization(ctx context.Context, req *organizations.Cre
 			s.logger.Warn("Parent organization is inactive", zap.String("parent_id", *req.ParentID))
 			return nil, errors.NewValidationError("parent organization is inactive")
 		}
+		if err := s.checkHierarchyDepth(ctx, "", *req.ParentID); err != nil {
+			return nil, err
+		}
 	}
 
 	// Validate organization type if provided
@@ -93,11 +116,15 @@ func (s *Service) CreateOrganization(ctx context.Context, req *organizations.Cre
 		return nil, errors.NewValidationError("invalid organization type")
 	}
 
+	// Near-duplicate names are allowed but flagged to the caller
+	s.warnSimilarNames(ctx, req.Name)
+
 	// Create organization model
 	org := models.NewOrganization(req.Name, req.Description, req.Type)
 	if req.ParentID != nil && *req.ParentID != "" {
 		org.ParentID = req.ParentID
 	}
+	actor.StampCreate(ctx, org)
 
 	// Save organization to repository
 	err = s.orgRepo.Create(ctx, org)
@@ -110,7 +137,7 @@ func (s *Service) CreateOrganization(ctx context.Context, req *organizations.Cre
 			"parent_id":         req.ParentID,
 			"error":             err.Error(),
 		}
-		s.auditService.LogOrganizationOperation(ctx, "system", models.AuditActionCreateOrganization, "", "Failed to create organization", false, auditDetails)
+		s.auditService.LogOrganizationOperation(ctx, actor.OrSystem(ctx), models.AuditActionCreateOrganization, "", "Failed to create organization", false, auditDetails)
 
 		if strings.Contains(err.Error(), "duplicate key") ||
 			strings.Contains(err.Error(), "unique constraint") {
@@ -125,7 +152,12 @@ func (s *Service) CreateOrganization(ctx context.Context, req *organizations.Cre
 		"parent_id":         org.ParentID,
 		"is_active":         org.IsActive,
 	}
-	s.auditService.LogOrganizationOperation(ctx, "system", models.AuditActionCreateOrganization, org.ID, "Organization created successfully", true, auditDetails)
+	s.auditService.LogOrganizationOperation(ctx, actor.OrSystem(ctx), models.AuditActionCreateOrganization, org.ID, "Organization created successfully", true, auditDetails)
+
+	// The new organization joins the descendants of its parent chain
+	if org.ParentID != nil {
+		s.invalidateLineage(ctx, org.ID, *org.ParentID)
+	}
 
 	s.logger.Info("Organization created successfully",
 		zap.String("org_id", org.ID),
@@ -213,15 +245,25 @@ func (s *Service) UpdateOrganization(ctx context.Context, orgID string, req *org
 				s.logger.Warn("Circular reference detected", zap.Error(err))
 				return nil, errors.NewValidationError("circular reference detected in organization hierarchy")
 			}
+			if err := s.checkHierarchyDepth(ctx, orgID, *req.ParentID); err != nil {
+				return nil, err
+			}
 		}
 	}
 
-	// Validate organization type if being changed
+	// Validate organization type and the change of type if being changed
 	if req.Type != nil && *req.Type != org.Type {
 		if !models.ValidOrganizationType(*req.Type) {
 			s.logger.Warn("Invalid organization type", zap.String("type", *req.Type))
 			return nil, errors.NewValidationError("invalid organization type")
 		}
+		if !models.CanChangeOrganizationType(org.Type, *req.Type) {
+			s.logger.Warn("Organization type change not allowed",
+				zap.String("org_id", orgID),
+				zap.String("from", org.Type),
+				zap.String("to", *req.Type))
+			return nil, errors.NewValidationError(fmt.Sprintf("organization type cannot change from %q to %q", org.Type, *req.Type))
+		}
 	}
 
 	// Capture old values for audit logging
@@ -274,6 +316,7 @@ func (s *Service) UpdateOrganization(ctx context.Context, orgID string, req *org
 	}
 
 	// Save changes
+	actor.StampUpdate(ctx, org)
 	err = s.orgRepo.Update(ctx, org)
 	if err != nil {
 		s.logger.Error("Failed to update organization", zap.Error(err))
@@ -284,7 +327,7 @@ func (s *Service) UpdateOrganization(ctx context.Context, orgID string, req *org
 			"new_values": newValues,
 			"error":      err.Error(),
 		}
-		s.auditService.LogOrganizationOperation(ctx, "system", models.AuditActionUpdateOrganization, orgID, "Failed to update organization", false, auditDetails)
+		s.auditService.LogOrganizationOperation(ctx, actor.OrSystem(ctx), models.AuditActionUpdateOrganization, orgID, "Failed to update organization", false, auditDetails)
 
 		return nil, errors.NewInternalError(err)
 	}
@@ -294,11 +337,11 @@ func (s *Service) UpdateOrganization(ctx context.Context, orgID string, req *org
 		"old_values": oldValues,
 		"new_values": newValues,
 	}
-	s.auditService.LogOrganizationOperation(ctx, "system", models.AuditActionUpdateOrganization, orgID, "Organization updated successfully", true, auditDetails)
+	s.auditService.LogOrganizationOperation(ctx, actor.OrSystem(ctx), models.AuditActionUpdateOrganization, orgID, "Organization updated successfully", true, auditDetails)
 
 	// Log hierarchy change separately if it occurred with comprehensive structure change logging
 	if hierarchyChanged {
-		s.auditService.LogHierarchyChange(ctx, "system", models.AuditActionChangeOrganizationHierarchy, models.ResourceTypeOrganization, orgID, oldParentID, newParentID, "Organization hierarchy changed", true, auditDetails)
+		s.auditService.LogHierarchyChange(ctx, actor.OrSystem(ctx), models.AuditActionChangeOrganizationHierarchy, models.ResourceTypeOrganization, orgID, oldParentID, newParentID, "Organization hierarchy changed", true, auditDetails)
 
 		// Also log comprehensive structure change for enhanced audit trail
 		hierarchyOldValues := map[string]interface{}{
@@ -307,11 +350,14 @@ func (s *Service) UpdateOrganization(ctx context.Context, orgID string, req *org
 		hierarchyNewValues := map[string]interface{}{
 			"parent_id": newParentID,
 		}
-		s.auditService.LogOrganizationStructureChange(ctx, "system", models.AuditActionChangeOrganizationHierarchy, orgID, models.ResourceTypeOrganization, orgID, hierarchyOldValues, hierarchyNewValues, true, "Organization hierarchy structure changed")
+		s.auditService.LogOrganizationStructureChange(ctx, actor.OrSystem(ctx), models.AuditActionChangeOrganizationHierarchy, orgID, models.ResourceTypeOrganization, orgID, hierarchyOldValues, hierarchyNewValues, true, "Organization hierarchy structure changed")
 	}
 
 	// Invalidate cache after successful update
 	s.orgCache.InvalidateOrganizationCache(ctx, orgID)
+	if hierarchyChanged {
+		s.invalidateLineage(ctx, orgID, oldParentID, newParentID)
+	}
 
 	s.logger.Info("Organization updated successfully", zap.String("org_id", orgID))
 
@@ -340,28 +386,8 @@ func (s *Service) DeleteOrganization(ctx context.Context, orgID string, deletedB
 		return errors.NewNotFoundError("organization not found")
 	}
 
-	// Check if organization has children
-	children, err := s.orgRepo.GetChildren(ctx, orgID)
-	if err != nil {
-		s.logger.Error("Failed to check organization children", zap.Error(err))
-		return errors.NewInternalError(err)
-	}
-
-	if len(children) > 0 {
-		s.logger.Warn("Cannot delete organization with children", zap.String("org_id", orgID))
-		return errors.NewValidationError("cannot delete organization with child organizations")
-	}
-
-	// Check if organization has active groups
-	hasGroups, err := s.orgRepo.HasActiveGroups(ctx, orgID)
-	if err != nil {
-		s.logger.Error("Failed to check organization groups", zap.Error(err))
-		return errors.NewInternalError(err)
-	}
-
-	if hasGroups {
-		s.logger.Warn("Cannot delete organization with active groups", zap.String("org_id", orgID))
-		return errors.NewValidationError("cannot delete organization with active groups")
+	if err := s.checkOrganizationDeletable(ctx, orgID); err != nil {
+		return err
 	}
 
 	// Soft delete the organization
@@ -384,15 +410,50 @@ func (s *Service) DeleteOrganization(ctx context.Context, orgID string, deletedB
 	auditDetails := map[string]interface{}{
 		"organization_name": org.Name,
 		"deleted_by":        deletedBy,
-		"had_children":      len(children) > 0,
-		"had_groups":        hasGroups,
 	}
 	s.auditService.LogOrganizationOperation(ctx, deletedBy, models.AuditActionDeleteOrganization, orgID, "Organization deleted successfully", true, auditDetails)
+	s.invalidateLineage(ctx, orgID, parentIDOf(org))
 
 	s.logger.Info("Organization deleted successfully", zap.String("org_id", orgID))
 	return nil
 }
 
+// checkOrganizationDeletable refuses the deletion of an organization that still has child
+// organizations or active groups
+func (s *Service) checkOrganizationDeletable(ctx context.Context, orgID string) error {
+	if err := s.checkNoChildOrganizations(ctx, orgID); err != nil {
+		return err
+	}
+
+	hasGroups, err := s.orgRepo.HasActiveGroups(ctx, orgID)
+	if err != nil {
+		s.logger.Error("Failed to check organization groups", zap.Error(err))
+		return errors.NewInternalError(err)
+	}
+
+	if hasGroups {
+		s.logger.Warn("Cannot delete organization with active groups", zap.String("org_id", orgID))
+		return errors.NewValidationError("cannot delete organization with active groups")
+	}
+	return nil
+}
+
+// checkNoChildOrganizations refuses the deletion of an organization that still has child
+// organizations
+func (s *Service) checkNoChildOrganizations(ctx context.Context, orgID string) error {
+	children, err := s.orgRepo.GetChildren(ctx, orgID)
+	if err != nil {
+		s.logger.Error("Failed to check organization children", zap.Error(err))
+		return errors.NewInternalError(err)
+	}
+
+	if len(children) > 0 {
+		s.logger.Warn("Cannot delete organization with children", zap.String("org_id", orgID))
+		return errors.NewValidationError("cannot delete organization with child organizations")
+	}
+	return nil
+}
+
 // ListOrganizations retrieves organizations with pagination and filtering
 func (s *Service) ListOrganizations(ctx context.Context, limit, offset int, includeInactive bool, orgType string) ([]*organizationResponses.OrganizationResponse, error) {
 	s.logger.Info("Listing organizations",
@@ -461,11 +522,34 @@ func (s *Service) GetOrganizationHierarchy(ctx context.Context, orgID string) (*
 		s.logger.Debug("Returning cached organization hierarchy", zap.String("org_id", orgID))
 		return cached, nil
 	}
+	if s.orgCache.IsOrganizationNotFoundCached(ctx, orgID) {
+		return nil, errors.NewNotFoundError("organization not found")
+	}
 
+	// Concurrent misses wait for a single load. It runs detached from the first caller's
+	// cancellation since every waiting caller depends on it.
+	result, err, _ := s.hierarchyLoads.Do(orgID, func() (interface{}, error) {
+		loadCtx := context.WithoutCancel(ctx)
+		if cached, found := s.orgCache.GetCachedOrganizationHierarchy(loadCtx, orgID); found {
+			return cached, nil
+		}
+		return s.loadOrganizationHierarchy(loadCtx, orgID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*organizationResponses.OrganizationHierarchyResponse), nil
+}
+
+// loadOrganizationHierarchy builds the hierarchy from the database and caches it
+func (s *Service) loadOrganizationHierarchy(ctx context.Context, orgID string) (*organizationResponses.OrganizationHierarchyResponse, error) {
 	// Get the organization
 	org, err := s.orgRepo.GetByID(ctx, orgID)
 	if err != nil || org == nil {
 		s.logger.Error("Organization not found", zap.String("org_id", orgID))
+		if org == nil && (err == nil || strings.Contains(err.Error(), "not found")) {
+			s.orgCache.CacheOrganizationNotFound(ctx, orgID)
+		}
 		return nil, errors.NewNotFoundError("organization not found")
 	}
 
@@ -498,7 +582,7 @@ func (s *Service) GetOrganizationHierarchy(ctx context.Context, orgID string) (*
 	}
 
 	// Get group hierarchy for the organization
-	groupHierarchy, err := s.buildGroupHierarchy(ctx, orgID)
+	groupHierarchy, groupsTruncated, err := s.buildGroupHierarchy(ctx, orgID)
 	if err != nil {
 		s.logger.Error("Failed to build group hierarchy", zap.Error(err))
 		return nil, errors.NewInternalError(err)
@@ -519,6 +603,8 @@ func (s *Service) GetOrganizationHierarchy(ctx context.Context, orgID string) (*
 		Parents:  make([]*organizationResponses.OrganizationResponse, len(parents)),
 		Children: make([]*organizationResponses.OrganizationResponse, len(children)),
 		Groups:   groupHierarchy,
+
+		GroupsTruncated: groupsTruncated,
 	}
 
 	// Convert parents
@@ -584,6 +670,7 @@ func (s *Service) ActivateOrganization(ctx context.Context, orgID string) error
 	}
 
 	org.IsActive = true
+	actor.StampUpdate(ctx, org)
 	err = s.orgRepo.Update(ctx, org)
 	if err != nil {
 		s.logger.Error("Failed to activate organization", zap.Error(err))
@@ -593,7 +680,7 @@ func (s *Service) ActivateOrganization(ctx context.Context, orgID string) error
 			"organization_name": org.Name,
 			"error":             err.Error(),
 		}
-		s.auditService.LogOrganizationOperation(ctx, "system", models.AuditActionActivateOrganization, orgID, "Failed to activate organization", false, auditDetails)
+		s.auditService.LogOrganizationOperation(ctx, actor.OrSystem(ctx), models.AuditActionActivateOrganization, orgID, "Failed to activate organization", false, auditDetails)
 
 		return errors.NewInternalError(err)
 	}
@@ -604,7 +691,7 @@ func (s *Service) ActivateOrganization(ctx context.Context, orgID string) error
 		"previous_status":   "inactive",
 		"new_status":        "active",
 	}
-	s.auditService.LogOrganizationOperation(ctx, "system", models.AuditActionActivateOrganization, orgID, "Organization activated successfully", true, auditDetails)
+	s.auditService.LogOrganizationOperation(ctx, actor.OrSystem(ctx), models.AuditActionActivateOrganization, orgID, "Organization activated successfully", true, auditDetails)
 
 	s.logger.Info("Organization activated successfully", zap.String("org_id", orgID))
 	return nil
@@ -638,6 +725,7 @@ func (s *Service) DeactivateOrganization(ctx context.Context, orgID string) erro
 	}
 
 	org.IsActive = false
+	actor.StampUpdate(ctx, org)
 	err = s.orgRepo.Update(ctx, org)
 	if err != nil {
 		s.logger.Error("Failed to deactivate organization", zap.Error(err))
@@ -648,7 +736,7 @@ func (s *Service) DeactivateOrganization(ctx context.Context, orgID string) erro
 			"active_children":   len(children),
 			"error":             err.Error(),
 		}
-		s.auditService.LogOrganizationOperation(ctx, "system", models.AuditActionDeactivateOrganization, orgID, "Failed to deactivate organization", false, auditDetails)
+		s.auditService.LogOrganizationOperation(ctx, actor.OrSystem(ctx), models.AuditActionDeactivateOrganization, orgID, "Failed to deactivate organization", false, auditDetails)
 
 		return errors.NewInternalError(err)
 	}
@@ -660,7 +748,7 @@ func (s *Service) DeactivateOrganization(ctx context.Context, orgID string) erro
 		"new_status":        "inactive",
 		"active_children":   len(children),
 	}
-	s.auditService.LogOrganizationOperation(ctx, "system", models.AuditActionDeactivateOrganization, orgID, "Organization deactivated successfully", true, auditDetails)
+	s.auditService.LogOrganizationOperation(ctx, actor.OrSystem(ctx), models.AuditActionDeactivateOrganization, orgID, "Organization deactivated successfully", true, auditDetails)
 
 	s.logger.Info("Organization deactivated successfully", zap.String("org_id", orgID))
 	return nil
@@ -1166,20 +1254,30 @@ func (s *Service) GetGroupRolesInOrganization(ctx context.Context, orgID, groupI
 	return result, nil
 }
 
-// buildGroupHierarchy builds the complete group hierarchy for an organization with role assignments
-func (s *Service) buildGroupHierarchy(ctx context.Context, orgID string) ([]*organizationResponses.GroupHierarchyNode, error) {
+// buildGroupHierarchy builds the complete group hierarchy for an organization with role assignments.
+// Organizations with more than MaxGroupHierarchyGroups active groups get only their first root
+// groups instead, and truncated is set.
+func (s *Service) buildGroupHierarchy(ctx context.Context, orgID string) (nodes []*organizationResponses.GroupHierarchyNode, truncated bool, err error) {
 	s.logger.Info("Building group hierarchy for organization", zap.String("org_id", orgID))
 
-	// Get all groups in the organization
-	allGroups, err := s.groupRepo.GetByOrganization(ctx, orgID, 1000, 0, false) // Get up to 1000 active groups
+	// Get all groups in the organization, plus one to detect an organization over the limit
+	allGroups, err := s.groupRepo.GetByOrganization(ctx, orgID, MaxGroupHierarchyGroups+1, 0, false)
 	if err != nil {
 		s.logger.Error("Failed to get organization groups", zap.Error(err))
-		return nil, err
+		return nil, false, err
 	}
 
 	if len(allGroups) == 0 {
 		s.logger.Info("No groups found in organization", zap.String("org_id", orgID))
-		return []*organizationResponses.GroupHierarchyNode{}, nil
+		return []*organizationResponses.GroupHierarchyNode{}, false, nil
+	}
+
+	if len(allGroups) > MaxGroupHierarchyGroups {
+		s.logger.Warn("Organization has too many groups for a full hierarchy, returning root groups only",
+			zap.String("org_id", orgID),
+			zap.Int("max_groups", MaxGroupHierarchyGroups))
+		nodes, err = s.truncatedGroupHierarchy(ctx, orgID)
+		return nodes, true, err
 	}
 
 	// Create a map for quick lookup
@@ -1226,12 +1324,16 @@ func (s *Service) buildGroupHierarchy(ctx context.Context, orgID string) ([]*org
 		}
 	}
 
+	for _, node := range nodeMap {
+		node.ChildCount = int64(len(node.Children))
+	}
+
 	s.logger.Info("Group hierarchy built successfully",
 		zap.String("org_id", orgID),
 		zap.Int("total_groups", len(allGroups)),
 		zap.Int("root_groups", len(rootNodes)))
 
-	return rootNodes, nil
+	return rootNodes, false, nil
 }
 
 // createGroupHierarchyNode creates a hierarchy node for a group with its roles
@@ -0,0 +1,209 @@
+package organizations
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/requests/organizations"
+	organizationResponses "github.com/Kisanlink/aaa-service/v2/internal/entities/responses/organizations"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// SetSettingRepository sets the repository backing per-organization settings
+func (s *Service) SetSettingRepository(settingRepo interfaces.OrganizationSettingRepository) {
+	s.settingRepo = settingRepo
+}
+
+// GetOrganizationSettings returns every known setting of an organization. Settings the organization
+// has not set take the default for its organization type.
+func (s *Service) GetOrganizationSettings(ctx context.Context, orgID string) (*organizationResponses.OrganizationSettingsResponse, error) {
+	if s.settingRepo == nil {
+		return nil, errors.NewInternalError(fmt.Errorf("organization setting repository not configured"))
+	}
+
+	if cached, found := s.orgCache.GetCachedOrganizationSettings(ctx, orgID); found {
+		return cached, nil
+	}
+
+	org, err := s.orgRepo.GetByID(ctx, orgID)
+	if err != nil || org == nil {
+		s.logger.Error("Organization not found", zap.String("org_id", orgID))
+		return nil, errors.NewNotFoundError("organization not found")
+	}
+
+	stored, err := s.settingRepo.ListByOrganization(ctx, orgID)
+	if err != nil {
+		s.logger.Error("Failed to load organization settings", zap.String("org_id", orgID), zap.Error(err))
+		return nil, errors.NewInternalError(err)
+	}
+
+	storedByKey := make(map[string]*models.OrganizationSetting, len(stored))
+	for _, setting := range stored {
+		storedByKey[setting.Key] = setting
+	}
+
+	keys := make([]string, 0, len(models.OrganizationSettingDefinitions))
+	for key := range models.OrganizationSettingDefinitions {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	response := &organizationResponses.OrganizationSettingsResponse{
+		OrganizationID: orgID,
+		Settings:       make([]*organizationResponses.OrganizationSettingResponse, 0, len(keys)),
+	}
+	for _, key := range keys {
+		def := models.OrganizationSettingDefinitions[key]
+		setting := &organizationResponses.OrganizationSettingResponse{
+			Key:         key,
+			Value:       def.DefaultFor(org.Type),
+			Type:        string(def.Type),
+			IsDefault:   true,
+			Description: def.Description,
+		}
+
+		if row, ok := storedByKey[key]; ok {
+			value, err := s.decodeStoredSetting(def, row)
+			if err != nil {
+				// A value that no longer validates (e.g. after a definition change) falls back to the default
+				s.logger.Warn("Ignoring invalid stored organization setting",
+					zap.String("org_id", orgID),
+					zap.String("key", key),
+					zap.Error(err))
+			} else {
+				setting.Value = value
+				setting.IsDefault = false
+			}
+		}
+
+		response.Settings = append(response.Settings, setting)
+	}
+
+	s.orgCache.CacheOrganizationSettings(ctx, orgID, response)
+
+	return response, nil
+}
+
+// GetSetting returns the effective value of a single setting: the stored value, or the default for
+// the organization's type when unset
+func (s *Service) GetSetting(ctx context.Context, orgID, key string) (interface{}, error) {
+	if _, ok := models.OrganizationSettingDefinitions[key]; !ok {
+		return nil, errors.NewValidationError(fmt.Sprintf("unknown organization setting %q", key))
+	}
+
+	settings, err := s.GetOrganizationSettings(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, setting := range settings.Settings {
+		if setting.Key == key {
+			return setting.Value, nil
+		}
+	}
+	return nil, errors.NewInternalError(fmt.Errorf("organization setting %q missing from settings", key))
+}
+
+// SetSetting stores the value of a single setting; a nil value resets it to the default
+func (s *Service) SetSetting(ctx context.Context, orgID, key string, value interface{}, updatedBy string) error {
+	_, err := s.UpdateOrganizationSettings(ctx, orgID, &organizations.UpdateOrganizationSettingsRequest{
+		Settings: map[string]interface{}{key: value},
+	}, updatedBy)
+	return err
+}
+
+// UpdateOrganizationSettings validates and stores several settings at once. Every key and value is
+// checked before anything is written; null values reset their key to the default.
+func (s *Service) UpdateOrganizationSettings(ctx context.Context, orgID string, req *organizations.UpdateOrganizationSettingsRequest, updatedBy string) (*organizationResponses.OrganizationSettingsResponse, error) {
+	s.logger.Info("Updating organization settings",
+		zap.String("org_id", orgID),
+		zap.String("updated_by", updatedBy))
+
+	if s.settingRepo == nil {
+		return nil, errors.NewInternalError(fmt.Errorf("organization setting repository not configured"))
+	}
+	if len(req.Settings) == 0 {
+		return nil, errors.NewValidationError("at least one setting is required")
+	}
+
+	normalized := make(map[string]interface{}, len(req.Settings))
+	var validationErrors []string
+	for key, value := range req.Settings {
+		def, ok := models.OrganizationSettingDefinitions[key]
+		if !ok {
+			validationErrors = append(validationErrors, fmt.Sprintf("unknown organization setting %q", key))
+			continue
+		}
+		if value == nil {
+			normalized[key] = nil
+			continue
+		}
+		v, err := def.Normalize(value)
+		if err != nil {
+			validationErrors = append(validationErrors, err.Error())
+			continue
+		}
+		normalized[key] = v
+	}
+	if len(validationErrors) > 0 {
+		sort.Strings(validationErrors)
+		return nil, errors.NewValidationError("invalid organization settings", validationErrors...)
+	}
+
+	org, err := s.orgRepo.GetByID(ctx, orgID)
+	if err != nil || org == nil {
+		s.logger.Error("Organization not found", zap.String("org_id", orgID))
+		return nil, errors.NewNotFoundError("organization not found")
+	}
+
+	for key, value := range normalized {
+		if value == nil {
+			err = s.settingRepo.DeleteByOrganizationAndKey(ctx, orgID, key)
+		} else {
+			var setting *models.OrganizationSetting
+			setting, err = models.NewOrganizationSetting(orgID, key, value)
+			if err == nil {
+				setting.CreatedBy = updatedBy
+				setting.UpdatedBy = updatedBy
+				err = s.settingRepo.Upsert(ctx, setting)
+			}
+		}
+		if err != nil {
+			s.logger.Error("Failed to store organization setting",
+				zap.String("org_id", orgID),
+				zap.String("key", key),
+				zap.Error(err))
+			s.orgCache.InvalidateOrganizationSettings(ctx, orgID)
+			s.auditService.LogOrganizationOperation(ctx, updatedBy, models.AuditActionUpdateOrganizationSettings, orgID, "Failed to update organization settings", false, map[string]interface{}{
+				"key":   key,
+				"error": err.Error(),
+			})
+			return nil, errors.NewInternalError(err)
+		}
+	}
+
+	s.orgCache.InvalidateOrganizationSettings(ctx, orgID)
+
+	s.auditService.LogOrganizationOperation(ctx, updatedBy, models.AuditActionUpdateOrganizationSettings, orgID, "Organization settings updated successfully", true, map[string]interface{}{
+		"settings":          normalized,
+		"organization_name": org.Name,
+	})
+
+	s.logger.Info("Organization settings updated successfully",
+		zap.String("org_id", orgID),
+		zap.Int("count", len(normalized)))
+
+	return s.GetOrganizationSettings(ctx, orgID)
+}
+
+func (s *Service) decodeStoredSetting(def models.OrganizationSettingDefinition, setting *models.OrganizationSetting) (interface{}, error) {
+	value, err := setting.DecodedValue()
+	if err != nil {
+		return nil, err
+	}
+	return def.Normalize(value)
+}
@@ -0,0 +1,192 @@
+package organizations
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/requests/organizations"
+	organizationResponses "github.com/Kisanlink/aaa-service/v2/internal/entities/responses/organizations"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/Kisanlink/aaa-service/v2/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type settingTestRepo struct {
+	settings map[string]*models.OrganizationSetting
+	writes   int
+}
+
+func (r *settingTestRepo) ListByOrganization(ctx context.Context, orgID string) ([]*models.OrganizationSetting, error) {
+	var settings []*models.OrganizationSetting
+	for _, setting := range r.settings {
+		if setting.OrganizationID == orgID {
+			settings = append(settings, setting)
+		}
+	}
+	return settings, nil
+}
+
+func (r *settingTestRepo) Upsert(ctx context.Context, setting *models.OrganizationSetting) error {
+	r.writes++
+	r.settings[setting.OrganizationID+"/"+setting.Key] = setting
+	return nil
+}
+
+func (r *settingTestRepo) DeleteByOrganizationAndKey(ctx context.Context, orgID, key string) error {
+	r.writes++
+	delete(r.settings, orgID+"/"+key)
+	return nil
+}
+
+type settingTestCache struct {
+	interfaces.CacheService
+	entries map[string]interface{}
+}
+
+func (c *settingTestCache) Get(key string) (interface{}, bool) {
+	value, ok := c.entries[key]
+	return value, ok
+}
+
+func (c *settingTestCache) Set(key string, value interface{}, expiration int) error {
+	c.entries[key] = value
+	return nil
+}
+
+func (c *settingTestCache) Delete(key string) error {
+	delete(c.entries, key)
+	return nil
+}
+
+func newSettingTestService(settingRepo *settingTestRepo, cache *settingTestCache, audit *memberTestAudit) *Service {
+	fpo := models.NewOrganization("Kisan FPO", "", models.OrgTypeFPO)
+	company := models.NewOrganization("Agri Co", "", models.OrgTypeAgribusiness)
+
+	service := NewOrganizationService(
+		&memberTestOrgRepo{orgs: map[string]*models.Organization{"ORG1": fpo, "ORG2": company}},
+		&memberTestUserRepo{},
+		nil,
+		nil,
+		utils.NewValidator(),
+		cache,
+		audit,
+		zap.NewNop(),
+	)
+	service.SetSettingRepository(settingRepo)
+	return service
+}
+
+func settingByKey(t *testing.T, settings *organizationResponses.OrganizationSettingsResponse, key string) *organizationResponses.OrganizationSettingResponse {
+	t.Helper()
+	for _, setting := range settings.Settings {
+		if setting.Key == key {
+			return setting
+		}
+	}
+	t.Fatalf("setting %s not returned", key)
+	return nil
+}
+
+func TestService_GetOrganizationSettings_Defaults(t *testing.T) {
+	service := newSettingTestService(&settingTestRepo{settings: map[string]*models.OrganizationSetting{}}, &settingTestCache{entries: map[string]interface{}{}}, &memberTestAudit{})
+
+	fpoSettings, err := service.GetOrganizationSettings(context.Background(), "ORG1")
+	require.NoError(t, err)
+	require.Len(t, fpoSettings.Settings, len(models.OrganizationSettingDefinitions))
+	kyc := settingByKey(t, fpoSettings, models.OrgSettingKYCRequiredBeforeRoleAssignment)
+	assert.Equal(t, true, kyc.Value, "FPOs require KYC by default")
+	assert.True(t, kyc.IsDefault)
+
+	companySettings, err := service.GetOrganizationSettings(context.Background(), "ORG2")
+	require.NoError(t, err)
+	assert.Equal(t, false, settingByKey(t, companySettings, models.OrgSettingKYCRequiredBeforeRoleAssignment).Value)
+	assert.Equal(t, int64(0), settingByKey(t, companySettings, models.OrgSettingMaxMembers).Value)
+	assert.Equal(t, models.OrganizationMemberRoleMember, settingByKey(t, companySettings, models.OrgSettingDefaultMemberRole).Value)
+
+	_, err = service.GetOrganizationSettings(context.Background(), "ORG404")
+	assert.True(t, errors.IsNotFoundError(err))
+}
+
+func TestService_UpdateOrganizationSettings(t *testing.T) {
+	settingRepo := &settingTestRepo{settings: map[string]*models.OrganizationSetting{}}
+	cache := &settingTestCache{entries: map[string]interface{}{}}
+	audit := &memberTestAudit{}
+	service := newSettingTestService(settingRepo, cache, audit)
+	ctx := context.Background()
+
+	// Prime the cache so the update has to invalidate it
+	_, err := service.GetOrganizationSettings(ctx, "ORG1")
+	require.NoError(t, err)
+	require.Contains(t, cache.entries, "org:ORG1:settings")
+
+	updated, err := service.UpdateOrganizationSettings(ctx, "ORG1", &organizations.UpdateOrganizationSettingsRequest{
+		Settings: map[string]interface{}{
+			models.OrgSettingKYCRequiredBeforeRoleAssignment: false,
+			models.OrgSettingMaxMembers:                      float64(250), // JSON numbers decode as float64
+		},
+	}, "ADMIN1")
+	require.NoError(t, err)
+	assert.Equal(t, false, settingByKey(t, updated, models.OrgSettingKYCRequiredBeforeRoleAssignment).Value)
+	assert.False(t, settingByKey(t, updated, models.OrgSettingKYCRequiredBeforeRoleAssignment).IsDefault)
+	assert.Equal(t, int64(250), settingByKey(t, updated, models.OrgSettingMaxMembers).Value)
+	assert.Equal(t, []string{models.AuditActionUpdateOrganizationSettings}, audit.actions)
+	assert.Equal(t, []bool{true}, audit.success)
+
+	value, err := service.GetSetting(ctx, "ORG1", models.OrgSettingMaxMembers)
+	require.NoError(t, err)
+	assert.Equal(t, int64(250), value)
+
+	// A null value resets the setting to the default for the organization type
+	reset, err := service.UpdateOrganizationSettings(ctx, "ORG1", &organizations.UpdateOrganizationSettingsRequest{
+		Settings: map[string]interface{}{models.OrgSettingKYCRequiredBeforeRoleAssignment: nil},
+	}, "ADMIN1")
+	require.NoError(t, err)
+	kyc := settingByKey(t, reset, models.OrgSettingKYCRequiredBeforeRoleAssignment)
+	assert.Equal(t, true, kyc.Value)
+	assert.True(t, kyc.IsDefault)
+	assert.NotContains(t, settingRepo.settings, "ORG1/"+models.OrgSettingKYCRequiredBeforeRoleAssignment)
+}
+
+func TestService_UpdateOrganizationSettings_Rejected(t *testing.T) {
+	tests := []struct {
+		name     string
+		orgID    string
+		settings map[string]interface{}
+		check    func(error) bool
+	}{
+		{name: "no settings", orgID: "ORG1", settings: map[string]interface{}{}, check: errors.IsValidationError},
+		{name: "unknown key", orgID: "ORG1", settings: map[string]interface{}{"dark_mode": true}, check: errors.IsValidationError},
+		{name: "wrong type", orgID: "ORG1", settings: map[string]interface{}{models.OrgSettingKYCRequiredBeforeRoleAssignment: "yes"}, check: errors.IsValidationError},
+		{name: "out of range", orgID: "ORG1", settings: map[string]interface{}{models.OrgSettingMaxMembers: float64(-1)}, check: errors.IsValidationError},
+		{name: "fractional int", orgID: "ORG1", settings: map[string]interface{}{models.OrgSettingMaxMembers: 2.5}, check: errors.IsValidationError},
+		{name: "value not allowed", orgID: "ORG1", settings: map[string]interface{}{models.OrgSettingDefaultMemberRole: "owner"}, check: errors.IsValidationError},
+		{
+			name:  "one invalid value rejects the batch",
+			orgID: "ORG1",
+			settings: map[string]interface{}{
+				models.OrgSettingMaxMembers:        float64(10),
+				models.OrgSettingDefaultMemberRole: 7,
+			},
+			check: errors.IsValidationError,
+		},
+		{name: "unknown organization", orgID: "ORG404", settings: map[string]interface{}{models.OrgSettingMaxMembers: float64(10)}, check: errors.IsNotFoundError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settingRepo := &settingTestRepo{settings: map[string]*models.OrganizationSetting{}}
+			audit := &memberTestAudit{}
+			service := newSettingTestService(settingRepo, &settingTestCache{entries: map[string]interface{}{}}, audit)
+
+			_, err := service.UpdateOrganizationSettings(context.Background(), tt.orgID, &organizations.UpdateOrganizationSettingsRequest{Settings: tt.settings}, "ADMIN1")
+			require.Error(t, err)
+			assert.True(t, tt.check(err), "unexpected error: %v", err)
+			assert.Zero(t, settingRepo.writes, "nothing is written when the request is rejected")
+			assert.Empty(t, audit.actions)
+		})
+	}
+}
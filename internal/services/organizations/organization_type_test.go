@@ -0,0 +1,66 @@
+package organizations
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	orgRequests "github.com/Kisanlink/aaa-service/v2/internal/entities/requests/organizations"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// typeTestOrgRepo serves one organization of a configurable type
+type typeTestOrgRepo struct {
+	interfaces.OrganizationRepository
+	org *models.Organization
+}
+
+func (r *typeTestOrgRepo) GetByID(ctx context.Context, id string) (*models.Organization, error) {
+	return r.org, nil
+}
+
+type acceptingValidator struct {
+	interfaces.Validator
+}
+
+func (acceptingValidator) ValidateStruct(s interface{}) error { return nil }
+
+func TestService_UpdateOrganization_RejectsInvalidTypeTransitions(t *testing.T) {
+	repo := &typeTestOrgRepo{}
+	service := NewOrganizationService(repo, nil, nil, nil, acceptingValidator{}, nil, nil, zap.NewNop())
+
+	invalid := 0
+	for _, from := range models.OrganizationTypes() {
+		for _, to := range models.OrganizationTypes() {
+			if from == to || models.CanChangeOrganizationType(from, to) {
+				continue
+			}
+			invalid++
+			t.Run(from+"->"+to, func(t *testing.T) {
+				repo.org = models.NewOrganization("Green Valley", "", from)
+				newType := to
+
+				_, err := service.UpdateOrganization(context.Background(), repo.org.ID, &orgRequests.UpdateOrganizationRequest{Type: &newType})
+				require.Error(t, err)
+				assert.True(t, errors.IsValidationError(err))
+				assert.Contains(t, err.Error(), "cannot change from")
+				assert.Equal(t, from, repo.org.Type, "the organization is left unchanged")
+			})
+		}
+	}
+	assert.Positive(t, invalid)
+}
+
+func TestService_UpdateOrganization_RejectsUnknownType(t *testing.T) {
+	repo := &typeTestOrgRepo{org: models.NewOrganization("Green Valley", "", models.OrgTypeIndividual)}
+	service := NewOrganizationService(repo, nil, nil, nil, acceptingValidator{}, nil, nil, zap.NewNop())
+	newType := "conglomerate"
+
+	_, err := service.UpdateOrganization(context.Background(), repo.org.ID, &orgRequests.UpdateOrganizationRequest{Type: &newType})
+	assert.True(t, errors.IsValidationError(err))
+	assert.Contains(t, err.Error(), "invalid organization type")
+}
@@ -0,0 +1,245 @@
+package organizations
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	organizationResponses "github.com/Kisanlink/aaa-service/v2/internal/entities/responses/organizations"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// SetUserAttributeRepository sets the repository backing the custom attributes organizations keep on their users
+func (s *Service) SetUserAttributeRepository(attributeRepo interfaces.OrganizationUserAttributeRepository) {
+	s.attributeRepo = attributeRepo
+}
+
+// GetUserAttributeSchema returns the custom user attribute schema of an organization; it is empty
+// when the organization has not defined one
+func (s *Service) GetUserAttributeSchema(ctx context.Context, orgID string) (*organizationResponses.UserAttributeSchemaResponse, error) {
+	if s.attributeRepo == nil {
+		return nil, errors.NewInternalError(fmt.Errorf("user attribute repository not configured"))
+	}
+	if _, err := s.getOrganizationForAttributes(ctx, orgID); err != nil {
+		return nil, err
+	}
+
+	schema, err := s.attributeRepo.GetSchema(ctx, orgID)
+	if err != nil {
+		s.logger.Error("Failed to load user attribute schema", zap.String("org_id", orgID), zap.Error(err))
+		return nil, errors.NewInternalError(err)
+	}
+	return newUserAttributeSchemaResponse(orgID, schema), nil
+}
+
+// SetUserAttributeSchema replaces the custom user attribute schema of an organization. Attributes
+// already stored are not revalidated; they must match the new schema the next time they are set.
+func (s *Service) SetUserAttributeSchema(ctx context.Context, orgID string, schema models.UserAttributeSchema, updatedBy string) (*organizationResponses.UserAttributeSchemaResponse, error) {
+	s.logger.Info("Setting user attribute schema",
+		zap.String("org_id", orgID),
+		zap.Int("attributes", len(schema)),
+		zap.String("updated_by", updatedBy))
+
+	if s.attributeRepo == nil {
+		return nil, errors.NewInternalError(fmt.Errorf("user attribute repository not configured"))
+	}
+	if problems := schema.Check(); len(problems) > 0 {
+		return nil, errors.NewValidationError("invalid user attribute schema", problems...)
+	}
+	org, err := s.getOrganizationForAttributes(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	if schema == nil {
+		schema = models.UserAttributeSchema{}
+	}
+	row := models.NewOrganizationUserAttributeSchema(orgID, schema)
+	row.CreatedBy = updatedBy
+	row.UpdatedBy = updatedBy
+	if err := s.attributeRepo.UpsertSchema(ctx, row); err != nil {
+		s.logger.Error("Failed to store user attribute schema", zap.String("org_id", orgID), zap.Error(err))
+		s.auditService.LogOrganizationOperation(ctx, updatedBy, models.AuditActionUpdateUserAttributeSchema, orgID, "Failed to update user attribute schema", false, map[string]interface{}{
+			"error": err.Error(),
+		})
+		return nil, errors.NewInternalError(err)
+	}
+
+	s.auditService.LogOrganizationOperation(ctx, updatedBy, models.AuditActionUpdateUserAttributeSchema, orgID, "User attribute schema updated successfully", true, map[string]interface{}{
+		"attributes":        sortedAttributeNames(schema),
+		"organization_name": org.Name,
+	})
+
+	return newUserAttributeSchemaResponse(orgID, row), nil
+}
+
+// GetUserAttributes returns the custom attributes an organization keeps on a user; they are empty
+// when none have been set
+func (s *Service) GetUserAttributes(ctx context.Context, orgID, userID string) (*organizationResponses.UserAttributesResponse, error) {
+	if s.attributeRepo == nil {
+		return nil, errors.NewInternalError(fmt.Errorf("user attribute repository not configured"))
+	}
+	if userID == "" {
+		return nil, errors.NewValidationError("user ID is required")
+	}
+	if _, err := s.getOrganizationForAttributes(ctx, orgID); err != nil {
+		return nil, err
+	}
+
+	attributes, err := s.attributeRepo.GetAttributes(ctx, orgID, userID)
+	if err != nil {
+		s.logger.Error("Failed to load user attributes",
+			zap.String("org_id", orgID),
+			zap.String("user_id", userID),
+			zap.Error(err))
+		return nil, errors.NewInternalError(err)
+	}
+	return newUserAttributesResponse(orgID, userID, attributes), nil
+}
+
+// SetUserAttributes replaces the custom attributes an organization keeps on one of its members.
+// Null values are dropped; the rest are validated against the organization's schema when it has
+// one, and nothing is written unless every attribute is valid.
+func (s *Service) SetUserAttributes(ctx context.Context, orgID, userID string, attrs map[string]interface{}, updatedBy string) (*organizationResponses.UserAttributesResponse, error) {
+	s.logger.Info("Setting user attributes",
+		zap.String("org_id", orgID),
+		zap.String("user_id", userID),
+		zap.String("updated_by", updatedBy))
+
+	if s.attributeRepo == nil {
+		return nil, errors.NewInternalError(fmt.Errorf("user attribute repository not configured"))
+	}
+	if userID == "" {
+		return nil, errors.NewValidationError("user ID is required")
+	}
+	if _, err := s.getOrganizationForAttributes(ctx, orgID); err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]interface{}, len(attrs))
+	for key, value := range attrs {
+		if value != nil {
+			values[key] = value
+		}
+	}
+
+	schema, err := s.attributeRepo.GetSchema(ctx, orgID)
+	if err != nil {
+		s.logger.Error("Failed to load user attribute schema", zap.String("org_id", orgID), zap.Error(err))
+		return nil, errors.NewInternalError(err)
+	}
+	var definitions models.UserAttributeSchema
+	if schema != nil {
+		definitions = schema.Attributes
+	}
+	if problems := definitions.Validate(values); len(problems) > 0 {
+		return nil, errors.NewValidationError("invalid user attributes", problems...)
+	}
+
+	isMember, err := s.isOrganizationMember(ctx, orgID, userID)
+	if err != nil {
+		s.logger.Error("Failed to check organization membership", zap.String("org_id", orgID), zap.String("user_id", userID), zap.Error(err))
+		return nil, errors.NewInternalError(err)
+	}
+	if !isMember {
+		return nil, errors.NewNotFoundError("user is not a member of this organization")
+	}
+
+	row := models.NewOrganizationUserAttributes(orgID, userID, values)
+	row.CreatedBy = updatedBy
+	row.UpdatedBy = updatedBy
+	if err := s.attributeRepo.UpsertAttributes(ctx, row); err != nil {
+		s.logger.Error("Failed to store user attributes",
+			zap.String("org_id", orgID),
+			zap.String("user_id", userID),
+			zap.Error(err))
+		s.auditService.LogOrganizationOperation(ctx, updatedBy, models.AuditActionUpdateUserAttributes, orgID, "Failed to update user attributes", false, map[string]interface{}{
+			"user_id": userID,
+			"error":   err.Error(),
+		})
+		return nil, errors.NewInternalError(err)
+	}
+
+	// Attribute values can be personal data, so only their names are audited
+	s.auditService.LogOrganizationOperation(ctx, updatedBy, models.AuditActionUpdateUserAttributes, orgID, "User attributes updated successfully", true, map[string]interface{}{
+		"user_id":    userID,
+		"attributes": sortedAttributeNames(values),
+	})
+
+	return newUserAttributesResponse(orgID, userID, row), nil
+}
+
+func (s *Service) getOrganizationForAttributes(ctx context.Context, orgID string) (*models.Organization, error) {
+	if orgID == "" {
+		return nil, errors.NewValidationError("organization ID is required")
+	}
+	org, err := s.orgRepo.GetByID(ctx, orgID)
+	if err != nil || org == nil {
+		s.logger.Error("Organization not found", zap.String("org_id", orgID))
+		return nil, errors.NewNotFoundError("organization not found")
+	}
+	return org, nil
+}
+
+// isOrganizationMember reports whether a user belongs to an organization directly or through one
+// of its groups
+func (s *Service) isOrganizationMember(ctx context.Context, orgID, userID string) (bool, error) {
+	if s.memberRepo == nil {
+		return false, fmt.Errorf("organization member repository not configured")
+	}
+	if member, err := s.memberRepo.GetByOrganizationAndUser(ctx, orgID, userID); err == nil && member != nil {
+		return true, nil
+	}
+	groupMemberships, err := s.memberRepo.CountActiveGroupMemberships(ctx, orgID, userID)
+	if err != nil {
+		return false, err
+	}
+	return groupMemberships > 0, nil
+}
+
+func newUserAttributeSchemaResponse(orgID string, schema *models.OrganizationUserAttributeSchema) *organizationResponses.UserAttributeSchemaResponse {
+	response := &organizationResponses.UserAttributeSchemaResponse{
+		OrganizationID: orgID,
+		Attributes:     models.UserAttributeSchema{},
+	}
+	if schema != nil {
+		if schema.Attributes != nil {
+			response.Attributes = schema.Attributes
+		}
+		if schema.BaseModel != nil && !schema.UpdatedAt.IsZero() {
+			updatedAt := schema.UpdatedAt
+			response.UpdatedAt = &updatedAt
+		}
+	}
+	return response
+}
+
+func newUserAttributesResponse(orgID, userID string, attributes *models.OrganizationUserAttributes) *organizationResponses.UserAttributesResponse {
+	response := &organizationResponses.UserAttributesResponse{
+		OrganizationID: orgID,
+		UserID:         userID,
+		Attributes:     map[string]interface{}{},
+	}
+	if attributes != nil {
+		if attributes.Attributes != nil {
+			response.Attributes = attributes.Attributes
+		}
+		if attributes.BaseModel != nil && !attributes.UpdatedAt.IsZero() {
+			updatedAt := attributes.UpdatedAt
+			response.UpdatedAt = &updatedAt
+		}
+	}
+	return response
+}
+
+func sortedAttributeNames[V any](attributes map[string]V) []string {
+	names := make([]string, 0, len(attributes))
+	for name := range attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,228 @@
+package organizations
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type attributeTestRepo struct {
+	schemas    map[string]*models.OrganizationUserAttributeSchema
+	attributes map[string]*models.OrganizationUserAttributes
+	writes     int
+}
+
+func newAttributeTestRepo() *attributeTestRepo {
+	return &attributeTestRepo{
+		schemas:    map[string]*models.OrganizationUserAttributeSchema{},
+		attributes: map[string]*models.OrganizationUserAttributes{},
+	}
+}
+
+func (r *attributeTestRepo) GetSchema(ctx context.Context, orgID string) (*models.OrganizationUserAttributeSchema, error) {
+	return r.schemas[orgID], nil
+}
+
+func (r *attributeTestRepo) UpsertSchema(ctx context.Context, schema *models.OrganizationUserAttributeSchema) error {
+	r.writes++
+	r.schemas[schema.OrganizationID] = schema
+	return nil
+}
+
+func (r *attributeTestRepo) GetAttributes(ctx context.Context, orgID, userID string) (*models.OrganizationUserAttributes, error) {
+	return r.attributes[orgID+"/"+userID], nil
+}
+
+func (r *attributeTestRepo) UpsertAttributes(ctx context.Context, attributes *models.OrganizationUserAttributes) error {
+	r.writes++
+	r.attributes[attributes.OrganizationID+"/"+attributes.UserID] = attributes
+	return nil
+}
+
+func newAttributeTestService(attributeRepo *attributeTestRepo, audit *memberTestAudit) *Service {
+	memberRepo := &memberTestMemberRepo{
+		members: map[string]*models.OrganizationMember{
+			"ORG1/USER1": models.NewOrganizationMember("ORG1", "USER1", models.OrganizationMemberRoleMember, "ADMIN1"),
+		},
+		groupMemberships: map[string]int64{"ORG1/USER2": 1},
+	}
+	service := newMemberTestService(memberRepo, audit)
+	service.SetUserAttributeRepository(attributeRepo)
+	return service
+}
+
+func floatPtr(v float64) *float64 { return &v }
+
+var farmerSchema = models.UserAttributeSchema{
+	"employee_id": {Type: models.UserAttributeTypeString, Required: true, MaxLength: 10},
+	"region":      {Type: models.UserAttributeTypeString, Allowed: []string{"north", "south"}},
+	"land_acres":  {Type: models.UserAttributeTypeNumber, Min: floatPtr(0)},
+	"family_size": {Type: models.UserAttributeTypeInteger, Min: floatPtr(1), Max: floatPtr(30)},
+	"verified":    {Type: models.UserAttributeTypeBoolean},
+}
+
+func TestService_SetUserAttributeSchema_RejectsInvalidDefinitions(t *testing.T) {
+	attributeRepo := newAttributeTestRepo()
+	audit := &memberTestAudit{}
+	service := newAttributeTestService(attributeRepo, audit)
+
+	_, err := service.SetUserAttributeSchema(context.Background(), "ORG1", models.UserAttributeSchema{
+		"age":    {Type: "date"},
+		"acres":  {Type: models.UserAttributeTypeNumber, Min: floatPtr(10), Max: floatPtr(1)},
+		"active": {Type: models.UserAttributeTypeBoolean, Allowed: []string{"yes"}},
+	}, "ADMIN1")
+
+	require.Error(t, err)
+	require.True(t, errors.IsValidationError(err))
+	assert.ElementsMatch(t, []string{
+		"acres: min must not exceed max",
+		"active: allowed values apply to string attributes only",
+		"age: type must be string, number, integer or boolean",
+	}, err.(*errors.ValidationError).Details())
+	assert.Zero(t, attributeRepo.writes)
+	assert.Empty(t, audit.actions)
+}
+
+func TestService_SetUserAttributeSchema(t *testing.T) {
+	attributeRepo := newAttributeTestRepo()
+	audit := &memberTestAudit{}
+	service := newAttributeTestService(attributeRepo, audit)
+	ctx := context.Background()
+
+	empty, err := service.GetUserAttributeSchema(ctx, "ORG1")
+	require.NoError(t, err)
+	assert.Empty(t, empty.Attributes)
+
+	schema, err := service.SetUserAttributeSchema(ctx, "ORG1", farmerSchema, "ADMIN1")
+	require.NoError(t, err)
+	assert.Len(t, schema.Attributes, len(farmerSchema))
+	assert.Equal(t, []string{models.AuditActionUpdateUserAttributeSchema}, audit.actions)
+
+	stored, err := service.GetUserAttributeSchema(ctx, "ORG1")
+	require.NoError(t, err)
+	assert.Equal(t, farmerSchema, stored.Attributes)
+
+	_, err = service.SetUserAttributeSchema(ctx, "ORG404", farmerSchema, "ADMIN1")
+	assert.True(t, errors.IsNotFoundError(err))
+}
+
+func TestService_SetUserAttributes_SchemaValidationFailures(t *testing.T) {
+	attributeRepo := newAttributeTestRepo()
+	audit := &memberTestAudit{}
+	service := newAttributeTestService(attributeRepo, audit)
+	ctx := context.Background()
+	_, err := service.SetUserAttributeSchema(ctx, "ORG1", farmerSchema, "ADMIN1")
+	require.NoError(t, err)
+	writes := attributeRepo.writes
+
+	tests := []struct {
+		name     string
+		attrs    map[string]interface{}
+		problems []string
+	}{
+		{
+			name:     "missing required attribute",
+			attrs:    map[string]interface{}{"region": "north"},
+			problems: []string{"employee_id is required"},
+		},
+		{
+			name:     "null required attribute",
+			attrs:    map[string]interface{}{"employee_id": nil},
+			problems: []string{"employee_id is required"},
+		},
+		{
+			name:     "unknown attribute",
+			attrs:    map[string]interface{}{"employee_id": "E1", "shoe_size": float64(9)},
+			problems: []string{"shoe_size is not an attribute of this organization"},
+		},
+		{
+			name: "wrong types",
+			attrs: map[string]interface{}{
+				"employee_id": float64(42),
+				"land_acres":  "ten",
+				"verified":    "yes",
+			},
+			problems: []string{
+				"employee_id must be a string",
+				"land_acres must be a number",
+				"verified must be a boolean",
+			},
+		},
+		{
+			name: "out of range",
+			attrs: map[string]interface{}{
+				"employee_id": "E1234567890",
+				"region":      "east",
+				"land_acres":  float64(-1),
+				"family_size": 2.5,
+			},
+			problems: []string{
+				"employee_id must be at most 10 characters",
+				"family_size must be an integer",
+				"land_acres must be at least 0",
+				"region must be one of [north south]",
+			},
+		},
+		{
+			name:     "integer above maximum",
+			attrs:    map[string]interface{}{"employee_id": "E1", "family_size": float64(31)},
+			problems: []string{"family_size must be at most 30"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.SetUserAttributes(ctx, "ORG1", "USER1", tt.attrs, "ADMIN1")
+			require.Error(t, err)
+			require.True(t, errors.IsValidationError(err))
+			assert.Equal(t, tt.problems, err.(*errors.ValidationError).Details())
+		})
+	}
+	assert.Equal(t, writes, attributeRepo.writes, "nothing is written when an attribute is invalid")
+}
+
+func TestService_SetUserAttributes(t *testing.T) {
+	attributeRepo := newAttributeTestRepo()
+	audit := &memberTestAudit{}
+	service := newAttributeTestService(attributeRepo, audit)
+	ctx := context.Background()
+
+	// Without a schema any attributes are accepted
+	attributes, err := service.SetUserAttributes(ctx, "ORG1", "USER2", map[string]interface{}{"nickname": "Ravi", "legacy": nil}, "ADMIN1")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"nickname": "Ravi"}, attributes.Attributes, "null values are dropped")
+
+	_, err = service.SetUserAttributeSchema(ctx, "ORG1", farmerSchema, "ADMIN1")
+	require.NoError(t, err)
+
+	attributes, err = service.SetUserAttributes(ctx, "ORG1", "USER1", map[string]interface{}{
+		"employee_id": "E1",
+		"region":      "south",
+		"family_size": float64(4),
+		"verified":    true,
+	}, "ADMIN1")
+	require.NoError(t, err)
+	assert.Equal(t, "south", attributes.Attributes["region"])
+
+	stored, err := service.GetUserAttributes(ctx, "ORG1", "USER1")
+	require.NoError(t, err)
+	assert.Equal(t, attributes.Attributes, stored.Attributes)
+	assert.Equal(t, []string{
+		models.AuditActionUpdateUserAttributes,
+		models.AuditActionUpdateUserAttributeSchema,
+		models.AuditActionUpdateUserAttributes,
+	}, audit.actions)
+
+	none, err := service.GetUserAttributes(ctx, "ORG1", "USER3")
+	require.NoError(t, err)
+	assert.Empty(t, none.Attributes)
+
+	_, err = service.SetUserAttributes(ctx, "ORG1", "USER3", map[string]interface{}{"employee_id": "E3"}, "ADMIN1")
+	assert.True(t, errors.IsNotFoundError(err), "attributes are only kept on members")
+	_, err = service.SetUserAttributes(ctx, "ORG404", "USER1", map[string]interface{}{"employee_id": "E1"}, "ADMIN1")
+	assert.True(t, errors.IsNotFoundError(err))
+}
@@ -0,0 +1,41 @@
+package organizations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Kisanlink/aaa-service/v2/pkg/warnings"
+	"go.uber.org/zap"
+)
+
+// similarNameCandidates bounds the organizations fetched per search term when looking for
+// near-duplicate names
+const similarNameCandidates = 20
+
+// warnSimilarNames records a warning for each existing organization whose name is nearly
+// identical to name. Lookup failures are logged and never fail the create.
+func (s *Service) warnSimilarNames(ctx context.Context, name string) {
+	if !warnings.Enabled(ctx) {
+		return
+	}
+
+	var candidates []string
+	for _, term := range warnings.SearchTerms(name) {
+		orgs, err := s.orgRepo.Search(ctx, term, similarNameCandidates, 0)
+		if err != nil {
+			s.logger.Warn("Failed to look up similar organization names", zap.String("term", term), zap.Error(err))
+			continue
+		}
+		for _, org := range orgs {
+			candidates = append(candidates, org.Name)
+		}
+	}
+
+	for _, similar := range warnings.SimilarNames(name, candidates) {
+		warnings.Add(ctx, warnings.Warning{
+			Code:    warnings.CodeSimilarName,
+			Field:   "name",
+			Message: fmt.Sprintf("an organization named %q already exists", similar),
+		})
+	}
+}
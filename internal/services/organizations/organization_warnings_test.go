@@ -0,0 +1,65 @@
+package organizations
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/pkg/warnings"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+// searchTestOrgRepo answers name searches from a fixed list of organizations
+type searchTestOrgRepo struct {
+	interfaces.OrganizationRepository
+	names    []string
+	searches int
+	err      error
+}
+
+func (r *searchTestOrgRepo) Search(ctx context.Context, keyword string, limit, offset int) ([]*models.Organization, error) {
+	r.searches++
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	var orgs []*models.Organization
+	for _, name := range r.names {
+		if strings.Contains(name, keyword) && len(orgs) < limit {
+			orgs = append(orgs, models.NewOrganization(name, "", models.OrgTypeFPO))
+		}
+	}
+	return orgs, nil
+}
+
+func TestService_WarnSimilarNames(t *testing.T) {
+	repo := &searchTestOrgRepo{names: []string{"Green Valley FPO", "Green Valley Traders", "Sunrise Dairy"}}
+	service := NewOrganizationService(repo, nil, nil, nil, nil, nil, nil, zap.NewNop())
+
+	ctx, collector := warnings.NewContext(context.Background())
+	service.warnSimilarNames(ctx, "Green Valley F.P.O.")
+
+	assert.Equal(t, []warnings.Warning{{
+		Code:    warnings.CodeSimilarName,
+		Field:   "name",
+		Message: `an organization named "Green Valley FPO" already exists`,
+	}}, collector.Warnings())
+}
+
+func TestService_WarnSimilarNames_NeverFails(t *testing.T) {
+	repo := &searchTestOrgRepo{err: fmt.Errorf("connection refused")}
+	service := NewOrganizationService(repo, nil, nil, nil, nil, nil, nil, zap.NewNop())
+
+	ctx, collector := warnings.NewContext(context.Background())
+	service.warnSimilarNames(ctx, "Green Valley FPO")
+	assert.Empty(t, collector.Warnings())
+
+	// Callers that do not collect warnings skip the lookup entirely
+	repo.searches = 0
+	service.warnSimilarNames(context.Background(), "Green Valley FPO")
+	assert.Zero(t, repo.searches)
+}
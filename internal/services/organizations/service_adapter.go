@@ -3,7 +3,9 @@ package organizations
 import (
 	"context"
 
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
 	"github.com/Kisanlink/aaa-service/v2/internal/entities/requests/organizations"
+	orgResponses "github.com/Kisanlink/aaa-service/v2/internal/entities/responses/organizations"
 	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
 	"go.uber.org/zap"
 )
@@ -72,11 +74,75 @@ func (a *ServiceAdapter) CountOrganizations(ctx context.Context, includeInactive
 	return a.service.CountOrganizations(ctx, includeInactive, orgType)
 }
 
+// RequestOrganizationHardDelete adapts the concrete method to the interface
+func (a *ServiceAdapter) RequestOrganizationHardDelete(ctx context.Context, orgID string, requestedBy string) (interface{}, error) {
+	return a.service.RequestOrganizationHardDelete(ctx, orgID, requestedBy)
+}
+
+// HardDeleteOrganization adapts the concrete method to the interface
+func (a *ServiceAdapter) HardDeleteOrganization(ctx context.Context, orgID string, deletedBy string, confirmationToken string) error {
+	return a.service.HardDeleteOrganization(ctx, orgID, deletedBy, confirmationToken)
+}
+
+// DeleteOrganizationCascade adapts the concrete method to the interface
+func (a *ServiceAdapter) DeleteOrganizationCascade(ctx context.Context, orgID string, deletedBy string) (interface{}, error) {
+	return a.service.DeleteOrganizationCascade(ctx, orgID, deletedBy)
+}
+
+// RestoreOrganization adapts the concrete method to the interface
+func (a *ServiceAdapter) RestoreOrganization(ctx context.Context, orgID string, restoredBy string) (interface{}, error) {
+	return a.service.RestoreOrganization(ctx, orgID, restoredBy)
+}
+
+// RestoreOrganizationCascade adapts the concrete method to the interface
+func (a *ServiceAdapter) RestoreOrganizationCascade(ctx context.Context, orgID string, restoredBy string) (interface{}, error) {
+	return a.service.RestoreOrganizationCascade(ctx, orgID, restoredBy)
+}
+
+// MergeOrganizations adapts the concrete method to the interface
+func (a *ServiceAdapter) MergeOrganizations(ctx context.Context, sourceID, targetID, mergedBy string) (interface{}, error) {
+	return a.service.MergeOrganizations(ctx, sourceID, targetID, mergedBy)
+}
+
+// ListOrganizationsWithDeleted adapts the concrete method to the interface
+func (a *ServiceAdapter) ListOrganizationsWithDeleted(ctx context.Context, limit, offset int) ([]interface{}, error) {
+	orgs, err := a.service.ListOrganizationsWithDeleted(ctx, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]interface{}, len(orgs))
+	for i, org := range orgs {
+		result[i] = org
+	}
+	return result, nil
+}
+
+// CountOrganizationsWithDeleted adapts the concrete method to the interface
+func (a *ServiceAdapter) CountOrganizationsWithDeleted(ctx context.Context) (int64, error) {
+	return a.service.CountOrganizationsWithDeleted(ctx)
+}
+
 // GetOrganizationHierarchy adapts the concrete method to the interface
 func (a *ServiceAdapter) GetOrganizationHierarchy(ctx context.Context, orgID string) (interface{}, error) {
 	return a.service.GetOrganizationHierarchy(ctx, orgID)
 }
 
+// CheckHierarchyDepth adapts the concrete method to the interface
+func (a *ServiceAdapter) CheckHierarchyDepth(ctx context.Context, maxDepth int) (interface{}, error) {
+	return a.service.CheckHierarchyDepth(ctx, maxDepth)
+}
+
+// GetOrganizationAncestors adapts the concrete method to the interface
+func (a *ServiceAdapter) GetOrganizationAncestors(ctx context.Context, orgID string, maxDepth, limit, offset int) (interface{}, error) {
+	return a.service.GetOrganizationAncestors(ctx, orgID, maxDepth, limit, offset)
+}
+
+// GetOrganizationDescendants adapts the concrete method to the interface
+func (a *ServiceAdapter) GetOrganizationDescendants(ctx context.Context, orgID string, maxDepth, limit, offset int) (interface{}, error) {
+	return a.service.GetOrganizationDescendants(ctx, orgID, maxDepth, limit, offset)
+}
+
 // ActivateOrganization adapts the concrete method to the interface
 func (a *ServiceAdapter) ActivateOrganization(ctx context.Context, orgID string) error {
 	return a.service.ActivateOrganization(ctx, orgID)
@@ -92,6 +158,96 @@ func (a *ServiceAdapter) GetOrganizationStats(ctx context.Context, orgID string)
 	return a.service.GetOrganizationStats(ctx, orgID)
 }
 
+// AddUserToOrganization adapts the concrete method to the interface
+func (a *ServiceAdapter) AddUserToOrganization(ctx context.Context, orgID string, req interface{}, addedBy string) (interface{}, error) {
+	addReq, ok := req.(*organizations.AddOrganizationMemberRequest)
+	if !ok {
+		a.logger.Error("Invalid request type for AddUserToOrganization")
+		return nil, &InvalidRequestTypeError{Expected: "*organizations.AddOrganizationMemberRequest"}
+	}
+	return a.service.AddUserToOrganization(ctx, orgID, addReq, addedBy)
+}
+
+// RemoveUserFromOrganization adapts the concrete method to the interface
+func (a *ServiceAdapter) RemoveUserFromOrganization(ctx context.Context, orgID, userID string, removedBy string) (interface{}, error) {
+	return a.service.RemoveUserFromOrganization(ctx, orgID, userID, removedBy)
+}
+
+// ListOrganizationMembers adapts the concrete method to the interface
+func (a *ServiceAdapter) ListOrganizationMembers(ctx context.Context, orgID string, limit, offset int) (interface{}, error) {
+	return a.service.ListOrganizationMembers(ctx, orgID, limit, offset)
+}
+
+// GetOrganizationSettings adapts the concrete method to the interface
+func (a *ServiceAdapter) GetOrganizationSettings(ctx context.Context, orgID string) (interface{}, error) {
+	return a.service.GetOrganizationSettings(ctx, orgID)
+}
+
+// UpdateOrganizationSettings adapts the concrete method to the interface
+func (a *ServiceAdapter) UpdateOrganizationSettings(ctx context.Context, orgID string, req interface{}, updatedBy string) (interface{}, error) {
+	updateReq, ok := req.(*organizations.UpdateOrganizationSettingsRequest)
+	if !ok {
+		a.logger.Error("Invalid request type for UpdateOrganizationSettings")
+		return nil, &InvalidRequestTypeError{Expected: "*organizations.UpdateOrganizationSettingsRequest"}
+	}
+	return a.service.UpdateOrganizationSettings(ctx, orgID, updateReq, updatedBy)
+}
+
+// GetSetting adapts the concrete method to the interface
+func (a *ServiceAdapter) GetSetting(ctx context.Context, orgID, key string) (interface{}, error) {
+	return a.service.GetSetting(ctx, orgID, key)
+}
+
+// GetUserAttributeSchema adapts the concrete method to the interface
+func (a *ServiceAdapter) GetUserAttributeSchema(ctx context.Context, orgID string) (*orgResponses.UserAttributeSchemaResponse, error) {
+	return a.service.GetUserAttributeSchema(ctx, orgID)
+}
+
+// SetUserAttributeSchema adapts the concrete method to the interface
+func (a *ServiceAdapter) SetUserAttributeSchema(ctx context.Context, orgID string, schema models.UserAttributeSchema, updatedBy string) (*orgResponses.UserAttributeSchemaResponse, error) {
+	return a.service.SetUserAttributeSchema(ctx, orgID, schema, updatedBy)
+}
+
+// GetUserAttributes adapts the concrete method to the interface
+func (a *ServiceAdapter) GetUserAttributes(ctx context.Context, orgID, userID string) (*orgResponses.UserAttributesResponse, error) {
+	return a.service.GetUserAttributes(ctx, orgID, userID)
+}
+
+// SetUserAttributes adapts the concrete method to the interface
+func (a *ServiceAdapter) SetUserAttributes(ctx context.Context, orgID, userID string, attrs map[string]interface{}, updatedBy string) (*orgResponses.UserAttributesResponse, error) {
+	return a.service.SetUserAttributes(ctx, orgID, userID, attrs, updatedBy)
+}
+
+// ListRoleTemplates adapts the concrete method to the interface
+func (a *ServiceAdapter) ListRoleTemplates(ctx context.Context) (interface{}, error) {
+	return a.service.ListRoleTemplates(ctx)
+}
+
+// CreateRoleTemplate adapts the concrete method to the interface
+func (a *ServiceAdapter) CreateRoleTemplate(ctx context.Context, req interface{}, createdBy string) (interface{}, error) {
+	createReq, ok := req.(*organizations.CreateRoleTemplateRequest)
+	if !ok {
+		a.logger.Error("Invalid request type for CreateRoleTemplate")
+		return nil, &InvalidRequestTypeError{Expected: "*organizations.CreateRoleTemplateRequest"}
+	}
+	return a.service.CreateRoleTemplate(ctx, createReq, createdBy)
+}
+
+// DeleteRoleTemplate adapts the concrete method to the interface
+func (a *ServiceAdapter) DeleteRoleTemplate(ctx context.Context, name string, deletedBy string) error {
+	return a.service.DeleteRoleTemplate(ctx, name, deletedBy)
+}
+
+// ApplyRoleTemplate adapts the concrete method to the interface
+func (a *ServiceAdapter) ApplyRoleTemplate(ctx context.Context, orgID, templateName, appliedBy string) (interface{}, error) {
+	return a.service.ApplyRoleTemplate(ctx, orgID, templateName, appliedBy)
+}
+
+// ListAvailableRoles adapts the concrete method to the interface
+func (a *ServiceAdapter) ListAvailableRoles(ctx context.Context, orgID string) (interface{}, error) {
+	return a.service.ListAvailableRoles(ctx, orgID)
+}
+
 // GetOrganizationGroups adapts the concrete method to the interface
 func (a *ServiceAdapter) GetOrganizationGroups(ctx context.Context, orgID string, limit, offset int, includeInactive bool) (interface{}, error) {
 	return a.service.GetOrganizationGroups(ctx, orgID, limit, offset, includeInactive)
@@ -117,6 +273,11 @@ func (a *ServiceAdapter) DeleteGroupInOrganization(ctx context.Context, orgID, g
 	return a.service.DeleteGroupInOrganization(ctx, orgID, groupID, deletedBy)
 }
 
+// GetGroupChildren adapts the concrete method to the interface
+func (a *ServiceAdapter) GetGroupChildren(ctx context.Context, orgID, parentGroupID string, limit, offset int) (interface{}, error) {
+	return a.service.GetGroupChildren(ctx, orgID, parentGroupID, limit, offset)
+}
+
 // GetGroupHierarchyInOrganization adapts the concrete method to the interface
 func (a *ServiceAdapter) GetGroupHierarchyInOrganization(ctx context.Context, orgID, groupID string) (interface{}, error) {
 	return a.service.GetGroupHierarchyInOrganization(ctx, orgID, groupID)
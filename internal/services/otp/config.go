@@ -0,0 +1,94 @@
+package otp
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/services/sms"
+)
+
+// Sender names accepted in the AAA_OTP_*_SENDER variables
+const (
+	SenderSMS   = "sms"
+	SenderEmail = "email"
+	SenderLog   = "log"
+)
+
+// Config controls how OTPs are generated, delivered and validated
+type Config struct {
+	// Senders maps a contact type to the sender its OTPs are delivered with.
+	// Contact types without a sender cannot receive OTPs.
+	Senders map[string]string
+
+	Length         int
+	TTL            time.Duration
+	MaxAttempts    int
+	ResendCooldown time.Duration
+}
+
+// DefaultConfig delivers mobile OTPs by SMS and email OTPs by email
+func DefaultConfig() *Config {
+	return &Config{
+		Senders: map[string]string{
+			models.ContactTypeMobile: SenderSMS,
+			models.ContactTypeEmail:  SenderEmail,
+		},
+		Length:         sms.DefaultOTPLength,
+		TTL:            10 * time.Minute,
+		MaxAttempts:    5,
+		ResendCooldown: 30 * time.Second,
+	}
+}
+
+// LoadConfigFromEnv loads OTP configuration from environment variables, starting from DefaultConfig.
+// A sender variable set to "none" disables OTPs for that contact type.
+// Variables:
+//
+//	AAA_OTP_MOBILE_SENDER (sms, log or none; default sms)
+//	AAA_OTP_EMAIL_SENDER (email, log or none; default email)
+//	AAA_OTP_LENGTH (default 6)
+//	AAA_OTP_TTL_SECONDS (default 600)
+//	AAA_OTP_MAX_ATTEMPTS (default 5)
+//	AAA_OTP_RESEND_COOLDOWN_SECONDS (default 30)
+func LoadConfigFromEnv() *Config {
+	cfg := DefaultConfig()
+
+	senderEnvVars := map[string]string{
+		models.ContactTypeMobile: "AAA_OTP_MOBILE_SENDER",
+		models.ContactTypeEmail:  "AAA_OTP_EMAIL_SENDER",
+	}
+	for contactType, envVar := range senderEnvVars {
+		value := strings.ToLower(strings.TrimSpace(os.Getenv(envVar)))
+		switch value {
+		case "":
+		case "none":
+			delete(cfg.Senders, contactType)
+		default:
+			cfg.Senders[contactType] = value
+		}
+	}
+
+	if length, ok := envInt("AAA_OTP_LENGTH"); ok && length > 0 {
+		cfg.Length = length
+	}
+	if seconds, ok := envInt("AAA_OTP_TTL_SECONDS"); ok && seconds > 0 {
+		cfg.TTL = time.Duration(seconds) * time.Second
+	}
+	if attempts, ok := envInt("AAA_OTP_MAX_ATTEMPTS"); ok && attempts > 0 {
+		cfg.MaxAttempts = attempts
+	}
+	if seconds, ok := envInt("AAA_OTP_RESEND_COOLDOWN_SECONDS"); ok && seconds >= 0 {
+		cfg.ResendCooldown = time.Duration(seconds) * time.Second
+	}
+
+	return cfg
+}
+
+// envInt returns the integer value of an environment variable; ok is false when it is unset or invalid
+func envInt(key string) (int, bool) {
+	value, err := strconv.Atoi(os.Getenv(key))
+	return value, err == nil
+}
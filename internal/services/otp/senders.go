@@ -0,0 +1,101 @@
+package otp
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"go.uber.org/zap"
+)
+
+// LogSender is the no-op sender: it logs that an OTP was issued, never the OTP itself, and
+// reports success. It suits development and contact types that should not receive messages yet.
+type LogSender struct {
+	logger *zap.Logger
+}
+
+// NewLogSender creates the no-op sender
+func NewLogSender(logger *zap.Logger) *LogSender {
+	return &LogSender{logger: logger}
+}
+
+// SendOTP logs the masked destination
+func (s *LogSender) SendOTP(ctx context.Context, destination, otp string) error {
+	s.logger.Info("OTP delivery not configured, logging OTP issue",
+		zap.String("destination_masked", MaskDestination(destination)))
+	return nil
+}
+
+// SMSSender delivers OTPs as verification SMS through the SMS gateway
+type SMSSender struct {
+	smsService interfaces.SMSService
+}
+
+// NewSMSSender creates an SMS sender on the given SMS service
+func NewSMSSender(smsService interfaces.SMSService) *SMSSender {
+	return &SMSSender{smsService: smsService}
+}
+
+// SendOTP sends the OTP to an E.164 phone number
+func (s *SMSSender) SendOTP(ctx context.Context, destination, otp string) error {
+	return s.smsService.SendVerificationOTP(ctx, destination, otp)
+}
+
+// EmailSender is a stub email sender: the service has no email provider yet, so it logs the email
+// it would send
+type EmailSender struct {
+	logger *zap.Logger
+}
+
+// NewEmailSender creates the stub email sender
+func NewEmailSender(logger *zap.Logger) *EmailSender {
+	return &EmailSender{logger: logger}
+}
+
+// SendOTP logs the OTP email that would be sent to the address
+func (s *EmailSender) SendOTP(ctx context.Context, destination, otp string) error {
+	s.logger.Info("Email provider not configured, logging OTP email",
+		zap.String("email_masked", MaskDestination(destination)))
+	return nil
+}
+
+// NewSenders creates the sender configured for each contact type. The SMS sender falls back to the
+// no-op sender when smsService is nil, and unknown sender names are skipped.
+func NewSenders(config *Config, smsService interfaces.SMSService, logger *zap.Logger) map[string]interfaces.OTPSender {
+	senders := make(map[string]interfaces.OTPSender, len(config.Senders))
+	for contactType, name := range config.Senders {
+		switch name {
+		case SenderSMS:
+			if smsService == nil {
+				logger.Warn("SMS service not configured, OTPs will be logged instead of sent",
+					zap.String("contact_type", contactType))
+				senders[contactType] = NewLogSender(logger)
+				continue
+			}
+			senders[contactType] = NewSMSSender(smsService)
+		case SenderEmail:
+			senders[contactType] = NewEmailSender(logger)
+		case SenderLog:
+			senders[contactType] = NewLogSender(logger)
+		default:
+			logger.Warn("Ignoring unknown OTP sender",
+				zap.String("contact_type", contactType),
+				zap.String("sender", name))
+		}
+	}
+	return senders
+}
+
+// MaskDestination masks an email address or a phone number for logs and responses
+func MaskDestination(destination string) string {
+	at := strings.LastIndex(destination, "@")
+	switch {
+	case at > 0:
+		return destination[:1] + "***" + destination[at:]
+	case at == 0:
+		return "***"
+	default:
+		return models.MaskPhoneNumberE164(destination)
+	}
+}
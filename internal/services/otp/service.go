@@ -0,0 +1,268 @@
+// Package otp issues one-time passwords, delivers them through a sender chosen by the contact type,
+// and validates them. Generation and validation are shared by every flow that needs an OTP; only
+// delivery is pluggable.
+//
+// An issued OTP is a challenge cached under otp_challenge:<id> until it expires. Only a bcrypt hash
+// of the code is kept once it has been delivered. When delivery fails the challenge stays valid and
+// also keeps the undelivered code, so a resend retries delivery of the same code instead of issuing
+// a new one. A resend after a successful delivery issues a fresh code.
+package otp
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/internal/services/sms"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Challenge is an issued OTP as seen by callers
+type Challenge struct {
+	ID          string    `json:"challenge_id"`
+	Purpose     string    `json:"purpose"`
+	SubjectID   string    `json:"subject_id"`
+	ContactType string    `json:"contact_type"`
+	Destination string    `json:"destination"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	// Delivered is false when the last delivery failed; the OTP is still valid and can be resent
+	Delivered bool `json:"delivered"`
+}
+
+// challengeRecord is the cached state of a challenge
+type challengeRecord struct {
+	Challenge
+	CodeHash    string    `json:"code_hash"`
+	PendingCode string    `json:"pending_code,omitempty"`
+	Attempts    int       `json:"attempts"`
+	LastSentAt  time.Time `json:"last_sent_at"`
+}
+
+// Service issues, delivers and validates OTPs
+type Service struct {
+	cache   interfaces.CacheService
+	senders map[string]interfaces.OTPSender
+	config  *Config
+	logger  *zap.Logger
+}
+
+// NewService creates an OTP service delivering through the given senders, keyed by contact type
+func NewService(cache interfaces.CacheService, senders map[string]interfaces.OTPSender, config *Config, logger *zap.Logger) *Service {
+	if config == nil {
+		config = DefaultConfig()
+	}
+	return &Service{
+		cache:   cache,
+		senders: senders,
+		config:  config,
+		logger:  logger,
+	}
+}
+
+// CanDeliver reports whether OTPs can be delivered to contacts of the given type
+func (s *Service) CanDeliver(contactType string) bool {
+	_, ok := s.senders[contactType]
+	return ok
+}
+
+// Issue generates an OTP for a purpose (such as "contact_verification") and subject, and sends it
+// to the destination with the sender of the contact type. A failed delivery is not an error: the
+// challenge is returned with Delivered false and can be resent.
+func (s *Service) Issue(ctx context.Context, purpose, subjectID, contactType, destination string) (*Challenge, error) {
+	if purpose == "" || subjectID == "" || destination == "" {
+		return nil, errors.NewValidationError("purpose, subject and destination are required")
+	}
+	if !s.CanDeliver(contactType) {
+		return nil, errors.NewValidationError(fmt.Sprintf("OTP delivery is not available for contact type %q", contactType))
+	}
+
+	id, err := newChallengeID()
+	if err != nil {
+		return nil, errors.NewInternalError(fmt.Errorf("failed to generate OTP challenge: %w", err))
+	}
+	record := &challengeRecord{
+		Challenge: Challenge{
+			ID:          id,
+			Purpose:     purpose,
+			SubjectID:   subjectID,
+			ContactType: contactType,
+			Destination: destination,
+		},
+	}
+	code, err := s.newCode(record)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.deliver(ctx, record, code); err != nil {
+		return nil, err
+	}
+	return &record.Challenge, nil
+}
+
+// Resend delivers the challenge's OTP again. An OTP whose delivery failed is sent again unchanged;
+// once an OTP has been delivered, a resend replaces it with a fresh one. Resends are limited by the
+// configured cooldown.
+func (s *Service) Resend(ctx context.Context, purpose, subjectID, challengeID string) (*Challenge, error) {
+	record, err := s.loadChallenge(purpose, subjectID, challengeID)
+	if err != nil {
+		return nil, err
+	}
+
+	if wait := s.config.ResendCooldown - time.Since(record.LastSentAt); wait > 0 {
+		return nil, errors.NewRateLimitError(strconv.Itoa(int(wait.Seconds()) + 1))
+	}
+
+	code := record.PendingCode
+	if code == "" {
+		if code, err = s.newCode(record); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.deliver(ctx, record, code); err != nil {
+		return nil, err
+	}
+	return &record.Challenge, nil
+}
+
+// Verify checks an OTP against the challenge. A correct OTP consumes the challenge; after the
+// configured number of wrong attempts the challenge is discarded.
+func (s *Service) Verify(ctx context.Context, purpose, subjectID, challengeID, code string) (*Challenge, error) {
+	if code == "" {
+		return nil, errors.NewValidationError("OTP is required")
+	}
+	record, err := s.loadChallenge(purpose, subjectID, challengeID)
+	if err != nil {
+		return nil, err
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(record.CodeHash), []byte(code)) != nil {
+		record.Attempts++
+		if record.Attempts >= s.config.MaxAttempts {
+			s.deleteChallenge(record.ID)
+			s.logger.Warn("OTP challenge discarded after too many attempts",
+				zap.String("challenge_id", record.ID),
+				zap.String("purpose", record.Purpose),
+				zap.String("subject_id", record.SubjectID))
+			return nil, errors.NewValidationError("too many invalid attempts, request a new OTP")
+		}
+		if err := s.saveChallenge(record); err != nil {
+			return nil, errors.NewInternalError(fmt.Errorf("failed to record OTP attempt: %w", err))
+		}
+		return nil, errors.NewValidationError("invalid OTP")
+	}
+
+	s.deleteChallenge(record.ID)
+	return &record.Challenge, nil
+}
+
+// newCode generates a code for the record, replacing any previous one, and restarts its validity
+func (s *Service) newCode(record *challengeRecord) (string, error) {
+	code, err := sms.GenerateNumericOTP(s.config.Length)
+	if err != nil {
+		return "", errors.NewInternalError(err)
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(code), bcrypt.DefaultCost)
+	if err != nil {
+		return "", errors.NewInternalError(fmt.Errorf("failed to hash OTP: %w", err))
+	}
+	record.CodeHash = string(hash)
+	record.PendingCode = ""
+	record.Attempts = 0
+	record.ExpiresAt = time.Now().Add(s.config.TTL)
+	return code, nil
+}
+
+// deliver sends the code and records the outcome. The challenge is saved before sending, so the
+// OTP is valid even if the sender fails; only a failure to save is returned as an error.
+func (s *Service) deliver(ctx context.Context, record *challengeRecord, code string) error {
+	record.Delivered = false
+	record.PendingCode = code
+	record.LastSentAt = time.Now()
+	if err := s.saveChallenge(record); err != nil {
+		return errors.NewInternalError(fmt.Errorf("failed to store OTP challenge: %w", err))
+	}
+
+	if err := s.senders[record.ContactType].SendOTP(ctx, record.Destination, code); err != nil {
+		s.logger.Warn("OTP delivery failed, the OTP stays valid for a resend",
+			zap.String("challenge_id", record.ID),
+			zap.String("purpose", record.Purpose),
+			zap.String("contact_type", record.ContactType),
+			zap.Error(err))
+		return nil
+	}
+
+	record.Delivered = true
+	record.PendingCode = ""
+	if err := s.saveChallenge(record); err != nil {
+		// The undelivered code stays cached until the challenge expires; it is still valid
+		s.logger.Warn("Failed to record OTP delivery",
+			zap.String("challenge_id", record.ID),
+			zap.Error(err))
+	}
+	return nil
+}
+
+// loadChallenge returns an unexpired challenge issued for the purpose and subject. Any other
+// challenge is reported as not found.
+func (s *Service) loadChallenge(purpose, subjectID, challengeID string) (*challengeRecord, error) {
+	notFound := errors.NewNotFoundError("OTP challenge not found or expired")
+	if challengeID == "" {
+		return nil, notFound
+	}
+	value, ok := s.cache.Get(challengeKey(challengeID))
+	if !ok {
+		return nil, notFound
+	}
+	data, ok := value.(string)
+	if !ok {
+		return nil, notFound
+	}
+	var record challengeRecord
+	if err := json.Unmarshal([]byte(data), &record); err != nil {
+		return nil, notFound
+	}
+	if record.Purpose != purpose || record.SubjectID != subjectID || !time.Now().Before(record.ExpiresAt) {
+		return nil, notFound
+	}
+	return &record, nil
+}
+
+// saveChallenge caches the record until it expires, as a JSON string like the other cached records
+func (s *Service) saveChallenge(record *challengeRecord) error {
+	ttl := int(time.Until(record.ExpiresAt).Seconds())
+	if ttl <= 0 {
+		return fmt.Errorf("challenge %s has expired", record.ID)
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return s.cache.Set(challengeKey(record.ID), string(data), ttl)
+}
+
+func (s *Service) deleteChallenge(challengeID string) {
+	if err := s.cache.Delete(challengeKey(challengeID)); err != nil {
+		s.logger.Warn("Failed to delete OTP challenge", zap.String("challenge_id", challengeID), zap.Error(err))
+	}
+}
+
+func challengeKey(challengeID string) string {
+	return fmt.Sprintf("otp_challenge:%s", challengeID)
+}
+
+func newChallengeID() (string, error) {
+	buf := make([]byte, 12)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return "OTPC" + hex.EncodeToString(buf), nil
+}
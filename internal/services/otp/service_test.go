@@ -0,0 +1,180 @@
+package otp
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type memoryCache struct {
+	interfaces.CacheService
+	values map[string]interface{}
+}
+
+func (c *memoryCache) Get(key string) (interface{}, bool) {
+	value, ok := c.values[key]
+	return value, ok
+}
+
+func (c *memoryCache) Set(key string, value interface{}, ttl int) error {
+	c.values[key] = value
+	return nil
+}
+
+func (c *memoryCache) Delete(key string) error {
+	delete(c.values, key)
+	return nil
+}
+
+// recordingSender wraps the no-op sender, remembering the codes and optionally failing
+type recordingSender struct {
+	*LogSender
+	codes []string
+	fail  bool
+}
+
+func (s *recordingSender) SendOTP(ctx context.Context, destination, otp string) error {
+	s.codes = append(s.codes, otp)
+	if s.fail {
+		return fmt.Errorf("gateway unavailable")
+	}
+	return s.LogSender.SendOTP(ctx, destination, otp)
+}
+
+func newTestService(sender interfaces.OTPSender) *Service {
+	config := DefaultConfig()
+	config.ResendCooldown = 0
+	return NewService(
+		&memoryCache{values: map[string]interface{}{}},
+		map[string]interfaces.OTPSender{models.ContactTypeMobile: sender},
+		config,
+		zap.NewNop(),
+	)
+}
+
+func TestIssueAndVerify(t *testing.T) {
+	sender := &recordingSender{LogSender: NewLogSender(zap.NewNop())}
+	service := newTestService(sender)
+	ctx := context.Background()
+
+	challenge, err := service.Issue(ctx, "contact_verification", "CONT1", models.ContactTypeMobile, "+919876543210")
+	require.NoError(t, err)
+	assert.True(t, challenge.Delivered)
+	require.Len(t, sender.codes, 1)
+	assert.Len(t, sender.codes[0], 6)
+
+	_, err = service.Verify(ctx, "contact_verification", "CONT2", challenge.ID, sender.codes[0])
+	assert.True(t, errors.IsNotFoundError(err), "a challenge only verifies its own subject")
+	_, err = service.Verify(ctx, "password_reset", "CONT1", challenge.ID, sender.codes[0])
+	assert.True(t, errors.IsNotFoundError(err), "a challenge only verifies its own purpose")
+
+	verified, err := service.Verify(ctx, "contact_verification", "CONT1", challenge.ID, sender.codes[0])
+	require.NoError(t, err)
+	assert.Equal(t, "+919876543210", verified.Destination)
+
+	_, err = service.Verify(ctx, "contact_verification", "CONT1", challenge.ID, sender.codes[0])
+	assert.True(t, errors.IsNotFoundError(err), "an OTP is used once")
+}
+
+func TestIssueRejectsContactTypeWithoutSender(t *testing.T) {
+	service := newTestService(NewLogSender(zap.NewNop()))
+
+	_, err := service.Issue(context.Background(), "contact_verification", "CONT1", models.ContactTypeEmail, "a@example.com")
+	assert.True(t, errors.IsValidationError(err))
+}
+
+func TestDeliveryFailureKeepsOTPForResend(t *testing.T) {
+	sender := &recordingSender{LogSender: NewLogSender(zap.NewNop()), fail: true}
+	service := newTestService(sender)
+	ctx := context.Background()
+
+	challenge, err := service.Issue(ctx, "contact_verification", "CONT1", models.ContactTypeMobile, "+919876543210")
+	require.NoError(t, err, "a failed delivery is not an error")
+	assert.False(t, challenge.Delivered)
+
+	sender.fail = false
+	resent, err := service.Resend(ctx, "contact_verification", "CONT1", challenge.ID)
+	require.NoError(t, err)
+	assert.True(t, resent.Delivered)
+	require.Len(t, sender.codes, 2)
+	assert.Equal(t, sender.codes[0], sender.codes[1], "the undelivered OTP is sent again")
+
+	_, err = service.Verify(ctx, "contact_verification", "CONT1", challenge.ID, sender.codes[0])
+	require.NoError(t, err)
+}
+
+func TestResendAfterDeliveryIssuesNewOTP(t *testing.T) {
+	sender := &recordingSender{LogSender: NewLogSender(zap.NewNop())}
+	service := newTestService(sender)
+	ctx := context.Background()
+
+	challenge, err := service.Issue(ctx, "contact_verification", "CONT1", models.ContactTypeMobile, "+919876543210")
+	require.NoError(t, err)
+	_, err = service.Resend(ctx, "contact_verification", "CONT1", challenge.ID)
+	require.NoError(t, err)
+	require.Len(t, sender.codes, 2)
+
+	if sender.codes[0] != sender.codes[1] {
+		_, err = service.Verify(ctx, "contact_verification", "CONT1", challenge.ID, sender.codes[0])
+		assert.True(t, errors.IsValidationError(err), "the replaced OTP is no longer valid")
+	}
+	_, err = service.Verify(ctx, "contact_verification", "CONT1", challenge.ID, sender.codes[1])
+	require.NoError(t, err)
+}
+
+func TestResendCooldown(t *testing.T) {
+	service := newTestService(NewLogSender(zap.NewNop()))
+	service.config.ResendCooldown = time.Minute
+	ctx := context.Background()
+
+	challenge, err := service.Issue(ctx, "contact_verification", "CONT1", models.ContactTypeMobile, "+919876543210")
+	require.NoError(t, err)
+
+	_, err = service.Resend(ctx, "contact_verification", "CONT1", challenge.ID)
+	assert.Equal(t, errors.CodeRateLimited, errors.CodeOf(err))
+}
+
+func TestVerifyDiscardsChallengeAfterMaxAttempts(t *testing.T) {
+	sender := &recordingSender{LogSender: NewLogSender(zap.NewNop())}
+	service := newTestService(sender)
+	service.config.MaxAttempts = 2
+	ctx := context.Background()
+
+	challenge, err := service.Issue(ctx, "contact_verification", "CONT1", models.ContactTypeMobile, "+919876543210")
+	require.NoError(t, err)
+	wrong := "000000"
+	if sender.codes[0] == wrong {
+		wrong = "111111"
+	}
+
+	_, err = service.Verify(ctx, "contact_verification", "CONT1", challenge.ID, wrong)
+	assert.True(t, errors.IsValidationError(err))
+	_, err = service.Verify(ctx, "contact_verification", "CONT1", challenge.ID, wrong)
+	assert.True(t, errors.IsValidationError(err))
+
+	_, err = service.Verify(ctx, "contact_verification", "CONT1", challenge.ID, sender.codes[0])
+	assert.True(t, errors.IsNotFoundError(err), "the challenge is gone after too many attempts")
+}
+
+func TestNewSendersSelectsByContactType(t *testing.T) {
+	config := DefaultConfig()
+	config.Senders[models.ContactTypePhone] = "fax"
+
+	senders := NewSenders(config, nil, zap.NewNop())
+	assert.IsType(t, &LogSender{}, senders[models.ContactTypeMobile], "without an SMS service mobile OTPs are logged")
+	assert.IsType(t, &EmailSender{}, senders[models.ContactTypeEmail])
+	assert.NotContains(t, senders, models.ContactTypePhone, "unknown senders are skipped")
+}
+
+func TestMaskDestination(t *testing.T) {
+	assert.Equal(t, "a***@example.com", MaskDestination("alice@example.com"))
+	assert.NotContains(t, MaskDestination("+919876543210"), "98765")
+}
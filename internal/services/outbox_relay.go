@@ -0,0 +1,214 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"go.uber.org/zap"
+)
+
+// OutboxRelayConfig tunes how the outbox relay polls for and retries events
+type OutboxRelayConfig struct {
+	// PollInterval is the wait between polls once the outbox is drained
+	PollInterval time.Duration
+	// BatchSize is the number of events claimed per poll
+	BatchSize int
+	// Lease is how long a claimed event is reserved for this relay
+	Lease time.Duration
+	// MaxAttempts is the number of publish attempts before an event is marked failed
+	MaxAttempts int
+	// RetryBackoff is the wait after the first failed attempt; it doubles with each further attempt
+	// up to MaxRetryBackoff
+	RetryBackoff    time.Duration
+	MaxRetryBackoff time.Duration
+}
+
+// DefaultOutboxRelayConfig returns the relay settings used when none are configured
+func DefaultOutboxRelayConfig() OutboxRelayConfig {
+	return OutboxRelayConfig{
+		PollInterval:    time.Second,
+		BatchSize:       100,
+		Lease:           time.Minute,
+		MaxAttempts:     20,
+		RetryBackoff:    5 * time.Second,
+		MaxRetryBackoff: time.Hour,
+	}
+}
+
+// retryDelay returns the wait before the next attempt of an event that failed attempts times
+func (c OutboxRelayConfig) retryDelay(attempts int) time.Duration {
+	delay := c.RetryBackoff
+	for i := 1; i < attempts && delay < c.MaxRetryBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, c.MaxRetryBackoff)
+}
+
+// OutboxRelay publishes the events written to the transactional outbox. An event is marked sent
+// only after the publisher accepts it, so a crash between publish and mark republishes it:
+// consumers must tolerate duplicates, identified by the event ID.
+type OutboxRelay struct {
+	repo      interfaces.OutboxRepository
+	publisher interfaces.EventPublisher
+	config    OutboxRelayConfig
+	logger    *zap.Logger
+	now       func() time.Time
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewOutboxRelay creates a relay publishing the events of repo with publisher. Call Start to run it
+// in the background.
+func NewOutboxRelay(repo interfaces.OutboxRepository, publisher interfaces.EventPublisher, config OutboxRelayConfig, logger *zap.Logger) *OutboxRelay {
+	defaults := DefaultOutboxRelayConfig()
+	if config.PollInterval <= 0 {
+		config.PollInterval = defaults.PollInterval
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = defaults.BatchSize
+	}
+	if config.Lease <= 0 {
+		config.Lease = defaults.Lease
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = defaults.MaxAttempts
+	}
+	if config.RetryBackoff <= 0 {
+		config.RetryBackoff = defaults.RetryBackoff
+	}
+	if config.MaxRetryBackoff < config.RetryBackoff {
+		config.MaxRetryBackoff = max(defaults.MaxRetryBackoff, config.RetryBackoff)
+	}
+
+	return &OutboxRelay{
+		repo:      repo,
+		publisher: publisher,
+		config:    config,
+		logger:    logger.Named("outbox_relay"),
+		now:       time.Now,
+	}
+}
+
+// Start relays events in the background until Stop is called or ctx is done
+func (r *OutboxRelay) Start(ctx context.Context) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.cancel != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	r.cancel = cancel
+	r.done = make(chan struct{})
+
+	go func(done chan struct{}) {
+		defer close(done)
+		r.run(ctx)
+	}(r.done)
+
+	r.logger.Info("Outbox relay started",
+		zap.Duration("poll_interval", r.config.PollInterval),
+		zap.Int("batch_size", r.config.BatchSize))
+}
+
+// Stop stops the relay and waits for the batch in flight to finish
+func (r *OutboxRelay) Stop() {
+	r.mu.Lock()
+	cancel, done := r.cancel, r.done
+	r.cancel, r.done = nil, nil
+	r.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+	cancel()
+	<-done
+	r.logger.Info("Outbox relay stopped")
+}
+
+func (r *OutboxRelay) run(ctx context.Context) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		claimed, err := r.RelayOnce(ctx)
+		if err != nil {
+			r.logger.Error("Failed to relay outbox events", zap.Error(err))
+		}
+
+		// Keep draining while batches come back full
+		wait := r.config.PollInterval
+		if err == nil && claimed == r.config.BatchSize {
+			wait = 0
+		}
+		timer.Reset(wait)
+	}
+}
+
+// RelayOnce claims one batch of due events and publishes them. It returns the number of events
+// claimed. A failed publish is rescheduled with backoff, or marked failed once the event has used
+// up its attempts; either way the event stays in the outbox.
+func (r *OutboxRelay) RelayOnce(ctx context.Context) (int, error) {
+	events, err := r.repo.ClaimDue(ctx, r.config.BatchSize, r.config.Lease)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, event := range events {
+		if ctx.Err() != nil {
+			// Unpublished events are claimed again once their lease runs out
+			return len(events), ctx.Err()
+		}
+
+		if err := r.publisher.Publish(ctx, event); err != nil {
+			r.recordFailure(ctx, event.ID, event.Topic, event.Attempts+1, err)
+			continue
+		}
+
+		if err := r.repo.MarkSent(ctx, event.ID, r.now()); err != nil {
+			// The event is published again after its lease runs out
+			r.logger.Error("Failed to mark outbox event sent",
+				zap.String("event_id", event.ID),
+				zap.String("topic", event.Topic),
+				zap.Error(err))
+		}
+	}
+	return len(events), nil
+}
+
+func (r *OutboxRelay) recordFailure(ctx context.Context, eventID, topic string, attempts int, publishErr error) {
+	var err error
+	if attempts >= r.config.MaxAttempts {
+		r.logger.Error("Outbox event failed permanently",
+			zap.String("event_id", eventID),
+			zap.String("topic", topic),
+			zap.Int("attempts", attempts),
+			zap.Error(publishErr))
+		err = r.repo.MarkFailed(ctx, eventID, attempts, publishErr.Error())
+	} else {
+		nextAttemptAt := r.now().Add(r.config.retryDelay(attempts))
+		r.logger.Warn("Failed to publish outbox event, will retry",
+			zap.String("event_id", eventID),
+			zap.String("topic", topic),
+			zap.Int("attempts", attempts),
+			zap.Time("next_attempt_at", nextAttemptAt),
+			zap.Error(publishErr))
+		err = r.repo.MarkRetry(ctx, eventID, attempts, nextAttemptAt, publishErr.Error())
+	}
+	if err != nil {
+		r.logger.Error("Failed to record outbox publish failure",
+			zap.String("event_id", eventID),
+			zap.Error(err))
+	}
+}
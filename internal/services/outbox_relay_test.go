@@ -0,0 +1,170 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// outboxTestRepo keeps outbox events in memory, claiming the due ones like the database does
+type outboxTestRepo struct {
+	events []*models.OutboxEvent
+	now    time.Time
+}
+
+func (r *outboxTestRepo) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*models.OutboxEvent, error) {
+	var claimed []*models.OutboxEvent
+	for _, event := range r.events {
+		if len(claimed) == limit {
+			break
+		}
+		if event.Status != models.OutboxStatusPending || event.NextAttemptAt.After(r.now) {
+			continue
+		}
+		if event.LockedUntil != nil && event.LockedUntil.After(r.now) {
+			continue
+		}
+		lockedUntil := r.now.Add(lease)
+		event.LockedUntil = &lockedUntil
+		claimed = append(claimed, event)
+	}
+	return claimed, nil
+}
+
+func (r *outboxTestRepo) find(id string) *models.OutboxEvent {
+	for _, event := range r.events {
+		if event.ID == id {
+			return event
+		}
+	}
+	return nil
+}
+
+func (r *outboxTestRepo) MarkSent(ctx context.Context, id string, sentAt time.Time) error {
+	event := r.find(id)
+	event.Status = models.OutboxStatusSent
+	event.SentAt = &sentAt
+	event.LockedUntil = nil
+	return nil
+}
+
+func (r *outboxTestRepo) MarkRetry(ctx context.Context, id string, attempts int, nextAttemptAt time.Time, lastError string) error {
+	event := r.find(id)
+	event.Attempts = attempts
+	event.NextAttemptAt = nextAttemptAt
+	event.LockedUntil = nil
+	event.LastError = &lastError
+	return nil
+}
+
+func (r *outboxTestRepo) MarkFailed(ctx context.Context, id string, attempts int, lastError string) error {
+	event := r.find(id)
+	event.Status = models.OutboxStatusFailed
+	event.Attempts = attempts
+	event.LockedUntil = nil
+	event.LastError = &lastError
+	return nil
+}
+
+// outboxTestPublisher records published events and fails while err is set
+type outboxTestPublisher struct {
+	published []string
+	err       error
+}
+
+func (p *outboxTestPublisher) Publish(ctx context.Context, event *models.OutboxEvent) error {
+	if p.err != nil {
+		return p.err
+	}
+	p.published = append(p.published, event.ID)
+	return nil
+}
+
+func newOutboxTestRelay(t *testing.T, maxAttempts int) (*OutboxRelay, *outboxTestRepo, *outboxTestPublisher) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	event, err := models.NewOutboxEvent("user.created", "user", "USER1", map[string]string{"username": "asha"})
+	require.NoError(t, err)
+	event.ID = "OUTB1"
+	event.NextAttemptAt = now
+
+	repo := &outboxTestRepo{events: []*models.OutboxEvent{event}, now: now}
+	publisher := &outboxTestPublisher{}
+	relay := NewOutboxRelay(repo, publisher, OutboxRelayConfig{
+		MaxAttempts:     maxAttempts,
+		RetryBackoff:    time.Second,
+		MaxRetryBackoff: time.Minute,
+	}, zap.NewNop())
+	relay.now = func() time.Time { return repo.now }
+	return relay, repo, publisher
+}
+
+func TestOutboxRelay_PublishesAndMarksSent(t *testing.T) {
+	relay, repo, publisher := newOutboxTestRelay(t, 3)
+
+	claimed, err := relay.RelayOnce(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, claimed)
+	assert.Equal(t, []string{"OUTB1"}, publisher.published)
+	assert.Equal(t, models.OutboxStatusSent, repo.events[0].Status)
+
+	claimed, err = relay.RelayOnce(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, claimed, "sent events are not published again")
+}
+
+func TestOutboxRelay_PublishFailureLeavesEventForRetry(t *testing.T) {
+	relay, repo, publisher := newOutboxTestRelay(t, 3)
+	publisher.err = fmt.Errorf("broker unavailable")
+
+	_, err := relay.RelayOnce(context.Background())
+	require.NoError(t, err)
+
+	event := repo.events[0]
+	assert.Equal(t, models.OutboxStatusPending, event.Status)
+	assert.Equal(t, 1, event.Attempts)
+	assert.Equal(t, repo.now.Add(time.Second), event.NextAttemptAt)
+	assert.Nil(t, event.LockedUntil)
+	require.NotNil(t, event.LastError)
+	assert.Equal(t, "broker unavailable", *event.LastError)
+
+	// Not due again until the backoff has passed
+	claimed, err := relay.RelayOnce(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, claimed)
+
+	publisher.err = nil
+	repo.now = repo.now.Add(time.Second)
+	_, err = relay.RelayOnce(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"OUTB1"}, publisher.published)
+	assert.Equal(t, models.OutboxStatusSent, event.Status)
+}
+
+func TestOutboxRelay_MarksFailedAfterMaxAttempts(t *testing.T) {
+	relay, repo, publisher := newOutboxTestRelay(t, 2)
+	publisher.err = fmt.Errorf("broker unavailable")
+
+	for i := 0; i < 2; i++ {
+		_, err := relay.RelayOnce(context.Background())
+		require.NoError(t, err)
+		repo.now = repo.now.Add(time.Minute)
+	}
+
+	assert.Equal(t, models.OutboxStatusFailed, repo.events[0].Status)
+	assert.Equal(t, 2, repo.events[0].Attempts)
+}
+
+func TestOutboxRelayConfig_RetryDelay(t *testing.T) {
+	config := OutboxRelayConfig{RetryBackoff: time.Second, MaxRetryBackoff: 5 * time.Second}
+
+	assert.Equal(t, time.Second, config.retryDelay(1))
+	assert.Equal(t, 2*time.Second, config.retryDelay(2))
+	assert.Equal(t, 4*time.Second, config.retryDelay(3))
+	assert.Equal(t, 5*time.Second, config.retryDelay(10))
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+)
+
+const defaultWebhookPublisherTimeout = 10 * time.Second
+
+// webhookEventEnvelope is the JSON body POSTed for each outbox event
+type webhookEventEnvelope struct {
+	ID            string          `json:"id"`
+	Topic         string          `json:"topic"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   string          `json:"aggregate_id"`
+	Payload       json.RawMessage `json:"payload"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+}
+
+// WebhookEventPublisher publishes outbox events by POSTing them to a webhook. Any non-2xx response
+// is a failure, so the relay retries the event later. Receivers deduplicate on the X-Event-ID header.
+type WebhookEventPublisher struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookEventPublisher creates a publisher POSTing events to url
+func NewWebhookEventPublisher(url string, timeout time.Duration) *WebhookEventPublisher {
+	if timeout <= 0 {
+		timeout = defaultWebhookPublisherTimeout
+	}
+	return &WebhookEventPublisher{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// Publish POSTs event to the webhook
+func (p *WebhookEventPublisher) Publish(ctx context.Context, event *models.OutboxEvent) error {
+	body, err := json.Marshal(webhookEventEnvelope{
+		ID:            event.ID,
+		Topic:         event.Topic,
+		AggregateType: event.AggregateType,
+		AggregateID:   event.AggregateID,
+		Payload:       json.RawMessage(event.Payload),
+		OccurredAt:    event.CreatedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Event-ID", event.ID)
+	req.Header.Set("X-Event-Topic", event.Topic)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
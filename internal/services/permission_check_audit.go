@@ -0,0 +1,78 @@
+package services
+
+import (
+	"context"
+	"math/rand/v2"
+	"slices"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+)
+
+// PermissionCheckAuditConfig configures verbose authorization auditing: a check_permission audit
+// log for every permission check, allowed or denied, recording the decision and the rule and role
+// that decided it. Denials are audited as access_denied whether or not it is enabled.
+type PermissionCheckAuditConfig struct {
+	Enabled bool
+	// ResourceTypes limits the auditing to these resource types; empty audits every resource type
+	ResourceTypes []string
+	// SamplePercent is the percentage of matching checks that are audited, from 1 to 100
+	SamplePercent int
+}
+
+// SetPermissionCheckAudit configures verbose auditing of permission checks; it is off by default
+func (s *AuthorizationService) SetPermissionCheckAudit(config PermissionCheckAuditConfig) {
+	s.postgresAuth.SetPermissionCheckAudit(config)
+}
+
+// SetPermissionCheckAudit configures verbose auditing of permission checks; it is off by default
+func (s *PostgresAuthorizationService) SetPermissionCheckAudit(config PermissionCheckAuditConfig) {
+	if config.SamplePercent <= 0 || config.SamplePercent > 100 {
+		config.SamplePercent = 100
+	}
+	s.checkAudit = config
+}
+
+// auditCheck records a check_permission audit log when verbose auditing covers the check
+func (s *PostgresAuthorizationService) auditCheck(ctx context.Context, perm *Permission, result *PermissionResult, cached bool) {
+	if s.auditService == nil || !s.shouldAuditCheck(perm) {
+		return
+	}
+
+	details := map[string]interface{}{
+		"allowed":          result.Allowed,
+		"reason":           result.Reason,
+		"attempted_action": perm.Action,
+		"cached":           cached,
+		"sample_percent":   s.checkAudit.SamplePercent,
+	}
+	if result.Rule != "" {
+		details["rule"] = result.Rule
+	}
+	if result.RoleName != "" {
+		details["role_name"] = result.RoleName
+	}
+	if perm.OrganizationID != "" {
+		details["organization_id"] = perm.OrganizationID
+	}
+
+	s.auditService.LogUserAction(ctx, perm.UserID, models.AuditActionCheckPermission, perm.Resource, perm.ResourceID, details)
+}
+
+func (s *PostgresAuthorizationService) shouldAuditCheck(perm *Permission) bool {
+	config := s.checkAudit
+	if !config.Enabled {
+		return false
+	}
+	if len(config.ResourceTypes) > 0 && !slices.Contains(config.ResourceTypes, perm.Resource) {
+		return false
+	}
+	if config.SamplePercent >= 100 {
+		return true
+	}
+
+	sample := s.checkAuditSample
+	if sample == nil {
+		sample = rand.IntN
+	}
+	return sample(100) < config.SamplePercent
+}
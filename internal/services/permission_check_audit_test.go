@@ -0,0 +1,126 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// newCheckAuditTestService returns an authorization service deciding checks from cached results,
+// so no database is needed
+func newCheckAuditTestService(results map[string]*PermissionResult) (*PostgresAuthorizationService, *impersonationAuditRepo) {
+	cache := &memoryCache{values: map[string]interface{}{}}
+	for key, result := range results {
+		cache.values[key] = result
+	}
+	auditRepo := &impersonationAuditRepo{}
+	auditService := NewAuditService(nil, auditRepo, nil, zap.NewNop())
+	return NewPostgresAuthorizationService(nil, cache, auditService, zap.NewNop()), auditRepo
+}
+
+func checkAuditResults() map[string]*PermissionResult {
+	return map[string]*PermissionResult{
+		"permission:USER1:farm:FARM1:read": {
+			Allowed:  true,
+			Reason:   "Permission granted through role: farmer",
+			Rule:     DecisionRuleRolePermission,
+			RoleName: "farmer",
+		},
+		"permission:USER1:farm:FARM1:delete": {Reason: "No matching permissions found"},
+		"permission:USER1:user:USER2:read":   {Allowed: true, Reason: "Permission granted through admin role: admin", Rule: DecisionRuleAdminRole, RoleName: "admin"},
+	}
+}
+
+func auditedActions(repo *impersonationAuditRepo) []string {
+	var actions []string
+	for _, log := range repo.logs {
+		actions = append(actions, log.Action)
+	}
+	return actions
+}
+
+func TestPermissionCheckAudit_OffByDefault(t *testing.T) {
+	ctx := context.Background()
+	authz, auditRepo := newCheckAuditTestService(checkAuditResults())
+
+	result, err := authz.CheckPermission(ctx, &Permission{UserID: "USER1", Resource: "farm", ResourceID: "FARM1", Action: "read"})
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+	_, err = authz.CheckPermission(ctx, &Permission{UserID: "USER1", Resource: "farm", ResourceID: "FARM1", Action: "delete"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{models.AuditActionAccessDenied}, auditedActions(auditRepo), "only denials are audited")
+}
+
+func TestPermissionCheckAudit_RecordsEveryCheck(t *testing.T) {
+	ctx := context.Background()
+	authz, auditRepo := newCheckAuditTestService(checkAuditResults())
+	authz.SetPermissionCheckAudit(PermissionCheckAuditConfig{Enabled: true})
+
+	_, err := authz.CheckPermission(ctx, &Permission{UserID: "USER1", Resource: "farm", ResourceID: "FARM1", Action: "read"})
+	require.NoError(t, err)
+	_, err = authz.CheckPermission(ctx, &Permission{UserID: "USER1", Resource: "farm", ResourceID: "FARM1", Action: "delete"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		models.AuditActionCheckPermission,
+		models.AuditActionAccessDenied,
+		models.AuditActionCheckPermission,
+	}, auditedActions(auditRepo))
+
+	granted := auditRepo.logs[0]
+	require.NotNil(t, granted.UserID)
+	assert.Equal(t, "USER1", *granted.UserID)
+	assert.Equal(t, "farm", granted.ResourceType)
+	assert.Equal(t, "FARM1", *granted.ResourceID)
+	assert.Equal(t, true, granted.Details["allowed"])
+	assert.Equal(t, DecisionRuleRolePermission, granted.Details["rule"])
+	assert.Equal(t, "farmer", granted.Details["role_name"])
+	assert.Equal(t, "read", granted.Details["attempted_action"])
+
+	denied := auditRepo.logs[2]
+	assert.Equal(t, false, denied.Details["allowed"])
+	assert.Equal(t, "No matching permissions found", denied.Details["reason"])
+	assert.NotContains(t, denied.Details, "rule", "nothing decided a denial without a matching rule")
+}
+
+func TestPermissionCheckAudit_LimitedToResourceTypes(t *testing.T) {
+	ctx := context.Background()
+	authz, auditRepo := newCheckAuditTestService(checkAuditResults())
+	authz.SetPermissionCheckAudit(PermissionCheckAuditConfig{Enabled: true, ResourceTypes: []string{"user"}})
+
+	_, err := authz.CheckPermission(ctx, &Permission{UserID: "USER1", Resource: "farm", ResourceID: "FARM1", Action: "read"})
+	require.NoError(t, err)
+	_, err = authz.CheckPermission(ctx, &Permission{UserID: "USER1", Resource: "user", ResourceID: "USER2", Action: "read"})
+	require.NoError(t, err)
+
+	require.Equal(t, []string{models.AuditActionCheckPermission}, auditedActions(auditRepo))
+	assert.Equal(t, "user", auditRepo.logs[0].ResourceType)
+	assert.Equal(t, DecisionRuleAdminRole, auditRepo.logs[0].Details["rule"])
+}
+
+func TestPermissionCheckAudit_Sampled(t *testing.T) {
+	ctx := context.Background()
+	authz, auditRepo := newCheckAuditTestService(checkAuditResults())
+	authz.SetPermissionCheckAudit(PermissionCheckAuditConfig{Enabled: true, SamplePercent: 25})
+
+	draws := []int{10, 60, 24, 25}
+	authz.checkAuditSample = func(n int) int {
+		draw := draws[0]
+		draws = draws[1:]
+		return draw
+	}
+
+	perm := &Permission{UserID: "USER1", Resource: "farm", ResourceID: "FARM1", Action: "read"}
+	for range 4 {
+		_, err := authz.CheckPermission(ctx, perm)
+		require.NoError(t, err)
+	}
+
+	require.Len(t, auditRepo.logs, 2, "checks drawn below the sample percentage are audited")
+	assert.Equal(t, 25, auditRepo.logs[0].Details["sample_percent"])
+}
@@ -0,0 +1,164 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func grant(roleID, resourceID, action, effect string) ResourceGrant {
+	return ResourceGrant{RoleID: roleID, ResourceType: "organization", ResourceID: resourceID, Action: action, Effect: effect}
+}
+
+func TestMatchResourceGrants_Precedence(t *testing.T) {
+	tests := []struct {
+		name        string
+		grants      []ResourceGrant
+		resourceID  string
+		wantAllowed bool
+		wantKind    string
+		wantPattern string
+	}{
+		{
+			name:        "exact beats full wildcard",
+			grants:      []ResourceGrant{grant("R1", "*", "read", GrantEffectAllow), grant("R2", "ORG1", "read", GrantEffectDeny)},
+			resourceID:  "ORG1",
+			wantAllowed: false,
+			wantKind:    GrantMatchExact,
+			wantPattern: "ORG1",
+		},
+		{
+			name:        "exact allow carves an exception out of a wildcard deny",
+			grants:      []ResourceGrant{grant("R1", "*", "read", GrantEffectDeny), grant("R2", "ORG1", "read", GrantEffectAllow)},
+			resourceID:  "ORG1",
+			wantAllowed: true,
+			wantKind:    GrantMatchExact,
+			wantPattern: "ORG1",
+		},
+		{
+			name:        "prefix beats full wildcard",
+			grants:      []ResourceGrant{grant("R1", "*", "read", GrantEffectAllow), grant("R2", "ORG*", "read", GrantEffectDeny)},
+			resourceID:  "ORG1",
+			wantAllowed: false,
+			wantKind:    GrantMatchPrefixWildcard,
+			wantPattern: "ORG*",
+		},
+		{
+			name:        "exact beats prefix",
+			grants:      []ResourceGrant{grant("R1", "ORG*", "read", GrantEffectDeny), grant("R2", "ORG1", "read", GrantEffectAllow)},
+			resourceID:  "ORG1",
+			wantAllowed: true,
+			wantKind:    GrantMatchExact,
+			wantPattern: "ORG1",
+		},
+		{
+			name:        "longer prefix beats shorter prefix",
+			grants:      []ResourceGrant{grant("R1", "OR*", "read", GrantEffectDeny), grant("R2", "ORG_FPO*", "read", GrantEffectAllow)},
+			resourceID:  "ORG_FPO_1",
+			wantAllowed: true,
+			wantKind:    GrantMatchPrefixWildcard,
+			wantPattern: "ORG_FPO*",
+		},
+		{
+			name:        "exact action beats wildcard action on the same pattern",
+			grants:      []ResourceGrant{grant("R1", "*", "*", GrantEffectDeny), grant("R2", "*", "read", GrantEffectAllow)},
+			resourceID:  "ORG1",
+			wantAllowed: true,
+			wantKind:    GrantMatchFullWildcard,
+			wantPattern: "*",
+		},
+		{
+			name:        "resource specificity outranks action specificity",
+			grants:      []ResourceGrant{grant("R1", "*", "read", GrantEffectAllow), grant("R2", "ORG1", "*", GrantEffectDeny)},
+			resourceID:  "ORG1",
+			wantAllowed: false,
+			wantKind:    GrantMatchExact,
+			wantPattern: "ORG1",
+		},
+		{
+			name:        "deny wins over allow at equal specificity",
+			grants:      []ResourceGrant{grant("R1", "ORG1", "read", GrantEffectAllow), grant("R2", "ORG1", "read", GrantEffectDeny)},
+			resourceID:  "ORG1",
+			wantAllowed: false,
+			wantKind:    GrantMatchExact,
+			wantPattern: "ORG1",
+		},
+		{
+			name:        "grants without an effect allow",
+			grants:      []ResourceGrant{grant("R1", "*", "read", "")},
+			resourceID:  "ORG1",
+			wantAllowed: true,
+			wantKind:    GrantMatchFullWildcard,
+			wantPattern: "*",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			winner, matches := MatchResourceGrants(tt.grants, "organization", tt.resourceID, "read")
+			require.NotNil(t, winner)
+			assert.Len(t, matches, len(tt.grants))
+			assert.Equal(t, tt.wantAllowed, winner.Grant.Effect == GrantEffectAllow)
+			assert.Equal(t, tt.wantKind, winner.MatchKind)
+			assert.Equal(t, tt.wantPattern, winner.Grant.ResourceID)
+			assert.Equal(t, *winner, matches[0], "the winner leads the candidates")
+		})
+	}
+}
+
+func TestMatchResourceGrants_OrderIndependent(t *testing.T) {
+	grants := []ResourceGrant{
+		grant("R1", "*", "read", GrantEffectAllow),
+		grant("R2", "ORG*", "read", GrantEffectAllow),
+		grant("R3", "ORG1", "read", GrantEffectDeny),
+		grant("R4", "ORG1", "read", GrantEffectAllow),
+	}
+	reversed := []ResourceGrant{grants[3], grants[2], grants[1], grants[0]}
+
+	winner, matches := MatchResourceGrants(grants, "organization", "ORG1", "read")
+	winnerReversed, matchesReversed := MatchResourceGrants(reversed, "organization", "ORG1", "read")
+
+	require.NotNil(t, winner)
+	assert.Equal(t, "R3", winner.Grant.RoleID)
+	assert.Equal(t, winner, winnerReversed)
+	assert.Equal(t, matches, matchesReversed)
+}
+
+func TestMatchResourceGrants_NoMatch(t *testing.T) {
+	grants := []ResourceGrant{
+		grant("R1", "ORG2", "read", GrantEffectAllow),
+		grant("R2", "GRP*", "read", GrantEffectAllow),
+		grant("R3", "*", "delete", GrantEffectAllow),
+		{RoleID: "R4", ResourceType: "user", ResourceID: "*", Action: "read", Effect: GrantEffectAllow},
+	}
+
+	winner, matches := MatchResourceGrants(grants, "organization", "ORG1", "read")
+	assert.Nil(t, winner)
+	assert.Empty(t, matches)
+}
+
+func TestMatchResourceGrants_TypeLevelCheck(t *testing.T) {
+	grants := []ResourceGrant{grant("R1", "ORG1", "read", GrantEffectAllow)}
+
+	winner, _ := MatchResourceGrants(grants, "organization", "", "read")
+	require.NotNil(t, winner, "an empty resource ID is satisfied by any grant on the type")
+	assert.Equal(t, GrantMatchExact, winner.MatchKind)
+}
+
+func TestRolesInOrganization(t *testing.T) {
+	org1, org2 := "ORG1", "ORG2"
+	roles := []models.Role{
+		{Name: "super_admin", Scope: models.RoleScopeGlobal},
+		{Name: "org1_admin", Scope: models.RoleScopeOrg, OrganizationID: &org1},
+		{Name: "org2_admin", Scope: models.RoleScopeOrg, OrganizationID: &org2},
+		{Name: "unbound", Scope: models.RoleScopeOrg},
+	}
+
+	var names []string
+	for _, role := range rolesInOrganization(roles, org1) {
+		names = append(names, role.Name)
+	}
+	assert.Equal(t, []string{"super_admin", "org1_admin", "unbound"}, names)
+}
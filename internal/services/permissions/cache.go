@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
 	"go.uber.org/zap"
 )
 
@@ -41,6 +42,8 @@ func (s *Service) InvalidateUserCache(ctx context.Context, userID string) error
 		}
 	}
 
+	s.broadcastInvalidation(ctx, nil, patterns)
+
 	s.logger.Info("User cache invalidated",
 		zap.String("user_id", userID))
 
@@ -80,6 +83,8 @@ func (s *Service) InvalidateRoleCache(ctx context.Context, roleID string) error
 		}
 	}
 
+	s.broadcastInvalidation(ctx, nil, patterns)
+
 	s.logger.Info("Role cache invalidated",
 		zap.String("role_id", roleID))
 
@@ -118,7 +123,9 @@ func (s *Service) InvalidatePermissionCache(ctx context.Context, permissionID st
 				zap.String("key", nameKey),
 				zap.Error(err))
 		}
+		cacheKeys = append(cacheKeys, nameKey)
 	}
+	s.broadcastInvalidation(ctx, cacheKeys, nil)
 
 	// Invalidate all role caches that use this permission
 	s.invalidateAllRoleCaches(ctx, permissionID)
@@ -162,6 +169,8 @@ func (s *Service) InvalidateAllPermissionCaches(ctx context.Context) error {
 		}
 	}
 
+	s.broadcastInvalidation(ctx, nil, patterns)
+
 	s.logger.Info("All permission caches invalidated",
 		zap.Int("total_deleted", totalDeleted))
 
@@ -178,6 +187,8 @@ func (s *Service) InvalidateCacheByPattern(ctx context.Context, pattern string)
 		return nil
 	}
 
+	s.broadcastInvalidation(ctx, nil, []string{pattern})
+
 	keys, err := s.cache.Keys(pattern)
 	if err != nil {
 		s.logger.Warn("Failed to get cache keys",
@@ -204,6 +215,21 @@ func (s *Service) InvalidateCacheByPattern(ctx context.Context, pattern string)
 	return nil
 }
 
+// broadcastInvalidation repeats an invalidation already applied here on the other service instances
+func (s *Service) broadcastInvalidation(ctx context.Context, keys, patterns []string) {
+	if s.broadcaster == nil {
+		return
+	}
+
+	invalidation := interfaces.CacheInvalidation{Keys: keys, Patterns: patterns}
+	if err := s.broadcaster.Publish(ctx, invalidation); err != nil {
+		s.logger.Warn("Failed to broadcast cache invalidation",
+			zap.Strings("keys", keys),
+			zap.Strings("patterns", patterns),
+			zap.Error(err))
+	}
+}
+
 // WarmupCache pre-loads frequently accessed data into cache
 func (s *Service) WarmupCache(ctx context.Context) error {
 	if s.cache == nil {
@@ -42,7 +42,7 @@ func (s *Service) CreatePermission(ctx context.Context, permission *models.Permi
 	// Verify resource exists if provided
 	if permission.ResourceID != nil && *permission.ResourceID != "" {
 		if err := s.validateResource(ctx, *permission.ResourceID); err != nil {
-			return fmt.Errorf("invalid resource: %w", err)
+			return err
 		}
 	}
 
@@ -135,13 +135,10 @@ func isValidPermissionName(name string) bool {
 	return true
 }
 
-// validateResource checks if a resource exists
+// validateResource checks that a resource exists. Dangling references are only rejected in strict
+// mode, see SetResourceValidator
 func (s *Service) validateResource(ctx context.Context, resourceID string) error {
-	// Note: This assumes we have access to resource repository
-	// For now, we'll skip actual validation and log a warning
-	s.logger.Debug("Resource validation skipped (implement when resource service is available)",
-		zap.String("resource_id", resourceID))
-	return nil
+	return s.checkResourceReference(ctx, "", resourceID)
 }
 
 // validateAction checks if an action exists
@@ -174,6 +171,8 @@ func (s *Service) invalidatePermissionRelatedCaches(ctx context.Context, permiss
 			zap.String("cache_key", nameCacheKey),
 			zap.Error(err))
 	}
+
+	s.broadcastInvalidation(ctx, []string{cacheKey, nameCacheKey}, nil)
 }
 
 // isNotFoundError checks if an error is a "not found" error
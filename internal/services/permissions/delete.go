@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
 	"go.uber.org/zap"
 )
 
@@ -96,6 +97,12 @@ func (s *Service) DeletePermissionWithCascade(ctx context.Context, id string) er
 		return fmt.Errorf("failed to get role assignments: %w", err)
 	}
 
+	// Extract user ID for audit trail
+	cascadeDeletedBy := "system"
+	if ctxUserID, ok := ctx.Value("user_id").(string); ok && ctxUserID != "" {
+		cascadeDeletedBy = ctxUserID
+	}
+
 	// Delete all role-permission assignments
 	for _, rp := range rolePerms {
 		if err := s.rolePermissionRepo.Revoke(ctx, rp.RoleID, id); err != nil {
@@ -104,13 +111,18 @@ func (s *Service) DeletePermissionWithCascade(ctx context.Context, id string) er
 				zap.String("permission_id", id),
 				zap.Error(err))
 			// Continue with other deletions even if one fails
+			continue
 		}
-	}
 
-	// Extract user ID for audit trail
-	cascadeDeletedBy := "system"
-	if ctxUserID, ok := ctx.Value("user_id").(string); ok && ctxUserID != "" {
-		cascadeDeletedBy = ctxUserID
+		// Each revocation is part of the role's permission history
+		if s.audit != nil {
+			s.audit.LogPermissionChange(ctx, cascadeDeletedBy, "revoke", models.ResourceTypeRole, rp.RoleID, permission.Name,
+				map[string]interface{}{
+					"role_id":       rp.RoleID,
+					"permission_id": id,
+					"operation":     "cascade_delete",
+				})
+		}
 	}
 
 	// Delete the permission
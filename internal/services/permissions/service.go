@@ -19,6 +19,9 @@ type Service struct {
 	resourcePermissionRepo *resource_permissions.ResourcePermissionRepository
 	roleRepo               *roles.RoleRepository
 	cache                  interfaces.CacheService
+	broadcaster            interfaces.InvalidationBroadcaster
+	resourceValidator      interfaces.ResourceReferenceValidator
+	strictResources        bool
 	audit                  interfaces.AuditService
 	logger                 interfaces.Logger
 }
@@ -44,6 +47,12 @@ func NewService(
 	}
 }
 
+// SetInvalidationBroadcaster sets the broadcaster that repeats cache invalidations on the other
+// service instances
+func (s *Service) SetInvalidationBroadcaster(broadcaster interfaces.InvalidationBroadcaster) {
+	s.broadcaster = broadcaster
+}
+
 // EvaluationContext contains contextual information for permission evaluation
 type EvaluationContext struct {
 	OrganizationID string
@@ -95,4 +104,7 @@ type ServiceInterface interface {
 	InvalidateUserCache(ctx context.Context, userID string) error
 	InvalidateRoleCache(ctx context.Context, roleID string) error
 	InvalidatePermissionCache(ctx context.Context, permissionID string) error
+
+	// Maintenance operations
+	ValidatePermissions(ctx context.Context) (*PermissionValidationReport, error)
 }
@@ -81,7 +81,7 @@ func (s *Service) UpdatePermission(ctx context.Context, permission *models.Permi
 	if permission.ResourceID != nil && *permission.ResourceID != "" {
 		if existing.ResourceID == nil || *existing.ResourceID != *permission.ResourceID {
 			if err := s.validateResource(ctx, *permission.ResourceID); err != nil {
-				return fmt.Errorf("invalid resource: %w", err)
+				return err
 			}
 		}
 	}
@@ -185,6 +185,7 @@ func (s *Service) invalidateAllRoleCaches(ctx context.Context, permissionID stri
 	}
 
 	// Invalidate cache for each role
+	cacheKeys := make([]string, 0, len(rolePerms))
 	for _, rp := range rolePerms {
 		cacheKey := fmt.Sprintf("role:%s:permissions", rp.RoleID)
 		if err := s.cache.Delete(cacheKey); err != nil {
@@ -192,5 +193,7 @@ func (s *Service) invalidateAllRoleCaches(ctx context.Context, permissionID stri
 				zap.String("cache_key", cacheKey),
 				zap.Error(err))
 		}
+		cacheKeys = append(cacheKeys, cacheKey)
 	}
+	s.broadcastInvalidation(ctx, cacheKeys, nil)
 }
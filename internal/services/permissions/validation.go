@@ -0,0 +1,194 @@
+package permissions
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// validationScanPageSize is the number of rows ValidatePermissions loads at a time
+const validationScanPageSize = 500
+
+// Sources of a dangling resource reference
+const (
+	ReferenceSourcePermission         = "permission"
+	ReferenceSourceResourcePermission = "resource_permission"
+)
+
+// DanglingReference is a permission or resource permission whose resource_id does not refer to an
+// existing resource
+type DanglingReference struct {
+	Source       string `json:"source"`
+	ID           string `json:"id"`
+	Name         string `json:"name,omitempty"`
+	RoleID       string `json:"role_id,omitempty"`
+	ResourceType string `json:"resource_type,omitempty"`
+	ResourceID   string `json:"resource_id"`
+	Action       string `json:"action,omitempty"`
+	Reason       string `json:"reason"`
+}
+
+// PermissionValidationReport is the result of a ValidatePermissions scan
+type PermissionValidationReport struct {
+	StrictMode                 bool                `json:"strict_mode"`
+	ScannedPermissions         int                 `json:"scanned_permissions"`
+	ScannedResourcePermissions int                 `json:"scanned_resource_permissions"`
+	Dangling                   []DanglingReference `json:"dangling"`
+	CheckedAt                  time.Time           `json:"checked_at"`
+}
+
+// SetResourceValidator enables resource reference validation. In strict mode permissions that
+// reference a missing resource are rejected; otherwise they are created and the dangling reference
+// is logged, so existing free-form permissions keep working.
+func (s *Service) SetResourceValidator(validator interfaces.ResourceReferenceValidator, strict bool) {
+	s.resourceValidator = validator
+	s.strictResources = strict
+}
+
+// ValidatePermissions scans the active permissions and resource permissions and reports those whose
+// resource_id does not refer to an existing resource. Wildcard resource IDs are never reported.
+func (s *Service) ValidatePermissions(ctx context.Context) (*PermissionValidationReport, error) {
+	if s.resourceValidator == nil {
+		return nil, errors.NewInternalError(fmt.Errorf("resource validation is not configured"))
+	}
+
+	checker := newReferenceChecker(s.resourceValidator)
+	report := &PermissionValidationReport{
+		StrictMode: s.strictResources,
+		Dangling:   []DanglingReference{},
+		CheckedAt:  time.Now(),
+	}
+
+	for offset := 0; ; offset += validationScanPageSize {
+		page, err := s.permissionRepo.GetActive(ctx, validationScanPageSize, offset)
+		if err != nil {
+			return nil, errors.NewInternalError(fmt.Errorf("failed to list permissions: %w", err))
+		}
+		if err := scanPermissions(ctx, checker, page, report); err != nil {
+			return nil, errors.NewInternalError(err)
+		}
+		if len(page) < validationScanPageSize {
+			break
+		}
+	}
+
+	for offset := 0; ; offset += validationScanPageSize {
+		page, err := s.resourcePermissionRepo.ListActive(ctx, validationScanPageSize, offset)
+		if err != nil {
+			return nil, errors.NewInternalError(fmt.Errorf("failed to list resource permissions: %w", err))
+		}
+		if err := scanResourcePermissions(ctx, checker, page, report); err != nil {
+			return nil, errors.NewInternalError(err)
+		}
+		if len(page) < validationScanPageSize {
+			break
+		}
+	}
+
+	s.logger.Info("Permission resource references validated",
+		zap.Int("permissions", report.ScannedPermissions),
+		zap.Int("resource_permissions", report.ScannedResourcePermissions),
+		zap.Int("dangling", len(report.Dangling)))
+
+	return report, nil
+}
+
+// checkResourceReference validates a resource reference before it is persisted. Without a validator
+// every reference is accepted; outside strict mode failures are only logged.
+func (s *Service) checkResourceReference(ctx context.Context, resourceType, resourceID string) error {
+	if s.resourceValidator == nil {
+		return nil
+	}
+
+	err := s.resourceValidator.ValidateResourceReference(ctx, resourceType, resourceID)
+	if err == nil {
+		return nil
+	}
+	if !s.strictResources {
+		s.logger.Warn("Permission references an unknown resource",
+			zap.String("resource_type", resourceType),
+			zap.String("resource_id", resourceID),
+			zap.Error(err))
+		return nil
+	}
+	return err
+}
+
+func scanPermissions(ctx context.Context, checker *referenceChecker, page []*models.Permission, report *PermissionValidationReport) error {
+	for _, permission := range page {
+		report.ScannedPermissions++
+		if permission.ResourceID == nil || *permission.ResourceID == "" {
+			continue
+		}
+		reason, err := checker.check(ctx, "", *permission.ResourceID)
+		if err != nil {
+			return err
+		}
+		if reason != "" {
+			report.Dangling = append(report.Dangling, DanglingReference{
+				Source:     ReferenceSourcePermission,
+				ID:         permission.ID,
+				Name:       permission.Name,
+				ResourceID: *permission.ResourceID,
+				Reason:     reason,
+			})
+		}
+	}
+	return nil
+}
+
+func scanResourcePermissions(ctx context.Context, checker *referenceChecker, page []*models.ResourcePermission, report *PermissionValidationReport) error {
+	for _, permission := range page {
+		report.ScannedResourcePermissions++
+		reason, err := checker.check(ctx, permission.ResourceType, permission.ResourceID)
+		if err != nil {
+			return err
+		}
+		if reason != "" {
+			report.Dangling = append(report.Dangling, DanglingReference{
+				Source:       ReferenceSourceResourcePermission,
+				ID:           permission.ID,
+				RoleID:       permission.RoleID,
+				ResourceType: permission.ResourceType,
+				ResourceID:   permission.ResourceID,
+				Action:       permission.Action,
+				Reason:       reason,
+			})
+		}
+	}
+	return nil
+}
+
+// referenceChecker validates resource references during a scan, looking each one up only once
+type referenceChecker struct {
+	validator interfaces.ResourceReferenceValidator
+	reasons   map[string]string
+}
+
+func newReferenceChecker(validator interfaces.ResourceReferenceValidator) *referenceChecker {
+	return &referenceChecker{validator: validator, reasons: make(map[string]string)}
+}
+
+// check returns why the reference is dangling, or "" when it is valid. Errors other than validation
+// errors abort the scan.
+func (c *referenceChecker) check(ctx context.Context, resourceType, resourceID string) (string, error) {
+	key := resourceType + "\x00" + resourceID
+	if reason, ok := c.reasons[key]; ok {
+		return reason, nil
+	}
+
+	reason := ""
+	if err := c.validator.ValidateResourceReference(ctx, resourceType, resourceID); err != nil {
+		if !errors.IsValidationError(err) {
+			return "", err
+		}
+		reason = err.Error()
+	}
+	c.reasons[key] = reason
+	return reason, nil
+}
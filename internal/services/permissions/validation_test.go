@@ -0,0 +1,90 @@
+package permissions
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/Kisanlink/aaa-service/v2/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// knownResourceValidator accepts the known resource IDs and counts its lookups
+type knownResourceValidator struct {
+	known   map[string]bool
+	lookups int
+	failAll bool
+}
+
+func (v *knownResourceValidator) ValidateResourceReference(ctx context.Context, resourceType, resourceID string) error {
+	v.lookups++
+	if v.failAll {
+		return fmt.Errorf("connection refused")
+	}
+	if resourceID == "*" || v.known[resourceID] {
+		return nil
+	}
+	return errors.NewValidationError("resource does not exist", resourceID)
+}
+
+func TestScanReferences_ReportsDanglingReferences(t *testing.T) {
+	validator := &knownResourceValidator{known: map[string]bool{"RES1": true}}
+	checker := newReferenceChecker(validator)
+	report := &PermissionValidationReport{}
+	ctx := context.Background()
+
+	known, missing := "RES1", "RES404"
+	withResource := models.NewPermission("read_farms", "Read farms")
+	withResource.ResourceID = &known
+	dangling := models.NewPermission("read_crops", "Read crops")
+	dangling.ResourceID = &missing
+	require.NoError(t, scanPermissions(ctx, checker, []*models.Permission{
+		withResource, dangling, models.NewPermission("no_resource", "No resource"),
+	}, report))
+
+	require.NoError(t, scanResourcePermissions(ctx, checker, []*models.ResourcePermission{
+		models.NewResourcePermission("*", "farmers/farm", "ROLE1", "read"),
+		models.NewResourcePermission("RES404", "farmers/farm", "ROLE1", "read"),
+		models.NewResourcePermission("RES404", "farmers/farm", "ROLE2", "update"),
+	}, report))
+
+	assert.Equal(t, 3, report.ScannedPermissions)
+	assert.Equal(t, 3, report.ScannedResourcePermissions)
+	require.Len(t, report.Dangling, 3)
+	assert.Equal(t, ReferenceSourcePermission, report.Dangling[0].Source)
+	assert.Equal(t, "read_crops", report.Dangling[0].Name)
+	assert.Equal(t, ReferenceSourceResourcePermission, report.Dangling[1].Source)
+	assert.Equal(t, "ROLE1", report.Dangling[1].RoleID)
+	assert.Equal(t, "ROLE2", report.Dangling[2].RoleID)
+	assert.Contains(t, report.Dangling[2].Reason, "RES404")
+
+	// RES1 and RES404 without a type, then * and RES404 of farmers/farm
+	assert.Equal(t, 4, validator.lookups)
+}
+
+func TestScanReferences_AbortsOnLookupFailure(t *testing.T) {
+	checker := newReferenceChecker(&knownResourceValidator{failAll: true})
+
+	err := scanResourcePermissions(context.Background(), checker, []*models.ResourcePermission{
+		models.NewResourcePermission("RES1", "farmers/farm", "ROLE1", "read"),
+	}, &PermissionValidationReport{})
+	assert.Error(t, err)
+}
+
+func TestService_CheckResourceReference_StrictMode(t *testing.T) {
+	service := &Service{logger: utils.NewLoggerAdapter(zap.NewNop())}
+	ctx := context.Background()
+
+	assert.NoError(t, service.checkResourceReference(ctx, "", "RES404"), "no validator configured")
+
+	service.SetResourceValidator(&knownResourceValidator{known: map[string]bool{"RES1": true}}, false)
+	assert.NoError(t, service.checkResourceReference(ctx, "", "RES404"), "only logged outside strict mode")
+
+	service.SetResourceValidator(&knownResourceValidator{known: map[string]bool{"RES1": true}}, true)
+	assert.NoError(t, service.checkResourceReference(ctx, "", "RES1"))
+	assert.True(t, errors.IsValidationError(service.checkResourceReference(ctx, "", "RES404")))
+}
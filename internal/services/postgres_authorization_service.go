@@ -3,11 +3,13 @@ package services
 import (
 	"context"
 	"fmt"
+	"slices"
 
 	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
 	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
 	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
 	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
 	"gorm.io/gorm"
 )
 
@@ -17,6 +19,15 @@ type PostgresAuthorizationService struct {
 	cacheService interfaces.CacheService
 	auditService *AuditService
 	logger       *zap.Logger
+	// cacheGuard rejects permission checks while the cache is degraded and fails closed
+	cacheGuard interfaces.CacheDegradation
+	// checkAudit enables verbose auditing of permission checks
+	checkAudit PermissionCheckAuditConfig
+	// checkAuditSample draws the sample of audited checks, rand.IntN unless set by tests
+	checkAuditSample func(n int) int
+
+	// loads shares one database evaluation between concurrent cache misses for the same key
+	loads singleflight.Group
 }
 
 // NewPostgresAuthorizationService creates a new PostgreSQL-based authorization service
@@ -26,28 +37,74 @@ func NewPostgresAuthorizationService(
 	auditService *AuditService,
 	logger *zap.Logger,
 ) *PostgresAuthorizationService {
-	return &PostgresAuthorizationService{
+	s := &PostgresAuthorizationService{
 		db:           db,
 		cacheService: cacheService,
 		auditService: auditService,
 		logger:       logger,
 	}
+	if degrading, ok := DegradingCacheOf(cacheService); ok {
+		s.cacheGuard = degrading
+	}
+	return s
+}
+
+// requireCache rejects a permission check while the cache is degraded and the policy fails closed
+func (s *PostgresAuthorizationService) requireCache(perm *Permission) error {
+	if s.cacheGuard == nil {
+		return nil
+	}
+	if err := s.cacheGuard.RequireAvailable(); err != nil {
+		s.logger.Warn("Rejecting permission check while the cache is unavailable",
+			zap.String("user_id", perm.UserID),
+			zap.String("resource", perm.Resource),
+			zap.String("action", perm.Action))
+		return fmt.Errorf("permission check rejected: %w", err)
+	}
+	return nil
 }
 
 // CheckPermission checks if a user has permission to perform an action on a resource
 func (s *PostgresAuthorizationService) CheckPermission(ctx context.Context, perm *Permission) (*PermissionResult, error) {
+	if err := s.requireCache(perm); err != nil {
+		return nil, err
+	}
+
 	// Create cache key for permission check
 	cacheKey := fmt.Sprintf("permission:%s:%s:%s:%s", perm.UserID, perm.Resource, perm.ResourceID, perm.Action)
+	if perm.OrganizationID != "" {
+		cacheKey += ":org:" + perm.OrganizationID
+	}
 
-	// Try to get result from cache first
+	// Try to get result from cache first; cached denials are audited like fresh ones
 	if cachedResult, exists := s.cacheService.Get(cacheKey); exists {
 		if result, ok := cachedResult.(*PermissionResult); ok {
+			s.auditDenied(ctx, perm, result)
+			s.auditCheck(ctx, perm, result, true)
 			return result, nil
 		}
 	}
 
-	// Check permission in database
-	allowed, reason, err := s.checkPermissionInDB(ctx, perm)
+	// Check permission in database; concurrent misses for the same check wait for one evaluation
+	loaded, err, _ := s.loads.Do(cacheKey, func() (interface{}, error) {
+		decision, err := s.evaluatePermission(context.WithoutCancel(ctx), perm)
+		if err != nil {
+			return nil, err
+		}
+
+		result := &PermissionResult{
+			Allowed:  decision.Allowed,
+			Reason:   decision.Reason,
+			Rule:     decision.Rule,
+			RoleName: decision.RoleName,
+		}
+
+		// Cache the result for 5 minutes (300 seconds)
+		if err := s.cacheService.Set(cacheKey, result, 300); err != nil {
+			s.logger.Warn("Failed to cache permission result", zap.String("key", cacheKey), zap.Error(err))
+		}
+		return result, nil
+	})
 	if err != nil {
 		s.logger.Error("Failed to check permission",
 			zap.String("user_id", perm.UserID),
@@ -56,40 +113,116 @@ func (s *PostgresAuthorizationService) CheckPermission(ctx context.Context, perm
 			zap.Error(err))
 		return nil, err
 	}
+	result := loaded.(*PermissionResult)
+	s.auditDenied(ctx, perm, result)
+	s.auditCheck(ctx, perm, result, false)
 
-	result := &PermissionResult{
-		Allowed: allowed,
-		Reason:  reason,
+	return result, nil
+}
+
+// auditDenied audits the permission check if denied
+func (s *PostgresAuthorizationService) auditDenied(ctx context.Context, perm *Permission, result *PermissionResult) {
+	if s.auditService != nil && !result.Allowed {
+		s.auditService.LogAccessDenied(ctx, perm.UserID, perm.Action, perm.Resource, perm.ResourceID, result.Reason)
 	}
+}
+
+// CanPerformCacheTTL is how long, in seconds, a CanPerform decision is cached
+const CanPerformCacheTTL = 30
 
-	// Cache the result for 5 minutes (300 seconds)
-	if err := s.cacheService.Set(cacheKey, result, 300); err != nil {
-		s.logger.Warn("Failed to cache permission result", zap.String("key", cacheKey), zap.Error(err))
+// CanPerform decides a permission check with a short-lived cache of its own. The key lives under
+// the user's permission:<user_id>: prefix so role and permission changes invalidate it.
+func (s *PostgresAuthorizationService) CanPerform(ctx context.Context, perm *Permission) (bool, error) {
+	if err := s.requireCache(perm); err != nil {
+		return false, err
 	}
 
-	// Audit the permission check if denied
-	if s.auditService != nil && !allowed {
-		s.auditService.LogAccessDenied(ctx, perm.UserID, perm.Action, perm.Resource, perm.ResourceID, reason)
+	cacheKey := fmt.Sprintf("permission:%s:can:%s:%s:%s:%s", perm.UserID, perm.OrganizationID, perm.Resource, perm.ResourceID, perm.Action)
+	if cached, exists := s.cacheService.Get(cacheKey); exists {
+		if allowed, ok := cached.(bool); ok {
+			return allowed, nil
+		}
 	}
 
-	return result, nil
+	loaded, err, _ := s.loads.Do(cacheKey, func() (interface{}, error) {
+		allowed, _, err := s.checkPermissionInDB(context.WithoutCancel(ctx), perm)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.cacheService.Set(cacheKey, allowed, CanPerformCacheTTL); err != nil {
+			s.logger.Warn("Failed to cache permission decision", zap.String("key", cacheKey), zap.Error(err))
+		}
+		return allowed, nil
+	})
+	if err != nil {
+		s.logger.Error("Failed to check permission",
+			zap.String("user_id", perm.UserID),
+			zap.String("resource", perm.Resource),
+			zap.String("action", perm.Action),
+			zap.Error(err))
+		return false, err
+	}
+	return loaded.(bool), nil
 }
 
 // checkPermissionInDB performs the actual permission check in the database
 func (s *PostgresAuthorizationService) checkPermissionInDB(ctx context.Context, perm *Permission) (bool, string, error) {
+	decision, err := s.evaluatePermission(ctx, perm)
+	if err != nil {
+		return false, decision.Reason, err
+	}
+	return decision.Allowed, decision.Reason, nil
+}
+
+// evaluatePermission decides a permission check and records which rule decided it.
+// Resource permissions are evaluated first with the precedence documented in permission_matcher.go;
+// when none match, role permissions and then admin roles are consulted.
+func (s *PostgresAuthorizationService) evaluatePermission(ctx context.Context, perm *Permission) (*PermissionDecision, error) {
 	// Step 1: Get user's roles (including inherited from groups)
 	userRoles, err := s.getUserRoles(ctx, perm.UserID)
 	if err != nil {
-		return false, "Failed to fetch user roles", err
+		return &PermissionDecision{Reason: "Failed to fetch user roles"}, err
+	}
+
+	if perm.OrganizationID != "" {
+		userRoles = rolesInOrganization(userRoles, perm.OrganizationID)
 	}
 
 	if len(userRoles) == 0 {
-		return false, "User has no roles", nil
+		return &PermissionDecision{Reason: "User has no roles"}, nil
 	}
 
-	// Step 2: Check if any role has the required permission for the resource
+	// Step 2: The most specific resource permission held by any role decides the check
+	roleNames := make(map[string]string, len(userRoles))
 	for _, role := range userRoles {
-		hasPermission, err := s.roleHasPermission(ctx, role.ID, perm.Resource, perm.ResourceID, perm.Action)
+		roleNames[role.ID] = role.Name
+	}
+	grants, err := s.loadResourceGrants(ctx, roleNames, perm.Resource, perm.Action)
+	if err != nil {
+		s.logger.Warn("Failed to load resource permissions",
+			zap.String("user_id", perm.UserID),
+			zap.String("resource", perm.Resource),
+			zap.Error(err))
+	}
+	if winner, matches := MatchResourceGrants(grants, perm.Resource, perm.ResourceID, perm.Action); winner != nil {
+		decision := &PermissionDecision{
+			Allowed:      winner.Grant.Effect == GrantEffectAllow,
+			Rule:         DecisionRuleResourcePermission,
+			RoleName:     winner.roleLabel(),
+			MatchedGrant: winner,
+			Candidates:   matches,
+		}
+		if decision.Allowed {
+			decision.Reason = fmt.Sprintf("Permission granted through role: %s (%s match on %s)", decision.RoleName, winner.MatchKind, winner.Grant.ResourceID)
+		} else {
+			decision.Reason = fmt.Sprintf("Permission denied by role: %s (%s match on %s)", decision.RoleName, winner.MatchKind, winner.Grant.ResourceID)
+		}
+		return decision, nil
+	}
+
+	// Step 3: Check if any role has the required permission through role permissions
+	for _, role := range userRoles {
+		hasPermission, err := s.roleHasNamedPermission(ctx, role.ID, perm.Resource, perm.Action)
 		if err != nil {
 			s.logger.Warn("Failed to check role permission",
 				zap.String("role_id", role.ID),
@@ -97,18 +230,77 @@ func (s *PostgresAuthorizationService) checkPermissionInDB(ctx context.Context,
 			continue
 		}
 		if hasPermission {
-			return true, fmt.Sprintf("Permission granted through role: %s", role.Name), nil
+			return &PermissionDecision{
+				Allowed:  true,
+				Reason:   fmt.Sprintf("Permission granted through role: %s", role.Name),
+				Rule:     DecisionRuleRolePermission,
+				RoleName: role.Name,
+			}, nil
 		}
 	}
 
-	// Step 3: Check for wildcard permissions (e.g., admin roles)
+	// Step 4: Check for wildcard permissions (e.g., admin roles)
 	for _, role := range userRoles {
 		if s.roleHasWildcardPermission(ctx, role) {
-			return true, fmt.Sprintf("Permission granted through admin role: %s", role.Name), nil
+			return &PermissionDecision{
+				Allowed:  true,
+				Reason:   fmt.Sprintf("Permission granted through admin role: %s", role.Name),
+				Rule:     DecisionRuleAdminRole,
+				RoleName: role.Name,
+			}, nil
+		}
+	}
+
+	return &PermissionDecision{Reason: "No matching permissions found"}, nil
+}
+
+// rolesInOrganization keeps the roles that apply within orgID: global roles, roles not bound to
+// an organization, and roles of orgID itself
+func rolesInOrganization(roles []models.Role, orgID string) []models.Role {
+	scoped := make([]models.Role, 0, len(roles))
+	for _, role := range roles {
+		if role.Scope == models.RoleScopeGlobal || role.OrganizationID == nil || *role.OrganizationID == "" || *role.OrganizationID == orgID {
+			scoped = append(scoped, role)
 		}
 	}
+	return scoped
+}
+
+// loadResourceGrants loads the active resource permissions that can apply to action on resourceType
+// for the roles in roleNames, which maps role IDs to role names
+func (s *PostgresAuthorizationService) loadResourceGrants(ctx context.Context, roleNames map[string]string, resourceType, action string) ([]ResourceGrant, error) {
+	roleIDs := make([]string, 0, len(roleNames))
+	for roleID := range roleNames {
+		roleIDs = append(roleIDs, roleID)
+	}
+
+	var rows []struct {
+		RoleID     string
+		ResourceID string
+		Action     string
+	}
+	err := s.db.WithContext(ctx).
+		Table("resource_permissions").
+		Select("role_id, resource_id, action").
+		Where("role_id IN ? AND resource_type = ? AND is_active = ?", roleIDs, resourceType, true).
+		Where("action IN ?", []string{action, wildcard}).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
 
-	return false, "No matching permissions found", nil
+	grants := make([]ResourceGrant, 0, len(rows))
+	for _, row := range rows {
+		grants = append(grants, ResourceGrant{
+			RoleID:       row.RoleID,
+			RoleName:     roleNames[row.RoleID],
+			ResourceType: resourceType,
+			ResourceID:   row.ResourceID,
+			Action:       row.Action,
+			Effect:       GrantEffectAllow,
+		})
+	}
+	return grants, nil
 }
 
 // getUserRoles gets all roles for a user, including inherited ones
@@ -174,37 +366,27 @@ func (s *PostgresAuthorizationService) includeParentRoles(ctx context.Context, r
 	return result
 }
 
-// roleHasPermission checks if a role has a specific permission for a resource
-// SECURITY FIX: Validates that resource_type matches the permission name pattern to prevent
-// authorization bypass where users with address_read could access other resource types.
-// Permissions follow the naming convention: {resource_type}_{action}
-// Examples: address_read, attachment_create, collaborator_update
+// roleHasPermission checks if a role has a specific permission for a resource.
+// The role's most specific matching resource permission decides; without one, role permissions are checked.
 func (s *PostgresAuthorizationService) roleHasPermission(ctx context.Context, roleID, resourceType, resourceID, action string) (bool, error) {
-	var count int64
-
-	// Check ResourcePermission table
-	query := s.db.WithContext(ctx).
-		Table("resource_permissions").
-		Where("role_id = ? AND resource_type = ? AND is_active = ?", roleID, resourceType, true)
-
-	// Check for specific resource or wildcard
-	if resourceID != "" {
-		query = query.Where("(resource_id = ? OR resource_id = '*')", resourceID)
-	}
-
-	// Check for specific action
-	query = query.Where("resource_permissions.action = ?", action)
-
-	if err := query.Count(&count).Error; err != nil {
+	grants, err := s.loadResourceGrants(ctx, map[string]string{roleID: ""}, resourceType, action)
+	if err != nil {
 		return false, err
 	}
-
-	if count > 0 {
-		return true, nil
+	if winner, _ := MatchResourceGrants(grants, resourceType, resourceID, action); winner != nil {
+		return winner.Grant.Effect == GrantEffectAllow, nil
 	}
 
-	// SECURITY FIX: Check RolePermission table for general permissions
-	// BUT validate that the permission name matches {resource_type}_{action} pattern.
+	return s.roleHasNamedPermission(ctx, roleID, resourceType, action)
+}
+
+// roleHasNamedPermission checks the RolePermission table for a general {resource_type}:{action} permission
+// SECURITY FIX: Validates that resource_type matches the permission name pattern to prevent
+// authorization bypass where users with address_read could access other resource types.
+// Examples: address:read, attachment:create, collaborator:update
+func (s *PostgresAuthorizationService) roleHasNamedPermission(ctx context.Context, roleID, resourceType, action string) (bool, error) {
+	var count int64
+
 	// Previously this checked (actions.name = ? OR permissions.name = ?) with only the action,
 	// which allowed ANY permission with that action name regardless of resource_type.
 	// This caused users with "address_read" to be able to access "attachment" resources.
@@ -227,11 +409,8 @@ func (s *PostgresAuthorizationService) roleHasPermission(ctx context.Context, ro
 // roleHasWildcardPermission checks if a role has admin/wildcard permissions
 func (s *PostgresAuthorizationService) roleHasWildcardPermission(ctx context.Context, role models.Role) bool {
 	// Check for super admin or global admin roles
-	adminRoleNames := []string{"super_admin", "admin", "system_admin", "CEO"}
-	for _, adminName := range adminRoleNames {
-		if role.Name == adminName {
-			return true
-		}
+	if slices.Contains(adminRoleNames, role.Name) {
+		return true
 	}
 
 	// Check if role has manage or admin permissions or the wildcard *:* permission
@@ -240,7 +419,7 @@ func (s *PostgresAuthorizationService) roleHasWildcardPermission(ctx context.Con
 		Table("role_permissions").
 		Joins("JOIN permissions ON role_permissions.permission_id = permissions.id").
 		Where("role_permissions.role_id = ? AND role_permissions.is_active = ?", role.ID, true).
-		Where("permissions.name IN (?) AND permissions.is_active = ?", []string{"manage", "admin", "super_admin", "*:*"}, true).
+		Where("permissions.name IN (?) AND permissions.is_active = ?", adminPermissionNames, true).
 		Count(&count)
 
 	return count > 0
@@ -348,8 +527,21 @@ func (s *PostgresAuthorizationService) RemoveRoleFromUser(ctx context.Context, u
 	return nil
 }
 
-// GetUserPermissions gets all permissions for a user
+// GetUserPermissions gets all permissions for a user. Concurrent calls for the same user share
+// one computation and each receive their own copy of the result.
 func (s *PostgresAuthorizationService) GetUserPermissions(ctx context.Context, userID string) ([]string, error) {
+	loaded, err, _ := s.loads.Do("user_permissions:"+userID, func() (interface{}, error) {
+		return s.loadUserPermissions(context.WithoutCancel(ctx), userID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	permissions := loaded.([]string)
+	return append(make([]string, 0, len(permissions)), permissions...), nil
+}
+
+// loadUserPermissions computes the effective permissions of a user from their roles
+func (s *PostgresAuthorizationService) loadUserPermissions(ctx context.Context, userID string) ([]string, error) {
 	roles, err := s.getUserRoles(ctx, userID)
 	if err != nil {
 		return nil, err
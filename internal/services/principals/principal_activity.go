@@ -0,0 +1,107 @@
+package principals
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	principalResponses "github.com/Kisanlink/aaa-service/v2/internal/entities/responses/principals"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// DefaultActivityWindowDays and MaxActivityWindowDays bound the window of GetPrincipalActivity
+const (
+	DefaultActivityWindowDays = 7
+	MaxActivityWindowDays     = 90
+)
+
+// SetActivityRepository enables GetPrincipalActivity
+func (s *Service) SetActivityRepository(activityRepo interfaces.PrincipalActivityRepository) {
+	s.activityRepo = activityRepo
+}
+
+// GetPrincipalActivity summarizes the API usage of a principal or service over the last windowDays:
+// when it was last used, its calls per endpoint and its failure rate
+func (s *Service) GetPrincipalActivity(ctx context.Context, principalID string, windowDays int) (*principalResponses.PrincipalActivityResponse, error) {
+	if s.activityRepo == nil {
+		return nil, errors.NewInternalError(fmt.Errorf("principal activity is not configured"))
+	}
+	if windowDays <= 0 {
+		windowDays = DefaultActivityWindowDays
+	}
+	if windowDays > MaxActivityWindowDays {
+		return nil, errors.NewValidationError(fmt.Sprintf("window cannot exceed %d days", MaxActivityWindowDays))
+	}
+
+	identities, err := s.principalIdentities(ctx, principalID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.buildActivity(ctx, principalID, identities, windowDays, time.Now())
+}
+
+// principalIdentities returns the IDs a principal appears under in the audit trail: its own ID and,
+// for service principals, the ID of the service its API key belongs to
+func (s *Service) principalIdentities(ctx context.Context, principalID string) ([]string, error) {
+	if principal, err := s.principalRepo.GetByID(ctx, principalID); err == nil && principal != nil {
+		identities := []string{principal.ID}
+		if principal.ServiceID != nil && *principal.ServiceID != "" {
+			identities = append(identities, *principal.ServiceID)
+		}
+		return identities, nil
+	}
+
+	if service, err := s.serviceRepo.GetByID(ctx, principalID); err == nil && service != nil {
+		return []string{service.ID}, nil
+	}
+
+	return nil, errors.NewNotFoundError("principal not found")
+}
+
+func (s *Service) buildActivity(ctx context.Context, principalID string, identities []string, windowDays int, now time.Time) (*principalResponses.PrincipalActivityResponse, error) {
+	since := now.AddDate(0, 0, -windowDays)
+
+	usage, err := s.activityRepo.GetPrincipalEndpointUsage(ctx, identities, since)
+	if err != nil {
+		s.logger.Error("Failed to aggregate principal activity", zap.String("principal_id", principalID), zap.Error(err))
+		return nil, errors.NewInternalError(err)
+	}
+	lastUsedAt, err := s.activityRepo.GetPrincipalLastActivity(ctx, identities)
+	if err != nil {
+		s.logger.Error("Failed to get principal last activity", zap.String("principal_id", principalID), zap.Error(err))
+		return nil, errors.NewInternalError(err)
+	}
+
+	response := &principalResponses.PrincipalActivityResponse{
+		PrincipalID: principalID,
+		WindowDays:  windowDays,
+		Since:       since,
+		LastUsedAt:  lastUsedAt,
+		Endpoints:   make([]principalResponses.EndpointActivityResponse, 0, len(usage)),
+	}
+	for _, endpoint := range usage {
+		response.TotalCalls += endpoint.Calls
+		response.FailedCalls += endpoint.Failures
+		response.Endpoints = append(response.Endpoints, principalResponses.EndpointActivityResponse{
+			Method:       endpoint.Method,
+			Endpoint:     endpoint.Endpoint,
+			Calls:        endpoint.Calls,
+			Failures:     endpoint.Failures,
+			FailureRate:  failureRate(endpoint.Failures, endpoint.Calls),
+			LastCalledAt: endpoint.LastCalledAt,
+		})
+	}
+	response.FailureRate = failureRate(response.FailedCalls, response.TotalCalls)
+
+	return response, nil
+}
+
+func failureRate(failures, calls int64) float64 {
+	if calls == 0 {
+		return 0
+	}
+	return float64(failures) / float64(calls)
+}
@@ -0,0 +1,87 @@
+package principals
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type fakeActivityRepo struct {
+	usage        []interfaces.EndpointUsage
+	lastUsedAt   *time.Time
+	lastActivity map[string]time.Time
+	gotIDs       []string
+	gotSince     time.Time
+}
+
+func (r *fakeActivityRepo) GetPrincipalEndpointUsage(ctx context.Context, principalIDs []string, since time.Time) ([]interfaces.EndpointUsage, error) {
+	r.gotIDs = principalIDs
+	r.gotSince = since
+	return r.usage, nil
+}
+
+func (r *fakeActivityRepo) GetPrincipalLastActivity(ctx context.Context, principalIDs []string) (*time.Time, error) {
+	return r.lastUsedAt, nil
+}
+
+func (r *fakeActivityRepo) GetPrincipalsLastActivity(ctx context.Context, principalIDs []string) (map[string]time.Time, error) {
+	r.gotIDs = principalIDs
+	lastActivity := make(map[string]time.Time)
+	for _, id := range principalIDs {
+		if usedAt, ok := r.lastActivity[id]; ok {
+			lastActivity[id] = usedAt
+		}
+	}
+	return lastActivity, nil
+}
+
+func TestService_BuildActivity(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	lastUsedAt := now.Add(-time.Hour)
+	repo := &fakeActivityRepo{
+		usage: []interfaces.EndpointUsage{
+			{Method: "GET", Endpoint: "/api/v1/users", Calls: 8, Failures: 2, LastCalledAt: lastUsedAt},
+			{Method: "POST", Endpoint: "/api/v1/authorize", Calls: 2, Failures: 0, LastCalledAt: now.Add(-2 * time.Hour)},
+		},
+		lastUsedAt: &lastUsedAt,
+	}
+	service := &Service{activityRepo: repo, logger: zap.NewNop()}
+
+	activity, err := service.buildActivity(context.Background(), "PRN1", []string{"PRN1", "SVC1"}, 7, now)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"PRN1", "SVC1"}, repo.gotIDs)
+	assert.Equal(t, now.AddDate(0, 0, -7), repo.gotSince)
+	assert.Equal(t, int64(10), activity.TotalCalls)
+	assert.Equal(t, int64(2), activity.FailedCalls)
+	assert.InDelta(t, 0.2, activity.FailureRate, 1e-9)
+	assert.Equal(t, &lastUsedAt, activity.LastUsedAt)
+	require.Len(t, activity.Endpoints, 2)
+	assert.InDelta(t, 0.25, activity.Endpoints[0].FailureRate, 1e-9)
+	assert.Zero(t, activity.Endpoints[1].FailureRate)
+}
+
+func TestService_BuildActivity_NeverUsed(t *testing.T) {
+	service := &Service{activityRepo: &fakeActivityRepo{}, logger: zap.NewNop()}
+
+	activity, err := service.buildActivity(context.Background(), "PRN1", []string{"PRN1"}, 7, time.Now())
+	require.NoError(t, err)
+
+	assert.Nil(t, activity.LastUsedAt)
+	assert.Zero(t, activity.TotalCalls)
+	assert.Zero(t, activity.FailureRate)
+	assert.Empty(t, activity.Endpoints)
+}
+
+func TestService_GetPrincipalActivity_WindowTooLarge(t *testing.T) {
+	service := &Service{activityRepo: &fakeActivityRepo{}, logger: zap.NewNop()}
+
+	_, err := service.GetPrincipalActivity(context.Background(), "PRN1", MaxActivityWindowDays+1)
+	assert.True(t, errors.IsValidationError(err))
+}
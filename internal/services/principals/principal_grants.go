@@ -0,0 +1,72 @@
+package principals
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	principalResponses "github.com/Kisanlink/aaa-service/v2/internal/entities/responses/principals"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// SetGrantRepository enables ListResourcePermissions
+func (s *Service) SetGrantRepository(grantRepo interfaces.PrincipalGrantRepository) {
+	s.grantRepo = grantRepo
+}
+
+// ListResourcePermissions lists a page of the permissions a user principal holds through its
+// roles, optionally limited to one resource type, with the total number of grants. Service
+// principals hold no roles and are rejected.
+func (s *Service) ListResourcePermissions(ctx context.Context, principalID, resourceType string, limit, offset int) ([]*principalResponses.ResourcePermissionGrantResponse, int64, error) {
+	if s.grantRepo == nil {
+		return nil, 0, errors.NewInternalError(fmt.Errorf("principal resource permissions are not configured"))
+	}
+
+	principal, err := s.principalRepo.GetByID(ctx, principalID)
+	if err != nil || principal == nil {
+		return nil, 0, errors.NewNotFoundError("principal not found")
+	}
+	if principal.Type != models.PrincipalTypeUser || principal.UserID == nil || *principal.UserID == "" {
+		return nil, 0, errors.NewValidationError("resource permissions are only listed for user principals")
+	}
+
+	return s.listUserGrants(ctx, principalID, *principal.UserID, resourceType, limit, offset)
+}
+
+func (s *Service) listUserGrants(ctx context.Context, principalID, userID, resourceType string, limit, offset int) ([]*principalResponses.ResourcePermissionGrantResponse, int64, error) {
+	grants, total, err := s.grantRepo.ListUserResourceGrants(ctx, userID, resourceType, limit, offset)
+	if err != nil {
+		s.logger.Error("Failed to list principal resource permissions", zap.String("principal_id", principalID), zap.Error(err))
+		return nil, 0, errors.NewInternalError(err)
+	}
+
+	responses := make([]*principalResponses.ResourcePermissionGrantResponse, 0, len(grants))
+	for _, grant := range grants {
+		response := &principalResponses.ResourcePermissionGrantResponse{
+			Source:       grant.Source,
+			RoleID:       grant.RoleID,
+			RoleName:     grant.RoleName,
+			ResourceType: grant.ResourceType,
+			ResourceID:   grant.ResourceID,
+			Action:       grant.Action,
+			Permission:   grant.Permission,
+		}
+		for key, value := range map[string]*string{
+			"organization_id": grant.RoleOrganizationID,
+			"group_id":        grant.RoleGroupID,
+			"source_group_id": grant.SourceGroupID,
+		} {
+			if value == nil || *value == "" {
+				continue
+			}
+			if response.Conditions == nil {
+				response.Conditions = make(map[string]string)
+			}
+			response.Conditions[key] = *value
+		}
+		responses = append(responses, response)
+	}
+	return responses, total, nil
+}
@@ -0,0 +1,101 @@
+package principals
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type fakeGrantRepo struct {
+	grants          []interfaces.PrincipalResourceGrant
+	total           int64
+	gotUserID       string
+	gotResourceType string
+	gotLimit        int
+	gotOffset       int
+}
+
+func (r *fakeGrantRepo) ListUserResourceGrants(ctx context.Context, userID, resourceType string, limit, offset int) ([]interfaces.PrincipalResourceGrant, int64, error) {
+	r.gotUserID = userID
+	r.gotResourceType = resourceType
+	r.gotLimit = limit
+	r.gotOffset = offset
+	return r.grants, r.total, nil
+}
+
+func TestService_ListUserGrants_DirectAndRoleDerived(t *testing.T) {
+	orgID := "ORG1"
+	salesGroupID := "GRP1"
+	repo := &fakeGrantRepo{
+		grants: []interfaces.PrincipalResourceGrant{
+			{
+				Source:             interfaces.GrantSourceDirect,
+				RoleID:             "ROLE1",
+				RoleName:           "farm_manager",
+				ResourceType:       "farmers/farm",
+				ResourceID:         "FARM1",
+				Action:             "write",
+				RoleOrganizationID: &orgID,
+			},
+			{
+				Source:        interfaces.GrantSourceRole,
+				RoleID:        "ROLE2",
+				RoleName:      "viewer",
+				ResourceType:  "farmers/farm",
+				Action:        "read",
+				Permission:    "farm:read",
+				SourceGroupID: &salesGroupID,
+			},
+		},
+		total: 12,
+	}
+	service := &Service{grantRepo: repo, logger: zap.NewNop()}
+
+	grants, total, err := service.listUserGrants(context.Background(), "PRN1", "USER1", "farmers/farm", 2, 10)
+	require.NoError(t, err)
+
+	assert.Equal(t, "USER1", repo.gotUserID)
+	assert.Equal(t, "farmers/farm", repo.gotResourceType)
+	assert.Equal(t, 2, repo.gotLimit)
+	assert.Equal(t, 10, repo.gotOffset)
+	assert.Equal(t, int64(12), total)
+	require.Len(t, grants, 2)
+
+	direct := grants[0]
+	assert.Equal(t, "direct", direct.Source)
+	assert.Equal(t, "FARM1", direct.ResourceID)
+	assert.Equal(t, "write", direct.Action)
+	assert.Empty(t, direct.Permission)
+	assert.Equal(t, map[string]string{"organization_id": "ORG1"}, direct.Conditions)
+
+	role := grants[1]
+	assert.Equal(t, "role", role.Source)
+	assert.Equal(t, "viewer", role.RoleName)
+	assert.Equal(t, "farm:read", role.Permission)
+	assert.Empty(t, role.ResourceID, "named permissions cover every resource of the type")
+	assert.Equal(t, map[string]string{"source_group_id": "GRP1"}, role.Conditions)
+}
+
+func TestService_ListUserGrants_NoConditions(t *testing.T) {
+	repo := &fakeGrantRepo{grants: []interfaces.PrincipalResourceGrant{
+		{Source: interfaces.GrantSourceDirect, RoleID: "ROLE1", ResourceType: "aaa/user", ResourceID: "*", Action: "read"},
+	}, total: 1}
+	service := &Service{grantRepo: repo, logger: zap.NewNop()}
+
+	grants, _, err := service.listUserGrants(context.Background(), "PRN1", "USER1", "", 10, 0)
+	require.NoError(t, err)
+	require.Len(t, grants, 1)
+	assert.Nil(t, grants[0].Conditions)
+}
+
+func TestService_ListResourcePermissions_NotConfigured(t *testing.T) {
+	service := &Service{logger: zap.NewNop()}
+
+	_, _, err := service.ListResourcePermissions(context.Background(), "PRN1", "", 10, 0)
+	assert.True(t, errors.IsInternalError(err))
+}
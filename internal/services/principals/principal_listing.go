@@ -0,0 +1,147 @@
+package principals
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	principalResponses "github.com/Kisanlink/aaa-service/v2/internal/entities/responses/principals"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// MaxLastUsedFilterServices bounds the services a last-used filter is matched against; the audit
+// trail is joined in the service layer, so larger listings must be narrowed by organization or name
+const MaxLastUsedFilterServices = 5000
+
+// ListServices retrieves a page of the service principals matching filter, sorted by name, and the
+// total number of matches. Each service carries its API key count and when it last called the API,
+// taken from the audit trail.
+func (s *Service) ListServices(ctx context.Context, filter interfaces.ServiceFilter, limit, offset int) ([]*principalResponses.ServiceSummaryResponse, int64, error) {
+	s.logger.Info("Listing services",
+		zap.String("organization_id", filter.OrganizationID),
+		zap.Int("limit", limit),
+		zap.Int("offset", offset))
+
+	filtersByUse := filter.UsedSince != nil || filter.UnusedSince != nil
+	if filtersByUse && s.activityRepo == nil {
+		return nil, 0, errors.NewInternalError(fmt.Errorf("principal activity is not configured"))
+	}
+	if filter.UsedSince != nil && filter.UnusedSince != nil && !filter.UsedSince.Before(*filter.UnusedSince) {
+		return nil, 0, errors.NewValidationError("used_since must be before unused_since")
+	}
+
+	if !filtersByUse {
+		services, total, err := s.serviceLister.ListFiltered(ctx, filter, limit, offset)
+		if err != nil {
+			s.logger.Error("Failed to list services", zap.Error(err))
+			return nil, 0, errors.NewInternalError(err)
+		}
+		lastUsed, err := s.servicesLastActivity(ctx, services)
+		if err != nil {
+			return nil, 0, err
+		}
+		return serviceSummaries(services, lastUsed, time.Now()), total, nil
+	}
+
+	// The last use of a service is only known from the audit trail, so every service matching the
+	// other filters is checked before the page is cut
+	candidates, total, err := s.serviceLister.ListFiltered(ctx, filter, 0, 0)
+	if err != nil {
+		s.logger.Error("Failed to list services", zap.Error(err))
+		return nil, 0, errors.NewInternalError(err)
+	}
+	if total > MaxLastUsedFilterServices {
+		return nil, 0, errors.NewValidationError(fmt.Sprintf(
+			"%d services match; narrow the listing by organization or name to at most %d before filtering by last use",
+			total, MaxLastUsedFilterServices))
+	}
+	lastUsed, err := s.servicesLastActivity(ctx, candidates)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	matches := make([]*models.Service, 0, len(candidates))
+	for _, service := range candidates {
+		if usedWithin(lastUsed, service.ID, filter) {
+			matches = append(matches, service)
+		}
+	}
+
+	page := matches[min(offset, len(matches)):]
+	if limit > 0 && len(page) > limit {
+		page = page[:limit]
+	}
+	return serviceSummaries(page, lastUsed, time.Now()), int64(len(matches)), nil
+}
+
+// servicesLastActivity returns when each service last called the API. Requests authenticated with
+// a service's API key are audited under the service ID.
+func (s *Service) servicesLastActivity(ctx context.Context, services []*models.Service) (map[string]time.Time, error) {
+	if s.activityRepo == nil || len(services) == 0 {
+		return map[string]time.Time{}, nil
+	}
+
+	ids := make([]string, len(services))
+	for i, service := range services {
+		ids[i] = service.ID
+	}
+	lastUsed, err := s.activityRepo.GetPrincipalsLastActivity(ctx, ids)
+	if err != nil {
+		s.logger.Error("Failed to get services last activity", zap.Error(err))
+		return nil, errors.NewInternalError(err)
+	}
+	return lastUsed, nil
+}
+
+// usedWithin reports whether the last use of a service matches the last-used fields of filter
+func usedWithin(lastUsed map[string]time.Time, serviceID string, filter interfaces.ServiceFilter) bool {
+	usedAt, used := lastUsed[serviceID]
+	if filter.UsedSince != nil && (!used || usedAt.Before(*filter.UsedSince)) {
+		return false
+	}
+	if filter.UnusedSince != nil && used && !usedAt.Before(*filter.UnusedSince) {
+		return false
+	}
+	return true
+}
+
+func serviceSummaries(services []*models.Service, lastUsed map[string]time.Time, now time.Time) []*principalResponses.ServiceSummaryResponse {
+	summaries := make([]*principalResponses.ServiceSummaryResponse, len(services))
+	for i, service := range services {
+		summary := &principalResponses.ServiceSummaryResponse{
+			ServiceResponse: principalResponses.ServiceResponse{
+				ID:             service.ID,
+				Name:           service.Name,
+				Description:    service.Description,
+				OrganizationID: service.OrganizationID,
+				IsActive:       service.IsActive,
+				Metadata:       service.Metadata,
+				CreatedAt:      &service.CreatedAt,
+				UpdatedAt:      &service.UpdatedAt,
+			},
+			KeyCount: serviceKeyCount(service, now),
+		}
+		if usedAt, ok := lastUsed[service.ID]; ok {
+			summary.LastUsedAt = &usedAt
+		}
+		summaries[i] = summary
+	}
+	return summaries
+}
+
+// serviceKeyCount counts the API keys that authenticate a service: its current key and, during
+// the grace period after a rotation, the key it replaced
+func serviceKeyCount(service *models.Service, now time.Time) int {
+	count := 0
+	if service.APIKey != "" {
+		count++
+	}
+	if service.PreviousAPIKey != nil && *service.PreviousAPIKey != "" &&
+		service.PreviousAPIKeyExpiresAt != nil && service.PreviousAPIKeyExpiresAt.After(now) {
+		count++
+	}
+	return count
+}
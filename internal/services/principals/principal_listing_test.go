@@ -0,0 +1,218 @@
+package principals
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/Kisanlink/kisanlink-db/pkg/base"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeServiceLister applies the repository filters of a ServiceFilter to services, sorted by name
+type fakeServiceLister struct {
+	services  []*models.Service
+	gotFilter interfaces.ServiceFilter
+	gotLimit  int
+}
+
+func (l *fakeServiceLister) ListFiltered(ctx context.Context, filter interfaces.ServiceFilter, limit, offset int) ([]*models.Service, int64, error) {
+	l.gotFilter = filter
+	l.gotLimit = limit
+
+	var matches []*models.Service
+	for _, service := range l.services {
+		if filter.OrganizationID != "" && service.OrganizationID != filter.OrganizationID {
+			continue
+		}
+		if filter.NameContains != "" && !strings.Contains(service.Name, filter.NameContains) {
+			continue
+		}
+		if filter.IsActive != nil && service.IsActive != *filter.IsActive {
+			continue
+		}
+		matches = append(matches, service)
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i].Name < matches[j].Name })
+
+	total := int64(len(matches))
+	matches = matches[min(offset, len(matches)):]
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches, total, nil
+}
+
+func testService(id, name, orgID string, active bool) *models.Service {
+	service := &models.Service{
+		BaseModel:      &base.BaseModel{},
+		Name:           name,
+		OrganizationID: orgID,
+		APIKey:         "hash-" + id,
+		IsActive:       active,
+	}
+	service.SetID(id)
+	return service
+}
+
+func newListingTestService(now time.Time) (*Service, *fakeServiceLister, *fakeActivityRepo) {
+	rotated := testService("SVC2", "billing", "ORG1", true)
+	previousKey := "hash-old"
+	graceEnds := now.Add(time.Hour)
+	rotated.PreviousAPIKey = &previousKey
+	rotated.PreviousAPIKeyExpiresAt = &graceEnds
+
+	expired := testService("SVC3", "catalog", "ORG1", true)
+	expiredKey := "hash-expired"
+	graceEnded := now.Add(-time.Hour)
+	expired.PreviousAPIKey = &expiredKey
+	expired.PreviousAPIKeyExpiresAt = &graceEnded
+
+	lister := &fakeServiceLister{services: []*models.Service{
+		testService("SVC1", "analytics", "ORG1", true),
+		rotated,
+		expired,
+		testService("SVC4", "dispatch", "ORG2", false),
+		testService("SVC5", "ledger-sync", "ORG2", true),
+	}}
+	activity := &fakeActivityRepo{lastActivity: map[string]time.Time{
+		"SVC1": now.Add(-time.Hour),
+		"SVC2": now.Add(-40 * 24 * time.Hour),
+		"SVC4": now.Add(-2 * time.Hour),
+	}}
+	return &Service{serviceLister: lister, activityRepo: activity, logger: zap.NewNop()}, lister, activity
+}
+
+func TestService_ListServices_RepositoryFilters(t *testing.T) {
+	now := time.Now()
+	service, lister, activity := newListingTestService(now)
+	active := true
+
+	services, total, err := service.ListServices(context.Background(), interfaces.ServiceFilter{
+		OrganizationID: "ORG1",
+		IsActive:       &active,
+	}, 2, 0)
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(3), total)
+	require.Len(t, services, 2)
+	assert.Equal(t, "analytics", services[0].Name)
+	assert.Equal(t, "billing", services[1].Name)
+	assert.Equal(t, 2, lister.gotLimit, "without last-used filters the repository paginates")
+	assert.Equal(t, []string{"SVC1", "SVC2"}, activity.gotIDs, "last activity is fetched for the page only")
+
+	services, total, err = service.ListServices(context.Background(), interfaces.ServiceFilter{NameContains: "sync"}, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Len(t, services, 1)
+	assert.Equal(t, "ledger-sync", services[0].Name)
+	assert.Nil(t, services[0].LastUsedAt, "never used")
+}
+
+func TestService_ListServices_KeyCountAndLastUsed(t *testing.T) {
+	now := time.Now()
+	service, _, _ := newListingTestService(now)
+
+	services, _, err := service.ListServices(context.Background(), interfaces.ServiceFilter{OrganizationID: "ORG1"}, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, services, 3)
+
+	assert.Equal(t, 1, services[0].KeyCount)
+	assert.Equal(t, 2, services[1].KeyCount, "the replaced key still authenticates during the grace period")
+	assert.Equal(t, 1, services[2].KeyCount, "a replaced key past its grace period is not counted")
+
+	require.NotNil(t, services[0].LastUsedAt)
+	assert.True(t, services[0].LastUsedAt.Equal(now.Add(-time.Hour)))
+	assert.Nil(t, services[2].LastUsedAt)
+}
+
+func TestService_ListServices_LastUsedFilters(t *testing.T) {
+	now := time.Now()
+	service, lister, _ := newListingTestService(now)
+	monthAgo := now.Add(-30 * 24 * time.Hour)
+	dayAgo := now.Add(-24 * time.Hour)
+
+	tests := []struct {
+		name   string
+		filter interfaces.ServiceFilter
+		want   []string
+	}{
+		{
+			name:   "used since",
+			filter: interfaces.ServiceFilter{UsedSince: &dayAgo},
+			want:   []string{"analytics", "dispatch"},
+		},
+		{
+			name:   "unused since includes never used",
+			filter: interfaces.ServiceFilter{UnusedSince: &monthAgo},
+			want:   []string{"billing", "catalog", "ledger-sync"},
+		},
+		{
+			name:   "used within a window",
+			filter: interfaces.ServiceFilter{UsedSince: &monthAgo, UnusedSince: &now},
+			want:   []string{"analytics", "dispatch"},
+		},
+		{
+			name:   "combined with repository filters",
+			filter: interfaces.ServiceFilter{OrganizationID: "ORG1", UnusedSince: &dayAgo},
+			want:   []string{"billing", "catalog"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			services, total, err := service.ListServices(context.Background(), tt.filter, 10, 0)
+			require.NoError(t, err)
+
+			names := make([]string, len(services))
+			for i, s := range services {
+				names[i] = s.Name
+			}
+			assert.Equal(t, tt.want, names)
+			assert.Equal(t, int64(len(tt.want)), total)
+			assert.Zero(t, lister.gotLimit, "every candidate is matched against the audit trail")
+		})
+	}
+}
+
+func TestService_ListServices_LastUsedFilterPaginatesMatches(t *testing.T) {
+	now := time.Now()
+	service, _, _ := newListingTestService(now)
+	monthAgo := now.Add(-30 * 24 * time.Hour)
+
+	services, total, err := service.ListServices(context.Background(), interfaces.ServiceFilter{UnusedSince: &monthAgo}, 2, 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	require.Len(t, services, 2)
+	assert.Equal(t, "catalog", services[0].Name)
+	assert.Equal(t, "ledger-sync", services[1].Name)
+
+	services, total, err = service.ListServices(context.Background(), interfaces.ServiceFilter{UnusedSince: &monthAgo}, 2, 5)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	assert.Empty(t, services)
+}
+
+func TestService_ListServices_InvalidLastUsedFilters(t *testing.T) {
+	now := time.Now()
+	service, _, _ := newListingTestService(now)
+	dayAgo := now.Add(-24 * time.Hour)
+
+	_, _, err := service.ListServices(context.Background(), interfaces.ServiceFilter{UsedSince: &now, UnusedSince: &dayAgo}, 10, 0)
+	assert.True(t, errors.IsValidationError(err))
+
+	withoutActivity := &Service{serviceLister: &fakeServiceLister{}, logger: zap.NewNop()}
+	_, _, err = withoutActivity.ListServices(context.Background(), interfaces.ServiceFilter{UsedSince: &dayAgo}, 10, 0)
+	assert.Error(t, err)
+
+	services, _, err := withoutActivity.ListServices(context.Background(), interfaces.ServiceFilter{}, 10, 0)
+	require.NoError(t, err, "listing without last-used filters does not need the audit trail")
+	assert.Empty(t, services)
+}
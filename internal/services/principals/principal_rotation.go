@@ -0,0 +1,125 @@
+package principals
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	principalResponses "github.com/Kisanlink/aaa-service/v2/internal/entities/responses/principals"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// DefaultRotationGracePeriod and MaxRotationGracePeriod bound how long a rotated API key keeps working
+const (
+	DefaultRotationGracePeriod = 24 * time.Hour
+	MaxRotationGracePeriod     = 30 * 24 * time.Hour
+)
+
+// rotateCredentialsAction is the security action recorded for credential rotations
+const rotateCredentialsAction = "rotate_service_credentials"
+
+// SetAuditService records credential rotations as security events
+func (s *Service) SetAuditService(auditService interfaces.AuditService) {
+	s.auditService = auditService
+}
+
+// SetRotationGracePeriod sets how long the previous API key stays valid after a rotation. Zero or
+// less restores DefaultRotationGracePeriod; longer periods are capped at MaxRotationGracePeriod.
+func (s *Service) SetRotationGracePeriod(grace time.Duration) {
+	if grace <= 0 {
+		grace = DefaultRotationGracePeriod
+	}
+	if grace > MaxRotationGracePeriod {
+		grace = MaxRotationGracePeriod
+	}
+	s.rotationGrace = grace
+}
+
+// RotateServiceCredentials replaces the API key of a service principal. The new key is returned
+// once; the replaced key is accepted alongside it until the grace period ends, so dependent
+// services can switch over without downtime. A key still in its grace period from an earlier
+// rotation is invalidated immediately.
+func (s *Service) RotateServiceCredentials(ctx context.Context, principalID string) (*principalResponses.RotateCredentialsResponse, error) {
+	s.logger.Info("Rotating service credentials", zap.String("principal_id", principalID))
+
+	principal, err := s.principalRepo.GetByID(ctx, principalID)
+	if err != nil || principal == nil {
+		return nil, errors.NewNotFoundError("principal not found")
+	}
+	if principal.Type != models.PrincipalTypeService || principal.ServiceID == nil || *principal.ServiceID == "" {
+		return nil, errors.NewValidationError("only service principals have credentials to rotate")
+	}
+
+	service, err := s.serviceRepo.GetByID(ctx, *principal.ServiceID)
+	if err != nil || service == nil {
+		return nil, errors.NewNotFoundError("service not found")
+	}
+	if !service.IsActive {
+		return nil, errors.NewValidationError("cannot rotate credentials of an inactive service")
+	}
+
+	apiKey, err := s.GenerateAPIKey()
+	if err != nil {
+		s.logger.Error("Failed to generate API key for rotation", zap.Error(err))
+		return nil, errors.NewInternalError(err)
+	}
+
+	now := time.Now()
+	grace := s.rotationGrace
+	if grace <= 0 {
+		grace = DefaultRotationGracePeriod
+	}
+	s.applyRotation(service, s.hashAPIKey(apiKey), now, grace)
+
+	actorID, _ := ctx.Value("user_id").(string)
+	details := map[string]interface{}{
+		"principal_id":            principal.ID,
+		"service_id":              service.ID,
+		"grace_period_seconds":    int64(grace.Seconds()),
+		"previous_key_expires_at": service.PreviousAPIKeyExpiresAt.Format(time.RFC3339),
+	}
+
+	if err := s.serviceRepo.Update(ctx, service); err != nil {
+		s.logger.Error("Failed to save rotated service credentials",
+			zap.String("principal_id", principal.ID),
+			zap.String("service_id", service.ID),
+			zap.Error(err))
+		if s.auditService != nil {
+			details["error"] = err.Error()
+			s.auditService.LogSecurityEvent(ctx, actorID, rotateCredentialsAction, "service", false, details)
+		}
+		return nil, errors.NewInternalError(fmt.Errorf("failed to rotate service credentials: %w", err))
+	}
+
+	if s.auditService != nil {
+		s.auditService.LogSecurityEvent(ctx, actorID, rotateCredentialsAction, "service", true, details)
+	}
+
+	s.logger.Info("Service credentials rotated",
+		zap.String("principal_id", principal.ID),
+		zap.String("service_id", service.ID),
+		zap.Time("previous_key_expires_at", *service.PreviousAPIKeyExpiresAt))
+
+	return &principalResponses.RotateCredentialsResponse{
+		PrincipalID:          principal.ID,
+		ServiceID:            service.ID,
+		APIKey:               apiKey,
+		RotatedAt:            now,
+		PreviousKeyExpiresAt: *service.PreviousAPIKeyExpiresAt,
+	}, nil
+}
+
+// applyRotation makes newKeyHash the current key of service and keeps its current key as the
+// previous one until now+grace
+func (s *Service) applyRotation(service *models.Service, newKeyHash string, now time.Time, grace time.Duration) {
+	previous := service.APIKey
+	expiresAt := now.Add(grace)
+
+	service.PreviousAPIKey = &previous
+	service.PreviousAPIKeyExpiresAt = &expiresAt
+	service.APIKeyRotatedAt = &now
+	service.APIKey = newKeyHash
+}
@@ -0,0 +1,57 @@
+package principals
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestService_ApplyRotation(t *testing.T) {
+	service := &Service{logger: zap.NewNop()}
+	svc := models.NewService("billing", "", "ORG1", service.hashAPIKey("old-key"))
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	service.applyRotation(svc, service.hashAPIKey("new-key"), now, time.Hour)
+
+	require.NotNil(t, svc.PreviousAPIKeyExpiresAt)
+	assert.Equal(t, now.Add(time.Hour), *svc.PreviousAPIKeyExpiresAt)
+	assert.Equal(t, now, *svc.APIKeyRotatedAt)
+
+	// Both keys are accepted during the grace period, only the new one after it
+	assert.True(t, svc.AcceptsAPIKey(service.hashAPIKey("new-key"), now.Add(30*time.Minute)))
+	assert.True(t, svc.AcceptsAPIKey(service.hashAPIKey("old-key"), now.Add(30*time.Minute)))
+	assert.True(t, svc.AcceptsAPIKey(service.hashAPIKey("new-key"), now.Add(2*time.Hour)))
+	assert.False(t, svc.AcceptsAPIKey(service.hashAPIKey("old-key"), now.Add(2*time.Hour)))
+	assert.False(t, svc.AcceptsAPIKey(service.hashAPIKey("other-key"), now))
+}
+
+func TestService_ApplyRotationTwiceDropsOldestKey(t *testing.T) {
+	service := &Service{logger: zap.NewNop()}
+	svc := models.NewService("billing", "", "ORG1", service.hashAPIKey("key-1"))
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	service.applyRotation(svc, service.hashAPIKey("key-2"), now, time.Hour)
+	service.applyRotation(svc, service.hashAPIKey("key-3"), now.Add(time.Minute), time.Hour)
+
+	at := now.Add(2 * time.Minute)
+	assert.False(t, svc.AcceptsAPIKey(service.hashAPIKey("key-1"), at))
+	assert.True(t, svc.AcceptsAPIKey(service.hashAPIKey("key-2"), at))
+	assert.True(t, svc.AcceptsAPIKey(service.hashAPIKey("key-3"), at))
+}
+
+func TestService_SetRotationGracePeriod(t *testing.T) {
+	service := &Service{logger: zap.NewNop()}
+
+	service.SetRotationGracePeriod(2 * time.Hour)
+	assert.Equal(t, 2*time.Hour, service.rotationGrace)
+
+	service.SetRotationGracePeriod(0)
+	assert.Equal(t, DefaultRotationGracePeriod, service.rotationGrace)
+
+	service.SetRotationGracePeriod(365 * 24 * time.Hour)
+	assert.Equal(t, MaxRotationGracePeriod, service.rotationGrace)
+}
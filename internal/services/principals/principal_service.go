@@ -7,6 +7,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
 	principalRequests "github.com/Kisanlink/aaa-service/v2/internal/entities/requests/principals"
@@ -23,6 +24,11 @@ type Service struct {
 	orgRepo       *organizations.OrganizationRepository
 	principalRepo *principalRepo.PrincipalRepository
 	serviceRepo   *principalRepo.ServiceRepository
+	serviceLister interfaces.ServiceListRepository
+	activityRepo  interfaces.PrincipalActivityRepository
+	grantRepo     interfaces.PrincipalGrantRepository
+	auditService  interfaces.AuditService
+	rotationGrace time.Duration
 	validator     interfaces.Validator
 	logger        *zap.Logger
 }
@@ -39,6 +45,8 @@ func NewPrincipalService(
 		orgRepo:       orgRepo,
 		principalRepo: principalRepo,
 		serviceRepo:   serviceRepo,
+		serviceLister: serviceRepo,
+		rotationGrace: DefaultRotationGracePeriod,
 		validator:     validator,
 		logger:        logger,
 	}
@@ -402,51 +410,6 @@ func (s *Service) CountPrincipals(ctx context.Context, principalType, organizati
 	return s.principalRepo.CountActive(ctx)
 }
 
-// ListServices retrieves services with pagination and filtering
-func (s *Service) ListServices(ctx context.Context, limit, offset int, organizationID string) ([]*principalResponses.ServiceResponse, error) {
-	s.logger.Info("Listing services", zap.Int("limit", limit), zap.Int("offset", offset))
-
-	var services []*models.Service
-	var err error
-
-	// Apply filters based on parameters
-	if organizationID != "" {
-		services, err = s.serviceRepo.GetByOrganization(ctx, organizationID, limit, offset)
-	} else {
-		services, err = s.serviceRepo.ListActive(ctx, limit, offset)
-	}
-
-	if err != nil {
-		s.logger.Error("Failed to list services", zap.Error(err))
-		return nil, errors.NewInternalError(err)
-	}
-
-	// Convert to response format
-	responses := make([]*principalResponses.ServiceResponse, len(services))
-	for i, service := range services {
-		responses[i] = &principalResponses.ServiceResponse{
-			ID:             service.ID,
-			Name:           service.Name,
-			Description:    service.Description,
-			OrganizationID: service.OrganizationID,
-			IsActive:       service.IsActive,
-			Metadata:       service.Metadata,
-			CreatedAt:      &service.CreatedAt,
-			UpdatedAt:      &service.UpdatedAt,
-		}
-	}
-
-	return responses, nil
-}
-
-// CountServices returns the count of services matching the filters
-func (s *Service) CountServices(ctx context.Context, organizationID string) (int64, error) {
-	if organizationID != "" {
-		return s.serviceRepo.CountByOrganization(ctx, organizationID)
-	}
-	return s.serviceRepo.CountActive(ctx)
-}
-
 // GenerateAPIKey generates a secure API key for services
 func (s *Service) GenerateAPIKey() (string, error) {
 	// Generate 32 random bytes
@@ -0,0 +1,77 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+)
+
+// ResourceLookup finds resources by ID or by their registered name
+type ResourceLookup interface {
+	GetByID(ctx context.Context, id string) (*models.Resource, error)
+	GetByName(ctx context.Context, name string) (*models.Resource, error)
+}
+
+// ReferenceValidator checks that the resource_id of a permission refers to a known resource
+type ReferenceValidator struct {
+	resources ResourceLookup
+}
+
+// NewReferenceValidator creates a ReferenceValidator backed by the resource store
+func NewReferenceValidator(resources ResourceLookup) *ReferenceValidator {
+	return &ReferenceValidator{resources: resources}
+}
+
+// ValidateResourceReference returns a validation error when resourceID does not refer to an existing
+// resource. The full wildcard and prefix wildcards ("ORG*") are always valid since they do not name
+// a single resource. An exact reference may be a resource ID or a registered resource name; when
+// resourceType is set the resource must also be of that type. Lookup failures other than
+// "not found" are returned as they are.
+func (v *ReferenceValidator) ValidateResourceReference(ctx context.Context, resourceType, resourceID string) error {
+	if resourceID == "" {
+		return errors.NewValidationError("resource_id is required")
+	}
+	if strings.HasSuffix(resourceID, "*") {
+		return nil
+	}
+
+	resource, err := v.lookup(ctx, resourceID)
+	if err != nil {
+		return err
+	}
+	if resource == nil || resource.DeletedAt != nil {
+		return errors.NewValidationError("resource does not exist", resourceID)
+	}
+	if resourceType != "" && resource.Type != resourceType {
+		return errors.NewValidationError("resource is of a different type", resourceID)
+	}
+	return nil
+}
+
+// lookup finds the resource by registered name first and by ID second, returning nil when neither
+// matches
+func (v *ReferenceValidator) lookup(ctx context.Context, resourceID string) (*models.Resource, error) {
+	resource, err := v.resources.GetByName(ctx, resourceID)
+	if err == nil && resource != nil {
+		return resource, nil
+	}
+	if err != nil && !isNotFound(err) {
+		return nil, fmt.Errorf("failed to look up resource %s: %w", resourceID, err)
+	}
+
+	resource, err = v.resources.GetByID(ctx, resourceID)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to look up resource %s: %w", resourceID, err)
+	}
+	return resource, nil
+}
+
+func isNotFound(err error) bool {
+	return strings.Contains(strings.ToLower(err.Error()), "not found")
+}
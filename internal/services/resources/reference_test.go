@@ -0,0 +1,80 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeResourceLookup struct {
+	resources []*models.Resource
+	failWith  error
+}
+
+func (f *fakeResourceLookup) GetByID(ctx context.Context, id string) (*models.Resource, error) {
+	if f.failWith != nil {
+		return nil, f.failWith
+	}
+	for _, resource := range f.resources {
+		if resource.ID == id {
+			return resource, nil
+		}
+	}
+	return nil, fmt.Errorf("record not found")
+}
+
+func (f *fakeResourceLookup) GetByName(ctx context.Context, name string) (*models.Resource, error) {
+	if f.failWith != nil {
+		return nil, f.failWith
+	}
+	for _, resource := range f.resources {
+		if resource.Name == name {
+			return resource, nil
+		}
+	}
+	return nil, fmt.Errorf("resource not found with name: %s", name)
+}
+
+func TestReferenceValidator_ValidateResourceReference(t *testing.T) {
+	farm := models.NewResource("farm-42", "farmers/farm", "Farm 42")
+	users := models.NewResource("users", models.ResourceTypeUser, "Users")
+	validator := NewReferenceValidator(&fakeResourceLookup{resources: []*models.Resource{farm, users}})
+	ctx := context.Background()
+
+	assert.NoError(t, validator.ValidateResourceReference(ctx, "farmers/farm", "farm-42"), "registered name")
+	assert.NoError(t, validator.ValidateResourceReference(ctx, models.ResourceTypeUser, users.ID), "resource ID")
+	assert.NoError(t, validator.ValidateResourceReference(ctx, "", farm.ID), "any type")
+	assert.NoError(t, validator.ValidateResourceReference(ctx, "farmers/farm", "*"), "full wildcard")
+	assert.NoError(t, validator.ValidateResourceReference(ctx, "farmers/farm", "farm-*"), "prefix wildcard")
+
+	err := validator.ValidateResourceReference(ctx, "farmers/farm", "farm-404")
+	assert.True(t, errors.IsValidationError(err))
+	assert.Contains(t, err.Error(), "farm-404")
+
+	err = validator.ValidateResourceReference(ctx, models.ResourceTypeRole, "farm-42")
+	assert.True(t, errors.IsValidationError(err), "type mismatch")
+
+	assert.True(t, errors.IsValidationError(validator.ValidateResourceReference(ctx, "farmers/farm", "")))
+}
+
+func TestReferenceValidator_DeletedResourceIsDangling(t *testing.T) {
+	farm := models.NewResource("farm-42", "farmers/farm", "Farm 42")
+	deletedAt := time.Now()
+	farm.DeletedAt = &deletedAt
+	validator := NewReferenceValidator(&fakeResourceLookup{resources: []*models.Resource{farm}})
+
+	assert.True(t, errors.IsValidationError(validator.ValidateResourceReference(context.Background(), "farmers/farm", "farm-42")))
+}
+
+func TestReferenceValidator_LookupFailure(t *testing.T) {
+	validator := NewReferenceValidator(&fakeResourceLookup{failWith: fmt.Errorf("connection refused")})
+
+	err := validator.ValidateResourceReference(context.Background(), "farmers/farm", "farm-42")
+	assert.Error(t, err)
+	assert.False(t, errors.IsValidationError(err))
+}
@@ -0,0 +1,229 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	resourceRepo "github.com/Kisanlink/aaa-service/v2/internal/repositories/resources"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// ActionLookup finds actions by name; registered resources may only allow known actions
+type ActionLookup interface {
+	GetByName(ctx context.Context, name string) (*models.Action, error)
+}
+
+// PermissionStore finds and creates the permissions that grant an action on a resource
+type PermissionStore interface {
+	GetByResourceAndAction(ctx context.Context, resourceID, actionID string) (*models.Permission, error)
+	Create(ctx context.Context, permission *models.Permission) error
+}
+
+// RegisteredResource is the result of registering a resource
+type RegisteredResource struct {
+	Resource *models.Resource `json:"resource"`
+	Actions  []string         `json:"actions"`
+	Created  bool             `json:"created"`
+}
+
+// RegisterResource upserts a resource owned by another service together with the actions that may
+// be granted on it. The resource ID is the resource's unique name. Registering the same resource
+// again updates its parent and attributes and adds any new actions, so services can register on
+// every start-up. Every action must already exist.
+func (s *Service) RegisterResource(
+	ctx context.Context,
+	resourceType, resourceID, parentID string,
+	attributes map[string]interface{},
+	actions []string,
+) (*RegisteredResource, error) {
+	if err := s.validateResourceType(resourceType); err != nil {
+		return nil, errors.NewValidationError("invalid resource type", err.Error())
+	}
+	if err := s.validateResourceName(resourceID); err != nil {
+		return nil, errors.NewValidationError("invalid resource id", err.Error())
+	}
+	if parentID == resourceID {
+		return nil, errors.NewValidationError("resource cannot be its own parent")
+	}
+
+	knownActions, err := s.resolveActions(ctx, actions)
+	if err != nil {
+		return nil, err
+	}
+
+	var parent *models.Resource
+	if parentID != "" {
+		parent, err = s.resourceRepo.GetByName(ctx, parentID)
+		if err != nil {
+			if strings.Contains(err.Error(), "not found") {
+				return nil, errors.NewNotFoundError("parent resource not found")
+			}
+			return nil, fmt.Errorf("failed to get parent resource: %w", err)
+		}
+	}
+
+	resource, created, err := s.upsertRegisteredResource(ctx, resourceType, resourceID, parent, attributes)
+	if err != nil {
+		return nil, err
+	}
+
+	actionNames := make([]string, 0, len(knownActions))
+	for _, action := range knownActions {
+		if err := s.ensurePermission(ctx, resource, action); err != nil {
+			return nil, err
+		}
+		actionNames = append(actionNames, action.Name)
+	}
+
+	s.invalidateResourceCache()
+	if parent != nil {
+		s.invalidateHierarchyCache(parent.ID)
+	}
+
+	s.logger.Info("resource registered",
+		zap.String("resource_id", resource.ID),
+		zap.String("name", resourceID),
+		zap.String("type", resourceType),
+		zap.Strings("actions", actionNames),
+		zap.Bool("created", created))
+
+	return &RegisteredResource{Resource: resource, Actions: actionNames, Created: created}, nil
+}
+
+// ListResourceTypes returns each resource type in use with the number of active resources of that type
+func (s *Service) ListResourceTypes(ctx context.Context) ([]resourceRepo.ResourceTypeCount, error) {
+	counts, err := s.resourceRepo.CountByType(ctx)
+	if err != nil {
+		s.logger.Error("failed to list resource types", zap.Error(err))
+		return nil, fmt.Errorf("failed to list resource types: %w", err)
+	}
+	return counts, nil
+}
+
+// resolveActions normalizes the requested action names and loads them, rejecting the request when
+// any action is unknown or inactive
+func (s *Service) resolveActions(ctx context.Context, names []string) ([]*models.Action, error) {
+	if s.actionRepo == nil || s.permissionRepo == nil {
+		return nil, fmt.Errorf("resource registration is not configured")
+	}
+
+	normalized := normalizeActionNames(names)
+	if len(normalized) == 0 {
+		return nil, errors.NewValidationError("at least one action is required")
+	}
+
+	actions := make([]*models.Action, 0, len(normalized))
+	var unknown []string
+	for _, name := range normalized {
+		action, err := s.actionRepo.GetByName(ctx, name)
+		if err != nil {
+			if strings.Contains(err.Error(), "not found") {
+				unknown = append(unknown, name)
+				continue
+			}
+			return nil, fmt.Errorf("failed to get action %s: %w", name, err)
+		}
+		if !action.IsActive {
+			unknown = append(unknown, name)
+			continue
+		}
+		actions = append(actions, action)
+	}
+
+	if len(unknown) > 0 {
+		return nil, errors.NewValidationError("unknown actions", unknown...)
+	}
+	return actions, nil
+}
+
+// upsertRegisteredResource creates the resource or updates the registered one. A resource
+// cannot change type once registered.
+func (s *Service) upsertRegisteredResource(
+	ctx context.Context,
+	resourceType, name string,
+	parent *models.Resource,
+	attributes map[string]interface{},
+) (*models.Resource, bool, error) {
+	existing, err := s.resourceRepo.GetByName(ctx, name)
+	if err != nil && !strings.Contains(err.Error(), "not found") {
+		return nil, false, fmt.Errorf("failed to get resource: %w", err)
+	}
+
+	if existing == nil {
+		resource := models.NewResource(name, resourceType, "")
+		if parent != nil {
+			resource.ParentID = &parent.ID
+		}
+		resource.Attributes = models.AttributeValue(attributes)
+
+		if err := s.resourceRepo.Create(ctx, resource); err != nil {
+			s.logger.Error("failed to create registered resource",
+				zap.String("name", name),
+				zap.String("type", resourceType),
+				zap.Error(err))
+			return nil, false, fmt.Errorf("failed to create resource: %w", err)
+		}
+		return resource, true, nil
+	}
+
+	if existing.Type != resourceType {
+		return nil, false, errors.NewConflictError("resource is already registered with a different type")
+	}
+
+	existing.ParentID = nil
+	if parent != nil {
+		existing.ParentID = &parent.ID
+	}
+	if attributes != nil {
+		existing.Attributes = models.AttributeValue(attributes)
+	}
+	existing.IsActive = true
+
+	if err := s.resourceRepo.Update(ctx, existing); err != nil {
+		s.logger.Error("failed to update registered resource",
+			zap.String("resource_id", existing.ID),
+			zap.Error(err))
+		return nil, false, fmt.Errorf("failed to update resource: %w", err)
+	}
+	return existing, false, nil
+}
+
+// ensurePermission creates the "<resource>:<action>" permission unless it already exists
+func (s *Service) ensurePermission(ctx context.Context, resource *models.Resource, action *models.Action) error {
+	if _, err := s.permissionRepo.GetByResourceAndAction(ctx, resource.ID, action.ID); err == nil {
+		return nil
+	} else if !strings.Contains(err.Error(), "not found") {
+		return fmt.Errorf("failed to get permission: %w", err)
+	}
+
+	permission := models.NewPermissionWithResourceAndAction(
+		fmt.Sprintf("%s:%s", resource.Name, action.Name),
+		fmt.Sprintf("%s permission on %s", action.Name, resource.Name),
+		resource.ID,
+		action.ID,
+	)
+	if err := s.permissionRepo.Create(ctx, permission); err != nil {
+		return fmt.Errorf("failed to create permission %s: %w", permission.Name, err)
+	}
+	return nil
+}
+
+// normalizeActionNames lowercases, trims and de-duplicates action names, returning them sorted
+func normalizeActionNames(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	normalized := make([]string, 0, len(names))
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		normalized = append(normalized, name)
+	}
+	sort.Strings(normalized)
+	return normalized
+}
@@ -0,0 +1,89 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type fakeActionLookup map[string]*models.Action
+
+func (f fakeActionLookup) GetByName(ctx context.Context, name string) (*models.Action, error) {
+	if action, ok := f[name]; ok {
+		return action, nil
+	}
+	return nil, fmt.Errorf("action not found with name: %s", name)
+}
+
+type fakePermissionStore struct{}
+
+func (fakePermissionStore) GetByResourceAndAction(ctx context.Context, resourceID, actionID string) (*models.Permission, error) {
+	return nil, fmt.Errorf("permission not found")
+}
+
+func (fakePermissionStore) Create(ctx context.Context, permission *models.Permission) error {
+	return nil
+}
+
+func newRegistrationTestService() *Service {
+	inactive := models.NewAction("archive", "Archive")
+	inactive.IsActive = false
+	return &Service{
+		actionRepo: fakeActionLookup{
+			"read":    models.NewAction("read", "Read"),
+			"update":  models.NewAction("update", "Update"),
+			"archive": inactive,
+		},
+		permissionRepo: fakePermissionStore{},
+		logger:         zap.NewNop(),
+	}
+}
+
+func TestResolveActions(t *testing.T) {
+	s := newRegistrationTestService()
+
+	actions, err := s.resolveActions(context.Background(), []string{" Update", "read", "READ", ""})
+	require.NoError(t, err)
+	require.Len(t, actions, 2)
+	assert.Equal(t, "read", actions[0].Name)
+	assert.Equal(t, "update", actions[1].Name)
+}
+
+func TestResolveActions_RejectsUnknownActions(t *testing.T) {
+	s := newRegistrationTestService()
+
+	_, err := s.resolveActions(context.Background(), []string{"read", "harvest", "archive"})
+	require.Error(t, err)
+	require.True(t, errors.IsValidationError(err))
+	assert.ElementsMatch(t, []string{"harvest", "archive"}, err.(*errors.ValidationError).Details())
+
+	_, err = s.resolveActions(context.Background(), []string{" ", ""})
+	assert.True(t, errors.IsValidationError(err), "an empty action set is rejected")
+}
+
+func TestRegisterResource_ValidatesInput(t *testing.T) {
+	s := newRegistrationTestService()
+
+	tests := []struct {
+		name         string
+		resourceType string
+		resourceID   string
+		parentID     string
+	}{
+		{name: "invalid type", resourceType: "Farm Crop", resourceID: "farm-service:crop"},
+		{name: "empty id", resourceType: "farm/crop", resourceID: ""},
+		{name: "own parent", resourceType: "farm/crop", resourceID: "farm-service:crop", parentID: "farm-service:crop"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.RegisterResource(context.Background(), tt.resourceType, tt.resourceID, tt.parentID, nil, []string{"read"})
+			assert.True(t, errors.IsValidationError(err), "got %v", err)
+		})
+	}
+}
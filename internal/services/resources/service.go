@@ -48,13 +48,19 @@ type ResourceService interface {
 	GetDescendants(ctx context.Context, id string) ([]*models.Resource, error)
 	HasChildren(ctx context.Context, id string) (bool, error)
 	ValidateHierarchy(ctx context.Context, id, parentID string) error
+
+	// Registration operations
+	RegisterResource(ctx context.Context, resourceType, resourceID, parentID string, attributes map[string]interface{}, actions []string) (*RegisteredResource, error)
+	ListResourceTypes(ctx context.Context) ([]resourceRepo.ResourceTypeCount, error)
 }
 
 // Service implements the ResourceService interface
 type Service struct {
-	resourceRepo *resourceRepo.ResourceRepository
-	cacheService interfaces.CacheService
-	logger       *zap.Logger
+	resourceRepo   *resourceRepo.ResourceRepository
+	actionRepo     ActionLookup
+	permissionRepo PermissionStore
+	cacheService   interfaces.CacheService
+	logger         *zap.Logger
 }
 
 // ResourceTree represents a hierarchical tree structure of resources
@@ -66,12 +72,16 @@ type ResourceTree struct {
 // NewService creates a new ResourceService instance
 func NewService(
 	resourceRepo *resourceRepo.ResourceRepository,
+	actionRepo ActionLookup,
+	permissionRepo PermissionStore,
 	cacheService interfaces.CacheService,
 	logger *zap.Logger,
 ) ResourceService {
 	return &Service{
-		resourceRepo: resourceRepo,
-		cacheService: cacheService,
-		logger:       logger,
+		resourceRepo:   resourceRepo,
+		actionRepo:     actionRepo,
+		permissionRepo: permissionRepo,
+		cacheService:   cacheService,
+		logger:         logger,
 	}
 }
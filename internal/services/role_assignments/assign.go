@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
 	"github.com/Kisanlink/aaa-service/v2/internal/repositories/resource_permissions"
 	"go.uber.org/zap"
 )
@@ -71,6 +72,8 @@ func (s *Service) AssignPermissionToRole(ctx context.Context, roleID, permission
 				"permission_name": permission.Name,
 			})
 	}
+	s.auditPermissionChanges(ctx, assignedBy, PermissionChangeGrant, "assign_permission", roleID, role.Name,
+		[]PermissionChange{{PermissionID: permissionID, PermissionName: permission.Name}})
 
 	s.logger.Info("Permission assigned to role",
 		zap.String("role_id", roleID),
@@ -104,11 +107,14 @@ func (s *Service) AssignPermissionsToRole(ctx context.Context, roleID string, pe
 	}
 
 	// Verify all permissions exist
+	changes := make([]PermissionChange, 0, len(permissionIDs))
 	for _, permID := range permissionIDs {
-		if _, err := s.permissionRepo.GetByID(ctx, permID); err != nil {
+		permission, err := s.permissionRepo.GetByID(ctx, permID)
+		if err != nil {
 			s.logger.Error("Permission not found", zap.String("permission_id", permID), zap.Error(err))
 			return fmt.Errorf("permission %s not found: %w", permID, err)
 		}
+		changes = append(changes, PermissionChange{PermissionID: permID, PermissionName: permission.Name})
 	}
 
 	// Batch assign
@@ -132,6 +138,7 @@ func (s *Service) AssignPermissionsToRole(ctx context.Context, roleID string, pe
 				"permission_ids":   permissionIDs,
 			})
 	}
+	s.auditPermissionChanges(ctx, assignedBy, PermissionChangeGrant, "assign_permissions_batch", roleID, role.Name, changes)
 
 	s.logger.Info("Permissions assigned to role (batch)",
 		zap.String("role_id", roleID),
@@ -170,6 +177,8 @@ func (s *Service) AssignPermissionToMultipleRoles(ctx context.Context, permissio
 
 		// Invalidate cache for each role
 		s.invalidateRoleCache(ctx, roleID)
+		s.auditPermissionChanges(ctx, assignedBy, PermissionChangeGrant, "assign_to_multiple_roles", roleID, "",
+			[]PermissionChange{{PermissionID: permissionID, PermissionName: permission.Name}})
 		successCount++
 	}
 
@@ -221,6 +230,10 @@ func (s *Service) AssignResourceActionToRole(ctx context.Context, roleID, resour
 		return fmt.Errorf("cannot assign resource-action to deleted role '%s'", role.Name)
 	}
 
+	if err := s.checkResourceReference(ctx, resourceType, resourceID); err != nil {
+		return err
+	}
+
 	// Assign resource-action
 	if err := s.resourcePermissionRepo.Assign(ctx, roleID, resourceType, resourceID, action); err != nil {
 		s.logger.Error("Failed to assign resource-action",
@@ -244,6 +257,8 @@ func (s *Service) AssignResourceActionToRole(ctx context.Context, roleID, resour
 				"action":        action,
 			})
 	}
+	s.auditPermissionChanges(ctx, assignedBy, PermissionChangeGrant, "assign_resource_action", roleID, role.Name,
+		[]PermissionChange{{ResourceType: resourceType, ResourceID: resourceID, Action: action}})
 
 	s.logger.Info("Resource-action assigned to role",
 		zap.String("role_id", roleID),
@@ -280,6 +295,9 @@ func (s *Service) AssignResourceActionsToRole(ctx context.Context, roleID string
 	// Build batch assignments
 	var batchAssignments []resource_permissions.ResourcePermissionAssignment
 	for _, assignment := range assignments {
+		if err := s.checkResourceReference(ctx, assignment.ResourceType, assignment.ResourceID); err != nil {
+			return err
+		}
 		for _, action := range assignment.Actions {
 			batchAssignments = append(batchAssignments, resource_permissions.ResourcePermissionAssignment{
 				RoleID:       roleID,
@@ -311,6 +329,15 @@ func (s *Service) AssignResourceActionsToRole(ctx context.Context, roleID string
 				"assignments":      assignments,
 			})
 	}
+	changes := make([]PermissionChange, 0, len(batchAssignments))
+	for _, assignment := range batchAssignments {
+		changes = append(changes, PermissionChange{
+			ResourceType: assignment.ResourceType,
+			ResourceID:   assignment.ResourceID,
+			Action:       assignment.Action,
+		})
+	}
+	s.auditPermissionChanges(ctx, assignedBy, PermissionChangeGrant, "assign_resource_actions_batch", roleID, role.Name, changes)
 
 	s.logger.Info("Resource-actions assigned to role (batch)",
 		zap.String("role_id", roleID),
@@ -320,6 +347,27 @@ func (s *Service) AssignResourceActionsToRole(ctx context.Context, roleID string
 	return nil
 }
 
+// checkResourceReference validates the resource of a resource-action assignment. Without a validator
+// every resource is accepted; outside strict mode failures are only logged.
+func (s *Service) checkResourceReference(ctx context.Context, resourceType, resourceID string) error {
+	if s.resourceValidator == nil {
+		return nil
+	}
+
+	err := s.resourceValidator.ValidateResourceReference(ctx, resourceType, resourceID)
+	if err == nil {
+		return nil
+	}
+	if !s.strictResources {
+		s.logger.Warn("Resource-action assigned to an unknown resource",
+			zap.String("resource_type", resourceType),
+			zap.String("resource_id", resourceID),
+			zap.Error(err))
+		return nil
+	}
+	return err
+}
+
 // invalidateRoleCache invalidates all cached data for a role
 func (s *Service) invalidateRoleCache(ctx context.Context, roleID string) {
 	if s.cache == nil {
@@ -340,4 +388,13 @@ func (s *Service) invalidateRoleCache(ctx context.Context, roleID string) {
 			zap.String("cache_key", resourceCacheKey),
 			zap.Error(err))
 	}
+
+	if s.broadcaster != nil {
+		invalidation := interfaces.CacheInvalidation{Keys: []string{cacheKey, resourceCacheKey}}
+		if err := s.broadcaster.Publish(ctx, invalidation); err != nil {
+			s.logger.Warn("Failed to broadcast role cache invalidation",
+				zap.String("role_id", roleID),
+				zap.Error(err))
+		}
+	}
 }
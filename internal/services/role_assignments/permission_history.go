@@ -0,0 +1,166 @@
+package role_assignments
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"go.uber.org/zap"
+)
+
+// Permission history
+//
+// Every permission granted to or revoked from a role is audited with LogPermissionChange, one entry
+// per permission on the role (resource aaa/role, the role ID as resource ID) with the action
+// permission_grant or permission_revoke. The entry details name the permission, or the resource
+// and action of a resource-action, and the operation that made the change. GetPermissionHistory
+// replays these entries into the role's timeline.
+
+const (
+	// PermissionChangeGrant marks a permission granted to a role
+	PermissionChangeGrant = "grant"
+	// PermissionChangeRevoke marks a permission revoked from a role
+	PermissionChangeRevoke = "revoke"
+
+	// DefaultPermissionHistoryLimit is the number of events returned when no limit is given
+	DefaultPermissionHistoryLimit = 500
+	// MaxPermissionHistoryLimit is the most events returned for one query
+	MaxPermissionHistoryLimit = 1000
+)
+
+// PermissionChange is a permission, or a resource-action, granted to or revoked from a role
+type PermissionChange struct {
+	PermissionID   string
+	PermissionName string
+	ResourceType   string
+	ResourceID     string
+	Action         string
+}
+
+// PermissionHistoryEvent is one entry of a role's permission timeline
+type PermissionHistoryEvent struct {
+	Change         string    `json:"change"`
+	PermissionID   string    `json:"permission_id,omitempty"`
+	PermissionName string    `json:"permission_name,omitempty"`
+	ResourceType   string    `json:"resource_type,omitempty"`
+	ResourceID     string    `json:"resource_id,omitempty"`
+	Action         string    `json:"action,omitempty"`
+	Operation      string    `json:"operation,omitempty"`
+	ChangedBy      string    `json:"changed_by,omitempty"`
+	ChangedAt      time.Time `json:"changed_at"`
+	AuditLogID     string    `json:"audit_log_id"`
+}
+
+// GetPermissionHistory reconstructs the permissions granted to and revoked from a role between
+// from and to, either of which may be nil, oldest first. When more than limit changes fall in the
+// range the most recent limit are returned.
+func (s *Service) GetPermissionHistory(ctx context.Context, roleID string, from, to *time.Time, limit int) ([]*PermissionHistoryEvent, error) {
+	if roleID == "" {
+		return nil, fmt.Errorf("role ID is required")
+	}
+	if from != nil && to != nil && to.Before(*from) {
+		return nil, fmt.Errorf("end of the range is before its start")
+	}
+	if limit <= 0 {
+		limit = DefaultPermissionHistoryLimit
+	}
+	if limit > MaxPermissionHistoryLimit {
+		limit = MaxPermissionHistoryLimit
+	}
+
+	filter := interfaces.AuditLogFilter{
+		Actions:       []string{models.AuditActionPermissionGrant, models.AuditActionPermissionRevoke},
+		ResourceTypes: []string{models.ResourceTypeRole},
+		ResourceID:    roleID,
+		StartTime:     from,
+		EndTime:       to,
+	}
+	auditLogs, err := s.auditRepo.ListByFilter(ctx, filter, limit, 0)
+	if err != nil {
+		s.logger.Error("Failed to retrieve permission history from audit logs",
+			zap.String("role_id", roleID),
+			zap.Error(err))
+		return nil, fmt.Errorf("failed to retrieve permission history: %w", err)
+	}
+
+	// The audit logs come most recent first
+	events := make([]*PermissionHistoryEvent, 0, len(auditLogs))
+	for i := len(auditLogs) - 1; i >= 0; i-- {
+		events = append(events, permissionHistoryEvent(auditLogs[i]))
+	}
+	return events, nil
+}
+
+// permissionHistoryEvent reads a permission change audit entry
+func permissionHistoryEvent(auditLog *models.AuditLog) *PermissionHistoryEvent {
+	event := &PermissionHistoryEvent{
+		Change:         PermissionChangeGrant,
+		PermissionID:   auditDetail(auditLog, "permission_id"),
+		PermissionName: auditDetail(auditLog, "permission"),
+		ResourceType:   auditDetail(auditLog, "resource_type"),
+		ResourceID:     auditDetail(auditLog, "resource_id"),
+		Action:         auditDetail(auditLog, "action"),
+		Operation:      auditDetail(auditLog, "operation"),
+		ChangedAt:      auditLog.Timestamp,
+		AuditLogID:     auditLog.ID,
+	}
+	if event.PermissionID == "" {
+		// Resource-actions are named by their resource type and action
+		event.PermissionName = ""
+	}
+	if auditLog.Action == models.AuditActionPermissionRevoke {
+		event.Change = PermissionChangeRevoke
+	}
+	if auditLog.ActorID != nil {
+		event.ChangedBy = *auditLog.ActorID
+	} else if auditLog.UserID != nil {
+		event.ChangedBy = *auditLog.UserID
+	}
+	return event
+}
+
+func auditDetail(auditLog *models.AuditLog, key string) string {
+	value, _ := auditLog.Details[key].(string)
+	return value
+}
+
+// auditPermissionChanges records the permissions granted to or revoked from a role, one audit entry
+// per permission
+func (s *Service) auditPermissionChanges(ctx context.Context, actorID, change, operation, roleID, roleName string, changes []PermissionChange) {
+	if s.audit == nil {
+		return
+	}
+	for _, pc := range changes {
+		permission := pc.PermissionName
+		details := map[string]interface{}{
+			"role_id":   roleID,
+			"role_name": roleName,
+			"operation": operation,
+		}
+		if pc.PermissionID != "" {
+			details["permission_id"] = pc.PermissionID
+		} else {
+			permission = fmt.Sprintf("%s:%s", pc.ResourceType, pc.Action)
+			details["resource_type"] = pc.ResourceType
+			details["resource_id"] = pc.ResourceID
+			details["action"] = pc.Action
+		}
+		s.audit.LogPermissionChange(ctx, actorID, change, models.ResourceTypeRole, roleID, permission, details)
+	}
+}
+
+// permissionChanges names the permissions with the given IDs, leaving the name of any that cannot
+// be loaded empty
+func (s *Service) permissionChanges(ctx context.Context, permissionIDs []string) []PermissionChange {
+	changes := make([]PermissionChange, 0, len(permissionIDs))
+	for _, permissionID := range permissionIDs {
+		change := PermissionChange{PermissionID: permissionID}
+		if permission, err := s.permissionRepo.GetByID(ctx, permissionID); err == nil && permission != nil {
+			change.PermissionName = permission.Name
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
@@ -0,0 +1,172 @@
+package role_assignments
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/internal/services"
+	"github.com/Kisanlink/aaa-service/v2/internal/services/adapters"
+	"github.com/Kisanlink/aaa-service/v2/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+var historyStart = time.Date(2026, 7, 1, 9, 0, 0, 0, time.UTC)
+
+// historyAuditRepo stores audit logs in memory, stamping them a minute apart
+type historyAuditRepo struct {
+	interfaces.AuditRepository
+	logs []*models.AuditLog
+}
+
+func (r *historyAuditRepo) Create(ctx context.Context, auditLog *models.AuditLog) error {
+	auditLog.Timestamp = historyStart.Add(time.Duration(len(r.logs)) * time.Minute)
+	r.logs = append(r.logs, auditLog)
+	return nil
+}
+
+func (r *historyAuditRepo) ListByFilter(ctx context.Context, filter interfaces.AuditLogFilter, limit, offset int) ([]*models.AuditLog, error) {
+	var matched []*models.AuditLog
+	for _, auditLog := range r.logs {
+		if !containsString(filter.Actions, auditLog.Action) || !containsString(filter.ResourceTypes, auditLog.ResourceType) {
+			continue
+		}
+		if auditLog.ResourceID == nil || *auditLog.ResourceID != filter.ResourceID {
+			continue
+		}
+		if filter.StartTime != nil && auditLog.Timestamp.Before(*filter.StartTime) {
+			continue
+		}
+		if filter.EndTime != nil && auditLog.Timestamp.After(*filter.EndTime) {
+			continue
+		}
+		matched = append(matched, auditLog)
+	}
+	sort.Slice(matched, func(i, j int) bool { return matched[i].Timestamp.After(matched[j].Timestamp) })
+	if len(matched) > limit {
+		matched = matched[:limit]
+	}
+	return matched, nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func newHistoryTestService(repo *historyAuditRepo) *Service {
+	logger := zap.NewNop()
+	return &Service{
+		auditRepo: repo,
+		audit:     adapters.NewAuditServiceAdapter(services.NewAuditService(nil, repo, nil, logger)),
+		logger:    utils.NewLoggerAdapter(logger),
+	}
+}
+
+// recordPermissionChanges audits a quarter's worth of changes to ROLE1, with noise around them
+func recordPermissionChanges(s *Service) {
+	ctx := context.Background()
+	read := PermissionChange{PermissionID: "PERM_READ", PermissionName: "farm:read"}
+	write := PermissionChange{PermissionID: "PERM_WRITE", PermissionName: "farm:write"}
+
+	s.auditPermissionChanges(ctx, "ADMIN1", PermissionChangeGrant, "assign_permissions_batch", "ROLE1", "agronomist",
+		[]PermissionChange{read, write})
+	s.auditPermissionChanges(ctx, "ADMIN1", PermissionChangeGrant, "assign_permission", "ROLE2", "viewer",
+		[]PermissionChange{read})
+	s.audit.LogRoleChange(ctx, "ADMIN1", "update", "ROLE1", nil)
+	s.auditPermissionChanges(ctx, "ADMIN2", PermissionChangeRevoke, "revoke_permission", "ROLE1", "agronomist",
+		[]PermissionChange{write})
+	s.auditPermissionChanges(ctx, "ADMIN2", PermissionChangeGrant, "assign_resource_action", "ROLE1", "agronomist",
+		[]PermissionChange{{ResourceType: "aaa/farm", ResourceID: "FARM1", Action: "harvest"}})
+	s.auditPermissionChanges(ctx, "system", PermissionChangeRevoke, "revoke_all_permissions", "ROLE1", "agronomist",
+		[]PermissionChange{read, {ResourceType: "aaa/farm", ResourceID: "FARM1", Action: "harvest"}})
+}
+
+type historyStep struct {
+	change, permissionID, action, changedBy string
+}
+
+func historySteps(events []*PermissionHistoryEvent) []historyStep {
+	steps := make([]historyStep, 0, len(events))
+	for _, event := range events {
+		steps = append(steps, historyStep{event.Change, event.PermissionID, event.Action, event.ChangedBy})
+	}
+	return steps
+}
+
+func TestGetPermissionHistory_ReplaysGrantsAndRevokes(t *testing.T) {
+	repo := &historyAuditRepo{}
+	s := newHistoryTestService(repo)
+	recordPermissionChanges(s)
+
+	events, err := s.GetPermissionHistory(context.Background(), "ROLE1", nil, nil, 0)
+	require.NoError(t, err)
+
+	assert.Equal(t, []historyStep{
+		{PermissionChangeGrant, "PERM_READ", "", "ADMIN1"},
+		{PermissionChangeGrant, "PERM_WRITE", "", "ADMIN1"},
+		{PermissionChangeRevoke, "PERM_WRITE", "", "ADMIN2"},
+		{PermissionChangeGrant, "", "harvest", "ADMIN2"},
+		{PermissionChangeRevoke, "PERM_READ", "", "system"},
+		{PermissionChangeRevoke, "", "harvest", "system"},
+	}, historySteps(events), "oldest first, without other roles or other role changes")
+
+	for i := 1; i < len(events); i++ {
+		assert.True(t, events[i].ChangedAt.After(events[i-1].ChangedAt))
+	}
+	first := events[0]
+	assert.Equal(t, "farm:read", first.PermissionName)
+	assert.Equal(t, "assign_permissions_batch", first.Operation)
+	assert.NotEmpty(t, first.AuditLogID)
+
+	resourceGrant := events[3]
+	assert.Equal(t, "aaa/farm", resourceGrant.ResourceType)
+	assert.Equal(t, "FARM1", resourceGrant.ResourceID)
+	assert.Empty(t, resourceGrant.PermissionName)
+	assert.Equal(t, "revoke_all_permissions", events[5].Operation)
+}
+
+func TestGetPermissionHistory_Range(t *testing.T) {
+	repo := &historyAuditRepo{}
+	s := newHistoryTestService(repo)
+	recordPermissionChanges(s)
+
+	// Audit entries 4 through 6 are the revoke of write, the resource grant and the first revoke-all entry
+	from := historyStart.Add(4 * time.Minute)
+	to := historyStart.Add(6 * time.Minute)
+	events, err := s.GetPermissionHistory(context.Background(), "ROLE1", &from, &to, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []historyStep{
+		{PermissionChangeRevoke, "PERM_WRITE", "", "ADMIN2"},
+		{PermissionChangeGrant, "", "harvest", "ADMIN2"},
+		{PermissionChangeRevoke, "PERM_READ", "", "system"},
+	}, historySteps(events))
+
+	_, err = s.GetPermissionHistory(context.Background(), "ROLE1", &to, &from, 0)
+	assert.Error(t, err)
+}
+
+func TestGetPermissionHistory_LimitKeepsMostRecent(t *testing.T) {
+	repo := &historyAuditRepo{}
+	s := newHistoryTestService(repo)
+	recordPermissionChanges(s)
+
+	events, err := s.GetPermissionHistory(context.Background(), "ROLE1", nil, nil, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []historyStep{
+		{PermissionChangeRevoke, "PERM_READ", "", "system"},
+		{PermissionChangeRevoke, "", "harvest", "system"},
+	}, historySteps(events))
+
+	_, err = s.GetPermissionHistory(context.Background(), "", nil, nil, 0)
+	assert.Error(t, err)
+}
@@ -60,6 +60,8 @@ func (s *Service) RevokePermissionFromRole(ctx context.Context, roleID, permissi
 				"permission_name": permission.Name,
 			})
 	}
+	s.auditPermissionChanges(ctx, revokedBy, PermissionChangeRevoke, "revoke_permission", roleID, role.Name,
+		[]PermissionChange{{PermissionID: permissionID, PermissionName: permission.Name}})
 
 	s.logger.Info("Permission revoked from role",
 		zap.String("role_id", roleID),
@@ -103,6 +105,8 @@ func (s *Service) RevokePermissionsFromRole(ctx context.Context, roleID string,
 				"permission_ids":   permissionIDs,
 			})
 	}
+	s.auditPermissionChanges(ctx, revokedBy, PermissionChangeRevoke, "revoke_permissions_batch", roleID, role.Name,
+		s.permissionChanges(ctx, permissionIDs))
 
 	s.logger.Info("Permissions revoked from role (batch)",
 		zap.String("role_id", roleID),
@@ -148,6 +152,8 @@ func (s *Service) RevokeResourceActionFromRole(ctx context.Context, roleID, reso
 				"action":        action,
 			})
 	}
+	s.auditPermissionChanges(ctx, revokedBy, PermissionChangeRevoke, "revoke_resource_action", roleID, role.Name,
+		[]PermissionChange{{ResourceType: resourceType, ResourceID: resourceID, Action: action}})
 
 	s.logger.Info("Resource-action revoked from role",
 		zap.String("role_id", roleID),
@@ -174,6 +180,21 @@ func (s *Service) RevokeAllPermissionsFromRole(ctx context.Context, roleID strin
 	// Get current permission count for audit
 	permissionCount, _ := s.rolePermissionRepo.CountByRole(ctx, roleID)
 
+	// Record what is revoked for the role's permission history
+	var revoked []PermissionChange
+	if rolePerms, err := s.rolePermissionRepo.GetByRoleID(ctx, roleID); err == nil {
+		permissionIDs := make([]string, 0, len(rolePerms))
+		for _, rp := range rolePerms {
+			permissionIDs = append(permissionIDs, rp.PermissionID)
+		}
+		revoked = append(revoked, s.permissionChanges(ctx, permissionIDs)...)
+	}
+	if resourcePerms, err := s.resourcePermissionRepo.GetByRoleID(ctx, roleID); err == nil {
+		for _, rp := range resourcePerms {
+			revoked = append(revoked, PermissionChange{ResourceType: rp.ResourceType, ResourceID: rp.ResourceID, Action: rp.Action})
+		}
+	}
+
 	// Revoke all role-permissions (Model 1)
 	if err := s.rolePermissionRepo.RevokeAll(ctx, roleID); err != nil {
 		s.logger.Error("Failed to revoke all permissions",
@@ -201,6 +222,7 @@ func (s *Service) RevokeAllPermissionsFromRole(ctx context.Context, roleID strin
 				"permission_count": permissionCount,
 			})
 	}
+	s.auditPermissionChanges(ctx, revokedBy, PermissionChangeRevoke, "revoke_all_permissions", roleID, role.Name, revoked)
 
 	s.logger.Info("All permissions revoked from role",
 		zap.String("role_id", roleID),
@@ -20,6 +20,9 @@ type Service struct {
 	permissionRepo         *permissions.PermissionRepository
 	auditRepo              interfaces.AuditRepository
 	cache                  interfaces.CacheService
+	broadcaster            interfaces.InvalidationBroadcaster
+	resourceValidator      interfaces.ResourceReferenceValidator
+	strictResources        bool
 	audit                  interfaces.AuditService
 	logger                 interfaces.Logger
 }
@@ -47,6 +50,20 @@ func NewService(
 	}
 }
 
+// SetInvalidationBroadcaster sets the broadcaster that repeats role cache invalidations on the other
+// service instances
+func (s *Service) SetInvalidationBroadcaster(broadcaster interfaces.InvalidationBroadcaster) {
+	s.broadcaster = broadcaster
+}
+
+// SetResourceValidator enables resource reference validation of resource-action assignments. In
+// strict mode assignments to a missing resource are rejected; otherwise they are stored and the
+// dangling reference is logged.
+func (s *Service) SetResourceValidator(validator interfaces.ResourceReferenceValidator, strict bool) {
+	s.resourceValidator = validator
+	s.strictResources = strict
+}
+
 // ResourceActionAssignment represents a batch assignment of resource-actions
 type ResourceActionAssignment struct {
 	ResourceType string
@@ -94,6 +111,7 @@ type ServiceInterface interface {
 	GetRoleResources(ctx context.Context, roleID string) ([]*models.ResourcePermission, error)
 	GetRolesWithPermission(ctx context.Context, permissionID string) ([]*models.Role, error)
 	GetRolesWithResourceAccess(ctx context.Context, resourceType, resourceID, action string) ([]*models.Role, error)
+	GetPermissionHistory(ctx context.Context, roleID string, from, to *time.Time, limit int) ([]*PermissionHistoryEvent, error)
 
 	// Inheritance operations
 	GetInheritedRoles(ctx context.Context, roleID string) ([]*models.Role, error)
@@ -0,0 +1,204 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// MaxBulkRoleAssignmentUsers caps the number of users in a single bulk role assignment
+const MaxBulkRoleAssignmentUsers = 500
+
+// AssignRoleToUsers assigns one role to many users.
+// The role and every user are validated before anything is written, so an unknown ID fails the whole request.
+// Each user is then assigned in its own transaction: users who already hold the role are skipped, and a
+// failure for one user is reported in its result without undoing the others.
+func (s *RoleService) AssignRoleToUsers(ctx context.Context, roleID string, userIDs []string, assignedBy string) (*interfaces.BulkRoleAssignmentResult, error) {
+	s.logger.Info("Assigning role to users in bulk",
+		zap.String("roleID", roleID),
+		zap.Int("userCount", len(userIDs)),
+		zap.String("assignedBy", assignedBy))
+
+	if roleID == "" {
+		return nil, errors.NewValidationError("role ID is required")
+	}
+
+	userIDs, err := normalizeBulkUserIDs(userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	role := &models.Role{}
+	if _, err := s.roleRepo.GetByID(ctx, roleID, role); err != nil {
+		s.logger.Error("Role not found for bulk assignment", zap.String("roleID", roleID), zap.Error(err))
+		return nil, errors.NewNotFoundError("role not found")
+	}
+	if role.DeletedAt != nil {
+		return nil, errors.NewNotFoundError("role not found")
+	}
+	if !role.IsActive {
+		return nil, errors.NewValidationError("role is not active")
+	}
+
+	if err := s.validateUsersExist(ctx, userIDs); err != nil {
+		return nil, err
+	}
+
+	result := &interfaces.BulkRoleAssignmentResult{
+		RoleID:  roleID,
+		Results: make([]interfaces.UserRoleAssignmentResult, 0, len(userIDs)),
+	}
+
+	for _, userID := range userIDs {
+		userResult := s.assignRoleInBulk(ctx, role, userID, assignedBy, len(userIDs))
+		switch userResult.Status {
+		case interfaces.RoleAssignmentStatusAssigned:
+			result.Assigned++
+		case interfaces.RoleAssignmentStatusSkipped:
+			result.Skipped++
+		case interfaces.RoleAssignmentStatusPendingKYC:
+			result.PendingKYC++
+		default:
+			result.Failed++
+		}
+		result.Results = append(result.Results, userResult)
+	}
+
+	s.logger.Info("Bulk role assignment completed",
+		zap.String("roleID", roleID),
+		zap.Int("assigned", result.Assigned),
+		zap.Int("skipped", result.Skipped),
+		zap.Int("pending_kyc", result.PendingKYC),
+		zap.Int("failed", result.Failed))
+
+	return result, nil
+}
+
+// assignRoleInBulk assigns the role to a single user and records the outcome
+func (s *RoleService) assignRoleInBulk(ctx context.Context, role *models.Role, userID, assignedBy string, batchSize int) interfaces.UserRoleAssignmentResult {
+	isAssigned, err := s.userRoleRepo.IsRoleAssigned(ctx, userID, role.ID)
+	if err != nil {
+		s.logger.Error("Failed to check existing role assignment", zap.String("userID", userID), zap.String("roleID", role.ID), zap.Error(err))
+		s.auditBulkAssignment(ctx, role, userID, assignedBy, batchSize, err)
+		return interfaces.UserRoleAssignmentResult{UserID: userID, Status: interfaces.RoleAssignmentStatusFailed, Error: "failed to check existing assignment"}
+	}
+	if isAssigned {
+		return interfaces.UserRoleAssignmentResult{UserID: userID, Status: interfaces.RoleAssignmentStatusSkipped}
+	}
+
+	if err := s.checkRoleConstraints(ctx, userID, role); err != nil {
+		s.auditBulkAssignment(ctx, role, userID, assignedBy, batchSize, err)
+		return interfaces.UserRoleAssignmentResult{UserID: userID, Status: interfaces.RoleAssignmentStatusFailed, Error: err.Error()}
+	}
+
+	pending, err := s.deferUntilKYCVerified(ctx, userID, role)
+	if err != nil {
+		return interfaces.UserRoleAssignmentResult{UserID: userID, Status: interfaces.RoleAssignmentStatusFailed, Error: err.Error()}
+	}
+	if pending {
+		return interfaces.UserRoleAssignmentResult{UserID: userID, Status: interfaces.RoleAssignmentStatusPendingKYC}
+	}
+
+	if err := s.userRoleRepo.AssignRole(ctx, userID, role.ID); err != nil {
+		s.logger.Error("Failed to assign role to user", zap.String("userID", userID), zap.String("roleID", role.ID), zap.Error(err))
+		s.auditBulkAssignment(ctx, role, userID, assignedBy, batchSize, err)
+		return interfaces.UserRoleAssignmentResult{UserID: userID, Status: interfaces.RoleAssignmentStatusFailed, Error: "failed to assign role"}
+	}
+
+	s.invalidateUserRoleCache(ctx, userID)
+	s.invalidateUserPermissionCache(ctx, userID)
+	s.auditBulkAssignment(ctx, role, userID, assignedBy, batchSize, nil)
+
+	return interfaces.UserRoleAssignmentResult{UserID: userID, Status: interfaces.RoleAssignmentStatusAssigned}
+}
+
+// validateUsersExist checks every target user before any assignment is made
+func (s *RoleService) validateUsersExist(ctx context.Context, userIDs []string) error {
+	if s.userRepo == nil {
+		return fmt.Errorf("user repository not configured for bulk role assignment")
+	}
+
+	var missing []string
+	for _, userID := range userIDs {
+		if _, err := s.userRepo.GetByID(ctx, userID, &models.User{}); err != nil {
+			missing = append(missing, userID)
+		}
+	}
+
+	if len(missing) > 0 {
+		s.logger.Warn("Bulk role assignment references unknown users", zap.Strings("userIDs", missing))
+		return errors.NewNotFoundError(fmt.Sprintf("users not found: %s", strings.Join(missing, ", ")))
+	}
+
+	return nil
+}
+
+func (s *RoleService) auditBulkAssignment(ctx context.Context, role *models.Role, userID, assignedBy string, batchSize int, assignErr error) {
+	if s.auditService == nil {
+		return
+	}
+
+	details := map[string]interface{}{
+		"role_name":  role.Name,
+		"batch_size": batchSize,
+	}
+	if assignErr != nil {
+		details["error"] = assignErr.Error()
+	}
+
+	s.auditService.LogRoleOperation(ctx, assignedBy, userID, role.ID, "assign_bulk", assignErr == nil, details)
+}
+
+// invalidateUserPermissionCache removes cached permission evaluations for a user
+func (s *RoleService) invalidateUserPermissionCache(ctx context.Context, userID string) {
+	patterns := []string{
+		fmt.Sprintf("user:%s:*", userID),
+		fmt.Sprintf("permission:%s:*", userID),
+	}
+
+	for _, pattern := range patterns {
+		keys, err := s.cacheService.Keys(pattern)
+		if err != nil {
+			s.logger.Warn("Failed to list permission cache keys", zap.String("pattern", pattern), zap.Error(err))
+			continue
+		}
+		for _, key := range keys {
+			if err := s.cacheService.Delete(key); err != nil {
+				s.logger.Warn("Failed to delete permission cache key", zap.String("key", key), zap.Error(err))
+			}
+		}
+	}
+	s.broadcastInvalidation(ctx, interfaces.CacheInvalidation{Patterns: patterns})
+}
+
+// normalizeBulkUserIDs trims and de-duplicates user IDs and enforces the batch size limit
+func normalizeBulkUserIDs(userIDs []string) ([]string, error) {
+	if len(userIDs) == 0 {
+		return nil, errors.NewValidationError("at least one user ID is required")
+	}
+
+	seen := make(map[string]bool, len(userIDs))
+	normalized := make([]string, 0, len(userIDs))
+	for _, userID := range userIDs {
+		userID = strings.TrimSpace(userID)
+		if userID == "" {
+			return nil, errors.NewValidationError("user IDs cannot be empty")
+		}
+		if seen[userID] {
+			continue
+		}
+		seen[userID] = true
+		normalized = append(normalized, userID)
+	}
+
+	if len(normalized) > MaxBulkRoleAssignmentUsers {
+		return nil, errors.NewValidationError(fmt.Sprintf("cannot assign a role to more than %d users at once", MaxBulkRoleAssignmentUsers))
+	}
+
+	return normalized, nil
+}
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/Kisanlink/aaa-service/v2/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type bulkRoleRepo struct {
+	interfaces.RoleRepository
+	roles map[string]*models.Role
+}
+
+func (r *bulkRoleRepo) GetByID(ctx context.Context, id string, role *models.Role) (*models.Role, error) {
+	found, ok := r.roles[id]
+	if !ok {
+		return nil, fmt.Errorf("role not found")
+	}
+	*role = *found
+	return role, nil
+}
+
+type bulkUserRepo struct {
+	interfaces.UserRepository
+	users map[string]bool
+}
+
+func (r *bulkUserRepo) GetByID(ctx context.Context, id string, user *models.User) (*models.User, error) {
+	if !r.users[id] {
+		return nil, fmt.Errorf("user not found")
+	}
+	return user, nil
+}
+
+type bulkUserRoleRepo struct {
+	interfaces.UserRoleRepository
+	assigned map[string]bool
+	failFor  string
+}
+
+func (r *bulkUserRoleRepo) IsRoleAssigned(ctx context.Context, userID, roleID string) (bool, error) {
+	return r.assigned[userID+"/"+roleID], nil
+}
+
+func (r *bulkUserRoleRepo) AssignRole(ctx context.Context, userID, roleID string) error {
+	if userID == r.failFor {
+		return fmt.Errorf("connection reset")
+	}
+	r.assigned[userID+"/"+roleID] = true
+	return nil
+}
+
+type bulkAuditRecorder struct {
+	interfaces.AuditService
+	targets []string
+	success []bool
+}
+
+func (a *bulkAuditRecorder) LogRoleOperation(ctx context.Context, actorUserID, targetUserID, roleID, operation string, success bool, details map[string]interface{}) {
+	a.targets = append(a.targets, targetUserID)
+	a.success = append(a.success, success)
+}
+
+func newBulkRoleTestService(userRoles *bulkUserRoleRepo, audit *bulkAuditRecorder) *RoleService {
+	loggerAdapter := utils.NewLoggerAdapter(zap.NewNop())
+	role := models.NewRole("field_officer", "Field officer", models.RoleScopeOrg)
+	role.ID = "ROLE1"
+	role.IsActive = true
+
+	service := NewRoleService(
+		&bulkRoleRepo{roles: map[string]*models.Role{"ROLE1": role}},
+		userRoles,
+		NewNoOpCacheService(loggerAdapter),
+		loggerAdapter,
+		nil,
+	).(*RoleService)
+	service.SetUserRepository(&bulkUserRepo{users: map[string]bool{"USER1": true, "USER2": true, "USER3": true}})
+	service.SetAuditService(audit)
+	return service
+}
+
+func TestRoleService_AssignRoleToUsers(t *testing.T) {
+	userRoles := &bulkUserRoleRepo{assigned: map[string]bool{"USER2/ROLE1": true}, failFor: "USER3"}
+	audit := &bulkAuditRecorder{}
+	service := newBulkRoleTestService(userRoles, audit)
+
+	result, err := service.AssignRoleToUsers(context.Background(), "ROLE1", []string{"USER1", "USER2", "USER3", "USER1"}, "ADMIN1")
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, result.Assigned)
+	assert.Equal(t, 1, result.Skipped)
+	assert.Equal(t, 1, result.Failed)
+	require.Len(t, result.Results, 3, "duplicate IDs are collapsed")
+	assert.Equal(t, interfaces.RoleAssignmentStatusAssigned, result.Results[0].Status)
+	assert.Equal(t, interfaces.RoleAssignmentStatusSkipped, result.Results[1].Status)
+	assert.Equal(t, interfaces.RoleAssignmentStatusFailed, result.Results[2].Status)
+	assert.NotEmpty(t, result.Results[2].Error)
+
+	assert.Equal(t, []string{"USER1", "USER3"}, audit.targets)
+	assert.Equal(t, []bool{true, false}, audit.success)
+}
+
+func TestRoleService_AssignRoleToUsers_ValidatesBeforeAssigning(t *testing.T) {
+	oversized := make([]string, MaxBulkRoleAssignmentUsers+1)
+	for i := range oversized {
+		oversized[i] = fmt.Sprintf("USER%d", i)
+	}
+
+	tests := []struct {
+		name    string
+		roleID  string
+		userIDs []string
+		check   func(error) bool
+	}{
+		{name: "unknown role", roleID: "ROLE404", userIDs: []string{"USER1"}, check: errors.IsNotFoundError},
+		{name: "unknown user", roleID: "ROLE1", userIDs: []string{"USER1", "USER404"}, check: errors.IsNotFoundError},
+		{name: "no users", roleID: "ROLE1", userIDs: nil, check: errors.IsValidationError},
+		{name: "blank user ID", roleID: "ROLE1", userIDs: []string{"USER1", " "}, check: errors.IsValidationError},
+		{name: "batch too large", roleID: "ROLE1", userIDs: oversized, check: errors.IsValidationError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRoles := &bulkUserRoleRepo{assigned: map[string]bool{}}
+			service := newBulkRoleTestService(userRoles, &bulkAuditRecorder{})
+
+			_, err := service.AssignRoleToUsers(context.Background(), tt.roleID, tt.userIDs, "ADMIN1")
+			require.Error(t, err)
+			assert.True(t, tt.check(err), err.Error())
+			assert.Empty(t, userRoles.assigned, "nothing is written when validation fails")
+		})
+	}
+}
@@ -0,0 +1,423 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	orgRequests "github.com/Kisanlink/aaa-service/v2/internal/entities/requests/organizations"
+	orgResponses "github.com/Kisanlink/aaa-service/v2/internal/entities/responses/organizations"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// Role assignment constraints
+//
+// An organization may constrain how its roles are assigned: roles that no user may hold together,
+// a cap on how many of a set of roles one user holds, or a cap on how many users hold a role.
+// Assignments that would break a constraint are rejected and audited as security events. Since
+// constraints can be added after roles were handed out, FindRoleConstraintViolations reports the
+// existing assignments that break them; those are left in place for an administrator to resolve.
+
+// SetConstraintRepository enables organization role assignment constraints
+func (s *RoleService) SetConstraintRepository(constraintRepo interfaces.RoleConstraintRepository) {
+	s.constraintRepo = constraintRepo
+}
+
+// CreateRoleConstraint adds a role assignment constraint to an organization. Every covered role
+// must belong to the organization. Existing assignments are not checked; use
+// FindRoleConstraintViolations for that.
+func (s *RoleService) CreateRoleConstraint(ctx context.Context, orgID string, req *orgRequests.CreateRoleConstraintRequest) (*orgResponses.RoleConstraintResponse, error) {
+	s.logger.Info("Creating role constraint", zap.String("orgID", orgID), zap.String("type", req.Type))
+
+	if s.constraintRepo == nil {
+		return nil, errors.NewInternalError(fmt.Errorf("role constraints are not enabled"))
+	}
+	if orgID == "" {
+		return nil, errors.NewValidationError("organization ID is required")
+	}
+
+	roleIDs, err := s.validateRoleConstraintRequest(ctx, orgID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	constraintType := models.RoleConstraintType(req.Type)
+	maxCount := req.MaxCount
+	if constraintType == models.RoleConstraintMutuallyExclusive {
+		maxCount = 0
+	}
+	constraint, err := models.NewRoleConstraint(orgID, strings.TrimSpace(req.Name), constraintType, roleIDs, maxCount)
+	if err != nil {
+		return nil, errors.NewInternalError(err)
+	}
+	constraint.Description = req.Description
+
+	if err := s.constraintRepo.Create(ctx, constraint); err != nil {
+		s.logger.Error("Failed to create role constraint", zap.String("orgID", orgID), zap.Error(err))
+		return nil, errors.NewInternalError(err)
+	}
+
+	s.auditConstraintChange(ctx, constraint, "create_role_constraint")
+	s.logger.Info("Role constraint created", zap.String("orgID", orgID), zap.String("constraintID", constraint.ID))
+	return toRoleConstraintResponse(constraint), nil
+}
+
+// ListRoleConstraints lists the role assignment constraints of an organization
+func (s *RoleService) ListRoleConstraints(ctx context.Context, orgID string) ([]*orgResponses.RoleConstraintResponse, error) {
+	if orgID == "" {
+		return nil, errors.NewValidationError("organization ID is required")
+	}
+
+	result := make([]*orgResponses.RoleConstraintResponse, 0)
+	if s.constraintRepo == nil {
+		return result, nil
+	}
+
+	constraints, err := s.constraintRepo.ListByOrganization(ctx, orgID)
+	if err != nil {
+		s.logger.Error("Failed to list role constraints", zap.String("orgID", orgID), zap.Error(err))
+		return nil, errors.NewInternalError(err)
+	}
+	for _, constraint := range constraints {
+		result = append(result, toRoleConstraintResponse(constraint))
+	}
+	return result, nil
+}
+
+// DeleteRoleConstraint removes a role assignment constraint from an organization
+func (s *RoleService) DeleteRoleConstraint(ctx context.Context, orgID, constraintID string) error {
+	if orgID == "" || constraintID == "" {
+		return errors.NewValidationError("organization ID and constraint ID are required")
+	}
+	if s.constraintRepo == nil {
+		return errors.NewNotFoundError("role constraint not found")
+	}
+
+	constraint, err := s.constraintRepo.GetByID(ctx, constraintID)
+	if err != nil || constraint.OrganizationID != orgID {
+		return errors.NewNotFoundError("role constraint not found")
+	}
+
+	if err := s.constraintRepo.Delete(ctx, constraintID); err != nil {
+		s.logger.Error("Failed to delete role constraint", zap.String("constraintID", constraintID), zap.Error(err))
+		return errors.NewInternalError(err)
+	}
+
+	s.auditConstraintChange(ctx, constraint, "delete_role_constraint")
+	s.logger.Info("Role constraint deleted", zap.String("orgID", orgID), zap.String("constraintID", constraintID))
+	return nil
+}
+
+// FindRoleConstraintViolations reports the existing role assignments of an organization that break
+// its constraints, typically because they were made before the constraint was added
+func (s *RoleService) FindRoleConstraintViolations(ctx context.Context, orgID string) (*orgResponses.RoleConstraintViolationsResponse, error) {
+	if orgID == "" {
+		return nil, errors.NewValidationError("organization ID is required")
+	}
+
+	result := &orgResponses.RoleConstraintViolationsResponse{
+		OrganizationID: orgID,
+		Violations:     make([]*orgResponses.RoleConstraintViolationResponse, 0),
+	}
+	if s.constraintRepo == nil {
+		return result, nil
+	}
+
+	constraints, err := s.constraintRepo.ListByOrganization(ctx, orgID)
+	if err != nil {
+		s.logger.Error("Failed to list role constraints", zap.String("orgID", orgID), zap.Error(err))
+		return nil, errors.NewInternalError(err)
+	}
+
+	for _, constraint := range constraints {
+		violations, err := s.findConstraintViolations(ctx, constraint)
+		if err != nil {
+			s.logger.Error("Failed to check role constraint", zap.String("constraintID", constraint.ID), zap.Error(err))
+			return nil, errors.NewInternalError(err)
+		}
+		result.Violations = append(result.Violations, violations...)
+	}
+
+	return result, nil
+}
+
+// findConstraintViolations checks the current holders of the roles a constraint covers
+func (s *RoleService) findConstraintViolations(ctx context.Context, constraint *models.RoleConstraint) ([]*orgResponses.RoleConstraintViolationResponse, error) {
+	limit := constraint.Limit()
+	rolesByUser := make(map[string][]string)
+	var violations []*orgResponses.RoleConstraintViolationResponse
+
+	for _, roleID := range constraint.GetRoleIDs() {
+		holders, err := s.roleHolders(ctx, roleID)
+		if err != nil {
+			return nil, err
+		}
+		for _, userID := range holders {
+			rolesByUser[userID] = append(rolesByUser[userID], roleID)
+		}
+
+		if constraint.Type == models.RoleConstraintMaxHolders && len(holders) > limit {
+			violations = append(violations, &orgResponses.RoleConstraintViolationResponse{
+				ConstraintID:   constraint.ID,
+				ConstraintName: constraint.Name,
+				Type:           string(constraint.Type),
+				UserIDs:        holders,
+				RoleIDs:        []string{roleID},
+				Message: fmt.Sprintf("role '%s' has %d holders, more than the %d allowed by constraint '%s'",
+					s.roleName(ctx, roleID), len(holders), limit, constraint.Name),
+			})
+		}
+	}
+
+	if constraint.Type == models.RoleConstraintMaxHolders {
+		return violations, nil
+	}
+
+	userIDs := make([]string, 0, len(rolesByUser))
+	for userID := range rolesByUser {
+		userIDs = append(userIDs, userID)
+	}
+	sort.Strings(userIDs)
+
+	for _, userID := range userIDs {
+		held := rolesByUser[userID]
+		if len(held) <= limit {
+			continue
+		}
+		violations = append(violations, &orgResponses.RoleConstraintViolationResponse{
+			ConstraintID:   constraint.ID,
+			ConstraintName: constraint.Name,
+			Type:           string(constraint.Type),
+			UserIDs:        []string{userID},
+			RoleIDs:        held,
+			Message: fmt.Sprintf("user %s holds %s, more than the %d allowed by constraint '%s'",
+				userID, s.roleNames(ctx, held), limit, constraint.Name),
+		})
+	}
+
+	return violations, nil
+}
+
+// checkRoleConstraints rejects assigning the role to the user when that would break a constraint
+// of the organization the role belongs to. Global roles are not constrained.
+func (s *RoleService) checkRoleConstraints(ctx context.Context, userID string, role *models.Role) error {
+	return s.checkRoleConstraintsHolding(ctx, userID, role, nil)
+}
+
+// checkRoleConstraintsHolding checks the role against the constraints as if the user held the
+// roles in heldRoleIDs; a nil set stands for the roles the user currently holds
+func (s *RoleService) checkRoleConstraintsHolding(ctx context.Context, userID string, role *models.Role, heldRoleIDs map[string]bool) error {
+	if s.constraintRepo == nil || role.OrganizationID == nil || *role.OrganizationID == "" {
+		return nil
+	}
+	orgID := *role.OrganizationID
+
+	constraints, err := s.constraintRepo.ListByOrganization(ctx, orgID)
+	if err != nil {
+		s.logger.Error("Failed to load role constraints", zap.String("orgID", orgID), zap.Error(err))
+		return fmt.Errorf("failed to check role constraints: %w", err)
+	}
+
+	for _, constraint := range constraints {
+		if !constraint.Covers(role.ID) {
+			continue
+		}
+
+		var violation string
+		switch constraint.Type {
+		case models.RoleConstraintMaxHolders:
+			holders, err := s.roleHolders(ctx, role.ID)
+			if err != nil {
+				return fmt.Errorf("failed to check role constraints: %w", err)
+			}
+			if len(holders) >= constraint.Limit() {
+				violation = fmt.Sprintf("role '%s' already has %d holders, the maximum allowed by constraint '%s'",
+					role.Name, len(holders), constraint.Name)
+			}
+		default:
+			if heldRoleIDs == nil {
+				if heldRoleIDs, err = s.activeRoleIDs(ctx, userID); err != nil {
+					return fmt.Errorf("failed to check role constraints: %w", err)
+				}
+			}
+			var held []string
+			for _, roleID := range constraint.GetRoleIDs() {
+				if roleID != role.ID && heldRoleIDs[roleID] {
+					held = append(held, roleID)
+				}
+			}
+			if len(held) >= constraint.Limit() {
+				if constraint.Type == models.RoleConstraintMutuallyExclusive {
+					violation = fmt.Sprintf("role '%s' cannot be held together with %s under constraint '%s'",
+						role.Name, s.roleNames(ctx, held), constraint.Name)
+				} else {
+					violation = fmt.Sprintf("user already holds %s, the maximum of %d allowed by constraint '%s'",
+						s.roleNames(ctx, held), constraint.Limit(), constraint.Name)
+				}
+			}
+		}
+
+		if violation != "" {
+			s.logger.Warn("Role assignment rejected by constraint",
+				zap.String("userID", userID),
+				zap.String("roleID", role.ID),
+				zap.String("constraintID", constraint.ID))
+			s.auditConstraintRejection(ctx, userID, role, constraint, violation)
+			return errors.NewConflictError(violation)
+		}
+	}
+
+	return nil
+}
+
+// activeRoleIDs returns the IDs of the roles a user currently holds
+func (s *RoleService) activeRoleIDs(ctx context.Context, userID string) (map[string]bool, error) {
+	userRoles, err := s.userRoleRepo.GetActiveRolesByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	roleIDs := make(map[string]bool, len(userRoles))
+	for _, userRole := range userRoles {
+		roleIDs[userRole.RoleID] = true
+	}
+	return roleIDs, nil
+}
+
+// roleHolders returns the sorted IDs of the users currently holding a role
+func (s *RoleService) roleHolders(ctx context.Context, roleID string) ([]string, error) {
+	userRoles, err := s.userRoleRepo.GetByRoleID(ctx, roleID)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool, len(userRoles))
+	holders := make([]string, 0, len(userRoles))
+	for _, userRole := range userRoles {
+		if !seen[userRole.UserID] {
+			seen[userRole.UserID] = true
+			holders = append(holders, userRole.UserID)
+		}
+	}
+	sort.Strings(holders)
+	return holders, nil
+}
+
+// validateRoleConstraintRequest checks a new constraint and returns its de-duplicated role IDs
+func (s *RoleService) validateRoleConstraintRequest(ctx context.Context, orgID string, req *orgRequests.CreateRoleConstraintRequest) ([]string, error) {
+	if req == nil || strings.TrimSpace(req.Name) == "" {
+		return nil, errors.NewValidationError("constraint name is required")
+	}
+
+	constraintType := models.RoleConstraintType(req.Type)
+	if !constraintType.IsValid() {
+		return nil, errors.NewValidationError(fmt.Sprintf("unknown constraint type %q", req.Type))
+	}
+
+	seen := make(map[string]bool, len(req.RoleIDs))
+	roleIDs := make([]string, 0, len(req.RoleIDs))
+	for _, roleID := range req.RoleIDs {
+		roleID = strings.TrimSpace(roleID)
+		if roleID == "" || seen[roleID] {
+			continue
+		}
+		seen[roleID] = true
+		roleIDs = append(roleIDs, roleID)
+	}
+
+	switch {
+	case constraintType == models.RoleConstraintMutuallyExclusive && len(roleIDs) < 2:
+		return nil, errors.NewValidationError("a mutually exclusive constraint needs at least two roles")
+	case len(roleIDs) == 0:
+		return nil, errors.NewValidationError("at least one role is required")
+	case constraintType != models.RoleConstraintMutuallyExclusive && req.MaxCount < 1:
+		return nil, errors.NewValidationError("max_count must be at least 1")
+	}
+
+	for _, roleID := range roleIDs {
+		role := &models.Role{}
+		if _, err := s.roleRepo.GetByID(ctx, roleID, role); err != nil || role.DeletedAt != nil {
+			return nil, errors.NewValidationError(fmt.Sprintf("role %s not found", roleID))
+		}
+		if role.OrganizationID == nil || *role.OrganizationID != orgID {
+			return nil, errors.NewValidationError(fmt.Sprintf("role '%s' does not belong to the organization", role.Name))
+		}
+	}
+
+	return roleIDs, nil
+}
+
+// roleName returns the name of a role for messages, falling back to its ID
+func (s *RoleService) roleName(ctx context.Context, roleID string) string {
+	role := &models.Role{}
+	if _, err := s.roleRepo.GetByID(ctx, roleID, role); err != nil || role.Name == "" {
+		return roleID
+	}
+	return role.Name
+}
+
+func (s *RoleService) roleNames(ctx context.Context, roleIDs []string) string {
+	names := make([]string, 0, len(roleIDs))
+	for _, roleID := range roleIDs {
+		names = append(names, fmt.Sprintf("'%s'", s.roleName(ctx, roleID)))
+	}
+	return strings.Join(names, ", ")
+}
+
+// auditConstraintRejection records a rejected assignment as a security event
+func (s *RoleService) auditConstraintRejection(ctx context.Context, userID string, role *models.Role, constraint *models.RoleConstraint, violation string) {
+	if s.auditService == nil {
+		return
+	}
+
+	actorID := "system"
+	if ctxUserID, ok := ctx.Value("user_id").(string); ok && ctxUserID != "" {
+		actorID = ctxUserID
+	}
+
+	s.auditService.LogSecurityEvent(ctx, actorID, "role_constraint_violation", "role", false, map[string]interface{}{
+		"target_user_id":  userID,
+		"role_id":         role.ID,
+		"role_name":       role.Name,
+		"organization_id": constraint.OrganizationID,
+		"constraint_id":   constraint.ID,
+		"constraint_name": constraint.Name,
+		"constraint_type": string(constraint.Type),
+		"reason":          violation,
+	})
+}
+
+func (s *RoleService) auditConstraintChange(ctx context.Context, constraint *models.RoleConstraint, operation string) {
+	if s.auditService == nil {
+		return
+	}
+
+	actorID := "system"
+	if ctxUserID, ok := ctx.Value("user_id").(string); ok && ctxUserID != "" {
+		actorID = ctxUserID
+	}
+
+	s.auditService.LogSecurityEvent(ctx, actorID, operation, "role_constraint", true, map[string]interface{}{
+		"organization_id": constraint.OrganizationID,
+		"constraint_id":   constraint.ID,
+		"constraint_name": constraint.Name,
+		"constraint_type": string(constraint.Type),
+		"role_ids":        constraint.GetRoleIDs(),
+		"max_count":       constraint.MaxCount,
+	})
+}
+
+func toRoleConstraintResponse(constraint *models.RoleConstraint) *orgResponses.RoleConstraintResponse {
+	return &orgResponses.RoleConstraintResponse{
+		ID:             constraint.ID,
+		OrganizationID: constraint.OrganizationID,
+		Name:           constraint.Name,
+		Description:    constraint.Description,
+		Type:           string(constraint.Type),
+		RoleIDs:        constraint.GetRoleIDs(),
+		MaxCount:       constraint.MaxCount,
+		CreatedAt:      constraint.CreatedAt,
+	}
+}
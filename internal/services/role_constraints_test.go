@@ -0,0 +1,250 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	orgRequests "github.com/Kisanlink/aaa-service/v2/internal/entities/requests/organizations"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/Kisanlink/aaa-service/v2/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// constraintUserRoleRepo keeps user-role assignments as "user/role" pairs
+type constraintUserRoleRepo struct {
+	interfaces.UserRoleRepository
+	assignments []string
+}
+
+func (r *constraintUserRoleRepo) has(userID, roleID string) bool {
+	for _, a := range r.assignments {
+		if a == userID+"/"+roleID {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *constraintUserRoleRepo) IsRoleAssigned(ctx context.Context, userID, roleID string) (bool, error) {
+	return r.has(userID, roleID), nil
+}
+
+func (r *constraintUserRoleRepo) AssignRole(ctx context.Context, userID, roleID string) error {
+	r.assignments = append(r.assignments, userID+"/"+roleID)
+	return nil
+}
+
+func (r *constraintUserRoleRepo) GetActiveRolesByUserID(ctx context.Context, userID string) ([]*models.UserRole, error) {
+	return r.find(func(u, _ string) bool { return u == userID }), nil
+}
+
+func (r *constraintUserRoleRepo) GetByRoleID(ctx context.Context, roleID string) ([]*models.UserRole, error) {
+	return r.find(func(_, ro string) bool { return ro == roleID }), nil
+}
+
+func (r *constraintUserRoleRepo) find(match func(userID, roleID string) bool) []*models.UserRole {
+	var result []*models.UserRole
+	for _, a := range r.assignments {
+		userID, roleID, _ := strings.Cut(a, "/")
+		if match(userID, roleID) {
+			result = append(result, models.NewUserRole(userID, roleID))
+		}
+	}
+	return result
+}
+
+// memoryConstraintRepo stores role constraints in memory
+type memoryConstraintRepo struct {
+	constraints []*models.RoleConstraint
+}
+
+func (r *memoryConstraintRepo) Create(ctx context.Context, constraint *models.RoleConstraint) error {
+	constraint.ID = fmt.Sprintf("RCON%d", len(r.constraints)+1)
+	r.constraints = append(r.constraints, constraint)
+	return nil
+}
+
+func (r *memoryConstraintRepo) GetByID(ctx context.Context, id string) (*models.RoleConstraint, error) {
+	for _, c := range r.constraints {
+		if c.ID == id {
+			return c, nil
+		}
+	}
+	return nil, fmt.Errorf("role constraint not found: %s", id)
+}
+
+func (r *memoryConstraintRepo) ListByOrganization(ctx context.Context, organizationID string) ([]*models.RoleConstraint, error) {
+	var constraints []*models.RoleConstraint
+	for _, c := range r.constraints {
+		if c.OrganizationID == organizationID {
+			constraints = append(constraints, c)
+		}
+	}
+	return constraints, nil
+}
+
+func (r *memoryConstraintRepo) Delete(ctx context.Context, id string) error {
+	for i, c := range r.constraints {
+		if c.ID == id {
+			r.constraints = append(r.constraints[:i], r.constraints[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("role constraint not found")
+}
+
+// securityEventRecorder records security events by action
+type securityEventRecorder struct {
+	interfaces.AuditService
+	actions []string
+}
+
+func (a *securityEventRecorder) LogSecurityEvent(ctx context.Context, userID, action, resource string, success bool, details map[string]interface{}) {
+	a.actions = append(a.actions, action)
+}
+
+func (a *securityEventRecorder) LogRoleOperation(ctx context.Context, actorUserID, targetUserID, roleID, operation string, success bool, details map[string]interface{}) {
+}
+
+func newConstraintTestService(userRoles *constraintUserRoleRepo) (*RoleService, *securityEventRecorder) {
+	loggerAdapter := utils.NewLoggerAdapter(zap.NewNop())
+	roles := map[string]*models.Role{}
+	for _, name := range []string{"approver", "requester", "admin", "auditor"} {
+		role := models.NewOrgRole(name, name, "ORG1")
+		role.ID = name
+		roles[name] = role
+	}
+	global := models.NewRole("viewer", "viewer", models.RoleScopeGlobal)
+	global.ID = "viewer"
+	roles["viewer"] = global
+	other := models.NewOrgRole("other", "other", "ORG2")
+	other.ID = "other"
+	roles["other"] = other
+
+	service := NewRoleService(
+		&bulkRoleRepo{roles: roles},
+		userRoles,
+		NewNoOpCacheService(loggerAdapter),
+		loggerAdapter,
+		nil,
+	).(*RoleService)
+	audit := &securityEventRecorder{}
+	service.SetAuditService(audit)
+	service.SetConstraintRepository(&memoryConstraintRepo{})
+	return service, audit
+}
+
+func TestRoleService_AssignRole_EnforcesConstraints(t *testing.T) {
+	userRoles := &constraintUserRoleRepo{assignments: []string{"USER1/approver", "USER2/admin"}}
+	service, audit := newConstraintTestService(userRoles)
+	ctx := context.Background()
+
+	_, err := service.CreateRoleConstraint(ctx, "ORG1", &orgRequests.CreateRoleConstraintRequest{
+		Name: "separation of duties", Type: "mutually_exclusive", RoleIDs: []string{"approver", "requester"},
+	})
+	require.NoError(t, err)
+	_, err = service.CreateRoleConstraint(ctx, "ORG1", &orgRequests.CreateRoleConstraintRequest{
+		Name: "single admin", Type: "max_holders", RoleIDs: []string{"admin"}, MaxCount: 1,
+	})
+	require.NoError(t, err)
+
+	err = service.AssignRole(ctx, "USER1", "requester")
+	require.True(t, errors.IsConflictError(err))
+	assert.Contains(t, err.Error(), "'approver'")
+	assert.Contains(t, err.Error(), "separation of duties")
+
+	err = service.AssignRole(ctx, "USER3", "admin")
+	require.True(t, errors.IsConflictError(err))
+	assert.Contains(t, err.Error(), "single admin")
+
+	assert.NoError(t, service.AssignRole(ctx, "USER3", "requester"), "a user without the other role may take it")
+	assert.NoError(t, service.AssignRole(ctx, "USER1", "auditor"), "uncovered roles are not constrained")
+	assert.NoError(t, service.AssignRole(ctx, "USER1", "viewer"), "global roles are not constrained")
+
+	assert.Equal(t, []string{"create_role_constraint", "create_role_constraint", "role_constraint_violation", "role_constraint_violation"}, audit.actions)
+
+	service.SetUserRepository(&bulkUserRepo{users: map[string]bool{"USER4": true}})
+	result, err := service.AssignRoleToUsers(ctx, "admin", []string{"USER4"}, "ADMIN")
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Failed, "bulk assignment applies the same constraints")
+	assert.Contains(t, result.Results[0].Error, "single admin")
+}
+
+func TestRoleService_AssignRole_MaxRolesPerUser(t *testing.T) {
+	userRoles := &constraintUserRoleRepo{assignments: []string{"USER1/approver"}}
+	service, _ := newConstraintTestService(userRoles)
+	ctx := context.Background()
+
+	_, err := service.CreateRoleConstraint(ctx, "ORG1", &orgRequests.CreateRoleConstraintRequest{
+		Name: "two duties", Type: "max_roles_per_user", RoleIDs: []string{"approver", "requester", "auditor"}, MaxCount: 2,
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, service.AssignRole(ctx, "USER1", "requester"))
+	err = service.AssignRole(ctx, "USER1", "auditor")
+	assert.True(t, errors.IsConflictError(err))
+}
+
+func TestRoleService_CreateRoleConstraint_Validation(t *testing.T) {
+	service, _ := newConstraintTestService(&constraintUserRoleRepo{})
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		req  *orgRequests.CreateRoleConstraintRequest
+	}{
+		{"unknown type", &orgRequests.CreateRoleConstraintRequest{Name: "x", Type: "forbidden", RoleIDs: []string{"admin"}}},
+		{"exclusive needs two roles", &orgRequests.CreateRoleConstraintRequest{Name: "x", Type: "mutually_exclusive", RoleIDs: []string{"admin", "admin"}}},
+		{"cap needs max count", &orgRequests.CreateRoleConstraintRequest{Name: "x", Type: "max_holders", RoleIDs: []string{"admin"}}},
+		{"unknown role", &orgRequests.CreateRoleConstraintRequest{Name: "x", Type: "max_holders", RoleIDs: []string{"missing"}, MaxCount: 1}},
+		{"role of another organization", &orgRequests.CreateRoleConstraintRequest{Name: "x", Type: "max_holders", RoleIDs: []string{"other"}, MaxCount: 1}},
+		{"global role", &orgRequests.CreateRoleConstraintRequest{Name: "x", Type: "max_holders", RoleIDs: []string{"viewer"}, MaxCount: 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.CreateRoleConstraint(ctx, "ORG1", tt.req)
+			assert.True(t, errors.IsValidationError(err), "got %v", err)
+		})
+	}
+}
+
+func TestRoleService_FindRoleConstraintViolations(t *testing.T) {
+	userRoles := &constraintUserRoleRepo{assignments: []string{
+		"USER1/approver", "USER1/requester", "USER2/approver", "USER2/admin", "USER3/admin",
+	}}
+	service, _ := newConstraintTestService(userRoles)
+	ctx := context.Background()
+
+	exclusive, err := service.CreateRoleConstraint(ctx, "ORG1", &orgRequests.CreateRoleConstraintRequest{
+		Name: "separation of duties", Type: "mutually_exclusive", RoleIDs: []string{"approver", "requester"},
+	})
+	require.NoError(t, err)
+	capped, err := service.CreateRoleConstraint(ctx, "ORG1", &orgRequests.CreateRoleConstraintRequest{
+		Name: "single admin", Type: "max_holders", RoleIDs: []string{"admin"}, MaxCount: 1,
+	})
+	require.NoError(t, err)
+
+	result, err := service.FindRoleConstraintViolations(ctx, "ORG1")
+	require.NoError(t, err)
+	require.Len(t, result.Violations, 2)
+
+	assert.Equal(t, exclusive.ID, result.Violations[0].ConstraintID)
+	assert.Equal(t, []string{"USER1"}, result.Violations[0].UserIDs)
+	assert.Equal(t, []string{"approver", "requester"}, result.Violations[0].RoleIDs)
+
+	assert.Equal(t, capped.ID, result.Violations[1].ConstraintID)
+	assert.Equal(t, []string{"USER2", "USER3"}, result.Violations[1].UserIDs)
+
+	require.NoError(t, service.DeleteRoleConstraint(ctx, "ORG1", capped.ID))
+	assert.True(t, errors.IsNotFoundError(service.DeleteRoleConstraint(ctx, "ORG2", exclusive.ID)), "constraints of another organization are not found")
+
+	result, err = service.FindRoleConstraintViolations(ctx, "ORG1")
+	require.NoError(t, err)
+	assert.Len(t, result.Violations, 1)
+}
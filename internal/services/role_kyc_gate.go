@@ -0,0 +1,184 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// KYC-gated roles
+//
+// A role whose metadata sets "kyc_gated" may only be held by users whose Aadhaar is verified, e.g.
+// the roles an FPO hands out to its office bearers. Assigning such a role to an unverified user
+// records a pending assignment that ActivatePendingRoleAssignments turns into an active one once
+// the verification completes; with "kyc_gate_action": "reject" the assignment is refused instead.
+// Each step of the gated flow is audited as a security event.
+
+// SetKYCGate enables KYC-gated roles. The profile reader provides users' Aadhaar verification
+// state; without a pending assignment repository gated assignments to unverified users are refused.
+func (s *RoleService) SetKYCGate(profileReader interfaces.UserProfileReader, pendingRepo interfaces.PendingRoleAssignmentRepository) {
+	s.profileReader = profileReader
+	s.pendingRepo = pendingRepo
+}
+
+// ActivatePendingRoleAssignments assigns a user the KYC-gated roles held back until their Aadhaar
+// was verified and returns how many were activated. Pending assignments that can no longer be made,
+// because the role was removed or deactivated or would now break a role constraint, are dropped.
+func (s *RoleService) ActivatePendingRoleAssignments(ctx context.Context, userID string) (int, error) {
+	if s.pendingRepo == nil || s.profileReader == nil {
+		return 0, nil
+	}
+	if userID == "" {
+		return 0, errors.NewValidationError("user ID cannot be empty")
+	}
+
+	pending, err := s.pendingRepo.ListByUser(ctx, userID)
+	if err != nil {
+		s.logger.Error("Failed to list pending role assignments", zap.String("userID", userID), zap.Error(err))
+		return 0, fmt.Errorf("failed to list pending role assignments: %w", err)
+	}
+	if len(pending) == 0 {
+		return 0, nil
+	}
+	if !s.isKYCVerified(ctx, userID) {
+		s.logger.Warn("Pending role assignments kept, user is not KYC verified", zap.String("userID", userID))
+		return 0, nil
+	}
+
+	activated := 0
+	for _, assignment := range pending {
+		role := &models.Role{}
+		if _, err := s.roleRepo.GetByID(ctx, assignment.RoleID, role); err != nil || !role.IsActive {
+			s.logger.Warn("Dropping pending assignment of unavailable role",
+				zap.String("userID", userID),
+				zap.String("roleID", assignment.RoleID))
+			s.auditKYCGate(ctx, "role_assignment_kyc_activation_failed", false, userID, assignment.RoleID, "", map[string]interface{}{
+				"requested_by": assignment.RequestedBy,
+				"reason":       "role not found or not active",
+			})
+			s.dropPendingAssignment(ctx, assignment)
+			continue
+		}
+
+		isAssigned, err := s.userRoleRepo.IsRoleAssigned(ctx, userID, role.ID)
+		if err != nil {
+			s.logger.Error("Failed to check existing role assignment", zap.String("userID", userID), zap.String("roleID", role.ID), zap.Error(err))
+			return activated, fmt.Errorf("failed to activate pending role assignments: %w", err)
+		}
+		if !isAssigned {
+			if err := s.checkRoleConstraints(ctx, userID, role); err != nil {
+				if !errors.IsConflictError(err) {
+					return activated, err
+				}
+				s.auditKYCGate(ctx, "role_assignment_kyc_activation_failed", false, userID, role.ID, role.Name, map[string]interface{}{
+					"requested_by": assignment.RequestedBy,
+					"reason":       err.Error(),
+				})
+				s.dropPendingAssignment(ctx, assignment)
+				continue
+			}
+			if err := s.userRoleRepo.AssignRole(ctx, userID, role.ID); err != nil {
+				s.logger.Error("Failed to activate pending role assignment", zap.String("userID", userID), zap.String("roleID", role.ID), zap.Error(err))
+				return activated, fmt.Errorf("failed to activate pending role assignments: %w", err)
+			}
+			activated++
+			s.auditKYCGate(ctx, "role_assignment_kyc_activated", true, userID, role.ID, role.Name, map[string]interface{}{
+				"requested_by":       assignment.RequestedBy,
+				"pending_assignment": assignment.ID,
+			})
+		}
+		s.dropPendingAssignment(ctx, assignment)
+	}
+
+	if activated > 0 {
+		s.invalidateUserRoleCache(ctx, userID)
+		s.invalidateUserPermissionCache(ctx, userID)
+	}
+
+	s.logger.Info("Pending role assignments activated", zap.String("userID", userID), zap.Int("count", activated))
+	return activated, nil
+}
+
+// deferUntilKYCVerified holds back assigning a KYC-gated role to a user whose Aadhaar is not
+// verified. It reports whether the assignment was recorded as pending, and refuses it when the role
+// rejects unverified users.
+func (s *RoleService) deferUntilKYCVerified(ctx context.Context, userID string, role *models.Role) (bool, error) {
+	gated, action := role.KYCGate()
+	if !gated || s.profileReader == nil || s.isKYCVerified(ctx, userID) {
+		return false, nil
+	}
+
+	if action == models.KYCGateActionReject || s.pendingRepo == nil {
+		s.logger.Warn("Role assignment rejected, user is not KYC verified", zap.String("userID", userID), zap.String("roleID", role.ID))
+		s.auditKYCGate(ctx, "role_assignment_kyc_rejected", false, userID, role.ID, role.Name, nil)
+		return false, errors.NewForbiddenError(fmt.Sprintf("role '%s' requires a verified Aadhaar", role.Name))
+	}
+
+	exists, err := s.pendingRepo.Exists(ctx, userID, role.ID)
+	if err != nil {
+		s.logger.Error("Failed to check pending role assignment", zap.String("userID", userID), zap.String("roleID", role.ID), zap.Error(err))
+		return false, fmt.Errorf("failed to check pending role assignment: %w", err)
+	}
+	if exists {
+		return false, errors.NewConflictError("role assignment already pending KYC verification")
+	}
+
+	assignment := models.NewPendingRoleAssignment(userID, role.ID, kycGateActor(ctx))
+	if err := s.pendingRepo.Create(ctx, assignment); err != nil {
+		s.logger.Error("Failed to store pending role assignment", zap.String("userID", userID), zap.String("roleID", role.ID), zap.Error(err))
+		return false, fmt.Errorf("failed to store pending role assignment: %w", err)
+	}
+
+	s.logger.Info("Role assignment pending KYC verification", zap.String("userID", userID), zap.String("roleID", role.ID))
+	s.auditKYCGate(ctx, "role_assignment_pending_kyc", true, userID, role.ID, role.Name, map[string]interface{}{
+		"pending_assignment": assignment.ID,
+	})
+	return true, nil
+}
+
+// isKYCVerified reports whether a user's Aadhaar is verified; users without a readable profile are
+// treated as unverified
+func (s *RoleService) isKYCVerified(ctx context.Context, userID string) bool {
+	profile, err := s.profileReader.GetByUserID(ctx, userID)
+	if err != nil || profile == nil {
+		s.logger.Debug("No profile to read KYC state from", zap.String("userID", userID), zap.Error(err))
+		return false
+	}
+	return profile.AadhaarVerified
+}
+
+func (s *RoleService) dropPendingAssignment(ctx context.Context, assignment *models.PendingRoleAssignment) {
+	if err := s.pendingRepo.Delete(ctx, assignment.ID); err != nil {
+		s.logger.Warn("Failed to delete pending role assignment", zap.String("id", assignment.ID), zap.Error(err))
+	}
+}
+
+func (s *RoleService) auditKYCGate(ctx context.Context, action string, success bool, userID, roleID, roleName string, extra map[string]interface{}) {
+	if s.auditService == nil {
+		return
+	}
+
+	details := map[string]interface{}{
+		"target_user_id": userID,
+		"role_id":        roleID,
+	}
+	if roleName != "" {
+		details["role_name"] = roleName
+	}
+	for key, value := range extra {
+		details[key] = value
+	}
+
+	s.auditService.LogSecurityEvent(ctx, kycGateActor(ctx), action, "role", success, details)
+}
+
+func kycGateActor(ctx context.Context) string {
+	if ctxUserID, ok := ctx.Value("user_id").(string); ok && ctxUserID != "" {
+		return ctxUserID
+	}
+	return "system"
+}
@@ -0,0 +1,206 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/Kisanlink/aaa-service/v2/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// kycProfileReader reports the users in verified as having a verified Aadhaar
+type kycProfileReader struct {
+	verified map[string]bool
+}
+
+func (r *kycProfileReader) GetByUserID(ctx context.Context, userID string) (*models.UserProfile, error) {
+	if _, ok := r.verified[userID]; !ok {
+		return nil, fmt.Errorf("profile not found with user ID: %s", userID)
+	}
+	return &models.UserProfile{UserID: userID, AadhaarVerified: r.verified[userID]}, nil
+}
+
+// memoryPendingRepo stores pending role assignments in memory
+type memoryPendingRepo struct {
+	assignments []*models.PendingRoleAssignment
+}
+
+func (r *memoryPendingRepo) Create(ctx context.Context, assignment *models.PendingRoleAssignment) error {
+	assignment.SetID(fmt.Sprintf("PRA%d", len(r.assignments)+1))
+	r.assignments = append(r.assignments, assignment)
+	return nil
+}
+
+func (r *memoryPendingRepo) Exists(ctx context.Context, userID, roleID string) (bool, error) {
+	for _, a := range r.assignments {
+		if a.UserID == userID && a.RoleID == roleID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *memoryPendingRepo) ListByUser(ctx context.Context, userID string) ([]*models.PendingRoleAssignment, error) {
+	var assignments []*models.PendingRoleAssignment
+	for _, a := range r.assignments {
+		if a.UserID == userID {
+			assignments = append(assignments, a)
+		}
+	}
+	return assignments, nil
+}
+
+func (r *memoryPendingRepo) Delete(ctx context.Context, id string) error {
+	for i, a := range r.assignments {
+		if a.ID == id {
+			r.assignments = append(r.assignments[:i], r.assignments[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("pending role assignment not found")
+}
+
+func kycGatedRole(id, metadata string) *models.Role {
+	role := models.NewOrgRole(id, id, "ORG1")
+	role.ID = id
+	if metadata != "" {
+		role.Metadata = &metadata
+	}
+	return role
+}
+
+func newKYCGateTestService() (*RoleService, *constraintUserRoleRepo, *kycProfileReader, *memoryPendingRepo, *securityEventRecorder) {
+	loggerAdapter := utils.NewLoggerAdapter(zap.NewNop())
+	roles := map[string]*models.Role{}
+	for _, role := range []*models.Role{
+		kycGatedRole("office_bearer", `{"kyc_gated": true}`),
+		kycGatedRole("treasurer", `{"kyc_gated": true, "kyc_gate_action": "reject"}`),
+		kycGatedRole("member", `{"kyc_gated": false}`),
+	} {
+		roles[role.ID] = role
+	}
+
+	userRoles := &constraintUserRoleRepo{}
+	service := NewRoleService(
+		&bulkRoleRepo{roles: roles},
+		userRoles,
+		NewNoOpCacheService(loggerAdapter),
+		loggerAdapter,
+		nil,
+	).(*RoleService)
+	audit := &securityEventRecorder{}
+	profiles := &kycProfileReader{verified: map[string]bool{"verified": true, "unverified": false}}
+	pending := &memoryPendingRepo{}
+	service.SetAuditService(audit)
+	service.SetKYCGate(profiles, pending)
+	return service, userRoles, profiles, pending, audit
+}
+
+func TestRole_KYCGate(t *testing.T) {
+	tests := []struct {
+		metadata   string
+		wantGated  bool
+		wantAction models.KYCGateAction
+	}{
+		{metadata: "", wantGated: false},
+		{metadata: `{"kyc_gated": false}`, wantGated: false},
+		{metadata: `not json`, wantGated: false},
+		{metadata: `{"kyc_gated": true}`, wantGated: true, wantAction: models.KYCGateActionPending},
+		{metadata: `{"kyc_gated": true, "kyc_gate_action": "reject"}`, wantGated: true, wantAction: models.KYCGateActionReject},
+		{metadata: `{"kyc_gated": true, "kyc_gate_action": "unknown"}`, wantGated: true, wantAction: models.KYCGateActionPending},
+	}
+
+	for _, tt := range tests {
+		gated, action := kycGatedRole("role", tt.metadata).KYCGate()
+		assert.Equal(t, tt.wantGated, gated, tt.metadata)
+		assert.Equal(t, tt.wantAction, action, tt.metadata)
+	}
+}
+
+func TestRoleService_AssignRole_KYCGated(t *testing.T) {
+	ctx := context.Background()
+	service, userRoles, _, pending, audit := newKYCGateTestService()
+
+	require.NoError(t, service.AssignRole(ctx, "verified", "office_bearer"))
+	require.NoError(t, service.AssignRole(ctx, "unverified", "member"), "roles that are not gated are assigned as usual")
+	assert.True(t, userRoles.has("verified", "office_bearer"))
+	assert.True(t, userRoles.has("unverified", "member"))
+
+	err := service.AssignRole(ctx, "unverified", "office_bearer")
+	assert.ErrorIs(t, err, models.ErrRoleAssignmentPendingKYC)
+	assert.False(t, userRoles.has("unverified", "office_bearer"))
+	require.Len(t, pending.assignments, 1)
+	assert.Equal(t, "system", pending.assignments[0].RequestedBy)
+
+	err = service.AssignRole(ctx, "unverified", "office_bearer")
+	assert.True(t, errors.IsConflictError(err), "the role is already pending")
+
+	err = service.AssignRole(ctx, "no-profile", "treasurer")
+	assert.True(t, errors.IsForbiddenError(err), "users without a profile are not verified")
+	assert.Len(t, pending.assignments, 1, "rejecting roles are not left pending")
+
+	assert.Equal(t, []string{"role_assignment_pending_kyc", "role_assignment_kyc_rejected"}, audit.actions)
+}
+
+func TestRoleService_ActivatePendingRoleAssignments(t *testing.T) {
+	ctx := context.Background()
+	service, userRoles, profiles, pending, audit := newKYCGateTestService()
+
+	require.ErrorIs(t, service.AssignRole(ctx, "unverified", "office_bearer"), models.ErrRoleAssignmentPendingKYC)
+
+	activated, err := service.ActivatePendingRoleAssignments(ctx, "unverified")
+	require.NoError(t, err)
+	assert.Zero(t, activated, "assignments stay pending until the verification completes")
+	assert.Len(t, pending.assignments, 1)
+
+	profiles.verified["unverified"] = true
+	activated, err = service.ActivatePendingRoleAssignments(ctx, "unverified")
+	require.NoError(t, err)
+	assert.Equal(t, 1, activated)
+	assert.True(t, userRoles.has("unverified", "office_bearer"))
+	assert.Empty(t, pending.assignments)
+	assert.Equal(t, []string{"role_assignment_pending_kyc", "role_assignment_kyc_activated"}, audit.actions)
+
+	activated, err = service.ActivatePendingRoleAssignments(ctx, "unverified")
+	require.NoError(t, err)
+	assert.Zero(t, activated, "pending assignments are activated once")
+}
+
+func TestRoleService_ActivatePendingRoleAssignments_DropsUnavailableRoles(t *testing.T) {
+	ctx := context.Background()
+	service, userRoles, profiles, pending, audit := newKYCGateTestService()
+
+	require.ErrorIs(t, service.AssignRole(ctx, "unverified", "office_bearer"), models.ErrRoleAssignmentPendingKYC)
+	service.roleRepo.(*bulkRoleRepo).roles["office_bearer"].IsActive = false
+
+	profiles.verified["unverified"] = true
+	activated, err := service.ActivatePendingRoleAssignments(ctx, "unverified")
+	require.NoError(t, err)
+	assert.Zero(t, activated)
+	assert.False(t, userRoles.has("unverified", "office_bearer"))
+	assert.Empty(t, pending.assignments)
+	assert.Contains(t, audit.actions, "role_assignment_kyc_activation_failed")
+}
+
+func TestRoleService_AssignRoleToUsers_KYCGated(t *testing.T) {
+	ctx := context.Background()
+	service, userRoles, _, pending, _ := newKYCGateTestService()
+	service.SetUserRepository(&bulkUserRepo{users: map[string]bool{"verified": true, "unverified": true}})
+
+	result, err := service.AssignRoleToUsers(ctx, "office_bearer", []string{"verified", "unverified"}, "admin")
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, result.Assigned)
+	assert.Equal(t, 1, result.PendingKYC)
+	assert.Zero(t, result.Failed)
+	assert.Equal(t, interfaces.RoleAssignmentStatusPendingKYC, result.Results[1].Status)
+	assert.True(t, userRoles.has("verified", "office_bearer"))
+	require.Len(t, pending.assignments, 1)
+	assert.Equal(t, "unverified", pending.assignments[0].UserID)
+}
@@ -13,11 +13,17 @@ import (
 
 // RoleService implements the RoleService interface
 type RoleService struct {
-	roleRepo     interfaces.RoleRepository
-	userRoleRepo interfaces.UserRoleRepository
-	cacheService interfaces.CacheService
-	logger       interfaces.Logger
-	validator    interfaces.Validator
+	roleRepo       interfaces.RoleRepository
+	userRoleRepo   interfaces.UserRoleRepository
+	userRepo       interfaces.UserRepository
+	constraintRepo interfaces.RoleConstraintRepository
+	profileReader  interfaces.UserProfileReader
+	pendingRepo    interfaces.PendingRoleAssignmentRepository
+	cacheService   interfaces.CacheService
+	broadcaster    interfaces.InvalidationBroadcaster
+	auditService   interfaces.AuditService
+	logger         interfaces.Logger
+	validator      interfaces.Validator
 }
 
 // NewRoleService creates a new RoleService instance
@@ -37,6 +43,22 @@ func NewRoleService(
 	}
 }
 
+// SetUserRepository sets the user repository used to validate bulk assignment targets
+func (s *RoleService) SetUserRepository(userRepo interfaces.UserRepository) {
+	s.userRepo = userRepo
+}
+
+// SetAuditService sets the audit service used to record role operations
+func (s *RoleService) SetAuditService(auditService interfaces.AuditService) {
+	s.auditService = auditService
+}
+
+// SetInvalidationBroadcaster sets the broadcaster that repeats role cache invalidations on the other
+// service instances
+func (s *RoleService) SetInvalidationBroadcaster(broadcaster interfaces.InvalidationBroadcaster) {
+	s.broadcaster = broadcaster
+}
+
 // CreateRole creates a new role
 func (s *RoleService) CreateRole(ctx context.Context, role *models.Role) error {
 	s.logger.Info("Creating new role")
@@ -60,9 +82,7 @@ func (s *RoleService) CreateRole(ctx context.Context, role *models.Role) error {
 	}
 
 	// Clear cache
-	if err := s.cacheService.Delete(fmt.Sprintf("role:%s", role.ID)); err != nil {
-		s.logger.Error("Failed to delete role from cache", zap.Error(err))
-	}
+	s.invalidateRoleCache(ctx, role.ID)
 
 	s.logger.Info("Role created successfully",
 		zap.String("roleID", role.ID),
@@ -125,9 +145,7 @@ func (s *RoleService) UpdateRole(ctx context.Context, role *models.Role) error {
 	}
 
 	// Clear cache
-	if err := s.cacheService.Delete(fmt.Sprintf("role:%s", role.ID)); err != nil {
-		s.logger.Error("Failed to delete role from cache", zap.Error(err))
-	}
+	s.invalidateRoleCache(ctx, role.ID)
 
 	s.logger.Info("Role updated successfully", zap.String("roleID", role.ID))
 	return nil
@@ -158,9 +176,7 @@ func (s *RoleService) DeleteRole(ctx context.Context, roleID string) error {
 	}
 
 	// Clear cache
-	if err := s.cacheService.Delete(fmt.Sprintf("role:%s", roleID)); err != nil {
-		s.logger.Error("Failed to delete role from cache", zap.Error(err))
-	}
+	s.invalidateRoleCache(ctx, roleID)
 
 	s.logger.Info("Role soft deleted successfully", zap.String("roleID", roleID), zap.String("deletedBy", userID))
 	return nil
@@ -191,9 +207,7 @@ func (s *RoleService) HardDeleteRole(ctx context.Context, roleID string) error {
 	}
 
 	// Clear cache
-	if err := s.cacheService.Delete(fmt.Sprintf("role:%s", roleID)); err != nil {
-		s.logger.Error("Failed to delete role from cache", zap.Error(err))
-	}
+	s.invalidateRoleCache(ctx, roleID)
 
 	s.logger.Info("Role hard deleted successfully", zap.String("roleID", roleID), zap.String("deletedBy", userID))
 	return nil
@@ -254,9 +268,19 @@ func (s *RoleService) AssignRole(ctx context.Context, userID, roleID string) err
 	s.logger.Info("Assigning role to user", zap.String("userID", userID), zap.String("roleID", roleID))
 
 	// Validate role assignment
-	if err := s.ValidateRoleAssignment(ctx, userID, roleID); err != nil {
+	role, err := s.validateRoleAssignment(ctx, userID, roleID)
+	if err != nil {
+		return err
+	}
+
+	// KYC-gated roles wait for unverified users to complete Aadhaar verification
+	pending, err := s.deferUntilKYCVerified(ctx, userID, role)
+	if err != nil {
 		return err
 	}
+	if pending {
+		return models.ErrRoleAssignmentPendingKYC
+	}
 
 	// Use the enhanced repository method with transaction support
 	if err := s.userRoleRepo.AssignRole(ctx, userID, roleID); err != nil {
@@ -265,7 +289,7 @@ func (s *RoleService) AssignRole(ctx context.Context, userID, roleID string) err
 	}
 
 	// Invalidate all user role-related cache entries
-	s.invalidateUserRoleCache(userID)
+	s.invalidateUserRoleCache(ctx, userID)
 
 	s.logger.Info("Role assigned to user successfully", zap.String("userID", userID), zap.String("roleID", roleID))
 	return nil
@@ -291,7 +315,7 @@ func (s *RoleService) RemoveRole(ctx context.Context, userID, roleID string) err
 	}
 
 	// Invalidate all user role-related cache entries
-	s.invalidateUserRoleCache(userID)
+	s.invalidateUserRoleCache(ctx, userID)
 
 	s.logger.Info("Role removed from user successfully", zap.String("userID", userID), zap.String("roleID", roleID))
 	return nil
@@ -335,12 +359,19 @@ func (s *RoleService) GetUserRoles(ctx context.Context, userID string) ([]*model
 	return userRoles, nil
 }
 
-// ValidateRoleAssignment validates that both user and role exist and are active before assignment
+// ValidateRoleAssignment validates that both user and role exist and are active before assignment,
+// and that the assignment breaks none of the role assignment constraints of the role's organization
 func (s *RoleService) ValidateRoleAssignment(ctx context.Context, userID, roleID string) error {
+	_, err := s.validateRoleAssignment(ctx, userID, roleID)
+	return err
+}
+
+// validateRoleAssignment validates a role assignment and returns the role being assigned
+func (s *RoleService) validateRoleAssignment(ctx context.Context, userID, roleID string) (*models.Role, error) {
 	s.logger.Debug("Validating role assignment", zap.String("userID", userID), zap.String("roleID", roleID))
 
 	if userID == "" || roleID == "" {
-		return errors.NewValidationError("user ID and role ID are required")
+		return nil, errors.NewValidationError("user ID and role ID are required")
 	}
 
 	// Check if role exists and is active
@@ -348,28 +379,32 @@ func (s *RoleService) ValidateRoleAssignment(ctx context.Context, userID, roleID
 	_, err := s.roleRepo.GetByID(ctx, roleID, role)
 	if err != nil {
 		s.logger.Error("Role not found for assignment", zap.String("roleID", roleID), zap.Error(err))
-		return errors.NewNotFoundError("role not found")
+		return nil, errors.NewNotFoundError("role not found")
 	}
 
 	if !role.IsActive {
 		s.logger.Error("Role is not active", zap.String("roleID", roleID))
-		return errors.NewValidationError("role is not active")
+		return nil, errors.NewValidationError("role is not active")
 	}
 
 	// Check if role is already assigned to user
 	isAssigned, err := s.userRoleRepo.IsRoleAssigned(ctx, userID, roleID)
 	if err != nil {
 		s.logger.Error("Failed to check existing role assignment", zap.String("userID", userID), zap.String("roleID", roleID), zap.Error(err))
-		return fmt.Errorf("failed to validate role assignment: %w", err)
+		return nil, fmt.Errorf("failed to validate role assignment: %w", err)
 	}
 
 	if isAssigned {
 		s.logger.Warn("Role already assigned to user", zap.String("userID", userID), zap.String("roleID", roleID))
-		return errors.NewConflictError("role already assigned to user")
+		return nil, errors.NewConflictError("role already assigned to user")
+	}
+
+	if err := s.checkRoleConstraints(ctx, userID, role); err != nil {
+		return nil, err
 	}
 
 	s.logger.Debug("Role assignment validation successful", zap.String("userID", userID), zap.String("roleID", roleID))
-	return nil
+	return role, nil
 }
 
 // Helper methods
@@ -395,7 +430,7 @@ func (s *RoleService) validateRole(role *models.Role) error {
 }
 
 // invalidateUserRoleCache removes user role-related cache entries
-func (s *RoleService) invalidateUserRoleCache(userID string) {
+func (s *RoleService) invalidateUserRoleCache(ctx context.Context, userID string) {
 	cacheKeys := []string{
 		fmt.Sprintf("user_roles:%s", userID),
 		fmt.Sprintf("user_with_roles:%s", userID),
@@ -406,6 +441,29 @@ func (s *RoleService) invalidateUserRoleCache(userID string) {
 			s.logger.Warn("Failed to delete role cache key", zap.String("key", key), zap.Error(err))
 		}
 	}
+	s.broadcastInvalidation(ctx, interfaces.CacheInvalidation{Keys: cacheKeys})
 
 	s.logger.Debug("User role cache invalidated", zap.String("user_id", userID))
 }
+
+// invalidateRoleCache removes a cached role
+func (s *RoleService) invalidateRoleCache(ctx context.Context, roleID string) {
+	cacheKey := fmt.Sprintf("role:%s", roleID)
+	if err := s.cacheService.Delete(cacheKey); err != nil {
+		s.logger.Error("Failed to delete role from cache", zap.Error(err))
+	}
+	s.broadcastInvalidation(ctx, interfaces.CacheInvalidation{Keys: []string{cacheKey}})
+}
+
+// broadcastInvalidation repeats a cache invalidation on the other service instances
+func (s *RoleService) broadcastInvalidation(ctx context.Context, invalidation interfaces.CacheInvalidation) {
+	if s.broadcaster == nil {
+		return
+	}
+	if err := s.broadcaster.Publish(ctx, invalidation); err != nil {
+		s.logger.Warn("Failed to broadcast role cache invalidation",
+			zap.Strings("keys", invalidation.Keys),
+			zap.Strings("patterns", invalidation.Patterns),
+			zap.Error(err))
+	}
+}
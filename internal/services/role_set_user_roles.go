@@ -0,0 +1,220 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// SetUserRoles makes roleIDs exactly the set of roles assigned directly to a user. The current
+// direct assignments are diffed against roleIDs and only the difference is written, in a single
+// transaction; roles the user inherits from groups are neither counted nor touched. Every role is
+// validated, and checked against the role constraints as the user would hold them afterwards,
+// before anything is written. KYC-gated roles are held back as pending for a user whose Aadhaar is
+// not verified, or refuse the whole set when the role rejects unverified users. The net change is
+// audited and the user's caches are cleared once.
+func (s *RoleService) SetUserRoles(ctx context.Context, userID string, roleIDs []string, actor string) (*interfaces.UserRoleSetResult, error) {
+	s.logger.Info("Setting user roles",
+		zap.String("userID", userID),
+		zap.Int("roleCount", len(roleIDs)),
+		zap.String("actor", actor))
+
+	if userID == "" {
+		return nil, errors.NewValidationError("user ID is required")
+	}
+	desired, err := normalizeRoleIDs(roleIDs)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validateUsersExist(ctx, []string{userID}); err != nil {
+		return nil, err
+	}
+
+	roles := make(map[string]*models.Role, len(desired))
+	for _, roleID := range desired {
+		role := &models.Role{}
+		if _, err := s.roleRepo.GetByID(ctx, roleID, role); err != nil || role.DeletedAt != nil {
+			s.logger.Warn("Role not found for user role set", zap.String("roleID", roleID))
+			return nil, errors.NewNotFoundError("role not found: " + roleID)
+		}
+		if !role.IsActive {
+			return nil, errors.NewValidationError("role is not active: " + roleID)
+		}
+		roles[roleID] = role
+	}
+
+	current, err := s.userRoleRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		s.logger.Error("Failed to get current user roles", zap.String("userID", userID), zap.Error(err))
+		return nil, errors.NewInternalError(err)
+	}
+
+	direct := make(map[string]bool)
+	held := make(map[string]bool, len(current)+len(desired))
+	for _, userRole := range current {
+		if userRole.IsInherited() {
+			held[userRole.RoleID] = true
+			continue
+		}
+		direct[userRole.RoleID] = true
+	}
+
+	wanted := make(map[string]bool, len(desired))
+	var assign, remove []string
+	for _, roleID := range desired {
+		wanted[roleID] = true
+		held[roleID] = true
+		if !direct[roleID] {
+			assign = append(assign, roleID)
+		}
+	}
+	for roleID := range direct {
+		if !wanted[roleID] {
+			remove = append(remove, roleID)
+		}
+	}
+	sort.Strings(remove)
+
+	result := &interfaces.UserRoleSetResult{
+		UserID:     userID,
+		RoleIDs:    desired,
+		Assigned:   []string{},
+		Removed:    []string{},
+		PendingKYC: []string{},
+	}
+	if len(assign) == 0 && len(remove) == 0 {
+		s.logger.Info("User roles unchanged", zap.String("userID", userID))
+		return result, nil
+	}
+
+	for _, roleID := range assign {
+		if err := s.checkRoleConstraintsHolding(ctx, userID, roles[roleID], held); err != nil {
+			return nil, err
+		}
+	}
+
+	assign, pending, err := s.deferKYCGatedRoles(ctx, userID, assign, roles)
+	if err != nil {
+		return nil, err
+	}
+	if len(pending) > 0 {
+		result.PendingKYC = pending
+		result.RoleIDs = withoutRoleIDs(desired, pending)
+	}
+	if len(assign) == 0 && len(remove) == 0 {
+		s.logger.Info("User roles unchanged, KYC-gated roles pending", zap.String("userID", userID), zap.Strings("pending", pending))
+		return result, nil
+	}
+
+	if err := s.userRoleRepo.ReplaceDirectRoles(ctx, userID, assign, remove); err != nil {
+		s.logger.Error("Failed to set user roles", zap.String("userID", userID), zap.Error(err))
+		s.auditSetUserRoles(ctx, actor, userID, assign, remove, err)
+		return nil, errors.NewInternalError(err)
+	}
+
+	s.invalidateUserRoleCache(ctx, userID)
+	s.invalidateUserPermissionCache(ctx, userID)
+	s.auditSetUserRoles(ctx, actor, userID, assign, remove, nil)
+
+	if assign != nil {
+		result.Assigned = assign
+	}
+	if remove != nil {
+		result.Removed = remove
+	}
+	s.logger.Info("User roles set",
+		zap.String("userID", userID),
+		zap.Strings("assigned", result.Assigned),
+		zap.Strings("removed", result.Removed))
+	return result, nil
+}
+
+// deferKYCGatedRoles splits the roles to assign into those assigned now and those left pending until
+// the user's Aadhaar is verified. Roles that reject unverified users are checked first, so a
+// rejection leaves no pending assignments behind. A role already pending from an earlier request
+// stays pending.
+func (s *RoleService) deferKYCGatedRoles(ctx context.Context, userID string, roleIDs []string, roles map[string]*models.Role) (assign, pending []string, err error) {
+	ordered := make([]string, 0, len(roleIDs))
+	for _, rejecting := range []bool{true, false} {
+		for _, roleID := range roleIDs {
+			if _, action := roles[roleID].KYCGate(); (action == models.KYCGateActionReject) == rejecting {
+				ordered = append(ordered, roleID)
+			}
+		}
+	}
+
+	deferred := make(map[string]bool)
+	for _, roleID := range ordered {
+		isPending, err := s.deferUntilKYCVerified(ctx, userID, roles[roleID])
+		if errors.IsConflictError(err) {
+			isPending, err = true, nil
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		deferred[roleID] = isPending
+	}
+
+	for _, roleID := range roleIDs {
+		if deferred[roleID] {
+			pending = append(pending, roleID)
+		} else {
+			assign = append(assign, roleID)
+		}
+	}
+	return assign, pending, nil
+}
+
+// withoutRoleIDs returns roleIDs, in order, without those in excluded
+func withoutRoleIDs(roleIDs, excluded []string) []string {
+	skip := make(map[string]bool, len(excluded))
+	for _, roleID := range excluded {
+		skip[roleID] = true
+	}
+	kept := make([]string, 0, len(roleIDs))
+	for _, roleID := range roleIDs {
+		if !skip[roleID] {
+			kept = append(kept, roleID)
+		}
+	}
+	return kept
+}
+
+func (s *RoleService) auditSetUserRoles(ctx context.Context, actor, userID string, assigned, removed []string, setErr error) {
+	if s.auditService == nil {
+		return
+	}
+
+	details := map[string]interface{}{
+		"assigned": assigned,
+		"removed":  removed,
+	}
+	if setErr != nil {
+		details["error"] = setErr.Error()
+	}
+
+	s.auditService.LogRoleOperation(ctx, actor, userID, "", "set", setErr == nil, details)
+}
+
+// normalizeRoleIDs trims and de-duplicates role IDs, keeping their order
+func normalizeRoleIDs(roleIDs []string) ([]string, error) {
+	seen := make(map[string]bool, len(roleIDs))
+	normalized := make([]string, 0, len(roleIDs))
+	for _, roleID := range roleIDs {
+		roleID = strings.TrimSpace(roleID)
+		if roleID == "" {
+			return nil, errors.NewValidationError("role IDs cannot be empty")
+		}
+		if seen[roleID] {
+			continue
+		}
+		seen[roleID] = true
+		normalized = append(normalized, roleID)
+	}
+	return normalized, nil
+}
@@ -0,0 +1,205 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/Kisanlink/aaa-service/v2/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// setRolesUserRoleRepo keeps the active role assignments of users in memory
+type setRolesUserRoleRepo struct {
+	interfaces.UserRoleRepository
+	userRoles []*models.UserRole
+	replaces  int
+}
+
+func (r *setRolesUserRoleRepo) GetByUserID(ctx context.Context, userID string) ([]*models.UserRole, error) {
+	var active []*models.UserRole
+	for _, userRole := range r.userRoles {
+		if userRole.UserID == userID && userRole.IsActive {
+			active = append(active, userRole)
+		}
+	}
+	return active, nil
+}
+
+func (r *setRolesUserRoleRepo) ReplaceDirectRoles(ctx context.Context, userID string, assign, remove []string) error {
+	r.replaces++
+	for _, userRole := range r.userRoles {
+		for _, roleID := range remove {
+			if userRole.UserID == userID && userRole.RoleID == roleID && !userRole.IsInherited() {
+				userRole.IsActive = false
+			}
+		}
+	}
+	for _, roleID := range assign {
+		r.userRoles = append(r.userRoles, models.NewUserRole(userID, roleID))
+	}
+	return nil
+}
+
+func (r *setRolesUserRoleRepo) roleIDs(userID string) (direct, inherited []string) {
+	active, _ := r.GetByUserID(context.Background(), userID)
+	for _, userRole := range active {
+		if userRole.IsInherited() {
+			inherited = append(inherited, userRole.RoleID)
+		} else {
+			direct = append(direct, userRole.RoleID)
+		}
+	}
+	return direct, inherited
+}
+
+func newSetRolesTestService(userRoles *setRolesUserRoleRepo, audit *bulkAuditRecorder) *RoleService {
+	loggerAdapter := utils.NewLoggerAdapter(zap.NewNop())
+	roles := map[string]*models.Role{}
+	for _, id := range []string{"ROLE_ADMIN", "ROLE_EDITOR", "ROLE_VIEWER", "ROLE_RETIRED"} {
+		role := models.NewRole(id, "", models.RoleScopeGlobal)
+		role.ID = id
+		role.IsActive = id != "ROLE_RETIRED"
+		roles[id] = role
+	}
+
+	service := NewRoleService(
+		&bulkRoleRepo{roles: roles},
+		userRoles,
+		NewNoOpCacheService(loggerAdapter),
+		loggerAdapter,
+		nil,
+	).(*RoleService)
+	service.SetUserRepository(&bulkUserRepo{users: map[string]bool{"USER1": true}})
+	service.SetAuditService(audit)
+	return service
+}
+
+func newSetRolesUserRoles() *setRolesUserRoleRepo {
+	return &setRolesUserRoleRepo{userRoles: []*models.UserRole{
+		models.NewUserRole("USER1", "ROLE_EDITOR"),
+		models.NewUserRole("USER1", "ROLE_VIEWER"),
+		models.NewInheritedUserRole("USER1", "ROLE_ADMIN", "GRP1"),
+	}}
+}
+
+func TestRoleService_SetUserRoles_AddsAndRemoves(t *testing.T) {
+	userRoles := newSetRolesUserRoles()
+	audit := &bulkAuditRecorder{}
+	service := newSetRolesTestService(userRoles, audit)
+
+	result, err := service.SetUserRoles(context.Background(), "USER1", []string{"ROLE_VIEWER", "ROLE_ADMIN", " ROLE_ADMIN"}, "ADMIN1")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"ROLE_VIEWER", "ROLE_ADMIN"}, result.RoleIDs)
+	assert.Equal(t, []string{"ROLE_ADMIN"}, result.Assigned, "a role held only through a group is assigned directly")
+	assert.Equal(t, []string{"ROLE_EDITOR"}, result.Removed)
+	assert.Equal(t, 1, userRoles.replaces, "the change is written in one transaction")
+
+	direct, inherited := userRoles.roleIDs("USER1")
+	assert.ElementsMatch(t, []string{"ROLE_VIEWER", "ROLE_ADMIN"}, direct)
+	assert.Equal(t, []string{"ROLE_ADMIN"}, inherited, "inherited roles are not touched")
+
+	assert.Equal(t, []string{"USER1"}, audit.targets, "the net change is audited once")
+	assert.Equal(t, []bool{true}, audit.success)
+}
+
+func TestRoleService_SetUserRoles_EmptyListRemovesDirectRoles(t *testing.T) {
+	userRoles := newSetRolesUserRoles()
+	service := newSetRolesTestService(userRoles, &bulkAuditRecorder{})
+
+	result, err := service.SetUserRoles(context.Background(), "USER1", []string{}, "ADMIN1")
+	require.NoError(t, err)
+
+	assert.Empty(t, result.Assigned)
+	assert.Equal(t, []string{"ROLE_EDITOR", "ROLE_VIEWER"}, result.Removed)
+	direct, inherited := userRoles.roleIDs("USER1")
+	assert.Empty(t, direct)
+	assert.Equal(t, []string{"ROLE_ADMIN"}, inherited)
+}
+
+func TestRoleService_SetUserRoles_NoOp(t *testing.T) {
+	userRoles := newSetRolesUserRoles()
+	audit := &bulkAuditRecorder{}
+	service := newSetRolesTestService(userRoles, audit)
+
+	result, err := service.SetUserRoles(context.Background(), "USER1", []string{"ROLE_VIEWER", "ROLE_EDITOR"}, "ADMIN1")
+	require.NoError(t, err)
+
+	assert.Empty(t, result.Assigned)
+	assert.Empty(t, result.Removed)
+	assert.Zero(t, userRoles.replaces, "nothing is written")
+	assert.Empty(t, audit.targets, "nothing is audited")
+}
+
+func TestRoleService_SetUserRoles_ValidatesBeforeWriting(t *testing.T) {
+	tests := []struct {
+		name    string
+		userID  string
+		roleIDs []string
+		check   func(error) bool
+	}{
+		{name: "unknown role", userID: "USER1", roleIDs: []string{"ROLE_VIEWER", "ROLE_MISSING"}, check: errors.IsNotFoundError},
+		{name: "inactive role", userID: "USER1", roleIDs: []string{"ROLE_RETIRED"}, check: errors.IsValidationError},
+		{name: "blank role ID", userID: "USER1", roleIDs: []string{" "}, check: errors.IsValidationError},
+		{name: "unknown user", userID: "USER9", roleIDs: []string{"ROLE_VIEWER"}, check: errors.IsNotFoundError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRoles := newSetRolesUserRoles()
+			service := newSetRolesTestService(userRoles, &bulkAuditRecorder{})
+
+			_, err := service.SetUserRoles(context.Background(), tt.userID, tt.roleIDs, "ADMIN1")
+			require.Error(t, err)
+			assert.True(t, tt.check(err), "unexpected error %v", err)
+			assert.Zero(t, userRoles.replaces)
+		})
+	}
+}
+
+func TestRoleService_SetUserRoles_KYCGated(t *testing.T) {
+	ctx := context.Background()
+	userRoles := &setRolesUserRoleRepo{userRoles: []*models.UserRole{models.NewUserRole("unverified", "ROLE_VIEWER")}}
+	service := newSetRolesTestService(userRoles, &bulkAuditRecorder{})
+	roles := service.roleRepo.(*bulkRoleRepo).roles
+	for _, role := range []*models.Role{
+		kycGatedRole("office_bearer", `{"kyc_gated": true}`),
+		kycGatedRole("treasurer", `{"kyc_gated": true, "kyc_gate_action": "reject"}`),
+	} {
+		roles[role.ID] = role
+	}
+	audit := &securityEventRecorder{}
+	pending := &memoryPendingRepo{}
+	service.SetAuditService(audit)
+	service.SetKYCGate(&kycProfileReader{verified: map[string]bool{"unverified": false}}, pending)
+	service.SetUserRepository(&bulkUserRepo{users: map[string]bool{"unverified": true}})
+
+	_, err := service.SetUserRoles(ctx, "unverified", []string{"office_bearer", "treasurer", "ROLE_EDITOR"}, "ADMIN1")
+	assert.True(t, errors.IsForbiddenError(err), "unexpected error %v", err)
+	assert.Zero(t, userRoles.replaces, "a rejected role refuses the whole set")
+	assert.Empty(t, pending.assignments, "the rejection leaves no pending assignment behind")
+
+	result, err := service.SetUserRoles(ctx, "unverified", []string{"office_bearer", "ROLE_EDITOR"}, "ADMIN1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ROLE_EDITOR"}, result.Assigned)
+	assert.Equal(t, []string{"ROLE_VIEWER"}, result.Removed)
+	assert.Equal(t, []string{"office_bearer"}, result.PendingKYC)
+	assert.Equal(t, []string{"ROLE_EDITOR"}, result.RoleIDs)
+	direct, _ := userRoles.roleIDs("unverified")
+	assert.Equal(t, []string{"ROLE_EDITOR"}, direct, "the gated role is not assigned")
+	require.Len(t, pending.assignments, 1)
+	assert.Equal(t, "office_bearer", pending.assignments[0].RoleID)
+
+	// Repeating the request leaves the role pending without writing anything
+	result, err = service.SetUserRoles(ctx, "unverified", []string{"office_bearer", "ROLE_EDITOR"}, "ADMIN1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"office_bearer"}, result.PendingKYC)
+	assert.Empty(t, result.Assigned)
+	assert.Equal(t, 1, userRoles.replaces)
+	assert.Len(t, pending.assignments, 1)
+}
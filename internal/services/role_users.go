@@ -0,0 +1,58 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// GetUsersByRole retrieves a page of the users currently holding a role and the total number of
+// them. Inherited assignments count only while their group role and group membership are valid.
+func (s *RoleService) GetUsersByRole(ctx context.Context, roleID string, limit, offset int) ([]*models.User, int64, error) {
+	s.logger.Info("Getting users by role", zap.String("roleID", roleID))
+
+	if roleID == "" {
+		return nil, 0, errors.NewValidationError("role ID is required")
+	}
+
+	role := &models.Role{}
+	if _, err := s.roleRepo.GetByID(ctx, roleID, role); err != nil || role.DeletedAt != nil {
+		s.logger.Error("Role not found", zap.String("roleID", roleID), zap.Error(err))
+		return nil, 0, errors.NewNotFoundError("role not found")
+	}
+
+	return s.listUsersByRole(ctx, roleID, limit, offset)
+}
+
+// GetUsersByRoleName retrieves a page of the users currently holding the role with a name, resolved
+// within an organization: the organization's own role takes precedence over a global one. An empty
+// orgID resolves the global role.
+func (s *RoleService) GetUsersByRoleName(ctx context.Context, name, orgID string, limit, offset int) ([]*models.User, int64, error) {
+	s.logger.Info("Getting users by role name", zap.String("roleName", name), zap.String("orgID", orgID))
+
+	if name == "" {
+		return nil, 0, errors.NewValidationError("role name is required")
+	}
+
+	role, err := s.roleRepo.GetByNameInOrganization(ctx, name, orgID)
+	if err != nil || role == nil {
+		s.logger.Error("Role not found", zap.String("roleName", name), zap.String("orgID", orgID), zap.Error(err))
+		return nil, 0, errors.NewNotFoundError("role not found")
+	}
+
+	return s.listUsersByRole(ctx, role.ID, limit, offset)
+}
+
+func (s *RoleService) listUsersByRole(ctx context.Context, roleID string, limit, offset int) ([]*models.User, int64, error) {
+	users, total, err := s.userRoleRepo.ListUsersByRole(ctx, roleID, limit, offset)
+	if err != nil {
+		s.logger.Error("Failed to list users by role", zap.String("roleID", roleID), zap.Error(err))
+		return nil, 0, errors.NewInternalError(fmt.Errorf("failed to list users by role: %w", err))
+	}
+
+	s.logger.Info("Users by role retrieved", zap.String("roleID", roleID), zap.Int64("total", total))
+	return users, total, nil
+}
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/Kisanlink/aaa-service/v2/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// roleUsersRoleRepo resolves role names to the roles of an organization, then to global roles
+type roleUsersRoleRepo struct {
+	bulkRoleRepo
+}
+
+func (r *roleUsersRoleRepo) GetByNameInOrganization(ctx context.Context, name, organizationID string) (*models.Role, error) {
+	var global *models.Role
+	for _, role := range r.roles {
+		if role.Name != name {
+			continue
+		}
+		if role.OrganizationID != nil && *role.OrganizationID == organizationID {
+			return role, nil
+		}
+		if role.OrganizationID == nil {
+			global = role
+		}
+	}
+	if global == nil {
+		return nil, fmt.Errorf("role not found")
+	}
+	return global, nil
+}
+
+// roleUsersUserRoleRepo lists the users holding each role
+type roleUsersUserRoleRepo struct {
+	interfaces.UserRoleRepository
+	holders map[string][]string
+}
+
+func (r *roleUsersUserRoleRepo) ListUsersByRole(ctx context.Context, roleID string, limit, offset int) ([]*models.User, int64, error) {
+	holders := r.holders[roleID]
+	var users []*models.User
+	for _, id := range holders[min(offset, len(holders)):min(offset+limit, len(holders))] {
+		user := models.NewUser("", "", "")
+		user.ID = id
+		users = append(users, user)
+	}
+	return users, int64(len(holders)), nil
+}
+
+func newRoleUsersTestService() *RoleService {
+	loggerAdapter := utils.NewLoggerAdapter(zap.NewNop())
+	global := models.NewGlobalRole("admin", "Global admin")
+	global.ID = "ROLE_GLOBAL"
+	orgAdmin := models.NewOrgRole("admin", "Organization admin", "ORG1")
+	orgAdmin.ID = "ROLE_ORG1"
+
+	return NewRoleService(
+		&roleUsersRoleRepo{bulkRoleRepo{roles: map[string]*models.Role{"ROLE_GLOBAL": global, "ROLE_ORG1": orgAdmin}}},
+		&roleUsersUserRoleRepo{holders: map[string][]string{
+			"ROLE_GLOBAL": {"USER1", "USER2", "USER3"},
+			"ROLE_ORG1":   {"USER4"},
+		}},
+		NewNoOpCacheService(loggerAdapter),
+		loggerAdapter,
+		nil,
+	).(*RoleService)
+}
+
+func TestRoleService_GetUsersByRole(t *testing.T) {
+	service := newRoleUsersTestService()
+
+	users, total, err := service.GetUsersByRole(context.Background(), "ROLE_GLOBAL", 2, 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	require.Len(t, users, 2)
+	assert.Equal(t, "USER2", users[0].ID)
+
+	_, _, err = service.GetUsersByRole(context.Background(), "MISSING", 10, 0)
+	assert.True(t, errors.IsNotFoundError(err))
+
+	_, _, err = service.GetUsersByRole(context.Background(), "", 10, 0)
+	assert.True(t, errors.IsValidationError(err))
+}
+
+func TestRoleService_GetUsersByRoleName(t *testing.T) {
+	service := newRoleUsersTestService()
+
+	users, total, err := service.GetUsersByRoleName(context.Background(), "admin", "ORG1", 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total, "the organization's role takes precedence over the global one")
+	require.Len(t, users, 1)
+	assert.Equal(t, "USER4", users[0].ID)
+
+	_, total, err = service.GetUsersByRoleName(context.Background(), "admin", "", 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+
+	_, _, err = service.GetUsersByRoleName(context.Background(), "auditor", "ORG1", 10, 0)
+	assert.True(t, errors.IsNotFoundError(err))
+}
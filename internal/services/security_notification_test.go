@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestIsNewDevice(t *testing.T) {
+	loginAt := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	earlier := loginAt.Add(-time.Hour)
+	const phone = "KisanlinkApp/2.1 (Android 14)"
+	const browser = "Mozilla/5.0 (Windows NT 10.0)"
+
+	tests := []struct {
+		name    string
+		history []LoginHistoryEntry
+		want    bool
+	}{
+		{
+			name: "first sign-in is not a new device",
+			want: false,
+		},
+		{
+			name:    "known device",
+			history: []LoginHistoryEntry{{Timestamp: earlier, Success: true, UserAgent: browser}, {Timestamp: earlier, Success: true, UserAgent: phone}},
+			want:    false,
+		},
+		{
+			name:    "unseen device",
+			history: []LoginHistoryEntry{{Timestamp: earlier, Success: true, UserAgent: browser}},
+			want:    true,
+		},
+		{
+			name:    "failed attempts from the device do not make it known",
+			history: []LoginHistoryEntry{{Timestamp: earlier, Success: false, UserAgent: phone}, {Timestamp: earlier, Success: true, UserAgent: browser}},
+			want:    true,
+		},
+		{
+			name:    "only failed attempts before is not a new device",
+			history: []LoginHistoryEntry{{Timestamp: earlier, Success: false, UserAgent: browser}},
+			want:    false,
+		},
+		{
+			name:    "the current sign-in does not count as earlier",
+			history: []LoginHistoryEntry{{Timestamp: loginAt, Success: true, UserAgent: phone}, {Timestamp: earlier, Success: true, UserAgent: browser}},
+			want:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, isNewDevice(tt.history, phone, loginAt))
+		})
+	}
+}
+
+// loginAuditRepo keeps saved audit logs and serves them back as login history
+type loginAuditRepo struct {
+	interfaces.AuditRepository
+	mu   sync.Mutex
+	logs []*models.AuditLog
+}
+
+func (r *loginAuditRepo) Create(ctx context.Context, auditLog *models.AuditLog) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.logs = append([]*models.AuditLog{auditLog}, r.logs...)
+	return nil
+}
+
+func (r *loginAuditRepo) ListByUserAndActions(ctx context.Context, userID string, actions []string, limit, offset int) ([]*models.AuditLog, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]*models.AuditLog(nil), r.logs...), nil
+}
+
+// lastLoginUserRepo accepts last-login updates
+type lastLoginUserRepo struct {
+	interfaces.UserRepository
+}
+
+func (r *lastLoginUserRepo) UpdateLastLogin(ctx context.Context, userID, ipAddress string) error {
+	return nil
+}
+
+// channelNotifier hands each security event to the test
+type channelNotifier chan interfaces.SecurityEvent
+
+func (n channelNotifier) NotifySecurityEvent(ctx context.Context, event interfaces.SecurityEvent) {
+	n <- event
+}
+
+func TestAuthService_RecordLogin_NotifiesNewDevice(t *testing.T) {
+	auditRepo := &loginAuditRepo{}
+	notifier := make(channelNotifier, 1)
+	service := &AuthService{
+		userRepository: &lastLoginUserRepo{},
+		auditService:   NewAuditService(nil, auditRepo, nil, zap.NewNop()),
+		logger:         zap.NewNop(),
+	}
+	service.SetSecurityNotifier(notifier)
+
+	fromDevice := func(userAgent string) context.Context {
+		ctx := context.WithValue(context.Background(), "ip_address", "203.0.113.7")
+		return context.WithValue(ctx, "user_agent", userAgent)
+	}
+	expectNoEvent := func() {
+		select {
+		case event := <-notifier:
+			t.Fatalf("unexpected %s notification", event.Type)
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+
+	service.RecordLogin(fromDevice("Mozilla/5.0"), "USER1", "password")
+	expectNoEvent()
+
+	service.RecordLogin(fromDevice("Mozilla/5.0"), "USER1", "mpin")
+	expectNoEvent()
+
+	service.RecordLogin(fromDevice("KisanlinkApp/2.1"), "USER1", "password")
+	select {
+	case event := <-notifier:
+		assert.Equal(t, interfaces.SecurityEventNewDeviceLogin, event.Type)
+		assert.Equal(t, "USER1", event.UserID)
+		assert.Equal(t, "KisanlinkApp/2.1", event.UserAgent)
+		assert.Equal(t, "203.0.113.7", event.IPAddress)
+	case <-time.After(2 * time.Second):
+		t.Fatal("no new device notification")
+	}
+
+	history, err := service.auditService.GetLoginHistory(context.Background(), "USER1", 10)
+	require.NoError(t, err)
+	require.Len(t, history, 3)
+	assert.Equal(t, "password", history[0].Method)
+	assert.True(t, history[0].Success)
+}
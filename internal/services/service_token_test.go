@@ -0,0 +1,95 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/config"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	jwt "github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newServiceTokenAuthService(t *testing.T) *AuthService {
+	t.Helper()
+	jwtCfg := &config.JWTConfig{Secret: "test-secret", Issuer: "aaa-service", TTL: time.Hour, Leeway: time.Minute}
+	service, err := NewAuthService(nil, nil, nil, nil, nil, nil, &AuthServiceConfig{JWTSecret: jwtCfg.Secret}, zap.NewNop(), nil, jwtCfg)
+	require.NoError(t, err)
+	return service
+}
+
+func TestIssueServiceToken_Claims(t *testing.T) {
+	service := newServiceTokenAuthService(t)
+
+	token, err := service.IssueServiceToken(context.Background(), "SVC1", "catalog-service", []string{"users:read", "roles:*"}, 5*time.Minute)
+	require.NoError(t, err)
+
+	claims := &ServiceTokenClaims{}
+	_, err = jwt.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) { return []byte("test-secret"), nil })
+	require.NoError(t, err)
+
+	assert.Equal(t, "SVC1", claims.Subject)
+	assert.Equal(t, jwt.ClaimStrings{"catalog-service"}, claims.Audience)
+	assert.Equal(t, []string{"users:read", "roles:*"}, claims.Scopes())
+	assert.Equal(t, ServiceTokenType, claims.TokenType)
+	assert.Equal(t, "service", claims.PrincipalType)
+	assert.WithinDuration(t, time.Now().Add(5*time.Minute), claims.ExpiresAt.Time, 5*time.Second)
+}
+
+func TestIssueServiceToken_DefaultTTL(t *testing.T) {
+	service := newServiceTokenAuthService(t)
+
+	token, err := service.IssueServiceToken(context.Background(), "SVC1", "aaa-service", []string{"*"}, 0)
+	require.NoError(t, err)
+
+	claims := &ServiceTokenClaims{}
+	_, err = jwt.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) { return []byte("test-secret"), nil })
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(DefaultServiceTokenTTL), claims.ExpiresAt.Time, 5*time.Second)
+}
+
+func TestIssueServiceToken_Invalid(t *testing.T) {
+	service := newServiceTokenAuthService(t)
+
+	tests := []struct {
+		name        string
+		principalID string
+		audience    string
+		scopes      []string
+		ttl         time.Duration
+	}{
+		{name: "missing principal", audience: "aaa-service", scopes: []string{"*"}},
+		{name: "missing audience", principalID: "SVC1", scopes: []string{"*"}},
+		{name: "no scopes", principalID: "SVC1", audience: "aaa-service"},
+		{name: "malformed scope", principalID: "SVC1", audience: "aaa-service", scopes: []string{"users"}},
+		{name: "unknown action", principalID: "SVC1", audience: "aaa-service", scopes: []string{"users:delete"}},
+		{name: "ttl too long", principalID: "SVC1", audience: "aaa-service", scopes: []string{"*"}, ttl: 48 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.IssueServiceToken(context.Background(), tt.principalID, tt.audience, tt.scopes, tt.ttl)
+			require.Error(t, err)
+			assert.True(t, errors.IsValidationError(err), err.Error())
+		})
+	}
+}
+
+func TestRequiredServiceScope(t *testing.T) {
+	assert.Equal(t, "users:read", RequiredServiceScope("GET", "/api/v1/users/USER1"))
+	assert.Equal(t, "users:write", RequiredServiceScope("DELETE", "/api/v1/users/USER1"))
+	assert.Equal(t, "organizations:write", RequiredServiceScope("post", "/api/v1/organizations"))
+	assert.Equal(t, "api_endpoint:read", RequiredServiceScope("GET", "/metrics"))
+}
+
+func TestServiceScopeCovers(t *testing.T) {
+	assert.True(t, ServiceScopeCovers([]string{"users:read"}, "users:read"))
+	assert.True(t, ServiceScopeCovers([]string{"users:*"}, "users:write"))
+	assert.True(t, ServiceScopeCovers([]string{"*"}, "roles:write"))
+	assert.False(t, ServiceScopeCovers([]string{"users:read"}, "users:write"))
+	assert.False(t, ServiceScopeCovers([]string{"users:*"}, "roles:read"))
+	assert.False(t, ServiceScopeCovers(nil, "users:read"))
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	configPkg "github.com/Kisanlink/aaa-service/v2/internal/config"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// Concurrent session limits
+//
+// A login counts as one session for as long as any token of its family is unexpired and unrevoked.
+// When a new login would take a user past their limit, the oldest logins (by their most recently
+// issued token) are revoked, or in reject mode the new login is refused. Either way the event is
+// audited and the user notified. The limit is the service limit, lowered by the
+// max_concurrent_sessions setting of the user's organizations.
+
+// SetSessionLimit configures the concurrent session limit. The resolver, which may be nil, reads the
+// limits set by organizations.
+func (s *AuthService) SetSessionLimit(cfg configPkg.SessionLimitConfig, resolver interfaces.SessionLimitResolver) {
+	s.sessionLimit = cfg
+	s.sessionLimits = resolver
+}
+
+// enforceSessionLimit makes room for a new login of the user, or refuses it with a forbidden
+// error when the limit is reached in reject mode
+func (s *AuthService) enforceSessionLimit(ctx context.Context, userID string) error {
+	limit := s.maxConcurrentSessions(ctx, userID)
+	if limit <= 0 {
+		return nil
+	}
+
+	sessions, err := s.userTokenSessions(userID)
+	if err != nil {
+		return errors.NewInternalError(fmt.Errorf("failed to count sessions: %w", err))
+	}
+	// Sessions are ordered most recently issued first, so families are too
+	var families []string
+	seen := make(map[string]bool)
+	for _, session := range sessions {
+		if session.FamilyID == "" || seen[session.FamilyID] {
+			continue
+		}
+		seen[session.FamilyID] = true
+		families = append(families, session.FamilyID)
+	}
+	if len(families) < limit {
+		return nil
+	}
+
+	details := map[string]interface{}{
+		"limit":           limit,
+		"active_sessions": len(families),
+		"mode":            s.sessionLimit.Mode,
+	}
+	if s.sessionLimit.Mode == configPkg.SessionLimitModeReject {
+		s.recordSessionLimit(ctx, userID, false, details)
+		return errors.NewForbiddenError(fmt.Sprintf("maximum of %d concurrent sessions reached; sign out of another session first", limit))
+	}
+
+	evicted := families[limit-1:]
+	evict := make(map[string]bool, len(evicted))
+	for _, familyID := range evicted {
+		evict[familyID] = true
+	}
+	for _, session := range sessions {
+		if evict[session.FamilyID] {
+			s.revokeToken(userID, session.JTI, session.FamilyID, session.ExpiresAt)
+		}
+	}
+	details["evicted_families"] = evicted
+	s.recordSessionLimit(ctx, userID, true, details)
+	return nil
+}
+
+// maxConcurrentSessions returns the session limit of a user, 0 meaning unlimited. An organization
+// can lower the service limit but not raise it.
+func (s *AuthService) maxConcurrentSessions(ctx context.Context, userID string) int {
+	limit := s.sessionLimit.MaxConcurrent
+	if s.sessionLimits == nil {
+		return limit
+	}
+	orgLimit, err := s.sessionLimits.MaxConcurrentSessions(ctx, userID)
+	if err != nil {
+		s.logger.Warn("Failed to read organization session limit, using the service limit",
+			zap.String("user_id", userID),
+			zap.Error(err))
+		return limit
+	}
+	if orgLimit > 0 && (limit == 0 || orgLimit < limit) {
+		return orgLimit
+	}
+	return limit
+}
+
+// recordSessionLimit audits a login that reached the session limit and notifies the user.
+// allowed is false when the login was refused.
+func (s *AuthService) recordSessionLimit(ctx context.Context, userID string, allowed bool, details map[string]interface{}) {
+	s.logger.Warn("Concurrent session limit reached",
+		zap.String("user_id", userID),
+		zap.Bool("login_allowed", allowed),
+		zap.Any("details", details))
+	if s.auditService != nil {
+		s.auditService.LogSecurityEvent(ctx, userID, interfaces.SecurityEventSessionLimit, "session", allowed, details)
+	}
+	s.notifySecurityEvent(ctx, interfaces.SecurityEventSessionLimit, userID, details)
+}
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/config"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fixedSessionLimit int
+
+func (l fixedSessionLimit) MaxConcurrentSessions(ctx context.Context, userID string) (int, error) {
+	return int(l), nil
+}
+
+// loginPair is the token pair of the nth login, issued n minutes after the first
+func loginPair(n int) []interfaces.IssuedToken {
+	issuedAt := time.Now().Add(time.Duration(n) * time.Minute)
+	return []interfaces.IssuedToken{
+		{JTI: fmt.Sprintf("A%d", n), TokenType: "access", IssuedAt: issuedAt, ExpiresAt: issuedAt.Add(time.Hour)},
+		{JTI: fmt.Sprintf("R%d", n), TokenType: "refresh", IssuedAt: issuedAt, ExpiresAt: issuedAt.Add(24 * time.Hour)},
+	}
+}
+
+func TestSessionLimit_EvictsOldestLogin(t *testing.T) {
+	cache := &memoryCache{values: map[string]interface{}{}}
+	service, _ := newDeviceSessionTestService(t, cache, 5)
+	service.SetSessionLimit(config.SessionLimitConfig{MaxConcurrent: 2, Mode: config.SessionLimitModeEvictOldest}, nil)
+	ctx := context.Background()
+
+	for n := 1; n <= 2; n++ {
+		_, err := service.TrackIssuedTokens(ctx, "USER1", "", loginPair(n)...)
+		require.NoError(t, err)
+	}
+	newest, err := service.TrackIssuedTokens(ctx, "USER1", "", loginPair(3)...)
+	require.NoError(t, err, "the third login is let in")
+
+	assert.True(t, service.IsTokenRevoked("A1"))
+	assert.True(t, service.IsTokenRevoked("R1"), "the oldest login is signed out")
+	for _, jti := range []string{"A2", "R2", "A3", "R3"} {
+		assert.False(t, service.IsTokenRevoked(jti), jti)
+	}
+
+	sessions, err := service.ListTokenSessions(ctx, "USER1")
+	require.NoError(t, err)
+	require.Len(t, sessions, 4)
+	assert.Equal(t, newest, sessions[0].FamilyID)
+
+	// Refreshing a login joins its family and takes no new session
+	_, err = service.TrackIssuedTokens(ctx, "USER1", newest, loginPair(4)...)
+	require.NoError(t, err)
+	assert.False(t, service.IsTokenRevoked("R2"))
+}
+
+func TestSessionLimit_RejectsLoginOverLimit(t *testing.T) {
+	cache := &memoryCache{values: map[string]interface{}{}}
+	service, _ := newDeviceSessionTestService(t, cache, 5)
+	service.SetSessionLimit(config.SessionLimitConfig{MaxConcurrent: 2, Mode: config.SessionLimitModeReject}, nil)
+	ctx := context.Background()
+
+	for n := 1; n <= 2; n++ {
+		_, err := service.TrackIssuedTokens(ctx, "USER1", "", loginPair(n)...)
+		require.NoError(t, err)
+	}
+	_, err := service.TrackIssuedTokens(ctx, "USER1", "", loginPair(3)...)
+	assert.True(t, errors.IsForbiddenError(err), "the third login is refused")
+
+	sessions, err := service.ListTokenSessions(ctx, "USER1")
+	require.NoError(t, err)
+	assert.Len(t, sessions, 4, "the refused login is not recorded and no login is signed out")
+	assert.False(t, service.IsTokenRevoked("R1"))
+
+	_, err = service.TrackIssuedTokens(ctx, "USER2", "", loginPair(5)...)
+	assert.NoError(t, err, "the limit is per user")
+}
+
+func TestSessionLimit_OrganizationLowersLimit(t *testing.T) {
+	cache := &memoryCache{values: map[string]interface{}{}}
+	service, _ := newDeviceSessionTestService(t, cache, 5)
+	service.SetSessionLimit(config.SessionLimitConfig{MaxConcurrent: 5, Mode: config.SessionLimitModeReject}, fixedSessionLimit(1))
+	assert.Equal(t, 1, service.maxConcurrentSessions(context.Background(), "USER1"))
+
+	service.SetSessionLimit(config.SessionLimitConfig{MaxConcurrent: 2}, fixedSessionLimit(3))
+	assert.Equal(t, 2, service.maxConcurrentSessions(context.Background(), "USER1"), "an organization cannot raise the limit")
+
+	service.SetSessionLimit(config.SessionLimitConfig{}, fixedSessionLimit(3))
+	assert.Equal(t, 3, service.maxConcurrentSessions(context.Background(), "USER1"))
+}
+
+func TestSessionLimit_AppliesToServiceLogins(t *testing.T) {
+	cache := &memoryCache{values: map[string]interface{}{}}
+	service, userRepo := newDeviceSessionTestService(t, cache, 5)
+	service.refreshExpiry = 24 * time.Hour
+	service.SetSessionLimit(config.SessionLimitConfig{MaxConcurrent: 1, Mode: config.SessionLimitModeReject}, nil)
+	ctx := context.Background()
+	user := userRepo.users["USER1"]
+
+	_, refreshToken, err := service.issueTokens(ctx, user, nil, nil, "")
+	require.NoError(t, err)
+	accessToken, _, err := service.issueTokens(ctx, user, nil, nil, "")
+	assert.True(t, errors.IsForbiddenError(err), "the second login is refused")
+	assert.Empty(t, accessToken, "no token is signed for a refused login")
+
+	// Refreshing the first login stays within its session
+	claims, err := service.ValidateToken(refreshToken)
+	require.NoError(t, err)
+	familyID, err := service.RotateRefreshToken(ctx, user.ID, issuedTokenOf(claims))
+	require.NoError(t, err)
+	_, _, err = service.issueTokens(ctx, user, nil, nil, familyID)
+	assert.NoError(t, err)
+
+	service.SetSessionLimit(config.SessionLimitConfig{MaxConcurrent: 1, Mode: config.SessionLimitModeEvictOldest}, nil)
+	_, _, err = service.issueTokens(ctx, user, nil, nil, "")
+	require.NoError(t, err)
+	assert.True(t, service.IsTokenRevoked(claims.ID), "the older login is signed out")
+}
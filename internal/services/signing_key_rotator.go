@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/security"
+	"go.uber.org/zap"
+)
+
+// signingKeyCheckInterval is how often the rotator looks for keys rotated by other instances and
+// whether the current key is due for rotation
+const signingKeyCheckInterval = time.Minute
+
+// SigningKeyRotator keeps a signing key ring current: it reloads the keys rotated by other
+// instances sharing the key directory and, when a rotation interval is set, promotes a new key
+// once the current one reaches that age.
+type SigningKeyRotator struct {
+	keys     *security.SigningKeyRing
+	interval time.Duration
+	logger   *zap.Logger
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewSigningKeyRotator creates a rotator for keys. A zero interval only reloads the key ring, for
+// deployments that rotate on demand. Call Start to run it in the background.
+func NewSigningKeyRotator(keys *security.SigningKeyRing, interval time.Duration, logger *zap.Logger) *SigningKeyRotator {
+	return &SigningKeyRotator{
+		keys:     keys,
+		interval: interval,
+		logger:   logger.Named("signing_keys"),
+	}
+}
+
+// Start checks the signing keys in the background until Stop is called or ctx is done
+func (r *SigningKeyRotator) Start(ctx context.Context) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.cancel != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	r.cancel = cancel
+	r.done = make(chan struct{})
+
+	go func(done chan struct{}) {
+		defer close(done)
+		r.run(ctx)
+	}(r.done)
+
+	r.logger.Info("Signing key rotator started",
+		zap.String("current_kid", r.keys.Current().ID),
+		zap.Duration("rotation_interval", r.interval),
+		zap.Duration("overlap", r.keys.Overlap()))
+}
+
+// Stop stops the rotator and waits for the check in flight to finish
+func (r *SigningKeyRotator) Stop() {
+	r.mu.Lock()
+	cancel, done := r.cancel, r.done
+	r.cancel, r.done = nil, nil
+	r.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+	cancel()
+	<-done
+	r.logger.Info("Signing key rotator stopped")
+}
+
+func (r *SigningKeyRotator) run(ctx context.Context) {
+	ticker := time.NewTicker(signingKeyCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		r.CheckOnce()
+	}
+}
+
+// CheckOnce reloads the signing keys and rotates them if the current key is due
+func (r *SigningKeyRotator) CheckOnce() {
+	if r.interval <= 0 {
+		if err := r.keys.Reload(); err != nil {
+			r.logger.Warn("Failed to reload signing keys", zap.Error(err))
+		}
+		return
+	}
+
+	previous := r.keys.Current().ID
+	key, rotated, err := r.keys.RotateIfOlderThan(r.interval)
+	if err != nil {
+		r.logger.Error("Failed to rotate signing key", zap.String("current_kid", previous), zap.Error(err))
+		return
+	}
+	if rotated {
+		r.logger.Info("Signing key rotated",
+			zap.String("kid", key.KeyID),
+			zap.String("previous_kid", previous),
+			zap.Time("previous_verifies_until", key.CreatedAt.Add(r.keys.Overlap())))
+	}
+}
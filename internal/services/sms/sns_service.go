@@ -79,6 +79,20 @@ func NewSNSService(
 
 // SendOTP sends a password reset OTP via SMS
 func (s *SNSService) SendOTP(ctx context.Context, phoneNumber, otp string) error {
+	message := fmt.Sprintf("Your password reset code is %s. Valid for %d minutes. Do not share this code.",
+		otp, int(s.config.OTPExpiry.Minutes()))
+	return s.sendOTPMessage(ctx, phoneNumber, otp, models.SMSTypePasswordResetOTP, message)
+}
+
+// SendVerificationOTP sends a verification OTP, such as a contact verification code, via SMS
+func (s *SNSService) SendVerificationOTP(ctx context.Context, phoneNumber, otp string) error {
+	message := fmt.Sprintf("Your verification code is %s. Valid for %d minutes. Do not share this code.",
+		otp, int(s.config.OTPExpiry.Minutes()))
+	return s.sendOTPMessage(ctx, phoneNumber, otp, models.SMSTypeVerification, message)
+}
+
+// sendOTPMessage sends an OTP message of the given delivery log type
+func (s *SNSService) sendOTPMessage(ctx context.Context, phoneNumber, otp, messageType, message string) error {
 	if !s.config.Enabled {
 		s.logger.Info("SMS service disabled, skipping OTP send",
 			zap.String("phone_masked", models.MaskPhoneNumber(phoneNumber)))
@@ -98,7 +112,7 @@ func (s *SNSService) SendOTP(ctx context.Context, phoneNumber, otp string) error
 	}
 
 	// Create delivery log
-	deliveryLog := models.NewSMSDeliveryLog(models.SMSTypePasswordResetOTP, maskedPhone)
+	deliveryLog := models.NewSMSDeliveryLog(messageType, maskedPhone)
 	deliveryLog.AddDetail("otp_length", len(otp))
 
 	if err := s.deliveryRepo.Create(ctx, deliveryLog); err != nil {
@@ -106,10 +120,6 @@ func (s *SNSService) SendOTP(ctx context.Context, phoneNumber, otp string) error
 		// Continue anyway - don't block SMS sending
 	}
 
-	// Format message
-	message := fmt.Sprintf("Your password reset code is %s. Valid for %d minutes. Do not share this code.",
-		otp, int(s.config.OTPExpiry.Minutes()))
-
 	// Send SMS
 	messageID, err := s.sendSMS(ctx, phoneNumber, message)
 	if err != nil {
@@ -124,7 +134,7 @@ func (s *SNSService) SendOTP(ctx context.Context, phoneNumber, otp string) error
 		if s.auditService != nil {
 			s.auditService.LogUserActionWithError(ctx, "", "sms_otp_send_failed", "sms", deliveryLog.ID, err, map[string]interface{}{
 				"phone_masked": maskedPhone,
-				"message_type": models.SMSTypePasswordResetOTP,
+				"message_type": messageType,
 			})
 		}
 
@@ -143,7 +153,7 @@ func (s *SNSService) SendOTP(ctx context.Context, phoneNumber, otp string) error
 		s.auditService.LogUserAction(ctx, "", "sms_otp_sent", "sms", deliveryLog.ID, map[string]interface{}{
 			"phone_masked": maskedPhone,
 			"message_id":   messageID,
-			"message_type": models.SMSTypePasswordResetOTP,
+			"message_type": messageType,
 		})
 	}
 
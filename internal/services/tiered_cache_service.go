@@ -0,0 +1,311 @@
+package services
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"go.uber.org/zap"
+)
+
+// L1CacheConfig configures the in-process cache kept in front of Redis
+type L1CacheConfig struct {
+	Enabled bool
+	// MaxEntries bounds the cache; the least recently used entry is evicted beyond it
+	MaxEntries int
+	// TTL caps how long an entry is served from process memory. It is kept short because
+	// another instance's write only reaches this one through an invalidation broadcast.
+	TTL time.Duration
+}
+
+// TieredCacheService serves hot keys from a bounded in-process LRU (L1) and falls through to the
+// shared cache (L2, normally Redis) on a miss. Writes and deletes go to both tiers. Keys also
+// reports L1 entries matching the pattern, so pattern invalidations evict entries this instance
+// holds even after another instance has already removed them from Redis.
+type TieredCacheService struct {
+	l2     interfaces.CacheService
+	logger interfaces.Logger
+	config L1CacheConfig
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List
+	// generation advances on every delete so a read racing an invalidation does not put the
+	// value it fetched from L2 back into L1
+	generation uint64
+
+	l1Hits      int64
+	l2Hits      int64
+	misses      int64
+	l1Evictions int64
+}
+
+type l1Entry struct {
+	key       string
+	value     interface{}
+	expiresAt time.Time
+}
+
+// NewTieredCacheService wraps l2 with an L1 cache. l2 is returned unchanged when the L1 cache is
+// disabled or has no room.
+func NewTieredCacheService(l2 interfaces.CacheService, config L1CacheConfig, logger interfaces.Logger) interfaces.CacheService {
+	if !config.Enabled || config.MaxEntries <= 0 || config.TTL <= 0 {
+		return l2
+	}
+
+	logger.Info("L1 cache enabled",
+		zap.Int("max_entries", config.MaxEntries),
+		zap.Duration("ttl", config.TTL))
+
+	return &TieredCacheService{
+		l2:      l2,
+		logger:  logger,
+		config:  config,
+		entries: make(map[string]*list.Element),
+		lru:     list.New(),
+	}
+}
+
+// Get returns the L1 entry for key if it is still fresh, otherwise the L2 value, which is then
+// kept in L1
+func (c *TieredCacheService) Get(key string) (interface{}, bool) {
+	if value, ok := c.getL1(key); ok {
+		atomic.AddInt64(&c.l1Hits, 1)
+		return value, true
+	}
+
+	c.mu.Lock()
+	generation := c.generation
+	c.mu.Unlock()
+
+	value, ok := c.l2.Get(key)
+	if !ok {
+		atomic.AddInt64(&c.misses, 1)
+		return nil, false
+	}
+	atomic.AddInt64(&c.l2Hits, 1)
+
+	c.mu.Lock()
+	if c.generation == generation {
+		c.storeL1(key, value, c.config.TTL)
+	}
+	c.mu.Unlock()
+	return value, true
+}
+
+// Set writes value to L2 and keeps it in L1 for at most the L1 TTL
+func (c *TieredCacheService) Set(key string, value interface{}, ttl int) error {
+	if err := c.l2.Set(key, value, ttl); err != nil {
+		c.deleteL1(key)
+		return err
+	}
+
+	l1TTL := c.config.TTL
+	if ttl > 0 && time.Duration(ttl)*time.Second < l1TTL {
+		l1TTL = time.Duration(ttl) * time.Second
+	}
+	c.mu.Lock()
+	c.storeL1(key, decodedLikeL2(value), l1TTL)
+	c.mu.Unlock()
+	return nil
+}
+
+// Delete removes key from both tiers. L2 goes first so a concurrent Get cannot refill L1 with
+// the value being deleted.
+func (c *TieredCacheService) Delete(key string) error {
+	err := c.l2.Delete(key)
+	c.deleteL1(key)
+	return err
+}
+
+// Exists checks L1 before L2
+func (c *TieredCacheService) Exists(key string) bool {
+	if _, ok := c.getL1(key); ok {
+		return true
+	}
+	return c.l2.Exists(key)
+}
+
+// Clear empties both tiers
+func (c *TieredCacheService) Clear() error {
+	c.mu.Lock()
+	c.entries = make(map[string]*list.Element)
+	c.lru.Init()
+	c.generation++
+	c.mu.Unlock()
+	return c.l2.Clear()
+}
+
+// Keys returns the L2 keys matching pattern together with the L1 keys matching it
+func (c *TieredCacheService) Keys(pattern string) ([]string, error) {
+	keys, err := c.l2.Keys(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		seen[key] = true
+	}
+
+	c.mu.Lock()
+	for key := range c.entries {
+		if !seen[key] && matchCachePattern(pattern, key) {
+			keys = append(keys, key)
+		}
+	}
+	c.mu.Unlock()
+
+	return keys, nil
+}
+
+// Expire changes the L2 TTL of key and drops the L1 copy so it is re-read with the new TTL
+func (c *TieredCacheService) Expire(key string, ttl int) error {
+	c.deleteL1(key)
+	return c.l2.Expire(key, ttl)
+}
+
+// TTL returns the L2 TTL of key
+func (c *TieredCacheService) TTL(key string) (int, error) {
+	return c.l2.TTL(key)
+}
+
+// Close closes L2
+func (c *TieredCacheService) Close() error {
+	return c.l2.Close()
+}
+
+// Ping checks L2, so readiness still reports Redis when the L1 cache is in front of it. An L2
+// without a connection to check is always reachable.
+func (c *TieredCacheService) Ping(ctx context.Context) error {
+	if pinger, ok := c.l2.(interface{ Ping(context.Context) error }); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+// L2 returns the cache behind the L1 tier
+func (c *TieredCacheService) L2() interfaces.CacheService {
+	return c.l2
+}
+
+// Stats returns the hit counters of both tiers
+func (c *TieredCacheService) Stats() interfaces.CacheTierStats {
+	c.mu.Lock()
+	entries := c.lru.Len()
+	c.mu.Unlock()
+
+	return interfaces.CacheTierStats{
+		L1Hits:      atomic.LoadInt64(&c.l1Hits),
+		L2Hits:      atomic.LoadInt64(&c.l2Hits),
+		Misses:      atomic.LoadInt64(&c.misses),
+		L1Evictions: atomic.LoadInt64(&c.l1Evictions),
+		L1Entries:   entries,
+	}
+}
+
+func (c *TieredCacheService) getL1(key string) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := element.Value.(*l1Entry)
+	if time.Now().After(entry.expiresAt) {
+		c.lru.Remove(element)
+		delete(c.entries, key)
+		return nil, false
+	}
+	c.lru.MoveToFront(element)
+	return entry.value, true
+}
+
+// storeL1 keeps value in L1, evicting the least recently used entries beyond MaxEntries.
+// The caller holds c.mu.
+func (c *TieredCacheService) storeL1(key string, value interface{}, ttl time.Duration) {
+	expiresAt := time.Now().Add(ttl)
+	if element, ok := c.entries[key]; ok {
+		entry := element.Value.(*l1Entry)
+		entry.value = value
+		entry.expiresAt = expiresAt
+		c.lru.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.lru.PushFront(&l1Entry{key: key, value: value, expiresAt: expiresAt})
+	for c.lru.Len() > c.config.MaxEntries {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*l1Entry).key)
+		atomic.AddInt64(&c.l1Evictions, 1)
+	}
+}
+
+func (c *TieredCacheService) deleteL1(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	if element, ok := c.entries[key]; ok {
+		c.lru.Remove(element)
+		delete(c.entries, key)
+	}
+}
+
+// decodedLikeL2 returns value in the shape a Redis read returns it (a JSON round trip), so callers
+// see the same types whichever tier serves them
+func decodedLikeL2(value interface{}) interface{} {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return value
+	}
+	return decoded
+}
+
+// matchCachePattern reports whether key matches a Redis KEYS glob pattern. It supports *, ? and
+// backslash escapes, which is what the cache invalidation patterns use.
+func matchCachePattern(pattern, key string) bool {
+	for len(pattern) > 0 {
+		switch pattern[0] {
+		case '*':
+			for len(pattern) > 0 && pattern[0] == '*' {
+				pattern = pattern[1:]
+			}
+			if len(pattern) == 0 {
+				return true
+			}
+			for i := 0; i <= len(key); i++ {
+				if matchCachePattern(pattern, key[i:]) {
+					return true
+				}
+			}
+			return false
+		case '?':
+			if len(key) == 0 {
+				return false
+			}
+		case '\\':
+			if len(pattern) > 1 {
+				pattern = pattern[1:]
+			}
+			fallthrough
+		default:
+			if len(key) == 0 || key[0] != pattern[0] {
+				return false
+			}
+		}
+		pattern = pattern[1:]
+		key = key[1:]
+	}
+	return len(key) == 0
+}
@@ -0,0 +1,134 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newTestTieredCache(t *testing.T, l2 interfaces.CacheService, maxEntries int, ttl time.Duration) *TieredCacheService {
+	t.Helper()
+	cache, ok := NewTieredCacheService(l2, L1CacheConfig{Enabled: true, MaxEntries: maxEntries, TTL: ttl}, utils.NewLoggerAdapter(zap.NewNop())).(*TieredCacheService)
+	require.True(t, ok)
+	return cache
+}
+
+func TestTieredCacheService_ServesHotKeysFromL1(t *testing.T) {
+	l2 := &memoryCache{values: map[string]interface{}{"role:ROLE1": "admin"}}
+	cache := newTestTieredCache(t, l2, 10, time.Minute)
+
+	value, ok := cache.Get("role:ROLE1")
+	require.True(t, ok)
+	assert.Equal(t, "admin", value)
+
+	// A write that bypasses this instance is not seen until the key is invalidated
+	l2.values["role:ROLE1"] = "editor"
+	value, _ = cache.Get("role:ROLE1")
+	assert.Equal(t, "admin", value)
+
+	require.NoError(t, cache.Delete("role:ROLE1"))
+	l2.values["role:ROLE1"] = "editor"
+	value, _ = cache.Get("role:ROLE1")
+	assert.Equal(t, "editor", value)
+
+	_, ok = cache.Get("role:MISSING")
+	assert.False(t, ok)
+
+	assert.Equal(t, interfaces.CacheTierStats{L1Hits: 1, L2Hits: 2, Misses: 1, L1Entries: 1}, cache.Stats())
+}
+
+func TestTieredCacheService_SetStoresValuesAsRedisReturnsThem(t *testing.T) {
+	l2 := &memoryCache{values: map[string]interface{}{}}
+	cache := newTestTieredCache(t, l2, 10, time.Minute)
+
+	type permission struct {
+		Name string `json:"name"`
+	}
+	require.NoError(t, cache.Set("permission:P1", permission{Name: "farm:read"}, 300))
+
+	value, ok := cache.Get("permission:P1")
+	require.True(t, ok)
+	assert.Equal(t, map[string]interface{}{"name": "farm:read"}, value)
+	assert.Equal(t, int64(1), cache.Stats().L1Hits)
+}
+
+func TestTieredCacheService_EvictsLeastRecentlyUsedAndExpired(t *testing.T) {
+	l2 := &memoryCache{values: map[string]interface{}{"a": "1", "b": "2", "c": "3"}}
+	cache := newTestTieredCache(t, l2, 2, time.Minute)
+
+	cache.Get("a")
+	cache.Get("b")
+	cache.Get("a") // b is now the least recently used
+	cache.Get("c")
+
+	stats := cache.Stats()
+	assert.Equal(t, int64(1), stats.L1Evictions)
+	assert.Equal(t, 2, stats.L1Entries)
+	_, inL1 := cache.getL1("b")
+	assert.False(t, inL1)
+	_, inL1 = cache.getL1("a")
+	assert.True(t, inL1)
+
+	short := newTestTieredCache(t, l2, 10, 20*time.Millisecond)
+	short.Get("a")
+	time.Sleep(30 * time.Millisecond)
+	_, inL1 = short.getL1("a")
+	assert.False(t, inL1)
+}
+
+func TestTieredCacheService_BroadcastInvalidationEvictsL1(t *testing.T) {
+	l2 := &memoryCache{values: map[string]interface{}{
+		"permission:USER1:farm:F1:view": "true",
+		"permission:USER2:farm:F1:view": "true",
+	}}
+	cache := newTestTieredCache(t, l2, 10, time.Minute)
+	cache.Get("permission:USER1:farm:F1:view")
+	cache.Get("permission:USER2:farm:F1:view")
+
+	// The publishing instance has already removed the keys from the shared cache
+	delete(l2.values, "permission:USER1:farm:F1:view")
+
+	broadcaster := NewRedisInvalidationBroadcaster(nil, cache, utils.NewLoggerAdapter(zap.NewNop()))
+	broadcaster.receive(context.Background(), invalidationPayload(t, interfaces.CacheInvalidation{
+		Patterns: []string{"permission:USER1:*"},
+		Origin:   "other-instance",
+	}))
+
+	_, ok := cache.Get("permission:USER1:farm:F1:view")
+	assert.False(t, ok)
+	_, ok = cache.Get("permission:USER2:farm:F1:view")
+	assert.True(t, ok)
+}
+
+func TestNewTieredCacheService_DisabledReturnsL2(t *testing.T) {
+	l2 := &memoryCache{values: map[string]interface{}{}}
+	cache := NewTieredCacheService(l2, L1CacheConfig{MaxEntries: 10, TTL: time.Second}, utils.NewLoggerAdapter(zap.NewNop()))
+	assert.Same(t, l2, cache)
+}
+
+func TestMatchCachePattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		key     string
+		want    bool
+	}{
+		{"permission:USER1:*", "permission:USER1:farm:F1:view", true},
+		{"permission:USER1:*", "permission:USER2:farm:F1:view", false},
+		{"*:permissions", "role:ROLE1:permissions", true},
+		{"role:ROLE?", "role:ROLE1", true},
+		{"role:ROLE?", "role:ROLE12", false},
+		{"user_roles:*/x", "user_roles:a/b/x", true},
+		{`role\*`, "role*", true},
+		{`role\*`, "roles", false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, matchCachePattern(tt.pattern, tt.key), "%s ~ %s", tt.pattern, tt.key)
+	}
+}
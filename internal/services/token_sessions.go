@@ -0,0 +1,369 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/responses"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// Issued tokens
+//
+// The access and refresh tokens handed out at login are recorded in the cache so that users and
+// admins can list and revoke them:
+//
+//	token_session:<user id>:<jti>   token record (type, family, client, issue and expiry time)
+//	revoked_token:<jti>             family of a revoked token, kept until the token expires
+//
+// The tokens of one login form a family. Refreshing rotates the refresh token: the presented token
+// is revoked and the new pair joins its family. A revoked refresh token that is presented again
+// was either replayed or stolen, so its whole family is revoked. Revoking a refresh token signs the
+// login out by revoking its family; revoking an access token revokes only that token.
+
+// tokenSession is the cached record of an issued token
+type tokenSession struct {
+	JTI       string    `json:"jti"`
+	UserID    string    `json:"user_id"`
+	TokenType string    `json:"token_type"`
+	FamilyID  string    `json:"family_id"`
+	IPAddress string    `json:"ip_address,omitempty"`
+	UserAgent string    `json:"user_agent,omitempty"`
+	IssuedAt  time.Time `json:"issued_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// TrackIssuedTokens records tokens just issued to a user, with the client's IP address and user
+// agent from the request context. The tokens join the given family; an empty familyID starts a new
+// family, that is a new login, which is subject to the concurrent session limit. The family ID is
+// returned.
+func (s *AuthService) TrackIssuedTokens(ctx context.Context, userID, familyID string, tokens ...interfaces.IssuedToken) (string, error) {
+	if userID == "" {
+		return "", errors.NewValidationError("user ID is required")
+	}
+
+	if familyID == "" {
+		if err := s.enforceSessionLimit(ctx, userID); err != nil {
+			return "", err
+		}
+	}
+	return s.recordIssuedTokens(ctx, userID, familyID, tokens...)
+}
+
+// recordIssuedTokens saves the records of tokens issued to a user in the given family, or in a new
+// family when familyID is empty, and returns the family ID
+func (s *AuthService) recordIssuedTokens(ctx context.Context, userID, familyID string, tokens ...interfaces.IssuedToken) (string, error) {
+	if familyID == "" {
+		id, err := generateChallengeToken()
+		if err != nil {
+			return "", errors.NewInternalError(fmt.Errorf("failed to generate token family: %w", err))
+		}
+		familyID = "TFAM" + id[:16]
+	}
+
+	userAgent, _ := ctx.Value("user_agent").(string)
+	ipAddress, _ := ctx.Value("ip_address").(string)
+
+	for _, token := range tokens {
+		if token.JTI == "" {
+			continue
+		}
+		session := &tokenSession{
+			JTI:       token.JTI,
+			UserID:    userID,
+			TokenType: token.TokenType,
+			FamilyID:  familyID,
+			IPAddress: ipAddress,
+			UserAgent: userAgent,
+			IssuedAt:  token.IssuedAt,
+			ExpiresAt: token.ExpiresAt,
+		}
+		if err := s.saveTokenSession(session); err != nil {
+			return familyID, errors.NewInternalError(fmt.Errorf("failed to record issued token: %w", err))
+		}
+	}
+	return familyID, nil
+}
+
+// issueTokens signs an access and refresh token pair for a user and records both in the given
+// token family, a new one when familyID is empty, so that logins through AuthService are listed
+// and revocable like those of the HTTP handlers. A new family is a new login, so the concurrent
+// session limit is enforced before anything is signed; in reject mode a forbidden error refuses
+// the login. The tokens are valid without the record, so a failure to save it is only logged.
+func (s *AuthService) issueTokens(ctx context.Context, user *models.User, roles []*models.UserRole, permissions []string, familyID string) (string, string, error) {
+	if familyID == "" {
+		if err := s.enforceSessionLimit(ctx, user.ID); err != nil {
+			return "", "", err
+		}
+	}
+
+	accessToken, err := s.generateAccessToken(user, roles, permissions)
+	if err != nil {
+		return "", "", errors.NewInternalError(fmt.Errorf("failed to generate access token: %w", err))
+	}
+	refreshToken, err := s.generateRefreshToken(user, roles, permissions)
+	if err != nil {
+		return "", "", errors.NewInternalError(fmt.Errorf("failed to generate refresh token: %w", err))
+	}
+
+	var issued []interfaces.IssuedToken
+	for _, token := range []string{accessToken, refreshToken} {
+		claims, err := s.validateToken(token)
+		if err != nil {
+			s.logger.Warn("Failed to read issued token", zap.String("user_id", user.ID), zap.Error(err))
+			continue
+		}
+		issued = append(issued, issuedTokenOf(claims))
+	}
+	if _, err := s.recordIssuedTokens(ctx, user.ID, familyID, issued...); err != nil {
+		s.logger.Warn("Failed to record issued tokens", zap.String("user_id", user.ID), zap.Error(err))
+	}
+	return accessToken, refreshToken, nil
+}
+
+// issuedTokenOf describes a token this service signed from its claims
+func issuedTokenOf(claims *TokenClaims) interfaces.IssuedToken {
+	issued := interfaces.IssuedToken{JTI: claims.ID, TokenType: claims.TokenType}
+	if claims.IssuedAt != nil {
+		issued.IssuedAt = claims.IssuedAt.Time
+	}
+	if claims.ExpiresAt != nil {
+		issued.ExpiresAt = claims.ExpiresAt.Time
+	}
+	return issued
+}
+
+// newTokenID returns a random jti for a token this service signs
+func newTokenID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("jti_%d", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
+
+// RotateRefreshToken revokes a refresh token that is being exchanged for a new token pair and
+// returns the family the new pair joins. A refresh token that was already revoked is rejected as
+// unauthorized and its family is revoked. A token issued before tracking began starts a new family.
+func (s *AuthService) RotateRefreshToken(ctx context.Context, userID string, refreshToken interfaces.IssuedToken) (string, error) {
+	if userID == "" {
+		return "", errors.NewValidationError("user ID is required")
+	}
+	if refreshToken.JTI == "" {
+		return "", nil
+	}
+
+	if familyID, revoked := s.revokedTokenFamily(refreshToken.JTI); revoked {
+		if familyID != "" {
+			s.revokeTokenFamily(userID, familyID)
+		}
+		s.logger.Warn("Revoked refresh token presented, token family revoked",
+			zap.String("user_id", userID),
+			zap.String("family_id", familyID))
+		return "", errors.NewUnauthorizedError("refresh token has been revoked")
+	}
+
+	familyID := ""
+	if session, ok := s.loadTokenSession(tokenSessionKey(userID, refreshToken.JTI)); ok {
+		familyID = session.FamilyID
+	}
+	s.revokeToken(userID, refreshToken.JTI, familyID, refreshToken.ExpiresAt)
+	return familyID, nil
+}
+
+// ListTokenSessions returns the unexpired, unrevoked tokens issued to a user, most recent first
+func (s *AuthService) ListTokenSessions(ctx context.Context, userID string) ([]*responses.TokenSessionResponse, error) {
+	if userID == "" {
+		return nil, errors.NewValidationError("user ID is required")
+	}
+
+	sessions, err := s.userTokenSessions(userID)
+	if err != nil {
+		return nil, errors.NewInternalError(fmt.Errorf("failed to list token sessions: %w", err))
+	}
+
+	result := make([]*responses.TokenSessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		result = append(result, &responses.TokenSessionResponse{
+			JTI:       session.JTI,
+			TokenType: session.TokenType,
+			FamilyID:  session.FamilyID,
+			IPAddress: session.IPAddress,
+			UserAgent: session.UserAgent,
+			IssuedAt:  session.IssuedAt,
+			ExpiresAt: session.ExpiresAt,
+		})
+	}
+	return result, nil
+}
+
+// RevokeTokenSession revokes a token issued to the user. Revoking a refresh token revokes its whole
+// family, so the login can neither refresh nor use the access tokens issued with it. A token of
+// another user is reported as not found.
+func (s *AuthService) RevokeTokenSession(ctx context.Context, userID, jti string) error {
+	if userID == "" || jti == "" {
+		return errors.NewValidationError("user ID and token ID are required")
+	}
+
+	session, ok := s.loadTokenSession(tokenSessionKey(userID, jti))
+	if !ok || session.UserID != userID {
+		return errors.NewNotFoundError("session not found")
+	}
+
+	if session.TokenType == "refresh" && session.FamilyID != "" {
+		s.revokeTokenFamily(userID, session.FamilyID)
+	} else {
+		s.revokeToken(userID, session.JTI, session.FamilyID, session.ExpiresAt)
+	}
+
+	s.logger.Info("Token session revoked",
+		zap.String("user_id", userID),
+		zap.String("jti", jti),
+		zap.String("token_type", session.TokenType),
+		zap.String("family_id", session.FamilyID))
+	return nil
+}
+
+// RevokeAllSessions signs a user out everywhere: every issued token is revoked and every remembered
+// device session is deleted. It returns how many tokens and device sessions were revoked.
+func (s *AuthService) RevokeAllSessions(ctx context.Context, userID string) (int, error) {
+	if userID == "" {
+		return 0, errors.NewValidationError("user ID is required")
+	}
+
+	tokens, err := s.userTokenSessions(userID)
+	if err != nil {
+		return 0, errors.NewInternalError(fmt.Errorf("failed to list token sessions: %w", err))
+	}
+	for _, session := range tokens {
+		s.revokeToken(userID, session.JTI, session.FamilyID, session.ExpiresAt)
+	}
+
+	devices, err := s.userDeviceSessions(userID)
+	if err != nil {
+		return len(tokens), errors.NewInternalError(fmt.Errorf("failed to list device sessions: %w", err))
+	}
+	for _, session := range devices {
+		s.deleteDeviceSession(userID, session.ID, session.TokenHash)
+	}
+
+	s.logger.Info("All sessions revoked",
+		zap.String("user_id", userID),
+		zap.Int("tokens", len(tokens)),
+		zap.Int("device_sessions", len(devices)))
+	return len(tokens) + len(devices), nil
+}
+
+// IsTokenRevoked reports whether the token with the given ID has been revoked
+func (s *AuthService) IsTokenRevoked(jti string) bool {
+	if jti == "" {
+		return false
+	}
+	_, revoked := s.revokedTokenFamily(jti)
+	return revoked
+}
+
+// userTokenSessions loads the unexpired tokens of a user, most recently issued first
+func (s *AuthService) userTokenSessions(userID string) ([]*tokenSession, error) {
+	keys, err := s.cacheService.Keys(tokenSessionKey(userID, "*"))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	sessions := make([]*tokenSession, 0, len(keys))
+	for _, key := range keys {
+		session, ok := s.loadTokenSession(key)
+		if !ok || session.UserID != userID || !now.Before(session.ExpiresAt) {
+			continue
+		}
+		sessions = append(sessions, session)
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].IssuedAt.After(sessions[j].IssuedAt)
+	})
+	return sessions, nil
+}
+
+// revokeTokenFamily revokes every recorded token of a family
+func (s *AuthService) revokeTokenFamily(userID, familyID string) {
+	sessions, err := s.userTokenSessions(userID)
+	if err != nil {
+		s.logger.Warn("Failed to load token sessions for revocation",
+			zap.String("user_id", userID),
+			zap.String("family_id", familyID),
+			zap.Error(err))
+		return
+	}
+	for _, session := range sessions {
+		if session.FamilyID == familyID {
+			s.revokeToken(userID, session.JTI, familyID, session.ExpiresAt)
+		}
+	}
+}
+
+// revokeToken blocks the token until it expires and drops its record
+func (s *AuthService) revokeToken(userID, jti, familyID string, expiresAt time.Time) {
+	if ttl := int(time.Until(expiresAt).Seconds()); ttl > 0 {
+		if err := s.cacheService.Set(revokedTokenKey(jti), familyID, ttl); err != nil {
+			s.logger.Warn("Failed to revoke token", zap.String("user_id", userID), zap.String("jti", jti), zap.Error(err))
+		}
+	}
+	if err := s.cacheService.Delete(tokenSessionKey(userID, jti)); err != nil {
+		s.logger.Warn("Failed to delete token session", zap.String("user_id", userID), zap.String("jti", jti), zap.Error(err))
+	}
+}
+
+func (s *AuthService) revokedTokenFamily(jti string) (string, bool) {
+	value, ok := s.cacheService.Get(revokedTokenKey(jti))
+	if !ok {
+		return "", false
+	}
+	familyID, _ := value.(string)
+	return familyID, true
+}
+
+// saveTokenSession caches the record until the token expires, as a JSON string like the device sessions
+func (s *AuthService) saveTokenSession(session *tokenSession) error {
+	ttl := int(time.Until(session.ExpiresAt).Seconds())
+	if ttl <= 0 {
+		return nil
+	}
+	data, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+	return s.cacheService.Set(tokenSessionKey(session.UserID, session.JTI), string(data), ttl)
+}
+
+func (s *AuthService) loadTokenSession(key string) (*tokenSession, bool) {
+	value, ok := s.cacheService.Get(key)
+	if !ok {
+		return nil, false
+	}
+	data, ok := value.(string)
+	if !ok {
+		return nil, false
+	}
+	var session tokenSession
+	if err := json.Unmarshal([]byte(data), &session); err != nil || session.UserID == "" || session.JTI == "" {
+		return nil, false
+	}
+	return &session, true
+}
+
+func tokenSessionKey(userID, jti string) string {
+	return fmt.Sprintf("token_session:%s:%s", userID, jti)
+}
+
+func revokedTokenKey(jti string) string {
+	return fmt.Sprintf("revoked_token:%s", jti)
+}
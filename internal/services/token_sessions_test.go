@@ -0,0 +1,163 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/config"
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func issuedPair(access, refresh string) []interfaces.IssuedToken {
+	now := time.Now()
+	return []interfaces.IssuedToken{
+		{JTI: access, TokenType: "access", IssuedAt: now, ExpiresAt: now.Add(time.Hour)},
+		{JTI: refresh, TokenType: "refresh", IssuedAt: now, ExpiresAt: now.Add(24 * time.Hour)},
+	}
+}
+
+func TestTokenSessions_TrackAndList(t *testing.T) {
+	cache := &memoryCache{values: map[string]interface{}{}}
+	service, _ := newDeviceSessionTestService(t, cache, 5)
+
+	familyID, err := service.TrackIssuedTokens(deviceRequestContext("203.0.113.1", "App/1.0"), "USER1", "", issuedPair("A1", "R1")...)
+	require.NoError(t, err)
+	assert.NotEmpty(t, familyID)
+	_, err = service.TrackIssuedTokens(context.Background(), "USER2", "", issuedPair("A9", "R9")...)
+	require.NoError(t, err)
+
+	sessions, err := service.ListTokenSessions(context.Background(), "USER1")
+	require.NoError(t, err)
+	require.Len(t, sessions, 2, "only the user's own tokens are listed")
+	for _, session := range sessions {
+		assert.Equal(t, familyID, session.FamilyID)
+		assert.Equal(t, "203.0.113.1", session.IPAddress)
+		assert.Equal(t, "App/1.0", session.UserAgent)
+	}
+}
+
+func TestTokenSessions_RevokeAccessToken(t *testing.T) {
+	cache := &memoryCache{values: map[string]interface{}{}}
+	service, _ := newDeviceSessionTestService(t, cache, 5)
+	_, err := service.TrackIssuedTokens(context.Background(), "USER1", "", issuedPair("A1", "R1")...)
+	require.NoError(t, err)
+
+	assert.True(t, errors.IsNotFoundError(service.RevokeTokenSession(context.Background(), "USER2", "A1")), "another user's token is not found")
+
+	require.NoError(t, service.RevokeTokenSession(context.Background(), "USER1", "A1"))
+	assert.True(t, service.IsTokenRevoked("A1"))
+	assert.False(t, service.IsTokenRevoked("R1"), "the refresh token of the login stays valid")
+
+	sessions, err := service.ListTokenSessions(context.Background(), "USER1")
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, "R1", sessions[0].JTI)
+}
+
+func TestTokenSessions_RotationKeepsFamily(t *testing.T) {
+	cache := &memoryCache{values: map[string]interface{}{}}
+	service, _ := newDeviceSessionTestService(t, cache, 5)
+	ctx := context.Background()
+
+	familyID, err := service.TrackIssuedTokens(ctx, "USER1", "", issuedPair("A1", "R1")...)
+	require.NoError(t, err)
+
+	rotatedFamily, err := service.RotateRefreshToken(ctx, "USER1", issuedPair("A1", "R1")[1])
+	require.NoError(t, err)
+	assert.Equal(t, familyID, rotatedFamily)
+	assert.True(t, service.IsTokenRevoked("R1"), "a refresh token is used once")
+	_, err = service.TrackIssuedTokens(ctx, "USER1", rotatedFamily, issuedPair("A2", "R2")...)
+	require.NoError(t, err)
+
+	// Revoking the current refresh token signs the whole login out
+	require.NoError(t, service.RevokeTokenSession(ctx, "USER1", "R2"))
+	for _, jti := range []string{"A1", "A2", "R2"} {
+		assert.True(t, service.IsTokenRevoked(jti), jti)
+	}
+	sessions, err := service.ListTokenSessions(ctx, "USER1")
+	require.NoError(t, err)
+	assert.Empty(t, sessions)
+
+	_, err = service.RotateRefreshToken(ctx, "USER1", issuedPair("A2", "R2")[1])
+	assert.True(t, errors.IsUnauthorizedError(err), "a revoked refresh token cannot be refreshed")
+}
+
+func TestTokenSessions_ReplayedRefreshTokenRevokesFamily(t *testing.T) {
+	cache := &memoryCache{values: map[string]interface{}{}}
+	service, _ := newDeviceSessionTestService(t, cache, 5)
+	ctx := context.Background()
+
+	familyID, err := service.TrackIssuedTokens(ctx, "USER1", "", issuedPair("A1", "R1")...)
+	require.NoError(t, err)
+	_, err = service.TrackIssuedTokens(ctx, "USER1", "", issuedPair("B1", "S1")...)
+	require.NoError(t, err)
+
+	_, err = service.RotateRefreshToken(ctx, "USER1", issuedPair("A1", "R1")[1])
+	require.NoError(t, err)
+	_, err = service.TrackIssuedTokens(ctx, "USER1", familyID, issuedPair("A2", "R2")...)
+	require.NoError(t, err)
+
+	_, err = service.RotateRefreshToken(ctx, "USER1", issuedPair("A1", "R1")[1])
+	assert.True(t, errors.IsUnauthorizedError(err))
+	assert.True(t, service.IsTokenRevoked("A2"))
+	assert.True(t, service.IsTokenRevoked("R2"), "replaying a rotated refresh token revokes its family")
+	assert.False(t, service.IsTokenRevoked("S1"), "other logins are not affected")
+}
+
+func TestRevokeAllSessions(t *testing.T) {
+	cache := &memoryCache{values: map[string]interface{}{}}
+	service, _ := newDeviceSessionTestService(t, cache, 5)
+	ctx := context.Background()
+
+	_, err := service.TrackIssuedTokens(ctx, "USER1", "", issuedPair("A1", "R1")...)
+	require.NoError(t, err)
+	_, err = service.TrackIssuedTokens(ctx, "USER2", "", issuedPair("B1", "S1")...)
+	require.NoError(t, err)
+	device, err := service.CreateDeviceSession(ctx, "USER1", "Pixel 8")
+	require.NoError(t, err)
+
+	revoked, err := service.RevokeAllSessions(ctx, "USER1")
+	require.NoError(t, err)
+	assert.Equal(t, 3, revoked)
+	assert.True(t, service.IsTokenRevoked("A1"))
+	assert.True(t, service.IsTokenRevoked("R1"))
+	assert.False(t, service.IsTokenRevoked("S1"), "other users are not affected")
+
+	_, err = service.ExchangeDeviceSession(ctx, device.SessionToken)
+	assert.Error(t, err, "the remembered device is signed out")
+	sessions, err := service.ListTokenSessions(ctx, "USER1")
+	require.NoError(t, err)
+	assert.Empty(t, sessions)
+}
+
+func TestTokenSessions_ServiceLoginTokensAreTracked(t *testing.T) {
+	cache := &memoryCache{values: map[string]interface{}{}}
+	jwtCfg := &config.JWTConfig{Secret: "test-secret", Issuer: "aaa-service", TTL: time.Hour}
+	service, err := NewAuthService(nil, nil, nil, cache, nil, nil,
+		&AuthServiceConfig{JWTSecret: jwtCfg.Secret, RefreshExpiry: 24 * time.Hour}, zap.NewNop(), nil, jwtCfg)
+	require.NoError(t, err)
+	user := models.NewUser("9876543210", "+91", "hash")
+	user.ID = "USER1"
+
+	accessToken, refreshToken, err := service.issueTokens(deviceRequestContext("203.0.113.1", "grpc-go/1.60"), user, nil, nil, "")
+	require.NoError(t, err)
+
+	sessions, err := service.ListTokenSessions(context.Background(), "USER1")
+	require.NoError(t, err)
+	require.Len(t, sessions, 2, "the access and refresh token are both listed")
+	assert.Equal(t, sessions[0].FamilyID, sessions[1].FamilyID)
+	assert.Equal(t, "grpc-go/1.60", sessions[0].UserAgent)
+
+	refreshClaims, err := service.ValidateToken(refreshToken)
+	require.NoError(t, err)
+	require.NoError(t, service.RevokeTokenSession(context.Background(), "USER1", refreshClaims.ID))
+	accessClaims, err := service.ValidateToken(accessToken)
+	require.NoError(t, err)
+	assert.True(t, service.IsTokenRevoked(accessClaims.ID), "revoking the refresh token signs the login out")
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/security"
+	jwt "github.com/golang-jwt/jwt/v4"
+)
+
+// SetSigningKeys signs tokens with the current RS256 key of keys instead of the HS256 secret.
+// Tokens signed with the secret are refused from then on, unless keys accept them for a migration
+// window (see SigningKeyRing.AcceptHS256Until).
+func (s *AuthService) SetSigningKeys(keys *security.SigningKeyRing) {
+	s.signingKeys = keys
+}
+
+// signToken signs claims with the current signing key, naming it in the kid header, or with the
+// HS256 secret when no signing keys are configured
+func (s *AuthService) signToken(claims jwt.Claims) (string, error) {
+	if s.signingKeys == nil {
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(s.jwtCfg.Secret))
+	}
+
+	key := s.signingKeys.Current()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.PrivateKey)
+}
+
+// SignToken signs claims as the tokens this service issues are signed, so other issuers such as the
+// HTTP auth handler use the current signing key
+func (s *AuthService) SignToken(claims jwt.Claims) (string, error) {
+	return s.signToken(claims)
+}
+
+// TokenKey returns the key a token issued by this service is verified with
+func (s *AuthService) TokenKey(token *jwt.Token) (interface{}, error) {
+	return s.tokenKey(token)
+}
+
+// tokenKey returns the key a token is verified with: the signing key named by its kid header for
+// RS256 tokens, the HS256 secret for HS256 tokens while signing keys are not configured or their
+// migration window is open
+func (s *AuthService) tokenKey(token *jwt.Token) (interface{}, error) {
+	switch token.Method.(type) {
+	case *jwt.SigningMethodHMAC:
+		if s.signingKeys != nil && !s.signingKeys.AcceptsHS256() {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return []byte(s.jwtCfg.Secret), nil
+	case *jwt.SigningMethodRSA:
+		if s.signingKeys == nil {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		kid, _ := token.Header["kid"].(string)
+		return s.signingKeys.PublicKey(kid)
+	default:
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+}
@@ -0,0 +1,65 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/security"
+	jwt "github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignToken_PreviousKeyValidatesAfterRotation(t *testing.T) {
+	service := newServiceTokenAuthService(t)
+	user := models.NewUser("9876543210", "+91", "hashed")
+	user.ID = "USER1"
+
+	keys, err := security.LoadSigningKeyRing(t.TempDir(), time.Hour)
+	require.NoError(t, err)
+	service.SetSigningKeys(keys)
+
+	oldToken, err := service.generateAccessToken(user, nil, nil)
+	require.NoError(t, err)
+	parsed, _, err := jwt.NewParser().ParseUnverified(oldToken, &TokenClaims{})
+	require.NoError(t, err)
+	assert.Equal(t, "RS256", parsed.Method.Alg())
+	assert.Equal(t, keys.Current().ID, parsed.Header["kid"])
+
+	_, err = keys.Rotate()
+	require.NoError(t, err)
+	newToken, err := service.generateAccessToken(user, nil, nil)
+	require.NoError(t, err)
+
+	for _, token := range []string{oldToken, newToken} {
+		claims, err := service.ValidateToken(token)
+		require.NoError(t, err)
+		assert.Equal(t, "USER1", claims.UserID)
+	}
+}
+
+func TestSignToken_HS256RefusedOutsideMigrationWindow(t *testing.T) {
+	service := newServiceTokenAuthService(t)
+	user := models.NewUser("9876543210", "+91", "hashed")
+	user.ID = "USER1"
+
+	// Issued before signing keys are configured
+	legacyToken, err := service.generateAccessToken(user, nil, nil)
+	require.NoError(t, err)
+
+	keys, err := security.LoadSigningKeyRing(t.TempDir(), time.Hour)
+	require.NoError(t, err)
+	service.SetSigningKeys(keys)
+	_, err = service.ValidateToken(legacyToken)
+	assert.Error(t, err, "HS256 tokens are refused once signing keys are configured")
+
+	keys.AcceptHS256Until(time.Now().Add(time.Hour))
+	claims, err := service.ValidateToken(legacyToken)
+	require.NoError(t, err)
+	assert.Equal(t, "USER1", claims.UserID)
+
+	keys.AcceptHS256Until(time.Now().Add(-time.Minute))
+	_, err = service.ValidateToken(legacyToken)
+	assert.Error(t, err, "the migration window has closed")
+}
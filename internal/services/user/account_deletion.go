@@ -0,0 +1,285 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/Kisanlink/aaa-service/v2/pkg/phonenumber"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Self-service account deletion
+//
+// A user asks for their account to be deleted by confirming their password. The account is
+// soft-deleted and its issued tokens revoked at once, which signs them out for good, and a
+// deletion request is scheduled at the end of the grace period. Until then the user can change
+// their mind by cancelling with their phone number and password, which restores the account with
+// its roles intact. Once the grace period is over PurgeDueAccountDeletions hard deletes the
+// account, its role assignments and devices. Unlike DeleteUser, which admins use, nothing is
+// removed before the grace period ends.
+
+// Lifecycle operations audited for self-service account deletion, recorded as user_<operation>
+const (
+	accountDeletionRequested = "deletion_requested"
+	accountDeletionCancelled = "deletion_cancelled"
+	accountDeletionCompleted = "deletion_completed"
+)
+
+// accountDeletionActor is the actor audited for deletions carried out after the grace period
+const accountDeletionActor = "system"
+
+// SetAccountDeletion enables self-service account deletion, hard deleting accounts gracePeriod
+// after their user asks for it
+func (s *Service) SetAccountDeletion(repo interfaces.AccountDeletionRepository, gracePeriod time.Duration) {
+	s.deletionRepo = repo
+	s.deletionGrace = gracePeriod
+}
+
+// RequestAccountDeletion soft-deletes the user's account and schedules its hard deletion at the end
+// of the grace period. The password is asked again so a stolen session cannot delete the account.
+func (s *Service) RequestAccountDeletion(ctx context.Context, userID, password, reason string) (*models.AccountDeletionRequest, error) {
+	if s.deletionRepo == nil {
+		return nil, errors.NewInternalError(fmt.Errorf("account deletion is not configured"))
+	}
+	if userID == "" {
+		return nil, errors.NewValidationError("user ID cannot be empty")
+	}
+	if password == "" {
+		return nil, errors.NewValidationError("password is required to delete the account")
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID, &models.User{})
+	if err != nil || user == nil {
+		return nil, errors.NewNotFoundError("user not found")
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
+		s.logAccountDeletion(ctx, userID, userID, accountDeletionRequested, false, map[string]interface{}{
+			"failure_reason": "invalid password",
+		})
+		return nil, errors.NewUnauthorizedError("invalid password")
+	}
+
+	request := models.NewAccountDeletionRequest(userID, reason, time.Now().Add(s.deletionGrace))
+	if err := s.deletionRepo.Create(ctx, request); err != nil {
+		s.logger.Error("Failed to record account deletion request", zap.String("user_id", userID), zap.Error(err))
+		return nil, errors.NewInternalError(err)
+	}
+
+	if err := s.userRepo.SoftDelete(ctx, userID, userID); err != nil {
+		s.logger.Error("Failed to soft delete account on deletion request", zap.String("user_id", userID), zap.Error(err))
+		request.MarkCancelled(time.Now())
+		if updateErr := s.deletionRepo.Update(ctx, request); updateErr != nil {
+			s.logger.Warn("Failed to withdraw account deletion request", zap.String("request_id", request.GetID()), zap.Error(updateErr))
+		}
+		return nil, errors.NewInternalError(err)
+	}
+
+	s.clearUserCache(userID)
+	s.clearUserRoleCache(userID)
+
+	// The soft delete stops new sign-ins; revoking the issued tokens ends the current sessions
+	revoked := 0
+	if s.sessionRevoker != nil {
+		if revoked, err = s.sessionRevoker.RevokeAllSessions(ctx, userID); err != nil {
+			s.logger.Error("Failed to revoke sessions on account deletion request", zap.String("user_id", userID), zap.Error(err))
+		}
+	}
+
+	s.logAccountDeletion(ctx, userID, userID, accountDeletionRequested, true, map[string]interface{}{
+		"request_id":       request.GetID(),
+		"scheduled_for":    request.ScheduledFor,
+		"sessions_revoked": revoked,
+	})
+	s.logger.Info("Account deletion requested",
+		zap.String("user_id", userID),
+		zap.String("request_id", request.GetID()),
+		zap.Time("scheduled_for", request.ScheduledFor),
+		zap.Int("sessions_revoked", revoked))
+	return request, nil
+}
+
+// CancelAccountDeletion restores an account awaiting deletion. The user cannot sign in while the
+// account is deleted, so they identify themselves with their phone number and password.
+func (s *Service) CancelAccountDeletion(ctx context.Context, phoneNumber, countryCode, password string) (*models.AccountDeletionRequest, error) {
+	if s.deletionRepo == nil {
+		return nil, errors.NewInternalError(fmt.Errorf("account deletion is not configured"))
+	}
+	if phoneNumber == "" || password == "" {
+		return nil, errors.NewValidationError("phone number and password are required")
+	}
+	phone, err := phonenumber.Parse(phoneNumber, countryCode)
+	if err != nil {
+		return nil, errors.NewValidationError(err.Error())
+	}
+
+	history, err := s.userRepo.GetByPhoneNumberIncludingDeleted(ctx, phone.NationalNumber, phone.CountryCode)
+	if err != nil {
+		s.logger.Error("Failed to look up deleted accounts", zap.Error(err))
+		return nil, errors.NewInternalError(err)
+	}
+
+	// The deleted accounts that match are tried newest first; an unknown number and a wrong
+	// password get the same answer
+	var user *models.User
+	for _, candidate := range history {
+		if candidate.DeletedAt == nil {
+			continue
+		}
+		if bcrypt.CompareHashAndPassword([]byte(candidate.Password), []byte(password)) == nil {
+			user = candidate
+			break
+		}
+	}
+	if user == nil {
+		return nil, errors.NewUnauthorizedError("invalid credentials")
+	}
+
+	request, err := s.deletionRepo.GetPendingByUserID(ctx, user.ID)
+	if err != nil || request == nil {
+		return nil, errors.NewNotFoundError("no account deletion is pending for this account")
+	}
+	now := time.Now()
+	if request.IsDue(now) {
+		// The account is about to be purged; restoring it now would race the purge
+		return nil, errors.NewConflictError("the grace period of this account deletion is over")
+	}
+
+	if err := s.userRepo.Restore(ctx, user.ID); err != nil {
+		s.logger.Error("Failed to restore account on deletion cancel", zap.String("user_id", user.ID), zap.Error(err))
+		return nil, errors.NewInternalError(err)
+	}
+	request.MarkCancelled(now)
+	if err := s.deletionRepo.Update(ctx, request); err != nil {
+		// The account is restored; the purge sees it is active and closes the request
+		s.logger.Warn("Failed to mark account deletion cancelled", zap.String("request_id", request.GetID()), zap.Error(err))
+	}
+
+	s.clearUserCache(user.ID)
+	s.logAccountDeletion(ctx, user.ID, user.ID, accountDeletionCancelled, true, map[string]interface{}{
+		"request_id": request.GetID(),
+	})
+	s.logger.Info("Account deletion cancelled", zap.String("user_id", user.ID), zap.String("request_id", request.GetID()))
+	return request, nil
+}
+
+// PurgeDueAccountDeletions hard deletes up to limit accounts whose grace period is over and
+// returns how many were deleted. Accounts restored in the meantime, for instance by registering
+// the phone number again, are kept and their request closed.
+func (s *Service) PurgeDueAccountDeletions(ctx context.Context, limit int) (int, error) {
+	if s.deletionRepo == nil {
+		return 0, nil
+	}
+
+	due, err := s.deletionRepo.ListDue(ctx, time.Now(), limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list due account deletions: %w", err)
+	}
+
+	purged := 0
+	for _, request := range due {
+		if ctx.Err() != nil {
+			break
+		}
+		deleted, err := s.purgeAccount(ctx, request)
+		if err != nil {
+			s.logger.Error("Failed to purge account",
+				zap.String("user_id", request.UserID),
+				zap.String("request_id", request.GetID()),
+				zap.Error(err))
+			s.logAccountDeletion(ctx, accountDeletionActor, request.UserID, accountDeletionCompleted, false, map[string]interface{}{
+				"request_id":     request.GetID(),
+				"failure_reason": err.Error(),
+			})
+			continue
+		}
+		if deleted {
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+// purgeAccount carries out a due deletion request and reports whether the account was deleted
+func (s *Service) purgeAccount(ctx context.Context, request *models.AccountDeletionRequest) (bool, error) {
+	userID := request.UserID
+
+	active, err := s.userRepo.ExistingIDs(ctx, []string{userID}, false)
+	if err != nil {
+		return false, fmt.Errorf("failed to check account: %w", err)
+	}
+	if len(active) > 0 {
+		request.MarkCancelled(time.Now())
+		if err := s.deletionRepo.Update(ctx, request); err != nil {
+			return false, fmt.Errorf("failed to close deletion request of restored account: %w", err)
+		}
+		s.logger.Info("Account restored before its deletion, request closed",
+			zap.String("user_id", userID),
+			zap.String("request_id", request.GetID()))
+		return false, nil
+	}
+
+	userRoles, err := s.userRoleRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get role assignments: %w", err)
+	}
+	for _, userRole := range userRoles {
+		if err := s.userRoleRepo.DeleteByUserAndRole(ctx, userID, userRole.RoleID); err != nil {
+			return false, fmt.Errorf("failed to remove role %s: %w", userRole.RoleID, err)
+		}
+	}
+
+	devices := 0
+	if s.deviceRepo != nil {
+		userDevices, err := s.deviceRepo.ListByUserID(ctx, userID)
+		if err != nil {
+			return false, fmt.Errorf("failed to list devices: %w", err)
+		}
+		for _, device := range userDevices {
+			if err := s.deviceRepo.Delete(ctx, device.GetID()); err != nil {
+				return false, fmt.Errorf("failed to remove device %s: %w", device.DeviceID, err)
+			}
+		}
+		devices = len(userDevices)
+	}
+
+	exists, err := s.userRepo.ExistingIDs(ctx, []string{userID}, true)
+	if err != nil {
+		return false, fmt.Errorf("failed to check account: %w", err)
+	}
+	if len(exists) > 0 {
+		if err := s.userRepo.Delete(ctx, userID, &models.User{}); err != nil {
+			return false, fmt.Errorf("failed to delete account: %w", err)
+		}
+	}
+
+	request.MarkCompleted(time.Now())
+	if err := s.deletionRepo.Update(ctx, request); err != nil {
+		// The account is gone; the next pass finds nothing left to delete and completes the request
+		s.logger.Warn("Failed to mark account deletion completed", zap.String("request_id", request.GetID()), zap.Error(err))
+	}
+
+	s.clearUserCache(userID)
+	s.clearUserRoleCache(userID)
+	s.logAccountDeletion(ctx, accountDeletionActor, userID, accountDeletionCompleted, true, map[string]interface{}{
+		"request_id":       request.GetID(),
+		"roles_removed":    len(userRoles),
+		"devices_removed":  devices,
+		"requested_reason": request.Reason,
+	})
+	s.logger.Info("Account deleted after grace period",
+		zap.String("user_id", userID),
+		zap.String("request_id", request.GetID()))
+	return true, nil
+}
+
+func (s *Service) logAccountDeletion(ctx context.Context, actorID, userID, operation string, success bool, details map[string]interface{}) {
+	if s.auditService == nil {
+		return
+	}
+	s.auditService.LogUserLifecycleEvent(ctx, actorID, userID, operation, success, details)
+}
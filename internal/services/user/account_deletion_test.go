@@ -0,0 +1,254 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// deletionUserRepo adds lookups by ID, soft and hard deletes to the phone-unique user store
+type deletionUserRepo struct {
+	*phoneUniqueUserRepo
+}
+
+func (r *deletionUserRepo) GetByID(ctx context.Context, id string, user *models.User) (*models.User, error) {
+	for _, u := range r.users {
+		if u.ID == id && u.DeletedAt == nil {
+			return u, nil
+		}
+	}
+	return nil, fmt.Errorf("user not found")
+}
+
+func (r *deletionUserRepo) SoftDelete(ctx context.Context, id string, deletedBy string) error {
+	if _, err := r.GetByID(ctx, id, nil); err != nil {
+		return err
+	}
+	r.softDelete(id)
+	return nil
+}
+
+func (r *deletionUserRepo) ExistingIDs(ctx context.Context, ids []string, includeDeleted bool) ([]string, error) {
+	var existing []string
+	for _, u := range r.users {
+		for _, id := range ids {
+			if u.ID == id && (includeDeleted || u.DeletedAt == nil) {
+				existing = append(existing, id)
+			}
+		}
+	}
+	return existing, nil
+}
+
+func (r *deletionUserRepo) Delete(ctx context.Context, id string, user *models.User) error {
+	for i, u := range r.users {
+		if u.ID == id {
+			r.users = append(r.users[:i], r.users[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("user not found")
+}
+
+// memoryUserRoleRepo holds role assignments by user
+type memoryUserRoleRepo struct {
+	interfaces.UserRoleRepository
+	roles map[string][]*models.UserRole
+}
+
+func (r *memoryUserRoleRepo) GetByUserID(ctx context.Context, userID string) ([]*models.UserRole, error) {
+	return r.roles[userID], nil
+}
+
+func (r *memoryUserRoleRepo) DeleteByUserAndRole(ctx context.Context, userID, roleID string) error {
+	kept := r.roles[userID][:0]
+	for _, userRole := range r.roles[userID] {
+		if userRole.RoleID != roleID {
+			kept = append(kept, userRole)
+		}
+	}
+	r.roles[userID] = kept
+	return nil
+}
+
+// memoryDeletionRepo stores deletion requests in memory
+type memoryDeletionRepo struct {
+	requests []*models.AccountDeletionRequest
+}
+
+func (r *memoryDeletionRepo) Create(ctx context.Context, request *models.AccountDeletionRequest) error {
+	request.ID = fmt.Sprintf("ACC_DEL%d", len(r.requests)+1)
+	r.requests = append(r.requests, request)
+	return nil
+}
+
+func (r *memoryDeletionRepo) Update(ctx context.Context, request *models.AccountDeletionRequest) error {
+	return nil
+}
+
+func (r *memoryDeletionRepo) GetPendingByUserID(ctx context.Context, userID string) (*models.AccountDeletionRequest, error) {
+	for i := len(r.requests) - 1; i >= 0; i-- {
+		if request := r.requests[i]; request.UserID == userID && request.Status == models.AccountDeletionStatusPending {
+			return request, nil
+		}
+	}
+	return nil, fmt.Errorf("no pending deletion request for user: %s", userID)
+}
+
+func (r *memoryDeletionRepo) ListDue(ctx context.Context, before time.Time, limit int) ([]*models.AccountDeletionRequest, error) {
+	var due []*models.AccountDeletionRequest
+	for _, request := range r.requests {
+		if request.IsDue(before) && len(due) < limit {
+			due = append(due, request)
+		}
+	}
+	return due, nil
+}
+
+// lifecycleAuditRecorder records user lifecycle audit events
+type lifecycleAuditRecorder struct {
+	interfaces.AuditService
+	events []string
+}
+
+func (a *lifecycleAuditRecorder) LogUserLifecycleEvent(ctx context.Context, actorUserID, targetUserID, operation string, success bool, details map[string]interface{}) {
+	a.events = append(a.events, fmt.Sprintf("%s:%s by %s:%t", operation, targetUserID, actorUserID, success))
+}
+
+type accountDeletionFixture struct {
+	service  *Service
+	users    *deletionUserRepo
+	roles    *memoryUserRoleRepo
+	devices  *memoryDeviceRepo
+	requests *memoryDeletionRepo
+	audit    *lifecycleAuditRecorder
+	sessions *resetSessionRevoker
+}
+
+func newAccountDeletionFixture(t *testing.T, gracePeriod time.Duration) *accountDeletionFixture {
+	t.Helper()
+	password, err := bcrypt.GenerateFromPassword([]byte("Secret123!"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	users := &deletionUserRepo{phoneUniqueUserRepo: &phoneUniqueUserRepo{}}
+	require.NoError(t, users.Create(context.Background(), models.NewUser("9876543210", "+91", string(password))))
+
+	f := &accountDeletionFixture{
+		users:    users,
+		roles:    &memoryUserRoleRepo{roles: map[string][]*models.UserRole{"USER1": {{UserID: "USER1", RoleID: "ROLE1"}}}},
+		devices:  &memoryDeviceRepo{},
+		requests: &memoryDeletionRepo{},
+		audit:    &lifecycleAuditRecorder{},
+		sessions: &resetSessionRevoker{},
+	}
+	require.NoError(t, f.devices.Create(context.Background(), models.NewUserDevice("USER1", "pixel", "Pixel 8", "hash")))
+	f.service = &Service{
+		userRepo:     users,
+		userRoleRepo: f.roles,
+		deviceRepo:   f.devices,
+		cacheService: noopCache{},
+		auditService: f.audit,
+		logger:       zap.NewNop(),
+	}
+	f.service.SetAccountDeletion(f.requests, gracePeriod)
+	f.service.SetSessionRevoker(f.sessions)
+	return f
+}
+
+func TestAccountDeletion_CancelWithinGracePeriod(t *testing.T) {
+	f := newAccountDeletionFixture(t, time.Hour)
+	ctx := context.Background()
+
+	_, err := f.service.RequestAccountDeletion(ctx, "USER1", "wrong-password", "")
+	assert.True(t, errors.IsUnauthorizedError(err))
+
+	request, err := f.service.RequestAccountDeletion(ctx, "USER1", "Secret123!", "moving on")
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), request.ScheduledFor, time.Minute)
+	_, err = f.users.GetByID(ctx, "USER1", nil)
+	assert.Error(t, err, "the account is deactivated at once")
+	assert.Equal(t, []string{"USER1"}, f.sessions.revoked, "the issued tokens are revoked at once")
+
+	purged, err := f.service.PurgeDueAccountDeletions(ctx, 10)
+	require.NoError(t, err)
+	assert.Zero(t, purged, "nothing is deleted within the grace period")
+
+	_, err = f.service.CancelAccountDeletion(ctx, "9876543210", "+91", "wrong-password")
+	assert.True(t, errors.IsUnauthorizedError(err))
+
+	cancelled, err := f.service.CancelAccountDeletion(ctx, "98765 43210", "+91", "Secret123!")
+	require.NoError(t, err)
+	assert.Equal(t, request.ID, cancelled.ID)
+	assert.Equal(t, models.AccountDeletionStatusCancelled, request.Status)
+
+	user, err := f.users.GetByID(ctx, "USER1", nil)
+	require.NoError(t, err, "the account is restored")
+	assert.Nil(t, user.DeletedAt)
+	assert.Len(t, f.roles.roles["USER1"], 1, "roles are kept through the grace period")
+	assert.Len(t, f.devices.devices, 1)
+
+	_, err = f.service.CancelAccountDeletion(ctx, "9876543210", "+91", "Secret123!")
+	assert.True(t, errors.IsUnauthorizedError(err), "an active account has no deletion to cancel")
+
+	assert.Equal(t, []string{
+		"deletion_requested:USER1 by USER1:false",
+		"deletion_requested:USER1 by USER1:true",
+		"deletion_cancelled:USER1 by USER1:true",
+	}, f.audit.events)
+}
+
+func TestAccountDeletion_PurgedAfterGracePeriod(t *testing.T) {
+	f := newAccountDeletionFixture(t, time.Hour)
+	ctx := context.Background()
+
+	request, err := f.service.RequestAccountDeletion(ctx, "USER1", "Secret123!", "")
+	require.NoError(t, err)
+	request.ScheduledFor = time.Now().Add(-time.Minute)
+
+	_, err = f.service.CancelAccountDeletion(ctx, "9876543210", "+91", "Secret123!")
+	assert.True(t, errors.IsConflictError(err), "the account cannot be restored once the grace period is over")
+
+	purged, err := f.service.PurgeDueAccountDeletions(ctx, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, purged)
+
+	assert.Empty(t, f.users.users, "the account is hard deleted")
+	assert.Empty(t, f.roles.roles["USER1"])
+	assert.Empty(t, f.devices.devices)
+	assert.Equal(t, models.AccountDeletionStatusCompleted, request.Status)
+	assert.NotNil(t, request.CompletedAt)
+
+	purged, err = f.service.PurgeDueAccountDeletions(ctx, 10)
+	require.NoError(t, err)
+	assert.Zero(t, purged)
+
+	assert.Equal(t, []string{
+		"deletion_requested:USER1 by USER1:true",
+		"deletion_completed:USER1 by system:true",
+	}, f.audit.events)
+}
+
+func TestAccountDeletion_RestoredAccountIsKept(t *testing.T) {
+	f := newAccountDeletionFixture(t, 0)
+	ctx := context.Background()
+
+	request, err := f.service.RequestAccountDeletion(ctx, "USER1", "Secret123!", "")
+	require.NoError(t, err)
+	// Registering the phone number again restores the deleted account
+	require.NoError(t, f.users.Restore(ctx, "USER1"))
+
+	purged, err := f.service.PurgeDueAccountDeletions(ctx, 10)
+	require.NoError(t, err)
+	assert.Zero(t, purged)
+	assert.Len(t, f.users.users, 1)
+	assert.Equal(t, models.AccountDeletionStatusCancelled, request.Status)
+}
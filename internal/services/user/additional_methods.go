@@ -8,6 +8,7 @@ import (
 	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
 	"github.com/Kisanlink/aaa-service/v2/internal/entities/responses"
 	userResponses "github.com/Kisanlink/aaa-service/v2/internal/entities/responses/users"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
 	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
 	"go.uber.org/zap"
 	"golang.org/x/crypto/bcrypt"
@@ -593,6 +594,7 @@ func (s *Service) VerifyUserPassword(ctx context.Context, username, password str
 	if err != nil {
 		return nil, errors.NewUnauthorizedError("invalid credentials")
 	}
+	s.hasher().RehashPasswordIfNeeded(ctx, s.userRepo, user, password, s.logger)
 
 	response := &userResponses.UserResponse{
 		ID:          user.ID,
@@ -624,6 +626,7 @@ func (s *Service) VerifyUserPasswordByPhone(ctx context.Context, phoneNumber, co
 	if err != nil {
 		return nil, errors.NewUnauthorizedError("invalid credentials")
 	}
+	s.hasher().RehashPasswordIfNeeded(ctx, s.userRepo, user, password, s.logger)
 
 	response := &userResponses.UserResponse{
 		ID:          user.ID,
@@ -686,13 +689,13 @@ func (s *Service) SetMPin(ctx context.Context, userID string, mPin string, curre
 	}
 
 	// Hash the MPIN with appropriate cost
-	hashedMPin, err := bcrypt.GenerateFromPassword([]byte(mPin), bcrypt.DefaultCost)
+	hashedMPin, err := s.hasher().HashMPin(mPin)
 	if err != nil {
 		s.logger.Error("Failed to hash MPIN", zap.String("user_id", userID), zap.Error(err))
 		return errors.NewInternalError(err)
 	}
 
-	user.SetMPin(string(hashedMPin))
+	user.SetMPin(hashedMPin)
 	err = s.userRepo.Update(ctx, user)
 	if err != nil {
 		s.logger.Error("Failed to set MPIN", zap.String("user_id", userID), zap.Error(err))
@@ -700,6 +703,7 @@ func (s *Service) SetMPin(ctx context.Context, userID string, mPin string, curre
 	}
 
 	s.clearUserCache(userID)
+	s.notifySecurityEvent(ctx, interfaces.SecurityEventMPinChanged, userID, map[string]interface{}{"change": "set"})
 	s.logger.Info("MPIN set successfully", zap.String("user_id", userID))
 	return nil
 }
@@ -748,13 +752,13 @@ func (s *Service) UpdateMPin(ctx context.Context, userID, currentMPin, newMPin s
 	}
 
 	// Hash the new MPIN
-	hashedMPin, err := bcrypt.GenerateFromPassword([]byte(newMPin), bcrypt.DefaultCost)
+	hashedMPin, err := s.hasher().HashMPin(newMPin)
 	if err != nil {
 		s.logger.Error("Failed to hash new MPIN", zap.String("user_id", userID), zap.Error(err))
 		return errors.NewInternalError(err)
 	}
 
-	user.SetMPin(string(hashedMPin))
+	user.SetMPin(hashedMPin)
 	err = s.userRepo.Update(ctx, user)
 	if err != nil {
 		s.logger.Error("Failed to update MPIN", zap.String("user_id", userID), zap.Error(err))
@@ -762,6 +766,7 @@ func (s *Service) UpdateMPin(ctx context.Context, userID, currentMPin, newMPin s
 	}
 
 	s.clearUserCache(userID)
+	s.notifySecurityEvent(ctx, interfaces.SecurityEventMPinChanged, userID, map[string]interface{}{"change": "update"})
 	s.logger.Info("MPIN updated successfully", zap.String("user_id", userID))
 	return nil
 }
@@ -793,6 +798,7 @@ func (s *Service) VerifyMPin(ctx context.Context, userID string, mPin string) er
 	if err != nil {
 		return errors.NewUnauthorizedError("invalid mPin")
 	}
+	s.hasher().RehashMPinIfNeeded(ctx, s.userRepo, user, mPin, s.logger)
 
 	return nil
 }
@@ -850,6 +856,7 @@ func (s *Service) VerifyUserCredentials(ctx context.Context, phone, countryCode
 			s.logger.Error("Password verification failed", zap.String("user_id", user.ID))
 			return nil, errors.NewUnauthorizedError("invalid credentials")
 		}
+		s.hasher().RehashPasswordIfNeeded(ctx, s.userRepo, user, *password, s.logger)
 	} else if mpin != nil && *mpin != "" {
 		if !user.HasMPin() {
 			s.logger.Error("MPIN not set for user", zap.String("user_id", user.ID))
@@ -861,6 +868,7 @@ func (s *Service) VerifyUserCredentials(ctx context.Context, phone, countryCode
 			s.logger.Error("MPIN verification failed", zap.String("user_id", user.ID))
 			return nil, errors.NewUnauthorizedError("invalid mpin")
 		}
+		s.hasher().RehashMPinIfNeeded(ctx, s.userRepo, user, *mpin, s.logger)
 	} else {
 		return nil, errors.NewValidationError("no valid credentials provided")
 	}
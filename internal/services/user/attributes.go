@@ -0,0 +1,34 @@
+package user
+
+import (
+	"context"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"go.uber.org/zap"
+)
+
+// SetUserAttributeRepository enables the custom attributes organizations keep on their users in
+// user details requested in an organization
+func (s *Service) SetUserAttributeRepository(attributeRepo interfaces.OrganizationUserAttributeRepository) {
+	s.attributeRepo = attributeRepo
+}
+
+// userAttributes returns the custom attributes an organization keeps on a user. A lookup failure
+// leaves them empty, as it does for the other sections of a user detail.
+func (s *Service) userAttributes(ctx context.Context, orgID, userID string) map[string]interface{} {
+	attributes := map[string]interface{}{}
+	stored, err := s.attributeRepo.GetAttributes(ctx, orgID, userID)
+	if err != nil {
+		s.logger.Warn("Failed to get user attributes for detail",
+			zap.String("user_id", userID),
+			zap.String("org_id", orgID),
+			zap.Error(err))
+		return attributes
+	}
+	if stored != nil {
+		for key, value := range stored.Attributes {
+			attributes[key] = value
+		}
+	}
+	return attributes
+}
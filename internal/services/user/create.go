@@ -9,9 +9,11 @@ import (
 	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
 	"github.com/Kisanlink/aaa-service/v2/internal/entities/requests/users"
 	userResponses "github.com/Kisanlink/aaa-service/v2/internal/entities/responses/users"
+	"github.com/Kisanlink/aaa-service/v2/internal/utils/actor"
 	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/Kisanlink/aaa-service/v2/pkg/phonenumber"
+	"github.com/Kisanlink/aaa-service/v2/pkg/warnings"
 	"go.uber.org/zap"
-	"golang.org/x/crypto/bcrypt"
 )
 
 // CreateUser creates a new user with proper validation and business logic
@@ -24,28 +26,43 @@ func (s *Service) CreateUser(ctx context.Context, req *users.CreateUserRequest)
 		return nil, errors.NewValidationError("invalid user data", err.Error())
 	}
 
+	// Canonicalize the phone number so equivalent formats map to the same account
+	phone, err := phonenumber.Parse(req.PhoneNumber, req.CountryCode)
+	if err != nil {
+		s.logger.Warn("User creation rejected: invalid phone number", zap.Error(err))
+		return nil, errors.NewValidationError("invalid phone number", err.Error())
+	}
+
 	// Check if user already exists by phone number
-	existingUser, err := s.userRepo.GetByPhoneNumber(ctx, req.PhoneNumber, req.CountryCode)
+	existingUser, err := s.userRepo.GetByPhoneNumber(ctx, phone.NationalNumber, phone.CountryCode)
 	if err == nil && existingUser != nil {
 		s.logger.Warn("User already exists with phone number",
-			zap.String("phone", req.PhoneNumber),
-			zap.String("country", req.CountryCode))
-		return nil, errors.NewConflictError("user with this phone number already exists")
+			zap.String("phone", phone.NationalNumber),
+			zap.String("country", phone.CountryCode))
+		return nil, phoneNumberConflictError(phone)
 	}
 
 	// Check if username is already taken (if username is provided)
-	if req.Username != nil && *req.Username != "" {
-		existingUserByUsername, err := s.userRepo.GetByUsername(ctx, *req.Username)
-		if err == nil && existingUserByUsername != nil {
-			s.logger.Warn("Username already taken",
-				zap.String("username", *req.Username))
-			return nil, errors.NewConflictError("username is already taken")
-		}
-		// If there was an error but it's not "user not found", log it
-		if err != nil && !strings.Contains(err.Error(), "user not found") {
+	username := ""
+	if req.Username != nil {
+		username = *req.Username
+	}
+	if username != "" {
+		taken, err := s.usernameTaken(ctx, username)
+		if err != nil {
 			s.logger.Error("Error checking username uniqueness", zap.Error(err))
 			return nil, errors.NewInternalError(err)
 		}
+		if taken {
+			s.logger.Warn("Username already taken",
+				zap.String("username", username))
+			return nil, errors.NewConflictError("username is already taken")
+		}
+
+		// Near-duplicate usernames are allowed but flagged to the caller
+		s.warnSimilarUsernames(ctx, username)
+	} else if s.usernamePolicy.Required && !s.usernameGenerated() {
+		return nil, errors.NewValidationError("username is required")
 	}
 
 	// Hash password
@@ -55,23 +72,80 @@ func (s *Service) CreateUser(ctx context.Context, req *users.CreateUserRequest)
 		return nil, errors.NewInternalError(err)
 	}
 
+	// Reuse the caller's deleted account instead of creating a new one when asked to
+	if req.ReclaimMode == users.ReclaimModeRestore {
+		restored, err := s.reclaimDeletedUser(ctx, phone, req, hashedPassword)
+		if err != nil {
+			return nil, err
+		}
+		if restored != nil {
+			return newCreatedUserResponse(restored, userResponses.CreationPathRestored), nil
+		}
+	}
+
+	// Users created without a username get one when the policy generates them
+	generated := false
+	if username == "" {
+		username, err = s.generateUsername(ctx, req.Name, phone)
+		if err != nil {
+			return nil, err
+		}
+		generated = username != ""
+	}
+
 	// Create user model using the appropriate constructor
 	var user *models.User
-	if req.Username != nil && *req.Username != "" {
-		user = models.NewUserWithUsername(req.PhoneNumber, req.CountryCode, *req.Username, hashedPassword)
+	if username != "" {
+		user = models.NewUserWithUsername(phone.NationalNumber, phone.CountryCode, username, hashedPassword)
 	} else {
-		user = models.NewUser(req.PhoneNumber, req.CountryCode, hashedPassword)
+		user = models.NewUser(phone.NationalNumber, phone.CountryCode, hashedPassword)
 	}
+	user.SetPhone(phone)
 
 	// Set must_change_password flag if requested
 	if req.MustChangePassword {
 		user.MustChangePassword = true
 	}
+	actor.StampCreate(ctx, user)
+
+	// Default roles are assigned in the same transaction, so the user never exists without them
+	userRoles, defaultRoles, err := s.newUserRoles(ctx, user.ID, req.OrganizationID)
+	if err != nil {
+		s.logger.Error("Failed to resolve default roles", zap.Error(err))
+		return nil, errors.NewInternalError(err)
+	}
 
 	// Save user to repository
-	err = s.userRepo.Create(ctx, user)
+	for _, userRole := range userRoles {
+		actor.StampCreate(ctx, userRole)
+	}
+	for attempt := 1; ; attempt++ {
+		if len(userRoles) > 0 {
+			err = s.userRepo.CreateWithRoles(ctx, user, userRoles)
+		} else {
+			err = s.userRepo.Create(ctx, user)
+		}
+		if err == nil || !generated || !isUsernameConflict(err) || attempt >= generatedUsernameAttempts {
+			break
+		}
+
+		// A concurrent create took the generated username after it was checked; generate another
+		s.logger.Info("Generated username taken concurrently, generating another", zap.String("username", username))
+		username, err = s.generateUsername(ctx, req.Name, phone)
+		if err != nil {
+			return nil, err
+		}
+		user.Username = &username
+	}
 	if err != nil {
 		s.logger.Error("Failed to create user in repository", zap.Error(err))
+		// A concurrent registration can pass the lookup above and still lose the race on the unique index
+		if strings.Contains(err.Error(), models.UsersPhoneActiveUniqueIndex) {
+			return nil, phoneNumberConflictError(phone)
+		}
+		if isUsernameConflict(err) {
+			return nil, errors.NewConflictError("username is already taken")
+		}
 		// Check if it's a database constraint violation
 		if strings.Contains(err.Error(), "duplicate key") ||
 			strings.Contains(err.Error(), "unique constraint") ||
@@ -81,17 +155,86 @@ func (s *Service) CreateUser(ctx context.Context, req *users.CreateUserRequest)
 		return nil, errors.NewInternalError(err)
 	}
 
-	// Log user creation with safe username extraction
-	username := ""
-	if user.Username != nil {
-		username = *user.Username
-	}
 	s.logger.Info("User created successfully",
 		zap.String("user_id", user.ID),
-		zap.String("username", username))
+		zap.String("username", username),
+		zap.Int("default_roles", len(defaultRoles)))
+	s.auditDefaultRoles(ctx, user.ID, req.OrganizationID, defaultRoles)
+
+	return newCreatedUserResponse(user, userResponses.CreationPathCreated), nil
+}
+
+// reclaimDeletedUser restores the most recently soft-deleted account holding the phone number and
+// resets its credentials from the request. The account keeps its ID, so its audit history stays
+// linked, but its MPIN and prior roles are cleared and it gets the default roles of a new user. The
+// restore is one transaction. It returns nil when no deleted account holds the number.
+func (s *Service) reclaimDeletedUser(ctx context.Context, phone phonenumber.Number, req *users.CreateUserRequest, hashedPassword string) (*models.User, error) {
+	history, err := s.userRepo.GetByPhoneNumberIncludingDeleted(ctx, phone.NationalNumber, phone.CountryCode)
+	if err != nil {
+		s.logger.Error("Failed to look up deleted accounts", zap.Error(err))
+		return nil, errors.NewInternalError(err)
+	}
+
+	// History is ordered newest first
+	var user *models.User
+	for _, candidate := range history {
+		if candidate.DeletedAt != nil {
+			user = candidate
+			break
+		}
+	}
+	if user == nil {
+		return nil, nil
+	}
+
+	userRoles, defaultRoles, err := s.newUserRoles(ctx, user.ID, req.OrganizationID)
+	if err != nil {
+		s.logger.Error("Failed to resolve default roles", zap.Error(err))
+		return nil, errors.NewInternalError(err)
+	}
+	for _, userRole := range userRoles {
+		actor.StampCreate(ctx, userRole)
+	}
+
+	user.DeletedAt = nil
+	user.DeletedBy = nil
+	user.Password = hashedPassword
+	user.MPin = nil
+	user.MustChangePassword = req.MustChangePassword
+	if req.Username != nil && *req.Username != "" {
+		user.Username = req.Username
+	}
+	user.SetPhone(phone)
+	actor.StampUpdate(ctx, user)
+
+	if err := s.userRepo.RestoreWithRoles(ctx, user, userRoles); err != nil {
+		s.logger.Error("Failed to restore deleted user", zap.String("user_id", user.ID), zap.Error(err))
+		// The number may have been registered again since the lookup; the partial unique index rejects the restore
+		if strings.Contains(err.Error(), models.UsersPhoneActiveUniqueIndex) {
+			return nil, phoneNumberConflictError(phone)
+		}
+		if isUsernameConflict(err) {
+			return nil, errors.NewConflictError("username is already taken")
+		}
+		if strings.Contains(err.Error(), "duplicate key") || strings.Contains(err.Error(), "unique constraint") {
+			return nil, errors.NewConflictError("user with this information already exists")
+		}
+		return nil, errors.NewInternalError(err)
+	}
+
+	// Lookups and roles cached while the account was deleted are stale now
+	s.clearUserCache(user.ID)
+
+	s.logger.Info("Deleted user restored on create",
+		zap.String("user_id", user.ID),
+		zap.Int("default_roles", len(defaultRoles)))
+	s.auditDefaultRoles(ctx, user.ID, req.OrganizationID, defaultRoles)
+	return user, nil
+}
 
-	// Convert to response format
-	response := &userResponses.UserResponse{
+// newCreatedUserResponse converts a created or restored user to the create response
+func newCreatedUserResponse(user *models.User, creationPath string) *userResponses.UserResponse {
+	return &userResponses.UserResponse{
 		ID:                 user.ID,
 		Username:           user.Username,
 		PhoneNumber:        user.PhoneNumber,
@@ -100,18 +243,56 @@ func (s *Service) CreateUser(ctx context.Context, req *users.CreateUserRequest)
 		MustChangePassword: user.MustChangePassword,
 		CreatedAt:          user.CreatedAt,
 		UpdatedAt:          user.UpdatedAt,
+		CreationPath:       creationPath,
 	}
+}
+
+// similarUsernameCandidates bounds the users fetched per search term when looking for
+// near-duplicate usernames
+const similarUsernameCandidates = 20
+
+// warnSimilarUsernames records a warning for each existing username nearly identical to
+// username. Lookup failures are logged and never fail the create.
+func (s *Service) warnSimilarUsernames(ctx context.Context, username string) {
+	if !warnings.Enabled(ctx) {
+		return
+	}
+
+	var candidates []string
+	for _, term := range warnings.SearchTerms(username) {
+		users, err := s.userRepo.Search(ctx, term, similarUsernameCandidates, 0)
+		if err != nil {
+			s.logger.Warn("Failed to look up similar usernames", zap.Error(err))
+			continue
+		}
+		for _, user := range users {
+			if user.Username != nil {
+				candidates = append(candidates, *user.Username)
+			}
+		}
+	}
+
+	for _, similar := range warnings.SimilarNames(username, candidates) {
+		warnings.Add(ctx, warnings.Warning{
+			Code:    warnings.CodeSimilarName,
+			Field:   "username",
+			Message: fmt.Sprintf("a user named %q already exists", similar),
+		})
+	}
+}
 
-	return response, nil
+// phoneNumberConflictError reports that a live account already holds the phone number
+func phoneNumberConflictError(phone phonenumber.Number) error {
+	return errors.NewConflictError(fmt.Sprintf("user with phone number %s already exists", phone.E164()))
 }
 
-// hashPassword creates a bcrypt hash of the password
+// hashPassword creates a bcrypt hash of the password at the configured cost
 func (s *Service) hashPassword(password string) (string, error) {
-	bytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	hash, err := s.hasher().HashPassword(password)
 	if err != nil {
 		return "", fmt.Errorf("failed to hash password: %w", err)
 	}
-	return string(bytes), nil
+	return hash, nil
 }
 
 // generateValidationToken creates a validation token for new users
@@ -0,0 +1,363 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/requests/users"
+	userResponses "github.com/Kisanlink/aaa-service/v2/internal/entities/responses/users"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/Kisanlink/aaa-service/v2/pkg/warnings"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// phoneUniqueUserRepo is an in-memory user store that mirrors the partial unique index:
+// a phone number may be reused once the previous holder is soft-deleted.
+type phoneUniqueUserRepo struct {
+	interfaces.UserRepository
+	users         []*models.User
+	restoredRoles []*models.UserRole
+}
+
+func (r *phoneUniqueUserRepo) GetByPhoneNumber(ctx context.Context, phoneNumber, countryCode string) (*models.User, error) {
+	for _, u := range r.users {
+		if u.PhoneNumber == phoneNumber && u.CountryCode == countryCode && u.DeletedAt == nil {
+			return u, nil
+		}
+	}
+	return nil, fmt.Errorf("user not found with phone number: %s%s", countryCode, phoneNumber)
+}
+
+func (r *phoneUniqueUserRepo) GetByPhoneNumberIncludingDeleted(ctx context.Context, phoneNumber, countryCode string) ([]*models.User, error) {
+	var matches []*models.User
+	for i := len(r.users) - 1; i >= 0; i-- {
+		u := r.users[i]
+		if u.PhoneNumber == phoneNumber && u.CountryCode == countryCode {
+			matches = append(matches, u)
+		}
+	}
+	return matches, nil
+}
+
+func (r *phoneUniqueUserRepo) Create(ctx context.Context, user *models.User) error {
+	if _, err := r.GetByPhoneNumber(ctx, user.PhoneNumber, user.CountryCode); err == nil {
+		return fmt.Errorf(`ERROR: duplicate key value violates unique constraint "%s" (SQLSTATE 23505)`, models.UsersPhoneActiveUniqueIndex)
+	}
+	user.ID = fmt.Sprintf("USER%d", len(r.users)+1)
+	r.users = append(r.users, user)
+	return nil
+}
+
+func (r *phoneUniqueUserRepo) GetByUsername(ctx context.Context, username string) (*models.User, error) {
+	for _, u := range r.users {
+		if u.Username != nil && *u.Username == username && u.DeletedAt == nil {
+			return u, nil
+		}
+	}
+	return nil, fmt.Errorf("user not found")
+}
+
+func (r *phoneUniqueUserRepo) Search(ctx context.Context, keyword string, limit, offset int) ([]*models.User, error) {
+	var matches []*models.User
+	for _, u := range r.users {
+		if u.Username != nil && strings.Contains(*u.Username, keyword) && u.DeletedAt == nil {
+			matches = append(matches, u)
+		}
+	}
+	return matches, nil
+}
+
+func (r *phoneUniqueUserRepo) Restore(ctx context.Context, id string) error {
+	for _, u := range r.users {
+		if u.ID == id && u.DeletedAt != nil {
+			if _, err := r.GetByPhoneNumber(ctx, u.PhoneNumber, u.CountryCode); err == nil {
+				return fmt.Errorf(`ERROR: duplicate key value violates unique constraint "%s" (SQLSTATE 23505)`, models.UsersPhoneActiveUniqueIndex)
+			}
+			u.DeletedAt = nil
+			return nil
+		}
+	}
+	return fmt.Errorf("soft-deleted record with id %v not found in table users", id)
+}
+
+func (r *phoneUniqueUserRepo) RestoreWithRoles(ctx context.Context, user *models.User, userRoles []*models.UserRole) error {
+	index := -1
+	for i, u := range r.users {
+		if u.ID == user.ID {
+			index = i
+		} else if u.PhoneNumber == user.PhoneNumber && u.CountryCode == user.CountryCode && u.DeletedAt == nil {
+			return fmt.Errorf(`ERROR: duplicate key value violates unique constraint "%s" (SQLSTATE 23505)`, models.UsersPhoneActiveUniqueIndex)
+		}
+	}
+	if index < 0 {
+		return fmt.Errorf("soft-deleted record with id %v not found in table users", user.ID)
+	}
+	r.users[index] = user
+	r.restoredRoles = userRoles
+	return nil
+}
+
+func (r *phoneUniqueUserRepo) softDelete(userID string) {
+	for _, u := range r.users {
+		if u.ID == userID {
+			now := time.Now()
+			u.DeletedAt = &now
+		}
+	}
+}
+
+// noopValidator accepts every request
+type noopValidator struct {
+	interfaces.Validator
+}
+
+func (noopValidator) ValidateStruct(s interface{}) error { return nil }
+
+// noopCache forgets everything it is asked to store
+type noopCache struct {
+	interfaces.CacheService
+}
+
+func (noopCache) Delete(key string) error { return nil }
+
+func newPhoneUniqueTestService(repo *phoneUniqueUserRepo) *Service {
+	return &Service{
+		userRepo:     repo,
+		cacheService: noopCache{},
+		logger:       zap.NewNop(),
+		validator:    noopValidator{},
+	}
+}
+
+func TestCreateUser_ReRegisterAfterSoftDelete(t *testing.T) {
+	repo := &phoneUniqueUserRepo{}
+	service := newPhoneUniqueTestService(repo)
+	ctx := context.Background()
+	req := &users.CreateUserRequest{PhoneNumber: "9876543210", CountryCode: "+91", Password: "Secret123!"}
+
+	first, err := service.CreateUser(ctx, req)
+	require.NoError(t, err)
+
+	repo.softDelete(first.ID)
+
+	second, err := service.CreateUser(ctx, req)
+	require.NoError(t, err, "a soft-deleted account must not block re-registration")
+	assert.NotEqual(t, first.ID, second.ID)
+
+	history, err := repo.GetByPhoneNumberIncludingDeleted(ctx, "9876543210", "+91")
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, second.ID, history[0].ID, "newest account first")
+	assert.Nil(t, history[0].DeletedAt)
+	assert.NotNil(t, history[1].DeletedAt, "prior account is still findable for restore")
+}
+
+func TestCreateUser_ReclaimRestoresDeletedAccount(t *testing.T) {
+	repo := &phoneUniqueUserRepo{}
+	service := newPhoneUniqueTestService(repo)
+	ctx := context.Background()
+	req := &users.CreateUserRequest{PhoneNumber: "9876543210", CountryCode: "+91", Password: "Secret123!"}
+
+	first, err := service.CreateUser(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, userResponses.CreationPathCreated, first.CreationPath)
+	oldHash := repo.users[0].Password
+
+	repo.softDelete(first.ID)
+
+	username := "farmer_returns"
+	restored, err := service.CreateUser(ctx, &users.CreateUserRequest{
+		PhoneNumber:        "98765 43210",
+		CountryCode:        "+91",
+		Password:           "NewSecret456!",
+		Username:           &username,
+		MustChangePassword: true,
+		ReclaimMode:        users.ReclaimModeRestore,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, userResponses.CreationPathRestored, restored.CreationPath)
+	assert.Equal(t, first.ID, restored.ID, "the restored account keeps its ID so its audit history stays linked")
+	assert.Equal(t, &username, restored.Username)
+	assert.True(t, restored.MustChangePassword)
+
+	require.Len(t, repo.users, 1, "no new account is created")
+	assert.Nil(t, repo.users[0].DeletedAt)
+	assert.NotEqual(t, oldHash, repo.users[0].Password, "the request's password replaces the old one")
+
+	// The number is live again, so a second reclaim is a conflict
+	_, err = service.CreateUser(ctx, &users.CreateUserRequest{PhoneNumber: "9876543210", CountryCode: "+91", Password: "Secret123!", ReclaimMode: users.ReclaimModeRestore})
+	assert.True(t, errors.IsConflictError(err))
+}
+
+func TestCreateUser_ReclaimDecisions(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("fresh mode creates a new account", func(t *testing.T) {
+		repo := &phoneUniqueUserRepo{}
+		service := newPhoneUniqueTestService(repo)
+		req := &users.CreateUserRequest{PhoneNumber: "9876543210", CountryCode: "+91", Password: "Secret123!"}
+
+		first, err := service.CreateUser(ctx, req)
+		require.NoError(t, err)
+		repo.softDelete(first.ID)
+
+		req.ReclaimMode = users.ReclaimModeFresh
+		second, err := service.CreateUser(ctx, req)
+		require.NoError(t, err)
+		assert.Equal(t, userResponses.CreationPathCreated, second.CreationPath)
+		assert.NotEqual(t, first.ID, second.ID)
+		assert.NotNil(t, repo.users[0].DeletedAt, "the deleted account is left alone")
+	})
+
+	t.Run("restore mode without a deleted account creates a new one", func(t *testing.T) {
+		repo := &phoneUniqueUserRepo{}
+		service := newPhoneUniqueTestService(repo)
+
+		created, err := service.CreateUser(ctx, &users.CreateUserRequest{PhoneNumber: "9876543210", CountryCode: "+91", Password: "Secret123!", ReclaimMode: users.ReclaimModeRestore})
+		require.NoError(t, err)
+		assert.Equal(t, userResponses.CreationPathCreated, created.CreationPath)
+	})
+
+	t.Run("restore mode picks the most recently deleted account", func(t *testing.T) {
+		repo := &phoneUniqueUserRepo{}
+		service := newPhoneUniqueTestService(repo)
+		req := &users.CreateUserRequest{PhoneNumber: "9876543210", CountryCode: "+91", Password: "Secret123!"}
+
+		first, err := service.CreateUser(ctx, req)
+		require.NoError(t, err)
+		repo.softDelete(first.ID)
+		second, err := service.CreateUser(ctx, req)
+		require.NoError(t, err)
+		repo.softDelete(second.ID)
+
+		req.ReclaimMode = users.ReclaimModeRestore
+		restored, err := service.CreateUser(ctx, req)
+		require.NoError(t, err)
+		assert.Equal(t, second.ID, restored.ID)
+		assert.NotNil(t, repo.users[0].DeletedAt)
+	})
+}
+
+func TestCreateUserRequest_ValidateReclaimMode(t *testing.T) {
+	req := &users.CreateUserRequest{PhoneNumber: "9876543210", CountryCode: "+91", Password: "Secret123!", ReclaimMode: "merge"}
+	assert.Error(t, req.Validate())
+
+	req.ReclaimMode = users.ReclaimModeRestore
+	assert.NoError(t, req.Validate())
+}
+
+func TestCreateUser_DuplicateActivePhoneNumber(t *testing.T) {
+	repo := &phoneUniqueUserRepo{}
+	service := newPhoneUniqueTestService(repo)
+	ctx := context.Background()
+	req := &users.CreateUserRequest{PhoneNumber: "9876543210", CountryCode: "+91", Password: "Secret123!"}
+
+	_, err := service.CreateUser(ctx, req)
+	require.NoError(t, err)
+
+	_, err = service.CreateUser(ctx, req)
+	require.Error(t, err)
+	assert.True(t, errors.IsConflictError(err))
+	assert.Contains(t, err.Error(), "+919876543210")
+}
+
+// raceUserRepo simulates a concurrent registration that wins between the lookup and the insert
+type raceUserRepo struct {
+	phoneUniqueUserRepo
+}
+
+func (r *raceUserRepo) GetByPhoneNumber(ctx context.Context, phoneNumber, countryCode string) (*models.User, error) {
+	return nil, fmt.Errorf("user not found with phone number: %s%s", countryCode, phoneNumber)
+}
+
+func (r *raceUserRepo) Create(ctx context.Context, user *models.User) error {
+	return fmt.Errorf(`ERROR: duplicate key value violates unique constraint "%s" (SQLSTATE 23505)`, models.UsersPhoneActiveUniqueIndex)
+}
+
+func TestCreateUser_UniqueIndexViolationCitesPhoneNumber(t *testing.T) {
+	service := &Service{
+		userRepo:  &raceUserRepo{},
+		logger:    zap.NewNop(),
+		validator: noopValidator{},
+	}
+
+	_, err := service.CreateUser(context.Background(), &users.CreateUserRequest{PhoneNumber: "9876543210", CountryCode: "+91", Password: "Secret123!"})
+	require.Error(t, err)
+	assert.True(t, errors.IsConflictError(err))
+	assert.Contains(t, err.Error(), "+919876543210")
+}
+
+func TestCreateUser_NormalizesPhoneNumber(t *testing.T) {
+	repo := &phoneUniqueUserRepo{}
+	service := newPhoneUniqueTestService(repo)
+	ctx := context.Background()
+
+	created, err := service.CreateUser(ctx, &users.CreateUserRequest{PhoneNumber: "098765-43210", CountryCode: "+91", Password: "Secret123!"})
+	require.NoError(t, err)
+	assert.Equal(t, "9876543210", created.PhoneNumber)
+
+	stored := repo.users[0]
+	require.NotNil(t, stored.PhoneE164)
+	assert.Equal(t, "+919876543210", *stored.PhoneE164)
+
+	for _, format := range []string{"98765 43210", "9876543210", "+91 98765-43210"} {
+		_, err = service.CreateUser(ctx, &users.CreateUserRequest{PhoneNumber: format, CountryCode: "+91", Password: "Secret123!"})
+		require.Error(t, err, format)
+		assert.True(t, errors.IsConflictError(err), "%q is the same number as the existing account", format)
+	}
+}
+
+func TestCreateUser_InvalidPhoneNumber(t *testing.T) {
+	repo := &phoneUniqueUserRepo{}
+	service := newPhoneUniqueTestService(repo)
+
+	_, err := service.CreateUser(context.Background(), &users.CreateUserRequest{PhoneNumber: "98765", CountryCode: "+91", Password: "Secret123!"})
+	require.Error(t, err)
+	assert.True(t, errors.IsValidationError(err))
+	assert.Empty(t, repo.users)
+}
+
+func TestCreateUser_WarnsOnSimilarUsername(t *testing.T) {
+	repo := &phoneUniqueUserRepo{}
+	service := newPhoneUniqueTestService(repo)
+
+	existing := "ravi_kumar"
+	_, err := service.CreateUser(context.Background(), &users.CreateUserRequest{PhoneNumber: "9876543210", CountryCode: "+91", Password: "Secret123!", Username: &existing})
+	require.NoError(t, err)
+
+	ctx, collector := warnings.NewContext(context.Background())
+	similar := "ravi.kumar"
+	created, err := service.CreateUser(ctx, &users.CreateUserRequest{PhoneNumber: "9876543211", CountryCode: "+91", Password: "Secret123!", Username: &similar})
+	require.NoError(t, err, "a near-duplicate username is still created")
+	assert.Equal(t, &similar, created.Username)
+
+	require.Len(t, collector.Warnings(), 1)
+	assert.Equal(t, warnings.CodeSimilarName, collector.Warnings()[0].Code)
+	assert.Equal(t, "username", collector.Warnings()[0].Field)
+	assert.Contains(t, collector.Warnings()[0].Message, "ravi_kumar")
+}
+
+func TestCreateUser_RecordsCreator(t *testing.T) {
+	repo := &phoneUniqueUserRepo{}
+	service := newPhoneUniqueTestService(repo)
+
+	ctx := context.WithValue(context.Background(), "user_id", "ADMIN1")
+	_, err := service.CreateUser(ctx, &users.CreateUserRequest{PhoneNumber: "9876543210", CountryCode: "+91", Password: "Secret123!"})
+	require.NoError(t, err)
+
+	stored := repo.users[0]
+	assert.Equal(t, "ADMIN1", stored.CreatedBy)
+	assert.Equal(t, "ADMIN1", stored.UpdatedBy)
+
+	// Self-registration has no authenticated actor
+	_, err = service.CreateUser(context.Background(), &users.CreateUserRequest{PhoneNumber: "9876500000", CountryCode: "+91", Password: "Secret123!"})
+	require.NoError(t, err)
+	assert.Equal(t, "system", repo.users[1].CreatedBy)
+}
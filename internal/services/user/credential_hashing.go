@@ -0,0 +1,18 @@
+package user
+
+import (
+	"github.com/Kisanlink/aaa-service/v2/internal/security"
+)
+
+// SetCredentialHasher sets the hasher, and with it the bcrypt costs, used for passwords and MPINs
+func (s *Service) SetCredentialHasher(hasher *security.CredentialHasher) {
+	s.credentialHasher = hasher
+}
+
+// hasher returns the configured credential hasher, or one with bcrypt's default costs
+func (s *Service) hasher() *security.CredentialHasher {
+	if s.credentialHasher == nil {
+		return security.DefaultCredentialHasher()
+	}
+	return s.credentialHasher
+}
@@ -0,0 +1,98 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/security"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// rehashTestUserRepo also finds its single user by username
+type rehashTestUserRepo struct {
+	*deviceTestUserRepo
+}
+
+func (r *rehashTestUserRepo) GetByUsername(ctx context.Context, username string) (*models.User, error) {
+	if r.user.Username == nil || *r.user.Username != username {
+		return nil, fmt.Errorf("user not found")
+	}
+	return r.user, nil
+}
+
+// newRehashTestService holds USER1 with a password and MPIN hashed at bcrypt.MinCost, and
+// hashes credentials at cost 5
+func newRehashTestService(t *testing.T) (*Service, *rehashTestUserRepo) {
+	t.Helper()
+	password, err := bcrypt.GenerateFromPassword([]byte("Secret123!"), bcrypt.MinCost)
+	require.NoError(t, err)
+	mPin, err := bcrypt.GenerateFromPassword([]byte("1111"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	username := "ravi"
+	user := models.NewUser("9876543210", "+91", string(password))
+	user.ID = "USER1"
+	user.Username = &username
+	user.SetMPin(string(mPin))
+
+	repo := &rehashTestUserRepo{&deviceTestUserRepo{user: user}}
+	service := &Service{userRepo: repo, logger: zap.NewNop(), validator: noopValidator{}}
+	service.SetCredentialHasher(security.NewCredentialHasher(bcrypt.MinCost+1, bcrypt.MinCost+1))
+	return service, repo
+}
+
+func hashCost(t *testing.T, hash string) int {
+	t.Helper()
+	cost, err := bcrypt.Cost([]byte(hash))
+	require.NoError(t, err)
+	return cost
+}
+
+func TestVerifyMPin_UpgradesWeakHash(t *testing.T) {
+	service, repo := newRehashTestService(t)
+	ctx := context.Background()
+
+	require.Error(t, service.VerifyMPin(ctx, "USER1", "9999"))
+	assert.Equal(t, bcrypt.MinCost, hashCost(t, *repo.user.MPin), "a failed verification leaves the hash alone")
+
+	require.NoError(t, service.VerifyMPin(ctx, "USER1", "1111"))
+	assert.Equal(t, bcrypt.MinCost+1, hashCost(t, *repo.user.MPin))
+	assert.Equal(t, 1, repo.updates)
+
+	require.NoError(t, service.VerifyMPin(ctx, "USER1", "1111"), "the MPIN still verifies against the new hash")
+	assert.Equal(t, 1, repo.updates, "a hash at the configured cost is not rehashed")
+}
+
+func TestVerifyUserPassword_UpgradesWeakHash(t *testing.T) {
+	service, repo := newRehashTestService(t)
+	ctx := context.Background()
+
+	_, err := service.VerifyUserPassword(ctx, "ravi", "Secret123!")
+	require.NoError(t, err)
+	assert.Equal(t, bcrypt.MinCost+1, hashCost(t, repo.user.Password))
+	assert.Equal(t, 1, repo.updates)
+
+	_, err = service.VerifyUserPassword(ctx, "ravi", "Secret123!")
+	require.NoError(t, err)
+	assert.Equal(t, 1, repo.updates)
+}
+
+func TestVerifyDeviceMPIN_UpgradesWeakHash(t *testing.T) {
+	service, repo := newRehashTestService(t)
+	devices := &memoryDeviceRepo{}
+	service.SetDeviceMPinSupport(devices, &mpinAuditRecorder{})
+	ctx := context.Background()
+
+	weak, err := bcrypt.GenerateFromPassword([]byte("2222"), bcrypt.MinCost)
+	require.NoError(t, err)
+	require.NoError(t, devices.Create(ctx, models.NewUserDevice("USER1", "phone", "Pixel 8", string(weak))))
+
+	require.NoError(t, service.VerifyDeviceMPIN(ctx, "USER1", "phone", "2222"))
+	assert.Equal(t, bcrypt.MinCost+1, hashCost(t, devices.devices[0].MPinHash))
+	assert.Equal(t, bcrypt.MinCost, hashCost(t, *repo.user.MPin), "the global MPIN is not touched")
+}
@@ -0,0 +1,461 @@
+package user
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/internal/services/otp"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/Kisanlink/aaa-service/v2/pkg/phonenumber"
+	"go.uber.org/zap"
+)
+
+// Password and MPIN resets
+//
+// A user who forgot their password or MPIN names their account by phone number, username or email.
+// An OTP is sent to a verified, active contact of the account, preferring a mobile number, and a
+// reset token is returned. CompleteReset takes the token, the OTP and the new credential, checks the
+// credential against the strength policy, sets it and signs the user out everywhere. The token
+// expires with its OTP, and the OTP is discarded after the configured number of wrong attempts.
+//
+// Resets are rate limited per client IP address and per submitted phone number, username or email,
+// whether or not an account holds it. Unknown accounts, and accounts without a verified contact, get
+// a token that never completes, so neither the response nor the rate limit reveals whether the
+// account exists. Reset state is cached under credential_reset:<SHA-256 of the token>, so the cache
+// never holds a usable token.
+
+const (
+	// CredentialResetsPerUser is how many resets of one phone number, username or email can be started
+	// within CredentialResetWindow
+	CredentialResetsPerUser = 3
+	// CredentialResetsPerIP is how many resets one client IP address can start within CredentialResetWindow
+	CredentialResetsPerIP = 10
+	// CredentialResetWindow is the window the reset rate limits apply to
+	CredentialResetWindow = time.Hour
+)
+
+// Credentials that can be reset; the OTP purpose is the credential followed by _reset
+const (
+	credentialPassword = "password"
+	credentialMPin     = "mpin"
+)
+
+// credentialReset is the cached state of a started reset
+type credentialReset struct {
+	UserID      string    `json:"user_id"`
+	Credential  string    `json:"credential"`
+	ChallengeID string    `json:"challenge_id"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// resetAttempts counts the resets started within a rate limiting window
+type resetAttempts struct {
+	Count   int       `json:"count"`
+	ResetAt time.Time `json:"reset_at"`
+}
+
+// mpinStrengthValidator is implemented by validators that reject weak MPINs
+type mpinStrengthValidator interface {
+	ValidateMPin(mpin string) error
+}
+
+// SetCredentialReset enables OTP-based password and MPIN resets, delivering the OTPs with otpService
+func (s *Service) SetCredentialReset(otpService *otp.Service) {
+	s.resetOTP = otpService
+}
+
+// SetSessionRevoker lets a completed credential reset or an account deletion request sign the user
+// out of every session
+func (s *Service) SetSessionRevoker(revoker interfaces.SessionRevoker) {
+	s.sessionRevoker = revoker
+}
+
+// InitiateMPINReset sends an MPIN reset OTP to a verified contact of the account and returns the
+// reset token to complete the reset with
+func (s *Service) InitiateMPINReset(ctx context.Context, phoneNumber, countryCode, username, email *string) (string, error) {
+	return s.initiateCredentialReset(ctx, credentialMPin, phoneNumber, countryCode, username, email)
+}
+
+// CompleteReset checks the OTP of a password or MPIN reset and sets the new credential. The user is
+// signed out of every session.
+func (s *Service) CompleteReset(ctx context.Context, token, otpCode, newCredential string) error {
+	return s.completeCredentialReset(ctx, "", token, otpCode, newCredential)
+}
+
+func (s *Service) initiateCredentialReset(ctx context.Context, credential string, phoneNumber, countryCode, username, email *string) (string, error) {
+	if s.resetOTP == nil {
+		return "", errors.NewInternalError(fmt.Errorf("credential reset is not configured"))
+	}
+
+	if ipAddress, _ := ctx.Value("ip_address").(string); ipAddress != "" {
+		if err := s.limitCredentialResets(ctx, credential, "", "ip:"+ipAddress, CredentialResetsPerIP); err != nil {
+			return "", err
+		}
+	}
+
+	// The identifier is limited before the lookup, so unknown accounts are limited like known ones
+	identifier, err := resetIdentifier(phoneNumber, countryCode, username, email)
+	if err != nil {
+		return "", err
+	}
+	if err := s.limitCredentialResets(ctx, credential, "", "account:"+hashResetValue(identifier), CredentialResetsPerUser); err != nil {
+		return "", err
+	}
+
+	user, err := s.findResetUser(ctx, phoneNumber, countryCode, username, email)
+	if err != nil {
+		return "", err
+	}
+	if user == nil {
+		s.logger.Info("Credential reset requested for an unknown account", zap.String("credential", credential))
+		return newCredentialResetToken()
+	}
+
+	contactType, destination := s.resetDestination(ctx, user.ID)
+	if destination == "" {
+		s.logger.Warn("Credential reset requested for an account without a verified contact",
+			zap.String("user_id", user.ID),
+			zap.String("credential", credential))
+		s.auditCredentialReset(ctx, credential, user.ID, "reset_requested", false, "no verified contact")
+		return newCredentialResetToken()
+	}
+
+	challenge, err := s.resetOTP.Issue(ctx, credential+"_reset", user.ID, contactType, destination)
+	if err != nil {
+		return "", err
+	}
+
+	token, err := newCredentialResetToken()
+	if err != nil {
+		return "", err
+	}
+	reset := &credentialReset{
+		UserID:      user.ID,
+		Credential:  credential,
+		ChallengeID: challenge.ID,
+		ExpiresAt:   challenge.ExpiresAt,
+	}
+	if err := s.saveCredentialReset(token, reset); err != nil {
+		return "", errors.NewInternalError(fmt.Errorf("failed to store credential reset: %w", err))
+	}
+
+	s.auditCredentialReset(ctx, credential, user.ID, "reset_requested", true, "")
+	s.logger.Info("Credential reset OTP sent",
+		zap.String("user_id", user.ID),
+		zap.String("credential", credential),
+		zap.String("sent_to", otp.MaskDestination(destination)),
+		zap.Bool("delivered", challenge.Delivered))
+	return token, nil
+}
+
+// completeCredentialReset completes a reset; an empty credential accepts a reset of either credential
+func (s *Service) completeCredentialReset(ctx context.Context, credential, token, otpCode, newCredential string) error {
+	if s.resetOTP == nil {
+		return errors.NewInternalError(fmt.Errorf("credential reset is not configured"))
+	}
+	if token == "" || otpCode == "" || newCredential == "" {
+		return errors.NewValidationError("reset token, OTP and new credential are required")
+	}
+
+	reset, ok := s.loadCredentialReset(token)
+	if !ok || (credential != "" && reset.Credential != credential) {
+		return errors.NewNotFoundError("reset token not found or expired")
+	}
+
+	// A credential that fails the policy is rejected before the OTP is checked, so it costs no attempt
+	if err := s.validateNewCredential(reset.Credential, newCredential); err != nil {
+		return err
+	}
+
+	if _, err := s.resetOTP.Verify(ctx, reset.Credential+"_reset", reset.UserID, reset.ChallengeID, otpCode); err != nil {
+		if errors.IsNotFoundError(err) {
+			// The OTP expired or was discarded after too many wrong attempts
+			s.deleteCredentialReset(token)
+			err = errors.NewNotFoundError("reset token not found or expired")
+		}
+		s.auditCredentialReset(ctx, reset.Credential, reset.UserID, "reset", false, err.Error())
+		return err
+	}
+	s.deleteCredentialReset(token)
+
+	user, err := s.userRepo.GetByID(ctx, reset.UserID, &models.User{})
+	if err != nil || user.DeletedAt != nil {
+		return errors.NewNotFoundError("user not found")
+	}
+
+	switch reset.Credential {
+	case credentialMPin:
+		hashed, err := s.hasher().HashMPin(newCredential)
+		if err != nil {
+			return errors.NewInternalError(err)
+		}
+		user.SetMPin(hashed)
+	default:
+		hashed, err := s.hasher().HashPassword(newCredential)
+		if err != nil {
+			return errors.NewInternalError(err)
+		}
+		user.Password = hashed
+		user.MustChangePassword = false
+	}
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		s.logger.Error("Failed to save reset credential",
+			zap.String("user_id", user.ID),
+			zap.String("credential", reset.Credential),
+			zap.Error(err))
+		return errors.NewInternalError(err)
+	}
+	s.clearUserCache(user.ID)
+
+	revoked := 0
+	if s.sessionRevoker != nil {
+		if revoked, err = s.sessionRevoker.RevokeAllSessions(ctx, user.ID); err != nil {
+			s.logger.Warn("Failed to revoke sessions after credential reset", zap.String("user_id", user.ID), zap.Error(err))
+		}
+	}
+
+	s.auditCredentialReset(ctx, reset.Credential, user.ID, "reset", true, "")
+	if reset.Credential == credentialMPin {
+		s.notifySecurityEvent(ctx, interfaces.SecurityEventMPinChanged, user.ID, map[string]interface{}{"change": "reset"})
+	} else {
+		s.notifySecurityEvent(ctx, interfaces.SecurityEventPasswordChanged, user.ID, map[string]interface{}{"method": "reset"})
+	}
+	s.logger.Info("Credential reset completed",
+		zap.String("user_id", user.ID),
+		zap.String("credential", reset.Credential),
+		zap.Int("sessions_revoked", revoked))
+	return nil
+}
+
+// findResetUser looks up the account a reset names; an unknown or deleted account is returned as nil
+func (s *Service) findResetUser(ctx context.Context, phoneNumber, countryCode, username, email *string) (*models.User, error) {
+	var user *models.User
+	var err error
+	switch {
+	case phoneNumber != nil && *phoneNumber != "":
+		if countryCode == nil || *countryCode == "" {
+			return nil, errors.NewValidationError("country code is required with a phone number")
+		}
+		user, err = s.userRepo.GetByPhoneNumber(ctx, *phoneNumber, *countryCode)
+	case username != nil && *username != "":
+		user, err = s.userRepo.GetByUsername(ctx, *username)
+	case email != nil && *email != "":
+		user, err = s.userRepo.GetByEmail(ctx, *email)
+	default:
+		return nil, errors.NewValidationError("a phone number, username or email is required")
+	}
+	if err != nil || user == nil || user.DeletedAt != nil {
+		return nil, nil
+	}
+	return user, nil
+}
+
+// resetIdentifier returns the account identifier a reset was requested for, normalized so that
+// spellings of the same phone number, username or email share a rate limit
+func resetIdentifier(phoneNumber, countryCode, username, email *string) (string, error) {
+	switch {
+	case phoneNumber != nil && *phoneNumber != "":
+		if countryCode == nil || *countryCode == "" {
+			return "", errors.NewValidationError("country code is required with a phone number")
+		}
+		if e164, err := phonenumber.Normalize(*phoneNumber, *countryCode); err == nil {
+			return "phone:" + e164, nil
+		}
+		return "phone:" + *countryCode + *phoneNumber, nil
+	case username != nil && *username != "":
+		return "username:" + strings.ToLower(strings.TrimSpace(*username)), nil
+	case email != nil && *email != "":
+		return "email:" + strings.ToLower(strings.TrimSpace(*email)), nil
+	default:
+		return "", errors.NewValidationError("a phone number, username or email is required")
+	}
+}
+
+// resetDestination picks the verified, active contact the reset OTP is sent to, preferring a mobile
+// number; it returns an empty destination when the user has none the OTP service can deliver to
+func (s *Service) resetDestination(ctx context.Context, userID string) (string, string) {
+	user, err := s.userRepo.GetWithDetails(ctx, userID, false, true, false, false)
+	if err != nil {
+		s.logger.Warn("Failed to load contacts for credential reset", zap.String("user_id", userID), zap.Error(err))
+		return "", ""
+	}
+
+	var emailDestination string
+	for i := range user.Contacts {
+		contact := &user.Contacts[i]
+		if !contact.IsVerified || !contact.IsActive || !s.resetOTP.CanDeliver(contact.Type) {
+			continue
+		}
+		switch contact.Type {
+		case models.ContactTypeMobile:
+			countryCode := ""
+			if contact.CountryCode != nil {
+				countryCode = *contact.CountryCode
+			}
+			if e164, err := phonenumber.Normalize(contact.Value, countryCode); err == nil {
+				return models.ContactTypeMobile, e164
+			}
+		case models.ContactTypeEmail:
+			if emailDestination == "" {
+				emailDestination = contact.Value
+			}
+		}
+	}
+	if emailDestination != "" {
+		return models.ContactTypeEmail, emailDestination
+	}
+	return "", ""
+}
+
+// validateNewCredential applies the strength policy of the credential
+func (s *Service) validateNewCredential(credential, value string) error {
+	if credential == credentialMPin {
+		if err := validateMPinFormat(value); err != nil {
+			return err
+		}
+		if validator, ok := s.validator.(mpinStrengthValidator); ok {
+			if err := validator.ValidateMPin(value); err != nil {
+				return errors.NewValidationError(err.Error())
+			}
+		}
+		return nil
+	}
+
+	if s.validator == nil {
+		if len(value) < 8 {
+			return errors.NewValidationError("password must be at least 8 characters long")
+		}
+		return nil
+	}
+	if err := s.validator.ValidatePassword(value); err != nil {
+		return errors.NewValidationError(err.Error())
+	}
+	return nil
+}
+
+// limitCredentialResets counts a reset against the limit of a client or user and rejects it once
+// the limit is reached within the window
+func (s *Service) limitCredentialResets(ctx context.Context, credential, userID, subject string, limit int) error {
+	key := fmt.Sprintf("credential_reset_limit:%s", subject)
+	now := time.Now()
+
+	attempts := resetAttempts{ResetAt: now.Add(CredentialResetWindow)}
+	if value, ok := s.cacheService.Get(key); ok {
+		if data, ok := value.(string); ok {
+			var cached resetAttempts
+			if json.Unmarshal([]byte(data), &cached) == nil && now.Before(cached.ResetAt) {
+				attempts = cached
+			}
+		}
+	}
+
+	if attempts.Count >= limit {
+		retryAfter := int(time.Until(attempts.ResetAt).Seconds()) + 1
+		s.logger.Warn("Credential reset rate limited",
+			zap.String("subject", subject),
+			zap.String("credential", credential),
+			zap.Int("limit", limit))
+		s.auditCredentialReset(ctx, credential, userID, "reset_rate_limited", false, fmt.Sprintf("more than %d resets of %s", limit, subject))
+		return errors.NewRateLimitError(strconv.Itoa(retryAfter))
+	}
+
+	attempts.Count++
+	data, err := json.Marshal(attempts)
+	if err != nil {
+		return errors.NewInternalError(err)
+	}
+	if err := s.cacheService.Set(key, string(data), int(time.Until(attempts.ResetAt).Seconds())+1); err != nil {
+		s.logger.Warn("Failed to count credential reset", zap.String("subject", subject), zap.Error(err))
+	}
+	return nil
+}
+
+// auditCredentialReset audits MPIN resets as MPIN operations and password resets as security events
+func (s *Service) auditCredentialReset(ctx context.Context, credential, userID, operation string, success bool, failureReason string) {
+	if s.auditService == nil {
+		return
+	}
+	ipAddress, _ := ctx.Value("ip_address").(string)
+	userAgent, _ := ctx.Value("user_agent").(string)
+	if credential == credentialMPin {
+		s.auditService.LogMPINOperation(ctx, userID, operation, ipAddress, userAgent, success, failureReason)
+		return
+	}
+
+	details := map[string]interface{}{
+		"ip_address": ipAddress,
+		"user_agent": userAgent,
+	}
+	if failureReason != "" {
+		details["failure_reason"] = failureReason
+	}
+	s.auditService.LogSecurityEvent(ctx, userID, "password_"+operation, models.ResourceTypeUser, success, details)
+}
+
+// saveCredentialReset caches a reset until its OTP expires, as a JSON string like the OTP challenges
+func (s *Service) saveCredentialReset(token string, reset *credentialReset) error {
+	ttl := int(time.Until(reset.ExpiresAt).Seconds())
+	if ttl <= 0 {
+		return fmt.Errorf("credential reset has expired")
+	}
+	data, err := json.Marshal(reset)
+	if err != nil {
+		return err
+	}
+	return s.cacheService.Set(credentialResetKey(token), string(data), ttl)
+}
+
+// loadCredentialReset returns an unexpired reset
+func (s *Service) loadCredentialReset(token string) (*credentialReset, bool) {
+	value, ok := s.cacheService.Get(credentialResetKey(token))
+	if !ok {
+		return nil, false
+	}
+	data, ok := value.(string)
+	if !ok {
+		return nil, false
+	}
+	var reset credentialReset
+	if err := json.Unmarshal([]byte(data), &reset); err != nil || reset.UserID == "" {
+		return nil, false
+	}
+	if !time.Now().Before(reset.ExpiresAt) {
+		return nil, false
+	}
+	return &reset, true
+}
+
+func (s *Service) deleteCredentialReset(token string) {
+	if err := s.cacheService.Delete(credentialResetKey(token)); err != nil {
+		s.logger.Warn("Failed to delete credential reset", zap.Error(err))
+	}
+}
+
+// credentialResetKey keys a reset by the hash of its token, so reading the cache does not yield
+// tokens that complete resets
+func credentialResetKey(token string) string {
+	return fmt.Sprintf("credential_reset:%s", hashResetValue(token))
+}
+
+// hashResetValue returns the hex SHA-256 of a reset token or account identifier
+func hashResetValue(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
+
+func newCredentialResetToken() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", errors.NewInternalError(fmt.Errorf("failed to generate reset token: %w", err))
+	}
+	return "CRST" + hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,354 @@
+package user
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/internal/security"
+	"github.com/Kisanlink/aaa-service/v2/internal/services/otp"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// resetUserRepo serves users by ID, phone number and username
+type resetUserRepo struct {
+	interfaces.UserRepository
+	users map[string]*models.User
+}
+
+func (r *resetUserRepo) GetByID(ctx context.Context, id string, user *models.User) (*models.User, error) {
+	if found, ok := r.users[id]; ok {
+		return found, nil
+	}
+	return nil, fmt.Errorf("user not found")
+}
+
+func (r *resetUserRepo) GetWithDetails(ctx context.Context, userID string, includeProfile, includeContacts, includeAddresses, includeRoles bool) (*models.User, error) {
+	return r.GetByID(ctx, userID, nil)
+}
+
+func (r *resetUserRepo) GetByPhoneNumber(ctx context.Context, phoneNumber, countryCode string) (*models.User, error) {
+	for _, user := range r.users {
+		if user.PhoneNumber == phoneNumber && user.CountryCode == countryCode {
+			return user, nil
+		}
+	}
+	return nil, fmt.Errorf("user not found")
+}
+
+func (r *resetUserRepo) GetByUsername(ctx context.Context, username string) (*models.User, error) {
+	for _, user := range r.users {
+		if user.Username != nil && *user.Username == username {
+			return user, nil
+		}
+	}
+	return nil, fmt.Errorf("user not found")
+}
+
+func (r *resetUserRepo) Update(ctx context.Context, user *models.User) error {
+	r.users[user.ID] = user
+	return nil
+}
+
+// resetCache keeps values in memory
+type resetCache struct {
+	interfaces.CacheService
+	values map[string]interface{}
+}
+
+func (c *resetCache) Get(key string) (interface{}, bool) {
+	value, ok := c.values[key]
+	return value, ok
+}
+
+func (c *resetCache) Set(key string, value interface{}, ttl int) error {
+	c.values[key] = value
+	return nil
+}
+
+func (c *resetCache) Delete(key string) error {
+	delete(c.values, key)
+	return nil
+}
+
+// resetSender remembers the OTPs it delivers
+type resetSender struct {
+	destinations []string
+	codes        []string
+}
+
+func (s *resetSender) SendOTP(ctx context.Context, destination, code string) error {
+	s.destinations = append(s.destinations, destination)
+	s.codes = append(s.codes, code)
+	return nil
+}
+
+// resetValidator applies a small password policy and rejects repeated-digit MPINs
+type resetValidator struct {
+	interfaces.Validator
+}
+
+func (resetValidator) ValidatePassword(password string) error {
+	if len(password) < 8 || !strings.ContainsAny(password, "0123456789") {
+		return fmt.Errorf("password must be at least 8 characters long and contain a digit")
+	}
+	return nil
+}
+
+func (resetValidator) ValidateMPin(mpin string) error {
+	if strings.Count(mpin, mpin[:1]) == len(mpin) {
+		return fmt.Errorf("MPIN is too weak - avoid sequential or repeated digits")
+	}
+	return nil
+}
+
+// resetSessionRevoker records whose sessions were revoked
+type resetSessionRevoker struct {
+	revoked []string
+}
+
+func (r *resetSessionRevoker) RevokeAllSessions(ctx context.Context, userID string) (int, error) {
+	r.revoked = append(r.revoked, userID)
+	return 2, nil
+}
+
+// resetAuditRecorder records MPIN operations and security events
+type resetAuditRecorder struct {
+	interfaces.AuditService
+	events []string
+}
+
+func (a *resetAuditRecorder) LogMPINOperation(ctx context.Context, userID, operation, ipAddress, userAgent string, success bool, failureReason string) {
+	a.events = append(a.events, fmt.Sprintf("mpin_%s:%t", operation, success))
+}
+
+func (a *resetAuditRecorder) LogSecurityEvent(ctx context.Context, userID, action, resource string, success bool, details map[string]interface{}) {
+	a.events = append(a.events, fmt.Sprintf("%s:%t", action, success))
+}
+
+type credentialResetFixture struct {
+	service  *Service
+	users    *resetUserRepo
+	cache    *resetCache
+	sender   *resetSender
+	sessions *resetSessionRevoker
+	audit    *resetAuditRecorder
+}
+
+func newCredentialResetFixture(t *testing.T) *credentialResetFixture {
+	t.Helper()
+	password, err := bcrypt.GenerateFromPassword([]byte("Secret123!"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	user := models.NewUser("9876543210", "+91", string(password))
+	user.ID = "USER1"
+	username := "ramesh"
+	user.Username = &username
+	mobile := models.NewContact("USER1", models.ContactTypeMobile, "9876543210")
+	mobile.IsVerified = true
+	user.Contacts = []models.Contact{*mobile}
+
+	unverified := models.NewUser("9123456780", "+91", string(password))
+	unverified.ID = "USER2"
+	unverified.Contacts = []models.Contact{*models.NewContact("USER2", models.ContactTypeMobile, "9123456780")}
+
+	fixture := &credentialResetFixture{
+		users:    &resetUserRepo{users: map[string]*models.User{"USER1": user, "USER2": unverified}},
+		cache:    &resetCache{values: map[string]interface{}{}},
+		sender:   &resetSender{},
+		sessions: &resetSessionRevoker{},
+		audit:    &resetAuditRecorder{},
+	}
+	fixture.service = &Service{
+		userRepo:     fixture.users,
+		cacheService: fixture.cache,
+		logger:       zap.NewNop(),
+		validator:    resetValidator{},
+		auditService: fixture.audit,
+	}
+	fixture.service.SetCredentialHasher(security.NewCredentialHasher(bcrypt.MinCost, bcrypt.MinCost))
+	fixture.service.SetCredentialReset(otp.NewService(
+		fixture.cache,
+		map[string]interfaces.OTPSender{models.ContactTypeMobile: fixture.sender},
+		otp.DefaultConfig(),
+		zap.NewNop(),
+	))
+	fixture.service.SetSessionRevoker(fixture.sessions)
+	return fixture
+}
+
+func (f *credentialResetFixture) lastCode() string {
+	return f.sender.codes[len(f.sender.codes)-1]
+}
+
+func resetRequestContext(ip string) context.Context {
+	ctx := context.WithValue(context.Background(), "ip_address", ip)
+	return context.WithValue(ctx, "user_agent", "App/1.0")
+}
+
+func TestCredentialReset_MPIN(t *testing.T) {
+	f := newCredentialResetFixture(t)
+	ctx := resetRequestContext("203.0.113.1")
+
+	token, err := f.service.InitiateMPINReset(ctx, stringPtr("9876543210"), stringPtr("+91"), nil, nil)
+	require.NoError(t, err)
+	require.NotEmpty(t, token)
+	assert.Equal(t, []string{"+919876543210"}, f.sender.destinations, "the OTP goes to the verified mobile number")
+
+	err = f.service.CompleteReset(ctx, token, f.lastCode(), "4444")
+	assert.True(t, errors.IsValidationError(err), "weak MPINs are rejected")
+	err = f.service.CompleteReset(ctx, token, f.lastCode(), "12ab")
+	assert.True(t, errors.IsValidationError(err))
+
+	require.NoError(t, f.service.CompleteReset(ctx, token, f.lastCode(), "2580"))
+	user := f.users.users["USER1"]
+	require.True(t, user.HasMPin())
+	assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(*user.MPin), []byte("2580")))
+	assert.Equal(t, []string{"USER1"}, f.sessions.revoked, "the user is signed out everywhere")
+	assert.Equal(t, []string{"mpin_reset_requested:true", "mpin_reset:true"}, f.audit.events)
+
+	err = f.service.CompleteReset(ctx, token, f.lastCode(), "3690")
+	assert.True(t, errors.IsNotFoundError(err), "a reset token is used once")
+}
+
+func TestCredentialReset_Password(t *testing.T) {
+	f := newCredentialResetFixture(t)
+	ctx := resetRequestContext("203.0.113.1")
+
+	mpinToken, err := f.service.InitiateMPINReset(ctx, nil, nil, stringPtr("ramesh"), nil)
+	require.NoError(t, err)
+	err = f.service.ResetPassword(ctx, mpinToken, f.lastCode(), "NewSecret456!")
+	assert.True(t, errors.IsNotFoundError(err), "an MPIN reset cannot set the password")
+
+	token, err := f.service.InitiatePasswordReset(ctx, nil, nil, stringPtr("ramesh"), nil)
+	require.NoError(t, err)
+	err = f.service.ResetPassword(ctx, token, f.lastCode(), "password")
+	assert.True(t, errors.IsValidationError(err), "the password policy applies")
+
+	require.NoError(t, f.service.ResetPassword(ctx, token, f.lastCode(), "NewSecret456!"))
+	user := f.users.users["USER1"]
+	assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(user.Password), []byte("NewSecret456!")))
+	assert.Equal(t, []string{"USER1"}, f.sessions.revoked)
+	assert.Contains(t, f.audit.events, "password_reset_requested:true")
+	assert.Contains(t, f.audit.events, "password_reset:true")
+}
+
+func TestCredentialReset_ExpiredToken(t *testing.T) {
+	f := newCredentialResetFixture(t)
+	ctx := resetRequestContext("203.0.113.1")
+
+	token, err := f.service.InitiateMPINReset(ctx, stringPtr("9876543210"), stringPtr("+91"), nil, nil)
+	require.NoError(t, err)
+
+	// Age the reset past its expiry
+	var reset credentialReset
+	require.NoError(t, json.Unmarshal([]byte(f.cache.values[credentialResetKey(token)].(string)), &reset))
+	reset.ExpiresAt = time.Now().Add(-time.Second)
+	data, err := json.Marshal(reset)
+	require.NoError(t, err)
+	f.cache.values[credentialResetKey(token)] = string(data)
+
+	err = f.service.CompleteReset(ctx, token, f.lastCode(), "2580")
+	assert.True(t, errors.IsNotFoundError(err))
+	assert.False(t, f.users.users["USER1"].HasMPin())
+	assert.Empty(t, f.sessions.revoked)
+}
+
+func TestCredentialReset_AttemptLimit(t *testing.T) {
+	f := newCredentialResetFixture(t)
+	ctx := resetRequestContext("203.0.113.1")
+
+	token, err := f.service.InitiateMPINReset(ctx, stringPtr("9876543210"), stringPtr("+91"), nil, nil)
+	require.NoError(t, err)
+	code := f.lastCode()
+	wrong := "000000"
+	if code == wrong {
+		wrong = "111111"
+	}
+
+	maxAttempts := otp.DefaultConfig().MaxAttempts
+	for i := 1; i < maxAttempts; i++ {
+		err := f.service.CompleteReset(ctx, token, wrong, "2580")
+		require.True(t, errors.IsValidationError(err), "attempt %d", i)
+	}
+	err = f.service.CompleteReset(ctx, token, wrong, "2580")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "too many invalid attempts")
+
+	err = f.service.CompleteReset(ctx, token, code, "2580")
+	assert.True(t, errors.IsNotFoundError(err), "the right OTP no longer works once the attempts are used up")
+	assert.False(t, f.users.users["USER1"].HasMPin())
+	assert.Equal(t, "mpin_reset:false", f.audit.events[len(f.audit.events)-1], "failed attempts are audited")
+}
+
+func TestCredentialReset_RateLimits(t *testing.T) {
+	f := newCredentialResetFixture(t)
+
+	for i := 0; i < CredentialResetsPerUser; i++ {
+		_, err := f.service.InitiateMPINReset(resetRequestContext(fmt.Sprintf("203.0.113.%d", i)), nil, nil, stringPtr("ramesh"), nil)
+		require.NoError(t, err)
+	}
+	_, err := f.service.InitiatePasswordReset(resetRequestContext("198.51.100.1"), nil, nil, stringPtr("ramesh"), nil)
+	assert.Equal(t, errors.CodeRateLimited, errors.CodeOf(err), "resets of one user are limited whatever the address")
+	assert.Equal(t, "password_reset_rate_limited:false", f.audit.events[len(f.audit.events)-1])
+
+	for i := 0; i < CredentialResetsPerUser; i++ {
+		_, err := f.service.InitiateMPINReset(resetRequestContext(fmt.Sprintf("203.0.113.%d", 100+i)), nil, nil, stringPtr("nobody"), nil)
+		require.NoError(t, err)
+	}
+	_, err = f.service.InitiateMPINReset(resetRequestContext("198.51.100.2"), nil, nil, stringPtr(" Nobody"), nil)
+	assert.Equal(t, errors.CodeRateLimited, errors.CodeOf(err), "unknown accounts are limited like known ones")
+
+	ctx := resetRequestContext("192.0.2.9")
+	for i := 0; i < CredentialResetsPerIP; i++ {
+		_, err := f.service.InitiateMPINReset(ctx, nil, nil, stringPtr(fmt.Sprintf("unknown%d", i)), nil)
+		require.NoError(t, err)
+	}
+	_, err = f.service.InitiateMPINReset(ctx, nil, nil, stringPtr("someone"), nil)
+	assert.Equal(t, errors.CodeRateLimited, errors.CodeOf(err), "resets from one address are limited whatever the account")
+}
+
+func TestCredentialReset_CachesTokenHashes(t *testing.T) {
+	f := newCredentialResetFixture(t)
+
+	token, err := f.service.InitiateMPINReset(resetRequestContext("203.0.113.1"), nil, nil, stringPtr("ramesh"), nil)
+	require.NoError(t, err)
+
+	for key := range f.cache.values {
+		assert.NotContains(t, key, token, "the cache does not hold usable reset tokens")
+	}
+	_, ok := f.cache.values[credentialResetKey(token)]
+	assert.True(t, ok)
+}
+
+func TestCredentialReset_DoesNotRevealAccounts(t *testing.T) {
+	f := newCredentialResetFixture(t)
+	ctx := resetRequestContext("203.0.113.1")
+
+	unknown, err := f.service.InitiateMPINReset(ctx, nil, nil, stringPtr("nobody"), nil)
+	require.NoError(t, err)
+	assert.NotEmpty(t, unknown, "unknown accounts get a token too")
+
+	unverified, err := f.service.InitiateMPINReset(ctx, stringPtr("9123456780"), stringPtr("+91"), nil, nil)
+	require.NoError(t, err)
+	assert.NotEmpty(t, unverified)
+	assert.Empty(t, f.sender.codes, "only verified contacts receive OTPs")
+	assert.Equal(t, []string{"mpin_reset_requested:false"}, f.audit.events)
+
+	for _, token := range []string{unknown, unverified} {
+		err := f.service.CompleteReset(ctx, token, "123456", "2580")
+		assert.True(t, errors.IsNotFoundError(err))
+	}
+
+	_, err = f.service.InitiateMPINReset(ctx, nil, nil, nil, nil)
+	assert.True(t, errors.IsValidationError(err))
+}
@@ -0,0 +1,157 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	userRequests "github.com/Kisanlink/aaa-service/v2/internal/entities/requests/users"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/internal/security"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// exportAuditPageSize is the number of audit entries read per query while exporting a user's data
+const exportAuditPageSize = 500
+
+// userIDPattern matches user IDs, which carry the USER prefix of the user model
+var userIDPattern = regexp.MustCompile(`USER[0-9A-Za-z]+`)
+
+// exportPersonalDetailKeys are audit details that describe a person. They are redacted from
+// entries that involve a user other than the one exporting.
+var exportPersonalDetailKeys = security.NewRedactor([]string{
+	"phone", "phone_number", "country_code", "email", "username",
+	"first_name", "last_name", "full_name", "ip_address", "user_agent",
+})
+
+// SetDataExportAuditRepository adds the audit trail to user data exports
+func (s *Service) SetDataExportAuditRepository(auditRepo interfaces.AuditRepository) {
+	s.exportAuditRepo = auditRepo
+}
+
+// ExportUserData writes the data held about a user: their account with its profile, contacts,
+// addresses, roles and group memberships, then the audit trail of the actions they performed and
+// of the actions others performed on their behalf. Audit entries are read a page at a time and
+// the IDs and personal details of other users are redacted from them.
+func (s *Service) ExportUserData(ctx context.Context, userID string, out interfaces.UserDataExportWriter) error {
+	account, err := s.GetUserDetail(ctx, userID, userRequests.AllUserDetails())
+	if err != nil {
+		return err
+	}
+	if err := out.WriteAccount(account); err != nil {
+		return err
+	}
+	if s.exportAuditRepo == nil {
+		return nil
+	}
+
+	entries := 0
+	for _, filter := range []interfaces.AuditLogFilter{{UserID: userID}, {OnBehalfOfID: userID}} {
+		for offset := 0; ; offset += exportAuditPageSize {
+			page, err := s.exportAuditRepo.ListByFilter(ctx, filter, exportAuditPageSize, offset)
+			if err != nil {
+				return errors.NewInternalError(fmt.Errorf("failed to read audit trail: %w", err))
+			}
+			for _, entry := range page {
+				// Entries of the user's own trail are not repeated in the on-behalf pass
+				if filter.OnBehalfOfID != "" && entry.UserID != nil && *entry.UserID == userID {
+					continue
+				}
+				if err := out.WriteAuditEntry(redactSharedAuditEntry(entry, userID)); err != nil {
+					return err
+				}
+				entries++
+			}
+			if len(page) < exportAuditPageSize {
+				break
+			}
+		}
+	}
+
+	s.logger.Info("User data exported", zap.String("user_id", userID), zap.Int("audit_entries", entries))
+	return nil
+}
+
+// redactSharedAuditEntry returns a copy of an audit entry fit for userID's data export. The IDs of
+// other users are replaced wherever they appear. When another user took part in the entry, as the
+// actor or as the user acted on, personal details are redacted too, and so are the client IP
+// address and user agent unless userID performed the action.
+func redactSharedAuditEntry(entry *models.AuditLog, userID string) *models.AuditLog {
+	redacted := *entry
+	redacted.User = nil
+
+	actedByOther := (entry.UserID == nil || *entry.UserID != userID) ||
+		(entry.ActorID != nil && *entry.ActorID != userID)
+	shared := actedByOther ||
+		(entry.OnBehalfOfID != nil && *entry.OnBehalfOfID != userID) ||
+		(entry.ResourceID != nil && isOtherUserID(*entry.ResourceID, userID))
+
+	redacted.UserID = redactUserIDRef(entry.UserID, userID)
+	redacted.ActorID = redactUserIDRef(entry.ActorID, userID)
+	redacted.OnBehalfOfID = redactUserIDRef(entry.OnBehalfOfID, userID)
+	if entry.ResourceID != nil {
+		resourceID := redactUserIDs(*entry.ResourceID, userID)
+		redacted.ResourceID = &resourceID
+	}
+	redacted.Message = redactUserIDs(entry.Message, userID)
+	if actedByOther {
+		redacted.IPAddress = ""
+		redacted.UserAgent = ""
+	}
+
+	details, _ := redactDetailUserIDs(entry.Details, userID).(map[string]interface{})
+	if shared {
+		details = exportPersonalDetailKeys.RedactMap(details)
+	}
+	redacted.Details = details
+	return &redacted
+}
+
+func redactUserIDRef(id *string, userID string) *string {
+	if id == nil || *id == "" {
+		return id
+	}
+	redacted := redactUserIDs(*id, userID)
+	return &redacted
+}
+
+// redactUserIDs replaces the user IDs other than userID in text
+func redactUserIDs(text, userID string) string {
+	return userIDPattern.ReplaceAllStringFunc(text, func(id string) string {
+		if id == userID {
+			return id
+		}
+		return security.RedactedValue
+	})
+}
+
+func isOtherUserID(id, userID string) bool {
+	return id != userID && userIDPattern.FindString(id) == id
+}
+
+// redactDetailUserIDs copies audit details with the user IDs other than userID replaced at any depth
+func redactDetailUserIDs(value interface{}, userID string) interface{} {
+	switch v := value.(type) {
+	case string:
+		return redactUserIDs(v, userID)
+	case map[string]interface{}:
+		if v == nil {
+			return v
+		}
+		redacted := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			redacted[key] = redactDetailUserIDs(item, userID)
+		}
+		return redacted
+	case []interface{}:
+		redacted := make([]interface{}, len(v))
+		for i, item := range v {
+			redacted[i] = redactDetailUserIDs(item, userID)
+		}
+		return redacted
+	default:
+		return v
+	}
+}
@@ -0,0 +1,135 @@
+package user
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	userResponses "github.com/Kisanlink/aaa-service/v2/internal/entities/responses/users"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/internal/security"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// exportAuditRepo serves audit entries by the user they were recorded for or on behalf of
+type exportAuditRepo struct {
+	interfaces.AuditRepository
+	entries []*models.AuditLog
+	filters []interfaces.AuditLogFilter
+}
+
+func (r *exportAuditRepo) ListByFilter(ctx context.Context, filter interfaces.AuditLogFilter, limit, offset int) ([]*models.AuditLog, error) {
+	r.filters = append(r.filters, filter)
+	var page []*models.AuditLog
+	for _, entry := range r.entries {
+		if (filter.UserID != "" && entry.UserID != nil && *entry.UserID == filter.UserID) ||
+			(filter.OnBehalfOfID != "" && entry.OnBehalfOfID != nil && *entry.OnBehalfOfID == filter.OnBehalfOfID) {
+			page = append(page, entry)
+		}
+	}
+	if offset >= len(page) {
+		return nil, nil
+	}
+	return page[offset:min(offset+limit, len(page))], nil
+}
+
+type recordedExport struct {
+	account *userResponses.UserDetailResponse
+	entries []*models.AuditLog
+}
+
+func (e *recordedExport) WriteAccount(account *userResponses.UserDetailResponse) error {
+	e.account = account
+	return nil
+}
+
+func (e *recordedExport) WriteAuditEntry(entry *models.AuditLog) error {
+	e.entries = append(e.entries, entry)
+	return nil
+}
+
+func auditEntry(userID, action string) *models.AuditLog {
+	entry := models.NewAuditLogWithUser(userID, action, "aaa/user", models.AuditStatusSuccess, "")
+	entry.IPAddress = "203.0.113.1"
+	entry.UserAgent = "App/1.0"
+	return entry
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
+
+func TestExportUserData_AccountAndAuditTrail(t *testing.T) {
+	own := auditEntry("USER1", models.AuditActionLogin)
+	own.ResourceID = stringPtr("USER1")
+
+	// USER1 assigned a role to USER2: USER2 is redacted
+	assign := auditEntry("USER1", models.AuditActionAssignRole)
+	assign.ResourceID = stringPtr("USER2")
+	assign.Message = "Role assigned to USER2 by USER1"
+	assign.Details = map[string]interface{}{
+		"target_user_id": "USER2",
+		"phone_number":   "9000000002",
+		"role_name":      "farmer",
+		"affected":       []interface{}{"USER1", "USER2"},
+	}
+
+	// An admin changed USER1's account: the admin and their client are redacted
+	adminUpdate := auditEntry("USER9", models.AuditActionUpdateUser)
+	adminUpdate.OnBehalfOfID = stringPtr("USER1")
+	adminUpdate.Details = map[string]interface{}{"username": "admin9", "field": "status"}
+
+	other := auditEntry("USER2", models.AuditActionLogin)
+
+	auditRepo := &exportAuditRepo{entries: []*models.AuditLog{own, assign, adminUpdate, other}}
+	service := &Service{userRepo: &detailUserRepo{user: newDetailTestUser()}, exportAuditRepo: auditRepo, logger: zap.NewNop()}
+
+	export := &recordedExport{}
+	require.NoError(t, service.ExportUserData(context.Background(), "USER1", export))
+
+	require.NotNil(t, export.account)
+	assert.Equal(t, "USER1", export.account.ID)
+	assert.NotNil(t, export.account.Profile)
+	assert.Len(t, export.account.Contacts, 2)
+	assert.Len(t, export.account.Addresses, 2)
+	assert.Len(t, export.account.Roles, 1)
+	assert.NotNil(t, export.account.Groups)
+
+	require.Len(t, export.entries, 3, "another user's own trail is not exported")
+	assert.Equal(t, []interfaces.AuditLogFilter{{UserID: "USER1"}, {OnBehalfOfID: "USER1"}}, auditRepo.filters)
+
+	ownEntry := export.entries[0]
+	assert.Equal(t, "USER1", *ownEntry.ResourceID)
+	assert.Equal(t, "203.0.113.1", ownEntry.IPAddress)
+
+	assignEntry := export.entries[1]
+	assert.Equal(t, "USER1", *assignEntry.UserID)
+	assert.Equal(t, security.RedactedValue, *assignEntry.ResourceID)
+	assert.Equal(t, "Role assigned to [REDACTED] by USER1", assignEntry.Message)
+	assert.Equal(t, security.RedactedValue, assignEntry.Details["target_user_id"])
+	assert.Equal(t, security.RedactedValue, assignEntry.Details["phone_number"])
+	assert.Equal(t, "farmer", assignEntry.Details["role_name"])
+	assert.Equal(t, []interface{}{"USER1", security.RedactedValue}, assignEntry.Details["affected"])
+	assert.Equal(t, "203.0.113.1", assignEntry.IPAddress, "the user's own client is kept")
+	assert.Equal(t, "USER2", *assign.ResourceID, "the stored entry is not modified")
+
+	adminEntry := export.entries[2]
+	assert.Equal(t, security.RedactedValue, *adminEntry.UserID)
+	assert.Equal(t, "USER1", *adminEntry.OnBehalfOfID)
+	assert.Empty(t, adminEntry.IPAddress)
+	assert.Empty(t, adminEntry.UserAgent)
+	assert.Equal(t, security.RedactedValue, adminEntry.Details["username"])
+	assert.Equal(t, "status", adminEntry.Details["field"])
+}
+
+func TestExportUserData_UserNotFound(t *testing.T) {
+	service := &Service{userRepo: &detailUserRepo{user: newDetailTestUser()}, exportAuditRepo: &exportAuditRepo{}, logger: zap.NewNop()}
+
+	export := &recordedExport{}
+	err := service.ExportUserData(context.Background(), "USER404", export)
+	assert.True(t, errors.IsNotFoundError(err))
+	assert.Nil(t, export.account)
+}
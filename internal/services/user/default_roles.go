@@ -0,0 +1,178 @@
+package user
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"go.uber.org/zap"
+)
+
+// DefaultRoleOperation is the role operation audited for roles given on registration
+const DefaultRoleOperation = "default_assign"
+
+// defaultRoles resolves the roles given to new users: the global default roles for every user,
+// plus the roles an organization lists in its default_user_roles setting for users registered in it
+type defaultRoles struct {
+	global      []string
+	roleRepo    interfaces.OrganizationRoleRepository
+	settingRepo interfaces.OrganizationSettingRepository // Optional: per-organization default roles
+}
+
+// SetDefaultRoles gives every new user the global roles named in global, and users registered in an
+// organization the roles named in its default_user_roles setting. Roles are looked up by name among
+// the global roles and, for an organization, its own roles first.
+func (s *Service) SetDefaultRoles(global []string, roleRepo interfaces.OrganizationRoleRepository, settingRepo interfaces.OrganizationSettingRepository) {
+	s.defaultRoles = &defaultRoles{
+		global:      normalizeRoleNames(global),
+		roleRepo:    roleRepo,
+		settingRepo: settingRepo,
+	}
+}
+
+// ValidateDefaultRoles warns about configured global default roles that do not exist or are
+// inactive, and returns their names. Users are created without them until they are added.
+func (s *Service) ValidateDefaultRoles(ctx context.Context) []string {
+	if s.defaultRoles == nil || len(s.defaultRoles.global) == 0 {
+		return nil
+	}
+
+	_, missing, err := s.defaultRoles.resolve(ctx, s.defaultRoles.global, "")
+	if err != nil {
+		s.logger.Warn("Failed to validate default roles", zap.Strings("roles", s.defaultRoles.global), zap.Error(err))
+		return nil
+	}
+	if len(missing) > 0 {
+		s.logger.Warn("Configured default roles do not exist; new users will not get them",
+			zap.Strings("missing_roles", missing))
+	}
+	return missing
+}
+
+// newUserRoles returns the default role assignments of a user registered in orgID, which is empty
+// outside an organization
+func (s *Service) newUserRoles(ctx context.Context, userID, orgID string) ([]*models.UserRole, []*models.Role, error) {
+	if s.defaultRoles == nil {
+		return nil, nil, nil
+	}
+
+	names := s.defaultRoles.global
+	if orgID != "" {
+		orgNames, err := s.defaultRoles.organizationRoleNames(ctx, orgID)
+		if err != nil {
+			return nil, nil, err
+		}
+		names = append(append([]string(nil), names...), orgNames...)
+	}
+	if len(names) == 0 {
+		return nil, nil, nil
+	}
+
+	roles, missing, err := s.defaultRoles.resolve(ctx, normalizeRoleNames(names), orgID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(missing) > 0 {
+		s.logger.Warn("Default roles not found, skipping them",
+			zap.String("organization_id", orgID),
+			zap.Strings("missing_roles", missing))
+	}
+
+	userRoles := make([]*models.UserRole, 0, len(roles))
+	for _, role := range roles {
+		userRoles = append(userRoles, models.NewUserRole(userID, role.ID))
+	}
+	return userRoles, roles, nil
+}
+
+// auditDefaultRoles records the default roles given to a new user. The assignment is made by the
+// service rather than a caller, so it has no actor.
+func (s *Service) auditDefaultRoles(ctx context.Context, userID, orgID string, roles []*models.Role) {
+	if s.auditService == nil {
+		return
+	}
+	for _, role := range roles {
+		details := map[string]interface{}{"role_name": role.Name}
+		if orgID != "" {
+			details["organization_id"] = orgID
+		}
+		s.auditService.LogRoleOperation(ctx, "", userID, role.ID, DefaultRoleOperation, true, details)
+	}
+}
+
+// organizationRoleNames reads the default_user_roles setting of an organization
+func (d *defaultRoles) organizationRoleNames(ctx context.Context, orgID string) ([]string, error) {
+	if d.settingRepo == nil {
+		return nil, nil
+	}
+	settings, err := d.settingRepo.ListByOrganization(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	for _, setting := range settings {
+		if setting.Key != models.OrgSettingDefaultUserRoles {
+			continue
+		}
+		// A value that no longer decodes as a string falls back to the default of no roles
+		var value string
+		if err := json.Unmarshal([]byte(setting.Value), &value); err != nil {
+			return nil, nil
+		}
+		return strings.Split(value, ","), nil
+	}
+	return nil, nil
+}
+
+// resolve finds the active roles named in names, preferring roles of orgID over global roles of the
+// same name, and returns the names it could not find
+func (d *defaultRoles) resolve(ctx context.Context, names []string, orgID string) ([]*models.Role, []string, error) {
+	available := make(map[string]*models.Role)
+	global, err := d.roleRepo.GetGlobalRoles(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, role := range global {
+		if role.IsActive {
+			available[role.Name] = role
+		}
+	}
+	if orgID != "" {
+		orgRoles, err := d.roleRepo.GetByOrganization(ctx, orgID)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, role := range orgRoles {
+			if role.IsActive {
+				available[role.Name] = role
+			}
+		}
+	}
+
+	var roles []*models.Role
+	var missing []string
+	for _, name := range names {
+		if role, ok := available[name]; ok {
+			roles = append(roles, role)
+		} else {
+			missing = append(missing, name)
+		}
+	}
+	return roles, missing, nil
+}
+
+// normalizeRoleNames trims role names and drops empty and repeated ones, keeping their order
+func normalizeRoleNames(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	normalized := make([]string, 0, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		normalized = append(normalized, name)
+	}
+	return normalized
+}
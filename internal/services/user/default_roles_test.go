@@ -0,0 +1,155 @@
+package user
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/requests/users"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rolesUserRepo records the role assignments created together with a user
+type rolesUserRepo struct {
+	phoneUniqueUserRepo
+	userID    string
+	userRoles []*models.UserRole
+}
+
+func (r *rolesUserRepo) CreateWithRoles(ctx context.Context, user *models.User, userRoles []*models.UserRole) error {
+	r.userID = user.ID
+	if err := r.Create(ctx, user); err != nil {
+		return err
+	}
+	r.userRoles = append(r.userRoles, userRoles...)
+	return nil
+}
+
+// fakeRoleRepo serves fixed global and organization roles
+type fakeRoleRepo struct {
+	interfaces.OrganizationRoleRepository
+	global []*models.Role
+	org    map[string][]*models.Role
+}
+
+func (r *fakeRoleRepo) GetGlobalRoles(ctx context.Context) ([]*models.Role, error) {
+	return r.global, nil
+}
+
+func (r *fakeRoleRepo) GetByOrganization(ctx context.Context, organizationID string) ([]*models.Role, error) {
+	return r.org[organizationID], nil
+}
+
+// fakeSettingRepo serves fixed organization settings
+type fakeSettingRepo struct {
+	interfaces.OrganizationSettingRepository
+	settings map[string][]*models.OrganizationSetting
+}
+
+func (r *fakeSettingRepo) ListByOrganization(ctx context.Context, orgID string) ([]*models.OrganizationSetting, error) {
+	return r.settings[orgID], nil
+}
+
+// roleAuditRecorder records audited role operations
+type roleAuditRecorder struct {
+	interfaces.AuditService
+	operations []string
+	roleIDs    []string
+}
+
+func (a *roleAuditRecorder) LogRoleOperation(ctx context.Context, actorUserID, targetUserID, roleID, operation string, success bool, details map[string]interface{}) {
+	a.operations = append(a.operations, operation)
+	a.roleIDs = append(a.roleIDs, roleID)
+}
+
+func newDefaultRolesTestService(t *testing.T, global []string) (*Service, *rolesUserRepo, *fakeRoleRepo) {
+	t.Helper()
+	repo := &rolesUserRepo{}
+	service := newPhoneUniqueTestService(&repo.phoneUniqueUserRepo)
+	service.userRepo = repo
+
+	inactive := models.NewGlobalRole("retired", "")
+	inactive.IsActive = false
+	roles := &fakeRoleRepo{
+		global: []*models.Role{models.NewGlobalRole("farmer", ""), models.NewGlobalRole("viewer", ""), inactive},
+		org: map[string][]*models.Role{
+			"ORG1": {models.NewOrgRole("field_agent", "", "ORG1"), models.NewOrgRole("viewer", "", "ORG1")},
+		},
+	}
+	setting, err := models.NewOrganizationSetting("ORG1", models.OrgSettingDefaultUserRoles, "field_agent, viewer")
+	require.NoError(t, err)
+	settings := &fakeSettingRepo{settings: map[string][]*models.OrganizationSetting{"ORG1": {setting}}}
+
+	service.SetDefaultRoles(global, roles, settings)
+	return service, repo, roles
+}
+
+func TestCreateUser_AssignsDefaultRoles(t *testing.T) {
+	service, repo, roles := newDefaultRolesTestService(t, []string{"farmer", " farmer", "missing"})
+	audit := &roleAuditRecorder{}
+	service.auditService = audit
+
+	_, err := service.CreateUser(context.Background(), &users.CreateUserRequest{PhoneNumber: "9876543210", CountryCode: "+91", Password: "Secret123!"})
+	require.NoError(t, err)
+
+	require.Len(t, repo.userRoles, 1, "missing roles are skipped and repeated ones assigned once")
+	assert.Equal(t, repo.userID, repo.userRoles[0].UserID)
+	assert.Equal(t, roles.global[0].ID, repo.userRoles[0].RoleID)
+	assert.True(t, repo.userRoles[0].IsActive)
+	assert.Equal(t, []string{DefaultRoleOperation}, audit.operations)
+	assert.Equal(t, []string{roles.global[0].ID}, audit.roleIDs)
+}
+
+func TestCreateUser_AssignsOrganizationDefaultRoles(t *testing.T) {
+	service, repo, roles := newDefaultRolesTestService(t, []string{"farmer"})
+
+	_, err := service.CreateUser(context.Background(), &users.CreateUserRequest{
+		PhoneNumber: "9876543210", CountryCode: "+91", Password: "Secret123!", OrganizationID: "ORG1",
+	})
+	require.NoError(t, err)
+
+	var roleIDs []string
+	for _, userRole := range repo.userRoles {
+		roleIDs = append(roleIDs, userRole.RoleID)
+	}
+	// The organization's own viewer role takes the place of the global one
+	assert.Equal(t, []string{roles.global[0].ID, roles.org["ORG1"][0].ID, roles.org["ORG1"][1].ID}, roleIDs)
+}
+
+func TestCreateUser_WithoutDefaultRoles(t *testing.T) {
+	service, repo, _ := newDefaultRolesTestService(t, nil)
+
+	_, err := service.CreateUser(context.Background(), &users.CreateUserRequest{PhoneNumber: "9876543210", CountryCode: "+91", Password: "Secret123!"})
+	require.NoError(t, err)
+	assert.Empty(t, repo.userRoles)
+	assert.Len(t, repo.users, 1)
+}
+
+func TestValidateDefaultRoles(t *testing.T) {
+	service, _, _ := newDefaultRolesTestService(t, []string{"farmer", "retired", "missing"})
+	assert.Equal(t, []string{"retired", "missing"}, service.ValidateDefaultRoles(context.Background()))
+}
+
+func TestCreateUser_ReclaimResetsMPinAndRoles(t *testing.T) {
+	service, repo, roles := newDefaultRolesTestService(t, []string{"farmer"})
+	ctx := context.Background()
+
+	first, err := service.CreateUser(ctx, &users.CreateUserRequest{PhoneNumber: "9876543210", CountryCode: "+91", Password: "Secret123!"})
+	require.NoError(t, err)
+	repo.users[0].SetMPin("old-mpin-hash")
+	repo.softDelete(first.ID)
+
+	restored, err := service.CreateUser(ctx, &users.CreateUserRequest{
+		PhoneNumber: "9876543210", CountryCode: "+91", Password: "NewSecret456!", ReclaimMode: users.ReclaimModeRestore,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, restored.ID)
+
+	require.Len(t, repo.users, 1)
+	assert.False(t, repo.users[0].HasMPin(), "the previous holder's MPIN does not carry over")
+	require.Len(t, repo.restoredRoles, 1, "prior roles are replaced by the default roles")
+	assert.Equal(t, first.ID, repo.restoredRoles[0].UserID)
+	assert.Equal(t, roles.global[0].ID, repo.restoredRoles[0].RoleID)
+}
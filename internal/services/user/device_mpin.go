@@ -0,0 +1,277 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/responses"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Device-bound MPINs
+//
+// A user may give each device its own MPIN. Verification against a device uses that device's MPIN
+// and its own failed-attempt counter, so a lockout or a revocation affects that device only.
+// Devices without an MPIN, and callers that name no device, keep using the global MPIN.
+
+const (
+	// DeviceMPinMaxAttempts is the number of consecutive wrong MPINs after which a device is locked
+	DeviceMPinMaxAttempts = 5
+	// DeviceMPinLockout is how long a device stays locked after too many wrong MPINs
+	DeviceMPinLockout = 15 * time.Minute
+	// maxDeviceIDLength bounds the client-supplied device identifier
+	maxDeviceIDLength = 255
+)
+
+// SetDeviceMPIN sets the MPIN of one device of a user, replacing any MPIN the device already has
+// and clearing its lockout. The user's current password is required.
+func (s *Service) SetDeviceMPIN(ctx context.Context, userID, deviceID, deviceName, mPin, currentPassword string) (*responses.UserDeviceResponse, error) {
+	s.logger.Info("Setting device MPIN", zap.String("user_id", userID), zap.String("device_id", deviceID))
+
+	if s.deviceRepo == nil {
+		return nil, errors.NewInternalError(fmt.Errorf("device MPINs are not enabled"))
+	}
+	if userID == "" || mPin == "" {
+		return nil, errors.NewValidationError("user ID and MPIN are required")
+	}
+	if err := validateDeviceID(deviceID); err != nil {
+		return nil, err
+	}
+	if currentPassword == "" {
+		return nil, errors.NewValidationError("current password is required to set MPIN")
+	}
+	if err := validateMPinFormat(mPin); err != nil {
+		return nil, err
+	}
+
+	ctx = withDeviceID(ctx, deviceID)
+
+	user, err := s.userRepo.GetByID(ctx, userID, &models.User{})
+	if err != nil || user == nil || user.DeletedAt != nil {
+		return nil, errors.NewNotFoundError("user not found")
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(currentPassword)); err != nil {
+		s.logger.Warn("Invalid password during device MPIN setup", zap.String("user_id", userID))
+		s.logDeviceMPinOperation(ctx, userID, "set", false, "invalid password")
+		return nil, errors.NewUnauthorizedError("invalid password")
+	}
+
+	hashedMPin, err := s.hasher().HashMPin(mPin)
+	if err != nil {
+		s.logger.Error("Failed to hash device MPIN", zap.String("user_id", userID), zap.Error(err))
+		return nil, errors.NewInternalError(err)
+	}
+
+	device, err := s.deviceRepo.GetByUserAndDevice(ctx, userID, deviceID)
+	switch {
+	case err == nil:
+		device.MPinHash = hashedMPin
+		device.FailedAttempts = 0
+		device.LockedUntil = nil
+		if deviceName = strings.TrimSpace(deviceName); deviceName != "" {
+			device.DeviceName = deviceName
+		}
+		err = s.deviceRepo.Update(ctx, device)
+	case strings.Contains(err.Error(), "not found"):
+		device = models.NewUserDevice(userID, deviceID, strings.TrimSpace(deviceName), hashedMPin)
+		err = s.deviceRepo.Create(ctx, device)
+	}
+	if err != nil {
+		s.logger.Error("Failed to save device MPIN", zap.String("user_id", userID), zap.Error(err))
+		s.logDeviceMPinOperation(ctx, userID, "set", false, "storage failure")
+		return nil, errors.NewInternalError(err)
+	}
+
+	s.logDeviceMPinOperation(ctx, userID, "set", true, "")
+	s.notifySecurityEvent(ctx, interfaces.SecurityEventMPinChanged, userID, map[string]interface{}{"change": "device_set", "device_id": deviceID})
+	s.logger.Info("Device MPIN set successfully", zap.String("user_id", userID), zap.String("device_id", deviceID))
+	return toUserDeviceResponse(device), nil
+}
+
+// VerifyDeviceMPIN verifies an MPIN entered on a device. A device with its own MPIN is checked
+// against it, counting failures toward that device's lockout; any other device falls back to the
+// user's global MPIN.
+func (s *Service) VerifyDeviceMPIN(ctx context.Context, userID, deviceID, mPin string) error {
+	if s.deviceRepo == nil || deviceID == "" {
+		return s.VerifyMPin(ctx, userID, mPin)
+	}
+	if userID == "" || mPin == "" {
+		return errors.NewValidationError("user ID and mPin are required")
+	}
+
+	device, err := s.deviceRepo.GetByUserAndDevice(ctx, userID, deviceID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return s.VerifyMPin(ctx, userID, mPin)
+		}
+		s.logger.Error("Failed to load device for MPIN verification", zap.String("user_id", userID), zap.Error(err))
+		return errors.NewInternalError(err)
+	}
+
+	ctx = withDeviceID(ctx, deviceID)
+
+	user, err := s.userRepo.GetByID(ctx, userID, &models.User{})
+	if err != nil || user == nil || user.DeletedAt != nil {
+		s.logger.Warn("VerifyDeviceMPIN attempt for missing or deleted user", zap.String("user_id", userID))
+		return errors.NewNotFoundError("user not found")
+	}
+
+	now := time.Now()
+	if device.IsLocked(now) {
+		s.logDeviceMPinOperation(ctx, userID, "verify", false, "device locked")
+		return errors.NewAccountLockedError()
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(device.MPinHash), []byte(mPin)); err != nil {
+		device.FailedAttempts++
+		reason := "invalid mpin"
+		if device.FailedAttempts >= DeviceMPinMaxAttempts {
+			lockedUntil := now.Add(DeviceMPinLockout)
+			device.LockedUntil = &lockedUntil
+			device.FailedAttempts = 0
+			reason = "invalid mpin, device locked"
+			s.logger.Warn("Device MPIN locked after repeated failures",
+				zap.String("user_id", userID),
+				zap.String("device_id", deviceID))
+			s.notifySecurityEvent(ctx, interfaces.SecurityEventAccountLocked, userID, map[string]interface{}{
+				"reason":       "device_mpin_attempts",
+				"device_id":    deviceID,
+				"locked_until": lockedUntil,
+			})
+		}
+		if err := s.deviceRepo.Update(ctx, device); err != nil {
+			s.logger.Error("Failed to record device MPIN failure", zap.String("user_id", userID), zap.Error(err))
+		}
+		s.logDeviceMPinOperation(ctx, userID, "verify", false, reason)
+		return errors.NewUnauthorizedError("invalid mPin")
+	}
+
+	if s.hasher().MPinNeedsRehash(device.MPinHash) {
+		if hashedMPin, err := s.hasher().HashMPin(mPin); err != nil {
+			s.logger.Warn("Failed to rehash device MPIN", zap.String("user_id", userID), zap.Error(err))
+		} else {
+			device.MPinHash = hashedMPin
+		}
+	}
+	device.FailedAttempts = 0
+	device.LockedUntil = nil
+	device.LastUsedAt = &now
+	if err := s.deviceRepo.Update(ctx, device); err != nil {
+		s.logger.Warn("Failed to record device MPIN use", zap.String("user_id", userID), zap.Error(err))
+	}
+	s.logDeviceMPinOperation(ctx, userID, "verify", true, "")
+	return nil
+}
+
+// ListUserDevices lists the devices of a user that have their own MPIN, most recently registered first
+func (s *Service) ListUserDevices(ctx context.Context, userID string) ([]*responses.UserDeviceResponse, error) {
+	if userID == "" {
+		return nil, errors.NewValidationError("user ID is required")
+	}
+
+	result := make([]*responses.UserDeviceResponse, 0)
+	if s.deviceRepo == nil {
+		return result, nil
+	}
+
+	devices, err := s.deviceRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		s.logger.Error("Failed to list user devices", zap.String("user_id", userID), zap.Error(err))
+		return nil, errors.NewInternalError(err)
+	}
+	for _, device := range devices {
+		result = append(result, toUserDeviceResponse(device))
+	}
+	return result, nil
+}
+
+// RevokeDeviceMPIN removes the MPIN of one device. The device falls back to the global MPIN; the
+// user's other devices are unaffected.
+func (s *Service) RevokeDeviceMPIN(ctx context.Context, userID, deviceID string) error {
+	if userID == "" {
+		return errors.NewValidationError("user ID is required")
+	}
+	if err := validateDeviceID(deviceID); err != nil {
+		return err
+	}
+	if s.deviceRepo == nil {
+		return errors.NewNotFoundError("device not found")
+	}
+
+	ctx = withDeviceID(ctx, deviceID)
+
+	device, err := s.deviceRepo.GetByUserAndDevice(ctx, userID, deviceID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return errors.NewNotFoundError("device not found")
+		}
+		return errors.NewInternalError(err)
+	}
+
+	if err := s.deviceRepo.Delete(ctx, device.ID); err != nil {
+		s.logger.Error("Failed to revoke device MPIN", zap.String("user_id", userID), zap.Error(err))
+		s.logDeviceMPinOperation(ctx, userID, "revoke", false, "storage failure")
+		return errors.NewInternalError(err)
+	}
+
+	s.logDeviceMPinOperation(ctx, userID, "revoke", true, "")
+	s.notifySecurityEvent(ctx, interfaces.SecurityEventMPinChanged, userID, map[string]interface{}{"change": "device_revoke", "device_id": deviceID})
+	s.logger.Info("Device MPIN revoked", zap.String("user_id", userID), zap.String("device_id", deviceID))
+	return nil
+}
+
+// logDeviceMPinOperation audits a device MPIN operation; the device ID travels in the context
+func (s *Service) logDeviceMPinOperation(ctx context.Context, userID, operation string, success bool, failureReason string) {
+	if s.auditService == nil {
+		return
+	}
+	ipAddress, _ := ctx.Value("ip_address").(string)
+	userAgent, _ := ctx.Value("user_agent").(string)
+	s.auditService.LogMPINOperation(ctx, userID, operation, ipAddress, userAgent, success, failureReason)
+}
+
+// withDeviceID records the device an MPIN operation concerns for the audit log
+func withDeviceID(ctx context.Context, deviceID string) context.Context {
+	return context.WithValue(ctx, "device_id", deviceID)
+}
+
+func validateDeviceID(deviceID string) error {
+	if strings.TrimSpace(deviceID) == "" {
+		return errors.NewValidationError("device ID is required")
+	}
+	if len(deviceID) > maxDeviceIDLength {
+		return errors.NewValidationError(fmt.Sprintf("device ID must be at most %d characters", maxDeviceIDLength))
+	}
+	return nil
+}
+
+// validateMPinFormat checks that an MPIN is 4-6 digits
+func validateMPinFormat(mPin string) error {
+	if len(mPin) < 4 || len(mPin) > 6 {
+		return errors.NewValidationError("MPIN must be 4-6 digits")
+	}
+	for _, char := range mPin {
+		if char < '0' || char > '9' {
+			return errors.NewValidationError("MPIN must contain only digits")
+		}
+	}
+	return nil
+}
+
+func toUserDeviceResponse(device *models.UserDevice) *responses.UserDeviceResponse {
+	return &responses.UserDeviceResponse{
+		DeviceID:       device.DeviceID,
+		DeviceName:     device.DeviceName,
+		FailedAttempts: device.FailedAttempts,
+		LockedUntil:    device.LockedUntil,
+		LastUsedAt:     device.LastUsedAt,
+		CreatedAt:      device.CreatedAt,
+	}
+}
@@ -0,0 +1,234 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// deviceTestUserRepo serves a single user and counts its updates
+type deviceTestUserRepo struct {
+	interfaces.UserRepository
+	user    *models.User
+	updates int
+}
+
+func (r *deviceTestUserRepo) Update(ctx context.Context, user *models.User) error {
+	r.user = user
+	r.updates++
+	return nil
+}
+
+func (r *deviceTestUserRepo) GetByID(ctx context.Context, id string, user *models.User) (*models.User, error) {
+	if r.user == nil || r.user.ID != id {
+		return nil, fmt.Errorf("user not found")
+	}
+	return r.user, nil
+}
+
+// memoryDeviceRepo stores devices in memory
+type memoryDeviceRepo struct {
+	devices []*models.UserDevice
+}
+
+func (r *memoryDeviceRepo) Create(ctx context.Context, device *models.UserDevice) error {
+	device.ID = fmt.Sprintf("USR_DEV%d", len(r.devices)+1)
+	r.devices = append(r.devices, device)
+	return nil
+}
+
+func (r *memoryDeviceRepo) Update(ctx context.Context, device *models.UserDevice) error { return nil }
+
+func (r *memoryDeviceRepo) GetByUserAndDevice(ctx context.Context, userID, deviceID string) (*models.UserDevice, error) {
+	for _, d := range r.devices {
+		if d.UserID == userID && d.DeviceID == deviceID {
+			return d, nil
+		}
+	}
+	return nil, fmt.Errorf("device not found: %s", deviceID)
+}
+
+func (r *memoryDeviceRepo) ListByUserID(ctx context.Context, userID string) ([]*models.UserDevice, error) {
+	var devices []*models.UserDevice
+	for _, d := range r.devices {
+		if d.UserID == userID {
+			devices = append(devices, d)
+		}
+	}
+	return devices, nil
+}
+
+func (r *memoryDeviceRepo) Delete(ctx context.Context, id string) error {
+	for i, d := range r.devices {
+		if d.ID == id {
+			r.devices = append(r.devices[:i], r.devices[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("device not found")
+}
+
+// mpinAuditRecorder records MPIN audit events with their device
+type mpinAuditRecorder struct {
+	interfaces.AuditService
+	events []string
+}
+
+func (a *mpinAuditRecorder) LogMPINOperation(ctx context.Context, userID, operation, ipAddress, userAgent string, success bool, failureReason string) {
+	deviceID, _ := ctx.Value("device_id").(string)
+	a.events = append(a.events, fmt.Sprintf("%s@%s:%t", operation, deviceID, success))
+}
+
+func newDeviceMPinTestService(t *testing.T) (*Service, *memoryDeviceRepo, *mpinAuditRecorder) {
+	t.Helper()
+	password, err := bcrypt.GenerateFromPassword([]byte("Secret123!"), bcrypt.MinCost)
+	require.NoError(t, err)
+	globalMPin, err := bcrypt.GenerateFromPassword([]byte("1111"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	user := models.NewUser("9876543210", "+91", string(password))
+	user.ID = "USER1"
+	user.SetMPin(string(globalMPin))
+
+	devices := &memoryDeviceRepo{}
+	audit := &mpinAuditRecorder{}
+	service := &Service{
+		userRepo:  &deviceTestUserRepo{user: user},
+		logger:    zap.NewNop(),
+		validator: noopValidator{},
+	}
+	service.SetDeviceMPinSupport(devices, audit)
+	return service, devices, audit
+}
+
+func TestSetDeviceMPIN(t *testing.T) {
+	service, devices, audit := newDeviceMPinTestService(t)
+	ctx := context.Background()
+
+	_, err := service.SetDeviceMPIN(ctx, "USER1", "phone", "Pixel 8", "2222", "wrong-password")
+	assert.True(t, errors.IsUnauthorizedError(err))
+
+	_, err = service.SetDeviceMPIN(ctx, "USER1", "", "", "2222", "Secret123!")
+	assert.True(t, errors.IsValidationError(err))
+
+	device, err := service.SetDeviceMPIN(ctx, "USER1", "phone", "Pixel 8", "2222", "Secret123!")
+	require.NoError(t, err)
+	assert.Equal(t, "phone", device.DeviceID)
+	assert.Equal(t, "Pixel 8", device.DeviceName)
+
+	// Setting it again replaces the MPIN of the same device
+	_, err = service.SetDeviceMPIN(ctx, "USER1", "phone", "", "3333", "Secret123!")
+	require.NoError(t, err)
+	require.Len(t, devices.devices, 1)
+	assert.Equal(t, "Pixel 8", devices.devices[0].DeviceName)
+	assert.NoError(t, service.VerifyDeviceMPIN(ctx, "USER1", "phone", "3333"))
+
+	assert.Equal(t, []string{"set@phone:false", "set@phone:true", "set@phone:true", "verify@phone:true"}, audit.events)
+}
+
+func TestVerifyDeviceMPIN_FallsBackToGlobalMPIN(t *testing.T) {
+	service, _, _ := newDeviceMPinTestService(t)
+	ctx := context.Background()
+
+	_, err := service.SetDeviceMPIN(ctx, "USER1", "phone", "", "2222", "Secret123!")
+	require.NoError(t, err)
+
+	assert.NoError(t, service.VerifyDeviceMPIN(ctx, "USER1", "phone", "2222"))
+	assert.Error(t, service.VerifyDeviceMPIN(ctx, "USER1", "phone", "1111"), "a device with its own MPIN does not accept the global one")
+
+	assert.NoError(t, service.VerifyDeviceMPIN(ctx, "USER1", "tablet", "1111"), "devices without an MPIN use the global one")
+	assert.NoError(t, service.VerifyDeviceMPIN(ctx, "USER1", "", "1111"))
+	assert.NoError(t, service.VerifyMPin(ctx, "USER1", "1111"), "the single-MPIN path is unchanged")
+}
+
+func TestVerifyDeviceMPIN_LockoutIsPerDevice(t *testing.T) {
+	service, devices, _ := newDeviceMPinTestService(t)
+	ctx := context.Background()
+
+	_, err := service.SetDeviceMPIN(ctx, "USER1", "phone", "", "2222", "Secret123!")
+	require.NoError(t, err)
+	_, err = service.SetDeviceMPIN(ctx, "USER1", "tablet", "", "4444", "Secret123!")
+	require.NoError(t, err)
+
+	for i := 0; i < DeviceMPinMaxAttempts; i++ {
+		assert.True(t, errors.IsUnauthorizedError(service.VerifyDeviceMPIN(ctx, "USER1", "phone", "9999")))
+	}
+	assert.NotNil(t, devices.devices[0].LockedUntil)
+
+	err = service.VerifyDeviceMPIN(ctx, "USER1", "phone", "2222")
+	assert.Error(t, err, "the locked device rejects even the right MPIN")
+	assert.NoError(t, service.VerifyDeviceMPIN(ctx, "USER1", "tablet", "4444"), "other devices are not locked")
+
+	// Setting a new MPIN clears the lockout
+	_, err = service.SetDeviceMPIN(ctx, "USER1", "phone", "", "5555", "Secret123!")
+	require.NoError(t, err)
+	assert.NoError(t, service.VerifyDeviceMPIN(ctx, "USER1", "phone", "5555"))
+}
+
+// recordedNotifier keeps the security events it is asked to send
+type recordedNotifier struct {
+	events []interfaces.SecurityEvent
+}
+
+func (n *recordedNotifier) NotifySecurityEvent(ctx context.Context, event interfaces.SecurityEvent) {
+	n.events = append(n.events, event)
+}
+
+func TestVerifyDeviceMPIN_LockoutNotifiesUser(t *testing.T) {
+	service, _, _ := newDeviceMPinTestService(t)
+	notifier := &recordedNotifier{}
+	service.SetSecurityNotifier(notifier)
+	ctx := context.WithValue(context.Background(), "user_agent", "KisanlinkApp/2.1")
+
+	_, err := service.SetDeviceMPIN(ctx, "USER1", "phone", "", "2222", "Secret123!")
+	require.NoError(t, err)
+	notifier.events = nil
+
+	for i := 0; i < DeviceMPinMaxAttempts-1; i++ {
+		_ = service.VerifyDeviceMPIN(ctx, "USER1", "phone", "9999")
+	}
+	assert.Empty(t, notifier.events, "failures short of the lockout are not reported")
+
+	_ = service.VerifyDeviceMPIN(ctx, "USER1", "phone", "9999")
+	require.Len(t, notifier.events, 1)
+	event := notifier.events[0]
+	assert.Equal(t, interfaces.SecurityEventAccountLocked, event.Type)
+	assert.Equal(t, "USER1", event.UserID)
+	assert.Equal(t, "KisanlinkApp/2.1", event.UserAgent)
+	assert.Equal(t, "phone", event.Details["device_id"])
+
+	// Attempts on the locked device do not notify again
+	_ = service.VerifyDeviceMPIN(ctx, "USER1", "phone", "2222")
+	assert.Len(t, notifier.events, 1)
+}
+
+func TestRevokeDeviceMPIN(t *testing.T) {
+	service, _, audit := newDeviceMPinTestService(t)
+	ctx := context.Background()
+
+	_, err := service.SetDeviceMPIN(ctx, "USER1", "phone", "", "2222", "Secret123!")
+	require.NoError(t, err)
+	_, err = service.SetDeviceMPIN(ctx, "USER1", "tablet", "", "4444", "Secret123!")
+	require.NoError(t, err)
+
+	require.NoError(t, service.RevokeDeviceMPIN(ctx, "USER1", "phone"))
+	assert.Contains(t, audit.events, "revoke@phone:true")
+
+	assert.NoError(t, service.VerifyDeviceMPIN(ctx, "USER1", "phone", "1111"), "a revoked device falls back to the global MPIN")
+	assert.NoError(t, service.VerifyDeviceMPIN(ctx, "USER1", "tablet", "4444"))
+
+	listed, err := service.ListUserDevices(ctx, "USER1")
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+	assert.Equal(t, "tablet", listed[0].DeviceID)
+
+	assert.True(t, errors.IsNotFoundError(service.RevokeDeviceMPIN(ctx, "USER1", "phone")))
+}
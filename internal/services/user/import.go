@@ -0,0 +1,413 @@
+package user
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/requests/users"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/internal/utils/actor"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/Kisanlink/aaa-service/v2/pkg/phonenumber"
+	"go.uber.org/zap"
+)
+
+// CSV user import
+//
+// Rows are read one at a time and validated as they arrive. Valid rows are created in chunks of
+// UserImportChunkSize with CreateMany, and each chunk's rows are written to the result CSV as soon
+// as it is stored, so memory stays bounded by the chunk size rather than the file size. A chunk
+// that fails as a whole is retried row by row so one bad row does not fail its neighbours.
+
+const (
+	// UserImportChunkSize is the number of rows validated before they are created and written out together
+	UserImportChunkSize = 100
+	// defaultImportCountryCode is used for rows without a country code
+	defaultImportCountryCode = "+91"
+)
+
+// Fields a CSV column can be mapped to
+const (
+	importFieldPhoneNumber        = "phone_number"
+	importFieldCountryCode        = "country_code"
+	importFieldPassword           = "password"
+	importFieldUsername           = "username"
+	importFieldMustChangePassword = "must_change_password"
+)
+
+var userImportFields = []string{
+	importFieldPhoneNumber,
+	importFieldCountryCode,
+	importFieldPassword,
+	importFieldUsername,
+	importFieldMustChangePassword,
+}
+
+// userImportResultColumns are appended to the input columns in the result CSV
+var userImportResultColumns = []string{"status", "user_id", "error"}
+
+// importRow is one data row of the file with its outcome
+type importRow struct {
+	number int
+	record []string
+	user   *models.User
+	status string
+	userID string
+	err    string
+}
+
+// userImport holds the state of one import while it streams
+type userImport struct {
+	service  *Service
+	opts     interfaces.UserImportOptions
+	columns  map[string]int
+	width    int
+	writer   *csv.Writer
+	summary  *interfaces.UserImportSummary
+	chunk    []*importRow
+	phones   map[string]int // canonical phone number to the row that first used it
+	username map[string]int // lower-cased username to the row that first used it
+}
+
+// ImportUsersCSV creates users from a CSV stream and writes a result CSV that repeats every input
+// row followed by its status, the created user's ID and any error. The first row is the header;
+// columns are matched to fields by name unless opts.Mapping maps the header to a field. In dry-run
+// mode rows are validated, including duplicate checks, but nothing is written. Errors about the
+// header are returned before any output is written; row errors are reported in the result CSV.
+func (s *Service) ImportUsersCSV(ctx context.Context, in io.Reader, out io.Writer, opts interfaces.UserImportOptions) (*interfaces.UserImportSummary, error) {
+	s.logger.Info("Importing users from CSV", zap.Bool("dry_run", opts.DryRun))
+
+	reader := csv.NewReader(in)
+	reader.FieldsPerRecord = -1 // Rows with a different column count are reported, not fatal
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, errors.NewValidationError("CSV file is empty")
+	}
+	if err != nil {
+		return nil, errors.NewValidationError("invalid CSV header", err.Error())
+	}
+
+	columns, err := mapImportColumns(header, opts.Mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	imp := &userImport{
+		service:  s,
+		opts:     opts,
+		columns:  columns,
+		width:    len(header),
+		writer:   csv.NewWriter(out),
+		summary:  &interfaces.UserImportSummary{DryRun: opts.DryRun},
+		chunk:    make([]*importRow, 0, UserImportChunkSize),
+		phones:   make(map[string]int),
+		username: make(map[string]int),
+	}
+
+	if err := imp.writer.Write(append(append([]string{}, header...), userImportResultColumns...)); err != nil {
+		return nil, errors.NewInternalError(err)
+	}
+
+	// Rows are numbered as in a spreadsheet, the header being row 1
+	for rowNumber := 2; ; rowNumber++ {
+		if err := ctx.Err(); err != nil {
+			_ = imp.flush(ctx)
+			return imp.summary, err
+		}
+
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+
+		row := &importRow{number: rowNumber, record: record}
+		if err != nil {
+			// A malformed row is skipped by the reader, so the import carries on with the next one
+			row.status = interfaces.UserImportStatusInvalid
+			row.err = fmt.Sprintf("malformed CSV row: %v", err)
+		} else {
+			imp.validate(ctx, row)
+		}
+
+		imp.chunk = append(imp.chunk, row)
+		if len(imp.chunk) >= UserImportChunkSize {
+			if err := imp.flush(ctx); err != nil {
+				return imp.summary, errors.NewInternalError(err)
+			}
+		}
+	}
+
+	if err := imp.flush(ctx); err != nil {
+		return imp.summary, errors.NewInternalError(err)
+	}
+
+	s.logger.Info("CSV user import completed",
+		zap.Bool("dry_run", opts.DryRun),
+		zap.Int("rows", imp.summary.Rows),
+		zap.Int("created", imp.summary.Created),
+		zap.Int("valid", imp.summary.Valid),
+		zap.Int("duplicates", imp.summary.Duplicates),
+		zap.Int("invalid", imp.summary.Invalid),
+		zap.Int("failed", imp.summary.Failed))
+
+	return imp.summary, nil
+}
+
+// validate checks one row and, outside dry-run mode, prepares the user to create
+func (imp *userImport) validate(ctx context.Context, row *importRow) {
+	if len(row.record) != imp.width {
+		row.status = interfaces.UserImportStatusInvalid
+		row.err = fmt.Sprintf("expected %d columns, got %d", imp.width, len(row.record))
+		return
+	}
+
+	req := &users.CreateUserRequest{
+		PhoneNumber: imp.cell(row, importFieldPhoneNumber),
+		CountryCode: imp.cell(row, importFieldCountryCode),
+		Password:    imp.cell(row, importFieldPassword),
+	}
+	if req.CountryCode == "" {
+		req.CountryCode = defaultImportCountryCode
+	}
+	if username := imp.cell(row, importFieldUsername); username != "" {
+		req.Username = &username
+	}
+	if value := imp.cell(row, importFieldMustChangePassword); value != "" {
+		mustChange, err := strconv.ParseBool(value)
+		if err != nil {
+			row.status = interfaces.UserImportStatusInvalid
+			row.err = "must_change_password must be true or false"
+			return
+		}
+		req.MustChangePassword = mustChange
+	}
+
+	if err := req.Validate(); err != nil {
+		row.status = interfaces.UserImportStatusInvalid
+		row.err = err.Error()
+		return
+	}
+	phone, err := phonenumber.Parse(req.PhoneNumber, req.CountryCode)
+	if err != nil {
+		row.status = interfaces.UserImportStatusInvalid
+		row.err = err.Error()
+		return
+	}
+
+	// Duplicates within the file are reported against the first row that used the value
+	phoneKey := phone.CountryCode + phone.NationalNumber
+	if first, seen := imp.phones[phoneKey]; seen {
+		row.status = interfaces.UserImportStatusDuplicate
+		row.err = fmt.Sprintf("phone number already used on row %d", first)
+		return
+	}
+	usernameKey := ""
+	if req.Username != nil {
+		usernameKey = strings.ToLower(*req.Username)
+		if first, seen := imp.username[usernameKey]; seen {
+			row.status = interfaces.UserImportStatusDuplicate
+			row.err = fmt.Sprintf("username already used on row %d", first)
+			return
+		}
+	}
+	imp.phones[phoneKey] = row.number
+	if usernameKey != "" {
+		imp.username[usernameKey] = row.number
+	}
+
+	s := imp.service
+	if existing, err := s.userRepo.GetByPhoneNumber(ctx, phone.NationalNumber, phone.CountryCode); err == nil && existing != nil {
+		row.status = interfaces.UserImportStatusDuplicate
+		row.err = "a user with this phone number already exists"
+		return
+	}
+	if req.Username != nil {
+		if taken, err := s.usernameTaken(ctx, *req.Username); err == nil && taken {
+			row.status = interfaces.UserImportStatusDuplicate
+			row.err = "username is already taken"
+			return
+		}
+	}
+
+	if imp.opts.DryRun {
+		row.status = interfaces.UserImportStatusValid
+		return
+	}
+
+	hashedPassword, err := s.hashPassword(req.Password)
+	if err != nil {
+		s.logger.Error("Failed to hash imported password", zap.Int("row", row.number), zap.Error(err))
+		row.status = interfaces.UserImportStatusFailed
+		row.err = "failed to hash password"
+		return
+	}
+
+	if req.Username != nil {
+		row.user = models.NewUserWithUsername(phone.NationalNumber, phone.CountryCode, *req.Username, hashedPassword)
+	} else {
+		row.user = models.NewUser(phone.NationalNumber, phone.CountryCode, hashedPassword)
+	}
+	row.user.SetPhone(phone)
+	row.user.MustChangePassword = req.MustChangePassword
+	actor.StampCreate(ctx, row.user)
+}
+
+// flush creates the chunk's pending users and writes the chunk's rows to the result
+func (imp *userImport) flush(ctx context.Context) error {
+	if len(imp.chunk) == 0 {
+		return nil
+	}
+	s := imp.service
+
+	var pending []*importRow
+	var pendingUsers []*models.User
+	for _, row := range imp.chunk {
+		if row.user != nil && row.status == "" {
+			pending = append(pending, row)
+			pendingUsers = append(pendingUsers, row.user)
+		}
+	}
+
+	if len(pendingUsers) > 0 {
+		if err := s.userRepo.CreateMany(ctx, pendingUsers); err != nil {
+			s.logger.Warn("Chunk create failed, retrying rows one by one", zap.Int("rows", len(pending)), zap.Error(err))
+			for _, row := range pending {
+				imp.createOne(ctx, row)
+			}
+		} else {
+			for _, row := range pending {
+				row.status = interfaces.UserImportStatusCreated
+				row.userID = row.user.ID
+			}
+		}
+	}
+
+	for _, row := range imp.chunk {
+		imp.count(row)
+		record := make([]string, imp.width, imp.width+len(userImportResultColumns))
+		copy(record, row.record)
+		record = append(record, row.status, row.userID, row.err)
+		if err := imp.writer.Write(record); err != nil {
+			return err
+		}
+	}
+	imp.chunk = imp.chunk[:0]
+
+	imp.writer.Flush()
+	return imp.writer.Error()
+}
+
+// createOne creates a single row's user after its chunk failed to insert as a whole
+func (imp *userImport) createOne(ctx context.Context, row *importRow) {
+	if err := imp.service.userRepo.Create(ctx, row.user); err != nil {
+		if strings.Contains(err.Error(), models.UsersPhoneActiveUniqueIndex) ||
+			strings.Contains(err.Error(), "duplicate key") ||
+			strings.Contains(err.Error(), "unique constraint") {
+			row.status = interfaces.UserImportStatusDuplicate
+			row.err = "user with this information already exists"
+			return
+		}
+		imp.service.logger.Error("Failed to create imported user", zap.Int("row", row.number), zap.Error(err))
+		row.status = interfaces.UserImportStatusFailed
+		row.err = "failed to create user"
+		return
+	}
+	row.status = interfaces.UserImportStatusCreated
+	row.userID = row.user.ID
+}
+
+func (imp *userImport) count(row *importRow) {
+	imp.summary.Rows++
+	switch row.status {
+	case interfaces.UserImportStatusCreated:
+		imp.summary.Created++
+	case interfaces.UserImportStatusValid:
+		imp.summary.Valid++
+	case interfaces.UserImportStatusDuplicate:
+		imp.summary.Duplicates++
+	case interfaces.UserImportStatusInvalid:
+		imp.summary.Invalid++
+	default:
+		imp.summary.Failed++
+	}
+}
+
+// cell returns the trimmed value of a field in a row, or "" when the file has no column for it
+func (imp *userImport) cell(row *importRow, field string) string {
+	index, ok := imp.columns[field]
+	if !ok {
+		return ""
+	}
+	return strings.TrimSpace(row.record[index])
+}
+
+// mapImportColumns resolves the column index of every field. A header listed in mapping is mapped
+// to the named field; any other header is used when its normalized name is a field. Unmapped
+// columns are ignored but kept in the result.
+func mapImportColumns(header []string, mapping map[string]string) (map[string]int, error) {
+	normalizedMapping := make(map[string]string, len(mapping))
+	for column, field := range mapping {
+		field = normalizeImportHeader(field)
+		if !isUserImportField(field) {
+			return nil, errors.NewValidationError(fmt.Sprintf("mapping of column %q names unknown field %q; fields are %s",
+				column, field, strings.Join(userImportFields, ", ")))
+		}
+		normalizedMapping[normalizeImportHeader(column)] = field
+	}
+
+	columns := make(map[string]int)
+	matched := make(map[string]bool)
+	for i, name := range header {
+		name = normalizeImportHeader(strings.TrimPrefix(name, "\ufeff"))
+		field, mapped := normalizedMapping[name]
+		if mapped {
+			matched[name] = true
+		} else if isUserImportField(name) {
+			field = name
+		} else {
+			continue
+		}
+		if _, taken := columns[field]; taken {
+			return nil, errors.NewValidationError(fmt.Sprintf("more than one column maps to field %q", field))
+		}
+		columns[field] = i
+	}
+
+	for column := range normalizedMapping {
+		if !matched[column] {
+			return nil, errors.NewValidationError(fmt.Sprintf("mapped column %q is not in the CSV header", column))
+		}
+	}
+	for _, field := range []string{importFieldPhoneNumber, importFieldPassword} {
+		if _, ok := columns[field]; !ok {
+			return nil, errors.NewValidationError(fmt.Sprintf("CSV header has no column for required field %q", field))
+		}
+	}
+
+	return columns, nil
+}
+
+// normalizeImportHeader lower-cases a header and joins its words with underscores, so "Phone Number"
+// matches phone_number
+func normalizeImportHeader(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	return strings.Join(strings.FieldsFunc(name, func(r rune) bool {
+		return r == ' ' || r == '-' || r == '_'
+	}), "_")
+}
+
+func isUserImportField(name string) bool {
+	for _, field := range userImportFields {
+		if field == name {
+			return true
+		}
+	}
+	return false
+}
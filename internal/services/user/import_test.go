@@ -0,0 +1,206 @@
+package user
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"strings"
+	"testing"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// importUserRepo adds an all-or-nothing CreateMany to the phone-unique store
+type importUserRepo struct {
+	*phoneUniqueUserRepo
+	chunks int
+}
+
+func (r *importUserRepo) CreateMany(ctx context.Context, users []*models.User) error {
+	r.chunks++
+	for _, u := range users {
+		if _, err := r.GetByPhoneNumber(ctx, u.PhoneNumber, u.CountryCode); err == nil {
+			return r.Create(ctx, u) // reports the unique index violation
+		}
+	}
+	for _, u := range users {
+		if err := r.Create(ctx, u); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newImportTestService(existing ...*models.User) (*Service, *importUserRepo) {
+	repo := &importUserRepo{phoneUniqueUserRepo: &phoneUniqueUserRepo{users: existing}}
+	return &Service{userRepo: repo, logger: zap.NewNop(), validator: noopValidator{}}, repo
+}
+
+func readImportResult(t *testing.T, out *bytes.Buffer) [][]string {
+	t.Helper()
+	reader := csv.NewReader(out)
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	require.NoError(t, err)
+	return records
+}
+
+func TestImportUsersCSV(t *testing.T) {
+	existing := models.NewUser("9000000001", "+91", "hash")
+	existing.ID = "USER0"
+	service, repo := newImportTestService(existing)
+
+	input := strings.Join([]string{
+		"phone_number,password,username,note",
+		"9876543210,Secret123!,alice,first",
+		"98765 43211,Secret123!,,second",
+		"not-a-phone,Secret123!,,bad phone",
+		"9876543210,Secret123!,,same phone as row 2",
+		"9000000001,Secret123!,,already registered",
+		"9876543212,short,,weak password",
+		"9876543213,Secret123!,ALICE,same username as row 2",
+		"9876543214,Secret123!",
+		`9876543215,"Secret"123!,,bare quote`,
+		"9876543216,Secret123!,bob,last",
+	}, "\n")
+
+	var out bytes.Buffer
+	summary, err := service.ImportUsersCSV(context.Background(), strings.NewReader(input), &out, interfaces.UserImportOptions{})
+	require.NoError(t, err)
+
+	records := readImportResult(t, &out)
+	require.Len(t, records, 11)
+	assert.Equal(t, []string{"phone_number", "password", "username", "note", "status", "user_id", "error"}, records[0])
+
+	statuses := make([]string, 0, 10)
+	for _, record := range records[1:] {
+		statuses = append(statuses, record[len(record)-3])
+	}
+	assert.Equal(t, []string{
+		interfaces.UserImportStatusCreated,
+		interfaces.UserImportStatusCreated,
+		interfaces.UserImportStatusInvalid,
+		interfaces.UserImportStatusDuplicate,
+		interfaces.UserImportStatusDuplicate,
+		interfaces.UserImportStatusInvalid,
+		interfaces.UserImportStatusDuplicate,
+		interfaces.UserImportStatusInvalid,
+		interfaces.UserImportStatusInvalid,
+		interfaces.UserImportStatusCreated,
+	}, statuses)
+
+	assert.Equal(t, "first", records[1][3], "input columns are kept")
+	assert.NotEmpty(t, records[1][5], "created rows carry the user ID")
+	assert.Equal(t, "phone number already used on row 2", records[4][6])
+	assert.Equal(t, "username already used on row 2", records[7][6])
+	assert.Contains(t, records[8][6], "expected 4 columns")
+	assert.Contains(t, records[9][6], "malformed CSV row")
+
+	assert.Equal(t, 10, summary.Rows)
+	assert.Equal(t, 3, summary.Created)
+	assert.Equal(t, 3, summary.Duplicates)
+	assert.Equal(t, 4, summary.Invalid)
+	assert.Equal(t, 1, repo.chunks, "rows are created in one chunk")
+	assert.Len(t, repo.users, 4)
+}
+
+func TestImportUsersCSV_DryRunWithMapping(t *testing.T) {
+	service, repo := newImportTestService()
+
+	input := "Mobile,Country,Initial Password,Must Change Password\n" +
+		"9876543210,+91,Secret123!,true\n" +
+		"9876543210,+91,Secret123!,true\n" +
+		"9876543211,+91,Secret123!,maybe\n"
+
+	var out bytes.Buffer
+	summary, err := service.ImportUsersCSV(context.Background(), strings.NewReader(input), &out, interfaces.UserImportOptions{
+		DryRun: true,
+		Mapping: map[string]string{
+			"Mobile":           "phone_number",
+			"country":          "country_code",
+			"Initial Password": "password",
+		},
+	})
+	require.NoError(t, err)
+
+	records := readImportResult(t, &out)
+	require.Len(t, records, 4)
+	assert.Equal(t, interfaces.UserImportStatusValid, records[1][4])
+	assert.Empty(t, records[1][5], "nothing is created in a dry run")
+	assert.Equal(t, interfaces.UserImportStatusDuplicate, records[2][4], "duplicates are found in a dry run too")
+	assert.Equal(t, interfaces.UserImportStatusInvalid, records[3][4])
+
+	assert.True(t, summary.DryRun)
+	assert.Equal(t, 1, summary.Valid)
+	assert.Zero(t, repo.chunks)
+	assert.Empty(t, repo.users)
+}
+
+func TestImportUsersCSV_ChunkFailureFallsBackToRows(t *testing.T) {
+	service, repo := newImportTestService()
+
+	var input strings.Builder
+	input.WriteString("phone_number,password\n")
+	input.WriteString("9876543210,Secret123!\n")
+	input.WriteString("9876543211,Secret123!\n")
+
+	// Another writer registers the first number after it was validated
+	ctx := context.Background()
+	var out bytes.Buffer
+	interleaved := &racingReader{Reader: strings.NewReader(input.String()), onEOF: func() {
+		_ = repo.phoneUniqueUserRepo.Create(ctx, models.NewUser("9876543210", "+91", "hash"))
+	}}
+
+	summary, err := service.ImportUsersCSV(ctx, interleaved, &out, interfaces.UserImportOptions{})
+	require.NoError(t, err)
+
+	records := readImportResult(t, &out)
+	assert.Equal(t, interfaces.UserImportStatusDuplicate, records[1][2])
+	assert.Equal(t, interfaces.UserImportStatusCreated, records[2][2], "the rest of the chunk is still created")
+	assert.Equal(t, 1, summary.Created)
+}
+
+func TestImportUsersCSV_RejectsBadHeader(t *testing.T) {
+	service, _ := newImportTestService()
+
+	tests := []struct {
+		name    string
+		input   string
+		mapping map[string]string
+	}{
+		{"empty file", "", nil},
+		{"missing password column", "phone_number,username\n9876543210,alice\n", nil},
+		{"mapping to unknown field", "Mobile,password\n", map[string]string{"Mobile": "mobile"}},
+		{"mapping of absent column", "phone_number,password\n", map[string]string{"Mobile": "phone_number"}},
+		{"two columns for one field", "phone_number,Phone Number,password\n", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			_, err := service.ImportUsersCSV(context.Background(), strings.NewReader(tt.input), &out, interfaces.UserImportOptions{Mapping: tt.mapping})
+			assert.True(t, errors.IsValidationError(err), "got %v", err)
+			assert.Zero(t, out.Len(), "nothing is written for a bad header")
+		})
+	}
+}
+
+// racingReader runs onEOF once the input is exhausted, before the final chunk is created
+type racingReader struct {
+	*strings.Reader
+	onEOF func()
+	done  bool
+}
+
+func (r *racingReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	if err != nil && !r.done {
+		r.done = true
+		r.onEOF()
+	}
+	return n, err
+}
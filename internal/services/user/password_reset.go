@@ -8,6 +8,7 @@ import (
 
 	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
 	userResponses "github.com/Kisanlink/aaa-service/v2/internal/entities/responses/users"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
 	"github.com/Kisanlink/aaa-service/v2/internal/repositories/users"
 	"github.com/Kisanlink/aaa-service/v2/internal/services/sms"
 	"github.com/Kisanlink/kisanlink-db/pkg/base"
@@ -41,7 +42,12 @@ func (s *Service) SetPasswordResetRepository(repo *users.PasswordResetRepository
 
 // InitiatePasswordReset creates a password reset OTP and sends it via SMS
 // Returns the token ID (not the OTP) for use in the reset step
+// Once SetCredentialReset is called the OTP goes to a verified contact instead; see CompleteReset
 func (s *Service) InitiatePasswordReset(ctx context.Context, phoneNumber, countryCode, username, email *string) (string, error) {
+	if s.resetOTP != nil {
+		return s.initiateCredentialReset(ctx, credentialPassword, phoneNumber, countryCode, username, email)
+	}
+
 	s.logger.Info("Initiating password reset",
 		zap.Any("phone", phoneNumber),
 		zap.Any("username", username),
@@ -138,7 +144,12 @@ func (s *Service) InitiatePasswordReset(ctx context.Context, phoneNumber, countr
 // tokenID: The ID returned from InitiatePasswordReset
 // otp: The 6-digit OTP sent via SMS
 // newPassword: The new password to set
+// Once SetCredentialReset is called this completes the password resets it starts
 func (s *Service) ResetPassword(ctx context.Context, tokenID, otp, newPassword string) error {
+	if s.resetOTP != nil {
+		return s.completeCredentialReset(ctx, credentialPassword, tokenID, otp, newPassword)
+	}
+
 	s.logger.Info("Processing password reset", zap.String("token_id", tokenID))
 
 	// Validate inputs
@@ -198,14 +209,14 @@ func (s *Service) ResetPassword(ctx context.Context, tokenID, otp, newPassword s
 	}
 
 	// Hash new password
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	hashedPassword, err := s.hasher().HashPassword(newPassword)
 	if err != nil {
 		s.logger.Error("Failed to hash password", zap.Error(err))
 		return fmt.Errorf("failed to process password")
 	}
 
 	// Update user password and clear force-change flag
-	user.Password = string(hashedPassword)
+	user.Password = hashedPassword
 	user.MustChangePassword = false
 	if err := s.userRepo.Update(ctx, user); err != nil {
 		s.logger.Error("Failed to update password", zap.Error(err))
@@ -218,6 +229,8 @@ func (s *Service) ResetPassword(ctx context.Context, tokenID, otp, newPassword s
 		// Don't fail the operation, password was already updated
 	}
 
+	s.notifySecurityEvent(ctx, interfaces.SecurityEventPasswordChanged, user.ID, map[string]interface{}{"method": "reset"})
+
 	s.logger.Info("Password reset successful", zap.String("user_id", user.ID))
 	return nil
 }
@@ -271,14 +284,14 @@ func (s *Service) ResetPasswordWithToken(ctx context.Context, token, newPassword
 	}
 
 	// Hash new password
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	hashedPassword, err := s.hasher().HashPassword(newPassword)
 	if err != nil {
 		s.logger.Error("Failed to hash password", zap.Error(err))
 		return fmt.Errorf("failed to process password")
 	}
 
 	// Update user password and clear force-change flag
-	user.Password = string(hashedPassword)
+	user.Password = hashedPassword
 	user.MustChangePassword = false
 	if err := s.userRepo.Update(ctx, user); err != nil {
 		s.logger.Error("Failed to update password", zap.Error(err))
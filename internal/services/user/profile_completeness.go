@@ -0,0 +1,108 @@
+package user
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/config"
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	userResponses "github.com/Kisanlink/aaa-service/v2/internal/entities/responses/users"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// SetProfileCompletenessWeights sets the weights of the profile completeness items, keyed by the
+// config.ProfileItem* names; config.DefaultProfileCompletenessWeights is used when unset
+func (s *Service) SetProfileCompletenessWeights(weights map[string]int) {
+	s.completenessWeights = weights
+}
+
+// GetProfileCompleteness scores how complete a user's profile is. Each item weighs as configured:
+// the name, date or year of birth and photo of the profile, a verified active contact, an address
+// with a pincode or full address on the profile or a contact, and a verified Aadhaar. The score is
+// the weight of the completed items as a percentage of the total, rounded down, so only a complete
+// profile scores 100.
+func (s *Service) GetProfileCompleteness(ctx context.Context, userID string) (*userResponses.ProfileCompletenessResponse, error) {
+	if userID == "" {
+		return nil, errors.NewValidationError("user ID cannot be empty")
+	}
+
+	user, err := s.userRepo.GetWithDetails(ctx, userID, true, true, true, false)
+	if err != nil {
+		s.logger.Error("Failed to get user for profile completeness", zap.String("user_id", userID), zap.Error(err))
+		return nil, errors.NewNotFoundError("user not found")
+	}
+	if user.DeletedAt != nil {
+		return nil, errors.NewNotFoundError("user not found")
+	}
+
+	weights := s.completenessWeights
+	if weights == nil {
+		weights = config.DefaultProfileCompletenessWeights()
+	}
+	completed := profileCompletedItems(user)
+
+	items := make([]string, 0, len(weights))
+	for item, weight := range weights {
+		if weight > 0 {
+			items = append(items, item)
+		}
+	}
+	slices.SortFunc(items, func(a, b string) int {
+		return cmp.Or(cmp.Compare(weights[b], weights[a]), cmp.Compare(a, b))
+	})
+
+	response := &userResponses.ProfileCompletenessResponse{
+		UserID:          userID,
+		KYCVerified:     completed[config.ProfileItemKYCVerified],
+		CompletedFields: []string{},
+		MissingFields:   []userResponses.ProfileCompletenessItem{},
+	}
+	total, earned := 0, 0
+	for _, item := range items {
+		total += weights[item]
+		if completed[item] {
+			earned += weights[item]
+			response.CompletedFields = append(response.CompletedFields, item)
+		} else {
+			response.MissingFields = append(response.MissingFields, userResponses.ProfileCompletenessItem{Field: item, Weight: weights[item]})
+		}
+	}
+	if total > 0 {
+		response.Score = earned * 100 / total
+	}
+	return response, nil
+}
+
+// profileCompletedItems reports which profile completeness items a user has completed
+func profileCompletedItems(user *models.User) map[string]bool {
+	completed := map[string]bool{}
+	if user.Profile.BaseModel != nil {
+		profile := &user.Profile
+		completed[config.ProfileItemName] = isFilled(profile.Name)
+		completed[config.ProfileItemDateOfBirth] = isFilled(profile.DateOfBirth) || isFilled(profile.YearOfBirth)
+		completed[config.ProfileItemPhoto] = isFilled(profile.Photo)
+		completed[config.ProfileItemAddress] = isUsableAddress(&profile.Address)
+		completed[config.ProfileItemKYCVerified] = profile.AadhaarVerified
+	}
+	for i := range user.Contacts {
+		contact := &user.Contacts[i]
+		if contact.IsVerified && contact.IsActive {
+			completed[config.ProfileItemVerifiedContact] = true
+		}
+		if isUsableAddress(&contact.Address) {
+			completed[config.ProfileItemAddress] = true
+		}
+	}
+	return completed
+}
+
+// isUsableAddress reports whether an address can locate the user: it has a pincode or a full address
+func isUsableAddress(address *models.Address) bool {
+	return address.BaseModel != nil && address.ID != "" && (isFilled(address.Pincode) || isFilled(address.FullAddress))
+}
+
+func isFilled(value *string) bool {
+	return value != nil && *value != ""
+}
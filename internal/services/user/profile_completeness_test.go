@@ -0,0 +1,177 @@
+package user
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/config"
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	userResponses "github.com/Kisanlink/aaa-service/v2/internal/entities/responses/users"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// newCompletenessTestUser returns a user with an empty profile and no contacts
+func newCompletenessTestUser() *models.User {
+	user := newBatchTestUser("USER1")
+	user.Profile = *models.NewUserProfile("USER1")
+	user.Profile.ID = "PROF1"
+	return user
+}
+
+func missingFieldNames(response *userResponses.ProfileCompletenessResponse) []string {
+	names := []string{}
+	for _, item := range response.MissingFields {
+		names = append(names, item.Field)
+	}
+	return names
+}
+
+func TestGetProfileCompleteness(t *testing.T) {
+	tests := []struct {
+		name          string
+		fill          func(user *models.User)
+		wantScore     int
+		wantCompleted []string
+		wantMissing   []string
+	}{
+		{
+			name:        "empty profile",
+			fill:        func(user *models.User) {},
+			wantScore:   0,
+			wantMissing: []string{"kyc_verified", "address", "name", "verified_contact", "date_of_birth", "photo"},
+		},
+		{
+			name: "profile details",
+			fill: func(user *models.User) {
+				user.Profile.Name = stringPtr("Ramesh Kumar")
+				user.Profile.YearOfBirth = stringPtr("1980")
+				user.Profile.Photo = stringPtr("https://example.com/photo.jpg")
+			},
+			wantScore:     35,
+			wantCompleted: []string{"name", "date_of_birth", "photo"},
+			wantMissing:   []string{"kyc_verified", "address", "verified_contact"},
+		},
+		{
+			name: "unverified contact and address without pincode",
+			fill: func(user *models.User) {
+				address := models.NewAddress()
+				address.ID = "ADDR1"
+				address.District = stringPtr("Guntur")
+				contact := models.NewContact("USER1", "email", "farmer@example.com")
+				contact.Address = *address
+				user.Contacts = []models.Contact{*contact}
+			},
+			wantScore:   0,
+			wantMissing: []string{"kyc_verified", "address", "name", "verified_contact", "date_of_birth", "photo"},
+		},
+		{
+			name: "verified contact with an address",
+			fill: func(user *models.User) {
+				address := models.NewAddress()
+				address.ID = "ADDR1"
+				address.Pincode = stringPtr("522001")
+				contact := models.NewContact("USER1", "mobile", "9876543210")
+				contact.IsVerified = true
+				contact.Address = *address
+				user.Contacts = []models.Contact{*contact}
+			},
+			wantScore:     35,
+			wantCompleted: []string{"address", "verified_contact"},
+			wantMissing:   []string{"kyc_verified", "name", "date_of_birth", "photo"},
+		},
+		{
+			name: "deactivated verified contact",
+			fill: func(user *models.User) {
+				contact := models.NewContact("USER1", "mobile", "9876543210")
+				contact.IsVerified = true
+				contact.IsActive = false
+				user.Contacts = []models.Contact{*contact}
+			},
+			wantScore:   0,
+			wantMissing: []string{"kyc_verified", "address", "name", "verified_contact", "date_of_birth", "photo"},
+		},
+		{
+			name: "complete profile",
+			fill: func(user *models.User) {
+				address := models.NewAddress()
+				address.ID = "ADDR1"
+				address.FullAddress = stringPtr("Ward 4, Guntur, Andhra Pradesh")
+				user.Profile.Address = *address
+				user.Profile.Name = stringPtr("Ramesh Kumar")
+				user.Profile.DateOfBirth = stringPtr("1980-04-12")
+				user.Profile.Photo = stringPtr("https://example.com/photo.jpg")
+				user.Profile.AadhaarVerified = true
+				contact := models.NewContact("USER1", "mobile", "9876543210")
+				contact.IsVerified = true
+				user.Contacts = []models.Contact{*contact}
+			},
+			wantScore:     100,
+			wantCompleted: []string{"kyc_verified", "address", "name", "verified_contact", "date_of_birth", "photo"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := newCompletenessTestUser()
+			tt.fill(user)
+			service := &Service{userRepo: &detailUserRepo{user: user}, logger: zap.NewNop()}
+
+			response, err := service.GetProfileCompleteness(context.Background(), "USER1")
+			require.NoError(t, err)
+
+			assert.Equal(t, "USER1", response.UserID)
+			assert.Equal(t, tt.wantScore, response.Score)
+			assert.Equal(t, append([]string{}, tt.wantCompleted...), response.CompletedFields)
+			assert.Equal(t, append([]string{}, tt.wantMissing...), missingFieldNames(response))
+		})
+	}
+}
+
+func TestGetProfileCompleteness_KYCVerifiedBoost(t *testing.T) {
+	user := newCompletenessTestUser()
+	user.Profile.Name = stringPtr("Ramesh Kumar")
+	service := &Service{userRepo: &detailUserRepo{user: user}, logger: zap.NewNop()}
+
+	before, err := service.GetProfileCompleteness(context.Background(), "USER1")
+	require.NoError(t, err)
+	assert.False(t, before.KYCVerified)
+	assert.Equal(t, userResponses.ProfileCompletenessItem{Field: "kyc_verified", Weight: 30}, before.MissingFields[0],
+		"the verified Aadhaar is the heaviest item")
+
+	user.Profile.AadhaarVerified = true
+	after, err := service.GetProfileCompleteness(context.Background(), "USER1")
+	require.NoError(t, err)
+	assert.True(t, after.KYCVerified)
+	assert.Equal(t, before.Score+30, after.Score)
+	assert.Equal(t, []string{"kyc_verified", "name"}, after.CompletedFields)
+}
+
+func TestGetProfileCompleteness_ConfiguredWeights(t *testing.T) {
+	user := newCompletenessTestUser()
+	user.Profile.Name = stringPtr("Ramesh Kumar")
+	user.Profile.AadhaarVerified = true
+	service := &Service{userRepo: &detailUserRepo{user: user}, logger: zap.NewNop()}
+
+	weights := config.DefaultProfileCompletenessWeights()
+	weights[config.ProfileItemKYCVerified] = 60
+	weights[config.ProfileItemPhoto] = 0
+	service.SetProfileCompletenessWeights(weights)
+
+	response, err := service.GetProfileCompleteness(context.Background(), "USER1")
+	require.NoError(t, err)
+	assert.Equal(t, 62, response.Score, "(60+15) of a total of 120")
+	assert.NotContains(t, missingFieldNames(response), "photo", "items weighing 0 are left out")
+}
+
+func TestGetProfileCompleteness_UserNotFound(t *testing.T) {
+	service := &Service{userRepo: &detailUserRepo{user: newCompletenessTestUser()}, logger: zap.NewNop()}
+
+	_, err := service.GetProfileCompleteness(context.Background(), "USER404")
+	assert.True(t, errors.IsNotFoundError(err))
+
+	_, err = service.GetProfileCompleteness(context.Background(), "")
+	assert.True(t, errors.IsValidationError(err))
+}
@@ -3,8 +3,10 @@ package user
 import (
 	"context"
 	"fmt"
+	"sort"
 
 	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	userRequests "github.com/Kisanlink/aaa-service/v2/internal/entities/requests/users"
 	"github.com/Kisanlink/aaa-service/v2/internal/entities/responses"
 	userResponses "github.com/Kisanlink/aaa-service/v2/internal/entities/responses/users"
 	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
@@ -105,3 +107,195 @@ func (s *Service) ListUsers(ctx context.Context, limit, offset int) (*responses.
 		Total: total,
 	}, nil
 }
+
+// GetUsersByIDs resolves several users in one call and reports the requested IDs that were not found.
+// Users are returned in request order; soft-deleted users are reported as missing. Only directly
+// assigned roles are included with IncludeRoles - use GetUserWithRoles for inherited group roles.
+func (s *Service) GetUsersByIDs(ctx context.Context, req *userRequests.BatchGetUsersRequest) (*userResponses.BatchUsersResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, errors.NewValidationError(err.Error())
+	}
+
+	s.logger.Info("Getting users by IDs",
+		zap.Int("count", len(req.IDs)),
+		zap.Bool("include_roles", req.IncludeRoles),
+		zap.Bool("include_profile", req.IncludeProfile),
+		zap.Bool("include_addresses", req.IncludeAddresses))
+
+	users, err := s.userRepo.GetUsersWithRelationships(ctx, req.IDs, req.IncludeRoles, req.IncludeProfile, req.IncludeAddresses)
+	if err != nil {
+		s.logger.Error("Failed to get users by IDs", zap.Error(err))
+		return nil, errors.NewInternalError(err)
+	}
+
+	byID := make(map[string]*models.User, len(users))
+	for _, user := range users {
+		if user.DeletedAt == nil {
+			byID[user.ID] = user
+		}
+	}
+
+	result := &userResponses.BatchUsersResponse{
+		Users:      make([]*userResponses.UserResponse, 0, len(byID)),
+		MissingIDs: []string{},
+	}
+	for _, id := range req.IDs {
+		user, ok := byID[id]
+		if !ok {
+			result.MissingIDs = append(result.MissingIDs, id)
+			continue
+		}
+
+		response := &userResponses.UserResponse{}
+		response.FromModel(user)
+		if req.IncludeRoles {
+			response.Roles = directRoleDetails(user.Roles)
+		}
+		result.Users = append(result.Users, response)
+	}
+
+	s.logger.Info("Users retrieved by IDs",
+		zap.Int("found", len(result.Users)),
+		zap.Int("missing", len(result.MissingIDs)))
+
+	return result, nil
+}
+
+// GetUserDetail assembles a user with the related data selected by expand, or everything when
+// expand is nil. Profile, contacts, addresses and roles are loaded in one query; group and
+// organization memberships come from the cached organizational context. Roles are the active,
+// directly assigned ones - use GetUserWithRoles for roles inherited through groups.
+func (s *Service) GetUserDetail(ctx context.Context, userID string, expand *userRequests.UserDetailExpand) (*userResponses.UserDetailResponse, error) {
+	if userID == "" {
+		return nil, errors.NewValidationError("user ID cannot be empty")
+	}
+	if expand == nil {
+		expand = userRequests.AllUserDetails()
+	}
+
+	s.logger.Info("Getting user detail",
+		zap.String("user_id", userID),
+		zap.Strings("expand", expand.Names()))
+
+	user, err := s.userRepo.GetWithDetails(ctx, userID, expand.Profile, expand.Contacts, expand.Addresses, expand.Roles)
+	if err != nil {
+		s.logger.Error("Failed to get user detail", zap.String("user_id", userID), zap.Error(err))
+		return nil, errors.NewNotFoundError("user not found")
+	}
+	if user.DeletedAt != nil {
+		return nil, errors.NewNotFoundError("user not found")
+	}
+
+	response := &userResponses.UserDetailResponse{Expanded: expand.Names()}
+	response.FromModel(user)
+
+	if expand.Profile {
+		response.Profile = userResponses.NewUserProfileDetail(&user.Profile)
+	}
+	if expand.Contacts {
+		response.Contacts = make([]userResponses.UserContactDetail, 0, len(user.Contacts))
+		for i := range user.Contacts {
+			response.Contacts = append(response.Contacts, userResponses.NewUserContactDetail(&user.Contacts[i]))
+		}
+	}
+	if expand.Addresses {
+		response.Addresses = userAddresses(user)
+	}
+	if expand.Roles {
+		response.Roles = directRoleDetails(user.Roles)
+	}
+	if expand.Groups {
+		response.Organizations, response.Groups = s.userMemberships(ctx, userID)
+	}
+	if expand.AttributesOrganizationID != "" && s.attributeRepo != nil {
+		response.AttributesOrganizationID = expand.AttributesOrganizationID
+		response.Attributes = s.userAttributes(ctx, expand.AttributesOrganizationID, userID)
+	}
+
+	s.logger.Info("User detail retrieved", zap.String("user_id", userID))
+	return response, nil
+}
+
+// userAddresses collects the distinct addresses of a user's profile and contacts
+func userAddresses(user *models.User) []userResponses.AddressResponse {
+	addresses := make([]userResponses.AddressResponse, 0, len(user.Contacts)+1)
+	seen := make(map[string]bool)
+	add := func(address *models.Address) {
+		if address.BaseModel == nil || address.ID == "" || seen[address.ID] {
+			return
+		}
+		seen[address.ID] = true
+		response := userResponses.AddressResponse{}
+		response.FromModel(address)
+		addresses = append(addresses, response)
+	}
+
+	if user.Profile.BaseModel != nil {
+		add(&user.Profile.Address)
+	}
+	for i := range user.Contacts {
+		add(&user.Contacts[i].Address)
+	}
+	return addresses
+}
+
+// userMemberships returns the organizations and groups of a user, sorted by name. Lookup failures
+// leave the sections empty, as they do for the token context.
+func (s *Service) userMemberships(ctx context.Context, userID string) ([]userResponses.UserOrganizationDetail, []userResponses.UserGroupDetail) {
+	organizations := []userResponses.UserOrganizationDetail{}
+	if orgs, err := s.GetUserOrganizations(ctx, userID); err != nil {
+		s.logger.Warn("Failed to get user organizations for detail", zap.String("user_id", userID), zap.Error(err))
+	} else {
+		for _, org := range orgs {
+			id, _ := org["id"].(string)
+			name, _ := org["name"].(string)
+			organizations = append(organizations, userResponses.UserOrganizationDetail{ID: id, Name: name})
+		}
+	}
+
+	groups := []userResponses.UserGroupDetail{}
+	if userGroups, err := s.GetUserGroups(ctx, userID); err != nil {
+		s.logger.Warn("Failed to get user groups for detail", zap.String("user_id", userID), zap.Error(err))
+	} else {
+		for _, group := range userGroups {
+			id, _ := group["id"].(string)
+			name, _ := group["name"].(string)
+			orgID, _ := group["organization_id"].(string)
+			groups = append(groups, userResponses.UserGroupDetail{ID: id, Name: name, OrganizationID: orgID})
+		}
+	}
+
+	sort.Slice(organizations, func(i, j int) bool { return organizations[i].Name < organizations[j].Name })
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Name < groups[j].Name })
+	return organizations, groups
+}
+
+// directRoleDetails maps preloaded, active user roles to their response form
+func directRoleDetails(userRoles []models.UserRole) []userResponses.UserRoleDetail {
+	roles := make([]userResponses.UserRoleDetail, 0, len(userRoles))
+	for _, userRole := range userRoles {
+		if !userRole.IsActive || userRole.Role.BaseModel == nil {
+			continue
+		}
+
+		assignedAt := ""
+		if userRole.BaseModel != nil {
+			assignedAt = userRole.CreatedAt.Format("2006-01-02T15:04:05Z07:00")
+		}
+
+		roles = append(roles, userResponses.UserRoleDetail{
+			ID:       userRole.ID,
+			UserID:   userRole.UserID,
+			RoleID:   userRole.RoleID,
+			IsActive: userRole.IsActive,
+			Role: userResponses.RoleDetail{
+				ID:          userRole.Role.ID,
+				Name:        userRole.Role.Name,
+				Description: userRole.Role.Description,
+				IsActive:    userRole.Role.IsActive,
+				AssignedAt:  assignedAt,
+			},
+		})
+	}
+	return roles
+}
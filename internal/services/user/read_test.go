@@ -0,0 +1,193 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/requests/users"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// batchUserRepo returns the stored users whose IDs were requested, like GetUsersWithRelationships
+type batchUserRepo struct {
+	interfaces.UserRepository
+	users     []*models.User
+	requested []string
+	roles     bool
+}
+
+func (r *batchUserRepo) GetUsersWithRelationships(ctx context.Context, userIDs []string, includeRoles, includeProfile, includeAddresses bool) ([]*models.User, error) {
+	r.requested = userIDs
+	r.roles = includeRoles
+	var found []*models.User
+	for _, u := range r.users {
+		for _, id := range userIDs {
+			if u.ID == id {
+				found = append(found, u)
+			}
+		}
+	}
+	return found, nil
+}
+
+func newBatchTestUser(id string) *models.User {
+	user := models.NewUser("9876543210", "+91", "hash")
+	user.ID = id
+	return user
+}
+
+func TestGetUsersByIDs(t *testing.T) {
+	deleted := newBatchTestUser("USER3")
+	now := time.Now()
+	deleted.DeletedAt = &now
+
+	withRoles := newBatchTestUser("USER2")
+	role := models.NewRole("farmer", "Farmer", models.RoleScopeGlobal)
+	active := models.NewUserRole("USER2", role.ID)
+	active.Role = *role
+	revoked := models.NewUserRole("USER2", role.ID)
+	revoked.Role = *role
+	revoked.IsActive = false
+	withRoles.Roles = []models.UserRole{*active, *revoked}
+
+	repo := &batchUserRepo{users: []*models.User{newBatchTestUser("USER1"), withRoles, deleted}}
+	service := &Service{userRepo: repo, logger: zap.NewNop()}
+
+	result, err := service.GetUsersByIDs(context.Background(), &users.BatchGetUsersRequest{
+		IDs:          []string{" USER2", "USER404", "USER1", "USER2", "USER3", ""},
+		IncludeRoles: true,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"USER2", "USER404", "USER1", "USER3"}, repo.requested)
+	assert.True(t, repo.roles)
+
+	require.Len(t, result.Users, 2)
+	assert.Equal(t, "USER2", result.Users[0].ID)
+	assert.Equal(t, "USER1", result.Users[1].ID)
+	require.Len(t, result.Users[0].Roles, 1)
+	assert.Equal(t, "farmer", result.Users[0].Roles[0].Role.Name)
+	assert.Empty(t, result.Users[1].Roles)
+	assert.Equal(t, []string{"USER404", "USER3"}, result.MissingIDs)
+}
+
+func TestGetUsersByIDs_TooManyIDs(t *testing.T) {
+	service := &Service{userRepo: &batchUserRepo{}, logger: zap.NewNop()}
+
+	ids := make([]string, users.MaxBatchUserIDs+1)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("USER%d", i)
+	}
+
+	_, err := service.GetUsersByIDs(context.Background(), &users.BatchGetUsersRequest{IDs: ids})
+	require.Error(t, err)
+	assert.True(t, errors.IsValidationError(err))
+}
+
+// detailUserRepo records the relationships requested from GetWithDetails
+type detailUserRepo struct {
+	interfaces.UserRepository
+	user     *models.User
+	included []bool
+}
+
+func (r *detailUserRepo) GetWithDetails(ctx context.Context, userID string, includeProfile, includeContacts, includeAddresses, includeRoles bool) (*models.User, error) {
+	r.included = []bool{includeProfile, includeContacts, includeAddresses, includeRoles}
+	if r.user == nil || r.user.ID != userID {
+		return nil, fmt.Errorf("user with id %s not found", userID)
+	}
+	return r.user, nil
+}
+
+func newDetailTestUser() *models.User {
+	user := newBatchTestUser("USER1")
+
+	home := models.NewAddress()
+	home.ID = "ADDR1"
+	user.Profile = *models.NewUserProfile("USER1")
+	user.Profile.ID = "PROF1"
+	user.Profile.Address = *home
+
+	phone := models.NewContact("USER1", "mobile", "9876543210")
+	phone.IsPrimary = true
+	phone.IsVerified = true
+	phone.Address = *home
+	farm := models.NewAddress()
+	farm.ID = "ADDR2"
+	email := models.NewContact("USER1", "email", "farmer@example.com")
+	email.Address = *farm
+	user.Contacts = []models.Contact{*phone, *email}
+
+	role := models.NewRole("farmer", "Farmer", models.RoleScopeGlobal)
+	userRole := models.NewUserRole("USER1", role.ID)
+	userRole.Role = *role
+	user.Roles = []models.UserRole{*userRole}
+	return user
+}
+
+func TestGetUserDetail_SelectiveExpansion(t *testing.T) {
+	repo := &detailUserRepo{user: newDetailTestUser()}
+	service := &Service{userRepo: repo, logger: zap.NewNop()}
+
+	detail, err := service.GetUserDetail(context.Background(), "USER1", &users.UserDetailExpand{Contacts: true, Roles: true})
+	require.NoError(t, err)
+
+	assert.Equal(t, []bool{false, true, false, true}, repo.included)
+	assert.Equal(t, []string{"contacts", "roles"}, detail.Expanded)
+	assert.Nil(t, detail.Profile)
+	assert.Nil(t, detail.Addresses)
+	assert.Nil(t, detail.Groups)
+
+	require.Len(t, detail.Contacts, 2)
+	assert.Equal(t, "mobile", detail.Contacts[0].Type)
+	assert.True(t, detail.Contacts[0].IsPrimary)
+	assert.True(t, detail.Contacts[0].IsVerified)
+	assert.False(t, detail.Contacts[1].IsPrimary)
+	require.Len(t, detail.Roles, 1)
+	assert.Equal(t, "farmer", detail.Roles[0].Role.Name)
+}
+
+func TestGetUserDetail_AllSections(t *testing.T) {
+	repo := &detailUserRepo{user: newDetailTestUser()}
+	service := &Service{userRepo: repo, logger: zap.NewNop()}
+
+	detail, err := service.GetUserDetail(context.Background(), "USER1", nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, []bool{true, true, true, true}, repo.included)
+	require.NotNil(t, detail.Profile)
+	require.NotNil(t, detail.Profile.Address)
+	assert.Equal(t, "ADDR1", detail.Profile.Address.ID)
+
+	require.Len(t, detail.Addresses, 2, "the profile and contact share ADDR1")
+	assert.Equal(t, "ADDR1", detail.Addresses[0].ID)
+	assert.Equal(t, "ADDR2", detail.Addresses[1].ID)
+
+	// Without membership repositories the sections are expanded but empty
+	assert.NotNil(t, detail.Organizations)
+	assert.NotNil(t, detail.Groups)
+	assert.Empty(t, detail.Groups)
+}
+
+func TestGetUserDetail_NotFound(t *testing.T) {
+	deleted := newDetailTestUser()
+	now := time.Now()
+	deleted.DeletedAt = &now
+	service := &Service{userRepo: &detailUserRepo{user: deleted}, logger: zap.NewNop()}
+
+	_, err := service.GetUserDetail(context.Background(), "USER1", nil)
+	assert.True(t, errors.IsNotFoundError(err))
+
+	_, err = service.GetUserDetail(context.Background(), "USER404", nil)
+	assert.True(t, errors.IsNotFoundError(err))
+
+	_, err = service.GetUserDetail(context.Background(), "", nil)
+	assert.True(t, errors.IsValidationError(err))
+}
@@ -1,7 +1,14 @@
 package user
 
 import (
+	"context"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/config"
 	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/internal/security"
+	"github.com/Kisanlink/aaa-service/v2/internal/services/notifications"
+	"github.com/Kisanlink/aaa-service/v2/internal/services/otp"
 	"go.uber.org/zap"
 )
 
@@ -15,7 +22,21 @@ type Service struct {
 	organizationRepo      any // Optional: for fetching organization details
 	roleInheritanceEngine any // Optional: for calculating inherited roles from groups
 	cacheService          interfaces.CacheService
-	smsService            interfaces.SMSService // Optional: for SMS OTP delivery
+	smsService            interfaces.SMSService                          // Optional: for SMS OTP delivery
+	notifier              interfaces.SecurityNotifier                    // Optional: security event notifications
+	deviceRepo            interfaces.UserDeviceRepository                // Optional: device-bound MPINs
+	auditService          interfaces.AuditService                        // Optional: audit trail of device MPIN operations and default roles
+	credentialHasher      *security.CredentialHasher                     // Optional: bcrypt costs; bcrypt.DefaultCost when unset
+	defaultRoles          *defaultRoles                                  // Optional: roles given to new users
+	sessionSettingRepo    interfaces.OrganizationSettingRepository       // Optional: per-organization session limits
+	exportAuditRepo       interfaces.AuditRepository                     // Optional: audit trail of user data exports
+	deletionRepo          interfaces.AccountDeletionRepository           // Optional: self-service account deletion
+	deletionGrace         time.Duration                                  // Grace period before a self-deleted account is purged
+	attributeRepo         interfaces.OrganizationUserAttributeRepository // Optional: organization-scoped custom attributes in user details
+	completenessWeights   map[string]int                                 // Weights of the profile completeness items; the config defaults when unset
+	resetOTP              *otp.Service                                   // Optional: OTP-based password and MPIN resets
+	sessionRevoker        interfaces.SessionRevoker                      // Optional: signs users out everywhere after a credential reset or deletion request
+	usernamePolicy        config.UsernamePolicyConfig                    // Username requirement, case-insensitive uniqueness and generation
 	logger                *zap.Logger
 	validator             interfaces.Validator
 }
@@ -60,6 +81,25 @@ func (s *Service) SetRoleInheritanceEngine(engine any) {
 	s.logger.Info("Role inheritance engine injected for group-based role inheritance")
 }
 
+// SetSecurityNotifier injects the notifier that tells users about password and MPIN changes
+func (s *Service) SetSecurityNotifier(notifier interfaces.SecurityNotifier) {
+	s.notifier = notifier
+}
+
+// SetDeviceMPinSupport enables device-bound MPINs, audited through the given audit service
+func (s *Service) SetDeviceMPinSupport(deviceRepo interfaces.UserDeviceRepository, auditService interfaces.AuditService) {
+	s.deviceRepo = deviceRepo
+	s.auditService = auditService
+}
+
+// notifySecurityEvent notifies the user about a security event without waiting for delivery
+func (s *Service) notifySecurityEvent(ctx context.Context, eventType, userID string, details map[string]interface{}) {
+	if s.notifier == nil {
+		return
+	}
+	s.notifier.NotifySecurityEvent(ctx, notifications.EventFromContext(ctx, eventType, userID, details))
+}
+
 // SetSMSService injects the SMS service for OTP delivery during password reset
 // This is optional and should be called after service initialization if SMS OTP is needed
 func (s *Service) SetSMSService(smsService interfaces.SMSService) {
@@ -0,0 +1,65 @@
+package user
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"go.uber.org/zap"
+)
+
+// SetSessionLimitSettings enables MaxConcurrentSessions, which reads the max_concurrent_sessions
+// setting of the user's organizations from settingRepo
+func (s *Service) SetSessionLimitSettings(settingRepo interfaces.OrganizationSettingRepository) {
+	s.sessionSettingRepo = settingRepo
+}
+
+// MaxConcurrentSessions returns the lowest max_concurrent_sessions setting of the organizations the
+// user belongs to, or 0 when none of them sets a limit
+func (s *Service) MaxConcurrentSessions(ctx context.Context, userID string) (int, error) {
+	if s.sessionSettingRepo == nil {
+		return 0, nil
+	}
+
+	organizations, err := s.GetUserOrganizations(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	limit := 0
+	for _, org := range organizations {
+		orgID, _ := org["id"].(string)
+		if orgID == "" {
+			continue
+		}
+		settings, err := s.sessionSettingRepo.ListByOrganization(ctx, orgID)
+		if err != nil {
+			return 0, err
+		}
+		if orgLimit := sessionLimitSetting(settings); orgLimit > 0 && (limit == 0 || orgLimit < limit) {
+			limit = orgLimit
+		}
+	}
+
+	if limit > 0 {
+		s.logger.Debug("Organization session limit applies", zap.String("user_id", userID), zap.Int("limit", limit))
+	}
+	return limit, nil
+}
+
+// sessionLimitSetting reads the max_concurrent_sessions setting; a value that no longer decodes
+// as an integer sets no limit
+func sessionLimitSetting(settings []*models.OrganizationSetting) int {
+	for _, setting := range settings {
+		if setting.Key != models.OrgSettingMaxConcurrentSessions {
+			continue
+		}
+		var value int
+		if err := json.Unmarshal([]byte(setting.Value), &value); err != nil || value < 0 {
+			return 0
+		}
+		return value
+	}
+	return 0
+}
@@ -8,7 +8,10 @@ import (
 	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
 	"github.com/Kisanlink/aaa-service/v2/internal/entities/requests/users"
 	userResponses "github.com/Kisanlink/aaa-service/v2/internal/entities/responses/users"
+	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
+	"github.com/Kisanlink/aaa-service/v2/internal/utils/actor"
 	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/Kisanlink/aaa-service/v2/pkg/phonenumber"
 	"go.uber.org/zap"
 	"golang.org/x/crypto/bcrypt"
 )
@@ -39,25 +42,31 @@ func (s *Service) UpdateUser(ctx context.Context, req *users.UpdateUserRequest)
 	// Update fields if provided
 	if req.MobileNumber != nil {
 		phoneStr := fmt.Sprintf("%d", *req.MobileNumber)
-		countryCode := "+91" // default
-		if req.CountryCode != nil {
+		countryCode := phonenumber.DefaultCountryCode
+		if req.CountryCode != nil && *req.CountryCode != "" {
 			countryCode = *req.CountryCode
 		}
 
+		phone, err := phonenumber.Parse(phoneStr, countryCode)
+		if err != nil {
+			s.logger.Warn("User update rejected: invalid phone number", zap.String("user_id", userID), zap.Error(err))
+			return nil, errors.NewValidationError("invalid phone number", err.Error())
+		}
+
 		// Check if new phone number conflicts with existing users
-		conflictUser, err := s.userRepo.GetByPhoneNumber(ctx, phoneStr, countryCode)
+		conflictUser, err := s.userRepo.GetByPhoneNumber(ctx, phone.NationalNumber, phone.CountryCode)
 		if err == nil && conflictUser != nil && conflictUser.ID != userID {
 			s.logger.Warn("Phone number already in use",
-				zap.String("phone", phoneStr),
-				zap.String("country", countryCode))
+				zap.String("phone", phone.NationalNumber),
+				zap.String("country", phone.CountryCode))
 			return nil, errors.NewConflictError("phone number already in use")
 		}
-		existingUser.PhoneNumber = phoneStr
-		existingUser.CountryCode = countryCode
+		existingUser.SetPhone(phone)
 		existingUser.IsValidated = false // Need to re-validate
 	}
 
 	existingUser.UpdatedAt = time.Now()
+	actor.StampUpdate(ctx, existingUser)
 
 	// Update in repository
 	err = s.userRepo.Update(ctx, existingUser)
@@ -111,16 +120,17 @@ func (s *Service) ChangePassword(ctx context.Context, userID, oldPassword, newPa
 	}
 
 	// Hash new password
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	hashedPassword, err := s.hasher().HashPassword(newPassword)
 	if err != nil {
 		s.logger.Error("Failed to hash new password", zap.String("user_id", userID), zap.Error(err))
 		return errors.NewInternalError(err)
 	}
 
 	// Update password and clear force-change flag
-	existingUser.Password = string(hashedPassword)
+	existingUser.Password = hashedPassword
 	existingUser.MustChangePassword = false
 	existingUser.UpdatedAt = time.Now()
+	actor.StampUpdate(ctx, existingUser)
 
 	// Update in repository
 	err = s.userRepo.Update(ctx, existingUser)
@@ -132,6 +142,8 @@ func (s *Service) ChangePassword(ctx context.Context, userID, oldPassword, newPa
 	// Clear cache
 	s.clearUserCache(userID)
 
+	s.notifySecurityEvent(ctx, interfaces.SecurityEventPasswordChanged, userID, map[string]interface{}{"method": "change"})
+
 	s.logger.Info("User password changed successfully", zap.String("user_id", userID))
 	return nil
 }
@@ -0,0 +1,140 @@
+package user
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/config"
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/Kisanlink/aaa-service/v2/pkg/phonenumber"
+	"go.uber.org/zap"
+)
+
+const (
+	// generatedUsernameMaxBase caps the part of a generated username derived from the name
+	generatedUsernameMaxBase = 30
+	// generatedUsernameAttempts is how many candidates are tried before generation gives up
+	generatedUsernameAttempts = 10
+	// generatedUsernameSuffixRange bounds the random numeric suffix of a retried candidate
+	generatedUsernameSuffixRange = 10000
+	// minUsernameLength is the shortest username the create request accepts
+	minUsernameLength = 3
+)
+
+// SetUsernamePolicy sets whether new users need a username, whether usernames are unique regardless of
+// case, and how a username is generated when none is given. Without it usernames are optional, only
+// exact duplicates are rejected and none are generated.
+func (s *Service) SetUsernamePolicy(policy config.UsernamePolicyConfig) {
+	s.usernamePolicy = policy
+}
+
+// usernameTaken reports whether a live user holds username, ignoring case when the policy makes
+// usernames unique regardless of case
+func (s *Service) usernameTaken(ctx context.Context, username string) (bool, error) {
+	var existing *models.User
+	var err error
+	if s.usernamePolicy.Unique {
+		existing, err = s.userRepo.GetByUsernameIgnoreCase(ctx, username)
+	} else {
+		existing, err = s.userRepo.GetByUsername(ctx, username)
+	}
+	if err != nil {
+		if strings.Contains(err.Error(), "user not found") {
+			return false, nil
+		}
+		return false, err
+	}
+	return existing != nil, nil
+}
+
+// usernameGenerated reports whether the policy generates usernames for users created without one
+func (s *Service) usernameGenerated() bool {
+	return s.usernamePolicy.Generate != "" && s.usernamePolicy.Generate != config.UsernameGenerateNone
+}
+
+// generateUsername derives a free username for a user created without one, from the name or the
+// last digits of the phone number as the policy says. The derived username is tried first, then
+// with random numeric suffixes until a free one is found. It returns "" when the policy generates
+// no usernames.
+func (s *Service) generateUsername(ctx context.Context, name *string, phone phonenumber.Number) (string, error) {
+	if !s.usernameGenerated() {
+		return "", nil
+	}
+
+	base := ""
+	if s.usernamePolicy.Generate == config.UsernameGenerateName && name != nil {
+		base = usernameFromName(*name)
+	}
+	// Names without enough Latin letters or digits, such as those in Devanagari, fall back to the phone number
+	if len(base) < minUsernameLength {
+		base = usernameFromPhone(phone)
+	}
+
+	candidate := base
+	for attempt := 0; attempt < generatedUsernameAttempts; attempt++ {
+		if attempt > 0 {
+			suffix, err := rand.Int(rand.Reader, big.NewInt(generatedUsernameSuffixRange))
+			if err != nil {
+				return "", errors.NewInternalError(err)
+			}
+			candidate = fmt.Sprintf("%s_%04d", base, suffix.Int64())
+		}
+
+		taken, err := s.usernameTaken(ctx, candidate)
+		if err != nil {
+			s.logger.Error("Error checking generated username uniqueness", zap.Error(err))
+			return "", errors.NewInternalError(err)
+		}
+		if !taken {
+			return candidate, nil
+		}
+	}
+
+	s.logger.Warn("Failed to generate a free username", zap.String("base", base), zap.Int("attempts", generatedUsernameAttempts))
+	return "", errors.NewConflictError("could not generate a unique username; provide one")
+}
+
+// usernameFromName lowercases name and joins its runs of ASCII letters and digits with underscores,
+// so "Ramesh Kumar" becomes ramesh_kumar
+func usernameFromName(name string) string {
+	var b strings.Builder
+	pendingSeparator := false
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if pendingSeparator && b.Len() > 0 {
+				b.WriteByte('_')
+			}
+			pendingSeparator = false
+			b.WriteRune(r)
+			continue
+		}
+		pendingSeparator = true
+	}
+
+	base := b.String()
+	if len(base) > generatedUsernameMaxBase {
+		base = strings.TrimRight(base[:generatedUsernameMaxBase], "_")
+	}
+	return base
+}
+
+// usernameFromPhone names the user after the last four digits of their phone number, so the full
+// number is not exposed
+func usernameFromPhone(phone phonenumber.Number) string {
+	digits := phone.NationalNumber
+	if len(digits) > 4 {
+		digits = digits[len(digits)-4:]
+	}
+	return "user_" + digits
+}
+
+// isUsernameConflict reports whether a create failed on a unique index or constraint over usernames
+func isUsernameConflict(err error) bool {
+	message := err.Error()
+	return strings.Contains(message, models.UsersUsernameLowerUniqueIndex) ||
+		strings.Contains(message, "users_username")
+}
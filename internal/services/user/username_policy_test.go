@@ -0,0 +1,212 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/config"
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/requests/users"
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// usernameUniqueUserRepo adds the case-insensitive username index to the in-memory user store
+type usernameUniqueUserRepo struct {
+	phoneUniqueUserRepo
+	// noIndex leaves out the index, as when AAA_USERNAME_UNIQUE=false skips its migration
+	noIndex bool
+	// takenAll reports every username as taken, as if generation kept colliding
+	takenAll bool
+	// staleLookups reports every username as free, as if its holder were created after the lookup
+	staleLookups bool
+	// missedLookups reports the next usernames looked up as free, like staleLookups
+	missedLookups int
+}
+
+func (r *usernameUniqueUserRepo) GetByUsernameIgnoreCase(ctx context.Context, username string) (*models.User, error) {
+	if r.takenAll {
+		return &models.User{Username: &username}, nil
+	}
+	if r.staleLookups || r.missedLookups > 0 {
+		r.missedLookups--
+		return nil, fmt.Errorf("user not found with username: %s", username)
+	}
+	return r.findUsernameIgnoreCase(username)
+}
+
+func (r *usernameUniqueUserRepo) findUsernameIgnoreCase(username string) (*models.User, error) {
+	for _, u := range r.users {
+		if u.Username != nil && strings.EqualFold(*u.Username, username) && u.DeletedAt == nil {
+			return u, nil
+		}
+	}
+	return nil, fmt.Errorf("user not found with username: %s", username)
+}
+
+func (r *usernameUniqueUserRepo) Create(ctx context.Context, user *models.User) error {
+	if user.Username != nil && !r.noIndex {
+		if _, err := r.findUsernameIgnoreCase(*user.Username); err == nil {
+			return fmt.Errorf(`ERROR: duplicate key value violates unique constraint "%s" (SQLSTATE 23505)`, models.UsersUsernameLowerUniqueIndex)
+		}
+	}
+	return r.phoneUniqueUserRepo.Create(ctx, user)
+}
+
+func newUsernamePolicyTestService(repo *usernameUniqueUserRepo, policy config.UsernamePolicyConfig) *Service {
+	service := newPhoneUniqueTestService(&repo.phoneUniqueUserRepo)
+	service.userRepo = repo
+	service.SetUsernamePolicy(policy)
+	return service
+}
+
+func createRequest(phoneNumber string, username, name *string) *users.CreateUserRequest {
+	return &users.CreateUserRequest{
+		PhoneNumber: phoneNumber,
+		CountryCode: "+91",
+		Password:    "Secret123!",
+		Username:    username,
+		Name:        name,
+	}
+}
+
+func TestCreateUser_UsernameUniqueIgnoresCase(t *testing.T) {
+	ctx := context.Background()
+	repo := &usernameUniqueUserRepo{}
+	service := newUsernamePolicyTestService(repo, config.UsernamePolicyConfig{Unique: true, Generate: config.UsernameGenerateNone})
+
+	_, err := service.CreateUser(ctx, createRequest("9876543210", stringPtr("ramesh"), nil))
+	require.NoError(t, err)
+
+	_, err = service.CreateUser(ctx, createRequest("9876543211", stringPtr("Ramesh"), nil))
+	require.Error(t, err)
+	assert.IsType(t, &errors.ConflictError{}, err)
+	assert.Contains(t, err.Error(), "username is already taken")
+
+	// Without case-insensitive uniqueness only exact duplicates are rejected
+	repo.noIndex = true
+	service.SetUsernamePolicy(config.UsernamePolicyConfig{})
+	created, err := service.CreateUser(ctx, createRequest("9876543212", stringPtr("RAMESH"), nil))
+	require.NoError(t, err)
+	assert.Equal(t, "RAMESH", *created.Username)
+}
+
+func TestCreateUser_UsernameRaceLostOnIndex(t *testing.T) {
+	ctx := context.Background()
+	repo := &usernameUniqueUserRepo{}
+	service := newUsernamePolicyTestService(repo, config.UsernamePolicyConfig{Unique: true, Generate: config.UsernameGenerateNone})
+
+	_, err := service.CreateUser(ctx, createRequest("9876543210", stringPtr("suresh"), nil))
+	require.NoError(t, err)
+
+	// The lookup misses "suresh", as a concurrent create would; the index rejects "Suresh"
+	repo.staleLookups = true
+	_, err = service.CreateUser(ctx, createRequest("9876543211", stringPtr("Suresh"), nil))
+	require.Error(t, err)
+	assert.IsType(t, &errors.ConflictError{}, err)
+	assert.Contains(t, err.Error(), "username is already taken")
+}
+
+func TestCreateUser_GeneratesUsernameFromName(t *testing.T) {
+	ctx := context.Background()
+	repo := &usernameUniqueUserRepo{}
+	service := newUsernamePolicyTestService(repo, config.UsernamePolicyConfig{Unique: true, Generate: config.UsernameGenerateName})
+
+	first, err := service.CreateUser(ctx, createRequest("9876543210", nil, stringPtr("Ramesh  Kumar-Patil")))
+	require.NoError(t, err)
+	require.NotNil(t, first.Username)
+	assert.Equal(t, "ramesh_kumar_patil", *first.Username)
+
+	// The next Ramesh Kumar Patil collides and is given a suffix
+	second, err := service.CreateUser(ctx, createRequest("9876543211", nil, stringPtr("RAMESH KUMAR PATIL")))
+	require.NoError(t, err)
+	require.NotNil(t, second.Username)
+	assert.Regexp(t, regexp.MustCompile(`^ramesh_kumar_patil_\d{4}$`), *second.Username)
+
+	// A name with no Latin letters or digits falls back to the phone number
+	third, err := service.CreateUser(ctx, createRequest("9876543212", nil, stringPtr("रमेश")))
+	require.NoError(t, err)
+	require.NotNil(t, third.Username)
+	assert.Equal(t, "user_3212", *third.Username)
+
+	// A username given in the request is kept as is
+	given, err := service.CreateUser(ctx, createRequest("9876543213", stringPtr("farmer_ramesh"), stringPtr("Ramesh Kumar")))
+	require.NoError(t, err)
+	assert.Equal(t, "farmer_ramesh", *given.Username)
+}
+
+func TestCreateUser_GeneratesUsernameFromPhone(t *testing.T) {
+	ctx := context.Background()
+	repo := &usernameUniqueUserRepo{}
+	service := newUsernamePolicyTestService(repo, config.UsernamePolicyConfig{Unique: true, Generate: config.UsernameGeneratePhone})
+
+	first, err := service.CreateUser(ctx, createRequest("9876543210", nil, stringPtr("Ramesh Kumar")))
+	require.NoError(t, err)
+	require.NotNil(t, first.Username)
+	assert.Equal(t, "user_3210", *first.Username)
+
+	// Another number ending in the same digits collides and is given a suffix
+	second, err := service.CreateUser(ctx, createRequest("9123453210", nil, nil))
+	require.NoError(t, err)
+	require.NotNil(t, second.Username)
+	assert.Regexp(t, regexp.MustCompile(`^user_3210_\d{4}$`), *second.Username)
+}
+
+func TestCreateUser_GeneratedUsernameRaceRetries(t *testing.T) {
+	ctx := context.Background()
+	repo := &usernameUniqueUserRepo{}
+	service := newUsernamePolicyTestService(repo, config.UsernamePolicyConfig{Unique: true, Generate: config.UsernameGenerateName})
+
+	_, err := service.CreateUser(ctx, createRequest("9876543210", nil, stringPtr("Ramesh Kumar")))
+	require.NoError(t, err)
+
+	// The lookup misses ramesh_kumar, as a concurrent create would; the index rejects it and another name is generated
+	repo.missedLookups = 1
+	second, err := service.CreateUser(ctx, createRequest("9876543211", nil, stringPtr("Ramesh Kumar")))
+	require.NoError(t, err)
+	require.NotNil(t, second.Username)
+	assert.Regexp(t, regexp.MustCompile(`^ramesh_kumar_\d{4}$`), *second.Username)
+	assert.Len(t, repo.users, 2)
+}
+
+func TestCreateUser_UsernameGenerationGivesUp(t *testing.T) {
+	repo := &usernameUniqueUserRepo{takenAll: true}
+	service := newUsernamePolicyTestService(repo, config.UsernamePolicyConfig{Unique: true, Generate: config.UsernameGenerateName})
+
+	_, err := service.CreateUser(context.Background(), createRequest("9876543210", nil, stringPtr("Ramesh")))
+	require.Error(t, err)
+	assert.IsType(t, &errors.ConflictError{}, err)
+	assert.Empty(t, repo.users, "no user is created without a free username")
+}
+
+func TestCreateUser_UsernameRequired(t *testing.T) {
+	ctx := context.Background()
+	repo := &usernameUniqueUserRepo{}
+	service := newUsernamePolicyTestService(repo, config.UsernamePolicyConfig{Required: true, Unique: true, Generate: config.UsernameGenerateNone})
+
+	_, err := service.CreateUser(ctx, createRequest("9876543210", nil, stringPtr("Ramesh")))
+	require.Error(t, err)
+	assert.IsType(t, &errors.ValidationError{}, err)
+
+	created, err := service.CreateUser(ctx, createRequest("9876543210", stringPtr("ramesh"), nil))
+	require.NoError(t, err)
+	assert.Equal(t, "ramesh", *created.Username)
+
+	// A generated username satisfies the requirement
+	service.SetUsernamePolicy(config.UsernamePolicyConfig{Required: true, Unique: true, Generate: config.UsernameGenerateName})
+	generated, err := service.CreateUser(ctx, createRequest("9876543211", nil, stringPtr("Ramesh")))
+	require.NoError(t, err)
+	require.NotNil(t, generated.Username)
+	assert.Regexp(t, regexp.MustCompile(`^ramesh_\d{4}$`), *generated.Username)
+}
+
+func TestUsernameFromName(t *testing.T) {
+	assert.Equal(t, "ramesh_kumar", usernameFromName("  Ramesh Kumar "))
+	assert.Equal(t, "o_brien_2", usernameFromName("O'Brien #2"))
+	assert.Equal(t, "", usernameFromName("रमेश"))
+	assert.Len(t, usernameFromName(strings.Repeat("ab ", 40)), generatedUsernameMaxBase-1)
+}
@@ -0,0 +1,49 @@
+// Package actor identifies who a request acts for and records them as the creator and updater of
+// the records it writes. The HTTP auth middleware and the gRPC auth interceptor put the
+// authenticated user (or service) ID on the request context under "user_id"; a *gin.Context
+// works as well, since it exposes its keys as context values.
+package actor
+
+import "context"
+
+// System is recorded for changes made outside an authenticated request
+const System = "system"
+
+// FromContext returns the authenticated user or service ID of ctx, or "" when there is none
+func FromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	userID, _ := ctx.Value("user_id").(string)
+	return userID
+}
+
+// OrSystem returns the authenticated user or service ID of ctx, or System when there is none
+func OrSystem(ctx context.Context) string {
+	if userID := FromContext(ctx); userID != "" {
+		return userID
+	}
+	return System
+}
+
+// Stampable is a record with creator and updater fields; models embedding base.BaseModel are
+type Stampable interface {
+	GetCreatedBy() string
+	SetCreatedBy(userID string)
+	SetUpdatedBy(userID string)
+}
+
+// StampCreate records the actor of ctx as the creator and last updater of a new record. A
+// creator set explicitly by the caller is kept.
+func StampCreate(ctx context.Context, record Stampable) {
+	userID := OrSystem(ctx)
+	if record.GetCreatedBy() == "" {
+		record.SetCreatedBy(userID)
+	}
+	record.SetUpdatedBy(userID)
+}
+
+// StampUpdate records the actor of ctx as the last updater of a record
+func StampUpdate(ctx context.Context, record Stampable) {
+	record.SetUpdatedBy(OrSystem(ctx))
+}
@@ -0,0 +1,45 @@
+package actor
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Kisanlink/kisanlink-db/pkg/base"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromContext(t *testing.T) {
+	assert.Empty(t, FromContext(context.Background()))
+	assert.Equal(t, System, OrSystem(context.Background()))
+
+	ctx := context.WithValue(context.Background(), "user_id", "USER1")
+	assert.Equal(t, "USER1", FromContext(ctx))
+	assert.Equal(t, "USER1", OrSystem(ctx))
+
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Set("user_id", "USER2")
+	assert.Equal(t, "USER2", FromContext(c), "gin contexts expose their keys")
+}
+
+func TestStampCreateAndUpdate(t *testing.T) {
+	ctx := context.WithValue(context.Background(), "user_id", "USER1")
+
+	record := base.NewBaseModel("TEST", "")
+	StampCreate(ctx, record)
+	assert.Equal(t, "USER1", record.CreatedBy)
+	assert.Equal(t, "USER1", record.UpdatedBy)
+
+	StampUpdate(context.WithValue(context.Background(), "user_id", "USER2"), record)
+	assert.Equal(t, "USER1", record.CreatedBy)
+	assert.Equal(t, "USER2", record.UpdatedBy)
+
+	// An explicitly set creator is kept
+	imported := base.NewBaseModel("TEST", "")
+	imported.CreatedBy = "IMPORTER"
+	StampCreate(ctx, imported)
+	assert.Equal(t, "IMPORTER", imported.CreatedBy)
+	assert.Equal(t, "USER1", imported.UpdatedBy)
+}
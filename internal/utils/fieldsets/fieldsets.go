@@ -0,0 +1,156 @@
+// Package fieldsets implements sparse fieldsets: a fields=id,name query parameter that trims
+// a response down to the listed top-level JSON fields. Field names are checked against the
+// JSON fields of the response DTO, so a client can only narrow what the endpoint already
+// returns and never reach a field the DTO does not expose.
+package fieldsets
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	organizationResponses "github.com/Kisanlink/aaa-service/v2/internal/entities/responses/organizations"
+	userResponses "github.com/Kisanlink/aaa-service/v2/internal/entities/responses/users"
+)
+
+// Fields selectable through the fields query parameter, per resource.
+// Names are the JSON field names of the DTO each endpoint returns.
+var (
+	OrganizationFields = Of(organizationResponses.OrganizationResponse{})
+	UserFields         = Of(userResponses.UserResponse{})
+	RoleFields         = Of(models.Role{})
+)
+
+// Of returns the sorted top-level JSON field names of dto, including the fields of embedded
+// structs that encoding/json promotes. Fields tagged json:"-" are left out.
+func Of(dto interface{}) []string {
+	seen := make(map[string]bool)
+	collect(reflect.TypeOf(dto), seen)
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func collect(t reflect.Type, seen map[string]bool) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+
+		if field.Anonymous && name == "" {
+			collect(field.Type, seen)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		seen[name] = true
+	}
+}
+
+// Parse validates the fields query parameter against allowed. An empty parameter returns nil,
+// which leaves responses untouched.
+func Parse(fields string, allowed []string) ([]string, error) {
+	if strings.TrimSpace(fields) == "" {
+		return nil, nil
+	}
+
+	var selected []string
+	var unknown []string
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(fields, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		if !contains(allowed, name) {
+			unknown = append(unknown, name)
+			continue
+		}
+		selected = append(selected, name)
+	}
+
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("invalid fields %s: must be among %s", strings.Join(unknown, ", "), strings.Join(allowed, ", "))
+	}
+	return selected, nil
+}
+
+// Project returns data reduced to the selected top-level fields. data is a single DTO or a
+// slice of them; with no fields selected it is returned as is.
+func Project(data interface{}, fields []string) (interface{}, error) {
+	if len(fields) == 0 {
+		return data, nil
+	}
+
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode response: %w", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.UseNumber()
+
+	trimmed := bytes.TrimSpace(encoded)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		var items []map[string]interface{}
+		if err := decoder.Decode(&items); err != nil {
+			return nil, fmt.Errorf("failed to project response: %w", err)
+		}
+		projected := make([]map[string]interface{}, len(items))
+		for i, item := range items {
+			projected[i] = pick(item, fields)
+		}
+		return projected, nil
+	}
+
+	var item map[string]interface{}
+	if err := decoder.Decode(&item); err != nil {
+		return nil, fmt.Errorf("failed to project response: %w", err)
+	}
+	return pick(item, fields), nil
+}
+
+func pick(item map[string]interface{}, fields []string) map[string]interface{} {
+	if item == nil {
+		return nil
+	}
+	projected := make(map[string]interface{}, len(fields))
+	for _, name := range fields {
+		if value, ok := item[name]; ok {
+			projected[name] = value
+		}
+	}
+	return projected
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,95 @@
+package fieldsets
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	organizationResponses "github.com/Kisanlink/aaa-service/v2/internal/entities/responses/organizations"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOf(t *testing.T) {
+	assert.Equal(t,
+		[]string{"created_at", "deleted_at", "description", "id", "is_active", "name", "parent_id", "type", "updated_at"},
+		OrganizationFields)
+
+	// Role embeds *base.BaseModel, whose fields encoding/json promotes
+	assert.Contains(t, RoleFields, "id")
+	assert.Contains(t, RoleFields, "created_at")
+	assert.Contains(t, RoleFields, "name")
+	assert.Contains(t, UserFields, "phone_number")
+
+	type dto struct {
+		Visible  string `json:"visible"`
+		Hidden   string `json:"-"`
+		internal string
+	}
+	assert.Equal(t, []string{"visible"}, Of(dto{internal: "x"}))
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		fields  string
+		want    []string
+		wantErr bool
+	}{
+		{name: "empty keeps the full response", fields: "", want: nil},
+		{name: "blank keeps the full response", fields: "  ", want: nil},
+		{name: "selected fields", fields: "id,name,is_active", want: []string{"id", "name", "is_active"}},
+		{name: "spaces and duplicates", fields: " id , name,id,", want: []string{"id", "name"}},
+		{name: "unknown field", fields: "id,password_hash", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.fields, OrganizationFields)
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "password_hash")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestProject(t *testing.T) {
+	now := time.Now()
+	org := &organizationResponses.OrganizationResponse{
+		ID:        "ORG1",
+		Name:      "Kisan FPO",
+		Type:      "fpo",
+		IsActive:  true,
+		CreatedAt: &now,
+	}
+
+	single, err := Project(org, []string{"id", "name"})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id":"ORG1","name":"Kisan FPO"}`, marshal(t, single))
+
+	list, err := Project([]*organizationResponses.OrganizationResponse{org, org}, []string{"id", "is_active"})
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"id":"ORG1","is_active":true},{"id":"ORG1","is_active":true}]`, marshal(t, list))
+
+	// omitted fields are skipped rather than sent as null
+	role := models.NewRole("viewer", "", models.RoleScopeGlobal)
+	projected, err := Project(role, []string{"name", "deleted_at"})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"name":"viewer"}`, marshal(t, projected))
+
+	unchanged, err := Project(org, nil)
+	require.NoError(t, err)
+	assert.Same(t, org, unchanged)
+}
+
+func marshal(t *testing.T, v interface{}) string {
+	t.Helper()
+	encoded, err := json.Marshal(v)
+	require.NoError(t, err)
+	return string(encoded)
+}
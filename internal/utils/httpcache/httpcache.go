@@ -0,0 +1,86 @@
+// Package httpcache adds conditional GET support to single-resource endpoints. A handler passes
+// the payload it is about to send together with the resource's last modification time; the
+// response gets ETag and Last-Modified headers, and a request whose If-None-Match or
+// If-Modified-Since validator still matches is answered with 304 Not Modified and no body.
+package httpcache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ETag returns a weak entity tag for the JSON encoding of entity. Payloads carry their
+// updated_at (and version, where the resource has one), so any change yields a new tag.
+// The tag is weak because the response envelope around the payload is not byte-identical
+// between requests.
+func ETag(entity interface{}) (string, error) {
+	data, err := json.Marshal(entity)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return `W/"` + hex.EncodeToString(sum[:16]) + `"`, nil
+}
+
+// NotModified sets the ETag and Last-Modified headers for entity and reports whether the
+// request's validators still match. When they do, the 304 response has been written and the
+// handler must not send a body. A zero lastModified omits Last-Modified and disables
+// If-Modified-Since. If-None-Match takes precedence over If-Modified-Since, as in RFC 9110.
+func NotModified(c *gin.Context, entity interface{}, lastModified time.Time) bool {
+	etag, err := ETag(entity)
+	if err != nil {
+		return false
+	}
+
+	c.Header("ETag", etag)
+	if !lastModified.IsZero() {
+		c.Header("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	method := c.Request.Method
+	if method != http.MethodGet && method != http.MethodHead {
+		return false
+	}
+
+	if !isFresh(c.Request.Header, etag, lastModified) {
+		return false
+	}
+
+	c.AbortWithStatus(http.StatusNotModified)
+	return true
+}
+
+func isFresh(header http.Header, etag string, lastModified time.Time) bool {
+	if inm := header.Get("If-None-Match"); inm != "" {
+		return etagMatches(inm, etag)
+	}
+
+	ims := header.Get("If-Modified-Since")
+	if ims == "" || lastModified.IsZero() {
+		return false
+	}
+	since, err := http.ParseTime(ims)
+	if err != nil {
+		return false
+	}
+	// HTTP dates have second precision
+	return !lastModified.Truncate(time.Second).After(since)
+}
+
+// etagMatches applies the weak comparison of If-None-Match to a comma-separated tag list
+func etagMatches(list, etag string) bool {
+	want := strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(list, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == want {
+			return true
+		}
+	}
+	return false
+}
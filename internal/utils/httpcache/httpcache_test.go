@@ -0,0 +1,81 @@
+package httpcache
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type resource struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func newRouter(current *resource) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/resource", func(c *gin.Context) {
+		if NotModified(c, current, current.UpdatedAt) {
+			return
+		}
+		c.JSON(http.StatusOK, current)
+	})
+	return router
+}
+
+func get(router *gin.Engine, header map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/resource", nil)
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestNotModified_ETag(t *testing.T) {
+	current := &resource{ID: "ROLE1", Name: "viewer", UpdatedAt: time.Date(2026, 3, 1, 10, 0, 0, 500, time.UTC)}
+	router := newRouter(current)
+
+	first := get(router, nil)
+	require.Equal(t, http.StatusOK, first.Code)
+	etag := first.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+	assert.Equal(t, "Sun, 01 Mar 2026 10:00:00 GMT", first.Header().Get("Last-Modified"))
+
+	second := get(router, map[string]string{"If-None-Match": etag})
+	assert.Equal(t, http.StatusNotModified, second.Code)
+	assert.Empty(t, second.Body.String())
+	assert.Equal(t, etag, second.Header().Get("ETag"))
+
+	listed := get(router, map[string]string{"If-None-Match": `"other", ` + etag})
+	assert.Equal(t, http.StatusNotModified, listed.Code, "any tag of the list may match")
+
+	current.Name = "editor"
+	current.UpdatedAt = current.UpdatedAt.Add(time.Minute)
+	changed := get(router, map[string]string{"If-None-Match": etag})
+	assert.Equal(t, http.StatusOK, changed.Code)
+	assert.NotEqual(t, etag, changed.Header().Get("ETag"))
+}
+
+func TestNotModified_IfModifiedSince(t *testing.T) {
+	current := &resource{ID: "ORG1", UpdatedAt: time.Date(2026, 3, 1, 10, 0, 0, 500, time.UTC)}
+	router := newRouter(current)
+
+	first := get(router, nil)
+	lastModified := first.Header().Get("Last-Modified")
+
+	assert.Equal(t, http.StatusNotModified, get(router, map[string]string{"If-Modified-Since": lastModified}).Code)
+	assert.Equal(t, http.StatusOK, get(router, map[string]string{"If-Modified-Since": "Sun, 01 Mar 2026 09:59:59 GMT"}).Code)
+	assert.Equal(t, http.StatusOK, get(router, map[string]string{"If-Modified-Since": "yesterday"}).Code)
+	assert.Equal(t, http.StatusOK, get(router, map[string]string{
+		"If-Modified-Since": lastModified,
+		"If-None-Match":     `W/"stale"`,
+	}).Code, "If-None-Match takes precedence")
+}
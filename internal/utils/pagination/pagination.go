@@ -0,0 +1,133 @@
+// Package pagination parses the pagination query parameters of list endpoints. A page is chosen
+// with limit and offset, or with their aliases per_page and page (1-based); limit/offset win
+// when both forms are given. Malformed and out-of-range values are rejected, and a limit above
+// the route's cap is lowered to the cap.
+package pagination
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Defaults are the default page size and the page size cap of a route. Zero fields fall back to
+// the service-wide defaults, so a route only sets what it overrides.
+type Defaults struct {
+	Limit    int
+	MaxLimit int
+}
+
+// maxOffset keeps offsets within the range of a 32-bit SQL OFFSET
+const maxOffset = math.MaxInt32
+
+// Service-wide defaults, see SetDefaults
+var standard = Defaults{Limit: 10, MaxLimit: 100}
+
+// SetDefaults replaces the service-wide defaults. Non-positive fields keep the current value, and
+// the default limit never exceeds the cap.
+func SetDefaults(defaults Defaults) {
+	if defaults.Limit > 0 {
+		standard.Limit = defaults.Limit
+	}
+	if defaults.MaxLimit > 0 {
+		standard.MaxLimit = defaults.MaxLimit
+	}
+	if standard.Limit > standard.MaxLimit {
+		standard.Limit = standard.MaxLimit
+	}
+}
+
+// Standard returns the service-wide defaults
+func Standard() Defaults {
+	return standard
+}
+
+// Params is a validated page request
+type Params struct {
+	Limit  int
+	Offset int
+}
+
+// Page returns the 1-based page number that starts at Offset
+func (p Params) Page() int {
+	return p.Offset/p.Limit + 1
+}
+
+// ParsePagination reads limit/per_page and offset/page from the query string of c
+func ParsePagination(c *gin.Context, defaults Defaults) (Params, error) {
+	defaults = defaults.resolve()
+
+	limit, err := queryInt(c, "limit", "per_page")
+	if err != nil {
+		return Params{}, err
+	}
+	if limit == nil {
+		limit = &defaults.Limit
+	}
+	if *limit < 1 {
+		return Params{}, fmt.Errorf("limit must be greater than 0")
+	}
+	params := Params{Limit: min(*limit, defaults.MaxLimit)}
+
+	offset, err := queryInt(c, "offset")
+	if err != nil {
+		return Params{}, err
+	}
+	if offset != nil {
+		if *offset < 0 {
+			return Params{}, fmt.Errorf("offset cannot be negative")
+		}
+		if *offset > maxOffset {
+			return Params{}, fmt.Errorf("offset is too large")
+		}
+		params.Offset = *offset
+		return params, nil
+	}
+
+	page, err := queryInt(c, "page")
+	if err != nil {
+		return Params{}, err
+	}
+	if page != nil {
+		if *page < 1 {
+			return Params{}, fmt.Errorf("page must be greater than 0")
+		}
+		if *page-1 > maxOffset/params.Limit {
+			return Params{}, fmt.Errorf("page is too large")
+		}
+		params.Offset = (*page - 1) * params.Limit
+	}
+
+	return params, nil
+}
+
+func (d Defaults) resolve() Defaults {
+	if d.MaxLimit <= 0 {
+		d.MaxLimit = standard.MaxLimit
+	}
+	if d.Limit <= 0 {
+		d.Limit = standard.Limit
+	}
+	if d.Limit > d.MaxLimit {
+		d.Limit = d.MaxLimit
+	}
+	return d
+}
+
+// queryInt parses the first of names present in the query string, returning nil when none is
+func queryInt(c *gin.Context, names ...string) (*int, error) {
+	for _, name := range names {
+		raw, ok := c.GetQuery(name)
+		if !ok || raw == "" {
+			continue
+		}
+		value, err := strconv.Atoi(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s parameter", name)
+		}
+		return &value, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,92 @@
+package pagination
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newContext(query string) *gin.Context {
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("GET", "/items?"+query, nil)
+	return c
+}
+
+func TestParsePagination(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		want    Params
+		wantErr string
+	}{
+		{name: "defaults", query: "", want: Params{Limit: 10, Offset: 0}},
+		{name: "blank values use defaults", query: "limit=&offset=", want: Params{Limit: 10, Offset: 0}},
+		{name: "limit and offset", query: "limit=25&offset=50", want: Params{Limit: 25, Offset: 50}},
+		{name: "limit clamped to cap", query: "limit=1000", want: Params{Limit: 100, Offset: 0}},
+		{name: "per_page and page", query: "per_page=20&page=3", want: Params{Limit: 20, Offset: 40}},
+		{name: "limit wins over per_page", query: "limit=5&per_page=20", want: Params{Limit: 5, Offset: 0}},
+		{name: "offset wins over page", query: "offset=7&page=3", want: Params{Limit: 10, Offset: 7}},
+		{name: "zero limit", query: "limit=0", wantErr: "limit must be greater than 0"},
+		{name: "negative limit", query: "limit=-1", wantErr: "limit must be greater than 0"},
+		{name: "negative offset", query: "offset=-1", wantErr: "offset cannot be negative"},
+		{name: "zero page", query: "page=0", wantErr: "page must be greater than 0"},
+		{name: "non-numeric limit", query: "limit=ten", wantErr: "invalid limit parameter"},
+		{name: "non-numeric per_page", query: "per_page=x", wantErr: "invalid per_page parameter"},
+		{name: "non-numeric offset", query: "offset=1.5", wantErr: "invalid offset parameter"},
+		{name: "overflowing offset", query: "offset=99999999999999999999", wantErr: "invalid offset parameter"},
+		{name: "offset too large", query: "offset=3000000000", wantErr: "offset is too large"},
+		{name: "page too large", query: "limit=100&page=100000000", wantErr: "page is too large"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePagination(newContext(tt.query), Standard())
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Equal(t, tt.wantErr, err.Error())
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParsePaginationRouteDefaults(t *testing.T) {
+	got, err := ParsePagination(newContext(""), Defaults{Limit: 50, MaxLimit: 500})
+	require.NoError(t, err)
+	assert.Equal(t, Params{Limit: 50}, got)
+
+	got, err = ParsePagination(newContext("limit=1000"), Defaults{MaxLimit: 500})
+	require.NoError(t, err)
+	assert.Equal(t, 500, got.Limit)
+
+	// A default above the cap is lowered to the cap
+	got, err = ParsePagination(newContext(""), Defaults{Limit: 50, MaxLimit: 20})
+	require.NoError(t, err)
+	assert.Equal(t, 20, got.Limit)
+}
+
+func TestSetDefaults(t *testing.T) {
+	saved := standard
+	t.Cleanup(func() { standard = saved })
+
+	SetDefaults(Defaults{Limit: 25, MaxLimit: 200})
+	assert.Equal(t, Defaults{Limit: 25, MaxLimit: 200}, Standard())
+
+	// Non-positive fields keep the current value
+	SetDefaults(Defaults{Limit: 0, MaxLimit: -1})
+	assert.Equal(t, Defaults{Limit: 25, MaxLimit: 200}, Standard())
+
+	SetDefaults(Defaults{MaxLimit: 20})
+	assert.Equal(t, Defaults{Limit: 20, MaxLimit: 20}, Standard())
+}
+
+func TestParamsPage(t *testing.T) {
+	assert.Equal(t, 1, Params{Limit: 10, Offset: 0}.Page())
+	assert.Equal(t, 3, Params{Limit: 10, Offset: 25}.Page())
+}
@@ -0,0 +1,29 @@
+// Package utc keeps timestamps in UTC. Timestamps are stored and returned in UTC so that records
+// written by servers in different timezones order correctly; timestamps received through the API
+// are RFC3339 with an offset and are converted on input.
+package utc
+
+import "time"
+
+// Now returns the current time in UTC
+func Now() time.Time {
+	return time.Now().UTC()
+}
+
+// Parse parses an RFC3339 timestamp, which carries its offset, and returns it in UTC
+func Parse(value string) (time.Time, error) {
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return parsed.UTC(), nil
+}
+
+// Ptr returns a copy of t in UTC, or nil when t is nil
+func Ptr(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	converted := t.UTC()
+	return &converted
+}
@@ -0,0 +1,15 @@
+-- Migration: Soft-delete aware unique phone numbers
+-- Date: 2026-10-15
+-- Description: Replaces the table-wide unique constraint on users.phone_number with a partial
+--              unique index on (phone_number, country_code) for non-deleted users, so a
+--              soft-deleted account no longer blocks re-registration with the same number.
+--              Applied automatically after automigration by AddUsersPhonePartialUniqueIndex.
+
+-- Fails if two live users already share a number; resolve those first
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_phone_country_active
+    ON users(phone_number, country_code)
+    WHERE deleted_at IS NULL;
+
+-- Constraint name depends on the GORM version that created it
+ALTER TABLE users DROP CONSTRAINT IF EXISTS uni_users_phone_number;
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_phone_number_key;
@@ -0,0 +1,9 @@
+-- Migration: KYC completion callbacks
+-- Date: 2026-10-16
+-- Description: Adds callback columns to aadhaar_verifications so a caller can register a URL
+--              at OTP generation time and be notified (signed POST) when the verification
+--              reaches VERIFIED or FAILED, instead of polling the status endpoint.
+
+ALTER TABLE aadhaar_verifications ADD COLUMN IF NOT EXISTS callback_url TEXT;
+ALTER TABLE aadhaar_verifications ADD COLUMN IF NOT EXISTS callback_status VARCHAR(50);
+ALTER TABLE aadhaar_verifications ADD COLUMN IF NOT EXISTS callback_sent_at TIMESTAMP;
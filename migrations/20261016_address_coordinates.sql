@@ -0,0 +1,11 @@
+-- Migration: Address coordinates
+-- Date: 2026-10-16
+-- Description: Adds latitude/longitude columns to addresses so addresses can be enriched by a
+--              geocoder. Coordinates stay NULL when geocoding is disabled or fails and can be
+--              backfilled later through POST /api/v1/addresses/{id}/geocode.
+
+ALTER TABLE addresses ADD COLUMN IF NOT EXISTS latitude DOUBLE PRECISION;
+ALTER TABLE addresses ADD COLUMN IF NOT EXISTS longitude DOUBLE PRECISION;
+
+-- Find addresses still waiting for a backfill
+CREATE INDEX IF NOT EXISTS idx_addresses_missing_coordinates ON addresses(id) WHERE latitude IS NULL;
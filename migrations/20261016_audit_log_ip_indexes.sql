@@ -0,0 +1,10 @@
+-- Migration: Audit log client address indexes
+-- Date: 2026-10-16
+-- Description: Indexes audit logs by client IP address for exact matches, and by the address as an
+--              inet for CIDR range queries. The expression must match auditIPExpression in the
+--              audit repository for the planner to use the index.
+
+CREATE INDEX IF NOT EXISTS idx_audit_logs_ip_address ON audit_logs(ip_address);
+
+CREATE INDEX IF NOT EXISTS idx_audit_logs_ip_inet ON audit_logs
+    ((CASE WHEN ip_address ~ '^[0-9A-Fa-f:.]+$' THEN ip_address::inet END));
@@ -0,0 +1,28 @@
+-- Migration: Transactional outbox
+-- Date: 2026-10-16
+-- Description: Events written in the same transaction as the change they describe. The outbox
+--              relay publishes pending events once due, leasing them with locked_until, and
+--              retries failed publishes with backoff until they are sent or marked failed.
+
+CREATE TABLE IF NOT EXISTS outbox_events (
+    id VARCHAR(255) PRIMARY KEY,
+    topic VARCHAR(100) NOT NULL,
+    aggregate_type VARCHAR(50) NOT NULL,
+    aggregate_id VARCHAR(255) NOT NULL,
+    payload JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL,
+    locked_until TIMESTAMPTZ,
+    last_error TEXT,
+    sent_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ,
+    created_by VARCHAR(255),
+    updated_by VARCHAR(255),
+    deleted_by VARCHAR(255)
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_events_status_next_attempt ON outbox_events(status, next_attempt_at);
+CREATE INDEX IF NOT EXISTS idx_outbox_events_aggregate_id ON outbox_events(aggregate_id);
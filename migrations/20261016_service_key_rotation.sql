@@ -0,0 +1,11 @@
+-- Migration: Service API key rotation
+-- Date: 2026-10-16
+-- Description: Keeps the API key replaced by a credential rotation valid until
+--              previous_api_key_expires_at, so services can switch to the new key without downtime.
+
+ALTER TABLE services ADD COLUMN IF NOT EXISTS previous_api_key VARCHAR(255);
+ALTER TABLE services ADD COLUMN IF NOT EXISTS previous_api_key_expires_at TIMESTAMPTZ;
+ALTER TABLE services ADD COLUMN IF NOT EXISTS api_key_rotated_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_services_previous_api_key ON services(previous_api_key)
+    WHERE previous_api_key IS NOT NULL;
@@ -57,6 +57,9 @@ CREATE TABLE aadhaar_verifications (
 	address_json JSONB,
 	attempts INT DEFAULT 0,
 	last_attempt_at TIMESTAMP,
+	callback_url TEXT,
+	callback_status VARCHAR(50),
+	callback_sent_at TIMESTAMP,
 	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 	deleted_at TIMESTAMP,
@@ -0,0 +1,112 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// AddUsersPhonePartialUniqueIndex replaces the table-wide unique constraint on users.phone_number with a
+// partial unique index on (phone_number, country_code) WHERE deleted_at IS NULL, so a soft-deleted account
+// no longer blocks re-registration while two live accounts still cannot share a number.
+// It is idempotent and safe to run on every start.
+func AddUsersPhonePartialUniqueIndex(ctx context.Context, db *gorm.DB, logger *zap.Logger) error {
+	if logger != nil {
+		logger.Info("Starting users phone partial unique index migration")
+	}
+
+	// Step 1: Refuse to continue if live duplicates exist; the index build would fail anyway
+	var duplicateCount int64
+	duplicateSQL := `
+		SELECT COUNT(*) FROM (
+			SELECT phone_number, country_code
+			FROM users
+			WHERE deleted_at IS NULL
+			GROUP BY phone_number, country_code
+			HAVING COUNT(*) > 1
+		) duplicates`
+
+	if err := db.WithContext(ctx).Raw(duplicateSQL).Scan(&duplicateCount).Error; err != nil {
+		return fmt.Errorf("failed to check for duplicate phone numbers: %w", err)
+	}
+
+	if duplicateCount > 0 {
+		if logger != nil {
+			logger.Error("Active users share phone numbers; resolve duplicates before the unique index can be created",
+				zap.Int64("duplicate_phone_numbers", duplicateCount))
+		}
+		return fmt.Errorf("found %d phone numbers shared by active users", duplicateCount)
+	}
+
+	// Step 2: Create the partial unique index before dropping the old constraint so uniqueness is never unenforced
+	createIndexSQL := fmt.Sprintf(`CREATE UNIQUE INDEX IF NOT EXISTS %s
+		ON users(phone_number, country_code)
+		WHERE deleted_at IS NULL`, models.UsersPhoneActiveUniqueIndex)
+
+	if err := db.WithContext(ctx).Exec(createIndexSQL).Error; err != nil {
+		if logger != nil {
+			logger.Error("Failed to create partial unique index", zap.String("index", models.UsersPhoneActiveUniqueIndex), zap.Error(err))
+		}
+		return fmt.Errorf("failed to create %s: %w", models.UsersPhoneActiveUniqueIndex, err)
+	}
+
+	// Step 3: Drop any table-wide unique constraint on phone_number alone.
+	// Its name depends on the GORM version that created it (uni_users_phone_number or users_phone_number_key).
+	var constraintNames []string
+	constraintSQL := `
+		SELECT con.conname
+		FROM pg_constraint con
+		JOIN pg_class rel ON rel.oid = con.conrelid
+		JOIN pg_attribute att ON att.attrelid = rel.oid AND att.attnum = ANY(con.conkey)
+		WHERE rel.relname = 'users'
+		  AND con.contype = 'u'
+		  AND array_length(con.conkey, 1) = 1
+		  AND att.attname = 'phone_number'`
+
+	if err := db.WithContext(ctx).Raw(constraintSQL).Scan(&constraintNames).Error; err != nil {
+		return fmt.Errorf("failed to look up phone number unique constraints: %w", err)
+	}
+
+	for _, name := range constraintNames {
+		dropSQL := fmt.Sprintf(`ALTER TABLE users DROP CONSTRAINT IF EXISTS %q`, name)
+		if err := db.WithContext(ctx).Exec(dropSQL).Error; err != nil {
+			if logger != nil {
+				logger.Error("Failed to drop phone number unique constraint", zap.String("constraint", name), zap.Error(err))
+			}
+			return fmt.Errorf("failed to drop constraint %s: %w", name, err)
+		}
+		if logger != nil {
+			logger.Info("Dropped table-wide phone number unique constraint", zap.String("constraint", name))
+		}
+	}
+
+	if logger != nil {
+		logger.Info("Users phone partial unique index migration completed",
+			zap.String("index", models.UsersPhoneActiveUniqueIndex),
+			zap.Int("dropped_constraints", len(constraintNames)))
+	}
+
+	return nil
+}
+
+// DropUsersPhonePartialUniqueIndex removes the partial unique index (rollback).
+// The table-wide constraint is not restored because soft-deleted rows may now share numbers with live ones.
+func DropUsersPhonePartialUniqueIndex(ctx context.Context, db *gorm.DB, logger *zap.Logger) error {
+	if logger != nil {
+		logger.Info("Rolling back users phone partial unique index migration")
+	}
+
+	dropSQL := fmt.Sprintf("DROP INDEX IF EXISTS %s", models.UsersPhoneActiveUniqueIndex)
+	if err := db.WithContext(ctx).Exec(dropSQL).Error; err != nil {
+		return fmt.Errorf("failed to drop %s: %w", models.UsersPhoneActiveUniqueIndex, err)
+	}
+
+	if logger != nil {
+		logger.Info("Dropped partial unique index", zap.String("index", models.UsersPhoneActiveUniqueIndex))
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// AddUsersUsernameCaseInsensitiveIndex creates a partial unique index on LOWER(username) WHERE deleted_at IS NULL,
+// so two live accounts cannot hold usernames differing only in case and GetByUsername lookups stay unambiguous.
+// It is idempotent and safe to run on every start.
+func AddUsersUsernameCaseInsensitiveIndex(ctx context.Context, db *gorm.DB, logger *zap.Logger) error {
+	if logger != nil {
+		logger.Info("Starting users username case-insensitive index migration")
+	}
+
+	// Step 1: Refuse to continue if live duplicates exist; the index build would fail anyway
+	var duplicateCount int64
+	duplicateSQL := `
+		SELECT COUNT(*) FROM (
+			SELECT LOWER(username)
+			FROM users
+			WHERE deleted_at IS NULL AND username IS NOT NULL
+			GROUP BY LOWER(username)
+			HAVING COUNT(*) > 1
+		) duplicates`
+
+	if err := db.WithContext(ctx).Raw(duplicateSQL).Scan(&duplicateCount).Error; err != nil {
+		return fmt.Errorf("failed to check for duplicate usernames: %w", err)
+	}
+
+	if duplicateCount > 0 {
+		if logger != nil {
+			logger.Error("Active users share usernames differing only in case; rename them before the unique index can be created",
+				zap.Int64("duplicate_usernames", duplicateCount))
+		}
+		return fmt.Errorf("found %d usernames shared case-insensitively by active users", duplicateCount)
+	}
+
+	// Step 2: Create the index
+	createIndexSQL := fmt.Sprintf(`CREATE UNIQUE INDEX IF NOT EXISTS %s
+		ON users(LOWER(username))
+		WHERE deleted_at IS NULL AND username IS NOT NULL`, models.UsersUsernameLowerUniqueIndex)
+
+	if err := db.WithContext(ctx).Exec(createIndexSQL).Error; err != nil {
+		if logger != nil {
+			logger.Error("Failed to create case-insensitive username index", zap.String("index", models.UsersUsernameLowerUniqueIndex), zap.Error(err))
+		}
+		return fmt.Errorf("failed to create %s: %w", models.UsersUsernameLowerUniqueIndex, err)
+	}
+
+	if logger != nil {
+		logger.Info("Users username case-insensitive index migration completed",
+			zap.String("index", models.UsersUsernameLowerUniqueIndex))
+	}
+
+	return nil
+}
+
+// DropUsersUsernameCaseInsensitiveIndex removes the case-insensitive username index (rollback).
+// The table-wide unique constraint on username still rejects exact duplicates.
+func DropUsersUsernameCaseInsensitiveIndex(ctx context.Context, db *gorm.DB, logger *zap.Logger) error {
+	if logger != nil {
+		logger.Info("Rolling back users username case-insensitive index migration")
+	}
+
+	dropSQL := fmt.Sprintf("DROP INDEX IF EXISTS %s", models.UsersUsernameLowerUniqueIndex)
+	if err := db.WithContext(ctx).Exec(dropSQL).Error; err != nil {
+		return fmt.Errorf("failed to drop %s: %w", models.UsersUsernameLowerUniqueIndex, err)
+	}
+
+	if logger != nil {
+		logger.Info("Dropped case-insensitive username index", zap.String("index", models.UsersUsernameLowerUniqueIndex))
+	}
+
+	return nil
+}
@@ -0,0 +1,115 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Kisanlink/aaa-service/v2/pkg/phonenumber"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// DefaultPhoneBackfillBatchSize is the number of users read per batch by BackfillUsersPhoneE164
+const DefaultPhoneBackfillBatchSize = 500
+
+// PhoneBackfillResult summarizes a phone number backfill run
+type PhoneBackfillResult struct {
+	Scanned    int
+	Normalized int
+	// InvalidUserIDs lists users whose stored number cannot be parsed; they keep a NULL phone_e164
+	InvalidUserIDs []string
+	// ConflictUserIDs lists live users whose normalized number is already held by another live user
+	ConflictUserIDs []string
+}
+
+// BackfillUsersPhoneE164 canonicalizes phone_number and country_code and fills phone_e164 for every user
+// that does not have it yet, soft-deleted users included. Numbers that cannot be parsed, and numbers that
+// turn out to duplicate another live account once normalized, are left untouched and reported for manual
+// review. With dryRun set nothing is written. It is idempotent and can be re-run after fixing reported rows.
+func BackfillUsersPhoneE164(ctx context.Context, db *gorm.DB, logger *zap.Logger, batchSize int, dryRun bool) (*PhoneBackfillResult, error) {
+	if batchSize <= 0 {
+		batchSize = DefaultPhoneBackfillBatchSize
+	}
+	if logger != nil {
+		logger.Info("Starting users phone E.164 backfill", zap.Int("batch_size", batchSize), zap.Bool("dry_run", dryRun))
+	}
+
+	type userPhoneRow struct {
+		ID          string
+		PhoneNumber string
+		CountryCode string
+	}
+
+	result := &PhoneBackfillResult{}
+	lastID := ""
+	for {
+		var rows []userPhoneRow
+		err := db.WithContext(ctx).
+			Table("users").
+			Select("id, phone_number, country_code").
+			Where("phone_e164 IS NULL AND id > ?", lastID).
+			Order("id ASC").
+			Limit(batchSize).
+			Scan(&rows).Error
+		if err != nil {
+			return result, fmt.Errorf("failed to read users for phone backfill: %w", err)
+		}
+		if len(rows) == 0 {
+			break
+		}
+
+		for _, row := range rows {
+			lastID = row.ID
+			result.Scanned++
+
+			number, err := phonenumber.Parse(row.PhoneNumber, row.CountryCode)
+			if err != nil {
+				result.InvalidUserIDs = append(result.InvalidUserIDs, row.ID)
+				if logger != nil {
+					logger.Warn("Skipping user with unparseable phone number", zap.String("user_id", row.ID), zap.Error(err))
+				}
+				continue
+			}
+
+			if dryRun {
+				result.Normalized++
+				continue
+			}
+
+			// UpdateColumns leaves updated_at alone: the number is unchanged, only its representation is
+			err = db.WithContext(ctx).
+				Table("users").
+				Where("id = ?", row.ID).
+				UpdateColumns(map[string]interface{}{
+					"phone_number": number.NationalNumber,
+					"country_code": number.CountryCode,
+					"phone_e164":   number.E164(),
+				}).Error
+			if err != nil {
+				if strings.Contains(err.Error(), "duplicate key") {
+					result.ConflictUserIDs = append(result.ConflictUserIDs, row.ID)
+					if logger != nil {
+						logger.Warn("Normalized phone number already belongs to another active user",
+							zap.String("user_id", row.ID),
+							zap.String("phone_e164", number.E164()))
+					}
+					continue
+				}
+				return result, fmt.Errorf("failed to backfill phone number for user %s: %w", row.ID, err)
+			}
+			result.Normalized++
+		}
+	}
+
+	if logger != nil {
+		logger.Info("Users phone E.164 backfill completed",
+			zap.Int("scanned", result.Scanned),
+			zap.Int("normalized", result.Normalized),
+			zap.Int("invalid", len(result.InvalidUserIDs)),
+			zap.Int("conflicts", len(result.ConflictUserIDs)),
+			zap.Bool("dry_run", dryRun))
+	}
+
+	return result, nil
+}
@@ -0,0 +1,73 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/aaa-service/v2/internal/repositories/tenancy"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// TenantSchemaTables are the tables an isolated organization keeps in its own schema. Every other
+// table stays shared in the public schema. Users stay shared too: logins are not tied to an
+// organization, so credentials must live in the one schema every login reads.
+var TenantSchemaTables = []string{
+	(&models.AuditLog{}).TableName(),
+}
+
+// ProvisionTenantSchema creates the schema of an isolated organization with an empty copy of each
+// tenant table. The copies take the columns, defaults, constraints and indexes of the public tables;
+// foreign keys are not copied. Existing rows are not moved. It is idempotent, so it can be re-run
+// after TenantSchemaTables grows. Returns the schema name.
+func ProvisionTenantSchema(ctx context.Context, db *gorm.DB, orgID string, logger *zap.Logger) (string, error) {
+	schema := tenancy.SchemaName(orgID)
+	if !tenancy.ValidSchemaName(schema) {
+		return "", fmt.Errorf("organization ID %q does not produce a valid schema name", orgID)
+	}
+
+	if logger != nil {
+		logger.Info("Provisioning tenant schema", zap.String("org_id", orgID), zap.String("schema", schema))
+	}
+
+	// Step 1: Only existing organizations get a schema
+	var orgCount int64
+	if err := db.WithContext(ctx).Table((&models.Organization{}).TableName()).
+		Where("id = ? AND deleted_at IS NULL", orgID).Count(&orgCount).Error; err != nil {
+		return "", fmt.Errorf("failed to look up organization: %w", err)
+	}
+	if orgCount == 0 {
+		return "", fmt.Errorf("organization %s not found", orgID)
+	}
+
+	// Step 2: Create the schema and its tables in one transaction so a failure leaves nothing behind.
+	// The schema name is validated above and safe to use unquoted.
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(fmt.Sprintf("CREATE SCHEMA IF NOT EXISTS %s", schema)).Error; err != nil {
+			return fmt.Errorf("failed to create schema %s: %w", schema, err)
+		}
+
+		for _, table := range TenantSchemaTables {
+			createSQL := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s.%s (LIKE public.%s INCLUDING ALL)", schema, table, table)
+			if err := tx.Exec(createSQL).Error; err != nil {
+				return fmt.Errorf("failed to create %s.%s: %w", schema, table, err)
+			}
+			if logger != nil {
+				logger.Info("Tenant table ready", zap.String("schema", schema), zap.String("table", table))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if logger != nil {
+			logger.Error("Failed to provision tenant schema", zap.String("schema", schema), zap.Error(err))
+		}
+		return "", err
+	}
+
+	if logger != nil {
+		logger.Info("Tenant schema provisioned", zap.String("org_id", orgID), zap.String("schema", schema))
+	}
+	return schema, nil
+}
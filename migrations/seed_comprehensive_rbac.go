@@ -13,17 +13,23 @@ import (
 // SeedComprehensiveRBAC seeds all RBAC resources and creates a comprehensive permission matrix
 // This extends the core seed with all resource types defined in the system
 func SeedComprehensiveRBAC(ctx context.Context, primary db.DBManager, logger *zap.Logger) error {
-	if err := seedAllResources(ctx, primary, logger); err != nil {
+	return runComprehensiveRBACSeed(ctx, &rbacSeedRun{primary: primary, logger: logger})
+}
+
+func runComprehensiveRBACSeed(ctx context.Context, run *rbacSeedRun) error {
+	if err := seedAllResources(ctx, run); err != nil {
 		return fmt.Errorf("seed all resources: %w", err)
 	}
-	if err := seedComprehensivePermissions(ctx, primary, logger); err != nil {
+	if err := seedComprehensivePermissions(ctx, run); err != nil {
 		return fmt.Errorf("seed comprehensive permissions: %w", err)
 	}
 	return nil
 }
 
 // seedAllResources creates all resource types defined in the system
-func seedAllResources(ctx context.Context, primary db.DBManager, logger *zap.Logger) error {
+func seedAllResources(ctx context.Context, run *rbacSeedRun) error {
+	primary, logger := run.primary, run.logger
+
 	// Comprehensive list of all resources matching resource.go constants
 	allResources := []struct {
 		Name string
@@ -89,7 +95,7 @@ func seedAllResources(ctx context.Context, primary db.DBManager, logger *zap.Log
 		}
 
 		res := models.NewResource(r.Name, r.Type, r.Desc)
-		if err := primary.Create(ctx, res); err != nil {
+		if err := run.createResource(ctx, res); err != nil {
 			return fmt.Errorf("create resource %s: %w", r.Name, err)
 		}
 		if logger != nil {
@@ -105,7 +111,9 @@ func seedAllResources(ctx context.Context, primary db.DBManager, logger *zap.Log
 
 // seedComprehensivePermissions creates additional permissions for the new resources
 // This complements the core permissions already created by seed_core_roles_permissions.go
-func seedComprehensivePermissions(ctx context.Context, primary db.DBManager, logger *zap.Logger) error {
+func seedComprehensivePermissions(ctx context.Context, run *rbacSeedRun) error {
+	primary, logger := run.primary, run.logger
+
 	// Load all resources
 	var resources []models.Resource
 	emptyFilter := &base.Filter{
@@ -121,6 +129,10 @@ func seedComprehensivePermissions(ctx context.Context, primary db.DBManager, log
 	for i := range resources {
 		resByName[resources[i].Name] = &resources[i]
 	}
+	// In diff mode the resources to be created are not in the database
+	for _, res := range run.plannedResources {
+		resByName[res.Name] = res
+	}
 
 	// Build actions index
 	actIdx, err := buildActionIndexDM(ctx, primary)
@@ -205,6 +217,7 @@ func seedComprehensivePermissions(ctx context.Context, primary db.DBManager, log
 		res := resByName[resourceName]
 		act, ok := actIdx.byName[actionName]
 		if res == nil || !ok {
+			run.skipPermission(resourceName, actionName)
 			if logger != nil {
 				logger.Debug("Skipping permission - resource or action not found",
 					zap.String("resource", resourceName),
@@ -226,7 +239,9 @@ func seedComprehensivePermissions(ctx context.Context, primary db.DBManager, log
 		}
 
 		var perm *models.Permission
-		if len(perms) == 0 {
+		if len(perms) == 0 && run.plannedPermissions[permName] != nil {
+			perm = run.plannedPermissions[permName]
+		} else if len(perms) == 0 {
 			// Create new permission
 			newPerm := models.NewPermissionWithResourceAndAction(
 				permName,
@@ -234,7 +249,7 @@ func seedComprehensivePermissions(ctx context.Context, primary db.DBManager, log
 				res.ID,
 				act.ID,
 			)
-			if err := primary.Create(ctx, newPerm); err != nil {
+			if err := run.createPermission(ctx, newPerm, resourceName, actionName); err != nil {
 				// Try to fetch it again - might exist with a different name/ID collision
 				if err2 := primary.List(ctx, permFilter, &perms); err2 == nil && len(perms) > 0 {
 					perm = &perms[0]
@@ -284,7 +299,7 @@ func seedComprehensivePermissions(ctx context.Context, primary db.DBManager, log
 
 		if len(existingRPs) == 0 {
 			rp := models.NewRolePermission(role.ID, perm.ID)
-			if err := primary.Create(ctx, rp); err != nil {
+			if err := run.assignPermission(ctx, rp, role.Name, permName); err != nil {
 				return fmt.Errorf("create role-permission %s:%s: %w", role.Name, permName, err)
 			}
 			if logger != nil {
@@ -310,6 +325,7 @@ func seedComprehensivePermissions(ctx context.Context, primary db.DBManager, log
 	for roleName, matrix := range permissionSets {
 		role := rolesByName[roleName]
 		if role == nil {
+			run.skipRole(roleName)
 			if logger != nil {
 				logger.Warn("Role not found, skipping permissions", zap.String("role", roleName))
 			}
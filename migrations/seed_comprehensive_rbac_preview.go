@@ -0,0 +1,195 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/kisanlink-db/pkg/db"
+	"go.uber.org/zap"
+)
+
+// errPreviewWrite is returned by any write attempted while previewing the seed
+var errPreviewWrite = errors.New("RBAC seed preview must not write to the database")
+
+// RBACSeedPreview lists what SeedComprehensiveRBAC would create in the current database. The seed
+// only adds rows, so nothing existing is reported as changed.
+type RBACSeedPreview struct {
+	Resources       []RBACSeedResource       `json:"resources"`
+	Permissions     []RBACSeedPermission     `json:"permissions"`
+	RolePermissions []RBACSeedRolePermission `json:"role_permissions"`
+	// MissingRoles are roles of the seed's permission matrix not in the database; the seed skips
+	// their permissions
+	MissingRoles []string `json:"missing_roles"`
+	// SkippedPermissions are resource:action pairs the seed skips because the action does not exist
+	SkippedPermissions []string `json:"skipped_permissions"`
+	UpToDate           bool     `json:"up_to_date"`
+}
+
+// RBACSeedResource is a resource the seed would create
+type RBACSeedResource struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// RBACSeedPermission is a permission the seed would create
+type RBACSeedPermission struct {
+	Name     string `json:"name"`
+	Resource string `json:"resource"`
+	Action   string `json:"action"`
+}
+
+// RBACSeedRolePermission is a permission the seed would grant to a role
+type RBACSeedRolePermission struct {
+	Role       string `json:"role"`
+	Permission string `json:"permission"`
+}
+
+// PreviewComprehensiveRBAC runs the comprehensive RBAC seed in diff mode: it reads the database as
+// the seed does and reports the resources, permissions and role grants the seed would create,
+// without writing anything. Writes are rejected, so it is safe to run against production.
+func PreviewComprehensiveRBAC(ctx context.Context, primary db.DBManager, logger *zap.Logger) (*RBACSeedPreview, error) {
+	if primary == nil {
+		return nil, fmt.Errorf("primary DB manager is nil")
+	}
+
+	run := &rbacSeedRun{
+		primary:            readOnlyDBManager{primary},
+		preview:            &RBACSeedPreview{},
+		plannedPermissions: map[string]*models.Permission{},
+	}
+	if err := runComprehensiveRBACSeed(ctx, run); err != nil {
+		return nil, err
+	}
+
+	preview := run.preview
+	sort.Slice(preview.Resources, func(i, j int) bool { return preview.Resources[i].Name < preview.Resources[j].Name })
+	sort.Slice(preview.Permissions, func(i, j int) bool { return preview.Permissions[i].Name < preview.Permissions[j].Name })
+	sort.Slice(preview.RolePermissions, func(i, j int) bool {
+		a, b := preview.RolePermissions[i], preview.RolePermissions[j]
+		if a.Role != b.Role {
+			return a.Role < b.Role
+		}
+		return a.Permission < b.Permission
+	})
+	sort.Strings(preview.MissingRoles)
+	preview.SkippedPermissions = uniqueSorted(preview.SkippedPermissions)
+	preview.UpToDate = len(preview.Resources) == 0 && len(preview.Permissions) == 0 && len(preview.RolePermissions) == 0
+
+	if logger != nil {
+		logger.Info("Previewed comprehensive RBAC seed",
+			zap.Int("resources", len(preview.Resources)),
+			zap.Int("permissions", len(preview.Permissions)),
+			zap.Int("role_permissions", len(preview.RolePermissions)),
+			zap.Bool("up_to_date", preview.UpToDate))
+	}
+	return preview, nil
+}
+
+// rbacSeedRun carries one run of the comprehensive seed. With preview set the run is in diff mode:
+// the rows the seed would create are recorded instead of created, and remembered so that later
+// steps see them as the real seed would.
+type rbacSeedRun struct {
+	primary db.DBManager
+	logger  *zap.Logger
+
+	preview            *RBACSeedPreview
+	plannedResources   []*models.Resource
+	plannedPermissions map[string]*models.Permission
+}
+
+func (r *rbacSeedRun) createResource(ctx context.Context, res *models.Resource) error {
+	if r.preview == nil {
+		return r.primary.Create(ctx, res)
+	}
+	r.plannedResources = append(r.plannedResources, res)
+	r.preview.Resources = append(r.preview.Resources, RBACSeedResource{Name: res.Name, Type: res.Type})
+	return nil
+}
+
+func (r *rbacSeedRun) createPermission(ctx context.Context, perm *models.Permission, resourceName, actionName string) error {
+	if r.preview == nil {
+		return r.primary.Create(ctx, perm)
+	}
+	r.plannedPermissions[perm.Name] = perm
+	r.preview.Permissions = append(r.preview.Permissions, RBACSeedPermission{Name: perm.Name, Resource: resourceName, Action: actionName})
+	return nil
+}
+
+func (r *rbacSeedRun) assignPermission(ctx context.Context, rp *models.RolePermission, roleName, permName string) error {
+	if r.preview == nil {
+		return r.primary.Create(ctx, rp)
+	}
+	r.preview.RolePermissions = append(r.preview.RolePermissions, RBACSeedRolePermission{Role: roleName, Permission: permName})
+	return nil
+}
+
+func (r *rbacSeedRun) skipRole(roleName string) {
+	if r.preview != nil {
+		r.preview.MissingRoles = append(r.preview.MissingRoles, roleName)
+	}
+}
+
+func (r *rbacSeedRun) skipPermission(resourceName, actionName string) {
+	if r.preview != nil {
+		r.preview.SkippedPermissions = append(r.preview.SkippedPermissions, fmt.Sprintf("%s:%s", resourceName, actionName))
+	}
+}
+
+func uniqueSorted(values []string) []string {
+	sort.Strings(values)
+	unique := values[:0]
+	for i, value := range values {
+		if i == 0 || value != values[i-1] {
+			unique = append(unique, value)
+		}
+	}
+	return unique
+}
+
+// readOnlyDBManager rejects every write, guarding the preview against side effects
+type readOnlyDBManager struct {
+	db.DBManager
+}
+
+func (readOnlyDBManager) Create(ctx context.Context, model interface{}) error { return errPreviewWrite }
+func (readOnlyDBManager) Update(ctx context.Context, model interface{}) error { return errPreviewWrite }
+func (readOnlyDBManager) Delete(ctx context.Context, id interface{}, model interface{}) error {
+	return errPreviewWrite
+}
+func (readOnlyDBManager) SoftDelete(ctx context.Context, id interface{}, model interface{}, deletedBy string) error {
+	return errPreviewWrite
+}
+func (readOnlyDBManager) Restore(ctx context.Context, id interface{}, model interface{}) error {
+	return errPreviewWrite
+}
+func (readOnlyDBManager) CreateMany(ctx context.Context, values []interface{}) error {
+	return errPreviewWrite
+}
+func (readOnlyDBManager) UpdateMany(ctx context.Context, values []interface{}) error {
+	return errPreviewWrite
+}
+func (readOnlyDBManager) DeleteMany(ctx context.Context, ids []interface{}) error {
+	return errPreviewWrite
+}
+func (readOnlyDBManager) AutoMigrateModels(ctx context.Context, values ...interface{}) error {
+	return errPreviewWrite
+}
+
+// RBACSeedPreviewer previews the comprehensive RBAC seed against a database
+type RBACSeedPreviewer struct {
+	primary db.DBManager
+	logger  *zap.Logger
+}
+
+// NewRBACSeedPreviewer creates a previewer of the seed against primary
+func NewRBACSeedPreviewer(primary db.DBManager, logger *zap.Logger) *RBACSeedPreviewer {
+	return &RBACSeedPreviewer{primary: primary, logger: logger}
+}
+
+// PreviewRBACSeed reports what the comprehensive RBAC seed would create, without writing
+func (p *RBACSeedPreviewer) PreviewRBACSeed(ctx context.Context) (*RBACSeedPreview, error) {
+	return PreviewComprehensiveRBAC(ctx, p.primary, p.logger)
+}
@@ -0,0 +1,126 @@
+package migrations
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Kisanlink/aaa-service/v2/internal/entities/models"
+	"github.com/Kisanlink/kisanlink-db/pkg/base"
+	"github.com/Kisanlink/kisanlink-db/pkg/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// previewTestDB holds RBAC rows in memory, answers equality filters and counts writes
+type previewTestDB struct {
+	db.DBManager
+	resources       []models.Resource
+	actions         []models.Action
+	roles           []models.Role
+	permissions     []models.Permission
+	rolePermissions []models.RolePermission
+	writes          int
+}
+
+func (d *previewTestDB) Create(ctx context.Context, model interface{}) error {
+	d.writes++
+	return nil
+}
+
+func (d *previewTestDB) List(ctx context.Context, filter *base.Filter, model interface{}) error {
+	switch out := model.(type) {
+	case *[]models.Resource:
+		*out = matchingRows(d.resources, filter, func(r models.Resource) map[string]interface{} {
+			return map[string]interface{}{"name": r.Name}
+		})
+	case *[]models.Action:
+		*out = d.actions
+	case *[]models.Role:
+		*out = d.roles
+	case *[]models.Permission:
+		*out = matchingRows(d.permissions, filter, func(p models.Permission) map[string]interface{} {
+			return map[string]interface{}{"name": p.Name}
+		})
+	case *[]models.RolePermission:
+		*out = matchingRows(d.rolePermissions, filter, func(rp models.RolePermission) map[string]interface{} {
+			return map[string]interface{}{"role_id": rp.RoleID, "permission_id": rp.PermissionID, "is_active": rp.IsActive}
+		})
+	}
+	return nil
+}
+
+func matchingRows[T any](rows []T, filter *base.Filter, fields func(T) map[string]interface{}) []T {
+	var matched []T
+	for _, row := range rows {
+		values := fields(row)
+		matches := true
+		for _, condition := range filter.Group.Conditions {
+			if value, ok := values[condition.Field]; ok && value != condition.Value {
+				matches = false
+			}
+		}
+		if matches {
+			matched = append(matched, row)
+		}
+	}
+	return matched
+}
+
+func newPreviewTestDB() *previewTestDB {
+	organization := models.NewResource("organization", models.ResourceTypeOrganization, "")
+	read := models.NewAction("read", "")
+	viewer := models.NewRole("viewer", "", models.RoleScopeOrg)
+	orgRead := models.NewPermissionWithResourceAndAction("organization:read", "", organization.ID, read.ID)
+
+	database := &previewTestDB{
+		resources:       []models.Resource{*organization},
+		roles:           []models.Role{*models.NewRole("admin", "", models.RoleScopeOrg), *viewer},
+		permissions:     []models.Permission{*orgRead},
+		rolePermissions: []models.RolePermission{*models.NewRolePermission(viewer.ID, orgRead.ID)},
+	}
+	for _, name := range []string{"read", "create", "delete", "assign", "unassign"} {
+		database.actions = append(database.actions, *models.NewAction(name, ""))
+	}
+	return database
+}
+
+func TestPreviewComprehensiveRBAC(t *testing.T) {
+	database := newPreviewTestDB()
+
+	preview, err := PreviewComprehensiveRBAC(context.Background(), database, zap.NewNop())
+	require.NoError(t, err)
+	assert.Zero(t, database.writes, "the preview does not write")
+	assert.False(t, preview.UpToDate)
+
+	assert.Contains(t, preview.Resources, RBACSeedResource{Name: "group", Type: models.ResourceTypeGroup})
+	assert.NotContains(t, preview.Resources, RBACSeedResource{Name: "organization", Type: models.ResourceTypeOrganization})
+
+	groupRead := RBACSeedPermission{Name: "group:read", Resource: "group", Action: "read"}
+	assert.Contains(t, preview.Permissions, groupRead, "permissions on resources to be created are previewed")
+	count := 0
+	for _, permission := range preview.Permissions {
+		if permission == groupRead {
+			count++
+		}
+		assert.NotEqual(t, "organization:read", permission.Name, "existing permissions are not created again")
+	}
+	assert.Equal(t, 1, count, "a permission granted to several roles is created once")
+
+	assert.Contains(t, preview.RolePermissions, RBACSeedRolePermission{Role: "viewer", Permission: "group:read"})
+	assert.Contains(t, preview.RolePermissions, RBACSeedRolePermission{Role: "admin", Permission: "organization:read"},
+		"existing permissions missing from a role are granted")
+	assert.NotContains(t, preview.RolePermissions, RBACSeedRolePermission{Role: "viewer", Permission: "organization:read"})
+
+	assert.Equal(t, []string{"aaa_admin", "module_admin", "super_admin", "user"}, preview.MissingRoles)
+	assert.Contains(t, preview.SkippedPermissions, "organization:update")
+}
+
+func TestReadOnlyDBManager_RejectsWrites(t *testing.T) {
+	database := newPreviewTestDB()
+	readOnly := readOnlyDBManager{database}
+
+	assert.ErrorIs(t, readOnly.Create(context.Background(), &models.Resource{}), errPreviewWrite)
+	assert.ErrorIs(t, readOnly.Delete(context.Background(), "RES1", &models.Resource{}), errPreviewWrite)
+	assert.Zero(t, database.writes)
+}
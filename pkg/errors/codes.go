@@ -0,0 +1,198 @@
+package errors
+
+import (
+	"net/http"
+	"strings"
+)
+
+// ErrorCode is a stable identifier of an error condition. Codes never change meaning once
+// published, so clients can branch on them instead of parsing messages.
+//
+// Codes are grouped by range:
+//
+//	AAA-1xxx  resource not found
+//	AAA-2xxx  invalid request
+//	AAA-3xxx  authentication
+//	AAA-4xxx  authorization
+//	AAA-5xxx  conflict
+//	AAA-9xxx  server errors
+type ErrorCode string
+
+// Error code catalog. The x000 code of each range is the generic code of its error type.
+const (
+	CodeNotFound             ErrorCode = "AAA-1000"
+	CodeUserNotFound         ErrorCode = "AAA-1001"
+	CodeOrganizationNotFound ErrorCode = "AAA-1002"
+	CodeGroupNotFound        ErrorCode = "AAA-1003"
+	CodeRoleNotFound         ErrorCode = "AAA-1004"
+	CodePermissionNotFound   ErrorCode = "AAA-1005"
+	CodeResourceNotFound     ErrorCode = "AAA-1006"
+	CodeActionNotFound       ErrorCode = "AAA-1007"
+	CodePrincipalNotFound    ErrorCode = "AAA-1008"
+	CodeServiceNotFound      ErrorCode = "AAA-1009"
+	CodeContactNotFound      ErrorCode = "AAA-1010"
+	CodeAddressNotFound      ErrorCode = "AAA-1011"
+	CodeSessionNotFound      ErrorCode = "AAA-1012"
+
+	CodeValidationFailed ErrorCode = "AAA-2000"
+	CodeBadRequest       ErrorCode = "AAA-2001"
+	CodeRateLimited      ErrorCode = "AAA-2002"
+
+	CodeUnauthorized       ErrorCode = "AAA-3000"
+	CodeInvalidCredentials ErrorCode = "AAA-3001"
+	CodeAccountLocked      ErrorCode = "AAA-3002"
+	CodeTokenExpired       ErrorCode = "AAA-3003"
+	CodeInvalidToken       ErrorCode = "AAA-3004"
+
+	CodeForbidden ErrorCode = "AAA-4000"
+
+	CodeConflict        ErrorCode = "AAA-5000"
+	CodeVersionConflict ErrorCode = "AAA-5001"
+
+	CodeInternal       ErrorCode = "AAA-9000"
+	CodeRequestTimeout ErrorCode = "AAA-9001"
+	CodeUnknown        ErrorCode = "AAA-9999"
+)
+
+// CatalogEntry documents an error code
+type CatalogEntry struct {
+	Code        ErrorCode `json:"code"`
+	Name        string    `json:"name"`
+	HTTPStatus  int       `json:"http_status"`
+	Description string    `json:"description"`
+}
+
+var catalog = []CatalogEntry{
+	{CodeNotFound, "not_found", http.StatusNotFound, "The requested entity does not exist"},
+	{CodeUserNotFound, "user_not_found", http.StatusNotFound, "The user does not exist"},
+	{CodeOrganizationNotFound, "organization_not_found", http.StatusNotFound, "The organization does not exist"},
+	{CodeGroupNotFound, "group_not_found", http.StatusNotFound, "The group does not exist"},
+	{CodeRoleNotFound, "role_not_found", http.StatusNotFound, "The role does not exist"},
+	{CodePermissionNotFound, "permission_not_found", http.StatusNotFound, "The permission does not exist"},
+	{CodeResourceNotFound, "resource_not_found", http.StatusNotFound, "The resource does not exist"},
+	{CodeActionNotFound, "action_not_found", http.StatusNotFound, "The action does not exist"},
+	{CodePrincipalNotFound, "principal_not_found", http.StatusNotFound, "The principal does not exist"},
+	{CodeServiceNotFound, "service_not_found", http.StatusNotFound, "The service does not exist"},
+	{CodeContactNotFound, "contact_not_found", http.StatusNotFound, "The contact does not exist"},
+	{CodeAddressNotFound, "address_not_found", http.StatusNotFound, "The address does not exist"},
+	{CodeSessionNotFound, "session_not_found", http.StatusNotFound, "The session does not exist"},
+
+	{CodeValidationFailed, "validation_failed", http.StatusBadRequest, "The request failed validation; see errors for the fields"},
+	{CodeBadRequest, "bad_request", http.StatusBadRequest, "The request is malformed"},
+	{CodeRateLimited, "rate_limited", http.StatusTooManyRequests, "Too many requests; retry later"},
+
+	{CodeUnauthorized, "unauthorized", http.StatusUnauthorized, "Authentication is required"},
+	{CodeInvalidCredentials, "invalid_credentials", http.StatusUnauthorized, "The credentials are invalid"},
+	{CodeAccountLocked, "account_locked", http.StatusUnauthorized, "The account is locked after repeated failed attempts"},
+	{CodeTokenExpired, "token_expired", http.StatusUnauthorized, "The token has expired"},
+	{CodeInvalidToken, "invalid_token", http.StatusUnauthorized, "The token is invalid"},
+
+	{CodeForbidden, "forbidden", http.StatusForbidden, "The caller is not allowed to perform the operation"},
+
+	{CodeConflict, "conflict", http.StatusConflict, "The request conflicts with the current state"},
+	{CodeVersionConflict, "version_conflict", http.StatusConflict, "The entity was modified concurrently; retry with the latest version"},
+
+	{CodeInternal, "internal_error", http.StatusInternalServerError, "An unexpected server error occurred"},
+	{CodeRequestTimeout, "request_timeout", http.StatusGatewayTimeout, "The request did not complete within the server's time limit; retry later"},
+	{CodeUnknown, "unknown_error", http.StatusInternalServerError, "An unclassified error occurred"},
+}
+
+// notFoundCodes maps the subject of "<subject> not found" messages to their specific code
+var notFoundCodes = map[string]ErrorCode{
+	"user":         CodeUserNotFound,
+	"organization": CodeOrganizationNotFound,
+	"group":        CodeGroupNotFound,
+	"role":         CodeRoleNotFound,
+	"permission":   CodePermissionNotFound,
+	"resource":     CodeResourceNotFound,
+	"action":       CodeActionNotFound,
+	"principal":    CodePrincipalNotFound,
+	"service":      CodeServiceNotFound,
+	"contact":      CodeContactNotFound,
+	"address":      CodeAddressNotFound,
+	"session":      CodeSessionNotFound,
+}
+
+// Catalog returns every error code with its documentation
+func Catalog() []CatalogEntry {
+	entries := make([]CatalogEntry, len(catalog))
+	copy(entries, catalog)
+	return entries
+}
+
+// LookupCode returns the catalog entry of a code
+func LookupCode(code ErrorCode) (CatalogEntry, bool) {
+	for _, entry := range catalog {
+		if entry.Code == code {
+			return entry, true
+		}
+	}
+	return CatalogEntry{}, false
+}
+
+// CodeOf returns the error code of err: the specific code it was created with, or else the
+// generic code of its error type. Errors that are not one of this package's types yield
+// CodeUnknown.
+func CodeOf(err error) ErrorCode {
+	switch e := err.(type) {
+	case *ValidationError:
+		return codeOr(e.code, CodeValidationFailed)
+	case *BadRequestError:
+		return codeOr(e.code, CodeBadRequest)
+	case *UnauthorizedError:
+		return codeOr(e.code, CodeUnauthorized)
+	case *ForbiddenError:
+		return codeOr(e.code, CodeForbidden)
+	case *NotFoundError:
+		return codeOr(e.code, CodeNotFound)
+	case *ConflictError:
+		return codeOr(e.code, CodeConflict)
+	case *OptimisticLockError:
+		return CodeVersionConflict
+	case *InternalError:
+		return CodeInternal
+	default:
+		return CodeUnknown
+	}
+}
+
+// CodeForStatus returns the generic code of an HTTP status, for responses without a typed error
+func CodeForStatus(status int) ErrorCode {
+	switch {
+	case status == http.StatusBadRequest:
+		return CodeBadRequest
+	case status == http.StatusUnauthorized:
+		return CodeUnauthorized
+	case status == http.StatusForbidden:
+		return CodeForbidden
+	case status == http.StatusNotFound:
+		return CodeNotFound
+	case status == http.StatusConflict:
+		return CodeConflict
+	case status == http.StatusTooManyRequests:
+		return CodeRateLimited
+	case status == http.StatusGatewayTimeout:
+		return CodeRequestTimeout
+	case status >= http.StatusInternalServerError:
+		return CodeInternal
+	default:
+		return CodeUnknown
+	}
+}
+
+func codeOr(code, fallback ErrorCode) ErrorCode {
+	if code != "" {
+		return code
+	}
+	return fallback
+}
+
+// notFoundCode returns the specific code of a "<subject> not found" message, or "" for any
+// other message
+func notFoundCode(message string) ErrorCode {
+	subject, ok := strings.CutSuffix(strings.ToLower(strings.TrimSpace(message)), " not found")
+	if !ok {
+		return ""
+	}
+	return notFoundCodes[subject]
+}
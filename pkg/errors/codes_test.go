@@ -0,0 +1,71 @@
+package errors
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCodeOf_EachErrorTypeYieldsACode(t *testing.T) {
+	tests := []struct {
+		err  error
+		code ErrorCode
+	}{
+		{NewValidationError("name is required"), CodeValidationFailed},
+		{NewBadRequestError("malformed body"), CodeBadRequest},
+		{NewRateLimitError("30"), CodeRateLimited},
+		{NewUnauthorizedError("login required"), CodeUnauthorized},
+		{NewAuthenticationFailedError(), CodeInvalidCredentials},
+		{NewTokenExpiredError(), CodeTokenExpired},
+		{NewForbiddenError("no access"), CodeForbidden},
+		{NewNotFoundError("widget not found"), CodeNotFound},
+		{NewNotFoundError("user not found"), CodeUserNotFound},
+		{NewNotFoundError("Organization not found"), CodeOrganizationNotFound},
+		{NewSecureNotFoundError("role"), CodeRoleNotFound},
+		{NewConflictError("already exists"), CodeConflict},
+		{NewOptimisticLockError("group", "GRP1", 1, 2), CodeVersionConflict},
+		{NewInternalError(fmt.Errorf("boom")), CodeInternal},
+		{fmt.Errorf("untyped"), CodeUnknown},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.code, CodeOf(tt.err), tt.err.Error())
+	}
+}
+
+func TestCodeOf_ExplicitAndWrappedCodes(t *testing.T) {
+	err := NewConflictError("phone number already registered").WithCode(CodeVersionConflict)
+	assert.Equal(t, CodeVersionConflict, CodeOf(err))
+
+	assert.Equal(t, CodeUserNotFound, CodeOf(WrapError(NewNotFoundError("user not found"), "failed to assign role")))
+}
+
+func TestCatalog_CodesAreUniqueAndMatchErrorStatus(t *testing.T) {
+	seen := map[ErrorCode]bool{}
+	names := map[string]bool{}
+	for _, entry := range Catalog() {
+		assert.False(t, seen[entry.Code], "duplicate code %s", entry.Code)
+		assert.False(t, names[entry.Name], "duplicate name %s", entry.Name)
+		seen[entry.Code] = true
+		names[entry.Name] = true
+	}
+
+	for _, err := range []error{
+		NewValidationError("invalid"), NewUnauthorizedError("login"), NewForbiddenError("denied"),
+		NewNotFoundError("user not found"), NewConflictError("exists"), NewInternalError(nil),
+	} {
+		entry, ok := LookupCode(CodeOf(err))
+		require.True(t, ok, "code of %T is not in the catalog", err)
+		assert.Equal(t, GetErrorCode(err), entry.HTTPStatus, entry.Name)
+	}
+}
+
+func TestCodeForStatus(t *testing.T) {
+	assert.Equal(t, CodeNotFound, CodeForStatus(http.StatusNotFound))
+	assert.Equal(t, CodeRateLimited, CodeForStatus(http.StatusTooManyRequests))
+	assert.Equal(t, CodeInternal, CodeForStatus(http.StatusBadGateway))
+	assert.Equal(t, CodeUnknown, CodeForStatus(http.StatusTeapot))
+}
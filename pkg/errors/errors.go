@@ -9,22 +9,27 @@ import (
 type ValidationError struct {
 	message string
 	details []string
+	code    ErrorCode
 }
 
 type NotFoundError struct {
 	message string
+	code    ErrorCode
 }
 
 type ConflictError struct {
 	message string
+	code    ErrorCode
 }
 
 type UnauthorizedError struct {
 	message string
+	code    ErrorCode
 }
 
 type ForbiddenError struct {
 	message string
+	code    ErrorCode
 }
 
 type InternalError struct {
@@ -35,6 +40,7 @@ type InternalError struct {
 type BadRequestError struct {
 	message string
 	details []string
+	code    ErrorCode
 }
 
 type OptimisticLockError struct {
@@ -97,6 +103,37 @@ func (e *OptimisticLockError) Details() map[string]interface{} {
 	return e.details
 }
 
+// WithCode methods attach a catalog code more specific than the default of the error type
+func (e *ValidationError) WithCode(code ErrorCode) *ValidationError {
+	e.code = code
+	return e
+}
+
+func (e *NotFoundError) WithCode(code ErrorCode) *NotFoundError {
+	e.code = code
+	return e
+}
+
+func (e *ConflictError) WithCode(code ErrorCode) *ConflictError {
+	e.code = code
+	return e
+}
+
+func (e *UnauthorizedError) WithCode(code ErrorCode) *UnauthorizedError {
+	e.code = code
+	return e
+}
+
+func (e *ForbiddenError) WithCode(code ErrorCode) *ForbiddenError {
+	e.code = code
+	return e
+}
+
+func (e *BadRequestError) WithCode(code ErrorCode) *BadRequestError {
+	e.code = code
+	return e
+}
+
 // Constructor functions
 func NewValidationError(message string, details ...string) *ValidationError {
 	return &ValidationError{
@@ -108,6 +145,7 @@ func NewValidationError(message string, details ...string) *ValidationError {
 func NewNotFoundError(message string) *NotFoundError {
 	return &NotFoundError{
 		message: sanitizeErrorMessage(message),
+		code:    notFoundCode(message),
 	}
 }
 
@@ -172,6 +210,7 @@ func NewSecureForbiddenError() *ForbiddenError {
 func NewSecureNotFoundError(resourceType string) *NotFoundError {
 	return &NotFoundError{
 		message: fmt.Sprintf("%s not found", resourceType),
+		code:    notFoundCode(fmt.Sprintf("%s not found", resourceType)),
 	}
 }
 
@@ -186,24 +225,28 @@ func NewSecureValidationError() *ValidationError {
 func NewAuthenticationFailedError() *UnauthorizedError {
 	return &UnauthorizedError{
 		message: "Invalid credentials",
+		code:    CodeInvalidCredentials,
 	}
 }
 
 func NewAccountLockedError() *UnauthorizedError {
 	return &UnauthorizedError{
 		message: "Account temporarily locked due to multiple failed attempts",
+		code:    CodeAccountLocked,
 	}
 }
 
 func NewTokenExpiredError() *UnauthorizedError {
 	return &UnauthorizedError{
 		message: "Token has expired",
+		code:    CodeTokenExpired,
 	}
 }
 
 func NewInvalidTokenError() *UnauthorizedError {
 	return &UnauthorizedError{
 		message: "Invalid token",
+		code:    CodeInvalidToken,
 	}
 }
 
@@ -212,6 +255,7 @@ func NewRateLimitError(retryAfter string) *BadRequestError {
 	return &BadRequestError{
 		message: "Rate limit exceeded. Please try again later.",
 		details: []string{fmt.Sprintf("retry_after: %s seconds", retryAfter)},
+		code:    CodeRateLimited,
 	}
 }
 
@@ -349,19 +393,19 @@ func WrapError(err error, message string) error {
 
 	switch e := err.(type) {
 	case *ValidationError:
-		return NewValidationError(fmt.Sprintf("%s: %s", message, e.message), e.details...)
+		return NewValidationError(fmt.Sprintf("%s: %s", message, e.message), e.details...).WithCode(e.code)
 	case *NotFoundError:
-		return NewNotFoundError(fmt.Sprintf("%s: %s", message, e.message))
+		return NewNotFoundError(fmt.Sprintf("%s: %s", message, e.message)).WithCode(e.code)
 	case *ConflictError:
-		return NewConflictError(fmt.Sprintf("%s: %s", message, e.message))
+		return NewConflictError(fmt.Sprintf("%s: %s", message, e.message)).WithCode(e.code)
 	case *UnauthorizedError:
-		return NewUnauthorizedError(fmt.Sprintf("%s: %s", message, e.message))
+		return NewUnauthorizedError(fmt.Sprintf("%s: %s", message, e.message)).WithCode(e.code)
 	case *ForbiddenError:
-		return NewForbiddenError(fmt.Sprintf("%s: %s", message, e.message))
+		return NewForbiddenError(fmt.Sprintf("%s: %s", message, e.message)).WithCode(e.code)
 	case *InternalError:
 		return NewInternalError(fmt.Errorf("%s: %w", message, e.err))
 	case *BadRequestError:
-		return NewBadRequestError(fmt.Sprintf("%s: %s", message, e.message), e.details...)
+		return NewBadRequestError(fmt.Sprintf("%s: %s", message, e.message), e.details...).WithCode(e.code)
 	case *OptimisticLockError:
 		// Preserve optimistic lock error details
 		return e
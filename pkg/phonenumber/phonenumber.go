@@ -0,0 +1,286 @@
+// Package phonenumber canonicalizes user-entered phone numbers to E.164.
+//
+// It implements the subset of libphonenumber-style parsing the service needs: separators are
+// stripped, international ("+" or "00") and national inputs are both accepted, the country calling
+// code is split from the national significant number, and national trunk prefixes such as the
+// leading 0 of Indian or UK local formats are removed. Regions listed in the metadata table are
+// validated against their national number length; other calling codes only get E.164 length checks.
+package phonenumber
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// DefaultCountryCode is assumed when a national number is given without a country code
+const DefaultCountryCode = "+91"
+
+// maxE164Digits is the maximum number of digits in an E.164 number, excluding the leading "+"
+const maxE164Digits = 15
+
+// minNationalDigits is the shortest national significant number accepted for unknown regions
+const minNationalDigits = 4
+
+// ErrInvalidPhoneNumber is returned, wrapped, for every input that cannot be normalized
+var ErrInvalidPhoneNumber = errors.New("invalid phone number")
+
+// Number is a parsed phone number split into its E.164 parts
+type Number struct {
+	// CountryCode is the country calling code with a leading "+", e.g. "+91"
+	CountryCode string
+	// NationalNumber is the national significant number without trunk prefix, e.g. "9876543210"
+	NationalNumber string
+}
+
+// E164 returns the number in E.164 format, e.g. "+919876543210"
+func (n Number) E164() string {
+	return n.CountryCode + n.NationalNumber
+}
+
+// region describes the numbering plan of one country calling code
+type region struct {
+	// lengths lists the valid national significant number lengths
+	lengths []int
+	// trunkPrefix is dialled before national numbers within the country and is not part of E.164
+	trunkPrefix string
+	// leadingDigits, when set, lists the digits a national significant number may start with
+	leadingDigits string
+}
+
+// regions holds numbering plan metadata keyed by calling code digits
+var regions = map[string]region{
+	"1":   {lengths: []int{10}, trunkPrefix: "1", leadingDigits: "23456789"}, // NANP
+	"44":  {lengths: []int{9, 10}, trunkPrefix: "0"},                         // United Kingdom
+	"61":  {lengths: []int{9}, trunkPrefix: "0"},                             // Australia
+	"65":  {lengths: []int{8}},                                               // Singapore
+	"86":  {lengths: []int{10, 11}, trunkPrefix: "0"},                        // China
+	"91":  {lengths: []int{10}, trunkPrefix: "0", leadingDigits: "6789"},     // India (mobile)
+	"92":  {lengths: []int{10}, trunkPrefix: "0"},                            // Pakistan
+	"94":  {lengths: []int{9}, trunkPrefix: "0"},                             // Sri Lanka
+	"234": {lengths: []int{10}, trunkPrefix: "0"},                            // Nigeria
+	"254": {lengths: []int{9}, trunkPrefix: "0"},                             // Kenya
+	"880": {lengths: []int{10}, trunkPrefix: "0"},                            // Bangladesh
+	"971": {lengths: []int{9}, trunkPrefix: "0"},                             // United Arab Emirates
+	"977": {lengths: []int{10}, trunkPrefix: "0"},                            // Nepal
+}
+
+// twoDigitCallingCodes lists the two-digit country calling codes. Of the rest, 1 and 7 are one
+// digit long and every other code is three. Calling codes are prefix-free, so the leading digits of
+// an international number decide its code even for regions missing from the metadata table.
+var twoDigitCallingCodes = map[string]bool{
+	"20": true, "27": true, "30": true, "31": true, "32": true, "33": true, "34": true, "36": true,
+	"39": true, "40": true, "41": true, "43": true, "44": true, "45": true, "46": true, "47": true,
+	"48": true, "49": true, "51": true, "52": true, "53": true, "54": true, "55": true, "56": true,
+	"57": true, "58": true, "60": true, "61": true, "62": true, "63": true, "64": true, "65": true,
+	"66": true, "81": true, "82": true, "84": true, "86": true, "90": true, "91": true, "92": true,
+	"93": true, "94": true, "95": true, "98": true,
+}
+
+// spareCallingCodePrefixes are calling codes, or blocks of them, that the ITU has not assigned
+var spareCallingCodePrefixes = []string{"28", "83", "89", "990", "997", "999"}
+
+// Parse normalizes a phone number. countryCode may be written as "+91", "91" or "0091" and is used
+// for national inputs; it defaults to DefaultCountryCode when empty. A number that already carries
+// an international prefix ("+" or "00") keeps its own calling code.
+func Parse(phoneNumber, countryCode string) (Number, error) {
+	raw := strings.TrimSpace(phoneNumber)
+	if raw == "" {
+		return Number{}, fmt.Errorf("%w: phone number is required", ErrInvalidPhoneNumber)
+	}
+
+	digits, international, err := stripSeparators(raw)
+	if err != nil {
+		return Number{}, err
+	}
+	if digits == "" {
+		return Number{}, fmt.Errorf("%w: phone number contains no digits", ErrInvalidPhoneNumber)
+	}
+
+	defaultCode, err := parseCountryCode(countryCode)
+	if err != nil {
+		return Number{}, err
+	}
+
+	if !international && strings.HasPrefix(digits, "00") {
+		international = true
+		digits = digits[2:]
+	}
+
+	var code, national string
+	if international {
+		code, national, err = splitCallingCode(digits, defaultCode)
+		if err != nil {
+			return Number{}, err
+		}
+	} else {
+		code = defaultCode
+		national = nationalFromLocal(digits, code)
+	}
+
+	if err := validate(code, national); err != nil {
+		return Number{}, err
+	}
+
+	return Number{CountryCode: "+" + code, NationalNumber: national}, nil
+}
+
+// Normalize returns the E.164 form of a phone number
+func Normalize(phoneNumber, countryCode string) (string, error) {
+	number, err := Parse(phoneNumber, countryCode)
+	if err != nil {
+		return "", err
+	}
+	return number.E164(), nil
+}
+
+// stripSeparators removes formatting characters and reports whether the number starts with "+"
+func stripSeparators(raw string) (string, bool, error) {
+	international := strings.HasPrefix(raw, "+")
+	if international {
+		raw = raw[1:]
+	}
+
+	var b strings.Builder
+	for _, r := range raw {
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == ' ' || r == '-' || r == '.' || r == '(' || r == ')' || r == '/':
+			// formatting only
+		default:
+			return "", false, fmt.Errorf("%w: unexpected character %q", ErrInvalidPhoneNumber, r)
+		}
+	}
+
+	return b.String(), international, nil
+}
+
+// parseCountryCode returns the calling code digits of a country code such as "+91"
+func parseCountryCode(countryCode string) (string, error) {
+	code := strings.TrimSpace(countryCode)
+	if code == "" {
+		code = DefaultCountryCode
+	}
+	code = strings.TrimPrefix(code, "+")
+	code = strings.TrimPrefix(code, "00")
+
+	if len(code) == 0 || len(code) > 3 || code[0] == '0' {
+		return "", fmt.Errorf("%w: invalid country code %q", ErrInvalidPhoneNumber, countryCode)
+	}
+	for _, r := range code {
+		if r < '0' || r > '9' {
+			return "", fmt.Errorf("%w: invalid country code %q", ErrInvalidPhoneNumber, countryCode)
+		}
+	}
+
+	return code, nil
+}
+
+// splitCallingCode separates the calling code from an internationally formatted number.
+// Known regions are matched first, then the supplied country code, then any assigned calling code.
+func splitCallingCode(digits, defaultCode string) (string, string, error) {
+	for length := 3; length >= 1; length-- {
+		if len(digits) <= length {
+			continue
+		}
+		if _, ok := regions[digits[:length]]; ok {
+			return digits[:length], digits[length:], nil
+		}
+	}
+
+	if strings.HasPrefix(digits, defaultCode) && len(digits) > len(defaultCode) {
+		return defaultCode, digits[len(defaultCode):], nil
+	}
+
+	if length := callingCodeLength(digits); length > 0 && len(digits) > length {
+		return digits[:length], digits[length:], nil
+	}
+
+	return "", "", fmt.Errorf("%w: unsupported country calling code", ErrInvalidPhoneNumber)
+}
+
+// callingCodeLength returns the length of the calling code digits start with, or 0 when they do not
+// start with an assigned calling code
+func callingCodeLength(digits string) int {
+	if digits == "" || digits[0] == '0' {
+		return 0
+	}
+	for _, prefix := range spareCallingCodePrefixes {
+		if strings.HasPrefix(digits, prefix) {
+			return 0
+		}
+	}
+
+	switch {
+	case digits[0] == '1' || digits[0] == '7':
+		return 1
+	case len(digits) >= 2 && twoDigitCallingCodes[digits[:2]]:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// nationalFromLocal removes a trunk prefix or a repeated calling code from a nationally formatted number
+func nationalFromLocal(digits, code string) string {
+	meta, known := regions[code]
+	if !known {
+		return digits
+	}
+
+	if meta.trunkPrefix != "" && strings.HasPrefix(digits, meta.trunkPrefix) {
+		if trimmed := digits[len(meta.trunkPrefix):]; validLength(meta, trimmed) {
+			return trimmed
+		}
+	}
+
+	// Calling code typed without "+", e.g. "919876543210" for India
+	if strings.HasPrefix(digits, code) {
+		if trimmed := digits[len(code):]; validLength(meta, trimmed) {
+			return trimmed
+		}
+	}
+
+	return digits
+}
+
+func validate(code, national string) error {
+	if len(code)+len(national) > maxE164Digits {
+		return fmt.Errorf("%w: number is too long", ErrInvalidPhoneNumber)
+	}
+
+	meta, known := regions[code]
+	if !known {
+		if len(national) < minNationalDigits {
+			return fmt.Errorf("%w: number is too short", ErrInvalidPhoneNumber)
+		}
+		return nil
+	}
+
+	if !validLength(meta, national) {
+		return fmt.Errorf("%w: expected %s digits for country code +%s, got %d", ErrInvalidPhoneNumber, describeLengths(meta.lengths), code, len(national))
+	}
+	if meta.leadingDigits != "" && !strings.ContainsRune(meta.leadingDigits, rune(national[0])) {
+		return fmt.Errorf("%w: numbers for country code +%s cannot start with %c", ErrInvalidPhoneNumber, code, national[0])
+	}
+
+	return nil
+}
+
+func validLength(meta region, national string) bool {
+	for _, length := range meta.lengths {
+		if len(national) == length {
+			return true
+		}
+	}
+	return false
+}
+
+func describeLengths(lengths []int) string {
+	parts := make([]string, len(lengths))
+	for i, length := range lengths {
+		parts[i] = fmt.Sprintf("%d", length)
+	}
+	return strings.Join(parts, " or ")
+}
@@ -0,0 +1,80 @@
+package phonenumber
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name        string
+		phoneNumber string
+		countryCode string
+		want        string
+	}{
+		{name: "plain national", phoneNumber: "9876543210", countryCode: "+91", want: "+919876543210"},
+		{name: "spaces", phoneNumber: "98765 43210", countryCode: "+91", want: "+919876543210"},
+		{name: "dashes", phoneNumber: "98765-43210", countryCode: "+91", want: "+919876543210"},
+		{name: "mixed separators", phoneNumber: " (987) 654.3210 ", countryCode: "+91", want: "+919876543210"},
+		{name: "leading zero local format", phoneNumber: "09876543210", countryCode: "+91", want: "+919876543210"},
+		{name: "leading zero with separators", phoneNumber: "0 98765-43210", countryCode: "+91", want: "+919876543210"},
+		{name: "calling code without plus", phoneNumber: "919876543210", countryCode: "+91", want: "+919876543210"},
+		{name: "already E.164", phoneNumber: "+919876543210", countryCode: "+91", want: "+919876543210"},
+		{name: "international prefix 00", phoneNumber: "0091 98765 43210", countryCode: "", want: "+919876543210"},
+		{name: "E.164 overrides country code", phoneNumber: "+44 20 7946 0958", countryCode: "+91", want: "+442079460958"},
+		{name: "country code without plus", phoneNumber: "9876543210", countryCode: "91", want: "+919876543210"},
+		{name: "default country code", phoneNumber: "9876543210", countryCode: "", want: "+919876543210"},
+		{name: "UK local with trunk zero", phoneNumber: "020 7946 0958", countryCode: "+44", want: "+442079460958"},
+		{name: "NANP with trunk one", phoneNumber: "1-415-555-2671", countryCode: "+1", want: "+14155552671"},
+		{name: "unknown region", phoneNumber: "612 345 678", countryCode: "+34", want: "+34612345678"},
+		{name: "E.164 from unlisted region", phoneNumber: "+49 1512 3456789", countryCode: "+91", want: "+4915123456789"},
+		{name: "E.164 from unlisted three-digit region", phoneNumber: "+255 712 345 678", countryCode: "", want: "+255712345678"},
+		{name: "E.164 from unlisted one-digit region", phoneNumber: "+7 912 345-67-89", countryCode: "+91", want: "+79123456789"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Normalize(tt.phoneNumber, tt.countryCode)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParse_SplitsParts(t *testing.T) {
+	number, err := Parse("098765-43210", "+91")
+	require.NoError(t, err)
+	assert.Equal(t, "+91", number.CountryCode)
+	assert.Equal(t, "9876543210", number.NationalNumber)
+}
+
+func TestParse_Invalid(t *testing.T) {
+	tests := []struct {
+		name        string
+		phoneNumber string
+		countryCode string
+	}{
+		{name: "empty", phoneNumber: "  ", countryCode: "+91"},
+		{name: "letters", phoneNumber: "98765abcde", countryCode: "+91"},
+		{name: "too short for India", phoneNumber: "98765", countryCode: "+91"},
+		{name: "too long for India", phoneNumber: "987654321012", countryCode: "+91"},
+		{name: "Indian number with invalid leading digit", phoneNumber: "1234567890", countryCode: "+91"},
+		{name: "bad country code", phoneNumber: "9876543210", countryCode: "+9a"},
+		{name: "too long for E.164", phoneNumber: "+34 1234567890123456", countryCode: ""},
+		{name: "unknown calling code in international format", phoneNumber: "+999 1234567", countryCode: "+91"},
+		{name: "unassigned calling code block", phoneNumber: "+281 2345678", countryCode: "+91"},
+		{name: "unlisted region too short", phoneNumber: "+255 712", countryCode: "+91"},
+		{name: "only separators", phoneNumber: "- -", countryCode: "+91"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.phoneNumber, tt.countryCode)
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, ErrInvalidPhoneNumber), err.Error())
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package warnings
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// SimilarityThreshold is the trigram similarity at which two names count as near-duplicates
+const SimilarityThreshold = 0.6
+
+// maxSearchTerms bounds the words of a name used to look up similar names
+const maxSearchTerms = 3
+
+// TrigramSimilarity compares two strings the way PostgreSQL's pg_trgm does: both are
+// lowercased and split into alphanumeric words, each word padded with two spaces in front and
+// one behind, and the result is the number of shared trigrams over the number of distinct
+// trigrams of both strings, from 0 to 1.
+func TrigramSimilarity(a, b string) float64 {
+	ta, tb := trigrams(a), trigrams(b)
+	if len(ta) == 0 || len(tb) == 0 {
+		return 0
+	}
+
+	shared := 0
+	for trigram := range ta {
+		if tb[trigram] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(ta)+len(tb)-shared)
+}
+
+func trigrams(s string) map[string]bool {
+	set := make(map[string]bool)
+	for _, word := range words(s) {
+		padded := []rune("  " + word + " ")
+		for i := 0; i+3 <= len(padded); i++ {
+			set[string(padded[i:i+3])] = true
+		}
+	}
+	return set
+}
+
+// words splits s into lowercase alphanumeric words
+func words(s string) []string {
+	return splitWords(strings.ToLower(s))
+}
+
+func splitWords(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// SearchTerms returns the longest distinct words of name, at least three characters each, to
+// look up candidate near-duplicates with a substring search. Words keep their case since
+// repository searches may be case-sensitive.
+func SearchTerms(name string) []string {
+	seen := make(map[string]bool)
+	var terms []string
+	for _, word := range splitWords(name) {
+		if key := strings.ToLower(word); len([]rune(word)) >= 3 && !seen[key] {
+			seen[key] = true
+			terms = append(terms, word)
+		}
+	}
+
+	sort.SliceStable(terms, func(i, j int) bool { return len(terms[i]) > len(terms[j]) })
+	if len(terms) > maxSearchTerms {
+		terms = terms[:maxSearchTerms]
+	}
+	return terms
+}
+
+// SimilarNames returns the distinct candidates whose similarity to name reaches
+// SimilarityThreshold, most similar first. Candidates equal to name ignoring case are exact
+// duplicates, which callers reject as conflicts, and are left out.
+func SimilarNames(name string, candidates []string) []string {
+	type match struct {
+		name  string
+		score float64
+	}
+
+	seen := make(map[string]bool)
+	var matches []match
+	for _, candidate := range candidates {
+		if seen[candidate] || strings.EqualFold(candidate, name) {
+			continue
+		}
+		seen[candidate] = true
+		if score := TrigramSimilarity(name, candidate); score >= SimilarityThreshold {
+			matches = append(matches, match{candidate, score})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].score > matches[j].score })
+	names := make([]string, 0, len(matches))
+	for _, m := range matches {
+		names = append(names, m.name)
+	}
+	return names
+}
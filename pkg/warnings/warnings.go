@@ -0,0 +1,75 @@
+// Package warnings carries non-fatal findings from services to the HTTP response. A create flow
+// that accepts suspicious but valid input records a warning instead of failing, and the
+// responder returns the warnings next to the data without changing the status code.
+package warnings
+
+import (
+	"context"
+	"sync"
+)
+
+// Code is a stable identifier of a warning condition. Like error codes, warning codes never
+// change meaning once published.
+type Code string
+
+// Warning code catalog
+const (
+	// CodeSimilarName flags a name nearly identical to an existing one
+	CodeSimilarName Code = "AAA-W001"
+)
+
+// Warning is a non-fatal finding about a request that still succeeded
+type Warning struct {
+	Code    Code   `json:"code"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// Collector accumulates the warnings raised while handling one request
+type Collector struct {
+	mu       sync.Mutex
+	warnings []Warning
+}
+
+type collectorKey struct{}
+
+// NewContext returns a context that collects warnings into the returned Collector
+func NewContext(ctx context.Context) (context.Context, *Collector) {
+	collector := &Collector{}
+	return context.WithValue(ctx, collectorKey{}, collector), collector
+}
+
+// FromContext returns the Collector of ctx, or nil when the caller does not collect warnings
+func FromContext(ctx context.Context) *Collector {
+	collector, _ := ctx.Value(collectorKey{}).(*Collector)
+	return collector
+}
+
+// Enabled reports whether ctx collects warnings. Services use it to skip checks whose only
+// outcome is a warning when nobody would see it.
+func Enabled(ctx context.Context) bool {
+	return FromContext(ctx) != nil
+}
+
+// Add records a warning in the Collector of ctx; it does nothing when ctx has none
+func Add(ctx context.Context, warning Warning) {
+	collector := FromContext(ctx)
+	if collector == nil {
+		return
+	}
+
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
+	collector.warnings = append(collector.warnings, warning)
+}
+
+// Warnings returns the warnings collected so far
+func (c *Collector) Warnings() []Warning {
+	if c == nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Warning(nil), c.warnings...)
+}
@@ -0,0 +1,62 @@
+package warnings
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrigramSimilarity(t *testing.T) {
+	assert.Equal(t, 1.0, TrigramSimilarity("Green Valley FPO", "green valley fpo"))
+	assert.Equal(t, 0.0, TrigramSimilarity("", "green valley"))
+	// pg_trgm: similarity('word', 'two words') = 4/11
+	assert.InDelta(t, 4.0/11.0, TrigramSimilarity("word", "two words"), 1e-9)
+
+	tests := []struct {
+		a, b    string
+		similar bool
+	}{
+		{"Kisan Farmers Cooperative", "Kisan Farmer Cooperative", true},
+		{"Green Valley FPO", "Green Valley F.P.O.", true},
+		{"Green Valley FPO", "Green Valley Traders", false},
+		{"Kisan Farmers Cooperative", "Sunrise Dairy", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+" vs "+tt.b, func(t *testing.T) {
+			assert.Equal(t, tt.similar, TrigramSimilarity(tt.a, tt.b) >= SimilarityThreshold)
+		})
+	}
+}
+
+func TestSearchTerms(t *testing.T) {
+	assert.Equal(t, []string{"Cooperative", "Farmers", "Kisan"}, SearchTerms("Kisan Farmers Cooperative of AP kisan"))
+	assert.Equal(t, []string{"ravi"}, SearchTerms("ravi_k"))
+	assert.Empty(t, SearchTerms("a b"))
+}
+
+func TestSimilarNames(t *testing.T) {
+	got := SimilarNames("Kisan Farmers Co", []string{
+		"Kisan Farmer Co",
+		"kisan farmers co",
+		"Sunrise Dairy",
+		"Kisan Farmers Coop",
+		"Kisan Farmer Co",
+	})
+	assert.Equal(t, []string{"Kisan Farmer Co", "Kisan Farmers Coop"}, got)
+}
+
+func TestCollector(t *testing.T) {
+	warning := Warning{Code: CodeSimilarName, Field: "name", Message: "similar"}
+
+	// Without a collector warnings are dropped
+	Add(context.Background(), warning)
+	assert.False(t, Enabled(context.Background()))
+	assert.Nil(t, FromContext(context.Background()).Warnings())
+
+	ctx, collector := NewContext(context.Background())
+	assert.True(t, Enabled(ctx))
+	Add(ctx, warning)
+	assert.Equal(t, []Warning{warning}, collector.Warnings())
+	assert.Same(t, collector, FromContext(ctx))
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/migrations"
+	"github.com/joho/godotenv"
+	"go.uber.org/zap"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Normalizes existing users' phone numbers to E.164 and fills users.phone_e164.
+//
+// Usage: go run ./scripts/backfill_phone_e164 [-dry-run] [-batch-size 500]
+func main() {
+	dryRun := flag.Bool("dry-run", false, "report what would change without writing")
+	batchSize := flag.Int("batch-size", migrations.DefaultPhoneBackfillBatchSize, "number of users read per batch")
+	flag.Parse()
+
+	// Load environment variables
+	if err := godotenv.Load(); err != nil {
+		log.Println("Warning: .env file not found, using system environment variables")
+	}
+
+	// Initialize logger
+	zapLogger, err := zap.NewDevelopment()
+	if err != nil {
+		log.Fatalf("Failed to initialize logger: %v", err)
+	}
+	defer zapLogger.Sync()
+
+	// Get database configuration
+	dbHost := getEnv("DB_POSTGRES_HOST", "localhost")
+	dbPort := getEnv("DB_POSTGRES_PORT", "5432")
+	dbUser := getEnv("DB_POSTGRES_USER", "postgres")
+	dbPassword := getEnv("DB_POSTGRES_PASSWORD", "")
+	dbName := getEnv("DB_POSTGRES_DBNAME", "aaa_service")
+
+	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
+		dbHost, dbPort, dbUser, dbPassword, dbName)
+
+	zapLogger.Info("Connecting to PostgreSQL database",
+		zap.String("host", dbHost),
+		zap.String("port", dbPort),
+		zap.String("database", dbName))
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Warn),
+	})
+	if err != nil {
+		zapLogger.Fatal("Failed to connect to database", zap.Error(err))
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		zapLogger.Fatal("Failed to get SQL DB", zap.Error(err))
+	}
+	defer sqlDB.Close()
+	sqlDB.SetConnMaxLifetime(time.Hour)
+
+	ctx := context.Background()
+	if err := sqlDB.PingContext(ctx); err != nil {
+		zapLogger.Fatal("Failed to ping database", zap.Error(err))
+	}
+
+	result, err := migrations.BackfillUsersPhoneE164(ctx, db, zapLogger, *batchSize, *dryRun)
+	if err != nil {
+		zapLogger.Fatal("Phone number backfill failed", zap.Error(err))
+	}
+
+	if len(result.InvalidUserIDs) > 0 {
+		zapLogger.Warn("Users with invalid phone numbers need manual review", zap.Strings("user_ids", result.InvalidUserIDs))
+	}
+	if len(result.ConflictUserIDs) > 0 {
+		zapLogger.Warn("Users whose normalized phone number duplicates another account need manual review", zap.Strings("user_ids", result.ConflictUserIDs))
+	}
+	if len(result.InvalidUserIDs) > 0 || len(result.ConflictUserIDs) > 0 {
+		os.Exit(2)
+	}
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
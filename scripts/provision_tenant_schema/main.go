@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Kisanlink/aaa-service/v2/migrations"
+	"github.com/joho/godotenv"
+	"go.uber.org/zap"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Creates the schema of an organization whose data is isolated from other tenants.
+// Add the organization to AAA_TENANT_SCHEMA_ORGS and restart the service to start using it.
+//
+// Usage: go run ./scripts/provision_tenant_schema -org <organization-id>
+func main() {
+	orgID := flag.String("org", "", "ID of the organization to isolate")
+	flag.Parse()
+
+	if strings.TrimSpace(*orgID) == "" {
+		fmt.Fprintln(os.Stderr, "usage: provision_tenant_schema -org <organization-id>")
+		os.Exit(2)
+	}
+
+	// Load environment variables
+	if err := godotenv.Load(); err != nil {
+		log.Println("Warning: .env file not found, using system environment variables")
+	}
+
+	// Initialize logger
+	zapLogger, err := zap.NewDevelopment()
+	if err != nil {
+		log.Fatalf("Failed to initialize logger: %v", err)
+	}
+	defer zapLogger.Sync()
+
+	// Get database configuration
+	dbHost := getEnv("DB_POSTGRES_HOST", "localhost")
+	dbPort := getEnv("DB_POSTGRES_PORT", "5432")
+	dbUser := getEnv("DB_POSTGRES_USER", "postgres")
+	dbPassword := getEnv("DB_POSTGRES_PASSWORD", "")
+	dbName := getEnv("DB_POSTGRES_DBNAME", "aaa_service")
+	sslMode := getEnv("DB_POSTGRES_SSLMODE", "disable")
+
+	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
+		dbHost, dbPort, dbUser, dbPassword, dbName, sslMode)
+
+	zapLogger.Info("Connecting to PostgreSQL database",
+		zap.String("host", dbHost),
+		zap.String("port", dbPort),
+		zap.String("database", dbName))
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Warn),
+	})
+	if err != nil {
+		zapLogger.Fatal("Failed to connect to database", zap.Error(err))
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		zapLogger.Fatal("Failed to get SQL DB", zap.Error(err))
+	}
+	defer sqlDB.Close()
+	sqlDB.SetConnMaxLifetime(time.Hour)
+
+	ctx := context.Background()
+	if err := sqlDB.PingContext(ctx); err != nil {
+		zapLogger.Fatal("Failed to ping database", zap.Error(err))
+	}
+
+	schema, err := migrations.ProvisionTenantSchema(ctx, db, strings.TrimSpace(*orgID), zapLogger)
+	if err != nil {
+		zapLogger.Fatal("Tenant schema provisioning failed", zap.Error(err))
+	}
+
+	zapLogger.Info("Add the organization to AAA_TENANT_SCHEMA_ORGS and set AAA_TENANT_SCHEMA_ISOLATION=true to route its data",
+		zap.String("org_id", *orgID),
+		zap.String("schema", schema))
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
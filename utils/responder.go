@@ -8,10 +8,16 @@ import (
 	"github.com/Kisanlink/aaa-service/v2/internal/entities/responses"
 	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
 	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/Kisanlink/aaa-service/v2/pkg/warnings"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
 
+// ConcealForbiddenKey marks a request for a specific resource of a concealed type. SendError answers
+// its forbidden errors with 404, like the route policy does, so handlers' own checks do not reveal
+// which IDs exist either.
+const ConcealForbiddenKey = "conceal_forbidden"
+
 // Responder implements the Responder interface
 type Responder struct {
 	logger interfaces.Logger
@@ -50,6 +56,13 @@ func (r *Responder) SendSuccess(c *gin.Context, statusCode int, data interface{}
 		response["request_id"] = requestID
 	}
 
+	// Non-fatal findings of the service ride along without changing the status
+	if c.Request != nil {
+		if found := warnings.FromContext(c.Request.Context()).Warnings(); len(found) > 0 {
+			response["warnings"] = found
+		}
+	}
+
 	c.JSON(statusCode, response)
 }
 
@@ -73,6 +86,12 @@ func (r *Responder) SendError(c *gin.Context, statusCode int, message string, er
 		requestID = fmt.Sprintf("req_%d", time.Now().UnixNano())
 	}
 
+	if c.GetBool(ConcealForbiddenKey) && (statusCode == http.StatusForbidden || errors.IsForbiddenError(err)) {
+		message = "Resource not found"
+		err = errors.NewNotFoundError(message)
+		statusCode = http.StatusNotFound
+	}
+
 	var errorResponse interface{}
 
 	// Create standardized error response
@@ -84,7 +103,7 @@ func (r *Responder) SendError(c *gin.Context, statusCode int, message string, er
 				Success:   false,
 				Error:     "VALIDATION_ERROR",
 				Message:   message,
-				Code:      "VALIDATION_ERROR",
+				Code:      string(errors.CodeOf(e)),
 				Errors:    e.Details(),
 				Timestamp: time.Now().UTC(),
 				RequestID: requestID,
@@ -92,31 +111,36 @@ func (r *Responder) SendError(c *gin.Context, statusCode int, message string, er
 			errorResponse = response
 			statusCode = http.StatusBadRequest
 		case *errors.BadRequestError:
-			errorResponse = responses.NewBadRequestResponse(message, requestID).
+			errorResponse = responses.NewBadRequestResponse(message, requestID).WithCode(errors.CodeOf(e)).
 				WithDetails(map[string]interface{}{"errors": e.Details()}).ToJSON()
 			statusCode = http.StatusBadRequest
 		case *errors.UnauthorizedError:
-			errorResponse = responses.NewUnauthorizedResponse(message, requestID).ToJSON()
+			errorResponse = responses.NewUnauthorizedResponse(message, requestID).WithCode(errors.CodeOf(e)).ToJSON()
 			statusCode = http.StatusUnauthorized
 		case *errors.ForbiddenError:
-			errorResponse = responses.NewForbiddenResponse(message, requestID).ToJSON()
+			errorResponse = responses.NewForbiddenResponse(message, requestID).WithCode(errors.CodeOf(e)).ToJSON()
 			statusCode = http.StatusForbidden
 		case *errors.NotFoundError:
-			errorResponse = responses.NewNotFoundResponse(message, requestID).ToJSON()
+			errorResponse = responses.NewNotFoundResponse(message, requestID).WithCode(errors.CodeOf(e)).ToJSON()
 			statusCode = http.StatusNotFound
 		case *errors.ConflictError:
-			errorResponse = responses.NewConflictResponse(message, requestID).ToJSON()
+			errorResponse = responses.NewConflictResponse(message, requestID).WithCode(errors.CodeOf(e)).ToJSON()
+			statusCode = http.StatusConflict
+		case *errors.OptimisticLockError:
+			errorResponse = responses.NewConflictResponse(message, requestID).WithCode(errors.CodeOf(e)).
+				WithDetails(e.Details()).ToJSON()
 			statusCode = http.StatusConflict
 		case *errors.InternalError:
 			errorResponse = responses.NewInternalErrorResponse(requestID).ToJSON()
 			statusCode = http.StatusInternalServerError
 		default:
-			errorResponse = responses.NewErrorResponse("GENERIC_ERROR", message, "GENERIC_ERROR").
+			// Untyped errors only carry the status the handler chose
+			errorResponse = responses.NewErrorResponse("GENERIC_ERROR", message, string(errors.CodeForStatus(statusCode))).
 				WithRequestID(requestID).ToJSON()
 		}
 	} else {
 		// No specific error provided
-		errorResponse = responses.NewErrorResponse("GENERIC_ERROR", message, "GENERIC_ERROR").
+		errorResponse = responses.NewErrorResponse("GENERIC_ERROR", message, string(errors.CodeForStatus(statusCode))).
 			WithRequestID(requestID).ToJSON()
 	}
 
@@ -163,7 +187,7 @@ func (r *Responder) SendErrorWithContext(c *gin.Context, statusCode int, message
 	if err != nil {
 		errorResponse = responses.NewErrorResponseFromError(err, requestID)
 	} else {
-		errorResponse = responses.NewErrorResponse("GENERIC_ERROR", message, "GENERIC_ERROR").
+		errorResponse = responses.NewErrorResponse("GENERIC_ERROR", message, string(errors.CodeForStatus(statusCode))).
 			WithRequestID(requestID)
 	}
 
@@ -207,7 +231,7 @@ func (r *Responder) SendErrorWithContext(c *gin.Context, statusCode int, message
 }
 
 // SendValidationError sends a validation error response
-func (r *Responder) SendValidationError(c *gin.Context, errors []string) {
+func (r *Responder) SendValidationError(c *gin.Context, validationErrors []string) {
 	if requestID := c.GetString("request_id"); requestID != "" {
 		c.Header("X-Request-Id", requestID)
 	}
@@ -223,9 +247,9 @@ func (r *Responder) SendValidationError(c *gin.Context, errors []string) {
 	response := gin.H{
 		"success":   false,
 		"timestamp": time.Now().UTC().Format(time.RFC3339),
-		"code":      "VALIDATION_ERROR",
+		"code":      errors.CodeValidationFailed,
 		"message":   "Validation failed",
-		"errors":    errors,
+		"errors":    validationErrors,
 	}
 
 	// Add request ID if available
@@ -235,7 +259,7 @@ func (r *Responder) SendValidationError(c *gin.Context, errors []string) {
 
 	// Log validation errors
 	r.logger.Warn("Validation error response",
-		zap.Strings("errors", errors),
+		zap.Strings("errors", validationErrors),
 		zap.String("path", c.Request.URL.Path),
 		zap.String("method", c.Request.Method),
 		zap.String("ip", c.ClientIP()),
@@ -0,0 +1,92 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Kisanlink/aaa-service/v2/pkg/errors"
+	"github.com/Kisanlink/aaa-service/v2/pkg/warnings"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func respond(t *testing.T, send func(c *gin.Context)) (int, map[string]interface{}) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/users/USER1", nil)
+
+	send(c)
+
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	return w.Code, body
+}
+
+func TestResponder_ErrorResponsesIncludeCatalogCode(t *testing.T) {
+	responder := NewResponder(NewLoggerAdapter(zap.NewNop()))
+
+	tests := []struct {
+		name   string
+		send   func(c *gin.Context)
+		status int
+		code   errors.ErrorCode
+	}{
+		{"specific not found", func(c *gin.Context) {
+			responder.SendError(c, http.StatusNotFound, "User not found", errors.NewNotFoundError("user not found"))
+		}, http.StatusNotFound, errors.CodeUserNotFound},
+		{"validation error", func(c *gin.Context) {
+			responder.SendError(c, http.StatusBadRequest, "Invalid request", errors.NewValidationError("name is required"))
+		}, http.StatusBadRequest, errors.CodeValidationFailed},
+		{"validation messages", func(c *gin.Context) {
+			responder.SendValidationError(c, []string{"name is required"})
+		}, http.StatusBadRequest, errors.CodeValidationFailed},
+		{"conflict", func(c *gin.Context) {
+			responder.SendError(c, http.StatusConflict, "Already exists", errors.NewConflictError("already exists"))
+		}, http.StatusConflict, errors.CodeConflict},
+		{"internal", func(c *gin.Context) {
+			responder.SendInternalError(c, fmt.Errorf("connection reset"))
+		}, http.StatusInternalServerError, errors.CodeInternal},
+		{"untyped error", func(c *gin.Context) {
+			responder.SendError(c, http.StatusForbidden, "Access denied", fmt.Errorf("not a member"))
+		}, http.StatusForbidden, errors.CodeForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := respond(t, tt.send)
+			assert.Equal(t, tt.status, status)
+			assert.Equal(t, string(tt.code), body["code"])
+		})
+	}
+}
+
+func TestResponder_SendSuccessIncludesWarnings(t *testing.T) {
+	responder := NewResponder(NewLoggerAdapter(zap.NewNop()))
+
+	status, body := respond(t, func(c *gin.Context) {
+		responder.SendSuccess(c, http.StatusCreated, gin.H{"id": "ORG1"})
+	})
+	assert.Equal(t, http.StatusCreated, status)
+	assert.NotContains(t, body, "warnings", "no warnings key without warnings")
+
+	status, body = respond(t, func(c *gin.Context) {
+		ctx, _ := warnings.NewContext(c.Request.Context())
+		c.Request = c.Request.WithContext(ctx)
+		warnings.Add(ctx, warnings.Warning{Code: warnings.CodeSimilarName, Field: "name", Message: "similar to Green Valley FPO"})
+		responder.SendSuccess(c, http.StatusCreated, gin.H{"id": "ORG1"})
+	})
+	assert.Equal(t, http.StatusCreated, status, "warnings never change the status")
+	assert.Equal(t, map[string]interface{}{"id": "ORG1"}, body["data"])
+	require.Len(t, body["warnings"], 1)
+	assert.Equal(t, map[string]interface{}{
+		"code":    string(warnings.CodeSimilarName),
+		"field":   "name",
+		"message": "similar to Green Valley FPO",
+	}, body["warnings"].([]interface{})[0])
+}
@@ -2,15 +2,21 @@ package utils
 
 import (
 	"fmt"
+	"reflect"
 	"regexp"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/Kisanlink/aaa-service/v2/internal/interfaces"
 	"github.com/gin-gonic/gin"
 	v10 "github.com/go-playground/validator/v10"
 )
 
+// MaxTextLength caps the characters of any field validated as free text ("text" rule). Fields
+// keep their own, usually smaller, max.
+const MaxTextLength = 10000
+
 // Validator implements the Validator interface
 type Validator struct {
 	validate *v10.Validate
@@ -42,14 +48,19 @@ func NewValidator() interfaces.Validator {
 	if err := validate.RegisterValidation("role_id", validateRoleID); err != nil {
 		panic(fmt.Sprintf("failed to register role_id validation: %v", err))
 	}
+	if err := validate.RegisterValidation("text", validateText); err != nil {
+		panic(fmt.Sprintf("failed to register text validation: %v", err))
+	}
 
 	return &Validator{
 		validate: validate,
 	}
 }
 
-// ValidateStruct validates a struct using the validator tags
+// ValidateStruct validates a struct using the validator tags. Fields with the "text" rule are
+// sanitized in place first (see SanitizeText), so pass a pointer for them to be cleaned.
 func (v *Validator) ValidateStruct(s interface{}) error {
+	sanitizeTextFields(reflect.ValueOf(s), 0)
 	return v.validate.Struct(s)
 }
 
@@ -151,19 +162,87 @@ func (v *Validator) ValidateMPin(mpin string) error {
 
 // SanitizeInput sanitizes user input to prevent injection attacks
 func (v *Validator) SanitizeInput(input string) string {
-	// Remove null bytes
-	input = strings.ReplaceAll(input, "\x00", "")
-
-	// Remove control characters except newline, carriage return, and tab
-	sanitized := ""
-	for _, r := range input {
-		if r >= 32 || r == '\n' || r == '\r' || r == '\t' {
-			sanitized += string(r)
+	return SanitizeText(input)
+}
+
+// SanitizeText cleans free text before it is stored: it drops invalid UTF-8, null bytes (which
+// Postgres text columns reject) and control characters other than newline, carriage return and
+// tab, then trims surrounding whitespace.
+func SanitizeText(input string) string {
+	input = strings.ToValidUTF8(input, "")
+	input = strings.Map(func(r rune) rune {
+		if isDisallowedControl(r) {
+			return -1
+		}
+		return r
+	}, input)
+	return strings.TrimSpace(input)
+}
+
+// TruncateText sanitizes input and cuts it to at most maxLen characters
+func TruncateText(input string, maxLen int) string {
+	input = SanitizeText(input)
+	if maxLen <= 0 || utf8.RuneCountInString(input) <= maxLen {
+		return input
+	}
+	runes := []rune(input)
+	return strings.TrimSpace(string(runes[:maxLen]))
+}
+
+// isDisallowedControl reports control characters free text must not contain
+func isDisallowedControl(r rune) bool {
+	if r == '\n' || r == '\r' || r == '\t' {
+		return false
+	}
+	return r < 32 || r == 0x7f
+}
+
+// sanitizeTextFields applies SanitizeText to the settable string and *string fields of a struct
+// whose validate tag has the "text" rule, descending into nested structs
+func sanitizeTextFields(value reflect.Value, depth int) {
+	if depth > 8 {
+		return
+	}
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return
+	}
+
+	valueType := value.Type()
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Field(i)
+		if !valueType.Field(i).IsExported() || !field.CanSet() {
+			continue
+		}
+
+		if hasValidationRule(valueType.Field(i).Tag.Get("validate"), "text") {
+			switch {
+			case field.Kind() == reflect.String:
+				field.SetString(SanitizeText(field.String()))
+			case field.Kind() == reflect.Ptr && !field.IsNil() && field.Elem().Kind() == reflect.String:
+				field.Elem().SetString(SanitizeText(field.Elem().String()))
+			}
+			continue
+		}
+
+		if field.Kind() == reflect.Struct || (field.Kind() == reflect.Ptr && field.Type().Elem().Kind() == reflect.Struct) {
+			sanitizeTextFields(field, depth+1)
 		}
 	}
+}
 
-	// Trim whitespace
-	return strings.TrimSpace(sanitized)
+func hasValidationRule(tag, rule string) bool {
+	for _, part := range strings.Split(tag, ",") {
+		if strings.TrimSpace(part) == rule {
+			return true
+		}
+	}
+	return false
 }
 
 // ValidateAndSanitizeString validates and sanitizes string input
@@ -517,6 +596,17 @@ func validateUserID(fl v10.FieldLevel) bool {
 	return userIDRegex.MatchString(userID)
 }
 
+// validateText accepts free text that is valid UTF-8, has no null bytes or other disallowed control
+// characters and is at most MaxTextLength characters. ValidateStruct sanitizes these fields first, so
+// this only fails for values that could not be cleaned in place.
+func validateText(fl v10.FieldLevel) bool {
+	text := fl.Field().String()
+	if !utf8.ValidString(text) || utf8.RuneCountInString(text) > MaxTextLength {
+		return false
+	}
+	return strings.IndexFunc(text, isDisallowedControl) < 0
+}
+
 func validateRoleID(fl v10.FieldLevel) bool {
 	roleID := fl.Field().String()
 	if roleID == "" {
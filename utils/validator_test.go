@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"strings"
 	"testing"
 
 	v10 "github.com/go-playground/validator/v10"
@@ -146,3 +147,160 @@ func TestValidateOrganizationIDWithOmitEmpty(t *testing.T) {
 func stringPtr(s string) *string {
 	return &s
 }
+
+func TestValidateStructSanitizesTextFields(t *testing.T) {
+	type nested struct {
+		Note string `validate:"text,max=10"`
+	}
+	type request struct {
+		Name        string  `validate:"required,text,min=1,max=100"`
+		Description *string `validate:"omitempty,text,max=1000"`
+		Raw         string
+		Nested      nested
+	}
+
+	description := "  first line\nsecond\x00 line\x07  "
+	req := &request{
+		Name:        "\t Acme\x00 Corp \r\n",
+		Description: &description,
+		Raw:         " untouched\x00 ",
+		Nested:      nested{Note: " note\x1b "},
+	}
+
+	if err := NewValidator().ValidateStruct(req); err != nil {
+		t.Fatalf("Expected no validation error, got: %v", err)
+	}
+	if req.Name != "Acme Corp" {
+		t.Errorf("Expected name %q, got %q", "Acme Corp", req.Name)
+	}
+	if *req.Description != "first line\nsecond line" {
+		t.Errorf("Expected description %q, got %q", "first line\nsecond line", *req.Description)
+	}
+	if req.Raw != " untouched\x00 " {
+		t.Errorf("Expected fields without the text rule to be left alone, got %q", req.Raw)
+	}
+	if req.Nested.Note != "note" {
+		t.Errorf("Expected nested note %q, got %q", "note", req.Nested.Note)
+	}
+}
+
+func TestValidateStructTextFieldLimits(t *testing.T) {
+	type request struct {
+		Name        string  `validate:"required,text,min=1,max=100"`
+		Description *string `validate:"omitempty,text,max=1000"`
+	}
+
+	tests := []struct {
+		name        string
+		reqName     string
+		description *string
+		wantErr     bool
+	}{
+		{
+			name:    "Name within limits",
+			reqName: "Engineering",
+			wantErr: false,
+		},
+		{
+			name:    "Name over the field limit",
+			reqName: strings.Repeat("a", 101),
+			wantErr: true,
+		},
+		{
+			name:    "Name of only whitespace and null bytes is empty after trimming",
+			reqName: " \x00\t \x00 ",
+			wantErr: true,
+		},
+		{
+			name:    "Padding does not count towards the limit",
+			reqName: "   " + strings.Repeat("a", 100) + "   ",
+			wantErr: false,
+		},
+		{
+			name:        "Description over the field limit",
+			reqName:     "Engineering",
+			description: stringPtr(strings.Repeat("d", 1001)),
+			wantErr:     true,
+		},
+		{
+			name:        "Description with null bytes within the limit",
+			reqName:     "Engineering",
+			description: stringPtr(strings.Repeat("d\x00", 1000)),
+			wantErr:     false,
+		},
+		{
+			name:        "Multi-byte characters count once",
+			reqName:     strings.Repeat("é", 100),
+			description: stringPtr(strings.Repeat("ಕ", 1000)),
+			wantErr:     false,
+		},
+	}
+
+	validator := NewValidator()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.ValidateStruct(&request{Name: tt.reqName, Description: tt.description})
+			if tt.wantErr && err == nil {
+				t.Errorf("Expected validation error, but got none")
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("Expected no validation error, but got: %v", err)
+			}
+		})
+	}
+}
+
+func TestValidateTextRejectsUncleanValues(t *testing.T) {
+	validate := v10.New()
+	if err := validate.RegisterValidation("text", validateText); err != nil {
+		t.Fatalf("Failed to register text validator: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		value   string
+		wantErr bool
+	}{
+		{name: "Plain text", value: "Leading provider of innovative solutions", wantErr: false},
+		{name: "Newlines and tabs", value: "line one\n\tline two", wantErr: false},
+		{name: "Null byte", value: "bad\x00value", wantErr: true},
+		{name: "Escape character", value: "bad\x1bvalue", wantErr: true},
+		{name: "Invalid UTF-8", value: "bad\xffvalue", wantErr: true},
+		{name: "Over the absolute cap", value: strings.Repeat("a", MaxTextLength+1), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validate.Var(tt.value, "text")
+			if tt.wantErr && err == nil {
+				t.Errorf("Expected validation error, but got none")
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("Expected no validation error, but got: %v", err)
+			}
+		})
+	}
+}
+
+func TestTruncateText(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		maxLen int
+		want   string
+	}{
+		{name: "Short text is kept", input: "hello", maxLen: 10, want: "hello"},
+		{name: "Long text is cut", input: "hello world", maxLen: 5, want: "hello"},
+		{name: "Cut on characters not bytes", input: "ನಮಸ್ಕಾರ", maxLen: 3, want: "ನಮಸ"},
+		{name: "Null bytes are stripped first", input: "\x00a\x00b\x00c", maxLen: 2, want: "ab"},
+		{name: "Zero means no limit", input: " text ", maxLen: 0, want: "text"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TruncateText(tt.input, tt.maxLen); got != tt.want {
+				t.Errorf("TruncateText(%q, %d) = %q, want %q", tt.input, tt.maxLen, got, tt.want)
+			}
+		})
+	}
+}